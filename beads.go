@@ -34,7 +34,7 @@ type (
 	IssueType = types.IssueType
 	// Dependency represents a relationship between issues.
 	Dependency = types.Dependency
-	// DependencyType represents the type of dependency (blocks, related, parent-child, discovered-from).
+	// DependencyType represents the type of dependency (blocks, related, parent-child, discovered-from, duplicates, caused-by).
 	DependencyType = types.DependencyType
 	// Comment represents a user comment on an issue.
 	Comment = types.Comment
@@ -83,6 +83,9 @@ const (
 	DepRelated        = types.DepRelated
 	DepParentChild    = types.DepParentChild
 	DepDiscoveredFrom = types.DepDiscoveredFrom
+	DepDuplicates     = types.DepDuplicates
+	DepCausedBy       = types.DepCausedBy
+	DepRelatesTo      = types.DepRelatesTo
 )
 
 // SortPolicy constants
@@ -105,6 +108,10 @@ const (
 	EventLabelAdded        = types.EventLabelAdded
 	EventLabelRemoved      = types.EventLabelRemoved
 	EventCompacted         = types.EventCompacted
+	EventReverted          = types.EventReverted
+	EventDeleted           = types.EventDeleted
+	EventRestored          = types.EventRestored
+	EventMentioned         = types.EventMentioned
 )
 
 // Storage provides the minimal interface for extension orchestration
@@ -116,6 +123,24 @@ func NewSQLiteStorage(dbPath string) (Storage, error) {
 	return sqlite.New(dbPath)
 }
 
+// StorageFactory opens a storage backend from a DSN; see RegisterStorage
+type StorageFactory = storage.Factory
+
+// RegisterStorage makes a custom storage backend available under name, so
+// bd's --db accepts DSNs with that scheme (e.g. "postgres" for
+// --db postgres://host/beads). Call it from an init function of the
+// backend's package; a bd build links the backend in by importing that
+// package (see cmd/bd/drivers.go). It panics if name is already registered.
+func RegisterStorage(name string, factory StorageFactory) {
+	storage.Register(name, factory)
+}
+
+// OpenStorage opens the database a DSN names: a path or sqlite: URL for
+// SQLite, or a URL whose scheme is a registered backend
+func OpenStorage(dsn string) (Storage, error) {
+	return storage.Open(dsn)
+}
+
 // FindDatabasePath discovers the bd database path using bd's standard search order:
 //  1. $BEADS_DB environment variable
 //  2. .beads/*.db in current directory or ancestors
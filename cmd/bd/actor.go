@@ -0,0 +1,263 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/spf13/cobra"
+)
+
+var actorCmd = &cobra.Command{
+	Use:   "actor",
+	Short: "Manage the registry of actor identities",
+	Long: `Actors are free-form names, so "alice", "Alice", and "alice@corp" would
+otherwise be three people. Registering an actor makes its aliases, its
+email, and any casing of its name resolve to it wherever an actor or
+assignee is written: BD_ACTOR, X-Actor, --assignee, and the like.
+
+Registering only affects new writes. To consolidate records already
+written under another name, merge it: every assignment, comment, event,
+and watch naming it is rewritten, and it becomes an alias.
+
+Examples:
+  bd actor add alice --email alice@corp.com --alias Alice
+  bd actor add ci-bot --type bot
+  bd actor alias alice al                 # Add an alias
+  bd actor alias --remove al              # Remove one
+  bd actor merge alice@corp alice --dry-run
+  bd actor merge alice@corp alice
+  bd actor list`,
+}
+
+var actorAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Register an actor",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		email, _ := cmd.Flags().GetString("email")
+		actorType, _ := cmd.Flags().GetString("type")
+		aliases, _ := cmd.Flags().GetStringSlice("alias")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		a := &sqlite.Actor{Name: args[0], Email: email, Type: actorType, Aliases: aliases}
+		if err := requireActorStore().CreateActor(context.Background(), a); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			outputJSON(a)
+			return
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Registered %s\n", green("✓"), a.Name)
+	},
+}
+
+var actorEditCmd = &cobra.Command{
+	Use:   "edit <name>",
+	Short: "Change an actor's email or type",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		email, _ := cmd.Flags().GetString("email")
+		actorType, _ := cmd.Flags().GetString("type")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		if email == "" && actorType == "" {
+			fmt.Fprintf(os.Stderr, "Error: nothing to change (use --email or --type)\n")
+			os.Exit(1)
+		}
+
+		a, err := requireActorStore().UpdateActor(context.Background(), args[0], &sqlite.Actor{Email: email, Type: actorType})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			outputJSON(a)
+			return
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Updated %s\n", green("✓"), a.Name)
+	},
+}
+
+var actorAliasCmd = &cobra.Command{
+	Use:   "alias <name> <alias...> | --remove <alias...>",
+	Short: "Add or remove an actor's aliases",
+	Run: func(cmd *cobra.Command, args []string) {
+		remove, _ := cmd.Flags().GetBool("remove")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		sqliteStore := requireActorStore()
+		ctx := context.Background()
+		green := color.New(color.FgGreen).SprintFunc()
+
+		if remove {
+			if len(args) == 0 {
+				fmt.Fprintf(os.Stderr, "Error: alias required\n")
+				os.Exit(1)
+			}
+			for _, alias := range args {
+				if err := sqliteStore.RemoveActorAlias(ctx, alias); err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+				if !jsonOutput {
+					fmt.Printf("%s Removed alias %s\n", green("✓"), alias)
+				}
+			}
+			if jsonOutput {
+				outputJSON(map[string]interface{}{"removed": args})
+			}
+			return
+		}
+
+		if len(args) < 2 {
+			fmt.Fprintf(os.Stderr, "Error: actor name and at least one alias required\n")
+			os.Exit(1)
+		}
+		a, err := sqliteStore.UpdateActor(ctx, args[0], &sqlite.Actor{Aliases: args[1:]})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			outputJSON(a)
+			return
+		}
+		fmt.Printf("%s %s is also %s\n", green("✓"), a.Name, strings.Join(a.Aliases, ", "))
+	},
+}
+
+var actorListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List registered actors",
+	Run: func(cmd *cobra.Command, _ []string) {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		actors, err := requireActorStore().ListActors(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			if actors == nil {
+				actors = []*sqlite.Actor{}
+			}
+			outputJSON(actors)
+			return
+		}
+
+		if len(actors) == 0 {
+			fmt.Println("No actors registered")
+			return
+		}
+		for _, a := range actors {
+			fmt.Printf("%s (%s)", a.Name, a.Type)
+			if a.Email != "" {
+				fmt.Printf(" <%s>", a.Email)
+			}
+			fmt.Println()
+			if len(a.Aliases) > 0 {
+				fmt.Printf("  aka %s\n", strings.Join(a.Aliases, ", "))
+			}
+		}
+	},
+}
+
+var actorMergeCmd = &cobra.Command{
+	Use:   "merge <from> <into>",
+	Short: "Consolidate one actor's history into another",
+	Long: `Rewrite every assignment, claim, comment, reaction, mention, watch, event,
+revision, work log, commit link, trash and archive record, digest
+subscription, snapshot, and label, milestone, webhook, API key, and triage
+rule naming <from> to name <into>, then make <from> an alias of <into> so
+later writes resolve too. Logged time and workload then roll up under
+<into>. If <from> is a registered actor, its aliases move to <into> and its
+registration goes. <into> is registered as a human if it isn't already.
+
+As with purge-actor, the audit chain is rebuilt over the rewritten history.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		merge, err := requireActorStore().MergeActors(context.Background(), args[0], args[1], dryRun)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if !dryRun {
+			markDirtyAndScheduleFullExport()
+		}
+
+		if jsonOutput {
+			outputJSON(merge)
+			return
+		}
+		if dryRun {
+			fmt.Printf("Would merge %s into %s: %d records on %d issues\n", merge.From, merge.Into, merge.Records, len(merge.IssuesAffected))
+			return
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Merged %s into %s: %d records on %d issues\n", green("✓"), merge.From, merge.Into, merge.Records, len(merge.IssuesAffected))
+	},
+}
+
+var actorRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Unregister an actor",
+	Long: `Unregister an actor and its aliases. Records naming the actor are kept
+as they are.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		if err := requireActorStore().DeleteActor(context.Background(), args[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Unregistered %s\n", green("✓"), args[0])
+	},
+}
+
+func requireActorStore() *sqlite.SQLiteStorage {
+	if err := ensureDirectMode("daemon does not support actor command"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: actor command requires SQLite backend\n")
+		os.Exit(1)
+	}
+	return sqliteStore
+}
+
+func init() {
+	actorAddCmd.Flags().String("email", "", "Email address that resolves to this actor")
+	actorAddCmd.Flags().String("type", sqlite.ActorHuman, "Actor type: human, agent, or bot")
+	actorAddCmd.Flags().StringSlice("alias", nil, "Other names that resolve to this actor")
+	actorEditCmd.Flags().String("email", "", "New email address")
+	actorEditCmd.Flags().String("type", "", "New type: human, agent, or bot")
+	actorAliasCmd.Flags().Bool("remove", false, "Remove the given aliases instead")
+	actorMergeCmd.Flags().Bool("dry-run", false, "Count what would be rewritten without changing anything")
+	for _, c := range []*cobra.Command{actorAddCmd, actorEditCmd, actorAliasCmd, actorListCmd, actorMergeCmd} {
+		c.Flags().Bool("json", false, "Output JSON format")
+	}
+
+	actorCmd.AddCommand(actorAddCmd)
+	actorCmd.AddCommand(actorEditCmd)
+	actorCmd.AddCommand(actorAliasCmd)
+	actorCmd.AddCommand(actorListCmd)
+	actorCmd.AddCommand(actorMergeCmd)
+	actorCmd.AddCommand(actorRemoveCmd)
+	rootCmd.AddCommand(actorCmd)
+}
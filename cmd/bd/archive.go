@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/spf13/cobra"
+)
+
+var archiveCmd = &cobra.Command{
+	Use:   "archive",
+	Short: "Move old closed issues out of default listings",
+	Long: `The archive holds closed issues that are no longer interesting day to day.
+Archived issues stay in the database and the JSONL export, and 'bd show' still
+finds them, but 'bd list', 'bd search', and the HTTP API leave them out unless
+asked to include them (--include-archived, ?include_archived=true).
+
+Reopening an archived issue takes it out of the archive.`,
+}
+
+var archiveRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Archive issues closed more than --older-than days ago",
+	Long: `Archive every issue closed more than --older-than days ago (default:
+archive_days config, 90 if unset).`,
+	Run: func(cmd *cobra.Command, _ []string) {
+		sqliteStore := requireArchiveStore()
+		ctx := context.Background()
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		days, err := sqliteStore.ArchiveDays(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if cmd.Flags().Changed("older-than") {
+			days, _ = cmd.Flags().GetInt("older-than")
+		}
+
+		ids, err := sqliteStore.ArchiveClosedIssues(ctx, time.Now().AddDate(0, 0, -days), actor, dryRun)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			if ids == nil {
+				ids = []string{}
+			}
+			outputJSON(map[string]interface{}{"archived": ids, "dry_run": dryRun})
+			return
+		}
+
+		if len(ids) == 0 {
+			fmt.Printf("No issues closed more than %d day(s) ago to archive\n", days)
+			return
+		}
+		if dryRun {
+			fmt.Printf("Would archive %d issue(s): %v\n", len(ids), ids)
+			return
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Archived %d issue(s) closed more than %d day(s) ago\n", green("✓"), len(ids), days)
+	},
+}
+
+var archiveListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List archived issues",
+	Run: func(_ *cobra.Command, _ []string) {
+		archived, err := requireArchiveStore().ListArchived(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			if archived == nil {
+				archived = []*sqlite.ArchivedIssue{}
+			}
+			outputJSON(archived)
+			return
+		}
+
+		if len(archived) == 0 {
+			fmt.Println("Archive is empty")
+			return
+		}
+		cyan := color.New(color.FgCyan).SprintFunc()
+		for _, a := range archived {
+			fmt.Printf("%s  %s\n", cyan(a.IssueID), a.Title)
+			fmt.Printf("      closed %s, archived %s\n",
+				a.ClosedAt.Local().Format("2006-01-02"), a.ArchivedAt.Local().Format("2006-01-02"))
+		}
+	},
+}
+
+var archiveRestoreCmd = &cobra.Command{
+	Use:   "restore <issue-id>...",
+	Short: "Bring issues back out of the archive",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		sqliteStore := requireArchiveStore()
+		ctx := context.Background()
+
+		green := color.New(color.FgGreen).SprintFunc()
+		restored := []string{}
+		failed := false
+		for _, id := range args {
+			err := sqliteStore.RestoreArchivedIssue(ctx, id)
+			if errors.Is(err, sqlite.ErrNotArchived) {
+				fmt.Fprintf(os.Stderr, "%s is not archived\n", id)
+				failed = true
+				continue
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error restoring %s: %v\n", id, err)
+				failed = true
+				continue
+			}
+			restored = append(restored, id)
+			if !jsonOutput {
+				fmt.Printf("%s Restored %s from the archive\n", green("✓"), id)
+			}
+		}
+
+		if jsonOutput {
+			outputJSON(map[string]interface{}{"restored": restored})
+		}
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+func requireArchiveStore() *sqlite.SQLiteStorage {
+	if err := ensureDirectMode("daemon does not support archive commands"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: archive requires SQLite backend\n")
+		os.Exit(1)
+	}
+	return sqliteStore
+}
+
+func init() {
+	archiveRunCmd.Flags().Int("older-than", 0, "Archive issues closed more than this many days ago")
+	archiveRunCmd.Flags().Bool("dry-run", false, "Show what would be archived")
+	archiveCmd.AddCommand(archiveRunCmd)
+	archiveCmd.AddCommand(archiveListCmd)
+	archiveCmd.AddCommand(archiveRestoreCmd)
+	rootCmd.AddCommand(archiveCmd)
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/imalsogreg/beads/internal/storage"
+	"github.com/imalsogreg/beads/internal/types"
+	"github.com/spf13/cobra"
+)
+
+var assignCmd = &cobra.Command{
+	Use:   "assign <issue-id> [assignee]",
+	Short: "Assign an issue, or pick the least-loaded assignee with --auto",
+	Long: `Assign an issue to someone, or let bd pick with --auto.
+
+--auto considers people with unfinished work or work updated in the last 90
+days. If the issue has labels, only those whose recent work shares one are
+eligible. The pick is whoever has the fewest unfinished issues, then the
+fewest estimated minutes, then the most matching issues.
+
+Examples:
+  bd assign bd-12 alice            # Assign bd-12 to alice
+  bd assign bd-12 --auto           # Assign bd-12 to the least-loaded eligible person
+  bd assign bd-12 --auto --dry-run # Show the ranked candidates without assigning`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		auto, _ := cmd.Flags().GetBool("auto")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		switch {
+		case auto && len(args) == 2:
+			fmt.Fprintf(os.Stderr, "Error: give an assignee or --auto, not both\n")
+			os.Exit(1)
+		case !auto && len(args) == 1:
+			fmt.Fprintf(os.Stderr, "Error: assignee required (or use --auto)\n")
+			os.Exit(1)
+		case dryRun && !auto:
+			fmt.Fprintf(os.Stderr, "Error: --dry-run requires --auto\n")
+			os.Exit(1)
+		}
+
+		if err := ensureDirectMode("daemon does not support assign command"); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		ctx := context.Background()
+		issueID := args[0]
+
+		var assignee string
+		var suggestions []*types.AssigneeSuggestion
+		if auto {
+			var err error
+			suggestions, err = storage.SuggestAssignees(ctx, store, issueID, time.Now())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if len(suggestions) == 0 {
+				fmt.Fprintf(os.Stderr, "Error: no eligible assignee for %s\n", issueID)
+				os.Exit(1)
+			}
+			assignee = suggestions[0].Assignee
+
+			if dryRun {
+				if jsonOutput {
+					outputJSON(suggestions)
+					return
+				}
+				fmt.Printf("Candidates for %s, best first:\n\n", issueID)
+				for i, s := range suggestions {
+					fmt.Printf("%d. %s: %s", i+1, s.Assignee, workloadLine(&s.AssigneeWorkload))
+					if s.MatchingIssues > 0 {
+						fmt.Printf(" · %d matching issue(s)", s.MatchingIssues)
+					}
+					fmt.Println()
+				}
+				return
+			}
+		} else {
+			assignee = args[1]
+		}
+
+		if err := store.UpdateIssue(ctx, issueID, map[string]interface{}{"assignee": assignee}, actor); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		markDirtyAndScheduleFlush()
+
+		if jsonOutput {
+			issue, _ := store.GetIssue(ctx, issueID)
+			outputJSON(issue)
+			return
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Assigned %s to %s\n", green("✓"), issueID, assignee)
+		if auto {
+			fmt.Printf("  %s\n", workloadLine(&suggestions[0].AssigneeWorkload))
+		}
+	},
+}
+
+var workloadCmd = &cobra.Command{
+	Use:   "workload",
+	Short: "Show unfinished work per assignee",
+	Run: func(_ *cobra.Command, _ []string) {
+		if err := ensureDirectMode("daemon does not support workload command"); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		workload, err := storage.GetWorkload(context.Background(), store)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			outputJSON(workload)
+			return
+		}
+		if len(workload) == 0 {
+			fmt.Println("No assigned work")
+			return
+		}
+		for _, load := range workload {
+			fmt.Printf("%s: %s\n", load.Assignee, workloadLine(load))
+		}
+	},
+}
+
+// workloadLine describes one assignee's unfinished work in a line
+func workloadLine(load *types.AssigneeWorkload) string {
+	line := fmt.Sprintf("%d open, %d in progress", load.Open, load.InProgress)
+	if load.Blocked > 0 {
+		line += fmt.Sprintf(", %d blocked", load.Blocked)
+	}
+	line += fmt.Sprintf(" · %d min estimated", load.EstimatedMinutes)
+	if load.Unestimated > 0 {
+		line += fmt.Sprintf(" · %d unestimated", load.Unestimated)
+	}
+	return line
+}
+
+func init() {
+	assignCmd.Flags().Bool("auto", false, "Pick the least-loaded eligible assignee")
+	assignCmd.Flags().Bool("dry-run", false, "With --auto, list the candidates without assigning")
+	rootCmd.AddCommand(assignCmd)
+	rootCmd.AddCommand(workloadCmd)
+}
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/imalsogreg/beads/internal/signing"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/spf13/cobra"
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Query the change log and maintain its tamper-evident audit chain",
+	Long: `Show who changed what and when, and maintain a hash chain over the audit
+trail (events table).
+
+Without a subcommand, lists changes newest first with field-level diffs.
+--since and --until take RFC 3339 times, dates (2025-01-31), or ages (36h, 7d).
+
+Each sealed event is hashed together with the previous chain entry, so any
+retroactive modification, deletion, or back-dated insertion of history is
+detected by 'bd audit verify'. Chain heads can optionally be signed.
+
+Configuration (bd config set):
+  audit.chain     true to seal new events automatically after each command
+  audit.sign      gpg to sign the chain head on every seal
+  audit.gpg_key   GPG key ID to sign with (default: gpg's default key)
+
+Signing uses GPG only: age keys can't sign, so they aren't supported.
+
+Examples:
+  bd audit --actor alice --since 7d          # What alice changed this week
+  bd audit --issue bd-12 --field priority    # Who changed bd-12's priority
+  bd audit --session sess-1a2b3c4d5e6f7a8b  # What one agent run changed
+  bd audit seal            # Append new events to the chain
+  bd audit seal --sign     # Seal and sign the new chain head
+  bd audit verify          # Detect any retroactive modification`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		filter := sqlite.AuditFilter{}
+		filter.Actor, _ = cmd.Flags().GetString("actor")
+		filter.IssueID, _ = cmd.Flags().GetString("issue")
+		filter.Field, _ = cmd.Flags().GetString("field")
+		filter.Session, _ = cmd.Flags().GetString("session")
+		filter.Limit, _ = cmd.Flags().GetInt("limit")
+		filter.Before, _ = cmd.Flags().GetInt64("before")
+
+		now := time.Now()
+		for flag, dest := range map[string]**time.Time{"since": &filter.Since, "until": &filter.Until} {
+			value, _ := cmd.Flags().GetString(flag)
+			if value == "" {
+				continue
+			}
+			t, err := sqlite.ParseAuditTime(value, now)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: --%s: %v\n", flag, err)
+				os.Exit(1)
+			}
+			*dest = &t
+		}
+
+		page, err := requireAuditStore().QueryAuditLog(context.Background(), filter)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			outputJSON(page)
+			return
+		}
+
+		if len(page.Entries) == 0 {
+			fmt.Println("No matching changes")
+			return
+		}
+		printAuditEntries(page.Entries)
+		if page.NextCursor != 0 {
+			fmt.Printf("\nMore changes: bd audit --before %d\n", page.NextCursor)
+		}
+	},
+}
+
+// printAuditEntries lists changes one per line with their field diffs
+func printAuditEntries(entries []*sqlite.AuditEntry) {
+	cyan := color.New(color.FgCyan).SprintFunc()
+	for _, e := range entries {
+		fmt.Printf("%s  %s  %-18s %s\n", e.CreatedAt.Local().Format("2006-01-02 15:04"), cyan(e.IssueID), e.EventType, e.Actor)
+		for _, c := range e.Changes {
+			fmt.Printf("    %s: %s → %s\n", c.Field, formatAuditValue(c.Old), formatAuditValue(c.New))
+		}
+		if e.Comment != "" {
+			fmt.Printf("    %s\n", truncateAuditText(e.Comment))
+		}
+	}
+}
+
+// formatAuditValue renders a changed field's value on one line
+func formatAuditValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "(none)"
+	case string:
+		if v == "" {
+			return `""`
+		}
+		return truncateAuditText(strconv.Quote(v))
+	default:
+		b, _ := json.Marshal(v)
+		return truncateAuditText(string(b))
+	}
+}
+
+func truncateAuditText(s string) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	if len(s) > 80 {
+		return s[:77] + "..."
+	}
+	return s
+}
+
+var auditSealCmd = &cobra.Command{
+	Use:   "seal",
+	Short: "Append unsealed events to the audit chain",
+	Run: func(cmd *cobra.Command, _ []string) {
+		sign, _ := cmd.Flags().GetBool("sign")
+
+		sqliteStore := requireAuditStore()
+		ctx := context.Background()
+
+		result, sig, err := sealAuditChain(ctx, sqliteStore, sign)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			outputJSON(map[string]interface{}{
+				"sealed":    result.Sealed,
+				"head_seq":  result.HeadSeq,
+				"head_hash": result.HeadHash,
+				"signature": sig,
+			})
+			return
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Sealed %d event(s)\n", green("✓"), result.Sealed)
+		fmt.Printf("  Head: #%d %s\n", result.HeadSeq, result.HeadHash)
+		if sig != nil {
+			fmt.Printf("  Signed with %s", sig.Method)
+			if sig.Signer != "" {
+				fmt.Printf(" (%s)", sig.Signer)
+			}
+			fmt.Println()
+		}
+	},
+}
+
+var auditVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify the audit chain and its signatures",
+	Run: func(_ *cobra.Command, _ []string) {
+		sqliteStore := requireAuditStore()
+		ctx := context.Background()
+
+		result, err := sqliteStore.VerifyAuditChain(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		sigProblems, err := verifyAuditSignatures(ctx, sqliteStore)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		valid := result.Valid && len(sigProblems) == 0
+
+		if jsonOutput {
+			outputJSON(map[string]interface{}{
+				"valid":              valid,
+				"chain":              result,
+				"signature_problems": sigProblems,
+			})
+			if !valid {
+				os.Exit(1)
+			}
+			return
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		red := color.New(color.FgRed).SprintFunc()
+		yellow := color.New(color.FgYellow).SprintFunc()
+
+		fmt.Printf("\nChecked %d sealed event(s), head #%d %s\n", result.Checked, result.HeadSeq, result.HeadHash)
+		if result.Unsealed > 0 {
+			fmt.Printf("%s %d event(s) not yet sealed (run 'bd audit seal')\n", yellow("⚠"), result.Unsealed)
+		}
+		for _, p := range result.Problems {
+			fmt.Printf("%s [%s] event %d: %s\n", red("✗"), p.Kind, p.EventID, p.Detail)
+		}
+		for _, p := range sigProblems {
+			fmt.Printf("%s [signature] %s\n", red("✗"), p)
+		}
+		if valid {
+			fmt.Printf("%s Audit chain intact\n\n", green("✓"))
+			return
+		}
+		fmt.Printf("\n%s Audit chain verification FAILED\n\n", red("✗"))
+		os.Exit(1)
+	},
+}
+
+// requireAuditStore switches to direct mode and returns the SQLite store
+func requireAuditStore() *sqlite.SQLiteStorage {
+	if err := ensureDirectMode("daemon does not support audit command"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: audit command requires SQLite backend\n")
+		os.Exit(1)
+	}
+	return sqliteStore
+}
+
+// sealAuditChain seals pending events and, if requested or configured, signs the new head
+func sealAuditChain(ctx context.Context, s *sqlite.SQLiteStorage, forceSign bool) (*sqlite.AuditSealResult, *sqlite.AuditSignature, error) {
+	result, err := s.SealAuditChain(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	method, _ := s.GetConfig(ctx, "audit.sign")
+	if method == "" && forceSign {
+		method = signing.MethodGPG
+	}
+	if method == "" || (result.Sealed == 0 && !forceSign) {
+		return result, nil, nil
+	}
+
+	gpgKey, _ := s.GetConfig(ctx, "audit.gpg_key")
+	signature, signer, err := signing.Sign(method, gpgKey, result.HeadHash)
+	if err != nil {
+		return result, nil, fmt.Errorf("failed to sign chain head: %w", err)
+	}
+	sig := &sqlite.AuditSignature{
+		HeadSeq:   result.HeadSeq,
+		HeadHash:  result.HeadHash,
+		Method:    method,
+		Signer:    signer,
+		Signature: signature,
+	}
+	if err := s.AddAuditSignature(ctx, sig); err != nil {
+		return result, nil, err
+	}
+	return result, sig, nil
+}
+
+// autoSealAuditChain seals new events at the end of a direct-mode command when audit.chain is enabled
+func autoSealAuditChain() {
+	sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+	if !ok {
+		return
+	}
+	ctx := context.Background()
+	if enabled, _ := sqliteStore.GetConfig(ctx, "audit.chain"); enabled != "true" {
+		return
+	}
+	if _, _, err := sealAuditChain(ctx, sqliteStore, false); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to seal audit chain: %v\n", err)
+	}
+}
+
+// verifyAuditSignatures checks that every signed head still matches the chain and that
+// each signature is valid. Signatures that cannot be checked locally are reported.
+func verifyAuditSignatures(ctx context.Context, s *sqlite.SQLiteStorage) ([]string, error) {
+	sigs, err := s.GetAuditSignatures(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	for _, sig := range sigs {
+		current, err := s.GetAuditChainHash(ctx, sig.HeadSeq)
+		if err != nil {
+			return nil, err
+		}
+		if current != sig.HeadHash {
+			problems = append(problems, fmt.Sprintf("signed head #%d no longer matches the chain", sig.HeadSeq))
+			continue
+		}
+
+		if err := signing.Verify(sig.Method, sig.HeadHash, sig.Signature); err != nil {
+			problems = append(problems, fmt.Sprintf("head #%d: %v", sig.HeadSeq, err))
+		}
+	}
+	return problems, nil
+}
+
+func init() {
+	auditCmd.Flags().String("actor", "", "Only changes made by this actor")
+	auditCmd.Flags().String("issue", "", "Only changes to this issue")
+	auditCmd.Flags().String("session", "", "Only changes made in this agent session")
+	auditCmd.Flags().String("field", "", "Only changes to this field (e.g. priority, assignee, status)")
+	auditCmd.Flags().String("since", "", "Only changes at or after this time")
+	auditCmd.Flags().String("until", "", "Only changes before this time")
+	auditCmd.Flags().Int("limit", sqlite.DefaultAuditLimit, "Maximum number of changes to show")
+	auditCmd.Flags().Int64("before", 0, "Page cursor: only changes older than this event ID")
+	auditSealCmd.Flags().Bool("sign", false, "Sign the new chain head (uses audit.sign, default gpg)")
+
+	auditCmd.AddCommand(auditSealCmd)
+	auditCmd.AddCommand(auditVerifyCmd)
+	rootCmd.AddCommand(auditCmd)
+}
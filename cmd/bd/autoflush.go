@@ -471,15 +471,21 @@ func writeJSONLAtomic(jsonlPath string, issues []*types.Issue) ([]string, error)
 			skip = false
 		}
 		
+		exported, err := exportableIssue(store, issue)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt issue %s: %w", issue.ID, err)
+		}
+		if err := encoder.Encode(exported); err != nil {
+			return nil, fmt.Errorf("failed to encode issue %s: %w", issue.ID, err)
+		}
+
+		// The whole file is rewritten, so a skipped issue is still written;
+		// it just isn't reported as exported
 		if skip {
 			skippedCount++
 			continue
 		}
 		
-		if err := encoder.Encode(issue); err != nil {
-			return nil, fmt.Errorf("failed to encode issue %s: %w", issue.ID, err)
-		}
-		
 		// Save content hash after successful export (bd-159)
 		contentHash, err := computeIssueContentHash(issue)
 		if err != nil {
@@ -609,16 +615,15 @@ func flushToJSONL() {
 	var err error
 
 	if fullExport {
-		// Full export: get ALL issues (needed after ID-changing operations like renumber)
-		allIssues, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+		// Full export: get ALL issue IDs (needed after ID-changing operations like renumber)
+		err := store.ForEachIssue(ctx, types.IssueFilter{}, func(issue *types.Issue) error {
+			dirtyIDs = append(dirtyIDs, issue.ID)
+			return nil
+		})
 		if err != nil {
 			recordFailure(fmt.Errorf("failed to get all issues: %w", err))
 			return
 		}
-		dirtyIDs = make([]string, len(allIssues))
-		for i, issue := range allIssues {
-			dirtyIDs[i] = issue.ID
-		}
 	} else {
 		// Incremental export: get only dirty issue IDs (bd-39 optimization)
 		dirtyIDs, err = store.GetDirtyIssues(ctx)
@@ -665,11 +670,13 @@ func flushToJSONL() {
 			recordFailure(fmt.Errorf("failed to get issue %s: %w", issueID, err))
 			return
 		}
-		if issue == nil {
-			// Issue was deleted, remove from map
+		if issue == nil || issue.DeletedAt != nil {
+			// Issue was deleted or moved to the trash, remove from map
 			delete(issueMap, issueID)
 			continue
 		}
+		// Versions count local revisions and differ between clones
+		issue.Version = 0
 
 		// Get dependencies for this issue
 		deps, err := store.GetDependencyRecords(ctx, issueID)
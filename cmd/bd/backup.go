@@ -0,0 +1,296 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/imalsogreg/beads/internal/backup"
+	"github.com/imalsogreg/beads/internal/config"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/spf13/cobra"
+)
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Take and restore snapshots of the database",
+	Long: `Backups are consistent snapshots of the SQLite database, taken without stopping
+anything that uses it, for recovering from corruption or a bad bulk edit.
+
+They are kept in .beads/backups (which git ignores), each with a manifest
+recording when it was taken and the snapshot's checksum. With --upload they
+are also copied to S3 or S3-compatible storage, set up in config:
+
+  backup:
+    s3:
+      endpoint: https://s3.us-east-1.amazonaws.com
+      bucket: my-backups
+      region: us-east-1   # default
+      prefix: beads/      # optional
+
+Credentials come from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, and, for
+temporary credentials, AWS_SESSION_TOKEN.
+
+'bd serve' takes backups too, through POST /admin/backup, and on a schedule
+with --backup-interval, keeping the newest --backup-retain of those (and
+uploading them when backup.s3 is set). Backups taken by hand are never
+rotated out.`,
+}
+
+var backupCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Snapshot the database",
+	Run: func(cmd *cobra.Command, _ []string) {
+		note, _ := cmd.Flags().GetString("note")
+		upload, _ := cmd.Flags().GetBool("upload")
+		sqliteStore := requireBackupStore()
+		ctx := context.Background()
+
+		var uploader *backup.S3
+		if upload {
+			var err error
+			if uploader, err = backupUploader(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if uploader == nil {
+				fmt.Fprintf(os.Stderr, "Error: --upload needs backup.s3.endpoint and backup.s3.bucket in config\n")
+				os.Exit(1)
+			}
+		}
+
+		m, err := createBackup(ctx, sqliteStore, note)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if uploader != nil {
+			if err := uploader.Upload(ctx, backup.Dir(sqliteStore.Path()), m); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: backup %s was taken but not uploaded: %v\n", m.ID, err)
+				os.Exit(1)
+			}
+		}
+
+		if jsonOutput {
+			outputJSON(m)
+			return
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Backup %s: %d issue(s), %s\n", green("✓"), m.ID, m.Issues, formatBytes(m.Size))
+		if m.Uploaded != "" {
+			fmt.Printf("  Uploaded to %s\n", m.Uploaded)
+		}
+	},
+}
+
+var backupListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List backups, newest first",
+	Run: func(_ *cobra.Command, _ []string) {
+		sqliteStore := requireBackupStore()
+		manifests, err := backup.List(backup.Dir(sqliteStore.Path()))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			if manifests == nil {
+				manifests = []*backup.Manifest{}
+			}
+			outputJSON(manifests)
+			return
+		}
+
+		if len(manifests) == 0 {
+			fmt.Println("No backups yet (take one with 'bd backup create')")
+			return
+		}
+		cyan := color.New(color.FgCyan).SprintFunc()
+		for _, m := range manifests {
+			scheduled := ""
+			if m.Scheduled {
+				scheduled = ", scheduled"
+			}
+			fmt.Printf("%s  %s  %d issue(s), %s%s\n", cyan(m.ID),
+				m.CreatedAt.Local().Format("2006-01-02 15:04:05"), m.Issues, formatBytes(m.Size), scheduled)
+			if m.Note != "" {
+				fmt.Printf("      %s\n", m.Note)
+			}
+			if m.Uploaded != "" {
+				fmt.Printf("      uploaded to %s\n", m.Uploaded)
+			}
+		}
+	},
+}
+
+var backupRestoreCmd = &cobra.Command{
+	Use:   "restore [backup-id]",
+	Short: "Replace the database with a backup",
+	Long: `Replace the database with a backup, given by ID or, with --at, the newest one
+taken at or before a point in time. A backup of the current database is taken
+first, so a restore can be undone by restoring that.
+
+The JSONL file is rewritten from the restored database, so commit it to share
+the restore. Stop the daemon and any 'bd serve' using this database first.
+
+The restored database gets a new replica ID for 'bd sync remote', and syncing
+with a replica that saw the undone changes brings them back.
+
+Examples:
+  bd backup restore 20261017T093000Z
+  bd backup restore --at "2026-10-17 09:00"
+  bd backup restore --at 2026-10-16     # the last backup of that day`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		at, _ := cmd.Flags().GetString("at")
+		if (len(args) == 0) == (at == "") {
+			fmt.Fprintf(os.Stderr, "Error: give either a backup ID or --at\n")
+			os.Exit(1)
+		}
+		sqliteStore := requireBackupStore()
+		ctx := context.Background()
+		path := sqliteStore.Path()
+		dir := backup.Dir(path)
+
+		if running, pid := isDaemonRunning(filepath.Join(filepath.Dir(path), "daemon.pid")); running {
+			fmt.Fprintf(os.Stderr, "Error: daemon (PID %d) has the database open; stop it first with 'bd daemon --stop'\n", pid)
+			os.Exit(1)
+		}
+
+		var m *backup.Manifest
+		var err error
+		if at != "" {
+			t, perr := backup.ParseTime(at)
+			if perr != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", perr)
+				os.Exit(1)
+			}
+			m, err = backup.At(dir, t)
+		} else {
+			m, err = backup.Find(dir, args[0])
+		}
+		if err == nil {
+			err = backup.Verify(dir, m)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		safety, err := createBackup(ctx, sqliteStore, "Before restoring "+m.ID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to back up the current database: %v\n", err)
+			os.Exit(1)
+		}
+
+		storeMutex.Lock()
+		storeActive = false
+		_ = store.Close()
+		storeMutex.Unlock()
+		if err := backup.Restore(dir, m, path); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v (the database before the restore is backup %s)\n", err, safety.ID)
+			os.Exit(1)
+		}
+
+		// Reopen without auto-import, which would bring the JSONL's newer
+		// issues straight back
+		restored, err := sqlite.NewWithConfig(path, sqlitePoolConfig())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to open restored database: %v\n", err)
+			os.Exit(1)
+		}
+		storeMutex.Lock()
+		store = restored
+		storeActive = true
+		storeMutex.Unlock()
+		if err := restored.ResetReplicaID(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to reset replica ID: %v\n", err)
+		}
+		// The snapshot's export hashes describe the JSONL as it was then, and
+		// would keep unchanged issues out of the rewrite
+		if err := restored.ClearAllExportHashes(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to clear export hashes: %v\n", err)
+		}
+		markDirtyAndScheduleFullExport()
+
+		if jsonOutput {
+			outputJSON(map[string]interface{}{"restored": m, "previous": safety})
+			return
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Restored backup %s from %s (%d issue(s))\n", green("✓"),
+			m.ID, m.CreatedAt.Local().Format("2006-01-02 15:04:05"), m.Issues)
+		fmt.Printf("  The database before the restore is backup %s\n", safety.ID)
+	},
+}
+
+func requireBackupStore() *sqlite.SQLiteStorage {
+	if err := ensureDirectMode("daemon does not support backup commands"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: backup requires SQLite backend\n")
+		os.Exit(1)
+	}
+	return sqliteStore
+}
+
+// createBackup snapshots st into its backup directory
+func createBackup(ctx context.Context, st *sqlite.SQLiteStorage, note string) (*backup.Manifest, error) {
+	stats, err := st.GetStatistics(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return backup.Create(ctx, st, backup.Dir(st.Path()), backup.Options{
+		Version: Version,
+		Issues:  stats.TotalIssues,
+		Note:    note,
+	})
+}
+
+// backupUploader returns the S3 uploader from backup.s3 config, or nil if
+// none is configured
+func backupUploader() (*backup.S3, error) {
+	endpoint := config.GetString("backup.s3.endpoint")
+	if endpoint == "" {
+		return nil, nil
+	}
+	return backup.NewS3(backup.S3Config{
+		Endpoint:     endpoint,
+		Bucket:       config.GetString("backup.s3.bucket"),
+		Region:       config.GetString("backup.s3.region"),
+		Prefix:       config.GetString("backup.s3.prefix"),
+		AccessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+	})
+}
+
+// formatBytes renders a size like 1.5 MB
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func init() {
+	backupCreateCmd.Flags().String("note", "", "Why the backup was taken, shown by 'bd backup list'")
+	backupCreateCmd.Flags().Bool("upload", false, "Also upload the backup to S3 (see 'bd backup --help')")
+	backupRestoreCmd.Flags().String("at", "", "Restore the newest backup taken at or before this time")
+	backupCmd.AddCommand(backupCreateCmd)
+	backupCmd.AddCommand(backupListCmd)
+	backupCmd.AddCommand(backupRestoreCmd)
+	rootCmd.AddCommand(backupCmd)
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/imalsogreg/beads/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var blocksCmd = &cobra.Command{
+	Use:   "blocks <issue-id>",
+	Short: "Show the open issues an issue holds up",
+	Long: `List every open issue blocked by an issue, directly or through the issues
+it blocks, with how many there are and the most urgent priority among them.
+Use it in triage to find the blockers worth clearing first.
+
+Blockage spreads as it does for 'bd ready': along 'blocks' dependencies, and
+from a blocked issue to its children. Closed issues are skipped, and a closed
+issue blocks nothing.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		if err := ensureDirectMode("daemon does not support blocks command"); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		ctx := context.Background()
+		impact, err := storage.GetBlockingImpact(ctx, store, args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			outputJSON(impact)
+			return
+		}
+
+		cyan := color.New(color.FgCyan).SprintFunc()
+		if impact.Total == 0 {
+			fmt.Printf("%s blocks no open issues\n", cyan(impact.Issue.ID))
+			return
+		}
+
+		bold := color.New(color.Bold).SprintFunc()
+		red := color.New(color.FgRed).SprintFunc()
+		fmt.Printf("\n%s %s blocks %d open issue(s), %d directly; highest priority %s\n\n",
+			bold("⛔"), cyan(impact.Issue.ID), impact.Total, impact.Direct, red(fmt.Sprintf("P%d", *impact.HighestPriority)))
+		for _, blocked := range impact.Blocked {
+			via := ""
+			if blocked.Depth > 1 {
+				via = " via " + blocked.BlockedBy
+				if blocked.Inherited {
+					via = " (child of " + blocked.BlockedBy + ")"
+				}
+			}
+			fmt.Printf("  %s%s [P%d] %s (%s)%s\n", strings.Repeat("  ", blocked.Depth-1),
+				cyan(blocked.Issue.ID), blocked.Issue.Priority, blocked.Issue.Title, blocked.Issue.Status, via)
+		}
+		fmt.Println()
+	},
+}
+
+var blockersCmd = &cobra.Command{
+	Use:   "blockers <issue-id>",
+	Short: "Show the open issues holding up an issue",
+	Long: `List the open issues blocking an issue, with their status and assignee, so
+you know exactly what to chase.
+
+An issue is held up as it is for 'bd ready': by the open issues it depends on
+with 'blocks' dependencies, and by those blocking any of its parents. With
+--transitive, the blockers' own blockers are listed too, indented under the
+issue each one blocks.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		transitive, _ := cmd.Flags().GetBool("transitive")
+
+		if err := ensureDirectMode("daemon does not support blockers command"); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		ctx := context.Background()
+		tree, err := storage.GetBlockers(ctx, store, args[0], transitive)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			outputJSON(tree)
+			return
+		}
+
+		cyan := color.New(color.FgCyan).SprintFunc()
+		if tree.Total == 0 {
+			fmt.Printf("%s is not blocked\n", cyan(tree.Issue.ID))
+			return
+		}
+
+		bold := color.New(color.Bold).SprintFunc()
+		yellow := color.New(color.FgYellow).SprintFunc()
+		fmt.Printf("\n%s %s is blocked by %d open issue(s)\n\n", bold("🚧"), cyan(tree.Issue.ID), tree.Total)
+		var walk func(issueID string)
+		walk = func(issueID string) {
+			for _, blocker := range tree.Children(issueID) {
+				owner := blocker.Issue.Assignee
+				if owner == "" {
+					owner = yellow("unassigned")
+				}
+				via := ""
+				if blocker.Via != "" {
+					via = " via ancestor " + blocker.Via
+				}
+				fmt.Printf("  %s%s [P%d] %s (%s, %s)%s\n", strings.Repeat("  ", blocker.Depth-1),
+					cyan(blocker.Issue.ID), blocker.Issue.Priority, blocker.Issue.Title, blocker.Issue.Status, owner, via)
+				walk(blocker.Issue.ID)
+			}
+		}
+		walk(tree.Issue.ID)
+		fmt.Println()
+	},
+}
+
+func init() {
+	blocksCmd.Flags().Bool("json", false, "Output in JSON format")
+	blockersCmd.Flags().BoolP("transitive", "t", false, "Include blockers of blockers")
+	blockersCmd.Flags().Bool("json", false, "Output in JSON format")
+	rootCmd.AddCommand(blocksCmd)
+	rootCmd.AddCommand(blockersCmd)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// parseUpdateFilter builds an issue filter from key=value terms given to
+// 'bd update --filter'. All terms must match; label may be repeated.
+func parseUpdateFilter(terms []string) (types.IssueFilter, error) {
+	var filter types.IssueFilter
+	for _, term := range terms {
+		key, value, ok := strings.Cut(strings.TrimSpace(term), "=")
+		if !ok || value == "" {
+			return filter, fmt.Errorf("invalid filter %q (expected key=value)", term)
+		}
+		switch key {
+		case "status":
+			s := types.Status(value)
+			if !s.IsValid() {
+				return filter, fmt.Errorf("invalid status: %s", value)
+			}
+			filter.Status = &s
+		case "priority":
+			p, err := strconv.Atoi(value)
+			if err != nil {
+				return filter, fmt.Errorf("invalid priority: %s", value)
+			}
+			filter.Priority = &p
+		case "assignee":
+			a := value
+			filter.Assignee = &a
+		case "type":
+			t := types.IssueType(value)
+			if !t.IsValid() {
+				return filter, fmt.Errorf("invalid issue type: %s", value)
+			}
+			filter.IssueType = &t
+		case "label":
+			filter.Labels = append(filter.Labels, value)
+		case "label-any":
+			filter.LabelsAny = append(filter.LabelsAny, value)
+		case "title":
+			filter.TitleSearch = value
+		case "id":
+			filter.IDs = append(filter.IDs, value)
+		default:
+			return filter, fmt.Errorf("unknown filter key %q (use status, priority, assignee, type, label, label-any, title, id)", key)
+		}
+	}
+	return filter, nil
+}
+
+// runBulkUpdate applies updates to every issue matching the --filter terms in
+// one transaction and reports the affected IDs
+func runBulkUpdate(terms []string, updates map[string]interface{}) {
+	filter, err := parseUpdateFilter(terms)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := ensureDirectMode("daemon does not support bulk update"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	ids, err := store.UpdateIssues(ctx, filter, updates, actor)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(ids) > 0 {
+		markDirtyAndScheduleFlush()
+	}
+
+	if jsonOutput {
+		outputJSON(map[string]interface{}{
+			"updated": ids,
+			"count":   len(ids),
+		})
+		return
+	}
+
+	if len(ids) == 0 {
+		fmt.Println("No issues matched")
+		return
+	}
+	green := color.New(color.FgGreen).SprintFunc()
+	fmt.Printf("%s Updated %d issue(s): %s\n", green("✓"), len(ids), strings.Join(ids, ", "))
+}
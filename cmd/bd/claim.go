@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/imalsogreg/beads/internal/storage"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+	"github.com/spf13/cobra"
+)
+
+var claimCmd = &cobra.Command{
+	Use:   "claim [issue-id]",
+	Short: "Claim work with a time-limited lease",
+	Long: `Claim an issue so parallel workers (people or agents) don't pick up the same work.
+
+Claiming flips an open issue to in_progress and assigns it to you (the current
+actor) for --ttl. Claim it again before the lease runs out to renew it. When a
+lease expires the issue goes back to open and unassigned, ready for someone
+else; the daemon checks for expired leases every minute (claims.expire=false
+turns this off), and every claim checks first.
+
+Examples:
+  bd claim bd-42                   # Claim (or renew) bd-42 for 30 minutes
+  bd claim --next --ttl 1h         # Claim the next ready issue that's unassigned or yours
+  bd claim --next -l backend       # ... only backend work
+  bd claim --release bd-42         # Hand bd-42 back to the pool
+  bd claim --list                  # Show who holds what, and until when`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		next, _ := cmd.Flags().GetBool("next")
+		release, _ := cmd.Flags().GetBool("release")
+		list, _ := cmd.Flags().GetBool("list")
+		ttl, _ := cmd.Flags().GetDuration("ttl")
+
+		modes := 0
+		for _, set := range []bool{next, release, list} {
+			if set {
+				modes++
+			}
+		}
+		switch {
+		case modes > 1:
+			fmt.Fprintf(os.Stderr, "Error: --next, --release, and --list are mutually exclusive\n")
+			os.Exit(1)
+		case (next || list) && len(args) > 0:
+			fmt.Fprintf(os.Stderr, "Error: --next and --list don't take an issue ID\n")
+			os.Exit(1)
+		case !next && !list && len(args) == 0:
+			fmt.Fprintf(os.Stderr, "Error: issue ID required (or use --next)\n")
+			os.Exit(1)
+		}
+
+		sqliteStore := requireClaimStore()
+		ctx := context.Background()
+		green := color.New(color.FgGreen).SprintFunc()
+
+		switch {
+		case list:
+			leases, err := sqliteStore.ListLeases(ctx)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if jsonOutput {
+				if leases == nil {
+					leases = []*sqlite.Lease{}
+				}
+				outputJSON(leases)
+				return
+			}
+			if len(leases) == 0 {
+				fmt.Println("No claimed issues")
+				return
+			}
+			for _, lease := range leases {
+				fmt.Printf("%s  %s for %s more\n", lease.IssueID, lease.Holder, formatDuration(time.Until(lease.ExpiresAt)))
+			}
+
+		case release:
+			if err := sqliteStore.ReleaseLease(ctx, args[0], actor); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			markDirtyAndScheduleFlush()
+			if jsonOutput {
+				outputJSON(map[string]interface{}{"released": args[0]})
+				return
+			}
+			fmt.Printf("%s Released %s\n", green("✓"), args[0])
+
+		default:
+			var issue *types.Issue
+			var lease *sqlite.Lease
+			var err error
+			if next {
+				filter := types.WorkFilter{}
+				filter.Labels, _ = cmd.Flags().GetStringSlice("label")
+				if cmd.Flags().Changed("max-priority") {
+					maxPriority, _ := cmd.Flags().GetInt("max-priority")
+					filter.MaxPriority = &maxPriority
+				}
+				issue, lease, err = sqliteStore.ClaimNext(ctx, actor, ttl, filter)
+			} else if lease, err = sqliteStore.ClaimIssue(ctx, args[0], actor, ttl); err == nil {
+				issue, err = sqliteStore.GetIssue(ctx, args[0])
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if issue == nil {
+				if jsonOutput {
+					outputJSON(map[string]interface{}{"issue": nil, "lease": nil})
+					return
+				}
+				yellow := color.New(color.FgYellow).SprintFunc()
+				fmt.Printf("\n%s No ready work to claim\n\n", yellow("✨"))
+				return
+			}
+			markDirtyAndScheduleFlush()
+
+			if jsonOutput {
+				outputJSON(map[string]interface{}{"issue": issue, "lease": lease})
+				return
+			}
+			fmt.Printf("%s Claimed %s: %s\n", green("✓"), issue.ID, issue.Title)
+			fmt.Printf("  Held by %s until %s\n", lease.Holder, lease.ExpiresAt.Local().Format("15:04:05"))
+		}
+	},
+}
+
+func requireClaimStore() *sqlite.SQLiteStorage {
+	if err := ensureDirectMode("daemon does not support claim command"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: claim command requires SQLite backend\n")
+		os.Exit(1)
+	}
+	return sqliteStore
+}
+
+func runScheduledLeaseExpiry(ctx context.Context, store storage.Storage, log daemonLogger) error {
+	sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+	if !ok {
+		return nil
+	}
+	released, err := sqliteStore.ReleaseExpiredLeases(ctx)
+	for _, lease := range released {
+		log.log("Claims: %s's lease on %s expired, returned it to the pool", lease.Holder, lease.IssueID)
+	}
+	return err
+}
+
+func init() {
+	claimCmd.Flags().Bool("next", false, "Claim the next ready issue that is unassigned or yours")
+	claimCmd.Flags().Bool("release", false, "Release your claim on the issue")
+	claimCmd.Flags().Bool("list", false, "List live claims")
+	claimCmd.Flags().Duration("ttl", sqlite.DefaultLeaseTTL, "How long the lease lasts")
+	claimCmd.Flags().StringSliceP("label", "l", []string{}, "With --next, only issues with these labels (must have all)")
+	claimCmd.Flags().Int("max-priority", 0, "With --next, only this priority or more urgent")
+	rootCmd.AddCommand(claimCmd)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"os/user"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -14,8 +15,9 @@ import (
 )
 
 var commentsCmd = &cobra.Command{
-	Use:   "comments [issue-id]",
-	Short: "View or manage comments on an issue",
+	Use:     "comments [issue-id]",
+	Aliases: []string{"comment"},
+	Short:   "View or manage comments on an issue",
 	Long: `View or manage comments on an issue.
 
 Examples:
@@ -29,7 +31,11 @@ Examples:
   bd comments add bd-123 "This is a comment"
 
   # Add a comment from a file
-  bd comments add bd-123 -f notes.txt`,
+  bd comments add bd-123 -f notes.txt
+
+  # Reply to comment 4, then fix a typo in comment 5
+  bd comments add bd-123 --reply-to 4 "Agreed"
+  bd comment edit bd-123 5 "Agreed, shipping today"`,
 	Args: cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		issueID := args[0]
@@ -88,13 +94,26 @@ Examples:
 		}
 
 		fmt.Printf("\nComments on %s:\n\n", issueID)
-		for _, comment := range comments {
-			fmt.Printf("[%s] %s at %s\n", comment.Author, comment.Text, comment.CreatedAt.Format("2006-01-02 15:04"))
-			fmt.Println()
+		for _, thread := range types.CommentThreads(comments) {
+			printComment(thread.Comment, thread.Depth)
 		}
 	},
 }
 
+// printComment prints one comment of a thread, indented under the one it answers
+func printComment(comment *types.Comment, depth int) {
+	indent := strings.Repeat("  ", depth)
+	edited := ""
+	if comment.UpdatedAt != nil {
+		edited = " (edited)"
+	}
+	fmt.Printf("%s#%d [%s] %s at %s%s\n", indent, comment.ID, comment.Author, comment.Text, comment.CreatedAt.Format("2006-01-02 15:04"), edited)
+	if len(comment.Reactions) > 0 {
+		fmt.Printf("%s  %s\n", indent, types.FormatReactions(comment.Reactions))
+	}
+	fmt.Println()
+}
+
 var commentsAddCmd = &cobra.Command{
 	Use:   "add [issue-id] [text]",
 	Short: "Add a comment to an issue",
@@ -105,50 +124,29 @@ Examples:
   bd comments add bd-123 "Working on this now"
 
   # Add a comment from a file
-  bd comments add bd-123 -f notes.txt`,
+  bd comments add bd-123 -f notes.txt
+
+  # Reply to comment 4
+  bd comments add bd-123 --reply-to 4 "Agreed"`,
 	Args: cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		issueID := args[0]
+		commentText := commentTextArg(cmd, args, 1)
+		author := commentAuthor(cmd)
 
-		// Get comment text from flag or argument
-		commentText, _ := cmd.Flags().GetString("file")
-		if commentText != "" {
-			// Read from file
-			data, err := os.ReadFile(commentText) // #nosec G304 - user-provided file path is intentional
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error reading file: %v\n", err)
-				os.Exit(1)
-			}
-			commentText = string(data)
-		} else if len(args) < 2 {
-			fmt.Fprintf(os.Stderr, "Error: comment text required (use -f to read from file)\n")
-			os.Exit(1)
-		} else {
-			commentText = args[1]
-		}
-
-		// Get author from author flag, BD_ACTOR var, or system USER var
-		author, _ := cmd.Flags().GetString("author")
-		if author == "" {
-			author = os.Getenv("BD_ACTOR")
-			if author == "" {
-				author = os.Getenv("USER")
-			}
-			if author == "" {
-				if u, err := user.Current(); err == nil {
-					author = u.Username
-				} else {
-					author = "unknown"
-				}
-			}
+		var replyTo *int64
+		if cmd.Flags().Changed("reply-to") {
+			id, _ := cmd.Flags().GetInt64("reply-to")
+			replyTo = &id
 		}
 
 		var comment *types.Comment
 		if daemonClient != nil {
 			resp, err := daemonClient.AddComment(&rpc.CommentAddArgs{
-				ID:     issueID,
-				Author: author,
-				Text:   commentText,
+				ID:      issueID,
+				Author:  author,
+				Text:    commentText,
+				ReplyTo: replyTo,
 			})
 			if err != nil {
 				if isUnknownOperationError(err) {
@@ -177,7 +175,11 @@ Examples:
 			}
 			ctx := context.Background()
 			var err error
-			comment, err = store.AddIssueComment(ctx, issueID, author, commentText)
+			if replyTo != nil {
+				comment, err = store.ReplyToComment(ctx, issueID, *replyTo, author, commentText)
+			} else {
+				comment, err = store.AddIssueComment(ctx, issueID, author, commentText)
+			}
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error adding comment: %v\n", err)
 				os.Exit(1)
@@ -194,17 +196,205 @@ Examples:
 			return
 		}
 
-		fmt.Printf("Comment added to %s\n", issueID)
+		fmt.Printf("Comment #%d added to %s\n", comment.ID, issueID)
+	},
+}
+
+var commentsEditCmd = &cobra.Command{
+	Use:   "edit [issue-id] [comment-id] [text]",
+	Short: "Edit a comment",
+	Long: `Replace a comment's text. The old text is kept; see it with bd comments history.
+
+Examples:
+  bd comments edit bd-123 5 "Fixed the typo"
+  bd comments edit bd-123 5 -f notes.txt`,
+	Args: cobra.RangeArgs(2, 3),
+	Run: func(cmd *cobra.Command, args []string) {
+		issueID, commentID := args[0], commentIDArg(args[1])
+		commentText := commentTextArg(cmd, args, 2)
+		if err := ensureDirectMode("daemon does not support comment edit"); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		comment, err := store.UpdateIssueComment(context.Background(), issueID, commentID, commentAuthor(cmd), commentText)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error editing comment: %v\n", err)
+			os.Exit(1)
+		}
+		markDirtyAndScheduleFlush()
+
+		if jsonOutput {
+			outputJSON(comment)
+			return
+		}
+		fmt.Printf("Comment #%d on %s updated\n", commentID, issueID)
+	},
+}
+
+var commentsDeleteCmd = &cobra.Command{
+	Use:   "delete [issue-id] [comment-id]",
+	Short: "Delete a comment",
+	Long: `Delete a comment with its edit history and reactions.
+
+Replies to the deleted comment move up to answer the comment it answered.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		issueID, commentID := args[0], commentIDArg(args[1])
+		if err := ensureDirectMode("daemon does not support comment delete"); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if err := store.DeleteIssueComment(context.Background(), issueID, commentID); err != nil {
+			fmt.Fprintf(os.Stderr, "Error deleting comment: %v\n", err)
+			os.Exit(1)
+		}
+		markDirtyAndScheduleFlush()
+
+		if jsonOutput {
+			outputJSON(map[string]interface{}{"issue_id": issueID, "comment_id": commentID, "deleted": true})
+			return
+		}
+		fmt.Printf("Comment #%d deleted from %s\n", commentID, issueID)
+	},
+}
+
+var commentsReactCmd = &cobra.Command{
+	Use:   "react [issue-id] [comment-id] [reaction]",
+	Short: "React to a comment",
+	Long: `React to a comment, e.g. with 👍 or +1. Reacting the same way twice counts once.
+
+Examples:
+  bd comments react bd-123 5 👍
+  bd comments react bd-123 5 👍 --remove`,
+	Args: cobra.ExactArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		issueID, commentID, reaction := args[0], commentIDArg(args[1]), args[2]
+		remove, _ := cmd.Flags().GetBool("remove")
+		if err := ensureDirectMode("daemon does not support comment reactions"); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		ctx := context.Background()
+		var comment *types.Comment
+		var err error
+		if remove {
+			comment, err = store.RemoveCommentReaction(ctx, issueID, commentID, commentAuthor(cmd), reaction)
+		} else {
+			comment, err = store.AddCommentReaction(ctx, issueID, commentID, commentAuthor(cmd), reaction)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		markDirtyAndScheduleFlush()
+
+		if jsonOutput {
+			outputJSON(comment)
+			return
+		}
+		if len(comment.Reactions) == 0 {
+			fmt.Printf("Comment #%d on %s has no reactions\n", commentID, issueID)
+			return
+		}
+		fmt.Printf("Comment #%d on %s: %s\n", commentID, issueID, types.FormatReactions(comment.Reactions))
+	},
+}
+
+var commentsHistoryCmd = &cobra.Command{
+	Use:   "history [issue-id] [comment-id]",
+	Short: "Show a comment's earlier versions",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		issueID, commentID := args[0], commentIDArg(args[1])
+		if err := ensureDirectMode("daemon does not support comment history"); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		edits, err := store.GetCommentEdits(context.Background(), issueID, commentID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting comment history: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			if edits == nil {
+				edits = []*types.CommentEdit{}
+			}
+			outputJSON(edits)
+			return
+		}
+		if len(edits) == 0 {
+			fmt.Printf("Comment #%d on %s has not been edited\n", commentID, issueID)
+			return
+		}
+		for _, edit := range edits {
+			fmt.Printf("Replaced by %s at %s:\n  %s\n\n", edit.EditedBy, edit.EditedAt.Format("2006-01-02 15:04"), edit.Text)
+		}
 	},
 }
 
 func init() {
-	commentsCmd.AddCommand(commentsAddCmd)
+	commentsCmd.AddCommand(commentsAddCmd, commentsEditCmd, commentsDeleteCmd, commentsReactCmd, commentsHistoryCmd)
+	commentsCmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "Output JSON format")
 	commentsAddCmd.Flags().StringP("file", "f", "", "Read comment text from file")
 	commentsAddCmd.Flags().StringP("author", "a", "", "Add author to comment")
+	commentsAddCmd.Flags().Int64("reply-to", 0, "ID of the comment this one answers")
+	commentsEditCmd.Flags().StringP("file", "f", "", "Read comment text from file")
+	commentsEditCmd.Flags().StringP("author", "a", "", "Who is making the edit")
+	commentsReactCmd.Flags().StringP("author", "a", "", "Who is reacting")
+	commentsReactCmd.Flags().Bool("remove", false, "Take the reaction back")
 	rootCmd.AddCommand(commentsCmd)
 }
 
+// commentTextArg reads comment text from --file, or else from args[i]
+func commentTextArg(cmd *cobra.Command, args []string, i int) string {
+	if path, _ := cmd.Flags().GetString("file"); path != "" {
+		data, err := os.ReadFile(path) // #nosec G304 - user-provided file path is intentional
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading file: %v\n", err)
+			os.Exit(1)
+		}
+		return string(data)
+	}
+	if len(args) <= i {
+		fmt.Fprintf(os.Stderr, "Error: comment text required (use -f to read from file)\n")
+		os.Exit(1)
+	}
+	return args[i]
+}
+
+// commentAuthor gets the author from the author flag, BD_ACTOR var, or system USER var
+func commentAuthor(cmd *cobra.Command) string {
+	author, _ := cmd.Flags().GetString("author")
+	if author == "" {
+		author = os.Getenv("BD_ACTOR")
+		if author == "" {
+			author = os.Getenv("USER")
+		}
+		if author == "" {
+			if u, err := user.Current(); err == nil {
+				author = u.Username
+			} else {
+				author = "unknown"
+			}
+		}
+	}
+	return author
+}
+
+func commentIDArg(arg string) int64 {
+	id, err := strconv.ParseInt(strings.TrimPrefix(arg, "#"), 10, 64)
+	if err != nil || id <= 0 {
+		fmt.Fprintf(os.Stderr, "Error: invalid comment ID %q\n", arg)
+		os.Exit(1)
+	}
+	return id
+}
+
 func isUnknownOperationError(err error) bool {
 	if err == nil {
 		return false
@@ -33,7 +33,11 @@ actively referenced. This is permanent graceful decay - original content is disc
 
 Tiers:
   - Tier 1: Semantic compression (30 days closed, 70% reduction)
-  - Tier 2: Ultra compression (90 days closed, 95% reduction)
+  - Tier 2: Ultra compression (90 days closed, 95% reduction). Keeps the first
+    sentence of the Tier 1 summary and drops verbose events; no API key needed.
+
+With --all, issues in the trash longer than compact_trash_days (default 30,
+0 to keep them) are also deleted permanently.
 
 Examples:
   bd compact --dry-run                  # Preview candidates
@@ -88,8 +92,9 @@ Examples:
 			os.Exit(1)
 		}
 
+		// Tier 2 is local; only Tier 1 summarizes with the API
 		apiKey := os.Getenv("ANTHROPIC_API_KEY")
-		if apiKey == "" && !compactDryRun {
+		if apiKey == "" && !compactDryRun && compactTier == 1 {
 			fmt.Fprintf(os.Stderr, "Error: ANTHROPIC_API_KEY environment variable not set\n")
 			os.Exit(1)
 		}
@@ -162,8 +167,7 @@ func runCompactSingle(ctx context.Context, compactor *compact.Compactor, store *
 	if compactTier == 1 {
 		compactErr = compactor.CompactTier1(ctx, issueID)
 	} else {
-		fmt.Fprintf(os.Stderr, "Error: Tier 2 compaction not yet implemented\n")
-		os.Exit(1)
+		compactErr = compactor.CompactTier2(ctx, issueID)
 	}
 
 	if compactErr != nil {
@@ -209,28 +213,75 @@ func runCompactSingle(ctx context.Context, compactor *compact.Compactor, store *
 func runCompactAll(ctx context.Context, compactor *compact.Compactor, store *sqlite.SQLiteStorage) {
 	start := time.Now()
 
-	var candidates []string
-	if compactTier == 1 {
-		tier1, err := store.GetTier1Candidates(ctx)
+	purgeExpiredTrash(ctx, store)
+
+	// Candidates are compacted a page at a time, so a large backlog isn't
+	// loaded whole
+	candidateCount := 0
+	totalSize := 0
+	successCount := 0
+	failCount := 0
+	totalSaved := 0
+	totalOriginal := 0
+	// A first pass counts the candidates for the progress bar
+	total := 0
+	if !compactDryRun && !jsonOutput {
+		err := store.ForEachCandidatePage(ctx, compactTier, func(page []*sqlite.CompactionCandidate) error {
+			total += len(page)
+			return nil
+		})
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: failed to get candidates: %v\n", err)
 			os.Exit(1)
 		}
-		for _, c := range tier1 {
-			candidates = append(candidates, c.IssueID)
+		if total > 0 {
+			fmt.Printf("Compacting %d issues (Tier %d)...\n\n", total, compactTier)
+		}
+	}
+	err := store.ForEachCandidatePage(ctx, compactTier, func(page []*sqlite.CompactionCandidate) error {
+		ids := compact.CandidateIDs(page)
+		candidateCount += len(ids)
+		if compactDryRun {
+			for _, id := range ids {
+				issue, err := store.GetIssue(ctx, id)
+				if err != nil || issue == nil {
+					continue
+				}
+				totalSize += len(issue.Description) + len(issue.Design) + len(issue.Notes) + len(issue.AcceptanceCriteria)
+			}
+			return nil
+		}
+
+		var results []*compact.Result
+		var err error
+		if compactTier == 1 {
+			results, err = compactor.CompactTier1Batch(ctx, ids)
+		} else {
+			results, err = compactor.CompactTier2Batch(ctx, ids)
 		}
-	} else {
-		tier2, err := store.GetTier2Candidates(ctx)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: failed to get candidates: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("batch compaction failed: %w", err)
+		}
+		for _, result := range results {
+			if result.Err != nil {
+				failCount++
+			} else {
+				successCount++
+				totalOriginal += result.OriginalSize
+				totalSaved += (result.OriginalSize - result.CompactedSize)
+			}
 		}
-		for _, c := range tier2 {
-			candidates = append(candidates, c.IssueID)
+		if done := successCount + failCount; !jsonOutput {
+			fmt.Printf("[%s] %d/%d\r", progressBar(done, max(total, done)), done, max(total, done))
 		}
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
 	}
 
-	if len(candidates) == 0 {
+	if candidateCount == 0 {
 		if jsonOutput {
 			outputJSON(map[string]interface{}{
 				"success": true,
@@ -244,20 +295,11 @@ func runCompactAll(ctx context.Context, compactor *compact.Compactor, store *sql
 	}
 
 	if compactDryRun {
-		totalSize := 0
-		for _, id := range candidates {
-			issue, err := store.GetIssue(ctx, id)
-			if err != nil {
-				continue
-			}
-			totalSize += len(issue.Description) + len(issue.Design) + len(issue.Notes) + len(issue.AcceptanceCriteria)
-		}
-
 		if jsonOutput {
 			output := map[string]interface{}{
 				"dry_run":             true,
 				"tier":                compactTier,
-				"candidate_count":     len(candidates),
+				"candidate_count":     candidateCount,
 				"total_size_bytes":    totalSize,
 				"estimated_reduction": "70-80%",
 			}
@@ -266,48 +308,19 @@ func runCompactAll(ctx context.Context, compactor *compact.Compactor, store *sql
 		}
 
 		fmt.Printf("DRY RUN - Tier %d compaction\n\n", compactTier)
-		fmt.Printf("Candidates: %d issues\n", len(candidates))
+		fmt.Printf("Candidates: %d issues\n", candidateCount)
 		fmt.Printf("Total size: %d bytes\n", totalSize)
 		fmt.Printf("Estimated reduction: 70-80%%\n")
 		return
 	}
 
-	if !jsonOutput {
-		fmt.Printf("Compacting %d issues (Tier %d)...\n\n", len(candidates), compactTier)
-	}
-
-	results, err := compactor.CompactTier1Batch(ctx, candidates)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: batch compaction failed: %v\n", err)
-		os.Exit(1)
-	}
-
-	successCount := 0
-	failCount := 0
-	totalSaved := 0
-	totalOriginal := 0
-
-	for i, result := range results {
-		if !jsonOutput {
-			fmt.Printf("[%s] %d/%d\r", progressBar(i+1, len(results)), i+1, len(results))
-		}
-
-		if result.Err != nil {
-			failCount++
-		} else {
-			successCount++
-			totalOriginal += result.OriginalSize
-			totalSaved += (result.OriginalSize - result.CompactedSize)
-		}
-	}
-
 	elapsed := time.Since(start)
 
 	if jsonOutput {
 		output := map[string]interface{}{
 			"success":       true,
 			"tier":          compactTier,
-			"total":         len(results),
+			"total":         successCount + failCount,
 			"succeeded":     successCount,
 			"failed":        failCount,
 			"saved_bytes":   totalSaved,
@@ -332,37 +345,50 @@ func runCompactAll(ctx context.Context, compactor *compact.Compactor, store *sql
 	}
 }
 
-func runCompactStats(ctx context.Context, store *sqlite.SQLiteStorage) {
-	tier1, err := store.GetTier1Candidates(ctx)
+// purgeExpiredTrash permanently deletes issues that have been in the trash
+// longer than compact_trash_days (0 disables)
+func purgeExpiredTrash(ctx context.Context, store *sqlite.SQLiteStorage) {
+	ids, err := compact.PurgeExpiredTrash(ctx, store, compactDryRun)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: failed to get Tier 1 candidates: %v\n", err)
-		os.Exit(1)
+		fmt.Fprintf(os.Stderr, "Warning: failed to purge trash: %v\n", err)
+		return
 	}
-
-	tier2, err := store.GetTier2Candidates(ctx)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: failed to get Tier 2 candidates: %v\n", err)
-		os.Exit(1)
+	if len(ids) == 0 || jsonOutput {
+		return
 	}
-
-	tier1Size := 0
-	for _, c := range tier1 {
-		tier1Size += c.OriginalSize
+	if compactDryRun {
+		fmt.Printf("Would purge %d issue(s) from the trash: %v\n\n", len(ids), ids)
+		return
 	}
+	markDirtyAndScheduleFlush()
+	fmt.Printf("Purged %d issue(s) from the trash\n\n", len(ids))
+}
 
-	tier2Size := 0
-	for _, c := range tier2 {
-		tier2Size += c.OriginalSize
+func runCompactStats(ctx context.Context, store *sqlite.SQLiteStorage) {
+	var tierCount, tierSize [3]int
+	for tier := 1; tier <= 2; tier++ {
+		err := store.ForEachCandidatePage(ctx, tier, func(page []*sqlite.CompactionCandidate) error {
+			tierCount[tier] += len(page)
+			for _, c := range page {
+				tierSize[tier] += c.OriginalSize
+			}
+			return nil
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to get Tier %d candidates: %v\n", tier, err)
+			os.Exit(1)
+		}
 	}
+	tier1Size, tier2Size := tierSize[1], tierSize[2]
 
 	if jsonOutput {
 		output := map[string]interface{}{
 			"tier1": map[string]interface{}{
-				"candidates": len(tier1),
+				"candidates": tierCount[1],
 				"total_size": tier1Size,
 			},
 			"tier2": map[string]interface{}{
-				"candidates": len(tier2),
+				"candidates": tierCount[2],
 				"total_size": tier2Size,
 			},
 		}
@@ -372,14 +398,14 @@ func runCompactStats(ctx context.Context, store *sqlite.SQLiteStorage) {
 
 	fmt.Println("Compaction Statistics")
 	fmt.Printf("Tier 1 (30+ days closed):\n")
-	fmt.Printf("  Candidates: %d\n", len(tier1))
+	fmt.Printf("  Candidates: %d\n", tierCount[1])
 	fmt.Printf("  Total size: %d bytes\n", tier1Size)
 	if tier1Size > 0 {
 		fmt.Printf("  Estimated savings: %d bytes (70%%)\n\n", tier1Size*7/10)
 	}
 
 	fmt.Printf("Tier 2 (90+ days closed, Tier 1 compacted):\n")
-	fmt.Printf("  Candidates: %d\n", len(tier2))
+	fmt.Printf("  Candidates: %d\n", tierCount[2])
 	fmt.Printf("  Total size: %d bytes\n", tier2Size)
 	if tier2Size > 0 {
 		fmt.Printf("  Estimated savings: %d bytes (95%%)\n", tier2Size*95/100)
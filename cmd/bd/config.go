@@ -5,7 +5,11 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"strings"
 
+	"github.com/imalsogreg/beads/internal/secrets"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
 	"github.com/spf13/cobra"
 )
 
@@ -21,10 +25,18 @@ Common namespaces:
   - linear.*   Linear integration settings
   - github.*   GitHub integration settings
   - custom.*   Custom integration settings
+  - secrets.mode  Handling of credentials pasted into issue text
+                  (off, flag, mask, reject)
+  - workflow      Custom statuses and transitions, as JSON
+                  (see bd workflow)
+  - validation.required.<type>
+                  Fields issues of a type must have, comma-separated
+                  (e.g. validation.required.bug = description,acceptance_criteria)
 
 Examples:
   bd config set jira.url "https://company.atlassian.net"
   bd config set jira.project "PROJ"
+  bd config set validation.required.bug description,acceptance_criteria
   bd config get jira.url
   bd config list
   bd config unset jira.url`,
@@ -44,8 +56,41 @@ var configSetCmd = &cobra.Command{
 		key := args[0]
 		value := args[1]
 
+		if key == "secrets.mode" {
+			if _, err := secrets.ParseMode(value); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
 		ctx := context.Background()
-		if err := store.SetConfig(ctx, key, value); err != nil {
+		if key == sqlite.WorkflowConfigKey {
+			// Checked and stored as by bd workflow set
+			workflow, err := types.ParseWorkflow([]byte(value))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if err := requireWorkflowStore().SetWorkflow(ctx, workflow); err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting config: %v\n", err)
+				os.Exit(1)
+			}
+		} else if issueType, ok := strings.CutPrefix(key, sqlite.RequiredFieldsConfigPrefix); ok {
+			fields, err := types.ParseRequiredFields(value)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+			if !ok {
+				fmt.Fprintf(os.Stderr, "Error: required fields require SQLite backend\n")
+				os.Exit(1)
+			}
+			if err := sqliteStore.SetRequiredFields(ctx, types.IssueType(issueType), fields); err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting config: %v\n", err)
+				os.Exit(1)
+			}
+		} else if err := store.SetConfig(ctx, key, value); err != nil {
 			fmt.Fprintf(os.Stderr, "Error setting config: %v\n", err)
 			os.Exit(1)
 		}
@@ -3,9 +3,11 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -64,6 +66,20 @@ var createCmd = &cobra.Command{
 		deps, _ := cmd.Flags().GetStringSlice("deps")
 		forceCreate, _ := cmd.Flags().GetBool("force")
 		jsonOutput, _ := cmd.Flags().GetBool("json")
+		due, _ := cmd.Flags().GetString("due")
+		start, _ := cmd.Flags().GetString("start")
+
+		now := time.Now()
+		dueDate, err := types.ParseOptionalDate(due, now)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --due: %v\n", err)
+			os.Exit(1)
+		}
+		startDate, err := types.ParseOptionalDate(start, now)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --start: %v\n", err)
+			os.Exit(1)
+		}
 
 		// Validate explicit ID format if provided (prefix-number)
 		if explicitID != "" {
@@ -122,6 +138,8 @@ var createCmd = &cobra.Command{
 				Assignee:           assignee,
 				Labels:             labels,
 				Dependencies:       deps,
+				DueDate:            due,
+				StartDate:          start,
 			}
 
 			resp, err := daemonClient.Create(createArgs)
@@ -143,6 +161,7 @@ var createCmd = &cobra.Command{
 				fmt.Printf("  Title: %s\n", issue.Title)
 				fmt.Printf("  Priority: P%d\n", issue.Priority)
 				fmt.Printf("  Status: %s\n", issue.Status)
+				printSchedule(&issue)
 			}
 			return
 		}
@@ -159,11 +178,13 @@ var createCmd = &cobra.Command{
 			IssueType:          types.IssueType(issueType),
 			Assignee:           assignee,
 			ExternalRef:        externalRefPtr,
+			DueDate:            dueDate,
+			StartDate:          startDate,
 		}
 
 		ctx := context.Background()
 		if err := store.CreateIssue(ctx, issue, actor); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			printCreateError(err)
 			os.Exit(1)
 		}
 
@@ -202,7 +223,7 @@ var createCmd = &cobra.Command{
 
 			// Validate dependency type
 			if !depType.IsValid() {
-				fmt.Fprintf(os.Stderr, "Warning: invalid dependency type '%s' (valid: blocks, related, parent-child, discovered-from)\n", depType)
+				fmt.Fprintf(os.Stderr, "Warning: invalid dependency type '%s' (valid: blocks, related, relates-to, parent-child, discovered-from, duplicates, caused-by)\n", depType)
 				continue
 			}
 
@@ -228,10 +249,35 @@ var createCmd = &cobra.Command{
 			fmt.Printf("  Title: %s\n", issue.Title)
 			fmt.Printf("  Priority: P%d\n", issue.Priority)
 			fmt.Printf("  Status: %s\n", issue.Status)
+			printSchedule(issue)
 		}
 	},
 }
 
+// printCreateError prints why an issue couldn't be created, one line per
+// problem for an invalid issue
+func printCreateError(err error) {
+	var invalid *types.ValidationError
+	if !errors.As(err, &invalid) || len(invalid.Violations) < 2 {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Error: the issue has %d problems:\n", len(invalid.Violations))
+	for _, v := range invalid.Violations {
+		fmt.Fprintf(os.Stderr, "  %s: %s\n", v.Field, v.Message)
+	}
+}
+
+// printSchedule prints an issue's start and due dates, if set
+func printSchedule(issue *types.Issue) {
+	if issue.StartDate != nil {
+		fmt.Printf("  Start: %s\n", issue.StartDate.Local().Format("2006-01-02"))
+	}
+	if issue.DueDate != nil {
+		fmt.Printf("  Due: %s\n", issue.DueDate.Local().Format("2006-01-02"))
+	}
+}
+
 func init() {
 	createCmd.Flags().StringP("file", "f", "", "Create multiple issues from markdown file")
 	createCmd.Flags().String("title", "", "Issue title (alternative to positional argument)")
@@ -245,6 +291,8 @@ func init() {
 	createCmd.Flags().String("id", "", "Explicit issue ID (e.g., 'bd-42' for partitioning)")
 	createCmd.Flags().String("external-ref", "", "External reference (e.g., 'gh-9', 'jira-ABC')")
 	createCmd.Flags().StringSlice("deps", []string{}, "Dependencies in format 'type:id' or 'id' (e.g., 'discovered-from:bd-20,blocks:bd-15' or 'bd-20')")
+	createCmd.Flags().String("due", "", "Due date (YYYY-MM-DD, today, tomorrow, or +Nd/+Nw)")
+	createCmd.Flags().String("start", "", "Start date; the issue isn't ready work before it (same formats as --due)")
 	createCmd.Flags().Bool("force", false, "Force creation even if prefix doesn't match database prefix")
 	createCmd.Flags().Bool("json", false, "Output JSON format")
 	rootCmd.AddCommand(createCmd)
@@ -56,6 +56,10 @@ Use --health to check daemon health and metrics.`,
 			fmt.Fprintf(os.Stderr, "Error: interval must be positive (got %v)\n", interval)
 			os.Exit(1)
 		}
+		if driver := storage.DSNDriver(dbPath); driver != sqlite.DriverName {
+			fmt.Fprintf(os.Stderr, "Error: the daemon only serves SQLite databases, not %s\n", driver)
+			os.Exit(1)
+		}
 
 		pidFile, err := getPIDFilePath(global)
 		if err != nil {
@@ -737,7 +741,12 @@ func exportToJSONLWithStore(ctx context.Context, store storage.Storage, jsonlPat
 
 	// Write JSONL
 	for _, issue := range issues {
-		data, marshalErr := json.Marshal(issue)
+		exported, encErr := exportableIssue(store, issue)
+		if encErr != nil {
+			writeErr = fmt.Errorf("failed to encrypt issue %s: %w", issue.ID, encErr)
+			return writeErr
+		}
+		data, marshalErr := json.Marshal(exported)
 		if marshalErr != nil {
 			writeErr = fmt.Errorf("failed to marshal issue %s: %w", issue.ID, marshalErr)
 			return writeErr
@@ -1054,6 +1063,9 @@ func createSyncFunc(ctx context.Context, store storage.Storage, autoCommit, auto
 			log.log("Found %d orphaned dependencies: %v", len(orphaned), orphaned)
 		}
 
+		// Run due maintenance tasks (retention, etc.) before exporting their effects
+		runScheduledTasks(syncCtx, store, log)
+
 		if err := exportToJSONLWithStore(syncCtx, store, jsonlPath); err != nil {
 			log.log("Export failed: %v", err)
 			return
@@ -1228,7 +1240,7 @@ func runDaemonLoop(interval time.Duration, autoCommit, autoPush bool, logPath, p
 
 	log.log("Using database: %s", daemonDBPath)
 
-	store, err := sqlite.New(daemonDBPath)
+	store, err := sqlite.NewWithConfig(daemonDBPath, sqlitePoolConfig())
 	if err != nil {
 		log.log("Error: cannot open database: %v", err)
 		os.Exit(1)
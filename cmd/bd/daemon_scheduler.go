@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/autoclose"
+	"github.com/imalsogreg/beads/internal/stale"
+	"github.com/imalsogreg/beads/internal/storage"
+)
+
+// scheduledTask is periodic maintenance run by the daemon. Each task runs at
+// most once per interval; the last run time is kept in metadata so restarts
+// don't reset the schedule.
+type scheduledTask struct {
+	name            string
+	enabledKey      string        // config key that must be "true" for the task to run
+	enabledDefault  bool          // run when enabledKey is unset
+	defaultInterval time.Duration // overridable with config key schedule.<name>.interval
+	run             func(ctx context.Context, store storage.Storage, log daemonLogger) error
+}
+
+// scheduledTasks lists the daemon's maintenance tasks
+var scheduledTasks = []scheduledTask{
+	{
+		name:            "retention",
+		enabledKey:      "retention.enabled",
+		defaultInterval: 24 * time.Hour,
+		run:             runScheduledRetention,
+	},
+	{
+		name:            "stale",
+		enabledKey:      stale.NudgeConfigKey,
+		defaultInterval: 24 * time.Hour,
+		run:             runScheduledStaleNudge,
+	},
+	{
+		name:            "escalation",
+		enabledKey:      "escalation.enabled",
+		defaultInterval: 24 * time.Hour,
+		run:             runScheduledEscalation,
+	},
+	{
+		name:            "epic-autoclose",
+		enabledKey:      autoclose.ConfigKey,
+		defaultInterval: 5 * time.Minute,
+		run:             runScheduledEpicAutoclose,
+	},
+	{
+		name:            "claims",
+		enabledKey:      "claims.expire",
+		enabledDefault:  true,
+		defaultInterval: time.Minute,
+		run:             runScheduledLeaseExpiry,
+	},
+}
+
+// runScheduledTasks runs every enabled task whose interval has elapsed
+func runScheduledTasks(ctx context.Context, store storage.Storage, log daemonLogger) {
+	now := time.Now()
+	for _, task := range scheduledTasks {
+		enabled, _ := store.GetConfig(ctx, task.enabledKey)
+		if enabled == "" && task.enabledDefault {
+			enabled = "true"
+		}
+		if enabled != "true" {
+			continue
+		}
+
+		interval := task.defaultInterval
+		if v, _ := store.GetConfig(ctx, "schedule."+task.name+".interval"); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil || d <= 0 {
+				log.log("Scheduler: invalid schedule.%s.interval %q, using %v", task.name, v, interval)
+			} else {
+				interval = d
+			}
+		}
+
+		lastRunKey := "schedule." + task.name + ".last_run"
+		if last, _ := store.GetMetadata(ctx, lastRunKey); last != "" {
+			if t, err := time.Parse(time.RFC3339, last); err == nil && now.Sub(t) < interval {
+				continue
+			}
+		}
+
+		log.log("Scheduler: running %s", task.name)
+		if err := task.run(ctx, store, log); err != nil {
+			log.log("Scheduler: %s failed: %v", task.name, err)
+			continue
+		}
+		if err := store.SetMetadata(ctx, lastRunKey, now.UTC().Format(time.RFC3339)); err != nil {
+			log.log("Scheduler: failed to record %s run: %v", task.name, err)
+		}
+	}
+}
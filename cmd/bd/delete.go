@@ -17,10 +17,22 @@ import (
 
 var deleteCmd = &cobra.Command{
 	Use:   "delete <issue-id> [issue-id...]",
-	Short: "Delete one or more issues and clean up references",
-	Long: `Delete one or more issues and clean up all references to them.
+	Short: "Move issues to the trash, or delete them permanently",
+	Long: `Move one or more issues to the trash, or with --force delete them permanently
+and clean up all references to them.
 
-This command will:
+TRASH:
+
+Without --force, issues are moved to the trash. They keep their links and
+history but are hidden from list, ready, search, and stats. Bring one back
+with 'bd restore <id>'; see what's there with 'bd trash list'. Compaction
+purges issues that have been in the trash for compact_trash_days (default 30).
+
+  bd delete bd-1 bd-2
+
+PERMANENT DELETION:
+
+With --force this command will:
 1. Remove all dependency links (any type, both directions) involving the issues
 2. Update text references to "[deleted:ID]" in directly connected issues
 3. Delete the issues from the database
@@ -77,6 +89,12 @@ Force: Delete and orphan dependents
 		// Remove duplicates
 		issueIDs = uniqueStrings(issueIDs)
 
+		// Without --force (or a preview), move the issues to the trash
+		if !force && !dryRun && !cascade {
+			trashIssues(issueIDs)
+			return
+		}
+
 		// Handle batch deletion
 		if len(issueIDs) > 1 {
 			deleteBatch(cmd, issueIDs, force, dryRun, cascade)
@@ -189,7 +207,8 @@ Force: Delete and orphan dependents
 			}
 
 			fmt.Printf("\n%s\n", yellow("This operation cannot be undone!"))
-			fmt.Printf("To proceed, run: %s\n\n", yellow("bd delete "+issueID+" --force"))
+			fmt.Printf("To proceed, run: %s\n", yellow("bd delete "+issueID+" --force"))
+			fmt.Printf("To move it to the trash instead, run: %s\n\n", yellow("bd delete "+issueID))
 			return
 		}
 
@@ -643,7 +662,7 @@ func uniqueStrings(slice []string) []string {
 }
 
 func init() {
-	deleteCmd.Flags().BoolP("force", "f", false, "Actually delete (without this flag, shows preview)")
+	deleteCmd.Flags().BoolP("force", "f", false, "Delete permanently instead of moving to the trash")
 	deleteCmd.Flags().String("from-file", "", "Read issue IDs from file (one per line)")
 	deleteCmd.Flags().Bool("dry-run", false, "Preview what would be deleted without making changes")
 	deleteCmd.Flags().Bool("cascade", false, "Recursively delete all dependent issues")
@@ -8,6 +8,8 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"github.com/imalsogreg/beads/internal/depgraph"
+	"github.com/imalsogreg/beads/internal/remote"
 	"github.com/imalsogreg/beads/internal/rpc"
 	"github.com/imalsogreg/beads/internal/storage/sqlite"
 	"github.com/imalsogreg/beads/internal/types"
@@ -21,12 +23,20 @@ var depCmd = &cobra.Command{
 var depAddCmd = &cobra.Command{
 	Use:   "add [issue-id] [depends-on-id]",
 	Short: "Add a dependency",
+	Long: `Add a dependency: issue-id depends on depends-on-id.
+
+depends-on-id can be an issue in another workspace, written remote/id (for
+example platform/bd-12), where remote is an entry of the remotes table in
+config.yaml. 'bd dep tree' and 'bd show' fetch such issues from the remote's
+bd serve API. Ready work doesn't wait on them, since their status isn't known
+locally.`,
 	Args:  cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
 		depType, _ := cmd.Flags().GetString("type")
 
 		// If daemon is running, use RPC
 		if daemonClient != nil {
+			warnUnknownRemote(args[1])
 			depArgs := &rpc.DepAddArgs{
 				FromID:  args[0],
 				ToID:    args[1],
@@ -56,6 +66,7 @@ var depAddCmd = &cobra.Command{
 			DependsOnID: args[1],
 			Type:        types.DependencyType(depType),
 		}
+		warnUnknownRemote(args[1])
 
 		ctx := context.Background()
 		if err := store.AddDependency(ctx, dep, actor); err != nil {
@@ -107,6 +118,17 @@ var depAddCmd = &cobra.Command{
 	},
 }
 
+// warnUnknownRemote warns when a dependency names another workspace that
+// isn't in the remotes table, since it can't be resolved until it is
+func warnUnknownRemote(dependsOnID string) {
+	ref, ok := remote.ParseRef(dependsOnID)
+	if !ok || mustRemoteRegistry().Has(ref.Remote) {
+		return
+	}
+	yellow := color.New(color.FgYellow).SprintFunc()
+	fmt.Fprintf(os.Stderr, "%s No remote named %q in config.yaml; add it under remotes: so %s can be resolved\n", yellow("⚠"), ref.Remote, ref)
+}
+
 var depRemoveCmd = &cobra.Command{
 	Use:   "remove [issue-id] [depends-on-id]",
 	Short: "Remove a dependency",
@@ -192,6 +214,14 @@ var depTreeCmd = &cobra.Command{
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
+		if !reverse {
+			// Dependencies on other workspaces' issues are fetched from their servers
+			records := func(id string) ([]*types.Dependency, error) { return store.GetDependencyRecords(ctx, id) }
+			if tree, err = mustRemoteRegistry().ExpandTree(ctx, tree, records); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
 
 		if jsonOutput {
 			// Always output array, even if empty
@@ -226,6 +256,13 @@ var depTreeCmd = &cobra.Command{
 			}
 			line := fmt.Sprintf("%s→ %s: %s [P%d] (%s)",
 				indent, node.ID, node.Title, node.Priority, node.Status)
+			if node.Unreachable != "" {
+				yellow := color.New(color.FgYellow).SprintFunc()
+				line = fmt.Sprintf("%s→ %s: %s", indent, node.ID, yellow("unreachable: "+node.Unreachable))
+			}
+			if node.DepType.IsLink() {
+				line += fmt.Sprintf(" [%s]", node.DepType)
+			}
 			if node.Truncated {
 				line += " … [truncated]"
 				hasTruncation = true
@@ -242,6 +279,71 @@ var depTreeCmd = &cobra.Command{
 	},
 }
 
+var depGraphCmd = &cobra.Command{
+	Use:   "graph [issue-id]",
+	Short: "Render the dependency graph as Graphviz DOT or Mermaid",
+	Long: `Render dependencies as a graph for visualization.
+
+With an issue ID, renders that issue's dependency tree (or its dependents
+with --reverse). Without one, renders every issue and the dependencies
+between them.
+
+DOT nodes are filled by status and shaped by issue type (epic=folder,
+feature=component, task=box, bug=octagon, chore=note).
+
+Examples:
+  bd dep graph bd-1 | dot -Tsvg > bd-1.svg
+  bd dep graph --format mermaid > deps.mmd`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// If daemon is running but doesn't support this command, use direct storage
+		if daemonClient != nil && store == nil {
+			var err error
+			store, err = sqlite.New(dbPath)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: failed to open database: %v\n", err)
+				os.Exit(1)
+			}
+			defer func() { _ = store.Close() }()
+		}
+
+		format, _ := cmd.Flags().GetString("format")
+		maxDepth, _ := cmd.Flags().GetInt("max-depth")
+		reverse, _ := cmd.Flags().GetBool("reverse")
+
+		if format != depgraph.FormatDOT && format != depgraph.FormatMermaid {
+			fmt.Fprintf(os.Stderr, "Error: --format must be dot or mermaid\n")
+			os.Exit(1)
+		}
+		if maxDepth < 1 {
+			fmt.Fprintf(os.Stderr, "Error: --max-depth must be >= 1\n")
+			os.Exit(1)
+		}
+
+		ctx := context.Background()
+		var g *depgraph.Graph
+		var err error
+		if len(args) == 1 {
+			g, err = depgraph.ForTree(ctx, store, args[0], maxDepth, reverse)
+		} else {
+			var issues []*types.Issue
+			issues, err = store.SearchIssues(ctx, "", types.IssueFilter{})
+			if err == nil {
+				g, err = depgraph.Build(ctx, store, issues)
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if err := depgraph.Write(os.Stdout, g, format); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
 var depCyclesCmd = &cobra.Command{
 	Use:   "cycles",
 	Short: "Detect dependency cycles",
@@ -292,13 +394,17 @@ var depCyclesCmd = &cobra.Command{
 }
 
 func init() {
-	depAddCmd.Flags().StringP("type", "t", "blocks", "Dependency type (blocks|related|parent-child|discovered-from)")
+	depAddCmd.Flags().StringP("type", "t", "blocks", "Dependency type (blocks|related|relates-to|parent-child|discovered-from|duplicates|caused-by)")
 	depTreeCmd.Flags().Bool("show-all-paths", false, "Show all paths to nodes (no deduplication for diamond dependencies)")
 	depTreeCmd.Flags().IntP("max-depth", "d", 50, "Maximum tree depth to display (safety limit)")
 	depTreeCmd.Flags().Bool("reverse", false, "Show dependent tree (what was discovered from this) instead of dependency tree (what blocks this)")
 	depCmd.AddCommand(depAddCmd)
 	depCmd.AddCommand(depRemoveCmd)
+	depGraphCmd.Flags().StringP("format", "f", "dot", "Output format: dot (Graphviz) or mermaid")
+	depGraphCmd.Flags().IntP("max-depth", "d", 50, "Maximum depth to follow from the issue")
+	depGraphCmd.Flags().Bool("reverse", false, "Graph dependents of the issue instead of its dependencies")
 	depCmd.AddCommand(depTreeCmd)
+	depCmd.AddCommand(depGraphCmd)
 	depCmd.AddCommand(depCyclesCmd)
 	rootCmd.AddCommand(depCmd)
 }
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/imalsogreg/beads/internal/digest"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/spf13/cobra"
+)
+
+var digestCmd = &cobra.Command{
+	Use:   "digest",
+	Short: "Manage email digests of assigned work",
+	Long: `Email assignees a daily or weekly summary of their open issues, issues
+that became blocked since the last digest, and stale in-progress work.
+
+Digests are sent by 'bd serve' once enabled and SMTP is configured:
+  bd config set digest.enabled true
+  bd config set smtp.host smtp.example.com
+  bd config set smtp.port 587                  # default
+  bd config set smtp.username beads@example.com
+  bd config set smtp.from "Beads <beads@example.com>"
+  export BEADS_SMTP_PASSWORD=...               # or: bd config set smtp.password ...
+  bd config set digest.stale_days 7            # default
+
+Examples:
+  bd digest subscribe alice --email alice@example.com
+  bd digest subscribe bob --email bob@example.com --frequency weekly
+  bd digest preview alice
+  bd digest send alice
+  bd digest list
+  bd digest unsubscribe bob`,
+}
+
+var digestSubscribeCmd = &cobra.Command{
+	Use:   "subscribe <actor>",
+	Short: "Subscribe an assignee to digests, or change their settings",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		email, _ := cmd.Flags().GetString("email")
+		frequency, _ := cmd.Flags().GetString("frequency")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		if email == "" {
+			fmt.Fprintf(os.Stderr, "Error: --email is required\n")
+			os.Exit(1)
+		}
+		if err := digest.ValidateFrequency(frequency); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		ctx := context.Background()
+		sqliteStore := requireDigestStore()
+		sub := &sqlite.DigestSubscription{Actor: args[0], Email: email, Frequency: frequency}
+		if err := sqliteStore.SetDigestSubscription(ctx, sub); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			sub, _ = sqliteStore.GetDigestSubscription(ctx, args[0])
+			outputJSON(sub)
+			return
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Subscribed %s to %s digests at %s\n", green("✓"), sub.Actor, sub.Frequency, sub.Email)
+	},
+}
+
+var digestUnsubscribeCmd = &cobra.Command{
+	Use:   "unsubscribe <actor>",
+	Short: "Stop sending digests to an assignee",
+	Args:  cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		if err := requireDigestStore().DeleteDigestSubscription(context.Background(), args[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Unsubscribed %s\n", green("✓"), args[0])
+	},
+}
+
+var digestListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List digest subscriptions",
+	Run: func(cmd *cobra.Command, _ []string) {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		subs, err := requireDigestStore().ListDigestSubscriptions(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			if subs == nil {
+				subs = []*sqlite.DigestSubscription{}
+			}
+			outputJSON(subs)
+			return
+		}
+
+		if len(subs) == 0 {
+			fmt.Println("No digest subscriptions")
+			return
+		}
+		for _, sub := range subs {
+			last := "never sent"
+			if sub.LastSentAt != nil {
+				last = "last sent " + sub.LastSentAt.Local().Format("2006-01-02 15:04")
+			}
+			fmt.Printf("%s  %s  %s  (%s)\n", sub.Actor, sub.Email, sub.Frequency, last)
+		}
+	},
+}
+
+var digestPreviewCmd = &cobra.Command{
+	Use:   "preview <actor>",
+	Short: "Show the digest an assignee would get next",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		ctx := context.Background()
+		d, _ := buildDigestArg(ctx, requireDigestStore(), args[0])
+
+		if jsonOutput {
+			outputJSON(d)
+			return
+		}
+		prefix, _ := store.GetConfig(ctx, "issue_prefix")
+		fmt.Printf("Subject: %s\n\n%s", d.Subject(prefix), d.Render())
+	},
+}
+
+var digestSendCmd = &cobra.Command{
+	Use:   "send <actor>",
+	Short: "Send an assignee's digest now",
+	Long: `Send an assignee's digest now, even if it isn't due, to check the SMTP
+settings. The scheduled digest still covers its usual period.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		ctx := context.Background()
+		sqliteStore := requireDigestStore()
+		d, sub := buildDigestArg(ctx, sqliteStore, args[0])
+		if sub == nil {
+			fmt.Fprintf(os.Stderr, "Error: %s has no digest subscription\n", args[0])
+			os.Exit(1)
+		}
+
+		cfg, err := digest.LoadSMTPConfig(ctx, sqliteStore)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		prefix, _ := sqliteStore.GetConfig(ctx, "issue_prefix")
+		mailer := &digest.SMTPMailer{Config: cfg}
+		if err := mailer.Send(ctx, sub.Email, d.Subject(prefix), d.Render()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Sent digest to %s\n", green("✓"), sub.Email)
+	},
+}
+
+// buildDigestArg builds the next digest for an actor, covering the period
+// since their last one (or a day, without a subscription), or exits
+func buildDigestArg(ctx context.Context, sqliteStore *sqlite.SQLiteStorage, actor string) (*digest.Digest, *sqlite.DigestSubscription) {
+	sub, err := sqliteStore.GetDigestSubscription(ctx, actor)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	now := time.Now()
+	since := now.Add(-digest.Period(digest.Daily))
+	if sub != nil {
+		since = digest.Since(sub, now)
+	}
+	d, err := digest.Build(ctx, sqliteStore, actor, since, digest.StaleAfter(ctx, sqliteStore))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	return d, sub
+}
+
+func requireDigestStore() *sqlite.SQLiteStorage {
+	if err := ensureDirectMode("daemon does not support digest command"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: digest command requires SQLite backend\n")
+		os.Exit(1)
+	}
+	return sqliteStore
+}
+
+func init() {
+	digestSubscribeCmd.Flags().String("email", "", "Address to send digests to")
+	digestSubscribeCmd.Flags().String("frequency", digest.Daily, "How often to send: daily or weekly")
+	digestSubscribeCmd.Flags().Bool("json", false, "Output JSON format")
+	digestListCmd.Flags().Bool("json", false, "Output JSON format")
+	digestPreviewCmd.Flags().Bool("json", false, "Output JSON format")
+
+	digestCmd.AddCommand(digestSubscribeCmd)
+	digestCmd.AddCommand(digestUnsubscribeCmd)
+	digestCmd.AddCommand(digestListCmd)
+	digestCmd.AddCommand(digestPreviewCmd)
+	digestCmd.AddCommand(digestSendCmd)
+	rootCmd.AddCommand(digestCmd)
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/imalsogreg/beads"
+	"github.com/imalsogreg/beads/internal/config"
 	"github.com/imalsogreg/beads/internal/storage/sqlite"
 )
 
@@ -65,7 +66,7 @@ func ensureStoreActive() error {
 		}
 	}
 
-	sqlStore, err := sqlite.New(dbPath)
+	sqlStore, err := sqlite.NewWithConfig(dbPath, sqlitePoolConfig())
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
 	}
@@ -75,6 +76,7 @@ func ensureStoreActive() error {
 	storeActive = true
 	storeMutex.Unlock()
 
+	applySession()
 	checkVersionMismatch()
 	if autoImportEnabled {
 		autoImportIfNewer()
@@ -82,3 +84,13 @@ func ensureStoreActive() error {
 
 	return nil
 }
+
+// sqlitePoolConfig reads the sqlite.* connection settings from config
+func sqlitePoolConfig() sqlite.PoolConfig {
+	return sqlite.PoolConfig{
+		ReadConnections:   config.GetInt("sqlite.read-connections"),
+		BusyTimeout:       config.GetDuration("sqlite.busy-timeout"),
+		Synchronous:       config.GetString("sqlite.synchronous"),
+		WALAutocheckpoint: config.GetInt("sqlite.wal-autocheckpoint"),
+	}
+}
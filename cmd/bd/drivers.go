@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/imalsogreg/beads/internal/storage"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+)
+
+// Storage backends other than SQLite are linked into bd by importing them,
+// so their init functions register a driver (see beads.RegisterStorage).
+// A build adds one without touching bd's own files by dropping in a file
+// like this one, e.g. drivers_postgres.go:
+//
+//	//go:build postgres
+//
+//	package main
+//
+//	import _ "example.com/beads-postgres"
+//
+// and building with -tags postgres. --db postgres://... then opens it.
+
+// resolveStorageDriver returns the storage driver dbPath names. A sqlite:
+// URL is reduced to its file, so everything that works with the database's
+// location (the daemon, the JSONL file, auto-import) sees a plain path.
+func resolveStorageDriver() string {
+	driver := storage.DSNDriver(dbPath)
+	if driver == sqlite.DriverName {
+		dbPath = sqlite.DSNPath(dbPath)
+	}
+	return driver
+}
+
+// initializeDriverMode opens dbPath with the registered driver its scheme
+// names, for databases that aren't SQLite files. Commands run directly
+// against it: the daemon only serves SQLite.
+func initializeDriverMode() error {
+	s, err := storage.Open(dbPath)
+	if err != nil {
+		return fmt.Errorf("--db: %w", err)
+	}
+
+	// The backend is the source of truth; there is no JSONL file beside it
+	// to sync
+	autoFlushEnabled = false
+	autoImportEnabled = false
+
+	storeMutex.Lock()
+	store = s
+	storeActive = true
+	storeMutex.Unlock()
+	return nil
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&dbPath, "db", "",
+		"Database path, or a URL whose scheme picks the storage driver (sqlite:./beads.db, postgres://...)")
+}
@@ -4,12 +4,12 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"regexp"
 	"strings"
 
 	"github.com/fatih/color"
-	"github.com/spf13/cobra"
+	"github.com/imalsogreg/beads/internal/storage"
 	"github.com/imalsogreg/beads/internal/types"
+	"github.com/spf13/cobra"
 )
 
 var duplicatesCmd = &cobra.Command{
@@ -24,8 +24,14 @@ The merge target is chosen by:
 
 Only groups issues with matching status (open with open, closed with closed).
 
+With --fuzzy, near-duplicates are grouped too: issues whose titles and
+descriptions share at least the given fraction of their words (0-1). Each
+issue is shown with its similarity to the merge target. Review fuzzy groups
+before merging them.
+
 Example:
   bd duplicates                    # Show all duplicate groups
+  bd duplicates --fuzzy 0.85       # Include near-duplicates
   bd duplicates --auto-merge       # Automatically merge all duplicates
   bd duplicates --dry-run          # Show what would be merged`,
 	Run: func(cmd *cobra.Command, _ []string) {
@@ -38,19 +44,17 @@ Example:
 
 		autoMerge, _ := cmd.Flags().GetBool("auto-merge")
 		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		fuzzy, _ := cmd.Flags().GetFloat64("fuzzy")
 
 		ctx := context.Background()
 
-		// Get all issues
-		allIssues, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+		// Find duplicates
+		duplicateGroups, err := storage.FindDuplicates(ctx, store, fuzzy)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error fetching issues: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
 
-		// Find duplicates
-		duplicateGroups := findDuplicateGroups(allIssues)
-
 		if len(duplicateGroups) == 0 {
 			if !jsonOutput {
 				fmt.Println("No duplicates found!")
@@ -63,50 +67,20 @@ Example:
 			return
 		}
 
-		// Count references for each issue
-		refCounts := countReferences(allIssues)
-
 		// Prepare output
 		var mergeCommands []string
-		var mergeResults []map[string]interface{}
+		var mergeResults []*types.MergeResult
 
 		for _, group := range duplicateGroups {
-			target := chooseMergeTarget(group, refCounts)
-			sources := make([]string, 0, len(group)-1)
-			for _, issue := range group {
-				if issue.ID != target.ID {
-					sources = append(sources, issue.ID)
+			if autoMerge && !dryRun {
+				result, err := performMerge(ctx, group.SuggestedTarget, group.SuggestedSources)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error merging %s into %s: %v\n", strings.Join(group.SuggestedSources, ", "), group.SuggestedTarget, err)
+					continue
 				}
+				mergeResults = append(mergeResults, result)
 			}
-
-			if autoMerge || dryRun {
-				// Perform merge (unless dry-run)
-				if !dryRun {
-					result, err := performMerge(ctx, target.ID, sources)
-					if err != nil {
-						fmt.Fprintf(os.Stderr, "Error merging %s into %s: %v\n", strings.Join(sources, ", "), target.ID, err)
-						continue
-					}
-
-					if jsonOutput {
-						mergeResults = append(mergeResults, map[string]interface{}{
-							"target_id":            target.ID,
-							"source_ids":           sources,
-							"dependencies_added":   result.depsAdded,
-							"dependencies_skipped": result.depsSkipped,
-							"text_references":      result.textRefCount,
-							"issues_closed":        result.issuesClosed,
-							"issues_skipped":       result.issuesSkipped,
-						})
-					}
-				}
-
-				cmd := fmt.Sprintf("bd merge %s --into %s", strings.Join(sources, " "), target.ID)
-				mergeCommands = append(mergeCommands, cmd)
-			} else {
-				cmd := fmt.Sprintf("bd merge %s --into %s", strings.Join(sources, " "), target.ID)
-				mergeCommands = append(mergeCommands, cmd)
-			}
+			mergeCommands = append(mergeCommands, group.SuggestedCommand)
 		}
 
 		// Mark dirty if we performed merges
@@ -118,7 +92,7 @@ Example:
 		if jsonOutput {
 			output := map[string]interface{}{
 				"duplicate_groups": len(duplicateGroups),
-				"groups":           formatDuplicateGroupsJSON(duplicateGroups, refCounts),
+				"groups":           duplicateGroups,
 			}
 			if autoMerge || dryRun {
 				output["merge_commands"] = mergeCommands
@@ -135,27 +109,22 @@ Example:
 			fmt.Printf("%s Found %d duplicate group(s):\n\n", yellow("🔍"), len(duplicateGroups))
 
 			for i, group := range duplicateGroups {
-				target := chooseMergeTarget(group, refCounts)
-				fmt.Printf("%s Group %d: %s\n", cyan("━━"), i+1, group[0].Title)
+				fmt.Printf("%s Group %d: %s\n", cyan("━━"), i+1, group.Title)
 
-				for _, issue := range group {
-					refs := refCounts[issue.ID]
+				for _, issue := range group.Issues {
 					marker := "  "
-					if issue.ID == target.ID {
+					if issue.IsMergeTarget {
 						marker = green("→ ")
 					}
-					fmt.Printf("%s%s (%s, P%d, %d references)\n",
-						marker, issue.ID, issue.Status, issue.Priority, refs)
-				}
-
-				sources := make([]string, 0, len(group)-1)
-				for _, issue := range group {
-					if issue.ID != target.ID {
-						sources = append(sources, issue.ID)
+					similarity := ""
+					if fuzzy > 0 && !issue.IsMergeTarget {
+						similarity = fmt.Sprintf(", %.0f%% similar", issue.Score*100)
 					}
+					fmt.Printf("%s%s (%s, P%d, %d references%s)\n",
+						marker, issue.ID, issue.Status, issue.Priority, issue.References, similarity)
 				}
-				fmt.Printf("  %s bd merge %s --into %s\n\n",
-					cyan("Suggested:"), strings.Join(sources, " "), target.ID)
+
+				fmt.Printf("  %s %s\n\n", cyan("Suggested:"), group.SuggestedCommand)
 			}
 
 			if autoMerge {
@@ -174,125 +143,6 @@ Example:
 func init() {
 	duplicatesCmd.Flags().Bool("auto-merge", false, "Automatically merge all duplicates")
 	duplicatesCmd.Flags().Bool("dry-run", false, "Show what would be merged without making changes")
+	duplicatesCmd.Flags().Float64("fuzzy", 0, "Also group near-duplicates at this similarity threshold (0-1, e.g. 0.85)")
 	rootCmd.AddCommand(duplicatesCmd)
 }
-
-// contentKey represents the fields we use to identify duplicate issues
-type contentKey struct {
-	title              string
-	description        string
-	design             string
-	acceptanceCriteria string
-	status             string // Only group issues with same status
-}
-
-// findDuplicateGroups groups issues by content hash
-func findDuplicateGroups(issues []*types.Issue) [][]*types.Issue {
-	groups := make(map[contentKey][]*types.Issue)
-
-	for _, issue := range issues {
-		key := contentKey{
-			title:              issue.Title,
-			description:        issue.Description,
-			design:             issue.Design,
-			acceptanceCriteria: issue.AcceptanceCriteria,
-			status:             string(issue.Status),
-		}
-
-		groups[key] = append(groups[key], issue)
-	}
-
-	// Filter to only groups with duplicates
-	var duplicates [][]*types.Issue
-	for _, group := range groups {
-		if len(group) > 1 {
-			duplicates = append(duplicates, group)
-		}
-	}
-
-	return duplicates
-}
-
-// countReferences counts how many times each issue is referenced in text fields
-func countReferences(issues []*types.Issue) map[string]int {
-	counts := make(map[string]int)
-	idPattern := regexp.MustCompile(`\b[a-zA-Z][-a-zA-Z0-9]*-\d+\b`)
-
-	for _, issue := range issues {
-		// Search in all text fields
-		textFields := []string{
-			issue.Description,
-			issue.Design,
-			issue.AcceptanceCriteria,
-			issue.Notes,
-		}
-
-		for _, text := range textFields {
-			matches := idPattern.FindAllString(text, -1)
-			for _, match := range matches {
-				counts[match]++
-			}
-		}
-	}
-
-	return counts
-}
-
-// chooseMergeTarget selects the best issue to merge into
-// Priority: highest reference count, then lexicographically smallest ID
-func chooseMergeTarget(group []*types.Issue, refCounts map[string]int) *types.Issue {
-	if len(group) == 0 {
-		return nil
-	}
-
-	target := group[0]
-	targetRefs := refCounts[target.ID]
-
-	for _, issue := range group[1:] {
-		issueRefs := refCounts[issue.ID]
-		if issueRefs > targetRefs || (issueRefs == targetRefs && issue.ID < target.ID) {
-			target = issue
-			targetRefs = issueRefs
-		}
-	}
-
-	return target
-}
-
-// formatDuplicateGroupsJSON formats duplicate groups for JSON output
-func formatDuplicateGroupsJSON(groups [][]*types.Issue, refCounts map[string]int) []map[string]interface{} {
-	var result []map[string]interface{}
-
-	for _, group := range groups {
-		target := chooseMergeTarget(group, refCounts)
-		issues := make([]map[string]interface{}, len(group))
-
-		for i, issue := range group {
-			issues[i] = map[string]interface{}{
-				"id":              issue.ID,
-				"title":           issue.Title,
-				"status":          issue.Status,
-				"priority":        issue.Priority,
-				"references":      refCounts[issue.ID],
-				"is_merge_target": issue.ID == target.ID,
-			}
-		}
-
-		sources := make([]string, 0, len(group)-1)
-		for _, issue := range group {
-			if issue.ID != target.ID {
-				sources = append(sources, issue.ID)
-			}
-		}
-
-		result = append(result, map[string]interface{}{
-			"title":               group[0].Title,
-			"issues":              issues,
-			"suggested_target":    target.ID,
-			"suggested_sources":   sources,
-			"suggested_merge_cmd": fmt.Sprintf("bd merge %s --into %s", strings.Join(sources, " "), target.ID),
-		})
-	}
-
-	return result
-}
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// issueDocument is the editable form of an issue used by bd edit: a YAML
+// front matter block with the short fields, then a markdown section for
+// each text field.
+type issueDocument struct {
+	Title              string
+	Status             string
+	Priority           int
+	Assignee           string
+	Labels             []string
+	Description        string
+	Design             string
+	AcceptanceCriteria string
+	Notes              string
+}
+
+// documentSections are the text fields, in document order
+var documentSections = []struct {
+	heading string
+	field   string
+}{
+	{"Description", "description"},
+	{"Design", "design"},
+	{"Acceptance Criteria", "acceptance_criteria"},
+	{"Notes", "notes"},
+}
+
+func (d *issueDocument) section(field string) *string {
+	switch field {
+	case "description":
+		return &d.Description
+	case "design":
+		return &d.Design
+	case "acceptance_criteria":
+		return &d.AcceptanceCriteria
+	default:
+		return &d.Notes
+	}
+}
+
+func documentFromIssue(issue *types.Issue) *issueDocument {
+	labels := append([]string(nil), issue.Labels...)
+	sort.Strings(labels)
+	return &issueDocument{
+		Title:              issue.Title,
+		Status:             string(issue.Status),
+		Priority:           issue.Priority,
+		Assignee:           issue.Assignee,
+		Labels:             labels,
+		Description:        issue.Description,
+		Design:             issue.Design,
+		AcceptanceCriteria: issue.AcceptanceCriteria,
+		Notes:              issue.Notes,
+	}
+}
+
+// formatIssueDocument renders issue for editing
+func formatIssueDocument(issue *types.Issue) string {
+	d := documentFromIssue(issue)
+	var b strings.Builder
+	fmt.Fprintf(&b, "---\n")
+	fmt.Fprintf(&b, "# Editing %s. Save and quit to apply your changes; quit without\n", issue.ID)
+	fmt.Fprintf(&b, "# saving to cancel. Labels are comma-separated.\n")
+	field := func(name, value string) {
+		b.WriteString(strings.TrimRight(name+": "+value, " ") + "\n")
+	}
+	field("title", d.Title)
+	field("status", d.Status)
+	field("priority", strconv.Itoa(d.Priority))
+	field("assignee", d.Assignee)
+	field("labels", strings.Join(d.Labels, ", "))
+	fmt.Fprintf(&b, "---\n")
+	for _, s := range documentSections {
+		fmt.Fprintf(&b, "\n## %s\n\n", s.heading)
+		if text := strings.TrimSpace(*d.section(s.field)); text != "" {
+			fmt.Fprintf(&b, "%s\n", text)
+		}
+	}
+	return b.String()
+}
+
+// parseIssueDocument reads an edited document. Text fields run from their
+// heading to the next known heading, so other markdown headings inside them
+// are kept.
+func parseIssueDocument(text string) (*issueDocument, error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return nil, fmt.Errorf("document must start with a --- front matter line")
+	}
+
+	d := &issueDocument{}
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "---" {
+			end = i
+			break
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected 'field: value', got %q", i+1, line)
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "title":
+			d.Title = value
+		case "status":
+			d.Status = value
+		case "priority":
+			p, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(value), "P"))
+			if err != nil || p < 0 || p > 4 {
+				return nil, fmt.Errorf("line %d: priority must be 0-4, got %q", i+1, value)
+			}
+			d.Priority = p
+		case "assignee":
+			d.Assignee = value
+		case "labels":
+			d.Labels = parseLabelList(value)
+		default:
+			return nil, fmt.Errorf("line %d: unknown field %q", i+1, key)
+		}
+	}
+	if end < 0 {
+		return nil, fmt.Errorf("front matter is missing its closing --- line")
+	}
+	if d.Title == "" {
+		return nil, fmt.Errorf("title cannot be empty")
+	}
+	if !types.Status(d.Status).IsValid() {
+		return nil, fmt.Errorf("invalid status %q (use open, in_progress, blocked, or closed)", d.Status)
+	}
+
+	var current *string
+	var body []string
+	flush := func() {
+		if current != nil {
+			*current = strings.TrimSpace(strings.Join(body, "\n"))
+		}
+		body = nil
+	}
+	for _, line := range lines[end+1:] {
+		if heading, ok := strings.CutPrefix(strings.TrimSpace(line), "## "); ok {
+			if field := sectionField(heading); field != "" {
+				flush()
+				current = d.section(field)
+				continue
+			}
+		}
+		body = append(body, line)
+	}
+	flush()
+	return d, nil
+}
+
+func sectionField(heading string) string {
+	for _, s := range documentSections {
+		if strings.EqualFold(strings.TrimSpace(heading), s.heading) {
+			return s.field
+		}
+	}
+	return ""
+}
+
+// parseLabelList splits a comma-separated label list, dropping blanks and
+// duplicates
+func parseLabelList(value string) []string {
+	seen := make(map[string]bool)
+	var labels []string
+	for _, label := range strings.Split(value, ",") {
+		label = strings.TrimSpace(label)
+		if label != "" && !seen[label] {
+			seen[label] = true
+			labels = append(labels, label)
+		}
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// documentChanges compares an edited document with the issue it came from.
+// Text fields are compared without surrounding whitespace, which the
+// document doesn't preserve.
+func documentChanges(issue *types.Issue, edited *issueDocument) (updates map[string]interface{}, addLabels, removeLabels []string) {
+	original := documentFromIssue(issue)
+	updates = make(map[string]interface{})
+	if edited.Title != original.Title {
+		updates["title"] = edited.Title
+	}
+	if edited.Status != original.Status {
+		updates["status"] = edited.Status
+	}
+	if edited.Priority != original.Priority {
+		updates["priority"] = edited.Priority
+	}
+	if edited.Assignee != original.Assignee {
+		updates["assignee"] = edited.Assignee
+	}
+	for _, s := range documentSections {
+		if *edited.section(s.field) != strings.TrimSpace(*original.section(s.field)) {
+			updates[s.field] = *edited.section(s.field)
+		}
+	}
+
+	had := make(map[string]bool)
+	for _, label := range original.Labels {
+		had[label] = true
+	}
+	for _, label := range edited.Labels {
+		if !had[label] {
+			addLabels = append(addLabels, label)
+		}
+		delete(had, label)
+	}
+	for label := range had {
+		removeLabels = append(removeLabels, label)
+	}
+	sort.Strings(removeLabels)
+	return updates, addLabels, removeLabels
+}
+
+// findEditor returns the user's editor command, or "" if there is none
+func findEditor() string {
+	if editor := os.Getenv("EDITOR"); editor != "" {
+		return editor
+	}
+	if editor := os.Getenv("VISUAL"); editor != "" {
+		return editor
+	}
+	// Try common defaults
+	for _, defaultEditor := range []string{"vim", "vi", "nano", "emacs"} {
+		if _, err := exec.LookPath(defaultEditor); err == nil {
+			return defaultEditor
+		}
+	}
+	return ""
+}
+
+// editText opens content in editor and returns the saved text. pattern names
+// the temp file, as for os.CreateTemp. The editor may include arguments,
+// e.g. "code --wait".
+func editText(editor, pattern, content string) (string, error) {
+	tmpFile, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", fmt.Errorf("creating temp file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmpFile.WriteString(content); err != nil {
+		tmpFile.Close()
+		return "", fmt.Errorf("writing to temp file: %w", err)
+	}
+	tmpFile.Close()
+
+	args := strings.Fields(editor)
+	editorCmd := exec.Command(args[0], append(args[1:], tmpPath)...)
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+	if err := editorCmd.Run(); err != nil {
+		return "", fmt.Errorf("running editor: %w", err)
+	}
+
+	edited, err := os.ReadFile(tmpPath)
+	if err != nil {
+		return "", fmt.Errorf("reading edited file: %w", err)
+	}
+	return string(edited), nil
+}
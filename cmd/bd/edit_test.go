@@ -0,0 +1,90 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestIssueDocumentRoundTrip(t *testing.T) {
+	issue := &types.Issue{
+		ID:                 "bd-12",
+		Title:              "Fix login: handle expired tokens",
+		Status:             types.StatusInProgress,
+		Priority:           1,
+		Assignee:           "alice",
+		Labels:             []string{"backend", "auth"},
+		Description:        "Tokens expire.\n\n## Steps\n1. Log in\n2. Wait\n",
+		Design:             "Refresh them.",
+		AcceptanceCriteria: "",
+		Notes:              "See bd-3",
+	}
+
+	doc, err := parseIssueDocument(formatIssueDocument(issue))
+	if err != nil {
+		t.Fatalf("parseIssueDocument failed: %v", err)
+	}
+	updates, added, removed := documentChanges(issue, doc)
+	if len(updates) != 0 || len(added) != 0 || len(removed) != 0 {
+		t.Fatalf("Expected no changes from an unedited document, got %v +%v -%v", updates, added, removed)
+	}
+	if doc.Description != "Tokens expire.\n\n## Steps\n1. Log in\n2. Wait" {
+		t.Errorf("Expected other headings to stay in the description, got %q", doc.Description)
+	}
+}
+
+func TestDocumentChanges(t *testing.T) {
+	issue := &types.Issue{
+		ID:          "bd-12",
+		Title:       "Old title",
+		Status:      types.StatusOpen,
+		Priority:    2,
+		Labels:      []string{"backend", "auth"},
+		Description: "Unchanged\n",
+		Notes:       "Old notes",
+	}
+	edited := strings.NewReplacer(
+		"title: Old title", "title: New title",
+		"priority: 2", "priority: P0",
+		"labels: auth, backend", "labels: backend, urgent, backend",
+		"Old notes", "New notes\nmore",
+	).Replace(formatIssueDocument(issue))
+
+	doc, err := parseIssueDocument(edited)
+	if err != nil {
+		t.Fatalf("parseIssueDocument failed: %v", err)
+	}
+	updates, added, removed := documentChanges(issue, doc)
+	want := map[string]interface{}{"title": "New title", "priority": 0, "notes": "New notes\nmore"}
+	if !reflect.DeepEqual(updates, want) {
+		t.Errorf("Expected updates %v, got %v", want, updates)
+	}
+	if !reflect.DeepEqual(added, []string{"urgent"}) || !reflect.DeepEqual(removed, []string{"auth"}) {
+		t.Errorf("Expected +[urgent] -[auth], got +%v -%v", added, removed)
+	}
+}
+
+func TestParseIssueDocumentErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"no front matter", "title: x\n", "must start with"},
+		{"unclosed front matter", "---\ntitle: x\nstatus: open\n", "closing ---"},
+		{"empty title", "---\ntitle:\nstatus: open\n---\n", "title cannot be empty"},
+		{"bad status", "---\ntitle: x\nstatus: done\n---\n", "invalid status"},
+		{"bad priority", "---\ntitle: x\nstatus: open\npriority: 7\n---\n", "priority must be 0-4"},
+		{"unknown field", "---\ntitle: x\nowner: me\n---\n", "unknown field"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseIssueDocument(tt.content)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
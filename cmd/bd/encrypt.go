@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/imalsogreg/beads/internal/fieldcrypt"
+	"github.com/imalsogreg/beads/internal/storage"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+	"github.com/spf13/cobra"
+)
+
+var encryptCmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "Field-level encryption for descriptions, notes, and comments",
+	Long: `Encrypt sensitive issue content at rest and in JSONL exports.
+
+Descriptions, notes, and comment text are encrypted with a per-workspace data
+key (AES-256-GCM). The data key is stored in the database only in wrapped
+form, encrypted by a key you supply:
+
+  BEADS_ENCRYPTION_KEY   32-byte key, hex or base64 encoded
+  BEADS_KMS_WRAP_CMD     shell command that wraps a key (stdin -> stdout)
+  BEADS_KMS_UNWRAP_CMD   shell command that unwraps a key (stdin -> stdout)
+
+Titles, labels, and other metadata stay in plaintext so listing and filtering
+keep working. Text search does not match encrypted content. Without the key,
+encrypted fields are shown as ciphertext and edits to them are rejected.
+
+Examples:
+  export BEADS_ENCRYPTION_KEY=$(openssl rand -hex 32)
+  bd encrypt enable        # Encrypt existing content and all future writes
+  bd encrypt status        # Show whether encryption is enabled and unlocked
+  bd encrypt disable       # Decrypt everything and remove the wrapped key`,
+}
+
+var encryptEnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Enable field encryption and encrypt existing content",
+	Run: func(_ *cobra.Command, _ []string) {
+		sqliteStore := requireEncryptStore()
+		ctx := context.Background()
+
+		provider, err := fieldcrypt.ProviderFromEnv()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if provider == nil {
+			fmt.Fprintf(os.Stderr, "Error: no encryption key configured (set %s or %s/%s)\n",
+				fieldcrypt.EnvKey, fieldcrypt.EnvKMSWrap, fieldcrypt.EnvKMSUnwrap)
+			os.Exit(1)
+		}
+
+		result, err := sqliteStore.EnableFieldEncryption(ctx, provider)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		markDirtyAndScheduleFullExport()
+
+		if jsonOutput {
+			outputJSON(result)
+			return
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Field encryption enabled (%s)\n", green("✓"), provider.Name())
+		printFieldEncryptionResult(result)
+		if result.HistorySkipped {
+			yellow := color.New(color.FgYellow).SprintFunc()
+			fmt.Printf("%s Existing event history was left as-is because the audit chain is sealed\n", yellow("⚠"))
+		}
+	},
+}
+
+var encryptStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show field encryption status",
+	Run: func(_ *cobra.Command, _ []string) {
+		sqliteStore := requireEncryptStore()
+
+		status, err := sqliteStore.GetFieldEncryptionStatus(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			outputJSON(status)
+			return
+		}
+
+		if !status.Enabled {
+			fmt.Println("Field encryption: disabled")
+			return
+		}
+		fmt.Printf("Field encryption: enabled (%s)\n", status.Provider)
+		if status.KeyLoaded {
+			green := color.New(color.FgGreen).SprintFunc()
+			fmt.Printf("%s Key loaded\n", green("✓"))
+		} else {
+			red := color.New(color.FgRed).SprintFunc()
+			fmt.Printf("%s Key not loaded: encrypted fields are read-only\n", red("✗"))
+		}
+	},
+}
+
+var encryptDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Decrypt all content and disable field encryption",
+	Run: func(_ *cobra.Command, _ []string) {
+		sqliteStore := requireEncryptStore()
+
+		result, err := sqliteStore.DisableFieldEncryption(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		markDirtyAndScheduleFullExport()
+
+		if jsonOutput {
+			outputJSON(result)
+			return
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Field encryption disabled\n", green("✓"))
+		printFieldEncryptionResult(result)
+	},
+}
+
+func requireEncryptStore() *sqlite.SQLiteStorage {
+	if err := ensureDirectMode("daemon does not support encrypt command"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: encrypt command requires SQLite backend\n")
+		os.Exit(1)
+	}
+	return sqliteStore
+}
+
+func printFieldEncryptionResult(result *sqlite.FieldEncryptionResult) {
+	fmt.Printf("  Issues:   %d\n", result.Issues)
+	fmt.Printf("  Comments: %d\n", result.Comments)
+	fmt.Printf("  Events:   %d\n", result.Events)
+}
+
+// exportableIssue returns the form of an issue written to JSONL. When field
+// encryption is enabled, sensitive fields are encrypted so exports never
+// contain plaintext.
+func exportableIssue(s storage.Storage, issue *types.Issue) (*types.Issue, error) {
+	sqliteStore, ok := s.(*sqlite.SQLiteStorage)
+	if !ok {
+		return issue, nil
+	}
+	return sqliteStore.EncryptForExport(issue)
+}
+
+func init() {
+	encryptCmd.AddCommand(encryptEnableCmd)
+	encryptCmd.AddCommand(encryptStatusCmd)
+	encryptCmd.AddCommand(encryptDisableCmd)
+	rootCmd.AddCommand(encryptCmd)
+}
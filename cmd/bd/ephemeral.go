@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/imalsogreg/beads/internal/storage/memory"
+)
+
+// ephemeral runs the command against a throwaway in-memory database.
+// Nothing is written back to SQLite or JSONL, so it is safe for experiments.
+var ephemeral bool
+
+// initializeEphemeralMode sets up in-memory storage that is discarded on exit.
+// If the current directory has a .beads/issues.jsonl, it is loaded as a
+// starting point so experiments can run against a copy of real issues.
+func initializeEphemeralMode() error {
+	memStore := memory.New("")
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	beadsDir := filepath.Join(cwd, ".beads")
+	jsonlPath := filepath.Join(beadsDir, "issues.jsonl")
+
+	if _, err := os.Stat(jsonlPath); err == nil {
+		issues, err := loadIssuesFromJSONL(jsonlPath)
+		if err != nil {
+			return fmt.Errorf("failed to load issues from %s: %w", jsonlPath, err)
+		}
+		if err := memStore.LoadFromIssues(issues); err != nil {
+			return fmt.Errorf("failed to load issues into memory: %w", err)
+		}
+		if os.Getenv("BD_DEBUG") != "" {
+			fmt.Fprintf(os.Stderr, "Debug: ephemeral session seeded with %d issues from %s\n", len(issues), jsonlPath)
+		}
+	}
+
+	prefix, err := detectPrefix(beadsDir, memStore)
+	if err != nil {
+		return fmt.Errorf("failed to detect prefix: %w", err)
+	}
+	if err := memStore.SetConfig(context.Background(), "issue_prefix", prefix); err != nil {
+		return fmt.Errorf("failed to set prefix: %w", err)
+	}
+
+	store = memStore
+	return nil
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&ephemeral, "ephemeral", false,
+		"Use a throwaway in-memory database (seeded from .beads/issues.jsonl if present); nothing is saved")
+}
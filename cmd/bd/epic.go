@@ -5,10 +5,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"github.com/imalsogreg/beads/internal/autoclose"
 	"github.com/imalsogreg/beads/internal/rpc"
+	"github.com/imalsogreg/beads/internal/storage"
 	"github.com/imalsogreg/beads/internal/types"
 )
 
@@ -18,12 +22,24 @@ var epicCmd = &cobra.Command{
 }
 
 var epicStatusCmd = &cobra.Command{
-	Use:   "status",
+	Use:   "status [epic-id]",
 	Short: "Show epic completion status",
+	Long: `Show the completion status of open epics, or with an epic ID, the
+progress of that epic over all its descendants.
+
+An epic's progress counts its children, their subtasks, and so on, with
+nested epics rolled up into it. It is given by issue count and by
+estimated minutes, and lists the open issues blocking its unfinished work.`,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		eligibleOnly, _ := cmd.Flags().GetBool("eligible-only")
 		jsonOutput, _ := cmd.Flags().GetBool("json")
 
+		if len(args) == 1 {
+			showEpicRollup(args[0], jsonOutput)
+			return
+		}
+
 		var epics []*types.EpicStatus
 		var err error
 
@@ -110,6 +126,70 @@ var epicStatusCmd = &cobra.Command{
 	},
 }
 
+// showEpicRollup prints the progress of one epic over its descendants
+func showEpicRollup(epicID string, jsonOutput bool) {
+	if err := ensureDirectMode("daemon does not support epic status for one epic"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	roll, err := storage.GetEpicRollup(context.Background(), store, epicID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting epic status: %v\n", err)
+		os.Exit(1)
+	}
+	if jsonOutput {
+		outputJSON(roll)
+		return
+	}
+
+	cyan := color.New(color.FgCyan).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	bold := color.New(color.Bold).SprintFunc()
+
+	fmt.Printf("\n%s %s\n", cyan(roll.Epic.ID), bold(roll.Epic.Title))
+	fmt.Printf("   Progress: %d/%d closed (%d%%)", roll.Closed, roll.Total, roll.Percent)
+	if roll.InProgress > 0 {
+		fmt.Printf(", %d in progress", roll.InProgress)
+	}
+	if roll.Blocked > 0 {
+		fmt.Printf(", %s", red(fmt.Sprintf("%d blocked", roll.Blocked)))
+	}
+	fmt.Println()
+	if roll.EstimatedMinutes > 0 {
+		fmt.Printf("   Estimate: %s of %s closed (%d%%)", formatMinutes(roll.ClosedMinutes), formatMinutes(roll.EstimatedMinutes), roll.PercentByMinutes)
+		if roll.Unestimated > 0 {
+			fmt.Printf("; %s", yellow(fmt.Sprintf("%d unestimated", roll.Unestimated)))
+		}
+		fmt.Println()
+	}
+
+	if len(roll.Epics) > 0 {
+		fmt.Printf("\n   Nested epics:\n")
+		var nested func(epics []*types.EpicRollup, indent string)
+		nested = func(epics []*types.EpicRollup, indent string) {
+			for _, e := range epics {
+				fmt.Printf("%s%s %s  %d/%d (%d%%)\n", indent, cyan(e.Epic.ID), e.Epic.Title, e.Closed, e.Total, e.Percent)
+				nested(e.Epics, indent+"  ")
+			}
+		}
+		nested(roll.Epics, "     ")
+	}
+
+	if len(roll.Blockers) > 0 {
+		fmt.Printf("\n   Blockers:\n")
+		for _, eb := range roll.Blockers {
+			outside := ""
+			if eb.External {
+				outside = yellow(" (outside the epic)")
+			}
+			fmt.Printf("     %s [P%d] %s (%s) blocks %s%s\n", cyan(eb.Issue.ID), eb.Issue.Priority, eb.Issue.Title, eb.Issue.Status, strings.Join(eb.Blocks, ", "), outside)
+		}
+	}
+	fmt.Println()
+}
+
 var closeEligibleEpicsCmd = &cobra.Command{
 	Use:   "close-eligible",
 	Short: "Close epics where all children are complete",
@@ -223,9 +303,138 @@ var closeEligibleEpicsCmd = &cobra.Command{
 	},
 }
 
+var epicCriticalPathCmd = &cobra.Command{
+	Use:   "critical-path <epic-id>",
+	Short: "Show the longest chain of unfinished work in an epic",
+	Long: `List the longest chain of unfinished work under an epic, in the order it
+must be done, with its total estimated duration.
+
+The chain follows 'blocks' dependencies between the epic's open descendants
+(children, their subtasks, and so on) and is weighted by estimated_minutes.
+Issues without an estimate count as zero and are flagged.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		if err := ensureDirectMode("daemon does not support epic critical-path"); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		ctx := context.Background()
+		path, err := storage.GetCriticalPath(ctx, store, args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error computing critical path: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			outputJSON(path)
+			return
+		}
+
+		if len(path.Issues) == 0 {
+			fmt.Printf("No unfinished work under %s\n", path.EpicID)
+			return
+		}
+
+		cyan := color.New(color.FgCyan).SprintFunc()
+		yellow := color.New(color.FgYellow).SprintFunc()
+		bold := color.New(color.Bold).SprintFunc()
+
+		fmt.Printf("\n%s Critical path for %s: %d issue(s), %s estimated\n\n",
+			bold("⏱"), cyan(path.EpicID), len(path.Issues), formatMinutes(path.TotalMinutes))
+		for i, issue := range path.Issues {
+			estimate := yellow("unestimated")
+			if issue.EstimatedMinutes != nil {
+				estimate = formatMinutes(*issue.EstimatedMinutes)
+			}
+			fmt.Printf("  %d. %s [P%d] %s (%s, %s)\n", i+1, cyan(issue.ID), issue.Priority, issue.Title, issue.Status, estimate)
+		}
+		if path.Unestimated > 0 {
+			fmt.Printf("\n%s %d issue(s) on the path have no estimate; the total is a lower bound\n", yellow("⚠"), path.Unestimated)
+		}
+		fmt.Println()
+	},
+}
+
+var epicAutocloseCmd = &cobra.Command{
+	Use:   "autoclose",
+	Short: "Close epics whose children are all closed, with a summary comment",
+	Long: `Close every open epic whose children are all closed. Each epic gets a
+comment listing its children and how long it was open. Closing a nested epic
+can complete its parent, which is closed too.
+
+To close epics automatically as their last child closes, turn on the policy;
+bd serve then checks every minute and the daemon every 5 minutes:
+
+  bd config set epic.autoclose true`,
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		if err := ensureDirectMode("daemon does not support epic autoclose"); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		ctx := context.Background()
+		closures, err := autoclose.Run(ctx, store, actor, time.Now(), dryRun)
+		if len(closures) > 0 && !dryRun {
+			markDirtyAndScheduleFlush()
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			if len(closures) == 0 {
+				os.Exit(1)
+			}
+		}
+
+		if jsonOutput {
+			if closures == nil {
+				closures = []*autoclose.Closure{}
+			}
+			outputJSON(closures)
+			return
+		}
+		if len(closures) == 0 {
+			fmt.Println("No epics eligible for closure")
+			return
+		}
+		verb := "Closed"
+		if dryRun {
+			verb = "Would close"
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		cyan := color.New(color.FgCyan).SprintFunc()
+		fmt.Printf("%s %s %d epic(s):\n", green("✓"), verb, len(closures))
+		for _, c := range closures {
+			fmt.Printf("  %s %s (%d children, open %s)\n", cyan(c.Epic.ID), c.Epic.Title, len(c.Children), autoclose.FormatElapsed(c.Elapsed))
+		}
+	},
+}
+
+func runScheduledEpicAutoclose(ctx context.Context, store storage.Storage, log daemonLogger) error {
+	closures, err := autoclose.Run(ctx, store, autoclose.Actor, time.Now(), false)
+	for _, c := range closures {
+		log.log("Autoclose: closed epic %s, all %d children closed", c.Epic.ID, len(c.Children))
+	}
+	return err
+}
+
+// formatMinutes renders a duration in minutes as "1h 30m" or "45m"
+func formatMinutes(minutes int) string {
+	if minutes >= 60 {
+		return fmt.Sprintf("%dh %dm", minutes/60, minutes%60)
+	}
+	return fmt.Sprintf("%dm", minutes)
+}
+
 func init() {
 	epicCmd.AddCommand(epicStatusCmd)
 	epicCmd.AddCommand(closeEligibleEpicsCmd)
+	epicCmd.AddCommand(epicCriticalPathCmd)
+	epicCmd.AddCommand(epicAutocloseCmd)
 
 	epicStatusCmd.Flags().Bool("eligible-only", false, "Show only epics eligible for closure")
 	epicStatusCmd.Flags().Bool("json", false, "Output in JSON format")
@@ -233,5 +442,10 @@ func init() {
 	closeEligibleEpicsCmd.Flags().Bool("dry-run", false, "Preview what would be closed without making changes")
 	closeEligibleEpicsCmd.Flags().Bool("json", false, "Output in JSON format")
 
+	epicCriticalPathCmd.Flags().Bool("json", false, "Output in JSON format")
+
+	epicAutocloseCmd.Flags().Bool("dry-run", false, "Preview what would be closed without making changes")
+	epicAutocloseCmd.Flags().Bool("json", false, "Output in JSON format")
+
 	rootCmd.AddCommand(epicCmd)
 }
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/imalsogreg/beads/internal/storage"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/spf13/cobra"
+)
+
+var escalateCmd = &cobra.Command{
+	Use:   "escalate",
+	Short: "Manage priority escalation rules",
+	Long: `Configure rules that raise the priority of, or label, issues left open too long.
+
+A rule matches issues that aren't closed by type, status, and current
+priority, once they are at least --older-than old (from creation). Rules run
+in order and only ever raise priority; a later rule sees the priority set by
+an earlier one. Issues already escalated are left alone, so running again is
+harmless.
+
+Rules are run by the daemon's scheduler (once a day by default) when
+escalation.enabled is true.
+
+Configuration (bd config set):
+  escalation.enabled             true to run rules from the daemon
+  schedule.escalation.interval   how often to run (default 24h)
+
+Examples:
+  bd escalate add --type bug --priority 2 --older-than 30d --set-priority 1
+  bd escalate add --older-than 90d --label neglected
+  bd escalate list
+  bd escalate run --dry-run
+  bd escalate run
+  bd escalate remove 2`,
+}
+
+var escalateAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add an escalation rule",
+	Run: func(cmd *cobra.Command, _ []string) {
+		issueType, _ := cmd.Flags().GetString("type")
+		status, _ := cmd.Flags().GetString("status")
+		olderThan, _ := cmd.Flags().GetString("older-than")
+		label, _ := cmd.Flags().GetString("label")
+
+		sqliteStore := requireEscalationStore()
+
+		days, err := sqlite.ParseRetentionAge(olderThan)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		rule := &sqlite.EscalationRule{
+			IssueType:  issueType,
+			Status:     status,
+			MinAgeDays: days,
+			AddLabel:   label,
+		}
+		if cmd.Flags().Changed("priority") {
+			p, _ := cmd.Flags().GetInt("priority")
+			rule.Priority = &p
+		}
+		if cmd.Flags().Changed("set-priority") {
+			p, _ := cmd.Flags().GetInt("set-priority")
+			rule.SetPriority = &p
+		}
+		if err := sqliteStore.AddEscalationRule(context.Background(), rule); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			outputJSON(rule)
+			return
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Added rule #%d: %s\n", green("✓"), rule.ID, rule.Describe())
+	},
+}
+
+var escalateListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List escalation rules",
+	Run: func(_ *cobra.Command, _ []string) {
+		sqliteStore := requireEscalationStore()
+
+		rules, err := sqliteStore.GetEscalationRules(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			if rules == nil {
+				rules = []*sqlite.EscalationRule{}
+			}
+			outputJSON(rules)
+			return
+		}
+		if len(rules) == 0 {
+			fmt.Println("No escalation rules")
+			return
+		}
+		for _, r := range rules {
+			fmt.Printf("#%d  %s\n", r.ID, r.Describe())
+		}
+	},
+}
+
+var escalateRemoveCmd = &cobra.Command{
+	Use:   "remove <rule-id>",
+	Short: "Remove an escalation rule",
+	Args:  cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		sqliteStore := requireEscalationStore()
+
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid rule ID %q\n", args[0])
+			os.Exit(1)
+		}
+		if err := sqliteStore.RemoveEscalationRule(context.Background(), id); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			outputJSON(map[string]interface{}{"removed": id})
+			return
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Removed rule #%d\n", green("✓"), id)
+	},
+}
+
+var escalateRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Run the escalation rules now",
+	Run: func(cmd *cobra.Command, _ []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		sqliteStore := requireEscalationStore()
+
+		report, err := sqliteStore.ApplyEscalation(context.Background(), dryRun, actor)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if !dryRun && len(report.Escalations) > 0 {
+			markDirtyAndScheduleFlush()
+		}
+
+		if jsonOutput {
+			outputJSON(report)
+			return
+		}
+
+		if dryRun {
+			yellow := color.New(color.FgYellow).SprintFunc()
+			fmt.Printf("\n%s\n\n", yellow("⚠️  ESCALATION DRY RUN"))
+		}
+		printEscalationReport(report)
+	},
+}
+
+func requireEscalationStore() *sqlite.SQLiteStorage {
+	if err := ensureDirectMode("daemon does not support escalate command"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: escalate command requires SQLite backend\n")
+		os.Exit(1)
+	}
+	return sqliteStore
+}
+
+func printEscalationReport(report *sqlite.EscalationReport) {
+	if report.Rules == 0 {
+		fmt.Println("No escalation rules")
+		return
+	}
+	for _, e := range report.Escalations {
+		var changes []string
+		if e.ToPriority != e.FromPriority {
+			changes = append(changes, fmt.Sprintf("P%d -> P%d", e.FromPriority, e.ToPriority))
+		}
+		for _, label := range e.Labels {
+			changes = append(changes, "+"+label)
+		}
+		rules := make([]string, len(e.RuleIDs))
+		for i, id := range e.RuleIDs {
+			rules[i] = fmt.Sprintf("#%d", id)
+		}
+		fmt.Printf("%s  %s\n    %s (rule %s)\n", e.IssueID, e.Title, strings.Join(changes, ", "), strings.Join(rules, ", "))
+	}
+	verb := "Would escalate"
+	if !report.DryRun {
+		verb = "Escalated"
+	}
+	if len(report.Escalations) > 0 {
+		fmt.Println()
+	}
+	fmt.Printf("%s %d issue(s)\n", verb, len(report.Escalations))
+}
+
+func runScheduledEscalation(ctx context.Context, store storage.Storage, log daemonLogger) error {
+	sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+	if !ok {
+		return fmt.Errorf("escalation requires SQLite backend")
+	}
+	report, err := sqliteStore.ApplyEscalation(ctx, false, "system")
+	if err != nil {
+		return err
+	}
+	for _, e := range report.Escalations {
+		log.log("Escalation: %s P%d -> P%d, labels %v (rules %v)", e.IssueID, e.FromPriority, e.ToPriority, e.Labels, e.RuleIDs)
+	}
+	log.log("Escalation: escalated %d issue(s)", len(report.Escalations))
+	return nil
+}
+
+func init() {
+	escalateAddCmd.Flags().String("type", "", "Only issues of this type")
+	escalateAddCmd.Flags().String("status", "", "Only issues with this status (e.g. open)")
+	escalateAddCmd.Flags().IntP("priority", "p", 0, "Only issues at this priority")
+	escalateAddCmd.Flags().String("older-than", "", "Age threshold since creation, e.g. 30d, 6w, 3mo (required)")
+	escalateAddCmd.Flags().Int("set-priority", 0, "Raise matching issues to this priority")
+	escalateAddCmd.Flags().String("label", "", "Add this label to matching issues")
+	_ = escalateAddCmd.MarkFlagRequired("older-than")
+
+	escalateRunCmd.Flags().Bool("dry-run", false, "Report what would change without changing anything")
+
+	escalateCmd.AddCommand(escalateAddCmd)
+	escalateCmd.AddCommand(escalateListCmd)
+	escalateCmd.AddCommand(escalateRemoveCmd)
+	escalateCmd.AddCommand(escalateRunCmd)
+	rootCmd.AddCommand(escalateCmd)
+}
@@ -6,10 +6,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/imalsogreg/beads/internal/anonymize"
 	"github.com/imalsogreg/beads/internal/storage/sqlite"
 	"github.com/imalsogreg/beads/internal/types"
 )
@@ -75,21 +75,75 @@ func validateExportPath(path string) error {
 	return nil
 }
 
+// sameFile reports whether two paths refer to the same location
+func sameFile(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
+
 var exportCmd = &cobra.Command{
 	Use:   "export",
 	Short: "Export issues to JSONL format",
 	Long: `Export all issues to JSON Lines format (one JSON object per line).
 Issues are sorted by ID for consistent diffs.
 
-Output to stdout by default, or use -o flag for file output.`,
+Output to stdout by default, or use -o flag for file output.
+
+With --anonymize, assignees and dependency authors are replaced with stable
+pseudonyms (user-<hash>), and emails, URLs, secrets, and actor names are
+stripped from text fields. Use it to share realistic datasets for debugging
+or benchmarking. Pseudonyms are keyed with --salt; pass a private salt if
+actor names could be guessed. Anonymized exports never update the project's
+JSONL file or its export state.
+
+With --format jira, writes a CSV that Jira's CSV importer accepts, for
+reporting back to Jira. Issues imported from Jira carry their key in the
+Issue key column; Parent id and issue link columns refer to beads IDs in the
+Issue id column. Jira exports never update the project's JSONL file or its
+export state.
+
+With --full, writes a workspace bundle instead: a tar of JSONL streams with
+the config, label definitions, issues, comments, and every issue's event
+history, for moving the workspace to another database or storage backend
+with 'bd import --full'. Bundles named .tar.gz or .tgz are gzipped.
+
+Examples:
+  bd export -o issues.jsonl
+  bd export --anonymize -o shareable.jsonl
+  bd export --anonymize --salt "$(openssl rand -hex 16)" > shareable.jsonl
+  bd export --format jira -o jira.csv
+  bd export --full -o workspace.tar.gz`,
 	Run: func(cmd *cobra.Command, args []string) {
 		format, _ := cmd.Flags().GetString("format")
 		output, _ := cmd.Flags().GetString("output")
 		statusFilter, _ := cmd.Flags().GetString("status")
 		force, _ := cmd.Flags().GetBool("force")
+		anonymizeOutput, _ := cmd.Flags().GetBool("anonymize")
+		salt, _ := cmd.Flags().GetString("salt")
+		full, _ := cmd.Flags().GetBool("full")
 
-		if format != "jsonl" {
-			fmt.Fprintf(os.Stderr, "Error: only 'jsonl' format is currently supported\n")
+		if format != "jsonl" && format != "jira" {
+			fmt.Fprintf(os.Stderr, "Error: unknown format %q (valid: jsonl, jira)\n", format)
+			os.Exit(1)
+		}
+		if full && (format != "jsonl" || anonymizeOutput || statusFilter != "") {
+			fmt.Fprintf(os.Stderr, "Error: --full exports everything; it can't be combined with --format, --anonymize, or --status\n")
+			os.Exit(1)
+		}
+		if format == "jira" && anonymizeOutput {
+			fmt.Fprintf(os.Stderr, "Error: --anonymize only applies to jsonl exports\n")
+			os.Exit(1)
+		}
+		if format == "jira" && output != "" && sameFile(output, findJSONLPath()) {
+			fmt.Fprintf(os.Stderr, "Error: refusing to write a Jira export over the project's JSONL file\n")
+			os.Exit(1)
+		}
+		if anonymizeOutput && output != "" && sameFile(output, findJSONLPath()) {
+			fmt.Fprintf(os.Stderr, "Error: refusing to write an anonymized export over the project's JSONL file\n")
 			os.Exit(1)
 		}
 
@@ -110,6 +164,11 @@ Output to stdout by default, or use -o flag for file output.`,
 			defer func() { _ = store.Close() }()
 			}
 
+		if full {
+			writeFullExport(context.Background(), output)
+			return
+		}
+
 			// Build filter
 		filter := types.IssueFilter{}
 		if statusFilter != "" {
@@ -117,16 +176,67 @@ Output to stdout by default, or use -o flag for file output.`,
 			filter.Status = &status
 		}
 
-		// Get all issues
 		ctx := context.Background()
-		issues, err := store.SearchIssues(ctx, "", filter)
+		if format == "jira" {
+			issues, err := store.SearchIssues(ctx, "", filter)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			writeJiraExport(ctx, issues, output)
+			return
+		}
+
+		// Populate dependencies for all issues in one query (avoids N+1 problem)
+		allDeps, err := store.GetAllDependencyRecords(ctx)
 		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting dependencies: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Issues stream from the database to the output in ID order, so large
+		// databases aren't loaded whole. Anonymizing needs them all up front
+		// to give everyone consistent pseudonyms.
+		filter.Sort = []types.SortKey{{Field: "id"}}
+		forEachIssue := func(fn func(*types.Issue) error) error {
+			return store.ForEachIssue(ctx, filter, func(issue *types.Issue) error {
+				issue.Dependencies = allDeps[issue.ID]
+				return fn(issue)
+			})
+		}
+		if anonymizeOutput {
+			issues, err := store.SearchIssues(ctx, "", filter)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			for _, issue := range issues {
+				issue.Dependencies = allDeps[issue.ID]
+			}
+			issues = anonymize.New(salt).Issues(issues)
+			forEachIssue = func(fn func(*types.Issue) error) error {
+				for _, issue := range issues {
+					if err := fn(issue); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+		}
+
+		// Count what will be exported for the safety checks, before anything
+		// is written
+		issueCount := 0
+		if err := forEachIssue(func(*types.Issue) error {
+			issueCount++
+			return nil
+		}); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
 
 		// Safety check: prevent exporting empty database over non-empty JSONL
-		if len(issues) == 0 && output != "" && !force {
+		if issueCount == 0 && output != "" && !force {
 			existingCount, err := countIssuesInJSONL(output)
 			if err != nil {
 				// If we can't read the file, it might not exist yet, which is fine
@@ -148,11 +258,11 @@ Output to stdout by default, or use -o flag for file output.`,
 		if output != "" {
 			existingCount, err := countIssuesInJSONL(output)
 			if err == nil && existingCount > 0 {
-				lossPercent := float64(existingCount-len(issues)) / float64(existingCount) * 100
+				lossPercent := float64(existingCount-issueCount) / float64(existingCount) * 100
 				if lossPercent > 50 {
 					fmt.Fprintf(os.Stderr, "WARNING: Export would lose %.1f%% of issues!\n", lossPercent)
 					fmt.Fprintf(os.Stderr, "  Existing JSONL: %d issues\n", existingCount)
-					fmt.Fprintf(os.Stderr, "  Database: %d issues\n", len(issues))
+					fmt.Fprintf(os.Stderr, "  Database: %d issues\n", issueCount)
 					fmt.Fprintf(os.Stderr, "  This suggests database staleness or corruption.\n")
 					fmt.Fprintf(os.Stderr, "Press Ctrl+C to abort, or Enter to continue: ")
 					// Read a line from stdin to wait for user confirmation
@@ -162,31 +272,6 @@ Output to stdout by default, or use -o flag for file output.`,
 			}
 		}
 
-		// Sort by ID for consistent output
-		sort.Slice(issues, func(i, j int) bool {
-			return issues[i].ID < issues[j].ID
-		})
-
-		// Populate dependencies for all issues in one query (avoids N+1 problem)
-		allDeps, err := store.GetAllDependencyRecords(ctx)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error getting dependencies: %v\n", err)
-			os.Exit(1)
-		}
-		for _, issue := range issues {
-			issue.Dependencies = allDeps[issue.ID]
-		}
-
-		// Populate labels for all issues
-		for _, issue := range issues {
-			labels, err := store.GetLabels(ctx, issue.ID)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error getting labels for %s: %v\n", issue.ID, err)
-				os.Exit(1)
-			}
-			issue.Labels = labels
-		}
-
 		// Open output
 		out := os.Stdout
 		var tempFile *os.File
@@ -224,9 +309,21 @@ Output to stdout by default, or use -o flag for file output.`,
 
 		// Write JSONL (with timestamp-only deduplication for bd-164)
 		encoder := json.NewEncoder(out)
-		exportedIDs := make([]string, 0, len(issues))
+		var exportedIDs []string
 		skippedCount := 0
-		for _, issue := range issues {
+		err = forEachIssue(func(issue *types.Issue) error {
+			if anonymizeOutput {
+				// Fields under encryption stay encrypted; anonymizing doesn't make them shareable
+				exported, err := exportableIssue(store, issue)
+				if err != nil {
+					return fmt.Errorf("encrypting issue %s: %w", issue.ID, err)
+				}
+				if err := encoder.Encode(exported); err != nil {
+					return fmt.Errorf("encoding issue %s: %w", issue.ID, err)
+				}
+				return nil
+			}
+
 			// Check if this is only a timestamp change (bd-164)
 			skip, err := shouldSkipExport(ctx, issue)
 			if err != nil {
@@ -237,12 +334,15 @@ Output to stdout by default, or use -o flag for file output.`,
 			
 			if skip {
 				skippedCount++
-				continue
+				return nil
 			}
 			
-			if err := encoder.Encode(issue); err != nil {
-				fmt.Fprintf(os.Stderr, "Error encoding issue %s: %v\n", issue.ID, err)
-				os.Exit(1)
+			exported, err := exportableIssue(store, issue)
+			if err != nil {
+				return fmt.Errorf("encrypting issue %s: %w", issue.ID, err)
+			}
+			if err := encoder.Encode(exported); err != nil {
+				return fmt.Errorf("encoding issue %s: %w", issue.ID, err)
 			}
 			
 			// Save content hash after successful export (bd-164)
@@ -254,6 +354,15 @@ Output to stdout by default, or use -o flag for file output.`,
 			}
 			
 			exportedIDs = append(exportedIDs, issue.ID)
+			return nil
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			if tempFile != nil {
+				_ = tempFile.Close()
+				_ = os.Remove(tempPath)
+			}
+			os.Exit(1)
 		}
 		
 		// Report skipped issues if any (helps debugging bd-159)
@@ -263,7 +372,7 @@ Output to stdout by default, or use -o flag for file output.`,
 
 		// Only clear dirty issues and auto-flush state if exporting to the default JSONL path
 		// This prevents clearing dirty flags when exporting to custom paths (e.g., bd export -o backup.jsonl)
-		if output == "" || output == findJSONLPath() {
+		if !anonymizeOutput && (output == "" || output == findJSONLPath()) {
 			// Clear only the issues that were actually exported (fixes bd-52 race condition)
 			if err := store.ClearDirtyIssuesByID(ctx, exportedIDs); err != nil {
 				fmt.Fprintf(os.Stderr, "Warning: failed to clear dirty issues: %v\n", err)
@@ -298,9 +407,12 @@ Output to stdout by default, or use -o flag for file output.`,
 }
 
 func init() {
-	exportCmd.Flags().StringP("format", "f", "jsonl", "Export format (jsonl)")
+	exportCmd.Flags().StringP("format", "f", "jsonl", "Export format (jsonl, jira)")
 	exportCmd.Flags().StringP("output", "o", "", "Output file (default: stdout)")
 	exportCmd.Flags().StringP("status", "s", "", "Filter by status")
 	exportCmd.Flags().Bool("force", false, "Force export even if database is empty")
+	exportCmd.Flags().Bool("anonymize", false, "Replace actors with pseudonyms and strip emails/URLs from text")
+	exportCmd.Flags().String("salt", "", "Key for anonymized pseudonyms (with --anonymize)")
+	exportCmd.Flags().Bool("full", false, "Export a workspace bundle with config, comments, and history (see above)")
 	rootCmd.AddCommand(exportCmd)
 }
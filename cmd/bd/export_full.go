@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/imalsogreg/beads/internal/bundle"
+	"github.com/imalsogreg/beads/internal/importer"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+)
+
+// writeFullExport writes the workspace bundle for bd export --full, to
+// output or stdout. Bundles named .tar.gz or .tgz are gzipped.
+func writeFullExport(ctx context.Context, output string) {
+	sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: --full requires SQLite backend\n")
+		os.Exit(1)
+	}
+	if output != "" && sameFile(output, findJSONLPath()) {
+		fmt.Fprintf(os.Stderr, "Error: refusing to write a workspace bundle over the project's JSONL file\n")
+		os.Exit(1)
+	}
+
+	b, err := bundle.Collect(ctx, sqliteStore, Version)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	var out io.Writer = os.Stdout
+	var tempFile *os.File
+	if output != "" {
+		if err := validateExportPath(output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		tempFile, err = os.CreateTemp(filepath.Dir(output), filepath.Base(output)+".tmp.*")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating temporary file: %v\n", err)
+			os.Exit(1)
+		}
+		defer func() { _ = os.Remove(tempFile.Name()) }()
+		out = tempFile
+	}
+
+	compress := strings.HasSuffix(output, ".gz") || strings.HasSuffix(output, ".tgz")
+	if err := bundle.Write(out, b, compress); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing bundle: %v\n", err)
+		os.Exit(1)
+	}
+	if tempFile != nil {
+		if err := tempFile.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing bundle: %v\n", err)
+			os.Exit(1)
+		}
+		if err := os.Rename(tempFile.Name(), output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error replacing output file: %v\n", err)
+			os.Exit(1)
+		}
+		// Config and history are as private as the database (0600: rw-------)
+		if err := os.Chmod(output, 0600); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to set file permissions: %v\n", err)
+		}
+		fmt.Fprintf(os.Stderr, "Exported %d issue(s), %d comment(s), %d event(s), %d label definition(s), and %d config setting(s) to %s\n",
+			len(b.Issues), len(b.Comments), len(b.Events), len(b.Labels), len(b.Config), output)
+	}
+}
+
+// readFullImport applies a workspace bundle for bd import --full
+func readFullImport(ctx context.Context, in io.Reader, opts ImportOptions) {
+	if err := ensureDirectMode("daemon does not support full imports"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: --full requires SQLite backend\n")
+		os.Exit(1)
+	}
+
+	b, err := bundle.Read(in)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	for _, name := range b.Skipped {
+		fmt.Fprintf(os.Stderr, "Warning: skipping %s, which this version of bd doesn't know (bundle from bd %s)\n", name, b.Manifest.BdVersion)
+	}
+
+	result, err := bundle.Apply(ctx, sqliteStore, b, importer.Options{
+		ResolveCollisions: opts.ResolveCollisions,
+		SkipUpdate:        opts.SkipUpdate,
+		Strict:            opts.Strict,
+		RenameOnImport:    opts.RenameOnImport,
+	})
+	if err != nil {
+		if result != nil && result.Issues != nil && result.Issues.PrefixMismatch {
+			fmt.Fprintf(os.Stderr, "Error: the bundle's issues don't use this workspace's prefix %s-; import into an empty workspace, or use --rename-on-import\n", result.Issues.ExpectedPrefix)
+			os.Exit(1)
+		}
+		if result != nil && result.Issues != nil && len(result.Issues.CollisionIDs) > 0 && !opts.ResolveCollisions {
+			fmt.Fprintf(os.Stderr, "Error: %d issue(s) collide with different issues here: %v\n", len(result.Issues.CollisionIDs), result.Issues.CollisionIDs)
+			fmt.Fprintf(os.Stderr, "Use --resolve-collisions to give the bundle's issues new IDs.\n")
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "Import failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Comments and history don't mark issues dirty, so rewrite the JSONL in full
+	markDirtyAndScheduleFullExport()
+
+	if result.KeptPrefix != "" {
+		fmt.Fprintf(os.Stderr, "Kept this workspace's issue prefix %s- over the bundle's %s-\n", result.KeptPrefix, b.Manifest.Prefix)
+	}
+	fmt.Fprintf(os.Stderr, "Import complete: %d created, %d updated", result.Issues.Created, result.Issues.Updated)
+	if result.Issues.Unchanged > 0 {
+		fmt.Fprintf(os.Stderr, ", %d unchanged", result.Issues.Unchanged)
+	}
+	if len(result.Issues.IDMapping) > 0 {
+		fmt.Fprintf(os.Stderr, ", %d issues remapped", len(result.Issues.IDMapping))
+	}
+	fmt.Fprintf(os.Stderr, "; %d comment(s), %d event(s), %d label definition(s), %d config setting(s)\n",
+		result.Comments, result.Events, result.Labels, result.Config)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/imalsogreg/beads/internal/integrations/jira"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// writeJiraExport writes issues as a Jira-importable CSV to output, or stdout
+func writeJiraExport(ctx context.Context, issues []*types.Issue, output string) {
+	allDeps, err := store.GetAllDependencyRecords(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting dependencies: %v\n", err)
+		os.Exit(1)
+	}
+	for _, issue := range issues {
+		issue.Dependencies = allDeps[issue.ID]
+		if issue.Labels, err = store.GetLabels(ctx, issue.ID); err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting labels for %s: %v\n", issue.ID, err)
+			os.Exit(1)
+		}
+	}
+
+	var out io.Writer = os.Stdout
+	if output != "" {
+		if err := validateExportPath(output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		// #nosec G304 - user-provided file path is intentional
+		f, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating output file: %v\n", err)
+			os.Exit(1)
+		}
+		defer func() { _ = f.Close() }()
+		out = f
+	}
+
+	if err := jira.WriteCSV(out, issues); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing Jira CSV: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -258,3 +259,89 @@ func TestExportCommand(t *testing.T) {
 		}
 	})
 }
+
+// TestExportManyIssues exports more issues than the store reads in a page,
+// so the export streams across several
+func TestExportManyIssues(t *testing.T) {
+	tmpDir := t.TempDir()
+	testDB := filepath.Join(tmpDir, "test.db")
+	s := newTestStore(t, testDB)
+	defer s.Close()
+	ctx := context.Background()
+
+	const n = 1200
+	var ids []string
+	for i := 0; i < n; i++ {
+		issue := &types.Issue{
+			Title:     fmt.Sprintf("Issue %d", i),
+			Priority:  i % 5,
+			IssueType: types.TypeTask,
+			Status:    types.StatusOpen,
+		}
+		if err := s.CreateIssue(ctx, issue, "test-user"); err != nil {
+			t.Fatalf("Failed to create issue: %v", err)
+		}
+		ids = append(ids, issue.ID)
+		if i%100 == 0 {
+			if err := s.AddLabel(ctx, issue.ID, "hundred", "test-user"); err != nil {
+				t.Fatalf("Failed to add label: %v", err)
+			}
+		}
+	}
+	dep := &types.Dependency{IssueID: ids[n-1], DependsOnID: ids[0], Type: types.DepBlocks}
+	if err := s.AddDependency(ctx, dep, "test-user"); err != nil {
+		t.Fatalf("Failed to add dependency: %v", err)
+	}
+
+	exportPath := filepath.Join(tmpDir, "export_many.jsonl")
+	store = s
+	dbPath = testDB
+	exportCmd.Flags().Set("output", exportPath)
+	exportCmd.Run(exportCmd, []string{})
+
+	file, err := os.Open(exportPath)
+	if err != nil {
+		t.Fatalf("Failed to open export file: %v", err)
+	}
+	defer file.Close()
+
+	var exported []*types.Issue
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var issue types.Issue
+		if err := json.Unmarshal(scanner.Bytes(), &issue); err != nil {
+			t.Fatalf("Failed to parse JSONL line %d: %v", len(exported)+1, err)
+		}
+		exported = append(exported, &issue)
+	}
+	if len(exported) != n {
+		t.Fatalf("Expected %d issues in export, got %d", n, len(exported))
+	}
+
+	labeled := 0
+	for i, issue := range exported {
+		if i > 0 && exported[i-1].ID >= issue.ID {
+			t.Fatalf("Export not sorted by ID: %s before %s", exported[i-1].ID, issue.ID)
+		}
+		if len(issue.Labels) > 0 {
+			labeled++
+		}
+		if issue.ID == ids[n-1] && (len(issue.Dependencies) != 1 || issue.Dependencies[0].DependsOnID != ids[0]) {
+			t.Errorf("Expected %s to depend on %s, got %v", issue.ID, ids[0], issue.Dependencies)
+		}
+	}
+	if labeled != n/100 {
+		t.Errorf("Expected %d labeled issues, got %d", n/100, labeled)
+	}
+
+	// Every exported issue has its export hash recorded
+	for _, id := range []string{ids[0], ids[n/2], ids[n-1]} {
+		hash, err := s.GetExportHash(ctx, id)
+		if err != nil {
+			t.Fatalf("GetExportHash failed: %v", err)
+		}
+		if hash == "" {
+			t.Errorf("Expected an export hash for %s", id)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/imalsogreg/beads/internal/git"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/spf13/cobra"
+)
+
+var gitCmd = &cobra.Command{
+	Use:   "git",
+	Short: "Link git commits to issues",
+	Long: `Link git commits to the issues their messages mention.
+
+A commit whose message contains an issue ID (bd-123) is linked to that issue.
+If the ID follows a closing keyword (close, fix, or resolve in any tense, as
+in "fixes bd-123" or "Closes: bd-4, bd-5"), the issue is also closed, with the
+commit as the reason. Set git.auto_close to false to only record links:
+  bd config set git.auto_close false
+
+'bd git install-hook' adds a post-commit hook that links each new commit.
+CI can report commits with POST /issues/{id}/commits on 'bd serve'.
+
+Examples:
+  bd git install-hook
+  bd git link                    # HEAD
+  bd git link main..feature      # every commit in a range
+  bd git commits bd-123`,
+}
+
+var gitLinkCmd = &cobra.Command{
+	Use:   "link [revision...]",
+	Short: "Link commits to the issues they mention",
+	Long: `Link commits to the issues they mention, closing issues they fix.
+
+Revisions are passed to 'git log' (default: HEAD). Commits already linked are
+skipped, so scanning the same history again is safe.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		noClose, _ := cmd.Flags().GetBool("no-close")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		ctx := context.Background()
+		sqliteStore := requireGitStore()
+		commits, err := git.ReadCommits(ctx, ".", args...)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		opts := git.LinkOptions{Actor: actor, DryRun: dryRun, AutoClose: !noClose}
+		opts.Prefix, _ = sqliteStore.GetConfig(ctx, "issue_prefix")
+		if value, _ := sqliteStore.GetConfig(ctx, git.AutoCloseConfigKey); !git.AutoCloseEnabled(value) {
+			opts.AutoClose = false
+		}
+
+		// Oldest first, so a later commit's links come after an earlier one's
+		results := []*git.LinkResult{}
+		for i := len(commits) - 1; i >= 0; i-- {
+			linked, err := git.Link(ctx, sqliteStore, commits[i], opts)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			results = append(results, linked...)
+		}
+
+		changed := false
+		for _, r := range results {
+			changed = changed || r.Closed
+		}
+		if changed && !dryRun {
+			markDirtyAndScheduleFlush()
+		}
+
+		if jsonOutput {
+			outputJSON(results)
+			return
+		}
+
+		prefix := ""
+		if dryRun {
+			prefix = "[DRY RUN] "
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		count := 0
+		for _, r := range results {
+			if !r.Linked && !r.Closed {
+				continue
+			}
+			count++
+			action := "Linked"
+			if r.Closed {
+				action = "Closed"
+			}
+			fmt.Printf("%s%s %s %s ← %s\n", prefix, green("✓"), action, r.IssueID, shortSHA(r.SHA))
+		}
+		if count == 0 {
+			fmt.Printf("No new issue references in %d commit(s)\n", len(commits))
+		}
+	},
+}
+
+var gitCommitsCmd = &cobra.Command{
+	Use:   "commits <issue-id>",
+	Short: "List commits linked to an issue",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		links, err := requireGitStore().GetCommitLinks(context.Background(), args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			if links == nil {
+				links = []*sqlite.CommitLink{}
+			}
+			outputJSON(links)
+			return
+		}
+
+		if len(links) == 0 {
+			fmt.Printf("No commits linked to %s\n", args[0])
+			return
+		}
+		for _, link := range links {
+			commit := &git.Commit{SHA: link.SHA, Message: link.Message}
+			when := ""
+			if link.CommittedAt != nil {
+				when = link.CommittedAt.Local().Format("2006-01-02 15:04") + "  "
+			}
+			fmt.Printf("%s  %s%s\n", commit.ShortSHA(), when, commit.Subject())
+			if link.URL != "" {
+				fmt.Printf("         %s\n", link.URL)
+			}
+		}
+	},
+}
+
+const postCommitHookSignature = "bd (beads) post-commit hook"
+
+var gitInstallHookCmd = &cobra.Command{
+	Use:   "install-hook",
+	Short: "Install a post-commit hook that links commits to issues",
+	Run: func(_ *cobra.Command, _ []string) {
+		out, err := exec.Command("git", "rev-parse", "--git-path", "hooks/post-commit").Output()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: not in a git repository\n")
+			os.Exit(1)
+		}
+		hookPath := strings.TrimSpace(string(out))
+
+		// #nosec G304 - path comes from git rev-parse
+		if existing, err := os.ReadFile(hookPath); err == nil {
+			if strings.Contains(string(existing), postCommitHookSignature) {
+				fmt.Println("post-commit hook already installed")
+				return
+			}
+			backup := hookPath + ".backup-" + time.Now().Format("20060102-150405")
+			if err := os.Rename(hookPath, backup); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: failed to back up existing hook: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("Backed up existing post-commit hook to %s\n", backup)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(hookPath), 0750); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to create hooks directory: %v\n", err)
+			os.Exit(1)
+		}
+		// #nosec G306 - git hooks must be executable
+		if err := os.WriteFile(hookPath, []byte(postCommitHook), 0755); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to write hook: %v\n", err)
+			os.Exit(1)
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Installed post-commit hook at %s\n", green("✓"), hookPath)
+	},
+}
+
+const postCommitHook = `#!/bin/sh
+#
+# ` + postCommitHookSignature + `
+#
+# Links the new commit to the issues its message mentions (bd-123) and
+# closes the ones it fixes ("fixes bd-123"). Installed by 'bd git install-hook'.
+
+# Check if bd is available
+if ! command -v bd >/dev/null 2>&1; then
+    exit 0
+fi
+
+# Check if we're in a bd workspace
+if [ ! -d .beads ]; then
+    exit 0
+fi
+
+if ! bd git link HEAD >/dev/null 2>&1; then
+    echo "Warning: failed to link commit to bd issues (run 'bd git link HEAD')" >&2
+fi
+
+exit 0
+`
+
+func requireGitStore() *sqlite.SQLiteStorage {
+	if err := ensureDirectMode("daemon does not support git command"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: git command requires SQLite backend\n")
+		os.Exit(1)
+	}
+	return sqliteStore
+}
+
+func shortSHA(sha string) string {
+	return (&git.Commit{SHA: sha}).ShortSHA()
+}
+
+func init() {
+	gitLinkCmd.Flags().Bool("no-close", false, "Only record links; don't close fixed issues")
+	gitLinkCmd.Flags().Bool("dry-run", false, "Show what would be linked without changing anything")
+	gitLinkCmd.Flags().Bool("json", false, "Output JSON format")
+	gitCommitsCmd.Flags().Bool("json", false, "Output JSON format")
+
+	gitCmd.AddCommand(gitLinkCmd)
+	gitCmd.AddCommand(gitCommitsCmd)
+	gitCmd.AddCommand(gitInstallHookCmd)
+	rootCmd.AddCommand(gitCmd)
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/spf13/cobra"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history <issue-id>",
+	Short: "Show every revision of an issue",
+	Long: `Show an issue's history as a timeline, oldest first. Each revision lists
+the fields it changed with their before and after values.
+
+Examples:
+  bd history bd-42
+  bd history bd-42 --json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		sqliteStore := requireHistoryStore()
+		ctx := context.Background()
+		id := args[0]
+
+		issue, err := sqliteStore.GetIssue(ctx, id)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if issue == nil {
+			fmt.Fprintf(os.Stderr, "Error: issue %s not found\n", id)
+			os.Exit(1)
+		}
+
+		history, err := sqliteStore.GetIssueHistory(ctx, id)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			if history == nil {
+				history = []*sqlite.IssueRevision{}
+			}
+			outputJSON(history)
+			return
+		}
+
+		cyan := color.New(color.FgCyan).SprintFunc()
+		fmt.Printf("%s: %s\n\n", cyan(issue.ID), issue.Title)
+		if len(history) == 0 {
+			fmt.Println("No history recorded")
+			return
+		}
+		fmt.Print(formatHistoryTimeline(history))
+	},
+}
+
+// formatHistoryTimeline renders revisions as a timeline, one block per revision
+func formatHistoryTimeline(history []*sqlite.IssueRevision) string {
+	bold := color.New(color.Bold).SprintFunc()
+
+	var b strings.Builder
+	lastDay := ""
+	for _, rev := range history {
+		when := rev.CreatedAt.Local()
+		if day := when.Format("Mon Jan 2, 2006"); day != lastDay {
+			if lastDay != "" {
+				b.WriteString("\n")
+			}
+			fmt.Fprintf(&b, "%s\n", bold(day))
+			lastDay = day
+		}
+
+		fmt.Fprintf(&b, "  %s  #%d %s by %s", when.Format("15:04"), rev.Revision, rev.Kind, rev.Actor)
+		if rev.Reverts != 0 {
+			fmt.Fprintf(&b, " (undoes #%d)", rev.Reverts)
+		}
+		b.WriteString("\n")
+		for _, c := range rev.Changes {
+			fmt.Fprintf(&b, "         %s: %s → %s\n", c.Field, formatAuditValue(c.Old), formatAuditValue(c.New))
+		}
+		if rev.Comment != "" {
+			fmt.Fprintf(&b, "         %s\n", truncateAuditText(rev.Comment))
+		}
+	}
+	return b.String()
+}
+
+var undoCmd = &cobra.Command{
+	Use:   "undo [issue-id]",
+	Short: "Undo your most recent change",
+	Long: `Revert your most recent update, close, or label change, restoring the
+prior values. With an issue ID, only changes to that issue are considered.
+Running it again undoes the change before that.
+
+Undo refuses to overwrite a field someone changed after you did.
+
+Examples:
+  bd undo          # Undo your last change to any issue
+  bd undo bd-42    # Undo your last change to bd-42`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		issueID := ""
+		if len(args) == 1 {
+			issueID = args[0]
+		}
+
+		rev, err := requireHistoryStore().RevertLastChange(context.Background(), issueID, actor)
+		if errors.Is(err, sqlite.ErrNothingToRevert) {
+			fmt.Fprintf(os.Stderr, "Error: no change by %s to undo\n", actor)
+			os.Exit(1)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		markDirtyAndScheduleFlush()
+
+		if jsonOutput {
+			outputJSON(rev)
+			return
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Reverted revision %d of %s\n", green("✓"), rev.Reverts, rev.IssueID)
+		for _, c := range rev.Changes {
+			fmt.Printf("  %s: %s → %s\n", c.Field, formatAuditValue(c.Old), formatAuditValue(c.New))
+		}
+	},
+}
+
+func requireHistoryStore() *sqlite.SQLiteStorage {
+	if err := ensureDirectMode("daemon does not support history commands"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: history commands require SQLite backend\n")
+		os.Exit(1)
+	}
+	return sqliteStore
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	rootCmd.AddCommand(undoCmd)
+}
@@ -5,11 +5,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"sort"
-	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/imalsogreg/beads/internal/integrations/jira"
+	"github.com/imalsogreg/beads/internal/storage"
 	"github.com/imalsogreg/beads/internal/types"
 )
 
@@ -20,13 +22,29 @@ var importCmd = &cobra.Command{
 
 Reads from stdin by default, or use -i flag for file input.
 
+With --format jira, reads a Jira export instead (JSON from the REST API or
+the CSV export). Epics and parents become parent-child dependencies, "Blocks"
+links become blocking dependencies, and other links become related ones.
+Story points become estimated_minutes via --minutes-per-point unless the
+issue has an original estimate. Each issue keeps its key in external_ref
+(jira-<KEY>), so importing a newer export updates the same issues.
+
 Behavior:
   - Existing issues (same ID) are updated
   - New issues are created
   - Collisions (same ID, different content) are detected
   - Use --resolve-collisions to automatically remap colliding issues
   - Use --dedupe-after to find and merge content duplicates after import
-  - Use --dry-run to preview changes without applying them`,
+  - Use --dry-run to preview changes without applying them
+
+With --full, reads a workspace bundle written by 'bd export --full': config,
+label definitions, issues, comments, and history. Into an empty workspace it
+brings everything across, including the issue prefix; into one with issues,
+the workspace keeps its prefix and existing issues keep their own history.
+
+Examples:
+  bd export --full -o workspace.tar.gz          # in the old workspace
+  bd init && bd import --full -i workspace.tar.gz   # in the new one`,
 	Run: func(cmd *cobra.Command, args []string) {
 		input, _ := cmd.Flags().GetString("input")
 		skipUpdate, _ := cmd.Flags().GetBool("skip-existing")
@@ -35,6 +53,18 @@ Behavior:
 		dryRun, _ := cmd.Flags().GetBool("dry-run")
 		renameOnImport, _ := cmd.Flags().GetBool("rename-on-import")
 		dedupeAfter, _ := cmd.Flags().GetBool("dedupe-after")
+		format, _ := cmd.Flags().GetString("format")
+		minutesPerPoint, _ := cmd.Flags().GetInt("minutes-per-point")
+		full, _ := cmd.Flags().GetBool("full")
+
+		if format != "jsonl" && format != "jira" {
+			fmt.Fprintf(os.Stderr, "Error: unknown format %q (valid: jsonl, jira)\n", format)
+			os.Exit(1)
+		}
+		if full && (format != "jsonl" || dryRun || dedupeAfter) {
+			fmt.Fprintf(os.Stderr, "Error: --full can't be combined with --format, --dry-run, or --dedupe-after\n")
+			os.Exit(1)
+		}
 
 		// Open input
 		in := os.Stdin
@@ -53,35 +83,22 @@ Behavior:
 			in = f
 		}
 
-		// Phase 1: Read and parse all JSONL
+		// Phase 1: Read and parse all JSONL (or convert a Jira export)
 		ctx := context.Background()
-		scanner := bufio.NewScanner(in)
-
-		var allIssues []*types.Issue
-		lineNum := 0
-
-		for scanner.Scan() {
-			lineNum++
-			line := scanner.Text()
-
-			// Skip empty lines
-			if line == "" {
-				continue
-			}
-
-			// Parse JSON
-			var issue types.Issue
-			if err := json.Unmarshal([]byte(line), &issue); err != nil {
-				fmt.Fprintf(os.Stderr, "Error parsing line %d: %v\n", lineNum, err)
-				os.Exit(1)
-			}
-
-			allIssues = append(allIssues, &issue)
+		if full {
+			readFullImport(ctx, in, ImportOptions{
+				ResolveCollisions: resolveCollisions,
+				SkipUpdate:        skipUpdate,
+				Strict:            strict,
+				RenameOnImport:    renameOnImport,
+			})
+			return
 		}
-
-		if err := scanner.Err(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
-			os.Exit(1)
+		var allIssues []*types.Issue
+		if format == "jira" {
+			allIssues = readJiraIssues(ctx, in, minutesPerPoint)
+		} else {
+			allIssues = readJSONLIssues(in)
 		}
 
 		// Phase 2: Use shared import logic
@@ -199,45 +216,31 @@ Behavior:
 		if dedupeAfter {
 			fmt.Fprintf(os.Stderr, "\n=== Post-Import Duplicate Detection ===\n")
 
-			// Get all issues (fresh after import)
-			allIssues, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+			duplicateGroups, err := storage.FindDuplicates(ctx, store, 0)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error fetching issues for deduplication: %v\n", err)
 				os.Exit(1)
 			}
-
-			duplicateGroups := findDuplicateGroups(allIssues)
 			if len(duplicateGroups) == 0 {
 				fmt.Fprintf(os.Stderr, "No duplicates found.\n")
 				return
 			}
 
-			refCounts := countReferences(allIssues)
-
 			fmt.Fprintf(os.Stderr, "Found %d duplicate group(s)\n\n", len(duplicateGroups))
 
 			for i, group := range duplicateGroups {
-				target := chooseMergeTarget(group, refCounts)
-				fmt.Fprintf(os.Stderr, "Group %d: %s\n", i+1, group[0].Title)
+				fmt.Fprintf(os.Stderr, "Group %d: %s\n", i+1, group.Title)
 
-				for _, issue := range group {
-					refs := refCounts[issue.ID]
+				for _, issue := range group.Issues {
 					marker := "  "
-					if issue.ID == target.ID {
+					if issue.IsMergeTarget {
 						marker = "→ "
 					}
 					fmt.Fprintf(os.Stderr, "  %s%s (%s, P%d, %d refs)\n",
-						marker, issue.ID, issue.Status, issue.Priority, refs)
+						marker, issue.ID, issue.Status, issue.Priority, issue.References)
 				}
 
-				sources := make([]string, 0, len(group)-1)
-				for _, issue := range group {
-					if issue.ID != target.ID {
-						sources = append(sources, issue.ID)
-					}
-				}
-				fmt.Fprintf(os.Stderr, "  Suggested: bd merge %s --into %s\n\n",
-					strings.Join(sources, " "), target.ID)
+				fmt.Fprintf(os.Stderr, "  Suggested: %s\n\n", group.SuggestedCommand)
 			}
 
 			fmt.Fprintf(os.Stderr, "Run 'bd duplicates --auto-merge' to merge all duplicates.\n")
@@ -245,6 +248,39 @@ Behavior:
 	},
 }
 
+// readJSONLIssues parses one issue per line, exiting on malformed input
+func readJSONLIssues(in io.Reader) []*types.Issue {
+	scanner := bufio.NewScanner(in)
+
+	var allIssues []*types.Issue
+	lineNum := 0
+
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+
+		// Skip empty lines
+		if line == "" {
+			continue
+		}
+
+		// Parse JSON
+		var issue types.Issue
+		if err := json.Unmarshal([]byte(line), &issue); err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing line %d: %v\n", lineNum, err)
+			os.Exit(1)
+		}
+
+		allIssues = append(allIssues, &issue)
+	}
+
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		os.Exit(1)
+	}
+	return allIssues
+}
+
 func init() {
 	importCmd.Flags().StringP("input", "i", "", "Input file (default: stdin)")
 	importCmd.Flags().BoolP("skip-existing", "s", false, "Skip existing issues instead of updating them")
@@ -253,5 +289,8 @@ func init() {
 	importCmd.Flags().Bool("dedupe-after", false, "Detect and report content duplicates after import")
 	importCmd.Flags().Bool("dry-run", false, "Preview collision detection without making changes")
 	importCmd.Flags().Bool("rename-on-import", false, "Rename imported issues to match database prefix (updates all references)")
+	importCmd.Flags().String("format", "jsonl", "Input format: jsonl or jira (Jira JSON or CSV export)")
+	importCmd.Flags().Int("minutes-per-point", jira.DefaultMinutesPerPoint, "Minutes per story point for --format jira")
+	importCmd.Flags().Bool("full", false, "Import a workspace bundle from 'bd export --full'")
 	rootCmd.AddCommand(importCmd)
 }
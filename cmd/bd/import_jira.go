@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/imalsogreg/beads/internal/integrations/jira"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// readJiraIssues converts a Jira export into issues for the importer. Issues
+// imported before keep their IDs; new ones are numbered after the highest
+// existing ID under the configured prefix.
+func readJiraIssues(ctx context.Context, in io.Reader, minutesPerPoint int) []*types.Issue {
+	if err := ensureDirectMode("daemon does not support Jira import"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	jiraIssues, err := jira.Parse(in)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	prefix, err := store.GetConfig(ctx, "issue_prefix")
+	if err != nil || prefix == "" {
+		fmt.Fprintf(os.Stderr, "Error: issue_prefix not configured in database\n")
+		os.Exit(1)
+	}
+	existing, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	conv, err := jira.Convert(jiraIssues, existing, jira.ConvertOptions{Prefix: prefix, MinutesPerPoint: minutesPerPoint})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Fprintf(os.Stderr, "Read %d Jira issue(s), %d not imported before\n", len(conv.Issues), conv.New)
+	if len(conv.Skipped) > 0 {
+		fmt.Fprintf(os.Stderr, "Skipped %d reference(s) to issues outside the export:\n", len(conv.Skipped))
+		for _, s := range conv.Skipped {
+			fmt.Fprintf(os.Stderr, "  %s\n", s)
+		}
+	}
+	return conv.Issues
+}
@@ -53,7 +53,7 @@ Examples:
 			}
 
 			// Get issue count from daemon
-			resp, err := daemonClient.Stats()
+			resp, err := daemonClient.Stats(nil)
 			if err == nil {
 				var stats types.Statistics
 				if jsonErr := json.Unmarshal(resp.Data, &stats); jsonErr == nil {
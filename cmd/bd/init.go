@@ -12,6 +12,7 @@ import (
 	"github.com/imalsogreg/beads"
 	"github.com/imalsogreg/beads/internal/config"
 	"github.com/imalsogreg/beads/internal/configfile"
+	"github.com/imalsogreg/beads/internal/storage"
 	"github.com/imalsogreg/beads/internal/storage/sqlite"
 )
 
@@ -66,6 +67,10 @@ With --no-db: creates .beads/ directory and issues.jsonl file instead of SQLite
 		if initDBPath == "" {
 		initDBPath = filepath.Join(".beads", beads.CanonicalDatabaseName)
 		}
+		if driver := storage.DSNDriver(initDBPath); driver != sqlite.DriverName {
+			fmt.Fprintf(os.Stderr, "Error: bd init creates SQLite databases; set up the %s backend with its own tools\n", driver)
+			os.Exit(1)
+		}
 
 		// Migrate old database files if they exist
 	if err := migrateOldDatabases(initDBPath, quiet); err != nil {
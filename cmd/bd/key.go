@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/fatih/color"
+	"github.com/imalsogreg/beads/internal/apikey"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/spf13/cobra"
+)
+
+var keyCmd = &cobra.Command{
+	Use:   "key",
+	Short: "Manage API keys for bd serve",
+	Long: `Issue API keys so each client of 'bd serve' gets only the access it needs.
+
+Each key has one role:
+  reader  GET requests
+  writer  reader, plus creating and changing issues
+  admin   writer, plus managing keys and webhooks, setting config, and /admin
+
+Clients send the key as 'Authorization: Bearer <token>'. Requests that don't
+send X-Actor are attributed to the key's name. BEADS_API_SECRET, if set,
+still works and has the admin role. Once any key exists, requests without a
+valid token are rejected even if BEADS_API_SECRET is unset.
+
+Examples:
+  bd key create triage-agent --role reader
+  bd key create ci --role writer
+  bd key list
+  bd key revoke 2`,
+}
+
+var keyCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create an API key",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		roleName, _ := cmd.Flags().GetString("role")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		role, err := apikey.ParseRole(roleName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		token, err := apikey.Generate()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		key := &sqlite.APIKey{Name: args[0], Role: string(role), Hint: apikey.Hint(token), CreatedBy: actor}
+		if err := requireKeyStore().CreateAPIKey(context.Background(), key, apikey.Hash(token)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			outputJSON(struct {
+				*sqlite.APIKey
+				Token string `json:"token"`
+			}{key, token})
+			return
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Created %s key %d for %s\n\n  %s\n\n", green("✓"), key.Role, key.ID, key.Name, token)
+		fmt.Println("Save this token now; it can't be shown again.")
+	},
+}
+
+var keyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List API keys",
+	Run: func(cmd *cobra.Command, _ []string) {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		keys, err := requireKeyStore().ListAPIKeys(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			if keys == nil {
+				keys = []*sqlite.APIKey{}
+			}
+			outputJSON(keys)
+			return
+		}
+
+		if len(keys) == 0 {
+			fmt.Println("No API keys")
+			return
+		}
+		for _, key := range keys {
+			used := "never used"
+			if key.LastUsedAt != nil {
+				used = "last used " + key.LastUsedAt.Local().Format("2006-01-02 15:04")
+			}
+			fmt.Printf("%d  %-7s %s  %s…  (%s)\n", key.ID, key.Role, key.Name, key.Hint, used)
+		}
+	},
+}
+
+var keyRevokeCmd = &cobra.Command{
+	Use:   "revoke <id>",
+	Short: "Revoke an API key",
+	Args:  cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid key ID %q\n", args[0])
+			os.Exit(1)
+		}
+		if err := requireKeyStore().DeleteAPIKey(context.Background(), id); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Revoked key %d\n", green("✓"), id)
+	},
+}
+
+func requireKeyStore() *sqlite.SQLiteStorage {
+	if err := ensureDirectMode("daemon does not support key command"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: key command requires SQLite backend\n")
+		os.Exit(1)
+	}
+	return sqliteStore
+}
+
+func init() {
+	keyCreateCmd.Flags().String("role", string(apikey.RoleReader), "Role: reader, writer, or admin")
+	keyCreateCmd.Flags().Bool("json", false, "Output JSON format")
+	keyListCmd.Flags().Bool("json", false, "Output JSON format")
+
+	keyCmd.AddCommand(keyCreateCmd)
+	keyCmd.AddCommand(keyListCmd)
+	keyCmd.AddCommand(keyRevokeCmd)
+	rootCmd.AddCommand(keyCmd)
+}
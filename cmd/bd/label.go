@@ -12,6 +12,7 @@ import (
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	"github.com/imalsogreg/beads/internal/rpc"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
 	"github.com/imalsogreg/beads/internal/types"
 )
 
@@ -268,10 +269,128 @@ var labelListAllCmd = &cobra.Command{
 	},
 }
 
+var labelDescribeCmd = &cobra.Command{
+	Use:   "describe [label]",
+	Short: "Show or set a label's color, description, and parent",
+	Long: `Show a label's metadata, children, and usage, or set its metadata.
+
+Setting --color, --description, or --parent defines the label if it isn't
+already. Path-style names such as infra/db sit under their prefix unless
+--parent says otherwise. Filters such as bd list --label 'infra/*' match every
+label under infra, at any depth.
+
+Examples:
+  bd label describe infra/db                                 # Show infra/db
+  bd label describe infra --color '#ff8800' -d "Servers"     # Define or update infra
+  bd label describe postgres --parent infra/db               # Put postgres under infra/db
+  bd label describe postgres --parent ""                     # Make postgres top-level`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		sqliteStore := requireLabelStore()
+		ctx := context.Background()
+		name := args[0]
+
+		setColor := cmd.Flags().Changed("color")
+		setDescription := cmd.Flags().Changed("description")
+		setParent := cmd.Flags().Changed("parent")
+		if setColor || setDescription || setParent {
+			label, err := sqliteStore.GetLabel(ctx, name)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if label == nil {
+				label = &sqlite.Label{Name: name}
+			}
+			if setColor {
+				label.Color, _ = cmd.Flags().GetString("color")
+			}
+			if setDescription {
+				label.Description, _ = cmd.Flags().GetString("description")
+			}
+			if setParent {
+				label.Parent, _ = cmd.Flags().GetString("parent")
+			}
+
+			verb := "Updated"
+			if label.Defined {
+				err = sqliteStore.UpdateLabel(ctx, label)
+			} else {
+				label.CreatedBy = actor
+				err = sqliteStore.CreateLabel(ctx, label)
+				verb = "Defined"
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if !jsonOutput {
+				green := color.New(color.FgGreen).SprintFunc()
+				fmt.Printf("%s %s label %s\n", green("✓"), verb, label.Name)
+			}
+		}
+
+		label, err := sqliteStore.GetLabel(ctx, name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if label == nil {
+			fmt.Fprintf(os.Stderr, "Error: label %s not found\n", name)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			outputJSON(label)
+			return
+		}
+
+		cyan := color.New(color.FgCyan).SprintFunc()
+		fmt.Printf("\n%s %s\n", cyan("🏷"), label.Name)
+		if label.Description != "" {
+			fmt.Printf("  %s\n", label.Description)
+		}
+		if label.Color != "" {
+			var r, g, b int
+			_, _ = fmt.Sscanf(label.Color, "#%02x%02x%02x", &r, &g, &b)
+			fmt.Printf("  Color: %s %s\n", color.RGB(r, g, b).Sprint("■"), label.Color)
+		}
+		if label.Parent != "" {
+			fmt.Printf("  Parent: %s\n", label.Parent)
+		}
+		if len(label.Children) > 0 {
+			fmt.Printf("  Children: %s\n", strings.Join(label.Children, ", "))
+		}
+		fmt.Printf("  Issues: %d\n", label.Issues)
+		if !label.Defined {
+			fmt.Printf("  Not defined yet; set --color, --description, or --parent to define it\n")
+		}
+		fmt.Println()
+	},
+}
+
+func requireLabelStore() *sqlite.SQLiteStorage {
+	if err := ensureDirectMode("daemon does not support label describe command"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: label describe requires SQLite backend\n")
+		os.Exit(1)
+	}
+	return sqliteStore
+}
+
 func init() {
+	labelDescribeCmd.Flags().String("color", "", "Color as #rrggbb (empty to clear)")
+	labelDescribeCmd.Flags().StringP("description", "d", "", "What the label is for")
+	labelDescribeCmd.Flags().String("parent", "", "Label this one sits under (empty for top-level)")
+
 	labelCmd.AddCommand(labelAddCmd)
 	labelCmd.AddCommand(labelRemoveCmd)
 	labelCmd.AddCommand(labelListCmd)
 	labelCmd.AddCommand(labelListAllCmd)
+	labelCmd.AddCommand(labelDescribeCmd)
 	rootCmd.AddCommand(labelCmd)
 }
@@ -8,8 +8,12 @@ import (
 	"os"
 	"strings"
 	"text/template"
+	"time"
 
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"github.com/imalsogreg/beads/internal/depgraph"
+	"github.com/imalsogreg/beads/internal/markdown"
 	"github.com/imalsogreg/beads/internal/rpc"
 	"github.com/imalsogreg/beads/internal/storage"
 	"github.com/imalsogreg/beads/internal/types"
@@ -47,13 +51,37 @@ var listCmd = &cobra.Command{
 		titleSearch, _ := cmd.Flags().GetString("title")
 	idFilter, _ := cmd.Flags().GetString("id")
 		jsonOutput, _ := cmd.Flags().GetBool("json")
+		includeArchived, _ := cmd.Flags().GetBool("include-archived")
+		overdue, _ := cmd.Flags().GetBool("overdue")
+		dueBefore, _ := cmd.Flags().GetString("due-before")
+		dueAfter, _ := cmd.Flags().GetString("due-after")
+		milestone, _ := cmd.Flags().GetString("milestone")
+		sortSpec, _ := cmd.Flags().GetString("sort")
+		columnSpec, _ := cmd.Flags().GetString("columns")
+		where, _ := cmd.Flags().GetString("query")
 
 		// Normalize labels: trim, dedupe, remove empty
 		labels = normalizeLabels(labels)
 	labelsAny = normalizeLabels(labelsAny)
 
 		filter := types.IssueFilter{
-		Limit: limit,
+		Limit:           limit,
+		ExcludeArchived: !includeArchived,
+		}
+		sortKeys, err := types.ParseIssueSort(sortSpec)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --sort: %v\n", err)
+			os.Exit(1)
+		}
+		filter.Sort = sortKeys
+		if filter.Query, err = types.ParseQuery(where, time.Now()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --query: %v\n", err)
+			os.Exit(1)
+		}
+		columns, err := parseListColumns(columnSpec)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --columns: %v\n", err)
+			os.Exit(1)
 		}
 		if status != "" && status != "all" {
 		s := types.Status(status)
@@ -86,6 +114,19 @@ var listCmd = &cobra.Command{
 	filter.IDs = ids
 	}
 	}
+		filter.Overdue = overdue
+		if milestone != "" {
+			filter.Milestone = &milestone
+		}
+		for flag, field := range map[string]**time.Time{"due-before": &filter.DueBefore, "due-after": &filter.DueAfter} {
+			value, _ := cmd.Flags().GetString(flag)
+			date, err := types.ParseOptionalDate(value, time.Now())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: --%s: %v\n", flag, err)
+				os.Exit(1)
+			}
+			*field = date
+		}
 
 	// If daemon is running, use RPC
 		if daemonClient != nil {
@@ -94,6 +135,14 @@ var listCmd = &cobra.Command{
 				IssueType: issueType,
 				Assignee:  assignee,
 				Limit:     limit,
+				DueBefore: dueBefore,
+				DueAfter:  dueAfter,
+				Overdue:   overdue,
+				Milestone: milestone,
+				Sort:      sortSpec,
+				Where:     where,
+
+				IncludeArchived: includeArchived,
 			}
 			if cmd.Flags().Changed("priority") {
 				priority, _ := cmd.Flags().GetInt("priority")
@@ -125,8 +174,12 @@ var listCmd = &cobra.Command{
 				os.Exit(1)
 			}
 
-			if jsonOutput {
+			if formatStr == "markdown" {
+				fmt.Print(markdown.IssueList(issues))
+			} else if jsonOutput {
 				outputJSON(issues)
+			} else if len(columns) > 0 {
+				printListColumns(issues, columns)
 			} else {
 				fmt.Printf("\nFound %d issues:\n\n", len(issues))
 				for _, issue := range issues {
@@ -135,6 +188,7 @@ var listCmd = &cobra.Command{
 					if issue.Assignee != "" {
 						fmt.Printf("  Assignee: %s\n", issue.Assignee)
 					}
+					printListDue(issue)
 					if len(issue.Labels) > 0 {
 						fmt.Printf("  Labels: %v\n", issue.Labels)
 					}
@@ -173,33 +227,45 @@ var listCmd = &cobra.Command{
 			return
 		}
 
+		// SearchIssues loads the issues' labels
 		if jsonOutput {
-			// Populate labels for JSON output
-			for _, issue := range issues {
-				issue.Labels, _ = store.GetLabels(ctx, issue.ID)
-			}
 			outputJSON(issues)
 			return
 		}
 
+		if len(columns) > 0 {
+			printListColumns(issues, columns)
+			return
+		}
+
 		fmt.Printf("\nFound %d issues:\n\n", len(issues))
 		for _, issue := range issues {
-			// Load labels for display
-			labels, _ := store.GetLabels(ctx, issue.ID)
-
 			fmt.Printf("%s [P%d] [%s] %s\n", issue.ID, issue.Priority, issue.IssueType, issue.Status)
 			fmt.Printf("  %s\n", issue.Title)
 			if issue.Assignee != "" {
 				fmt.Printf("  Assignee: %s\n", issue.Assignee)
 			}
-			if len(labels) > 0 {
-				fmt.Printf("  Labels: %v\n", labels)
+			printListDue(issue)
+			if len(issue.Labels) > 0 {
+				fmt.Printf("  Labels: %v\n", issue.Labels)
 			}
 			fmt.Println()
 		}
 	},
 }
 
+// printListDue prints an issue's due date under it in bd list output
+func printListDue(issue *types.Issue) {
+	if issue.DueDate == nil {
+		return
+	}
+	overdue := ""
+	if issue.IsOverdue(time.Now()) {
+		overdue = color.New(color.FgRed).Sprint(" (overdue)")
+	}
+	fmt.Printf("  Due: %s%s\n", issue.DueDate.Local().Format("2006-01-02"), overdue)
+}
+
 func init() {
 	listCmd.Flags().StringP("status", "s", "", "Filter by status (open, in_progress, blocked, closed)")
 	listCmd.Flags().IntP("priority", "p", 0, "Filter by priority (0-4: 0=critical, 1=high, 2=medium, 3=low, 4=backlog)")
@@ -210,87 +276,27 @@ func init() {
 	listCmd.Flags().String("title", "", "Filter by title text (case-insensitive substring match)")
 	listCmd.Flags().String("id", "", "Filter by specific issue IDs (comma-separated, e.g., bd-1,bd-5,bd-10)")
 	listCmd.Flags().IntP("limit", "n", 0, "Limit results")
-	listCmd.Flags().String("format", "", "Output format: 'digraph' (for golang.org/x/tools/cmd/digraph), 'dot' (Graphviz), or Go template")
+	listCmd.Flags().String("format", "", "Output format: 'markdown' (a table), 'digraph' (for golang.org/x/tools/cmd/digraph), 'dot' (Graphviz), or Go template")
 	listCmd.Flags().Bool("all", false, "Show all issues (default behavior; flag provided for CLI familiarity)")
+	listCmd.Flags().Bool("overdue", false, "Only open issues whose due date has passed")
+	listCmd.Flags().String("due-before", "", "Only issues due before this date (YYYY-MM-DD, today, tomorrow, or +Nd/+Nw)")
+	listCmd.Flags().String("due-after", "", "Only issues due on or after this date")
+	listCmd.Flags().String("milestone", "", "Only issues assigned to this milestone (sprint)")
+	listCmd.Flags().StringP("query", "q", "", "Filter by a query, e.g. 'status:open AND (label:backend OR assignee:alice) AND updated:<7d'")
+	listCmd.Flags().String("sort", "", "Order by comma-separated fields, - for descending (e.g. priority,-updated_at); default priority, newest first")
+	listCmd.Flags().String("columns", "", "Print a table of these comma-separated columns (e.g. id,title,status,priority,assignee,labels,due_date)")
+	listCmd.Flags().Bool("include-archived", false, "Include issues moved to the archive by 'bd archive run'")
 	listCmd.Flags().Bool("json", false, "Output JSON format")
 	rootCmd.AddCommand(listCmd)
 }
 
 // outputDotFormat outputs issues in Graphviz DOT format
 func outputDotFormat(ctx context.Context, store storage.Storage, issues []*types.Issue) error {
-	fmt.Println("digraph dependencies {")
-	fmt.Println("  rankdir=TB;")
-	fmt.Println("  node [shape=box, style=rounded];")
-	fmt.Println()
-
-	// Build map of all issues for quick lookup
-	issueMap := make(map[string]*types.Issue)
-	for _, issue := range issues {
-		issueMap[issue.ID] = issue
-	}
-
-	// Output nodes with labels including ID, type, priority, and status
-	for _, issue := range issues {
-		// Build label with ID, type, priority, and title (using actual newlines)
-		label := fmt.Sprintf("%s\n[%s P%d]\n%s\n(%s)",
-			issue.ID,
-			issue.IssueType,
-			issue.Priority,
-			issue.Title,
-			issue.Status)
-
-		// Color by status only - keep it simple
-		fillColor := "white"
-		fontColor := "black"
-
-		switch issue.Status {
-		case "closed":
-			fillColor = "lightgray"
-			fontColor = "dimgray"
-		case "in_progress":
-			fillColor = "lightyellow"
-		case "blocked":
-			fillColor = "lightcoral"
-		}
-
-		fmt.Printf("  %q [label=%q, style=\"rounded,filled\", fillcolor=%q, fontcolor=%q];\n",
-			issue.ID, label, fillColor, fontColor)
-	}
-	fmt.Println()
-
-	// Output edges with labels for dependency type
-	for _, issue := range issues {
-		deps, err := store.GetDependencyRecords(ctx, issue.ID)
-		if err != nil {
-			continue
-		}
-		for _, dep := range deps {
-			// Only output edges where both nodes are in the filtered list
-			if issueMap[dep.DependsOnID] != nil {
-				// Color code by dependency type
-				color := "black"
-				style := "solid"
-				switch dep.Type {
-				case "blocks":
-					color = "red"
-					style = "bold"
-				case "parent-child":
-					color = "blue"
-				case "discovered-from":
-					color = "green"
-					style = "dashed"
-				case "related":
-					color = "gray"
-					style = "dashed"
-				}
-				fmt.Printf("  %q -> %q [label=%q, color=%s, style=%s];\n",
-					issue.ID, dep.DependsOnID, dep.Type, color, style)
-			}
-		}
+	g, err := depgraph.Build(ctx, store, issues)
+	if err != nil {
+		return err
 	}
-
-	fmt.Println("}")
-	return nil
+	return depgraph.WriteDOT(os.Stdout, g)
 }
 
 // outputFormattedList outputs issues in a custom format (preset or Go template)
@@ -299,6 +305,10 @@ func outputFormattedList(ctx context.Context, store storage.Storage, issues []*t
 	if formatStr == "dot" {
 		return outputDotFormat(ctx, store, issues)
 	}
+	if formatStr == "markdown" {
+		fmt.Print(markdown.IssueList(issues))
+		return nil
+	}
 
 	// Built-in format presets
 	presets := map[string]string{
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// listColumns are the columns bd list --columns can show, keyed by the
+// issue's JSON field name
+var listColumns = map[string]func(issue *types.Issue) string{
+	"id":         func(issue *types.Issue) string { return issue.ID },
+	"title":      func(issue *types.Issue) string { return issue.Title },
+	"status":     func(issue *types.Issue) string { return string(issue.Status) },
+	"priority":   func(issue *types.Issue) string { return fmt.Sprintf("P%d", issue.Priority) },
+	"issue_type": func(issue *types.Issue) string { return string(issue.IssueType) },
+	"assignee":   func(issue *types.Issue) string { return issue.Assignee },
+	"milestone":  func(issue *types.Issue) string { return issue.Milestone },
+	"labels":     func(issue *types.Issue) string { return strings.Join(issue.Labels, ",") },
+	"estimated_minutes": func(issue *types.Issue) string {
+		if issue.EstimatedMinutes == nil {
+			return ""
+		}
+		return strconv.Itoa(*issue.EstimatedMinutes)
+	},
+	"created_at": func(issue *types.Issue) string { return listColumnDate(&issue.CreatedAt) },
+	"updated_at": func(issue *types.Issue) string { return listColumnDate(&issue.UpdatedAt) },
+	"closed_at":  func(issue *types.Issue) string { return listColumnDate(issue.ClosedAt) },
+	"due_date":   func(issue *types.Issue) string { return listColumnDate(issue.DueDate) },
+	"start_date": func(issue *types.Issue) string { return listColumnDate(issue.StartDate) },
+}
+
+// listColumnOrder is the order bd list --help names the columns in
+var listColumnOrder = []string{
+	"id", "title", "status", "priority", "issue_type", "assignee", "milestone", "labels",
+	"estimated_minutes", "created_at", "updated_at", "closed_at", "due_date", "start_date",
+}
+
+// parseListColumns parses a comma-separated --columns value
+func parseListColumns(spec string) ([]string, error) {
+	var columns []string
+	for _, column := range strings.Split(spec, ",") {
+		column = strings.TrimSpace(column)
+		if column == "" {
+			continue
+		}
+		if column == "type" {
+			column = "issue_type"
+		}
+		if listColumns[column] == nil {
+			return nil, fmt.Errorf("unknown column %q (valid columns: %s)", column, strings.Join(listColumnOrder, ", "))
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// printListColumns prints issues as an aligned table of the given columns
+func printListColumns(issues []*types.Issue, columns []string) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = strings.ToUpper(column)
+	}
+	_, _ = fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, issue := range issues {
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = listColumns[column](issue)
+		}
+		_, _ = fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	_ = w.Flush()
+}
+
+func listColumnDate(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Local().Format("2006-01-02")
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/spf13/cobra"
+)
+
+var logTimeCmd = &cobra.Command{
+	Use:   "log-time <id> [duration] [note...]",
+	Short: "Log time spent on an issue",
+	Long: `Record time spent on an issue, or list the time logged on it.
+
+The duration is in minutes or a duration like 45m or 1h30m. Entries are
+logged as the current actor. Totals per issue and per assignee appear in
+'bd stats'.
+
+Examples:
+  bd log-time bd-12 45m "debugging"
+  bd log-time bd-12 90
+  bd log-time bd-12          # List the time logged on bd-12`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		if err := ensureDirectMode("daemon does not support log-time command"); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: log-time command requires SQLite backend\n")
+			os.Exit(1)
+		}
+		ctx := context.Background()
+
+		issue, err := sqliteStore.GetIssue(ctx, args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if issue == nil {
+			fmt.Fprintf(os.Stderr, "Error: issue %s not found\n", args[0])
+			os.Exit(1)
+		}
+
+		if len(args) > 1 {
+			minutes, err := parseMinutes(args[1])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			log := &sqlite.WorkLog{IssueID: issue.ID, Actor: actor, Minutes: minutes, Note: strings.Join(args[2:], " ")}
+			if err := sqliteStore.AddWorkLog(ctx, log); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if jsonOutput {
+				outputJSON(log)
+				return
+			}
+			green := color.New(color.FgGreen).SprintFunc()
+			fmt.Printf("%s Logged %s on %s\n", green("✓"), formatMinutes(minutes), issue.ID)
+		}
+
+		logs, err := sqliteStore.GetWorkLogs(ctx, issue.ID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if jsonOutput {
+			if logs == nil {
+				logs = []*sqlite.WorkLog{}
+			}
+			outputJSON(logs)
+			return
+		}
+
+		total := 0
+		for _, log := range logs {
+			total += log.Minutes
+		}
+		logged := formatMinutes(total)
+		if issue.EstimatedMinutes != nil {
+			logged += " of " + formatMinutes(*issue.EstimatedMinutes) + " estimated"
+		}
+		if len(args) > 1 {
+			fmt.Printf("  Total: %s\n", logged)
+			return
+		}
+
+		if len(logs) == 0 {
+			fmt.Printf("No time logged on %s\n", issue.ID)
+			return
+		}
+		cyan := color.New(color.FgCyan).SprintFunc()
+		fmt.Printf("%s %s: %s\n\n", cyan(issue.ID), issue.Title, logged)
+		for _, log := range logs {
+			line := fmt.Sprintf("  %s  %-8s %s", log.LoggedAt.Local().Format("2006-01-02 15:04"), formatMinutes(log.Minutes), log.Actor)
+			if log.Note != "" {
+				line += ": " + log.Note
+			}
+			fmt.Println(line)
+		}
+	},
+}
+
+func init() {
+	logTimeCmd.Flags().Bool("json", false, "Output JSON format")
+	rootCmd.AddCommand(logTimeCmd)
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -48,6 +49,7 @@ const (
 var (
 	dbPath       string
 	actor        string
+	session      string // Agent session to tag changes with (BD_SESSION)
 	store        storage.Storage
 	jsonOutput   bool
 	daemonStatus DaemonStatus // Tracks daemon connection state for current command
@@ -86,6 +88,10 @@ var rootCmd = &cobra.Command{
 		// Apply viper configuration if flags weren't explicitly set
 		// Priority: flags > viper (config file + env vars) > defaults
 		// Do this BEFORE early-return so init/version/help respect config
+		if err := config.Initialize(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to initialize config: %v\n", err)
+			// Non-fatal - continue with defaults
+		}
 
 		// If flag wasn't explicitly set, use viper value
 		if !cmd.Flags().Changed("json") {
@@ -103,15 +109,23 @@ var rootCmd = &cobra.Command{
 		if !cmd.Flags().Changed("no-db") {
 			noDb = config.GetBool("no-db")
 		}
+		if !cmd.Flags().Changed("ephemeral") {
+			ephemeral = config.GetBool("ephemeral")
+		}
 		if !cmd.Flags().Changed("db") && dbPath == "" {
 			dbPath = config.GetString("db")
 		}
+		resolveStorageDriver()
 		if !cmd.Flags().Changed("actor") && actor == "" {
 			actor = config.GetString("actor")
 		}
+		session = config.GetString("session")
+		if !cmd.Flags().Changed("remote") {
+			remoteURL = config.GetString("remote")
+		}
 
 		// Skip database initialization for commands that don't need a database
-		if cmd.Name() == "init" || cmd.Name() == cmdDaemon || cmd.Name() == "help" || cmd.Name() == "version" || cmd.Name() == "quickstart" {
+		if cmd.Name() == "init" || cmd.Name() == cmdDaemon || cmd.Name() == "help" || cmd.Name() == "version" || cmd.Name() == "quickstart" || cmd.Name() == "merge-file" {
 			return
 		}
 
@@ -128,9 +142,30 @@ var rootCmd = &cobra.Command{
 		// Set auto-import based on flag (invert no-auto-import)
 		autoImportEnabled = !noAutoImport
 
-		// Handle --no-db mode: load from JSONL, use in-memory storage
-		if noDb {
-			if err := initializeNoDbMode(); err != nil {
+		// Handle --remote: every command goes through a bd serve API
+		if remoteURL != "" {
+			if actor == "" {
+				if user := os.Getenv("USER"); user != "" {
+					actor = user
+				} else {
+					actor = "unknown"
+				}
+			}
+			if err := initializeRemoteMode(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		// Handle --no-db and --ephemeral modes: use in-memory storage
+		if noDb || ephemeral {
+			if ephemeral {
+				if err := initializeEphemeralMode(); err != nil {
+					fmt.Fprintf(os.Stderr, "Error initializing --ephemeral mode: %v\n", err)
+					os.Exit(1)
+				}
+			} else if err := initializeNoDbMode(); err != nil {
 				fmt.Fprintf(os.Stderr, "Error initializing --no-db mode: %v\n", err)
 				os.Exit(1)
 			}
@@ -206,6 +241,19 @@ var rootCmd = &cobra.Command{
 			}
 		}
 
+		// A --db URL for a registered backend other than SQLite skips the
+		// daemon and runs directly against it
+		if resolveStorageDriver() != sqlite.DriverName {
+			if err := initializeDriverMode(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if cmd.Parent() != sessionCmd {
+				applySession()
+			}
+			return
+		}
+
 		// Initialize daemon status
 		socketPath := getSocketPath()
 		daemonStatus = DaemonStatus{
@@ -217,12 +265,16 @@ var rootCmd = &cobra.Command{
 			FallbackReason:   FallbackNone,
 		}
 
-		// Try to connect to daemon first (unless --no-daemon flag is set)
+		// Try to connect to daemon first (unless --no-daemon flag is set).
+		// Session writes go direct: the daemon writes as its own actor.
 		if noDaemon {
 			daemonStatus.FallbackReason = FallbackFlagNoDaemon
 			if os.Getenv("BD_DEBUG") != "" {
 				fmt.Fprintf(os.Stderr, "Debug: --no-daemon flag set, using direct mode\n")
 			}
+		} else if session != "" {
+			daemonStatus.FallbackReason = FallbackDaemonUnsupported
+			daemonStatus.Detail = "agent sessions require direct mode"
 		} else {
 			// Attempt daemon connection
 			client, err := rpc.TryConnect(socketPath)
@@ -404,7 +456,7 @@ var rootCmd = &cobra.Command{
 
 		// Fall back to direct storage access
 		var err error
-		store, err = sqlite.New(dbPath)
+		store, err = sqlite.NewWithConfig(dbPath, sqlitePoolConfig())
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: failed to open database: %v\n", err)
 			os.Exit(1)
@@ -415,6 +467,18 @@ var rootCmd = &cobra.Command{
 		storeActive = true
 		storeMutex.Unlock()
 
+		// Write as the registered actor this one is an alias or email of
+		if sqliteStore, ok := store.(*sqlite.SQLiteStorage); ok {
+			if resolved, err := sqliteStore.ResolveActor(context.Background(), actor); err == nil && resolved != "" {
+				actor = resolved
+			}
+		}
+		// Session commands manage sessions rather than write in one, so a
+		// stale BD_SESSION doesn't block starting the next
+		if cmd.Parent() != sessionCmd {
+			applySession()
+		}
+
 		// Warn if multiple databases detected in directory hierarchy
 		warnMultipleDatabases(dbPath)
 
@@ -424,7 +488,8 @@ var rootCmd = &cobra.Command{
 		// Auto-import if JSONL is newer than DB (e.g., after git pull)
 		// Skip for import command itself to avoid recursion
 		// Skip if sync --dry-run to avoid modifying DB in dry-run mode (bd-191)
-		if cmd.Name() != "import" && autoImportEnabled {
+		// Skip for sync file, which reads the JSONL on its own terms
+		if cmd.Name() != "import" && cmd != syncFileCmd && autoImportEnabled {
 			// Check if this is sync command with --dry-run flag
 			if cmd.Name() == "sync" {
 				if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
@@ -441,6 +506,22 @@ var rootCmd = &cobra.Command{
 		}
 	},
 	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		// Handle --remote mode: the server has already saved everything
+		if remoteURL != "" {
+			if store != nil {
+				_ = store.Close()
+			}
+			return
+		}
+
+		// Handle --ephemeral mode: discard everything
+		if ephemeral {
+			if store != nil {
+				_ = store.Close()
+			}
+			return
+		}
+
 		// Handle --no-db mode: write memory storage back to JSONL
 		if noDb {
 			if store != nil {
@@ -486,6 +567,11 @@ var rootCmd = &cobra.Command{
 			flushToJSONL()
 		}
 
+		// Seal new events into the audit chain if enabled (audit.chain)
+		if store != nil {
+			autoSealAuditChain()
+		}
+
 		// Signal that store is closing (prevents background flush from accessing closed store)
 		storeMutex.Lock()
 		storeActive = false
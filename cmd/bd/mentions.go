@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var mentionsCmd = &cobra.Command{
+	Use:   "mentions [user]",
+	Short: "List comments that @mention you or another user",
+	Long: `List the comments that @mention a user, newest first. Without a user, lists
+your own: BD_ACTOR's, or else your login name's.
+
+A comment mentions @name anywhere outside markdown code. Mentioning an
+issue ID like bd-123 in a comment links the two issues as related.
+
+Examples:
+  bd mentions
+  bd mentions alice --json`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		user := actor
+		if len(args) > 0 {
+			user = args[0]
+		}
+		if err := ensureDirectMode("daemon does not support mentions command"); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		mentions, err := store.GetMentions(context.Background(), user)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting mentions: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			outputJSON(mentions)
+			return
+		}
+		if len(mentions) == 0 {
+			fmt.Printf("No comments mention @%s\n", user)
+			return
+		}
+
+		fmt.Printf("\nComments mentioning @%s:\n\n", user)
+		for _, m := range mentions {
+			fmt.Printf("%s: %s\n", m.IssueID, m.IssueTitle)
+			printComment(m.Comment, 1)
+		}
+	},
+}
+
+func init() {
+	mentionsCmd.Flags().Bool("json", false, "Output in JSON format")
+	rootCmd.AddCommand(mentionsCmd)
+}
@@ -0,0 +1,311 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+	"github.com/spf13/cobra"
+)
+
+var mergeFileCmd = &cobra.Command{
+	Use:   "merge-file <base> <ours> <theirs>",
+	Short: "Three-way merge of issues.jsonl files, for use as a git merge driver",
+	Long: `Merge two versions of an issues JSONL file that branched from a common base,
+issue by issue and field by field, and write the result over <ours>.
+
+For each issue:
+  - A field changed on one side takes that side's value. A field both sides
+    changed differently takes the value from the side updated last.
+  - Labels and dependencies are merged as sets: additions from either side
+    are kept, and ones either side removed stay removed.
+  - Comments from both sides are kept.
+  - An issue added on either side is kept. An issue deleted on one side is
+    deleted, unless the other side changed it, in which case it is kept.
+
+The result is written in the canonical form of 'bd sync file'. Fields resolved
+by update time and deletions that were overridden are reported on stderr.
+It exits non-zero, leaving the conflict to git, only when an input can't be
+read, e.g. because it already contains conflict markers.
+
+To have git use it for the issues file:
+
+  git config merge.beads.name "bd JSONL merge"
+  git config merge.beads.driver "bd merge-file %O %A %B"
+  echo ".beads/issues.jsonl merge=beads" >> .gitattributes
+
+Examples:
+  bd merge-file base.jsonl ours.jsonl theirs.jsonl
+  bd merge-file base.jsonl ours.jsonl theirs.jsonl -o merged.jsonl`,
+	Args: cobra.ExactArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		if output == "" {
+			output = args[1]
+		}
+
+		var sides [3]map[string][]byte
+		for i, path := range args {
+			_, lines, _, err := readJSONLFile(path)
+			// Git passes an empty base when the sides have no common ancestor
+			if err != nil && !(i == 0 && os.IsNotExist(err)) {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			sides[i] = lines
+		}
+
+		issues, notes, err := mergeJSONLIssues(sides[0], sides[1], sides[2])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		for _, note := range notes {
+			fmt.Fprintf(os.Stderr, "merge-file: %s\n", note)
+		}
+
+		var buf bytes.Buffer
+		for _, issue := range issues {
+			line, err := json.Marshal(issue)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: failed to encode issue %s: %v\n", issue.ID, err)
+				os.Exit(1)
+			}
+			buf.Write(line)
+			buf.WriteByte('\n')
+		}
+		if output == "-" {
+			_, _ = os.Stdout.Write(buf.Bytes())
+			return
+		}
+		if err := writeFileAtomic(output, buf.Bytes()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// issueFields is an issue as its canonical JSON fields, so fields can be
+// compared and merged without knowing the Issue struct
+type issueFields map[string]json.RawMessage
+
+// canonicalFields decodes an exported issue line into canonical fields
+func canonicalFields(line []byte) (issueFields, error) {
+	if line == nil {
+		return nil, nil
+	}
+	var issue types.Issue
+	if err := json.Unmarshal(line, &issue); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(canonicalIssue(&issue))
+	if err != nil {
+		return nil, err
+	}
+	var fields issueFields
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func (f issueFields) equal(other issueFields) bool {
+	if len(f) != len(other) {
+		return false
+	}
+	for k, v := range f {
+		if !bytes.Equal(v, other[k]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f issueFields) updatedAt() time.Time {
+	var t time.Time
+	_ = json.Unmarshal(f["updated_at"], &t)
+	return t
+}
+
+// mergeJSONLIssues three-way merges issue lines keyed by ID, returning the
+// merged issues sorted by ID and notes on decisions worth a look
+func mergeJSONLIssues(base, ours, theirs map[string][]byte) ([]*types.Issue, []string, error) {
+	ids := make(map[string]bool)
+	for _, side := range []map[string][]byte{base, ours, theirs} {
+		for id := range side {
+			ids[id] = true
+		}
+	}
+	sorted := make([]string, 0, len(ids))
+	for id := range ids {
+		sorted = append(sorted, id)
+	}
+	sort.Strings(sorted)
+
+	var merged []*types.Issue
+	var notes []string
+	for _, id := range sorted {
+		b, err := canonicalFields(base[id])
+		if err != nil {
+			return nil, nil, fmt.Errorf("base %s: %w", id, err)
+		}
+		o, err := canonicalFields(ours[id])
+		if err != nil {
+			return nil, nil, fmt.Errorf("ours %s: %w", id, err)
+		}
+		t, err := canonicalFields(theirs[id])
+		if err != nil {
+			return nil, nil, fmt.Errorf("theirs %s: %w", id, err)
+		}
+
+		var result issueFields
+		switch {
+		case o == nil && t == nil:
+			continue
+		case o == nil || t == nil:
+			kept, deletedBy := t, "ours"
+			if t == nil {
+				kept, deletedBy = o, "theirs"
+			}
+			if b != nil && kept.equal(b) {
+				continue // deleted on one side, untouched on the other
+			}
+			if b != nil {
+				notes = append(notes, fmt.Sprintf("%s: kept, since it was deleted in %s but changed in the other", id, deletedBy))
+			}
+			result = kept
+		default:
+			var resolved []string
+			result, resolved = mergeIssueFields(b, o, t)
+			for _, field := range resolved {
+				notes = append(notes, fmt.Sprintf("%s: both sides changed %s; kept the later update", id, field))
+			}
+		}
+
+		data, err := json.Marshal(result)
+		if err != nil {
+			return nil, nil, err
+		}
+		var issue types.Issue
+		if err := json.Unmarshal(data, &issue); err != nil {
+			return nil, nil, fmt.Errorf("merged %s: %w", id, err)
+		}
+		// closed_at follows the merged status
+		if issue.Status != types.StatusClosed {
+			issue.ClosedAt = nil
+		} else if issue.ClosedAt == nil {
+			issue.ClosedAt = &issue.UpdatedAt
+		}
+		merged = append(merged, canonicalIssue(&issue))
+	}
+	return merged, notes, nil
+}
+
+// mergeIssueFields merges one issue's fields. base is nil when both sides
+// added the issue independently. It returns the fields that both sides
+// changed, which take the value from the side updated last.
+func mergeIssueFields(base, ours, theirs issueFields) (issueFields, []string) {
+	newer := ours
+	if theirs.updatedAt().After(ours.updatedAt()) {
+		newer = theirs
+	}
+
+	keys := make(map[string]bool)
+	for _, f := range []issueFields{base, ours, theirs} {
+		for k := range f {
+			keys[k] = true
+		}
+	}
+
+	result := make(issueFields)
+	var resolved []string
+	for k := range keys {
+		b, o, t := base[k], ours[k], theirs[k]
+		var v json.RawMessage
+		switch k {
+		case "updated_at":
+			v = newer[k]
+		case "labels":
+			v = mergeSets(b, o, t, func(raw json.RawMessage) string { return string(raw) })
+		case "dependencies":
+			v = mergeSets(b, o, t, func(raw json.RawMessage) string {
+				var dep types.Dependency
+				_ = json.Unmarshal(raw, &dep)
+				return dep.DependsOnID + "|" + string(dep.Type)
+			})
+		case "comments":
+			// Comments are only ever added, so every comment from either side stays
+			v = mergeSets(nil, o, t, func(raw json.RawMessage) string {
+				var c types.Comment
+				_ = json.Unmarshal(raw, &c)
+				return c.Author + "|" + c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.Text
+			})
+		default:
+			switch {
+			case bytes.Equal(o, t):
+				v = o
+			case bytes.Equal(o, b):
+				v = t
+			case bytes.Equal(t, b):
+				v = o
+			default:
+				v = newer[k]
+				resolved = append(resolved, k)
+			}
+		}
+		if v != nil {
+			result[k] = v
+		}
+	}
+	sort.Strings(resolved)
+	return result, resolved
+}
+
+// mergeSets three-way merges JSON arrays as sets keyed by key: elements
+// either side added are kept, and elements of base either side removed are
+// dropped. Elements in both sides keep ours.
+func mergeSets(base, ours, theirs json.RawMessage, key func(json.RawMessage) string) json.RawMessage {
+	decode := func(raw json.RawMessage) ([]json.RawMessage, map[string]bool) {
+		var elems []json.RawMessage
+		_ = json.Unmarshal(raw, &elems)
+		keys := make(map[string]bool, len(elems))
+		for _, e := range elems {
+			keys[key(e)] = true
+		}
+		return elems, keys
+	}
+	_, inBase := decode(base)
+	oursElems, _ := decode(ours)
+	theirsElems, inTheirs := decode(theirs)
+
+	var merged []json.RawMessage
+	seen := make(map[string]bool)
+	for _, e := range oursElems {
+		k := key(e)
+		if !seen[k] && (inTheirs[k] || !inBase[k]) {
+			seen[k] = true
+			merged = append(merged, e)
+		}
+	}
+	for _, e := range theirsElems {
+		k := key(e)
+		if !seen[k] && !inBase[k] {
+			seen[k] = true
+			merged = append(merged, e)
+		}
+	}
+	if len(merged) == 0 {
+		return nil
+	}
+	data, _ := json.Marshal(merged)
+	return data
+}
+
+func init() {
+	mergeFileCmd.Flags().StringP("output", "o", "", "Write the result here instead of over <ours> (- for stdout)")
+	rootCmd.AddCommand(mergeFileCmd)
+}
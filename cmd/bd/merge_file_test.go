@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestMergeJSONLIssues(t *testing.T) {
+	lines := func(issues ...string) map[string][]byte {
+		m := make(map[string][]byte)
+		for _, line := range issues {
+			var issue types.Issue
+			if err := json.Unmarshal([]byte(line), &issue); err != nil {
+				t.Fatal(err)
+			}
+			m[issue.ID] = []byte(line)
+		}
+		return m
+	}
+	const (
+		one   = `{"id":"bd-1","title":"One","status":"open","priority":2,"issue_type":"task","created_at":"2026-01-01T00:00:00Z","updated_at":"2026-01-01T00:00:00Z","labels":["a","b"],"dependencies":[{"issue_id":"bd-1","depends_on_id":"bd-2","type":"blocks","created_at":"2026-01-01T00:00:00Z","created_by":"x"}]}`
+		two   = `{"id":"bd-2","title":"Two","status":"open","priority":2,"issue_type":"task","created_at":"2026-01-01T00:00:00Z","updated_at":"2026-01-01T00:00:00Z"}`
+		three = `{"id":"bd-3","title":"Three","status":"open","priority":2,"issue_type":"task","created_at":"2026-01-01T00:00:00Z","updated_at":"2026-01-01T00:00:00Z"}`
+	)
+	base := lines(one, two, three)
+	ours := lines(
+		`{"id":"bd-1","title":"One (ours)","status":"closed","priority":1,"issue_type":"task","created_at":"2026-01-01T00:00:00Z","updated_at":"2026-01-02T00:00:00Z","closed_at":"2026-01-02T00:00:00Z","labels":["a","b","c"],"dependencies":[{"issue_id":"bd-1","depends_on_id":"bd-2","type":"blocks","created_at":"2026-01-01T00:00:00Z","created_by":"x"}],"comments":[{"id":7,"issue_id":"bd-1","author":"me","text":"mine","created_at":"2026-01-02T00:00:00Z"}]}`,
+		three,
+	)
+	theirs := lines(
+		`{"id":"bd-1","title":"One (theirs)","description":"More","status":"open","priority":2,"issue_type":"task","created_at":"2026-01-01T00:00:00Z","updated_at":"2026-01-03T00:00:00Z","labels":["b","d"],"comments":[{"id":2,"issue_id":"bd-1","author":"you","text":"yours","created_at":"2026-01-03T00:00:00Z"}]}`,
+		two,
+		`{"id":"bd-3","title":"Three (theirs)","status":"open","priority":2,"issue_type":"task","created_at":"2026-01-01T00:00:00Z","updated_at":"2026-01-03T00:00:00Z"}`,
+		`{"id":"bd-4","title":"Four","status":"open","priority":2,"issue_type":"task","created_at":"2026-01-03T00:00:00Z","updated_at":"2026-01-03T00:00:00Z"}`,
+	)
+
+	merged, notes, err := mergeJSONLIssues(base, ours, theirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, issue := range merged {
+		ids = append(ids, issue.ID)
+	}
+	// bd-2 was deleted in ours and untouched in theirs
+	if want := []string{"bd-1", "bd-3", "bd-4"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("Merged IDs = %v, want %v", ids, want)
+	}
+
+	got := merged[0]
+	if got.Title != "One (theirs)" || got.Description != "More" {
+		t.Errorf("Expected theirs' later title and its description, got %q, %q", got.Title, got.Description)
+	}
+	if got.Status != types.StatusClosed || got.Priority != 1 || got.ClosedAt == nil {
+		t.Errorf("Expected ours' status, closed_at and priority, got %s, %v, P%d", got.Status, got.ClosedAt, got.Priority)
+	}
+	if want := []string{"b", "c", "d"}; !reflect.DeepEqual(got.Labels, want) {
+		t.Errorf("Labels = %v, want %v", got.Labels, want)
+	}
+	if len(got.Dependencies) != 0 {
+		t.Errorf("Expected the dependency theirs removed to stay removed, got %v", got.Dependencies)
+	}
+	if len(got.Comments) != 2 || got.Comments[0].Text != "mine" || got.Comments[1].Text != "yours" {
+		t.Errorf("Expected comments from both sides, got %v", got.Comments)
+	}
+	if got.UpdatedAt.Day() != 3 {
+		t.Errorf("Expected the later updated_at, got %v", got.UpdatedAt)
+	}
+	if merged[1].Title != "Three (theirs)" {
+		t.Errorf("Expected theirs' change to bd-3, got %q", merged[1].Title)
+	}
+	if want := []string{"bd-1: both sides changed title; kept the later update"}; !reflect.DeepEqual(notes, want) {
+		t.Errorf("Notes = %q, want %q", notes, want)
+	}
+
+	// A change on one side outlives a deletion on the other
+	merged, notes, err = mergeJSONLIssues(base, lines(one, two), theirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(merged) != 4 || len(notes) != 1 {
+		t.Errorf("Expected bd-3 kept with a note, got %d issues and %q", len(merged), notes)
+	}
+}
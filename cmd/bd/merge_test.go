@@ -232,13 +232,13 @@ func TestPerformMergeIdempotent(t *testing.T) {
 		t.Fatalf("First merge failed: %v", err)
 	}
 
-	if result1.issuesClosed != 2 {
-		t.Errorf("First merge: expected 2 issues closed, got %d", result1.issuesClosed)
+	if result1.IssuesClosed != 2 {
+		t.Errorf("First merge: expected 2 issues closed, got %d", result1.IssuesClosed)
 	}
-	if result1.issuesSkipped != 0 {
-		t.Errorf("First merge: expected 0 issues skipped, got %d", result1.issuesSkipped)
+	if result1.IssuesSkipped != 0 {
+		t.Errorf("First merge: expected 0 issues skipped, got %d", result1.IssuesSkipped)
 	}
-	if result1.depsAdded == 0 {
+	if result1.DependenciesAdded == 0 {
 		t.Errorf("First merge: expected some dependencies added, got 0")
 	}
 
@@ -259,16 +259,16 @@ func TestPerformMergeIdempotent(t *testing.T) {
 	}
 
 	// All operations should be skipped
-	if result2.issuesClosed != 0 {
-		t.Errorf("Second merge: expected 0 issues closed, got %d", result2.issuesClosed)
+	if result2.IssuesClosed != 0 {
+		t.Errorf("Second merge: expected 0 issues closed, got %d", result2.IssuesClosed)
 	}
-	if result2.issuesSkipped != 2 {
-		t.Errorf("Second merge: expected 2 issues skipped, got %d", result2.issuesSkipped)
+	if result2.IssuesSkipped != 2 {
+		t.Errorf("Second merge: expected 2 issues skipped, got %d", result2.IssuesSkipped)
 	}
 
 	// Dependencies should be skipped (already exist)
-	if result2.depsAdded != 0 {
-		t.Errorf("Second merge: expected 0 dependencies added, got %d", result2.depsAdded)
+	if result2.DependenciesAdded != 0 {
+		t.Errorf("Second merge: expected 0 dependencies added, got %d", result2.DependenciesAdded)
 	}
 
 	// Text references are naturally idempotent - count may vary
@@ -328,11 +328,11 @@ func TestPerformMergePartialRetry(t *testing.T) {
 	}
 
 	// Should skip the already-closed issue and close the other
-	if result.issuesClosed != 1 {
-		t.Errorf("Expected 1 issue closed, got %d", result.issuesClosed)
+	if result.IssuesClosed != 1 {
+		t.Errorf("Expected 1 issue closed, got %d", result.IssuesClosed)
 	}
-	if result.issuesSkipped != 1 {
-		t.Errorf("Expected 1 issue skipped, got %d", result.issuesSkipped)
+	if result.IssuesSkipped != 1 {
+		t.Errorf("Expected 1 issue skipped, got %d", result.IssuesSkipped)
 	}
 
 	// Verify both are now closed
@@ -25,7 +25,10 @@ This command:
 - Checks schema versions
 - Migrates old databases to beads.db
 - Updates schema version metadata
-- Removes stale databases (with confirmation)`,
+- Removes stale databases (with confirmation)
+
+Schema migrations within a database are applied automatically when it is
+opened; see 'bd migrate status', 'bd migrate up', and 'bd migrate down'.`,
 	Run: func(cmd *cobra.Command, _ []string) {
 		autoYes, _ := cmd.Flags().GetBool("yes")
 		cleanup, _ := cmd.Flags().GetBool("cleanup")
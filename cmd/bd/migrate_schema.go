@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/spf13/cobra"
+)
+
+var migrateStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the database's schema migrations",
+	Long: `Show every schema migration this bd knows, and when each was applied to the
+database. Opening the database applies pending migrations, so they are only
+pending after 'bd migrate down'. Migrations applied by a newer bd are listed
+too; this bd leaves them alone.`,
+	Run: func(_ *cobra.Command, _ []string) {
+		sqliteStore := requireMigrateStore()
+		statuses, err := sqliteStore.Migrations(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		current := 0
+		for _, s := range statuses {
+			if s.AppliedAt != nil && s.Version > current {
+				current = s.Version
+			}
+		}
+
+		if jsonOutput {
+			outputJSON(map[string]interface{}{
+				"current":    current,
+				"latest":     sqlite.LatestMigration(),
+				"migrations": statuses,
+			})
+			return
+		}
+
+		fmt.Printf("Schema version %d (this bd knows up to %d)\n\n", current, sqlite.LatestMigration())
+		green := color.New(color.FgGreen).SprintFunc()
+		yellow := color.New(color.FgYellow).SprintFunc()
+		for _, s := range statuses {
+			name := fmt.Sprintf("%04d_%s", s.Version, s.Name)
+			switch {
+			case s.Unknown:
+				fmt.Printf("  %s %-32s applied %s by a newer bd\n", yellow("?"), name, s.AppliedAt.Local().Format("2006-01-02 15:04:05"))
+			case s.AppliedAt != nil:
+				fmt.Printf("  %s %-32s applied %s\n", green("✓"), name, s.AppliedAt.Local().Format("2006-01-02 15:04:05"))
+			default:
+				fmt.Printf("  %s %-32s pending\n", yellow("○"), name)
+			}
+		}
+	},
+}
+
+var migrateUpCmd = &cobra.Command{
+	Use:   "up",
+	Short: "Apply pending schema migrations",
+	Long: `Apply pending schema migrations, oldest first, up to --to or all of them.
+
+Opening the database already applies them all, so this is only needed to
+step back up after 'bd migrate down'.`,
+	Run: func(cmd *cobra.Command, _ []string) {
+		to, _ := cmd.Flags().GetInt("to")
+		sqliteStore := requireMigrateStore()
+		applied, err := sqliteStore.MigrateUp(context.Background(), to)
+		reportMigrations("Applied", applied, err)
+	},
+}
+
+var migrateDownCmd = &cobra.Command{
+	Use:   "down",
+	Short: "Roll back schema migrations",
+	Long: `Roll back the schema migrations newer than --to, newest first, so an older bd
+can use the database. Rolling back can drop tables and the data in them; take
+a backup first with 'bd backup create'.
+
+Any bd that knows the migrations applies them again when it opens the
+database, so run the older bd next. The daemon and any 'bd serve' using the
+database must be stopped first.
+
+Examples:
+  bd migrate down --to 1    # the schema from before versioned migrations`,
+	Run: func(cmd *cobra.Command, _ []string) {
+		to, _ := cmd.Flags().GetInt("to")
+		if !cmd.Flags().Changed("to") {
+			fmt.Fprintf(os.Stderr, "Error: --to is required (see 'bd migrate status' for versions)\n")
+			os.Exit(1)
+		}
+		sqliteStore := requireMigrateStore()
+		if running, pid := isDaemonRunning(filepath.Join(filepath.Dir(sqliteStore.Path()), "daemon.pid")); running {
+			fmt.Fprintf(os.Stderr, "Error: daemon (PID %d) has the database open; stop it first with 'bd daemon --stop'\n", pid)
+			os.Exit(1)
+		}
+		undone, err := sqliteStore.MigrateDown(context.Background(), to)
+		reportMigrations("Rolled back", undone, err)
+	},
+}
+
+func requireMigrateStore() *sqlite.SQLiteStorage {
+	if err := ensureDirectMode("daemon does not support migrate commands"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: migrate requires SQLite backend\n")
+		os.Exit(1)
+	}
+	return sqliteStore
+}
+
+// reportMigrations prints the versions migrate up or down got through, and
+// the error it stopped at if any
+func reportMigrations(verb string, versions []int, err error) {
+	if jsonOutput {
+		if versions == nil {
+			versions = []int{}
+		}
+		result := map[string]interface{}{"versions": versions}
+		if err != nil {
+			result["error"] = err.Error()
+		}
+		outputJSON(result)
+	} else {
+		if len(versions) == 0 && err == nil {
+			fmt.Println("Nothing to do")
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		for _, v := range versions {
+			fmt.Printf("%s %s migration %d\n", green("✓"), verb, v)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+	}
+	if err != nil {
+		os.Exit(1)
+	}
+}
+
+func init() {
+	migrateUpCmd.Flags().Int("to", 0, "Stop after this version (default: apply all)")
+	migrateDownCmd.Flags().Int("to", 0, "Roll back to this version")
+	migrateCmd.AddCommand(migrateStatusCmd)
+	migrateCmd.AddCommand(migrateUpCmd)
+	migrateCmd.AddCommand(migrateDownCmd)
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+	"github.com/spf13/cobra"
+)
+
+var prefixCmd = &cobra.Command{
+	Use:   "prefix",
+	Short: "Show or change the issue ID prefix",
+	Long: `Show the prefix new issue IDs get (the issue_prefix config value), or
+change it.
+
+'bd prefix set' on its own only changes the prefix of issues created from now
+on. With --rewrite it also renames every existing issue in one transaction:
+IDs, dependencies, labels, comments, and history all move to the new prefix,
+and references to renamed issues in titles, descriptions, design notes,
+acceptance criteria, notes, and comments are rewritten. If any step fails
+nothing changes. Old IDs are kept as redirects, so 'bd serve' answers
+GET /issues/{old-id} with a redirect to the new ID.
+
+Examples:
+  bd prefix
+  bd prefix set kw
+  bd prefix set kw --rewrite --dry-run
+  bd prefix set kw --rewrite`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		prefix, err := requirePrefixStore().GetConfig(context.Background(), "issue_prefix")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if jsonOutput {
+			outputJSON(map[string]string{"prefix": prefix})
+			return
+		}
+		fmt.Println(prefix)
+	},
+}
+
+var prefixSetCmd = &cobra.Command{
+	Use:   "set <new-prefix>",
+	Short: "Change the issue ID prefix",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		rewrite, _ := cmd.Flags().GetBool("rewrite")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		ctx := context.Background()
+		st := requirePrefixStore()
+
+		if err := validatePrefix(args[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		newPrefix := strings.TrimRight(args[0], "-")
+		oldPrefix, err := st.GetConfig(ctx, "issue_prefix")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to get current prefix: %v\n", err)
+			os.Exit(1)
+		}
+		if oldPrefix == newPrefix {
+			fmt.Fprintf(os.Stderr, "Error: prefix is already %s\n", oldPrefix)
+			os.Exit(1)
+		}
+
+		issues, err := st.SearchIssues(ctx, "", types.IssueFilter{})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to list issues: %v\n", err)
+			os.Exit(1)
+		}
+		var affected []string
+		for _, issue := range issues {
+			if strings.HasPrefix(issue.ID, oldPrefix+"-") {
+				affected = append(affected, issue.ID)
+			}
+		}
+		sort.Strings(affected)
+
+		cyan := color.New(color.FgCyan).SprintFunc()
+		green := color.New(color.FgGreen).SprintFunc()
+		yellow := color.New(color.FgYellow).SprintFunc()
+
+		if !rewrite {
+			if dryRun {
+				fmt.Printf("DRY RUN: Would change the prefix from %s to %s\n", cyan(oldPrefix), cyan(newPrefix))
+				return
+			}
+			if err := st.SetConfig(ctx, "issue_prefix", newPrefix); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: failed to update prefix: %v\n", err)
+				os.Exit(1)
+			}
+			if jsonOutput {
+				outputJSON(map[string]interface{}{"old_prefix": oldPrefix, "new_prefix": newPrefix, "renamed": map[string]string{}})
+				return
+			}
+			fmt.Printf("%s New issues will use prefix %s\n", green("✓"), cyan(newPrefix))
+			if len(affected) > 0 {
+				fmt.Printf("%s %d existing issues keep prefix %s; use --rewrite to rename them\n", yellow("!"), len(affected), oldPrefix)
+			}
+			return
+		}
+
+		if dryRun {
+			fmt.Printf("DRY RUN: Would rename %d issues from %s to %s\n", len(affected), cyan(oldPrefix), cyan(newPrefix))
+			for i, id := range affected {
+				if i >= 5 {
+					fmt.Printf("  ... and %d more\n", len(affected)-5)
+					break
+				}
+				fmt.Printf("  %s -> %s\n", id, newPrefix+"-"+strings.TrimPrefix(id, oldPrefix+"-"))
+			}
+			return
+		}
+
+		renames, err := st.RenamePrefix(ctx, oldPrefix, newPrefix, actor)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to rename prefix: %v\n", err)
+			os.Exit(1)
+		}
+
+		// IDs changed, so an incremental export won't do
+		markDirtyAndScheduleFullExport()
+
+		if jsonOutput {
+			outputJSON(map[string]interface{}{"old_prefix": oldPrefix, "new_prefix": newPrefix, "renamed": renames})
+			return
+		}
+		fmt.Printf("%s Renamed %d issues from %s to %s\n", green("✓"), len(renames), cyan(oldPrefix), cyan(newPrefix))
+		if len(renames) > 0 {
+			fmt.Println("Old IDs redirect to the new ones in 'bd serve'.")
+		}
+	},
+}
+
+func requirePrefixStore() *sqlite.SQLiteStorage {
+	if err := ensureDirectMode("daemon does not support prefix command"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: prefix command requires SQLite backend\n")
+		os.Exit(1)
+	}
+	return sqliteStore
+}
+
+func init() {
+	prefixSetCmd.Flags().Bool("rewrite", false, "Rename existing issues and rewrite references to them")
+	prefixSetCmd.Flags().Bool("dry-run", false, "Preview changes without applying them")
+	prefixSetCmd.Flags().Bool("json", false, "Output JSON format")
+	prefixCmd.Flags().Bool("json", false, "Output JSON format")
+
+	prefixCmd.AddCommand(prefixSetCmd)
+	rootCmd.AddCommand(prefixCmd)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/imalsogreg/beads/internal/anonymize"
+	"github.com/imalsogreg/beads/internal/publish"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/spf13/cobra"
+)
+
+var publishCmd = &cobra.Command{
+	Use:   "publish",
+	Short: "Render issues to a static HTML site",
+	Long: `Render the current issues, epics, and dependency graph to a static HTML site.
+
+The site needs no server or JavaScript, so it can be published to GitHub Pages
+or any static host as a read-only project status page. It contains:
+  index.html       in-progress, ready, blocked, open, and closed issues
+  epics.html       epic progress with child issues
+  graph.html       blocking dependencies drawn as an SVG graph
+  issues/<id>.html one page per issue with relationships and comments
+
+Re-running overwrites the generated files and removes pages for issues that no
+longer exist. Other files in the output directory are left alone.
+
+If field encryption is enabled, descriptions, notes, and comments are left
+out of the site. Use --anonymize to publish with pseudonymized actors and
+emails/URLs stripped (see 'bd export --help').
+
+Examples:
+  bd publish --out ./site
+  bd publish --out ./docs --title "Project status"
+  bd publish --out ./site --anonymize`,
+	Run: func(cmd *cobra.Command, _ []string) {
+		outDir, _ := cmd.Flags().GetString("out")
+		title, _ := cmd.Flags().GetString("title")
+		anonymizeOutput, _ := cmd.Flags().GetBool("anonymize")
+		salt, _ := cmd.Flags().GetString("salt")
+
+		if err := ensureDirectMode("daemon does not support publish command"); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		ctx := context.Background()
+
+		site, err := publish.Load(ctx, store)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if title == "" {
+			prefix, _ := store.GetConfig(ctx, "issue_prefix")
+			title = "Issues"
+			if prefix != "" {
+				title = prefix + " issues"
+			}
+		}
+		site.Title = title
+
+		if sqliteStore, ok := store.(*sqlite.SQLiteStorage); ok {
+			status, err := sqliteStore.GetFieldEncryptionStatus(ctx)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if status.Enabled {
+				site.Redact()
+			}
+		}
+		if anonymizeOutput {
+			site.Issues = anonymize.New(salt).Issues(site.Issues)
+		}
+
+		written, err := site.Write(outDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			outputJSON(map[string]interface{}{
+				"out":      outDir,
+				"issues":   len(site.Issues),
+				"files":    written,
+				"redacted": site.Redacted,
+			})
+			return
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Published %d issue(s) to %s (%d files)\n", green("✓"), len(site.Issues), outDir, written)
+		if site.Redacted {
+			fmt.Println("  Field encryption is enabled: descriptions, notes, and comments were left out")
+		}
+	},
+}
+
+func init() {
+	publishCmd.Flags().StringP("out", "o", "site", "Output directory")
+	publishCmd.Flags().String("title", "", "Site title (default: \"<prefix> issues\")")
+	publishCmd.Flags().Bool("anonymize", false, "Replace actors with pseudonyms and strip emails/URLs from text")
+	publishCmd.Flags().String("salt", "", "Key for anonymized pseudonyms (with --anonymize)")
+	rootCmd.AddCommand(publishCmd)
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/imalsogreg/beads/internal/signing"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/spf13/cobra"
+)
+
+var purgeActorCmd = &cobra.Command{
+	Use:   "purge-actor <name>",
+	Short: "Erase an actor's personal data (GDPR erasure)",
+	Long: `Anonymize or remove all personal data for an actor while preserving issue content.
+
+Affected data:
+  - assignee fields and claims (pseudonymized, or cleared with --mode remove)
+  - comment authorship (pseudonymized, or comments deleted with --mode remove)
+  - dependency and event actors, including names embedded in event history
+  - revision history (bd history): who made each change, and assignees changed
+  - the replication log sent by 'bd sync remote': who made each change, and
+    names in the changed fields
+  - authors of linked git commits
+  - who created labels, milestones, webhooks, API keys, and triage rules,
+    and triage rules' assignees
+  - who deleted issues in the trash, and who archived issues
+  - time logged with bd log-time (kept, under the pseudonym)
+  - compaction snapshots and secret redaction reports
+  - watches and email digest subscriptions (deleted in either mode)
+  - the actor's registration, email, and aliases (deleted in either mode)
+
+The actor is replaced with a stable pseudonym (deleted-user-<hash>) unless
+--replacement is given. If the audit chain is in use it is rebuilt over the
+rewritten history.
+
+Every purge stores a deletion report that identifies the actor only by a hash
+of their name. The report is signed with GPG when audit.sign is set to gpg
+(see 'bd audit --help'). Run 'bd export' afterwards; earlier JSONL revisions
+in git history are not rewritten.
+
+Without --force, shows a preview of what would change.
+
+Examples:
+  bd purge-actor alice                     # Preview
+  bd purge-actor alice --force             # Anonymize
+  bd purge-actor alice --mode remove --force
+  bd purge-actor --reports                 # List deletion reports`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		mode, _ := cmd.Flags().GetString("mode")
+		replacement, _ := cmd.Flags().GetString("replacement")
+		force, _ := cmd.Flags().GetBool("force")
+		showReports, _ := cmd.Flags().GetBool("reports")
+
+		if err := ensureDirectMode("daemon does not support purge-actor command"); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: purge-actor command requires SQLite backend\n")
+			os.Exit(1)
+		}
+		ctx := context.Background()
+
+		if showReports {
+			listPurgeReports(ctx, sqliteStore)
+			return
+		}
+		if len(args) != 1 {
+			fmt.Fprintf(os.Stderr, "Error: actor name is required\n")
+			os.Exit(1)
+		}
+
+		report, err := sqliteStore.PurgeActor(ctx, args[0], sqlite.PurgeActorOptions{
+			Mode:        mode,
+			Replacement: replacement,
+			PurgedBy:    actor,
+			DryRun:      !force,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if force {
+			if err := signPurgeReport(ctx, sqliteStore, report); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: deletion report is unsigned: %v\n", err)
+			}
+			markDirtyAndScheduleFullExport()
+		}
+
+		if jsonOutput {
+			outputJSON(report)
+			return
+		}
+
+		if !force {
+			yellow := color.New(color.FgYellow).SprintFunc()
+			fmt.Printf("\n%s\n\n", yellow("⚠️  PURGE PREVIEW"))
+		} else {
+			green := color.New(color.FgGreen).SprintFunc()
+			fmt.Printf("%s Purged actor data (report #%d)\n", green("✓"), report.ID)
+		}
+		printPurgeReport(report)
+		if !force {
+			fmt.Printf("\nTo proceed, run: bd purge-actor %s --force\n", args[0])
+		}
+	},
+}
+
+// signPurgeReport signs a deletion report using the audit.sign configuration
+func signPurgeReport(ctx context.Context, s *sqlite.SQLiteStorage, report *sqlite.PurgeReport) error {
+	method, _ := s.GetConfig(ctx, "audit.sign")
+	if method == "" {
+		return fmt.Errorf("audit.sign is not configured")
+	}
+	gpgKey, _ := s.GetConfig(ctx, "audit.gpg_key")
+	signature, signer, err := signing.Sign(method, gpgKey, report.SigningPayload())
+	if err != nil {
+		return err
+	}
+	report.Method, report.Signer, report.Signature = method, signer, signature
+	return s.SignPurgeReport(ctx, report)
+}
+
+func printPurgeReport(r *sqlite.PurgeReport) {
+	fmt.Printf("  Subject:      %s\n", r.SubjectHash)
+	fmt.Printf("  Replacement:  %s (%s)\n", r.Replacement, r.Mode)
+	fmt.Printf("  Assignments:  %d\n", r.Assignments)
+	fmt.Printf("  Comments:     %d anonymized, %d deleted\n", r.Comments, r.CommentsDeleted)
+	fmt.Printf("  Events:       %d actors, %d payloads, %d comment events deleted\n", r.Events, r.EventPayloads, r.CommentEventsDeleted)
+	fmt.Printf("  Dependencies: %d\n", r.Dependencies)
+	fmt.Printf("  Snapshots:    %d\n", r.Snapshots)
+	fmt.Printf("  Redactions:   %d\n", r.Redactions)
+	fmt.Printf("  History:      %d revisions\n", r.History)
+	fmt.Printf("  Commits:      %d\n", r.Commits)
+	fmt.Printf("  Deletions:    %d\n", r.Deletions)
+	fmt.Printf("  Archivals:    %d\n", r.Archivals)
+	fmt.Printf("  Work logs:    %d\n", r.WorkLogs)
+	fmt.Printf("  Leases:       %d\n", r.Leases)
+	fmt.Printf("  Oplog:        %d ops\n", r.Oplog)
+	fmt.Printf("  Records:      %d (labels, milestones, webhooks, keys, triage rules)\n", r.Records)
+	fmt.Printf("  Registry:     %d (registration and aliases)\n", r.Registry)
+	fmt.Printf("  Issues:       %d affected\n", len(r.IssuesAffected))
+	if r.AuditChainRebuilt {
+		fmt.Printf("  Audit chain:  rebuilt %s → %s (%d signature(s) removed)\n",
+			shortHash(r.AuditHeadBefore), shortHash(r.AuditHeadAfter), r.AuditSignaturesRemoved)
+	}
+	if r.Signature != "" {
+		fmt.Printf("  Signed:       %s", r.Method)
+		if r.Signer != "" {
+			fmt.Printf(" (%s)", r.Signer)
+		}
+		fmt.Println()
+	}
+}
+
+func listPurgeReports(ctx context.Context, s *sqlite.SQLiteStorage) {
+	reports, err := s.GetPurgeReports(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if jsonOutput {
+		if reports == nil {
+			reports = []*sqlite.PurgeReport{}
+		}
+		outputJSON(reports)
+		return
+	}
+	if len(reports) == 0 {
+		fmt.Println("No deletion reports")
+		return
+	}
+	for _, r := range reports {
+		signed := "unsigned"
+		if r.Signature != "" {
+			signed = "signed"
+			if err := signing.Verify(r.Method, r.SigningPayload(), r.Signature); err != nil {
+				signed = "signature INVALID"
+			}
+		}
+		fmt.Printf("#%d  %s  by %s  [%s]\n", r.ID, r.PurgedAt.Format("2006-01-02 15:04"), r.PurgedBy, signed)
+		printPurgeReport(r)
+		fmt.Println()
+	}
+}
+
+func shortHash(h string) string {
+	if len(h) > 12 {
+		return h[:12]
+	}
+	return h
+}
+
+func init() {
+	purgeActorCmd.Flags().String("mode", sqlite.PurgeModeAnonymize, "Purge mode: anonymize or remove")
+	purgeActorCmd.Flags().String("replacement", "", "Pseudonym to substitute (default: deleted-user-<hash>)")
+	purgeActorCmd.Flags().BoolP("force", "f", false, "Actually purge (without this flag, shows preview)")
+	purgeActorCmd.Flags().Bool("reports", false, "List stored deletion reports and verify their signatures")
+	rootCmd.AddCommand(purgeActorCmd)
+}
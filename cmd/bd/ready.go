@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -16,14 +18,31 @@ import (
 var readyCmd = &cobra.Command{
 	Use:   "ready",
 	Short: "Show ready work (no blockers, open or in-progress)",
+	Long: `Show ready work: open or in-progress issues with no open blockers.
+
+Examples:
+  bd ready --mine                  # Your queue (assigned to the current actor)
+  bd ready -a alice -l backend     # alice's backend work
+  bd ready --max-priority 1        # Only P0 and P1`,
 	Run: func(cmd *cobra.Command, args []string) {
 		limit, _ := cmd.Flags().GetInt("limit")
 		assignee, _ := cmd.Flags().GetString("assignee")
+		labels, _ := cmd.Flags().GetStringSlice("label")
+		mine, _ := cmd.Flags().GetBool("mine")
 		sortPolicy, _ := cmd.Flags().GetString("sort")
 		jsonOutput, _ := cmd.Flags().GetBool("json")
 
+		if mine {
+			if assignee != "" {
+				fmt.Fprintf(os.Stderr, "Error: --mine and --assignee are mutually exclusive\n")
+				os.Exit(1)
+			}
+			assignee = actor
+		}
+
 		filter := types.WorkFilter{
 			// Leave Status empty to get both 'open' and 'in_progress' (bd-165)
+			Labels:     labels,
 			Limit:      limit,
 			SortPolicy: types.SortPolicy(sortPolicy),
 		}
@@ -32,6 +51,10 @@ var readyCmd = &cobra.Command{
 			priority, _ := cmd.Flags().GetInt("priority")
 			filter.Priority = &priority
 		}
+		if cmd.Flags().Changed("max-priority") {
+			maxPriority, _ := cmd.Flags().GetInt("max-priority")
+			filter.MaxPriority = &maxPriority
+		}
 		if assignee != "" {
 			filter.Assignee = &assignee
 		}
@@ -45,13 +68,12 @@ var readyCmd = &cobra.Command{
 		// If daemon is running, use RPC
 		if daemonClient != nil {
 			readyArgs := &rpc.ReadyArgs{
-				Assignee:   assignee,
-				Limit:      limit,
-				SortPolicy: sortPolicy,
-			}
-			if cmd.Flags().Changed("priority") {
-				priority, _ := cmd.Flags().GetInt("priority")
-				readyArgs.Priority = &priority
+				Assignee:    assignee,
+				Priority:    filter.Priority,
+				MaxPriority: filter.MaxPriority,
+				Labels:      labels,
+				Limit:       limit,
+				SortPolicy:  sortPolicy,
 			}
 
 			resp, err := daemonClient.Ready(readyArgs)
@@ -81,19 +103,7 @@ var readyCmd = &cobra.Command{
 				return
 			}
 
-			cyan := color.New(color.FgCyan).SprintFunc()
-			fmt.Printf("\n%s Ready work (%d issues with no blockers):\n\n", cyan("📋"), len(issues))
-
-			for i, issue := range issues {
-				fmt.Printf("%d. [P%d] %s: %s\n", i+1, issue.Priority, issue.ID, issue.Title)
-				if issue.EstimatedMinutes != nil {
-					fmt.Printf("   Estimate: %d min\n", *issue.EstimatedMinutes)
-				}
-				if issue.Assignee != "" {
-					fmt.Printf("   Assignee: %s\n", issue.Assignee)
-				}
-			}
-			fmt.Println()
+			printReadyWork(issues)
 			return
 		}
 
@@ -133,20 +143,48 @@ var readyCmd = &cobra.Command{
 			return
 		}
 
-		cyan := color.New(color.FgCyan).SprintFunc()
-		fmt.Printf("\n%s Ready work (%d issues with no blockers):\n\n", cyan("📋"), len(issues))
+		printReadyWork(issues)
+	},
+}
 
-		for i, issue := range issues {
-			fmt.Printf("%d. [P%d] %s: %s\n", i+1, issue.Priority, issue.ID, issue.Title)
-			if issue.EstimatedMinutes != nil {
-				fmt.Printf("   Estimate: %d min\n", *issue.EstimatedMinutes)
-			}
-			if issue.Assignee != "" {
-				fmt.Printf("   Assignee: %s\n", issue.Assignee)
+// printReadyWork prints ready work as a numbered list. Overdue issues, which
+// the store sorts first, are shown in a section of their own.
+func printReadyWork(issues []*types.Issue) {
+	cyan := color.New(color.FgCyan).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	fmt.Printf("\n%s Ready work (%d issues with no blockers):\n\n", cyan("📋"), len(issues))
+
+	now := time.Now()
+	overdue := 0
+	for _, issue := range issues {
+		if issue.IsOverdue(now) {
+			overdue++
+		}
+	}
+	if overdue > 0 {
+		fmt.Printf("%s\n", red(fmt.Sprintf("⚠️  Overdue (%d):", overdue)))
+	}
+
+	for i, issue := range issues {
+		if overdue > 0 && i > 0 && issues[i-1].IsOverdue(now) && !issue.IsOverdue(now) {
+			fmt.Println()
+		}
+		fmt.Printf("%d. [P%d] %s: %s\n", i+1, issue.Priority, issue.ID, issue.Title)
+		if issue.DueDate != nil {
+			due := "Due: " + issue.DueDate.Local().Format("2006-01-02")
+			if issue.IsOverdue(now) {
+				due = red(due)
 			}
+			fmt.Printf("   %s\n", due)
 		}
-		fmt.Println()
-	},
+		if issue.EstimatedMinutes != nil {
+			fmt.Printf("   Estimate: %d min\n", *issue.EstimatedMinutes)
+		}
+		if issue.Assignee != "" {
+			fmt.Printf("   Assignee: %s\n", issue.Assignee)
+		}
+	}
+	fmt.Println()
 }
 
 var blockedCmd = &cobra.Command{
@@ -205,10 +243,25 @@ var blockedCmd = &cobra.Command{
 var statsCmd = &cobra.Command{
 	Use:   "stats",
 	Short: "Show statistics",
+	Long: `Show issue counts, lead time, and logged time.
+
+With --group-by, also shows a table of issues by status for each assignee,
+label, type, priority, or status. Grouping by label counts an issue under
+each of its labels.
+
+Examples:
+  bd stats --group-by assignee
+  bd stats --group-by label`,
 	Run: func(cmd *cobra.Command, args []string) {
+		groupBy, _ := cmd.Flags().GetString("group-by")
+		if groupBy != "" && !types.IsValidStatGroup(groupBy) {
+			fmt.Fprintf(os.Stderr, "Error: invalid --group-by %q (valid: %s)\n", groupBy, strings.Join(types.StatGroupDimensions, ", "))
+			os.Exit(1)
+		}
+
 		// If daemon is running, use RPC
 		if daemonClient != nil {
-			resp, err := daemonClient.Stats()
+			resp, err := daemonClient.Stats(&rpc.StatsArgs{GroupBy: groupBy})
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				os.Exit(1)
@@ -239,6 +292,8 @@ var statsCmd = &cobra.Command{
 			if stats.AverageLeadTime > 0 {
 				fmt.Printf("Avg Lead Time:     %.1f hours\n", stats.AverageLeadTime)
 			}
+			printTimeStats(&stats)
+			printStatGroups(&stats)
 			fmt.Println()
 			return
 		}
@@ -263,6 +318,14 @@ var statsCmd = &cobra.Command{
 		}
 	}
 
+		if groupBy != "" {
+			if stats.Groups, err = store.GetGroupedStatistics(ctx, groupBy); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			stats.GroupBy = groupBy
+		}
+
 		if jsonOutput {
 			outputJSON(stats)
 			return
@@ -285,17 +348,93 @@ var statsCmd = &cobra.Command{
 		if stats.AverageLeadTime > 0 {
 			fmt.Printf("Avg Lead Time:          %.1f hours\n", stats.AverageLeadTime)
 		}
+		printTimeStats(stats)
+		printStatGroups(stats)
 		fmt.Println()
 	},
 }
 
+// printStatGroups prints the counts by status for each group, if grouped
+func printStatGroups(stats *types.Statistics) {
+	if stats.GroupBy == "" {
+		return
+	}
+	fmt.Printf("\nBy %s:\n", stats.GroupBy)
+	if len(stats.Groups) == 0 {
+		fmt.Println("  (no issues)")
+		return
+	}
+	statuses := types.StatGroupStatuses(stats.Groups)
+	width := len(stats.GroupBy)
+	for _, g := range stats.Groups {
+		if n := len(statGroupLabel(stats.GroupBy, g.Key)); n > width {
+			width = n
+		}
+	}
+
+	fmt.Printf("  %-*s", width, stats.GroupBy)
+	for _, status := range statuses {
+		fmt.Printf("  %11s", status)
+	}
+	fmt.Printf("  %6s\n", "total")
+	for _, g := range stats.Groups {
+		fmt.Printf("  %-*s", width, statGroupLabel(stats.GroupBy, g.Key))
+		for _, status := range statuses {
+			fmt.Printf("  %11d", g.ByStatus[status])
+		}
+		fmt.Printf("  %6d\n", g.Total)
+	}
+}
+
+// statGroupLabel names a group's key in stats tables
+func statGroupLabel(groupBy, key string) string {
+	switch {
+	case key == "" && groupBy == types.GroupByLabel:
+		return "(no labels)"
+	case key == "":
+		return "(unassigned)"
+	case groupBy == types.GroupByPriority:
+		return "P" + key
+	}
+	return key
+}
+
+// printTimeStats prints the work log rollups, if any time has been logged
+func printTimeStats(stats *types.Statistics) {
+	if stats.LoggedMinutes == 0 {
+		return
+	}
+	fmt.Printf("\nTime Logged:            %s\n", formatMinutes(stats.LoggedMinutes))
+	fmt.Println("  By assignee:")
+	for _, t := range stats.TimeByAssignee {
+		assignee := t.Assignee
+		if assignee == "" {
+			assignee = "(unassigned)"
+		}
+		fmt.Printf("    %-20s %s across %d issue(s)\n", assignee, formatMinutes(t.LoggedMinutes), t.Issues)
+	}
+	fmt.Println("  By issue:")
+	for _, t := range stats.TimeByIssue {
+		logged := formatMinutes(t.LoggedMinutes)
+		if t.EstimatedMinutes != nil {
+			logged += " of " + formatMinutes(*t.EstimatedMinutes) + " estimated"
+		}
+		fmt.Printf("    %s %s: %s\n", t.IssueID, t.Title, logged)
+	}
+}
+
 func init() {
 	readyCmd.Flags().IntP("limit", "n", 10, "Maximum issues to show")
 	readyCmd.Flags().IntP("priority", "p", 0, "Filter by priority")
 	readyCmd.Flags().StringP("assignee", "a", "", "Filter by assignee")
+	readyCmd.Flags().Bool("mine", false, "Only work assigned to the current actor")
+	readyCmd.Flags().StringSliceP("label", "l", []string{}, "Filter by labels (must have all)")
+	readyCmd.Flags().Int("max-priority", 0, "Only this priority or more urgent (e.g. 1 for P0 and P1)")
 	readyCmd.Flags().StringP("sort", "s", "hybrid", "Sort policy: hybrid (default), priority, oldest")
 	readyCmd.Flags().Bool("json", false, "Output JSON format")
 
+	statsCmd.Flags().String("group-by", "", "Also count issues by status per assignee, label, type, priority, or status")
+
 	rootCmd.AddCommand(readyCmd)
 	rootCmd.AddCommand(blockedCmd)
 	rootCmd.AddCommand(statsCmd)
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/spf13/cobra"
+)
+
+var redactionsCmd = &cobra.Command{
+	Use:   "redactions",
+	Short: "Show secrets detected in issue text",
+	Long: `Show credentials (API keys, tokens, private keys) detected in descriptions,
+notes, design, acceptance criteria, and comments when they were written.
+
+Detection is controlled by the secrets.mode config key:
+  off      No scanning (default)
+  flag     Store text unchanged but record the finding here
+  mask     Replace the secret with [REDACTED:<kind>] and record it
+  reject   Refuse the write
+
+Only a short preview of each secret is kept in this report.
+
+Examples:
+  bd config set secrets.mode mask
+  bd redactions
+  bd redactions --issue bd-42`,
+	Run: func(cmd *cobra.Command, _ []string) {
+		issueID, _ := cmd.Flags().GetString("issue")
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		if err := ensureDirectMode("daemon does not support redactions command"); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: redactions command requires SQLite backend\n")
+			os.Exit(1)
+		}
+
+		redactions, err := sqliteStore.GetSecretRedactions(context.Background(), issueID, limit)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			if redactions == nil {
+				redactions = []*sqlite.SecretRedaction{}
+			}
+			outputJSON(redactions)
+			return
+		}
+
+		if len(redactions) == 0 {
+			green := color.New(color.FgGreen).SprintFunc()
+			fmt.Printf("%s No secrets detected\n", green("✓"))
+			return
+		}
+
+		yellow := color.New(color.FgYellow).SprintFunc()
+		fmt.Printf("%s %d secret(s) detected:\n\n", yellow("⚠"), len(redactions))
+		for _, r := range redactions {
+			fmt.Printf("  %s  %s.%s  %s  %s  [%s by %s]\n",
+				r.CreatedAt.Format("2006-01-02 15:04"), r.IssueID, r.Field, r.Kind, r.Preview, r.Action, r.Actor)
+		}
+	},
+}
+
+func init() {
+	redactionsCmd.Flags().String("issue", "", "Only show redactions for this issue")
+	redactionsCmd.Flags().Int("limit", 50, "Maximum number of redactions to show")
+	rootCmd.AddCommand(redactionsCmd)
+}
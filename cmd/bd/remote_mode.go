@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/imalsogreg/beads/internal/config"
+	"github.com/imalsogreg/beads/internal/storage/rest"
+	"github.com/imalsogreg/beads/pkg/client"
+)
+
+// remoteURL runs commands against a bd serve API instead of a local
+// database: its URL, or the name of an entry in the remotes config
+var remoteURL string
+
+// initializeRemoteMode points store at the API named by remoteURL. The token
+// comes from BEADS_REMOTE_TOKEN, else from the named remote.
+func initializeRemoteMode() error {
+	baseURL := remoteURL
+	token := config.GetString("remote-token")
+	for _, r := range mustRemoteRegistry().Remotes() {
+		if r.Name == remoteURL {
+			baseURL = r.URL
+			if token == "" {
+				token = r.Token
+			}
+		}
+	}
+
+	c, err := client.New(baseURL, client.WithToken(token), client.WithActor(actor))
+	if err != nil {
+		return fmt.Errorf("--remote: %w (expected a URL or a remote from config)", err)
+	}
+
+	// The server keeps the JSONL file; there is nothing local to sync
+	autoFlushEnabled = false
+	autoImportEnabled = false
+
+	storeMutex.Lock()
+	store = rest.New(c)
+	storeActive = true
+	storeMutex.Unlock()
+	return nil
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&remoteURL, "remote", "",
+		"Run against the bd serve API at this URL (or a remote from config) instead of a local database")
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/imalsogreg/beads/internal/config"
+	"github.com/imalsogreg/beads/internal/remote"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// remoteRegistry builds the registry of other workspaces from the remotes
+// table in config.yaml. Each entry is the base URL of a bd serve API, or a
+// table with url and token; tokens are expanded from the environment, so
+// $VAR keeps a key out of the file:
+//
+//	remotes:
+//	  docs: http://localhost:8080
+//	  platform:
+//	    url: https://beads.example.com/w/platform
+//	    token: $PLATFORM_BEADS_TOKEN
+//
+// Lookups are cached for ttl; zero caches them for the registry's lifetime.
+func remoteRegistry(ttl time.Duration) (*remote.Registry, error) {
+	var remotes []remote.Remote
+	for name, value := range config.GetStringMap("remotes") {
+		r := remote.Remote{Name: name}
+		switch v := value.(type) {
+		case string:
+			r.URL = v
+		case map[string]interface{}:
+			r.URL, _ = v["url"].(string)
+			token, _ := v["token"].(string)
+			r.Token = os.ExpandEnv(token)
+		default:
+			return nil, fmt.Errorf("remote %s: expected a URL or a table with url and token", name)
+		}
+		remotes = append(remotes, r)
+	}
+	return remote.NewRegistry(remotes, ttl)
+}
+
+// mustRemoteRegistry is remoteRegistry for a single command, exiting on a
+// bad remotes table
+func mustRemoteRegistry() *remote.Registry {
+	registry, err := remoteRegistry(0)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid remotes in config: %v\n", err)
+		os.Exit(1)
+	}
+	return registry
+}
+
+// printRemoteRefs lists an issue's dependencies on other workspaces' issues,
+// and the ones its text mentions, for bd show. Mentions only count for
+// registered remotes, since prose is full of things shaped like "a/b-1".
+func printRemoteRefs(ctx context.Context, registry *remote.Registry, issue *types.Issue, records []*types.Dependency) {
+	type entry struct {
+		ref   remote.Ref
+		arrow string
+	}
+	var entries []entry
+	seen := make(map[remote.Ref]bool)
+	for _, dep := range records {
+		if ref, ok := remote.ParseRef(dep.DependsOnID); ok && !seen[ref] {
+			seen[ref] = true
+			entries = append(entries, entry{ref, "→"})
+		}
+	}
+	var mentioned []remote.Ref
+	for _, text := range []string{issue.Title, issue.Description, issue.Design, issue.AcceptanceCriteria, issue.Notes} {
+		mentioned = append(mentioned, remote.FindRefs(text)...)
+	}
+	sort.SliceStable(mentioned, func(i, j int) bool { return mentioned[i].String() < mentioned[j].String() })
+	for _, ref := range mentioned {
+		if registry.Has(ref.Remote) && !seen[ref] {
+			seen[ref] = true
+			entries = append(entries, entry{ref, "·"})
+		}
+	}
+	if len(entries) == 0 {
+		return
+	}
+
+	fmt.Printf("\nOther workspaces (%d):\n", len(entries))
+	for _, e := range entries {
+		other, err := registry.GetIssue(ctx, e.ref)
+		if err != nil {
+			yellow := color.New(color.FgYellow).SprintFunc()
+			fmt.Printf("  %s %s: %s\n", e.arrow, e.ref, yellow("unreachable: "+err.Error()))
+			continue
+		}
+		fmt.Printf("  %s %s: %s [P%d] (%s)\n", e.arrow, e.ref, other.Title, other.Priority, other.Status)
+	}
+}
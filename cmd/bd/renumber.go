@@ -23,7 +23,7 @@ var renumberCmd = &cobra.Command{
 
 This command will:
 - Renumber all issues starting from 1 (keeping chronological order)
-- Update all dependency links (blocks, related, parent-child, discovered-from)
+- Update all dependency links (blocks, related, parent-child, discovered-from, duplicates, caused-by)
 - Update all text references in descriptions, notes, acceptance criteria
 - Show a mapping report of old ID -> new ID
 - Export the updated database to JSONL
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/leadtime"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/spf13/cobra"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Reports on how work flows",
+}
+
+var reportLeadTimeCmd = &cobra.Command{
+	Use:   "lead-time",
+	Short: "Lead and cycle time of closed issues",
+	Long: `Show how long closed issues took, as percentiles and a histogram
+sparkline.
+
+Lead time runs from an issue's creation to its close, and cycle time from
+its first move to in_progress to its close. Issues that were never in
+progress count toward lead time only.
+
+Examples:
+  bd report lead-time
+  bd report lead-time --group-by label --days 30
+  bd report lead-time --group-by assignee --json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		groupBy, _ := cmd.Flags().GetString("group-by")
+		days, _ := cmd.Flags().GetInt("days")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		if groupBy != "" && !leadtime.IsValidGroup(groupBy) {
+			fmt.Fprintf(os.Stderr, "Error: invalid --group-by %q (valid: %s)\n", groupBy, strings.Join(leadtime.GroupDimensions, ", "))
+			os.Exit(1)
+		}
+		if days < 0 {
+			fmt.Fprintf(os.Stderr, "Error: --days must be positive\n")
+			os.Exit(1)
+		}
+		var since time.Time
+		if days > 0 {
+			since = time.Now().AddDate(0, 0, -days)
+		}
+
+		report, err := leadtime.Compute(context.Background(), requireReportStore(), groupBy, since)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if jsonOutput {
+			outputJSON(report)
+			return
+		}
+		fmt.Print(report.Table())
+	},
+}
+
+func requireReportStore() *sqlite.SQLiteStorage {
+	if err := ensureDirectMode("daemon does not support report commands"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: report requires SQLite backend\n")
+		os.Exit(1)
+	}
+	return sqliteStore
+}
+
+func init() {
+	reportLeadTimeCmd.Flags().String("group-by", "", "Group by label, assignee, type, or priority")
+	reportLeadTimeCmd.Flags().Int("days", 0, "Only issues closed in the last N days (default all)")
+	reportLeadTimeCmd.Flags().Bool("json", false, "Output in JSON format")
+	reportCmd.AddCommand(reportLeadTimeCmd)
+	rootCmd.AddCommand(reportCmd)
+}
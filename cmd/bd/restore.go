@@ -16,8 +16,11 @@ import (
 
 var restoreCmd = &cobra.Command{
 	Use:   "restore <issue-id>",
-	Short: "Restore full history of a compacted issue from git",
-	Long: `Restore full history of a compacted issue from git version control.
+	Short: "Restore an issue from the trash, or a compacted issue's history from git",
+	Long: `Restore an issue moved to the trash by 'bd delete', or show the full
+history of a compacted issue from git version control.
+
+For an issue in the trash, this takes it back out, links and history intact.
 
 When an issue is compacted, the git commit hash is saved. This command:
 1. Reads the compacted_at_commit from the database
@@ -26,12 +29,17 @@ When an issue is compacted, the git commit hash is saved. This command:
 4. Displays the full issue history (description, events, etc.)
 5. Returns to the current git state
 
-This is read-only and does not modify the database or git state.`,
+Restoring a compacted issue is read-only and does not modify the database or
+git state.`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		issueID := args[0]
 		ctx := context.Background()
 
+		if restoreFromTrash(ctx, issueID) {
+			return
+		}
+
 		// Check if we're in a git repository
 		if !isGitRepo() {
 			fmt.Fprintf(os.Stderr, "Error: not in a git repository\n")
@@ -0,0 +1,345 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/imalsogreg/beads/internal/signing"
+	"github.com/imalsogreg/beads/internal/storage"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/spf13/cobra"
+)
+
+var retentionCmd = &cobra.Command{
+	Use:   "retention",
+	Short: "Manage data retention rules",
+	Long: `Configure rules that permanently delete old issues and events.
+
+Issue rules match on status and type, measuring age from when the issue was
+closed (or last updated if it is not closed). Without --status a rule only
+deletes closed issues; name another status to delete issues that aren't. Event rules purge audit trail
+entries by age and optionally by event type. Deleted issues take their
+comments, labels, dependencies, and events with them.
+
+Rules are enforced by the daemon's scheduler (once a day by default) when
+retention.enabled is true. Always check 'bd retention preview' first.
+
+Each run that deletes anything is stored as a report with the audit chain
+heads from before and after the chain is rebuilt, signed when audit.sign is
+set. List them with 'bd retention reports'.
+
+Configuration (bd config set):
+  retention.enabled             true to enforce rules from the daemon
+  schedule.retention.interval   how often to enforce (default 24h)
+
+Examples:
+  bd retention add --target issues --status closed --type chore --older-than 2y
+  bd retention add --target events --older-than 18mo
+  bd retention list
+  bd retention preview
+  bd retention apply --force
+  bd retention reports
+  bd retention remove 2`,
+}
+
+var retentionAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add a retention rule",
+	Run: func(cmd *cobra.Command, _ []string) {
+		target, _ := cmd.Flags().GetString("target")
+		issueType, _ := cmd.Flags().GetString("type")
+		status, _ := cmd.Flags().GetString("status")
+		eventType, _ := cmd.Flags().GetString("event-type")
+		olderThan, _ := cmd.Flags().GetString("older-than")
+
+		sqliteStore := requireRetentionStore()
+
+		days, err := sqlite.ParseRetentionAge(olderThan)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		rule := &sqlite.RetentionRule{
+			Target:     target,
+			IssueType:  issueType,
+			Status:     status,
+			EventType:  eventType,
+			MaxAgeDays: days,
+		}
+		if err := sqliteStore.AddRetentionRule(context.Background(), rule); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			outputJSON(rule)
+			return
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Added rule #%d: %s\n", green("✓"), rule.ID, rule.Describe())
+	},
+}
+
+var retentionListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List retention rules",
+	Run: func(_ *cobra.Command, _ []string) {
+		sqliteStore := requireRetentionStore()
+
+		rules, err := sqliteStore.GetRetentionRules(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			if rules == nil {
+				rules = []*sqlite.RetentionRule{}
+			}
+			outputJSON(rules)
+			return
+		}
+		if len(rules) == 0 {
+			fmt.Println("No retention rules")
+			return
+		}
+		for _, r := range rules {
+			fmt.Printf("#%d  %s\n", r.ID, r.Describe())
+		}
+	},
+}
+
+var retentionRemoveCmd = &cobra.Command{
+	Use:   "remove <rule-id>",
+	Short: "Remove a retention rule",
+	Args:  cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		sqliteStore := requireRetentionStore()
+
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid rule ID %q\n", args[0])
+			os.Exit(1)
+		}
+		if err := sqliteStore.RemoveRetentionRule(context.Background(), id); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			outputJSON(map[string]interface{}{"removed": id})
+			return
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Removed rule #%d\n", green("✓"), id)
+	},
+}
+
+var retentionPreviewCmd = &cobra.Command{
+	Use:   "preview",
+	Short: "Show what the retention rules would delete",
+	Run: func(_ *cobra.Command, _ []string) {
+		runRetention(false)
+	},
+}
+
+var retentionApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Enforce the retention rules now",
+	Run: func(cmd *cobra.Command, _ []string) {
+		force, _ := cmd.Flags().GetBool("force")
+		runRetention(force)
+	},
+}
+
+var retentionReportsCmd = &cobra.Command{
+	Use:   "reports",
+	Short: "List stored retention reports",
+	Run: func(_ *cobra.Command, _ []string) {
+		sqliteStore := requireRetentionStore()
+
+		reports, err := sqliteStore.GetRetentionReports(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if jsonOutput {
+			if reports == nil {
+				reports = []*sqlite.RetentionReport{}
+			}
+			outputJSON(reports)
+			return
+		}
+		if len(reports) == 0 {
+			fmt.Println("No retention reports")
+			return
+		}
+		for _, r := range reports {
+			signed := "unsigned"
+			if r.Signature != "" {
+				signed = "signed"
+				if err := signing.Verify(r.Method, r.SigningPayload(), r.Signature); err != nil {
+					signed = "signature INVALID"
+				}
+			}
+			fmt.Printf("Report #%d  %s  [%s]\n", r.ID, r.AppliedAt.Format("2006-01-02 15:04"), signed)
+			printRetentionReport(r)
+			fmt.Println()
+		}
+	},
+}
+
+func requireRetentionStore() *sqlite.SQLiteStorage {
+	if err := ensureDirectMode("daemon does not support retention command"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: retention command requires SQLite backend\n")
+		os.Exit(1)
+	}
+	return sqliteStore
+}
+
+func runRetention(force bool) {
+	sqliteStore := requireRetentionStore()
+
+	report, err := sqliteStore.ApplyRetention(context.Background(), !force)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if report.ID != 0 {
+		if err := signRetentionReport(context.Background(), sqliteStore, report); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: retention report is unsigned: %v\n", err)
+		}
+	}
+	if force && (report.IssuesDeleted > 0 || report.EventsDeleted > 0) {
+		markDirtyAndScheduleFullExport()
+	}
+
+	if jsonOutput {
+		outputJSON(report)
+		return
+	}
+
+	if !force {
+		yellow := color.New(color.FgYellow).SprintFunc()
+		fmt.Printf("\n%s\n\n", yellow("⚠️  RETENTION PREVIEW"))
+	}
+	printRetentionReport(report)
+	if !force && (report.IssuesDeleted > 0 || report.EventsDeleted > 0) {
+		fmt.Printf("\nTo proceed, run: bd retention apply --force\n")
+	}
+}
+
+// signRetentionReport signs a stored retention report with the audit.sign method
+func signRetentionReport(ctx context.Context, s *sqlite.SQLiteStorage, report *sqlite.RetentionReport) error {
+	method, _ := s.GetConfig(ctx, "audit.sign")
+	if method == "" {
+		return fmt.Errorf("audit.sign is not configured")
+	}
+	gpgKey, _ := s.GetConfig(ctx, "audit.gpg_key")
+	signature, signer, err := signing.Sign(method, gpgKey, report.SigningPayload())
+	if err != nil {
+		return err
+	}
+	report.Method, report.Signer, report.Signature = method, signer, signature
+	return s.SignRetentionReport(ctx, report)
+}
+
+func printRetentionReport(report *sqlite.RetentionReport) {
+	if len(report.Rules) == 0 {
+		fmt.Println("No retention rules")
+		return
+	}
+	for _, r := range report.Rules {
+		fmt.Printf("#%d  %s\n", r.Rule.ID, r.Rule.Describe())
+		if r.Rule.Target == sqlite.RetentionTargetIssues {
+			fmt.Printf("    %d issue(s)", len(r.IssueIDs))
+			if len(r.IssueIDs) > 0 {
+				shown := r.IssueIDs
+				if len(shown) > 10 {
+					shown = shown[:10]
+				}
+				fmt.Printf(": %s", strings.Join(shown, ", "))
+				if len(r.IssueIDs) > len(shown) {
+					fmt.Printf(" (+%d more)", len(r.IssueIDs)-len(shown))
+				}
+			}
+			fmt.Println()
+		} else {
+			fmt.Printf("    %d event(s)\n", r.Events)
+		}
+	}
+	verb := "Would delete"
+	if !report.DryRun {
+		verb = "Deleted"
+	}
+	fmt.Printf("\n%s %d issue(s) and %d event(s)\n", verb, report.IssuesDeleted, report.EventsDeleted)
+	if report.AuditChainRebuilt {
+		fmt.Printf("Audit chain rebuilt %s → %s (%d signature(s) removed)\n",
+			shortHash(report.AuditHeadBefore), shortHash(report.AuditHeadAfter), report.AuditSignaturesRemoved)
+	}
+	if report.Signature != "" {
+		fmt.Printf("Signed: %s", report.Method)
+		if report.Signer != "" {
+			fmt.Printf(" (%s)", report.Signer)
+		}
+		fmt.Println()
+	}
+}
+
+// runScheduledRetention enforces retention rules from the daemon scheduler
+func runScheduledRetention(ctx context.Context, store storage.Storage, log daemonLogger) error {
+	sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+	if !ok {
+		return fmt.Errorf("retention requires SQLite backend")
+	}
+	report, err := sqliteStore.ApplyRetention(ctx, false)
+	if err != nil {
+		return err
+	}
+	for _, r := range report.Rules {
+		if len(r.IssueIDs) > 0 || r.Events > 0 {
+			log.log("Retention rule #%d (%s): %d issue(s) %v, %d event(s)",
+				r.Rule.ID, r.Rule.Describe(), len(r.IssueIDs), r.IssueIDs, r.Events)
+		}
+	}
+	log.log("Retention: deleted %d issue(s) and %d event(s)", report.IssuesDeleted, report.EventsDeleted)
+	if report.AuditChainRebuilt {
+		log.log("Retention: audit chain rebuilt %s -> %s", report.AuditHeadBefore, report.AuditHeadAfter)
+	}
+	if report.ID != 0 {
+		if method, _ := sqliteStore.GetConfig(ctx, "audit.sign"); method != "" {
+			if err := signRetentionReport(ctx, sqliteStore, report); err != nil {
+				log.log("Retention: report #%d is unsigned: %v", report.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+func init() {
+	retentionAddCmd.Flags().String("target", sqlite.RetentionTargetIssues, "What to delete: issues or events")
+	retentionAddCmd.Flags().String("type", "", "Only issues of this type (issue rules)")
+	retentionAddCmd.Flags().String("status", "", "Only issues with this status (issue rules; default closed)")
+	retentionAddCmd.Flags().String("event-type", "", "Only events of this type (event rules)")
+	retentionAddCmd.Flags().String("older-than", "", "Age threshold, e.g. 90d, 12w, 6mo, 2y (required)")
+	_ = retentionAddCmd.MarkFlagRequired("older-than")
+
+	retentionApplyCmd.Flags().BoolP("force", "f", false, "Actually delete (without this flag, shows preview)")
+
+	retentionCmd.AddCommand(retentionAddCmd)
+	retentionCmd.AddCommand(retentionListCmd)
+	retentionCmd.AddCommand(retentionRemoveCmd)
+	retentionCmd.AddCommand(retentionPreviewCmd)
+	retentionCmd.AddCommand(retentionApplyCmd)
+	retentionCmd.AddCommand(retentionReportsCmd)
+	rootCmd.AddCommand(retentionCmd)
+}
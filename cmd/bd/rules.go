@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+	"github.com/spf13/cobra"
+)
+
+var rulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "Manage triage rules applied to new and updated issues",
+	Long: `Triage rules label, prioritize, or assign issues as they are written.
+
+A rule matches an issue that meets all of its conditions: a title or
+description regular expression, a type, and a priority. Rules run --on
+create (the default), update, or any. An update only fires rules the issue
+didn't match before it, so a rule doesn't undo a later manual change.
+Rules run in the order they were added; where they disagree, the last
+one's priority or assignee wins. Changes are recorded as the actor
+"triage". Imported issues and closed issues aren't triaged.
+
+Examples:
+  bd rules add network --title-match '(?i)timeout' --add-label network --set-priority 1
+  bd rules add crashes --on any --type bug --description-match panic --set-assignee oncall
+  bd rules list
+  bd rules test --title "Request timeout on login"
+  bd rules test bd-12 --on update
+  bd rules remove network`,
+}
+
+var rulesAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add a triage rule",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		rule := &sqlite.TriageRule{Name: args[0], CreatedBy: actor}
+		rule.On, _ = cmd.Flags().GetString("on")
+		rule.TitleMatch, _ = cmd.Flags().GetString("title-match")
+		rule.DescriptionMatch, _ = cmd.Flags().GetString("description-match")
+		rule.IssueType, _ = cmd.Flags().GetString("type")
+		rule.AddLabels, _ = cmd.Flags().GetStringSlice("add-label")
+		rule.SetAssignee, _ = cmd.Flags().GetString("set-assignee")
+		if cmd.Flags().Changed("priority") {
+			p, _ := cmd.Flags().GetInt("priority")
+			rule.Priority = &p
+		}
+		if cmd.Flags().Changed("set-priority") {
+			p, _ := cmd.Flags().GetInt("set-priority")
+			rule.SetPriority = &p
+		}
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		if err := requireRulesStore().AddTriageRule(context.Background(), rule); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			outputJSON(rule)
+			return
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Added rule %s: %s\n", green("✓"), rule.Name, rule.Describe())
+	},
+}
+
+var rulesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List triage rules in the order they run",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		rules, err := requireRulesStore().GetTriageRules(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			if rules == nil {
+				rules = []*sqlite.TriageRule{}
+			}
+			outputJSON(rules)
+			return
+		}
+		if len(rules) == 0 {
+			fmt.Println("No triage rules")
+			return
+		}
+		cyan := color.New(color.FgCyan).SprintFunc()
+		for _, r := range rules {
+			fmt.Printf("%s  %s\n", cyan(r.Name), r.Describe())
+		}
+	},
+}
+
+var rulesRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a triage rule",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		if err := requireRulesStore().RemoveTriageRule(context.Background(), args[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			outputJSON(map[string]interface{}{"removed": args[0]})
+			return
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Removed rule %s\n", green("✓"), args[0])
+	},
+}
+
+var rulesTestCmd = &cobra.Command{
+	Use:   "test [issue-id]",
+	Short: "Show what the rules would do to an issue, without changing it",
+	Long: `Dry-run the triage rules against an existing issue, or against one
+described with --title, --description, --type, and --priority. Given an
+issue, those flags replace its own fields.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		on, _ := cmd.Flags().GetString("on")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		sqliteStore := requireRulesStore()
+		ctx := context.Background()
+
+		issue := &types.Issue{Priority: 2, IssueType: types.TypeTask}
+		var labels []string
+		if len(args) > 0 {
+			existing, err := sqliteStore.GetIssue(ctx, args[0])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if existing == nil {
+				fmt.Fprintf(os.Stderr, "Error: issue %s not found\n", args[0])
+				os.Exit(1)
+			}
+			issue = existing
+			if labels, err = sqliteStore.GetLabels(ctx, issue.ID); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if cmd.Flags().Changed("title") {
+			issue.Title, _ = cmd.Flags().GetString("title")
+		}
+		if cmd.Flags().Changed("description") {
+			issue.Description, _ = cmd.Flags().GetString("description")
+		}
+		if cmd.Flags().Changed("type") {
+			issueType, _ := cmd.Flags().GetString("type")
+			issue.IssueType = types.IssueType(issueType)
+		}
+		if cmd.Flags().Changed("priority") {
+			issue.Priority, _ = cmd.Flags().GetInt("priority")
+		}
+		if cmd.Flags().Changed("label") {
+			labels, _ = cmd.Flags().GetStringSlice("label")
+		}
+
+		result, err := sqliteStore.EvaluateTriageRules(ctx, issue, labels, on)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			outputJSON(result)
+			return
+		}
+		printTriageResult(result)
+	},
+}
+
+func printTriageResult(result *sqlite.TriageResult) {
+	if len(result.Matched) == 0 {
+		fmt.Println("No rules match")
+		return
+	}
+	fmt.Printf("Matched: %s\n", strings.Join(result.Matched, ", "))
+	if !result.Changed() {
+		fmt.Println("No changes")
+		return
+	}
+	if len(result.AddLabels) > 0 {
+		fmt.Printf("  add labels %s\n", strings.Join(result.AddLabels, ", "))
+	}
+	if result.Priority != nil {
+		fmt.Printf("  set priority P%d\n", *result.Priority)
+	}
+	if result.Assignee != "" {
+		fmt.Printf("  assign %s\n", result.Assignee)
+	}
+}
+
+func requireRulesStore() *sqlite.SQLiteStorage {
+	if err := ensureDirectMode("daemon does not support rules command"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: rules command requires SQLite backend\n")
+		os.Exit(1)
+	}
+	return sqliteStore
+}
+
+func init() {
+	rulesAddCmd.Flags().String("on", sqlite.TriageOnCreate, "When the rule runs: create, update, or any")
+	rulesAddCmd.Flags().String("title-match", "", "Regular expression the title must match, e.g. '(?i)timeout'")
+	rulesAddCmd.Flags().String("description-match", "", "Regular expression the description must match")
+	rulesAddCmd.Flags().String("type", "", "Only issues of this type")
+	rulesAddCmd.Flags().IntP("priority", "p", 0, "Only issues at this priority")
+	rulesAddCmd.Flags().StringSlice("add-label", nil, "Add this label to matching issues (repeatable)")
+	rulesAddCmd.Flags().Int("set-priority", 0, "Set matching issues to this priority")
+	rulesAddCmd.Flags().String("set-assignee", "", "Assign matching issues to this actor")
+
+	rulesTestCmd.Flags().String("on", sqlite.TriageOnCreate, "Which rules to run: create or update")
+	rulesTestCmd.Flags().String("title", "", "Issue title")
+	rulesTestCmd.Flags().String("description", "", "Issue description")
+	rulesTestCmd.Flags().String("type", "", "Issue type (default task)")
+	rulesTestCmd.Flags().IntP("priority", "p", 2, "Issue priority")
+	rulesTestCmd.Flags().StringSlice("label", nil, "Labels the issue has (repeatable)")
+
+	for _, c := range []*cobra.Command{rulesAddCmd, rulesListCmd, rulesRemoveCmd, rulesTestCmd} {
+		c.Flags().Bool("json", false, "Output JSON format")
+	}
+
+	rulesCmd.AddCommand(rulesAddCmd)
+	rulesCmd.AddCommand(rulesListCmd)
+	rulesCmd.AddCommand(rulesRemoveCmd)
+	rulesCmd.AddCommand(rulesTestCmd)
+	rootCmd.AddCommand(rulesCmd)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/schedule"
+	"github.com/imalsogreg/beads/internal/types"
+	"github.com/spf13/cobra"
+)
+
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule [epic-id]",
+	Short: "Project start and finish dates for unfinished work",
+	Long: `Project when unfinished work will start and finish, from estimates,
+'blocks' dependencies, and start and due dates, as a timeline to share.
+
+With an epic ID, schedules the epic's open descendants, with a section per
+nested epic. Without one, schedules every open issue that isn't an epic.
+
+Each issue starts when its blockers finish, or on its start date if later,
+and takes its estimate in working days of --hours-per-day (unestimated
+issues take a day). Work runs in parallel wherever dependencies allow, and
+issues projected past their due date are flagged.
+
+Examples:
+  bd schedule bd-1
+  bd schedule bd-1 --format mermaid-gantt > launch.mmd
+  bd schedule --format csv --from 2025-11-03 > schedule.csv`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+		fromFlag, _ := cmd.Flags().GetString("from")
+		hoursPerDay, _ := cmd.Flags().GetInt("hours-per-day")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		if format != "" && format != schedule.FormatMermaidGantt && format != schedule.FormatCSV {
+			fmt.Fprintf(os.Stderr, "Error: --format must be %s\n", strings.Join(schedule.Formats, " or "))
+			os.Exit(1)
+		}
+		if hoursPerDay <= 0 || hoursPerDay > 24 {
+			fmt.Fprintf(os.Stderr, "Error: --hours-per-day must be between 1 and 24\n")
+			os.Exit(1)
+		}
+		opts := schedule.Options{HoursPerDay: hoursPerDay}
+		if fromFlag != "" {
+			from, err := types.ParseDate(fromFlag, time.Now())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: --from: %v\n", err)
+				os.Exit(1)
+			}
+			opts.From = from
+		}
+
+		if err := ensureDirectMode("daemon does not support schedule command"); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		ctx := context.Background()
+		var sched *schedule.Schedule
+		var err error
+		if len(args) == 1 {
+			sched, err = schedule.ForEpic(ctx, store, args[0], opts)
+		} else {
+			sched, err = schedule.ForAll(ctx, store, opts)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		switch {
+		case format != "":
+			if err := schedule.Write(os.Stdout, sched, format); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		case jsonOutput:
+			outputJSON(sched)
+		default:
+			fmt.Print(sched.Table())
+		}
+	},
+}
+
+func init() {
+	scheduleCmd.Flags().StringP("format", "f", "", "Output format: mermaid-gantt or csv (default a plain-text timeline)")
+	scheduleCmd.Flags().String("from", "", "First day work can start (YYYY-MM-DD, today, tomorrow, or +Nd/+Nw; default today)")
+	scheduleCmd.Flags().Int("hours-per-day", schedule.DefaultHoursPerDay, "Working hours in a day, for turning estimates into days")
+	scheduleCmd.Flags().Bool("json", false, "Output in JSON format")
+	rootCmd.AddCommand(scheduleCmd)
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+	"github.com/spf13/cobra"
+)
+
+// Control characters bracket matches in snippets so they can be colored
+// after the fact without clashing with issue text
+const (
+	searchMarkStart = "\x02"
+	searchMarkEnd   = "\x03"
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Full-text search across issue text and comments",
+	Long: `Search titles, descriptions, design notes, acceptance criteria, notes,
+and comments. Results are ranked by relevance (title matches weigh most) and
+shown with a highlighted snippet of the best-matching text.
+
+Every word must match, as a prefix ("auth" matches "authentication").
+Wrap text in double quotes to match an exact phrase.
+
+Examples:
+  bd search login timeout
+  bd search '"connection reset"' --status open
+  bd search migration --label backend --limit 5`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		status, _ := cmd.Flags().GetString("status")
+		assignee, _ := cmd.Flags().GetString("assignee")
+		issueType, _ := cmd.Flags().GetString("type")
+		labels, _ := cmd.Flags().GetStringSlice("label")
+		limit, _ := cmd.Flags().GetInt("limit")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		includeArchived, _ := cmd.Flags().GetBool("include-archived")
+
+		if err := ensureDirectMode("daemon does not support search command"); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: search command requires SQLite backend\n")
+			os.Exit(1)
+		}
+
+		filter := types.IssueFilter{Limit: limit, ExcludeArchived: !includeArchived}
+		if status != "" && status != "all" {
+			s := types.Status(status)
+			filter.Status = &s
+		}
+		// Use Changed() to properly handle P0 (priority=0)
+		if cmd.Flags().Changed("priority") {
+			priority, _ := cmd.Flags().GetInt("priority")
+			filter.Priority = &priority
+		}
+		if assignee != "" {
+			filter.Assignee = &assignee
+		}
+		if issueType != "" {
+			t := types.IssueType(issueType)
+			filter.IssueType = &t
+		}
+		if labels = normalizeLabels(labels); len(labels) > 0 {
+			filter.Labels = labels
+		}
+
+		opts := sqlite.SearchOptions{Filter: filter}
+		if !jsonOutput {
+			opts.HighlightStart, opts.HighlightEnd = searchMarkStart, searchMarkEnd
+		}
+
+		ctx := context.Background()
+		hits, err := sqliteStore.FullTextSearch(ctx, strings.Join(args, " "), opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			if hits == nil {
+				hits = []*sqlite.SearchHit{}
+			}
+			outputJSON(hits)
+			return
+		}
+
+		if len(hits) == 0 {
+			fmt.Println("No matches found")
+			return
+		}
+
+		cyan := color.New(color.FgCyan).SprintFunc()
+		fmt.Printf("\nFound %d match(es):\n\n", len(hits))
+		for _, hit := range hits {
+			issue := hit.Issue
+			fmt.Printf("%s [P%d] [%s] %s\n", cyan(issue.ID), issue.Priority, issue.IssueType, issue.Status)
+			fmt.Printf("  %s\n", issue.Title)
+			if hit.Snippet != "" {
+				fmt.Printf("  %s\n", highlightSnippet(hit.Snippet))
+			}
+			fmt.Println()
+		}
+	},
+}
+
+// highlightSnippet replaces the search match markers with bold yellow, or
+// with brackets when color is disabled
+func highlightSnippet(s string) string {
+	start, end := sqlite.DefaultHighlightStart, sqlite.DefaultHighlightEnd
+	if !color.NoColor {
+		start, end = "\x1b[1;33m", "\x1b[0m"
+	}
+	return strings.NewReplacer(searchMarkStart, start, searchMarkEnd, end).Replace(s)
+}
+
+func init() {
+	searchCmd.Flags().StringP("status", "s", "", "Filter by status (open, in_progress, blocked, closed)")
+	searchCmd.Flags().IntP("priority", "p", 0, "Filter by priority (0-4)")
+	searchCmd.Flags().StringP("assignee", "a", "", "Filter by assignee")
+	searchCmd.Flags().StringP("type", "t", "", "Filter by type (bug, feature, task, epic, chore)")
+	searchCmd.Flags().StringSliceP("label", "l", []string{}, "Filter by labels (must have ALL)")
+	searchCmd.Flags().IntP("limit", "n", 20, "Maximum number of results")
+	searchCmd.Flags().Bool("include-archived", false, "Include issues moved to the archive by 'bd archive run'")
+	searchCmd.Flags().Bool("json", false, "Output JSON format")
+	rootCmd.AddCommand(searchCmd)
+}
@@ -4,12 +4,27 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"log/slog"
+	"math"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/imalsogreg/beads"
+	"github.com/imalsogreg/beads/internal/apikey"
+	"github.com/imalsogreg/beads/internal/config"
 	httpserver "github.com/imalsogreg/beads/internal/http"
+	"github.com/imalsogreg/beads/internal/oidc"
+	"github.com/imalsogreg/beads/internal/rpc"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/telemetry"
+	"github.com/imalsogreg/beads/internal/types"
 )
 
 var serveCmd = &cobra.Command{
@@ -18,8 +33,13 @@ var serveCmd = &cobra.Command{
 	Long: `Start an HTTP server that exposes all beads commands via REST API.
 
 The server provides both JSON and human-readable text responses based on
-the Accept header. All endpoints (except GET /) require Bearer token
-authentication via the BEADS_API_SECRET environment variable.
+the Accept header. All endpoints (except the docs at GET /, /openapi.json,
+and /docs, and the web UI at /ui/) require Bearer token authentication via
+the BEADS_API_SECRET environment variable, an API key from 'bd key create',
+or a token from an OpenID Connect provider (--oidc-issuer).
+
+Open http://localhost:8080/ui/ in a browser for a web dashboard with an
+issue list, a board by status, issue detail, and dependency graphs.
 
 Example:
   # Start server on default port 8080
@@ -35,19 +55,329 @@ Example:
   export BEADS_API_SECRET=your-secret-token
   bd serve
 
-The server will run until interrupted (Ctrl+C).`,
+  # Compact old closed issues and purge expired trash once a day
+  # (Tier 1 also needs ANTHROPIC_API_KEY; Tier 2 runs without it)
+  bd serve --compact-interval 24h
+
+  # Back up every 6 hours, keeping the last 28 (a week's worth)
+  bd serve --backup-interval 6h --backup-retain 28
+
+  # Allow 50 requests/s overall, 5/s per API key or actor, 20/s for the CI key
+  bd serve --rate-limit 50 --client-rate-limit 5 --key-rate-limit ci=20
+
+  # Serve HTTPS with your own certificate
+  bd serve --tls-cert server.crt --tls-key server.key
+
+  # Get a certificate from Let's Encrypt (the domain must reach this
+  # server on port 443)
+  bd serve --port 443 --acme-domain beads.example.com
+
+  # Accept tokens from an OpenID Connect provider; the token's subject
+  # (or --oidc-actor-claim) becomes the actor
+  bd serve --oidc-issuer https://accounts.example.com --oidc-audience beads
+
+  # Let a browser dashboard on another origin call the API (or set
+  # cors.origins, cors.methods, and cors.headers with 'bd config set')
+  bd serve --cors-origin https://dash.example.com
+
+  # Require client certificates signed by your CA (mutual TLS); a
+  # certificate's common name becomes the actor
+  bd serve --tls-cert server.crt --tls-key server.key --client-ca clients.pem
+
+  # Log requests as JSON and send traces to a local OpenTelemetry collector
+  bd serve --request-log json --otlp-endpoint localhost:4318 --otlp-insecure
+
+  # Expose a dashboard publicly without letting anyone change issues,
+  # reading from a replica so the primary isn't loaded
+  bd serve --read-only --replica /replicas/beads.db
+
+  # Host two more projects under /w/frontend/ and /w/infra/ (or list them
+  # in a workspaces table in config.yaml); each checks tokens against its
+  # own keys, made with 'bd --db <path> key create'
+  bd serve --workspace frontend=/src/frontend --workspace infra=/srv/infra/.beads/beads.db
+
+  # Tell clients still calling paths without /v1/ when they'll stop working
+  bd serve --unversioned-sunset 2027-06-30
+
+  # Agents polling ready work get cached results until the database
+  # changes; with a lagging replica, shorten how long a result may live
+  bd serve --replica /replicas/beads.db --cache-ttl 2s
+
+  # Give running requests up to two minutes to finish on shutdown
+  bd serve --drain-timeout 2m
+
+  # Responses of 1 KB or more are compressed (zstd or gzip, as the client
+  # accepts); raise the threshold or turn compression off
+  bd serve --compress-min-size 8192
+  bd serve --compress=false
+
+The server will run until interrupted (Ctrl+C) or sent SIGTERM. It then
+stops taking new requests and waits up to --drain-timeout for running ones
+to finish; a second signal stops it at once. Before a planned restart,
+POST /admin/drain turns new work away while load balancers catch up.`,
 	RunE: runServe,
 }
 
 var (
-	servePort string
-	serveHost string
+	servePort            string
+	serveHost            string
+	serveCompactInterval time.Duration
+	serveBackupInterval  time.Duration
+	serveBackupRetain    int
+	serveRateLimit       float64
+	serveClientRateLimit float64
+	serveRateLimitBurst  int
+	serveKeyRateLimits   []string
+	serveTLSCert         string
+	serveTLSKey          string
+	serveACMEDomains     []string
+	serveACMECache       string
+	serveACMEEmail       string
+	serveClientCA        string
+	serveOIDCIssuer      string
+	serveOIDCAudience    string
+	serveOIDCJWKSURL     string
+	serveOIDCActorClaim  string
+	serveOIDCRole        string
+	serveCORSOrigins     []string
+	serveCORSMethods     []string
+	serveCORSHeaders     []string
+	serveCompress        bool
+	serveCompressMinSize int
+	serveRequestLog      string
+	serveOTLPEndpoint    string
+	serveOTLPInsecure    bool
+	serveOTLPHeaders     []string
+	serveTraceSampling   float64
+	serveDrainTimeout    time.Duration
+	serveReadOnly        bool
+	serveReplica         string
+	serveSunset          string
+	serveCacheTTL        time.Duration
+	serveWorkspaces      []string
 )
 
 func init() {
 	rootCmd.AddCommand(serveCmd)
 	serveCmd.Flags().StringVar(&servePort, "port", "8080", "Port to listen on")
 	serveCmd.Flags().StringVar(&serveHost, "host", "0.0.0.0", "Host to bind to")
+	serveCmd.Flags().DurationVar(&serveCompactInterval, "compact-interval", 0, "Compact and purge expired trash in the background at this interval (0 disables)")
+	serveCmd.Flags().DurationVar(&serveBackupInterval, "backup-interval", 0, "Back up the database in the background at this interval (0 disables); see 'bd backup'")
+	serveCmd.Flags().IntVar(&serveBackupRetain, "backup-retain", 7, "Scheduled backups to keep, oldest removed first (0 keeps all)")
+	serveCmd.Flags().Float64Var(&serveRateLimit, "rate-limit", 0, "Requests per second allowed across all clients (0 disables)")
+	serveCmd.Flags().Float64Var(&serveClientRateLimit, "client-rate-limit", 0, "Requests per second allowed for each API key, or each actor without one (0 disables)")
+	serveCmd.Flags().IntVar(&serveRateLimitBurst, "rate-limit-burst", 0, "Requests allowed in a burst above the rate (default: one second's worth)")
+	serveCmd.Flags().StringArrayVar(&serveKeyRateLimits, "key-rate-limit", nil, "Per-second limit for one API key, as NAME=RATE (repeatable; overrides --client-rate-limit)")
+	serveCmd.Flags().StringVar(&serveTLSCert, "tls-cert", "", "PEM certificate file; serves HTTPS with --tls-key")
+	serveCmd.Flags().StringVar(&serveTLSKey, "tls-key", "", "PEM private key file for --tls-cert")
+	serveCmd.Flags().StringSliceVar(&serveACMEDomains, "acme-domain", nil, "Serve HTTPS with certificates from Let's Encrypt for these domains")
+	serveCmd.Flags().StringVar(&serveACMECache, "acme-cache", "", "Directory for Let's Encrypt certificates (default: <user cache dir>/beads/acme)")
+	serveCmd.Flags().StringVar(&serveACMEEmail, "acme-email", "", "Contact email for Let's Encrypt")
+	serveCmd.Flags().StringVar(&serveClientCA, "client-ca", "", "PEM bundle of CAs for client certificates; requires them (mutual TLS)")
+	serveCmd.Flags().StringVar(&serveOIDCIssuer, "oidc-issuer", "", "Accept JWTs from this OpenID Connect issuer as Bearer tokens")
+	serveCmd.Flags().StringVar(&serveOIDCAudience, "oidc-audience", "", "Audience OIDC tokens must be issued for (required with --oidc-issuer)")
+	serveCmd.Flags().StringVar(&serveOIDCJWKSURL, "oidc-jwks-url", "", "JWKS URL for OIDC signing keys (default: discovered from the issuer)")
+	serveCmd.Flags().StringVar(&serveOIDCActorClaim, "oidc-actor-claim", "sub", "OIDC token claim to use as the actor, e.g. email or preferred_username")
+	serveCmd.Flags().StringVar(&serveOIDCRole, "oidc-role", "writer", "Role granted to OIDC tokens (reader, writer, or admin)")
+	serveCmd.Flags().StringSliceVar(&serveCORSOrigins, "cors-origin", nil, "Origins browsers may call the API from, or * for any (default: cors.origins config)")
+	serveCmd.Flags().StringSliceVar(&serveCORSMethods, "cors-methods", nil, "Methods allowed cross-origin (default: cors.methods config, else GET,POST,PUT,PATCH,DELETE)")
+	serveCmd.Flags().StringSliceVar(&serveCORSHeaders, "cors-headers", nil, "Request headers allowed cross-origin (default: cors.headers config, else the headers the API uses)")
+	serveCmd.Flags().BoolVar(&serveCompress, "compress", true, "Compress responses with zstd or gzip for clients that accept them")
+	serveCmd.Flags().IntVar(&serveCompressMinSize, "compress-min-size", 1024, "Smallest response, in bytes, worth compressing")
+	serveCmd.Flags().StringVar(&serveRequestLog, "request-log", "text", "Log each request to stderr as text, json, or off")
+	serveCmd.Flags().StringVar(&serveOTLPEndpoint, "otlp-endpoint", "", "Send OpenTelemetry traces to this OTLP/HTTP collector (host:port or URL)")
+	serveCmd.Flags().BoolVar(&serveOTLPInsecure, "otlp-insecure", false, "Use plain HTTP for a host:port --otlp-endpoint")
+	serveCmd.Flags().StringArrayVar(&serveOTLPHeaders, "otlp-header", nil, "Header sent with trace exports, as KEY=VALUE (repeatable)")
+	serveCmd.Flags().DurationVar(&serveDrainTimeout, "drain-timeout", 30*time.Second, "How long shutdown waits for running requests and WebSockets before closing them")
+	serveCmd.Flags().Float64Var(&serveTraceSampling, "trace-sample-ratio", 1, "Fraction of requests to trace when the caller hasn't decided (0-1)")
+	serveCmd.Flags().BoolVar(&serveReadOnly, "read-only", false, "Refuse every write with 403 and open the database read-only, e.g. for a public dashboard")
+	serveCmd.Flags().StringArrayVar(&serveWorkspaces, "workspace", nil, "Also serve another project under /w/NAME/, as NAME=PATH to its database or project directory (repeatable; adds to the workspaces table in config.yaml)")
+	serveCmd.Flags().StringVar(&serveSunset, "unversioned-sunset", "", "Announce when API paths without /v1/ stop working, as a Sunset header (YYYY-MM-DD, or e.g. +90d)")
+	serveCmd.Flags().DurationVar(&serveCacheTTL, "cache-ttl", 10*time.Second, "Longest to reuse statistics, ready work, and listings between database changes (0 disables caching)")
+	serveCmd.Flags().StringVar(&serveReplica, "replica", "", "Serve reads from this copy of the database (kept current by e.g. Litestream); writes still go to --db")
+}
+
+// corsConfig builds the server's CORS settings from the serve flags, falling
+// back to the cors.* config values. The second result is false when no
+// origin is allowed.
+func corsConfig(ctx context.Context) (httpserver.CORSConfig, bool, error) {
+	setting := func(flag []string, key string) ([]string, error) {
+		if len(flag) > 0 {
+			return flag, nil
+		}
+		value, err := store.GetConfig(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		var values []string
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+		return values, nil
+	}
+
+	var config httpserver.CORSConfig
+	var err error
+	if config.AllowedOrigins, err = setting(serveCORSOrigins, "cors.origins"); err != nil {
+		return config, false, err
+	}
+	if config.AllowedMethods, err = setting(serveCORSMethods, "cors.methods"); err != nil {
+		return config, false, err
+	}
+	if config.AllowedHeaders, err = setting(serveCORSHeaders, "cors.headers"); err != nil {
+		return config, false, err
+	}
+	return config, len(config.AllowedOrigins) > 0, nil
+}
+
+// tlsConfig builds the server's TLS settings from the serve flags. The
+// second result is false when TLS isn't requested.
+func tlsConfig() (httpserver.TLSConfig, bool, error) {
+	config := httpserver.TLSConfig{
+		CertFile:     serveTLSCert,
+		KeyFile:      serveTLSKey,
+		ACMEDomains:  serveACMEDomains,
+		ACMECacheDir: serveACMECache,
+		ACMEEmail:    serveACMEEmail,
+		ClientCAFile: serveClientCA,
+	}
+	if config.CertFile == "" && config.KeyFile == "" && len(config.ACMEDomains) == 0 {
+		if config.ClientCAFile != "" {
+			return config, false, fmt.Errorf("--client-ca requires --tls-cert/--tls-key or --acme-domain")
+		}
+		return config, false, nil
+	}
+	if len(config.ACMEDomains) > 0 && config.ACMECacheDir == "" {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return config, false, fmt.Errorf("failed to find a cache directory for ACME certificates (set --acme-cache): %w", err)
+		}
+		config.ACMECacheDir = filepath.Join(cacheDir, "beads", "acme")
+	}
+	return config, true, nil
+}
+
+// workspaceRegistry maps workspace names to database paths, from the
+// workspaces table in config.yaml overridden by --workspace flags
+func workspaceRegistry() (map[string]string, error) {
+	registry := config.GetStringMapString("workspaces")
+	for _, spec := range serveWorkspaces {
+		name, path, ok := strings.Cut(spec, "=")
+		if !ok || name == "" || path == "" {
+			return nil, fmt.Errorf("invalid --workspace %q: expected NAME=PATH", spec)
+		}
+		registry[name] = path
+	}
+	return registry, nil
+}
+
+// addWorkspaces opens every registered workspace's database and mounts it
+// on server. The returned function closes them again.
+func addWorkspaces(server *httpserver.Server) (func(), error) {
+	registry, err := workspaceRegistry()
+	if err != nil {
+		return nil, err
+	}
+	var stores []*sqlite.SQLiteStorage
+	closeAll := func() {
+		for _, store := range stores {
+			_ = store.Close()
+		}
+	}
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		// A project directory stands for its canonical database, which must
+		// exist so a typo doesn't create an empty one
+		path := registry[name]
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			path = filepath.Join(path, ".beads", beads.CanonicalDatabaseName)
+		}
+		if _, err := os.Stat(path); err != nil {
+			closeAll()
+			return nil, fmt.Errorf("workspace %s: %w", name, err)
+		}
+
+		poolConfig := sqlitePoolConfig()
+		poolConfig.ReadOnly = serveReadOnly
+		store, err := sqlite.NewWithConfig(path, poolConfig)
+		if err != nil {
+			closeAll()
+			return nil, fmt.Errorf("workspace %s: failed to open database: %w", name, err)
+		}
+		stores = append(stores, store)
+		if err := server.AddWorkspace(name, store); err != nil {
+			closeAll()
+			return nil, err
+		}
+		log.Printf("🗂️  Workspace %s: %s\n", name, path)
+	}
+	return closeAll, nil
+}
+
+// rateLimitConfig builds the server's rate limits from the serve flags. The
+// second result is false when no limit is set.
+func rateLimitConfig() (httpserver.RateLimitConfig, bool, error) {
+	limit := func(rate float64) httpserver.RateLimit {
+		burst := serveRateLimitBurst
+		if burst <= 0 {
+			burst = int(math.Ceil(rate))
+		}
+		return httpserver.RateLimit{Rate: rate, Burst: burst}
+	}
+
+	config := httpserver.RateLimitConfig{Keys: map[string]httpserver.RateLimit{}}
+	if serveRateLimit < 0 || serveClientRateLimit < 0 {
+		return config, false, fmt.Errorf("rate limits cannot be negative")
+	}
+	enabled := serveRateLimit > 0 || serveClientRateLimit > 0
+	if serveRateLimit > 0 {
+		config.Global = limit(serveRateLimit)
+	}
+	if serveClientRateLimit > 0 {
+		config.PerClient = limit(serveClientRateLimit)
+	}
+	for _, spec := range serveKeyRateLimits {
+		name, value, ok := strings.Cut(spec, "=")
+		rate, err := strconv.ParseFloat(value, 64)
+		if !ok || name == "" || err != nil || rate < 0 {
+			return config, false, fmt.Errorf("invalid --key-rate-limit %q: expected NAME=RATE", spec)
+		}
+		config.Keys[name] = limit(rate)
+		enabled = true
+	}
+	return config, enabled, nil
+}
+
+// reopenServeStore swaps the global store for one opened with --read-only
+// and --replica
+func reopenServeStore() error {
+	sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+	if !ok {
+		return fmt.Errorf("--read-only and --replica require SQLite backend")
+	}
+	poolConfig := sqlitePoolConfig()
+	poolConfig.ReadOnly = serveReadOnly
+	poolConfig.ReplicaPath = serveReplica
+	reopened, err := sqlite.NewWithConfig(sqliteStore.Path(), poolConfig)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+
+	storeMutex.Lock()
+	defer storeMutex.Unlock()
+	_ = store.Close()
+	store = reopened
+	if serveReplica != "" {
+		log.Printf("📖 Replica: %s\n", serveReplica)
+	}
+	return nil
 }
 
 func runServe(cmd *cobra.Command, args []string) error {
@@ -56,23 +386,196 @@ func runServe(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to initialize database")
 	}
 
+	// PersistentPreRun opened the database for writing
+	if serveReadOnly || serveReplica != "" {
+		if err := reopenServeStore(); err != nil {
+			return err
+		}
+	}
+
 	defer store.Close()
 
 	log.Printf("📂 Database: %s\n", dbPath)
 
-	// Check for API secret
-	if secret := os.Getenv("BEADS_API_SECRET"); secret != "" {
+	// Check for API secret or keys
+	hasKeys := false
+	if sqliteStore, ok := store.(*sqlite.SQLiteStorage); ok {
+		hasKeys, _ = sqliteStore.HasAPIKeys(context.Background())
+	}
+	if serveOIDCIssuer != "" {
+		log.Printf("🔒 Authentication: enabled (OIDC: %s)\n", serveOIDCIssuer)
+	} else if secret := os.Getenv("BEADS_API_SECRET"); secret != "" {
 		log.Printf("🔒 Authentication: enabled (BEADS_API_SECRET is set)\n")
+	} else if hasKeys {
+		log.Printf("🔒 Authentication: enabled (API keys)\n")
 	} else {
-		log.Printf("⚠️  Authentication: disabled (BEADS_API_SECRET not set - development mode)\n")
+		log.Printf("⚠️  Authentication: disabled (no BEADS_API_SECRET or API keys - development mode)\n")
 	}
 
+	// Reported in the OpenAPI document
+	rpc.ServerVersion = Version
+
 	// Create HTTP server
 	addr := fmt.Sprintf("%s:%s", serveHost, servePort)
 	server, err := httpserver.NewServer(store, addr)
 	if err != nil {
 		return fmt.Errorf("failed to create server: %w", err)
 	}
+	if serveReadOnly {
+		server.EnableReadOnly()
+		log.Printf("👀 Read-only: writes are refused\n")
+	}
+	if serveSunset != "" {
+		sunset, err := types.ParseDate(serveSunset, time.Now())
+		if err != nil {
+			return fmt.Errorf("--unversioned-sunset: %w", err)
+		}
+		server.SetUnversionedSunset(sunset)
+	}
+	if serveCompactInterval > 0 {
+		if _, ok := store.(*sqlite.SQLiteStorage); !ok {
+			return fmt.Errorf("--compact-interval requires SQLite backend")
+		}
+		if serveReadOnly {
+			return fmt.Errorf("--compact-interval can't be used with --read-only")
+		}
+		server.EnableCompaction(serveCompactInterval)
+		log.Printf("🗜️  Compaction: every %v\n", serveCompactInterval)
+	}
+	limits, limited, err := rateLimitConfig()
+	if err != nil {
+		return err
+	}
+	if serveOIDCIssuer != "" {
+		role, err := apikey.ParseRole(serveOIDCRole)
+		if err != nil {
+			return fmt.Errorf("invalid --oidc-role: %w", err)
+		}
+		err = server.EnableOIDC(oidc.Config{
+			Issuer:     serveOIDCIssuer,
+			Audience:   serveOIDCAudience,
+			JWKSURL:    serveOIDCJWKSURL,
+			ActorClaim: serveOIDCActorClaim,
+		}, role)
+		if err != nil {
+			return err
+		}
+	}
+	cors, useCORS, err := corsConfig(context.Background())
+	if err != nil {
+		return err
+	}
+	if useCORS {
+		server.EnableCORS(cors)
+		log.Printf("🌐 CORS: %s\n", strings.Join(cors.AllowedOrigins, ", "))
+	}
+	tlsSettings, useTLS, err := tlsConfig()
+	if err != nil {
+		return err
+	}
+	scheme := "http"
+	if useTLS {
+		if err := server.EnableTLS(tlsSettings); err != nil {
+			return err
+		}
+		scheme = "https"
+		if len(tlsSettings.ACMEDomains) > 0 {
+			log.Printf("🔐 TLS: Let's Encrypt for %s (cache: %s)\n", strings.Join(tlsSettings.ACMEDomains, ", "), tlsSettings.ACMECacheDir)
+		} else {
+			log.Printf("🔐 TLS: %s\n", tlsSettings.CertFile)
+		}
+		if tlsSettings.ClientCAFile != "" {
+			log.Printf("🔐 Client certificates: required (%s)\n", tlsSettings.ClientCAFile)
+		}
+	}
+	switch serveRequestLog {
+	case "text":
+		server.EnableRequestLog(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	case "json":
+		server.EnableRequestLog(slog.New(slog.NewJSONHandler(os.Stderr, nil)))
+	case "off":
+	default:
+		return fmt.Errorf("invalid --request-log %q: expected text, json, or off", serveRequestLog)
+	}
+	if serveOTLPEndpoint != "" {
+		headers, err := telemetry.ParseHeaders(serveOTLPHeaders)
+		if err != nil {
+			return fmt.Errorf("invalid --otlp-header: %w", err)
+		}
+		shutdownTracing, err := telemetry.Setup(context.Background(), telemetry.Config{
+			Endpoint:       serveOTLPEndpoint,
+			Insecure:       serveOTLPInsecure,
+			Headers:        headers,
+			SampleRatio:    serveTraceSampling,
+			ServiceVersion: Version,
+		})
+		if err != nil {
+			return err
+		}
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := shutdownTracing(ctx); err != nil {
+				log.Printf("⚠️  Failed to flush traces: %v\n", err)
+			}
+		}()
+		log.Printf("🔭 Tracing: OTLP to %s (sampling %v)\n", serveOTLPEndpoint, serveTraceSampling)
+	}
+	if serveDrainTimeout <= 0 {
+		return fmt.Errorf("--drain-timeout must be positive")
+	}
+	if serveCompress {
+		if serveCompressMinSize <= 0 {
+			return fmt.Errorf("--compress-min-size must be positive")
+		}
+		server.EnableCompression(httpserver.CompressionConfig{MinSize: serveCompressMinSize})
+	}
+	if serveCacheTTL < 0 {
+		return fmt.Errorf("--cache-ttl must not be negative")
+	}
+	server.EnableQueryCache(serveCacheTTL)
+	if limited {
+		server.EnableRateLimit(limits)
+		log.Printf("🚦 Rate limits: %v/s overall, %v/s per client, %d key override(s)\n",
+			serveRateLimit, serveClientRateLimit, len(limits.Keys))
+	}
+	// Remote issues change, so trees re-fetch them after a minute
+	remotes, err := remoteRegistry(time.Minute)
+	if err != nil {
+		return fmt.Errorf("invalid remotes in config: %w", err)
+	}
+	server.EnableRemotes(remotes)
+	if n := len(remotes.Remotes()); n > 0 {
+		log.Printf("🔗 Remotes: %d other workspace(s) resolvable in dependency trees\n", n)
+	}
+	uploader, err := backupUploader()
+	if err != nil {
+		return fmt.Errorf("invalid backup.s3 config: %w", err)
+	}
+	if uploader != nil {
+		server.EnableBackups(uploader)
+		log.Printf("💾 Backups: uploads go to %s\n", config.GetString("backup.s3.bucket"))
+	}
+	if serveBackupInterval > 0 {
+		if _, ok := store.(*sqlite.SQLiteStorage); !ok {
+			return fmt.Errorf("--backup-interval requires SQLite backend")
+		}
+		if serveBackupRetain < 0 {
+			return fmt.Errorf("--backup-retain can't be negative")
+		}
+		server.EnableScheduledBackups(serveBackupInterval, serveBackupRetain)
+		keep := fmt.Sprintf("the last %d", serveBackupRetain)
+		if serveBackupRetain == 0 {
+			keep = "all"
+		}
+		log.Printf("💾 Backups: every %v, keeping %s\n", serveBackupInterval, keep)
+	}
+	// Workspaces share the settings above, so they're added last
+	closeWorkspaces, err := addWorkspaces(server)
+	if err != nil {
+		return err
+	}
+	defer closeWorkspaces()
 
 	// Setup graceful shutdown
 	stop := make(chan os.Signal, 1)
@@ -81,8 +584,9 @@ func runServe(cmd *cobra.Command, args []string) error {
 	// Start server in goroutine
 	errChan := make(chan error, 1)
 	go func() {
-		log.Printf("🚀 Server starting on http://%s\n", addr)
-		log.Printf("📚 API docs available at http://%s/\n", addr)
+		log.Printf("🚀 Server starting on %s://%s\n", scheme, addr)
+		log.Printf("📚 API docs available at %s://%s/\n", scheme, addr)
+		log.Printf("🖥️  Web UI available at %s://%s/ui/\n", scheme, addr)
 		if err := server.Start(); err != nil {
 			errChan <- err
 		}
@@ -93,9 +597,17 @@ func runServe(cmd *cobra.Command, args []string) error {
 	case err := <-errChan:
 		return fmt.Errorf("server error: %w", err)
 	case <-stop:
-		log.Println("\n🛑 Shutting down server...")
-		ctx, cancel := context.WithTimeout(context.Background(), 10)
+		log.Printf("\n🛑 Shutting down server (draining for up to %v; interrupt again to stop now)...\n", serveDrainTimeout)
+		ctx, cancel := context.WithTimeout(context.Background(), serveDrainTimeout)
 		defer cancel()
+		go func() {
+			select {
+			case <-stop:
+				log.Println("🛑 Stopping now")
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
 		if err := server.Stop(ctx); err != nil {
 			return fmt.Errorf("failed to stop server: %w", err)
 		}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/spf13/cobra"
+)
+
+var sessionCmd = &cobra.Command{
+	Use:   "session",
+	Short: "Track the changes an agent run makes",
+	Long: `An agent session records one run of an AI agent or bot: who it acts as,
+the model and tool driving it, and the run's own ID. Every change made
+while BD_SESSION names a session is tagged with it, so the whole run can
+be reviewed (or reverted by hand) afterwards.
+
+A session belongs to the actor that started it, and only that actor can
+write in it or end it. Commands run in a session bypass the daemon.
+
+Examples:
+  export BD_SESSION=$(bd session start --model claude --tool ci-agent --run-id job-42)
+  bd update bd-12 --status in_progress    # Tagged with the session
+  bd session show $BD_SESSION             # Everything the run changed
+  bd session end
+  bd session list --active`,
+}
+
+var sessionStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Start a session and print its ID",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		model, _ := cmd.Flags().GetString("model")
+		tool, _ := cmd.Flags().GetString("tool")
+		runID, _ := cmd.Flags().GetString("run-id")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		s := &sqlite.AgentSession{Actor: actor, Model: model, Tool: tool, RunID: runID}
+		if err := requireSessionStore().StartSession(context.Background(), s); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			outputJSON(s)
+			return
+		}
+		// Just the ID, for export BD_SESSION=$(bd session start)
+		fmt.Println(s.ID)
+	},
+}
+
+var sessionEndCmd = &cobra.Command{
+	Use:   "end [id]",
+	Short: "End a session (default: BD_SESSION)",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		id := sessionArg(args)
+		sqliteStore := requireSessionStore()
+		ctx := context.Background()
+
+		s, err := sqliteStore.GetSession(ctx, id)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if s.Actor != actor {
+			fmt.Fprintf(os.Stderr, "Error: %v: %s is %s's\n", sqlite.ErrSessionNotOwned, id, s.Actor)
+			os.Exit(1)
+		}
+		if s, err = sqliteStore.EndSession(ctx, id); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			outputJSON(s)
+			return
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Ended %s (%d changes)\n", green("✓"), s.ID, s.Changes)
+	},
+}
+
+var sessionListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List sessions, newest first",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		filter := sqlite.SessionFilter{}
+		filter.Actor, _ = cmd.Flags().GetString("actor")
+		filter.Active, _ = cmd.Flags().GetBool("active")
+		filter.Limit, _ = cmd.Flags().GetInt("limit")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		sessions, err := requireSessionStore().ListSessions(context.Background(), filter)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			if sessions == nil {
+				sessions = []*sqlite.AgentSession{}
+			}
+			outputJSON(sessions)
+			return
+		}
+
+		if len(sessions) == 0 {
+			fmt.Println("No sessions")
+			return
+		}
+		for _, s := range sessions {
+			printSession(s)
+		}
+	},
+}
+
+var sessionShowCmd = &cobra.Command{
+	Use:   "show [id]",
+	Short: "Show a session and everything it changed (default: BD_SESSION)",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		filter := sqlite.AuditFilter{}
+		filter.Limit, _ = cmd.Flags().GetInt("limit")
+		filter.Before, _ = cmd.Flags().GetInt64("before")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		id := sessionArg(args)
+
+		activity, err := requireSessionStore().GetSessionActivity(context.Background(), id, filter)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			outputJSON(activity)
+			return
+		}
+
+		printSession(activity.Session)
+		fmt.Println()
+		if len(activity.Entries) == 0 {
+			fmt.Println("No changes")
+			return
+		}
+		printAuditEntries(activity.Entries)
+		if activity.NextCursor != 0 {
+			fmt.Printf("\nMore changes: bd session show %s --before %d\n", id, activity.NextCursor)
+		}
+	},
+}
+
+func printSession(s *sqlite.AgentSession) {
+	cyan := color.New(color.FgCyan).SprintFunc()
+	state := "active"
+	if s.EndedAt != nil {
+		state = "ended " + s.EndedAt.Local().Format("2006-01-02 15:04")
+	}
+	fmt.Printf("%s  %s  %s  started %s, %s, %d changes\n", cyan(s.ID), s.Actor, sessionAgent(s),
+		s.StartedAt.Local().Format("2006-01-02 15:04"), state, s.Changes)
+	if s.RunID != "" {
+		fmt.Printf("    run %s\n", s.RunID)
+	}
+}
+
+// sessionAgent describes what drove a session, e.g. "ci-agent/claude"
+func sessionAgent(s *sqlite.AgentSession) string {
+	switch {
+	case s.Tool != "" && s.Model != "":
+		return s.Tool + "/" + s.Model
+	case s.Tool != "":
+		return s.Tool
+	case s.Model != "":
+		return s.Model
+	default:
+		return "-"
+	}
+}
+
+// sessionArg returns the session ID given on the command line, or BD_SESSION
+func sessionArg(args []string) string {
+	if len(args) > 0 {
+		return args[0]
+	}
+	if session == "" {
+		fmt.Fprintf(os.Stderr, "Error: session ID required (or set BD_SESSION)\n")
+		os.Exit(1)
+	}
+	return session
+}
+
+// applySession tags the store's writes with BD_SESSION, once the store is
+// open and the actor resolved. The session must be the actor's and running.
+func applySession() {
+	if session == "" {
+		return
+	}
+	sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: agent sessions require SQLite backend\n")
+		os.Exit(1)
+	}
+	if err := sqliteStore.CheckSession(context.Background(), session, actor); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: BD_SESSION: %v\n", err)
+		os.Exit(1)
+	}
+	sqliteStore.SetSession(session)
+}
+
+func requireSessionStore() *sqlite.SQLiteStorage {
+	if err := ensureDirectMode("daemon does not support session command"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: session command requires SQLite backend\n")
+		os.Exit(1)
+	}
+	return sqliteStore
+}
+
+func init() {
+	sessionStartCmd.Flags().String("model", "", "Model driving the agent")
+	sessionStartCmd.Flags().String("tool", "", "Agent or harness running the session")
+	sessionStartCmd.Flags().String("run-id", "", "The agent's own ID for the run, e.g. a CI job")
+	sessionListCmd.Flags().String("actor", "", "Only this actor's sessions")
+	sessionListCmd.Flags().Bool("active", false, "Only sessions that haven't ended")
+	sessionListCmd.Flags().Int("limit", 0, "Maximum number of sessions to show")
+	sessionShowCmd.Flags().Int("limit", sqlite.DefaultAuditLimit, "Maximum number of changes to show")
+	sessionShowCmd.Flags().Int64("before", 0, "Page cursor: only changes older than this event ID")
+	for _, c := range []*cobra.Command{sessionStartCmd, sessionEndCmd, sessionListCmd, sessionShowCmd} {
+		c.Flags().Bool("json", false, "Output JSON format")
+	}
+
+	sessionCmd.AddCommand(sessionStartCmd)
+	sessionCmd.AddCommand(sessionEndCmd)
+	sessionCmd.AddCommand(sessionListCmd)
+	sessionCmd.AddCommand(sessionShowCmd)
+	rootCmd.AddCommand(sessionCmd)
+}
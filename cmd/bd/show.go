@@ -3,14 +3,19 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
-	"os/exec"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"github.com/imalsogreg/beads/internal/markdown"
 	"github.com/imalsogreg/beads/internal/rpc"
+	"github.com/imalsogreg/beads/internal/storage"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
 	"github.com/imalsogreg/beads/internal/types"
 )
 
@@ -19,6 +24,17 @@ var showCmd = &cobra.Command{
 	Short: "Show issue details",
 	Args:  cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+		switch format {
+		case "":
+		case "markdown":
+			showMarkdown(args)
+			return
+		default:
+			fmt.Fprintf(os.Stderr, "Error: unknown format %q (use markdown)\n", format)
+			os.Exit(1)
+		}
+
 		// If daemon is running, use RPC
 		if daemonClient != nil {
 			allDetails := []interface{}{}
@@ -54,9 +70,10 @@ var showCmd = &cobra.Command{
 					// Parse response and use existing formatting code
 					type IssueDetails struct {
 						types.Issue
-						Labels       []string       `json:"labels,omitempty"`
-						Dependencies []*types.Issue `json:"dependencies,omitempty"`
-						Dependents   []*types.Issue `json:"dependents,omitempty"`
+						Labels             []string            `json:"labels,omitempty"`
+						Dependencies       []*types.Issue      `json:"dependencies,omitempty"`
+						Dependents         []*types.Issue      `json:"dependents,omitempty"`
+						RemoteDependencies []*types.Dependency `json:"remote_dependencies,omitempty"`
 					}
 					var details IssueDetails
 					if err := json.Unmarshal(resp.Data, &details); err != nil {
@@ -86,9 +103,13 @@ var showCmd = &cobra.Command{
 					if issue.Assignee != "" {
 						fmt.Printf("Assignee: %s\n", issue.Assignee)
 					}
+					if issue.Milestone != "" {
+						fmt.Printf("Milestone: %s\n", issue.Milestone)
+					}
 					if issue.EstimatedMinutes != nil {
 						fmt.Printf("Estimated: %d minutes\n", *issue.EstimatedMinutes)
 					}
+					printDates(issue)
 					fmt.Printf("Created: %s\n", issue.CreatedAt.Format("2006-01-02 15:04"))
 					fmt.Printf("Updated: %s\n", issue.UpdatedAt.Format("2006-01-02 15:04"))
 
@@ -131,21 +152,26 @@ var showCmd = &cobra.Command{
 					if len(details.Labels) > 0 {
 						fmt.Printf("\nLabels: %v\n", details.Labels)
 					}
+					printWatchers(issue.Watchers)
 
-					if len(details.Dependencies) > 0 {
-						fmt.Printf("\nDepends on (%d):\n", len(details.Dependencies))
-						for _, dep := range details.Dependencies {
+					if deps := storage.WithoutLinks(details.Dependencies, issue.Links, false); len(deps) > 0 {
+						fmt.Printf("\nDepends on (%d):\n", len(deps))
+						for _, dep := range deps {
 							fmt.Printf("  → %s: %s [P%d]\n", dep.ID, dep.Title, dep.Priority)
 						}
 					}
 
-					if len(details.Dependents) > 0 {
-						fmt.Printf("\nBlocks (%d):\n", len(details.Dependents))
-						for _, dep := range details.Dependents {
+					if dependents := storage.WithoutLinks(details.Dependents, issue.Links, true); len(dependents) > 0 {
+						fmt.Printf("\nBlocks (%d):\n", len(dependents))
+						for _, dep := range dependents {
 							fmt.Printf("  ← %s: %s [P%d]\n", dep.ID, dep.Title, dep.Priority)
 						}
 					}
 
+					printLinks(issue.Links)
+
+					printRemoteRefs(context.Background(), mustRemoteRegistry(), issue, details.RemoteDependencies)
+
 					fmt.Println()
 				}
 			}
@@ -179,6 +205,10 @@ var showCmd = &cobra.Command{
 					Dependents   []*types.Issue   `json:"dependents,omitempty"`
 					Comments     []*types.Comment `json:"comments,omitempty"`
 				}
+				if links, _ := storage.GetIssueLinks(ctx, store, issue.ID); len(links) > 0 {
+					issue.Links = links
+				}
+				issue.Watchers = getWatchers(ctx, issue.ID)
 				details := &IssueDetails{Issue: issue}
 				details.Labels, _ = store.GetLabels(ctx, issue.ID)
 				details.Dependencies, _ = store.GetDependencies(ctx, issue.ID)
@@ -213,9 +243,13 @@ var showCmd = &cobra.Command{
 			if issue.Assignee != "" {
 				fmt.Printf("Assignee: %s\n", issue.Assignee)
 			}
+			if issue.Milestone != "" {
+				fmt.Printf("Milestone: %s\n", issue.Milestone)
+			}
 			if issue.EstimatedMinutes != nil {
 				fmt.Printf("Estimated: %d minutes\n", *issue.EstimatedMinutes)
 			}
+			printDates(issue)
 			fmt.Printf("Created: %s\n", issue.CreatedAt.Format("2006-01-02 15:04"))
 			fmt.Printf("Updated: %s\n", issue.UpdatedAt.Format("2006-01-02 15:04"))
 
@@ -262,9 +296,12 @@ var showCmd = &cobra.Command{
 			if len(labels) > 0 {
 				fmt.Printf("\nLabels: %v\n", labels)
 			}
+			printWatchers(getWatchers(ctx, issue.ID))
 
-			// Show dependencies
+			// Show dependencies, with links listed separately
+			links, _ := storage.GetIssueLinks(ctx, store, issue.ID)
 			deps, _ := store.GetDependencies(ctx, issue.ID)
+			deps = storage.WithoutLinks(deps, links, false)
 			if len(deps) > 0 {
 				fmt.Printf("\nDepends on (%d):\n", len(deps))
 				for _, dep := range deps {
@@ -274,6 +311,7 @@ var showCmd = &cobra.Command{
 
 			// Show dependents
 			dependents, _ := store.GetDependents(ctx, issue.ID)
+			dependents = storage.WithoutLinks(dependents, links, true)
 			if len(dependents) > 0 {
 				fmt.Printf("\nBlocks (%d):\n", len(dependents))
 				for _, dep := range dependents {
@@ -281,6 +319,11 @@ var showCmd = &cobra.Command{
 				}
 			}
 
+			printLinks(links)
+
+			records, _ := store.GetDependencyRecords(ctx, issue.ID)
+			printRemoteRefs(ctx, mustRemoteRegistry(), issue, records)
+
 			// Show comments
 			comments, _ := store.GetIssueComments(ctx, issue.ID)
 			if len(comments) > 0 {
@@ -299,10 +342,111 @@ var showCmd = &cobra.Command{
 	},
 }
 
+// showMarkdown prints issues as markdown for bd show --format markdown
+func showMarkdown(ids []string) {
+	ctx := context.Background()
+	var details []markdown.Detail
+	for _, id := range ids {
+		if daemonClient != nil {
+			resp, err := daemonClient.Show(&rpc.ShowArgs{ID: id})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error fetching %s: %v\n", id, err)
+				continue
+			}
+			var issue struct {
+				types.Issue
+				Dependencies []*types.Issue `json:"dependencies,omitempty"`
+				Dependents   []*types.Issue `json:"dependents,omitempty"`
+			}
+			if string(resp.Data) == "null" || json.Unmarshal(resp.Data, &issue) != nil {
+				fmt.Fprintf(os.Stderr, "Issue %s not found\n", id)
+				continue
+			}
+			details = append(details, markdown.Detail{
+				Issue:        &issue.Issue,
+				Dependencies: issue.Dependencies,
+				Dependents:   issue.Dependents,
+			})
+			continue
+		}
+
+		issue, err := store.GetIssue(ctx, id)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error fetching %s: %v\n", id, err)
+			continue
+		}
+		if issue == nil {
+			fmt.Fprintf(os.Stderr, "Issue %s not found\n", id)
+			continue
+		}
+		issue.Links, _ = storage.GetIssueLinks(ctx, store, issue.ID)
+		issue.Watchers = getWatchers(ctx, issue.ID)
+		d := markdown.Detail{Issue: issue}
+		d.Labels, _ = store.GetLabels(ctx, issue.ID)
+		d.Dependencies, _ = store.GetDependencies(ctx, issue.ID)
+		d.Dependents, _ = store.GetDependents(ctx, issue.ID)
+		d.Comments, _ = store.GetIssueComments(ctx, issue.ID)
+		details = append(details, d)
+	}
+	if len(details) > 0 {
+		fmt.Print(markdown.Issues(details))
+	}
+}
+
+// printLinks prints an issue's non-blocking links for bd show
+func printLinks(links []*types.IssueLink) {
+	if len(links) == 0 {
+		return
+	}
+	fmt.Printf("\nLinks (%d):\n", len(links))
+	for _, l := range links {
+		fmt.Printf("  ~ %s %s: %s [P%d]\n", l.Relation(), l.ID, l.Title, l.Priority)
+	}
+}
+
+// printWatchers prints the users watching an issue for bd show
+func printWatchers(watchers []string) {
+	if len(watchers) > 0 {
+		fmt.Printf("\nWatchers (%d): %s\n", len(watchers), strings.Join(watchers, ", "))
+	}
+}
+
+// printDates prints an issue's start and due dates for bd show, flagging an
+// overdue issue
+func printDates(issue *types.Issue) {
+	if issue.StartDate != nil {
+		fmt.Printf("Start: %s\n", issue.StartDate.Local().Format("2006-01-02"))
+	}
+	if issue.DueDate != nil {
+		overdue := ""
+		if issue.IsOverdue(time.Now()) {
+			overdue = color.New(color.FgRed).Sprint(" (overdue)")
+		}
+		fmt.Printf("Due: %s%s\n", issue.DueDate.Local().Format("2006-01-02"), overdue)
+	}
+}
+
 var updateCmd = &cobra.Command{
 	Use:   "update [id...]",
 	Short: "Update one or more issues",
-	Args:  cobra.MinimumNArgs(1),
+	Long: `Update one or more issues by ID, or every issue matching --filter.
+
+With --filter, all matching issues are updated in a single transaction and
+the affected IDs are reported. Filter terms are key=value pairs and must all
+match: status, priority, assignee, type, label, label-any, title, id.
+
+Examples:
+  bd update bd-42 --status in_progress
+  bd update --filter status=open --filter label=infra --assignee bob`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if cmd.Flags().Changed("filter") {
+			if len(args) > 0 {
+				return fmt.Errorf("cannot combine issue IDs with --filter")
+			}
+			return nil
+		}
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		updates := make(map[string]interface{})
 
@@ -347,12 +491,32 @@ var updateCmd = &cobra.Command{
 			externalRef, _ := cmd.Flags().GetString("external-ref")
 			updates["external_ref"] = externalRef
 		}
+		for _, flag := range []string{"due", "start"} {
+			if cmd.Flags().Changed(flag) {
+				value, _ := cmd.Flags().GetString(flag)
+				if _, err := types.ParseOptionalDate(value, time.Now()); err != nil {
+					fmt.Fprintf(os.Stderr, "Error: --%s: %v\n", flag, err)
+					os.Exit(1)
+				}
+				updates[flag+"_date"] = value
+			}
+		}
+		if cmd.Flags().Changed("milestone") {
+			milestone, _ := cmd.Flags().GetString("milestone")
+			updates["milestone"] = milestone
+		}
 
 		if len(updates) == 0 {
 			fmt.Println("No updates specified")
 			return
 		}
 
+		if cmd.Flags().Changed("filter") {
+			filterTerms, _ := cmd.Flags().GetStringArray("filter")
+			runBulkUpdate(filterTerms, updates)
+			return
+		}
+
 		// If daemon is running, use RPC
 		if daemonClient != nil {
 			updatedIssues := []*types.Issue{}
@@ -384,6 +548,15 @@ var updateCmd = &cobra.Command{
 				if acceptanceCriteria, ok := updates["acceptance_criteria"].(string); ok {
 					updateArgs.AcceptanceCriteria = &acceptanceCriteria
 				}
+				if dueDate, ok := updates["due_date"].(string); ok {
+					updateArgs.DueDate = &dueDate
+				}
+				if startDate, ok := updates["start_date"].(string); ok {
+					updateArgs.StartDate = &startDate
+				}
+				if milestone, ok := updates["milestone"].(string); ok {
+					updateArgs.Milestone = &milestone
+				}
 
 				resp, err := daemonClient.Update(updateArgs)
 				if err != nil {
@@ -441,26 +614,37 @@ var updateCmd = &cobra.Command{
 
 var editCmd = &cobra.Command{
 	Use:   "edit [id]",
-	Short: "Edit an issue field in $EDITOR",
-	Long: `Edit an issue field using your configured $EDITOR.
+	Short: "Edit an issue in $EDITOR",
+	Long: `Edit an issue using your configured $EDITOR.
 
-By default, edits the description. Use flags to edit other fields.
+By default, opens the whole issue as a document: title, status, priority,
+assignee, and labels at the top, then a markdown section each for the
+description, design, acceptance criteria, and notes. Only the fields you
+change are saved, in a single update. Use a flag to edit one field as plain
+text instead.
 
 Examples:
-  bd edit bd-42                    # Edit description
+  bd edit bd-42                    # Edit the whole issue
+  bd edit bd-42 --description      # Edit description
   bd edit bd-42 --title            # Edit title
   bd edit bd-42 --design           # Edit design notes
   bd edit bd-42 --notes            # Edit notes
-  bd edit bd-42 --acceptance       # Edit acceptance criteria`,
+  bd edit bd-42 --acceptance       # Edit acceptance criteria
+
+If someone else changes the issue while the editor is open, the edit is not
+saved. Re-run bd edit to start from their version, or use --force to save
+over it.`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		id := args[0]
 		ctx := context.Background()
 
-		// Determine which field to edit
-		fieldToEdit := "description"
+		// Determine which field to edit; without a flag, edit the whole issue
+		fieldToEdit := ""
 		if cmd.Flags().Changed("title") {
 			fieldToEdit = "title"
+		} else if cmd.Flags().Changed("description") {
+			fieldToEdit = "description"
 		} else if cmd.Flags().Changed("design") {
 			fieldToEdit = "design"
 		} else if cmd.Flags().Changed("notes") {
@@ -469,20 +653,7 @@ Examples:
 			fieldToEdit = "acceptance_criteria"
 		}
 
-		// Get the editor from environment
-		editor := os.Getenv("EDITOR")
-		if editor == "" {
-			editor = os.Getenv("VISUAL")
-		}
-		if editor == "" {
-			// Try common defaults
-			for _, defaultEditor := range []string{"vim", "vi", "nano", "emacs"} {
-				if _, err := exec.LookPath(defaultEditor); err == nil {
-					editor = defaultEditor
-					break
-				}
-			}
-		}
+		editor := findEditor()
 		if editor == "" {
 			fmt.Fprintf(os.Stderr, "Error: No editor found. Set $EDITOR or $VISUAL environment variable.\n")
 			os.Exit(1)
@@ -493,7 +664,7 @@ Examples:
 		var err error
 
 		if daemonClient != nil {
-			// Daemon mode
+			// Daemon mode; the response includes labels
 			showArgs := &rpc.ShowArgs{ID: id}
 			resp, err := daemonClient.Show(showArgs)
 			if err != nil {
@@ -517,6 +688,62 @@ Examples:
 				fmt.Fprintf(os.Stderr, "Issue %s not found\n", id)
 				os.Exit(1)
 			}
+			issue.Labels, _ = store.GetLabels(ctx, issue.ID)
+		}
+		force, _ := cmd.Flags().GetBool("force")
+		green := color.New(color.FgGreen).SprintFunc()
+
+		if fieldToEdit == "" {
+			original := formatIssueDocument(issue)
+			edited, err := editText(editor, "bd-edit-"+issue.ID+"-*.md", original)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if edited == original {
+				fmt.Println("No changes made")
+				return
+			}
+			doc, err := parseIssueDocument(edited)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			updates, addLabels, removeLabels := documentChanges(issue, doc)
+			if len(updates) == 0 && len(addLabels) == 0 && len(removeLabels) == 0 {
+				fmt.Println("No changes made")
+				return
+			}
+
+			if len(updates) > 0 {
+				applyEdit(ctx, issue, updates, force)
+			}
+			for _, label := range addLabels {
+				if err := editLabel(ctx, issue.ID, label, true); err != nil {
+					fmt.Fprintf(os.Stderr, "Error adding label %s: %v\n", label, err)
+					os.Exit(1)
+				}
+			}
+			for _, label := range removeLabels {
+				if err := editLabel(ctx, issue.ID, label, false); err != nil {
+					fmt.Fprintf(os.Stderr, "Error removing label %s: %v\n", label, err)
+					os.Exit(1)
+				}
+			}
+			if daemonClient == nil {
+				markDirtyAndScheduleFlush()
+			}
+
+			var changed []string
+			for field := range updates {
+				changed = append(changed, strings.ReplaceAll(field, "_", " "))
+			}
+			if len(addLabels) > 0 || len(removeLabels) > 0 {
+				changed = append(changed, "labels")
+			}
+			sort.Strings(changed)
+			fmt.Printf("%s Updated %s for issue: %s\n", green("✓"), strings.Join(changed, ", "), id)
+			return
 		}
 
 		// Get the current field value
@@ -534,43 +761,12 @@ Examples:
 			currentValue = issue.AcceptanceCriteria
 		}
 
-		// Create a temporary file with the current value
-		tmpFile, err := os.CreateTemp("", fmt.Sprintf("bd-edit-%s-*.txt", fieldToEdit))
+		newValue, err := editText(editor, fmt.Sprintf("bd-edit-%s-*.txt", fieldToEdit), currentValue)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error creating temp file: %v\n", err)
-			os.Exit(1)
-		}
-		tmpPath := tmpFile.Name()
-		defer os.Remove(tmpPath)
-
-		// Write current value to temp file
-		if _, err := tmpFile.WriteString(currentValue); err != nil {
-			tmpFile.Close()
-			fmt.Fprintf(os.Stderr, "Error writing to temp file: %v\n", err)
-			os.Exit(1)
-		}
-		tmpFile.Close()
-
-		// Open the editor
-		editorCmd := exec.Command(editor, tmpPath)
-		editorCmd.Stdin = os.Stdin
-		editorCmd.Stdout = os.Stdout
-		editorCmd.Stderr = os.Stderr
-
-		if err := editorCmd.Run(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error running editor: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
 
-		// Read the edited content
-		editedContent, err := os.ReadFile(tmpPath)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error reading edited file: %v\n", err)
-			os.Exit(1)
-		}
-
-		newValue := string(editedContent)
-
 		// Check if the value changed
 		if newValue == currentValue {
 			fmt.Println("No changes made")
@@ -583,46 +779,82 @@ Examples:
 			os.Exit(1)
 		}
 
-		// Update the issue
-		updates := map[string]interface{}{
-			fieldToEdit: newValue,
+		applyEdit(ctx, issue, map[string]interface{}{fieldToEdit: newValue}, force)
+		if daemonClient == nil {
+			markDirtyAndScheduleFlush()
 		}
 
-		if daemonClient != nil {
-			// Daemon mode
-			updateArgs := &rpc.UpdateArgs{ID: id}
+		fieldName := strings.ReplaceAll(fieldToEdit, "_", " ")
+		fmt.Printf("%s Updated %s for issue: %s\n", green("✓"), fieldName, id)
+	},
+}
 
-			switch fieldToEdit {
+// applyEdit saves updates to issue as one change, unless it changed while
+// the editor was open (and force is off). Exits on failure.
+func applyEdit(ctx context.Context, issue *types.Issue, updates map[string]interface{}, force bool) {
+	id := issue.ID
+	checkVersion := !force && issue.Version > 0
+
+	var err error
+	if daemonClient != nil {
+		// Daemon mode
+		updateArgs := &rpc.UpdateArgs{ID: id}
+		if checkVersion {
+			updateArgs.ExpectedVersion = &issue.Version
+		}
+		for field, value := range updates {
+			text, _ := value.(string)
+			switch field {
 			case "title":
-				updateArgs.Title = &newValue
+				updateArgs.Title = &text
 			case "description":
-				updateArgs.Description = &newValue
+				updateArgs.Description = &text
+			case "status":
+				updateArgs.Status = &text
+			case "priority":
+				priority := value.(int)
+				updateArgs.Priority = &priority
+			case "assignee":
+				updateArgs.Assignee = &text
 			case "design":
-				updateArgs.Design = &newValue
+				updateArgs.Design = &text
 			case "notes":
-				updateArgs.Notes = &newValue
+				updateArgs.Notes = &text
 			case "acceptance_criteria":
-				updateArgs.AcceptanceCriteria = &newValue
-			}
+				updateArgs.AcceptanceCriteria = &text
+			}
+		}
+		_, err = daemonClient.Update(updateArgs)
+	} else if sqliteStore, ok := store.(*sqlite.SQLiteStorage); ok && checkVersion {
+		err = sqliteStore.UpdateIssueIfVersion(ctx, id, updates, actor, issue.Version)
+	} else {
+		err = store.UpdateIssue(ctx, id, updates, actor)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error updating issue: %v\n", err)
+		var conflict *sqlite.VersionConflictError
+		if errors.As(err, &conflict) || strings.Contains(err.Error(), "has changed") {
+			fmt.Fprintf(os.Stderr, "Re-run 'bd edit %s' to edit the latest version, or use --force to overwrite it\n", id)
+		}
+		os.Exit(1)
+	}
+}
 
-			_, err := daemonClient.Update(updateArgs)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error updating issue: %v\n", err)
-				os.Exit(1)
-			}
+// editLabel adds or removes a label for bd edit
+func editLabel(ctx context.Context, id, label string, add bool) error {
+	if daemonClient != nil {
+		var err error
+		if add {
+			_, err = daemonClient.AddLabel(&rpc.LabelAddArgs{ID: id, Label: label})
 		} else {
-			// Direct mode
-			if err := store.UpdateIssue(ctx, id, updates, actor); err != nil {
-				fmt.Fprintf(os.Stderr, "Error updating issue: %v\n", err)
-				os.Exit(1)
-			}
-			markDirtyAndScheduleFlush()
-		}
-
-		green := color.New(color.FgGreen).SprintFunc()
-		fieldName := strings.ReplaceAll(fieldToEdit, "_", " ")
-		fmt.Printf("%s Updated %s for issue: %s\n", green("✓"), fieldName, id)
-	},
+			_, err = daemonClient.RemoveLabel(&rpc.LabelRemoveArgs{ID: id, Label: label})
+		}
+		return err
+	}
+	if add {
+		return store.AddLabel(ctx, id, label, actor)
+	}
+	return store.RemoveLabel(ctx, id, label, actor)
 }
 
 var closeCmd = &cobra.Command{
@@ -630,9 +862,12 @@ var closeCmd = &cobra.Command{
 	Short: "Close one or more issues",
 	Args:  cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
+		// Without --reason, no reason is recorded, so a workflow that
+		// requires one can reject the close
 		reason, _ := cmd.Flags().GetString("reason")
-		if reason == "" {
-			reason = "Closed"
+		shown := reason
+		if shown == "" {
+			shown = "Closed"
 		}
 
 		// If daemon is running, use RPC
@@ -656,7 +891,7 @@ var closeCmd = &cobra.Command{
 					}
 				} else {
 					green := color.New(color.FgGreen).SprintFunc()
-					fmt.Printf("%s Closed %s: %s\n", green("✓"), id, reason)
+					fmt.Printf("%s Closed %s: %s\n", green("✓"), id, shown)
 				}
 			}
 
@@ -681,7 +916,7 @@ var closeCmd = &cobra.Command{
 				}
 			} else {
 				green := color.New(color.FgGreen).SprintFunc()
-				fmt.Printf("%s Closed %s: %s\n", green("✓"), id, reason)
+				fmt.Printf("%s Closed %s: %s\n", green("✓"), id, shown)
 			}
 		}
 
@@ -697,6 +932,7 @@ var closeCmd = &cobra.Command{
 }
 
 func init() {
+	showCmd.Flags().String("format", "", "Output format: markdown")
 	rootCmd.AddCommand(showCmd)
 
 	updateCmd.Flags().StringP("status", "s", "", "New status")
@@ -710,13 +946,18 @@ func init() {
 	updateCmd.Flags().String("acceptance-criteria", "", "DEPRECATED: use --acceptance")
 	_ = updateCmd.Flags().MarkHidden("acceptance-criteria")
 	updateCmd.Flags().String("external-ref", "", "External reference (e.g., 'gh-9', 'jira-ABC')")
+	updateCmd.Flags().String("due", "", "Due date (YYYY-MM-DD, today, tomorrow, or +Nd/+Nw; \"\" clears it)")
+	updateCmd.Flags().String("start", "", "Start date (same formats as --due; \"\" clears it)")
+	updateCmd.Flags().String("milestone", "", "Milestone (sprint) to plan the issue for (\"\" removes it; see 'bd sprint')")
+	updateCmd.Flags().StringArray("filter", nil, "Update all issues matching key=value (repeatable, e.g. status=open)")
 	rootCmd.AddCommand(updateCmd)
 
 	editCmd.Flags().Bool("title", false, "Edit the title")
-	editCmd.Flags().Bool("description", false, "Edit the description (default)")
+	editCmd.Flags().Bool("description", false, "Edit the description")
 	editCmd.Flags().Bool("design", false, "Edit the design notes")
 	editCmd.Flags().Bool("notes", false, "Edit the notes")
 	editCmd.Flags().Bool("acceptance", false, "Edit the acceptance criteria")
+	editCmd.Flags().Bool("force", false, "Save even if the issue changed while you were editing")
 	rootCmd.AddCommand(editCmd)
 
 	closeCmd.Flags().StringP("reason", "r", "", "Reason for closing")
@@ -0,0 +1,311 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+	"github.com/spf13/cobra"
+)
+
+var sprintCmd = &cobra.Command{
+	Use:     "sprint",
+	Aliases: []string{"milestone"},
+	Short:   "Plan work in time-boxed milestones (sprints)",
+	Long: `Create milestones with a date range and a capacity, assign issues to them,
+and track committed vs. completed work.
+
+Committed work is the estimated_minutes of every issue in the milestone;
+completed work counts only the closed ones. A milestone is over capacity
+when more is committed than its capacity allows.
+
+Examples:
+  bd sprint create sprint-12 --start 2025-03-03 --end 2025-03-14 --capacity 60h
+  bd sprint add sprint-12 bd-4 bd-7 bd-9
+  bd sprint show sprint-12
+  bd sprint list
+  bd sprint remove bd-9
+  bd sprint delete sprint-12`,
+}
+
+var sprintCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a milestone",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		startFlag, _ := cmd.Flags().GetString("start")
+		endFlag, _ := cmd.Flags().GetString("end")
+		capacityFlag, _ := cmd.Flags().GetString("capacity")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		now := time.Now()
+		start, err := types.ParseDate(startFlag, now)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --start: %v\n", err)
+			os.Exit(1)
+		}
+		end, err := types.ParseDate(endFlag, now)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --end: %v\n", err)
+			os.Exit(1)
+		}
+		capacity, err := parseMinutes(capacityFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --capacity: %v\n", err)
+			os.Exit(1)
+		}
+
+		sqliteStore := requireMilestoneStore()
+		milestone := &sqlite.Milestone{Name: args[0], StartDate: start, EndDate: end, CapacityMinutes: capacity, CreatedBy: actor}
+		if err := sqliteStore.CreateMilestone(context.Background(), milestone); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			outputJSON(milestone)
+			return
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Created milestone %s: %s to %s\n", green("✓"), milestone.Name, formatDay(start), formatDay(end))
+	},
+}
+
+var sprintListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List milestones with their progress",
+	Run: func(cmd *cobra.Command, _ []string) {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		milestones, err := requireMilestoneStore().ListMilestones(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			if milestones == nil {
+				milestones = []*sqlite.Milestone{}
+			}
+			outputJSON(milestones)
+			return
+		}
+
+		if len(milestones) == 0 {
+			fmt.Println("No milestones")
+			return
+		}
+		for i, m := range milestones {
+			if i > 0 {
+				fmt.Println()
+			}
+			printMilestone(m)
+		}
+	},
+}
+
+var sprintShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Show a milestone and its issues",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		ctx := context.Background()
+		sqliteStore := requireMilestoneStore()
+		milestone := getMilestoneArg(ctx, sqliteStore, args[0])
+
+		issues, err := sqliteStore.SearchIssues(ctx, "", types.IssueFilter{Milestone: &milestone.Name})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			if issues == nil {
+				issues = []*types.Issue{}
+			}
+			outputJSON(map[string]interface{}{"milestone": milestone, "issues": issues})
+			return
+		}
+
+		printMilestone(milestone)
+		if len(issues) == 0 {
+			fmt.Println("\nNo issues assigned")
+			return
+		}
+		fmt.Println()
+		for _, issue := range issues {
+			estimate := "unestimated"
+			if issue.EstimatedMinutes != nil {
+				estimate = formatMinutes(*issue.EstimatedMinutes)
+			}
+			fmt.Printf("  %s [P%d] %s (%s, %s)\n", issue.ID, issue.Priority, issue.Title, issue.Status, estimate)
+		}
+	},
+}
+
+var sprintAddCmd = &cobra.Command{
+	Use:   "add <name> <id...>",
+	Short: "Assign issues to a milestone",
+	Long:  `Assign issues to a milestone, moving them out of any milestone they were in.`,
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(_ *cobra.Command, args []string) {
+		ctx := context.Background()
+		sqliteStore := requireMilestoneStore()
+		if err := sqliteStore.AssignMilestone(ctx, args[0], args[1:], actor); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		markDirtyAndScheduleFlush()
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Added %d issue(s) to %s\n", green("✓"), len(args)-1, args[0])
+		if milestone, err := sqliteStore.GetMilestone(ctx, args[0]); err == nil && milestone != nil {
+			printMilestoneProgress(milestone)
+		}
+	},
+}
+
+var sprintRemoveCmd = &cobra.Command{
+	Use:   "remove <id...>",
+	Short: "Take issues out of their milestone",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		if err := requireMilestoneStore().AssignMilestone(context.Background(), "", args, actor); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		markDirtyAndScheduleFlush()
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Removed %d issue(s) from their milestone\n", green("✓"), len(args))
+	},
+}
+
+var sprintDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a milestone",
+	Long:  `Delete a milestone. Its issues are taken out of it, not deleted.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		ctx := context.Background()
+		sqliteStore := requireMilestoneStore()
+		milestone := getMilestoneArg(ctx, sqliteStore, args[0])
+
+		if err := sqliteStore.DeleteMilestone(ctx, milestone.Name, actor); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if milestone.Issues > 0 {
+			markDirtyAndScheduleFlush()
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Deleted milestone %s (%d issue(s) unassigned)\n", green("✓"), milestone.Name, milestone.Issues)
+	},
+}
+
+// printMilestone prints a milestone's dates and progress for bd sprint
+func printMilestone(m *sqlite.Milestone) {
+	cyan := color.New(color.FgCyan).SprintFunc()
+	active := ""
+	if m.Active(time.Now()) {
+		active = color.New(color.FgGreen).Sprint(" (active)")
+	}
+	fmt.Printf("%s  %s to %s%s\n", cyan(m.Name), formatDay(m.StartDate), formatDay(m.EndDate), active)
+	printMilestoneProgress(m)
+}
+
+// printMilestoneProgress prints committed and completed work against capacity
+func printMilestoneProgress(m *sqlite.Milestone) {
+	fmt.Printf("  Issues:    %d/%d closed\n", m.ClosedIssues, m.Issues)
+	capacity := "no capacity set"
+	if m.CapacityMinutes > 0 {
+		capacity = formatMinutes(m.CapacityMinutes)
+	}
+	fmt.Printf("  Capacity:  %s\n", capacity)
+
+	committed := formatMinutes(m.CommittedMinutes)
+	if m.OverCapacity() {
+		committed += color.New(color.FgRed).Sprintf(" (over by %s)", formatMinutes(m.CommittedMinutes-m.CapacityMinutes))
+	}
+	fmt.Printf("  Committed: %s\n", committed)
+	fmt.Printf("  Completed: %s\n", formatMinutes(m.CompletedMinutes))
+	if m.Unestimated > 0 {
+		fmt.Printf("  %d issue(s) have no estimate\n", m.Unestimated)
+	}
+}
+
+// formatDay renders a date without its time of day
+func formatDay(t time.Time) string {
+	return t.Local().Format("2006-01-02")
+}
+
+// parseMinutes reads a length of time given in minutes ("600") or as a
+// duration ("40h", "1h30m")
+func parseMinutes(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if mins, err := strconv.Atoi(s); err == nil {
+		if mins < 0 {
+			return 0, fmt.Errorf("time cannot be negative")
+		}
+		return mins, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid time %q (use minutes or a duration like 1h30m)", s)
+	}
+	return int(d.Minutes()), nil
+}
+
+func requireMilestoneStore() *sqlite.SQLiteStorage {
+	if err := ensureDirectMode("daemon does not support sprint command"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: sprint command requires SQLite backend\n")
+		os.Exit(1)
+	}
+	return sqliteStore
+}
+
+// getMilestoneArg loads the milestone named by a command argument or exits
+func getMilestoneArg(ctx context.Context, sqliteStore *sqlite.SQLiteStorage, name string) *sqlite.Milestone {
+	milestone, err := sqliteStore.GetMilestone(ctx, name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if milestone == nil {
+		fmt.Fprintf(os.Stderr, "Error: milestone %s not found\n", name)
+		os.Exit(1)
+	}
+	return milestone
+}
+
+func init() {
+	sprintCreateCmd.Flags().String("start", "today", "First day (YYYY-MM-DD, today, tomorrow, or +Nd/+Nw)")
+	sprintCreateCmd.Flags().String("end", "", "Last day (same formats as --start)")
+	sprintCreateCmd.Flags().String("capacity", "", "Work the milestone can hold, in minutes or as a duration (e.g. 40h)")
+	_ = sprintCreateCmd.MarkFlagRequired("end")
+	sprintCreateCmd.Flags().Bool("json", false, "Output JSON format")
+	sprintListCmd.Flags().Bool("json", false, "Output JSON format")
+	sprintShowCmd.Flags().Bool("json", false, "Output JSON format")
+
+	sprintCmd.AddCommand(sprintCreateCmd)
+	sprintCmd.AddCommand(sprintListCmd)
+	sprintCmd.AddCommand(sprintShowCmd)
+	sprintCmd.AddCommand(sprintAddCmd)
+	sprintCmd.AddCommand(sprintRemoveCmd)
+	sprintCmd.AddCommand(sprintDeleteCmd)
+	rootCmd.AddCommand(sprintCmd)
+}
@@ -8,8 +8,11 @@ import (
 	"time"
 
 	"github.com/fatih/color"
-	"github.com/spf13/cobra"
+	"github.com/imalsogreg/beads/internal/digest"
+	"github.com/imalsogreg/beads/internal/stale"
+	"github.com/imalsogreg/beads/internal/storage"
 	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/spf13/cobra"
 )
 
 // StaleIssueInfo contains information about an orphaned issue claim
@@ -37,10 +40,29 @@ An issue is considered stale if:
   - AND the executor status is 'stopped'
   - OR the executor's last_heartbeat is older than the threshold
 
-Default threshold: 300 seconds (5 minutes)`,
+Default threshold: 300 seconds (5 minutes)
+
+With --idle, shows in_progress issues with no updates in stale.days days
+instead (default 7, or --days). --nudge applies the staleness policy to them:
+
+  bd config set stale.days 14
+  bd config set stale.action comment   # comment (default), label, or reopen
+  bd config set stale.label stale      # label added by the label action
+  bd config set stale.nudge true       # nudge from the daemon once a day
+
+Each issue is nudged once per quiet spell. Assignees with a digest
+subscription are emailed when SMTP is configured (see 'bd digest --help').`,
 	Run: func(cmd *cobra.Command, args []string) {
 		threshold, _ := cmd.Flags().GetInt("threshold")
 		release, _ := cmd.Flags().GetBool("release")
+		idle, _ := cmd.Flags().GetBool("idle")
+		days, _ := cmd.Flags().GetInt("days")
+		nudge, _ := cmd.Flags().GetBool("nudge")
+
+		if idle || days > 0 || nudge {
+			runIdleStale(days, nudge)
+			return
+		}
 
 		// Get stale issues
 		staleIssues, err := getStaleIssues(threshold)
@@ -278,6 +300,111 @@ func releaseStaleIssues(staleIssues []*StaleIssueInfo) (int, error) {
 	return releaseCount, nil
 }
 
+// runIdleStale lists in_progress issues that have gone quiet and, with
+// nudge, applies the staleness policy to them
+func runIdleStale(days int, nudge bool) {
+	if err := ensureDirectMode("daemon does not support stale command"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	ctx := context.Background()
+	policy, err := stale.LoadPolicy(ctx, store)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if days > 0 {
+		policy.Days = days
+	}
+
+	var issues []*stale.Issue
+	if nudge {
+		sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: stale --nudge requires SQLite backend\n")
+			os.Exit(1)
+		}
+		issues, err = stale.Nudge(ctx, sqliteStore, policy, actor, time.Now(), staleNotifier(ctx, sqliteStore))
+		if len(issues) > 0 {
+			markDirtyAndScheduleFlush()
+		}
+	} else {
+		issues, err = stale.Find(ctx, store, policy.After(), time.Now())
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		if !nudge || len(issues) == 0 {
+			os.Exit(1)
+		}
+	}
+
+	if jsonOutput {
+		if issues == nil {
+			issues = []*stale.Issue{}
+		}
+		outputJSON(issues)
+		return
+	}
+
+	if len(issues) == 0 {
+		green := color.New(color.FgGreen).SprintFunc()
+		if nudge {
+			fmt.Printf("\n%s No stale issues to nudge\n\n", green("✨"))
+		} else {
+			fmt.Printf("\n%s No in-progress issues idle for %d days or more\n\n", green("✨"), policy.Days)
+		}
+		return
+	}
+
+	yellow := color.New(color.FgYellow).SprintFunc()
+	if nudge {
+		fmt.Printf("\n%s Nudged %d stale issue(s) (%s):\n\n", yellow("⚠️"), len(issues), policy.Action)
+	} else {
+		fmt.Printf("\n%s Found %d in-progress issue(s) idle for %d days or more:\n\n", yellow("⚠️"), len(issues), policy.Days)
+	}
+	for i, issue := range issues {
+		assignee := issue.Assignee
+		if assignee == "" {
+			assignee = "unassigned"
+		}
+		fmt.Printf("%d. [P%d] %s: %s\n", i+1, issue.Priority, issue.ID, issue.Title)
+		fmt.Printf("   %s, no updates in %d days\n", assignee, issue.IdleDays)
+	}
+	fmt.Println()
+}
+
+// staleNotifier mails assignees about nudged issues, or returns nil if SMTP
+// isn't configured
+func staleNotifier(ctx context.Context, sqliteStore *sqlite.SQLiteStorage) stale.Notifier {
+	cfg, err := digest.LoadSMTPConfig(ctx, sqliteStore)
+	if err != nil {
+		return nil
+	}
+	prefix, _ := sqliteStore.GetConfig(ctx, "issue_prefix")
+	return stale.MailNotifier(sqliteStore, &digest.SMTPMailer{Config: cfg}, prefix)
+}
+
+// runScheduledStaleNudge nudges stale issues from the daemon scheduler
+func runScheduledStaleNudge(ctx context.Context, store storage.Storage, log daemonLogger) error {
+	sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+	if !ok {
+		return fmt.Errorf("stale nudges require SQLite backend")
+	}
+	policy, err := stale.LoadPolicy(ctx, sqliteStore)
+	if err != nil {
+		return err
+	}
+	notify := staleNotifier(ctx, sqliteStore)
+	if notify == nil {
+		log.log("Stale: SMTP not configured, nudging without email")
+	}
+	issues, err := stale.Nudge(ctx, sqliteStore, policy, "system", time.Now(), notify)
+	for _, issue := range issues {
+		log.log("Stale: nudged %s (%s, no updates in %d days)", issue.ID, policy.Action, issue.IdleDays)
+	}
+	return err
+}
+
 // formatDuration formats a duration in a human-readable way
 func formatDuration(d time.Duration) string {
 	if d < time.Minute {
@@ -295,6 +422,10 @@ func formatDuration(d time.Duration) string {
 func init() {
 	staleCmd.Flags().IntP("threshold", "t", 300, "Heartbeat threshold in seconds (default: 300 = 5 minutes)")
 	staleCmd.Flags().BoolP("release", "r", false, "Automatically release all stale issues")
+	staleCmd.Flags().Bool("idle", false, "Show in_progress issues with no recent updates instead of orphaned claims")
+	staleCmd.Flags().Int("days", 0, "Days without updates before an issue is stale (implies --idle; default stale.days)")
+	staleCmd.Flags().Bool("nudge", false, "Apply stale.action to idle issues and notify their assignees (implies --idle)")
+	staleCmd.Flags().BoolVar(&jsonOutput, "json", false, "Output JSON format")
 
 	rootCmd.AddCommand(staleCmd)
 }
@@ -0,0 +1,526 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/imalsogreg/beads/internal/importer"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+	"github.com/spf13/cobra"
+)
+
+var syncFileCmd = &cobra.Command{
+	Use:   "file",
+	Short: "Write the canonical issues.jsonl for git, or apply it to the database",
+	Long: `Round-trip the database through .beads/issues.jsonl, treating the file as the
+copy that lives in git.
+
+Without flags, writes every issue to the file in canonical form: sorted by ID,
+with labels, dependencies, and comments sorted, timestamps in UTC, and fields
+that differ between clones (like local revision numbers and comment row IDs)
+left out. The same database always produces the same bytes, so the file only
+changes when issues do and diffs stay small. Encrypted fields are only
+re-encrypted when their content changes.
+
+With --apply, merges the file into the database, e.g. after a git pull. Issues
+in the file are created or updated according to --strategy (by default the
+newer of the two versions wins), and for issues the file wins, labels and
+dependencies are made to match it exactly. With --prune, issues missing from
+the file are moved to the trash, unless they have local changes that were
+never written to it. The file is then rewritten from the database, so any
+local changes that won show up in git.
+
+Other commands auto-import the file when it changes, and treat an issue that
+differs from the database as a collision to rename. When the file is the
+source of truth, set no-auto-import: true in .beads/config.yaml and run
+'bd sync file --apply' after pulling instead.
+
+Use --check in CI or a pre-commit hook to fail when the file is out of date.
+
+Examples:
+  bd sync file
+  bd sync file --check
+  git pull && bd sync file --apply --prune
+  bd sync file --apply --strategy overwrite --dry-run`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		apply, _ := cmd.Flags().GetBool("apply")
+		check, _ := cmd.Flags().GetBool("check")
+		prune, _ := cmd.Flags().GetBool("prune")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		strategyName, _ := cmd.Flags().GetString("strategy")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		if apply && check {
+			fmt.Fprintf(os.Stderr, "Error: --check can't be combined with --apply\n")
+			os.Exit(1)
+		}
+		if prune && !apply {
+			fmt.Fprintf(os.Stderr, "Error: --prune requires --apply\n")
+			os.Exit(1)
+		}
+		strategy, err := importer.ParseStrategy(strategyName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		ctx := context.Background()
+		st := requireSyncFileStore()
+		jsonlPath := findJSONLPath()
+
+		fileIssues, previous, current, err := readJSONLFile(jsonlPath)
+		if err != nil && !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if apply && os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Error: %s does not exist\n", jsonlPath)
+			os.Exit(1)
+		}
+
+		result := map[string]interface{}{"path": jsonlPath, "dry_run": dryRun}
+		if apply {
+			merged, pruned, err := applyJSONLFile(ctx, st, fileIssues, strategy, prune, dryRun)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: failed to apply %s: %v\n", jsonlPath, err)
+				os.Exit(1)
+			}
+			result["apply"] = merged
+			result["pruned"] = pruned
+			if !jsonOutput {
+				printSyncFileApply(jsonlPath, merged, pruned, dryRun)
+			}
+			if dryRun {
+				if jsonOutput {
+					outputJSON(result)
+				}
+				return
+			}
+		}
+
+		issues, err := canonicalIssues(ctx, st)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		data, err := encodeCanonicalJSONL(st, issues, previous)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		changed := !bytes.Equal(data, current)
+		result["issues"] = len(issues)
+		result["changed"] = changed
+
+		green := color.New(color.FgGreen).SprintFunc()
+		yellow := color.New(color.FgYellow).SprintFunc()
+
+		if check {
+			if jsonOutput {
+				outputJSON(result)
+			} else if changed {
+				fmt.Printf("%s %s is out of date; run 'bd sync file'\n", yellow("!"), jsonlPath)
+			} else {
+				fmt.Printf("%s %s is up to date (%d issues)\n", green("✓"), jsonlPath, len(issues))
+			}
+			if changed {
+				os.Exit(1)
+			}
+			return
+		}
+		if dryRun {
+			if jsonOutput {
+				outputJSON(result)
+			} else if changed {
+				fmt.Printf("DRY RUN: Would write %d issues to %s\n", len(issues), jsonlPath)
+			} else {
+				fmt.Printf("DRY RUN: %s is already up to date (%d issues)\n", jsonlPath, len(issues))
+			}
+			return
+		}
+
+		if changed {
+			if err := writeFileAtomic(jsonlPath, data); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		finishSyncFileWrite(ctx, st, issues, data)
+
+		if jsonOutput {
+			outputJSON(result)
+			return
+		}
+		if changed {
+			fmt.Printf("%s Wrote %d issues to %s\n", green("✓"), len(issues), jsonlPath)
+		} else {
+			fmt.Printf("%s %s is up to date (%d issues)\n", green("✓"), jsonlPath, len(issues))
+		}
+	},
+}
+
+func requireSyncFileStore() *sqlite.SQLiteStorage {
+	if err := ensureDirectMode("daemon does not support sync file"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: sync file requires SQLite backend\n")
+		os.Exit(1)
+	}
+	return sqliteStore
+}
+
+// canonicalIssue returns a copy of issue in the form the committed file
+// holds: relations sorted, times in UTC, and per-clone and derived fields
+// cleared
+func canonicalIssue(issue *types.Issue) *types.Issue {
+	out := *issue
+	out.Version = 0
+	out.ParentID = "" // carried by the parent-child dependency
+	out.Subtasks = nil
+	out.Links = nil
+	out.Watchers = nil
+	out.CreatedAt = out.CreatedAt.UTC()
+	out.UpdatedAt = out.UpdatedAt.UTC()
+	for _, t := range []**time.Time{&out.ClosedAt, &out.CompactedAt, &out.DeletedAt, &out.DueDate, &out.StartDate} {
+		if *t != nil {
+			utc := (*t).UTC()
+			*t = &utc
+		}
+	}
+
+	out.Labels = append([]string(nil), issue.Labels...)
+	sort.Strings(out.Labels)
+
+	out.Dependencies = make([]*types.Dependency, len(issue.Dependencies))
+	for i, dep := range issue.Dependencies {
+		d := *dep
+		d.CreatedAt = d.CreatedAt.UTC()
+		out.Dependencies[i] = &d
+	}
+	sort.Slice(out.Dependencies, func(i, j int) bool {
+		a, b := out.Dependencies[i], out.Dependencies[j]
+		if a.DependsOnID != b.DependsOnID {
+			return a.DependsOnID < b.DependsOnID
+		}
+		return a.Type < b.Type
+	})
+
+	// Comment IDs, and the replies that refer to them, are row numbers in
+	// each clone's database
+	out.Comments = make([]*types.Comment, len(issue.Comments))
+	for i, c := range issue.Comments {
+		cc := *c
+		cc.ID = 0
+		cc.ReplyTo = nil
+		cc.CreatedAt = cc.CreatedAt.UTC()
+		if cc.UpdatedAt != nil {
+			utc := cc.UpdatedAt.UTC()
+			cc.UpdatedAt = &utc
+		}
+		out.Comments[i] = &cc
+	}
+	sort.SliceStable(out.Comments, func(i, j int) bool {
+		a, b := out.Comments[i], out.Comments[j]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		if a.Author != b.Author {
+			return a.Author < b.Author
+		}
+		return a.Text < b.Text
+	})
+
+	if len(out.Labels) == 0 {
+		out.Labels = nil
+	}
+	if len(out.Dependencies) == 0 {
+		out.Dependencies = nil
+	}
+	if len(out.Comments) == 0 {
+		out.Comments = nil
+	}
+	return &out
+}
+
+// canonicalIssues loads every live issue with its labels, dependencies, and
+// comments, in canonical form and sorted by ID
+func canonicalIssues(ctx context.Context, st *sqlite.SQLiteStorage) ([]*types.Issue, error) {
+	issues, err := st.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issues: %w", err)
+	}
+	allDeps, err := st.GetAllDependencyRecords(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dependencies: %w", err)
+	}
+
+	canonical := make([]*types.Issue, 0, len(issues))
+	for _, issue := range issues {
+		issue.Dependencies = allDeps[issue.ID]
+		if issue.Labels, err = st.GetLabels(ctx, issue.ID); err != nil {
+			return nil, fmt.Errorf("failed to get labels for %s: %w", issue.ID, err)
+		}
+		if issue.Comments, err = st.GetIssueComments(ctx, issue.ID); err != nil {
+			return nil, fmt.Errorf("failed to get comments for %s: %w", issue.ID, err)
+		}
+		canonical = append(canonical, canonicalIssue(issue))
+	}
+	sort.Slice(canonical, func(i, j int) bool { return canonical[i].ID < canonical[j].ID })
+	return canonical, nil
+}
+
+// encodeCanonicalJSONL renders issues as the committed file. previous holds
+// the current file's lines by issue ID; a line is kept when it already holds
+// the same issue, so encrypted fields (which encrypt differently each time)
+// only change when their content does.
+func encodeCanonicalJSONL(st *sqlite.SQLiteStorage, issues []*types.Issue, previous map[string][]byte) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, issue := range issues {
+		exported, err := exportableIssue(st, issue)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt issue %s: %w", issue.ID, err)
+		}
+		line, err := json.Marshal(exported)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode issue %s: %w", issue.ID, err)
+		}
+		if exported != issue {
+			line = reuseEncryptedLine(st, issue, line, previous[issue.ID])
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// reuseEncryptedLine returns the previous line for an issue in place of its
+// freshly encrypted one when both hold the same content
+func reuseEncryptedLine(st *sqlite.SQLiteStorage, issue *types.Issue, line, previous []byte) []byte {
+	var old types.Issue
+	if previous == nil || json.Unmarshal(previous, &old) != nil {
+		return line
+	}
+	was, err1 := json.Marshal(canonicalIssue(st.DecryptFromExport(&old)))
+	now, err2 := json.Marshal(issue)
+	if err1 != nil || err2 != nil || !bytes.Equal(was, now) {
+		return line
+	}
+	return previous
+}
+
+// readJSONLFile reads a JSONL file, returning its issues, each issue's line
+// by ID, and the raw contents. Unlike auto-import it refuses files with merge
+// conflict markers or duplicate IDs rather than guessing.
+func readJSONLFile(path string) ([]*types.Issue, map[string][]byte, []byte, error) {
+	// #nosec G304 - controlled path from config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var issues []*types.Issue
+	lines := make(map[string][]byte)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 1024), 2*1024*1024) // 2MB buffer for large JSON lines
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if bytes.HasPrefix(line, []byte("<<<<<<< ")) || bytes.Equal(line, []byte("=======")) || bytes.HasPrefix(line, []byte(">>>>>>> ")) {
+			return nil, nil, nil, fmt.Errorf("%s has unresolved merge conflicts (line %d)", path, lineNum)
+		}
+		var issue types.Issue
+		if err := json.Unmarshal(line, &issue); err != nil {
+			return nil, nil, nil, fmt.Errorf("%s line %d: %w", path, lineNum, err)
+		}
+		if _, dup := lines[issue.ID]; dup {
+			return nil, nil, nil, fmt.Errorf("%s line %d: duplicate issue %s", path, lineNum, issue.ID)
+		}
+		lines[issue.ID] = append([]byte(nil), line...)
+		issues = append(issues, &issue)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return issues, lines, data, nil
+}
+
+// applyJSONLFile merges the file's issues into the database with strategy.
+// Issues with changes never written to the file keep their local labels and
+// dependencies and are never pruned, unless the strategy is overwrite.
+func applyJSONLFile(ctx context.Context, st *sqlite.SQLiteStorage, fileIssues []*types.Issue, strategy importer.Strategy, prune, dryRun bool) (*importer.MergeResult, []string, error) {
+	for _, issue := range fileIssues {
+		if issue.ID == "" {
+			return nil, nil, fmt.Errorf("issue without an ID")
+		}
+		issue.DeletedAt = nil
+		issue.DeletedBy = ""
+	}
+
+	dirtyIDs, err := st.GetDirtyIssues(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get unexported changes: %w", err)
+	}
+	dirty := make(map[string]bool, len(dirtyIDs))
+	for _, id := range dirtyIDs {
+		dirty[id] = true
+	}
+
+	var pruned []string
+	if prune {
+		inFile := make(map[string]bool, len(fileIssues))
+		for _, issue := range fileIssues {
+			inFile[issue.ID] = true
+		}
+		existing, err := st.SearchIssues(ctx, "", types.IssueFilter{})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get issues: %w", err)
+		}
+		for _, issue := range existing {
+			if !inFile[issue.ID] && (!dirty[issue.ID] || strategy == importer.StrategyOverwrite) {
+				pruned = append(pruned, issue.ID)
+			}
+		}
+		sort.Strings(pruned)
+	}
+
+	result, err := importer.MergeIssues(ctx, st, fileIssues, strategy, importer.Options{DryRun: dryRun})
+	if err != nil {
+		return nil, nil, err
+	}
+	if dryRun {
+		return result, pruned, nil
+	}
+
+	won := make(map[string]bool)
+	updated := make(map[string]bool)
+	for _, change := range result.Changes {
+		if change.Action != importer.ActionSkip && (!dirty[change.ID] || strategy == importer.StrategyOverwrite) {
+			won[change.ID] = true
+		}
+		updated[change.ID] = change.Action == importer.ActionUpdate
+	}
+	var replace []*types.Issue
+	for _, issue := range fileIssues {
+		if won[issue.ID] {
+			replace = append(replace, issue)
+		}
+	}
+	if err := importer.ReplaceRelations(ctx, st, replace, importer.Options{}); err != nil {
+		return nil, nil, err
+	}
+	// Updated issues keep the file's times, so rewriting the file doesn't
+	// change it
+	for _, issue := range fileIssues {
+		if updated[issue.ID] {
+			if err := st.SetIssueTimes(ctx, issue.ID, issue.UpdatedAt, issue.ClosedAt); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	for _, id := range pruned {
+		if err := st.SoftDeleteIssue(ctx, id, actor); err != nil {
+			return nil, nil, fmt.Errorf("failed to move %s to the trash: %w", id, err)
+		}
+	}
+	return result, pruned, nil
+}
+
+// finishSyncFileWrite records that the database and the file agree, so
+// neither auto-flush nor auto-import touches the file afterwards
+func finishSyncFileWrite(ctx context.Context, st *sqlite.SQLiteStorage, issues []*types.Issue, data []byte) {
+	ids := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		ids = append(ids, issue.ID)
+	}
+	// Trashed issues are dirty too, and their removal is now written
+	if dirtyIDs, err := st.GetDirtyIssues(ctx); err == nil {
+		ids = append(ids, dirtyIDs...)
+	}
+	if err := st.ClearDirtyIssuesByID(ctx, ids); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to clear dirty flags: %v\n", err)
+	}
+
+	hash := sha256.Sum256(data)
+	if err := st.SetMetadata(ctx, "last_import_hash", hex.EncodeToString(hash[:])); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to update last_import_hash: %v\n", err)
+	}
+	clearAutoFlushState()
+}
+
+// writeFileAtomic replaces path with data through a temp file and rename
+func writeFileAtomic(path string, data []byte) error {
+	tempFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp.*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tempPath := tempFile.Name()
+	defer func() { _ = os.Remove(tempPath) }()
+
+	if _, err := tempFile.Write(data); err != nil {
+		_ = tempFile.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tempFile.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	// The file is meant to be committed, so it's readable like other sources
+	if err := os.Chmod(tempPath, 0644); err != nil {
+		return fmt.Errorf("failed to set permissions on %s: %w", path, err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
+
+func printSyncFileApply(jsonlPath string, result *importer.MergeResult, pruned []string, dryRun bool) {
+	green := color.New(color.FgGreen).SprintFunc()
+	verb := "Applied"
+	if dryRun {
+		verb = "DRY RUN: Would apply"
+	}
+	fmt.Printf("%s %s %s: %d created, %d updated, %d unchanged, %d skipped\n",
+		green("✓"), verb, jsonlPath, result.Created, result.Updated, result.Unchanged, result.Skipped)
+	for _, change := range result.Changes {
+		if change.Action == importer.ActionSkip {
+			fmt.Printf("  skipped %s: %s\n", change.ID, change.Reason)
+		}
+	}
+	if len(pruned) > 0 {
+		action := "Moved to the trash"
+		if dryRun {
+			action = "Would move to the trash"
+		}
+		fmt.Printf("  %s (not in the file): %v\n", action, pruned)
+	}
+}
+
+func init() {
+	syncFileCmd.Flags().Bool("apply", false, "Merge the file into the database instead of writing it")
+	syncFileCmd.Flags().String("strategy", string(importer.StrategyMergeNewer), "With --apply, how to treat issues that already exist (skip-existing, overwrite, merge-newer)")
+	syncFileCmd.Flags().Bool("prune", false, "With --apply, move issues missing from the file to the trash")
+	syncFileCmd.Flags().Bool("check", false, "Exit with status 1 if the file is out of date, without writing it")
+	syncFileCmd.Flags().Bool("dry-run", false, "Preview changes without applying them")
+	syncFileCmd.Flags().Bool("json", false, "Output JSON format")
+	syncCmd.AddCommand(syncFileCmd)
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/importer"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestSyncFileRoundTrip(t *testing.T) {
+	tmpDir := t.TempDir()
+	s := newTestStore(t, filepath.Join(tmpDir, ".beads", "test.db"))
+	jsonlPath := filepath.Join(tmpDir, ".beads", "issues.jsonl")
+	ctx := context.Background()
+
+	first := &types.Issue{Title: "First", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	second := &types.Issue{Title: "Second", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeBug}
+	for _, issue := range []*types.Issue{first, second} {
+		if err := s.CreateIssue(ctx, issue, "test-user"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, label := range []string{"zeta", "alpha"} {
+		if err := s.AddLabel(ctx, second.ID, label, "test-user"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.AddDependency(ctx, &types.Dependency{IssueID: second.ID, DependsOnID: first.ID, Type: types.DepBlocks}, "test-user"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.AddIssueComment(ctx, second.ID, "test-user", "Looking into it"); err != nil {
+		t.Fatal(err)
+	}
+
+	write := func() []byte {
+		t.Helper()
+		_, previous, _, err := readJSONLFile(jsonlPath)
+		if err != nil && !os.IsNotExist(err) {
+			t.Fatal(err)
+		}
+		issues, err := canonicalIssues(ctx, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := encodeCanonicalJSONL(s, issues, previous)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := writeFileAtomic(jsonlPath, data); err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	data := write()
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], `{"id":"test-1"`) || !strings.HasPrefix(lines[1], `{"id":"test-2"`) {
+		t.Fatalf("Expected issues sorted by ID, got:\n%s", data)
+	}
+	if !strings.Contains(lines[1], `"labels":["alpha","zeta"]`) || !strings.Contains(lines[1], `"comments":[{"id":0,`) {
+		t.Errorf("Expected sorted labels and comments without row IDs, got %s", lines[1])
+	}
+	if again := write(); !bytes.Equal(again, data) {
+		t.Errorf("Expected the same bytes from the same database, got:\n%s\nthen:\n%s", data, again)
+	}
+
+	// A teammate's edit: a newer title, one label fewer, a new issue, and
+	// the first issue deleted
+	fileIssues, _, _, err := readJSONLFile(jsonlPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edited := fileIssues[1]
+	edited.Title = "Second (edited)"
+	edited.UpdatedAt = time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	edited.Labels = []string{"alpha"}
+	edited.Dependencies = nil
+	added := &types.Issue{ID: "test-9", Title: "Third", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, CreatedAt: edited.UpdatedAt, UpdatedAt: edited.UpdatedAt}
+	pulled, err := encodeCanonicalJSONL(s, []*types.Issue{canonicalIssue(edited), canonicalIssue(added)}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(jsonlPath, pulled, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ClearDirtyIssues(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	fileIssues, _, _, err = readJSONLFile(jsonlPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, pruned, err := applyJSONLFile(ctx, s, fileIssues, importer.StrategyMergeNewer, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Created != 1 || result.Updated != 1 || len(pruned) != 1 || pruned[0] != first.ID {
+		t.Errorf("Unexpected apply result: %+v, pruned %v", result, pruned)
+	}
+	if labels, _ := s.GetLabels(ctx, second.ID); len(labels) != 1 || labels[0] != "alpha" {
+		t.Errorf("Expected labels to match the file, got %v", labels)
+	}
+	if deps, _ := s.GetDependencyRecords(ctx, second.ID); len(deps) != 0 {
+		t.Errorf("Expected the dependency removed, got %v", deps)
+	}
+
+	// Writing the applied database back reproduces the pulled file
+	if rewritten := write(); !bytes.Equal(rewritten, pulled) {
+		t.Errorf("Expected the pulled file back, got:\n%s\nwant:\n%s", rewritten, pulled)
+	}
+}
+
+func TestReadJSONLFileRejectsConflicts(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"conflict":  "<<<<<<< HEAD\n{\"id\":\"bd-1\"}\n=======\n{\"id\":\"bd-1\"}\n>>>>>>> theirs\n",
+		"duplicate": "{\"id\":\"bd-1\"}\n{\"id\":\"bd-1\"}\n",
+	} {
+		path := filepath.Join(dir, name+".jsonl")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, _, err := readJSONLFile(path); err == nil {
+			t.Errorf("Expected %s file to be rejected", name)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/imalsogreg/beads/internal/integrations/github"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/spf13/cobra"
+)
+
+var syncGithubCmd = &cobra.Command{
+	Use:   "github",
+	Short: "Two-way sync with GitHub Issues",
+	Long: `Synchronize issues with a GitHub repository's issues.
+
+Titles, descriptions, labels, the assignee, and open/closed state are synced
+in both directions. The first run imports every GitHub issue (pull requests
+are skipped); later runs fetch only issues updated since the previous sync.
+Linked issues get external_ref gh-<number>.
+
+When an issue changed on both sides since the last sync, whichever side was
+updated more recently wins. Local statuses other than closed (in_progress,
+blocked) show as open on GitHub and are kept unless GitHub closes the issue.
+
+Local issues that aren't linked stay local unless --create is given, which
+opens a GitHub issue for each open one.
+
+The repository defaults to the github.repo config value and the token to
+$GITHUB_TOKEN.
+
+Examples:
+  bd config set github.repo acme/widgets
+  bd sync github --dry-run
+  bd sync github
+  bd sync github --import-only
+  bd sync github --repo acme/widgets --create`,
+	Run: func(cmd *cobra.Command, _ []string) {
+		repo, _ := cmd.Flags().GetString("repo")
+		token, _ := cmd.Flags().GetString("token")
+		apiURL, _ := cmd.Flags().GetString("api-url")
+		importOnly, _ := cmd.Flags().GetBool("import-only")
+		create, _ := cmd.Flags().GetBool("create")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		if err := ensureDirectMode("daemon does not support sync github command"); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: sync github command requires SQLite backend\n")
+			os.Exit(1)
+		}
+		ctx := context.Background()
+
+		if repo == "" {
+			repo, _ = sqliteStore.GetConfig(ctx, "github.repo")
+		}
+		if repo == "" {
+			fmt.Fprintf(os.Stderr, "Error: no repository given (use --repo or 'bd config set github.repo owner/name')\n")
+			os.Exit(1)
+		}
+		if token == "" {
+			token = os.Getenv("GITHUB_TOKEN")
+		}
+
+		client, err := github.NewClient(repo, token)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if apiURL != "" {
+			client.BaseURL = apiURL
+		}
+
+		result, err := github.NewSyncer(client, sqliteStore).Sync(ctx, github.Options{
+			Actor:        actor,
+			ImportOnly:   importOnly,
+			CreateRemote: create,
+			DryRun:       dryRun,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if !dryRun && len(result.Changes) > 0 {
+			markDirtyAndScheduleFlush()
+		}
+
+		if jsonOutput {
+			outputJSON(result)
+			return
+		}
+
+		if len(result.Changes) == 0 {
+			fmt.Printf("Already in sync with %s\n", repo)
+			return
+		}
+		prefix := ""
+		if dryRun {
+			prefix = "[DRY RUN] "
+		}
+		yellow := color.New(color.FgYellow).SprintFunc()
+		for _, c := range result.Changes {
+			local, remote := c.IssueID, fmt.Sprintf("#%d", c.Number)
+			if local == "" {
+				local = "(new)"
+			}
+			if c.Number == 0 {
+				remote = "(new)"
+			}
+			conflict := ""
+			if c.Conflict {
+				conflict = yellow(" (conflict, newer side kept)")
+			}
+			fmt.Printf("%s%-8s %s ↔ %s  %s%s\n", prefix, c.Action, local, remote, c.Title, conflict)
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		verb := "Synced"
+		if dryRun {
+			verb = "Would sync"
+		}
+		fmt.Printf("%s %s %d issue(s) with %s\n", green("✓"), verb, len(result.Changes), repo)
+	},
+}
+
+func init() {
+	syncGithubCmd.Flags().String("repo", "", "GitHub repository as owner/name (default: github.repo config)")
+	syncGithubCmd.Flags().String("token", "", "GitHub token (default: $GITHUB_TOKEN)")
+	syncGithubCmd.Flags().String("api-url", "", "GitHub API base URL, for GitHub Enterprise (default: "+github.DefaultBaseURL+")")
+	syncGithubCmd.Flags().Bool("import-only", false, "Only pull from GitHub; don't push local changes")
+	syncGithubCmd.Flags().Bool("create", false, "Open GitHub issues for open local issues that aren't linked yet")
+	syncGithubCmd.Flags().Bool("dry-run", false, "Show what would change without syncing")
+	syncGithubCmd.Flags().Bool("json", false, "Output JSON format")
+	syncCmd.AddCommand(syncGithubCmd)
+}
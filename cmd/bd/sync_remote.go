@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/imalsogreg/beads/internal/replication"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/spf13/cobra"
+)
+
+var syncRemoteCmd = &cobra.Command{
+	Use:   "remote <url|name>",
+	Short: "Exchange changes with another replica through its bd serve API",
+	Long: `Sync this workspace with a peer running 'bd serve', for working offline and
+catching up later without going through git.
+
+Each workspace is a replica with its own ID, and logs every change it makes to
+an issue (creation, field edits, labels, moving to and from the trash) as an
+op, stamped with a vector clock of the changes it had seen. Syncing sends the
+peer the ops it hasn't seen and applies the ones this replica hasn't, so both
+end up with the same ops.
+
+Ops are applied field by field, and a field takes the value of the last op to
+write it: a change made after seeing another wins over it, and when two
+replicas changed the same field without seeing each other's change, the one
+whose replica had seen more changes wins, then the later one. Every replica
+orders ops the same way, so replicas that have synced agree.
+
+Issues are matched by the op that created them rather than by ID, since two
+replicas working offline can both create, say, bd-12. The second one a replica
+receives gets the next free ID there instead.
+
+Dependencies, comments, ID renames, and permanent deletes are not replicated,
+nor are issues created before either replica logged ops; share those through
+the JSONL file first.
+
+The argument is the peer's API URL (including /w/<name> for one workspace of
+a multi-workspace server) or the name of an entry in the remotes config, whose
+token is used. --token overrides it.
+
+Examples:
+  bd sync remote http://laptop.local:8080
+  bd sync remote platform
+  bd sync remote https://beads.example.com/w/docs --token $BEADS_TOKEN`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		token, _ := cmd.Flags().GetString("token")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		if err := ensureDirectMode("daemon does not support sync remote"); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: sync remote requires SQLite backend\n")
+			os.Exit(1)
+		}
+		ctx := context.Background()
+
+		peerURL := args[0]
+		for _, r := range mustRemoteRegistry().Remotes() {
+			if r.Name == args[0] {
+				peerURL = r.URL
+				if token == "" {
+					token = r.Token
+				}
+			}
+		}
+		client, err := replication.NewClient(peerURL, token)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v (expected a URL or a remote from config)\n", err)
+			os.Exit(1)
+		}
+
+		result, err := syncWithPeer(ctx, sqliteStore, client)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if result.Received.Applied > 0 {
+			markDirtyAndScheduleFlush()
+		}
+
+		if jsonOutput {
+			outputJSON(result)
+			return
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Synced with %s (replica %s)\n", green("✓"), peerURL, result.Peer)
+		fmt.Printf("  Sent %d op(s): %s\n", result.Sent, describeApply(result.PeerResult))
+		fmt.Printf("  Received %d op(s): %s\n", result.ReceivedOps, describeApply(result.Received))
+	},
+}
+
+// syncRemoteResult is what bd sync remote reports
+type syncRemoteResult struct {
+	Replica     string                   `json:"replica"`
+	Peer        string                   `json:"peer"`
+	Sent        int                      `json:"sent"`
+	PeerResult  *replication.ApplyResult `json:"peer_result"`
+	ReceivedOps int                      `json:"received"`
+	Received    *replication.ApplyResult `json:"received_result"`
+}
+
+// syncWithPeer sends the peer the ops its clock hasn't seen, and applies
+// the ops it returns
+func syncWithPeer(ctx context.Context, st *sqlite.SQLiteStorage, client *replication.Client) (*syncRemoteResult, error) {
+	local, err := st.ReplicationState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	peer, err := client.State(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if peer.Replica == local.Replica {
+		return nil, fmt.Errorf("peer has this workspace's replica ID %s; is it the same database?", local.Replica)
+	}
+	ops, err := st.OpsSince(ctx, peer.Clock)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Exchange(ctx, &replication.ExchangeRequest{Replica: local.Replica, Clock: local.Clock, Ops: ops})
+	if err != nil {
+		return nil, err
+	}
+	received, err := st.ApplyOps(ctx, resp.Ops)
+	if err != nil {
+		return nil, err
+	}
+	return &syncRemoteResult{
+		Replica:     local.Replica,
+		Peer:        peer.Replica,
+		Sent:        len(ops),
+		PeerResult:  resp.Result,
+		ReceivedOps: len(resp.Ops),
+		Received:    received,
+	}, nil
+}
+
+func describeApply(r *replication.ApplyResult) string {
+	if r == nil {
+		return "nothing to apply"
+	}
+	s := fmt.Sprintf("%d applied, %d already overwritten", r.Applied, r.Stale)
+	if r.Skipped > 0 {
+		s += fmt.Sprintf(", %d for unknown issues", r.Skipped)
+	}
+	if r.Conflicts > 0 {
+		s += fmt.Sprintf(", %d concurrent field edit(s) resolved", r.Conflicts)
+	}
+	if r.Renamed > 0 {
+		s += fmt.Sprintf(", %d new issue(s) given another ID because theirs was taken", r.Renamed)
+	}
+	return s
+}
+
+func init() {
+	syncRemoteCmd.Flags().String("token", "", "API key for the peer (default: the remote's token from config)")
+	syncRemoteCmd.Flags().Bool("json", false, "Output JSON format")
+	syncCmd.AddCommand(syncRemoteCmd)
+}
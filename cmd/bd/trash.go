@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+	"github.com/spf13/cobra"
+)
+
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "List and purge issues deleted with bd delete",
+	Long: `Issues removed with 'bd delete' (without --force) go to the trash. They are
+hidden everywhere else but keep their links and history, and 'bd restore <id>'
+brings them back.
+
+Compaction ('bd compact --all') purges issues that have been in the trash for
+longer than compact_trash_days (default 30; 0 keeps them until 'bd trash purge').`,
+}
+
+var trashListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List issues in the trash",
+	Run: func(_ *cobra.Command, _ []string) {
+		issues, err := requireTrashStore().ListTrash(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			if issues == nil {
+				issues = []*types.Issue{}
+			}
+			outputJSON(issues)
+			return
+		}
+
+		if len(issues) == 0 {
+			fmt.Println("Trash is empty")
+			return
+		}
+		cyan := color.New(color.FgCyan).SprintFunc()
+		for _, issue := range issues {
+			fmt.Printf("%s  %s\n", cyan(issue.ID), issue.Title)
+			if issue.DeletedAt != nil {
+				fmt.Printf("      deleted %s by %s\n", issue.DeletedAt.Local().Format("2006-01-02 15:04"), issue.DeletedBy)
+			}
+		}
+	},
+}
+
+var trashPurgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Permanently delete issues from the trash",
+	Long: `Permanently delete issues that have been in the trash longer than
+--older-than (default: compact_trash_days). --older-than 0 empties the trash.`,
+	Run: func(cmd *cobra.Command, _ []string) {
+		sqliteStore := requireTrashStore()
+		ctx := context.Background()
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		days, err := sqliteStore.TrashDays(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if cmd.Flags().Changed("older-than") {
+			days, _ = cmd.Flags().GetInt("older-than")
+		}
+
+		ids, err := sqliteStore.PurgeTrash(ctx, time.Now().AddDate(0, 0, -days), dryRun)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(ids) > 0 && !dryRun {
+			markDirtyAndScheduleFlush()
+		}
+
+		if jsonOutput {
+			if ids == nil {
+				ids = []string{}
+			}
+			outputJSON(map[string]interface{}{"purged": ids, "dry_run": dryRun})
+			return
+		}
+
+		if len(ids) == 0 {
+			fmt.Printf("Nothing in the trash older than %d day(s)\n", days)
+			return
+		}
+		if dryRun {
+			fmt.Printf("Would permanently delete %d issue(s): %v\n", len(ids), ids)
+			return
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Permanently deleted %d issue(s) from the trash\n", green("✓"), len(ids))
+	},
+}
+
+// trashIssues moves issues to the trash for bd delete
+func trashIssues(issueIDs []string) {
+	sqliteStore := requireTrashStore()
+	ctx := context.Background()
+
+	green := color.New(color.FgGreen).SprintFunc()
+	trashed := []string{}
+	failed := false
+	for _, id := range issueIDs {
+		err := sqliteStore.SoftDeleteIssue(ctx, id, actor)
+		if errors.Is(err, sqlite.ErrAlreadyInTrash) {
+			fmt.Fprintf(os.Stderr, "%s is already in the trash (use --force to delete it permanently)\n", id)
+			continue
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error deleting %s: %v\n", id, err)
+			failed = true
+			continue
+		}
+		trashed = append(trashed, id)
+		if !jsonOutput {
+			fmt.Printf("%s Moved %s to the trash\n", green("✓"), id)
+		}
+	}
+
+	if len(trashed) > 0 {
+		markDirtyAndScheduleFlush()
+	}
+	if jsonOutput {
+		outputJSON(map[string]interface{}{"trashed": trashed})
+	} else if len(trashed) > 0 {
+		fmt.Printf("Restore with: bd restore <id>\n")
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// restoreFromTrash handles bd restore for an issue in the trash. It returns
+// false if the issue isn't there, so bd restore can fall back to restoring
+// a compacted issue from git.
+func restoreFromTrash(ctx context.Context, issueID string) bool {
+	if err := ensureDirectMode("daemon does not support trash commands"); err != nil {
+		return false
+	}
+	sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+	if !ok {
+		return false
+	}
+	issue, err := sqliteStore.GetIssue(ctx, issueID)
+	if err != nil || issue == nil || issue.DeletedAt == nil {
+		return false
+	}
+
+	if err := sqliteStore.RestoreIssue(ctx, issueID, actor); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	markDirtyAndScheduleFlush()
+
+	if jsonOutput {
+		outputJSON(map[string]string{"restored": issueID})
+		return true
+	}
+	green := color.New(color.FgGreen).SprintFunc()
+	fmt.Printf("%s Restored %s from the trash\n", green("✓"), issueID)
+	return true
+}
+
+func requireTrashStore() *sqlite.SQLiteStorage {
+	if err := ensureDirectMode("daemon does not support trash commands"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: trash requires SQLite backend (use bd delete --force to delete permanently)\n")
+		os.Exit(1)
+	}
+	return sqliteStore
+}
+
+func init() {
+	trashPurgeCmd.Flags().Int("older-than", 0, "Only purge issues deleted more than this many days ago")
+	trashPurgeCmd.Flags().Bool("dry-run", false, "Show what would be purged")
+	trashCmd.AddCommand(trashListCmd)
+	trashCmd.AddCommand(trashPurgeCmd)
+	rootCmd.AddCommand(trashCmd)
+}
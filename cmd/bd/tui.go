@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/imalsogreg/beads/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Browse and edit issues in a full-screen terminal UI",
+	Long: `Open a full-screen terminal UI with the issue list on the left and the
+selected issue on the right.
+
+Keys:
+  j/k, arrows   Move through the list (PgUp/PgDn, g/G for pages and ends)
+  /             Search as you type; Enter keeps the results, Esc clears them
+  s / S         Move the issue to the next / previous status
+                (open, in_progress, blocked, closed)
+  0-4           Set the priority
+  c             Write a comment; Enter saves, Esc cancels
+  Tab           Move through the issue's dependencies; Enter opens one
+  b, Backspace  Go back after following dependencies
+  J/K           Scroll the detail pane
+  a             Show or hide closed issues
+  r             Reload
+  q, Ctrl-C     Quit`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := ensureDirectMode("tui uses direct database access"); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		app, err := tui.New(context.Background(), store, actor)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		app.OnChange = markDirtyAndScheduleFlush
+		if err := app.Run(os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tuiCmd)
+}
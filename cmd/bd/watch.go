@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+	"github.com/spf13/cobra"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch [issue-id...]",
+	Short: "Subscribe to changes on issues",
+	Long: `Watch issues to hear about every change to them. Webhook deliveries for a
+watched issue list its watchers, so whatever receives them (chat, email) can
+notify each one. bd show lists an issue's watchers.
+
+You watch as the current actor: BD_ACTOR, or else your login name.
+
+Examples:
+  bd watch bd-12                # Watch bd-12
+  bd watch --remove bd-12       # Stop watching it
+  bd watch --list               # Issues you're watching`,
+	Run: func(cmd *cobra.Command, args []string) {
+		remove, _ := cmd.Flags().GetBool("remove")
+		list, _ := cmd.Flags().GetBool("list")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		switch {
+		case list && (remove || len(args) > 0):
+			fmt.Fprintf(os.Stderr, "Error: --list doesn't take --remove or issue IDs\n")
+			os.Exit(1)
+		case !list && len(args) == 0:
+			fmt.Fprintf(os.Stderr, "Error: issue ID required (or use --list)\n")
+			os.Exit(1)
+		}
+
+		sqliteStore := requireWatchStore()
+		ctx := context.Background()
+
+		if list {
+			issues, err := sqliteStore.GetWatchedIssues(ctx, actor)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if jsonOutput {
+				if issues == nil {
+					issues = []*types.Issue{}
+				}
+				outputJSON(issues)
+				return
+			}
+			if len(issues) == 0 {
+				fmt.Printf("%s isn't watching any issues\n", actor)
+				return
+			}
+			fmt.Printf("\nWatched by %s (%d):\n\n", actor, len(issues))
+			for _, issue := range issues {
+				fmt.Printf("  %s [P%d] [%s] %s\n", issue.ID, issue.Priority, issue.Status, issue.Title)
+			}
+			return
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		type watchResult struct {
+			IssueID  string   `json:"issue_id"`
+			Watchers []string `json:"watchers"`
+		}
+		var results []watchResult
+		for _, id := range args {
+			var err error
+			if remove {
+				err = sqliteStore.UnwatchIssue(ctx, id, actor)
+			} else {
+				err = sqliteStore.WatchIssue(ctx, id, actor)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			if jsonOutput {
+				watchers := getWatchers(ctx, id)
+				if watchers == nil {
+					watchers = []string{}
+				}
+				results = append(results, watchResult{IssueID: id, Watchers: watchers})
+			} else if remove {
+				fmt.Printf("%s Stopped watching %s\n", green("✓"), id)
+			} else {
+				fmt.Printf("%s Watching %s\n", green("✓"), id)
+			}
+		}
+		if jsonOutput {
+			outputJSON(results)
+		}
+	},
+}
+
+func requireWatchStore() *sqlite.SQLiteStorage {
+	if err := ensureDirectMode("daemon does not support watch command"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: watch command requires SQLite backend\n")
+		os.Exit(1)
+	}
+	return sqliteStore
+}
+
+// getWatchers returns the users watching an issue for bd show, or nil if
+// the store doesn't support watching
+func getWatchers(ctx context.Context, issueID string) []string {
+	sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+	if !ok {
+		return nil
+	}
+	watchers, _ := sqliteStore.GetWatchers(ctx, issueID)
+	return watchers
+}
+
+func init() {
+	watchCmd.Flags().Bool("remove", false, "Stop watching the issues")
+	watchCmd.Flags().Bool("list", false, "List the issues you're watching")
+	watchCmd.Flags().Bool("json", false, "Output in JSON format")
+	rootCmd.AddCommand(watchCmd)
+}
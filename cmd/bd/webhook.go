@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/webhook"
+	"github.com/spf13/cobra"
+)
+
+var webhookCmd = &cobra.Command{
+	Use:   "webhook",
+	Short: "Manage webhooks that receive issue events",
+	Long: `Register URLs that receive issue events as JSON POSTs.
+
+While 'bd serve' is running it delivers issue.created, issue.updated,
+issue.closed, issue.commented, and issue.mentioned events for changes made
+through any client, retrying failed deliveries with exponential backoff.
+An issue.mentioned delivery names the user a comment @mentioned, so a chat
+bot can ping them, and every delivery lists the users watching the issue
+(see bd watch).
+
+With a secret, each delivery carries
+  X-Beads-Signature: sha256=<hex HMAC-SHA256 of the body>
+so receivers can verify it came from this workspace.
+
+Examples:
+  bd webhook add https://example.com/hooks/beads --secret s3cret
+  bd webhook add https://chat.example.com/in --events issue.created,issue.closed
+  bd webhook list
+  bd webhook test 1
+  bd webhook remove 1`,
+}
+
+var webhookAddCmd = &cobra.Command{
+	Use:   "add <url>",
+	Short: "Register a webhook",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		secret, _ := cmd.Flags().GetString("secret")
+		events, _ := cmd.Flags().GetStringSlice("events")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		if err := webhook.ValidateURL(args[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := webhook.ValidateEvents(events); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		sqliteStore := requireWebhookStore()
+		hook := &sqlite.Webhook{URL: args[0], Secret: secret, Events: events, CreatedBy: actor}
+		if err := sqliteStore.CreateWebhook(context.Background(), hook); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			outputJSON(hook)
+			return
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Added webhook %d: %s\n", green("✓"), hook.ID, hook.URL)
+	},
+}
+
+var webhookListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List webhooks",
+	Run: func(cmd *cobra.Command, _ []string) {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		hooks, err := requireWebhookStore().ListWebhooks(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			if hooks == nil {
+				hooks = []*sqlite.Webhook{}
+			}
+			outputJSON(hooks)
+			return
+		}
+
+		if len(hooks) == 0 {
+			fmt.Println("No webhooks registered")
+			return
+		}
+		for _, hook := range hooks {
+			events := "all events"
+			if len(hook.Events) > 0 {
+				events = strings.Join(hook.Events, ", ")
+			}
+			signed := ""
+			if hook.HasSecret {
+				signed = ", signed"
+			}
+			fmt.Printf("%d  %s  (%s%s)\n", hook.ID, hook.URL, events, signed)
+		}
+	},
+}
+
+var webhookTestCmd = &cobra.Command{
+	Use:   "test <id>",
+	Short: "Send a ping delivery to a webhook",
+	Args:  cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		ctx := context.Background()
+		hook := getWebhookArg(ctx, requireWebhookStore(), args[0])
+
+		sender := webhook.NewSender()
+		sender.Retries = 0
+		payload := &webhook.Payload{Event: webhook.EventPing, Actor: actor, Timestamp: time.Now()}
+		if err := sender.Send(ctx, hook, payload); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Delivered ping to %s\n", green("✓"), hook.URL)
+	},
+}
+
+var webhookRemoveCmd = &cobra.Command{
+	Use:   "remove <id>",
+	Short: "Remove a webhook",
+	Args:  cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		ctx := context.Background()
+		sqliteStore := requireWebhookStore()
+		hook := getWebhookArg(ctx, sqliteStore, args[0])
+
+		if err := sqliteStore.DeleteWebhook(ctx, hook.ID); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Removed webhook %d\n", green("✓"), hook.ID)
+	},
+}
+
+func requireWebhookStore() *sqlite.SQLiteStorage {
+	if err := ensureDirectMode("daemon does not support webhook command"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: webhook command requires SQLite backend\n")
+		os.Exit(1)
+	}
+	return sqliteStore
+}
+
+// getWebhookArg loads the webhook named by a command argument or exits
+func getWebhookArg(ctx context.Context, sqliteStore *sqlite.SQLiteStorage, arg string) *sqlite.Webhook {
+	id, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid webhook ID %q\n", arg)
+		os.Exit(1)
+	}
+	hook, err := sqliteStore.GetWebhook(ctx, id)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if hook == nil {
+		fmt.Fprintf(os.Stderr, "Error: webhook %d not found\n", id)
+		os.Exit(1)
+	}
+	return hook
+}
+
+func init() {
+	webhookAddCmd.Flags().String("secret", "", "Secret for signing deliveries (HMAC-SHA256)")
+	webhookAddCmd.Flags().StringSlice("events", nil, "Events to deliver (default all): "+strings.Join(webhook.Events, ", "))
+	webhookAddCmd.Flags().Bool("json", false, "Output JSON format")
+	webhookListCmd.Flags().Bool("json", false, "Output JSON format")
+
+	webhookCmd.AddCommand(webhookAddCmd)
+	webhookCmd.AddCommand(webhookListCmd)
+	webhookCmd.AddCommand(webhookTestCmd)
+	webhookCmd.AddCommand(webhookRemoveCmd)
+	rootCmd.AddCommand(webhookCmd)
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+	"github.com/spf13/cobra"
+)
+
+var workflowCmd = &cobra.Command{
+	Use:   "workflow",
+	Short: "Show or configure the statuses issues move through",
+	Long: `Show the workflow: the statuses issues can be in and the moves allowed
+between them.
+
+A workflow is a JSON document. It keeps the built-in statuses (open,
+in_progress, blocked, closed) and may add custom ones, each counting as
+open, in_progress, or blocked for ready work and blockers. Transitions list
+the allowed moves; "from": "*" means any status, and "require" names fields
+that must be set for the move (reason, for closing, or an issue field such
+as assignee). With no transitions, any move is allowed.
+
+Example workflow:
+  {
+    "statuses": [
+      {"name": "open", "category": "open"},
+      {"name": "in_progress", "category": "in_progress"},
+      {"name": "in_review", "category": "in_progress", "description": "Waiting for review"},
+      {"name": "blocked", "category": "blocked"},
+      {"name": "closed", "category": "closed"}
+    ],
+    "transitions": [
+      {"from": "open", "to": "in_progress", "require": ["assignee"]},
+      {"from": "in_progress", "to": "in_review"},
+      {"from": "in_review", "to": "in_progress"},
+      {"from": "in_review", "to": "closed", "require": ["reason"]},
+      {"from": "*", "to": "blocked"},
+      {"from": "blocked", "to": "open"},
+      {"from": "closed", "to": "open"}
+    ]
+  }
+
+Examples:
+  bd workflow                      # Show statuses and transitions
+  bd workflow next bd-12           # Where bd-12 can move next
+  bd workflow set workflow.json    # Replace the workflow (- reads stdin)
+  bd workflow reset                # Back to the built-in statuses, any move allowed`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		workflow, err := requireWorkflowStore().GetWorkflow(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			outputJSON(workflow)
+			return
+		}
+		printWorkflow(workflow)
+	},
+}
+
+var workflowNextCmd = &cobra.Command{
+	Use:   "next <issue-id>",
+	Short: "List the statuses an issue can move to",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		sqliteStore := requireWorkflowStore()
+		ctx := context.Background()
+
+		issue, err := sqliteStore.GetIssue(ctx, args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if issue == nil {
+			fmt.Fprintf(os.Stderr, "Error: issue %s not found\n", args[0])
+			os.Exit(1)
+		}
+		workflow, err := sqliteStore.GetWorkflow(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		next := workflow.Next(issue.Status)
+
+		if jsonOutput {
+			outputJSON(next)
+			return
+		}
+		if len(next) == 0 {
+			fmt.Printf("%s is %s and can't move\n", issue.ID, issue.Status)
+			return
+		}
+		fmt.Printf("%s is %s and can move to:\n", issue.ID, issue.Status)
+		for _, t := range next {
+			line := "  " + string(t.To)
+			if len(t.Require) > 0 {
+				line += " (requires " + strings.Join(t.Require, ", ") + ")"
+			}
+			fmt.Println(line)
+		}
+	},
+}
+
+var workflowSetCmd = &cobra.Command{
+	Use:   "set <file>",
+	Short: "Replace the workflow with one read from a JSON file (- for stdin)",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		var data []byte
+		var err error
+		if args[0] == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(args[0])
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		workflow, err := types.ParseWorkflow(data)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := requireWorkflowStore().SetWorkflow(context.Background(), workflow); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			outputJSON(workflow)
+			return
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Set workflow: %d statuses, %d transitions\n", green("✓"), len(workflow.Statuses), len(workflow.Transitions))
+	},
+}
+
+var workflowResetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Restore the built-in statuses, with any move allowed",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		if err := requireWorkflowStore().SetWorkflow(context.Background(), nil); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			outputJSON(types.DefaultWorkflow())
+			return
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Restored the default workflow\n", green("✓"))
+	},
+}
+
+func printWorkflow(workflow *types.Workflow) {
+	cyan := color.New(color.FgCyan).SprintFunc()
+	fmt.Println("Statuses:")
+	for _, s := range workflow.Statuses {
+		line := "  " + cyan(string(s.Name))
+		if s.Category != "" && s.Category != s.Name {
+			line += " (counts as " + string(s.Category) + ")"
+		}
+		if s.Description != "" {
+			line += "  " + s.Description
+		}
+		fmt.Println(line)
+	}
+	fmt.Println()
+	if len(workflow.Transitions) == 0 {
+		fmt.Println("Any status can move to any other")
+		return
+	}
+	fmt.Println("Transitions:")
+	for _, t := range workflow.Transitions {
+		line := fmt.Sprintf("  %s -> %s", t.From, t.To)
+		if len(t.Require) > 0 {
+			line += " (requires " + strings.Join(t.Require, ", ") + ")"
+		}
+		fmt.Println(line)
+	}
+}
+
+func requireWorkflowStore() *sqlite.SQLiteStorage {
+	if err := ensureDirectMode("daemon does not support workflow command"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: workflow command requires SQLite backend\n")
+		os.Exit(1)
+	}
+	return sqliteStore
+}
+
+func init() {
+	for _, c := range []*cobra.Command{workflowCmd, workflowNextCmd, workflowSetCmd, workflowResetCmd} {
+		c.Flags().Bool("json", false, "Output JSON format")
+	}
+
+	workflowCmd.AddCommand(workflowNextCmd)
+	workflowCmd.AddCommand(workflowSetCmd)
+	workflowCmd.AddCommand(workflowResetCmd)
+	rootCmd.AddCommand(workflowCmd)
+}
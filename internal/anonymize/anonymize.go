@@ -0,0 +1,163 @@
+// Package anonymize rewrites issues so that realistic datasets can be shared
+// for debugging or benchmarking without leaking people or internal links.
+//
+// Actors are replaced with stable pseudonyms: the same name always maps to the
+// same pseudonym for a given salt, so assignment and authorship patterns
+// survive. Emails, URLs, and detected secrets are stripped from text fields.
+package anonymize
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/imalsogreg/beads/internal/secrets"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// Replacement markers for stripped text
+const (
+	EmailMarker = "[email]"
+	URLMarker   = "[url]"
+)
+
+var (
+	emailRe = regexp.MustCompile(`(?i)\b[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,}\b`)
+	urlRe   = regexp.MustCompile(`(?i)\b(?:[a-z][a-z0-9+.\-]*://|www\.)[^\s<>"'` + "`" + `)\]]+`)
+)
+
+// minNameLength is the shortest actor name that is replaced inside free text;
+// shorter names would clobber ordinary words
+const minNameLength = 3
+
+// Anonymizer maps actors to pseudonyms and scrubs text
+type Anonymizer struct {
+	salt  []byte
+	names map[string]string
+	order []string // names longest first, for replacement in text
+}
+
+// New creates an Anonymizer. Pseudonyms are derived from an HMAC of the actor
+// name keyed with salt; use a private salt when names could be guessed from a
+// list of likely candidates.
+func New(salt string) *Anonymizer {
+	return &Anonymizer{salt: []byte(salt), names: make(map[string]string)}
+}
+
+// Actor returns the pseudonym for name. Empty names stay empty.
+func (a *Anonymizer) Actor(name string) string {
+	if name == "" {
+		return ""
+	}
+	if p, ok := a.names[name]; ok {
+		return p
+	}
+	mac := hmac.New(sha256.New, a.salt)
+	mac.Write([]byte(name))
+	p := "user-" + hex.EncodeToString(mac.Sum(nil))[:8]
+	a.names[name] = p
+	if len(name) >= minNameLength {
+		a.order = append(a.order, name)
+		sort.Slice(a.order, func(i, j int) bool {
+			if len(a.order[i]) != len(a.order[j]) {
+				return len(a.order[i]) > len(a.order[j])
+			}
+			return a.order[i] < a.order[j]
+		})
+	}
+	return p
+}
+
+// Text strips secrets, URLs, and email addresses from s. Names of actors seen
+// so far are replaced with their pseudonyms; call Issues to register every
+// actor before any text is rewritten.
+func (a *Anonymizer) Text(s string) string {
+	if s == "" {
+		return s
+	}
+	s, _ = secrets.Mask(s)
+	s = urlRe.ReplaceAllString(s, URLMarker)
+	s = emailRe.ReplaceAllString(s, EmailMarker)
+	for _, name := range a.order {
+		s = replaceWord(s, name, a.names[name])
+	}
+	return s
+}
+
+// Issue returns an anonymized copy of issue. The original is not modified.
+// Actor fields are pseudonymized before text so that names mentioned in
+// descriptions and comments are caught.
+func (a *Anonymizer) Issue(issue *types.Issue) *types.Issue {
+	out := *issue
+	out.Assignee = a.Actor(issue.Assignee)
+
+	if len(issue.Dependencies) > 0 {
+		out.Dependencies = make([]*types.Dependency, len(issue.Dependencies))
+		for i, dep := range issue.Dependencies {
+			d := *dep
+			d.CreatedBy = a.Actor(dep.CreatedBy)
+			out.Dependencies[i] = &d
+		}
+	}
+	if len(issue.Comments) > 0 {
+		out.Comments = make([]*types.Comment, len(issue.Comments))
+		for i, c := range issue.Comments {
+			cc := *c
+			cc.Author = a.Actor(c.Author)
+			out.Comments[i] = &cc
+		}
+		for _, c := range out.Comments {
+			c.Text = a.Text(c.Text)
+		}
+	}
+
+	out.Title = a.Text(issue.Title)
+	out.Description = a.Text(issue.Description)
+	out.Design = a.Text(issue.Design)
+	out.AcceptanceCriteria = a.Text(issue.AcceptanceCriteria)
+	out.Notes = a.Text(issue.Notes)
+	if issue.ExternalRef != nil {
+		ref := a.Text(*issue.ExternalRef)
+		out.ExternalRef = &ref
+	}
+	return &out
+}
+
+// Issues anonymizes a batch of issues. All actors are registered first so that
+// a name mentioned in one issue's text is replaced even if that actor only
+// appears as an assignee or author on a later issue.
+func (a *Anonymizer) Issues(issues []*types.Issue) []*types.Issue {
+	for _, issue := range issues {
+		a.Actor(issue.Assignee)
+		for _, dep := range issue.Dependencies {
+			a.Actor(dep.CreatedBy)
+		}
+		for _, c := range issue.Comments {
+			a.Actor(c.Author)
+		}
+	}
+	out := make([]*types.Issue, len(issues))
+	for i, issue := range issues {
+		out[i] = a.Issue(issue)
+	}
+	return out
+}
+
+// replaceWord replaces whole-word occurrences of name in s
+func replaceWord(s, name, replacement string) string {
+	if !strings.Contains(s, name) {
+		return s
+	}
+	re := regexp.MustCompile(`(^|[^\w.@\-])` + regexp.QuoteMeta(name) + `($|[^\w\-])`)
+	// Repeat so adjacent matches sharing a boundary character are all replaced
+	for {
+		next := re.ReplaceAllString(s, "${1}"+replacement+"${2}")
+		if next == s {
+			return s
+		}
+		s = next
+	}
+}
@@ -0,0 +1,78 @@
+package anonymize
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestActorPseudonymsAreStable(t *testing.T) {
+	a := New("salt")
+	p := a.Actor("alice")
+	if !strings.HasPrefix(p, "user-") || len(p) != len("user-")+8 {
+		t.Fatalf("unexpected pseudonym %q", p)
+	}
+	if a.Actor("alice") != p || New("salt").Actor("alice") != p {
+		t.Error("pseudonym should be stable for the same salt")
+	}
+	if New("other").Actor("alice") == p {
+		t.Error("pseudonym should depend on the salt")
+	}
+	if a.Actor("bob") == p {
+		t.Error("different actors should get different pseudonyms")
+	}
+	if a.Actor("") != "" {
+		t.Error("empty actor should stay empty")
+	}
+}
+
+func TestTextStripsEmailsAndURLs(t *testing.T) {
+	a := New("")
+	got := a.Text("Mail jane.doe@corp.example.com or see https://wiki.corp.example/page?id=1 and www.internal.example.")
+	for _, leaked := range []string{"jane.doe", "corp.example", "wiki", "internal.example"} {
+		if strings.Contains(got, leaked) {
+			t.Errorf("text still contains %q: %s", leaked, got)
+		}
+	}
+	if !strings.Contains(got, EmailMarker) || !strings.Contains(got, URLMarker) {
+		t.Errorf("expected markers in %q", got)
+	}
+}
+
+func TestIssuesReplacesActorNamesInText(t *testing.T) {
+	ref := "https://jira.corp.example/browse/ABC-1"
+	issues := []*types.Issue{
+		{ID: "bd-1", Title: "Ask bob about it", Description: "alice says hi", Assignee: "alice", ExternalRef: &ref},
+		{ID: "bd-2", Title: "Other", Assignee: "bob",
+			Dependencies: []*types.Dependency{{IssueID: "bd-2", DependsOnID: "bd-1", CreatedBy: "alice"}},
+			Comments:     []*types.Comment{{Author: "bob", Text: "thanks alice, also ping al"}}},
+	}
+	a := New("s")
+	out := a.Issues(issues)
+
+	alice, bob := a.Actor("alice"), a.Actor("bob")
+	if out[0].Assignee != alice || out[1].Assignee != bob {
+		t.Errorf("assignees not pseudonymized: %q, %q", out[0].Assignee, out[1].Assignee)
+	}
+	if out[0].Title != "Ask "+bob+" about it" {
+		t.Errorf("actor from a later issue not replaced in title: %q", out[0].Title)
+	}
+	if out[0].Description != alice+" says hi" {
+		t.Errorf("unexpected description %q", out[0].Description)
+	}
+	if *out[0].ExternalRef != URLMarker {
+		t.Errorf("external ref not scrubbed: %q", *out[0].ExternalRef)
+	}
+	if out[1].Dependencies[0].CreatedBy != alice || out[1].Comments[0].Author != bob {
+		t.Error("dependency/comment actors not pseudonymized")
+	}
+	if out[1].Comments[0].Text != "thanks "+alice+", also ping al" {
+		t.Errorf("unexpected comment text %q", out[1].Comments[0].Text)
+	}
+
+	// Originals are untouched
+	if issues[0].Assignee != "alice" || issues[1].Dependencies[0].CreatedBy != "alice" || *issues[0].ExternalRef != ref {
+		t.Error("input issues were modified")
+	}
+}
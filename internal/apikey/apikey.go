@@ -0,0 +1,86 @@
+// Package apikey issues API keys with roles for bd serve.
+//
+// A key is a random token shown once at creation; only its SHA-256 hash is
+// stored. Each key has a role, and roles are ordered so a higher role can do
+// everything a lower one can:
+//
+//	reader  GET requests
+//	writer  reader, plus creating and changing issues
+//	admin   writer, plus keys, webhooks, config changes, and /admin
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Role is what a key is allowed to do
+type Role string
+
+// Roles, lowest first
+const (
+	RoleReader Role = "reader"
+	RoleWriter Role = "writer"
+	RoleAdmin  Role = "admin"
+)
+
+// Roles lists the valid roles, lowest first
+var Roles = []Role{RoleReader, RoleWriter, RoleAdmin}
+
+func (r Role) rank() int {
+	for i, role := range Roles {
+		if r == role {
+			return i
+		}
+	}
+	return -1
+}
+
+// Allows reports whether r includes required
+func (r Role) Allows(required Role) bool {
+	return r.rank() >= 0 && r.rank() >= required.rank()
+}
+
+// ParseRole parses a role name, accepting "read-only" and "readonly" for reader
+func ParseRole(s string) (Role, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "reader", "read-only", "readonly", "read":
+		return RoleReader, nil
+	case "writer", "write":
+		return RoleWriter, nil
+	case "admin":
+		return RoleAdmin, nil
+	default:
+		return "", fmt.Errorf("invalid role %q (must be reader, writer, or admin)", s)
+	}
+}
+
+// tokenPrefix marks beads API keys so they're recognizable in configs and
+// secret scanners
+const tokenPrefix = "bdk_"
+
+// Generate returns a new random token
+func Generate() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate key: %w", err)
+	}
+	return tokenPrefix + hex.EncodeToString(buf), nil
+}
+
+// Hash returns the stored form of a token
+func Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Hint returns the start of a token, enough to tell keys apart in listings
+func Hint(token string) string {
+	if len(token) > len(tokenPrefix)+4 {
+		return token[:len(tokenPrefix)+4]
+	}
+	return token
+}
@@ -0,0 +1,38 @@
+package apikey
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRoles(t *testing.T) {
+	if !RoleAdmin.Allows(RoleWriter) || !RoleWriter.Allows(RoleReader) || !RoleReader.Allows(RoleReader) {
+		t.Error("Expected higher roles to include lower ones")
+	}
+	if RoleReader.Allows(RoleWriter) || RoleWriter.Allows(RoleAdmin) || Role("bogus").Allows(RoleReader) {
+		t.Error("Expected lower or unknown roles to be refused")
+	}
+
+	for input, want := range map[string]Role{"reader": RoleReader, "read-only": RoleReader, "Writer": RoleWriter, "admin": RoleAdmin} {
+		if got, err := ParseRole(input); err != nil || got != want {
+			t.Errorf("ParseRole(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseRole("owner"); err == nil {
+		t.Error("Expected error for unknown role")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	a, err := Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := Generate()
+	if a == b || !strings.HasPrefix(a, tokenPrefix) {
+		t.Errorf("Expected distinct prefixed tokens, got %q and %q", a, b)
+	}
+	if Hash(a) == a || Hash(a) != Hash(a) || len(Hash(a)) != 64 {
+		t.Errorf("Unexpected hash %q", Hash(a))
+	}
+}
@@ -0,0 +1,190 @@
+// Package autoclose closes epics once all their children are closed.
+//
+// It is opt-in: with epic.autoclose set to "true", bd serve and the daemon
+// close eligible epics as they find them, and 'bd epic autoclose' does so on
+// demand. Each epic gets a comment summarizing its children and how long it
+// was open.
+package autoclose
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// ConfigKey is "true" to close eligible epics automatically
+const ConfigKey = "epic.autoclose"
+
+// Actor is who automatic closes and their comments are recorded as
+const Actor = "system"
+
+// Reason is the close reason recorded on epics closed automatically
+const Reason = "All children completed"
+
+// checkInterval is how often bd serve looks for epics to close
+const checkInterval = time.Minute
+
+// Closure is an epic closed, or that would be closed, because its children
+// are all closed
+type Closure struct {
+	Epic     *types.Issue   `json:"epic"`
+	Children []*types.Issue `json:"children"`
+	Elapsed  time.Duration  `json:"-"` // From the epic's creation to its close
+	Comment  string         `json:"comment"`
+
+	ElapsedMinutes int `json:"elapsed_minutes"`
+}
+
+// Enabled reports whether epic.autoclose is on
+func Enabled(ctx context.Context, store storage.Storage) bool {
+	v, err := store.GetConfig(ctx, ConfigKey)
+	return err == nil && v == "true"
+}
+
+// Run closes every open epic whose children are all closed, commenting on
+// each, and returns them. Closing a nested epic can complete its parent,
+// which is closed in the same run. With dryRun nothing is changed, so only
+// the epics eligible now are returned. A failure to close one epic stops the
+// run; the epics closed before it are returned.
+func Run(ctx context.Context, store storage.Storage, actor string, now time.Time, dryRun bool) ([]*Closure, error) {
+	var closures []*Closure
+	for {
+		closed, err := runOnce(ctx, store, actor, now, dryRun)
+		closures = append(closures, closed...)
+		if err != nil || dryRun || len(closed) == 0 {
+			return closures, err
+		}
+	}
+}
+
+// runOnce closes the epics eligible now
+func runOnce(ctx context.Context, store storage.Storage, actor string, now time.Time, dryRun bool) ([]*Closure, error) {
+	epics, err := store.GetEpicsEligibleForClosure(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var closures []*Closure
+	for _, status := range epics {
+		if !status.EligibleForClose {
+			continue
+		}
+		children, err := store.GetChildren(ctx, status.Epic.ID)
+		if err != nil {
+			return closures, err
+		}
+		c := &Closure{Epic: status.Epic, Children: children, Elapsed: now.Sub(status.Epic.CreatedAt)}
+		c.ElapsedMinutes = int(c.Elapsed.Minutes())
+		c.Comment = summarize(c)
+		if !dryRun {
+			if _, err := store.AddIssueComment(ctx, c.Epic.ID, actor, c.Comment); err != nil {
+				return closures, fmt.Errorf("failed to comment on %s: %w", c.Epic.ID, err)
+			}
+			if err := store.CloseIssue(ctx, c.Epic.ID, Reason, actor); err != nil {
+				return closures, fmt.Errorf("failed to close %s: %w", c.Epic.ID, err)
+			}
+		}
+		closures = append(closures, c)
+	}
+	return closures, nil
+}
+
+// summarize writes the comment left on an epic closed automatically
+func summarize(c *Closure) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Closed automatically: all %d children are closed. Open for %s.\n", len(c.Children), FormatElapsed(c.Elapsed))
+	for _, child := range c.Children {
+		fmt.Fprintf(&b, "\n- %s %s", child.ID, child.Title)
+		if child.ClosedAt != nil {
+			fmt.Fprintf(&b, " (closed %s)", child.ClosedAt.UTC().Format("2006-01-02"))
+		}
+	}
+	return b.String()
+}
+
+// FormatElapsed formats a duration as days and hours, or hours and minutes
+// under a day
+func FormatElapsed(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	if d < 24*time.Hour {
+		return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+	days := int(d.Hours()) / 24
+	return fmt.Sprintf("%dd %dh", days, int(d.Hours())%24)
+}
+
+// Scheduler closes eligible epics while bd serve runs. The config key is
+// read on every check, so turning autoclose on or off takes effect without
+// a restart.
+type Scheduler struct {
+	store storage.Storage
+
+	mu      sync.Mutex
+	lastRun time.Time
+	lastErr error
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+}
+
+// NewScheduler creates a scheduler for store
+func NewScheduler(store storage.Storage) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{store: store, ctx: ctx, cancel: cancel}
+}
+
+// Start checks for epics to close now and then periodically in the
+// background
+func (s *Scheduler) Start() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(checkInterval)
+		defer ticker.Stop()
+		for {
+			var err error
+			if Enabled(s.ctx, s.store) {
+				var closed []*Closure
+				closed, err = Run(s.ctx, s.store, Actor, time.Now(), false)
+				for _, c := range closed {
+					log.Printf("autoclose: closed epic %s, all %d children closed", c.Epic.ID, len(c.Children))
+				}
+				if err != nil {
+					log.Printf("autoclose: %v", err)
+				}
+			}
+			s.mu.Lock()
+			s.lastRun, s.lastErr = time.Now(), err
+			s.mu.Unlock()
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// LastRun reports when the scheduler last checked for epics to close and
+// what that check failed with, if anything
+func (s *Scheduler) LastRun() (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastRun, s.lastErr
+}
+
+// Close stops the scheduler and waits for a round in progress to finish
+func (s *Scheduler) Close() {
+	s.stopOnce.Do(s.cancel)
+	s.wg.Wait()
+}
@@ -0,0 +1,110 @@
+package autoclose
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage/memory"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New("")
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+
+	create := func(title string, issueType types.IssueType, parentID string) *types.Issue {
+		t.Helper()
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 2, IssueType: issueType, ParentID: parentID}
+		if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+			t.Fatal(err)
+		}
+		return issue
+	}
+	epic := create("Launch", types.TypeEpic, "")
+	nested := create("Backend", types.TypeEpic, epic.ID)
+	api := create("API", types.TypeTask, nested.ID)
+	docs := create("Docs", types.TypeTask, epic.ID)
+	unfinished := create("Later", types.TypeEpic, "")
+	create("Someday", types.TypeTask, unfinished.ID)
+	for _, id := range []string{api.ID, docs.ID} {
+		if err := store.CloseIssue(ctx, id, "done", "alice"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	now := time.Now().Add(50 * time.Hour)
+	preview, err := Run(ctx, store, Actor, now, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(preview) != 1 || preview[0].Epic.ID != nested.ID {
+		t.Fatalf("Expected a dry run to find only the nested epic, got %v", preview)
+	}
+	if got, _ := store.GetIssue(ctx, nested.ID); got.Status == types.StatusClosed {
+		t.Error("Expected a dry run to close nothing")
+	}
+
+	closed, err := Run(ctx, store, Actor, now, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(closed) != 2 || closed[0].Epic.ID != nested.ID || closed[1].Epic.ID != epic.ID {
+		t.Fatalf("Expected the nested epic and then its parent closed, got %v", closed)
+	}
+	for _, id := range []string{nested.ID, epic.ID} {
+		if got, _ := store.GetIssue(ctx, id); got.Status != types.StatusClosed {
+			t.Errorf("Expected %s closed, got %s", id, got.Status)
+		}
+	}
+	if got, _ := store.GetIssue(ctx, unfinished.ID); got.Status != types.StatusOpen {
+		t.Errorf("Expected the unfinished epic left open, got %s", got.Status)
+	}
+
+	comments, err := store.GetIssueComments(ctx, epic.ID)
+	if err != nil || len(comments) != 1 {
+		t.Fatalf("Expected one comment on the epic, got %v, %v", comments, err)
+	}
+	text := comments[0].Text
+	if comments[0].Author != Actor || !strings.Contains(text, "all 2 children") || !strings.Contains(text, "Open for 2d 2h") ||
+		!strings.Contains(text, docs.ID) || !strings.Contains(text, nested.ID) {
+		t.Errorf("Unexpected comment by %s:\n%s", comments[0].Author, text)
+	}
+
+	if again, err := Run(ctx, store, Actor, now, false); err != nil || len(again) != 0 {
+		t.Errorf("Expected nothing left to close, got %v, %v", again, err)
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New("")
+	defer store.Close()
+	if Enabled(ctx, store) {
+		t.Error("Expected autoclose off by default")
+	}
+	if err := store.SetConfig(ctx, ConfigKey, "true"); err != nil {
+		t.Fatal(err)
+	}
+	if !Enabled(ctx, store) {
+		t.Error("Expected autoclose on")
+	}
+}
+
+func TestFormatElapsed(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		90 * time.Minute:            "1h 30m",
+		50 * time.Hour:              "2d 2h",
+		-time.Minute:                "0h 0m",
+		10*24*time.Hour + time.Hour: "10d 1h",
+	} {
+		if got := FormatElapsed(d); got != want {
+			t.Errorf("FormatElapsed(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
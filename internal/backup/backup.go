@@ -0,0 +1,268 @@
+// Package backup keeps point-in-time snapshots of a beads database.
+//
+// Each backup is a consistent copy of the SQLite file, taken while the
+// database stays in use, plus a manifest recording when it was taken and the
+// snapshot's checksum. Backups live in a backups directory next to the
+// database, named by the UTC time they were taken, and can be copied to
+// S3-compatible storage (see S3) for recovery when the machine is lost.
+package backup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// IDFormat is the layout of backup IDs, the UTC time the backup was taken
+const IDFormat = "20060102T150405Z"
+
+// Manifest describes one backup
+type Manifest struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Version   string    `json:"version,omitempty"`   // bd version that took it
+	Database  string    `json:"database"`            // Snapshot file name, in the backup directory
+	Size      int64     `json:"size"`                // Snapshot size in bytes
+	SHA256    string    `json:"sha256"`              // Hex digest of the snapshot
+	Issues    int       `json:"issues"`              // Issue count when taken
+	Note      string    `json:"note,omitempty"`      // Why it was taken
+	Scheduled bool      `json:"scheduled,omitempty"` // Taken by bd serve --backup-interval, and rotated
+	Uploaded  string    `json:"uploaded,omitempty"`  // URL of the remote copy, if uploaded
+}
+
+// Snapshotter writes a consistent copy of a database to a new file
+type Snapshotter interface {
+	Snapshot(ctx context.Context, path string) error
+}
+
+// Options are the details recorded in a new backup's manifest
+type Options struct {
+	Version   string
+	Issues    int
+	Note      string
+	Scheduled bool
+}
+
+// Dir returns the backup directory for the database at dbPath
+func Dir(dbPath string) string {
+	return filepath.Join(filepath.Dir(dbPath), "backups")
+}
+
+// Create snapshots src into dir and writes its manifest
+func Create(ctx context.Context, src Snapshotter, dir string, opts Options) (*Manifest, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	// Backups sit inside .beads, so keep git from picking them up
+	ignore := filepath.Join(dir, ".gitignore")
+	if _, err := os.Stat(ignore); os.IsNotExist(err) {
+		if err := os.WriteFile(ignore, []byte("*\n"), 0600); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", ignore, err)
+		}
+	}
+
+	now := time.Now().UTC()
+	id := now.Format(IDFormat)
+	for n := 2; exists(filepath.Join(dir, id+".json")); n++ {
+		id = fmt.Sprintf("%s-%d", now.Format(IDFormat), n)
+	}
+	m := &Manifest{
+		ID:        id,
+		CreatedAt: now,
+		Version:   opts.Version,
+		Database:  id + ".db",
+		Issues:    opts.Issues,
+		Note:      opts.Note,
+		Scheduled: opts.Scheduled,
+	}
+
+	// Snapshot to a temp name so a failed backup never looks complete
+	tmp := filepath.Join(dir, m.Database+".tmp")
+	_ = os.Remove(tmp)
+	if err := src.Snapshot(ctx, tmp); err != nil {
+		_ = os.Remove(tmp)
+		return nil, fmt.Errorf("failed to snapshot database: %w", err)
+	}
+	size, sum, err := checksum(tmp)
+	if err != nil {
+		_ = os.Remove(tmp)
+		return nil, err
+	}
+	m.Size, m.SHA256 = size, sum
+	if err := os.Rename(tmp, filepath.Join(dir, m.Database)); err != nil {
+		_ = os.Remove(tmp)
+		return nil, fmt.Errorf("failed to save snapshot: %w", err)
+	}
+	if err := Save(dir, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Save writes m's manifest into dir
+func Save(dir string, m *Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, m.ID+".json")
+	if err := os.WriteFile(path+".tmp", append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// List returns the backups in dir, newest first
+func List(dir string) ([]*Manifest, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var manifests []*Manifest
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var m Manifest
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("invalid manifest %s: %w", path, err)
+		}
+		manifests = append(manifests, &m)
+	}
+	sort.Slice(manifests, func(i, j int) bool {
+		return manifests[i].CreatedAt.After(manifests[j].CreatedAt)
+	})
+	return manifests, nil
+}
+
+// Find returns the backup in dir with the given ID
+func Find(dir, id string) (*Manifest, error) {
+	manifests, err := List(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range manifests {
+		if m.ID == id {
+			return m, nil
+		}
+	}
+	return nil, fmt.Errorf("no backup %s in %s", id, dir)
+}
+
+// At returns the newest backup in dir taken at or before t, which is the
+// state to go back to for a point-in-time restore
+func At(dir string, t time.Time) (*Manifest, error) {
+	manifests, err := List(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range manifests {
+		if !m.CreatedAt.After(t) {
+			return m, nil
+		}
+	}
+	return nil, fmt.Errorf("no backup in %s taken at or before %s", dir, t.Format(time.RFC3339))
+}
+
+// Verify checks that m's snapshot is intact
+func Verify(dir string, m *Manifest) error {
+	size, sum, err := checksum(filepath.Join(dir, m.Database))
+	if err != nil {
+		return err
+	}
+	if size != m.Size || sum != m.SHA256 {
+		return fmt.Errorf("backup %s is corrupt: checksum does not match its manifest", m.ID)
+	}
+	return nil
+}
+
+// Restore replaces the database at dbPath with m's snapshot. Nothing may
+// have the database open: its WAL and shared-memory files are removed so
+// SQLite doesn't replay changes from before the restore onto the snapshot.
+func Restore(dir string, m *Manifest, dbPath string) error {
+	if err := Verify(dir, m); err != nil {
+		return err
+	}
+
+	src, err := os.Open(filepath.Join(dir, m.Database))
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	tmp := dbPath + ".restore.tmp"
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", tmp, err)
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to copy snapshot: %w", err)
+	}
+	if err := dst.Sync(); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	for _, suffix := range []string{"-wal", "-shm"} {
+		if err := os.Remove(dbPath + suffix); err != nil && !os.IsNotExist(err) {
+			_ = os.Remove(tmp)
+			return fmt.Errorf("failed to remove %s: %w", dbPath+suffix, err)
+		}
+	}
+	if err := os.Rename(tmp, dbPath); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to replace database: %w", err)
+	}
+	return nil
+}
+
+// ParseTime reads a --at time: RFC 3339, a date and time in local time, a
+// date (its end, so the day's last backup is chosen)
+func ParseTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02 15:04", "2006-01-02T15:04"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t.Add(24*time.Hour - time.Nanosecond), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (use RFC 3339, YYYY-MM-DD HH:MM, or YYYY-MM-DD)", s)
+}
+
+// checksum returns a file's size and hex SHA-256
+func checksum(path string) (int64, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return n, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,197 @@
+package backup
+
+import (
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+)
+
+// Status reports a scheduler's backups, for /status and /metrics
+type Status struct {
+	Interval    string     `json:"interval"`
+	Retain      int        `json:"retain"`
+	Last        *Manifest  `json:"last,omitempty"`         // Newest backup the scheduler took
+	LastAttempt *time.Time `json:"last_attempt,omitempty"` // When the last round finished
+	LastError   string     `json:"last_error,omitempty"`   // What the last round failed with
+	Failures    int64      `json:"failures"`               // Failed rounds since the server started
+	Retained    int        `json:"retained"`               // Scheduled backups on disk
+}
+
+// Scheduler takes a backup every interval while bd serve runs and keeps the
+// newest retain of them. Backups taken by hand are never rotated out.
+type Scheduler struct {
+	store    *sqlite.SQLiteStorage
+	interval time.Duration
+	retain   int
+	uploader *S3
+	version  string
+
+	mu          sync.Mutex
+	last        *Manifest
+	lastAttempt time.Time
+	lastErr     error
+	failures    int64
+	retained    int
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+}
+
+// NewScheduler creates a scheduler that backs store up every interval,
+// keeping retain scheduled backups (0 keeps them all) and uploading each
+// with uploader if it isn't nil. version is recorded in the manifests.
+func NewScheduler(store *sqlite.SQLiteStorage, interval time.Duration, retain int, uploader *S3, version string) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		store:    store,
+		interval: interval,
+		retain:   retain,
+		uploader: uploader,
+		version:  version,
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Start takes a backup every interval in the background. The first is
+// taken one interval after starting, so restarts don't pile up backups.
+func (s *Scheduler) Start() {
+	if _, kept, err := Rotate(Dir(s.store.Path()), 0); err == nil {
+		s.mu.Lock()
+		s.retained = kept
+		s.mu.Unlock()
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			if err := s.RunOnce(s.ctx); err != nil && s.ctx.Err() == nil {
+				log.Printf("backup: %v", err)
+			}
+		}
+	}()
+}
+
+// LastRun reports when the last round finished and what it failed with, if
+// anything
+func (s *Scheduler) LastRun() (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastAttempt, s.lastErr
+}
+
+// Status reports the scheduler's settings and last round
+func (s *Scheduler) Status() *Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := &Status{
+		Interval: s.interval.String(),
+		Retain:   s.retain,
+		Last:     s.last,
+		Failures: s.failures,
+		Retained: s.retained,
+	}
+	if !s.lastAttempt.IsZero() {
+		t := s.lastAttempt
+		status.LastAttempt = &t
+	}
+	if s.lastErr != nil {
+		status.LastError = s.lastErr.Error()
+	}
+	return status
+}
+
+// Close stops the scheduler and waits for a backup in progress to finish
+func (s *Scheduler) Close() {
+	s.stopOnce.Do(s.cancel)
+	s.wg.Wait()
+}
+
+// RunOnce takes and uploads a backup, then rotates out the oldest scheduled
+// backups beyond retain
+func (s *Scheduler) RunOnce(ctx context.Context) error {
+	m, err := s.backup(ctx)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastAttempt, s.lastErr = time.Now(), err
+	if err != nil {
+		s.failures++
+		return err
+	}
+	s.last = m
+	return nil
+}
+
+func (s *Scheduler) backup(ctx context.Context) (*Manifest, error) {
+	stats, err := s.store.GetStatistics(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dir := Dir(s.store.Path())
+	m, err := Create(ctx, s.store, dir, Options{Version: s.version, Issues: stats.TotalIssues, Scheduled: true})
+	if err != nil {
+		return nil, err
+	}
+	if s.uploader != nil {
+		if err := s.uploader.Upload(ctx, dir, m); err != nil {
+			return nil, err
+		}
+	}
+
+	removed, kept, err := Rotate(dir, s.retain)
+	if err != nil {
+		return nil, err
+	}
+	if len(removed) > 0 {
+		log.Printf("backup: took %s, rotated out %d old backup(s)", m.ID, len(removed))
+	} else {
+		log.Printf("backup: took %s", m.ID)
+	}
+	s.mu.Lock()
+	s.retained = kept
+	s.mu.Unlock()
+	return m, nil
+}
+
+// Rotate deletes all but the newest retain scheduled backups in dir, and
+// returns the IDs it deleted and how many scheduled backups remain. Uploaded
+// copies are left alone; expire them with the bucket's lifecycle rules.
+func Rotate(dir string, retain int) ([]string, int, error) {
+	manifests, err := List(dir)
+	if err != nil {
+		return nil, 0, err
+	}
+	var removed []string
+	kept := 0
+	for _, m := range manifests {
+		if !m.Scheduled {
+			continue
+		}
+		if retain <= 0 || kept < retain {
+			kept++
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, m.ID+".json")); err != nil {
+			return removed, kept, err
+		}
+		if err := os.Remove(filepath.Join(dir, m.Database)); err != nil && !os.IsNotExist(err) {
+			return removed, kept, err
+		}
+		removed = append(removed, m.ID)
+	}
+	return removed, kept, nil
+}
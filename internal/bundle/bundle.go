@@ -0,0 +1,458 @@
+// Package bundle reads and writes workspace bundles: a tar of JSONL streams
+// holding everything needed to move a workspace to another database or
+// storage backend, not just its issues.
+//
+// A bundle holds, in this order:
+//
+//	manifest.json   format, version, and the number of records per stream
+//	config.jsonl    {"key": ..., "value": ...} per config setting, except credentials
+//	labels.jsonl    label definitions (colors, descriptions, parents)
+//	issues.jsonl    issues with their labels and dependencies, as in the JSONL file
+//	comments.jsonl  comments with their original IDs, authors, and times
+//	events.jsonl    the audit trail of every issue
+//
+// Readers skip streams they don't know, so later versions can add streams
+// without breaking older bd.
+package bundle
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/digest"
+	"github.com/imalsogreg/beads/internal/importer"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// Format identifies a workspace bundle's manifest
+const Format = "beads-workspace"
+
+// Version is the bundle format version this package writes and the newest it
+// reads
+const Version = 1
+
+// Stream file names
+const (
+	manifestFile = "manifest.json"
+	configFile   = "config.jsonl"
+	labelsFile   = "labels.jsonl"
+	issuesFile   = "issues.jsonl"
+	commentsFile = "comments.jsonl"
+	eventsFile   = "events.jsonl"
+)
+
+// Manifest describes a bundle
+type Manifest struct {
+	Format    string         `json:"format"`
+	Version   int            `json:"version"`
+	CreatedAt time.Time      `json:"created_at"`
+	BdVersion string         `json:"bd_version,omitempty"`
+	Prefix    string         `json:"prefix,omitempty"`
+	Streams   map[string]int `json:"streams"` // Records per stream file
+}
+
+// ConfigEntry is one config setting
+type ConfigEntry struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Bundle is a workspace's contents
+type Bundle struct {
+	Manifest Manifest
+	Config   []ConfigEntry
+	Labels   []*sqlite.Label
+	Issues   []*types.Issue
+	Comments []*types.Comment
+	Events   []*types.Event
+
+	Skipped []string // Streams Read didn't recognize
+}
+
+// Collect reads a workspace's contents from st. bdVersion is recorded in
+// the manifest.
+func Collect(ctx context.Context, st *sqlite.SQLiteStorage, bdVersion string) (*Bundle, error) {
+	b := &Bundle{}
+
+	config, err := st.GetAllConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	for key, value := range config {
+		// Credentials like smtp.password stay behind; set them again where the bundle lands
+		if digest.IsSecretConfig(key) {
+			continue
+		}
+		b.Config = append(b.Config, ConfigEntry{Key: key, Value: value})
+	}
+	sort.Slice(b.Config, func(i, j int) bool { return b.Config[i].Key < b.Config[j].Key })
+
+	labels, err := st.ListLabels(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read labels: %w", err)
+	}
+	for _, l := range labels {
+		if l.Defined {
+			b.Labels = append(b.Labels, l)
+		}
+	}
+
+	issues, err := st.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read issues: %w", err)
+	}
+	sort.Slice(issues, func(i, j int) bool { return issues[i].ID < issues[j].ID })
+	deps, err := st.GetAllDependencyRecords(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dependencies: %w", err)
+	}
+	for _, issue := range issues {
+		issue.Dependencies = deps[issue.ID]
+		if issue.Labels, err = st.GetLabels(ctx, issue.ID); err != nil {
+			return nil, fmt.Errorf("failed to read labels for %s: %w", issue.ID, err)
+		}
+		exported, err := st.EncryptForExport(issue)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt %s: %w", issue.ID, err)
+		}
+		b.Issues = append(b.Issues, exported)
+	}
+
+	if b.Comments, err = st.ExportComments(ctx); err != nil {
+		return nil, err
+	}
+	if b.Events, err = st.ExportEvents(ctx); err != nil {
+		return nil, err
+	}
+
+	b.Manifest = Manifest{
+		Format:    Format,
+		Version:   Version,
+		CreatedAt: time.Now().UTC(),
+		BdVersion: bdVersion,
+		Prefix:    config["issue_prefix"],
+	}
+	return b, nil
+}
+
+// Write writes b as a tar, gzipped if compress is set, filling in the
+// manifest's stream counts
+func Write(w io.Writer, b *Bundle, compress bool) error {
+	var gz *gzip.Writer
+	if compress {
+		gz = gzip.NewWriter(w)
+		w = gz
+	}
+	tw := tar.NewWriter(w)
+
+	type stream struct {
+		name    string
+		records int
+		data    []byte
+	}
+	var streams []stream
+	add := func(name string, records interface{}, n int) error {
+		data, err := encodeJSONL(records)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", name, err)
+		}
+		streams = append(streams, stream{name, n, data})
+		return nil
+	}
+	if err := add(configFile, b.Config, len(b.Config)); err != nil {
+		return err
+	}
+	if err := add(labelsFile, b.Labels, len(b.Labels)); err != nil {
+		return err
+	}
+	if err := add(issuesFile, b.Issues, len(b.Issues)); err != nil {
+		return err
+	}
+	if err := add(commentsFile, b.Comments, len(b.Comments)); err != nil {
+		return err
+	}
+	if err := add(eventsFile, b.Events, len(b.Events)); err != nil {
+		return err
+	}
+
+	b.Manifest.Streams = make(map[string]int)
+	for _, s := range streams {
+		b.Manifest.Streams[s.name] = s.records
+	}
+	manifest, err := json.MarshalIndent(b.Manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	modTime := b.Manifest.CreatedAt
+	if err := writeEntry(tw, manifestFile, append(manifest, '\n'), modTime); err != nil {
+		return err
+	}
+	for _, s := range streams {
+		if err := writeEntry(tw, s.name, s.data, modTime); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if gz != nil {
+		return gz.Close()
+	}
+	return nil
+}
+
+func writeEntry(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	hdr := &tar.Header{Name: name, Mode: 0600, Size: int64(len(data)), ModTime: modTime, Typeflag: tar.TypeReg}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// encodeJSONL encodes a slice as one JSON value per line
+func encodeJSONL(records interface{}) ([]byte, error) {
+	raw, err := json.Marshal(records)
+	if err != nil {
+		return nil, err
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	for _, item := range items {
+		buf.Write(item)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// Read reads a bundle written by Write, gzipped or not
+func Read(r io.Reader) (*Bundle, error) {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+	} else {
+		r = br
+	}
+
+	b := &Bundle{}
+	haveManifest := false
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		switch hdr.Name {
+		case manifestFile:
+			if err := json.NewDecoder(tr).Decode(&b.Manifest); err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", hdr.Name, err)
+			}
+			if b.Manifest.Format != Format {
+				return nil, fmt.Errorf("not a workspace bundle (format %q)", b.Manifest.Format)
+			}
+			if b.Manifest.Version > Version {
+				return nil, fmt.Errorf("bundle format version %d was written by a newer bd (this one reads up to %d)", b.Manifest.Version, Version)
+			}
+			haveManifest = true
+		case configFile:
+			err = decodeJSONL(tr, &b.Config)
+		case labelsFile:
+			err = decodeJSONL(tr, &b.Labels)
+		case issuesFile:
+			err = decodeJSONL(tr, &b.Issues)
+		case commentsFile:
+			err = decodeJSONL(tr, &b.Comments)
+		case eventsFile:
+			err = decodeJSONL(tr, &b.Events)
+		default:
+			b.Skipped = append(b.Skipped, hdr.Name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", hdr.Name, err)
+		}
+	}
+	if !haveManifest {
+		return nil, fmt.Errorf("not a workspace bundle (no %s)", manifestFile)
+	}
+	return b, nil
+}
+
+// decodeJSONL appends one value per line to the slice out points to
+func decodeJSONL[T any](r io.Reader, out *[]T) error {
+	dec := json.NewDecoder(r)
+	for {
+		var v T
+		if err := dec.Decode(&v); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		*out = append(*out, v)
+	}
+}
+
+// Result reports what Apply imported
+type Result struct {
+	Issues     *importer.Result
+	Config     int
+	KeptPrefix string // The workspace's own issue prefix, kept over the bundle's because it already has issues
+	Labels     int
+	Comments   int
+	Events     int
+}
+
+// Apply imports b into st: config, then label definitions, issues (through
+// the importer, with opts), comments, and the history of each issue it
+// creates. Issues that already existed keep their own history.
+func Apply(ctx context.Context, st *sqlite.SQLiteStorage, b *Bundle, opts importer.Options) (*Result, error) {
+	result := &Result{}
+
+	ids, err := st.GetAllIssueIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		existing[id] = true
+	}
+
+	// A workspace with issues keeps its prefix; an empty one takes the
+	// bundle's, so the issues import under their own IDs
+	for _, c := range b.Config {
+		if c.Key == "issue_prefix" && len(existing) > 0 {
+			if prefix, err := st.GetConfig(ctx, c.Key); err == nil && prefix != "" {
+				if prefix != c.Value {
+					result.KeptPrefix = prefix
+				}
+				continue
+			}
+		}
+		if err := st.SetConfig(ctx, c.Key, c.Value); err != nil {
+			return result, fmt.Errorf("failed to set config %s: %w", c.Key, err)
+		}
+		result.Config++
+	}
+
+	if result.Labels, err = applyLabels(ctx, st, b.Labels); err != nil {
+		return result, err
+	}
+
+	if result.Issues, err = importer.ImportIssues(ctx, st.Path(), st, b.Issues, opts); err != nil {
+		return result, err
+	}
+	newID := func(id string) string {
+		if mapped, ok := result.Issues.IDMapping[id]; ok {
+			return mapped
+		}
+		return id
+	}
+
+	comments := make([]*types.Comment, 0, len(b.Comments))
+	for _, c := range b.Comments {
+		cc := *c
+		cc.IssueID = newID(c.IssueID)
+		comments = append(comments, &cc)
+	}
+	if result.Comments, err = st.ImportComments(ctx, comments); err != nil {
+		return result, err
+	}
+
+	var events []*types.Event
+	for _, e := range b.Events {
+		id := newID(e.IssueID)
+		if existing[id] {
+			continue
+		}
+		ee := *e
+		ee.IssueID = id
+		events = append(events, &ee)
+	}
+	if result.Events, err = st.ReplaceEvents(ctx, events); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// applyLabels defines the labels st doesn't have yet. Parents are defined
+// before their children, whatever order the labels come in.
+func applyLabels(ctx context.Context, st *sqlite.SQLiteStorage, labels []*sqlite.Label) (int, error) {
+	current, err := st.ListLabels(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defined := make(map[string]bool)
+	for _, l := range current {
+		if l.Defined {
+			defined[l.Name] = true
+		}
+	}
+
+	pending := make([]*sqlite.Label, 0, len(labels))
+	for _, l := range labels {
+		if !defined[l.Name] {
+			pending = append(pending, l)
+		}
+	}
+	added := 0
+	for len(pending) > 0 {
+		var retry []*sqlite.Label
+		var lastErr error
+		for _, l := range pending {
+			if l.Parent != "" && !defined[l.Parent] && containsLabel(pending, l.Parent) {
+				retry = append(retry, l)
+				continue
+			}
+			label := &sqlite.Label{Name: l.Name, Color: l.Color, Description: l.Description, Parent: l.Parent, CreatedBy: l.CreatedBy}
+			if err := st.CreateLabel(ctx, label); err != nil {
+				lastErr = fmt.Errorf("failed to define label %s: %w", l.Name, err)
+				continue
+			}
+			defined[l.Name] = true
+			added++
+		}
+		if lastErr != nil {
+			return added, lastErr
+		}
+		if len(retry) == len(pending) {
+			return added, fmt.Errorf("label parents form a cycle: %s", retry[0].Name)
+		}
+		pending = retry
+	}
+	return added, nil
+}
+
+func containsLabel(labels []*sqlite.Label, name string) bool {
+	for _, l := range labels {
+		if l.Name == name {
+			return true
+		}
+	}
+	return false
+}
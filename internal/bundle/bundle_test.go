@@ -0,0 +1,199 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/digest"
+	"github.com/imalsogreg/beads/internal/importer"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func newStore(t *testing.T, prefix string) *sqlite.SQLiteStorage {
+	t.Helper()
+	st, err := sqlite.New(filepath.Join(t.TempDir(), "beads.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+	if err := st.SetConfig(context.Background(), "issue_prefix", prefix); err != nil {
+		t.Fatal(err)
+	}
+	return st
+}
+
+func TestRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	src := newStore(t, "src")
+	if err := src.SetConfig(ctx, "team", "rockets"); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.SetConfig(ctx, digest.SMTPPasswordKey, "hunter2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.CreateLabel(ctx, &sqlite.Label{Name: "area", Color: "#00ff00"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.CreateLabel(ctx, &sqlite.Label{Name: "area/ui", Description: "UI work"}); err != nil {
+		t.Fatal(err)
+	}
+	parent := &types.Issue{Title: "Parent", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeEpic}
+	child := &types.Issue{Title: "Child", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	for _, issue := range []*types.Issue{parent, child} {
+		if err := src.CreateIssue(ctx, issue, "alice"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := src.AddDependency(ctx, &types.Dependency{IssueID: child.ID, DependsOnID: parent.ID, Type: types.DepBlocks}, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.AddLabel(ctx, child.ID, "area/ui", "alice"); err != nil {
+		t.Fatal(err)
+	}
+	comment, err := src.AddIssueComment(ctx, child.ID, "bob", "Looks good")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := src.UpdateIssue(ctx, child.ID, map[string]interface{}{"status": string(types.StatusInProgress)}, "bob"); err != nil {
+		t.Fatal(err)
+	}
+	srcEvents, err := src.GetEvents(ctx, child.ID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := Collect(ctx, src, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range b.Config {
+		if entry.Key == digest.SMTPPasswordKey {
+			t.Errorf("Expected the SMTP password left out of the bundle, got %+v", entry)
+		}
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, b, true); err != nil {
+		t.Fatal(err)
+	}
+	read, err := Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.Manifest.Prefix != "src" || read.Manifest.Streams[issuesFile] != 2 || read.Manifest.Streams[commentsFile] != 1 {
+		t.Errorf("Unexpected manifest %+v", read.Manifest)
+	}
+
+	// An empty workspace takes the bundle's prefix
+	dst := newStore(t, "dst")
+	result, err := Apply(ctx, dst, read, importer.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Issues.Created != 2 || result.Comments != 1 || result.Labels != 2 || result.KeptPrefix != "" {
+		t.Errorf("Unexpected result %+v (issues %+v)", result, result.Issues)
+	}
+	if prefix, _ := dst.GetConfig(ctx, "issue_prefix"); prefix != "src" {
+		t.Errorf("Expected the bundle's prefix, got %q", prefix)
+	}
+	if team, _ := dst.GetConfig(ctx, "team"); team != "rockets" {
+		t.Errorf("Expected config carried over, got %q", team)
+	}
+	if password, _ := dst.GetConfig(ctx, digest.SMTPPasswordKey); password != "" {
+		t.Errorf("Expected no SMTP password carried over, got %q", password)
+	}
+	if label, err := dst.GetLabel(ctx, "area/ui"); err != nil || label == nil || label.Description != "UI work" || label.Parent != "area" {
+		t.Errorf("Expected the label definition carried over, got %+v, %v", label, err)
+	}
+
+	got, err := dst.GetIssue(ctx, child.ID)
+	if err != nil || got == nil || got.Status != types.StatusInProgress {
+		t.Fatalf("Expected the child imported in progress, got %+v, %v", got, err)
+	}
+	if labels, _ := dst.GetLabels(ctx, child.ID); len(labels) != 1 || labels[0] != "area/ui" {
+		t.Errorf("Expected the child's label, got %v", labels)
+	}
+	if deps, _ := dst.GetDependencyRecords(ctx, child.ID); len(deps) != 1 || deps[0].DependsOnID != parent.ID {
+		t.Errorf("Expected the child's dependency, got %v", deps)
+	}
+	comments, err := dst.GetIssueComments(ctx, child.ID)
+	if err != nil || len(comments) != 1 || comments[0].Author != "bob" || !comments[0].CreatedAt.Equal(comment.CreatedAt) {
+		t.Errorf("Expected the comment with its author and time, got %+v, %v", comments, err)
+	}
+	events, err := dst.GetEvents(ctx, child.ID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != len(srcEvents) {
+		t.Fatalf("Expected the child's %d events in place of the import's, got %d", len(srcEvents), len(events))
+	}
+	for i := range events {
+		if events[i].EventType != srcEvents[i].EventType || events[i].Actor != srcEvents[i].Actor || !events[i].CreatedAt.Equal(srcEvents[i].CreatedAt) {
+			t.Errorf("Event %d = %+v, want %+v", i, events[i], srcEvents[i])
+		}
+	}
+
+	// Applying again changes nothing
+	again, err := Apply(ctx, dst, read, importer.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Issues.Created != 0 || again.Comments != 0 || again.Events != 0 || again.Labels != 0 {
+		t.Errorf("Expected a second apply to add nothing, got %+v", again)
+	}
+}
+
+func TestApplyKeepsPrefixOfNonEmptyWorkspace(t *testing.T) {
+	ctx := context.Background()
+	dst := newStore(t, "dst")
+	if err := dst.CreateIssue(ctx, &types.Issue{Title: "Mine", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	b := &Bundle{Config: []ConfigEntry{{Key: "issue_prefix", Value: "src"}}}
+	result, err := Apply(ctx, dst, b, importer.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.KeptPrefix != "dst" {
+		t.Errorf("Expected the workspace's prefix kept, got %+v", result)
+	}
+	if prefix, _ := dst.GetConfig(ctx, "issue_prefix"); prefix != "dst" {
+		t.Errorf("Expected prefix dst, got %q", prefix)
+	}
+}
+
+func TestReadSkipsUnknownStreamsAndRefusesNewerVersions(t *testing.T) {
+	write := func(manifest string, extra string) *bytes.Buffer {
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		entries := [][2]string{{manifestFile, manifest}, {issuesFile, `{"id":"x-1","title":"One"}` + "\n"}}
+		if extra != "" {
+			entries = append(entries, [2]string{extra, "{}\n"})
+		}
+		for _, e := range entries {
+			_ = tw.WriteHeader(&tar.Header{Name: e[0], Mode: 0600, Size: int64(len(e[1])), Typeflag: tar.TypeReg})
+			_, _ = tw.Write([]byte(e[1]))
+		}
+		_ = tw.Close()
+		return &buf
+	}
+
+	b, err := Read(write(`{"format":"beads-workspace","version":1}`, "custom_fields.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b.Issues) != 1 || len(b.Skipped) != 1 || b.Skipped[0] != "custom_fields.jsonl" {
+		t.Errorf("Expected one issue and the unknown stream skipped, got %+v", b)
+	}
+
+	if _, err := Read(write(`{"format":"beads-workspace","version":99}`, "")); err == nil || !strings.Contains(err.Error(), "newer bd") {
+		t.Errorf("Expected a newer format refused, got %v", err)
+	}
+	if _, err := Read(write(`{"format":"something-else","version":1}`, "")); err == nil {
+		t.Error("Expected another format refused")
+	}
+}
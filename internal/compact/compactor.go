@@ -25,6 +25,7 @@ type Compactor struct {
 	store  *sqlite.SQLiteStorage
 	haiku  *HaikuClient
 	config *Config
+	dryRun bool // DryRun as requested; without an API key config.DryRun is forced on for Tier 1 only
 }
 
 // New creates a new Compactor instance with the given configuration.
@@ -41,6 +42,7 @@ func New(store *sqlite.SQLiteStorage, apiKey string, config *Config) (*Compactor
 		config.APIKey = apiKey
 	}
 
+	dryRun := config.DryRun
 	var haikuClient *HaikuClient
 	var err error
 	if !config.DryRun {
@@ -58,9 +60,16 @@ func New(store *sqlite.SQLiteStorage, apiKey string, config *Config) (*Compactor
 		store:  store,
 		haiku:  haikuClient,
 		config: config,
+		dryRun: dryRun,
 	}, nil
 }
 
+// CanSummarize reports whether Tier 1 compaction can run, i.e. an API key
+// was available. Tier 2 doesn't need one.
+func (c *Compactor) CanSummarize() bool {
+	return c.haiku != nil
+}
+
 // Result holds the outcome of a compaction operation.
 type Result struct {
 	IssueID       string
@@ -286,3 +295,60 @@ func (c *Compactor) compactSingleWithResult(ctx context.Context, issueID string,
 
 	return nil
 }
+
+// CompactTier2 performs tier-2 compaction on a single issue. It needs no API
+// key: the Tier 1 summary is cut to one sentence and verbose events dropped.
+func (c *Compactor) CompactTier2(ctx context.Context, issueID string) error {
+	result := &Result{IssueID: issueID}
+	if err := c.compactTier2WithResult(ctx, issueID, result); err != nil {
+		return err
+	}
+	if c.dryRun {
+		return fmt.Errorf("dry-run: would compact %s (original size: %d bytes)", issueID, result.OriginalSize)
+	}
+	return nil
+}
+
+// CompactTier2Batch performs tier-2 compaction on multiple issues. Tier 2 is
+// local, so issues are compacted one at a time.
+func (c *Compactor) CompactTier2Batch(ctx context.Context, issueIDs []string) ([]*Result, error) {
+	results := make([]*Result, 0, len(issueIDs))
+	for _, id := range issueIDs {
+		if ctx.Err() != nil {
+			return results, ctx.Err()
+		}
+		result := &Result{IssueID: id}
+		if err := c.compactTier2WithResult(ctx, id, result); err != nil {
+			result.Err = err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (c *Compactor) compactTier2WithResult(ctx context.Context, issueID string, result *Result) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	eligible, reason, err := c.store.CheckEligibility(ctx, issueID, 2)
+	if err != nil {
+		return fmt.Errorf("failed to verify eligibility: %w", err)
+	}
+	if !eligible {
+		return fmt.Errorf("not eligible for Tier 2 compaction: %s", reason)
+	}
+
+	issue, err := c.store.GetIssue(ctx, issueID)
+	if err != nil {
+		return fmt.Errorf("failed to get issue: %w", err)
+	}
+	result.OriginalSize = len(issue.Description) + len(issue.Design) + len(issue.Notes) + len(issue.AcceptanceCriteria)
+
+	if c.dryRun {
+		return nil
+	}
+
+	result.CompactedSize, err = c.store.CompactTier2(ctx, issueID, GetCurrentCommitHash())
+	return err
+}
@@ -336,6 +336,67 @@ func TestCompactTier1Batch_WithIneligible(t *testing.T) {
 	}
 }
 
+func TestCompactTier2Batch_NoAPIKey(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	store := setupTestStorage(t)
+	defer store.Close()
+
+	ctx := context.Background()
+	for key, value := range map[string]string{"compact_tier2_days": "0", "compact_tier2_commits": "0"} {
+		if err := store.SetConfig(ctx, key, value); err != nil {
+			t.Fatalf("failed to set config: %v", err)
+		}
+	}
+
+	tier1 := createClosedIssue(t, store, "bd-tier1")
+	if err := store.ApplyCompaction(ctx, tier1.ID, 1, 2000, 400, ""); err != nil {
+		t.Fatalf("failed to apply compaction: %v", err)
+	}
+	uncompacted := createClosedIssue(t, store, "bd-raw")
+
+	c, err := New(store, "", nil)
+	if err != nil {
+		t.Fatalf("failed to create compactor: %v", err)
+	}
+	if c.CanSummarize() {
+		t.Fatal("expected no summarizer without an API key")
+	}
+
+	results, err := c.CompactTier2Batch(ctx, []string{tier1.ID, uncompacted.ID})
+	if err != nil {
+		t.Fatalf("failed to batch compact: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	for _, result := range results {
+		switch result.IssueID {
+		case uncompacted.ID:
+			if result.Err == nil {
+				t.Error("expected error for issue not at level 1")
+			}
+		case tier1.ID:
+			if result.Err != nil {
+				t.Fatalf("unexpected error: %v", result.Err)
+			}
+			if result.CompactedSize >= result.OriginalSize {
+				t.Errorf("expected compacted size below %d, got %d", result.OriginalSize, result.CompactedSize)
+			}
+		}
+	}
+
+	after, err := store.GetIssue(ctx, tier1.ID)
+	if err != nil {
+		t.Fatalf("failed to get issue: %v", err)
+	}
+	if after.CompactionLevel != 2 {
+		t.Errorf("expected compaction level 2, got %d", after.CompactionLevel)
+	}
+	if after.Design != "" || after.Notes != "" || after.AcceptanceCriteria != "" {
+		t.Error("expected design, notes, and acceptance criteria cleared")
+	}
+}
+
 func TestCompactTier1Batch_WithAPI(t *testing.T) {
 	if os.Getenv("ANTHROPIC_API_KEY") == "" {
 		t.Skip("ANTHROPIC_API_KEY not set, skipping API test")
@@ -0,0 +1,145 @@
+package compact
+
+import (
+	"context"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+)
+
+// Scheduler compacts eligible issues and purges expired trash periodically
+// while bd serve runs. Tier 1 only runs when ANTHROPIC_API_KEY is set.
+type Scheduler struct {
+	store    *sqlite.SQLiteStorage
+	interval time.Duration
+
+	mu      sync.Mutex
+	lastRun time.Time
+	lastErr error
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+}
+
+// NewScheduler creates a scheduler that runs a round every interval
+func NewScheduler(store *sqlite.SQLiteStorage, interval time.Duration) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		store:    store,
+		interval: interval,
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Start runs a round now and then every interval in the background
+func (s *Scheduler) Start() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			err := s.RunOnce(s.ctx)
+			if err != nil && s.ctx.Err() == nil {
+				log.Printf("compact: %v", err)
+			}
+			s.mu.Lock()
+			s.lastRun, s.lastErr = time.Now(), err
+			s.mu.Unlock()
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// LastRun reports when the last round finished and what it failed with, if
+// anything
+func (s *Scheduler) LastRun() (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastRun, s.lastErr
+}
+
+// Close stops the scheduler and waits for a round in progress to finish
+func (s *Scheduler) Close() {
+	s.stopOnce.Do(s.cancel)
+	s.wg.Wait()
+}
+
+// RunOnce purges expired trash, then compacts every Tier 1 and Tier 2
+// candidate. Failures on single issues are logged, not returned.
+func (s *Scheduler) RunOnce(ctx context.Context) error {
+	purged, err := PurgeExpiredTrash(ctx, s.store, false)
+	if err != nil {
+		return err
+	}
+	if len(purged) > 0 {
+		log.Printf("compact: purged %d issue(s) from the trash", len(purged))
+	}
+
+	compactor, err := New(s.store, os.Getenv("ANTHROPIC_API_KEY"), nil)
+	if err != nil {
+		return err
+	}
+
+	// A page of candidates at a time, so a large backlog isn't loaded whole
+	if compactor.CanSummarize() {
+		err := s.store.ForEachCandidatePage(ctx, 1, func(page []*sqlite.CompactionCandidate) error {
+			results, err := compactor.CompactTier1Batch(ctx, CandidateIDs(page))
+			logResults(1, results)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.store.ForEachCandidatePage(ctx, 2, func(page []*sqlite.CompactionCandidate) error {
+		results, err := compactor.CompactTier2Batch(ctx, CandidateIDs(page))
+		logResults(2, results)
+		return err
+	})
+}
+
+// PurgeExpiredTrash permanently deletes issues that have been in the trash
+// longer than compact_trash_days (0 disables) and returns their IDs. With
+// dryRun nothing is deleted.
+func PurgeExpiredTrash(ctx context.Context, store *sqlite.SQLiteStorage, dryRun bool) ([]string, error) {
+	days, err := store.TrashDays(ctx)
+	if err != nil || days == 0 {
+		return nil, err
+	}
+	return store.PurgeTrash(ctx, time.Now().AddDate(0, 0, -days), dryRun)
+}
+
+// CandidateIDs returns the IDs of candidates
+func CandidateIDs(candidates []*sqlite.CompactionCandidate) []string {
+	ids := make([]string, len(candidates))
+	for i, c := range candidates {
+		ids[i] = c.IssueID
+	}
+	return ids
+}
+
+func logResults(tier int, results []*Result) {
+	compacted := 0
+	for _, r := range results {
+		if r.Err != nil {
+			log.Printf("compact: tier %d %s: %v", tier, r.IssueID, r.Err)
+			continue
+		}
+		compacted++
+	}
+	if compacted > 0 {
+		log.Printf("compact: compacted %d issue(s) at tier %d", compacted, tier)
+	}
+}
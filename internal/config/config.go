@@ -17,11 +17,14 @@ var v *viper.Viper
 func Initialize() error {
 	v = viper.New()
 
-	// Set config file name and type
-	v.SetConfigName("config")
+	// Set config file type
 	v.SetConfigType("yaml")
 
-	// Add config search paths (in order of precedence)
+	// Config file search paths (in order of precedence). Only config.yaml
+	// counts: .beads/ also holds bd's own config.json, which viper's search
+	// by name would otherwise pick up first.
+	var searchDirs []string
+
 	// 1. Walk up from CWD to find project .beads/ directory
 	//    This allows commands to work from subdirectories
 	cwd, err := os.Getwd()
@@ -32,28 +35,36 @@ func Initialize() error {
 			configPath := filepath.Join(beadsDir, "config.yaml")
 			if _, err := os.Stat(configPath); err == nil {
 				// Found .beads/config.yaml - add this path
-				v.AddConfigPath(beadsDir)
+				searchDirs = append(searchDirs, beadsDir)
 				break
 			}
 			// Also check if .beads directory exists (even without config.yaml)
 			if info, err := os.Stat(beadsDir); err == nil && info.IsDir() {
-				v.AddConfigPath(beadsDir)
+				searchDirs = append(searchDirs, beadsDir)
 				break
 			}
 		}
 		
 		// Also add CWD/.beads for backward compatibility
-		v.AddConfigPath(filepath.Join(cwd, ".beads"))
+		searchDirs = append(searchDirs, filepath.Join(cwd, ".beads"))
 	}
 
 	// 2. User config directory (~/.config/bd/)
 	if configDir, err := os.UserConfigDir(); err == nil {
-		v.AddConfigPath(filepath.Join(configDir, "bd"))
+		searchDirs = append(searchDirs, filepath.Join(configDir, "bd"))
 	}
 
 	// 3. Home directory (~/.beads/)
 	if homeDir, err := os.UserHomeDir(); err == nil {
-		v.AddConfigPath(filepath.Join(homeDir, ".beads"))
+		searchDirs = append(searchDirs, filepath.Join(homeDir, ".beads"))
+	}
+
+	configFile := ""
+	for _, dir := range searchDirs {
+		if _, err := os.Stat(filepath.Join(dir, "config.yaml")); err == nil {
+			configFile = filepath.Join(dir, "config.yaml")
+			break
+		}
 	}
 
 	// Automatic environment variable binding
@@ -72,26 +83,37 @@ func Initialize() error {
 	v.SetDefault("no-auto-flush", false)
 	v.SetDefault("no-auto-import", false)
 	v.SetDefault("no-db", false)
+	v.SetDefault("ephemeral", false)
 	v.SetDefault("db", "")
 	v.SetDefault("actor", "")
+	v.SetDefault("session", "")
 	v.SetDefault("issue-prefix", "")
 	
 	// Additional environment variables (not prefixed with BD_)
 	// These are bound explicitly for backward compatibility
 	_ = v.BindEnv("flush-debounce", "BEADS_FLUSH_DEBOUNCE")
 	_ = v.BindEnv("auto-start-daemon", "BEADS_AUTO_START_DAEMON")
+	_ = v.BindEnv("remote", "BD_REMOTE", "BEADS_REMOTE")
+	_ = v.BindEnv("remote-token", "BD_REMOTE_TOKEN", "BEADS_REMOTE_TOKEN")
 	
 	// Set defaults for additional settings
 	v.SetDefault("flush-debounce", "30s")
 	v.SetDefault("auto-start-daemon", true)
-
-	// Read config file if it exists (don't error if not found)
-	if err := v.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-			// Config file found but another error occurred
+	v.SetDefault("remote", "")
+	v.SetDefault("remote-token", "")
+
+	// SQLite connection tuning, e.g. BD_SQLITE_READ_CONNECTIONS
+	v.SetDefault("sqlite.read-connections", 4)
+	v.SetDefault("sqlite.busy-timeout", "30s")
+	v.SetDefault("sqlite.synchronous", "full")
+	v.SetDefault("sqlite.wal-autocheckpoint", 0)
+
+	// Read config file if it exists (no config file is ok, we'll use defaults)
+	if configFile != "" {
+		v.SetConfigFile(configFile)
+		if err := v.ReadInConfig(); err != nil {
 			return fmt.Errorf("error reading config file: %w", err)
 		}
-		// Config file not found - this is ok, we'll use defaults
 	}
 
 	return nil
@@ -129,6 +151,24 @@ func GetDuration(key string) time.Duration {
 	return v.GetDuration(key)
 }
 
+// GetStringMapString retrieves a map of strings, such as a table in the
+// config file
+func GetStringMapString(key string) map[string]string {
+	if v == nil {
+		return map[string]string{}
+	}
+	return v.GetStringMapString(key)
+}
+
+// GetStringMap retrieves a table from the config file whose values may
+// themselves be tables
+func GetStringMap(key string) map[string]interface{} {
+	if v == nil {
+		return map[string]interface{}{}
+	}
+	return v.GetStringMap(key)
+}
+
 // Set sets a configuration value
 func Set(key string, value interface{}) {
 	if v != nil {
@@ -0,0 +1,245 @@
+// Package depgraph renders issue dependency graphs as Graphviz DOT or Mermaid.
+package depgraph
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/imalsogreg/beads/internal/storage"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// Output formats
+const (
+	FormatDOT     = "dot"
+	FormatMermaid = "mermaid"
+	FormatGraph   = "graph" // the Graph itself as JSON, for clients that draw their own
+)
+
+// Graph is a set of issues and the dependencies between them
+type Graph struct {
+	Root   string              `json:"root,omitempty"` // optional issue to emphasize, e.g. the root of a tree
+	Issues []*types.Issue      `json:"issues"`         // nodes, in output order
+	Edges  []*types.Dependency `json:"edges"`          // only dependencies with both ends in Issues
+}
+
+// Build loads the dependencies among issues. Dependencies on issues outside
+// the set are dropped.
+func Build(ctx context.Context, store storage.Storage, issues []*types.Issue) (*Graph, error) {
+	inGraph := make(map[string]bool, len(issues))
+	for _, issue := range issues {
+		inGraph[issue.ID] = true
+	}
+
+	g := &Graph{Issues: issues}
+	for _, issue := range issues {
+		deps, err := store.GetDependencyRecords(ctx, issue.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get dependencies of %s: %w", issue.ID, err)
+		}
+		for _, dep := range deps {
+			if inGraph[dep.DependsOnID] {
+				g.Edges = append(g.Edges, dep)
+			}
+		}
+	}
+	return g, nil
+}
+
+// ForTree builds the graph of everything rootID depends on, or everything
+// that depends on it when reverse is set, down to maxDepth
+func ForTree(ctx context.Context, store storage.Storage, rootID string, maxDepth int, reverse bool) (*Graph, error) {
+	tree, err := store.GetDependencyTree(ctx, rootID, maxDepth, false, reverse)
+	if err != nil {
+		return nil, err
+	}
+	if len(tree) == 0 {
+		return nil, fmt.Errorf("issue %s not found", rootID)
+	}
+
+	issues := make([]*types.Issue, len(tree))
+	for i, node := range tree {
+		issue := node.Issue
+		issues[i] = &issue
+	}
+
+	g, err := Build(ctx, store, issues)
+	if err != nil {
+		return nil, err
+	}
+	g.Root = rootID
+	return g, nil
+}
+
+// Write renders g in the given format
+func Write(w io.Writer, g *Graph, format string) error {
+	switch format {
+	case FormatDOT:
+		return WriteDOT(w, g)
+	case FormatMermaid:
+		return WriteMermaid(w, g)
+	default:
+		return fmt.Errorf("unknown graph format %q (use dot or mermaid)", format)
+	}
+}
+
+// statusColors are the fill and font colors for each status
+var statusColors = map[types.Status][2]string{
+	types.StatusOpen:       {"white", "black"},
+	types.StatusInProgress: {"lightyellow", "black"},
+	types.StatusBlocked:    {"lightcoral", "black"},
+	types.StatusClosed:     {"lightgray", "dimgray"},
+}
+
+// dotShapes gives each issue type a distinct node shape
+var dotShapes = map[types.IssueType]string{
+	types.TypeEpic:    "folder",
+	types.TypeFeature: "component",
+	types.TypeTask:    "box",
+	types.TypeBug:     "octagon",
+	types.TypeChore:   "note",
+}
+
+// edgeStyles are the DOT color and style for each dependency type
+var edgeStyles = map[types.DependencyType][2]string{
+	types.DepBlocks:         {"red", "bold"},
+	types.DepParentChild:    {"blue", "solid"},
+	types.DepDiscoveredFrom: {"green", "dashed"},
+	types.DepRelated:        {"gray", "dashed"},
+	types.DepDuplicates:     {"gray", "dotted"},
+	types.DepCausedBy:       {"orange", "dashed"},
+}
+
+// WriteDOT renders g as a Graphviz digraph. Nodes are filled by status and
+// shaped by issue type; edges point from an issue to what it depends on.
+func WriteDOT(w io.Writer, g *Graph) error {
+	var b strings.Builder
+	b.WriteString("digraph dependencies {\n")
+	b.WriteString("  rankdir=TB;\n")
+	b.WriteString("  node [shape=box, style=\"rounded,filled\"];\n\n")
+
+	for _, issue := range g.Issues {
+		label := fmt.Sprintf("%s\n[%s P%d]\n%s\n(%s)", issue.ID, issue.IssueType, issue.Priority, issue.Title, issue.Status)
+
+		colors, ok := statusColors[issue.Status]
+		if !ok {
+			colors = statusColors[types.StatusOpen]
+		}
+		shape, ok := dotShapes[issue.IssueType]
+		if !ok {
+			shape = "box"
+		}
+		style := "filled"
+		if shape == "box" {
+			style = "rounded,filled"
+		}
+
+		fmt.Fprintf(&b, "  %q [label=%q, shape=%s, style=%q, fillcolor=%q, fontcolor=%q",
+			issue.ID, label, shape, style, colors[0], colors[1])
+		if issue.ID == g.Root {
+			b.WriteString(", penwidth=2")
+		}
+		b.WriteString("];\n")
+	}
+	b.WriteString("\n")
+
+	for _, dep := range g.Edges {
+		style, ok := edgeStyles[dep.Type]
+		if !ok {
+			style = [2]string{"black", "solid"}
+		}
+		fmt.Fprintf(&b, "  %q -> %q [label=%q, color=%s, style=%s];\n",
+			dep.IssueID, dep.DependsOnID, dep.Type, style[0], style[1])
+	}
+
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// mermaidShapes wraps a label in the node shape for each issue type
+var mermaidShapes = map[types.IssueType][2]string{
+	types.TypeEpic:    {"[[", "]]"},
+	types.TypeFeature: {"([", "])"},
+	types.TypeTask:    {"[", "]"},
+	types.TypeBug:     {"{{", "}}"},
+	types.TypeChore:   {"[/", "/]"},
+}
+
+// mermaidArrows draws each dependency type with a distinct line
+var mermaidArrows = map[types.DependencyType]string{
+	types.DepBlocks:         "==>",
+	types.DepParentChild:    "-->",
+	types.DepDiscoveredFrom: "-.->",
+	types.DepRelated:        "-.->",
+	types.DepDuplicates:     "-.->",
+	types.DepCausedBy:       "-.->",
+}
+
+// WriteMermaid renders g as a Mermaid flowchart with the same status colors
+// and per-type shapes as the DOT output
+func WriteMermaid(w io.Writer, g *Graph) error {
+	// Issue IDs contain hyphens, which Mermaid would read as edges, so nodes
+	// get positional names
+	names := make(map[string]string, len(g.Issues))
+	for i, issue := range g.Issues {
+		names[issue.ID] = fmt.Sprintf("n%d", i)
+	}
+
+	var b strings.Builder
+	b.WriteString("flowchart TB\n")
+
+	for _, issue := range g.Issues {
+		shape, ok := mermaidShapes[issue.IssueType]
+		if !ok {
+			shape = mermaidShapes[types.TypeTask]
+		}
+		label := fmt.Sprintf("%s: %s<br/>%s P%d · %s",
+			issue.ID, mermaidEscape(issue.Title), issue.IssueType, issue.Priority, issue.Status)
+		fmt.Fprintf(&b, "  %s%s\"%s\"%s\n", names[issue.ID], shape[0], label, shape[1])
+	}
+
+	for _, dep := range g.Edges {
+		arrow, ok := mermaidArrows[dep.Type]
+		if !ok {
+			arrow = "-->"
+		}
+		fmt.Fprintf(&b, "  %s %s|%s| %s\n", names[dep.IssueID], arrow, dep.Type, names[dep.DependsOnID])
+	}
+
+	// One class per status, applied to the nodes that have it
+	for _, status := range []types.Status{types.StatusOpen, types.StatusInProgress, types.StatusBlocked, types.StatusClosed} {
+		var members []string
+		for _, issue := range g.Issues {
+			if issue.Status == status {
+				members = append(members, names[issue.ID])
+			}
+		}
+		if len(members) == 0 {
+			continue
+		}
+		colors := statusColors[status]
+		fmt.Fprintf(&b, "  classDef %s fill:%s,color:%s,stroke:#333\n", status, colors[0], colors[1])
+		fmt.Fprintf(&b, "  class %s %s\n", strings.Join(members, ","), status)
+	}
+
+	if name, ok := names[g.Root]; ok {
+		fmt.Fprintf(&b, "  style %s stroke-width:3px\n", name)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// mermaidEscape replaces characters that would end or confuse a quoted
+// Mermaid label with entity codes
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(
+		`"`, "#quot;",
+		"<", "#lt;",
+		">", "#gt;",
+		"\n", " ",
+	).Replace(s)
+}
@@ -0,0 +1,123 @@
+package depgraph
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage/memory"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func setupGraph(t *testing.T) (*memory.MemoryStorage, []*types.Issue) {
+	t.Helper()
+	ctx := context.Background()
+	store := memory.New("")
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+
+	var issues []*types.Issue
+	for _, spec := range []struct {
+		title     string
+		issueType types.IssueType
+		status    types.Status
+	}{
+		{"Launch", types.TypeEpic, types.StatusOpen},
+		{"Fix \"crash\" <now>", types.TypeBug, types.StatusInProgress},
+		{"Write docs", types.TypeTask, types.StatusClosed},
+	} {
+		issue := &types.Issue{Title: spec.title, Status: spec.status, Priority: 1, IssueType: spec.issueType}
+		if spec.status == types.StatusClosed {
+			issue.Status = types.StatusOpen
+		}
+		if err := store.CreateIssue(ctx, issue, "test-user"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		if spec.status == types.StatusClosed {
+			if err := store.CloseIssue(ctx, issue.ID, "done", "test-user"); err != nil {
+				t.Fatalf("CloseIssue failed: %v", err)
+			}
+			issue.Status = types.StatusClosed
+		}
+		issues = append(issues, issue)
+	}
+
+	for _, dep := range []*types.Dependency{
+		{IssueID: issues[1].ID, DependsOnID: issues[0].ID, Type: types.DepParentChild},
+		{IssueID: issues[1].ID, DependsOnID: issues[2].ID, Type: types.DepBlocks},
+	} {
+		if err := store.AddDependency(ctx, dep, "test-user"); err != nil {
+			t.Fatalf("AddDependency failed: %v", err)
+		}
+	}
+	return store, issues
+}
+
+func TestWriteDOT(t *testing.T) {
+	store, issues := setupGraph(t)
+	defer store.Close()
+
+	g, err := ForTree(context.Background(), store, issues[1].ID, 10, false)
+	if err != nil {
+		t.Fatalf("ForTree failed: %v", err)
+	}
+	if len(g.Issues) != 3 || len(g.Edges) != 2 {
+		t.Fatalf("Expected 3 nodes and 2 edges, got %d and %d", len(g.Issues), len(g.Edges))
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, g, FormatDOT); err != nil {
+		t.Fatalf("WriteDOT failed: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		`"bd-1" [label="bd-1\n[epic P1]\nLaunch\n(open)", shape=folder, style="filled", fillcolor="white"`,
+		`shape=octagon, style="filled", fillcolor="lightyellow", fontcolor="black", penwidth=2]`,
+		`shape=box, style="rounded,filled", fillcolor="lightgray", fontcolor="dimgray"]`,
+		`"bd-2" -> "bd-1" [label="parent-child", color=blue, style=solid];`,
+		`"bd-2" -> "bd-3" [label="blocks", color=red, style=bold];`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("DOT output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteMermaid(t *testing.T) {
+	store, issues := setupGraph(t)
+	defer store.Close()
+
+	g, err := Build(context.Background(), store, issues)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, g, FormatMermaid); err != nil {
+		t.Fatalf("WriteMermaid failed: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"flowchart TB\n",
+		`n0[["bd-1: Launch<br/>epic P1 · open"]]`,
+		`n1{{"bd-2: Fix #quot;crash#quot; #lt;now#gt;<br/>bug P1 · in_progress"}}`,
+		"n1 -->|parent-child| n0",
+		"n1 ==>|blocks| n2",
+		"class n2 closed",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Mermaid output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "style n") {
+		t.Errorf("Expected no root emphasis without a root:\n%s", out)
+	}
+
+	if err := Write(&buf, g, "svg"); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
@@ -0,0 +1,202 @@
+// Package digest emails assignees a periodic summary of their work.
+//
+// Each subscriber gets their open issues, issues that became blocked since
+// the previous digest, and in-progress issues that haven't been touched in a
+// while. Mail goes out through the SMTP server in the smtp.* config keys.
+package digest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// Config keys read by the scheduler
+const (
+	EnabledConfigKey   = "digest.enabled"    // "true" to send digests from bd serve
+	StaleDaysConfigKey = "digest.stale_days" // in-progress issues untouched this long are stale
+)
+
+// DefaultStaleDays is used when digest.stale_days is unset
+const DefaultStaleDays = 7
+
+// Subscription frequencies
+const (
+	Daily  = "daily"
+	Weekly = "weekly"
+)
+
+// Period returns how often a frequency sends
+func Period(frequency string) time.Duration {
+	if frequency == Weekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// ValidateFrequency checks that frequency is daily or weekly
+func ValidateFrequency(frequency string) error {
+	if frequency != Daily && frequency != Weekly {
+		return fmt.Errorf("invalid digest frequency %q (must be %s or %s)", frequency, Daily, Weekly)
+	}
+	return nil
+}
+
+// Due reports whether a subscription's next digest should go out at now
+func Due(sub *sqlite.DigestSubscription, now time.Time) bool {
+	return sub.LastSentAt == nil || now.Sub(*sub.LastSentAt) >= Period(sub.Frequency)
+}
+
+// Since returns the start of the window a subscription's next digest covers
+func Since(sub *sqlite.DigestSubscription, now time.Time) time.Time {
+	if sub.LastSentAt != nil {
+		return *sub.LastSentAt
+	}
+	return now.Add(-Period(sub.Frequency))
+}
+
+// StaleAfter reads digest.stale_days
+func StaleAfter(ctx context.Context, store storage.Storage) time.Duration {
+	days := DefaultStaleDays
+	if v, _ := store.GetConfig(ctx, StaleDaysConfigKey); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			days = n
+		}
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// Digest summarizes an actor's work as of Generated
+type Digest struct {
+	Actor        string                `json:"actor"`
+	Since        time.Time             `json:"since"`
+	Generated    time.Time             `json:"generated"`
+	Open         []*types.Issue        `json:"open"`
+	NewlyBlocked []*types.BlockedIssue `json:"newly_blocked"`
+	Stale        []*types.Issue        `json:"stale"`
+}
+
+// Empty reports whether the digest has nothing to say
+func (d *Digest) Empty() bool {
+	return len(d.Open) == 0 && len(d.NewlyBlocked) == 0 && len(d.Stale) == 0
+}
+
+// Build gathers actor's digest. Open lists every issue assigned to actor
+// that isn't closed; NewlyBlocked the ones that gained an open blocker after
+// since; Stale the in-progress ones not updated within staleAfter.
+func Build(ctx context.Context, store storage.Storage, actor string, since time.Time, staleAfter time.Duration) (*Digest, error) {
+	now := time.Now()
+	d := &Digest{
+		Actor:        actor,
+		Since:        since,
+		Generated:    now,
+		Open:         []*types.Issue{},
+		NewlyBlocked: []*types.BlockedIssue{},
+		Stale:        []*types.Issue{},
+	}
+
+	assigned, err := store.SearchIssues(ctx, "", types.IssueFilter{Assignee: &actor})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issues for %s: %w", actor, err)
+	}
+	for _, issue := range assigned {
+		if issue.Status == types.StatusClosed {
+			continue
+		}
+		d.Open = append(d.Open, issue)
+		if issue.Status == types.StatusInProgress && now.Sub(issue.UpdatedAt) >= staleAfter {
+			d.Stale = append(d.Stale, issue)
+		}
+	}
+	sortIssues(d.Open)
+	sort.Slice(d.Stale, func(i, j int) bool { return d.Stale[i].UpdatedAt.Before(d.Stale[j].UpdatedAt) })
+
+	blocked, err := store.GetBlockedIssues(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blocked issues: %w", err)
+	}
+	for _, b := range blocked {
+		if b.Assignee != actor {
+			continue
+		}
+		deps, err := store.GetDependencyRecords(ctx, b.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, dep := range deps {
+			if dep.Type == types.DepBlocks && dep.CreatedAt.After(since) && contains(b.BlockedBy, dep.DependsOnID) {
+				d.NewlyBlocked = append(d.NewlyBlocked, b)
+				break
+			}
+		}
+	}
+	return d, nil
+}
+
+// Subject returns the email subject line for d
+func (d *Digest) Subject(prefix string) string {
+	if prefix == "" {
+		prefix = "beads"
+	}
+	return fmt.Sprintf("[%s] Digest for %s: %d open, %d newly blocked, %d stale",
+		prefix, d.Actor, len(d.Open), len(d.NewlyBlocked), len(d.Stale))
+}
+
+// Render formats d as a plain-text email body
+func (d *Digest) Render() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Hi %s,\n\nHere's your beads digest since %s.\n", d.Actor, d.Since.Local().Format("Mon Jan 2 15:04"))
+
+	if len(d.NewlyBlocked) > 0 {
+		fmt.Fprintf(&b, "\nNewly blocked (%d):\n", len(d.NewlyBlocked))
+		for _, issue := range d.NewlyBlocked {
+			fmt.Fprintf(&b, "  %s  [P%d] %s\n", issue.ID, issue.Priority, issue.Title)
+			fmt.Fprintf(&b, "      blocked by %s\n", strings.Join(issue.BlockedBy, ", "))
+		}
+	}
+
+	if len(d.Stale) > 0 {
+		fmt.Fprintf(&b, "\nStale work (%d):\n", len(d.Stale))
+		for _, issue := range d.Stale {
+			days := int(d.Generated.Sub(issue.UpdatedAt).Hours() / 24)
+			fmt.Fprintf(&b, "  %s  [P%d] %s (no updates in %d days)\n", issue.ID, issue.Priority, issue.Title, days)
+		}
+	}
+
+	fmt.Fprintf(&b, "\nOpen issues (%d):\n", len(d.Open))
+	if len(d.Open) == 0 {
+		b.WriteString("  Nothing assigned to you. 🎉\n")
+	}
+	for _, issue := range d.Open {
+		fmt.Fprintf(&b, "  %s  [P%d] %s (%s)\n", issue.ID, issue.Priority, issue.Title, issue.Status)
+	}
+
+	b.WriteString("\nTo change or stop these emails: bd digest subscribe / bd digest unsubscribe\n")
+	return b.String()
+}
+
+// sortIssues orders by priority, then oldest first
+func sortIssues(issues []*types.Issue) {
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].Priority != issues[j].Priority {
+			return issues[i].Priority < issues[j].Priority
+		}
+		return issues[i].CreatedAt.Before(issues[j].CreatedAt)
+	})
+}
+
+func contains(ids []string, id string) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
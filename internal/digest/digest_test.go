@@ -0,0 +1,157 @@
+package digest
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+type sentMail struct {
+	to, subject, body string
+}
+
+type fakeMailer struct {
+	sent []sentMail
+}
+
+func (m *fakeMailer) Send(_ context.Context, to, subject, body string) error {
+	m.sent = append(m.sent, sentMail{to, subject, body})
+	return nil
+}
+
+func newTestStore(t *testing.T) *sqlite.SQLiteStorage {
+	t.Helper()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	if err := store.SetConfig(context.Background(), "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func createIssue(t *testing.T, store *sqlite.SQLiteStorage, title, assignee string, status types.Status) *types.Issue {
+	t.Helper()
+	issue := &types.Issue{Title: title, Status: status, Priority: 2, IssueType: types.TypeTask, Assignee: assignee}
+	if status == types.StatusClosed {
+		now := time.Now()
+		issue.ClosedAt = &now
+	}
+	if err := store.CreateIssue(context.Background(), issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	return issue
+}
+
+func TestBuild(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+
+	open := createIssue(t, store, "Write docs", "alice", types.StatusOpen)
+	stale := createIssue(t, store, "Old refactor", "alice", types.StatusInProgress)
+	blocked := createIssue(t, store, "Ship release", "alice", types.StatusOpen)
+	createIssue(t, store, "Done already", "alice", types.StatusClosed)
+	createIssue(t, store, "Someone else's", "bob", types.StatusOpen)
+	blocker := createIssue(t, store, "Fix CI", "bob", types.StatusOpen)
+
+	if _, err := store.UnderlyingDB().ExecContext(ctx, `UPDATE issues SET updated_at = ? WHERE id = ?`,
+		time.Now().Add(-10*24*time.Hour), stale.ID); err != nil {
+		t.Fatal(err)
+	}
+	since := time.Now().Add(-time.Hour)
+	dep := &types.Dependency{IssueID: blocked.ID, DependsOnID: blocker.ID, Type: types.DepBlocks}
+	if err := store.AddDependency(ctx, dep, "test"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+
+	d, err := Build(ctx, store, "alice", since, 7*24*time.Hour)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if len(d.Open) != 3 {
+		t.Errorf("Expected 3 open issues, got %d", len(d.Open))
+	}
+	if len(d.Stale) != 1 || d.Stale[0].ID != stale.ID {
+		t.Errorf("Expected %s to be stale, got %+v", stale.ID, d.Stale)
+	}
+	if len(d.NewlyBlocked) != 1 || d.NewlyBlocked[0].ID != blocked.ID {
+		t.Errorf("Expected %s to be newly blocked, got %+v", blocked.ID, d.NewlyBlocked)
+	}
+
+	body := d.Render()
+	for _, want := range []string{open.ID, "Newly blocked (1)", "blocked by " + blocker.ID, "Stale work (1)", "no updates in 10 days"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected digest to contain %q:\n%s", want, body)
+		}
+	}
+
+	// A blocker added before the window isn't news
+	d, err = Build(ctx, store, "alice", time.Now().Add(time.Minute), 7*24*time.Hour)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if len(d.NewlyBlocked) != 0 {
+		t.Errorf("Expected no newly blocked issues, got %d", len(d.NewlyBlocked))
+	}
+}
+
+func TestSendDue(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+	createIssue(t, store, "Write docs", "alice", types.StatusOpen)
+
+	for _, sub := range []*sqlite.DigestSubscription{
+		{Actor: "alice", Email: "alice@example.com", Frequency: Daily},
+		{Actor: "bob", Email: "bob@example.com", Frequency: Weekly},
+	} {
+		if err := store.SetDigestSubscription(ctx, sub); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mailer := &fakeMailer{}
+	sched := NewScheduler(store)
+	sched.NewMailer = func(context.Context) (Mailer, error) { return mailer, nil }
+
+	now := time.Now()
+	sent, err := sched.SendDue(ctx, now)
+	if err != nil {
+		t.Fatalf("SendDue failed: %v", err)
+	}
+	// bob has nothing assigned, so only alice gets mail
+	if len(sent) != 1 || sent[0] != "alice" || len(mailer.sent) != 1 {
+		t.Fatalf("Expected one digest for alice, got %v", sent)
+	}
+	if mailer.sent[0].to != "alice@example.com" || !strings.HasPrefix(mailer.sent[0].subject, "[bd] Digest for alice: 1 open") {
+		t.Errorf("Unexpected mail %+v", mailer.sent[0])
+	}
+
+	// Both are marked sent; alice is due again a day later, bob a week later
+	if sent, _ := sched.SendDue(ctx, now.Add(time.Hour)); len(sent) != 0 {
+		t.Errorf("Expected nothing due an hour later, got %v", sent)
+	}
+	if sent, _ := sched.SendDue(ctx, now.Add(25*time.Hour)); len(sent) != 1 {
+		t.Errorf("Expected alice's next digest a day later, got %v", sent)
+	}
+	bob, _ := store.GetDigestSubscription(ctx, "bob")
+	if bob.LastSentAt == nil || !bob.LastSentAt.Equal(now) {
+		t.Errorf("Expected bob's empty digest to count as sent, got %v", bob.LastSentAt)
+	}
+}
+
+func TestBuildMessageSanitizesSubject(t *testing.T) {
+	msg := string(buildMessage("from@example.com", "to@example.com", "Hi\r\nBcc: evil@example.com", "line1\nline2", time.Now()))
+	if strings.Contains(msg, "\r\nBcc:") {
+		t.Errorf("Subject injected a header:\n%s", msg)
+	}
+	if !strings.HasSuffix(msg, "\r\n\r\nline1\r\nline2") {
+		t.Errorf("Expected CRLF body, got %q", msg)
+	}
+}
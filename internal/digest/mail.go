@@ -0,0 +1,108 @@
+package digest
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage"
+)
+
+// SMTP config keys. The password can instead come from BEADS_SMTP_PASSWORD,
+// which takes precedence so it needn't be stored in the database.
+const (
+	SMTPHostKey     = "smtp.host"
+	SMTPPortKey     = "smtp.port" // default 587
+	SMTPUsernameKey = "smtp.username"
+	SMTPPasswordKey = "smtp.password"
+	SMTPFromKey     = "smtp.from"
+)
+
+// IsSecretConfig reports whether a config key holds a credential, which
+// shouldn't be shown to API readers or copied out of the database
+func IsSecretConfig(key string) bool {
+	return key == SMTPPasswordKey
+}
+
+// SMTPConfig says how to reach the mail server
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// LoadSMTPConfig reads the smtp.* config keys
+func LoadSMTPConfig(ctx context.Context, store storage.Storage) (*SMTPConfig, error) {
+	cfg := &SMTPConfig{}
+	cfg.Host, _ = store.GetConfig(ctx, SMTPHostKey)
+	cfg.Port, _ = store.GetConfig(ctx, SMTPPortKey)
+	cfg.Username, _ = store.GetConfig(ctx, SMTPUsernameKey)
+	cfg.Password, _ = store.GetConfig(ctx, SMTPPasswordKey)
+	cfg.From, _ = store.GetConfig(ctx, SMTPFromKey)
+	if env := os.Getenv("BEADS_SMTP_PASSWORD"); env != "" {
+		cfg.Password = env
+	}
+	if cfg.Port == "" {
+		cfg.Port = "587"
+	}
+
+	if cfg.Host == "" {
+		return nil, fmt.Errorf("%s is not set", SMTPHostKey)
+	}
+	if cfg.From == "" {
+		return nil, fmt.Errorf("%s is not set", SMTPFromKey)
+	}
+	return cfg, nil
+}
+
+// Mailer sends a plain-text email
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// SMTPMailer sends mail through an SMTP server, using STARTTLS when the
+// server offers it and PLAIN auth when a username is set
+type SMTPMailer struct {
+	Config *SMTPConfig
+}
+
+// Send delivers one message
+func (m *SMTPMailer) Send(_ context.Context, to, subject, body string) error {
+	cfg := m.Config
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+	addr := net.JoinHostPort(cfg.Host, cfg.Port)
+	if err := smtp.SendMail(addr, auth, cfg.From, []string{to}, buildMessage(cfg.From, to, subject, body, time.Now())); err != nil {
+		return fmt.Errorf("failed to send mail to %s: %w", to, err)
+	}
+	return nil
+}
+
+// buildMessage formats an RFC 5322 message with CRLF line endings
+func buildMessage(from, to, subject, body string, date time.Time) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %s\r\n", sanitizeHeader(subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// sanitizeHeader keeps issue titles from injecting extra headers
+func sanitizeHeader(value string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(value)
+}
@@ -0,0 +1,136 @@
+package digest
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+)
+
+const checkInterval = 15 * time.Minute
+
+// Scheduler sends digests that are due while bd serve runs. Settings are
+// read on every check, so enabling digests or changing SMTP config takes
+// effect without a restart.
+type Scheduler struct {
+	store *sqlite.SQLiteStorage
+
+	// NewMailer builds the mailer for a round of sends; defaults to SMTP
+	// using the smtp.* config keys
+	NewMailer func(ctx context.Context) (Mailer, error)
+
+	mu      sync.Mutex
+	lastRun time.Time
+	lastErr error
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+}
+
+// NewScheduler creates a scheduler that mails through SMTP
+func NewScheduler(store *sqlite.SQLiteStorage) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		store: store,
+		NewMailer: func(ctx context.Context) (Mailer, error) {
+			cfg, err := LoadSMTPConfig(ctx, store)
+			if err != nil {
+				return nil, err
+			}
+			return &SMTPMailer{Config: cfg}, nil
+		},
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Start checks for due digests now and then periodically in the background
+func (s *Scheduler) Start() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(checkInterval)
+		defer ticker.Stop()
+		for {
+			enabled, err := s.store.GetConfig(s.ctx, EnabledConfigKey)
+			if err == nil && enabled == "true" {
+				if _, err = s.SendDue(s.ctx, time.Now()); err != nil {
+					log.Printf("digest: %v", err)
+				}
+			}
+			s.mu.Lock()
+			s.lastRun, s.lastErr = time.Now(), err
+			s.mu.Unlock()
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// LastRun reports when the scheduler last checked for due digests and what
+// that check failed with, if anything
+func (s *Scheduler) LastRun() (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastRun, s.lastErr
+}
+
+// Close stops the scheduler and waits for a round in progress to finish
+func (s *Scheduler) Close() {
+	s.stopOnce.Do(s.cancel)
+	s.wg.Wait()
+}
+
+// SendDue sends every digest due at now and returns the actors mailed.
+// Digests with nothing in them are skipped but still count as sent, so the
+// next one covers the following period. A failed send is logged and retried
+// on the next check.
+func (s *Scheduler) SendDue(ctx context.Context, now time.Time) ([]string, error) {
+	subs, err := s.store.ListDigestSubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var due []*sqlite.DigestSubscription
+	for _, sub := range subs {
+		if Due(sub, now) {
+			due = append(due, sub)
+		}
+	}
+	if len(due) == 0 {
+		return nil, nil
+	}
+
+	mailer, err := s.NewMailer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	prefix, _ := s.store.GetConfig(ctx, "issue_prefix")
+	staleAfter := StaleAfter(ctx, s.store)
+
+	var sent []string
+	for _, sub := range due {
+		d, err := Build(ctx, s.store, sub.Actor, Since(sub, now), staleAfter)
+		if err != nil {
+			return sent, err
+		}
+		if !d.Empty() {
+			if err := mailer.Send(ctx, sub.Email, d.Subject(prefix), d.Render()); err != nil {
+				log.Printf("digest: %v", err)
+				continue
+			}
+			sent = append(sent, sub.Actor)
+		}
+		if err := s.store.MarkDigestSent(ctx, sub.Actor, now); err != nil {
+			return sent, err
+		}
+	}
+	return sent, nil
+}
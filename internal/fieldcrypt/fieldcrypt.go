@@ -0,0 +1,250 @@
+// Package fieldcrypt implements envelope encryption for sensitive issue fields.
+//
+// Each workspace has a random data encryption key (DEK) that encrypts field
+// values with AES-256-GCM. The DEK itself is stored only in wrapped form,
+// encrypted by a key encryption key (KEK) supplied by a KeyProvider: either a
+// key in the environment or an external KMS reached through a command.
+package fieldcrypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Prefix marks an encrypted field value
+const Prefix = "enc:v1:"
+
+// KeySize is the size in bytes of data and key encryption keys
+const KeySize = 32
+
+// Environment variables used to configure key providers
+const (
+	EnvKey             = "BEADS_ENCRYPTION_KEY" // base64 or hex encoded 32-byte KEK
+	EnvKMSWrap         = "BEADS_KMS_WRAP_CMD"   // shell command: plaintext DEK on stdin, wrapped key on stdout
+	EnvKMSUnwrap       = "BEADS_KMS_UNWRAP_CMD" // shell command: wrapped key on stdin, plaintext DEK on stdout
+	providerEnv        = "env"
+	providerKMSCommand = "kms-command"
+)
+
+// KeyProvider wraps and unwraps the workspace data key
+type KeyProvider interface {
+	Name() string
+	WrapKey(dek []byte) ([]byte, error)
+	UnwrapKey(wrapped []byte) ([]byte, error)
+}
+
+// IsEncrypted reports whether a stored value is an encrypted field
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, Prefix)
+}
+
+// NewDataKey generates a random data encryption key
+func NewDataKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+	return key, nil
+}
+
+// ProviderFromEnv returns the configured key provider, or nil if none is configured.
+// A KMS command pair takes precedence over a raw environment key.
+func ProviderFromEnv() (KeyProvider, error) {
+	wrapCmd, unwrapCmd := os.Getenv(EnvKMSWrap), os.Getenv(EnvKMSUnwrap)
+	if wrapCmd != "" || unwrapCmd != "" {
+		if wrapCmd == "" || unwrapCmd == "" {
+			return nil, fmt.Errorf("both %s and %s must be set", EnvKMSWrap, EnvKMSUnwrap)
+		}
+		return &CommandKeyProvider{WrapCmd: wrapCmd, UnwrapCmd: unwrapCmd}, nil
+	}
+	if raw := os.Getenv(EnvKey); raw != "" {
+		kek, err := decodeKey(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", EnvKey, err)
+		}
+		return &StaticKeyProvider{kek: kek}, nil
+	}
+	return nil, nil
+}
+
+// decodeKey accepts a base64 or hex encoded 32-byte key
+func decodeKey(raw string) ([]byte, error) {
+	raw = strings.TrimSpace(raw)
+	if key, err := hex.DecodeString(raw); err == nil && len(key) == KeySize {
+		return key, nil
+	}
+	if key, err := base64.StdEncoding.DecodeString(raw); err == nil && len(key) == KeySize {
+		return key, nil
+	}
+	return nil, fmt.Errorf("expected a %d-byte key encoded as hex or base64", KeySize)
+}
+
+// StaticKeyProvider wraps keys with a KEK held in process memory
+type StaticKeyProvider struct {
+	kek []byte
+}
+
+// NewStaticKeyProvider creates a provider from a raw 32-byte KEK
+func NewStaticKeyProvider(kek []byte) (*StaticKeyProvider, error) {
+	if len(kek) != KeySize {
+		return nil, fmt.Errorf("key must be %d bytes (got %d)", KeySize, len(kek))
+	}
+	return &StaticKeyProvider{kek: kek}, nil
+}
+
+// Name identifies the provider in status output
+func (p *StaticKeyProvider) Name() string { return providerEnv }
+
+// WrapKey encrypts the DEK with the KEK
+func (p *StaticKeyProvider) WrapKey(dek []byte) ([]byte, error) {
+	return seal(p.kek, dek)
+}
+
+// UnwrapKey decrypts the DEK with the KEK
+func (p *StaticKeyProvider) UnwrapKey(wrapped []byte) ([]byte, error) {
+	return open(p.kek, wrapped)
+}
+
+// CommandKeyProvider delegates key wrapping to external commands, typically a KMS CLI
+// such as "aws kms encrypt" or "gcloud kms encrypt". Commands run through sh -c.
+type CommandKeyProvider struct {
+	WrapCmd   string
+	UnwrapCmd string
+}
+
+// Name identifies the provider in status output
+func (p *CommandKeyProvider) Name() string { return providerKMSCommand }
+
+// WrapKey pipes the DEK through the wrap command
+func (p *CommandKeyProvider) WrapKey(dek []byte) ([]byte, error) {
+	return runKeyCommand(p.WrapCmd, dek)
+}
+
+// UnwrapKey pipes the wrapped key through the unwrap command
+func (p *CommandKeyProvider) UnwrapKey(wrapped []byte) ([]byte, error) {
+	dek, err := runKeyCommand(p.UnwrapCmd, wrapped)
+	if err != nil {
+		return nil, err
+	}
+	if len(dek) != KeySize {
+		return nil, fmt.Errorf("unwrap command returned %d bytes, expected %d", len(dek), KeySize)
+	}
+	return dek, nil
+}
+
+func runKeyCommand(command string, input []byte) ([]byte, error) {
+	cmd := exec.Command("sh", "-c", command) // #nosec G204 - command supplied by the operator's environment
+	cmd.Stdin = bytes.NewReader(input)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("key command failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// Cipher encrypts and decrypts field values with a workspace data key
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher creates a field cipher from a 32-byte data key
+func NewCipher(dek []byte) (*Cipher, error) {
+	if len(dek) != KeySize {
+		return nil, fmt.Errorf("data key must be %d bytes (got %d)", KeySize, len(dek))
+	}
+	block, err := aes.NewCipher(dek)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// Encrypt returns the encrypted form of a value. Empty values, and values
+// this cipher already encrypted, are returned unchanged. Text that merely
+// starts with Prefix is encrypted like any other, so it still reads back.
+func (c *Cipher) Encrypt(value string) (string, error) {
+	if value == "" {
+		return value, nil
+	}
+	if IsEncrypted(value) {
+		if _, err := c.Decrypt(value); err == nil {
+			return value, nil
+		}
+	}
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(value), nil)
+	return Prefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt returns the plaintext of an encrypted value. Values without the
+// encryption prefix are returned unchanged.
+func (c *Cipher) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, Prefix))
+	if err != nil {
+		return "", fmt.Errorf("malformed encrypted value: %w", err)
+	}
+	nonceSize := c.aead.NonceSize()
+	if len(data) < nonceSize {
+		return "", fmt.Errorf("malformed encrypted value: too short")
+	}
+	plain, err := c.aead.Open(nil, data[:nonceSize], data[nonceSize:], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value (wrong key?): %w", err)
+	}
+	return string(plain), nil
+}
+
+func seal(key, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func open(key, sealed []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, fmt.Errorf("wrapped key too short")
+	}
+	plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key (wrong key?): %w", err)
+	}
+	return plain, nil
+}
@@ -0,0 +1,161 @@
+package fieldcrypt
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestCipherRoundTrip(t *testing.T) {
+	dek, err := NewDataKey()
+	if err != nil {
+		t.Fatalf("NewDataKey failed: %v", err)
+	}
+	c, err := NewCipher(dek)
+	if err != nil {
+		t.Fatalf("NewCipher failed: %v", err)
+	}
+
+	enc, err := c.Encrypt("secret plans")
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if !IsEncrypted(enc) || strings.Contains(enc, "secret") {
+		t.Fatalf("expected ciphertext, got %q", enc)
+	}
+
+	// Encrypting twice is a no-op
+	again, err := c.Encrypt(enc)
+	if err != nil || again != enc {
+		t.Errorf("expected already-encrypted value to pass through, got %q (%v)", again, err)
+	}
+
+	dec, err := c.Decrypt(enc)
+	if err != nil {
+		t.Fatalf("Decrypt failed: %v", err)
+	}
+	if dec != "secret plans" {
+		t.Errorf("expected round trip, got %q", dec)
+	}
+
+	// Empty and plaintext values pass through
+	if v, _ := c.Encrypt(""); v != "" {
+		t.Errorf("expected empty value to stay empty, got %q", v)
+	}
+	if v, _ := c.Decrypt("plain"); v != "plain" {
+		t.Errorf("expected plaintext to pass through, got %q", v)
+	}
+}
+
+func TestCipherPrefixedPlaintext(t *testing.T) {
+	dek, err := NewDataKey()
+	if err != nil {
+		t.Fatalf("NewDataKey failed: %v", err)
+	}
+	c, err := NewCipher(dek)
+	if err != nil {
+		t.Fatalf("NewCipher failed: %v", err)
+	}
+
+	// Text that only looks encrypted, including another key's ciphertext,
+	// is encrypted and reads back as written
+	other, _ := NewDataKey()
+	oc, _ := NewCipher(other)
+	foreign, _ := oc.Encrypt("not ours")
+	for _, plain := range []string{Prefix + "see the migration notes", Prefix, foreign} {
+		enc, err := c.Encrypt(plain)
+		if err != nil {
+			t.Fatalf("Encrypt(%q) failed: %v", plain, err)
+		}
+		if enc == plain {
+			t.Errorf("expected %q to be encrypted", plain)
+		}
+		if dec, err := c.Decrypt(enc); err != nil || dec != plain {
+			t.Errorf("expected %q back, got %q (%v)", plain, dec, err)
+		}
+	}
+}
+
+func TestCipherWrongKey(t *testing.T) {
+	k1, _ := NewDataKey()
+	k2, _ := NewDataKey()
+	c1, _ := NewCipher(k1)
+	c2, _ := NewCipher(k2)
+
+	enc, err := c1.Encrypt("value")
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if _, err := c2.Decrypt(enc); err == nil {
+		t.Error("expected decryption with the wrong key to fail")
+	}
+}
+
+func TestStaticKeyProviderWrapUnwrap(t *testing.T) {
+	kek, _ := NewDataKey()
+	p, err := NewStaticKeyProvider(kek)
+	if err != nil {
+		t.Fatalf("NewStaticKeyProvider failed: %v", err)
+	}
+	dek, _ := NewDataKey()
+	wrapped, err := p.WrapKey(dek)
+	if err != nil {
+		t.Fatalf("WrapKey failed: %v", err)
+	}
+	got, err := p.UnwrapKey(wrapped)
+	if err != nil {
+		t.Fatalf("UnwrapKey failed: %v", err)
+	}
+	if hex.EncodeToString(got) != hex.EncodeToString(dek) {
+		t.Error("unwrapped key does not match")
+	}
+}
+
+func TestProviderFromEnv(t *testing.T) {
+	t.Setenv(EnvKey, "")
+	t.Setenv(EnvKMSWrap, "")
+	t.Setenv(EnvKMSUnwrap, "")
+
+	p, err := ProviderFromEnv()
+	if err != nil || p != nil {
+		t.Fatalf("expected no provider, got %v (%v)", p, err)
+	}
+
+	t.Setenv(EnvKey, "not-a-key")
+	if _, err := ProviderFromEnv(); err == nil {
+		t.Error("expected error for malformed key")
+	}
+
+	t.Setenv(EnvKey, strings.Repeat("ab", KeySize))
+	p, err = ProviderFromEnv()
+	if err != nil || p == nil || p.Name() != providerEnv {
+		t.Fatalf("expected env provider, got %v (%v)", p, err)
+	}
+
+	t.Setenv(EnvKMSWrap, "cat")
+	if _, err := ProviderFromEnv(); err == nil {
+		t.Error("expected error when only the wrap command is set")
+	}
+
+	t.Setenv(EnvKMSUnwrap, "cat")
+	p, err = ProviderFromEnv()
+	if err != nil || p == nil || p.Name() != providerKMSCommand {
+		t.Fatalf("expected command provider, got %v (%v)", p, err)
+	}
+}
+
+func TestCommandKeyProvider(t *testing.T) {
+	p := &CommandKeyProvider{WrapCmd: "cat", UnwrapCmd: "cat"}
+	dek, _ := NewDataKey()
+	wrapped, err := p.WrapKey(dek)
+	if err != nil {
+		t.Fatalf("WrapKey failed: %v", err)
+	}
+	got, err := p.UnwrapKey(wrapped)
+	if err != nil {
+		t.Fatalf("UnwrapKey failed: %v", err)
+	}
+	if string(got) != string(dek) {
+		t.Error("unwrapped key does not match")
+	}
+}
@@ -0,0 +1,229 @@
+// Package git links commits to the issues their messages mention.
+//
+// Any issue ID in a commit message (bd-123) links the commit to that issue.
+// IDs after a closing keyword (close, fix, or resolve in any tense, as in
+// "fixes bd-123" or "Closes: bd-4, bd-5") also close the issue.
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// AutoCloseConfigKey is the config key that turns off closing issues from
+// commit messages when set to "false"
+const AutoCloseConfigKey = "git.auto_close"
+
+// AutoCloseEnabled interprets the AutoCloseConfigKey value; closing is on
+// unless explicitly disabled
+func AutoCloseEnabled(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "false", "0", "no", "off":
+		return false
+	default:
+		return true
+	}
+}
+
+// Ref is an issue mentioned in a commit message
+type Ref struct {
+	IssueID string `json:"issue_id"`
+	Closes  bool   `json:"closes"`
+}
+
+var (
+	anyIDPattern = regexp.MustCompile(`\b[a-z][a-z0-9]*-\d+\b`)
+	closeKeyword = regexp.MustCompile(`(?i)\b(close[sd]?|fix(e[sd])?|resolve[sd]?)\s*:?\s*$`)
+	listJoiner   = regexp.MustCompile(`(?i)^(\s*,\s*|\s+and\s+|\s*&\s*|\s+)$`)
+)
+
+// ParseRefs finds the issue IDs in message, in order of first mention. With
+// a prefix only IDs using it are matched; otherwise any prefix-number token
+// counts.
+func ParseRefs(message, prefix string) []Ref {
+	pattern := anyIDPattern
+	if prefix != "" {
+		pattern = regexp.MustCompile(`\b` + regexp.QuoteMeta(prefix) + `-\d+\b`)
+	}
+
+	var refs []Ref
+	index := make(map[string]int)
+	closing := false
+	last := 0
+	for _, m := range pattern.FindAllStringIndex(message, -1) {
+		gap := message[last:m[0]]
+		switch {
+		case closeKeyword.MatchString(gap):
+			closing = true
+		case closing && listJoiner.MatchString(gap):
+			// "fixes bd-1, bd-2 and bd-3" closes all three
+		default:
+			closing = false
+		}
+		last = m[1]
+
+		id := message[m[0]:m[1]]
+		if i, ok := index[id]; ok {
+			refs[i].Closes = refs[i].Closes || closing
+			continue
+		}
+		index[id] = len(refs)
+		refs = append(refs, Ref{IssueID: id, Closes: closing})
+	}
+	return refs
+}
+
+// Commit is a git commit
+type Commit struct {
+	SHA       string    `json:"sha"`
+	Author    string    `json:"author"`
+	Message   string    `json:"message"`
+	Timestamp time.Time `json:"timestamp"`
+	URL       string    `json:"url,omitempty"`
+}
+
+// Subject returns the first line of the commit message
+func (c *Commit) Subject() string {
+	subject, _, _ := strings.Cut(strings.TrimSpace(c.Message), "\n")
+	return strings.TrimSpace(subject)
+}
+
+// ShortSHA returns the abbreviated commit hash
+func (c *Commit) ShortSHA() string {
+	if len(c.SHA) > 7 {
+		return c.SHA[:7]
+	}
+	return c.SHA
+}
+
+const (
+	fieldSep  = "\x1f"
+	recordSep = "\x1e"
+)
+
+// ReadCommits runs git log in dir with the given revision arguments (e.g.
+// "HEAD", "main..feature", or "-5"), newest first. With no arguments it
+// reads only HEAD.
+func ReadCommits(ctx context.Context, dir string, revs ...string) ([]*Commit, error) {
+	args := []string{"log", "--format=%H" + fieldSep + "%an <%ae>" + fieldSep + "%cI" + fieldSep + "%B" + recordSep}
+	if len(revs) == 0 {
+		args = append(args, "-1", "HEAD")
+	} else {
+		args = append(args, revs...)
+	}
+	args = append(args, "--")
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var commits []*Commit
+	for _, record := range strings.Split(string(out), recordSep) {
+		record = strings.TrimLeft(record, "\n")
+		if record == "" {
+			continue
+		}
+		fields := strings.SplitN(record, fieldSep, 4)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("unexpected git log output %q", record)
+		}
+		ts, _ := time.Parse(time.RFC3339, fields[2])
+		commits = append(commits, &Commit{
+			SHA:       fields[0],
+			Author:    fields[1],
+			Timestamp: ts,
+			Message:   strings.TrimSpace(fields[3]),
+		})
+	}
+	return commits, nil
+}
+
+// LinkOptions control Link
+type LinkOptions struct {
+	Prefix    string // issue prefix to look for
+	Actor     string
+	AutoClose bool // close issues the message says it fixes
+	DryRun    bool
+}
+
+// LinkResult describes what Link did for one mentioned issue
+type LinkResult struct {
+	SHA     string `json:"sha"`
+	IssueID string `json:"issue_id"`
+	Linked  bool   `json:"linked"` // false if it was linked before
+	Closed  bool   `json:"closed"`
+}
+
+// Link records commit against every existing issue its message mentions and
+// closes the ones it fixes. Mentions of unknown issues are ignored.
+func Link(ctx context.Context, store *sqlite.SQLiteStorage, commit *Commit, opts LinkOptions) ([]*LinkResult, error) {
+	var results []*LinkResult
+	for _, ref := range ParseRefs(commit.Message, opts.Prefix) {
+		issue, err := store.GetIssue(ctx, ref.IssueID)
+		if err != nil {
+			return nil, err
+		}
+		if issue == nil {
+			continue
+		}
+		result, err := LinkIssue(ctx, store, issue, commit, ref.Closes && opts.AutoClose, opts)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// LinkIssue records commit against issue and, if closeIssue is set, closes
+// the issue with the commit as the reason. A commit only closes an issue the
+// first time it's linked, so reopened issues stay open when history is
+// scanned again.
+func LinkIssue(ctx context.Context, store *sqlite.SQLiteStorage, issue *types.Issue, commit *Commit, closeIssue bool, opts LinkOptions) (*LinkResult, error) {
+	result := &LinkResult{SHA: commit.SHA, IssueID: issue.ID}
+	closeIssue = closeIssue && issue.Status != types.StatusClosed
+	if opts.DryRun {
+		result.Linked = true
+		result.Closed = closeIssue
+		return result, nil
+	}
+
+	var committedAt *time.Time
+	if !commit.Timestamp.IsZero() {
+		committedAt = &commit.Timestamp
+	}
+	linked, err := store.AddCommitLink(ctx, &sqlite.CommitLink{
+		IssueID:     issue.ID,
+		SHA:         commit.SHA,
+		Message:     commit.Message,
+		Author:      commit.Author,
+		URL:         commit.URL,
+		CommittedAt: committedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Linked = linked
+
+	if closeIssue && linked {
+		reason := fmt.Sprintf("Fixed in %s: %s", commit.ShortSHA(), commit.Subject())
+		if err := store.CloseIssue(ctx, issue.ID, reason, opts.Actor); err != nil {
+			return nil, err
+		}
+		result.Closed = true
+	}
+	return result, nil
+}
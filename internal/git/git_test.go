@@ -0,0 +1,93 @@
+package git
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestParseRefs(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		prefix  string
+		want    []Ref
+	}{
+		{"mention", "Refactor parser (bd-12)", "bd", []Ref{{IssueID: "bd-12"}}},
+		{"fixes", "fixes bd-3", "bd", []Ref{{IssueID: "bd-3", Closes: true}}},
+		{"closed list", "Closes: bd-4, bd-5 and bd-6", "bd", []Ref{
+			{IssueID: "bd-4", Closes: true}, {IssueID: "bd-5", Closes: true}, {IssueID: "bd-6", Closes: true},
+		}},
+		{"keyword stops at prose", "Resolved bd-1; see bd-2", "bd", []Ref{
+			{IssueID: "bd-1", Closes: true}, {IssueID: "bd-2"},
+		}},
+		{"repeated mention", "bd-7 groundwork\n\nFix bd-7", "bd", []Ref{{IssueID: "bd-7", Closes: true}}},
+		{"other prefix ignored", "Fix bd-2 (port of web-1)", "bd", []Ref{{IssueID: "bd-2", Closes: true}}},
+		{"any prefix", "fixes web-1", "", []Ref{{IssueID: "web-1", Closes: true}}},
+		{"no refs", "Bump version", "bd", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseRefs(tt.message, tt.prefix); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRefs(%q) = %+v, want %+v", tt.message, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinkClosesFixedIssues(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+
+	fixed := &types.Issue{Title: "Crash", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeBug}
+	mentioned := &types.Issue{Title: "Cleanup", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	for _, issue := range []*types.Issue{fixed, mentioned} {
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+
+	commit := &Commit{SHA: "0123456789abcdef", Message: "Fix " + fixed.ID + "\n\nRelated to " + mentioned.ID + " and bd-999"}
+	opts := LinkOptions{Prefix: "bd", Actor: "test", AutoClose: true}
+	results, err := Link(ctx, store, commit, opts)
+	if err != nil {
+		t.Fatalf("Link failed: %v", err)
+	}
+	if len(results) != 2 || !results[0].Closed || results[1].Closed {
+		t.Fatalf("Unexpected results %+v", results)
+	}
+
+	got, _ := store.GetIssue(ctx, fixed.ID)
+	if got.Status != types.StatusClosed {
+		t.Errorf("Expected %s to be closed, got %s", fixed.ID, got.Status)
+	}
+	if links, _ := store.GetCommitLinks(ctx, mentioned.ID); len(links) != 1 {
+		t.Errorf("Expected 1 link on %s, got %d", mentioned.ID, len(links))
+	}
+
+	// Scanning the same commit again leaves a reopened issue alone
+	if err := store.UpdateIssue(ctx, fixed.ID, map[string]interface{}{"status": string(types.StatusOpen)}, "test"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	results, err = Link(ctx, store, commit, opts)
+	if err != nil {
+		t.Fatalf("Link failed: %v", err)
+	}
+	for _, r := range results {
+		if r.Linked || r.Closed {
+			t.Errorf("Expected relink to be a no-op, got %+v", r)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+)
+
+// actorRequest is the body of POST /actors and PATCH /actors/{name}
+type actorRequest struct {
+	Name    string   `json:"name,omitempty" doc:"Required on POST; ignored on PATCH"`
+	Email   string   `json:"email,omitempty"`
+	Type    string   `json:"type,omitempty" doc:"human, agent, or bot; defaults to human"`
+	Aliases []string `json:"aliases,omitempty" doc:"Other names that resolve to this actor. PATCH adds to the existing ones."`
+}
+
+// actorMergeRequest is the body of POST /actors/{name}/merge
+type actorMergeRequest struct {
+	From   string `json:"from" doc:"The actor name to fold into {name}"`
+	DryRun bool   `json:"dry_run,omitempty" doc:"Count what would be rewritten without changing anything"`
+}
+
+// actorErrorStatus maps registry errors to a status, treating anything
+// else as a bad request
+func actorErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, sqlite.ErrActorNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, sqlite.ErrActorTaken):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// handleListActors handles GET /actors
+func (s *Server) handleListActors(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.actorStore(w, r)
+	if !ok {
+		return
+	}
+
+	actors, err := sqliteStore.ListActors(r.Context())
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if actors == nil {
+		actors = []*sqlite.Actor{}
+	}
+
+	s.writeSuccess(w, r, actors, opActors)
+}
+
+// handleCreateActor handles POST /actors
+func (s *Server) handleCreateActor(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.actorStore(w, r)
+	if !ok {
+		return
+	}
+
+	var body actorRequest
+	if err := s.parseBody(r, &body); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	actor := &sqlite.Actor{Name: body.Name, Email: body.Email, Type: body.Type, Aliases: body.Aliases}
+	if err := sqliteStore.CreateActor(r.Context(), actor); err != nil {
+		s.writeError(w, r, actorErrorStatus(err), err)
+		return
+	}
+
+	s.writeSuccess(w, r, []*sqlite.Actor{actor}, opActors)
+}
+
+// handleShowActor handles GET /actors/{name}, where name may be an alias or
+// email
+func (s *Server) handleShowActor(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.actorStore(w, r)
+	if !ok {
+		return
+	}
+
+	actor, err := sqliteStore.GetActor(r.Context(), mux.Vars(r)["name"])
+	if err != nil {
+		s.writeError(w, r, actorErrorStatus(err), err)
+		return
+	}
+
+	s.writeSuccess(w, r, []*sqlite.Actor{actor}, opActors)
+}
+
+// handleUpdateActor handles PATCH /actors/{name}
+func (s *Server) handleUpdateActor(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.actorStore(w, r)
+	if !ok {
+		return
+	}
+
+	var body actorRequest
+	if err := s.parseBody(r, &body); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	actor, err := sqliteStore.UpdateActor(r.Context(), mux.Vars(r)["name"], &sqlite.Actor{Email: body.Email, Type: body.Type, Aliases: body.Aliases})
+	if err != nil {
+		s.writeError(w, r, actorErrorStatus(err), err)
+		return
+	}
+
+	s.writeSuccess(w, r, []*sqlite.Actor{actor}, opActors)
+}
+
+// handleDeleteActor handles DELETE /actors/{name}. Records naming the
+// actor are left alone.
+func (s *Server) handleDeleteActor(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.actorStore(w, r)
+	if !ok {
+		return
+	}
+
+	if err := sqliteStore.DeleteActor(r.Context(), mux.Vars(r)["name"]); err != nil {
+		s.writeError(w, r, actorErrorStatus(err), err)
+		return
+	}
+
+	s.writeSuccess(w, r, map[string]string{"message": "actor deleted"}, "actor_delete")
+}
+
+// handleRemoveActorAlias handles DELETE /actors/{name}/aliases/{alias}
+func (s *Server) handleRemoveActorAlias(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.actorStore(w, r)
+	if !ok {
+		return
+	}
+
+	vars := mux.Vars(r)
+	actor, err := sqliteStore.GetActor(r.Context(), vars["name"])
+	if err != nil {
+		s.writeError(w, r, actorErrorStatus(err), err)
+		return
+	}
+	owner, err := sqliteStore.ResolveActor(r.Context(), vars["alias"])
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if owner != actor.Name {
+		s.writeError(w, r, http.StatusNotFound, fmt.Errorf("%s is not an alias of %s", vars["alias"], actor.Name))
+		return
+	}
+	if err := sqliteStore.RemoveActorAlias(r.Context(), vars["alias"]); err != nil {
+		s.writeError(w, r, actorErrorStatus(err), err)
+		return
+	}
+
+	actor, err = sqliteStore.GetActor(r.Context(), actor.Name)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	s.writeSuccess(w, r, []*sqlite.Actor{actor}, opActors)
+}
+
+// handleMergeActor handles POST /actors/{name}/merge, rewriting every
+// record naming from to name {name} instead
+func (s *Server) handleMergeActor(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.actorStore(w, r)
+	if !ok {
+		return
+	}
+
+	var body actorMergeRequest
+	if err := s.parseBody(r, &body); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	merge, err := sqliteStore.MergeActors(r.Context(), body.From, mux.Vars(r)["name"], body.DryRun)
+	if err != nil {
+		s.writeError(w, r, actorErrorStatus(err), err)
+		return
+	}
+
+	s.writeSuccess(w, r, merge, opActorMerge)
+}
+
+func (s *Server) actorStore(w http.ResponseWriter, r *http.Request) (*sqlite.SQLiteStorage, bool) {
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("the actor registry requires SQLite backend"))
+	}
+	return sqliteStore, ok
+}
@@ -0,0 +1,119 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestActors(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	do := func(method, path, actor, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("X-Actor", actor)
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+	actor := func(rec *httptest.ResponseRecorder) *sqlite.Actor {
+		t.Helper()
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+		}
+		var actors []*sqlite.Actor
+		if err := json.Unmarshal(rec.Body.Bytes(), &actors); err != nil || len(actors) != 1 {
+			t.Fatalf("Expected one actor, got %s", rec.Body)
+		}
+		return actors[0]
+	}
+
+	// History written under an alias before it was registered
+	issue := &types.Issue{Title: "Fix it", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, Assignee: "al"}
+	if err := store.CreateIssue(ctx, issue, "al"); err != nil {
+		t.Fatal(err)
+	}
+
+	created := actor(do("POST", "/actors", "", `{"name": "alice", "email": "alice@corp.com", "type": "agent"}`))
+	if created.Type != sqlite.ActorAgent {
+		t.Errorf("Expected an agent, got %+v", created)
+	}
+	if rec := do("POST", "/actors", "", `{"name": "bob", "email": "ALICE@corp.com"}`); rec.Code != http.StatusConflict {
+		t.Errorf("Expected 409 reusing an email, got %d", rec.Code)
+	}
+	if rec := do("POST", "/actors", "", `{"name": "ci", "type": "robot"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid type, got %d", rec.Code)
+	}
+	if got := actor(do("GET", "/actors/alice@corp.com", "", "")); got.Name != "alice" {
+		t.Errorf("Expected the email to find alice, got %+v", got)
+	}
+	if rec := do("GET", "/actors/nobody", "", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unregistered actor, got %d", rec.Code)
+	}
+
+	// The request's actor is normalized
+	rec := do("POST", "/issues", "Alice@Corp.com", `{"title": "New", "priority": 2, "issue_type": "task"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected the issue created, got %d: %s", rec.Code, rec.Body)
+	}
+	var createdIssue types.Issue
+	if err := json.Unmarshal(rec.Body.Bytes(), &createdIssue); err != nil {
+		t.Fatal(err)
+	}
+	events, _ := store.GetEvents(ctx, createdIssue.ID, 0)
+	if len(events) == 0 || events[0].Actor != "alice" {
+		t.Errorf("Expected the create attributed to alice, got %+v", events)
+	}
+
+	// Merging rewrites the old history and makes al an alias
+	rec = do("POST", "/actors/alice/merge", "", `{"from": "al"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var merge sqlite.ActorMerge
+	if err := json.Unmarshal(rec.Body.Bytes(), &merge); err != nil {
+		t.Fatal(err)
+	}
+	if merge.Into != "alice" || len(merge.IssuesAffected) != 1 || merge.IssuesAffected[0] != issue.ID {
+		t.Errorf("Expected al merged into alice on %s, got %+v", issue.ID, merge)
+	}
+	if got, _ := store.GetIssue(ctx, issue.ID); got.Assignee != "alice" {
+		t.Errorf("Expected the assignee rewritten, got %q", got.Assignee)
+	}
+	if got := actor(do("GET", "/actors/AL", "", "")); got.Name != "alice" {
+		t.Errorf("Expected al to resolve to alice, got %+v", got)
+	}
+
+	if got := actor(do("DELETE", "/actors/alice/aliases/al", "", "")); len(got.Aliases) != 0 {
+		t.Errorf("Expected the alias removed, got %+v", got)
+	}
+	if rec := do("DELETE", "/actors/alice", "", ""); rec.Code != http.StatusOK {
+		t.Errorf("Expected 200 deleting alice, got %d: %s", rec.Code, rec.Body)
+	}
+	var actors []*sqlite.Actor
+	if err := json.Unmarshal(do("GET", "/actors", "", "").Body.Bytes(), &actors); err != nil || len(actors) != 0 {
+		t.Errorf("Expected no actors left, got %v (%v)", actors, err)
+	}
+}
@@ -0,0 +1,43 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/leadtime"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+)
+
+// handleLeadTime handles GET /analytics/lead-time
+func (s *Server) handleLeadTime(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("lead time reports require SQLite backend"))
+		return
+	}
+
+	groupBy := r.URL.Query().Get("group_by")
+	if groupBy != "" && !leadtime.IsValidGroup(groupBy) {
+		s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("invalid group_by %q", groupBy))
+		return
+	}
+	var since time.Time
+	if v := r.URL.Query().Get("days"); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil || days <= 0 {
+			s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("invalid days %q", v))
+			return
+		}
+		since = time.Now().AddDate(0, 0, -days)
+	}
+
+	report, err := leadtime.Compute(r.Context(), sqliteStore, groupBy, since)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.writeSuccess(w, r, report, opLeadTime)
+}
@@ -0,0 +1,74 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/leadtime"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestLeadTime(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	do := func(path, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Accept", accept)
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for _, assignee := range []string{"alice", "bob"} {
+		issue := &types.Issue{Title: "Work", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, Assignee: assignee}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.CloseIssue(ctx, issue.ID, "done", "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rec := do("/analytics/lead-time?group_by=assignee&days=7", "application/json")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var report leadtime.Report
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("failed to decode %s: %v", rec.Body, err)
+	}
+	if report.Overall.LeadTime.Count != 2 || len(report.Groups) != 2 || report.Groups[0].Key != "alice" || report.Since == nil {
+		t.Errorf("Unexpected report %s", rec.Body)
+	}
+
+	rec = do("/analytics/lead-time", "text/plain")
+	if body := rec.Body.String(); !strings.Contains(body, "Lead time") || !strings.Contains(body, "(all)") {
+		t.Errorf("Expected lead time tables, got %s", body)
+	}
+
+	if rec = do("/analytics/lead-time?group_by=status", "application/json"); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown dimension, got %d", rec.Code)
+	}
+	if rec = do("/analytics/lead-time?days=-1", "application/json"); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for negative days, got %d", rec.Code)
+	}
+}
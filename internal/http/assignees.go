@@ -0,0 +1,48 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/imalsogreg/beads/internal/storage"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// handleWorkload handles GET /assignees/workload
+func (s *Server) handleWorkload(w http.ResponseWriter, r *http.Request) {
+	workload, err := storage.GetWorkload(r.Context(), s.storage)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.writeSuccess(w, r, workload, opWorkload)
+}
+
+// handleSuggestAssignees handles GET /issues/{id}/assignee-suggestions
+func (s *Server) handleSuggestAssignees(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := mux.Vars(r)["id"]
+
+	issue, err := s.storage.GetIssue(ctx, id)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if issue == nil {
+		s.writeError(w, r, http.StatusNotFound, types.NotFound("issue", id))
+		return
+	}
+
+	suggestions, err := storage.SuggestAssignees(ctx, s.storage, id, time.Now())
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if suggestions == nil {
+		suggestions = []*types.AssigneeSuggestion{}
+	}
+
+	s.writeSuccess(w, r, suggestions, opAssigneeSuggestions)
+}
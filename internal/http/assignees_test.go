@@ -0,0 +1,74 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestWorkloadEndpoints(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Accept", "application/json")
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	estimate := 120
+	for _, issue := range []*types.Issue{
+		{Title: "Alice's first", Assignee: "alice", EstimatedMinutes: &estimate},
+		{Title: "Alice's second", Assignee: "alice"},
+		{Title: "Bob's", Assignee: "bob"},
+		{Title: "Nobody's"},
+	} {
+		issue.Status = types.StatusOpen
+		issue.IssueType = types.TypeTask
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rec := get("/assignees/workload")
+	var workload []*types.AssigneeWorkload
+	if err := json.Unmarshal(rec.Body.Bytes(), &workload); err != nil {
+		t.Fatalf("failed to decode %s: %v", rec.Body, err)
+	}
+	if len(workload) != 2 || workload[0].Assignee != "alice" || workload[0].Open != 2 || workload[0].EstimatedMinutes != 120 {
+		t.Errorf("Unexpected workload: %s", rec.Body)
+	}
+
+	rec = get("/issues/bd-4/assignee-suggestions")
+	var suggestions []*types.AssigneeSuggestion
+	if err := json.Unmarshal(rec.Body.Bytes(), &suggestions); err != nil {
+		t.Fatalf("failed to decode %s: %v", rec.Body, err)
+	}
+	if len(suggestions) != 2 || suggestions[0].Assignee != "bob" {
+		t.Errorf("Expected bob to be suggested first, got %s", rec.Body)
+	}
+
+	if rec := get("/issues/bd-99/assignee-suggestions"); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a missing issue, got %d", rec.Code)
+	}
+}
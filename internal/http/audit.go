@@ -0,0 +1,78 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+)
+
+// maxAuditLimit caps the page size of GET /audit
+const maxAuditLimit = 500
+
+// handleAuditLog handles GET /audit, a paginated change log with
+// field-level diffs
+func (s *Server) handleAuditLog(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("audit log requires SQLite backend"))
+		return
+	}
+
+	filter, ok := s.parseAuditFilter(w, r)
+	if !ok {
+		return
+	}
+	page, err := sqliteStore.QueryAuditLog(r.Context(), filter)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.writeSuccess(w, r, page, opAudit)
+}
+
+// parseAuditFilter reads the audit log's query parameters, writing an
+// error response if one is invalid
+func (s *Server) parseAuditFilter(w http.ResponseWriter, r *http.Request) (sqlite.AuditFilter, bool) {
+	query := r.URL.Query()
+	filter := sqlite.AuditFilter{
+		Actor:   query.Get("actor"),
+		Session: query.Get("session"),
+		IssueID: query.Get("issue"),
+		Field:   query.Get("field"),
+	}
+
+	now := time.Now()
+	for param, dest := range map[string]**time.Time{"since": &filter.Since, "until": &filter.Until} {
+		value := query.Get(param)
+		if value == "" {
+			continue
+		}
+		t, err := sqlite.ParseAuditTime(value, now)
+		if err != nil {
+			s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("%s: %w", param, err))
+			return filter, false
+		}
+		*dest = &t
+	}
+	if v := query.Get("cursor"); v != "" {
+		cursor, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || cursor <= 0 {
+			s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("invalid cursor %q", v))
+			return filter, false
+		}
+		filter.Before = cursor
+	}
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("invalid limit %q", v))
+			return filter, false
+		}
+		filter.Limit = min(limit, maxAuditLimit)
+	}
+	return filter, true
+}
@@ -1,60 +1,238 @@
 package http
 
 import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"os"
 	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/imalsogreg/beads/internal/apikey"
+	"github.com/imalsogreg/beads/internal/oidc"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
 )
 
-// authMiddleware checks for valid Bearer token
+// principal is who a request authenticated as
+type principal struct {
+	Role    apikey.Role
+	Key     *sqlite.APIKey // nil for BEADS_API_SECRET, OIDC, and development mode
+	Subject string         // The actor named by an OIDC token
+}
+
+type principalKey struct{}
+
+// requestPrincipal returns who r authenticated as, or nil for public reads
+func requestPrincipal(r *http.Request) *principal {
+	p, _ := r.Context().Value(principalKey{}).(*principal)
+	return p
+}
+
+// adminRoutes need an admin key. Of the rest, GETs need a reader and
+// everything else a writer.
+var adminRoutes = map[string]bool{
+	"GET /keys":                 true,
+	"POST /keys":                true,
+	"DELETE /keys/{id}":         true,
+	"PUT /config/{key}":         true,
+	"DELETE /config/{key}":      true,
+	"PUT /workflow":             true,
+	"GET /webhooks":             true,
+	"POST /webhooks":            true,
+	"DELETE /webhooks/{id}":     true,
+	"POST /webhooks/{id}/test":  true,
+	"POST /actors/{name}/merge": true,
+	"POST /rules":               true,
+	"DELETE /rules/{name}":      true,
+	"POST /admin/purge-actor":   true,
+	"GET /admin/purge-reports":  true,
+	"GET /admin/drain":          true,
+	"POST /admin/drain":         true,
+	"DELETE /admin/drain":       true,
+	"POST /admin/backup":        true,
+}
+
+// requiredRole returns the role needed to call a route
+func requiredRole(method, pathTemplate string) apikey.Role {
+	switch {
+	case adminRoutes[method+" "+pathTemplate]:
+		return apikey.RoleAdmin
+	case method == http.MethodGet || method == http.MethodHead:
+		return apikey.RoleReader
+	default:
+		return apikey.RoleWriter
+	}
+}
+
+// keyTouchInterval limits how often a key's last use is written
+const keyTouchInterval = time.Minute
+
+// EnableOIDC accepts JWTs from an OpenID Connect provider as Bearer tokens,
+// alongside BEADS_API_SECRET and API keys. Requests with a valid token act
+// as the token's subject (or config.ActorClaim) with role. Call before Start.
+func (s *Server) EnableOIDC(config oidc.Config, role apikey.Role) error {
+	verifier, err := oidc.NewVerifier(config)
+	if err != nil {
+		return err
+	}
+	s.oidc = verifier
+	s.oidcRole = role
+	return nil
+}
+
+// authMiddleware checks the Bearer token and the role it grants for the
+// matched route. BEADS_API_SECRET acts as an admin key. With neither a
+// secret, OIDC, nor any API keys, every request is allowed (development
+// mode).
 func (s *Server) authMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Skip auth for the docs endpoint so agents can read how to authenticate
-		if r.URL.Path == "/" && r.Method == "GET" {
+		// Skip auth for the docs endpoints so agents can read how to authenticate
+		if r.Method == "GET" && (r.URL.Path == "/" || r.URL.Path == "/openapi.json" || r.URL.Path == "/docs" || r.URL.Path == "/tools") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Orchestrators probe without tokens
+		if r.Method == "GET" && probeRoutes[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// The workspace listings check the token against each workspace
+		if r.Method == "GET" && workspaceListRoutes[r.URL.Path] {
 			next.ServeHTTP(w, r)
 			return
 		}
 
-		// Get the expected token from environment
-		expectedToken := os.Getenv("BEADS_API_SECRET")
-		if expectedToken == "" {
-			// If no secret is configured, allow all requests (development mode)
+		// The web UI's static files carry no token; its API calls do
+		if isUIPath(r) {
 			next.ServeHTTP(w, r)
 			return
 		}
 
-		// Check Authorization header
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			s.writeAuthError(w, r, "Missing Authorization header")
+		// Feeds can be opened to readers without API tokens
+		if s.isPublicRead(r) {
+			next.ServeHTTP(w, r)
 			return
 		}
 
-		// Expect "Bearer <token>"
-		parts := strings.SplitN(authHeader, " ", 2)
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			s.writeAuthError(w, r, "Invalid Authorization header format. Expected: Authorization: Bearer <token>")
+		p, ok := s.authenticate(w, r)
+		if !ok {
 			return
 		}
 
-		token := parts[1]
-		if token != expectedToken {
-			s.writeAuthError(w, r, "Invalid token")
+		required := apikey.RoleWriter
+		if route := mux.CurrentRoute(r); route != nil {
+			if tmpl, err := route.GetPathTemplate(); err == nil {
+				required = requiredRole(r.Method, tmpl)
+			}
+		}
+		if !p.Role.Allows(required) {
+			s.writeError(w, r, http.StatusForbidden, fmt.Errorf("this endpoint requires the %s role; key has %s", required, p.Role))
 			return
 		}
 
-		// Token is valid, continue
-		next.ServeHTTP(w, r)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), principalKey{}, p)))
 	})
 }
 
+// authenticate works out who r is from its Bearer token. If that fails it
+// writes the error response and returns false. With no credentials
+// configured at all, everyone is an admin.
+func (s *Server) authenticate(w http.ResponseWriter, r *http.Request) (*principal, bool) {
+	ctx := r.Context()
+	expectedToken := os.Getenv("BEADS_API_SECRET")
+	sqliteStore, hasKeyStore := s.storage.(*sqlite.SQLiteStorage)
+	if expectedToken == "" && s.oidc == nil {
+		hasKeys := false
+		if hasKeyStore {
+			var err error
+			if hasKeys, err = sqliteStore.HasAPIKeys(ctx); err != nil {
+				s.writeStoreError(w, r, err)
+				return nil, false
+			}
+		}
+		if !hasKeys {
+			// No credentials configured, allow all requests (development mode)
+			return &principal{Role: apikey.RoleAdmin}, true
+		}
+	}
+
+	token, msg := bearerToken(r)
+	if token == "" {
+		s.writeAuthError(w, r, msg)
+		return nil, false
+	}
+
+	var p *principal
+	if expectedToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(expectedToken)) == 1 {
+		p = &principal{Role: apikey.RoleAdmin}
+	} else if s.oidc != nil && oidc.LooksLikeJWT(token) {
+		claims, err := s.oidc.Verify(ctx, token)
+		if errors.Is(err, oidc.ErrInvalidToken) {
+			s.writeAuthError(w, r, err.Error())
+			return nil, false
+		}
+		if err != nil {
+			// The provider couldn't be reached, which isn't the caller's fault
+			s.writeError(w, r, http.StatusServiceUnavailable, err)
+			return nil, false
+		}
+		p = &principal{Role: s.oidcRole, Subject: claims.Actor}
+	} else if hasKeyStore {
+		key, err := sqliteStore.GetAPIKeyByHash(ctx, apikey.Hash(token))
+		if err != nil {
+			s.writeStoreError(w, r, err)
+			return nil, false
+		}
+		if key != nil {
+			p = &principal{Role: apikey.Role(key.Role), Key: key}
+			now := time.Now()
+			if !s.readOnly && (key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > keyTouchInterval) {
+				_ = sqliteStore.TouchAPIKey(ctx, key.ID, now)
+			}
+		}
+	}
+	if p == nil {
+		s.writeAuthError(w, r, "Invalid token")
+		return nil, false
+	}
+	return p, true
+}
+
+// bearerToken extracts the token from the Authorization header, or on /ws
+// from the token query parameter since browsers cannot set headers on
+// WebSocket connections. It returns an error message if there's none.
+func bearerToken(r *http.Request) (string, string) {
+	authHeader := r.Header.Get("Authorization")
+	if r.URL.Path == "/ws" && authHeader == "" {
+		if token := r.URL.Query().Get("token"); token != "" {
+			return token, ""
+		}
+	}
+	if authHeader == "" {
+		return "", "Missing Authorization header"
+	}
+
+	// Expect "Bearer <token>"
+	parts := strings.SplitN(authHeader, " ", 2)
+	if len(parts) != 2 || parts[0] != "Bearer" || parts[1] == "" {
+		return "", "Invalid Authorization header format. Expected: Authorization: Bearer <token>"
+	}
+	return parts[1], ""
+}
+
 // writeAuthError writes an authentication error response
 func (s *Server) writeAuthError(w http.ResponseWriter, r *http.Request, message string) {
 	if s.wantsJSON(r) {
 		w.Header().Set("Content-Type", "application/json")
 		w.Header().Set("WWW-Authenticate", `Bearer realm="beads-api"`)
 		w.WriteHeader(http.StatusUnauthorized)
-		w.Write([]byte(`{"error":"` + message + `","success":false}`))
+		json.NewEncoder(w).Encode(newErrorBody(http.StatusUnauthorized, errors.New(message)))
 	} else {
 		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 		w.Header().Set("WWW-Authenticate", `Bearer realm="beads-api"`)
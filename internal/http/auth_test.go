@@ -0,0 +1,219 @@
+package http
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/apikey"
+	"github.com/imalsogreg/beads/internal/digest"
+	"github.com/imalsogreg/beads/internal/oidc"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestAPIKeyRoles(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	do := func(method, path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Accept", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Without a secret or keys everything is allowed, including creating the first key
+	rec := do("POST", "/keys", "", `{"name": "root", "role": "admin"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected first key to be created in development mode, got %d: %s", rec.Code, rec.Body)
+	}
+	var admin createdKey
+	if err := json.Unmarshal(rec.Body.Bytes(), &admin); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(admin.Token, admin.Hint) {
+		t.Errorf("Expected hint %q to prefix token", admin.Hint)
+	}
+
+	// Once a key exists, requests need a token
+	if rec := do("GET", "/issues", "", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without a token, got %d", rec.Code)
+	}
+	if rec := do("GET", "/issues", "bdk_wrong", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for an unknown token, got %d", rec.Code)
+	}
+
+	tokens := make(map[apikey.Role]string)
+	for _, role := range []apikey.Role{apikey.RoleReader, apikey.RoleWriter} {
+		rec := do("POST", "/keys", admin.Token, `{"name": "agent-`+string(role)+`", "role": "`+string(role)+`"}`)
+		var key createdKey
+		if err := json.Unmarshal(rec.Body.Bytes(), &key); err != nil || rec.Code != http.StatusOK {
+			t.Fatalf("Failed to create %s key: %d %s", role, rec.Code, rec.Body)
+		}
+		tokens[role] = key.Token
+	}
+	tokens[apikey.RoleAdmin] = admin.Token
+
+	createIssue := `{"title": "From a key", "issue_type": "task", "priority": 2}`
+	tests := []struct {
+		role         apikey.Role
+		method, path string
+		body         string
+		want         int
+	}{
+		{apikey.RoleReader, "GET", "/issues", "", http.StatusOK},
+		{apikey.RoleReader, "POST", "/issues", createIssue, http.StatusForbidden},
+		{apikey.RoleWriter, "POST", "/issues", createIssue, http.StatusOK},
+		{apikey.RoleWriter, "GET", "/keys", "", http.StatusForbidden},
+		{apikey.RoleWriter, "PUT", "/config/issue_prefix", `{"value": "xx"}`, http.StatusForbidden},
+		{apikey.RoleAdmin, "GET", "/keys", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		if rec := do(tt.method, tt.path, tokens[tt.role], tt.body); rec.Code != tt.want {
+			t.Errorf("%s %s as %s: got %d, want %d: %s", tt.method, tt.path, tt.role, rec.Code, tt.want, rec.Body)
+		}
+	}
+
+	// Readers see config, but not the credentials in it
+	if err := store.SetConfig(ctx, digest.SMTPPasswordKey, "hunter2"); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"/config", "/config/" + digest.SMTPPasswordKey} {
+		rec := do("GET", path, tokens[apikey.RoleReader], "")
+		if rec.Code != http.StatusOK || strings.Contains(rec.Body.String(), "hunter2") || !strings.Contains(rec.Body.String(), maskedConfigValue) {
+			t.Errorf("Expected GET %s to mask the SMTP password, got %d: %s", path, rec.Code, rec.Body)
+		}
+	}
+
+	// Writes made with a key are attributed to its name
+	issues, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil || len(issues) != 1 {
+		t.Fatalf("Expected one issue, got %d (%v)", len(issues), err)
+	}
+	events, _ := store.GetEvents(ctx, issues[0].ID, 1)
+	if len(events) != 1 || events[0].Actor != "agent-writer" {
+		t.Errorf("Expected create by agent-writer, got %+v", events)
+	}
+
+	// The shared secret still works and has the admin role
+	t.Setenv("BEADS_API_SECRET", "sekret")
+	if rec := do("GET", "/keys", "sekret", ""); rec.Code != http.StatusOK {
+		t.Errorf("Expected secret to have admin access, got %d", rec.Code)
+	}
+
+	// Revoked keys stop working
+	keys, _ := store.ListAPIKeys(ctx)
+	if rec := do("DELETE", "/keys/"+strconv.FormatInt(keys[1].ID, 10), admin.Token, ""); rec.Code != http.StatusOK {
+		t.Fatalf("Failed to revoke key: %d %s", rec.Code, rec.Body)
+	}
+	if rec := do("GET", "/issues", tokens[apikey.RoleReader], ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected revoked key to be rejected, got %d", rec.Code)
+	}
+}
+
+func TestOIDCAuth(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA", "kid": "k1",
+			"n": base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	}))
+	defer jwks.Close()
+	sign := func(claims string) string {
+		signed := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","kid":"k1"}`)) + "." +
+			base64.RawURLEncoding.EncodeToString([]byte(claims))
+		digest := sha256.Sum256([]byte(signed))
+		sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+	}
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = srv.EnableOIDC(oidc.Config{Issuer: "https://idp.example.com", Audience: "beads", JWKSURL: jwks.URL}, apikey.RoleWriter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	do := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/issues", strings.NewReader(`{"title": "From OIDC", "issue_type": "task"}`))
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("X-Actor", "mallory")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// OIDC turns off development mode
+	if rec := do(""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without a token, got %d", rec.Code)
+	}
+	exp := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	if rec := do(sign(`{"iss":"https://idp.example.com","aud":"other","sub":"alice","exp":` + exp + `}`)); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for the wrong audience, got %d", rec.Code)
+	}
+
+	rec := do(sign(`{"iss":"https://idp.example.com","aud":"beads","sub":"alice","exp":` + exp + `}`))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected a valid token to be accepted, got %d: %s", rec.Code, rec.Body)
+	}
+	var issue types.Issue
+	if err := json.Unmarshal(rec.Body.Bytes(), &issue); err != nil {
+		t.Fatal(err)
+	}
+	events, _ := store.GetEvents(ctx, issue.ID, 1)
+	if len(events) != 1 || events[0].Actor != "alice" {
+		t.Errorf("Expected the token subject as actor despite X-Actor, got %+v", events)
+	}
+}
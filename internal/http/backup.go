@@ -0,0 +1,76 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/backup"
+	"github.com/imalsogreg/beads/internal/rpc"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+)
+
+// EnableBackups lets POST /admin/backup upload backups with uploader.
+// Without it, backups are only kept next to the database.
+func (s *Server) EnableBackups(uploader *backup.S3) {
+	s.backupUploader = uploader
+}
+
+// EnableScheduledBackups makes Start take a backup every interval in the
+// background, keeping the newest retain (0 keeps all) and uploading them if
+// EnableBackups was given an uploader. SQLite only.
+func (s *Server) EnableScheduledBackups(interval time.Duration, retain int) {
+	s.backupInterval = interval
+	s.backupRetain = retain
+}
+
+// backupRequest is the body of POST /admin/backup
+type backupRequest struct {
+	Note   string `json:"note,omitempty"`
+	Upload bool   `json:"upload,omitempty"`
+}
+
+// handleBackup handles POST /admin/backup, which snapshots the database
+// into the backups directory next to it, like bd backup create
+func (s *Server) handleBackup(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("backups require SQLite backend"))
+		return
+	}
+
+	var body backupRequest
+	if err := s.parseBody(r, &body); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if body.Upload && s.backupUploader == nil {
+		s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("no backup storage configured (backup.s3 config)"))
+		return
+	}
+
+	stats, err := sqliteStore.GetStatistics(ctx)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	dir := backup.Dir(sqliteStore.Path())
+	m, err := backup.Create(ctx, sqliteStore, dir, backup.Options{
+		Version: rpc.ServerVersion,
+		Issues:  stats.TotalIssues,
+		Note:    body.Note,
+	})
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if body.Upload {
+		if err := s.backupUploader.Upload(ctx, dir, m); err != nil {
+			s.writeError(w, r, http.StatusBadGateway, fmt.Errorf("backup %s was taken but not uploaded: %w", m.ID, err))
+			return
+		}
+	}
+
+	s.writeSuccess(w, r, m, opBackup)
+}
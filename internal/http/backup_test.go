@@ -0,0 +1,84 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/backup"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+)
+
+func TestBackupEndpoint(t *testing.T) {
+	srv := newLifecycleTestServer(t)
+	srv.EnableReadOnly()
+	do := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/admin/backup", strings.NewReader(body))
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Backups only read the database, so a read-only server takes them
+	rec := do(`{"note": "nightly"}`)
+	var m backup.Manifest
+	if err := json.Unmarshal(rec.Body.Bytes(), &m); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("POST /admin/backup: %d %s", rec.Code, rec.Body)
+	}
+	dir := backup.Dir(srv.storage.(*sqlite.SQLiteStorage).Path())
+	if err := backup.Verify(dir, &m); err != nil {
+		t.Errorf("Expected a complete backup: %v", err)
+	}
+	if m.Note != "nightly" {
+		t.Errorf("Expected the note in the manifest, got %+v", m)
+	}
+
+	if rec := do(`{"upload": true}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected upload without backup storage to be refused, got %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestScheduledBackupStatus(t *testing.T) {
+	srv := newLifecycleTestServer(t)
+	srv.EnableScheduledBackups(time.Hour, 3)
+	if err := srv.startBackground(); err != nil {
+		t.Fatal(err)
+	}
+	defer srv.stopBackground()
+	if err := srv.backups.RunOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest("GET", "/status", nil)
+	req.Header.Set("Accept", "application/json")
+	rec := httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, req)
+	var status serverStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+		t.Fatalf("GET /status: %d %s", rec.Code, rec.Body)
+	}
+	if status.Backups == nil || status.Backups.Last == nil || status.Backups.Retain != 3 || status.Backups.Retained != 1 {
+		t.Errorf("Expected the scheduled backup in /status, got %+v", status.Backups)
+	}
+
+	// Prometheus asks for the text format in its Accept header
+	req = httptest.NewRequest("GET", "/metrics", nil)
+	req.Header.Set("Accept", "application/openmetrics-text;version=1.0.0,text/plain;version=0.0.4;q=0.5,*/*;q=0.1")
+	rec = httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, req)
+	body := rec.Body.String()
+	want := fmt.Sprintf(`beads_backup_last_success_timestamp_seconds{workspace="default"} %d`, status.Backups.Last.CreatedAt.Unix())
+	if !strings.Contains(body, want) || !strings.Contains(body, "# TYPE beads_backup_failures_total counter") {
+		t.Errorf("Expected backup metrics in the Prometheus format, got:\n%s", body)
+	}
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Errorf("Unexpected content type %q", rec.Header().Get("Content-Type"))
+	}
+}
@@ -0,0 +1,81 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestBlockers(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	do := func(path, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Accept", accept)
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	var issues []*types.Issue
+	for _, assignee := range []string{"", "bob", "carol"} {
+		issue := &types.Issue{Title: "Work", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, Assignee: assignee}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatal(err)
+		}
+		issues = append(issues, issue)
+	}
+	for i := 1; i < len(issues); i++ {
+		dep := &types.Dependency{IssueID: issues[i-1].ID, DependsOnID: issues[i].ID, Type: types.DepBlocks}
+		if err := store.AddDependency(ctx, dep, "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	decode := func(rec *httptest.ResponseRecorder) types.BlockerTree {
+		t.Helper()
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+		}
+		var tree types.BlockerTree
+		if err := json.Unmarshal(rec.Body.Bytes(), &tree); err != nil {
+			t.Fatalf("failed to decode %s: %v", rec.Body, err)
+		}
+		return tree
+	}
+	if tree := decode(do("/issues/"+issues[0].ID+"/blockers", "application/json")); tree.Total != 1 || tree.Blockers[0].Issue.Assignee != "bob" {
+		t.Errorf("Expected bob's issue as the only direct blocker, got %+v", tree)
+	}
+	if tree := decode(do("/issues/"+issues[0].ID+"/blockers?transitive=true", "application/json")); tree.Total != 2 || tree.Blockers[1].Depth != 2 {
+		t.Errorf("Expected carol's issue at depth 2, got %+v", tree)
+	}
+
+	rec := do("/issues/"+issues[0].ID+"/blockers?transitive=true", "text/plain")
+	if body := rec.Body.String(); !strings.Contains(body, "blocked by 2 open issue(s)") || !strings.Contains(body, "\n    "+issues[2].ID+" [P2] Work (open, carol)") {
+		t.Errorf("Expected the blockers as text, got %s", body)
+	}
+
+	if rec = do("/issues/bd-999/blockers", "application/json"); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a missing issue, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,176 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+)
+
+// maxCacheEntries bounds the query cache; when it fills up, results from
+// older generations go first, then everything
+const maxCacheEntries = 1000
+
+// CacheStats counts query cache lookups, reported by GET /metrics
+type CacheStats struct {
+	Hits          int64 `json:"hits"`
+	Misses        int64 `json:"misses"`
+	Invalidations int64 `json:"invalidations"` // Misses because the database changed
+	Expirations   int64 `json:"expirations"`   // Misses because the result outlived the TTL
+	Entries       int   `json:"entries"`
+}
+
+// queryCache keeps the results of hot reads (statistics, ready work,
+// listings) keyed on the query and the database's generation, so polls
+// between writes skip the database. A write anywhere, including by another
+// process, changes the generation and so invalidates every result; the TTL
+// bounds how stale a result can get when the generation misses a change,
+// as with a read replica that lags the primary or ready work that becomes
+// due with time.
+type queryCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	stats   CacheStats
+}
+
+// cacheEntry is one cached result. done is closed once the result is
+// loaded, so concurrent misses for the same query wait for the first one
+// rather than all hitting the database.
+type cacheEntry struct {
+	generation uint64
+	expires    time.Time
+	done       chan struct{}
+	value      interface{}
+	err        error
+}
+
+// errLoadPanicked is the result recorded for a load that panicked, so
+// requests waiting on it load for themselves
+var errLoadPanicked = errors.New("query cache: load panicked")
+
+func newQueryCache(ttl time.Duration) *queryCache {
+	return &queryCache{ttl: ttl, entries: make(map[string]*cacheEntry)}
+}
+
+// EnableQueryCache caches statistics, ready work, and issue listings for up
+// to ttl, or until the database changes. SQLite only (file databases); a
+// zero ttl leaves caching off. Call before Start.
+func (s *Server) EnableQueryCache(ttl time.Duration) {
+	if ttl > 0 {
+		s.cache = newQueryCache(ttl)
+	}
+}
+
+// snapshot returns the counters so far
+func (c *queryCache) snapshot() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Entries = len(c.entries)
+	return stats
+}
+
+// lookup returns the entry for key at generation, and whether the caller
+// must load it (and then call finish)
+func (c *queryCache) lookup(key string, generation uint64, now time.Time) (*cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.entries[key]; ok {
+		switch {
+		case entry.generation != generation:
+			c.stats.Invalidations++
+		case now.After(entry.expires):
+			c.stats.Expirations++
+		default:
+			c.stats.Hits++
+			return entry, false
+		}
+	}
+	c.stats.Misses++
+	if len(c.entries) >= maxCacheEntries {
+		c.evict(generation)
+	}
+	entry := &cacheEntry{generation: generation, expires: now.Add(c.ttl), done: make(chan struct{})}
+	c.entries[key] = entry
+	return entry, true
+}
+
+// evict drops the entries of other generations, or all of them if every
+// entry is current
+func (c *queryCache) evict(generation uint64) {
+	for key, entry := range c.entries {
+		if entry.generation != generation {
+			delete(c.entries, key)
+		}
+	}
+	if len(c.entries) >= maxCacheEntries {
+		c.entries = make(map[string]*cacheEntry)
+	}
+}
+
+// finish stores a loaded result, dropping it again if loading failed so the
+// next request retries
+func (c *queryCache) finish(key string, entry *cacheEntry, value interface{}, err error) {
+	entry.value, entry.err = value, err
+	close(entry.done)
+	if err != nil {
+		c.mu.Lock()
+		if c.entries[key] == entry {
+			delete(c.entries, key)
+		}
+		c.mu.Unlock()
+	}
+}
+
+// cachedQuery returns load's result for key, from the server's query cache
+// when it has a current one. Without a cache, or when the generation can't
+// be read, it just calls load. Cached results are shared between requests,
+// so callers must not modify them.
+func cachedQuery[T any](ctx context.Context, s *Server, key string, load func() (T, error)) (T, error) {
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if s.cache == nil || !ok {
+		return load()
+	}
+	generation, err := sqliteStore.Generation(ctx)
+	if err != nil {
+		return load()
+	}
+
+	entry, miss := s.cache.lookup(key, generation, time.Now())
+	if miss {
+		loaded := false
+		defer func() {
+			// A panicking load must still release its waiters and drop
+			// the entry, or the query would block until the next write
+			if !loaded {
+				s.cache.finish(key, entry, nil, errLoadPanicked)
+			}
+		}()
+		value, err := load()
+		loaded = true
+		s.cache.finish(key, entry, value, err)
+		return value, err
+	}
+	select {
+	case <-entry.done:
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+	if entry.err != nil {
+		// The first request's failure may be its own, e.g. a disconnect
+		return load()
+	}
+	return entry.value.(T), nil
+}
+
+// cacheKey names a query for the cache: its kind and its parameters
+func cacheKey(kind string, params interface{}) string {
+	data, _ := json.Marshal(params)
+	return kind + " " + string(data)
+}
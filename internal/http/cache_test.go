@@ -0,0 +1,172 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestQueryCache(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	store, err := sqlite.New(dbPath)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.EnableQueryCache(time.Minute)
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Accept", "application/json")
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+	listed := func() int {
+		t.Helper()
+		var issues []*types.Issue
+		if err := json.Unmarshal(get("/issues").Body.Bytes(), &issues); err != nil {
+			t.Fatal(err)
+		}
+		return len(issues)
+	}
+
+	if n := listed(); n != 0 {
+		t.Fatalf("Expected no issues, got %d", n)
+	}
+	listed()
+	get("/issues/ready")
+	get("/issues/ready")
+	if stats := srv.cache.snapshot(); stats.Hits != 2 || stats.Misses != 2 || stats.Entries != 2 {
+		t.Errorf("Expected 2 hits and 2 misses, got %+v", stats)
+	}
+
+	// A write by another process invalidates every result
+	other, err := sqlite.New(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Close()
+	issue := &types.Issue{Title: "Fresh", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := other.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatal(err)
+	}
+	if n := listed(); n != 1 {
+		t.Errorf("Expected the new issue listed, got %d issues", n)
+	}
+	if stats := srv.cache.snapshot(); stats.Invalidations != 1 {
+		t.Errorf("Expected an invalidation, got %+v", stats)
+	}
+
+	// So does one through the server
+	req := httptest.NewRequest("POST", "/issues", strings.NewReader(`{"title": "Posted", "issue_type": "task", "priority": 2}`))
+	req.Header.Set("Content-Type", "application/json")
+	srv.router.ServeHTTP(httptest.NewRecorder(), req)
+	if n := listed(); n != 2 {
+		t.Errorf("Expected the posted issue listed, got %d issues", n)
+	}
+
+	rec := get("/metrics")
+	var metrics serverMetrics
+	if err := json.Unmarshal(rec.Body.Bytes(), &metrics); err != nil {
+		t.Fatal(err)
+	}
+	if metrics.Cache == nil || metrics.Cache.Hits != 2 {
+		t.Errorf("Expected cache metrics, got %+v", metrics.Cache)
+	}
+	if !strings.Contains(get("/metrics?format=prometheus").Body.String(), `beads_query_cache_lookups_total{result="hit"} 2`) {
+		t.Error("Expected cache lookups in the Prometheus metrics")
+	}
+}
+
+func TestQueryCacheLoadsOnce(t *testing.T) {
+	cache := newQueryCache(time.Minute)
+	now := time.Now()
+
+	entry, miss := cache.lookup("k", 1, now)
+	if !miss {
+		t.Fatal("Expected the first lookup to miss")
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			waiting, miss := cache.lookup("k", 1, now)
+			if miss {
+				t.Error("Expected lookups during a load to wait for it")
+				return
+			}
+			<-waiting.done
+			if waiting.value != "loaded" {
+				t.Errorf("Expected the loaded value, got %v", waiting.value)
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	cache.finish("k", entry, "loaded", nil)
+	wg.Wait()
+
+	if _, miss := cache.lookup("k", 1, now.Add(2*time.Minute)); !miss {
+		t.Error("Expected a result past its TTL to miss")
+	}
+	entry, _ = cache.lookup("k", 2, now)
+	cache.finish("k", entry, nil, errors.New("boom"))
+	if stats := cache.snapshot(); stats.Hits != 5 || stats.Expirations != 1 || stats.Invalidations != 1 || stats.Entries != 0 {
+		t.Errorf("Expected a failed load dropped, got %+v", stats)
+	}
+}
+
+func TestQueryCacheLoadPanics(t *testing.T) {
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.EnableQueryCache(time.Minute)
+	ctx := context.Background()
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected the load's panic to propagate")
+			}
+		}()
+		_, _ = cachedQuery(ctx, srv, "k", func() (string, error) { panic("boom") })
+	}()
+	if stats := srv.cache.snapshot(); stats.Entries != 0 {
+		t.Errorf("Expected the panicked load dropped, got %+v", stats)
+	}
+
+	// The next request loads again rather than waiting forever
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	value, err := cachedQuery(ctx, srv, "k", func() (string, error) { return "loaded", nil })
+	if err != nil || value != "loaded" {
+		t.Errorf("Expected a fresh load, got %q, %v", value, err)
+	}
+}
@@ -0,0 +1,145 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// calendarPublicKey is the config key that opens GET /calendar.ics to
+// unauthenticated readers. Calendar apps subscribe by URL and cannot send
+// bearer tokens.
+const calendarPublicKey = "calendar.public"
+
+// calendarEvent is one dated item in the iCalendar feed. All-day events set
+// only Start (and optionally End, exclusive); the feed never carries times of day.
+type calendarEvent struct {
+	UID         string
+	Summary     string
+	Description string
+	URL         string
+	Start       time.Time
+	End         time.Time // zero for single-day events
+	Categories  []string
+}
+
+// calendarFilter narrows the calendar to part of the project
+type calendarFilter struct {
+	Label string
+	Epic  string
+}
+
+// calendarSource contributes dated events to the calendar. Sources are
+// registered by the features that own the dates (due dates, sprints,
+// milestones).
+type calendarSource func(ctx context.Context, s *Server, filter calendarFilter, base string) ([]calendarEvent, error)
+
+// calendarSources lists the providers of calendar events
+var calendarSources []calendarSource
+
+// handleCalendar handles GET /calendar.ics
+func (s *Server) handleCalendar(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+	filter := calendarFilter{Label: query.Get("label"), Epic: query.Get("epic")}
+	base := baseURL(r)
+
+	var events []calendarEvent
+	for _, source := range calendarSources {
+		evs, err := source(ctx, s, filter, base)
+		if err != nil {
+			s.writeStoreError(w, r, err)
+			return
+		}
+		events = append(events, evs...)
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Start.Before(events[j].Start) })
+
+	prefix, _ := s.storage.GetConfig(ctx, "issue_prefix")
+	name := "Issues"
+	if prefix != "" {
+		name = prefix + " issues"
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, renderICS(name, prefix, events, time.Now()))
+}
+
+// renderICS encodes events as an RFC 5545 calendar
+func renderICS(name, prefix string, events []calendarEvent, now time.Time) string {
+	var b strings.Builder
+	line := func(s string) {
+		b.WriteString(foldICSLine(s))
+		b.WriteString("\r\n")
+	}
+	stamp := now.UTC().Format("20060102T150405Z")
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//beads//bd serve//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:" + escapeICSText(name))
+	for _, e := range events {
+		line("BEGIN:VEVENT")
+		line("UID:" + e.UID + "@" + escapeICSText(prefix) + ".beads")
+		line("DTSTAMP:" + stamp)
+		line("DTSTART;VALUE=DATE:" + e.Start.Format("20060102"))
+		end := e.End
+		if end.IsZero() || !end.After(e.Start) {
+			end = e.Start.AddDate(0, 0, 1)
+		}
+		line("DTEND;VALUE=DATE:" + end.Format("20060102"))
+		line("SUMMARY:" + escapeICSText(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION:" + escapeICSText(e.Description))
+		}
+		if e.URL != "" {
+			line("URL:" + e.URL)
+		}
+		if len(e.Categories) > 0 {
+			cats := make([]string, len(e.Categories))
+			for i, c := range e.Categories {
+				cats[i] = escapeICSText(c)
+			}
+			line("CATEGORIES:" + strings.Join(cats, ","))
+		}
+		line("TRANSP:TRANSPARENT")
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return b.String()
+}
+
+// escapeICSText escapes a TEXT property value
+func escapeICSText(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+	return r.Replace(s)
+}
+
+// foldICSLine splits content lines longer than 75 octets, without breaking
+// UTF-8 sequences
+func foldICSLine(s string) string {
+	const limit = 75
+	if len(s) <= limit {
+		return s
+	}
+	var b strings.Builder
+	width := limit
+	for len(s) > width {
+		cut := width
+		for cut > 0 && s[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		width = limit - 1 // continuation lines start with a space
+	}
+	b.WriteString(s)
+	return b.String()
+}
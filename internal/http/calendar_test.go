@@ -0,0 +1,232 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestRenderICS(t *testing.T) {
+	day := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	ics := renderICS("bd issues", "bd", []calendarEvent{
+		{UID: "one", Summary: "Single day", Start: day},
+		{UID: "span", Summary: "Spans a week", Start: day, End: day.AddDate(0, 0, 7)},
+		{UID: "backwards", Summary: "Ends before it starts", Start: day, End: day.AddDate(0, 0, -2)},
+		{UID: "text", Summary: "a, b; c\\d", Description: "line one\nline two", Categories: []string{"due", "x,y"}},
+	}, time.Date(2025, 3, 1, 12, 30, 0, 0, time.UTC))
+
+	if !strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\n") || !strings.HasSuffix(ics, "END:VCALENDAR\r\n") {
+		t.Errorf("Expected a CRLF-delimited VCALENDAR, got:\n%s", ics)
+	}
+	if strings.Count(ics, "BEGIN:VEVENT") != 4 || strings.Count(ics, "DTSTAMP:20250301T123000Z") != 4 {
+		t.Errorf("Expected 4 stamped events, got:\n%s", ics)
+	}
+
+	events := strings.Split(ics, "BEGIN:VEVENT")[1:]
+	for i, want := range []string{
+		"DTSTART;VALUE=DATE:20250303\r\nDTEND;VALUE=DATE:20250304",
+		"DTSTART;VALUE=DATE:20250303\r\nDTEND;VALUE=DATE:20250310",
+		"DTSTART;VALUE=DATE:20250303\r\nDTEND;VALUE=DATE:20250304",
+	} {
+		if !strings.Contains(events[i], want) {
+			t.Errorf("Expected event %d to contain %q, got:\n%s", i, want, events[i])
+		}
+	}
+	for _, want := range []string{
+		"UID:one@bd.beads",
+		`SUMMARY:a\, b\; c\\d`,
+		`DESCRIPTION:line one\nline two`,
+		`CATEGORIES:due,x\,y`,
+		"X-WR-CALNAME:bd issues",
+	} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("Expected %q in calendar:\n%s", want, ics)
+		}
+	}
+}
+
+func TestFoldICSLine(t *testing.T) {
+	if got := foldICSLine("SUMMARY:short"); got != "SUMMARY:short" {
+		t.Errorf("Expected a short line unchanged, got %q", got)
+	}
+
+	long := "SUMMARY:" + strings.Repeat("é", 100)
+	folded := foldICSLine(long)
+	lines := strings.Split(folded, "\r\n")
+	if len(lines) < 3 {
+		t.Fatalf("Expected a 208-octet line folded at least twice, got %q", folded)
+	}
+	var unfolded strings.Builder
+	for i, line := range lines {
+		if len(line) > 75 {
+			t.Errorf("Line %d is %d octets", i, len(line))
+		}
+		if i > 0 {
+			if !strings.HasPrefix(line, " ") {
+				t.Errorf("Expected continuation line %d to start with a space, got %q", i, line)
+			}
+			line = line[1:]
+		}
+		if !strings.HasPrefix(line, "SUMMARY") && !strings.HasPrefix(line, "é") {
+			t.Errorf("Line %d splits a UTF-8 sequence: %q", i, line)
+		}
+		unfolded.WriteString(line)
+	}
+	if unfolded.String() != long {
+		t.Errorf("Expected unfolding to restore the line, got %q", unfolded.String())
+	}
+}
+
+func TestCalendarSources(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	do := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Host = "beads.example.com"
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	saved := calendarSources
+	t.Cleanup(func() { calendarSources = saved })
+
+	// Every source's events land in one calendar, in date order, and each
+	// source sees the filter and base URL
+	var seen []calendarFilter
+	day := func(d int) time.Time { return time.Date(2025, 3, d, 0, 0, 0, 0, time.UTC) }
+	source := func(summaries map[int]string) calendarSource {
+		return func(_ context.Context, _ *Server, filter calendarFilter, base string) ([]calendarEvent, error) {
+			seen = append(seen, filter)
+			var events []calendarEvent
+			for d, summary := range summaries {
+				events = append(events, calendarEvent{UID: summary, Summary: summary, URL: base, Start: day(d)})
+			}
+			return events, nil
+		}
+	}
+	calendarSources = []calendarSource{
+		source(map[int]string{20: "Third"}),
+		source(map[int]string{5: "First", 12: "Second"}),
+	}
+
+	rec := do("/calendar.ics?label=backend&epic=bd-1")
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "text/calendar; charset=utf-8" {
+		t.Fatalf("Expected a 200 calendar, got %d (%s): %s", rec.Code, rec.Header().Get("Content-Type"), rec.Body)
+	}
+	ics := rec.Body.String()
+	first, second, third := strings.Index(ics, "SUMMARY:First"), strings.Index(ics, "SUMMARY:Second"), strings.Index(ics, "SUMMARY:Third")
+	if first < 0 || second < first || third < second {
+		t.Errorf("Expected events from both sources in date order, got:\n%s", ics)
+	}
+	if !strings.Contains(ics, "URL:http://beads.example.com") || !strings.Contains(ics, "X-WR-CALNAME:bd issues") {
+		t.Errorf("Expected the request's base URL and the prefix's calendar name, got:\n%s", ics)
+	}
+	want := calendarFilter{Label: "backend", Epic: "bd-1"}
+	if len(seen) != 2 || seen[0] != want || seen[1] != want {
+		t.Errorf("Expected both sources to get %+v, got %+v", want, seen)
+	}
+
+	// A failing source fails the whole feed rather than serving part of it
+	calendarSources = append(calendarSources, func(context.Context, *Server, calendarFilter, string) ([]calendarEvent, error) {
+		return nil, errors.New("source broke")
+	})
+	if rec := do("/calendar.ics"); rec.Code != http.StatusInternalServerError {
+		t.Errorf("Expected 500 when a source fails, got %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestCalendarDateBuckets(t *testing.T) {
+	// Well west of UTC, so a late evening is already tomorrow in UTC
+	saved := time.Local
+	time.Local = time.FixedZone("HST", -10*60*60)
+	t.Cleanup(func() { time.Local = saved })
+
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	do := func(path string) string {
+		req := httptest.NewRequest("GET", path, nil)
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected 200 for %s, got %d: %s", path, rec.Code, rec.Body)
+		}
+		return rec.Body.String()
+	}
+
+	due := time.Date(2026, 3, 14, 23, 30, 0, 0, time.Local)
+	issue := &types.Issue{Title: "Late evening", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, DueDate: &due}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AddLabel(ctx, issue.ID, "backend", "test"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.CreateMilestone(ctx, &sqlite.Milestone{
+		Name:      "sprint-1",
+		StartDate: time.Date(2026, 3, 16, 22, 0, 0, 0, time.Local),
+		EndDate:   time.Date(2026, 3, 20, 22, 0, 0, 0, time.Local),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Dates fall on the local day they were set for, not the UTC one
+	ics := do("/calendar.ics")
+	dueEvent := strings.Index(ics, "UID:due-"+issue.ID)
+	milestoneEvent := strings.Index(ics, "UID:milestone-sprint-1")
+	if dueEvent < 0 || milestoneEvent < dueEvent {
+		t.Fatalf("Expected the due date before the milestone, got:\n%s", ics)
+	}
+	for _, want := range []string{
+		"DTSTART;VALUE=DATE:20260314\r\nDTEND;VALUE=DATE:20260315",
+		"DTSTART;VALUE=DATE:20260316\r\nDTEND;VALUE=DATE:20260321",
+	} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("Expected %q in calendar:\n%s", want, ics)
+		}
+	}
+
+	// A label filter keeps matching due dates and leaves project-wide
+	// milestones out
+	ics = do("/calendar.ics?label=backend")
+	if !strings.Contains(ics, "UID:due-"+issue.ID) || strings.Contains(ics, "milestone-sprint-1") {
+		t.Errorf("Expected only the labeled due date, got:\n%s", ics)
+	}
+	if ics = do("/calendar.ics?label=frontend"); strings.Contains(ics, "BEGIN:VEVENT") {
+		t.Errorf("Expected no events for an unused label, got:\n%s", ics)
+	}
+}
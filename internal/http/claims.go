@@ -0,0 +1,167 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// claimRequest is the body of POST /issues/{id}/claim and POST /issues/ready/claim
+type claimRequest struct {
+	TTL string `json:"ttl,omitempty" doc:"Lease length as a Go duration, e.g. 15m; default 30m"`
+}
+
+// claimResult is a claimed issue and the lease on it
+type claimResult struct {
+	Issue *types.Issue  `json:"issue"`
+	Lease *sqlite.Lease `json:"lease"`
+}
+
+// parseTTL parses a lease TTL, defaulting to sqlite.DefaultLeaseTTL
+func parseTTL(value string) (time.Duration, error) {
+	if value == "" {
+		return sqlite.DefaultLeaseTTL, nil
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl <= 0 {
+		return 0, fmt.Errorf("invalid ttl %q: expected a positive duration such as 15m", value)
+	}
+	return ttl, nil
+}
+
+// claimErrorStatus maps lease errors to 409 and anything else to 500
+func claimErrorStatus(err error) int {
+	var conflict *sqlite.LeaseConflictError
+	if errors.As(err, &conflict) || errors.Is(err, sqlite.ErrNotClaimable) || errors.Is(err, sqlite.ErrLeaseNotHeld) {
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// handleClaimIssue handles POST /issues/{id}/claim, leasing the issue to the
+// caller. Claiming an issue the caller already holds renews the lease.
+func (s *Server) handleClaimIssue(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("claims require SQLite backend"))
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	issue, err := sqliteStore.GetIssue(ctx, id)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if issue == nil {
+		s.writeError(w, r, http.StatusNotFound, types.NotFound("issue", id))
+		return
+	}
+
+	var body claimRequest
+	if err := s.parseBody(r, &body); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	ttl, err := parseTTL(body.TTL)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	lease, err := sqliteStore.ClaimIssue(ctx, id, s.getActor(r), ttl)
+	if err != nil {
+		s.writeError(w, r, claimErrorStatus(err), err)
+		return
+	}
+	if issue, err = sqliteStore.GetIssue(ctx, id); err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.writeSuccess(w, r, &claimResult{Issue: issue, Lease: lease}, opClaim)
+}
+
+// handleReleaseClaim handles DELETE /issues/{id}/claim, ending the caller's
+// lease and returning the issue to the pool
+func (s *Server) handleReleaseClaim(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("claims require SQLite backend"))
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	if err := sqliteStore.ReleaseLease(r.Context(), id, s.getActor(r)); err != nil {
+		s.writeError(w, r, claimErrorStatus(err), err)
+		return
+	}
+
+	s.writeSuccess(w, r, map[string]string{"message": "released " + id}, "release")
+}
+
+// handleClaimNext handles POST /issues/ready/claim, claiming the next ready
+// issue that is unassigned or assigned to the caller. It takes the same
+// filters as GET /issues/ready, except status.
+func (s *Server) handleClaimNext(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("claims require SQLite backend"))
+		return
+	}
+
+	filter, err := workFilterFromQuery(r.URL.Query())
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	var body claimRequest
+	if err := s.parseBody(r, &body); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	ttl, err := parseTTL(body.TTL)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	issue, lease, err := sqliteStore.ClaimNext(ctx, s.getActor(r), ttl, filter)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if issue == nil {
+		s.writeError(w, r, http.StatusNotFound, fmt.Errorf("no ready work to claim"))
+		return
+	}
+
+	s.writeSuccess(w, r, &claimResult{Issue: issue, Lease: lease}, opClaim)
+}
+
+// handleListClaims handles GET /claims, listing live leases
+func (s *Server) handleListClaims(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("claims require SQLite backend"))
+		return
+	}
+
+	leases, err := sqliteStore.ListLeases(r.Context())
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if leases == nil {
+		leases = []*sqlite.Lease{}
+	}
+
+	s.writeSuccess(w, r, leases, opClaims)
+}
@@ -0,0 +1,104 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestClaims(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	do := func(method, path, body, actor string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("X-Actor", actor)
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for _, title := range []string{"Urgent", "Later"} {
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+		if title == "Later" {
+			issue.Priority = 3
+		}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rec := do("POST", "/issues/bd-2/claim", `{"ttl": "10m"}`, "agent-1")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var result claimResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Issue.Status != types.StatusInProgress || result.Issue.Assignee != "agent-1" || result.Lease.Holder != "agent-1" {
+		t.Errorf("Expected bd-2 in progress for agent-1, got %+v / %+v", result.Issue, result.Lease)
+	}
+
+	if rec := do("POST", "/issues/bd-2/claim", "", "agent-2"); rec.Code != http.StatusConflict {
+		t.Errorf("Expected 409 for a claimed issue, got %d: %s", rec.Code, rec.Body)
+	}
+	if rec := do("POST", "/issues/bd-2/claim", `{"ttl": "soon"}`, "agent-1"); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a bad ttl, got %d", rec.Code)
+	}
+	if rec := do("POST", "/issues/bd-99/claim", "", "agent-1"); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a missing issue, got %d", rec.Code)
+	}
+
+	// The next ready issue skips the one already claimed
+	rec = do("POST", "/issues/ready/claim", "", "agent-2")
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatalf("failed to decode %s: %v", rec.Body, err)
+	}
+	if result.Issue.ID != "bd-1" || result.Lease.Holder != "agent-2" {
+		t.Errorf("Expected agent-2 to claim bd-1, got %s", rec.Body)
+	}
+	if rec := do("POST", "/issues/ready/claim", "", "agent-3"); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 with nothing left to claim, got %d: %s", rec.Code, rec.Body)
+	}
+
+	rec = do("GET", "/claims", "", "agent-1")
+	var leases []*sqlite.Lease
+	if err := json.Unmarshal(rec.Body.Bytes(), &leases); err != nil {
+		t.Fatal(err)
+	}
+	if len(leases) != 2 {
+		t.Errorf("Expected 2 live claims, got %s", rec.Body)
+	}
+
+	if rec := do("DELETE", "/issues/bd-1/claim", "", "agent-1"); rec.Code != http.StatusConflict {
+		t.Errorf("Expected 409 releasing someone else's claim, got %d", rec.Code)
+	}
+	if rec := do("DELETE", "/issues/bd-1/claim", "", "agent-2"); rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	issue, _ := store.GetIssue(ctx, "bd-1")
+	if issue.Status != types.StatusOpen || issue.Assignee != "" {
+		t.Errorf("Expected released issue back in the pool, got %s for %q", issue.Status, issue.Assignee)
+	}
+}
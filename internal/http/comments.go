@@ -0,0 +1,260 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/imalsogreg/beads/internal/rpc"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// commentRequest is the body of POST /issues/{id}/comments
+type commentRequest struct {
+	Text    string `json:"text"`
+	ReplyTo *int64 `json:"reply_to,omitempty" doc:"ID of the comment on the same issue this one answers"`
+}
+
+// commentEditRequest is the body of PATCH /issues/{id}/comments/{cid}
+type commentEditRequest struct {
+	Text string `json:"text"`
+}
+
+// reactionRequest is the body of POST /issues/{id}/comments/{cid}/reactions
+type reactionRequest struct {
+	Reaction string `json:"reaction" doc:"An emoji or a short name like +1"`
+}
+
+func (s *Server) handleAddComment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	actor := s.getActor(r)
+	vars := mux.Vars(r)
+
+	var body commentRequest
+	if err := s.parseBody(r, &body); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if body.Text == "" {
+		s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("text is required"))
+		return
+	}
+	if !s.requireIssue(w, r, vars["id"]) {
+		return
+	}
+	if body.ReplyTo != nil {
+		if _, ok := s.getCommentOr404(w, r, vars["id"], *body.ReplyTo); !ok {
+			return
+		}
+	}
+
+	var comment *types.Comment
+	var err error
+	if body.ReplyTo != nil {
+		comment, err = s.storage.ReplyToComment(ctx, vars["id"], *body.ReplyTo, actor, body.Text)
+	} else {
+		comment, err = s.storage.AddIssueComment(ctx, vars["id"], actor, body.Text)
+	}
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.writeSuccess(w, r, comment, rpc.OpCommentAdd)
+}
+
+func (s *Server) handleListComments(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+
+	if !s.requireIssue(w, r, vars["id"]) {
+		return
+	}
+	comments, err := s.storage.GetIssueComments(ctx, vars["id"])
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if comments == nil {
+		comments = []*types.Comment{}
+	}
+
+	s.writeSuccess(w, r, comments, rpc.OpCommentList)
+}
+
+// handleEditComment handles PATCH /issues/{id}/comments/{cid}
+func (s *Server) handleEditComment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	actor := s.getActor(r)
+	vars := mux.Vars(r)
+
+	var body commentEditRequest
+	if err := s.parseBody(r, &body); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if body.Text == "" {
+		s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("text is required"))
+		return
+	}
+	comment, ok := s.commentFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	comment, err := s.storage.UpdateIssueComment(ctx, vars["id"], comment.ID, actor, body.Text)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.writeSuccess(w, r, comment, rpc.OpCommentAdd)
+}
+
+// handleDeleteComment handles DELETE /issues/{id}/comments/{cid}
+func (s *Server) handleDeleteComment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+
+	comment, ok := s.commentFromPath(w, r)
+	if !ok {
+		return
+	}
+	if err := s.storage.DeleteIssueComment(ctx, vars["id"], comment.ID); err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.writeSuccess(w, r, map[string]string{"message": "comment deleted"}, "comment_delete")
+}
+
+// handleCommentEdits handles GET /issues/{id}/comments/{cid}/edits
+func (s *Server) handleCommentEdits(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+
+	comment, ok := s.commentFromPath(w, r)
+	if !ok {
+		return
+	}
+	edits, err := s.storage.GetCommentEdits(ctx, vars["id"], comment.ID)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.writeSuccess(w, r, edits, opCommentEdits)
+}
+
+// handleAddReaction handles POST /issues/{id}/comments/{cid}/reactions
+func (s *Server) handleAddReaction(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	actor := s.getActor(r)
+	vars := mux.Vars(r)
+
+	var body reactionRequest
+	if err := s.parseBody(r, &body); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if err := types.ValidateReaction(body.Reaction); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	comment, ok := s.commentFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	comment, err := s.storage.AddCommentReaction(ctx, vars["id"], comment.ID, actor, body.Reaction)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.writeSuccess(w, r, comment, rpc.OpCommentAdd)
+}
+
+// handleRemoveReaction handles DELETE /issues/{id}/comments/{cid}/reactions/{reaction}
+func (s *Server) handleRemoveReaction(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	actor := s.getActor(r)
+	vars := mux.Vars(r)
+
+	comment, ok := s.commentFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	comment, err := s.storage.RemoveCommentReaction(ctx, vars["id"], comment.ID, actor, vars["reaction"])
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.writeSuccess(w, r, comment, rpc.OpCommentAdd)
+}
+
+// handleMentions handles GET /mentions: the comments that @mention ?user=,
+// or the caller
+func (s *Server) handleMentions(w http.ResponseWriter, r *http.Request) {
+	user := r.URL.Query().Get("user")
+	if user == "" {
+		user = s.getActor(r)
+	}
+
+	mentions, err := s.storage.GetMentions(r.Context(), user)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.writeSuccess(w, r, mentions, opMentions)
+}
+
+// commentFromPath returns the comment {cid} on issue {id}, or writes a 400
+// or 404
+func (s *Server) commentFromPath(w http.ResponseWriter, r *http.Request) (*types.Comment, bool) {
+	vars := mux.Vars(r)
+	commentID, err := strconv.ParseInt(vars["cid"], 10, 64)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("invalid comment ID %q", vars["cid"]))
+		return nil, false
+	}
+	return s.getCommentOr404(w, r, vars["id"], commentID)
+}
+
+// getCommentOr404 returns an issue's comment, or writes a 404 if the issue
+// or comment doesn't exist
+func (s *Server) getCommentOr404(w http.ResponseWriter, r *http.Request, issueID string, commentID int64) (*types.Comment, bool) {
+	comments, err := s.storage.GetIssueComments(r.Context(), issueID)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return nil, false
+	}
+	for _, c := range comments {
+		if c.ID == commentID {
+			return c, true
+		}
+	}
+	if !s.requireIssue(w, r, issueID) {
+		return nil, false
+	}
+	s.writeError(w, r, http.StatusNotFound, fmt.Errorf("comment %d not found on %s", commentID, issueID))
+	return nil, false
+}
+
+// requireIssue reports whether an issue exists, writing a 404 if it doesn't
+func (s *Server) requireIssue(w http.ResponseWriter, r *http.Request, issueID string) bool {
+	issue, err := s.storage.GetIssue(r.Context(), issueID)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return false
+	}
+	if issue == nil {
+		s.writeError(w, r, http.StatusNotFound, types.NotFound("issue", issueID))
+		return false
+	}
+	return true
+}
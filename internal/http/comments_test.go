@@ -0,0 +1,171 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestCommentThreadsAPI(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	do := func(method, path, actor, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Actor", actor)
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+	decode := func(rec *httptest.ResponseRecorder, v interface{}) {
+		t.Helper()
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+			t.Fatalf("Failed to decode %s: %v", rec.Body, err)
+		}
+	}
+
+	issue := &types.Issue{Title: "Discuss", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatal(err)
+	}
+	base := "/issues/" + issue.ID + "/comments"
+
+	var first, reply types.Comment
+	decode(do("POST", base, "alice", `{"text":"Ship it?"}`), &first)
+	if first.ID == 0 || first.Author != "alice" {
+		t.Fatalf("Expected the new comment back, got %+v", first)
+	}
+	decode(do("POST", base, "bob", fmt.Sprintf(`{"text":"Not yet","reply_to":%d}`, first.ID)), &reply)
+	if reply.ReplyTo == nil || *reply.ReplyTo != first.ID {
+		t.Errorf("Expected a reply to #%d, got %+v", first.ID, reply)
+	}
+	if rec := do("POST", base, "bob", `{"text":"Lost","reply_to":999}`); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 replying to a missing comment, got %d", rec.Code)
+	}
+
+	var edited types.Comment
+	decode(do("PATCH", fmt.Sprintf("%s/%d", base, first.ID), "carol", `{"text":"Ship it today?"}`), &edited)
+	if edited.Text != "Ship it today?" || edited.UpdatedAt == nil {
+		t.Errorf("Expected the edited comment, got %+v", edited)
+	}
+	var edits []*types.CommentEdit
+	decode(do("GET", fmt.Sprintf("%s/%d/edits", base, first.ID), "", ""), &edits)
+	if len(edits) != 1 || edits[0].Text != "Ship it?" || edits[0].EditedBy != "carol" {
+		t.Errorf("Expected one edit by carol, got %+v", edits)
+	}
+
+	var reacted types.Comment
+	decode(do("POST", fmt.Sprintf("%s/%d/reactions", base, first.ID), "bob", `{"reaction":"+1"}`), &reacted)
+	decode(do("POST", fmt.Sprintf("%s/%d/reactions", base, first.ID), "bob", `{"reaction":"+1"}`), &reacted)
+	if reacted.Reactions["+1"] != 1 {
+		t.Errorf("Expected one +1, got %v", reacted.Reactions)
+	}
+	var unreacted types.Comment
+	decode(do("DELETE", fmt.Sprintf("%s/%d/reactions/+1", base, first.ID), "bob", ""), &unreacted)
+	if len(unreacted.Reactions) != 0 {
+		t.Errorf("Expected no reactions, got %v", unreacted.Reactions)
+	}
+
+	req := httptest.NewRequest("GET", base, nil)
+	req.Header.Set("Accept", "text/plain")
+	rec := httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, req)
+	text := rec.Body.String()
+	if !strings.Contains(text, "(edited)") || !strings.Contains(text, "\n   #"+fmt.Sprint(reply.ID)) {
+		t.Errorf("Expected the text listing to thread the reply and mark the edit, got:\n%s", text)
+	}
+
+	if rec := do("DELETE", fmt.Sprintf("%s/%d", base, first.ID), "alice", ""); rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var comments []*types.Comment
+	decode(do("GET", base, "", ""), &comments)
+	if len(comments) != 1 || comments[0].ID != reply.ID || comments[0].ReplyTo != nil {
+		t.Errorf("Expected only the reply left, now at the top, got %+v", comments)
+	}
+	if rec := do("PATCH", fmt.Sprintf("%s/%d", base, first.ID), "alice", `{"text":"x"}`); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 editing a deleted comment, got %d", rec.Code)
+	}
+	if rec := do("PATCH", base+"/abc", "alice", `{"text":"x"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a malformed comment ID, got %d", rec.Code)
+	}
+}
+
+func TestMentionsAPI(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	do := func(path, actor, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Accept", accept)
+		req.Header.Set("X-Actor", actor)
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	issue := &types.Issue{Title: "Discuss", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.AddIssueComment(ctx, issue.ID, "alice", "@bob thoughts?"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The caller's own mentions by default
+	rec := do("/mentions", "bob", "application/json")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var mentions []*types.Mention
+	if err := json.Unmarshal(rec.Body.Bytes(), &mentions); err != nil {
+		t.Fatal(err)
+	}
+	if len(mentions) != 1 || mentions[0].User != "bob" || mentions[0].IssueID != issue.ID || mentions[0].Author != "alice" {
+		t.Errorf("Expected bob's mention on %s, got %+v", issue.ID, mentions)
+	}
+
+	if rec := do("/mentions?user=carol", "bob", "application/json"); strings.TrimSpace(rec.Body.String()) != "[]" {
+		t.Errorf("Expected no mentions of carol, got %s", rec.Body)
+	}
+	if text := do("/mentions?user=bob", "", "text/plain").Body.String(); !strings.Contains(text, "Mentions of @bob (1)") || !strings.Contains(text, "@bob thoughts?") {
+		t.Errorf("Unexpected text listing:\n%s", text)
+	}
+}
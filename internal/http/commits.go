@@ -0,0 +1,101 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/imalsogreg/beads/internal/git"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+)
+
+// commitRequest is the body of POST /issues/{id}/commits
+type commitRequest struct {
+	SHA       string    `json:"sha"`
+	Message   string    `json:"message" doc:"Closes the issue if it says so, e.g. \"fixes bd-123\""`
+	Author    string    `json:"author,omitempty"`
+	URL       string    `json:"url,omitempty" doc:"Link to the commit, e.g. on the code host"`
+	Timestamp time.Time `json:"timestamp,omitempty" doc:"Commit time; RFC 3339"`
+}
+
+// handleListCommits handles GET /issues/{id}/commits
+func (s *Server) handleListCommits(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("commit links require SQLite backend"))
+		return
+	}
+
+	links, err := sqliteStore.GetCommitLinks(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if links == nil {
+		links = []*sqlite.CommitLink{}
+	}
+
+	s.writeSuccess(w, r, links, opCommits)
+}
+
+// handleLinkCommit handles POST /issues/{id}/commits, which lets CI report a
+// commit that mentions an issue. The issue is closed if the message fixes it
+// and git.auto_close isn't turned off.
+func (s *Server) handleLinkCommit(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("commit links require SQLite backend"))
+		return
+	}
+
+	var body commitRequest
+	if err := s.parseBody(r, &body); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	body.SHA = strings.TrimSpace(body.SHA)
+	if body.SHA == "" {
+		s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("sha is required"))
+		return
+	}
+
+	issue, err := sqliteStore.GetIssue(ctx, mux.Vars(r)["id"])
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if issue == nil {
+		s.writeError(w, r, http.StatusNotFound, fmt.Errorf("issue not found"))
+		return
+	}
+
+	opts := git.LinkOptions{Actor: s.getActor(r)}
+	value, _ := sqliteStore.GetConfig(ctx, git.AutoCloseConfigKey)
+	opts.AutoClose = git.AutoCloseEnabled(value)
+
+	closeIssue := false
+	if opts.AutoClose {
+		prefix, _ := sqliteStore.GetConfig(ctx, "issue_prefix")
+		for _, ref := range git.ParseRefs(body.Message, prefix) {
+			closeIssue = closeIssue || (ref.IssueID == issue.ID && ref.Closes)
+		}
+	}
+
+	commit := &git.Commit{
+		SHA:       body.SHA,
+		Author:    body.Author,
+		Message:   strings.TrimSpace(body.Message),
+		Timestamp: body.Timestamp,
+		URL:       body.URL,
+	}
+	result, err := git.LinkIssue(ctx, sqliteStore, issue, commit, closeIssue, opts)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.writeSuccess(w, r, result, opCommitLink)
+}
@@ -0,0 +1,172 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/compact"
+	"github.com/imalsogreg/beads/internal/rpc"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// handleCompact handles POST /compact. Without issue_id every candidate for
+// the tier is compacted and expired trash is purged.
+func (s *Server) handleCompact(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("compact requires SQLite backend"))
+		return
+	}
+
+	var args rpc.CompactArgs
+	if err := s.parseBody(r, &args); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if r.URL.Query().Get("dry_run") == "true" {
+		args.DryRun = true
+	}
+	if args.Tier == 0 {
+		args.Tier = 1
+	}
+	if args.Tier != 1 && args.Tier != 2 {
+		s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("invalid tier: %d (must be 1 or 2)", args.Tier))
+		return
+	}
+
+	apiKey := args.APIKey
+	if apiKey == "" {
+		apiKey = os.Getenv("ANTHROPIC_API_KEY")
+	}
+	compactor, err := compact.New(sqliteStore, apiKey, &compact.Config{
+		APIKey:      apiKey,
+		Concurrency: args.Workers,
+		DryRun:      args.DryRun,
+	})
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if args.Tier == 1 && !args.DryRun && !compactor.CanSummarize() {
+		s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("tier 1 compaction requires ANTHROPIC_API_KEY on the server or api_key in the request"))
+		return
+	}
+
+	start := time.Now()
+	resp := rpc.CompactResponse{Success: true, IssueID: args.IssueID, DryRun: args.DryRun}
+
+	resp.Results = []rpc.CompactResult{}
+	compactIDs := func(ids []string) error {
+		var results []*compact.Result
+		var err error
+		if args.Tier == 2 {
+			results, err = compactor.CompactTier2Batch(ctx, ids)
+		} else {
+			results, err = compactor.CompactTier1Batch(ctx, ids)
+		}
+		if err != nil {
+			return err
+		}
+		for _, res := range results {
+			result := rpc.CompactResult{
+				IssueID:       res.IssueID,
+				Success:       res.Err == nil,
+				OriginalSize:  res.OriginalSize,
+				CompactedSize: res.CompactedSize,
+			}
+			if res.Err != nil {
+				result.Error = res.Err.Error()
+			} else if res.OriginalSize > 0 && res.CompactedSize > 0 {
+				result.Reduction = fmt.Sprintf("%.1f%%", float64(res.OriginalSize-res.CompactedSize)/float64(res.OriginalSize)*100)
+			}
+			resp.Results = append(resp.Results, result)
+		}
+		return nil
+	}
+
+	if args.IssueID != "" {
+		issue, err := s.storage.GetIssue(ctx, args.IssueID)
+		if err != nil {
+			s.writeStoreError(w, r, err)
+			return
+		}
+		if issue == nil {
+			s.writeError(w, r, http.StatusNotFound, types.NotFound("issue", args.IssueID))
+			return
+		}
+		err = compactIDs([]string{issue.ID})
+	} else {
+		resp.Purged, err = compact.PurgeExpiredTrash(ctx, sqliteStore, args.DryRun)
+		if err != nil {
+			s.writeError(w, r, http.StatusInternalServerError, fmt.Errorf("failed to purge trash: %w", err))
+			return
+		}
+
+		// Candidates a page at a time, so a large backlog isn't loaded whole
+		err = sqliteStore.ForEachCandidatePage(ctx, args.Tier, func(page []*sqlite.CompactionCandidate) error {
+			return compactIDs(compact.CandidateIDs(page))
+		})
+	}
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	resp.Duration = time.Since(start).String()
+
+	s.writeSuccess(w, r, resp, rpc.OpCompact)
+}
+
+// handleCompactStats handles GET /compact/stats
+func (s *Server) handleCompactStats(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("compact requires SQLite backend"))
+		return
+	}
+
+	// Same estimates as bd compact --stats: 70% at Tier 1, 95% at Tier 2
+	var counts [3]int
+	savings := 0
+	for tier, pct := range map[int]int{1: 70, 2: 95} {
+		err := sqliteStore.ForEachCandidatePage(ctx, tier, func(page []*sqlite.CompactionCandidate) error {
+			counts[tier] += len(page)
+			for _, c := range page {
+				savings += c.OriginalSize * pct / 100
+			}
+			return nil
+		})
+		if err != nil {
+			s.writeStoreError(w, r, err)
+			return
+		}
+	}
+	stats, err := s.storage.GetStatistics(ctx)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	data := rpc.CompactStatsData{
+		Tier1Candidates:  counts[1],
+		Tier2Candidates:  counts[2],
+		TotalClosed:      stats.ClosedIssues,
+		Tier1MinAge:      s.configDays(r, "compact_tier1_days", "30"),
+		Tier2MinAge:      s.configDays(r, "compact_tier2_days", "90"),
+		EstimatedSavings: fmt.Sprintf("%d bytes", savings),
+	}
+	s.writeSuccess(w, r, data, rpc.OpCompactStats)
+}
+
+// configDays formats a day-count config value, falling back to def
+func (s *Server) configDays(r *http.Request, key, def string) string {
+	days, _ := s.storage.GetConfig(r.Context(), key)
+	if days == "" {
+		days = def
+	}
+	return days + " days"
+}
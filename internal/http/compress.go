@@ -0,0 +1,211 @@
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// defaultCompressionMinSize is the smallest response compressed by default;
+// below about a kilobyte the savings don't pay for the work
+const defaultCompressionMinSize = 1024
+
+// CompressionConfig controls response compression
+type CompressionConfig struct {
+	MinSize int // Responses smaller than this many bytes go out as is (default 1024)
+}
+
+// compressionEncodings are the encodings the server speaks, most preferred first
+var compressionEncodings = []string{"zstd", "gzip"}
+
+var (
+	gzipWriters = sync.Pool{New: func() interface{} { return gzip.NewWriter(io.Discard) }}
+	zstdWriters = sync.Pool{New: func() interface{} {
+		w, _ := zstd.NewWriter(io.Discard, zstd.WithEncoderConcurrency(1))
+		return w
+	}}
+)
+
+// EnableCompression compresses responses with zstd or gzip for clients
+// that accept them. Call before Start.
+func (s *Server) EnableCompression(config CompressionConfig) {
+	if config.MinSize <= 0 {
+		config.MinSize = defaultCompressionMinSize
+	}
+	s.httpServer.Handler = compressHandler(config, s.httpServer.Handler)
+}
+
+// compressHandler wraps the whole server rather than being router
+// middleware, so responses replayed for idempotent retries and CORS
+// headers are handled the same as the rest
+func compressHandler(config CompressionConfig, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// WebSocket upgrades take over the connection
+		if r.Header.Get("Upgrade") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Add("Vary", "Accept-Encoding")
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressWriter{ResponseWriter: w, encoding: encoding, minSize: config.MinSize, status: http.StatusOK}
+		defer cw.Close()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// negotiateEncoding picks the preferred encoding an Accept-Encoding header
+// allows, or "" for none
+func negotiateEncoding(header string) string {
+	accepted := make(map[string]bool)
+	wildcard := false
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		ok := true
+		if q, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				ok = false
+			}
+		}
+		if name == "*" {
+			wildcard = ok
+		} else if name != "" {
+			accepted[name] = ok
+		}
+	}
+	for _, encoding := range compressionEncodings {
+		if ok, listed := accepted[encoding]; ok || (!listed && wildcard) {
+			return encoding
+		}
+	}
+	return ""
+}
+
+// compressWriter holds back the start of a response until it knows whether
+// the response is big enough to compress
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	minSize  int
+
+	status      int
+	buf         bytes.Buffer
+	started     bool           // headers sent
+	encoder     io.WriteCloser // nil if sent as is
+	wroteHeader bool
+}
+
+func (c *compressWriter) WriteHeader(status int) {
+	if !c.wroteHeader {
+		c.status, c.wroteHeader = status, true
+	}
+}
+
+func (c *compressWriter) Write(p []byte) (int, error) {
+	if c.started {
+		if c.encoder != nil {
+			return c.encoder.Write(p)
+		}
+		return c.ResponseWriter.Write(p)
+	}
+	c.buf.Write(p)
+	if c.buf.Len() >= c.minSize {
+		if err := c.start(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// start sends the headers and whatever is buffered, compressing from here
+// on if compress is set and the response allows it
+func (c *compressWriter) start(compress bool) error {
+	c.started = true
+	h := c.Header()
+	if compress && h.Get("Content-Encoding") == "" && c.status != http.StatusNoContent && c.status != http.StatusNotModified {
+		h.Set("Content-Encoding", c.encoding)
+		h.Del("Content-Length")
+		// The compressed bytes differ, so a strong validator no longer holds
+		if etag := h.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			h.Set("ETag", "W/"+etag)
+		}
+		c.encoder = c.newEncoder()
+	}
+	c.ResponseWriter.WriteHeader(c.status)
+
+	data := c.buf.Bytes()
+	c.buf = bytes.Buffer{}
+	if len(data) == 0 {
+		return nil
+	}
+	var err error
+	if c.encoder != nil {
+		_, err = c.encoder.Write(data)
+	} else {
+		_, err = c.ResponseWriter.Write(data)
+	}
+	return err
+}
+
+func (c *compressWriter) newEncoder() io.WriteCloser {
+	if c.encoding == "zstd" {
+		zw := zstdWriters.Get().(*zstd.Encoder)
+		zw.Reset(c.ResponseWriter)
+		return zw
+	}
+	gw := gzipWriters.Get().(*gzip.Writer)
+	gw.Reset(c.ResponseWriter)
+	return gw
+}
+
+// Flush sends what's been written so far, for streamed responses. A
+// response flushed before reaching the minimum size goes out uncompressed.
+func (c *compressWriter) Flush() {
+	if !c.started {
+		if err := c.start(false); err != nil {
+			return
+		}
+	}
+	if flusher, ok := c.encoder.(interface{ Flush() error }); ok {
+		if err := flusher.Flush(); err != nil {
+			return
+		}
+	}
+	_ = http.NewResponseController(c.ResponseWriter).Flush()
+}
+
+// Unwrap lets streamed responses set write deadlines
+func (c *compressWriter) Unwrap() http.ResponseWriter {
+	return c.ResponseWriter
+}
+
+// Close sends a response too small to compress, or finishes a compressed one
+func (c *compressWriter) Close() {
+	if !c.started {
+		_ = c.start(false)
+		return
+	}
+	if c.encoder == nil {
+		return
+	}
+	_ = c.encoder.Close()
+	switch encoder := c.encoder.(type) {
+	case *zstd.Encoder:
+		encoder.Reset(io.Discard)
+		zstdWriters.Put(encoder)
+	case *gzip.Writer:
+		encoder.Reset(io.Discard)
+		gzipWriters.Put(encoder)
+	}
+}
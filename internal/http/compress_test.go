@@ -0,0 +1,119 @@
+package http
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		header, want string
+	}{
+		{"", ""},
+		{"gzip", "gzip"},
+		{"gzip, deflate, br, zstd", "zstd"},
+		{"zstd;q=0, gzip;q=0.5", "gzip"},
+		{"*", "zstd"},
+		{"*, zstd;q=0", "gzip"},
+		{"identity", ""},
+		{"GZIP", "gzip"},
+	}
+	for _, tt := range tests {
+		if got := negotiateEncoding(tt.header); got != tt.want {
+			t.Errorf("negotiateEncoding(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestCompression(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.EnableCompression(CompressionConfig{MinSize: 512})
+	get := func(path, acceptEncoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+		rec := httptest.NewRecorder()
+		srv.httpServer.Handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i := 0; i < 20; i++ {
+		issue := &types.Issue{Title: fmt.Sprintf("Issue %d", i), Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	plain := get("/issues", "")
+	if plain.Header().Get("Content-Encoding") != "" || !strings.Contains(plain.Header().Get("Vary"), "Accept-Encoding") {
+		t.Fatalf("Expected an uncompressed response varying on Accept-Encoding, got %v", plain.Header())
+	}
+
+	decoders := map[string]func(io.Reader) (io.Reader, error){
+		"gzip": func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+		"zstd": func(r io.Reader) (io.Reader, error) { return zstd.NewReader(r) },
+	}
+	for encoding, decode := range decoders {
+		rec := get("/issues", encoding)
+		if got := rec.Header().Get("Content-Encoding"); got != encoding {
+			t.Errorf("Content-Encoding = %q, want %s", got, encoding)
+			continue
+		}
+		if rec.Body.Len() >= plain.Body.Len() {
+			t.Errorf("%s: %d bytes is no smaller than %d", encoding, rec.Body.Len(), plain.Body.Len())
+		}
+		if etag := rec.Header().Get("ETag"); !strings.HasPrefix(etag, "W/") {
+			t.Errorf("%s: expected a weak ETag, got %q", encoding, etag)
+		}
+		r, err := decode(rec.Body)
+		if err != nil {
+			t.Fatalf("%s: %v", encoding, err)
+		}
+		var issues []*types.Issue
+		if err := json.NewDecoder(r).Decode(&issues); err != nil || len(issues) != 20 {
+			t.Errorf("%s: decoded %d issues, err %v", encoding, len(issues), err)
+		}
+	}
+
+	// Small responses aren't worth it
+	if rec := get("/issues?limit=1", "gzip"); rec.Header().Get("Content-Encoding") != "" || rec.Code != http.StatusOK {
+		t.Errorf("Expected a small response to go out as is, got %d %v", rec.Code, rec.Header())
+	}
+
+	// Revalidating with the weak ETag still works
+	etag := get("/issues", "gzip").Header().Get("ETag")
+	req := httptest.NewRequest("GET", "/issues", nil)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("If-None-Match", etag)
+	rec := httptest.NewRecorder()
+	srv.httpServer.Handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 || rec.Header().Get("Content-Encoding") != "" {
+		t.Errorf("Expected a bare 304, got %d %v", rec.Code, rec.Header())
+	}
+}
@@ -0,0 +1,76 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// versionETag is the entity tag for an issue version
+func versionETag(version int) string {
+	return fmt.Sprintf("%q", strconv.Itoa(version))
+}
+
+// setIssueETag sets the ETag header for issue, if it has a version
+func setIssueETag(w http.ResponseWriter, issue *types.Issue) {
+	if issue != nil && issue.Version > 0 {
+		w.Header().Set("ETag", versionETag(issue.Version))
+	}
+}
+
+// expectedVersion returns the version an update is conditional on, from the
+// If-Match header or an expected_version field in the body (which is removed
+// from updates). It returns nil when the update is unconditional.
+func expectedVersion(r *http.Request, updates map[string]interface{}) (*int, error) {
+	if raw, ok := updates["expected_version"]; ok {
+		delete(updates, "expected_version")
+		n, ok := raw.(float64)
+		if !ok || n < 0 || n != float64(int(n)) {
+			return nil, fmt.Errorf("expected_version must be a whole number")
+		}
+		version := int(n)
+		return &version, nil
+	}
+
+	match := strings.TrimSpace(r.Header.Get("If-Match"))
+	if match == "" || match == "*" {
+		return nil, nil
+	}
+	tag := strings.Trim(strings.TrimPrefix(match, "W/"), `"`)
+	version, err := strconv.Atoi(tag)
+	if err != nil || version < 0 {
+		return nil, fmt.Errorf("invalid If-Match %q: expected an issue version such as \"3\"", match)
+	}
+	return &version, nil
+}
+
+// writeVersionConflict writes a 409 carrying the issue's current state so
+// the client can merge and retry
+func (s *Server) writeVersionConflict(w http.ResponseWriter, r *http.Request, conflict *sqlite.VersionConflictError) {
+	issue, err := s.storage.GetIssue(r.Context(), conflict.IssueID)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	setIssueETag(w, issue)
+
+	if s.wantsJSON(r) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		body := newErrorBody(http.StatusConflict, conflict)
+		json.NewEncoder(w).Encode(struct {
+			errorBody
+			CurrentVersion int          `json:"current_version"`
+			Issue          *types.Issue `json:"issue"`
+		}{body, conflict.Current, issue})
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusConflict)
+	fmt.Fprintf(w, "Error: %s\nFetch the issue again and retry with If-Match: %s\n", conflict.Error(), versionETag(conflict.Current))
+}
@@ -0,0 +1,107 @@
+package http
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// cacheControl lets clients keep a response but makes them revalidate it
+// each time, which conditional requests make cheap
+const cacheControl = "private, no-cache"
+
+// issueETag is the entity tag for an issue: its version, as for If-Match, or
+// with backends that don't track versions a weak tag from its update time
+func issueETag(issue *types.Issue) string {
+	if issue.Version > 0 {
+		return versionETag(issue.Version)
+	}
+	return fmt.Sprintf(`W/"%d"`, issue.UpdatedAt.UnixNano())
+}
+
+// notModified sets the validator and caching headers for a response and,
+// if the request's If-None-Match or If-Modified-Since shows the client
+// already has it, writes 304 Not Modified and returns true. modified may be
+// zero when the response has no modification time.
+func notModified(w http.ResponseWriter, r *http.Request, etag string, modified time.Time) bool {
+	h := w.Header()
+	h.Set("ETag", etag)
+	if !modified.IsZero() {
+		h.Set("Last-Modified", modified.UTC().Format(http.TimeFormat))
+	}
+	h.Set("Cache-Control", cacheControl)
+	h.Add("Vary", "Accept")
+
+	// If-Modified-Since only counts without If-None-Match (RFC 9110)
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		if !etagMatches(match, etag) {
+			return false
+		}
+	} else {
+		since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+		if err != nil || modified.IsZero() || modified.Truncate(time.Second).After(since) {
+			return false
+		}
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// etagMatches reports whether an If-None-Match header lists etag, comparing
+// weakly as RFC 9110 requires for GET
+func etagMatches(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// bufferedResponse holds a response back so its body can be hashed
+type bufferedResponse struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	b.status = status
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	return b.body.Write(p)
+}
+
+// withETag tags successful responses of a listing with a hash of the body
+// and answers conditional requests for an unchanged listing with 304. That
+// saves sending the listing again, though it is still read from storage.
+// Streamed (NDJSON) listings go out as they are read, untagged.
+func withETag(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if wantsNDJSON(r) {
+			next(w, r)
+			return
+		}
+		buffered := &bufferedResponse{ResponseWriter: w, status: http.StatusOK}
+		next(buffered, r)
+
+		if buffered.status == http.StatusOK {
+			sum := sha256.Sum256(buffered.body.Bytes())
+			if notModified(w, r, `"`+hex.EncodeToString(sum[:16])+`"`, time.Time{}) {
+				return
+			}
+		}
+		w.WriteHeader(buffered.status)
+		_, _ = w.Write(buffered.body.Bytes())
+	}
+}
@@ -0,0 +1,94 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestConditionalGet(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	get := func(path string, header ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Accept", "application/json")
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	issue := &types.Issue{Title: "Polled", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := get("/issues/" + issue.ID)
+	etag := rec.Header().Get("ETag")
+	if rec.Code != http.StatusOK || etag == "" || rec.Header().Get("Last-Modified") == "" {
+		t.Fatalf("Expected 200 with validators, got %d %v", rec.Code, rec.Header())
+	}
+	if got := rec.Header().Get("Cache-Control"); got != cacheControl {
+		t.Errorf("Cache-Control = %q", got)
+	}
+	if rec := get("/issues/"+issue.ID, "If-None-Match", etag); rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Errorf("Expected an empty 304 for a matching ETag, got %d: %s", rec.Code, rec.Body)
+	}
+	if rec := get("/issues/"+issue.ID, "If-None-Match", `"other", W/`+etag); rec.Code != http.StatusNotModified {
+		t.Errorf("Expected 304 for a weak match in a list, got %d", rec.Code)
+	}
+	future := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+	if rec := get("/issues/"+issue.ID, "If-Modified-Since", future); rec.Code != http.StatusNotModified {
+		t.Errorf("Expected 304 for If-Modified-Since after the update, got %d", rec.Code)
+	}
+	past := time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)
+	if rec := get("/issues/"+issue.ID, "If-Modified-Since", past); rec.Code != http.StatusOK {
+		t.Errorf("Expected 200 for If-Modified-Since before the update, got %d", rec.Code)
+	}
+
+	list := get("/issues")
+	listETag := list.Header().Get("ETag")
+	if list.Code != http.StatusOK || listETag == "" {
+		t.Fatalf("Expected 200 with an ETag for the listing, got %d %v", list.Code, list.Header())
+	}
+	if rec := get("/issues", "If-None-Match", listETag); rec.Code != http.StatusNotModified {
+		t.Errorf("Expected 304 for an unchanged listing, got %d", rec.Code)
+	}
+
+	// A change invalidates both
+	if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"title": "Polled again"}, "test"); err != nil {
+		t.Fatal(err)
+	}
+	if rec := get("/issues/"+issue.ID, "If-None-Match", etag); rec.Code != http.StatusOK || rec.Header().Get("ETag") == etag {
+		t.Errorf("Expected 200 with a new ETag after an update, got %d %q", rec.Code, rec.Header().Get("ETag"))
+	}
+	if rec := get("/issues", "If-None-Match", listETag); rec.Code != http.StatusOK {
+		t.Errorf("Expected 200 for a changed listing, got %d", rec.Code)
+	}
+
+	// Errors aren't tagged
+	if rec := get("/issues?status=done"); rec.Code != http.StatusBadRequest || rec.Header().Get("ETag") != "" {
+		t.Errorf("Expected an untagged 400, got %d %q", rec.Code, rec.Header().Get("ETag"))
+	}
+}
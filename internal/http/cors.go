@@ -0,0 +1,100 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSConfig lets browser apps on other origins call the API
+type CORSConfig struct {
+	AllowedOrigins []string // Exact origins such as https://dash.example.com, or "*" for any
+	AllowedMethods []string // Default: GET, POST, PUT, PATCH, DELETE
+	AllowedHeaders []string // Default: the request headers the API understands
+}
+
+var (
+	defaultCORSMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
+	defaultCORSHeaders = []string{"Authorization", "Content-Type", "Accept", "X-Actor", "X-Session", "If-Match", "If-None-Match", "If-Modified-Since", "Idempotency-Key", "API-Version"}
+
+	// corsExposedHeaders are response headers browser code may read
+	corsExposedHeaders = "ETag, Last-Modified, Retry-After, Idempotent-Replayed, API-Version, Deprecation, Sunset, Link"
+)
+
+// corsMaxAge is how long browsers may cache a preflight response
+const corsMaxAge = 10 * time.Minute
+
+// EnableCORS answers CORS preflights and adds CORS headers for the allowed
+// origins. Call before Start.
+func (s *Server) EnableCORS(config CORSConfig) {
+	if len(config.AllowedMethods) == 0 {
+		config.AllowedMethods = defaultCORSMethods
+	}
+	if len(config.AllowedHeaders) == 0 {
+		config.AllowedHeaders = defaultCORSHeaders
+	}
+	s.httpServer.Handler = corsHandler(config, s.httpServer.Handler)
+}
+
+// corsHandler wraps the router rather than being router middleware, because
+// preflight OPTIONS requests match no route and carry no credentials
+func corsHandler(config CORSConfig, next http.Handler) http.Handler {
+	methods := strings.Join(config.AllowedMethods, ", ")
+	headers := strings.Join(config.AllowedHeaders, ", ")
+	maxAge := strconv.Itoa(int(corsMaxAge.Seconds()))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		allowed := corsAllowedOrigin(config.AllowedOrigins, origin)
+		w.Header().Add("Vary", "Origin")
+		if allowed == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", allowed)
+		requested := r.Header.Get("Access-Control-Request-Method")
+		if r.Method != http.MethodOptions || requested == "" {
+			w.Header().Set("Access-Control-Expose-Headers", corsExposedHeaders)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Preflight: the browser asks before sending the real request
+		if !containsFold(config.AllowedMethods, requested) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Methods", methods)
+		w.Header().Set("Access-Control-Allow-Headers", headers)
+		w.Header().Set("Access-Control-Max-Age", maxAge)
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// corsAllowedOrigin returns the Access-Control-Allow-Origin value for
+// origin, or "" if it isn't allowed
+func corsAllowedOrigin(allowed []string, origin string) string {
+	if origin == "" {
+		return ""
+	}
+	for _, a := range allowed {
+		if a == "*" {
+			return "*"
+		}
+		if strings.EqualFold(strings.TrimSuffix(a, "/"), origin) {
+			return origin
+		}
+	}
+	return ""
+}
+
+func containsFold(values []string, want string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, want) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+)
+
+func TestCORS(t *testing.T) {
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	t.Setenv("BEADS_API_SECRET", "sekret")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.EnableCORS(CORSConfig{AllowedOrigins: []string{"https://dash.example.com"}})
+	do := func(method, origin, requestMethod string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/issues/bd-1", nil)
+		req.Header.Set("Origin", origin)
+		if requestMethod != "" {
+			req.Header.Set("Access-Control-Request-Method", requestMethod)
+		}
+		rec := httptest.NewRecorder()
+		srv.httpServer.Handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Preflights are answered without credentials
+	for _, method := range []string{"PATCH", "DELETE"} {
+		rec := do("OPTIONS", "https://dash.example.com", method)
+		if rec.Code != http.StatusNoContent {
+			t.Fatalf("Expected 204 for %s preflight, got %d", method, rec.Code)
+		}
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://dash.example.com" {
+			t.Errorf("Unexpected Allow-Origin %q", got)
+		}
+		if rec.Header().Get("Access-Control-Allow-Methods") == "" || rec.Header().Get("Access-Control-Allow-Headers") == "" {
+			t.Errorf("Expected allowed methods and headers, got %v", rec.Header())
+		}
+	}
+	if rec := do("OPTIONS", "https://dash.example.com", "TRACE"); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for a disallowed method, got %d", rec.Code)
+	}
+
+	// Real requests still need auth, but carry CORS headers so the browser can read the error
+	rec := do("GET", "https://dash.example.com", "")
+	if rec.Code != http.StatusUnauthorized || rec.Header().Get("Access-Control-Allow-Origin") != "https://dash.example.com" {
+		t.Errorf("Expected 401 with CORS headers, got %d %v", rec.Code, rec.Header())
+	}
+
+	// Other origins get no CORS headers
+	if rec := do("GET", "https://evil.example.com", ""); rec.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Error("Expected no CORS headers for an unlisted origin")
+	}
+}
@@ -0,0 +1,102 @@
+package http
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/gorilla/mux"
+	"github.com/imalsogreg/beads/internal/rpc"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// handleListDependencies handles GET /issues/{id}/dependencies, the
+// dependency records of the issues {id} depends on
+func (s *Server) handleListDependencies(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := mux.Vars(r)["id"]
+	if !s.requireIssue(w, r, id) {
+		return
+	}
+
+	deps, err := s.storage.GetDependencyRecords(ctx, id)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if deps == nil {
+		deps = []*types.Dependency{}
+	}
+
+	s.writeSuccess(w, r, deps, opDependencies)
+}
+
+// handleListDependents handles GET /issues/{id}/dependents, the issues that
+// depend on {id}
+func (s *Server) handleListDependents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := mux.Vars(r)["id"]
+	if !s.requireIssue(w, r, id) {
+		return
+	}
+
+	dependents, err := s.storage.GetDependents(ctx, id)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if dependents == nil {
+		dependents = []*types.Issue{}
+	}
+
+	s.writeSuccess(w, r, dependents, rpc.OpList)
+}
+
+// handleListAllDependencies handles GET /dependencies, every dependency
+// record ordered by issue
+func (s *Server) handleListAllDependencies(w http.ResponseWriter, r *http.Request) {
+	byIssue, err := s.storage.GetAllDependencyRecords(r.Context())
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	ids := make([]string, 0, len(byIssue))
+	for id := range byIssue {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	deps := []*types.Dependency{}
+	for _, id := range ids {
+		deps = append(deps, byIssue[id]...)
+	}
+
+	s.writeSuccess(w, r, deps, opDependencies)
+}
+
+// handleDetectCycles handles GET /dependencies/cycles
+func (s *Server) handleDetectCycles(w http.ResponseWriter, r *http.Request) {
+	cycles, err := s.storage.DetectCycles(r.Context())
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if cycles == nil {
+		cycles = [][]*types.Issue{}
+	}
+
+	s.writeSuccess(w, r, cycles, opCycles)
+}
+
+// handleBlockedIssues handles GET /issues/blocked
+func (s *Server) handleBlockedIssues(w http.ResponseWriter, r *http.Request) {
+	blocked, err := s.storage.GetBlockedIssues(r.Context())
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if blocked == nil {
+		blocked = []*types.BlockedIssue{}
+	}
+
+	s.writeSuccess(w, r, blocked, opBlocked)
+}
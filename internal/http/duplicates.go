@@ -0,0 +1,119 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/imalsogreg/beads/internal/storage"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// mergeRequest is the body of POST /duplicates/merge
+type mergeRequest struct {
+	TargetID  string   `json:"target_id,omitempty" doc:"Issue to merge into"`
+	SourceIDs []string `json:"source_ids,omitempty" doc:"Issues to merge and close"`
+	All       bool     `json:"all,omitempty" doc:"Merge every duplicate group into its suggested target instead"`
+	Fuzzy     float64  `json:"fuzzy,omitempty" doc:"With all, also merge near-duplicates at this similarity threshold (0-1)"`
+}
+
+// mergeIntoRequest is the body of POST /issues/{id}/merge
+type mergeIntoRequest struct {
+	Into string `json:"into" doc:"Issue to merge into"`
+}
+
+// handleListDuplicates handles GET /duplicates
+func (s *Server) handleListDuplicates(w http.ResponseWriter, r *http.Request) {
+	var fuzzy float64
+	if v := r.URL.Query().Get("fuzzy"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f < 0 || f > 1 {
+			s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("invalid fuzzy threshold %q (use 0-1)", v))
+			return
+		}
+		fuzzy = f
+	}
+
+	groups, err := storage.FindDuplicates(r.Context(), s.storage, fuzzy)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.writeSuccess(w, r, groups, opDuplicates)
+}
+
+// handleMergeDuplicates handles POST /duplicates/merge
+func (s *Server) handleMergeDuplicates(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	actor := s.getActor(r)
+
+	var body mergeRequest
+	if err := s.parseBody(r, &body); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if body.All == (body.TargetID != "" || len(body.SourceIDs) > 0) {
+		s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("give either target_id and source_ids, or all"))
+		return
+	}
+	if body.Fuzzy < 0 || body.Fuzzy > 1 {
+		s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("invalid fuzzy threshold %g (use 0-1)", body.Fuzzy))
+		return
+	}
+
+	merges := []*types.DuplicateGroup{{SuggestedTarget: body.TargetID, SuggestedSources: body.SourceIDs}}
+	if body.All {
+		groups, err := storage.FindDuplicates(ctx, s.storage, body.Fuzzy)
+		if err != nil {
+			s.writeStoreError(w, r, err)
+			return
+		}
+		merges = groups
+	} else if err := storage.ValidateMerge(ctx, s.storage, body.TargetID, body.SourceIDs); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	results := []*types.MergeResult{}
+	for _, m := range merges {
+		result, err := storage.MergeIssues(ctx, s.storage, m.SuggestedTarget, m.SuggestedSources, actor)
+		if err != nil {
+			s.writeError(w, r, http.StatusInternalServerError, fmt.Errorf("failed to merge into %s: %w", m.SuggestedTarget, err))
+			return
+		}
+		results = append(results, result)
+	}
+
+	s.writeSuccess(w, r, results, opMerge)
+}
+
+// handleMergeIssue handles POST /issues/{id}/merge, merging the issue into
+// another one
+func (s *Server) handleMergeIssue(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sourceID := mux.Vars(r)["id"]
+
+	var body mergeIntoRequest
+	if err := s.parseBody(r, &body); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if body.Into == "" {
+		s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("into is required"))
+		return
+	}
+	if err := storage.ValidateMerge(ctx, s.storage, body.Into, []string{sourceID}); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := storage.MergeIssues(ctx, s.storage, body.Into, []string{sourceID}, s.getActor(r))
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.writeSuccess(w, r, result, opMergeIssue)
+}
@@ -0,0 +1,168 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestDuplicatesEndpoints(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	var ids []string
+	for _, title := range []string{"Fix login", "Fix login", "Fix login", "Unrelated", "Fix the login"} {
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, issue.ID)
+	}
+
+	rec := do("GET", "/duplicates", "")
+	var groups []*types.DuplicateGroup
+	if err := json.Unmarshal(rec.Body.Bytes(), &groups); err != nil {
+		t.Fatalf("failed to decode %s: %v", rec.Body, err)
+	}
+	if len(groups) != 1 || len(groups[0].Issues) != 3 || groups[0].SuggestedTarget != ids[0] {
+		t.Fatalf("Expected one group of 3 targeting %s, got %s", ids[0], rec.Body)
+	}
+
+	rec = do("GET", "/duplicates?fuzzy=0.6", "")
+	groups = nil
+	if err := json.Unmarshal(rec.Body.Bytes(), &groups); err != nil {
+		t.Fatalf("failed to decode %s: %v", rec.Body, err)
+	}
+	if len(groups) != 1 || len(groups[0].Issues) != 4 || groups[0].Issues[3].ID != ids[4] || groups[0].Issues[3].Score >= 1 {
+		t.Fatalf("Expected %s to join the group with a partial score, got %s", ids[4], rec.Body)
+	}
+	if rec := do("GET", "/duplicates?fuzzy=2", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for fuzzy=2, got %d", rec.Code)
+	}
+
+	for _, body := range []string{`{}`, `{"all": true, "fuzzy": -1}`, `{"all": true, "target_id": "bd-1"}`, `{"target_id": "bd-1", "source_ids": ["bd-99"]}`} {
+		if rec := do("POST", "/duplicates/merge", body); rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %s, got %d: %s", body, rec.Code, rec.Body)
+		}
+	}
+
+	rec = do("POST", "/duplicates/merge", `{"target_id": "`+ids[0]+`", "source_ids": ["`+ids[1]+`"]}`)
+	var results []*types.MergeResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &results); err != nil {
+		t.Fatalf("failed to decode %s: %v", rec.Body, err)
+	}
+	if len(results) != 1 || results[0].IssuesClosed != 1 {
+		t.Fatalf("Expected one source closed, got %s", rec.Body)
+	}
+
+	// The closed issue no longer matches the open one, so merge the rest with all
+	rec = do("POST", "/duplicates/merge", `{"all": true}`)
+	results = nil
+	if err := json.Unmarshal(rec.Body.Bytes(), &results); err != nil {
+		t.Fatalf("failed to decode %s: %v", rec.Body, err)
+	}
+	if len(results) != 1 || results[0].TargetID != ids[0] || len(results[0].SourceIDs) != 1 || results[0].SourceIDs[0] != ids[2] {
+		t.Fatalf("Expected %s merged into %s, got %s", ids[2], ids[0], rec.Body)
+	}
+	if issue, err := store.GetIssue(ctx, ids[2]); err != nil || issue.Status != types.StatusClosed {
+		t.Errorf("Expected %s to be closed, got %+v (%v)", ids[2], issue, err)
+	}
+}
+
+func TestMergeIssueEndpoint(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	post := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for _, title := range []string{"Login fails", "Login broken"} {
+		if err := store.CreateIssue(ctx, &types.Issue{Title: title, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeBug}, "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := store.AddIssueComment(ctx, "bd-2", "alice", "Happens on Safari"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, body := range []string{`{}`, `{"into": "bd-2"}`, `{"into": "bd-99"}`} {
+		if rec := post("/issues/bd-2/merge", body); rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %s, got %d: %s", body, rec.Code, rec.Body)
+		}
+	}
+
+	rec := post("/issues/bd-2/merge", `{"into": "bd-1"}`)
+	var result types.MergeResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatalf("failed to decode %s: %v", rec.Body, err)
+	}
+	if result.TargetID != "bd-1" || result.CommentsMoved != 1 || result.IssuesClosed != 1 {
+		t.Errorf("Unexpected result %s", rec.Body)
+	}
+	deps, err := store.GetDependencyRecords(ctx, "bd-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deps) != 1 || deps[0].Type != types.DepDuplicates {
+		t.Errorf("Expected a duplicates link from bd-2, got %+v", deps)
+	}
+
+	// The link shows on both issues
+	req := httptest.NewRequest("GET", "/issues/bd-1", nil)
+	req.Header.Set("Accept", "application/json")
+	rec = httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, req)
+	var issue types.Issue
+	if err := json.Unmarshal(rec.Body.Bytes(), &issue); err != nil {
+		t.Fatalf("failed to decode %s: %v", rec.Body, err)
+	}
+	if len(issue.Links) != 1 || issue.Links[0].ID != "bd-2" || issue.Links[0].Relation() != "duplicated by" {
+		t.Errorf("Expected bd-1 to be duplicated by bd-2, got %s", rec.Body)
+	}
+}
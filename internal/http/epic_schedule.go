@@ -0,0 +1,83 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/imalsogreg/beads/internal/schedule"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// handleEpicSchedule handles GET /epics/{id}/schedule
+func (s *Server) handleEpicSchedule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	query := r.URL.Query()
+
+	format := query.Get("format")
+	if format != "" && format != schedule.FormatMermaidGantt && format != schedule.FormatCSV {
+		s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("unknown format %q (use %s)", format, strings.Join(schedule.Formats, " or ")))
+		return
+	}
+	var opts schedule.Options
+	if v := query.Get("from"); v != "" {
+		from, err := types.ParseDate(v, time.Now())
+		if err != nil {
+			s.writeError(w, r, http.StatusBadRequest, err)
+			return
+		}
+		opts.From = from
+	}
+	if v := query.Get("hours_per_day"); v != "" {
+		hours, err := strconv.Atoi(v)
+		if err != nil || hours <= 0 || hours > 24 {
+			s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("invalid hours_per_day %q", v))
+			return
+		}
+		opts.HoursPerDay = hours
+	}
+
+	epic, err := s.storage.GetIssue(ctx, vars["id"])
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if epic == nil {
+		s.writeError(w, r, http.StatusNotFound, types.NotFound("issue", vars["id"]))
+		return
+	}
+
+	sched, err := schedule.ForEpic(ctx, s.storage, epic.ID, opts)
+	if err != nil {
+		if errors.Is(err, schedule.ErrCycle) {
+			s.writeError(w, r, http.StatusConflict, err)
+			return
+		}
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	if format == "" {
+		s.writeSuccess(w, r, sched, opSchedule)
+		return
+	}
+	var buf bytes.Buffer
+	if err := schedule.Write(&buf, sched, format); err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if format == schedule.FormatCSV {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", epic.ID+"-schedule.csv"))
+	} else {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(buf.Bytes())
+}
@@ -0,0 +1,88 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/schedule"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestEpicSchedule(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	do := func(path, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Accept", accept)
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	epic := &types.Issue{Title: "Launch", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeEpic}
+	if err := store.CreateIssue(ctx, epic, "test"); err != nil {
+		t.Fatal(err)
+	}
+	var tasks []*types.Issue
+	for _, title := range []string{"Schema", "API"} {
+		task := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, ParentID: epic.ID}
+		if err := store.CreateIssue(ctx, task, "test"); err != nil {
+			t.Fatal(err)
+		}
+		tasks = append(tasks, task)
+	}
+	dep := &types.Dependency{IssueID: tasks[1].ID, DependsOnID: tasks[0].ID, Type: types.DepBlocks}
+	if err := store.AddDependency(ctx, dep, "test"); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := do("/epics/"+epic.ID+"/schedule?from=2025-11-03", "application/json")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var sched schedule.Schedule
+	if err := json.Unmarshal(rec.Body.Bytes(), &sched); err != nil {
+		t.Fatalf("failed to decode %s: %v", rec.Body, err)
+	}
+	if len(sched.Items) != 2 || sched.Items[0].Issue.ID != tasks[0].ID || sched.Items[1].Start.Format("2006-01-02") != "2025-11-04" {
+		t.Errorf("Unexpected schedule %s", rec.Body)
+	}
+
+	rec = do("/epics/"+epic.ID+"/schedule?format=mermaid-gantt&from=2025-11-03", "application/json")
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") || !strings.HasPrefix(rec.Body.String(), "gantt\n") {
+		t.Errorf("Expected a mermaid chart, got %s: %s", ct, rec.Body)
+	}
+
+	rec = do("/epics/"+epic.ID+"/schedule?format=csv&from=2025-11-03", "application/json")
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") || !strings.Contains(rec.Body.String(), ",2025-11-04,2025-11-04,") {
+		t.Errorf("Expected a csv schedule, got %s: %s", ct, rec.Body)
+	}
+
+	for _, query := range []string{"format=xml", "from=someday", "hours_per_day=0"} {
+		if rec = do("/epics/"+epic.ID+"/schedule?"+query, "application/json"); rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %s, got %d", query, rec.Code)
+		}
+	}
+	if rec = do("/epics/bd-999/schedule", "application/json"); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a missing epic, got %d", rec.Code)
+	}
+}
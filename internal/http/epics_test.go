@@ -0,0 +1,74 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestEpicStatus(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	do := func(path, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Accept", accept)
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	epic := &types.Issue{Title: "Launch", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeEpic}
+	if err := store.CreateIssue(ctx, epic, "test"); err != nil {
+		t.Fatal(err)
+	}
+	nested := &types.Issue{Title: "Backend", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeEpic, ParentID: epic.ID}
+	if err := store.CreateIssue(ctx, nested, "test"); err != nil {
+		t.Fatal(err)
+	}
+	for _, parent := range []string{epic.ID, nested.ID} {
+		if err := store.CreateIssue(ctx, &types.Issue{Title: "Task", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, ParentID: parent}, "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rec := do("/epics/"+epic.ID+"/status", "application/json")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var roll types.EpicRollup
+	if err := json.Unmarshal(rec.Body.Bytes(), &roll); err != nil {
+		t.Fatalf("failed to decode %s: %v", rec.Body, err)
+	}
+	if roll.Epic.ID != epic.ID || roll.Total != 2 || len(roll.Epics) != 1 || roll.Epics[0].Total != 1 {
+		t.Errorf("Unexpected rollup %s", rec.Body)
+	}
+
+	rec = do("/epics/"+epic.ID+"/status", "text/plain")
+	if body := rec.Body.String(); !strings.Contains(body, "Progress: 0/2") || !strings.Contains(body, "Nested epics") {
+		t.Errorf("Expected the rollup as text, got %s", body)
+	}
+
+	if rec = do("/epics/bd-999/status", "application/json"); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a missing epic, got %d", rec.Code)
+	}
+}
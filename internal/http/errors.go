@@ -0,0 +1,94 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/imalsogreg/beads/internal/secrets"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// errorBody is the JSON body of a failed request: a types.APIError, with
+// success false as in every response
+type errorBody struct {
+	Error   string          `json:"error"`
+	Code    types.ErrorCode `json:"code"`
+	Details interface{}     `json:"details,omitempty"`
+	Success bool            `json:"success"`
+}
+
+// classifyError maps the errors storage returns to a status, a code more
+// specific than the status's, and details for the client. The status is 0
+// for errors without a mapping.
+func classifyError(err error) (int, types.ErrorCode, interface{}) {
+	var invalid *types.ValidationError
+	var transition *types.TransitionError
+	var detected *secrets.DetectedError
+	var cycle *types.CycleError
+	var notFound *types.NotFoundError
+	var conflict *sqlite.VersionConflictError
+	switch {
+	case errors.As(err, &invalid):
+		return http.StatusUnprocessableEntity, types.CodeValidationFailed, invalid
+	case errors.As(err, &transition):
+		return http.StatusUnprocessableEntity, types.CodeInvalidTransition, transition
+	case errors.As(err, &detected):
+		return http.StatusUnprocessableEntity, types.CodeValidationFailed, map[string]interface{}{
+			"violations": []types.Violation{{Field: detected.Field, Code: types.ViolationInvalid, Message: detected.Error()}},
+		}
+	case errors.As(err, &cycle):
+		return http.StatusConflict, types.CodeDependencyCycle, cycle
+	case errors.As(err, &notFound):
+		return http.StatusNotFound, types.CodeNotFound, notFound
+	case errors.As(err, &conflict):
+		return http.StatusConflict, types.CodeConflict, conflict
+	}
+	return 0, "", nil
+}
+
+// newErrorBody builds the body for err sent with status. Details come with
+// the error's own code only when status agrees with it.
+func newErrorBody(status int, err error) errorBody {
+	body := errorBody{Error: err.Error(), Code: types.ErrorCodeForStatus(status)}
+	if mapped, code, details := classifyError(err); mapped == status {
+		body.Code, body.Details = code, details
+	}
+	return body
+}
+
+// writeStoreError writes an error from storage with the status it maps to:
+// 404 for missing records, 409 for cycles and version conflicts, 422 for
+// invalid issues, rejected content, and status changes the workflow doesn't
+// allow, and 500 for anything else
+func (s *Server) writeStoreError(w http.ResponseWriter, r *http.Request, err error) {
+	status, _, _ := classifyError(err)
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+	s.writeError(w, r, status, err)
+}
+
+// writeError writes an error response. JSON responses carry the error's
+// code and details; text ones list each problem with an invalid issue.
+func (s *Server) writeError(w http.ResponseWriter, r *http.Request, statusCode int, err error) {
+	if s.wantsJSON(r) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statusCode)
+		json.NewEncoder(w).Encode(newErrorBody(statusCode, err))
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(statusCode)
+	var invalid *types.ValidationError
+	if errors.As(err, &invalid) && len(invalid.Violations) > 1 {
+		fmt.Fprintf(w, "Error: %d problems:\n", len(invalid.Violations))
+		for _, v := range invalid.Violations {
+			fmt.Fprintf(w, "  %s: %s\n", v.Field, v.Message)
+		}
+		return
+	}
+	fmt.Fprintf(w, "Error: %s\n", err.Error())
+}
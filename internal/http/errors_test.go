@@ -0,0 +1,117 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestErrorCodes(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	a := &types.Issue{Title: "A", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	b := &types.Issue{Title: "B", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	for _, issue := range []*types.Issue{a, b} {
+		if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.AddDependency(ctx, &types.Dependency{IssueID: a.ID, DependsOnID: b.ID, Type: types.DepBlocks}, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	do := func(method, path, body string, header ...string) (*httptest.ResponseRecorder, *types.APIError) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("X-Actor", "alice")
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec, types.ParseAPIError(rec.Code, rec.Body.Bytes())
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		header []string
+		status int
+		code   types.ErrorCode
+	}{
+		{"missing issue", "GET", "/issues/bd-999", "", nil, http.StatusNotFound, types.CodeNotFound},
+		{"missing dependency target", "POST", "/issues/" + a.ID + "/dependencies", `{"depends_on": "bd-999"}`, nil, http.StatusNotFound, types.CodeNotFound},
+		{"cycle", "POST", "/issues/" + b.ID + "/dependencies", `{"depends_on": "` + a.ID + `"}`, nil, http.StatusConflict, types.CodeDependencyCycle},
+		{"stale version", "PATCH", "/issues/" + a.ID, `{"title": "A2"}`, []string{"If-Match", `"99"`}, http.StatusConflict, types.CodeConflict},
+		{"invalid issue", "POST", "/issues", `{"title": "", "issue_type": "task"}`, nil, http.StatusUnprocessableEntity, types.CodeValidationFailed},
+		{"malformed body", "POST", "/issues", `{`, nil, http.StatusBadRequest, types.CodeBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, apiErr := do(tt.method, tt.path, tt.body, tt.header...)
+			if rec.Code != tt.status || apiErr.Code != tt.code {
+				t.Errorf("Expected %d %s, got %d %s: %s", tt.status, tt.code, rec.Code, apiErr.Code, rec.Body)
+			}
+		})
+	}
+
+	// Details describe the error
+	_, apiErr := do("POST", "/issues/"+b.ID+"/dependencies", `{"depends_on": "`+a.ID+`"}`)
+	var cycle types.CycleError
+	if err := json.Unmarshal(apiErr.Details, &cycle); err != nil || cycle.IssueID != b.ID || cycle.DependsOnID != a.ID {
+		t.Errorf("Expected the cycle's issues in details, got %s (%v)", apiErr.Details, err)
+	}
+	rec, apiErr := do("PATCH", "/issues/"+a.ID, `{"title": "A2"}`, "If-Match", `"99"`)
+	var conflict struct {
+		CurrentVersion int          `json:"current_version"`
+		Issue          *types.Issue `json:"issue"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &conflict); err != nil || conflict.Issue == nil || !strings.Contains(string(apiErr.Details), `"expected_version":99`) {
+		t.Errorf("Expected the current issue and the versions, got %s (%v)", rec.Body, err)
+	}
+}
+
+func TestUnauthorizedErrorCode(t *testing.T) {
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	t.Setenv("BEADS_API_SECRET", "s3cret")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest("GET", "/issues", nil)
+	req.Header.Set("Accept", "application/json")
+	rec := httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, req)
+
+	apiErr := types.ParseAPIError(rec.Code, rec.Body.Bytes())
+	if rec.Code != http.StatusUnauthorized || apiErr.Code != types.CodeUnauthorized || apiErr.Message != "Missing Authorization header" {
+		t.Errorf("Expected 401 unauthorized, got %d %+v", rec.Code, apiErr)
+	}
+}
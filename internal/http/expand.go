@@ -0,0 +1,142 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// issueExpansions are the related records the expand query parameter can
+// add to issues, each under a key of the same name
+var issueExpansions = []string{"comments", "dependencies", "children", "history"}
+
+// parseExpand parses the expand query parameter, a comma-separated list of
+// issueExpansions
+func parseExpand(value string) ([]string, error) {
+	var expand []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || containsString(expand, name) {
+			continue
+		}
+		if !containsString(issueExpansions, name) {
+			return nil, fmt.Errorf("expand: unknown expansion %q (use %s)", name, strings.Join(issueExpansions, ", "))
+		}
+		expand = append(expand, name)
+	}
+	return expand, nil
+}
+
+// requestedExpansions reads a request's expand parameter, answering 400 for
+// an unknown expansion and 501 for history without SQLite
+func (s *Server) requestedExpansions(w http.ResponseWriter, r *http.Request) ([]string, bool) {
+	expand, err := parseExpand(r.URL.Query().Get("expand"))
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return nil, false
+	}
+	if _, ok := s.storage.(*sqlite.SQLiteStorage); !ok && containsString(expand, "history") {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("expand=history requires SQLite backend"))
+		return nil, false
+	}
+	return expand, true
+}
+
+// issueRelations are the related records of a set of issues, by issue ID
+type issueRelations map[string]map[string]interface{}
+
+// expandRelations loads the expansions of issues. With SQLite each
+// expansion takes a query per few hundred issues; other backends look
+// issues up one at a time and have no history (see requestedExpansions).
+func (s *Server) expandRelations(ctx context.Context, issues []*types.Issue, expand []string) (issueRelations, error) {
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	relations := make(issueRelations, len(expand))
+	sqliteStore, _ := s.storage.(*sqlite.SQLiteStorage)
+	for _, name := range expand {
+		byIssue := make(map[string]interface{}, len(ids))
+		switch {
+		case sqliteStore != nil:
+			var err error
+			switch name {
+			case "comments":
+				err = collect(ctx, ids, byIssue, sqliteStore.GetCommentsForIssues)
+			case "dependencies":
+				err = collect(ctx, ids, byIssue, sqliteStore.GetDependencyRecordsForIssues)
+			case "children":
+				err = collect(ctx, ids, byIssue, sqliteStore.GetChildrenForIssues)
+			case "history":
+				err = collect(ctx, ids, byIssue, sqliteStore.GetHistoryForIssues)
+			}
+			if err != nil {
+				return nil, err
+			}
+		default:
+			for _, id := range ids {
+				var records interface{}
+				var err error
+				switch name {
+				case "comments":
+					records, err = s.storage.GetIssueComments(ctx, id)
+				case "dependencies":
+					records, err = s.storage.GetDependencyRecords(ctx, id)
+				case "children":
+					records, err = s.storage.GetChildren(ctx, id)
+				}
+				if err != nil {
+					return nil, err
+				}
+				byIssue[id] = records
+			}
+		}
+		relations[name] = byIssue
+	}
+	return relations, nil
+}
+
+// collect stores the records load finds for ids in byIssue
+func collect[T any](ctx context.Context, ids []string, byIssue map[string]interface{}, load func(context.Context, []string) (map[string][]T, error)) error {
+	records, err := load(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for id, list := range records {
+		byIssue[id] = list
+	}
+	return nil
+}
+
+// expandIssues renders issues with their expansions, slimmed down to fields
+// if any are given. An issue without related records of a kind gets an
+// empty list, so every object has the same keys.
+func expandIssues(issues []*types.Issue, fields []string, relations issueRelations) []map[string]json.RawMessage {
+	var expanded []map[string]json.RawMessage
+	if len(fields) > 0 {
+		expanded = selectIssueFields(issues, fields)
+	} else {
+		expanded = make([]map[string]json.RawMessage, len(issues))
+		for i, issue := range issues {
+			data, _ := json.Marshal(issue)
+			_ = json.Unmarshal(data, &expanded[i])
+		}
+	}
+	for i, issue := range issues {
+		for name, byIssue := range relations {
+			value := json.RawMessage("[]")
+			if records, ok := byIssue[issue.ID]; ok {
+				if data, err := json.Marshal(records); err == nil && string(data) != "null" {
+					value = data
+				}
+			}
+			expanded[i][name] = value
+		}
+	}
+	return expanded
+}
@@ -0,0 +1,105 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestExpandIssues(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	epic := &types.Issue{Title: "Epic", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeEpic}
+	if err := store.CreateIssue(ctx, epic, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	child := &types.Issue{Title: "Child", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, ParentID: epic.ID}
+	if err := store.CreateIssue(ctx, child, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.AddIssueComment(ctx, epic.ID, "alice", "Kickoff"); err != nil {
+		t.Fatal(err)
+	}
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Accept", "application/json")
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+	type expanded struct {
+		ID           string                  `json:"id"`
+		Title        string                  `json:"title"`
+		Comments     []*types.Comment        `json:"comments"`
+		Dependencies []*types.Dependency     `json:"dependencies"`
+		Children     []*types.Issue          `json:"children"`
+		History      []*sqlite.IssueRevision `json:"history"`
+	}
+
+	rec := get("/issues/" + epic.ID + "?expand=comments,children,history")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /issues/%s: status %d: %s", epic.ID, rec.Code, rec.Body)
+	}
+	var shown expanded
+	if err := json.Unmarshal(rec.Body.Bytes(), &shown); err != nil {
+		t.Fatal(err)
+	}
+	if len(shown.Comments) != 1 || len(shown.Children) != 1 || shown.Children[0].ID != child.ID || len(shown.History) == 0 {
+		t.Errorf("Expected the comment, child, and history, got %s", rec.Body)
+	}
+	if shown.Dependencies != nil {
+		t.Errorf("Expected dependencies left out when not asked for, got %v", shown.Dependencies)
+	}
+
+	rec = get("/issues?expand=dependencies,comments&fields=id")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /issues: status %d: %s", rec.Code, rec.Body)
+	}
+	var raw []map[string]json.RawMessage
+	if err := json.Unmarshal(rec.Body.Bytes(), &raw); err != nil {
+		t.Fatal(err)
+	}
+	for _, issue := range raw {
+		if len(issue) != 3 || issue["comments"] == nil || issue["dependencies"] == nil {
+			t.Errorf("Expected id, comments, and dependencies, got %v", issue)
+		}
+	}
+	var listed []expanded
+	_ = json.Unmarshal(rec.Body.Bytes(), &listed)
+	for _, issue := range listed {
+		switch issue.ID {
+		case epic.ID:
+			if len(issue.Comments) != 1 || len(issue.Dependencies) != 0 {
+				t.Errorf("%s: expected a comment and no dependencies, got %+v", issue.ID, issue)
+			}
+		case child.ID:
+			if len(issue.Comments) != 0 || len(issue.Dependencies) != 1 || issue.Dependencies[0].DependsOnID != epic.ID {
+				t.Errorf("%s: expected its parent dependency, got %+v", issue.ID, issue)
+			}
+		}
+	}
+
+	if rec := get("/issues?expand=watchers"); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown expansion, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,194 @@
+package http
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+)
+
+// feedPublicKey is the config key that opens GET /feed.atom to unauthenticated readers
+const feedPublicKey = "feed.public"
+
+const (
+	defaultFeedLimit = 50
+	maxFeedLimit     = 500
+)
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	ID       string        `xml:"id"`
+	Title    string        `xml:"title"`
+	Updated  string        `xml:"updated"`
+	Author   atomAuthor    `xml:"author"`
+	Link     atomLink      `xml:"link"`
+	Category *atomCategory `xml:"category,omitempty"`
+	Content  *atomText     `xml:"content,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// handleFeed handles GET /feed.atom
+func (s *Server) handleFeed(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("activity feed requires SQLite backend"))
+		return
+	}
+
+	limit := defaultFeedLimit
+	if l := query.Get("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n <= 0 {
+			s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("invalid limit %q", l))
+			return
+		}
+		limit = n
+	}
+	if limit > maxFeedLimit {
+		limit = maxFeedLimit
+	}
+
+	filter := sqlite.ActivityFilter{
+		Label: query.Get("label"),
+		Epic:  query.Get("epic"),
+		Limit: limit,
+	}
+	entries, err := sqliteStore.GetActivity(ctx, filter)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	// Encrypted text stays out of the feed, which may be public
+	encryption, err := sqliteStore.GetFieldEncryptionStatus(ctx)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	prefix, _ := s.storage.GetConfig(ctx, "issue_prefix")
+	base := baseURL(r)
+
+	title := "Issue activity"
+	if prefix != "" {
+		title = prefix + " issue activity"
+	}
+	switch {
+	case filter.Epic != "" && filter.Label != "":
+		title += fmt.Sprintf(" (epic %s, label %s)", filter.Epic, filter.Label)
+	case filter.Epic != "":
+		title += fmt.Sprintf(" (epic %s)", filter.Epic)
+	case filter.Label != "":
+		title += fmt.Sprintf(" (label %s)", filter.Label)
+	}
+
+	feed := atomFeed{
+		ID:    "urn:beads:" + prefix + ":feed",
+		Title: title,
+		Links: []atomLink{{Rel: "self", Href: base + r.URL.RequestURI()}},
+	}
+	if filter.Epic != "" {
+		feed.ID += ":epic:" + filter.Epic
+	}
+	if filter.Label != "" {
+		feed.ID += ":label:" + filter.Label
+	}
+
+	updated := time.Now().UTC()
+	if len(entries) > 0 {
+		updated = entries[0].CreatedAt.UTC()
+	}
+	feed.Updated = updated.Format(time.RFC3339)
+
+	for _, e := range entries {
+		entry := atomEntry{
+			ID:       "urn:beads:" + prefix + ":" + e.ID,
+			Title:    activityTitle(e),
+			Updated:  e.CreatedAt.UTC().Format(time.RFC3339),
+			Author:   atomAuthor{Name: e.Actor},
+			Link:     atomLink{Href: base + "/issues/" + e.IssueID},
+			Category: &atomCategory{Term: e.Kind},
+		}
+		if e.Text != "" && !encryption.Enabled {
+			entry.Content = &atomText{Type: "text", Body: e.Text}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, xml.Header)
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	_ = enc.Encode(feed)
+}
+
+// activityTitle summarizes an activity entry for a feed reader
+func activityTitle(e *sqlite.ActivityEntry) string {
+	switch e.Kind {
+	case sqlite.ActivityCommented:
+		return fmt.Sprintf("%s commented on %s: %s", e.Actor, e.IssueID, e.IssueTitle)
+	default:
+		return fmt.Sprintf("%s %s: %s", e.IssueID, e.Kind, e.IssueTitle)
+	}
+}
+
+// baseURL reconstructs the externally visible scheme and host of a request
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		scheme = proto
+	}
+	return scheme + "://" + r.Host
+}
+
+// publicReadPaths maps endpoints that can be opened to unauthenticated
+// readers to the config key that enables it
+var publicReadPaths = map[string]string{
+	"/feed.atom":    feedPublicKey,
+	"/calendar.ics": calendarPublicKey,
+}
+
+// isPublicRead reports whether r may read a subscribable endpoint without a token
+func (s *Server) isPublicRead(r *http.Request) bool {
+	key, ok := publicReadPaths[r.URL.Path]
+	if !ok || r.Method != "GET" {
+		return false
+	}
+	public, err := s.storage.GetConfig(r.Context(), key)
+	return err == nil && public == "true"
+}
@@ -0,0 +1,61 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// issueFields lists the JSON fields of an issue, in declaration order
+var issueFields = func() []string {
+	t := reflect.TypeOf(types.Issue{})
+	fields := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}()
+
+// parseIssueFields parses the fields query parameter, a comma-separated list
+// of issue JSON fields. An empty value selects every field.
+func parseIssueFields(value string) ([]string, error) {
+	var fields []string
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if !containsString(issueFields, field) {
+			return nil, fmt.Errorf("fields: unknown issue field %q", field)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// selectIssueFields slims issues down to the given fields. Fields an issue
+// doesn't have are null rather than left out, so every object has the same keys.
+func selectIssueFields(issues []*types.Issue, fields []string) []map[string]json.RawMessage {
+	selected := make([]map[string]json.RawMessage, len(issues))
+	for i, issue := range issues {
+		var all map[string]json.RawMessage
+		data, _ := json.Marshal(issue)
+		_ = json.Unmarshal(data, &all)
+
+		selected[i] = make(map[string]json.RawMessage, len(fields))
+		for _, field := range fields {
+			if value, ok := all[field]; ok {
+				selected[i][field] = value
+			} else {
+				selected[i][field] = json.RawMessage("null")
+			}
+		}
+	}
+	return selected
+}
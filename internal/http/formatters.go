@@ -1,11 +1,19 @@
 package http
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/imalsogreg/beads/internal/backup"
+	"github.com/imalsogreg/beads/internal/git"
+	"github.com/imalsogreg/beads/internal/importer"
+	"github.com/imalsogreg/beads/internal/replication"
 	"github.com/imalsogreg/beads/internal/rpc"
+	"github.com/imalsogreg/beads/internal/stale"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
 	"github.com/imalsogreg/beads/internal/types"
 )
 
@@ -47,7 +55,7 @@ func (s *Server) formatIssueList(issues []*types.Issue) string {
 			assignee = fmt.Sprintf(" (@%s)", issue.Assignee)
 		}
 
-		fmt.Fprintf(&b, "%s%s%s %s%s\n", issue.ID, priority, issueType, issue.Status, assignee)
+		fmt.Fprintf(&b, "%s%s%s %s%s%s\n", issue.ID, priority, issueType, issue.Status, assignee, dueSuffix(issue))
 		fmt.Fprintf(&b, "  %s\n\n", issue.Title)
 	}
 
@@ -59,7 +67,7 @@ func (s *Server) formatIssueDetail(issue *types.Issue) string {
 	var b strings.Builder
 
 	fmt.Fprintf(&b, "\n%s: %s\n", issue.ID, issue.Title)
-	fmt.Fprintf(&b, strings.Repeat("=", len(issue.ID)+len(issue.Title)+2)+"\n\n")
+	fmt.Fprint(&b, strings.Repeat("=", len(issue.ID)+len(issue.Title)+2)+"\n\n")
 
 	fmt.Fprintf(&b, "Status: %s\n", issue.Status)
 	if issue.Priority >= 0 && issue.Priority <= 4 {
@@ -71,6 +79,22 @@ func (s *Server) formatIssueDetail(issue *types.Issue) string {
 		fmt.Fprintf(&b, "Assignee: %s\n", issue.Assignee)
 	}
 
+	if issue.Milestone != "" {
+		fmt.Fprintf(&b, "Milestone: %s\n", issue.Milestone)
+	}
+
+	if issue.ParentID != "" {
+		fmt.Fprintf(&b, "Parent: %s\n", issue.ParentID)
+	}
+
+	if p := issue.Subtasks; p != nil && p.Total > 0 {
+		fmt.Fprintf(&b, "Subtasks: %d/%d closed (%d%%)", p.Closed, p.Total, p.Closed*100/p.Total)
+		if p.InProgress > 0 || p.Blocked > 0 {
+			fmt.Fprintf(&b, ", %d in progress, %d blocked", p.InProgress, p.Blocked)
+		}
+		fmt.Fprint(&b, "\n")
+	}
+
 	if issue.EstimatedMinutes != nil && *issue.EstimatedMinutes > 0 {
 		hours := *issue.EstimatedMinutes / 60
 		minutes := *issue.EstimatedMinutes % 60
@@ -88,6 +112,17 @@ func (s *Server) formatIssueDetail(issue *types.Issue) string {
 		fmt.Fprintf(&b, "Closed: %s\n", issue.ClosedAt.Format("2006-01-02 15:04:05"))
 	}
 
+	if issue.StartDate != nil {
+		fmt.Fprintf(&b, "Start: %s\n", issue.StartDate.Local().Format("2006-01-02"))
+	}
+	if issue.DueDate != nil {
+		overdue := ""
+		if issue.IsOverdue(time.Now()) {
+			overdue = " (overdue)"
+		}
+		fmt.Fprintf(&b, "Due: %s%s\n", issue.DueDate.Local().Format("2006-01-02"), overdue)
+	}
+
 	if issue.Description != "" {
 		fmt.Fprintf(&b, "\nDescription:\n%s\n", issue.Description)
 	}
@@ -108,6 +143,10 @@ func (s *Server) formatIssueDetail(issue *types.Issue) string {
 		fmt.Fprintf(&b, "\nLabels: %s\n", strings.Join(issue.Labels, ", "))
 	}
 
+	if len(issue.Watchers) > 0 {
+		fmt.Fprintf(&b, "\nWatchers: %s\n", strings.Join(issue.Watchers, ", "))
+	}
+
 	if len(issue.Dependencies) > 0 {
 		fmt.Fprintf(&b, "\nDependencies:\n")
 		for _, dep := range issue.Dependencies {
@@ -128,7 +167,8 @@ func (s *Server) formatIssueDetail(issue *types.Issue) string {
 	return b.String()
 }
 
-// formatReadyWork formats ready work list
+// formatReadyWork formats ready work list. Overdue issues, which the store
+// sorts first, get a section of their own.
 func (s *Server) formatReadyWork(issues []*types.Issue) string {
 	if len(issues) == 0 {
 		return "\nNo ready work found.\n"
@@ -137,7 +177,21 @@ func (s *Server) formatReadyWork(issues []*types.Issue) string {
 	var b strings.Builder
 	fmt.Fprintf(&b, "\n📋 Ready work (%d issue(s) with no blockers):\n\n", len(issues))
 
+	now := time.Now()
+	overdue := 0
+	for _, issue := range issues {
+		if issue.IsOverdue(now) {
+			overdue++
+		}
+	}
+	if overdue > 0 {
+		fmt.Fprintf(&b, "⚠️  Overdue (%d):\n", overdue)
+	}
+
 	for i, issue := range issues {
+		if overdue > 0 && i > 0 && issues[i-1].IsOverdue(now) && !issue.IsOverdue(now) {
+			fmt.Fprint(&b, "\n")
+		}
 		priority := ""
 		if issue.Priority >= 0 && issue.Priority <= 4 {
 			priority = fmt.Sprintf(" [P%d]", issue.Priority)
@@ -147,12 +201,20 @@ func (s *Server) formatReadyWork(issues []*types.Issue) string {
 			assignee = fmt.Sprintf(" (@%s)", issue.Assignee)
 		}
 
-		fmt.Fprintf(&b, "%d. %s%s: %s%s\n", i+1, issue.ID, priority, issue.Title, assignee)
+		fmt.Fprintf(&b, "%d. %s%s: %s%s%s\n", i+1, issue.ID, priority, issue.Title, assignee, dueSuffix(issue))
 	}
 
 	return b.String()
 }
 
+// dueSuffix notes an issue's due date, if it has one
+func dueSuffix(issue *types.Issue) string {
+	if issue.DueDate == nil {
+		return ""
+	}
+	return fmt.Sprintf(" [due %s]", issue.DueDate.Local().Format("2006-01-02"))
+}
+
 // formatStats formats database statistics
 func (s *Server) formatStats(stats *types.Statistics) string {
 	var b strings.Builder
@@ -171,50 +233,133 @@ func (s *Server) formatStats(stats *types.Statistics) string {
 		fmt.Fprintf(&b, "Average Lead Time: %.1f hours\n", stats.AverageLeadTime)
 	}
 
+	if stats.LoggedMinutes > 0 {
+		fmt.Fprintf(&b, "\nTime Logged: %s\n", formatMinutes(stats.LoggedMinutes))
+		fmt.Fprintf(&b, "\nBy assignee:\n")
+		for _, t := range stats.TimeByAssignee {
+			fmt.Fprintf(&b, "  %-20s %s on %d issue(s)\n", assigneeLabel(t.Assignee), formatMinutes(t.LoggedMinutes), t.Issues)
+		}
+		fmt.Fprintf(&b, "\nBy issue:\n")
+		for _, t := range stats.TimeByIssue {
+			fmt.Fprintf(&b, "  %s %s: %s\n", t.IssueID, t.Title, loggedVsEstimate(t.LoggedMinutes, t.EstimatedMinutes))
+		}
+	}
+
+	if stats.GroupBy != "" {
+		fmt.Fprintf(&b, "\nBy %s:\n", stats.GroupBy)
+		formatStatGroups(&b, stats.GroupBy, stats.Groups)
+	}
+
 	return b.String()
 }
 
-// formatEpicStatus formats epic status (expects array of EpicStatus)
-func (s *Server) formatEpicStatus(statuses []*types.EpicStatus) string {
-	if len(statuses) == 0 {
-		return "\nNo epics found.\n"
+// formatStatGroups writes a table of issue counts by status for each group
+func formatStatGroups(b *strings.Builder, groupBy string, groups []*types.StatGroup) {
+	if len(groups) == 0 {
+		fmt.Fprintf(b, "  (no issues)\n")
+		return
+	}
+	label := func(key string) string {
+		switch {
+		case key == "" && groupBy == types.GroupByLabel:
+			return "(no labels)"
+		case key == "":
+			return assigneeLabel(key)
+		case groupBy == types.GroupByPriority:
+			return "P" + key
+		}
+		return key
+	}
+	statuses := types.StatGroupStatuses(groups)
+	width := len(groupBy)
+	for _, g := range groups {
+		if n := len(label(g.Key)); n > width {
+			width = n
+		}
 	}
 
-	var b strings.Builder
-	fmt.Fprintf(&b, "\n🎯 Epic Status\n")
-	fmt.Fprintf(&b, "==============\n\n")
-
+	fmt.Fprintf(b, "  %-*s", width, groupBy)
 	for _, status := range statuses {
-		epicID := status.Epic.ID
-		epicTitle := status.Epic.Title
-
-		fmt.Fprintf(&b, "%s: %s\n", epicID, epicTitle)
-
-		total := status.TotalChildren
-		completed := status.ClosedChildren
-		percentage := 0.0
-		if total > 0 {
-			percentage = float64(completed) / float64(total) * 100.0
+		fmt.Fprintf(b, "  %11s", status)
+	}
+	fmt.Fprintf(b, "  %6s\n", "total")
+	for _, g := range groups {
+		fmt.Fprintf(b, "  %-*s", width, label(g.Key))
+		for _, status := range statuses {
+			fmt.Fprintf(b, "  %11d", g.ByStatus[status])
 		}
+		fmt.Fprintf(b, "  %6d\n", g.Total)
+	}
+}
 
-		fmt.Fprintf(&b, "Progress: %d/%d (%.1f%%)", completed, total, percentage)
+// assigneeLabel names an assignee in time rollups
+func assigneeLabel(assignee string) string {
+	if assignee == "" {
+		return "(unassigned)"
+	}
+	return assignee
+}
+
+// loggedVsEstimate renders logged time against an optional estimate
+func loggedVsEstimate(logged int, estimated *int) string {
+	if estimated == nil {
+		return formatMinutes(logged) + " logged"
+	}
+	return fmt.Sprintf("%s logged of %s estimated", formatMinutes(logged), formatMinutes(*estimated))
+}
 
-		if status.EligibleForClose {
-			fmt.Fprintf(&b, " ✅ Eligible for closure")
+// formatEpicRollup formats an epic's progress, its nested epics, and what
+// is blocking it
+func (s *Server) formatEpicRollup(roll *types.EpicRollup) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n🎯 %s: %s\n", roll.Epic.ID, roll.Epic.Title)
+	fmt.Fprintf(&b, "Progress: %d/%d closed (%d%%)", roll.Closed, roll.Total, roll.Percent)
+	if roll.InProgress > 0 {
+		fmt.Fprintf(&b, ", %d in progress", roll.InProgress)
+	}
+	if roll.Blocked > 0 {
+		fmt.Fprintf(&b, ", %d blocked", roll.Blocked)
+	}
+	fmt.Fprintf(&b, "\n")
+	if roll.Total > 0 {
+		barWidth := 40
+		filled := barWidth * roll.Percent / 100
+		fmt.Fprintf(&b, "[%s%s]\n", strings.Repeat("█", filled), strings.Repeat("░", barWidth-filled))
+	}
+	if roll.EstimatedMinutes > 0 {
+		fmt.Fprintf(&b, "Estimate: %s of %s closed (%d%%)", formatMinutes(roll.ClosedMinutes), formatMinutes(roll.EstimatedMinutes), roll.PercentByMinutes)
+		if roll.Unestimated > 0 {
+			fmt.Fprintf(&b, "; %d unestimated", roll.Unestimated)
 		}
 		fmt.Fprintf(&b, "\n")
+	}
 
-		if total > 0 {
-			// Simple progress bar
-			barWidth := 40
-			filled := int(float64(barWidth) * percentage / 100.0)
-			bar := strings.Repeat("█", filled) + strings.Repeat("░", barWidth-filled)
-			fmt.Fprintf(&b, "[%s]\n", bar)
+	if len(roll.Epics) > 0 {
+		fmt.Fprintf(&b, "\nNested epics:\n")
+		var nested func(epics []*types.EpicRollup, indent string)
+		nested = func(epics []*types.EpicRollup, indent string) {
+			for _, e := range epics {
+				fmt.Fprintf(&b, "%s%s: %s  %d/%d (%d%%)", indent, e.Epic.ID, e.Epic.Title, e.Closed, e.Total, e.Percent)
+				if e.Blocked > 0 {
+					fmt.Fprintf(&b, ", %d blocked", e.Blocked)
+				}
+				fmt.Fprintf(&b, "\n")
+				nested(e.Epics, indent+"  ")
+			}
 		}
-
-		fmt.Fprintf(&b, "\n")
+		nested(roll.Epics, "  ")
 	}
 
+	if len(roll.Blockers) > 0 {
+		fmt.Fprintf(&b, "\nBlockers:\n")
+		for _, eb := range roll.Blockers {
+			fmt.Fprintf(&b, "  %s [P%d] %s (%s) blocks %s", eb.Issue.ID, eb.Issue.Priority, eb.Issue.Title, eb.Issue.Status, strings.Join(eb.Blocks, ", "))
+			if eb.External {
+				fmt.Fprintf(&b, " (outside the epic)")
+			}
+			fmt.Fprintf(&b, "\n")
+		}
+	}
 	return b.String()
 }
 
@@ -234,18 +379,28 @@ func (s *Server) formatDependencyTree(tree []*types.TreeNode) string {
 			priority = fmt.Sprintf(" [P%d]", node.Priority)
 		}
 
+		link := ""
+		if node.DepType.IsLink() {
+			link = fmt.Sprintf(" [%s]", node.DepType)
+		}
+
 		truncated := ""
 		if node.Truncated {
 			truncated = " [truncated]"
 		}
 
-		fmt.Fprintf(&b, "%s→ %s: %s%s (%s)%s\n", indent, node.ID, node.Title, priority, node.Status, truncated)
+		if node.Unreachable != "" {
+			fmt.Fprintf(&b, "%s→ %s: (unreachable: %s)%s\n", indent, node.ID, node.Unreachable, link)
+			continue
+		}
+		fmt.Fprintf(&b, "%s→ %s: %s%s (%s)%s%s\n", indent, node.ID, node.Title, priority, node.Status, link, truncated)
 	}
 
 	return b.String()
 }
 
-// formatComments formats comment list
+// formatComments formats comment list, with replies indented under the
+// comments they answer
 func (s *Server) formatComments(comments []*types.Comment) string {
 	if len(comments) == 0 {
 		return "\nNo comments.\n"
@@ -254,37 +409,100 @@ func (s *Server) formatComments(comments []*types.Comment) string {
 	var b strings.Builder
 	fmt.Fprintf(&b, "\n💬 Comments (%d):\n\n", len(comments))
 
-	for i, comment := range comments {
-		fmt.Fprintf(&b, "%d. [%s] %s:\n", i+1,
-			comment.CreatedAt.Format("2006-01-02 15:04"),
-			comment.Author)
-		fmt.Fprintf(&b, "   %s\n\n", comment.Text)
+	for _, thread := range types.CommentThreads(comments) {
+		b.WriteString(s.formatComment(thread.Comment, thread.Depth))
 	}
 
 	return b.String()
 }
 
-// formatHealth formats health check result
-func (s *Server) formatHealth(health *rpc.HealthResponse) string {
+// formatComment formats one comment, indented by its depth in a thread
+func (s *Server) formatComment(comment *types.Comment, depth int) string {
+	indent := strings.Repeat("   ", depth)
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s#%d [%s] %s", indent, comment.ID, comment.CreatedAt.Format("2006-01-02 15:04"), comment.Author)
+	if comment.UpdatedAt != nil {
+		b.WriteString(" (edited)")
+	}
+	b.WriteString(":\n")
+	for _, line := range strings.Split(comment.Text, "\n") {
+		fmt.Fprintf(&b, "%s   %s\n", indent, line)
+	}
+	if reactions := types.FormatReactions(comment.Reactions); reactions != "" {
+		fmt.Fprintf(&b, "%s   %s\n", indent, reactions)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// formatCommentEdits formats a comment's earlier versions
+func (s *Server) formatCommentEdits(edits []*types.CommentEdit) string {
+	if len(edits) == 0 {
+		return "\nNever edited.\n"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n✏️  Edits (%d):\n\n", len(edits))
+	for _, edit := range edits {
+		fmt.Fprintf(&b, "[%s] %s replaced:\n", edit.EditedAt.Format("2006-01-02 15:04"), edit.EditedBy)
+		for _, line := range strings.Split(edit.Text, "\n") {
+			fmt.Fprintf(&b, "   %s\n", line)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func (s *Server) formatMentions(mentions []*types.Mention) string {
+	if len(mentions) == 0 {
+		return "\nNo mentions.\n"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n💬 Mentions of @%s (%d):\n\n", mentions[0].User, len(mentions))
+	for _, m := range mentions {
+		fmt.Fprintf(&b, "%s: %s\n", m.IssueID, m.IssueTitle)
+		b.WriteString(s.formatComment(m.Comment, 1))
+	}
+	return b.String()
+}
+
+// formatWatchers formats the users watching an issue
+func (s *Server) formatWatchers(result *watchersResult) string {
+	if len(result.Watchers) == 0 {
+		return fmt.Sprintf("\nNo one is watching %s.\n", result.IssueID)
+	}
+	return fmt.Sprintf("\n👀 Watching %s (%d): %s\n", result.IssueID, len(result.Watchers), strings.Join(result.Watchers, ", "))
+}
+
+// formatHealthReport formats GET /readyz, /healthz, and /health
+func (s *Server) formatHealthReport(report *HealthReport) string {
 	var b strings.Builder
 
 	status := "✓"
-	if health.Status != "healthy" {
+	if report.Status != healthHealthy {
 		status = "✗"
 	}
 
 	fmt.Fprintf(&b, "\n%s Health Check\n", status)
 	fmt.Fprintf(&b, "==============\n\n")
-	fmt.Fprintf(&b, "Status: %s\n", health.Status)
-	fmt.Fprintf(&b, "Version: %s\n", health.Version)
-	fmt.Fprintf(&b, "Compatible: %v\n", health.Compatible)
-	fmt.Fprintf(&b, "Database Response Time: %.2fms\n", health.DBResponseTime)
-	fmt.Fprintf(&b, "Uptime: %v\n", time.Duration(health.Uptime)*time.Second)
-	fmt.Fprintf(&b, "Active Connections: %d/%d\n", health.ActiveConns, health.MaxConns)
-	fmt.Fprintf(&b, "Memory: %d MB\n", health.MemoryAllocMB)
+	fmt.Fprintf(&b, "Status: %s\n", report.Status)
 
-	if health.Error != "" {
-		fmt.Fprintf(&b, "\nError: %s\n", health.Error)
+	names := make([]string, 0, len(report.Checks))
+	for name := range report.Checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		check := report.Checks[name]
+		fmt.Fprintf(&b, "  %s: %s", name, check.Status)
+		if check.LatencyMS > 0 {
+			fmt.Fprintf(&b, " (%.2fms)", check.LatencyMS)
+		}
+		if check.Message != "" {
+			fmt.Fprintf(&b, " - %s", check.Message)
+		}
+		fmt.Fprintf(&b, "\n")
 	}
 
 	return b.String()
@@ -313,6 +531,27 @@ func (s *Server) formatStatus(status *rpc.StatusResponse) string {
 	return b.String()
 }
 
+// formatServerStatus formats GET /status
+func (s *Server) formatServerStatus(status *serverStatus) string {
+	var b strings.Builder
+
+	b.WriteString(s.formatStatus(&status.StatusResponse))
+	if status.Backups == nil {
+		return b.String()
+	}
+	fmt.Fprintf(&b, "\nBackups: every %s, keeping %d\n", status.Backups.Interval, status.Backups.Retain)
+	if last := status.Backups.Last; last != nil {
+		fmt.Fprintf(&b, "  Last: %s (%d issue(s), %d bytes)\n", last.ID, last.Issues, last.Size)
+	} else {
+		fmt.Fprintf(&b, "  Last: none yet\n")
+	}
+	if status.Backups.LastError != "" {
+		fmt.Fprintf(&b, "  Last attempt failed: %s\n", status.Backups.LastError)
+	}
+	fmt.Fprintf(&b, "  Failures: %d\n", status.Backups.Failures)
+	return b.String()
+}
+
 // formatMetrics formats metrics result
 func (s *Server) formatMetrics(metrics *rpc.MetricsSnapshot) string {
 	var b strings.Builder
@@ -341,6 +580,70 @@ func (s *Server) formatMetrics(metrics *rpc.MetricsSnapshot) string {
 	return b.String()
 }
 
+// formatServerMetrics formats GET /metrics
+func (s *Server) formatServerMetrics(metrics *serverMetrics) string {
+	var b strings.Builder
+
+	b.WriteString(s.formatMetrics(&metrics.MetricsSnapshot))
+	if metrics.RateLimit == nil {
+		fmt.Fprintf(&b, "\nRate limiting: disabled\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "\nThrottled requests: %d (%d by the global limit)\n", metrics.RateLimit.Throttled, metrics.RateLimit.ThrottledGlobal)
+	clients := make([]string, 0, len(metrics.RateLimit.ByClient))
+	for client := range metrics.RateLimit.ByClient {
+		clients = append(clients, client)
+	}
+	sort.Strings(clients)
+	for _, client := range clients {
+		fmt.Fprintf(&b, "  %s: %d\n", client, metrics.RateLimit.ByClient[client])
+	}
+	return b.String()
+}
+
+// formatDrainStatus formats GET /admin/drain
+func (s *Server) formatDrainStatus(status *DrainStatus) string {
+	var b strings.Builder
+
+	if status.Draining {
+		fmt.Fprintf(&b, "Draining since %s\n", status.Since.Format(time.RFC3339))
+	} else {
+		fmt.Fprintf(&b, "Accepting requests\n")
+	}
+	fmt.Fprintf(&b, "Requests in flight: %d\n", status.Requests)
+	fmt.Fprintf(&b, "Connections: %d (%d idle)\n", status.Connections, status.Idle)
+	fmt.Fprintf(&b, "WebSockets: %d\n", status.WebSockets)
+	return b.String()
+}
+
+// formatBackup formats a new backup's manifest
+func (s *Server) formatBackup(m *backup.Manifest) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Backup %s: %d issue(s), %d bytes\n", m.ID, m.Issues, m.Size)
+	fmt.Fprintf(&b, "SHA-256: %s\n", m.SHA256)
+	if m.Uploaded != "" {
+		fmt.Fprintf(&b, "Uploaded to %s\n", m.Uploaded)
+	}
+	return b.String()
+}
+
+// formatReplicationState formats a replica's ID and vector clock
+func (s *Server) formatReplicationState(state *replication.State) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Replica: %s\n", state.Replica)
+	ids := make([]string, 0, len(state.Clock))
+	for id := range state.Clock {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		fmt.Fprintf(&b, "  %s: %d ops\n", id, state.Clock[id])
+	}
+	return b.String()
+}
+
 // formatCompactStats formats compaction statistics
 func (s *Server) formatCompactStats(stats *rpc.CompactStatsData) string {
 	var b strings.Builder
@@ -359,3 +662,882 @@ func (s *Server) formatCompactStats(stats *rpc.CompactStatsData) string {
 
 	return b.String()
 }
+
+// formatCompact formats the result of a compaction run
+func (s *Server) formatCompact(result *rpc.CompactResponse) string {
+	var b strings.Builder
+
+	if result.DryRun {
+		fmt.Fprintf(&b, "DRY RUN - nothing was changed\n\n")
+	}
+	if len(result.Purged) > 0 {
+		fmt.Fprintf(&b, "Purged from trash: %s\n\n", strings.Join(result.Purged, ", "))
+	}
+	if len(result.Results) == 0 {
+		fmt.Fprintf(&b, "No eligible candidates for compaction\n")
+		return b.String()
+	}
+
+	succeeded := 0
+	for _, r := range result.Results {
+		switch {
+		case r.Error != "":
+			fmt.Fprintf(&b, "✗ %s: %s\n", r.IssueID, r.Error)
+		case result.DryRun:
+			succeeded++
+			fmt.Fprintf(&b, "  %s: %d bytes\n", r.IssueID, r.OriginalSize)
+		default:
+			succeeded++
+			fmt.Fprintf(&b, "✓ %s: %d → %d bytes (%s)\n", r.IssueID, r.OriginalSize, r.CompactedSize, r.Reduction)
+		}
+	}
+	fmt.Fprintf(&b, "\n%d of %d compacted", succeeded, len(result.Results))
+	if result.DryRun {
+		fmt.Fprintf(&b, " (would be)")
+	}
+	if result.Duration != "" {
+		fmt.Fprintf(&b, " in %s", result.Duration)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// formatRedactions formats the secret redaction report
+func (s *Server) formatRedactions(redactions []*sqlite.SecretRedaction) string {
+	if len(redactions) == 0 {
+		return "No secrets detected\n"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Detected secrets (%d):\n\n", len(redactions))
+	for _, r := range redactions {
+		fmt.Fprintf(&b, "  %s  %s.%s  %s  %s  [%s by %s]\n",
+			r.CreatedAt.Format("2006-01-02 15:04"), r.IssueID, r.Field, r.Kind, r.Preview, r.Action, r.Actor)
+	}
+	return b.String()
+}
+
+// formatWebhooks formats registered webhooks
+func (s *Server) formatWebhooks(hooks []*sqlite.Webhook) string {
+	if len(hooks) == 0 {
+		return "No webhooks registered\n"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Webhooks (%d):\n\n", len(hooks))
+	for _, hook := range hooks {
+		events := "all events"
+		if len(hook.Events) > 0 {
+			events = strings.Join(hook.Events, ", ")
+		}
+		signed := ""
+		if hook.HasSecret {
+			signed = ", signed"
+		}
+		fmt.Fprintf(&b, "  %d  %s  (%s%s)\n", hook.ID, hook.URL, events, signed)
+	}
+	return b.String()
+}
+
+// formatAPIKeys formats a list of API keys
+func (s *Server) formatAPIKeys(keys []*sqlite.APIKey) string {
+	if len(keys) == 0 {
+		return "No API keys\n"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "API keys (%d):\n\n", len(keys))
+	for _, key := range keys {
+		used := "never used"
+		if key.LastUsedAt != nil {
+			used = "last used " + key.LastUsedAt.Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(&b, "  %d  %-8s %s  %s…  (%s)\n", key.ID, key.Role, key.Name, key.Hint, used)
+	}
+	return b.String()
+}
+
+// formatAuditLog formats a page of the change log
+func (s *Server) formatAuditLog(page *sqlite.AuditPage) string {
+	if len(page.Entries) == 0 {
+		return "No matching changes\n"
+	}
+
+	var b strings.Builder
+	for _, e := range page.Entries {
+		fmt.Fprintf(&b, "%s  %s  %-18s %s\n", e.CreatedAt.Format("2006-01-02 15:04"), e.IssueID, e.EventType, e.Actor)
+		for _, c := range e.Changes {
+			oldValue, _ := json.Marshal(c.Old)
+			newValue, _ := json.Marshal(c.New)
+			fmt.Fprintf(&b, "    %s: %s → %s\n", c.Field, oldValue, newValue)
+		}
+		if e.Comment != "" {
+			fmt.Fprintf(&b, "    %s\n", e.Comment)
+		}
+	}
+	if page.NextCursor != 0 {
+		fmt.Fprintf(&b, "\nNext page: ?cursor=%d\n", page.NextCursor)
+	}
+	return b.String()
+}
+
+// formatTrash formats the issues in the trash
+func (s *Server) formatTrash(issues []*types.Issue) string {
+	if len(issues) == 0 {
+		return "Trash is empty\n"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Trash (%d):\n\n", len(issues))
+	for _, issue := range issues {
+		fmt.Fprintf(&b, "  %s  %s\n", issue.ID, issue.Title)
+		if issue.DeletedAt != nil {
+			fmt.Fprintf(&b, "      deleted %s by %s\n", issue.DeletedAt.Format("2006-01-02 15:04"), issue.DeletedBy)
+		}
+	}
+	return b.String()
+}
+
+// formatHistory formats an issue's revisions as a timeline
+func (s *Server) formatHistory(history []*sqlite.IssueRevision) string {
+	if len(history) == 0 {
+		return "No history recorded\n"
+	}
+
+	var b strings.Builder
+	for _, rev := range history {
+		fmt.Fprintf(&b, "#%-3d %s  %-14s %s", rev.Revision, rev.CreatedAt.Format("2006-01-02 15:04"), rev.Kind, rev.Actor)
+		if rev.Reverts != 0 {
+			fmt.Fprintf(&b, " (undoes #%d)", rev.Reverts)
+		}
+		b.WriteString("\n")
+		for _, c := range rev.Changes {
+			oldValue, _ := json.Marshal(c.Old)
+			newValue, _ := json.Marshal(c.New)
+			fmt.Fprintf(&b, "     %s: %s → %s\n", c.Field, oldValue, newValue)
+		}
+		if rev.Comment != "" {
+			fmt.Fprintf(&b, "     %s\n", rev.Comment)
+		}
+	}
+	return b.String()
+}
+
+// formatCommits formats the commits linked to an issue
+func (s *Server) formatCommits(links []*sqlite.CommitLink) string {
+	if len(links) == 0 {
+		return "No commits linked\n"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Commits (%d):\n\n", len(links))
+	for _, link := range links {
+		commit := &git.Commit{SHA: link.SHA, Message: link.Message}
+		fmt.Fprintf(&b, "  %s  %s\n", commit.ShortSHA(), commit.Subject())
+		if link.URL != "" {
+			fmt.Fprintf(&b, "           %s\n", link.URL)
+		}
+	}
+	return b.String()
+}
+
+// formatCommitLink formats the result of reporting a commit
+func (s *Server) formatCommitLink(result *git.LinkResult) string {
+	commit := &git.Commit{SHA: result.SHA}
+	switch {
+	case result.Closed:
+		return fmt.Sprintf("Linked %s to %s and closed it\n", commit.ShortSHA(), result.IssueID)
+	case result.Linked:
+		return fmt.Sprintf("Linked %s to %s\n", commit.ShortSHA(), result.IssueID)
+	default:
+		return fmt.Sprintf("%s was already linked to %s\n", commit.ShortSHA(), result.IssueID)
+	}
+}
+
+// formatCriticalPath formats the longest chain of unfinished work under an epic
+func (s *Server) formatCriticalPath(path *types.CriticalPath) string {
+	if len(path.Issues) == 0 {
+		return fmt.Sprintf("No unfinished work under %s\n", path.EpicID)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "\nCritical path for %s (%d issue(s), %s estimated):\n\n",
+		path.EpicID, len(path.Issues), formatMinutes(path.TotalMinutes))
+	for i, issue := range path.Issues {
+		estimate := "unestimated"
+		if issue.EstimatedMinutes != nil {
+			estimate = formatMinutes(*issue.EstimatedMinutes)
+		}
+		fmt.Fprintf(&b, "  %d. %s [P%d] %s (%s, %s)\n", i+1, issue.ID, issue.Priority, issue.Title, issue.Status, estimate)
+	}
+	if path.Unestimated > 0 {
+		fmt.Fprintf(&b, "\n%d issue(s) on the path have no estimate\n", path.Unestimated)
+	}
+	return b.String()
+}
+
+// formatBlockingImpact formats the issues an issue holds up, indented by distance
+func (s *Server) formatBlockingImpact(impact *types.BlockingImpact) string {
+	if impact.Total == 0 {
+		return fmt.Sprintf("%s blocks no open issues\n", impact.Issue.ID)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n%s blocks %d open issue(s), %d directly; highest priority P%d:\n\n",
+		impact.Issue.ID, impact.Total, impact.Direct, *impact.HighestPriority)
+	for _, blocked := range impact.Blocked {
+		via := ""
+		if blocked.Depth > 1 {
+			via = " via " + blocked.BlockedBy
+			if blocked.Inherited {
+				via = " (child of " + blocked.BlockedBy + ")"
+			}
+		}
+		fmt.Fprintf(&b, "  %s%s [P%d] %s (%s)%s\n", strings.Repeat("  ", blocked.Depth-1),
+			blocked.Issue.ID, blocked.Issue.Priority, blocked.Issue.Title, blocked.Issue.Status, via)
+	}
+	return b.String()
+}
+
+// formatBlockers formats the open issues holding up an issue as a tree, each
+// blocker under the issue it holds up
+func (s *Server) formatBlockers(tree *types.BlockerTree) string {
+	if tree.Total == 0 {
+		return fmt.Sprintf("%s is not blocked\n", tree.Issue.ID)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n%s is blocked by %d open issue(s):\n\n", tree.Issue.ID, tree.Total)
+	var walk func(issueID string)
+	walk = func(issueID string) {
+		for _, blocker := range tree.Children(issueID) {
+			owner := blocker.Issue.Assignee
+			if owner == "" {
+				owner = "unassigned"
+			}
+			via := ""
+			if blocker.Via != "" {
+				via = " via ancestor " + blocker.Via
+			}
+			fmt.Fprintf(&b, "  %s%s [P%d] %s (%s, %s)%s\n", strings.Repeat("  ", blocker.Depth-1),
+				blocker.Issue.ID, blocker.Issue.Priority, blocker.Issue.Title, blocker.Issue.Status, owner, via)
+			walk(blocker.Issue.ID)
+		}
+	}
+	walk(tree.Issue.ID)
+	return b.String()
+}
+
+// formatMinutes renders a duration in minutes as "1h 30m" or "45m"
+func formatMinutes(minutes int) string {
+	if minutes >= 60 {
+		return fmt.Sprintf("%dh %dm", minutes/60, minutes%60)
+	}
+	return fmt.Sprintf("%dm", minutes)
+}
+
+// formatBulkUpdate formats the result of a bulk update
+func (s *Server) formatBulkUpdate(result *bulkUpdateResult) string {
+	if result.Count == 0 {
+		return "No issues matched\n"
+	}
+	return fmt.Sprintf("Updated %d issue(s): %s\n", result.Count, strings.Join(result.Updated, ", "))
+}
+
+// formatSearch formats full-text search results with their snippets
+func (s *Server) formatSearch(hits []*sqlite.SearchHit) string {
+	if len(hits) == 0 {
+		return "No matches found.\n"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "\nFound %d match(es):\n\n", len(hits))
+	for _, hit := range hits {
+		fmt.Fprintf(&b, "%s [P%d] %s %s\n", hit.Issue.ID, hit.Issue.Priority, hit.Issue.Status, hit.Issue.Title)
+		if hit.Snippet != "" {
+			fmt.Fprintf(&b, "  %s\n", hit.Snippet)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// formatImport formats an import result
+func (s *Server) formatImport(result *importer.MergeResult) string {
+	var b strings.Builder
+	if result.DryRun {
+		fmt.Fprintf(&b, "Import preview (strategy %s):\n", result.Strategy)
+	} else {
+		fmt.Fprintf(&b, "Import complete (strategy %s):\n", result.Strategy)
+	}
+	fmt.Fprintf(&b, "  Created:   %d\n", result.Created)
+	fmt.Fprintf(&b, "  Updated:   %d\n", result.Updated)
+	fmt.Fprintf(&b, "  Unchanged: %d\n", result.Unchanged)
+	fmt.Fprintf(&b, "  Skipped:   %d\n", result.Skipped)
+
+	var details []string
+	for _, c := range result.Changes {
+		if c.Action == importer.ActionUnchanged {
+			continue
+		}
+		line := fmt.Sprintf("  %-9s %s", c.Action, c.ID)
+		if len(c.Fields) > 0 {
+			line += " (" + strings.Join(c.Fields, ", ") + ")"
+		}
+		if c.Reason != "" {
+			line += ": " + c.Reason
+		}
+		details = append(details, line)
+	}
+	if len(details) > 0 {
+		b.WriteString("\n")
+		b.WriteString(strings.Join(details, "\n"))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// formatMilestones formats milestones with their progress
+func (s *Server) formatMilestones(milestones []*sqlite.Milestone) string {
+	if len(milestones) == 0 {
+		return "No milestones\n"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Milestones (%d):\n\n", len(milestones))
+	now := time.Now()
+	for _, m := range milestones {
+		active := ""
+		if m.Active(now) {
+			active = " (active)"
+		}
+		fmt.Fprintf(&b, "  %s  %s to %s%s\n", m.Name, m.StartDate.Local().Format("2006-01-02"), m.EndDate.Local().Format("2006-01-02"), active)
+		fmt.Fprintf(&b, "    %s\n", milestoneProgress(m))
+	}
+	return b.String()
+}
+
+// formatActors formats registered actors with their aliases
+func (s *Server) formatActors(actors []*sqlite.Actor) string {
+	if len(actors) == 0 {
+		return "No actors registered\n"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Actors (%d):\n\n", len(actors))
+	for _, a := range actors {
+		fmt.Fprintf(&b, "  %s (%s)", a.Name, a.Type)
+		if a.Email != "" {
+			fmt.Fprintf(&b, " <%s>", a.Email)
+		}
+		b.WriteString("\n")
+		if len(a.Aliases) > 0 {
+			fmt.Fprintf(&b, "    aka %s\n", strings.Join(a.Aliases, ", "))
+		}
+	}
+	return b.String()
+}
+
+// formatActorMerge formats what merging one actor into another rewrote
+func (s *Server) formatActorMerge(merge *sqlite.ActorMerge) string {
+	verb := "Merged"
+	if merge.DryRun {
+		verb = "Would merge"
+	}
+	return fmt.Sprintf("%s %s into %s: %d records on %d issues\n", verb, merge.From, merge.Into, merge.Records, len(merge.IssuesAffected))
+}
+
+// formatSessions formats agent sessions
+func (s *Server) formatSessions(sessions []*sqlite.AgentSession) string {
+	if len(sessions) == 0 {
+		return "No sessions\n"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Sessions (%d):\n\n", len(sessions))
+	for _, session := range sessions {
+		state := "running"
+		if session.EndedAt != nil {
+			state = "ended " + session.EndedAt.Local().Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(&b, "  %s  %s  started %s, %s, %d changes\n", session.ID, session.Actor,
+			session.StartedAt.Local().Format("2006-01-02 15:04"), state, session.Changes)
+		var about []string
+		for _, field := range [][2]string{{"model", session.Model}, {"tool", session.Tool}, {"run", session.RunID}} {
+			if field[1] != "" {
+				about = append(about, field[0]+" "+field[1])
+			}
+		}
+		if len(about) > 0 {
+			fmt.Fprintf(&b, "    %s\n", strings.Join(about, ", "))
+		}
+	}
+	return b.String()
+}
+
+// formatSessionActivity formats a session and the changes made in it
+func (s *Server) formatSessionActivity(activity *sqlite.SessionActivity) string {
+	var b strings.Builder
+	b.WriteString(s.formatSessions([]*sqlite.AgentSession{activity.Session}))
+	b.WriteString("\n")
+	b.WriteString(s.formatAuditLog(&sqlite.AuditPage{Entries: activity.Entries, NextCursor: activity.NextCursor}))
+	return b.String()
+}
+
+// formatRules formats triage rules in the order they run
+func (s *Server) formatRules(rules []*sqlite.TriageRule) string {
+	if len(rules) == 0 {
+		return "No triage rules\n"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Triage rules (%d):\n\n", len(rules))
+	for _, rule := range rules {
+		fmt.Fprintf(&b, "  %s\n    %s\n", rule.Name, rule.Describe())
+	}
+	return b.String()
+}
+
+// formatRuleTest formats a dry run of the triage rules
+func (s *Server) formatRuleTest(result *sqlite.TriageResult) string {
+	if len(result.Matched) == 0 {
+		return "No rules match\n"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Matched: %s\n", strings.Join(result.Matched, ", "))
+	if !result.Changed() {
+		b.WriteString("No changes\n")
+		return b.String()
+	}
+	if len(result.AddLabels) > 0 {
+		fmt.Fprintf(&b, "  add labels %s\n", strings.Join(result.AddLabels, ", "))
+	}
+	if result.Priority != nil {
+		fmt.Fprintf(&b, "  set priority P%d\n", *result.Priority)
+	}
+	if result.Assignee != "" {
+		fmt.Fprintf(&b, "  assign %s\n", result.Assignee)
+	}
+	return b.String()
+}
+
+// formatWorkflow formats the workflow's statuses and transitions, or just
+// the moves from one status
+func (s *Server) formatWorkflow(workflow *workflowResponse) string {
+	var b strings.Builder
+	if workflow.From != "" {
+		if len(workflow.Next) == 0 {
+			return fmt.Sprintf("No moves from %s\n", workflow.From)
+		}
+		fmt.Fprintf(&b, "From %s:\n", workflow.From)
+		for _, t := range workflow.Next {
+			b.WriteString("  " + formatTransition(t) + "\n")
+		}
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "Statuses (%d):\n", len(workflow.Statuses))
+	for _, status := range workflow.Statuses {
+		fmt.Fprintf(&b, "  %s", status.Name)
+		if status.Category != "" && status.Category != status.Name {
+			fmt.Fprintf(&b, " (counts as %s)", status.Category)
+		}
+		if status.Description != "" {
+			fmt.Fprintf(&b, " - %s", status.Description)
+		}
+		b.WriteString("\n")
+	}
+	if len(workflow.Transitions) == 0 {
+		b.WriteString("\nAny status can move to any other\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "\nTransitions (%d):\n", len(workflow.Transitions))
+	for _, t := range workflow.Transitions {
+		b.WriteString("  " + formatTransition(t) + "\n")
+	}
+	return b.String()
+}
+
+// formatTransition formats a transition, e.g. "in_review -> closed (requires reason)"
+func formatTransition(t types.WorkflowTransition) string {
+	line := fmt.Sprintf("%s -> %s", t.From, t.To)
+	if len(t.Require) > 0 {
+		line += " (requires " + strings.Join(t.Require, ", ") + ")"
+	}
+	return line
+}
+
+// formatMilestoneDetail formats a milestone and its issues
+func (s *Server) formatMilestoneDetail(detail *milestoneDetail) string {
+	var b strings.Builder
+	b.WriteString(s.formatMilestones([]*sqlite.Milestone{&detail.Milestone}))
+	if len(detail.IssueList) == 0 {
+		b.WriteString("\nNo issues assigned\n")
+		return b.String()
+	}
+	b.WriteString("\n")
+	for _, issue := range detail.IssueList {
+		estimate := "unestimated"
+		if issue.EstimatedMinutes != nil {
+			estimate = formatMinutes(*issue.EstimatedMinutes)
+		}
+		fmt.Fprintf(&b, "  %s [P%d] %s (%s, %s)\n", issue.ID, issue.Priority, issue.Title, issue.Status, estimate)
+	}
+	return b.String()
+}
+
+// milestoneProgress summarizes committed and completed work against capacity
+func milestoneProgress(m *sqlite.Milestone) string {
+	capacity := "no capacity set"
+	if m.CapacityMinutes > 0 {
+		capacity = formatMinutes(m.CapacityMinutes) + " capacity"
+	}
+	progress := fmt.Sprintf("%d/%d issue(s) closed · %s committed, %s completed of %s",
+		m.ClosedIssues, m.Issues, formatMinutes(m.CommittedMinutes), formatMinutes(m.CompletedMinutes), capacity)
+	if m.OverCapacity() {
+		progress += fmt.Sprintf(" · over by %s", formatMinutes(m.CommittedMinutes-m.CapacityMinutes))
+	}
+	if m.Unestimated > 0 {
+		progress += fmt.Sprintf(" · %d unestimated", m.Unestimated)
+	}
+	return progress
+}
+
+// formatWorkLogs formats the time logged on an issue
+func (s *Server) formatWorkLogs(logs []*sqlite.WorkLog) string {
+	if len(logs) == 0 {
+		return "No time logged\n"
+	}
+
+	var b strings.Builder
+	total := 0
+	for _, log := range logs {
+		total += log.Minutes
+		fmt.Fprintf(&b, "  [%s] %s: %s", log.LoggedAt.Format("2006-01-02 15:04"), log.Actor, formatMinutes(log.Minutes))
+		if log.Note != "" {
+			fmt.Fprintf(&b, " (%s)", log.Note)
+		}
+		b.WriteString("\n")
+	}
+	return fmt.Sprintf("Time logged on %s: %s\n\n", logs[0].IssueID, formatMinutes(total)) + b.String()
+}
+
+// formatStaleIssues formats in-progress issues that have gone quiet
+func (s *Server) formatStaleIssues(issues []*stale.Issue) string {
+	if len(issues) == 0 {
+		return "No stale issues\n"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Stale issues (%d):\n\n", len(issues))
+	for _, issue := range issues {
+		fmt.Fprintf(&b, "  %s  [P%d] %s\n", issue.ID, issue.Priority, issue.Title)
+		fmt.Fprintf(&b, "      %s, no updates in %d days\n", assigneeLabel(issue.Assignee), issue.IdleDays)
+	}
+	return b.String()
+}
+
+// formatDuplicates formats duplicate groups with their suggested merges
+func (s *Server) formatDuplicates(groups []*types.DuplicateGroup) string {
+	if len(groups) == 0 {
+		return "No duplicates found\n"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Found %d duplicate group(s):\n", len(groups))
+	for i, group := range groups {
+		fmt.Fprintf(&b, "\nGroup %d: %s\n", i+1, group.Title)
+		for _, issue := range group.Issues {
+			marker := "  "
+			if issue.IsMergeTarget {
+				marker = "→ "
+			}
+			similarity := ""
+			if issue.Score < 1 {
+				similarity = fmt.Sprintf(", %.0f%% similar", issue.Score*100)
+			}
+			fmt.Fprintf(&b, "  %s%s (%s, P%d, %d references%s)\n", marker, issue.ID, issue.Status, issue.Priority, issue.References, similarity)
+		}
+		fmt.Fprintf(&b, "  Suggested: %s\n", group.SuggestedCommand)
+	}
+	return b.String()
+}
+
+// formatMergeResults formats what each merge changed
+func (s *Server) formatMergeResults(results []*types.MergeResult) string {
+	if len(results) == 0 {
+		return "Nothing to merge\n"
+	}
+
+	var b strings.Builder
+	for _, r := range results {
+		fmt.Fprintf(&b, "Merged %s into %s\n", strings.Join(r.SourceIDs, ", "), r.TargetID)
+		fmt.Fprintf(&b, "  Dependencies: %d migrated, %d already existed\n", r.DependenciesAdded, r.DependenciesSkipped)
+		fmt.Fprintf(&b, "  Comments: %d moved, labels: %d moved\n", r.CommentsMoved, r.LabelsMoved)
+		fmt.Fprintf(&b, "  Text references: %d updated\n", r.TextReferences)
+		fmt.Fprintf(&b, "  Source issues: %d closed, %d already closed\n", r.IssuesClosed, r.IssuesSkipped)
+	}
+	return b.String()
+}
+
+// formatReadyQueues formats ready work grouped by assignee
+func (s *Server) formatReadyQueues(queues []*types.ReadyQueue) string {
+	if len(queues) == 0 {
+		return "\nNo ready work found.\n"
+	}
+
+	var b strings.Builder
+	for _, q := range queues {
+		name := "@" + q.Assignee
+		if q.Assignee == "" {
+			name = "Unassigned"
+		}
+		fmt.Fprintf(&b, "\n%s (%d):\n", name, len(q.Issues))
+		for i, issue := range q.Issues {
+			fmt.Fprintf(&b, "%d. %s [P%d]: %s%s\n", i+1, issue.ID, issue.Priority, issue.Title, dueSuffix(issue))
+		}
+	}
+	return b.String()
+}
+
+// formatClaim formats a claimed issue and its lease
+func (s *Server) formatClaim(result *claimResult) string {
+	return fmt.Sprintf("Claimed %s: %s\nHeld by %s until %s\n",
+		result.Issue.ID, result.Issue.Title, result.Lease.Holder, result.Lease.ExpiresAt.Format(time.RFC3339))
+}
+
+// formatClaims formats the live leases
+func (s *Server) formatClaims(leases []*sqlite.Lease) string {
+	if len(leases) == 0 {
+		return "No claimed issues\n"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Claimed issues (%d):\n\n", len(leases))
+	for _, lease := range leases {
+		fmt.Fprintf(&b, "  %s  %s until %s\n", lease.IssueID, lease.Holder, lease.ExpiresAt.Format(time.RFC3339))
+	}
+	return b.String()
+}
+
+// formatWorkload formats unfinished work per assignee
+func (s *Server) formatWorkload(workload []*types.AssigneeWorkload) string {
+	if len(workload) == 0 {
+		return "No assigned work\n"
+	}
+
+	var b strings.Builder
+	for _, load := range workload {
+		fmt.Fprintf(&b, "  %s: %s\n", load.Assignee, workloadSummary(load))
+	}
+	return fmt.Sprintf("Workload (%d assignees):\n\n", len(workload)) + b.String()
+}
+
+// formatAssigneeSuggestions formats candidate assignees, best first
+func (s *Server) formatAssigneeSuggestions(suggestions []*types.AssigneeSuggestion) string {
+	if len(suggestions) == 0 {
+		return "No eligible assignees\n"
+	}
+
+	var b strings.Builder
+	for i, suggestion := range suggestions {
+		fmt.Fprintf(&b, "%d. %s: %s", i+1, suggestion.Assignee, workloadSummary(&suggestion.AssigneeWorkload))
+		if suggestion.MatchingIssues > 0 {
+			fmt.Fprintf(&b, " · %d matching issue(s)", suggestion.MatchingIssues)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// workloadSummary describes one assignee's unfinished work in a line
+func workloadSummary(load *types.AssigneeWorkload) string {
+	summary := fmt.Sprintf("%d open, %d in progress", load.Open, load.InProgress)
+	if load.Blocked > 0 {
+		summary += fmt.Sprintf(", %d blocked", load.Blocked)
+	}
+	summary += " · " + formatMinutes(load.EstimatedMinutes) + " estimated"
+	if load.Unestimated > 0 {
+		summary += fmt.Sprintf(" · %d unestimated", load.Unestimated)
+	}
+	return summary
+}
+
+// formatLabels formats labels with their usage
+func (s *Server) formatLabels(labels []*sqlite.Label) string {
+	if len(labels) == 0 {
+		return "No labels\n"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Labels (%d):\n\n", len(labels))
+	for _, label := range labels {
+		fmt.Fprintf(&b, "  %s  %d issue(s)", label.Name, label.Issues)
+		if label.Color != "" {
+			fmt.Fprintf(&b, "  %s", label.Color)
+		}
+		// Path-style names already show their parent
+		if label.Parent != "" && label.Parent != types.LabelPathParent(label.Name) {
+			fmt.Fprintf(&b, "  (under %s)", label.Parent)
+		}
+		if label.Description != "" {
+			fmt.Fprintf(&b, "  %s", label.Description)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// formatLabel formats one label's metadata and children
+func (s *Server) formatLabel(label *sqlite.Label) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Label: %s\n", label.Name)
+	if !label.Defined {
+		b.WriteString("Not defined (in use only)\n")
+	}
+	if label.Description != "" {
+		fmt.Fprintf(&b, "Description: %s\n", label.Description)
+	}
+	if label.Color != "" {
+		fmt.Fprintf(&b, "Color: %s\n", label.Color)
+	}
+	if label.Parent != "" {
+		fmt.Fprintf(&b, "Parent: %s\n", label.Parent)
+	}
+	if len(label.Children) > 0 {
+		fmt.Fprintf(&b, "Children: %s\n", strings.Join(label.Children, ", "))
+	}
+	fmt.Fprintf(&b, "Issues: %d\n", label.Issues)
+	return b.String()
+}
+
+// formatWorkspaces formats the workspaces a caller can read
+func (s *Server) formatWorkspaces(infos []WorkspaceInfo) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Workspaces (%d):\n\n", len(infos))
+	for _, info := range infos {
+		fmt.Fprintf(&b, "  %s  %s\n", info.Name, info.Path)
+		if stats := info.Statistics; stats != nil {
+			fmt.Fprintf(&b, "    %d open, %d in progress, %d blocked, %d closed\n",
+				stats.OpenIssues, stats.InProgressIssues, stats.BlockedIssues, stats.ClosedIssues)
+		}
+	}
+	return b.String()
+}
+
+// formatWorkspaceIssues formats issues from several workspaces, each
+// workspace under its own heading
+func (s *Server) formatWorkspaceIssues(issues []workspaceIssue) string {
+	if len(issues) == 0 {
+		return "No issues found.\n"
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(issues); {
+		j := i
+		var group []*types.Issue
+		for ; j < len(issues) && issues[j].Workspace == issues[i].Workspace; j++ {
+			group = append(group, issues[j].Issue)
+		}
+		fmt.Fprintf(&b, "== %s ==\n", issues[i].Workspace)
+		b.WriteString(s.formatIssueList(group))
+		i = j
+	}
+	return b.String()
+}
+
+// formatIssueLabels formats an issue's labels
+func (s *Server) formatIssueLabels(labels []string) string {
+	if len(labels) == 0 {
+		return "\nNo labels.\n"
+	}
+	return fmt.Sprintf("\n🏷  Labels (%d): %s\n", len(labels), strings.Join(labels, ", "))
+}
+
+// formatDependencies formats dependency records
+func (s *Server) formatDependencies(deps []*types.Dependency) string {
+	if len(deps) == 0 {
+		return "\nNo dependencies found.\n"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "\nDependencies (%d):\n\n", len(deps))
+	for _, dep := range deps {
+		fmt.Fprintf(&b, "  %s depends on %s (%s)\n", dep.IssueID, dep.DependsOnID, dep.Type)
+	}
+	return b.String()
+}
+
+// formatCycles formats dependency cycles, each as the chain of issues
+// that closes it
+func (s *Server) formatCycles(cycles [][]*types.Issue) string {
+	if len(cycles) == 0 {
+		return "\n✓ No dependency cycles.\n"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n⚠ Dependency cycles (%d):\n\n", len(cycles))
+	for i, cycle := range cycles {
+		ids := make([]string, len(cycle), len(cycle)+1)
+		for j, issue := range cycle {
+			ids[j] = issue.ID
+		}
+		if len(cycle) > 0 {
+			ids = append(ids, cycle[0].ID)
+		}
+		fmt.Fprintf(&b, "%d. %s\n", i+1, strings.Join(ids, " → "))
+	}
+	return b.String()
+}
+
+// formatBlockedIssues formats blocked issues with what blocks them
+func (s *Server) formatBlockedIssues(blocked []*types.BlockedIssue) string {
+	if len(blocked) == 0 {
+		return "\nNo blocked issues.\n"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n🚫 Blocked issues (%d):\n\n", len(blocked))
+	for _, issue := range blocked {
+		fmt.Fprintf(&b, "%s [P%d]: %s\n", issue.ID, issue.Priority, issue.Title)
+		fmt.Fprintf(&b, "  Blocked by %d open dependencies: %s\n\n", issue.BlockedByCount, strings.Join(issue.BlockedBy, ", "))
+	}
+	return b.String()
+}
+
+// formatEpics formats epics with their closed children
+func (s *Server) formatEpics(epics []*types.EpicStatus) string {
+	if len(epics) == 0 {
+		return "\nNo epics found.\n"
+	}
+
+	var b strings.Builder
+	for _, epic := range epics {
+		eligible := ""
+		if epic.EligibleForClose {
+			eligible = " [ready to close]"
+		}
+		fmt.Fprintf(&b, "%s: %s (%d/%d children closed)%s\n", epic.Epic.ID, epic.Epic.Title, epic.ClosedChildren, epic.TotalChildren, eligible)
+	}
+	return b.String()
+}
+
+// formatConfig formats config values in key order
+func (s *Server) formatConfig(config map[string]string) string {
+	if len(config) == 0 {
+		return "\nNo config set.\n"
+	}
+
+	keys := make([]string, 0, len(config))
+	for key := range config {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		fmt.Fprintf(&b, "%s = %s\n", key, config[key])
+	}
+	return b.String()
+}
@@ -1,99 +1,29 @@
 package http
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/imalsogreg/beads/internal/depgraph"
+	"github.com/imalsogreg/beads/internal/digest"
+	"github.com/imalsogreg/beads/internal/importer"
+	"github.com/imalsogreg/beads/internal/markdown"
 	"github.com/imalsogreg/beads/internal/rpc"
+	"github.com/imalsogreg/beads/internal/signing"
+	"github.com/imalsogreg/beads/internal/storage"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
 	"github.com/imalsogreg/beads/internal/types"
 )
 
-// handleDocs serves API documentation at /
-func (s *Server) handleDocs(w http.ResponseWriter, r *http.Request) {
-	docs := `BEADS REST API
-
-Base URL: /
-
-AUTHENTICATION
-  All requests (except GET /) require Bearer token authentication.
-
-  To authenticate:
-    1. Get the API secret from your team
-    2. Set environment variable: export BEADS_API_SECRET=<secret>
-    3. Include header in all requests:
-       Authorization: Bearer $BEADS_API_SECRET
-
-  Example:
-    curl -H "Authorization: Bearer your-secret-token" \
-         http://api.example.com/issues
-
-  For agents/scripts:
-    - Read token from environment variable: BEADS_API_SECRET
-    - Send it in Authorization header: Authorization: Bearer <token>
-
-  Actor tracking (optional):
-    Include actor name for audit trail via:
-    - Header: X-Actor: username
-    - Query param: ?actor=username
-    - Default: "http-user"
-
-CONTENT NEGOTIATION
-  - Accept: application/json → JSON response
-  - Accept: text/plain → Human-readable text (default)
-
-CORE ENDPOINTS
-
-  GET  /health                        Health check
-  GET  /ping                          Ping server
-
-  POST /issues                        Create issue
-       Body: {"title": "...", "description": "...", "issue_type": "task",
-              "priority": 0, "assignee": "..."}
-
-  GET  /issues                        List issues
-       Query params: status, priority, assignee, type, label, limit
-
-  GET  /issues/{id}                   Show issue details
-
-  PATCH /issues/{id}                  Update issue
-        Body: {"title": "...", "status": "...", "priority": 0, ...}
-
-  GET  /issues/stats                  Database statistics
-
-CONFIGURATION
-  GET  /config/{key}                  Get config value (e.g., issue_prefix)
-  PUT  /config/{key}                  Set config value
-       Body: {"value": "..."}
-
-EXAMPLES
-
-  Get current prefix:
-    curl -H "Authorization: Bearer $BEADS_API_SECRET" \
-      http://localhost:8080/config/issue_prefix
-
-  Create an issue:
-    curl -X POST http://localhost:8080/issues \
-      -H "Content-Type: application/json" \
-      -H "Authorization: Bearer $BEADS_API_SECRET" \
-      -H "X-Actor: alice" \
-      -d '{"title":"Fix login bug","issue_type":"bug","priority":0}'
-
-  List open issues (JSON):
-    curl -H "Accept: application/json" \
-      -H "Authorization: Bearer $BEADS_API_SECRET" \
-      "http://localhost:8080/issues?status=open"
-
-  Show issue details (text):
-    curl -H "Authorization: Bearer $BEADS_API_SECRET" \
-      http://localhost:8080/issues/bd-1
-`
-	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(docs))
-}
-
 // handlePing handles ping requests
 func (s *Server) handlePing(w http.ResponseWriter, r *http.Request) {
 	result := map[string]string{
@@ -103,45 +33,76 @@ func (s *Server) handlePing(w http.ResponseWriter, r *http.Request) {
 	s.writeSuccess(w, r, result, "ping")
 }
 
-// handleHealth handles health check requests
-func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+// handleStats handles GET /issues/stats, with ?group_by= for counts by a
+// dimension and status
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	// Simple health check - try to get stats from DB
-	_, err := s.storage.GetStatistics(ctx)
-
-	health := map[string]interface{}{
-		"status":  "healthy",
-		"message": "OK",
+	groupBy := r.URL.Query().Get("group_by")
+	if groupBy != "" && !types.IsValidStatGroup(groupBy) {
+		s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("invalid group_by %q (valid: %s)", groupBy, strings.Join(types.StatGroupDimensions, ", ")))
+		return
 	}
 
+	stats, err := cachedQuery(ctx, s, cacheKey("stats", groupBy), func() (*types.Statistics, error) {
+		stats, err := s.storage.GetStatistics(ctx)
+		if err != nil || groupBy == "" {
+			return stats, err
+		}
+		if stats.Groups, err = s.storage.GetGroupedStatistics(ctx, groupBy); err != nil {
+			return nil, err
+		}
+		stats.GroupBy = groupBy
+		return stats, nil
+	})
 	if err != nil {
-		health["status"] = "unhealthy"
-		health["message"] = err.Error()
-		s.writeError(w, r, http.StatusServiceUnavailable, err)
+		s.writeStoreError(w, r, err)
 		return
 	}
 
-	s.writeSuccess(w, r, health, "health")
+	s.writeSuccess(w, r, stats, rpc.OpStats)
 }
 
-// handleStats handles GET /issues/stats
-func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+// handleCreateIssue handles POST /issues
+func (s *Server) handleCreateIssue(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	actor := s.getActor(r)
 
-	stats, err := s.storage.GetStatistics(ctx)
+	var args rpc.CreateArgs
+	if err := s.parseBody(r, &args); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	// Create the issue
+	issue, err := issueFromCreateArgs(&args)
 	if err != nil {
-		s.writeError(w, r, http.StatusInternalServerError, err)
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if err := s.storage.CreateIssue(ctx, issue, actor); err != nil {
+		s.writeStoreError(w, r, err)
 		return
 	}
 
-	s.writeSuccess(w, r, stats, rpc.OpStats)
+	s.writeSuccess(w, r, issue, rpc.OpCreate)
 }
 
-// handleCreateIssue handles POST /issues
-func (s *Server) handleCreateIssue(w http.ResponseWriter, r *http.Request) {
+// handleCreateSubtask handles POST /issues/{id}/subtasks
+func (s *Server) handleCreateSubtask(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	actor := s.getActor(r)
+	vars := mux.Vars(r)
+
+	parent, err := s.storage.GetIssue(ctx, vars["id"])
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if parent == nil {
+		s.writeError(w, r, http.StatusNotFound, types.NotFound("issue", vars["id"]))
+		return
+	}
 
 	var args rpc.CreateArgs
 	if err := s.parseBody(r, &args); err != nil {
@@ -149,7 +110,50 @@ func (s *Server) handleCreateIssue(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Convert args to Issue
+	issue, err := issueFromCreateArgs(&args)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	issue.ParentID = parent.ID
+	if err := s.storage.CreateIssue(ctx, issue, actor); err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.writeSuccess(w, r, issue, rpc.OpCreate)
+}
+
+// handleListChildren handles GET /issues/{id}/children
+func (s *Server) handleListChildren(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+
+	parent, err := s.storage.GetIssue(ctx, vars["id"])
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if parent == nil {
+		s.writeError(w, r, http.StatusNotFound, types.NotFound("issue", vars["id"]))
+		return
+	}
+
+	children, err := s.storage.GetChildren(ctx, parent.ID)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if children == nil {
+		children = []*types.Issue{}
+	}
+
+	s.writeSuccess(w, r, children, rpc.OpList)
+}
+
+// issueFromCreateArgs converts create args to a new open issue. It fails
+// only on a malformed date.
+func issueFromCreateArgs(args *rpc.CreateArgs) (*types.Issue, error) {
 	issue := &types.Issue{
 		ID:                 args.ID,
 		Title:              args.Title,
@@ -165,13 +169,32 @@ func (s *Server) handleCreateIssue(w http.ResponseWriter, r *http.Request) {
 		issue.Assignee = args.Assignee
 	}
 
-	// Create the issue
-	if err := s.storage.CreateIssue(ctx, issue, actor); err != nil {
-		s.writeError(w, r, http.StatusInternalServerError, err)
-		return
+	var err error
+	now := time.Now()
+	if issue.DueDate, err = types.ParseOptionalDate(args.DueDate, now); err != nil {
+		return nil, fmt.Errorf("due_date: %w", err)
+	}
+	if issue.StartDate, err = types.ParseOptionalDate(args.StartDate, now); err != nil {
+		return nil, fmt.Errorf("start_date: %w", err)
 	}
 
-	s.writeSuccess(w, r, issue, rpc.OpCreate)
+	return issue, nil
+}
+
+// subtaskProgress rolls up the status of an issue's direct children
+func subtaskProgress(children []*types.Issue) *types.SubtaskProgress {
+	progress := &types.SubtaskProgress{Total: len(children)}
+	for _, child := range children {
+		switch child.Status {
+		case types.StatusClosed:
+			progress.Closed++
+		case types.StatusInProgress:
+			progress.InProgress++
+		case types.StatusBlocked:
+			progress.Blocked++
+		}
+	}
+	return progress
 }
 
 // handleListIssues handles GET /issues
@@ -180,41 +203,267 @@ func (s *Server) handleListIssues(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query()
 
 	// Build filter from query params
+	workflow, err := s.workflow(ctx)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	filter, err := issueFilterFromQuery(query, workflow)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	filter.WithCounts = true
+	fields, err := parseIssueFields(query.Get("fields"))
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	expand, ok := s.requestedExpansions(w, r)
+	if !ok {
+		return
+	}
+	// Field selection and expansions only change JSON; text and markdown
+	// keep their layout
+	respond := func(issues []*types.Issue) {
+		if wantsNDJSON(r) {
+			s.streamIssues(w, r, fields, expand, func(fn func(*types.Issue) error) error {
+				for _, issue := range issues {
+					if err := fn(issue); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			return
+		}
+		if len(expand) > 0 && s.wantsJSON(r) {
+			relations, err := s.expandRelations(ctx, issues, expand)
+			if err != nil {
+				s.writeStoreError(w, r, err)
+				return
+			}
+			s.writeSuccess(w, r, expandIssues(issues, fields, relations), rpc.OpList)
+			return
+		}
+		if len(fields) > 0 && s.wantsJSON(r) {
+			s.writeSuccess(w, r, selectIssueFields(issues, fields), rpc.OpList)
+			return
+		}
+		s.writeSuccess(w, r, issues, rpc.OpList)
+	}
+
+	// With SQLite, q is a ranked full-text search over all text fields and
+	// comments; other backends fall back to a title substring match
+	if q := query.Get("q"); q != "" {
+		if sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage); ok {
+			hits, err := sqliteStore.FullTextSearch(ctx, q, sqlite.SearchOptions{Filter: filter})
+			if err != nil {
+				s.writeError(w, r, http.StatusBadRequest, err)
+				return
+			}
+			issues := make([]*types.Issue, len(hits))
+			for i, hit := range hits {
+				issues[i] = hit.Issue
+			}
+			respond(issues)
+			return
+		}
+		filter.TitleSearch = q
+	}
+
+	// NDJSON streams from storage, so memory stays flat for listings of any
+	// size
+	if wantsNDJSON(r) {
+		s.streamIssues(w, r, fields, expand, func(fn func(*types.Issue) error) error {
+			return s.storage.ForEachIssue(ctx, filter, fn)
+		})
+		return
+	}
+
+	issues, err := cachedQuery(ctx, s, cacheKey("list", filter), func() ([]*types.Issue, error) {
+		return s.storage.SearchIssues(ctx, "", filter)
+	})
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	respond(issues)
+}
+
+// handleSearchIssues handles GET /issues/search
+func (s *Server) handleSearchIssues(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("full-text search requires SQLite backend"))
+		return
+	}
+
+	q := query.Get("q")
+	if q == "" {
+		s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("q is required"))
+		return
+	}
+
+	workflow, err := sqliteStore.GetWorkflow(ctx)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	filter, err := issueFilterFromQuery(query, workflow)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	opts := sqlite.SearchOptions{Filter: filter}
+	if opts.Filter.Limit == 0 {
+		opts.Filter.Limit = 20
+	}
+	// JSON clients usually render snippets as HTML, so mark matches with <mark>
+	if s.wantsJSON(r) {
+		opts.HighlightStart, opts.HighlightEnd = "<mark>", "</mark>"
+	}
+
+	hits, err := sqliteStore.FullTextSearch(ctx, q, opts)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if hits == nil {
+		hits = []*sqlite.SearchHit{}
+	}
+
+	s.writeSuccess(w, r, hits, opSearch)
+}
+
+// unassignedParam is the assignee query value that matches issues with no
+// assignee
+const unassignedParam = "unassigned"
+
+// issueFilterFromQuery builds an issue filter from the list query parameters.
+// status, priority, type, assignee, and label may be repeated, and a value
+// starting with ! excludes instead (label=!wontfix). Repeated values match
+// any of them, except labels, which must all be present (label_any needs
+// one of its values). query takes an
+// expression as for types.ParseQuery. It fails on a status not in workflow
+// (the built-in statuses if nil), an unknown type or sort field, or a
+// malformed priority, date, or query.
+func issueFilterFromQuery(query url.Values, workflow *types.Workflow) (types.IssueFilter, error) {
 	filter := types.IssueFilter{}
 
-	if status := query.Get("status"); status != "" {
-		s := types.Status(status)
-		filter.Status = &s
+	for _, value := range nonEmpty(query["status"]) {
+		value, exclude := negatedParam(value)
+		status := types.Status(value)
+		if (workflow == nil && !status.IsValid()) || (workflow != nil && !workflow.HasStatus(status)) {
+			return filter, fmt.Errorf("status: unknown status %q", value)
+		}
+		if exclude {
+			filter.ExcludeStatuses = append(filter.ExcludeStatuses, status)
+		} else {
+			filter.Statuses = append(filter.Statuses, status)
+		}
 	}
-	if priority := query.Get("priority"); priority != "" {
-		p, _ := strconv.Atoi(priority)
-		filter.Priority = &p
+	for _, value := range nonEmpty(query["priority"]) {
+		value, exclude := negatedParam(value)
+		priority, err := strconv.Atoi(value)
+		if err != nil {
+			return filter, fmt.Errorf("priority: %q is not a number", value)
+		}
+		if exclude {
+			filter.ExcludePriorities = append(filter.ExcludePriorities, priority)
+		} else {
+			filter.Priorities = append(filter.Priorities, priority)
+		}
 	}
-	if assignee := query.Get("assignee"); assignee != "" {
-		filter.Assignee = &assignee
+	for _, value := range nonEmpty(query["assignee"]) {
+		value, exclude := negatedParam(value)
+		if value == unassignedParam {
+			value = ""
+		}
+		if exclude {
+			filter.ExcludeAssignees = append(filter.ExcludeAssignees, value)
+		} else {
+			filter.Assignees = append(filter.Assignees, value)
+		}
 	}
-	if issueType := query.Get("type"); issueType != "" {
-		t := types.IssueType(issueType)
-		filter.IssueType = &t
+	for _, value := range nonEmpty(query["type"]) {
+		value, exclude := negatedParam(value)
+		issueType := types.IssueType(value)
+		if !issueType.IsValid() {
+			return filter, fmt.Errorf("type: unknown issue type %q", value)
+		}
+		if exclude {
+			filter.ExcludeIssueTypes = append(filter.ExcludeIssueTypes, issueType)
+		} else {
+			filter.IssueTypes = append(filter.IssueTypes, issueType)
+		}
 	}
-	if label := query.Get("label"); label != "" {
-		filter.Labels = []string{label}
+	for _, value := range nonEmpty(query["label"]) {
+		value, exclude := negatedParam(value)
+		if exclude {
+			filter.ExcludeLabels = append(filter.ExcludeLabels, value)
+		} else {
+			filter.Labels = append(filter.Labels, value)
+		}
 	}
+	filter.LabelsAny = nonEmpty(query["label_any"])
+	filter.IDs = nonEmpty(query["id"])
+	filter.TitleSearch = query.Get("title")
 	if limit := query.Get("limit"); limit != "" {
 		l, _ := strconv.Atoi(limit)
 		filter.Limit = l
 	}
-	if q := query.Get("q"); q != "" {
-		filter.TitleSearch = q
+	filter.ExcludeArchived = query.Get("include_archived") != "true"
+
+	now := time.Now()
+	for name, field := range map[string]**time.Time{"due_before": &filter.DueBefore, "due_after": &filter.DueAfter} {
+		if value := query.Get(name); value != "" {
+			t, err := types.ParseDate(value, now)
+			if err != nil {
+				return filter, fmt.Errorf("%s: %w", name, err)
+			}
+			*field = &t
+		}
+	}
+	filter.Overdue = query.Get("overdue") == "true"
+	if milestone := query.Get("milestone"); milestone != "" {
+		filter.Milestone = &milestone
 	}
 
-	issues, err := s.storage.SearchIssues(ctx, "", filter)
+	sortKeys, err := types.ParseIssueSort(query.Get("sort"))
 	if err != nil {
-		s.writeError(w, r, http.StatusInternalServerError, err)
-		return
+		return filter, fmt.Errorf("sort: %w", err)
 	}
+	filter.Sort = sortKeys
 
-	s.writeSuccess(w, r, issues, rpc.OpList)
+	if filter.Query, err = types.ParseQuery(query.Get("query"), now); err != nil {
+		return filter, fmt.Errorf("query: %w", err)
+	}
+
+	return filter, nil
+}
+
+// nonEmpty drops empty query values, which mean no filter
+func nonEmpty(values []string) []string {
+	var kept []string
+	for _, value := range values {
+		if value != "" {
+			kept = append(kept, value)
+		}
+	}
+	return kept
+}
+
+// negatedParam strips the ! from an excluding query value
+func negatedParam(value string) (string, bool) {
+	if strings.HasPrefix(value, "!") {
+		return value[1:], true
+	}
+	return value, false
 }
 
 // handleShowIssue handles GET /issues/{id}
@@ -224,13 +473,91 @@ func (s *Server) handleShowIssue(w http.ResponseWriter, r *http.Request) {
 
 	issue, err := s.storage.GetIssue(ctx, vars["id"])
 	if err != nil {
-		s.writeError(w, r, http.StatusNotFound, err)
+		s.writeStoreError(w, r, err)
 		return
 	}
+	if issue == nil {
+		if !s.redirectRenamedIssue(w, r, vars["id"]) {
+			s.writeStoreError(w, r, types.NotFound("issue", vars["id"]))
+		}
+		return
+	}
+
+	expand, ok := s.requestedExpansions(w, r)
+	if !ok {
+		return
+	}
+	// Answer polls for an unchanged issue before loading anything else.
+	// Expansions change without the issue, so they're always sent.
+	if len(expand) == 0 && notModified(w, r, issueETag(issue), issue.UpdatedAt) {
+		return
+	}
+
+	children, err := s.storage.GetChildren(ctx, issue.ID)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if len(children) > 0 {
+		issue.Subtasks = subtaskProgress(children)
+	}
+	links, err := storage.GetIssueLinks(ctx, s.storage, issue.ID)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if len(links) > 0 {
+		issue.Links = links
+	}
+	if sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage); ok {
+		if issue.Watchers, err = sqliteStore.GetWatchers(ctx, issue.ID); err != nil {
+			s.writeStoreError(w, r, err)
+			return
+		}
+	}
 
+	if s.wantsMarkdown(r) {
+		writeMarkdown(w, markdown.Issue(s.issueDetail(ctx, issue)))
+		return
+	}
+	if len(expand) > 0 && s.wantsJSON(r) {
+		relations, err := s.expandRelations(ctx, []*types.Issue{issue}, expand)
+		if err != nil {
+			s.writeStoreError(w, r, err)
+			return
+		}
+		s.writeSuccess(w, r, expandIssues([]*types.Issue{issue}, nil, relations)[0], rpc.OpShow)
+		return
+	}
 	s.writeSuccess(w, r, issue, rpc.OpShow)
 }
 
+// redirectRenamedIssue answers a request for an ID that a prefix rename
+// replaced with 301 to the new ID, and reports whether it answered
+func (s *Server) redirectRenamedIssue(w http.ResponseWriter, r *http.Request, id string) bool {
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		return false
+	}
+	newID, err := sqliteStore.GetIssueRedirect(r.Context(), id)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return true
+	}
+	if newID == "" {
+		return false
+	}
+
+	// Relative, so it resolves under a /w/{name}/ prefix too
+	location := url.PathEscape(newID)
+	if r.URL.RawQuery != "" {
+		location += "?" + r.URL.RawQuery
+	}
+	w.Header().Set("Location", location)
+	w.WriteHeader(http.StatusMovedPermanently)
+	return true
+}
+
 // handleUpdateIssue handles PATCH /issues/{id}
 func (s *Server) handleUpdateIssue(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -244,29 +571,116 @@ func (s *Server) handleUpdateIssue(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Update the issue
-	if err := s.storage.UpdateIssue(ctx, vars["id"], updates, actor); err != nil {
-		s.writeError(w, r, http.StatusInternalServerError, err)
+	version, err := expectedVersion(r, updates)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	// Update the issue, only if it is still at the expected version
+	if version != nil {
+		sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+		if !ok {
+			s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("conditional updates require SQLite backend"))
+			return
+		}
+		err = sqliteStore.UpdateIssueIfVersion(ctx, vars["id"], updates, actor, *version)
+	} else {
+		err = s.storage.UpdateIssue(ctx, vars["id"], updates, actor)
+	}
+	var conflict *sqlite.VersionConflictError
+	if errors.As(err, &conflict) {
+		s.writeVersionConflict(w, r, conflict)
+		return
+	}
+	if err != nil {
+		s.writeStoreError(w, r, err)
 		return
 	}
 
 	// Get the updated issue
 	issue, err := s.storage.GetIssue(ctx, vars["id"])
 	if err != nil {
-		s.writeError(w, r, http.StatusInternalServerError, err)
+		s.writeStoreError(w, r, err)
 		return
 	}
 
+	setIssueETag(w, issue)
 	s.writeSuccess(w, r, issue, rpc.OpUpdate)
 }
 
-// Placeholder handlers for other endpoints
-func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
-	s.writeSuccess(w, r, map[string]string{"status": "ok"}, "status")
+// bulkUpdateRequest is the body of PATCH /issues
+type bulkUpdateRequest struct {
+	Filter struct {
+		Status    string   `json:"status"`
+		Priority  *int     `json:"priority"`
+		Assignee  *string  `json:"assignee"`
+		Type      string   `json:"type"`
+		Labels    []string `json:"labels"`
+		LabelsAny []string `json:"labels_any"`
+		Title     string   `json:"title"`
+		IDs       []string `json:"ids"`
+		Limit     int      `json:"limit"`
+	} `json:"filter"`
+	Updates map[string]interface{} `json:"updates"`
 }
 
-func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
-	s.writeSuccess(w, r, map[string]string{"message": "not implemented"}, "metrics")
+// bulkUpdateResult reports the issues changed by a bulk update
+type bulkUpdateResult struct {
+	Updated []string `json:"updated"`
+	Count   int      `json:"count"`
+}
+
+// handleBulkUpdate handles PATCH /issues
+func (s *Server) handleBulkUpdate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	actor := s.getActor(r)
+
+	var req bulkUpdateRequest
+	if err := s.parseBody(r, &req); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	filter := types.IssueFilter{
+		Priority:    req.Filter.Priority,
+		Assignee:    req.Filter.Assignee,
+		Labels:      req.Filter.Labels,
+		LabelsAny:   req.Filter.LabelsAny,
+		TitleSearch: req.Filter.Title,
+		IDs:         req.Filter.IDs,
+		Limit:       req.Filter.Limit,
+	}
+	if req.Filter.Status != "" {
+		status := types.Status(req.Filter.Status)
+		filter.Status = &status
+	}
+	if req.Filter.Type != "" {
+		issueType := types.IssueType(req.Filter.Type)
+		filter.IssueType = &issueType
+	}
+
+	if filter.IsEmpty() {
+		s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("filter is required"))
+		return
+	}
+	if len(req.Updates) == 0 {
+		s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("updates are required"))
+		return
+	}
+
+	ids, err := s.storage.UpdateIssues(ctx, filter, req.Updates, actor)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.writeSuccess(w, r, bulkUpdateResult{Updated: ids, Count: len(ids)}, opBulkUpdate)
+}
+
+// closeRequest is the optional body of POST /issues/{id}/close
+type closeRequest struct {
+	Reason string `json:"reason,omitempty"`
 }
 
 func (s *Server) handleCloseIssue(w http.ResponseWriter, r *http.Request) {
@@ -274,13 +688,11 @@ func (s *Server) handleCloseIssue(w http.ResponseWriter, r *http.Request) {
 	actor := s.getActor(r)
 	vars := mux.Vars(r)
 
-	var body struct {
-		Reason string `json:"reason"`
-	}
+	var body closeRequest
 	s.parseBody(r, &body)
 
 	if err := s.storage.CloseIssue(ctx, vars["id"], body.Reason, actor); err != nil {
-		s.writeError(w, r, http.StatusInternalServerError, err)
+		s.writeStoreError(w, r, err)
 		return
 	}
 
@@ -289,49 +701,104 @@ func (s *Server) handleCloseIssue(w http.ResponseWriter, r *http.Request) {
 
 func (s *Server) handleReadyWork(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	filter := types.WorkFilter{Status: types.StatusOpen}
+	filter, err := workFilterFromQuery(r.URL.Query())
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
 
-	issues, err := s.storage.GetReadyWork(ctx, filter)
+	issues, err := cachedQuery(ctx, s, cacheKey("ready", filter), func() ([]*types.Issue, error) {
+		return s.storage.GetReadyWork(ctx, filter)
+	})
 	if err != nil {
-		s.writeError(w, r, http.StatusInternalServerError, err)
+		s.writeStoreError(w, r, err)
 		return
 	}
 
 	s.writeSuccess(w, r, issues, rpc.OpReady)
 }
 
-func (s *Server) handleAddComment(w http.ResponseWriter, r *http.Request) {
+// handleReadyQueues handles GET /issues/ready/queues, splitting ready work
+// into a queue per assignee. The limit applies to each queue.
+func (s *Server) handleReadyQueues(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	actor := s.getActor(r)
-	vars := mux.Vars(r)
-
-	var body struct {
-		Text string `json:"text"`
-	}
-	if err := s.parseBody(r, &body); err != nil {
+	filter, err := workFilterFromQuery(r.URL.Query())
+	if err != nil {
 		s.writeError(w, r, http.StatusBadRequest, err)
 		return
 	}
+	limit := filter.Limit
+	filter.Limit = 0
 
-	if err := s.storage.AddComment(ctx, vars["id"], actor, body.Text); err != nil {
-		s.writeError(w, r, http.StatusInternalServerError, err)
+	issues, err := s.storage.GetReadyWork(ctx, filter)
+	if err != nil {
+		s.writeStoreError(w, r, err)
 		return
 	}
 
-	s.writeSuccess(w, r, map[string]string{"message": "comment added"}, "comment_add")
+	queues := []*types.ReadyQueue{}
+	byAssignee := make(map[string]*types.ReadyQueue)
+	for _, issue := range issues {
+		q, ok := byAssignee[issue.Assignee]
+		if !ok {
+			q = &types.ReadyQueue{Assignee: issue.Assignee}
+			byAssignee[issue.Assignee] = q
+			queues = append(queues, q)
+		}
+		if limit <= 0 || len(q.Issues) < limit {
+			q.Issues = append(q.Issues, issue)
+		}
+	}
+	// Alphabetical, with unassigned work last
+	sort.Slice(queues, func(i, j int) bool {
+		if (queues[i].Assignee == "") != (queues[j].Assignee == "") {
+			return queues[j].Assignee == ""
+		}
+		return queues[i].Assignee < queues[j].Assignee
+	})
+
+	s.writeSuccess(w, r, queues, opReadyQueues)
 }
 
-func (s *Server) handleListComments(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	vars := mux.Vars(r)
-
-	events, err := s.storage.GetEvents(ctx, vars["id"], 100)
-	if err != nil {
-		s.writeError(w, r, http.StatusInternalServerError, err)
-		return
+// workFilterFromQuery builds a ready work filter from query parameters.
+// Status defaults to open.
+func workFilterFromQuery(query url.Values) (types.WorkFilter, error) {
+	filter := types.WorkFilter{
+		Status:     types.StatusOpen,
+		Labels:     query["label"],
+		SortPolicy: types.SortPolicy(query.Get("sort")),
+	}
+	if status := query.Get("status"); status != "" {
+		filter.Status = types.Status(status)
 	}
+	if !filter.SortPolicy.IsValid() {
+		return filter, fmt.Errorf("invalid sort %q (use hybrid, priority, or oldest)", filter.SortPolicy)
+	}
+	if assignee := query.Get("assignee"); assignee != "" {
+		filter.Assignee = &assignee
+	}
+	for name, field := range map[string]**int{"priority": &filter.Priority, "max_priority": &filter.MaxPriority} {
+		if value := query.Get(name); value != "" {
+			p, err := strconv.Atoi(value)
+			if err != nil || p < 0 || p > 4 {
+				return filter, fmt.Errorf("invalid %s %q (use 0-4)", name, value)
+			}
+			*field = &p
+		}
+	}
+	if limit := query.Get("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil || l < 0 {
+			return filter, fmt.Errorf("invalid limit %q", limit)
+		}
+		filter.Limit = l
+	}
+	return filter, nil
+}
 
-	s.writeSuccess(w, r, events, "comment_list")
+// labelRequest is the body of POST /issues/{id}/labels
+type labelRequest struct {
+	Label string `json:"label"`
 }
 
 func (s *Server) handleAddLabel(w http.ResponseWriter, r *http.Request) {
@@ -339,44 +806,65 @@ func (s *Server) handleAddLabel(w http.ResponseWriter, r *http.Request) {
 	actor := s.getActor(r)
 	vars := mux.Vars(r)
 
-	var body struct {
-		Label string `json:"label"`
-	}
+	var body labelRequest
 	if err := s.parseBody(r, &body); err != nil {
 		s.writeError(w, r, http.StatusBadRequest, err)
 		return
 	}
 
 	if err := s.storage.AddLabel(ctx, vars["id"], body.Label, actor); err != nil {
-		s.writeError(w, r, http.StatusInternalServerError, err)
+		s.writeStoreError(w, r, err)
 		return
 	}
 
 	s.writeSuccess(w, r, map[string]string{"message": "label added"}, "label_add")
 }
 
+// handleListIssueLabels handles GET /issues/{id}/labels
+func (s *Server) handleListIssueLabels(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+
+	if !s.requireIssue(w, r, vars["id"]) {
+		return
+	}
+	labels, err := s.storage.GetLabels(ctx, vars["id"])
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if labels == nil {
+		labels = []string{}
+	}
+
+	s.writeSuccess(w, r, labels, opIssueLabels)
+}
+
 func (s *Server) handleRemoveLabel(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	actor := s.getActor(r)
 	vars := mux.Vars(r)
 
 	if err := s.storage.RemoveLabel(ctx, vars["id"], vars["label"], actor); err != nil {
-		s.writeError(w, r, http.StatusInternalServerError, err)
+		s.writeStoreError(w, r, err)
 		return
 	}
 
 	s.writeSuccess(w, r, map[string]string{"message": "label removed"}, "label_remove")
 }
 
+// dependencyRequest is the body of POST /issues/{id}/dependencies
+type dependencyRequest struct {
+	DependsOn string `json:"depends_on"`
+	Type      string `json:"type,omitempty" enum:"blocks,related,relates-to,parent-child,discovered-from,duplicates,caused-by" doc:"Defaults to blocks"`
+}
+
 func (s *Server) handleAddDependency(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	actor := s.getActor(r)
 	vars := mux.Vars(r)
 
-	var body struct {
-		DependsOn string `json:"depends_on"`
-		Type      string `json:"type"`
-	}
+	var body dependencyRequest
 	if err := s.parseBody(r, &body); err != nil {
 		s.writeError(w, r, http.StatusBadRequest, err)
 		return
@@ -394,7 +882,7 @@ func (s *Server) handleAddDependency(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := s.storage.AddDependency(ctx, dep, actor); err != nil {
-		s.writeError(w, r, http.StatusInternalServerError, err)
+		s.writeStoreError(w, r, err)
 		return
 	}
 
@@ -407,7 +895,7 @@ func (s *Server) handleRemoveDependency(w http.ResponseWriter, r *http.Request)
 	vars := mux.Vars(r)
 
 	if err := s.storage.RemoveDependency(ctx, vars["id"], vars["depId"], actor); err != nil {
-		s.writeError(w, r, http.StatusInternalServerError, err)
+		s.writeStoreError(w, r, err)
 		return
 	}
 
@@ -424,48 +912,243 @@ func (s *Server) handleDependencyTree(w http.ResponseWriter, r *http.Request) {
 		maxDepth, _ = strconv.Atoi(d)
 	}
 
-	tree, err := s.storage.GetDependencyTree(ctx, vars["id"], maxDepth, false, false)
+	reverse := query.Get("reverse") == "true"
+
+	// Graph formats render the tree for Graphviz or Mermaid instead
+	if format := query.Get("format"); format != "" {
+		if format != depgraph.FormatDOT && format != depgraph.FormatMermaid && format != depgraph.FormatGraph {
+			s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("unknown format %q (use dot, mermaid, or graph)", format))
+			return
+		}
+		g, err := depgraph.ForTree(ctx, s.storage, vars["id"], maxDepth, reverse)
+		if err != nil {
+			s.writeStoreError(w, r, err)
+			return
+		}
+		if format == depgraph.FormatGraph {
+			if g.Edges == nil {
+				g.Edges = []*types.Dependency{}
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_ = json.NewEncoder(w).Encode(g)
+			return
+		}
+		var buf bytes.Buffer
+		if err := depgraph.Write(&buf, g, format); err != nil {
+			s.writeStoreError(w, r, err)
+			return
+		}
+		if format == depgraph.FormatDOT {
+			w.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+		} else {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(buf.Bytes())
+		return
+	}
+
+	tree, err := s.storage.GetDependencyTree(ctx, vars["id"], maxDepth, false, reverse)
 	if err != nil {
-		s.writeError(w, r, http.StatusInternalServerError, err)
+		s.writeStoreError(w, r, err)
 		return
 	}
+	if !reverse {
+		// Dependencies on other workspaces' issues hang below their issue
+		records := func(id string) ([]*types.Dependency, error) { return s.storage.GetDependencyRecords(ctx, id) }
+		if tree, err = s.remotes.ExpandTree(ctx, tree, records); err != nil {
+			s.writeStoreError(w, r, err)
+			return
+		}
+	}
 
 	s.writeSuccess(w, r, tree, rpc.OpDepTree)
 }
 
+// handleListEpics handles GET /epics, every epic with how many of its
+// children are closed
+func (s *Server) handleListEpics(w http.ResponseWriter, r *http.Request) {
+	epics, err := s.storage.GetEpicsEligibleForClosure(r.Context())
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if epics == nil {
+		epics = []*types.EpicStatus{}
+	}
+
+	s.writeSuccess(w, r, epics, opEpics)
+}
+
+// handleEpicStatus handles GET /epics/{id}/status
 func (s *Server) handleEpicStatus(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	vars := mux.Vars(r)
 
-	epics, err := s.storage.GetEpicsEligibleForClosure(ctx)
+	epic, err := s.storage.GetIssue(ctx, vars["id"])
 	if err != nil {
-		s.writeError(w, r, http.StatusInternalServerError, err)
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if epic == nil {
+		s.writeError(w, r, http.StatusNotFound, types.NotFound("issue", vars["id"]))
 		return
 	}
 
-	s.writeSuccess(w, r, epics, rpc.OpEpicStatus)
+	rollup, err := storage.GetEpicRollup(ctx, s.storage, epic.ID)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.writeSuccess(w, r, rollup, rpc.OpEpicStatus)
 }
 
-// Placeholder stubs for remaining endpoints
-func (s *Server) handleCompact(w http.ResponseWriter, r *http.Request) {
-	s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("not implemented"))
+// handleCriticalPath handles GET /epics/{id}/critical-path
+func (s *Server) handleCriticalPath(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+
+	epic, err := s.storage.GetIssue(ctx, vars["id"])
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if epic == nil {
+		s.writeError(w, r, http.StatusNotFound, types.NotFound("issue", vars["id"]))
+		return
+	}
+
+	path, err := storage.GetCriticalPath(ctx, s.storage, epic.ID)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.writeSuccess(w, r, path, opCriticalPath)
 }
 
-func (s *Server) handleCompactStats(w http.ResponseWriter, r *http.Request) {
-	s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("not implemented"))
+// handleBlockingImpact handles GET /issues/{id}/impact
+func (s *Server) handleBlockingImpact(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+
+	issue, err := s.storage.GetIssue(ctx, vars["id"])
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if issue == nil {
+		s.writeError(w, r, http.StatusNotFound, types.NotFound("issue", vars["id"]))
+		return
+	}
+
+	impact, err := storage.GetBlockingImpact(ctx, s.storage, issue.ID)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.writeSuccess(w, r, impact, opImpact)
+}
+
+// handleBlockers handles GET /issues/{id}/blockers
+func (s *Server) handleBlockers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	transitive := r.URL.Query().Get("transitive") == "true"
+
+	issue, err := s.storage.GetIssue(ctx, vars["id"])
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if issue == nil {
+		s.writeError(w, r, http.StatusNotFound, types.NotFound("issue", vars["id"]))
+		return
+	}
+
+	tree, err := storage.GetBlockers(ctx, s.storage, issue.ID, transitive)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.writeSuccess(w, r, tree, opBlockers)
 }
 
+// Placeholder stubs for remaining endpoints
 func (s *Server) handleExport(w http.ResponseWriter, r *http.Request) {
 	s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("not implemented"))
 }
 
+// handleImport handles POST /import
 func (s *Server) handleImport(w http.ResponseWriter, r *http.Request) {
-	s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("not implemented"))
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("import requires SQLite backend"))
+		return
+	}
+
+	strategy, err := importer.ParseStrategy(query.Get("strategy"))
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	issues, err := parseImportPayload(http.MaxBytesReader(w, r.Body, maxImportBytes))
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := importer.MergeIssues(ctx, sqliteStore, issues, strategy, importer.Options{
+		DryRun:         query.Get("dry_run") == "true",
+		Strict:         query.Get("strict") == "true",
+		RenameOnImport: query.Get("rename_on_import") == "true",
+	})
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.writeSuccess(w, r, result, opImport)
 }
 
 func (s *Server) handleBatch(w http.ResponseWriter, r *http.Request) {
 	s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("not implemented"))
 }
 
+// maskedConfigValue stands in for a credential held in config, which any
+// reader could otherwise fetch
+const maskedConfigValue = "[REDACTED]"
+
+// configValue returns value as API readers see it, masked if key holds a credential
+func configValue(key, value string) string {
+	if value != "" && digest.IsSecretConfig(key) {
+		return maskedConfigValue
+	}
+	return value
+}
+
+// handleListConfig handles GET /config
+func (s *Server) handleListConfig(w http.ResponseWriter, r *http.Request) {
+	config, err := s.storage.GetAllConfig(r.Context())
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	for key, value := range config {
+		config[key] = configValue(key, value)
+	}
+
+	s.writeSuccess(w, r, config, opConfig)
+}
+
 // handleGetConfig handles GET /config/{key}
 func (s *Server) handleGetConfig(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -479,26 +1162,51 @@ func (s *Server) handleGetConfig(w http.ResponseWriter, r *http.Request) {
 
 	result := map[string]string{
 		"key":   vars["key"],
-		"value": value,
+		"value": configValue(vars["key"], value),
 	}
 	s.writeSuccess(w, r, result, "config_get")
 }
 
+// configRequest is the body of PUT /config/{key}
+type configRequest struct {
+	Value string `json:"value"`
+}
+
 // handleSetConfig handles PUT /config/{key}
 func (s *Server) handleSetConfig(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	vars := mux.Vars(r)
 
-	var body struct {
-		Value string `json:"value"`
-	}
+	var body configRequest
 	if err := s.parseBody(r, &body); err != nil {
 		s.writeError(w, r, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := s.storage.SetConfig(ctx, vars["key"], body.Value); err != nil {
-		s.writeError(w, r, http.StatusInternalServerError, err)
+	// The workflow and required fields are checked before they're stored,
+	// as with PUT /workflow
+	sqliteStore, isSQLite := s.storage.(*sqlite.SQLiteStorage)
+	issueType, isRequired := strings.CutPrefix(vars["key"], sqlite.RequiredFieldsConfigPrefix)
+	var err error
+	switch {
+	case isSQLite && vars["key"] == sqlite.WorkflowConfigKey:
+		var workflow *types.Workflow
+		if workflow, err = types.ParseWorkflow([]byte(body.Value)); err == nil {
+			err = sqliteStore.SetWorkflow(ctx, workflow)
+		}
+	case isSQLite && isRequired:
+		var fields []string
+		if fields, err = types.ParseRequiredFields(body.Value); err == nil {
+			err = sqliteStore.SetRequiredFields(ctx, types.IssueType(issueType), fields)
+		}
+	default:
+		if err := s.storage.SetConfig(ctx, vars["key"], body.Value); err != nil {
+			s.writeStoreError(w, r, err)
+			return
+		}
+	}
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
 		return
 	}
 
@@ -508,3 +1216,114 @@ func (s *Server) handleSetConfig(w http.ResponseWriter, r *http.Request) {
 	}
 	s.writeSuccess(w, r, result, "config_set")
 }
+
+// handleDeleteConfig handles DELETE /config/{key}
+func (s *Server) handleDeleteConfig(w http.ResponseWriter, r *http.Request) {
+	if err := s.storage.DeleteConfig(r.Context(), mux.Vars(r)["key"]); err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.writeSuccess(w, r, map[string]string{"message": "config deleted"}, "config_delete")
+}
+
+// handleListRedactions handles GET /redactions
+func (s *Server) handleListRedactions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("redaction report requires SQLite backend"))
+		return
+	}
+
+	limit := 100
+	if l := query.Get("limit"); l != "" {
+		limit, _ = strconv.Atoi(l)
+	}
+
+	redactions, err := sqliteStore.GetSecretRedactions(ctx, query.Get("issue"), limit)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.writeSuccess(w, r, redactions, opRedactions)
+}
+
+// purgeActorRequest is the body of POST /admin/purge-actor
+type purgeActorRequest struct {
+	Actor       string `json:"actor"`
+	Mode        string `json:"mode,omitempty" enum:"anonymize,remove"`
+	Replacement string `json:"replacement,omitempty"`
+	DryRun      bool   `json:"dry_run,omitempty"`
+}
+
+// handlePurgeActor handles POST /admin/purge-actor
+func (s *Server) handlePurgeActor(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("actor purge requires SQLite backend"))
+		return
+	}
+
+	var body purgeActorRequest
+	if err := s.parseBody(r, &body); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if body.Actor == "" {
+		s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("actor is required"))
+		return
+	}
+
+	report, err := sqliteStore.PurgeActor(ctx, body.Actor, sqlite.PurgeActorOptions{
+		Mode:        body.Mode,
+		Replacement: body.Replacement,
+		PurgedBy:    s.getActor(r),
+		DryRun:      body.DryRun,
+	})
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	// Sign the deletion report if signing is configured
+	if !body.DryRun {
+		if method, _ := sqliteStore.GetConfig(ctx, "audit.sign"); method != "" {
+			gpgKey, _ := sqliteStore.GetConfig(ctx, "audit.gpg_key")
+			signature, signer, err := signing.Sign(method, gpgKey, report.SigningPayload())
+			if err != nil {
+				s.writeError(w, r, http.StatusInternalServerError, fmt.Errorf("purge completed but signing failed: %w", err))
+				return
+			}
+			report.Method, report.Signer, report.Signature = method, signer, signature
+			if err := sqliteStore.SignPurgeReport(ctx, report); err != nil {
+				s.writeStoreError(w, r, err)
+				return
+			}
+		}
+	}
+
+	s.writeSuccess(w, r, report, "purge_actor")
+}
+
+// handleListPurgeReports handles GET /admin/purge-reports
+func (s *Server) handleListPurgeReports(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("purge reports require SQLite backend"))
+		return
+	}
+
+	reports, err := sqliteStore.GetPurgeReports(r.Context())
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.writeSuccess(w, r, reports, "purge_reports")
+}
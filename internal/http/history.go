@@ -0,0 +1,70 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// handleIssueHistory handles GET /issues/{id}/history
+func (s *Server) handleIssueHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("issue history requires SQLite backend"))
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	issue, err := s.storage.GetIssue(ctx, id)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if issue == nil {
+		s.writeError(w, r, http.StatusNotFound, types.NotFound("issue", id))
+		return
+	}
+
+	history, err := sqliteStore.GetIssueHistory(ctx, id)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if history == nil {
+		history = []*sqlite.IssueRevision{}
+	}
+
+	s.writeSuccess(w, r, history, opHistory)
+}
+
+// handleRevert handles POST /issues/{id}/revert, which undoes the caller's
+// most recent update, close, or label change on the issue
+func (s *Server) handleRevert(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("revert requires SQLite backend"))
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	rev, err := sqliteStore.RevertLastChange(r.Context(), id, s.getActor(r))
+	var conflict *sqlite.RevertConflictError
+	switch {
+	case errors.Is(err, sqlite.ErrNothingToRevert):
+		s.writeError(w, r, http.StatusNotFound, fmt.Errorf("no change by %s to revert on %s", s.getActor(r), id))
+		return
+	case errors.As(err, &conflict):
+		s.writeError(w, r, http.StatusConflict, err)
+		return
+	case err != nil:
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.writeSuccess(w, r, rev, opRevert)
+}
@@ -0,0 +1,142 @@
+package http
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+)
+
+// maxIdempotencyKeyLen bounds the Idempotency-Key header
+const maxIdempotencyKeyLen = 255
+
+// responseCapture records a response while passing it through
+type responseCapture struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (c *responseCapture) WriteHeader(status int) {
+	c.status = status
+	c.ResponseWriter.WriteHeader(status)
+}
+
+func (c *responseCapture) Write(p []byte) (int, error) {
+	c.body.Write(p)
+	return c.ResponseWriter.Write(p)
+}
+
+// idempotencyMiddleware makes writes sent with an Idempotency-Key header
+// safe to retry: the first response for a key is stored for
+// sqlite.IdempotencyKeyTTL and replayed for later requests with the same key,
+// so a retried POST /issues returns the original issue instead of creating a
+// second one. Keys are scoped to the caller's actor and API key, so two
+// callers picking the same key never see each other's responses. Reusing a
+// key for a different request is rejected with 422, and a retry that arrives
+// while the original is still running gets 409. Server errors are not
+// stored, so those requests can be retried for real.
+func (s *Server) idempotencyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Idempotency-Key")
+		if key == "" || r.Method == http.MethodGet || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLen {
+			s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("Idempotency-Key must be at most %d characters", maxIdempotencyKeyLen))
+			return
+		}
+		sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+		if !ok {
+			s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("Idempotency-Key requires SQLite backend"))
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("failed to read body: %w", err))
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		// The key ID is digits, so the actor after it can't blur the two
+		keyID := ""
+		if p := requestPrincipal(r); p != nil && p.Key != nil {
+			keyID = strconv.FormatInt(p.Key.ID, 10)
+		}
+		scope := keyID + ":" + s.getActor(r)
+		hash := sha256.New()
+		fmt.Fprintf(hash, "%s %s\n", r.Method, r.URL.RequestURI())
+		hash.Write(body)
+		requestHash := hex.EncodeToString(hash.Sum(nil))
+
+		// Hold the key until the response is stored, so a concurrent retry
+		// can't run the request a second time
+		if !s.claimIdempotencyKey(scope, key) {
+			s.writeError(w, r, http.StatusConflict, fmt.Errorf("a request with Idempotency-Key %q is still in progress", key))
+			return
+		}
+		defer s.releaseIdempotencyKey(scope, key)
+
+		ctx := r.Context()
+		stored, err := sqliteStore.GetIdempotentResponse(ctx, scope, key)
+		if err != nil {
+			s.writeStoreError(w, r, err)
+			return
+		}
+		if stored != nil {
+			if stored.RequestHash != requestHash {
+				s.writeError(w, r, http.StatusUnprocessableEntity, fmt.Errorf("Idempotency-Key %q was already used for a different request", key))
+				return
+			}
+			if stored.ContentType != "" {
+				w.Header().Set("Content-Type", stored.ContentType)
+			}
+			w.Header().Set("Idempotent-Replayed", "true")
+			w.WriteHeader(stored.StatusCode)
+			_, _ = w.Write(stored.Body)
+			return
+		}
+
+		capture := &responseCapture{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(capture, r)
+		if capture.status >= http.StatusInternalServerError {
+			return
+		}
+		_ = sqliteStore.SaveIdempotentResponse(ctx, &sqlite.IdempotentResponse{
+			Scope:       scope,
+			Key:         key,
+			RequestHash: requestHash,
+			StatusCode:  capture.status,
+			ContentType: w.Header().Get("Content-Type"),
+			Body:        capture.body.Bytes(),
+		})
+	})
+}
+
+// claimIdempotencyKey marks a key as in flight, reporting false if it already is
+func (s *Server) claimIdempotencyKey(scope, key string) bool {
+	s.idempotencyMu.Lock()
+	defer s.idempotencyMu.Unlock()
+	id := scope + "\x00" + key
+	if s.idempotencyInFlight[id] {
+		return false
+	}
+	if s.idempotencyInFlight == nil {
+		s.idempotencyInFlight = make(map[string]bool)
+	}
+	s.idempotencyInFlight[id] = true
+	return true
+}
+
+func (s *Server) releaseIdempotencyKey(scope, key string) {
+	s.idempotencyMu.Lock()
+	defer s.idempotencyMu.Unlock()
+	delete(s.idempotencyInFlight, scope+"\x00"+key)
+}
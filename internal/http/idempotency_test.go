@@ -0,0 +1,129 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	do := func(key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/issues", strings.NewReader(body))
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("X-Actor", "alice")
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := do("create-1", `{"title": "Retry me", "issue_type": "task"}`)
+	if first.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", first.Code, first.Body)
+	}
+	var created types.Issue
+	if err := json.Unmarshal(first.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+
+	// A retry replays the original response instead of creating another issue
+	retry := do("create-1", `{"title": "Retry me", "issue_type": "task"}`)
+	if retry.Code != http.StatusOK || retry.Header().Get("Idempotent-Replayed") != "true" {
+		t.Fatalf("Expected replayed 200, got %d (replayed=%q)", retry.Code, retry.Header().Get("Idempotent-Replayed"))
+	}
+	if retry.Body.String() != first.Body.String() {
+		t.Errorf("Expected replay to match original response, got %s", retry.Body)
+	}
+	issues, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 || issues[0].ID != created.ID {
+		t.Fatalf("Expected only %s to exist, got %d issues", created.ID, len(issues))
+	}
+
+	// The same key with a different body is a client error
+	if rec := do("create-1", `{"title": "Something else", "issue_type": "task"}`); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 for reused key, got %d", rec.Code)
+	}
+
+	// Other keys, and requests without one, run normally
+	if rec := do("create-2", `{"title": "Retry me", "issue_type": "task"}`); rec.Code != http.StatusOK || rec.Header().Get("Idempotent-Replayed") != "" {
+		t.Errorf("Expected a fresh create for a new key, got %d", rec.Code)
+	}
+	do("", `{"title": "Retry me", "issue_type": "task"}`)
+	if issues, _ = store.SearchIssues(ctx, "", types.IssueFilter{}); len(issues) != 3 {
+		t.Errorf("Expected 3 issues, got %d", len(issues))
+	}
+}
+
+func TestIdempotencyKeyPerActor(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	do := func(actor string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/issues", strings.NewReader(`{"title": "Same request", "issue_type": "task"}`))
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("X-Actor", actor)
+		req.Header.Set("Idempotency-Key", "create-1")
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Two actors sharing a key each get their own issue, not the other's
+	alice := do("alice")
+	bob := do("bob")
+	for name, rec := range map[string]*httptest.ResponseRecorder{"alice": alice, "bob": bob} {
+		if rec.Code != http.StatusOK || rec.Header().Get("Idempotent-Replayed") != "" {
+			t.Fatalf("Expected a fresh create for %s, got %d (replayed=%q)", name, rec.Code, rec.Header().Get("Idempotent-Replayed"))
+		}
+	}
+	if alice.Body.String() == bob.Body.String() {
+		t.Error("Expected bob not to be handed alice's response")
+	}
+	if issues, _ := store.SearchIssues(ctx, "", types.IssueFilter{}); len(issues) != 2 {
+		t.Errorf("Expected 2 issues, got %d", len(issues))
+	}
+
+	// Each actor's retry still replays their own response
+	if rec := do("bob"); rec.Header().Get("Idempotent-Replayed") != "true" || rec.Body.String() != bob.Body.String() {
+		t.Errorf("Expected bob's retry to replay bob's response, got %d: %s", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,75 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestBlockingImpact(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	do := func(path, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Accept", accept)
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	var issues []*types.Issue
+	for i, title := range []string{"Schema", "API", "Release"} {
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 2 - i, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatal(err)
+		}
+		issues = append(issues, issue)
+	}
+	for i := 1; i < len(issues); i++ {
+		dep := &types.Dependency{IssueID: issues[i].ID, DependsOnID: issues[i-1].ID, Type: types.DepBlocks}
+		if err := store.AddDependency(ctx, dep, "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rec := do("/issues/"+issues[0].ID+"/impact", "application/json")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var impact types.BlockingImpact
+	if err := json.Unmarshal(rec.Body.Bytes(), &impact); err != nil {
+		t.Fatalf("failed to decode %s: %v", rec.Body, err)
+	}
+	if impact.Total != 2 || impact.Direct != 1 || impact.HighestPriority == nil || *impact.HighestPriority != 0 {
+		t.Errorf("Unexpected impact %s", rec.Body)
+	}
+
+	rec = do("/issues/"+issues[0].ID+"/impact", "text/plain")
+	if body := rec.Body.String(); !strings.Contains(body, "blocks 2 open issue(s), 1 directly") || !strings.Contains(body, "via "+issues[1].ID) {
+		t.Errorf("Expected the impact as text, got %s", body)
+	}
+
+	if rec = do("/issues/bd-999/impact", "application/json"); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a missing issue, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,55 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// maxImportBytes caps the size of a POST /import body
+const maxImportBytes = 64 << 20
+
+// parseImportPayload decodes issues from either a JSON array or JSON Lines
+func parseImportPayload(body io.Reader) ([]*types.Issue, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read body: %w", err)
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty import payload")
+	}
+
+	if data[0] == '[' {
+		var issues []*types.Issue
+		if err := json.Unmarshal(data, &issues); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON array: %w", err)
+		}
+		return issues, nil
+	}
+
+	var issues []*types.Issue
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportBytes)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var issue types.Issue
+		if err := json.Unmarshal(line, &issue); err != nil {
+			return nil, fmt.Errorf("failed to parse JSONL line %d: %w", lineNum, err)
+		}
+		issues = append(issues, &issue)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read JSONL: %w", err)
+	}
+	return issues, nil
+}
@@ -0,0 +1,91 @@
+package http
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// patchOp is an RFC 6902 JSON Patch operation
+type patchOp struct {
+	Op    string
+	Path  string
+	Value interface{}
+}
+
+// MarshalJSON includes value for add and replace, even when it is null
+func (p patchOp) MarshalJSON() ([]byte, error) {
+	if p.Op == "remove" {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{p.Op, p.Path})
+	}
+	return json.Marshal(struct {
+		Op    string      `json:"op"`
+		Path  string      `json:"path"`
+		Value interface{} `json:"value"`
+	}{p.Op, p.Path, p.Value})
+}
+
+// toJSONDoc converts v to its generic JSON object form
+func toJSONDoc(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// diffJSON returns the patch that turns from into to. Nested objects are
+// diffed recursively; arrays and scalars are replaced whole.
+func diffJSON(from, to map[string]interface{}) []patchOp {
+	return diffObjects("", from, to)
+}
+
+func diffObjects(prefix string, from, to map[string]interface{}) []patchOp {
+	keys := make(map[string]bool, len(from)+len(to))
+	for k := range from {
+		keys[k] = true
+	}
+	for k := range to {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var ops []patchOp
+	for _, k := range sorted {
+		path := prefix + "/" + escapePointer(k)
+		oldVal, hadOld := from[k]
+		newVal, hasNew := to[k]
+		switch {
+		case !hasNew:
+			ops = append(ops, patchOp{Op: "remove", Path: path})
+		case !hadOld:
+			ops = append(ops, patchOp{Op: "add", Path: path, Value: newVal})
+		default:
+			oldObj, oldIsObj := oldVal.(map[string]interface{})
+			newObj, newIsObj := newVal.(map[string]interface{})
+			if oldIsObj && newIsObj {
+				ops = append(ops, diffObjects(path, oldObj, newObj)...)
+			} else if !reflect.DeepEqual(oldVal, newVal) {
+				ops = append(ops, patchOp{Op: "replace", Path: path, Value: newVal})
+			}
+		}
+	}
+	return ops
+}
+
+// escapePointer escapes a key for use in a JSON Pointer (RFC 6901)
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
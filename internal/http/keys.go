@@ -0,0 +1,103 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/imalsogreg/beads/internal/apikey"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+)
+
+// keyRequest is the body of POST /keys
+type keyRequest struct {
+	Name string `json:"name" doc:"Who the key is for; used as the actor when requests don't send X-Actor"`
+	Role string `json:"role" doc:"reader, writer, or admin"`
+}
+
+// createdKey is the response to POST /keys, the only time the token is shown
+type createdKey struct {
+	sqlite.APIKey
+	Token string `json:"token"`
+}
+
+// handleListKeys handles GET /keys
+func (s *Server) handleListKeys(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("API keys require SQLite backend"))
+		return
+	}
+
+	keys, err := sqliteStore.ListAPIKeys(r.Context())
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if keys == nil {
+		keys = []*sqlite.APIKey{}
+	}
+
+	s.writeSuccess(w, r, keys, opKeys)
+}
+
+// handleCreateKey handles POST /keys
+func (s *Server) handleCreateKey(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("API keys require SQLite backend"))
+		return
+	}
+
+	var body keyRequest
+	if err := s.parseBody(r, &body); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	body.Name = strings.TrimSpace(body.Name)
+	if body.Name == "" {
+		s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("name is required"))
+		return
+	}
+	role, err := apikey.ParseRole(body.Role)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	token, err := apikey.Generate()
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	key := &sqlite.APIKey{Name: body.Name, Role: string(role), Hint: apikey.Hint(token), CreatedBy: s.getActor(r)}
+	if err := sqliteStore.CreateAPIKey(r.Context(), key, apikey.Hash(token)); err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.writeSuccess(w, r, createdKey{APIKey: *key, Token: token}, opKeyCreate)
+}
+
+// handleDeleteKey handles DELETE /keys/{id}
+func (s *Server) handleDeleteKey(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("API keys require SQLite backend"))
+		return
+	}
+
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("invalid key ID %q", mux.Vars(r)["id"]))
+		return
+	}
+	if err := sqliteStore.DeleteAPIKey(r.Context(), id); err != nil {
+		s.writeError(w, r, http.StatusNotFound, err)
+		return
+	}
+
+	s.writeSuccess(w, r, map[string]string{"message": "key revoked"}, "key_delete")
+}
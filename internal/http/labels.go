@@ -0,0 +1,162 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// labelDefinitionRequest is the body of POST /labels
+type labelDefinitionRequest struct {
+	Name        string `json:"name" doc:"Path-style names such as infra/db nest under their prefix"`
+	Color       string `json:"color,omitempty" doc:"#rrggbb"`
+	Description string `json:"description,omitempty"`
+	Parent      string `json:"parent,omitempty" doc:"Label this one sits under; defaults to the name's path prefix"`
+}
+
+// labelUpdateRequest is the body of PATCH /labels/{name}; omitted fields
+// are left alone
+type labelUpdateRequest struct {
+	Color       *string `json:"color,omitempty" doc:"#rrggbb, or empty to clear"`
+	Description *string `json:"description,omitempty"`
+	Parent      *string `json:"parent,omitempty" doc:"Empty to make the label top-level"`
+}
+
+// handleListLabels handles GET /labels
+func (s *Server) handleListLabels(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.labelStore(w, r)
+	if !ok {
+		return
+	}
+
+	labels, err := sqliteStore.ListLabels(r.Context())
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.writeSuccess(w, r, labels, opLabels)
+}
+
+// handleCreateLabel handles POST /labels
+func (s *Server) handleCreateLabel(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.labelStore(w, r)
+	if !ok {
+		return
+	}
+
+	var body labelDefinitionRequest
+	if err := s.parseBody(r, &body); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	label := &sqlite.Label{Name: body.Name, Color: body.Color, Description: body.Description, Parent: body.Parent, CreatedBy: s.getActor(r)}
+	if err := sqliteStore.CreateLabel(r.Context(), label); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	s.showLabel(w, r, sqliteStore, label.Name)
+}
+
+// handleShowLabel handles GET /labels/{name}
+func (s *Server) handleShowLabel(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.labelStore(w, r)
+	if !ok {
+		return
+	}
+	s.showLabel(w, r, sqliteStore, mux.Vars(r)["name"])
+}
+
+// handleUpdateLabel handles PATCH /labels/{name}
+func (s *Server) handleUpdateLabel(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.labelStore(w, r)
+	if !ok {
+		return
+	}
+
+	name := mux.Vars(r)["name"]
+	label, err := sqliteStore.GetLabel(r.Context(), name)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if label == nil || !label.Defined {
+		s.writeError(w, r, http.StatusNotFound, fmt.Errorf("label %s is not defined", name))
+		return
+	}
+
+	var body labelUpdateRequest
+	if err := s.parseBody(r, &body); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if body.Color != nil {
+		label.Color = *body.Color
+	}
+	if body.Description != nil {
+		label.Description = *body.Description
+	}
+	if body.Parent != nil {
+		label.Parent = *body.Parent
+	}
+
+	if err := sqliteStore.UpdateLabel(r.Context(), label); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	s.showLabel(w, r, sqliteStore, name)
+}
+
+// handleDeleteLabel handles DELETE /labels/{name}
+func (s *Server) handleDeleteLabel(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.labelStore(w, r)
+	if !ok {
+		return
+	}
+
+	name := mux.Vars(r)["name"]
+	label, err := sqliteStore.GetLabel(r.Context(), name)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if label == nil || !label.Defined {
+		s.writeError(w, r, http.StatusNotFound, fmt.Errorf("label %s is not defined", name))
+		return
+	}
+
+	if err := sqliteStore.DeleteLabel(r.Context(), name); err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.writeSuccess(w, r, map[string]string{"message": "label definition deleted"}, "label_delete")
+}
+
+// showLabel responds with a label and its children
+func (s *Server) showLabel(w http.ResponseWriter, r *http.Request, sqliteStore *sqlite.SQLiteStorage, name string) {
+	label, err := sqliteStore.GetLabel(r.Context(), name)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if label == nil {
+		s.writeError(w, r, http.StatusNotFound, types.NotFound("label", name))
+		return
+	}
+	s.writeSuccess(w, r, label, opLabel)
+}
+
+// labelStore returns the SQLite backend, writing an error response if the
+// server has another one
+func (s *Server) labelStore(w http.ResponseWriter, r *http.Request) (*sqlite.SQLiteStorage, bool) {
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("label definitions require SQLite backend"))
+	}
+	return sqliteStore, ok
+}
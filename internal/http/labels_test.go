@@ -0,0 +1,122 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestLabelEndpoints(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+	decode := func(rec *httptest.ResponseRecorder) *sqlite.Label {
+		t.Helper()
+		var label sqlite.Label
+		if err := json.Unmarshal(rec.Body.Bytes(), &label); err != nil {
+			t.Fatalf("failed to decode %s: %v", rec.Body, err)
+		}
+		return &label
+	}
+
+	rec := do("POST", "/labels", `{"name": "infra/db", "color": "#336699", "description": "Databases"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST /labels: status %d: %s", rec.Code, rec.Body)
+	}
+	if label := decode(rec); label.Parent != "infra" || !label.Defined {
+		t.Errorf("Expected infra/db under infra, got %+v", label)
+	}
+	if rec := do("POST", "/labels", `{"name": "infra/db"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 defining infra/db twice, got %d", rec.Code)
+	}
+	if rec := do("POST", "/labels", `{"name": "net", "color": "blue"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a bad color, got %d", rec.Code)
+	}
+
+	// Names with slashes work in the path
+	rec = do("PATCH", "/labels/infra/db", `{"description": "Data stores"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("PATCH /labels/infra/db: status %d: %s", rec.Code, rec.Body)
+	}
+	if label := decode(rec); label.Description != "Data stores" || label.Color != "#336699" {
+		t.Errorf("Expected only the description to change, got %+v", label)
+	}
+	if rec := do("PATCH", "/labels/infra", `{"description": "x"}`); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 updating an undefined label, got %d", rec.Code)
+	}
+
+	issue := &types.Issue{Title: "Tune queries", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AddLabel(ctx, issue.ID, "infra/db", "test"); err != nil {
+		t.Fatal(err)
+	}
+
+	// infra isn't defined or used, but has a child
+	rec = do("GET", "/labels/infra", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /labels/infra: status %d: %s", rec.Code, rec.Body)
+	}
+	if label := decode(rec); label.Defined || len(label.Children) != 1 || label.Children[0] != "infra/db" {
+		t.Errorf("Expected infra to list infra/db, got %+v", label)
+	}
+	if rec := do("GET", "/labels/nothing", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown label, got %d", rec.Code)
+	}
+
+	rec = do("GET", "/issues?label=infra/*", "")
+	var issues []*types.Issue
+	if err := json.Unmarshal(rec.Body.Bytes(), &issues); err != nil {
+		t.Fatalf("failed to decode %s: %v", rec.Body, err)
+	}
+	if len(issues) != 1 || issues[0].ID != issue.ID {
+		t.Errorf("Expected label=infra/* to find %s, got %s", issue.ID, rec.Body)
+	}
+
+	rec = do("GET", "/labels", "")
+	var labels []*sqlite.Label
+	if err := json.Unmarshal(rec.Body.Bytes(), &labels); err != nil {
+		t.Fatalf("failed to decode %s: %v", rec.Body, err)
+	}
+	if len(labels) != 1 || labels[0].Issues != 1 {
+		t.Errorf("Unexpected labels: %s", rec.Body)
+	}
+
+	if rec := do("DELETE", "/issues/"+issue.ID+"/labels/infra/db", ""); rec.Code != http.StatusOK {
+		t.Errorf("DELETE issue label: status %d: %s", rec.Code, rec.Body)
+	}
+	if rec := do("DELETE", "/labels/infra/db", ""); rec.Code != http.StatusOK {
+		t.Errorf("DELETE /labels/infra/db: status %d: %s", rec.Code, rec.Body)
+	}
+	if rec := do("DELETE", "/labels/infra/db", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 deleting an undefined label, got %d", rec.Code)
+	}
+}
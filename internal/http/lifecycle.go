@@ -0,0 +1,261 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// drainRetryAfter is how long, in seconds, clients turned away while the
+// server drains are told to wait before retrying (by then against the
+// restarted server)
+const drainRetryAfter = "5"
+
+// drainExemptRoutes keep answering while the server drains, so load
+// balancers and operators can watch it wind down
+var drainExemptRoutes = map[string]bool{
+	"/health":      true,
+	"/ping":        true,
+	"/status":      true,
+	"/metrics":     true,
+	"/admin/drain": true,
+}
+
+// lifecycle tracks open connections and in-flight requests, and whether the
+// server has stopped taking new work
+type lifecycle struct {
+	mu            sync.Mutex
+	drainingSince time.Time // zero unless draining
+	conns         map[net.Conn]http.ConnState
+	inFlight      int
+
+	// streams counts WebSocket handlers, which http.Server.Shutdown doesn't
+	// wait for since their connections are hijacked
+	streams sync.WaitGroup
+}
+
+// DrainStatus is the body of GET /admin/drain
+type DrainStatus struct {
+	Draining    bool       `json:"draining"`
+	Since       *time.Time `json:"since,omitempty"`
+	Requests    int        `json:"requests"`    // requests being handled, this one included
+	Connections int        `json:"connections"` // open HTTP connections, idle ones included
+	Idle        int        `json:"idle"`        // connections waiting for their next request
+	WebSockets  int        `json:"websockets"`
+}
+
+// trackConn is the http.Server ConnState hook. Hijacked connections leave
+// the count here; WebSockets are counted by the hub instead.
+func (s *Server) trackConn(conn net.Conn, state http.ConnState) {
+	s.life.mu.Lock()
+	defer s.life.mu.Unlock()
+	switch state {
+	case http.StateNew:
+		s.metrics.RecordConnection()
+		s.life.conns[conn] = state
+	case http.StateHijacked, http.StateClosed:
+		delete(s.life.conns, conn)
+	default:
+		s.life.conns[conn] = state
+	}
+}
+
+// Drain stops the server taking new work ahead of a restart: new requests
+// get 503 with Retry-After, keep-alives are turned off so clients reconnect
+// elsewhere, and WebSocket clients are told to go away. Requests already
+// running finish normally. Calling it again has no effect.
+func (s *Server) Drain() {
+	s.life.mu.Lock()
+	if !s.life.drainingSince.IsZero() {
+		s.life.mu.Unlock()
+		return
+	}
+	s.life.drainingSince = time.Now()
+	s.life.mu.Unlock()
+
+	s.httpServer.SetKeepAlivesEnabled(false)
+	s.wsMu.Lock()
+	if s.wsHub != nil {
+		s.wsHub.closeClients()
+	}
+	s.wsMu.Unlock()
+	for _, ws := range s.workspaces {
+		ws.Drain()
+	}
+}
+
+// Resume undoes Drain, for a restart that was called off
+func (s *Server) Resume() {
+	s.life.mu.Lock()
+	s.life.drainingSince = time.Time{}
+	s.life.mu.Unlock()
+	s.httpServer.SetKeepAlivesEnabled(true)
+	for _, ws := range s.workspaces {
+		ws.Resume()
+	}
+}
+
+// Draining reports whether Drain has been called
+func (s *Server) Draining() bool {
+	s.life.mu.Lock()
+	defer s.life.mu.Unlock()
+	return !s.life.drainingSince.IsZero()
+}
+
+// DrainStatus reports the drain state and the work still in progress,
+// counting every workspace's requests and WebSockets
+func (s *Server) DrainStatus() DrainStatus {
+	s.life.mu.Lock()
+	status := DrainStatus{
+		Draining:    !s.life.drainingSince.IsZero(),
+		Requests:    s.life.inFlight,
+		Connections: len(s.life.conns),
+	}
+	if status.Draining {
+		since := s.life.drainingSince
+		status.Since = &since
+	}
+	for _, state := range s.life.conns {
+		if state == http.StateIdle {
+			status.Idle++
+		}
+	}
+	s.life.mu.Unlock()
+
+	s.wsMu.Lock()
+	if s.wsHub != nil {
+		status.WebSockets = s.wsHub.clientCount()
+	}
+	s.wsMu.Unlock()
+
+	for _, ws := range s.workspaces {
+		sub := ws.DrainStatus()
+		status.Requests += sub.Requests
+		status.WebSockets += sub.WebSockets
+	}
+	return status
+}
+
+// Stop drains the server and shuts it down, waiting until ctx is done for
+// running requests and WebSockets to finish. Whatever is still open then is
+// closed, and Stop reports how much was cut off. Background jobs are
+// stopped either way.
+func (s *Server) Stop(ctx context.Context) error {
+	s.Drain()
+
+	err := s.httpServer.Shutdown(ctx)
+	if err == nil {
+		err = waitContext(ctx, &s.life.streams)
+	}
+	for _, ws := range s.workspaces {
+		if err == nil {
+			err = waitContext(ctx, &ws.life.streams)
+		}
+	}
+	if err != nil {
+		status := s.DrainStatus()
+		_ = s.httpServer.Close()
+		err = fmt.Errorf("gave up draining with %d request(s) and %d WebSocket(s) still open: %w",
+			status.Requests, status.WebSockets, err)
+	}
+
+	s.stopBackground()
+	for _, ws := range s.workspaces {
+		ws.stopBackground()
+	}
+	return err
+}
+
+// stopBackground closes the WebSocket hub and stops the background jobs
+// for s's own database
+func (s *Server) stopBackground() {
+	s.wsMu.Lock()
+	if s.wsHub != nil {
+		s.wsHub.close()
+	}
+	s.wsMu.Unlock()
+	if s.webhooks != nil {
+		s.webhooks.Close()
+	}
+	if s.digests != nil {
+		s.digests.Close()
+	}
+	if s.epics != nil {
+		s.epics.Close()
+	}
+	if s.compactions != nil {
+		s.compactions.Close()
+	}
+	if s.backups != nil {
+		s.backups.Close()
+	}
+}
+
+// waitContext waits for wg, giving up when ctx is done
+func waitContext(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// drainMiddleware counts in-flight requests and, while draining, turns
+// away new ones except to the exempt routes
+func (s *Server) drainMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.life.mu.Lock()
+		draining := !s.life.drainingSince.IsZero()
+		if draining {
+			route := ""
+			if current := mux.CurrentRoute(r); current != nil {
+				route, _ = current.GetPathTemplate()
+			}
+			if !drainExemptRoutes[route] && !probeRoutes[route] {
+				s.life.mu.Unlock()
+				s.metrics.RecordRejectedConnection()
+				w.Header().Set("Retry-After", drainRetryAfter)
+				w.Header().Set("Connection", "close")
+				s.writeError(w, r, http.StatusServiceUnavailable, fmt.Errorf("server is draining for a restart"))
+				return
+			}
+		}
+		s.life.inFlight++
+		s.life.mu.Unlock()
+
+		defer func() {
+			s.life.mu.Lock()
+			s.life.inFlight--
+			s.life.mu.Unlock()
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleDrainStatus handles GET /admin/drain
+func (s *Server) handleDrainStatus(w http.ResponseWriter, r *http.Request) {
+	s.writeSuccess(w, r, s.DrainStatus(), opDrain)
+}
+
+// handleDrain handles POST /admin/drain
+func (s *Server) handleDrain(w http.ResponseWriter, r *http.Request) {
+	s.Drain()
+	s.writeSuccess(w, r, s.DrainStatus(), opDrain)
+}
+
+// handleResume handles DELETE /admin/drain
+func (s *Server) handleResume(w http.ResponseWriter, r *http.Request) {
+	s.Resume()
+	s.writeSuccess(w, r, s.DrainStatus(), opDrain)
+}
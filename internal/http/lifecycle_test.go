@@ -0,0 +1,157 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+)
+
+func newLifecycleTestServer(t *testing.T) *Server {
+	t.Helper()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	if err := store.SetConfig(context.Background(), "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return srv
+}
+
+func TestDrainEndpoint(t *testing.T) {
+	srv := newLifecycleTestServer(t)
+	do := func(method, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Accept", "application/json")
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do("POST", "/admin/drain")
+	var status DrainStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("POST /admin/drain: %d %s", rec.Code, rec.Body)
+	}
+	if !status.Draining || status.Since == nil || status.Requests != 1 {
+		t.Errorf("Unexpected drain status: %+v", status)
+	}
+
+	rec = do("GET", "/issues")
+	if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") == "" {
+		t.Errorf("Expected 503 with Retry-After while draining, got %d %v", rec.Code, rec.Header())
+	}
+	if rec := do("GET", "/health"); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected /health to report 503 while draining, got %d", rec.Code)
+	}
+	if rec := do("GET", "/ping"); rec.Code != http.StatusOK {
+		t.Errorf("Expected /ping to answer while draining, got %d", rec.Code)
+	}
+
+	if rec := do("DELETE", "/admin/drain"); rec.Code != http.StatusOK || srv.Draining() {
+		t.Fatalf("DELETE /admin/drain: %d, draining %v", rec.Code, srv.Draining())
+	}
+	if rec := do("GET", "/issues"); rec.Code != http.StatusOK {
+		t.Errorf("Expected 200 after resuming, got %d", rec.Code)
+	}
+}
+
+func TestStopDrainsRequests(t *testing.T) {
+	srv := newLifecycleTestServer(t)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	srv.router.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		_, _ = io.WriteString(w, "done")
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = srv.httpServer.Serve(ln) }()
+	base := "http://" + ln.Addr().String()
+
+	body := make(chan string, 1)
+	go func() {
+		resp, err := http.Get(base + "/slow")
+		if err != nil {
+			body <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		body <- string(data)
+	}()
+	<-started
+	if status := srv.DrainStatus(); status.Requests != 1 || status.Connections != 1 {
+		t.Errorf("Expected one request on one connection, got %+v", status)
+	}
+
+	stopped := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		stopped <- srv.Stop(ctx)
+	}()
+	select {
+	case err := <-stopped:
+		t.Fatalf("Stop returned with a request still running: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	if got := <-body; got != "done" {
+		t.Errorf("Expected the running request to finish, got %q", got)
+	}
+	if err := <-stopped; err != nil {
+		t.Errorf("Stop: %v", err)
+	}
+}
+
+func TestStopGivesUp(t *testing.T) {
+	srv := newLifecycleTestServer(t)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	srv.router.HandleFunc("/stuck", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = srv.httpServer.Serve(ln) }()
+	go func() {
+		if resp, err := http.Get("http://" + ln.Addr().String() + "/stuck"); err == nil {
+			resp.Body.Close()
+		}
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = srv.Stop(ctx)
+	if err == nil || !strings.Contains(err.Error(), "1 request(s)") {
+		t.Errorf("Expected Stop to give up on the stuck request, got %v", err)
+	}
+}
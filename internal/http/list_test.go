@@ -0,0 +1,163 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestListIssuesFilters(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Accept", "application/json")
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for _, issue := range []*types.Issue{
+		{Title: "open-alice", Status: types.StatusOpen, Priority: 1, Assignee: "alice"},
+		{Title: "progress", Status: types.StatusInProgress, Priority: 2},
+		{Title: "blocked-bob", Status: types.StatusBlocked, Priority: 3, Assignee: "bob"},
+		{Title: "wontfix", Status: types.StatusOpen, Priority: 4},
+	} {
+		issue.IssueType = types.TypeTask
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatal(err)
+		}
+		if issue.Title == "wontfix" {
+			if err := store.AddLabel(ctx, issue.ID, "wontfix", "test"); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"status=open&status=in_progress", "open-alice progress wontfix"},
+		{"status=!open", "blocked-bob progress"},
+		{"label=!wontfix&status=open", "open-alice"},
+		{"assignee=unassigned", "progress wontfix"},
+		{"assignee=!unassigned", "blocked-bob open-alice"},
+		{"assignee=alice&assignee=bob", "blocked-bob open-alice"},
+		{"priority=1&priority=3", "blocked-bob open-alice"},
+		{"priority=!4&type=task", "blocked-bob open-alice progress"},
+		{"status=&label=", "blocked-bob open-alice progress wontfix"},
+		{"query=" + url.QueryEscape("status:open AND (label:wontfix OR assignee:alice)"), "open-alice wontfix"},
+	}
+	for _, tt := range tests {
+		rec := get("/issues?" + tt.query)
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s: status %d: %s", tt.query, rec.Code, rec.Body)
+			continue
+		}
+		var issues []*types.Issue
+		if err := json.Unmarshal(rec.Body.Bytes(), &issues); err != nil {
+			t.Fatalf("failed to decode %s: %v", rec.Body, err)
+		}
+		var titles []string
+		for _, issue := range issues {
+			titles = append(titles, issue.Title)
+		}
+		sort.Strings(titles)
+		if got := strings.Join(titles, " "); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.query, got, tt.want)
+		}
+	}
+
+	for _, query := range []string{"status=done", "type=!story", "priority=high", "query=nope:x"} {
+		if rec := get("/issues?" + query); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, rec.Code)
+		}
+	}
+}
+
+func TestListIssuesSortAndFields(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Accept", "application/json")
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for _, issue := range []*types.Issue{
+		{Title: "low", Status: types.StatusOpen, Priority: 3, Assignee: "alice"},
+		{Title: "high", Status: types.StatusOpen, Priority: 0},
+		{Title: "medium", Status: types.StatusOpen, Priority: 2},
+	} {
+		issue.IssueType = types.TypeTask
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rec := get("/issues?sort=-priority&fields=title,priority,assignee")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+	var issues []map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &issues); err != nil {
+		t.Fatalf("failed to decode %s: %v", rec.Body, err)
+	}
+	var titles []string
+	for _, issue := range issues {
+		if len(issue) != 3 {
+			t.Errorf("Expected exactly the selected fields, got %v", issue)
+		}
+		titles = append(titles, issue["title"].(string))
+	}
+	if got := strings.Join(titles, " "); got != "low medium high" {
+		t.Errorf("sort=-priority: got %q", got)
+	}
+	if issues[1]["assignee"] != nil {
+		t.Errorf("Expected a null assignee, got %v", issues[1]["assignee"])
+	}
+
+	for _, query := range []string{"sort=labels", "sort=-", "fields=id,nope"} {
+		if rec := get("/issues?" + query); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/imalsogreg/beads/internal/markdown"
+	"github.com/imalsogreg/beads/internal/rpc"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// wantsMarkdown reports whether the client asked for markdown with
+// Accept: text/markdown (JSON wins if both are accepted)
+func (s *Server) wantsMarkdown(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/markdown") && !s.wantsJSON(r)
+}
+
+// formatMarkdown renders the operations that have a markdown form: issues
+// and lists of issues. ok is false for the rest, which fall back to text.
+func formatMarkdown(operation string, data json.RawMessage) (string, bool) {
+	switch operation {
+	case rpc.OpCreate, rpc.OpShow:
+		var issue *types.Issue
+		if err := json.Unmarshal(data, &issue); err != nil || issue == nil {
+			return "", false
+		}
+		return markdown.Issue(markdown.Detail{Issue: issue}), true
+
+	case rpc.OpList, rpc.OpReady:
+		var issues []*types.Issue
+		if err := json.Unmarshal(data, &issues); err != nil {
+			return "", false
+		}
+		return markdown.IssueList(issues), true
+
+	case opSearch:
+		var hits []*sqlite.SearchHit
+		if err := json.Unmarshal(data, &hits); err != nil {
+			return "", false
+		}
+		issues := make([]*types.Issue, len(hits))
+		for i, hit := range hits {
+			issues[i] = hit.Issue
+		}
+		return markdown.IssueList(issues), true
+	}
+	return "", false
+}
+
+func writeMarkdown(w http.ResponseWriter, text string) {
+	w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, text)
+}
+
+// issueDetail gathers what the markdown form of GET /issues/{id} shows
+// along with the issue
+func (s *Server) issueDetail(ctx context.Context, issue *types.Issue) markdown.Detail {
+	d := markdown.Detail{Issue: issue}
+	d.Labels, _ = s.storage.GetLabels(ctx, issue.ID)
+	d.Dependencies, _ = s.storage.GetDependencies(ctx, issue.ID)
+	d.Dependents, _ = s.storage.GetDependents(ctx, issue.ID)
+	d.Comments, _ = s.storage.GetIssueComments(ctx, issue.ID)
+	return d
+}
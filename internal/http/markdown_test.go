@@ -0,0 +1,69 @@
+package http
+
+import (
+	"context"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestMarkdownResponses(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	issue := &types.Issue{Title: "Ship it", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask,
+		AcceptanceCriteria: "- tests pass\n- docs updated"}
+	if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AddLabel(ctx, issue.ID, "release", "tester"); err != nil {
+		t.Fatal(err)
+	}
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	get := func(path, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Accept", accept)
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("/issues/"+issue.ID, "text/markdown")
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/markdown") {
+		t.Fatalf("Expected text/markdown, got %q: %s", ct, rec.Body.String())
+	}
+	for _, want := range []string{"# " + issue.ID + ": Ship it", "**Labels:** `release`", "- [ ] tests pass\n- [ ] docs updated"} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("Expected %q in:\n%s", want, rec.Body.String())
+		}
+	}
+
+	rec = get("/issues", "text/markdown")
+	if !strings.Contains(rec.Body.String(), "| "+issue.ID+" | Ship it | open | P1 | task |") {
+		t.Errorf("Expected a markdown table, got:\n%s", rec.Body.String())
+	}
+
+	// Endpoints without a markdown form answer with text; JSON wins when both are accepted
+	if ct := get("/status", "text/markdown").Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Expected text/plain for status, got %q", ct)
+	}
+	if ct := get("/issues", "text/markdown, application/json").Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected application/json, got %q", ct)
+	}
+}
@@ -0,0 +1,87 @@
+package http
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/backup"
+	"github.com/imalsogreg/beads/internal/rpc"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// serverMetrics is the body of GET /metrics: the daemon's metrics snapshot
+// plus what only the HTTP server tracks
+type serverMetrics struct {
+	rpc.MetricsSnapshot
+	RateLimit *RateLimitStats `json:"rate_limit,omitempty"` // Only when rate limiting is enabled
+	Cache     *CacheStats     `json:"cache,omitempty"`      // Only when the query cache is enabled
+}
+
+// serverStatus is the body of GET /status: the daemon's status plus what
+// only the HTTP server tracks
+type serverStatus struct {
+	rpc.StatusResponse
+	Backups *backup.Status `json:"backups,omitempty"` // Only with scheduled backups
+}
+
+// recordRequest counts a finished request under its method and route, e.g.
+// "GET /issues/{id}", so the operations stay few however many issues there
+// are. Responses of 400 and up count as errors.
+func (s *Server) recordRequest(operation string, status int, latency time.Duration) {
+	if s.parent != nil {
+		s.parent.recordRequest(operation, status, latency)
+		return
+	}
+	s.metrics.RecordRequest(operation, latency)
+	if status >= http.StatusBadRequest {
+		s.metrics.RecordError(operation)
+	}
+	s.lastActivity.Store(time.Now())
+}
+
+// handleStatus handles GET /status
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	status := serverStatus{StatusResponse: rpc.StatusResponse{
+		Version:       rpc.ServerVersion,
+		PID:           os.Getpid(),
+		UptimeSeconds: time.Since(s.startTime).Seconds(),
+	}}
+	if lastActivity, ok := s.lastActivity.Load().(time.Time); ok {
+		status.LastActivityTime = lastActivity.Format(time.RFC3339)
+	}
+	if sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage); ok {
+		status.DatabasePath = sqliteStore.Path()
+		beadsDir := filepath.Dir(status.DatabasePath)
+		status.WorkspacePath = filepath.Dir(beadsDir)
+		if skip, holder, _ := types.ShouldSkipDatabase(beadsDir); skip {
+			status.ExclusiveLockActive = true
+			status.ExclusiveLockHolder = holder
+		}
+	}
+	if s.backups != nil {
+		status.Backups = s.backups.Status()
+	}
+	s.writeSuccess(w, r, status, rpc.OpStatus)
+}
+
+// handleMetrics handles GET /metrics
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	drain := s.DrainStatus()
+	metrics := serverMetrics{MetricsSnapshot: s.metrics.Snapshot(drain.Connections + drain.WebSockets)}
+	if s.limiter != nil {
+		stats := s.limiter.snapshot()
+		metrics.RateLimit = &stats
+	}
+	if s.cache != nil {
+		stats := s.cache.snapshot()
+		metrics.Cache = &stats
+	}
+	if wantsPrometheus(r) {
+		s.writePrometheus(w, &metrics)
+		return
+	}
+	s.writeSuccess(w, r, metrics, opMetrics)
+}
@@ -0,0 +1,77 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/rpc"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+)
+
+func TestStatusAndMetrics(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), ".beads", "test.db")
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	store, err := sqlite.New(dbPath)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(context.Background(), "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Accept", "application/json")
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	get("/issues")
+	get("/issues")
+	get("/issues?status=done")
+	get("/issues/stats")
+
+	var status rpc.StatusResponse
+	if err := json.Unmarshal(get("/status").Body.Bytes(), &status); err != nil {
+		t.Fatal(err)
+	}
+	if status.PID != os.Getpid() || status.DatabasePath != store.Path() || status.WorkspacePath != filepath.Dir(filepath.Dir(store.Path())) {
+		t.Errorf("Unexpected status: %+v", status)
+	}
+	if status.LastActivityTime == "" {
+		t.Error("Expected the last request's time")
+	}
+
+	rec := get("/metrics")
+	var metrics serverMetrics
+	if err := json.Unmarshal(rec.Body.Bytes(), &metrics); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("GET /metrics: %d %s", rec.Code, rec.Body)
+	}
+	ops := make(map[string]rpc.OperationMetrics)
+	for _, op := range metrics.Operations {
+		ops[op.Operation] = op
+	}
+	if list := ops["GET /issues"]; list.TotalCount != 3 || list.ErrorCount != 1 || list.Latency.MaxMS <= 0 {
+		t.Errorf("Expected the 400 to count as an error, got %+v", list)
+	}
+	if stats := ops["GET /issues/stats"]; stats.TotalCount != 1 || stats.ErrorCount != 0 {
+		t.Errorf("Expected routes to be counted separately, got %+v", stats)
+	}
+	if metrics.MemoryAllocMB == 0 && metrics.MemorySysMB == 0 || metrics.GoroutineCount == 0 {
+		t.Errorf("Expected memory and goroutine counts, got %+v", metrics.MetricsSnapshot)
+	}
+}
@@ -0,0 +1,236 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func init() {
+	calendarSources = append(calendarSources, milestoneEvents)
+}
+
+// milestoneRequest is the body of POST /milestones
+type milestoneRequest struct {
+	Name            string `json:"name"`
+	StartDate       string `json:"start_date" doc:"YYYY-MM-DD, RFC 3339, today, tomorrow, or +Nd/+Nw"`
+	EndDate         string `json:"end_date" doc:"Last day of the milestone; same formats as start_date"`
+	CapacityMinutes int    `json:"capacity_minutes,omitempty" doc:"Estimated minutes of work the milestone can hold"`
+}
+
+// milestoneIssuesRequest is the body of POST /milestones/{name}/issues
+type milestoneIssuesRequest struct {
+	IDs []string `json:"ids"`
+}
+
+// milestoneDetail is a milestone with the issues assigned to it
+type milestoneDetail struct {
+	sqlite.Milestone
+	IssueList []*types.Issue `json:"issue_list"`
+}
+
+// handleListMilestones handles GET /milestones
+func (s *Server) handleListMilestones(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("milestones require SQLite backend"))
+		return
+	}
+
+	milestones, err := sqliteStore.ListMilestones(r.Context())
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if milestones == nil {
+		milestones = []*sqlite.Milestone{}
+	}
+
+	s.writeSuccess(w, r, milestones, opMilestones)
+}
+
+// handleCreateMilestone handles POST /milestones
+func (s *Server) handleCreateMilestone(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("milestones require SQLite backend"))
+		return
+	}
+
+	var body milestoneRequest
+	if err := s.parseBody(r, &body); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	now := time.Now()
+	start, err := types.ParseDate(body.StartDate, now)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("start_date: %w", err))
+		return
+	}
+	end, err := types.ParseDate(body.EndDate, now)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("end_date: %w", err))
+		return
+	}
+
+	milestone := &sqlite.Milestone{Name: body.Name, StartDate: start, EndDate: end, CapacityMinutes: body.CapacityMinutes, CreatedBy: s.getActor(r)}
+	if err := sqliteStore.CreateMilestone(r.Context(), milestone); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	s.writeSuccess(w, r, []*sqlite.Milestone{milestone}, opMilestones)
+}
+
+// handleShowMilestone handles GET /milestones/{name}
+func (s *Server) handleShowMilestone(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, milestone, ok := s.lookupMilestone(w, r)
+	if !ok {
+		return
+	}
+
+	issues, err := sqliteStore.SearchIssues(r.Context(), "", types.IssueFilter{Milestone: &milestone.Name})
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if issues == nil {
+		issues = []*types.Issue{}
+	}
+
+	s.writeSuccess(w, r, &milestoneDetail{Milestone: *milestone, IssueList: issues}, opMilestone)
+}
+
+// handleDeleteMilestone handles DELETE /milestones/{name}
+func (s *Server) handleDeleteMilestone(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, milestone, ok := s.lookupMilestone(w, r)
+	if !ok {
+		return
+	}
+
+	if err := sqliteStore.DeleteMilestone(r.Context(), milestone.Name, s.getActor(r)); err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.writeSuccess(w, r, map[string]string{"message": "milestone deleted"}, "milestone_delete")
+}
+
+// handleAssignMilestone handles POST /milestones/{name}/issues
+func (s *Server) handleAssignMilestone(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, milestone, ok := s.lookupMilestone(w, r)
+	if !ok {
+		return
+	}
+
+	var body milestoneIssuesRequest
+	if err := s.parseBody(r, &body); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if len(body.IDs) == 0 {
+		s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("ids is required"))
+		return
+	}
+
+	if err := sqliteStore.AssignMilestone(r.Context(), milestone.Name, body.IDs, s.getActor(r)); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	s.showMilestoneAfterChange(w, r, sqliteStore, milestone.Name)
+}
+
+// handleUnassignMilestone handles DELETE /milestones/{name}/issues/{id}
+func (s *Server) handleUnassignMilestone(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, milestone, ok := s.lookupMilestone(w, r)
+	if !ok {
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	issue, err := sqliteStore.GetIssue(r.Context(), id)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if issue == nil || issue.Milestone != milestone.Name {
+		s.writeError(w, r, http.StatusNotFound, fmt.Errorf("issue %s is not in milestone %s", id, milestone.Name))
+		return
+	}
+
+	if err := sqliteStore.AssignMilestone(r.Context(), "", []string{id}, s.getActor(r)); err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	s.showMilestoneAfterChange(w, r, sqliteStore, milestone.Name)
+}
+
+// showMilestoneAfterChange responds with a milestone's updated progress
+func (s *Server) showMilestoneAfterChange(w http.ResponseWriter, r *http.Request, sqliteStore *sqlite.SQLiteStorage, name string) {
+	milestone, err := sqliteStore.GetMilestone(r.Context(), name)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	s.writeSuccess(w, r, []*sqlite.Milestone{milestone}, opMilestones)
+}
+
+// lookupMilestone resolves the {name} route variable, writing an error
+// response if the backend or milestone is missing
+func (s *Server) lookupMilestone(w http.ResponseWriter, r *http.Request) (*sqlite.SQLiteStorage, *sqlite.Milestone, bool) {
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("milestones require SQLite backend"))
+		return nil, nil, false
+	}
+
+	name := mux.Vars(r)["name"]
+	milestone, err := sqliteStore.GetMilestone(r.Context(), name)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return nil, nil, false
+	}
+	if milestone == nil {
+		s.writeError(w, r, http.StatusNotFound, types.NotFound("milestone", name))
+		return nil, nil, false
+	}
+	return sqliteStore, milestone, true
+}
+
+// milestoneEvents puts each milestone on the calendar as a multi-day event.
+// Milestones span the whole project, so a label or epic filter leaves them out.
+func milestoneEvents(ctx context.Context, s *Server, filter calendarFilter, base string) ([]calendarEvent, error) {
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok || filter.Label != "" || filter.Epic != "" {
+		return nil, nil
+	}
+	milestones, err := sqliteStore.ListMilestones(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]calendarEvent, 0, len(milestones))
+	for _, m := range milestones {
+		// Like due dates, milestone dates are floating days
+		sy, sm, sd := m.StartDate.Local().Date()
+		ey, em, ed := m.EndDate.Local().Date()
+		events = append(events, calendarEvent{
+			UID:         "milestone-" + m.Name,
+			Summary:     "Milestone: " + m.Name,
+			Description: milestoneProgress(m),
+			URL:         base + "/milestones/" + url.PathEscape(m.Name),
+			Start:       time.Date(sy, sm, sd, 0, 0, 0, 0, time.UTC),
+			End:         time.Date(ey, em, ed+1, 0, 0, 0, 0, time.UTC),
+			Categories:  []string{"milestone"},
+		})
+	}
+	return events, nil
+}
@@ -0,0 +1,114 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestMilestones(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	do := func(method, path, body, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Accept", accept)
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do("POST", "/milestones", `{"name": "sprint-1", "start_date": "2025-03-03", "end_date": "2025-03-14", "capacity_minutes": 120}`, "application/json")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	rec = do("POST", "/milestones", `{"name": "sprint-2", "start_date": "soon", "end_date": "2025-03-14"}`, "application/json")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a bad start date, got %d: %s", rec.Code, rec.Body)
+	}
+
+	var ids []string
+	for _, mins := range []int{90, 60} {
+		issue := &types.Issue{Title: "Sprint work", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask, EstimatedMinutes: &mins}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, issue.ID)
+	}
+
+	body, _ := json.Marshal(milestoneIssuesRequest{IDs: ids})
+	rec = do("POST", "/milestones/sprint-1/issues", string(body), "application/json")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	if err := store.CloseIssue(ctx, ids[0], "done", "test"); err != nil {
+		t.Fatal(err)
+	}
+
+	rec = do("GET", "/milestones/sprint-1", "", "application/json")
+	var detail milestoneDetail
+	if err := json.Unmarshal(rec.Body.Bytes(), &detail); err != nil {
+		t.Fatalf("failed to decode %s: %v", rec.Body, err)
+	}
+	if detail.CommittedMinutes != 150 || detail.CompletedMinutes != 90 || len(detail.IssueList) != 2 {
+		t.Errorf("Unexpected milestone %s", rec.Body)
+	}
+
+	text := do("GET", "/milestones", "", "text/plain").Body.String()
+	for _, want := range []string{"sprint-1", "1/2 issue(s) closed", "2h 30m committed, 1h 30m completed of 2h 0m capacity", "over by 30m"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in:\n%s", want, text)
+		}
+	}
+
+	rec = do("GET", "/issues?milestone=sprint-1", "", "application/json")
+	var issues []*types.Issue
+	if err := json.Unmarshal(rec.Body.Bytes(), &issues); err != nil {
+		t.Fatalf("failed to decode %s: %v", rec.Body, err)
+	}
+	if len(issues) != 2 {
+		t.Errorf("Expected 2 issues in sprint-1, got %s", rec.Body)
+	}
+
+	// Milestones span their days on the calendar, end date included
+	ics := do("GET", "/calendar.ics", "", "text/calendar").Body.String()
+	for _, want := range []string{"SUMMARY:Milestone: sprint-1", "DTSTART;VALUE=DATE:20250303", "DTEND;VALUE=DATE:20250315"} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("Expected %q in calendar:\n%s", want, ics)
+		}
+	}
+
+	rec = do("DELETE", "/milestones/sprint-1/issues/"+ids[1], "", "application/json")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	if rec = do("DELETE", "/milestones/sprint-1/issues/"+ids[1], "", "application/json"); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an issue outside the milestone, got %d", rec.Code)
+	}
+
+	if rec = do("DELETE", "/milestones/sprint-1", "", "application/json"); rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	if rec = do("GET", "/milestones/sprint-1", "", "application/json"); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 after delete, got %d", rec.Code)
+	}
+}
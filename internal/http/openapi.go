@@ -0,0 +1,1142 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/backup"
+	"github.com/imalsogreg/beads/internal/git"
+	"github.com/imalsogreg/beads/internal/importer"
+	"github.com/imalsogreg/beads/internal/leadtime"
+	"github.com/imalsogreg/beads/internal/replication"
+	"github.com/imalsogreg/beads/internal/rpc"
+	"github.com/imalsogreg/beads/internal/schedule"
+	"github.com/imalsogreg/beads/internal/stale"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// apiOverview introduces the API in both the OpenAPI document and GET /
+const apiOverview = `AUTHENTICATION
+  All requests (except GET /, /openapi.json, /docs, and /ui/) require Bearer
+  token authentication. Set BEADS_API_SECRET and send:
+    Authorization: Bearer $BEADS_API_SECRET
+  If the server has no secret, API keys, or OIDC, all requests are allowed.
+
+  API keys (POST /keys or 'bd key create') grant one role each:
+    reader  GET requests
+    writer  reader, plus creating and changing issues
+    admin   writer, plus /keys, /webhooks, PUT /config and /workflow,
+            adding and removing /rules, and /admin
+  BEADS_API_SECRET has the admin role. Requests without X-Actor act as the
+  key's name.
+
+  With OIDC enabled (bd serve --oidc-issuer), a JWT from the provider is
+  also accepted as the Bearer token. Its subject is the actor; X-Actor is
+  ignored.
+
+  Actor tracking (optional):
+    Include actor name for audit trail via:
+    - OIDC token subject (always wins when present)
+    - Header: X-Actor: username
+    - Query param: ?actor=username
+    - With mutual TLS (bd serve --client-ca): the client certificate's common name
+    - Default: "http-user"
+
+  Agent sessions (optional):
+    An agent can POST /sessions to start a session, then send
+    X-Session: <id> with its requests. Every change they make records the
+    session, and GET /sessions/{id}/activity lists them. The session must be
+    the request actor's and not have ended.
+
+VERSIONS
+  The API is versioned: call it under /v1/, e.g. GET /v1/issues, or
+  /v1/w/NAME/... for a workspace. Responses carry API-Version: 1. A client
+  whose base URL can't take the prefix may send API-Version: 1 instead.
+
+  Paths without a version still work and answer as v1 does, but they are
+  deprecated: responses carry Deprecation and a Link to the /v1/ path, and
+  a Sunset date once the server announces one (bd serve
+  --unversioned-sunset). An unknown version gets 400 in the header or 404
+  in the path.
+
+CONTENT NEGOTIATION
+  - Accept: application/json → JSON response
+  - Accept: text/plain → Human-readable text (default)
+  - Accept: text/markdown → Markdown for issues and issue lists, for pasting
+    into pull requests and docs (other endpoints answer with text)
+
+RETRIES
+  Send Idempotency-Key: <unique string> with a POST, PATCH, PUT, or DELETE
+  to make it safe to retry. For 24 hours, repeating the request with the same
+  key returns the original response (marked Idempotent-Replayed: true)
+  instead of running it again. Reusing a key for a different request gets
+  422; a retry while the original is still running gets 409. SQLite only.
+
+WEB UI
+  Open /ui/ in a browser for an issue list, a board by status, issue detail
+  with comments, and dependency graphs. It asks for a token when the API
+  needs one and keeps it in the browser's local storage.
+
+CORS
+  Browser apps can call the API from origins allowed with bd serve
+  --cors-origin or the cors.origins config (comma-separated, or *).
+  Preflights for PATCH and DELETE are answered without credentials.
+
+ERRORS
+  Failed requests answer with a JSON body (for Accept: application/json)
+  of the form {"error": "...", "code": "...", "details": {...},
+  "success": false}. code is stable; switch on it rather than the message:
+    bad_request         malformed request or parameters (400)
+    unauthorized        missing or invalid credentials (401)
+    forbidden           the key lacks the role, or the server is read-only (403)
+    not_found           the issue or other record doesn't exist (404);
+                        details: {kind, id}
+    conflict            the issue changed since the version sent (409);
+                        details: {issue_id, expected_version, current_version}
+    dependency_cycle    the dependency would close a cycle (409);
+                        details: {issue_id, depends_on_id}
+    validation_failed   invalid issue or content (422);
+                        details: {violations: [{field, code, message}]}
+    invalid_transition  the workflow doesn't allow the status change (422);
+                        details: {issue_id, from, to, missing, allowed}
+    rate_limited        too many requests (429)
+    not_implemented     the storage backend lacks the feature (501)
+    unavailable         the server or an upstream is down (502, 503)
+    internal            anything else (500)
+
+RATE LIMITS
+  When bd serve runs with rate limits, requests over the limit get 429 with
+  Retry-After: <seconds>. Limits apply overall and per API key (or per actor
+  on requests without a key).
+`
+
+// apiExamples closes the plain-text docs at GET /
+const apiExamples = `EXAMPLES
+
+  Get current prefix:
+    curl -H "Authorization: Bearer $BEADS_API_SECRET" \
+      http://localhost:8080/v1/config/issue_prefix
+
+  Create an issue:
+    curl -X POST http://localhost:8080/v1/issues \
+      -H "Content-Type: application/json" \
+      -H "Authorization: Bearer $BEADS_API_SECRET" \
+      -H "X-Actor: alice" \
+      -d '{"title":"Fix login bug","issue_type":"bug","priority":0}'
+
+  List open issues (JSON):
+    curl -H "Accept: application/json" \
+      -H "Authorization: Bearer $BEADS_API_SECRET" \
+      "http://localhost:8080/v1/issues?status=open"
+
+  Show issue details (text):
+    curl -H "Authorization: Bearer $BEADS_API_SECRET" \
+      http://localhost:8080/v1/issues/bd-1
+`
+
+// apiParam is a query parameter of an API route. Path parameters are
+// derived from the route path.
+type apiParam struct {
+	Name        string
+	Type        string // "string" (default), "integer", or "boolean"
+	Description string
+	Required    bool
+}
+
+// apiRoute documents one endpoint. Bodies and responses are Go values whose
+// types are reflected into schemas, so the handlers' request and response
+// types (mostly from internal/rpc and internal/types) stay the source of truth.
+type apiRoute struct {
+	Method       string
+	Path         string
+	Tag          string
+	Summary      string
+	Description  string
+	Params       []apiParam
+	Body         interface{} // JSON request body, if any
+	BodyType     string      // content type of a non-JSON request body
+	Response     interface{} // JSON response body, if any
+	ResponseType string      // content type of a non-JSON response
+	Public       bool        // served without authentication
+	Markdown     bool        // also answers Accept: text/markdown
+
+	// Example is the arguments of the sample call in GET /tools; without
+	// one, the sample fills in the required arguments
+	Example map[string]interface{}
+}
+
+// messageResponse is the JSON body of endpoints that only acknowledge a change
+type messageResponse struct {
+	Message string `json:"message"`
+}
+
+// configResponse is the JSON body of the config endpoints
+type configResponse struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// expandParam adds related records to the issues of GET /issues and
+// /issues/{id}
+var expandParam = apiParam{Name: "expand", Description: "Comma-separated related records to include with each issue, under keys of the same names: " +
+	"comments, dependencies (the issue's dependency records), children (its subtasks), and history (its revisions; SQLite only). " +
+	"Each is fetched for all the issues at once. JSON responses only"}
+
+var issueFilterParams = []apiParam{
+	{Name: "status", Description: "open, in_progress, blocked, or closed; repeat for any of several, prefix with ! to exclude"},
+	{Name: "priority", Description: "0 (highest) to 4; repeat for any of several, prefix with ! to exclude"},
+	{Name: "assignee", Description: "unassigned for issues with no assignee; repeat for any of several, prefix with ! to exclude"},
+	{Name: "type", Description: "bug, feature, task, epic, or chore; repeat for any of several, prefix with ! to exclude"},
+	{Name: "label", Description: "Only issues with this label (infra/* matches the labels under infra); repeat to require several, prefix with ! to exclude"},
+	{Name: "label_any", Description: "Only issues with at least one of these labels; repeat for several"},
+	{Name: "id", Description: "Only this issue; repeat for several"},
+	{Name: "title", Description: "Only issues whose title contains this text"},
+	{Name: "limit", Type: "integer"},
+	{Name: "include_archived", Type: "boolean", Description: "Include issues moved to the archive by bd archive run"},
+	{Name: "due_before", Description: "Due before this date (YYYY-MM-DD, RFC 3339, today, tomorrow, or +Nd/+Nw)"},
+	{Name: "due_after", Description: "Due on or after this date"},
+	{Name: "overdue", Type: "boolean", Description: "Only open issues whose due date has passed"},
+	{Name: "milestone", Description: "Only issues assigned to this milestone"},
+	{Name: "query", Description: "Query expression, e.g. status:open AND (label:backend OR assignee:alice) AND updated:<7d"},
+	{Name: "sort", Description: "Comma-separated fields to order by, - for descending, e.g. priority,-updated_at; defaults to priority, newest first"},
+}
+
+// apiRoutes lists every documented endpoint, grouped by tag in display order
+var apiRoutes = []apiRoute{
+	{Method: "GET", Path: "/", Tag: "Meta", Summary: "Plain-text API documentation", ResponseType: "text/plain", Public: true},
+	{Method: "GET", Path: "/openapi.json", Tag: "Meta", Summary: "OpenAPI 3.0 document for this API", ResponseType: "application/json", Public: true},
+	{Method: "GET", Path: "/docs", Tag: "Meta", Summary: "Swagger UI for the OpenAPI document", ResponseType: "text/html", Public: true},
+	{Method: "GET", Path: "/tools", Tag: "Meta", Summary: "Function-calling tool manifest for LLM agents",
+		Description: "Every endpoint that takes or returns JSON as a tool: a name, a description, a self-contained JSON Schema of its parameters, " +
+			"how each parameter maps onto the request (path, query, or body), the API key role it needs, and an example call. " +
+			"Generated from the same request types as /openapi.json.",
+		ResponseType: "application/json", Public: true},
+	{Method: "GET", Path: "/ui", Tag: "Meta", Summary: "Redirect to the web UI", ResponseType: "text/html", Public: true},
+	{Method: "GET", Path: "/ui/", Tag: "Meta", Summary: "Web UI: issue list, board, issue detail, and dependency graph",
+		Description: "Static files for a single-page app that uses this API. Paths under /ui/ serve its scripts and styles.", ResponseType: "text/html", Public: true},
+	{Method: "GET", Path: "/health", Tag: "Meta", Summary: "Health check",
+		Description: "The same report as /readyz, for callers with a token.",
+		Response:    HealthReport{}},
+	{Method: "GET", Path: "/healthz", Tag: "Meta", Summary: "Liveness probe",
+		Description: "200 whenever the process is serving requests; checks nothing else.",
+		Response:    HealthReport{}, Public: true},
+	{Method: "GET", Path: "/readyz", Tag: "Meta", Summary: "Readiness probe",
+		Description: "Checks the database answers, its migrations are applied, and the background workers' last rounds succeeded. " +
+			"status is the worst of the checks: healthy, degraded (a worker failed; still serving, 200), or unhealthy (503), which draining also causes.",
+		Response: HealthReport{}, Public: true},
+	{Method: "GET", Path: "/ping", Tag: "Meta", Summary: "Ping server", Response: map[string]string{}},
+	{Method: "GET", Path: "/status", Tag: "Meta", Summary: "Server status",
+		Description: "Version, process ID, uptime, database path, time of the last request, and whether an exclusive lock is held on the database. " +
+			"backups reports scheduled backups when bd serve runs with --backup-interval.",
+		Response: serverStatus{}},
+	{Method: "GET", Path: "/metrics", Tag: "Meta", Summary: "Server metrics",
+		Description: "Requests are counted by method and route, e.g. GET /issues/{id}, with latency percentiles over the last 1000 of each; responses of 400 and up count as errors. " +
+			"active_connections includes idle keep-alive connections and WebSockets; rejected_connections counts requests turned away while draining. " +
+			"rate_limit counts requests throttled with 429, in total and by client (key:<name> or actor:<name>), when bd serve runs with rate limits. " +
+			"cache counts query cache hits and misses (invalidations are misses because the database changed, expirations because a result outlived --cache-ttl). " +
+			"Prometheus scrapes (by their Accept header, or ?format=prometheus) get the text exposition format instead, including scheduled backup status per workspace.",
+		Params:   []apiParam{{Name: "format", Description: "prometheus for the Prometheus text exposition format"}},
+		Response: serverMetrics{}},
+
+	{Method: "GET", Path: "/workspaces", Tag: "Workspaces", Summary: "List workspaces",
+		Description: "With bd serve --workspace, other projects' databases are served under /w/{workspace}/ with this same API, each checking tokens against its own API keys; " +
+			"the server's own database is the default workspace, at the top level and under /w/default/. " +
+			"Lists the workspaces the token can read, with their issue counts.",
+		Response: []WorkspaceInfo{}},
+	{Method: "GET", Path: "/workspaces/issues", Tag: "Workspaces", Summary: "List issues across workspaces",
+		Description: "Issues from every workspace the token can read, each labeled with its workspace. limit applies per workspace and to the whole list.",
+		Params:      issueFilterParams, Response: []workspaceIssue{}},
+
+	{Method: "POST", Path: "/issues", Tag: "Issues", Summary: "Create issue",
+		Description: "An invalid issue gets 422 (validation_failed) with details.violations, one {field, code, message} per problem: code is required, too_long, out_of_range, or invalid. " +
+			"Titles are 1-500 characters, priorities 0-4, and types bug, feature, task, epic, or chore. " +
+			"Config keys validation.required.<type> list fields issues of a type must have, e.g. validation.required.bug = description,acceptance_criteria.",
+		Body: rpc.CreateArgs{}, Response: types.Issue{}, Markdown: true,
+		Example: map[string]interface{}{"title": "Fix login bug", "issue_type": "bug", "priority": 1}},
+	{Method: "GET", Path: "/issues", Tag: "Issues", Summary: "List issues",
+		Description: "With SQLite, q is a ranked full-text search (see /issues/search). " +
+			"Each issue carries dependency_count, dependent_count (trashed dependents aside), and comment_count. " +
+			"With Accept: application/x-ndjson the issues stream one per line as they are read, in batches of 500 with their expansions, untagged and uncached, so listing a whole workspace (limit=0) takes little server memory. " +
+			"The ETag header hashes the response, and a poll sending it back in If-None-Match gets 304 if nothing changed; the same holds for /issues/ready and /issues/search.",
+		Params: append([]apiParam{{Name: "q", Description: "Search text"},
+			{Name: "fields", Description: "Comma-separated issue fields to return, e.g. id,title,status; JSON responses only"},
+			expandParam}, issueFilterParams...),
+		Response: []*types.Issue{}, Markdown: true,
+		Example: map[string]interface{}{"status": "open", "label": "backend", "limit": 20}},
+	{Method: "PATCH", Path: "/issues", Tag: "Issues", Summary: "Update every issue matching a filter",
+		Description: "Runs in one transaction; a filter is required. Example body:\n" +
+			`{"filter": {"status": "open", "labels": ["infra"]}, "updates": {"assignee": "bob"}}`,
+		Body: bulkUpdateRequest{}, Response: bulkUpdateResult{}},
+	{Method: "GET", Path: "/issues/ready", Tag: "Issues", Summary: "Open issues with no open blockers",
+		Params: readyParams, Response: []*types.Issue{}, Markdown: true,
+		Example: map[string]interface{}{"assignee": "alice", "limit": 5}},
+	{Method: "GET", Path: "/issues/ready/queues", Tag: "Issues", Summary: "Ready work split into a queue per assignee",
+		Description: "Takes the same filters as /issues/ready; limit applies to each queue. Unassigned work comes last, with an empty assignee.",
+		Params:      readyParams, Response: []*types.ReadyQueue{}},
+	{Method: "GET", Path: "/issues/stats", Tag: "Issues", Summary: "Database statistics",
+		Description: "With group_by, groups counts issues by status for each value of the dimension. Grouping by label counts an issue under each of its labels; unassigned and unlabeled issues have an empty key.",
+		Params:      []apiParam{{Name: "group_by", Description: "status, assignee, label, type, or priority"}},
+		Response:    types.Statistics{}},
+	{Method: "GET", Path: "/issues/stale", Tag: "Issues", Summary: "In-progress issues with no recent updates",
+		Description: "Quietest first. The threshold is config stale.days (default 7) unless days is given.",
+		Params:      []apiParam{{Name: "days", Type: "integer", Description: "Days without updates"}},
+		Response:    []*stale.Issue{}},
+	{Method: "GET", Path: "/issues/blocked", Tag: "Issues", Summary: "Open issues waiting on open blockers",
+		Description: "Each lists the IDs of the open issues it depends on with blocks dependencies.",
+		Response:    []*types.BlockedIssue{}},
+	{Method: "GET", Path: "/issues/{id}", Tag: "Issues", Summary: "Show issue details",
+		Description: "Includes parent_id and a subtasks roll-up (total, closed, in_progress, blocked) when the issue has children. " +
+			"The ETag header carries the issue's version for conditional updates. " +
+			"Polls sending If-None-Match or If-Modified-Since get 304 while the issue itself is unchanged; subtask and link changes alone don't count. " +
+			"The text/markdown form also includes labels, dependencies, and comments. " +
+			"An ID replaced by a prefix rename (bd prefix set --rewrite) gets 301 to the issue's new ID. SQLite only. " +
+			"With expand, the issue comes with its comments, dependency records, children, or history in one call, and polls always get the full response.",
+		Params: []apiParam{expandParam}, Response: types.Issue{}, Markdown: true},
+	{Method: "PATCH", Path: "/issues/{id}", Tag: "Issues", Summary: "Update issue",
+		Description: "Send If-Match with the ETag from GET /issues/{id} (or expected_version in the body) to update only if nobody else has changed the issue since. " +
+			"A stale version gets 409 with current_version and the current issue. Without either the update always applies. Conditional updates are SQLite only. " +
+			"Invalid values, or clearing a field the issue's type requires, get 422 with details.violations as for POST /issues.",
+		Body: rpc.UpdateArgs{}, Response: types.Issue{},
+		Example: map[string]interface{}{"id": "bd-1", "status": "in_progress", "assignee": "alice"}},
+	{Method: "POST", Path: "/issues/{id}/close", Tag: "Issues", Summary: "Close issue", Body: closeRequest{}, Response: messageResponse{},
+		Example: map[string]interface{}{"id": "bd-1", "reason": "Fixed in the login handler"}},
+	{Method: "DELETE", Path: "/issues/{id}", Tag: "Issues", Summary: "Move an issue to the trash",
+		Description: "A soft delete: the issue keeps its links and history but is hidden from listings, search, ready work, and stats. " +
+			"Restore it with POST /issues/{id}/restore; compaction purges it after compact_trash_days (default 30). SQLite only.",
+		Response: messageResponse{}},
+	{Method: "POST", Path: "/issues/{id}/restore", Tag: "Issues", Summary: "Restore an issue from the trash", Response: messageResponse{}},
+	{Method: "GET", Path: "/trash", Tag: "Issues", Summary: "Issues in the trash, most recently deleted first", Response: []*types.Issue{}},
+	{Method: "POST", Path: "/issues/{id}/revert", Tag: "Issues", Summary: "Undo your last change to an issue",
+		Description: "Reverts the caller's most recent update, close, or label change on {id} that hasn't been reverted, restoring the prior values. " +
+			"Calling it again undoes the change before that. 404 if there's nothing left to revert; 409 if a field has since been changed by a later edit. SQLite only.",
+		Response: sqlite.IssueRevision{}},
+	{Method: "POST", Path: "/issues/{id}/merge", Tag: "Issues", Summary: "Merge an issue into another",
+		Description: "Moves {id}'s dependencies, comments, and labels to the target, rewrites mentions of {id} in other issues, " +
+			"then links {id} to the target as a duplicate and closes it. Safe to retry.",
+		Body: mergeIntoRequest{}, Response: types.MergeResult{}},
+
+	{Method: "GET", Path: "/issues/{id}/comments", Tag: "Comments and labels", Summary: "List comments",
+		Description: "Oldest first. reply_to threads a comment under the one it answers; updated_at is set once a comment is edited, " +
+			"and reactions counts the actors who reacted each way.",
+		Response: []*types.Comment{}},
+	{Method: "GET", Path: "/issues/{id}/history", Tag: "Comments and labels", Summary: "Every revision of an issue",
+		Description: "Oldest first. Each revision lists the fields it changed with before and after values; revision 1 is the creation. SQLite only.",
+		Response:    []*sqlite.IssueRevision{}},
+	{Method: "POST", Path: "/issues/{id}/comments", Tag: "Comments and labels", Summary: "Add comment",
+		Description: "With reply_to, the comment answers another comment on the same issue.",
+		Body:        commentRequest{}, Response: types.Comment{}},
+	{Method: "PATCH", Path: "/issues/{id}/comments/{cid}", Tag: "Comments and labels", Summary: "Edit a comment",
+		Description: "The text it replaces is kept in the comment's edit history.",
+		Body:        commentEditRequest{}, Response: types.Comment{}},
+	{Method: "DELETE", Path: "/issues/{id}/comments/{cid}", Tag: "Comments and labels", Summary: "Delete a comment",
+		Description: "Removes the comment with its edit history and reactions. Replies to it move up to answer the comment it answered, if any.",
+		Response:    messageResponse{}},
+	{Method: "GET", Path: "/issues/{id}/comments/{cid}/edits", Tag: "Comments and labels", Summary: "A comment's earlier versions",
+		Description: "Oldest first, each with the text an edit replaced and who made the edit.",
+		Response:    []*types.CommentEdit{}},
+	{Method: "POST", Path: "/issues/{id}/comments/{cid}/reactions", Tag: "Comments and labels", Summary: "React to a comment",
+		Description: "Reacting the same way twice counts once.",
+		Body:        reactionRequest{}, Response: types.Comment{}},
+	{Method: "DELETE", Path: "/issues/{id}/comments/{cid}/reactions/{reaction}", Tag: "Comments and labels", Summary: "Take back a reaction",
+		Response: types.Comment{}},
+	{Method: "GET", Path: "/mentions", Tag: "Comments and labels", Summary: "Comments that @mention a user",
+		Description: "Newest first. Mentions in markdown code don't count. Each new mention also triggers the issue.mentioned webhook event.",
+		Params:      []apiParam{{Name: "user", Description: "Whose mentions to list (default: the caller)"}},
+		Response:    []*types.Mention{}},
+	{Method: "GET", Path: "/issues/{id}/labels", Tag: "Comments and labels", Summary: "List an issue's labels", Response: []string{}},
+	{Method: "POST", Path: "/issues/{id}/labels", Tag: "Comments and labels", Summary: "Add label", Body: labelRequest{}, Response: messageResponse{}},
+	{Method: "DELETE", Path: "/issues/{id}/labels/{label:.+}", Tag: "Comments and labels", Summary: "Remove label", Response: messageResponse{}},
+
+	{Method: "POST", Path: "/issues/{id}/dependencies", Tag: "Dependencies", Summary: "Add dependency", Body: dependencyRequest{}, Response: messageResponse{},
+		Example: map[string]interface{}{"id": "bd-1", "depends_on": "bd-2", "type": "blocks"}},
+	{Method: "DELETE", Path: "/issues/{id}/dependencies/{depId}", Tag: "Dependencies", Summary: "Remove dependency", Response: messageResponse{}},
+	{Method: "GET", Path: "/issues/{id}/dependencies", Tag: "Dependencies", Summary: "List what an issue depends on",
+		Description: "The dependency records from {id}, of every type; depends_on_id may name another workspace's issue (remote/bd-12).",
+		Response:    []*types.Dependency{}},
+	{Method: "GET", Path: "/issues/{id}/dependents", Tag: "Dependencies", Summary: "List the issues that depend on an issue", Response: []*types.Issue{}},
+	{Method: "GET", Path: "/dependencies", Tag: "Dependencies", Summary: "Every dependency record", Description: "Ordered by issue ID.", Response: []*types.Dependency{}},
+	{Method: "GET", Path: "/dependencies/cycles", Tag: "Dependencies", Summary: "Find dependency cycles",
+		Description: "Each cycle is the issues along it, in order; the last depends on the first.",
+		Response:    [][]*types.Issue{}},
+	{Method: "GET", Path: "/issues/{id}/tree", Tag: "Dependencies", Summary: "Dependency tree",
+		Description: "With format=dot or format=mermaid the graph is rendered as text; DOT nodes are filled by status and shaped by issue type. " +
+			"format=graph returns {root, issues, edges} JSON for clients that lay out the graph themselves. " +
+			"Dependencies on other workspaces' issues (remote/bd-12) are fetched from the remotes in config.yaml and listed as leaves with remote set, " +
+			"or with unreachable set if the fetch failed; the graph formats leave them out.",
+		Params: []apiParam{
+			{Name: "max_depth", Type: "integer", Description: "Default 10"},
+			{Name: "reverse", Type: "boolean", Description: "Show dependents instead of dependencies"},
+			{Name: "format", Description: "dot, mermaid, or graph"},
+		},
+		Response: []*types.TreeNode{}},
+	{Method: "GET", Path: "/issues/{id}/impact", Tag: "Dependencies", Summary: "Open issues this issue holds up",
+		Description: "Follows blocks dependencies transitively from {id}, and from each blocked issue to its children, as ready work does. " +
+			"Blocked issues are listed nearest first, with the issue holding each one up; closed issues are skipped, and a closed {id} blocks nothing. " +
+			"highest_priority is the most urgent priority among them, for triaging which blockers to clear first.",
+		Response: types.BlockingImpact{}},
+	{Method: "GET", Path: "/issues/{id}/blockers", Tag: "Dependencies", Summary: "Open issues holding up this issue",
+		Description: "Lists the open issues {id} depends on with blocks dependencies, and those blocking its ancestors, as ready work does. " +
+			"With transitive, the blockers' own blockers are followed too. Blockers are listed nearest first, with depth, status, and assignee; " +
+			"blocks is the issue in the tree each one holds up, and via the ancestor of it the blocker depends on, if any.",
+		Params: []apiParam{
+			{Name: "transitive", Type: "boolean", Description: "Include blockers of blockers"},
+		},
+		Response: types.BlockerTree{}},
+
+	{Method: "GET", Path: "/issues/{id}/commits", Tag: "Git", Summary: "List commits linked to an issue", Response: []*sqlite.CommitLink{}},
+	{Method: "POST", Path: "/issues/{id}/commits", Tag: "Git", Summary: "Report a commit that mentions an issue",
+		Description: "For CI. Links the commit to {id}; reporting the same sha again is a no-op. If the message closes {id} " +
+			`("fixes bd-123", "Closes: bd-4, bd-5") the issue is closed, unless config git.auto_close is false. SQLite only.`,
+		Body: commitRequest{}, Response: git.LinkResult{}},
+
+	{Method: "GET", Path: "/duplicates", Tag: "Duplicates", Summary: "Find issues with identical content",
+		Description: "Issues match when title, description, design, acceptance criteria, and status are all equal. " +
+			"The suggested target is the most referenced issue in the group, then the smallest ID. " +
+			"With fuzzy, near-duplicates whose titles and descriptions share at least that fraction of their words are grouped too, " +
+			"and each issue's score is its similarity to the target.",
+		Params:   []apiParam{{Name: "fuzzy", Type: "number", Description: "Similarity threshold from 0 to 1, e.g. 0.85"}},
+		Response: []*types.DuplicateGroup{}},
+	{Method: "POST", Path: "/duplicates/merge", Tag: "Duplicates", Summary: "Merge duplicate issues",
+		Description: "Moves the sources' dependencies to the target, rewrites mentions of them in other issues, and closes them. " +
+			"Safe to retry. Example bodies:\n" + `{"target_id": "bd-3", "source_ids": ["bd-7"]}` + "\n" + `{"all": true}`,
+		Body: mergeRequest{}, Response: []*types.MergeResult{}},
+
+	{Method: "POST", Path: "/issues/{id}/claim", Tag: "Claims", Summary: "Claim an issue for a time-limited lease",
+		Description: "Flips an open issue to in_progress and assigns it to the request's actor. Claiming again renews the lease. " +
+			"When the lease expires the issue goes back to open and unassigned. 409 if someone else holds it or it isn't open. SQLite only.",
+		Body: claimRequest{}, Response: claimResult{}},
+	{Method: "DELETE", Path: "/issues/{id}/claim", Tag: "Claims", Summary: "Release a claim",
+		Description: "Returns the issue to open and unassigned. 409 unless the request's actor holds a live lease on it.",
+		Response:    messageResponse{}},
+	{Method: "POST", Path: "/issues/ready/claim", Tag: "Claims", Summary: "Claim the next ready issue",
+		Description: "Claims the first ready issue that is open and unassigned or assigned to the request's actor, skipping any claimed meanwhile. " +
+			"Takes the filters of /issues/ready except status. 404 if there is nothing to claim.",
+		Params: readyParams, Body: claimRequest{}, Response: claimResult{}},
+	{Method: "GET", Path: "/claims", Tag: "Claims", Summary: "List live claims", Description: "Soonest to expire first.",
+		Response: []*sqlite.Lease{}},
+
+	{Method: "POST", Path: "/issues/{id}/watch", Tag: "Watching", Summary: "Watch an issue",
+		Description: "Subscribes the request's actor to the issue. Every webhook delivery for the issue lists its watchers, " +
+			"so the receiver can notify them. Watching twice is a no-op. SQLite only.",
+		Response: watchersResult{}},
+	{Method: "DELETE", Path: "/issues/{id}/watch", Tag: "Watching", Summary: "Stop watching an issue", Response: watchersResult{}},
+	{Method: "GET", Path: "/issues/{id}/watchers", Tag: "Watching", Summary: "List an issue's watchers",
+		Description: "Longest-watching first. Issue detail lists them too.", Response: watchersResult{}},
+
+	{Method: "GET", Path: "/assignees/workload", Tag: "Workload", Summary: "Unfinished work per assignee",
+		Description: "Open, in-progress, and blocked counts and summed estimates for everyone with unfinished work, by assignee.",
+		Response:    []*types.AssigneeWorkload{}},
+	{Method: "GET", Path: "/issues/{id}/assignee-suggestions", Tag: "Workload", Summary: "Who could take an issue, least loaded first",
+		Description: "Candidates are people with unfinished work or work updated in the last 90 days. " +
+			"If the issue has labels, only those whose recent work shares one are eligible. " +
+			"Ranked by unfinished issues, then estimated minutes, then most matching issues.",
+		Response: []*types.AssigneeSuggestion{}},
+
+	{Method: "GET", Path: "/issues/{id}/worklogs", Tag: "Time tracking", Summary: "List time logged on an issue", Response: []*sqlite.WorkLog{}},
+	{Method: "POST", Path: "/issues/{id}/worklogs", Tag: "Time tracking", Summary: "Log time spent on an issue",
+		Description: "The entry is recorded for the request's actor. Totals by issue and by assignee appear in GET /issues/stats. SQLite only.",
+		Body:        workLogRequest{}, Response: []*sqlite.WorkLog{}},
+
+	{Method: "GET", Path: "/issues/{id}/children", Tag: "Subtasks", Summary: "List direct subtasks", Response: []*types.Issue{}, Markdown: true},
+	{Method: "POST", Path: "/issues/{id}/subtasks", Tag: "Subtasks", Summary: "Create a subtask",
+		Description: "Subtasks are linked to {id} with a parent-child dependency.",
+		Body:        rpc.CreateArgs{}, Response: types.Issue{}, Markdown: true},
+
+	{Method: "GET", Path: "/epics", Tag: "Epics", Summary: "Every open epic with how many of its children are closed",
+		Description: "eligible_for_close is set when all of an epic's children are closed.",
+		Response:    []*types.EpicStatus{}},
+	{Method: "GET", Path: "/epics/{id}/status", Tag: "Epics", Summary: "Progress of an epic over all its descendants",
+		Description: "Counts the epic's descendants recursively, with nested epics rolled up into it and reported under epics. " +
+			"Percent is by issue count and percent_by_minutes by estimated_minutes, leaving out unestimated issues. " +
+			"Blockers lists the open issues blocking unfinished descendants, most blocking first.",
+		Response: types.EpicRollup{}},
+	{Method: "GET", Path: "/epics/{id}/critical-path", Tag: "Epics", Summary: "Longest chain of unfinished work",
+		Description: "Follows blocks dependencies between the epic's open descendants, weighted by estimated_minutes, with issues in blocking order.",
+		Response:    types.CriticalPath{}},
+	{Method: "GET", Path: "/epics/{id}/schedule", Tag: "Epics", Summary: "Projected start and finish dates of an epic's unfinished work",
+		Description: "Orders the epic's open descendants by blocks dependencies; each starts when its blockers finish, or on its start_date if later. " +
+			"Estimates are split into working days of hours_per_day, and unestimated issues take a day. Nested epics become sections. " +
+			"With format=mermaid-gantt the schedule is a Mermaid Gantt chart; with format=csv, a CSV file. 409 if the dependencies have a cycle.",
+		Params: []apiParam{
+			{Name: "format", Description: "mermaid-gantt or csv"},
+			{Name: "from", Description: "First day work can start (YYYY-MM-DD, today, tomorrow, or +Nd/+Nw); default today"},
+			{Name: "hours_per_day", Type: "integer", Description: "Working hours in a day (default 8)"},
+		},
+		Response: schedule.Schedule{}},
+
+	{Method: "GET", Path: "/analytics/lead-time", Tag: "Analytics", Summary: "Lead and cycle time distributions of closed issues",
+		Description: "Lead time runs from creation to close and cycle time from the first move to in_progress to close. " +
+			"Each distribution has percentiles in hours and a histogram over buckets. " +
+			"Grouping by label counts an issue under each of its labels; unassigned and unlabeled issues have an empty key. SQLite only.",
+		Params: []apiParam{
+			{Name: "group_by", Description: "label, assignee, type, or priority"},
+			{Name: "days", Type: "integer", Description: "Only issues closed in the last this many days"},
+		},
+		Response: leadtime.Report{}},
+
+	{Method: "GET", Path: "/issues/search", Tag: "Search", Summary: "Full-text search with ranked snippets",
+		Description: "Searches title, description, design, acceptance criteria, notes, and comments. " +
+			`Words match as prefixes; "quoted text" as a phrase. JSON snippets wrap matches in <mark></mark> ` +
+			"(text responses use [ ]). SQLite only.",
+		Params:   append([]apiParam{{Name: "q", Required: true, Description: "Search text"}}, issueFilterParams...),
+		Response: []*sqlite.SearchHit{}, Markdown: true},
+
+	{Method: "GET", Path: "/config", Tag: "Configuration", Summary: "Every config value, by key", Response: map[string]string{}},
+	{Method: "GET", Path: "/config/{key}", Tag: "Configuration", Summary: "Get config value (e.g., issue_prefix)", Response: configResponse{}},
+	{Method: "PUT", Path: "/config/{key}", Tag: "Configuration", Summary: "Set config value",
+		Description: "workflow and validation.required.<type> (comma-separated fields from assignee, description, design, acceptance_criteria, notes, estimated_minutes, due_date, milestone, external_ref) are checked first; invalid values get 400.",
+		Body:        configRequest{}, Response: configResponse{}},
+	{Method: "DELETE", Path: "/config/{key}", Tag: "Configuration", Summary: "Delete a config value", Description: "Admin only.", Response: messageResponse{}},
+	{Method: "GET", Path: "/workflow", Tag: "Configuration", Summary: "The statuses issues can be in and the moves between them",
+		Description: "With from, next lists the moves allowed from that status and the fields each requires. " +
+			"Status changes the workflow doesn't allow, or that lack a required field, fail with 422.",
+		Params:   []apiParam{{Name: "from", Description: "A status to list the next moves from"}},
+		Response: workflowResponse{}},
+	{Method: "PUT", Path: "/workflow", Tag: "Configuration", Summary: "Replace the workflow",
+		Description: "Must keep the built-in statuses; custom ones count as open, in_progress, or blocked. " +
+			"With no transitions, any move is allowed. Fails with 400 if issues are in a status the new workflow drops. Admin only. SQLite only.",
+		Body: types.Workflow{}, Response: workflowResponse{}},
+
+	{Method: "GET", Path: "/ws", Tag: "Streaming", Summary: "WebSocket stream of issue changes",
+		Description: "Authenticate with the Authorization header or ?token=<secret>.\n" +
+			`Send {"type": "subscribe", "filter": {"labels": [...], "assignee": "...", "status": ["open", ...]}} or {"type": "unsubscribe"}.` + "\n" +
+			`Receive {"type": "snapshot", "issues": [...]} after subscribing, then {"type": "add", "issue": {...}}, ` +
+			`{"type": "patch", "id": "...", "patch": [JSON Patch ops]}, and {"type": "remove", "id": "...", "reason": "deleted|filtered"}.` + "\n" +
+			"Labels match any of the listed labels. The server pings every 30s; connections that stop answering are closed.",
+		Params: []apiParam{{Name: "token", Description: "API secret, for clients that cannot set headers"}}},
+
+	{Method: "GET", Path: "/webhooks", Tag: "Webhooks", Summary: "List webhooks", Response: []*sqlite.Webhook{}},
+	{Method: "POST", Path: "/webhooks", Tag: "Webhooks", Summary: "Register a webhook",
+		Description: "The server POSTs issue.created, issue.updated, issue.closed, issue.commented, and issue.mentioned events as JSON " +
+			"with X-Beads-Event and X-Beads-Delivery headers. With a secret, X-Beads-Signature carries " +
+			"sha256=<hex HMAC-SHA256 of the body>. Each delivery lists the issue's watchers. " +
+			"Failed deliveries are retried with exponential backoff. SQLite only.",
+		Body: webhookRequest{}, Response: []*sqlite.Webhook{}},
+	{Method: "DELETE", Path: "/webhooks/{id}", Tag: "Webhooks", Summary: "Remove a webhook", Response: messageResponse{}},
+	{Method: "POST", Path: "/webhooks/{id}/test", Tag: "Webhooks", Summary: "Send a ping delivery",
+		Description: "Sends one ping event without retries; fails with 502 if the endpoint doesn't accept it.",
+		Response:    messageResponse{}},
+
+	{Method: "GET", Path: "/milestones", Tag: "Milestones", Summary: "List milestones with their progress",
+		Description: "committed_minutes sums estimated_minutes over every assigned issue; completed_minutes over the closed ones. SQLite only.",
+		Response:    []*sqlite.Milestone{}},
+	{Method: "POST", Path: "/milestones", Tag: "Milestones", Summary: "Create a milestone", Body: milestoneRequest{}, Response: []*sqlite.Milestone{}},
+	{Method: "GET", Path: "/milestones/{name}", Tag: "Milestones", Summary: "Show a milestone and its issues", Response: milestoneDetail{}},
+	{Method: "DELETE", Path: "/milestones/{name}", Tag: "Milestones", Summary: "Delete a milestone",
+		Description: "Its issues are taken out of the milestone, not deleted.", Response: messageResponse{}},
+	{Method: "POST", Path: "/milestones/{name}/issues", Tag: "Milestones", Summary: "Assign issues to a milestone",
+		Description: "Issues move from any milestone they were in. Assigning through PATCH /issues/{id} with milestone also works.",
+		Body:        milestoneIssuesRequest{}, Response: []*sqlite.Milestone{}},
+	{Method: "DELETE", Path: "/milestones/{name}/issues/{id}", Tag: "Milestones", Summary: "Take an issue out of a milestone", Response: []*sqlite.Milestone{}},
+
+	{Method: "GET", Path: "/labels", Tag: "Labels", Summary: "List labels with their metadata and usage",
+		Description: "Every defined label and every label an issue carries, sorted by name. SQLite only.",
+		Response:    []*sqlite.Label{}},
+	{Method: "POST", Path: "/labels", Tag: "Labels", Summary: "Define a label",
+		Description: "Gives a label a color, description, and parent. Filtering by parent/* matches its children at any depth.",
+		Body:        labelDefinitionRequest{}, Response: sqlite.Label{}},
+	{Method: "GET", Path: "/labels/{name:.+}", Tag: "Labels", Summary: "Show a label and its children",
+		Description: "Names may contain slashes, e.g. /labels/infra/db.", Response: sqlite.Label{}},
+	{Method: "PATCH", Path: "/labels/{name:.+}", Tag: "Labels", Summary: "Update a label definition", Body: labelUpdateRequest{}, Response: sqlite.Label{}},
+	{Method: "DELETE", Path: "/labels/{name:.+}", Tag: "Labels", Summary: "Delete a label definition",
+		Description: "Issues keep the label. Labels defined under it fall back to their path parent.", Response: messageResponse{}},
+
+	{Method: "GET", Path: "/keys", Tag: "API keys", Summary: "List API keys", Description: "Admin only. Tokens are never returned.", Response: []*sqlite.APIKey{}},
+	{Method: "POST", Path: "/keys", Tag: "API keys", Summary: "Create an API key",
+		Description: "Admin only. The response is the only time the token is shown. SQLite only.",
+		Body:        keyRequest{}, Response: createdKey{}},
+	{Method: "DELETE", Path: "/keys/{id}", Tag: "API keys", Summary: "Revoke an API key", Description: "Admin only.", Response: messageResponse{}},
+
+	{Method: "POST", Path: "/import", Tag: "Import and export", Summary: "Upsert issues from a JSONL or JSON array body",
+		Description: "merge-newer replaces an existing issue only if the imported updated_at is later. dry_run=true reports changes without writing. SQLite only.",
+		Params: []apiParam{
+			{Name: "strategy", Description: "skip-existing (default), overwrite, or merge-newer"},
+			{Name: "dry_run", Type: "boolean"},
+			{Name: "strict", Type: "boolean"},
+			{Name: "rename_on_import", Type: "boolean"},
+		},
+		Body: []*types.Issue{}, BodyType: "application/x-ndjson", Response: importer.MergeResult{}},
+	{Method: "POST", Path: "/export", Tag: "Import and export", Summary: "Export (not implemented)"},
+	{Method: "POST", Path: "/compact", Tag: "Import and export", Summary: "Compact old closed issues",
+		Description: "Tier 1 (default) replaces an issue's text with an AI summary and needs ANTHROPIC_API_KEY on the server or api_key in the body. " +
+			"Tier 2 cuts a Tier 1 summary to one sentence and drops verbose events. Without issue_id every candidate for the tier is compacted " +
+			"and expired trash is purged. dry_run=true (query or body) reports what would change. Per-issue failures are reported in results. SQLite only.",
+		Params: []apiParam{{Name: "dry_run", Type: "boolean"}},
+		Body:   rpc.CompactArgs{}, Response: rpc.CompactResponse{}},
+	{Method: "GET", Path: "/compact/stats", Tag: "Import and export", Summary: "Compaction candidates and estimated savings", Response: rpc.CompactStatsData{}},
+	{Method: "POST", Path: "/batch", Tag: "Import and export", Summary: "Batch operations (not implemented)"},
+
+	{Method: "GET", Path: "/feed.atom", Tag: "Feeds", Summary: "Atom feed of creates, closes, and comments",
+		Description: "Set config feed.public=true to allow reads without a token.",
+		Params: []apiParam{
+			{Name: "label"},
+			{Name: "epic", Description: "Includes descendants"},
+			{Name: "limit", Type: "integer", Description: "Default 50"},
+		},
+		ResponseType: "application/atom+xml"},
+	{Method: "GET", Path: "/calendar.ics", Tag: "Feeds", Summary: "iCalendar feed of issue dates (all-day events)",
+		Description:  "Set config calendar.public=true so calendar apps can subscribe.",
+		Params:       []apiParam{{Name: "label"}, {Name: "epic"}},
+		ResponseType: "text/calendar"},
+
+	{Method: "GET", Path: "/audit", Tag: "Security", Summary: "Change log with field-level diffs",
+		Description: "Every recorded change, newest first. since and until take RFC 3339 times, dates (2025-01-31), or ages (36h, 7d). " +
+			"Pass next_cursor from a response as cursor to get the next page. SQLite only.",
+		Params: []apiParam{
+			{Name: "actor"},
+			{Name: "session", Description: "Only changes made in this agent session"},
+			{Name: "issue"},
+			{Name: "field", Description: "Only changes to this field, e.g. priority"},
+			{Name: "since"},
+			{Name: "until"},
+			{Name: "cursor", Type: "integer"},
+			{Name: "limit", Type: "integer", Description: "Default 50, at most 500"},
+		},
+		Response: sqlite.AuditPage{}},
+	{Method: "GET", Path: "/redactions", Tag: "Security", Summary: "Secrets detected in issue text on write",
+		Description: "Detection is controlled by config key secrets.mode: off (default), flag, mask, or reject (writes fail with 422).",
+		Params:      []apiParam{{Name: "issue"}, {Name: "limit", Type: "integer"}},
+		Response:    []*sqlite.SecretRedaction{}},
+
+	{Method: "GET", Path: "/actors", Tag: "Actors", Summary: "List registered actors",
+		Description: "Actors are free-form names. Registering one makes its aliases, its email, and any casing of its name " +
+			"resolve to it wherever an actor or assignee is written. SQLite only.",
+		Response: []*sqlite.Actor{}},
+	{Method: "POST", Path: "/actors", Tag: "Actors", Summary: "Register an actor",
+		Description: "Fails with 409 if the name, an alias, or the email already resolves to another actor.",
+		Body:        actorRequest{}, Response: []*sqlite.Actor{}},
+	{Method: "GET", Path: "/actors/{name}", Tag: "Actors", Summary: "Show an actor",
+		Description: "name may be the actor's name, an alias, or its email, in any case.", Response: []*sqlite.Actor{}},
+	{Method: "PATCH", Path: "/actors/{name}", Tag: "Actors", Summary: "Change an actor's email or type, or add aliases",
+		Body: actorRequest{}, Response: []*sqlite.Actor{}},
+	{Method: "DELETE", Path: "/actors/{name}", Tag: "Actors", Summary: "Unregister an actor",
+		Description: "Its aliases stop resolving. Records naming the actor are kept as they are.", Response: messageResponse{}},
+	{Method: "DELETE", Path: "/actors/{name}/aliases/{alias}", Tag: "Actors", Summary: "Remove an alias", Response: []*sqlite.Actor{}},
+	{Method: "POST", Path: "/actors/{name}/merge", Tag: "Actors", Summary: "Consolidate another actor's history into this one",
+		Description: "Rewrites every assignment, comment, reaction, mention, watch, event, and snapshot naming from to name {name}, " +
+			"and makes from an alias so later writes resolve too. A registered from has its aliases moved and its registration removed. " +
+			"The audit chain is rebuilt over the rewritten history. Admin only.",
+		Body: actorMergeRequest{}, Response: sqlite.ActorMerge{}},
+
+	{Method: "GET", Path: "/sessions", Tag: "Agent sessions", Summary: "List agent sessions",
+		Description: "Newest first, each with the number of changes made in it. SQLite only.",
+		Params: []apiParam{
+			{Name: "actor"},
+			{Name: "active", Type: "boolean", Description: "Only sessions that haven't ended"},
+			{Name: "limit", Type: "integer"},
+		},
+		Response: []*sqlite.AgentSession{}},
+	{Method: "POST", Path: "/sessions", Tag: "Agent sessions", Summary: "Start a session for the request's actor",
+		Description: "Send the returned id as X-Session on later requests to tag the changes they make with the session.",
+		Body:        sessionRequest{}, Response: []*sqlite.AgentSession{}},
+	{Method: "GET", Path: "/sessions/{id}", Tag: "Agent sessions", Summary: "Show a session", Response: []*sqlite.AgentSession{}},
+	{Method: "POST", Path: "/sessions/{id}/end", Tag: "Agent sessions", Summary: "End a session",
+		Description: "Only the session's actor can end it. Requests naming an ended session in X-Session get 409.",
+		Response:    []*sqlite.AgentSession{}},
+	{Method: "GET", Path: "/sessions/{id}/activity", Tag: "Agent sessions", Summary: "Everything a session changed",
+		Description: "The session and its changes, newest first, with field-level diffs. Takes the same filters as GET /audit.",
+		Params: []apiParam{
+			{Name: "issue"},
+			{Name: "field", Description: "Only changes to this field, e.g. priority"},
+			{Name: "since"},
+			{Name: "until"},
+			{Name: "cursor", Type: "integer"},
+			{Name: "limit", Type: "integer", Description: "Default 50, at most 500"},
+		},
+		Response: sqlite.SessionActivity{}},
+
+	{Method: "GET", Path: "/rules", Tag: "Triage rules", Summary: "List triage rules in the order they run",
+		Description: "Rules label, prioritize, or assign issues as they are created or updated, as the actor triage. " +
+			"An update only fires rules the issue didn't already match. Imports aren't triaged. SQLite only.",
+		Response: []*sqlite.TriageRule{}},
+	{Method: "POST", Path: "/rules", Tag: "Triage rules", Summary: "Add a triage rule",
+		Description: "An issue matches when it meets every condition given. Fails with 409 if the name is taken. Admin only.",
+		Body:        ruleRequest{}, Response: []*sqlite.TriageRule{},
+		Example: map[string]interface{}{"name": "network", "title_match": "(?i)timeout", "add_labels": []string{"network"}, "set_priority": 1}},
+	{Method: "POST", Path: "/rules/test", Tag: "Triage rules", Summary: "Dry-run the triage rules against an issue",
+		Description: "Reports which rules match and what they would change, without changing anything. " +
+			"Describe the issue in the body, or name an existing one with issue_id.",
+		Body: ruleTestRequest{}, Response: sqlite.TriageResult{}},
+	{Method: "DELETE", Path: "/rules/{name}", Tag: "Triage rules", Summary: "Remove a triage rule",
+		Description: "Changes it already made are kept. Admin only.", Response: messageResponse{}},
+
+	{Method: "GET", Path: "/replication", Tag: "Replication", Summary: "This replica's ID and vector clock",
+		Description: "The clock maps each replica ID to the last of its ops this server has. SQLite only.",
+		Response:    replication.State{}},
+	{Method: "POST", Path: "/replication/sync", Tag: "Replication", Summary: "Exchange ops with a peer replica",
+		Description: "Applies the ops sent, field by field with last-writer-wins by vector clock, and returns the ops the sender's clock hasn't seen. " +
+			"Used by bd sync remote. SQLite only.",
+		Body: replication.ExchangeRequest{}, Response: replication.ExchangeResponse{}},
+
+	{Method: "POST", Path: "/admin/purge-actor", Tag: "Administration", Summary: "Erase an actor's personal data (GDPR)",
+		Description: "Returns a deletion report, signed per audit.sign config.",
+		Body:        purgeActorRequest{}, Response: sqlite.PurgeReport{}},
+	{Method: "GET", Path: "/admin/purge-reports", Tag: "Administration", Summary: "List stored deletion reports", Response: []*sqlite.PurgeReport{}},
+	{Method: "GET", Path: "/admin/drain", Tag: "Administration", Summary: "Drain state and work in progress", Response: DrainStatus{}},
+	{Method: "POST", Path: "/admin/drain", Tag: "Administration", Summary: "Stop accepting new work ahead of a restart",
+		Description: "New requests get 503 with Retry-After, except /health and /readyz (which report 503 too), /healthz, /ping, /status, /metrics, and /admin/drain. " +
+			"Running requests finish; WebSocket clients are closed with 1001 (going away). Poll GET /admin/drain until requests drops to 1.",
+		Response: DrainStatus{}},
+	{Method: "DELETE", Path: "/admin/drain", Tag: "Administration", Summary: "Resume accepting work after a drain", Response: DrainStatus{}},
+	{Method: "POST", Path: "/admin/backup", Tag: "Administration", Summary: "Snapshot the database",
+		Description: "Takes a consistent snapshot into the backups directory next to the database, restorable with bd backup restore. " +
+			"With upload, also copies it to the S3-compatible storage in backup.s3 config. SQLite only.",
+		Body: backupRequest{}, Response: backup.Manifest{}},
+}
+
+// readyParams are the filters shared by the ready work endpoints
+var readyParams = []apiParam{
+	{Name: "status", Type: "string", Description: "open (default) or in_progress"},
+	{Name: "assignee", Type: "string", Description: "Only work assigned to this actor"},
+	{Name: "label", Type: "string", Description: "Only work with this label (infra/* matches the labels under infra); repeat to require several"},
+	{Name: "priority", Type: "integer", Description: "Only this priority"},
+	{Name: "max_priority", Type: "integer", Description: "Only this priority or more urgent, e.g. 1 for P0 and P1"},
+	{Name: "sort", Type: "string", Description: "hybrid (default), priority, or oldest"},
+	{Name: "limit", Type: "integer", Description: "Maximum issues to return"},
+}
+
+// enumValues lists the allowed values of the string types used in schemas
+var enumValues = map[reflect.Type][]string{
+	reflect.TypeOf(types.StatusOpen):           {"open", "in_progress", "blocked", "closed"},
+	reflect.TypeOf(types.TypeTask):             {"bug", "feature", "task", "epic", "chore"},
+	reflect.TypeOf(types.DepBlocks):            {"blocks", "related", "relates-to", "parent-child", "discovered-from", "duplicates", "caused-by"},
+	reflect.TypeOf(importer.StrategyOverwrite): {"skip-existing", "overwrite", "merge-newer"},
+}
+
+// schemaBuilder reflects Go types into OpenAPI schemas, collecting named
+// structs as reusable components
+type schemaBuilder struct {
+	components map[string]interface{}
+	names      map[reflect.Type]string
+}
+
+func newSchemaBuilder() *schemaBuilder {
+	return &schemaBuilder{components: make(map[string]interface{}), names: make(map[reflect.Type]string)}
+}
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	rawType  = reflect.TypeOf(json.RawMessage{})
+)
+
+// schema returns the schema for t, referencing components for named structs
+func (b *schemaBuilder) schema(t reflect.Type) map[string]interface{} {
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t == rawType:
+		return map[string]interface{}{}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		s := b.schema(t.Elem())
+		if _, isRef := s["$ref"]; isRef {
+			return s
+		}
+		s["nullable"] = true
+		return s
+	case reflect.String:
+		s := map[string]interface{}{"type": "string"}
+		if values, ok := enumValues[t]; ok {
+			s["enum"] = values
+		}
+		return s
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": b.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": b.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + b.component(t)}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// component registers a named struct and returns its component name
+func (b *schemaBuilder) component(t reflect.Type) string {
+	if name, ok := b.names[t]; ok {
+		return name
+	}
+
+	// Unexported HTTP-only types get an exported-looking name; types from
+	// different packages that share a name are qualified by package
+	name := strings.ToUpper(t.Name()[:1]) + t.Name()[1:]
+	if _, taken := b.components[name]; taken {
+		pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+	b.names[t] = name
+	b.components[name] = nil // reserve before recursing so cycles resolve
+	b.components[name] = b.structSchema(t)
+	return name
+}
+
+// structSchema builds an object schema from a struct's json tags. Fields
+// without omitempty are required; doc and enum tags add a description and
+// allowed values; openapi:"path" fields come from the URL and are skipped.
+func (b *schemaBuilder) structSchema(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	var required []string
+	b.addFields(t, properties, &required)
+
+	s := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+func (b *schemaBuilder) addFields(t reflect.Type, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || field.Tag.Get("openapi") == "path" {
+			continue
+		}
+
+		// Embedded structs (or pointers to them) without a json name are
+		// flattened, as encoding/json does
+		if embedded := field.Type; field.Anonymous && tag == "" {
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				b.addFields(embedded, properties, required)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+
+		prop := b.schema(field.Type)
+		if doc := field.Tag.Get("doc"); doc != "" {
+			prop = withExtra(prop, "description", doc)
+		}
+		if enum := field.Tag.Get("enum"); enum != "" {
+			prop = withExtra(prop, "enum", strings.Split(enum, ","))
+		}
+		properties[name] = prop
+
+		if !strings.Contains(opts, "omitempty") && field.Type.Kind() != reflect.Ptr {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// withExtra adds a keyword to a schema. A $ref can't carry siblings in
+// OpenAPI 3.0, so references are wrapped in allOf first.
+func withExtra(s map[string]interface{}, key string, value interface{}) map[string]interface{} {
+	if _, isRef := s["$ref"]; isRef {
+		s = map[string]interface{}{"allOf": []interface{}{s}}
+	}
+	s[key] = value
+	return s
+}
+
+// buildOpenAPI generates the OpenAPI 3.0 document for routes
+func buildOpenAPI(routes []apiRoute) map[string]interface{} {
+	b := newSchemaBuilder()
+	paths := make(map[string]map[string]interface{})
+	var tags []interface{}
+	seenTags := make(map[string]bool)
+
+	for _, route := range routes {
+		if !seenTags[route.Tag] {
+			seenTags[route.Tag] = true
+			tags = append(tags, map[string]interface{}{"name": route.Tag})
+		}
+
+		op := map[string]interface{}{
+			"tags":        []string{route.Tag},
+			"summary":     route.Summary,
+			"operationId": operationID(route),
+		}
+		if route.Description != "" {
+			op["description"] = route.Description
+		}
+		if route.Public {
+			op["security"] = []interface{}{}
+		}
+
+		var params []interface{}
+		for _, name := range pathParams(route.Path) {
+			params = append(params, map[string]interface{}{
+				"name": name, "in": "path", "required": true,
+				"schema": map[string]interface{}{"type": "string"},
+			})
+		}
+		for _, p := range route.Params {
+			typ := p.Type
+			if typ == "" {
+				typ = "string"
+			}
+			param := map[string]interface{}{
+				"name": p.Name, "in": "query", "required": p.Required,
+				"schema": map[string]interface{}{"type": typ},
+			}
+			if p.Description != "" {
+				param["description"] = p.Description
+			}
+			params = append(params, param)
+		}
+		if len(params) > 0 {
+			op["parameters"] = params
+		}
+
+		if route.Body != nil {
+			content := map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": b.schema(reflect.TypeOf(route.Body))},
+			}
+			if route.BodyType != "" {
+				content[route.BodyType] = map[string]interface{}{"schema": map[string]interface{}{"type": "string"}}
+			}
+			op["requestBody"] = map[string]interface{}{"required": true, "content": content}
+		}
+
+		success := map[string]interface{}{"description": "Success"}
+		switch {
+		case route.Response != nil:
+			success["content"] = map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": b.schema(reflect.TypeOf(route.Response))},
+				"text/plain":       map[string]interface{}{"schema": map[string]interface{}{"type": "string"}},
+			}
+			if route.Markdown {
+				success["content"].(map[string]interface{})["text/markdown"] = map[string]interface{}{"schema": map[string]interface{}{"type": "string"}}
+			}
+		case route.ResponseType != "":
+			success["content"] = map[string]interface{}{
+				route.ResponseType: map[string]interface{}{"schema": map[string]interface{}{"type": "string"}},
+			}
+		}
+		op["responses"] = map[string]interface{}{
+			"200":     success,
+			"default": map[string]interface{}{"$ref": "#/components/responses/Error"},
+		}
+
+		path := specPath(route.Path)
+		if paths[path] == nil {
+			paths[path] = make(map[string]interface{})
+		}
+		paths[path][strings.ToLower(route.Method)] = op
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       "Beads REST API",
+			"version":     rpc.ServerVersion,
+			"description": apiOverview,
+		},
+		"servers":  []interface{}{map[string]interface{}{"url": fmt.Sprintf("/v%d", APIVersion)}},
+		"tags":     tags,
+		"paths":    paths,
+		"security": []interface{}{map[string]interface{}{"bearerAuth": []string{}}},
+		"components": map[string]interface{}{
+			"schemas": b.components,
+			"securitySchemes": map[string]interface{}{
+				"bearerAuth": map[string]interface{}{"type": "http", "scheme": "bearer"},
+			},
+			"responses": map[string]interface{}{
+				"Error": map[string]interface{}{
+					"description": "Error",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{"schema": map[string]interface{}{
+							"type": "object",
+							"properties": map[string]interface{}{
+								"error":   map[string]interface{}{"type": "string"},
+								"code":    map[string]interface{}{"type": "string", "enum": types.ErrorCodes},
+								"details": map[string]interface{}{"type": "object", "description": "Depends on code; see the API description"},
+								"success": map[string]interface{}{"type": "boolean"},
+							},
+						}},
+						"text/plain": map[string]interface{}{"schema": map[string]interface{}{"type": "string"}},
+					},
+				},
+			},
+		},
+	}
+}
+
+// routeVarPattern matches a route variable's pattern, as in {name:.+}
+var routeVarPattern = regexp.MustCompile(`\{(\w+):[^}]*\}`)
+
+// specPath strips the patterns from route variables, so /labels/{name:.+}
+// is documented as /labels/{name}
+func specPath(path string) string {
+	return routeVarPattern.ReplaceAllString(path, "{$1}")
+}
+
+// pathParams returns the {name} segments of a route path
+func pathParams(path string) []string {
+	var names []string
+	for _, segment := range strings.Split(specPath(path), "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			names = append(names, segment[1:len(segment)-1])
+		}
+	}
+	return names
+}
+
+// operationID derives a stable identifier such as getIssuesIdTree
+func operationID(route apiRoute) string {
+	id := strings.ToLower(route.Method)
+	for _, word := range strings.FieldsFunc(route.Path, func(r rune) bool {
+		return !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9')
+	}) {
+		id += strings.ToUpper(word[:1]) + word[1:]
+	}
+	if route.Path == "/" {
+		id += "Root"
+	}
+	return id
+}
+
+// renderTextDocs renders routes as the plain-text reference served at GET /
+func renderTextDocs(routes []apiRoute) string {
+	var b strings.Builder
+	b.WriteString("BEADS REST API\n\nBase URL: /\n\n")
+	b.WriteString(apiOverview)
+	b.WriteString("\n  Machine-readable spec: GET /openapi.json (browse it at /docs)\n")
+	b.WriteString("  Function-calling tools for LLM agents: GET /tools\n")
+
+	tag := ""
+	for _, route := range routes {
+		if route.Tag != tag {
+			tag = route.Tag
+			fmt.Fprintf(&b, "\n%s\n", strings.ToUpper(tag))
+		}
+
+		fmt.Fprintf(&b, "  %-6s %-34s %s\n", route.Method, specPath(route.Path), route.Summary)
+		var notes []string
+		if route.Description != "" {
+			notes = append(notes, route.Description)
+		}
+		if len(route.Params) > 0 {
+			var names []string
+			for _, p := range route.Params {
+				names = append(names, p.Name)
+			}
+			notes = append(notes, "Query params: "+strings.Join(names, ", "))
+		}
+		if route.Body != nil {
+			if fields := bodyFields(route.Body); len(fields) > 0 {
+				notes = append(notes, "Body fields: "+strings.Join(fields, ", "))
+			}
+		}
+		for _, note := range notes {
+			for _, line := range wrapText(note, 70) {
+				b.WriteString("         " + line + "\n")
+			}
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString(apiExamples)
+	return b.String()
+}
+
+// bodyFields lists the top-level JSON fields of a struct body, sorted
+func bodyFields(body interface{}) []string {
+	t := reflect.TypeOf(body)
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	properties := make(map[string]interface{})
+	var required []string
+	newSchemaBuilder().addFields(t, properties, &required)
+
+	fields := make([]string, 0, len(properties))
+	for name := range properties {
+		fields = append(fields, name)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// wrapText splits text into lines of at most width characters, keeping
+// existing line breaks
+func wrapText(text string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			if line != "" && len(line)+1+len(word) > width {
+				lines = append(lines, line)
+				line = ""
+			}
+			if line != "" {
+				line += " "
+			}
+			line += word
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// handleDocs serves plain-text API documentation at /
+func (s *Server) handleDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, renderTextDocs(apiRoutes))
+}
+
+// handleOpenAPI serves the OpenAPI document at /openapi.json
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(buildOpenAPI(apiRoutes))
+}
+
+// swaggerUIPage loads Swagger UI from a CDN and points it at /openapi.json
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Beads REST API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({url: "/openapi.json", dom_id: "#swagger-ui", persistAuthorization: true});
+  </script>
+</body>
+</html>
+`
+
+// handleSwaggerUI serves an interactive API browser at /docs
+func (s *Server) handleSwaggerUI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, swaggerUIPage)
+}
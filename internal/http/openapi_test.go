@@ -0,0 +1,138 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+)
+
+func TestOpenAPICoversRoutes(t *testing.T) {
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	documented := make(map[string]bool)
+	for _, route := range apiRoutes {
+		documented[route.Method+" "+route.Path] = true
+	}
+
+	registered := make(map[string]bool)
+	err = srv.router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return err
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return err
+		}
+		for _, method := range methods {
+			key := method + " " + path
+			registered[key] = true
+			if !documented[key] {
+				t.Errorf("route %s is not in apiRoutes", key)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key := range documented {
+		if !registered[key] {
+			t.Errorf("apiRoutes documents %s, which is not registered", key)
+		}
+	}
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	t.Setenv("BEADS_API_SECRET", "sekret")
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The spec is readable without a token
+	rec := httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, httptest.NewRequest("GET", "/openapi.json", nil))
+	if rec.Code != 200 {
+		t.Fatalf("GET /openapi.json: status %d: %s", rec.Code, rec.Body.String()[len(rec.Body.String())-3000:])
+	}
+
+	var doc struct {
+		OpenAPI    string                                     `json:"openapi"`
+		Paths      map[string]map[string]json.RawMessage      `json:"paths"`
+		Components struct{ Schemas map[string]schemaForTest } `json:"components"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if doc.OpenAPI != "3.0.3" {
+		t.Errorf("expected openapi 3.0.3, got %q", doc.OpenAPI)
+	}
+	if _, ok := doc.Paths["/issues/{id}"]["patch"]; !ok {
+		t.Error("missing PATCH /issues/{id}")
+	}
+
+	// Schemas come from the rpc types' json tags
+	create := doc.Components.Schemas["CreateArgs"]
+	if !contains(create.Required, "title") || contains(create.Required, "description") {
+		t.Errorf("CreateArgs required = %v, want title but not description", create.Required)
+	}
+	if enum := create.Properties["issue_type"].Enum; len(enum) != 5 {
+		t.Errorf("issue_type enum = %v", enum)
+	}
+	// The update ID comes from the path, not the body
+	if _, ok := doc.Components.Schemas["UpdateArgs"].Properties["id"]; ok {
+		t.Error("UpdateArgs should not include the path ID")
+	}
+	// Embedded structs are flattened
+	tree := doc.Components.Schemas["TreeNode"]
+	if _, ok := tree.Properties["title"]; !ok {
+		t.Errorf("TreeNode should include Issue fields, got %v", tree.Properties)
+	}
+	if _, ok := tree.Properties["depth"]; !ok {
+		t.Error("TreeNode should include depth")
+	}
+
+	// The text docs are generated from the same routes
+	rec = httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if !strings.Contains(rec.Body.String(), "/epics/{id}/critical-path") {
+		t.Error("text docs missing critical-path route")
+	}
+}
+
+type schemaForTest struct {
+	Required   []string `json:"required"`
+	Properties map[string]struct {
+		Enum []string `json:"enum"`
+	} `json:"properties"`
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestRenamedIssueRedirects(t *testing.T) {
+	srv := newLifecycleTestServer(t)
+	store := srv.storage.(*sqlite.SQLiteStorage)
+	ctx := context.Background()
+
+	issue := &types.Issue{Title: "Renamed", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatal(err)
+	}
+	renames, err := store.RenamePrefix(ctx, "bd", "kw", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest("GET", "/issues/"+issue.ID+"?format=json", nil)
+	req.Header.Set("Accept", "application/json")
+	rec := httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusMovedPermanently {
+		t.Fatalf("Expected 301 for a renamed ID, got %d %s", rec.Code, rec.Body)
+	}
+	if loc := rec.Header().Get("Location"); loc != renames[issue.ID]+"?format=json" {
+		t.Errorf("Location = %q", loc)
+	}
+
+	req = httptest.NewRequest("GET", "/issues/"+renames[issue.ID], nil)
+	req.Header.Set("Accept", "application/json")
+	rec = httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Renamed") {
+		t.Errorf("GET the new ID: %d %s", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,174 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+)
+
+// Health states, from best to worst. A degraded server still serves
+// requests, but something in the background needs attention.
+const (
+	healthHealthy   = "healthy"
+	healthDegraded  = "degraded"
+	healthUnhealthy = "unhealthy"
+)
+
+// probeTimeout bounds each check so a wedged database fails the probe
+// rather than hanging it
+const probeTimeout = 2 * time.Second
+
+// probeRoutes answer without a token, so orchestrators can call them, and
+// are never rate limited or turned away while draining
+var probeRoutes = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+}
+
+// HealthCheck is the result of checking one component
+type HealthCheck struct {
+	Status    string     `json:"status"`
+	Message   string     `json:"message,omitempty"`
+	LatencyMS float64    `json:"latency_ms,omitempty"` // Database checks only
+	LastRun   *time.Time `json:"last_run,omitempty"`   // Background workers only
+}
+
+// HealthReport is the body of GET /readyz and GET /health
+type HealthReport struct {
+	Status string                 `json:"status"`
+	Checks map[string]HealthCheck `json:"checks"`
+}
+
+// lastRunner is a background worker that reports its last round
+type lastRunner interface {
+	LastRun() (time.Time, error)
+}
+
+// checkReadiness checks the database, its schema, the background workers,
+// each workspace's too, and whether the server is draining. The report's status is the worst of
+// the checks'.
+func (s *Server) checkReadiness(ctx context.Context) HealthReport {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	report := HealthReport{Status: healthHealthy, Checks: make(map[string]HealthCheck)}
+	add := func(name string, check HealthCheck) {
+		report.Checks[name] = check
+		if healthRank(check.Status) > healthRank(report.Status) {
+			report.Status = check.Status
+		}
+	}
+
+	start := time.Now()
+	var err error
+	sqliteStore, isSQLite := s.storage.(*sqlite.SQLiteStorage)
+	if isSQLite {
+		err = sqliteStore.Ping(ctx)
+	} else {
+		_, err = s.storage.GetStatistics(ctx)
+	}
+	database := HealthCheck{Status: healthHealthy, LatencyMS: float64(time.Since(start).Microseconds()) / 1000}
+	if err != nil {
+		database.Status, database.Message = healthUnhealthy, err.Error()
+	}
+	add("database", database)
+
+	if isSQLite && err == nil {
+		schema := HealthCheck{Status: healthHealthy}
+		if err := sqliteStore.CheckSchema(ctx); err != nil {
+			schema.Status, schema.Message = healthUnhealthy, err.Error()
+		}
+		add("schema", schema)
+	}
+
+	// Only the workers Start launched are checked
+	workers := map[string]lastRunner{}
+	if s.webhooks != nil {
+		workers["webhooks"] = s.webhooks
+	}
+	if s.digests != nil {
+		workers["digests"] = s.digests
+	}
+	if s.epics != nil {
+		workers["epic_autoclose"] = s.epics
+	}
+	if s.compactions != nil {
+		workers["compaction"] = s.compactions
+	}
+	if s.backups != nil {
+		workers["backups"] = s.backups
+	}
+	for name, worker := range workers {
+		add(name, workerCheck(worker))
+	}
+
+	// Each workspace's own checks, e.g. frontend/database
+	for _, name := range s.workspaceNames() {
+		for check, result := range s.workspaces[name].checkReadiness(ctx).Checks {
+			if check != "drain" {
+				add(name+"/"+check, result)
+			}
+		}
+	}
+
+	if s.Draining() {
+		add("drain", HealthCheck{Status: healthUnhealthy, Message: "draining for a restart"})
+	}
+	return report
+}
+
+// workerCheck reports a worker as degraded if its last round failed
+func workerCheck(worker lastRunner) HealthCheck {
+	lastRun, err := worker.LastRun()
+	check := HealthCheck{Status: healthHealthy}
+	if lastRun.IsZero() {
+		check.Message = "not run yet"
+		return check
+	}
+	check.LastRun = &lastRun
+	if err != nil {
+		check.Status, check.Message = healthDegraded, err.Error()
+	}
+	return check
+}
+
+func healthRank(status string) int {
+	switch status {
+	case healthDegraded:
+		return 1
+	case healthUnhealthy:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// writeHealthReport sends report with 503 if it is unhealthy. A degraded
+// server is still ready for traffic.
+func (s *Server) writeHealthReport(w http.ResponseWriter, r *http.Request, report HealthReport) {
+	statusCode := http.StatusOK
+	if report.Status == healthUnhealthy {
+		statusCode = http.StatusServiceUnavailable
+	}
+	s.writeResponse(w, r, statusCode, report, opHealth)
+}
+
+// handleLiveness handles GET /healthz. It only shows the process is up and
+// serving; a failing database makes the server unready, not dead, since
+// restarting it wouldn't help.
+func (s *Server) handleLiveness(w http.ResponseWriter, r *http.Request) {
+	s.writeSuccess(w, r, HealthReport{Status: healthHealthy, Checks: map[string]HealthCheck{}}, opHealth)
+}
+
+// handleReadiness handles GET /readyz
+func (s *Server) handleReadiness(w http.ResponseWriter, r *http.Request) {
+	s.writeHealthReport(w, r, s.checkReadiness(r.Context()))
+}
+
+// handleHealth handles GET /health, the authenticated readiness report kept
+// for existing monitors
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	s.writeHealthReport(w, r, s.checkReadiness(r.Context()))
+}
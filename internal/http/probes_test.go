@@ -0,0 +1,102 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+)
+
+type fakeWorker struct {
+	lastRun time.Time
+	err     error
+}
+
+func (f fakeWorker) LastRun() (time.Time, error) { return f.lastRun, f.err }
+
+func TestWorkerCheck(t *testing.T) {
+	if check := workerCheck(fakeWorker{}); check.Status != healthHealthy || check.LastRun != nil {
+		t.Errorf("Expected a worker that hasn't run to be healthy, got %+v", check)
+	}
+	if check := workerCheck(fakeWorker{lastRun: time.Now()}); check.Status != healthHealthy || check.LastRun == nil {
+		t.Errorf("Expected a healthy check with last_run, got %+v", check)
+	}
+	if check := workerCheck(fakeWorker{lastRun: time.Now(), err: errors.New("disk I/O error")}); check.Status != healthDegraded || check.Message != "disk I/O error" {
+		t.Errorf("Expected a failed round to degrade, got %+v", check)
+	}
+}
+
+func TestProbes(t *testing.T) {
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(context.Background(), "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "sekret")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	get := func(path string) (*httptest.ResponseRecorder, HealthReport) {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Accept", "application/json")
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		var report HealthReport
+		_ = json.Unmarshal(rec.Body.Bytes(), &report)
+		return rec, report
+	}
+
+	// Probes need no token; /health still does
+	if rec, report := get("/healthz"); rec.Code != http.StatusOK || report.Status != healthHealthy {
+		t.Errorf("GET /healthz: %d %s", rec.Code, rec.Body)
+	}
+	rec, report := get("/readyz")
+	if rec.Code != http.StatusOK || report.Status != healthHealthy {
+		t.Fatalf("GET /readyz: %d %s", rec.Code, rec.Body)
+	}
+	for _, name := range []string{"database", "schema"} {
+		if report.Checks[name].Status != healthHealthy {
+			t.Errorf("Expected a healthy %s check, got %+v", name, report.Checks[name])
+		}
+	}
+	if rec, _ := get("/health"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected /health to need a token, got %d", rec.Code)
+	}
+
+	// Draining makes the server unready but not dead
+	srv.Drain()
+	if rec, report := get("/readyz"); rec.Code != http.StatusServiceUnavailable || report.Checks["drain"].Status != healthUnhealthy {
+		t.Errorf("Expected /readyz to fail while draining, got %d %s", rec.Code, rec.Body)
+	}
+	if rec, _ := get("/healthz"); rec.Code != http.StatusOK {
+		t.Errorf("Expected /healthz to pass while draining, got %d", rec.Code)
+	}
+	srv.Resume()
+
+	// A missing migration fails the schema check
+	if _, err := store.UnderlyingDB().Exec(`DROP TABLE export_hashes`); err != nil {
+		t.Fatal(err)
+	}
+	rec, report = get("/readyz")
+	if rec.Code != http.StatusServiceUnavailable || report.Status != healthUnhealthy || report.Checks["schema"].Message == "" {
+		t.Errorf("Expected an unhealthy schema check, got %d %s", rec.Code, rec.Body)
+	}
+
+	// An unreachable database fails readiness
+	store.Close()
+	rec, report = get("/readyz")
+	if rec.Code != http.StatusServiceUnavailable || report.Checks["database"].Status != healthUnhealthy {
+		t.Errorf("Expected an unhealthy database check, got %d %s", rec.Code, rec.Body)
+	}
+}
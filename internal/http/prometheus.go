@@ -0,0 +1,159 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/imalsogreg/beads/internal/backup"
+)
+
+// wantsPrometheus reports whether a /metrics request is a Prometheus scrape:
+// ?format=prometheus, or the Accept header Prometheus sends
+func wantsPrometheus(r *http.Request) bool {
+	if r.URL.Query().Get("format") == "prometheus" {
+		return true
+	}
+	accept := r.Header.Get("Accept")
+	return strings.Contains(accept, "application/openmetrics-text") || strings.Contains(accept, "version=0.0.4")
+}
+
+// promWriter writes the Prometheus text exposition format, one family at a
+// time
+type promWriter struct {
+	w io.Writer
+}
+
+func (p promWriter) family(name, kind, help string) {
+	fmt.Fprintf(p.w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// sample writes one value; labels alternate names and values
+func (p promWriter) sample(name string, value float64, labels ...string) {
+	var b strings.Builder
+	b.WriteString(name)
+	if len(labels) > 0 {
+		b.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(labels[i] + `="` + escapeLabel(labels[i+1]) + `"`)
+		}
+		b.WriteByte('}')
+	}
+	fmt.Fprintf(p.w, "%s %s\n", b.String(), strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+// writePrometheus writes GET /metrics for Prometheus. Backup status covers
+// the server's own database and every workspace with scheduled backups.
+func (s *Server) writePrometheus(w http.ResponseWriter, metrics *serverMetrics) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	p := promWriter{w: w}
+
+	p.family("beads_uptime_seconds", "gauge", "Time since the server started.")
+	p.sample("beads_uptime_seconds", metrics.UptimeSeconds)
+	p.family("beads_connections_active", "gauge", "Open connections, including idle keep-alive connections and WebSockets.")
+	p.sample("beads_connections_active", float64(metrics.ActiveConns))
+	p.family("beads_connections_total", "counter", "Connections accepted.")
+	p.sample("beads_connections_total", float64(metrics.TotalConns))
+	p.family("beads_connections_rejected_total", "counter", "Requests turned away while draining.")
+	p.sample("beads_connections_rejected_total", float64(metrics.RejectedConns))
+	p.family("beads_goroutines", "gauge", "Goroutines running.")
+	p.sample("beads_goroutines", float64(metrics.GoroutineCount))
+	p.family("beads_memory_alloc_megabytes", "gauge", "Heap memory allocated.")
+	p.sample("beads_memory_alloc_megabytes", float64(metrics.MemoryAllocMB))
+
+	p.family("beads_requests_total", "counter", "Requests by method and route.")
+	for _, op := range metrics.Operations {
+		p.sample("beads_requests_total", float64(op.TotalCount), "operation", op.Operation)
+	}
+	p.family("beads_request_errors_total", "counter", "Responses of 400 and up by method and route.")
+	for _, op := range metrics.Operations {
+		p.sample("beads_request_errors_total", float64(op.ErrorCount), "operation", op.Operation)
+	}
+	p.family("beads_request_latency_milliseconds", "gauge", "Latency percentiles over the last 1000 requests by method and route.")
+	for _, op := range metrics.Operations {
+		p.sample("beads_request_latency_milliseconds", op.Latency.P50MS, "operation", op.Operation, "quantile", "0.5")
+		p.sample("beads_request_latency_milliseconds", op.Latency.P95MS, "operation", op.Operation, "quantile", "0.95")
+		p.sample("beads_request_latency_milliseconds", op.Latency.P99MS, "operation", op.Operation, "quantile", "0.99")
+	}
+
+	if metrics.RateLimit != nil {
+		p.family("beads_rate_limit_throttled_total", "counter", "Requests throttled with 429.")
+		p.sample("beads_rate_limit_throttled_total", float64(metrics.RateLimit.Throttled))
+	}
+	if metrics.Cache != nil {
+		p.family("beads_query_cache_lookups_total", "counter", "Query cache lookups by result: hit, or why it missed.")
+		p.sample("beads_query_cache_lookups_total", float64(metrics.Cache.Hits), "result", "hit")
+		p.sample("beads_query_cache_lookups_total", float64(metrics.Cache.Misses-metrics.Cache.Invalidations-metrics.Cache.Expirations), "result", "miss")
+		p.sample("beads_query_cache_lookups_total", float64(metrics.Cache.Invalidations), "result", "invalidated")
+		p.sample("beads_query_cache_lookups_total", float64(metrics.Cache.Expirations), "result", "expired")
+		p.family("beads_query_cache_entries", "gauge", "Results held by the query cache.")
+		p.sample("beads_query_cache_entries", float64(metrics.Cache.Entries))
+	}
+
+	s.writeBackupMetrics(p)
+}
+
+// writeBackupMetrics writes scheduled backup status, labelled by workspace
+func (s *Server) writeBackupMetrics(p promWriter) {
+	root := s
+	if s.parent != nil {
+		root = s.parent
+	}
+	statuses := map[string]*backup.Status{}
+	var names []string
+	if root.backups != nil {
+		statuses[defaultWorkspace] = root.backups.Status()
+		names = append(names, defaultWorkspace)
+	}
+	for _, name := range root.workspaceNames() {
+		if ws := root.workspaces[name]; ws.backups != nil {
+			statuses[name] = ws.backups.Status()
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+
+	p.family("beads_backup_last_success_timestamp_seconds", "gauge", "When the newest scheduled backup was taken (0 if none yet).")
+	for _, name := range names {
+		var t float64
+		if last := statuses[name].Last; last != nil {
+			t = float64(last.CreatedAt.Unix())
+		}
+		p.sample("beads_backup_last_success_timestamp_seconds", t, "workspace", name)
+	}
+	p.family("beads_backup_last_size_bytes", "gauge", "Size of the newest scheduled backup.")
+	for _, name := range names {
+		var size float64
+		if last := statuses[name].Last; last != nil {
+			size = float64(last.Size)
+		}
+		p.sample("beads_backup_last_size_bytes", size, "workspace", name)
+	}
+	p.family("beads_backup_last_failed", "gauge", "1 if the last scheduled backup failed.")
+	for _, name := range names {
+		failed := 0.0
+		if statuses[name].LastError != "" {
+			failed = 1
+		}
+		p.sample("beads_backup_last_failed", failed, "workspace", name)
+	}
+	p.family("beads_backup_failures_total", "counter", "Scheduled backups that failed.")
+	for _, name := range names {
+		p.sample("beads_backup_failures_total", float64(statuses[name].Failures), "workspace", name)
+	}
+	p.family("beads_backup_retained", "gauge", "Scheduled backups kept on disk.")
+	for _, name := range names {
+		p.sample("beads_backup_retained", float64(statuses[name].Retained), "workspace", name)
+	}
+}
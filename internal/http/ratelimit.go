@@ -0,0 +1,189 @@
+package http
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit is a token bucket: Rate requests per second on average, in
+// bursts of up to Burst. A zero Rate means no limit.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimitConfig configures request throttling for the server
+type RateLimitConfig struct {
+	Global    RateLimit            // Shared by every request
+	PerClient RateLimit            // For each API key, or each actor on requests without one
+	Keys      map[string]RateLimit // Overrides PerClient for API keys by name
+}
+
+// RateLimitStats counts throttled requests, reported by GET /metrics
+type RateLimitStats struct {
+	Throttled       int64            `json:"throttled"`
+	ThrottledGlobal int64            `json:"throttled_global"`
+	ByClient        map[string]int64 `json:"by_client,omitempty"`
+}
+
+// maxIdleBuckets bounds the per-client buckets kept before full (idle) ones
+// are dropped
+const maxIdleBuckets = 10000
+
+type tokenBucket struct {
+	limit  RateLimit
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(limit RateLimit, now time.Time) *tokenBucket {
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+	return &tokenBucket{limit: limit, tokens: float64(limit.Burst), last: now}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if !now.After(b.last) {
+		return
+	}
+	b.tokens = math.Min(float64(b.limit.Burst), b.tokens+now.Sub(b.last).Seconds()*b.limit.Rate)
+	b.last = now
+}
+
+// take spends a token, or reports how long until one is available
+func (b *tokenBucket) take(now time.Time) (bool, time.Duration) {
+	b.refill(now)
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / b.limit.Rate * float64(time.Second))
+}
+
+// rateLimiter holds the global bucket and one bucket per client, each
+// created on first use
+type rateLimiter struct {
+	mu      sync.Mutex
+	config  RateLimitConfig
+	global  *tokenBucket
+	clients map[string]*tokenBucket
+	stats   RateLimitStats
+}
+
+func newRateLimiter(config RateLimitConfig) *rateLimiter {
+	return &rateLimiter{config: config, clients: make(map[string]*tokenBucket)}
+}
+
+// allow spends a token for client (and globally), or reports how long the
+// client should wait before retrying
+func (l *rateLimiter) allow(client, keyName string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limit := l.config.PerClient
+	if override, ok := l.config.Keys[keyName]; ok && keyName != "" {
+		limit = override
+	}
+	var bucket *tokenBucket
+	if limit.Rate > 0 {
+		bucket = l.clients[client]
+		if bucket == nil {
+			if len(l.clients) >= maxIdleBuckets {
+				l.pruneIdle(now)
+			}
+			bucket = newTokenBucket(limit, now)
+			l.clients[client] = bucket
+		}
+		if ok, wait := bucket.take(now); !ok {
+			l.recordThrottle(client, false)
+			return false, wait
+		}
+	}
+
+	if l.config.Global.Rate > 0 {
+		if l.global == nil {
+			l.global = newTokenBucket(l.config.Global, now)
+		}
+		if ok, wait := l.global.take(now); !ok {
+			// The client didn't get through, so it shouldn't pay for the attempt
+			if bucket != nil {
+				bucket.tokens++
+			}
+			l.recordThrottle(client, true)
+			return false, wait
+		}
+	}
+	return true, 0
+}
+
+func (l *rateLimiter) recordThrottle(client string, global bool) {
+	l.stats.Throttled++
+	if global {
+		l.stats.ThrottledGlobal++
+	}
+	if l.stats.ByClient == nil {
+		l.stats.ByClient = make(map[string]int64)
+	}
+	l.stats.ByClient[client]++
+}
+
+// pruneIdle drops buckets that have refilled completely, which behave the
+// same as a new bucket
+func (l *rateLimiter) pruneIdle(now time.Time) {
+	for client, bucket := range l.clients {
+		bucket.refill(now)
+		if bucket.tokens >= float64(bucket.limit.Burst) {
+			delete(l.clients, client)
+		}
+	}
+}
+
+func (l *rateLimiter) snapshot() RateLimitStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	stats := l.stats
+	stats.ByClient = make(map[string]int64, len(l.stats.ByClient))
+	for client, n := range l.stats.ByClient {
+		stats.ByClient[client] = n
+	}
+	return stats
+}
+
+// EnableRateLimit throttles requests with config; over-limit requests get
+// 429 with Retry-After. Call before Start.
+func (s *Server) EnableRateLimit(config RateLimitConfig) {
+	s.limiter = newRateLimiter(config)
+}
+
+// rateLimitMiddleware applies the configured rate limits, identifying the
+// client by API key, or by actor for requests without one. Health checks
+// and probes are never throttled.
+func (s *Server) rateLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.limiter == nil || r.URL.Path == "/health" || probeRoutes[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		client, keyName := "actor:"+s.getActor(r), ""
+		if p := requestPrincipal(r); p != nil && p.Key != nil {
+			client, keyName = "key:"+p.Key.Name, p.Key.Name
+		}
+		ok, wait := s.limiter.allow(client, keyName, time.Now())
+		if !ok {
+			seconds := int(math.Ceil(wait.Seconds()))
+			if seconds < 1 {
+				seconds = 1
+			}
+			w.Header().Set("Retry-After", strconv.Itoa(seconds))
+			s.writeError(w, r, http.StatusTooManyRequests, fmt.Errorf("rate limit exceeded; retry in %ds", seconds))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
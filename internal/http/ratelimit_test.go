@@ -0,0 +1,91 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Now()
+	l := newRateLimiter(RateLimitConfig{
+		Global:    RateLimit{Rate: 1, Burst: 3},
+		PerClient: RateLimit{Rate: 1, Burst: 2},
+		Keys:      map[string]RateLimit{"ci": {Rate: 0}},
+	})
+
+	// Each client gets its burst, then waits for the bucket to refill
+	for i := 0; i < 2; i++ {
+		if ok, _ := l.allow("key:alice", "alice", now); !ok {
+			t.Fatalf("Expected request %d within burst to be allowed", i+1)
+		}
+	}
+	ok, wait := l.allow("key:alice", "alice", now)
+	if ok || wait <= 0 || wait > time.Second {
+		t.Fatalf("Expected alice to be throttled for up to 1s, got ok=%v wait=%v", ok, wait)
+	}
+	if ok, _ := l.allow("key:alice", "alice", now.Add(time.Second)); !ok {
+		t.Error("Expected alice to be allowed after the bucket refilled")
+	}
+
+	// The global bucket is shared; an exempt key still counts against it
+	if ok, _ := l.allow("key:ci", "ci", now.Add(time.Second)); !ok {
+		t.Error("Expected ci to be allowed")
+	}
+	if ok, _ := l.allow("key:ci", "ci", now.Add(time.Second)); ok {
+		t.Error("Expected the global limit to throttle ci")
+	}
+
+	stats := l.snapshot()
+	if stats.Throttled != 2 || stats.ThrottledGlobal != 1 || stats.ByClient["key:alice"] != 1 || stats.ByClient["key:ci"] != 1 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.EnableRateLimit(RateLimitConfig{PerClient: RateLimit{Rate: 0.1, Burst: 1}})
+	do := func(path, actor string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("X-Actor", actor)
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := do("/issues", "alice"); rec.Code != http.StatusOK {
+		t.Fatalf("Expected first request to pass, got %d: %s", rec.Code, rec.Body)
+	}
+	rec := do("/issues", "alice")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected 429, got %d", rec.Code)
+	}
+	if retry := rec.Header().Get("Retry-After"); retry == "" || retry == "0" {
+		t.Errorf("Expected Retry-After, got %q", retry)
+	}
+	if rec := do("/issues", "bob"); rec.Code != http.StatusOK {
+		t.Errorf("Expected another actor to have its own quota, got %d", rec.Code)
+	}
+	if rec := do("/health", "alice"); rec.Code != http.StatusOK {
+		t.Errorf("Expected health checks to bypass the limit, got %d", rec.Code)
+	}
+}
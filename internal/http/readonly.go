@@ -0,0 +1,48 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// readOnlyExemptRoutes take writes even on a read-only server, since they
+// change the server rather than the database
+var readOnlyExemptRoutes = map[string]bool{
+	"/admin/drain":  true,
+	"/admin/backup": true,
+}
+
+// EnableReadOnly makes the server refuse every request that could change
+// the database with 403, and keeps Start from running the background jobs
+// that write. Pair it with a read-only storage (sqlite.PoolConfig.ReadOnly)
+// so nothing slips through.
+func (s *Server) EnableReadOnly() {
+	s.readOnly = true
+}
+
+// readOnlyMiddleware turns away requests other than GET, HEAD, and OPTIONS
+// on a read-only server
+func (s *Server) readOnlyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.readOnly {
+			next.ServeHTTP(w, r)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			next.ServeHTTP(w, r)
+			return
+		}
+		route := ""
+		if current := mux.CurrentRoute(r); current != nil {
+			route, _ = current.GetPathTemplate()
+		}
+		if readOnlyExemptRoutes[route] {
+			next.ServeHTTP(w, r)
+			return
+		}
+		s.writeError(w, r, http.StatusForbidden, fmt.Errorf("server is read-only"))
+	})
+}
@@ -0,0 +1,36 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestReadOnlyServer(t *testing.T) {
+	srv := newLifecycleTestServer(t)
+	srv.EnableReadOnly()
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := do("POST", "/issues", `{"title": "Nope"}`); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected POST /issues to be refused, got %d %s", rec.Code, rec.Body)
+	}
+	if rec := do("PUT", "/config/issue_prefix", `{"value": "xx"}`); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected PUT /config to be refused, got %d", rec.Code)
+	}
+	if rec := do("GET", "/issues", ""); rec.Code != http.StatusOK {
+		t.Errorf("Expected GET /issues to work, got %d %s", rec.Code, rec.Body)
+	}
+
+	// Draining changes the server, not the database
+	if rec := do("POST", "/admin/drain", ""); rec.Code != http.StatusOK {
+		t.Errorf("Expected POST /admin/drain to work, got %d", rec.Code)
+	}
+}
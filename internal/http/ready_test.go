@@ -0,0 +1,103 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestReadyWorkFilters(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Accept", "application/json")
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for _, issue := range []*types.Issue{
+		{Title: "Alice urgent", Priority: 0, Assignee: "alice"},
+		{Title: "Alice later", Priority: 3, Assignee: "alice"},
+		{Title: "Bob urgent", Priority: 1, Assignee: "bob"},
+		{Title: "Nobody's", Priority: 1},
+	} {
+		issue.Status = types.StatusOpen
+		issue.IssueType = types.TypeTask
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.AddLabel(ctx, "bd-3", "backend", "test"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"bd-1", "bd-3", "bd-4", "bd-2"}},
+		{"?assignee=alice", []string{"bd-1", "bd-2"}},
+		{"?max_priority=1&limit=2", []string{"bd-1", "bd-3"}},
+		{"?label=backend", []string{"bd-3"}},
+	}
+	for _, tt := range tests {
+		rec := get("/issues/ready" + tt.query)
+		var issues []*types.Issue
+		if err := json.Unmarshal(rec.Body.Bytes(), &issues); err != nil {
+			t.Fatalf("%s: failed to decode %s: %v", tt.query, rec.Body, err)
+		}
+		var got []string
+		for _, issue := range issues {
+			got = append(got, issue.ID)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.query, tt.want, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: expected %v, got %v", tt.query, tt.want, got)
+				break
+			}
+		}
+	}
+
+	for _, query := range []string{"?max_priority=9", "?limit=x", "?sort=random"} {
+		if rec := get("/issues/ready" + query); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, rec.Code)
+		}
+	}
+
+	rec := get("/issues/ready/queues?limit=1")
+	var queues []*types.ReadyQueue
+	if err := json.Unmarshal(rec.Body.Bytes(), &queues); err != nil {
+		t.Fatalf("failed to decode %s: %v", rec.Body, err)
+	}
+	if len(queues) != 3 || queues[0].Assignee != "alice" || queues[1].Assignee != "bob" || queues[2].Assignee != "" {
+		t.Fatalf("Expected alice, bob, then unassigned queues, got %s", rec.Body)
+	}
+	if len(queues[0].Issues) != 1 || queues[0].Issues[0].ID != "bd-1" {
+		t.Errorf("Expected alice's queue limited to bd-1, got %+v", queues[0].Issues)
+	}
+}
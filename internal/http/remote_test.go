@@ -0,0 +1,145 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage/rest"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+	"github.com/imalsogreg/beads/pkg/client"
+)
+
+// TestRemoteStorage runs the REST client and the storage over it against a
+// real server
+func TestRemoteStorage(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(srv.httpServer.Handler)
+	defer ts.Close()
+
+	c, err := client.New(ts.URL, client.WithActor("alice"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	remote := rest.New(c)
+
+	// Fields the create endpoint doesn't take are set after
+	minutes := 30
+	parent := &types.Issue{Title: "Parent", Status: types.StatusInProgress, Priority: 1,
+		IssueType: types.TypeEpic, Notes: "remote notes", EstimatedMinutes: &minutes}
+	if err := remote.CreateIssue(ctx, parent, "alice"); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+	got, err := store.GetIssue(ctx, parent.ID)
+	if err != nil || got == nil {
+		t.Fatalf("GetIssue %s: %v", parent.ID, err)
+	}
+	if got.Status != types.StatusInProgress || got.Notes != "remote notes" || got.EstimatedMinutes == nil || *got.EstimatedMinutes != 30 {
+		t.Errorf("Expected the status, notes and estimate set, got %+v", got)
+	}
+
+	child, err := c.CreateSubtask(ctx, parent.ID, &client.CreateArgs{Title: "Child", IssueType: "task", Priority: 2})
+	if err != nil {
+		t.Fatalf("CreateSubtask: %v", err)
+	}
+	blocker := &types.Issue{Title: "Blocker", Status: types.StatusOpen, Priority: 0, IssueType: types.TypeBug}
+	if err := remote.CreateIssue(ctx, blocker, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.AddDependency(ctx, &types.Dependency{IssueID: child.ID, DependsOnID: blocker.ID, Type: types.DepBlocks}, "alice"); err != nil {
+		t.Fatalf("AddDependency: %v", err)
+	}
+	if err := remote.AddLabel(ctx, blocker.ID, "urgent", "alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	deps, err := remote.GetDependencies(ctx, child.ID)
+	if err != nil || len(deps) != 2 {
+		t.Fatalf("Expected the parent and blocker as dependencies, got %v (%v)", deps, err)
+	}
+	all, err := remote.GetAllDependencyRecords(ctx)
+	if err != nil || len(all[child.ID]) != 2 {
+		t.Errorf("Expected 2 dependency records for %s, got %v (%v)", child.ID, all, err)
+	}
+	children, err := remote.GetChildren(ctx, parent.ID)
+	if err != nil || len(children) != 1 || children[0].ID != child.ID {
+		t.Errorf("Expected %s as the only child, got %v (%v)", child.ID, children, err)
+	}
+	blocked, err := remote.GetBlockedIssues(ctx)
+	if err != nil || len(blocked) != 1 || blocked[0].ID != child.ID {
+		t.Errorf("Expected %s blocked, got %v (%v)", child.ID, blocked, err)
+	}
+	labeled, err := remote.GetIssuesByLabel(ctx, "urgent")
+	if err != nil || len(labeled) != 1 || labeled[0].ID != blocker.ID {
+		t.Errorf("Expected %s labeled urgent, got %v (%v)", blocker.ID, labeled, err)
+	}
+
+	bug := types.TypeBug
+	found, err := remote.SearchIssues(ctx, "Blocker", types.IssueFilter{IssueType: &bug})
+	if err != nil || len(found) != 1 || found[0].ID != blocker.ID {
+		t.Errorf("Expected to find %s, got %v (%v)", blocker.ID, found, err)
+	}
+
+	if err := remote.CloseIssue(ctx, blocker.ID, "fixed", "alice"); err != nil {
+		t.Fatal(err)
+	}
+	ready, err := remote.GetReadyWork(ctx, types.WorkFilter{Status: types.StatusOpen})
+	if err != nil || len(ready) != 1 || ready[0].ID != child.ID {
+		t.Errorf("Expected %s ready once its blocker closed, got %v (%v)", child.ID, ready, err)
+	}
+
+	if _, err := remote.AddIssueComment(ctx, child.ID, "alice", "looks good"); err != nil {
+		t.Fatal(err)
+	}
+	comments, err := remote.GetIssueComments(ctx, child.ID)
+	if err != nil || len(comments) != 1 || comments[0].Author != "alice" {
+		t.Errorf("Expected one comment by alice, got %v (%v)", comments, err)
+	}
+
+	if err := remote.SetConfig(ctx, "team", "core"); err != nil {
+		t.Fatal(err)
+	}
+	if value, err := remote.GetConfig(ctx, "team"); err != nil || value != "core" {
+		t.Errorf("Expected team=core, got %q (%v)", value, err)
+	}
+	if err := remote.DeleteConfig(ctx, "team"); err != nil {
+		t.Fatal(err)
+	}
+	if value, err := store.GetConfig(ctx, "team"); err != nil || value != "" {
+		t.Errorf("Expected team deleted, got %q (%v)", value, err)
+	}
+
+	// Missing issues are nil, as with SQLite, but errors from the client
+	if issue, err := remote.GetIssue(ctx, "bd-999"); issue != nil || err != nil {
+		t.Errorf("Expected nil for a missing issue, got %v (%v)", issue, err)
+	}
+	if _, err := c.GetIssue(ctx, "bd-999"); !errors.Is(err, client.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound from the client, got %v", err)
+	}
+	err = remote.AddDependency(ctx, &types.Dependency{IssueID: parent.ID, DependsOnID: child.ID, Type: types.DepBlocks}, "alice")
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != types.CodeDependencyCycle {
+		t.Errorf("Expected a dependency_cycle error, got %v", err)
+	}
+	if _, err := remote.GetEvents(ctx, child.ID, 10); !errors.Is(err, rest.ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported for events, got %v", err)
+	}
+
+}
@@ -0,0 +1,10 @@
+package http
+
+import "github.com/imalsogreg/beads/internal/remote"
+
+// EnableRemotes resolves dependencies on other workspaces' issues through
+// registry when serving dependency trees. Without it those dependencies
+// still appear, marked unreachable.
+func (s *Server) EnableRemotes(registry *remote.Registry) {
+	s.remotes = registry
+}
@@ -0,0 +1,75 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/imalsogreg/beads/internal/replication"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+)
+
+// handleReplicationState handles GET /replication, which reports this
+// replica's ID and vector clock so a peer knows which ops to send
+func (s *Server) handleReplicationState(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("replication requires SQLite backend"))
+		return
+	}
+
+	state, err := sqliteStore.ReplicationState(r.Context())
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.writeSuccess(w, r, state, opReplication)
+}
+
+// handleReplicationSync handles POST /replication/sync: it applies the ops
+// a peer sent and replies with the ops the peer's clock hasn't seen
+func (s *Server) handleReplicationSync(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("replication requires SQLite backend"))
+		return
+	}
+
+	var req replication.ExchangeRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxImportBytes)).Decode(&req); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+	if req.Replica == "" {
+		s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("replica is required"))
+		return
+	}
+
+	state, err := sqliteStore.ReplicationState(ctx)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if req.Replica == state.Replica {
+		s.writeError(w, r, http.StatusConflict, fmt.Errorf("peer has this server's replica ID %s; was the database copied?", state.Replica))
+		return
+	}
+
+	result, err := sqliteStore.ApplyOps(ctx, req.Ops)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	ops, err := sqliteStore.OpsSince(ctx, req.Clock)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if ops == nil {
+		ops = []*replication.Op{}
+	}
+
+	s.writeSuccess(w, r, &replication.ExchangeResponse{Replica: state.Replica, Result: result, Ops: ops}, opReplicationSync)
+}
@@ -0,0 +1,186 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// ruleRequest is the body of POST /rules
+type ruleRequest struct {
+	Name             string   `json:"name"`
+	On               string   `json:"on,omitempty" enum:"create,update,any" doc:"When the rule runs; defaults to create"`
+	TitleMatch       string   `json:"title_match,omitempty" doc:"Regular expression the title must match, e.g. (?i)timeout"`
+	DescriptionMatch string   `json:"description_match,omitempty" doc:"Regular expression the description must match"`
+	IssueType        string   `json:"issue_type,omitempty" enum:"bug,feature,task,epic,chore"`
+	Priority         *int     `json:"priority,omitempty" doc:"Only issues at this priority"`
+	AddLabels        []string `json:"add_labels,omitempty"`
+	SetPriority      *int     `json:"set_priority,omitempty"`
+	SetAssignee      string   `json:"set_assignee,omitempty"`
+}
+
+// ruleTestRequest is the body of POST /rules/test. With issue_id, the
+// issue and its labels are evaluated, with any other fields given
+// replacing its own.
+type ruleTestRequest struct {
+	IssueID     string   `json:"issue_id,omitempty"`
+	Title       string   `json:"title,omitempty"`
+	Description string   `json:"description,omitempty"`
+	IssueType   string   `json:"issue_type,omitempty" enum:"bug,feature,task,epic,chore"`
+	Priority    *int     `json:"priority,omitempty"`
+	Labels      []string `json:"labels,omitempty" doc:"Labels the issue has; replaces an existing issue's"`
+	On          string   `json:"on,omitempty" enum:"create,update" doc:"Which rules to run; defaults to create"`
+}
+
+// triageErrorStatus maps rule errors to a status, treating anything else
+// as a bad request
+func triageErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, sqlite.ErrTriageRuleNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, sqlite.ErrTriageRuleExists):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// handleListRules handles GET /rules
+func (s *Server) handleListRules(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.ruleStore(w, r)
+	if !ok {
+		return
+	}
+
+	rules, err := sqliteStore.GetTriageRules(r.Context())
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if rules == nil {
+		rules = []*sqlite.TriageRule{}
+	}
+
+	s.writeSuccess(w, r, rules, opRules)
+}
+
+// handleCreateRule handles POST /rules
+func (s *Server) handleCreateRule(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.ruleStore(w, r)
+	if !ok {
+		return
+	}
+
+	var body ruleRequest
+	if err := s.parseBody(r, &body); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	rule := &sqlite.TriageRule{
+		Name:             body.Name,
+		On:               body.On,
+		TitleMatch:       body.TitleMatch,
+		DescriptionMatch: body.DescriptionMatch,
+		IssueType:        body.IssueType,
+		Priority:         body.Priority,
+		AddLabels:        body.AddLabels,
+		SetPriority:      body.SetPriority,
+		SetAssignee:      body.SetAssignee,
+		CreatedBy:        s.getActor(r),
+	}
+	if err := sqliteStore.AddTriageRule(r.Context(), rule); err != nil {
+		s.writeError(w, r, triageErrorStatus(err), err)
+		return
+	}
+
+	s.writeSuccess(w, r, []*sqlite.TriageRule{rule}, opRules)
+}
+
+// handleDeleteRule handles DELETE /rules/{name}
+func (s *Server) handleDeleteRule(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.ruleStore(w, r)
+	if !ok {
+		return
+	}
+
+	if err := sqliteStore.RemoveTriageRule(r.Context(), mux.Vars(r)["name"]); err != nil {
+		s.writeError(w, r, triageErrorStatus(err), err)
+		return
+	}
+
+	s.writeSuccess(w, r, map[string]string{"message": "rule deleted"}, "rule_delete")
+}
+
+// handleTestRules handles POST /rules/test, a dry run of the rules against
+// an existing issue or one described in the body
+func (s *Server) handleTestRules(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.ruleStore(w, r)
+	if !ok {
+		return
+	}
+
+	var body ruleTestRequest
+	if err := s.parseBody(r, &body); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	issue := &types.Issue{Priority: 2, IssueType: types.TypeTask}
+	var labels []string
+	if body.IssueID != "" {
+		existing, err := sqliteStore.GetIssue(r.Context(), body.IssueID)
+		if err != nil {
+			s.writeStoreError(w, r, err)
+			return
+		}
+		if existing == nil {
+			s.writeError(w, r, http.StatusNotFound, types.NotFound("issue", body.IssueID))
+			return
+		}
+		issue = existing
+		if labels, err = sqliteStore.GetLabels(r.Context(), issue.ID); err != nil {
+			s.writeStoreError(w, r, err)
+			return
+		}
+	}
+	if body.Title != "" {
+		issue.Title = body.Title
+	}
+	if body.Description != "" {
+		issue.Description = body.Description
+	}
+	if body.IssueType != "" {
+		issue.IssueType = types.IssueType(body.IssueType)
+	}
+	if body.Priority != nil {
+		issue.Priority = *body.Priority
+	}
+	if body.Labels != nil {
+		labels = body.Labels
+	}
+	on := body.On
+	if on == "" {
+		on = sqlite.TriageOnCreate
+	}
+
+	result, err := sqliteStore.EvaluateTriageRules(r.Context(), issue, labels, on)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	s.writeSuccess(w, r, result, opRuleTest)
+}
+
+func (s *Server) ruleStore(w http.ResponseWriter, r *http.Request) (*sqlite.SQLiteStorage, bool) {
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("triage rules require SQLite backend"))
+	}
+	return sqliteStore, ok
+}
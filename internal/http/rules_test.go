@@ -0,0 +1,116 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestTriageRulesAPI(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("X-Actor", "alice")
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do("POST", "/rules", `{"name": "network", "title_match": "(?i)timeout", "add_labels": ["network"], "set_priority": 1}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var rules []*sqlite.TriageRule
+	if err := json.Unmarshal(rec.Body.Bytes(), &rules); err != nil || len(rules) != 1 {
+		t.Fatalf("Expected one rule, got %s", rec.Body)
+	}
+	if rules[0].On != sqlite.TriageOnCreate || rules[0].CreatedBy != "alice" {
+		t.Errorf("Expected a create rule by alice, got %+v", rules[0])
+	}
+	if rec := do("POST", "/rules", `{"name": "network", "add_labels": ["x"]}`); rec.Code != http.StatusConflict {
+		t.Errorf("Expected 409 for a taken name, got %d", rec.Code)
+	}
+	if rec := do("POST", "/rules", `{"name": "bad", "title_match": "(", "add_labels": ["x"]}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid pattern, got %d", rec.Code)
+	}
+
+	// Dry run: nothing is written
+	rec = do("POST", "/rules/test", `{"title": "Request timeout on login"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var result sqlite.TriageResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Matched) != 1 || result.Priority == nil || *result.Priority != 1 || len(result.AddLabels) != 1 {
+		t.Errorf("Expected the network rule to match, got %+v", result)
+	}
+	if rec := do("POST", "/rules/test", `{"title": "Timeout", "on": "never"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid trigger, got %d", rec.Code)
+	}
+
+	// Created issues are triaged
+	rec = do("POST", "/issues", `{"title": "Timeout talking to the API", "priority": 3, "issue_type": "bug"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected the issue created, got %d: %s", rec.Code, rec.Body)
+	}
+	var issue types.Issue
+	if err := json.Unmarshal(rec.Body.Bytes(), &issue); err != nil {
+		t.Fatal(err)
+	}
+	if issue.Priority != 1 {
+		t.Errorf("Expected the response to show priority 1, got %d", issue.Priority)
+	}
+	labels, err := store.GetLabels(ctx, issue.ID)
+	if err != nil || len(labels) != 1 || labels[0] != "network" {
+		t.Errorf("Expected label network, got %v (%v)", labels, err)
+	}
+
+	// Testing the issue itself: the rule matches but has nothing left to do
+	rec = do("POST", "/rules/test", `{"issue_id": "`+issue.ID+`"}`)
+	result = sqlite.TriageResult{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Matched) != 1 || result.Changed() {
+		t.Errorf("Expected a match with no changes, got %+v", result)
+	}
+	if rec := do("POST", "/rules/test", `{"issue_id": "bd-999"}`); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown issue, got %d", rec.Code)
+	}
+
+	if rec := do("DELETE", "/rules/network", ""); rec.Code != http.StatusOK {
+		t.Errorf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	if rec := do("DELETE", "/rules/network", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 removing it again, got %d", rec.Code)
+	}
+	rec = do("GET", "/rules", "")
+	if strings.TrimSpace(rec.Body.String()) != "[]" {
+		t.Errorf("Expected no rules, got %s", rec.Body)
+	}
+}
@@ -0,0 +1,76 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func init() {
+	calendarSources = append(calendarSources, dueDateEvents)
+}
+
+// dueDateEvents puts each issue's due date on the calendar
+func dueDateEvents(ctx context.Context, s *Server, filter calendarFilter, base string) ([]calendarEvent, error) {
+	issueFilter := types.IssueFilter{ExcludeArchived: true}
+	if filter.Label != "" {
+		issueFilter.Labels = []string{filter.Label}
+	}
+	issues, err := s.storage.SearchIssues(ctx, "", issueFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	var inEpic map[string]bool
+	if filter.Epic != "" {
+		if inEpic, err = s.descendants(ctx, filter.Epic); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	var events []calendarEvent
+	for _, issue := range issues {
+		if issue.DueDate == nil || (inEpic != nil && !inEpic[issue.ID]) {
+			continue
+		}
+		// All-day events are floating dates, so take the date where the
+		// due date was set rather than in UTC
+		y, m, d := issue.DueDate.Local().Date()
+		description := fmt.Sprintf("%s · P%d · %s", issue.Status, issue.Priority, issue.IssueType)
+		if issue.IsOverdue(now) {
+			description += " · overdue"
+		}
+		events = append(events, calendarEvent{
+			UID:         "due-" + issue.ID,
+			Summary:     fmt.Sprintf("Due: %s %s", issue.ID, issue.Title),
+			Description: description,
+			URL:         base + "/issues/" + issue.ID,
+			Start:       time.Date(y, m, d, 0, 0, 0, 0, time.UTC),
+			Categories:  []string{"due", string(issue.Status)},
+		})
+	}
+	return events, nil
+}
+
+// descendants returns an epic and every issue below it via parent-child links
+func (s *Server) descendants(ctx context.Context, epicID string) (map[string]bool, error) {
+	found := map[string]bool{epicID: true}
+	queue := []string{epicID}
+	for len(queue) > 0 {
+		children, err := s.storage.GetChildren(ctx, queue[0])
+		if err != nil {
+			return nil, err
+		}
+		queue = queue[1:]
+		for _, child := range children {
+			if !found[child.ID] {
+				found[child.ID] = true
+				queue = append(queue, child.ID)
+			}
+		}
+	}
+	return found, nil
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestDueDates(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	do := func(method, path, body, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Accept", accept)
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	late := do("POST", "/issues", `{"title": "Late", "issue_type": "task", "priority": 3, "due_date": "2000-01-01"}`, "application/json")
+	if late.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", late.Code, late.Body)
+	}
+	var created types.Issue
+	if err := json.Unmarshal(late.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	if created.DueDate == nil || created.DueDate.Year() != 2000 {
+		t.Fatalf("Expected a due date in 2000, got %v", created.DueDate)
+	}
+	do("POST", "/issues", `{"title": "On time", "issue_type": "task", "priority": 0, "due_date": "+7d"}`, "application/json")
+
+	rec := do("POST", "/issues", `{"title": "Bad", "issue_type": "task", "due_date": "someday"}`, "application/json")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a bad due date, got %d: %s", rec.Code, rec.Body)
+	}
+
+	rec = do("GET", "/issues?overdue=true", "", "application/json")
+	var issues []*types.Issue
+	if err := json.Unmarshal(rec.Body.Bytes(), &issues); err != nil {
+		t.Fatalf("failed to decode %s: %v", rec.Body, err)
+	}
+	if len(issues) != 1 || issues[0].ID != created.ID {
+		t.Errorf("Expected only %s overdue, got %s", created.ID, rec.Body)
+	}
+
+	rec = do("GET", "/issues?due_before=soon", "", "application/json")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a bad due_before, got %d: %s", rec.Code, rec.Body)
+	}
+
+	// Due dates are all-day calendar events
+	ics := do("GET", "/calendar.ics", "", "text/calendar").Body.String()
+	for _, want := range []string{"DTSTART;VALUE=DATE:20000101", "SUMMARY:Due: " + created.ID + " Late", "overdue"} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("Expected %q in calendar:\n%s", want, ics)
+		}
+	}
+
+	// Ready work lists the overdue issue first, in its own section
+	text := srv.formatReadyWork(mustReady(t, store))
+	overdue, rest := strings.Index(text, "Overdue (1)"), strings.Index(text, "On time")
+	if overdue < 0 || !strings.Contains(text, "1. "+created.ID) || rest < overdue {
+		t.Errorf("Expected an overdue section first, got:\n%s", text)
+	}
+}
+
+func mustReady(t *testing.T, store *sqlite.SQLiteStorage) []*types.Issue {
+	t.Helper()
+	issues, err := store.GetReadyWork(context.Background(), types.WorkFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return issues
+}
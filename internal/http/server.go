@@ -1,18 +1,98 @@
 package http
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
+	"net"
 	"net/http"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/imalsogreg/beads/internal/apikey"
+	"github.com/imalsogreg/beads/internal/autoclose"
+	"github.com/imalsogreg/beads/internal/backup"
+	"github.com/imalsogreg/beads/internal/compact"
+	"github.com/imalsogreg/beads/internal/digest"
+	"github.com/imalsogreg/beads/internal/git"
+	"github.com/imalsogreg/beads/internal/importer"
+	"github.com/imalsogreg/beads/internal/leadtime"
+	"github.com/imalsogreg/beads/internal/oidc"
+	"github.com/imalsogreg/beads/internal/remote"
+	"github.com/imalsogreg/beads/internal/replication"
 	"github.com/imalsogreg/beads/internal/rpc"
+	"github.com/imalsogreg/beads/internal/schedule"
+	"github.com/imalsogreg/beads/internal/stale"
 	"github.com/imalsogreg/beads/internal/storage"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
 	"github.com/imalsogreg/beads/internal/types"
+	"github.com/imalsogreg/beads/internal/webhook"
+)
+
+// Operations that only exist on the HTTP API, for text formatting
+const (
+	opRedactions   = "redactions"
+	opImport       = "import"
+	opSearch       = "search"
+	opBulkUpdate   = "bulk-update"
+	opCriticalPath = "critical-path"
+	opImpact       = "impact"
+	opBlockers     = "blockers"
+	opCommentEdits = "comment-edits"
+	opMentions     = "mentions"
+	opWatchers     = "watchers"
+	opWebhooks     = "webhooks"
+	opCommits      = "commits"
+	opCommitLink   = "commit-link"
+	opKeys         = "keys"
+	opKeyCreate    = "key-create"
+	opAudit        = "audit"
+	opHistory      = "history"
+	opRevert       = "revert"
+	opTrash        = "trash"
+	opMetrics      = "server-metrics"
+	opMilestones   = "milestones"
+	opMilestone    = "milestone"
+	opActors       = "actors"
+	opActorMerge   = "actor-merge"
+	opSessions     = "sessions"
+	opSessionAudit = "session-activity"
+	opRules        = "rules"
+	opRuleTest     = "rule-test"
+	opWorkflow     = "workflow"
+	opWorkLogs     = "worklogs"
+	opStale        = "stale"
+	opDuplicates   = "duplicates"
+	opMerge        = "merge"
+	opMergeIssue   = "merge_issue"
+	opReadyQueues  = "ready_queues"
+	opClaim        = "claim"
+	opClaims       = "claims"
+	opWorkload     = "workload"
+	opLabels       = "labels"
+	opLabel        = "label"
+	opDrain        = "drain"
+	opHealth       = "health"
+	opWorkspaces   = "workspaces"
+	opReplication  = "replication"
+	opBackup       = "backup"
+	opLeadTime     = "lead-time"
+	opSchedule     = "schedule"
+
+	opWorkspaceIssues = "workspace_issues"
+	opReplicationSync = "replication_sync"
+
+	opAssigneeSuggestions = "assignee_suggestions"
+	opIssueLabels         = "issue_labels"
+	opDependencies        = "dependencies"
+	opCycles              = "cycles"
+	opBlocked             = "blocked"
+	opEpics               = "epics"
+	opConfig              = "config"
 )
 
 // Server wraps storage with HTTP endpoints
@@ -20,6 +100,49 @@ type Server struct {
 	storage    storage.Storage
 	httpServer *http.Server
 	router     *mux.Router
+
+	wsMu  sync.Mutex
+	wsHub *wsHub
+
+	webhooks *webhook.Dispatcher
+	digests  *digest.Scheduler
+	epics    *autoclose.Scheduler
+
+	compactInterval time.Duration
+	compactions     *compact.Scheduler
+
+	readOnly bool
+
+	unversionedSunset time.Time // When paths without /v1/ stop working, if announced
+
+	remotes *remote.Registry
+
+	backupUploader *backup.S3
+	backupInterval time.Duration
+	backupRetain   int
+	backups        *backup.Scheduler
+
+	idempotencyMu       sync.Mutex
+	idempotencyInFlight map[string]bool
+
+	limiter *rateLimiter
+
+	cache *queryCache
+
+	oidc     *oidc.Verifier
+	oidcRole apikey.Role
+
+	requestLog *slog.Logger
+
+	life lifecycle
+
+	metrics      *rpc.Metrics
+	startTime    time.Time
+	lastActivity atomic.Value // time.Time of the last finished request
+
+	workspaces map[string]*Server // Other databases served under /w/{name}/
+	parent     *Server            // The server this one is a workspace of, if any
+	workspace  string             // Name under the parent's /w/
 }
 
 // NewServer creates a new HTTP server
@@ -27,69 +150,208 @@ func NewServer(store storage.Storage, addr string) (*Server, error) {
 	s := &Server{
 		storage: store,
 		router:  mux.NewRouter(),
+		life:    lifecycle{conns: make(map[net.Conn]http.ConnState)},
+
+		metrics:   rpc.NewMetrics(),
+		startTime: time.Now(),
 	}
 
 	s.setupRoutes()
 
 	s.httpServer = &http.Server{
 		Addr:         addr,
-		Handler:      s.router,
+		Handler:      s.routeVersions(s.routeWorkspaces(s.router)),
 		ReadTimeout:  30 * time.Second,
 		WriteTimeout: 30 * time.Second,
 		IdleTimeout:  60 * time.Second,
+		ConnState:    s.trackConn,
 	}
 
 	return s, nil
 }
 
-// Start starts the HTTP server
+// EnableCompaction makes Start run compaction and trash purging every
+// interval in the background. SQLite only.
+func (s *Server) EnableCompaction(interval time.Duration) {
+	s.compactInterval = interval
+}
+
+// Start starts the HTTP server (HTTPS if EnableTLS was called) and, with
+// SQLite, webhook delivery, the email digest scheduler, and scheduled
+// compaction and backups if enabled. A read-only server only runs backups.
 func (s *Server) Start() error {
+	if err := s.startBackground(); err != nil {
+		return err
+	}
+	for _, name := range s.workspaceNames() {
+		if err := s.workspaces[name].startBackground(); err != nil {
+			return fmt.Errorf("workspace %s: %w", name, err)
+		}
+	}
+	if s.httpServer.TLSConfig != nil {
+		// Certificates come from TLSConfig
+		return s.httpServer.ListenAndServeTLS("", "")
+	}
 	return s.httpServer.ListenAndServe()
 }
 
-// Stop gracefully stops the HTTP server
-func (s *Server) Stop(ctx context.Context) error {
-	return s.httpServer.Shutdown(ctx)
+// startBackground starts the background jobs for s's own database
+func (s *Server) startBackground() error {
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		return nil
+	}
+	// Backups only read the database
+	if s.backupInterval > 0 {
+		s.backups = backup.NewScheduler(sqliteStore, s.backupInterval, s.backupRetain, s.backupUploader, rpc.ServerVersion)
+		s.backups.Start()
+	}
+	if s.readOnly {
+		return nil
+	}
+	dispatcher, err := webhook.NewDispatcher(sqliteStore, webhook.NewSender())
+	if err != nil {
+		return fmt.Errorf("failed to start webhooks: %w", err)
+	}
+	dispatcher.Start()
+	s.webhooks = dispatcher
+
+	s.digests = digest.NewScheduler(sqliteStore)
+	s.digests.Start()
+
+	s.epics = autoclose.NewScheduler(sqliteStore)
+	s.epics.Start()
+
+	if s.compactInterval > 0 {
+		s.compactions = compact.NewScheduler(sqliteStore, s.compactInterval)
+		s.compactions.Start()
+	}
+	return nil
 }
 
 // setupRoutes configures all HTTP endpoints
 func (s *Server) setupRoutes() {
-	// Apply auth middleware to all routes
+	// Trace and log every request, turn new work away while draining and
+	// writes away on a read-only server, apply auth middleware to all
+	// routes, then rate limits, then replay retried writes
+	s.router.Use(s.traceMiddleware)
+	s.router.Use(s.drainMiddleware)
+	s.router.Use(s.readOnlyMiddleware)
 	s.router.Use(s.authMiddleware)
+	s.router.Use(s.actorMiddleware)
+	s.router.Use(s.sessionMiddleware)
+	s.router.Use(s.rateLimitMiddleware)
+	s.router.Use(s.idempotencyMiddleware)
 
 	// API documentation
 	s.router.HandleFunc("/", s.handleDocs).Methods("GET")
+	s.router.HandleFunc("/openapi.json", s.handleOpenAPI).Methods("GET")
+	s.router.HandleFunc("/docs", s.handleSwaggerUI).Methods("GET")
+	s.router.HandleFunc("/tools", s.handleTools).Methods("GET")
+
+	// Web UI
+	s.router.Handle("/ui", http.RedirectHandler("/ui/", http.StatusMovedPermanently)).Methods("GET")
+	s.router.PathPrefix("/ui/").Handler(http.StripPrefix("/ui/", uiHandler())).Methods("GET")
 
 	// Diagnostics
 	s.router.HandleFunc("/health", s.handleHealth).Methods("GET")
+	s.router.HandleFunc("/healthz", s.handleLiveness).Methods("GET")
+	s.router.HandleFunc("/readyz", s.handleReadiness).Methods("GET")
 	s.router.HandleFunc("/ping", s.handlePing).Methods("GET")
 	s.router.HandleFunc("/status", s.handleStatus).Methods("GET")
 	s.router.HandleFunc("/metrics", s.handleMetrics).Methods("GET")
 
+	// Workspaces
+	s.router.HandleFunc("/workspaces", s.handleListWorkspaces).Methods("GET")
+	s.router.HandleFunc("/workspaces/issues", s.handleListWorkspaceIssues).Methods("GET")
+
 	// Issues
 	s.router.HandleFunc("/issues", s.handleCreateIssue).Methods("POST")
-	s.router.HandleFunc("/issues", s.handleListIssues).Methods("GET")
+	s.router.HandleFunc("/issues", withETag(s.handleListIssues)).Methods("GET")
+	s.router.HandleFunc("/issues", s.handleBulkUpdate).Methods("PATCH")
+	s.router.HandleFunc("/issues/search", withETag(s.handleSearchIssues)).Methods("GET")
+	s.router.HandleFunc("/issues/ready", withETag(s.handleReadyWork)).Methods("GET")
+	s.router.HandleFunc("/issues/ready/queues", s.handleReadyQueues).Methods("GET")
+	s.router.HandleFunc("/issues/ready/claim", s.handleClaimNext).Methods("POST")
+	s.router.HandleFunc("/issues/stats", s.handleStats).Methods("GET")
+	s.router.HandleFunc("/issues/stale", s.handleStaleIssues).Methods("GET")
+	s.router.HandleFunc("/issues/blocked", s.handleBlockedIssues).Methods("GET")
 	s.router.HandleFunc("/issues/{id}", s.handleShowIssue).Methods("GET")
 	s.router.HandleFunc("/issues/{id}", s.handleUpdateIssue).Methods("PATCH")
+	s.router.HandleFunc("/issues/{id}", s.handleDeleteIssue).Methods("DELETE")
+	s.router.HandleFunc("/issues/{id}/restore", s.handleRestoreIssue).Methods("POST")
+	s.router.HandleFunc("/trash", s.handleListTrash).Methods("GET")
+
+	// Duplicates
+	s.router.HandleFunc("/duplicates", s.handleListDuplicates).Methods("GET")
+	s.router.HandleFunc("/duplicates/merge", s.handleMergeDuplicates).Methods("POST")
+
 	s.router.HandleFunc("/issues/{id}/close", s.handleCloseIssue).Methods("POST")
-	s.router.HandleFunc("/issues/ready", s.handleReadyWork).Methods("GET")
-	s.router.HandleFunc("/issues/stats", s.handleStats).Methods("GET")
+	s.router.HandleFunc("/issues/{id}/revert", s.handleRevert).Methods("POST")
+	s.router.HandleFunc("/issues/{id}/merge", s.handleMergeIssue).Methods("POST")
+	s.router.HandleFunc("/issues/{id}/claim", s.handleClaimIssue).Methods("POST")
+	s.router.HandleFunc("/issues/{id}/claim", s.handleReleaseClaim).Methods("DELETE")
+	s.router.HandleFunc("/claims", s.handleListClaims).Methods("GET")
+
+	// Workload
+	s.router.HandleFunc("/assignees/workload", s.handleWorkload).Methods("GET")
+	s.router.HandleFunc("/issues/{id}/assignee-suggestions", s.handleSuggestAssignees).Methods("GET")
 
 	// Comments
 	s.router.HandleFunc("/issues/{id}/comments", s.handleAddComment).Methods("POST")
 	s.router.HandleFunc("/issues/{id}/comments", s.handleListComments).Methods("GET")
+	s.router.HandleFunc("/issues/{id}/comments/{cid}", s.handleEditComment).Methods("PATCH")
+	s.router.HandleFunc("/issues/{id}/comments/{cid}", s.handleDeleteComment).Methods("DELETE")
+	s.router.HandleFunc("/issues/{id}/comments/{cid}/edits", s.handleCommentEdits).Methods("GET")
+	s.router.HandleFunc("/issues/{id}/comments/{cid}/reactions", s.handleAddReaction).Methods("POST")
+	s.router.HandleFunc("/issues/{id}/comments/{cid}/reactions/{reaction}", s.handleRemoveReaction).Methods("DELETE")
+	s.router.HandleFunc("/mentions", s.handleMentions).Methods("GET")
+	s.router.HandleFunc("/issues/{id}/watch", s.handleWatchIssue).Methods("POST")
+	s.router.HandleFunc("/issues/{id}/watch", s.handleUnwatchIssue).Methods("DELETE")
+	s.router.HandleFunc("/issues/{id}/watchers", s.handleListWatchers).Methods("GET")
+	s.router.HandleFunc("/issues/{id}/history", s.handleIssueHistory).Methods("GET")
 
 	// Labels
 	s.router.HandleFunc("/issues/{id}/labels", s.handleAddLabel).Methods("POST")
-	s.router.HandleFunc("/issues/{id}/labels/{label}", s.handleRemoveLabel).Methods("DELETE")
+	s.router.HandleFunc("/issues/{id}/labels", s.handleListIssueLabels).Methods("GET")
+	s.router.HandleFunc("/issues/{id}/labels/{label:.+}", s.handleRemoveLabel).Methods("DELETE")
+	s.router.HandleFunc("/labels", s.handleListLabels).Methods("GET")
+	s.router.HandleFunc("/labels", s.handleCreateLabel).Methods("POST")
+	s.router.HandleFunc("/labels/{name:.+}", s.handleShowLabel).Methods("GET")
+	s.router.HandleFunc("/labels/{name:.+}", s.handleUpdateLabel).Methods("PATCH")
+	s.router.HandleFunc("/labels/{name:.+}", s.handleDeleteLabel).Methods("DELETE")
 
 	// Dependencies
 	s.router.HandleFunc("/issues/{id}/dependencies", s.handleAddDependency).Methods("POST")
 	s.router.HandleFunc("/issues/{id}/dependencies/{depId}", s.handleRemoveDependency).Methods("DELETE")
+	s.router.HandleFunc("/issues/{id}/dependencies", s.handleListDependencies).Methods("GET")
+	s.router.HandleFunc("/issues/{id}/dependents", s.handleListDependents).Methods("GET")
+	s.router.HandleFunc("/dependencies", s.handleListAllDependencies).Methods("GET")
+	s.router.HandleFunc("/dependencies/cycles", s.handleDetectCycles).Methods("GET")
 	s.router.HandleFunc("/issues/{id}/tree", s.handleDependencyTree).Methods("GET")
+	s.router.HandleFunc("/issues/{id}/impact", s.handleBlockingImpact).Methods("GET")
+	s.router.HandleFunc("/issues/{id}/blockers", s.handleBlockers).Methods("GET")
+
+	// Git commit links
+	s.router.HandleFunc("/issues/{id}/commits", s.handleListCommits).Methods("GET")
+	s.router.HandleFunc("/issues/{id}/commits", s.handleLinkCommit).Methods("POST")
+
+	// Time tracking
+	s.router.HandleFunc("/issues/{id}/worklogs", s.handleListWorkLogs).Methods("GET")
+	s.router.HandleFunc("/issues/{id}/worklogs", s.handleAddWorkLog).Methods("POST")
+
+	// Subtasks
+	s.router.HandleFunc("/issues/{id}/children", s.handleListChildren).Methods("GET")
+	s.router.HandleFunc("/issues/{id}/subtasks", s.handleCreateSubtask).Methods("POST")
 
 	// Epics
+	s.router.HandleFunc("/epics", s.handleListEpics).Methods("GET")
 	s.router.HandleFunc("/epics/{id}/status", s.handleEpicStatus).Methods("GET")
+	s.router.HandleFunc("/epics/{id}/critical-path", s.handleCriticalPath).Methods("GET")
+	s.router.HandleFunc("/epics/{id}/schedule", s.handleEpicSchedule).Methods("GET")
+
+	// Analytics
+	s.router.HandleFunc("/analytics/lead-time", s.handleLeadTime).Methods("GET")
 
 	// Compaction
 	s.router.HandleFunc("/compact", s.handleCompact).Methods("POST")
@@ -103,25 +365,106 @@ func (s *Server) setupRoutes() {
 	s.router.HandleFunc("/batch", s.handleBatch).Methods("POST")
 
 	// Config endpoints
+	s.router.HandleFunc("/config", s.handleListConfig).Methods("GET")
 	s.router.HandleFunc("/config/{key}", s.handleGetConfig).Methods("GET")
 	s.router.HandleFunc("/config/{key}", s.handleSetConfig).Methods("PUT")
+	s.router.HandleFunc("/config/{key}", s.handleDeleteConfig).Methods("DELETE")
+	s.router.HandleFunc("/workflow", s.handleGetWorkflow).Methods("GET")
+	s.router.HandleFunc("/workflow", s.handleSetWorkflow).Methods("PUT")
+
+	// Streaming
+	s.router.HandleFunc("/ws", s.handleWebSocket).Methods("GET")
+
+	// Webhooks
+	s.router.HandleFunc("/webhooks", s.handleListWebhooks).Methods("GET")
+	s.router.HandleFunc("/webhooks", s.handleCreateWebhook).Methods("POST")
+	s.router.HandleFunc("/webhooks/{id}", s.handleDeleteWebhook).Methods("DELETE")
+	s.router.HandleFunc("/webhooks/{id}/test", s.handleTestWebhook).Methods("POST")
+
+	// Milestones
+	s.router.HandleFunc("/milestones", s.handleListMilestones).Methods("GET")
+	s.router.HandleFunc("/milestones", s.handleCreateMilestone).Methods("POST")
+	s.router.HandleFunc("/milestones/{name}", s.handleShowMilestone).Methods("GET")
+	s.router.HandleFunc("/milestones/{name}", s.handleDeleteMilestone).Methods("DELETE")
+	s.router.HandleFunc("/milestones/{name}/issues", s.handleAssignMilestone).Methods("POST")
+	s.router.HandleFunc("/milestones/{name}/issues/{id}", s.handleUnassignMilestone).Methods("DELETE")
+
+	// Actor registry
+	s.router.HandleFunc("/actors", s.handleListActors).Methods("GET")
+	s.router.HandleFunc("/actors", s.handleCreateActor).Methods("POST")
+	s.router.HandleFunc("/actors/{name}", s.handleShowActor).Methods("GET")
+	s.router.HandleFunc("/actors/{name}", s.handleUpdateActor).Methods("PATCH")
+	s.router.HandleFunc("/actors/{name}", s.handleDeleteActor).Methods("DELETE")
+	s.router.HandleFunc("/actors/{name}/aliases/{alias}", s.handleRemoveActorAlias).Methods("DELETE")
+	s.router.HandleFunc("/actors/{name}/merge", s.handleMergeActor).Methods("POST")
+
+	// API keys
+	s.router.HandleFunc("/keys", s.handleListKeys).Methods("GET")
+	s.router.HandleFunc("/keys", s.handleCreateKey).Methods("POST")
+	s.router.HandleFunc("/keys/{id}", s.handleDeleteKey).Methods("DELETE")
+
+	// Agent sessions
+	s.router.HandleFunc("/sessions", s.handleListSessions).Methods("GET")
+	s.router.HandleFunc("/sessions", s.handleStartSession).Methods("POST")
+	s.router.HandleFunc("/sessions/{id}", s.handleShowSession).Methods("GET")
+	s.router.HandleFunc("/sessions/{id}/end", s.handleEndSession).Methods("POST")
+	s.router.HandleFunc("/sessions/{id}/activity", s.handleSessionActivity).Methods("GET")
+
+	// Triage rules
+	s.router.HandleFunc("/rules", s.handleListRules).Methods("GET")
+	s.router.HandleFunc("/rules", s.handleCreateRule).Methods("POST")
+	s.router.HandleFunc("/rules/test", s.handleTestRules).Methods("POST")
+	s.router.HandleFunc("/rules/{name}", s.handleDeleteRule).Methods("DELETE")
+
+	// Feeds
+	s.router.HandleFunc("/feed.atom", s.handleFeed).Methods("GET")
+	s.router.HandleFunc("/calendar.ics", s.handleCalendar).Methods("GET")
+
+	// Audit log
+	s.router.HandleFunc("/audit", s.handleAuditLog).Methods("GET")
+
+	// Security reports
+	s.router.HandleFunc("/redactions", s.handleListRedactions).Methods("GET")
+
+	// Replication
+	s.router.HandleFunc("/replication", s.handleReplicationState).Methods("GET")
+	s.router.HandleFunc("/replication/sync", s.handleReplicationSync).Methods("POST")
+
+	// Administration
+	s.router.HandleFunc("/admin/purge-actor", s.handlePurgeActor).Methods("POST")
+	s.router.HandleFunc("/admin/purge-reports", s.handleListPurgeReports).Methods("GET")
+	s.router.HandleFunc("/admin/drain", s.handleDrainStatus).Methods("GET")
+	s.router.HandleFunc("/admin/drain", s.handleDrain).Methods("POST")
+	s.router.HandleFunc("/admin/drain", s.handleResume).Methods("DELETE")
+	s.router.HandleFunc("/admin/backup", s.handleBackup).Methods("POST")
 }
 
 // writeSuccess writes a successful response with content negotiation
 func (s *Server) writeSuccess(w http.ResponseWriter, r *http.Request, data interface{}, operation string) {
+	s.writeResponse(w, r, http.StatusOK, data, operation)
+}
+
+// writeResponse is writeSuccess with a status other than 200 OK
+func (s *Server) writeResponse(w http.ResponseWriter, r *http.Request, statusCode int, data interface{}, operation string) {
 	wantsJSON := s.wantsJSON(r)
 
 	if wantsJSON {
 		// Return raw JSON response
 		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
+		w.WriteHeader(statusCode)
 		json.NewEncoder(w).Encode(data)
 	} else {
 		// Marshal to JSON first, then format
 		dataJSON, _ := json.Marshal(data)
+		if s.wantsMarkdown(r) {
+			if text, ok := formatMarkdown(operation, dataJSON); ok {
+				writeMarkdown(w, text)
+				return
+			}
+		}
 		formatted := s.formatResponse(operation, dataJSON)
 		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
-		w.WriteHeader(http.StatusOK)
+		w.WriteHeader(statusCode)
 		fmt.Fprint(w, formatted)
 	}
 }
@@ -137,8 +480,25 @@ func (s *Server) wantsJSON(r *http.Request) bool {
 	return false
 }
 
-// getActor extracts the actor from request (header, query param, or default)
+// getActor returns the request's actor, resolved to a registered actor's
+// name when it is one's alias or email
 func (s *Server) getActor(r *http.Request) string {
+	actor := s.requestActor(r)
+	if sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage); ok {
+		if resolved, err := sqliteStore.ResolveActor(r.Context(), actor); err == nil && resolved != "" {
+			return resolved
+		}
+	}
+	return actor
+}
+
+// requestActor extracts the actor from request (OIDC token, header, query
+// param, API key name, client certificate, or default)
+func (s *Server) requestActor(r *http.Request) string {
+	// An OIDC token names the actor, and can't be overridden
+	if p := requestPrincipal(r); p != nil && p.Subject != "" {
+		return p.Subject
+	}
 	// Check X-Actor header
 	if actor := r.Header.Get("X-Actor"); actor != "" {
 		return actor
@@ -147,26 +507,18 @@ func (s *Server) getActor(r *http.Request) string {
 	if actor := r.URL.Query().Get("actor"); actor != "" {
 		return actor
 	}
+	// Requests made with an API key act as the key's owner
+	if p := requestPrincipal(r); p != nil && p.Key != nil {
+		return p.Key.Name
+	}
+	// With mutual TLS, the client certificate names the actor
+	if name := clientCertName(r); name != "" {
+		return name
+	}
 	// Default to "http-user"
 	return "http-user"
 }
 
-// writeError writes an error response
-func (s *Server) writeError(w http.ResponseWriter, r *http.Request, statusCode int, err error) {
-	if s.wantsJSON(r) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(statusCode)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error":   err.Error(),
-			"success": false,
-		})
-	} else {
-		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
-		w.WriteHeader(statusCode)
-		fmt.Fprintf(w, "Error: %s\n", err.Error())
-	}
-}
-
 // formatResponse formats RPC response data as human-readable text
 func (s *Server) formatResponse(operation string, data json.RawMessage) string {
 	switch operation {
@@ -206,11 +558,11 @@ func (s *Server) formatResponse(operation string, data json.RawMessage) string {
 		return s.formatStats(&stats)
 
 	case rpc.OpEpicStatus:
-		var statuses []*types.EpicStatus
-		if err := json.Unmarshal(data, &statuses); err != nil {
+		var rollup types.EpicRollup
+		if err := json.Unmarshal(data, &rollup); err != nil {
 			return fmt.Sprintf("Error parsing response: %v", err)
 		}
-		return s.formatEpicStatus(statuses)
+		return s.formatEpicRollup(&rollup)
 
 	case rpc.OpDepTree:
 		var tree []*types.TreeNode
@@ -226,19 +578,47 @@ func (s *Server) formatResponse(operation string, data json.RawMessage) string {
 		}
 		return s.formatComments(comments)
 
-	case rpc.OpHealth:
-		var health rpc.HealthResponse
-		if err := json.Unmarshal(data, &health); err != nil {
+	case rpc.OpCommentAdd:
+		var comment types.Comment
+		if err := json.Unmarshal(data, &comment); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatComment(&comment, 0)
+
+	case opCommentEdits:
+		var edits []*types.CommentEdit
+		if err := json.Unmarshal(data, &edits); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatCommentEdits(edits)
+
+	case opMentions:
+		var mentions []*types.Mention
+		if err := json.Unmarshal(data, &mentions); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatMentions(mentions)
+
+	case opWatchers:
+		var result watchersResult
+		if err := json.Unmarshal(data, &result); err != nil {
 			return fmt.Sprintf("Error parsing response: %v", err)
 		}
-		return s.formatHealth(&health)
+		return s.formatWatchers(&result)
+
+	case opHealth:
+		var report HealthReport
+		if err := json.Unmarshal(data, &report); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatHealthReport(&report)
 
 	case rpc.OpStatus:
-		var status rpc.StatusResponse
+		var status serverStatus
 		if err := json.Unmarshal(data, &status); err != nil {
 			return fmt.Sprintf("Error parsing response: %v", err)
 		}
-		return s.formatStatus(&status)
+		return s.formatServerStatus(&status)
 
 	case rpc.OpMetrics:
 		var metrics rpc.MetricsSnapshot
@@ -247,6 +627,55 @@ func (s *Server) formatResponse(operation string, data json.RawMessage) string {
 		}
 		return s.formatMetrics(&metrics)
 
+	case opWorkspaces:
+		var infos []WorkspaceInfo
+		if err := json.Unmarshal(data, &infos); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatWorkspaces(infos)
+
+	case opWorkspaceIssues:
+		var issues []workspaceIssue
+		if err := json.Unmarshal(data, &issues); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatWorkspaceIssues(issues)
+
+	case opReplication:
+		var state replication.State
+		if err := json.Unmarshal(data, &state); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatReplicationState(&state)
+
+	case opBackup:
+		var m backup.Manifest
+		if err := json.Unmarshal(data, &m); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatBackup(&m)
+
+	case opDrain:
+		var status DrainStatus
+		if err := json.Unmarshal(data, &status); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatDrainStatus(&status)
+
+	case opMetrics:
+		var metrics serverMetrics
+		if err := json.Unmarshal(data, &metrics); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatServerMetrics(&metrics)
+
+	case rpc.OpCompact:
+		var result rpc.CompactResponse
+		if err := json.Unmarshal(data, &result); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatCompact(&result)
+
 	case rpc.OpCompactStats:
 		var stats rpc.CompactStatsData
 		if err := json.Unmarshal(data, &stats); err != nil {
@@ -254,6 +683,321 @@ func (s *Server) formatResponse(operation string, data json.RawMessage) string {
 		}
 		return s.formatCompactStats(&stats)
 
+	case opImport:
+		var result importer.MergeResult
+		if err := json.Unmarshal(data, &result); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatImport(&result)
+
+	case opCriticalPath:
+		var path types.CriticalPath
+		if err := json.Unmarshal(data, &path); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatCriticalPath(&path)
+
+	case opImpact:
+		var impact types.BlockingImpact
+		if err := json.Unmarshal(data, &impact); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatBlockingImpact(&impact)
+
+	case opBlockers:
+		var tree types.BlockerTree
+		if err := json.Unmarshal(data, &tree); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatBlockers(&tree)
+
+	case opBulkUpdate:
+		var result bulkUpdateResult
+		if err := json.Unmarshal(data, &result); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatBulkUpdate(&result)
+
+	case opSearch:
+		var hits []*sqlite.SearchHit
+		if err := json.Unmarshal(data, &hits); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatSearch(hits)
+
+	case opWebhooks:
+		var hooks []*sqlite.Webhook
+		if err := json.Unmarshal(data, &hooks); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatWebhooks(hooks)
+
+	case opMilestones:
+		var milestones []*sqlite.Milestone
+		if err := json.Unmarshal(data, &milestones); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatMilestones(milestones)
+
+	case opMilestone:
+		var detail milestoneDetail
+		if err := json.Unmarshal(data, &detail); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatMilestoneDetail(&detail)
+
+	case opActors:
+		var actors []*sqlite.Actor
+		if err := json.Unmarshal(data, &actors); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatActors(actors)
+
+	case opActorMerge:
+		var merge sqlite.ActorMerge
+		if err := json.Unmarshal(data, &merge); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatActorMerge(&merge)
+
+	case opSessions:
+		var sessions []*sqlite.AgentSession
+		if err := json.Unmarshal(data, &sessions); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatSessions(sessions)
+
+	case opSessionAudit:
+		var activity sqlite.SessionActivity
+		if err := json.Unmarshal(data, &activity); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatSessionActivity(&activity)
+
+	case opRules:
+		var rules []*sqlite.TriageRule
+		if err := json.Unmarshal(data, &rules); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatRules(rules)
+
+	case opRuleTest:
+		var result sqlite.TriageResult
+		if err := json.Unmarshal(data, &result); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatRuleTest(&result)
+
+	case opWorkflow:
+		var workflow workflowResponse
+		if err := json.Unmarshal(data, &workflow); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatWorkflow(&workflow)
+
+	case opKeys:
+		var keys []*sqlite.APIKey
+		if err := json.Unmarshal(data, &keys); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatAPIKeys(keys)
+
+	case opKeyCreate:
+		var key createdKey
+		if err := json.Unmarshal(data, &key); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return fmt.Sprintf("Created %s key %d for %s\n\n  %s\n\nSave this token now; it can't be shown again.\n", key.Role, key.ID, key.Name, key.Token)
+
+	case opAudit:
+		var page sqlite.AuditPage
+		if err := json.Unmarshal(data, &page); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatAuditLog(&page)
+
+	case opHistory:
+		var history []*sqlite.IssueRevision
+		if err := json.Unmarshal(data, &history); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatHistory(history)
+
+	case opTrash:
+		var issues []*types.Issue
+		if err := json.Unmarshal(data, &issues); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatTrash(issues)
+
+	case opRevert:
+		var rev sqlite.IssueRevision
+		if err := json.Unmarshal(data, &rev); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return fmt.Sprintf("Reverted revision %d of %s\n", rev.Reverts, rev.IssueID) + s.formatHistory([]*sqlite.IssueRevision{&rev})
+
+	case opCommits:
+		var links []*sqlite.CommitLink
+		if err := json.Unmarshal(data, &links); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatCommits(links)
+
+	case opWorkLogs:
+		var logs []*sqlite.WorkLog
+		if err := json.Unmarshal(data, &logs); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatWorkLogs(logs)
+
+	case opSchedule:
+		var sched schedule.Schedule
+		if err := json.Unmarshal(data, &sched); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return sched.Table()
+
+	case opLeadTime:
+		var report leadtime.Report
+		if err := json.Unmarshal(data, &report); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return report.Table()
+
+	case opStale:
+		var issues []*stale.Issue
+		if err := json.Unmarshal(data, &issues); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatStaleIssues(issues)
+
+	case opDuplicates:
+		var groups []*types.DuplicateGroup
+		if err := json.Unmarshal(data, &groups); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatDuplicates(groups)
+
+	case opMerge:
+		var results []*types.MergeResult
+		if err := json.Unmarshal(data, &results); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatMergeResults(results)
+
+	case opReadyQueues:
+		var queues []*types.ReadyQueue
+		if err := json.Unmarshal(data, &queues); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatReadyQueues(queues)
+
+	case opMergeIssue:
+		var result types.MergeResult
+		if err := json.Unmarshal(data, &result); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatMergeResults([]*types.MergeResult{&result})
+
+	case opClaim:
+		var result claimResult
+		if err := json.Unmarshal(data, &result); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatClaim(&result)
+
+	case opClaims:
+		var leases []*sqlite.Lease
+		if err := json.Unmarshal(data, &leases); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatClaims(leases)
+
+	case opWorkload:
+		var workload []*types.AssigneeWorkload
+		if err := json.Unmarshal(data, &workload); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatWorkload(workload)
+
+	case opAssigneeSuggestions:
+		var suggestions []*types.AssigneeSuggestion
+		if err := json.Unmarshal(data, &suggestions); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatAssigneeSuggestions(suggestions)
+
+	case opLabels:
+		var labels []*sqlite.Label
+		if err := json.Unmarshal(data, &labels); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatLabels(labels)
+
+	case opLabel:
+		var label sqlite.Label
+		if err := json.Unmarshal(data, &label); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatLabel(&label)
+
+	case opCommitLink:
+		var result git.LinkResult
+		if err := json.Unmarshal(data, &result); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatCommitLink(&result)
+
+	case opIssueLabels:
+		var labels []string
+		if err := json.Unmarshal(data, &labels); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatIssueLabels(labels)
+
+	case opDependencies:
+		var deps []*types.Dependency
+		if err := json.Unmarshal(data, &deps); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatDependencies(deps)
+
+	case opCycles:
+		var cycles [][]*types.Issue
+		if err := json.Unmarshal(data, &cycles); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatCycles(cycles)
+
+	case opBlocked:
+		var blocked []*types.BlockedIssue
+		if err := json.Unmarshal(data, &blocked); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatBlockedIssues(blocked)
+
+	case opEpics:
+		var epics []*types.EpicStatus
+		if err := json.Unmarshal(data, &epics); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatEpics(epics)
+
+	case opConfig:
+		var config map[string]string
+		if err := json.Unmarshal(data, &config); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatConfig(config)
+
+	case opRedactions:
+		var redactions []*sqlite.SecretRedaction
+		if err := json.Unmarshal(data, &redactions); err != nil {
+			return fmt.Sprintf("Error parsing response: %v", err)
+		}
+		return s.formatRedactions(redactions)
+
 	default:
 		// For operations that just return success (update, close, label ops, etc.)
 		return "Success\n"
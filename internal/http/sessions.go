@@ -0,0 +1,179 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+)
+
+// sessionRequest is the body of POST /sessions
+type sessionRequest struct {
+	Model string `json:"model,omitempty" doc:"The model driving the agent"`
+	Tool  string `json:"tool,omitempty" doc:"The agent or harness, e.g. a coding assistant or CI bot"`
+	RunID string `json:"run_id,omitempty" doc:"The agent's own ID for the run, e.g. a CI job or trace ID"`
+}
+
+// sessionErrorStatus maps session errors to a status
+func sessionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, sqlite.ErrSessionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, sqlite.ErrSessionNotOwned):
+		return http.StatusForbidden
+	case errors.Is(err, sqlite.ErrSessionEnded):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// sessionMiddleware tags the changes a request makes with the agent session
+// in its X-Session header. The session must be the request actor's and
+// still running.
+func (s *Server) sessionMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("X-Session")
+		if id == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+		if !ok {
+			s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("agent sessions require SQLite backend"))
+			return
+		}
+		if err := sqliteStore.CheckSession(r.Context(), id, s.getActor(r)); err != nil {
+			s.writeError(w, r, sessionErrorStatus(err), err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(sqlite.WithSession(r.Context(), id)))
+	})
+}
+
+// handleListSessions handles GET /sessions
+func (s *Server) handleListSessions(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.sessionStore(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	filter := sqlite.SessionFilter{Actor: query.Get("actor"), Active: query.Get("active") == "true"}
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("invalid limit %q", v))
+			return
+		}
+		filter.Limit = limit
+	}
+
+	sessions, err := sqliteStore.ListSessions(r.Context(), filter)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if sessions == nil {
+		sessions = []*sqlite.AgentSession{}
+	}
+
+	s.writeSuccess(w, r, sessions, opSessions)
+}
+
+// handleStartSession handles POST /sessions, starting a session for the
+// request's actor
+func (s *Server) handleStartSession(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.sessionStore(w, r)
+	if !ok {
+		return
+	}
+
+	var body sessionRequest
+	if err := s.parseBody(r, &body); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	session := &sqlite.AgentSession{Actor: s.getActor(r), Model: body.Model, Tool: body.Tool, RunID: body.RunID}
+	if err := sqliteStore.StartSession(r.Context(), session); err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.writeSuccess(w, r, []*sqlite.AgentSession{session}, opSessions)
+}
+
+// handleShowSession handles GET /sessions/{id}
+func (s *Server) handleShowSession(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.sessionStore(w, r)
+	if !ok {
+		return
+	}
+
+	session, err := sqliteStore.GetSession(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		s.writeError(w, r, sessionErrorStatus(err), err)
+		return
+	}
+
+	s.writeSuccess(w, r, []*sqlite.AgentSession{session}, opSessions)
+}
+
+// handleEndSession handles POST /sessions/{id}/end. Only the session's
+// actor can end it.
+func (s *Server) handleEndSession(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.sessionStore(w, r)
+	if !ok {
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	session, err := sqliteStore.GetSession(r.Context(), id)
+	if err != nil {
+		s.writeError(w, r, sessionErrorStatus(err), err)
+		return
+	}
+	if actor := s.getActor(r); session.Actor != actor {
+		s.writeError(w, r, http.StatusForbidden, fmt.Errorf("%w: %s is %s's", sqlite.ErrSessionNotOwned, id, session.Actor))
+		return
+	}
+	if session, err = sqliteStore.EndSession(r.Context(), id); err != nil {
+		s.writeError(w, r, sessionErrorStatus(err), err)
+		return
+	}
+
+	s.writeSuccess(w, r, []*sqlite.AgentSession{session}, opSessions)
+}
+
+// handleSessionActivity handles GET /sessions/{id}/activity: everything
+// the session changed, newest first, with field-level diffs
+func (s *Server) handleSessionActivity(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.sessionStore(w, r)
+	if !ok {
+		return
+	}
+
+	filter, ok := s.parseAuditFilter(w, r)
+	if !ok {
+		return
+	}
+	activity, err := sqliteStore.GetSessionActivity(r.Context(), mux.Vars(r)["id"], filter)
+	if err != nil {
+		s.writeError(w, r, sessionErrorStatus(err), err)
+		return
+	}
+
+	s.writeSuccess(w, r, activity, opSessionAudit)
+}
+
+func (s *Server) sessionStore(w http.ResponseWriter, r *http.Request) (*sqlite.SQLiteStorage, bool) {
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("agent sessions require SQLite backend"))
+	}
+	return sqliteStore, ok
+}
@@ -0,0 +1,98 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestAgentSessions(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	do := func(method, path, actor, session, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("X-Actor", actor)
+		if session != "" {
+			req.Header.Set("X-Session", session)
+		}
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do("POST", "/sessions", "agent-1", "", `{"model": "m1", "tool": "ci", "run_id": "job-42"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var sessions []*sqlite.AgentSession
+	if err := json.Unmarshal(rec.Body.Bytes(), &sessions); err != nil || len(sessions) != 1 {
+		t.Fatalf("Expected one session, got %s", rec.Body)
+	}
+	id := sessions[0].ID
+	if sessions[0].Actor != "agent-1" || sessions[0].RunID != "job-42" {
+		t.Errorf("Expected agent-1's job-42 session, got %+v", sessions[0])
+	}
+
+	// Writes carrying X-Session are tagged with it
+	rec = do("POST", "/issues", "agent-1", id, `{"title": "Fix it", "priority": 2, "issue_type": "task"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected the issue created, got %d: %s", rec.Code, rec.Body)
+	}
+	var issue types.Issue
+	if err := json.Unmarshal(rec.Body.Bytes(), &issue); err != nil {
+		t.Fatal(err)
+	}
+	if rec := do("POST", "/issues", "bob", id, `{"title": "Not mine", "priority": 2, "issue_type": "task"}`); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 writing in another actor's session, got %d", rec.Code)
+	}
+	if rec := do("POST", "/issues", "agent-1", "sess-missing", `{"title": "Lost", "priority": 2, "issue_type": "task"}`); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown session, got %d", rec.Code)
+	}
+
+	rec = do("GET", "/sessions/"+id+"/activity", "", "", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var activity sqlite.SessionActivity
+	if err := json.Unmarshal(rec.Body.Bytes(), &activity); err != nil {
+		t.Fatal(err)
+	}
+	if activity.Session.Changes != 1 || len(activity.Entries) != 1 || activity.Entries[0].IssueID != issue.ID {
+		t.Errorf("Expected the session's one create, got %s", rec.Body)
+	}
+
+	if rec := do("POST", "/sessions/"+id+"/end", "bob", "", ""); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 ending another actor's session, got %d", rec.Code)
+	}
+	if rec := do("POST", "/sessions/"+id+"/end", "agent-1", "", ""); rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200 ending the session, got %d: %s", rec.Code, rec.Body)
+	}
+	if rec := do("PATCH", "/issues/"+issue.ID, "agent-1", id, `{"priority": 1}`); rec.Code != http.StatusConflict {
+		t.Errorf("Expected 409 writing in an ended session, got %d", rec.Code)
+	}
+	if err := json.Unmarshal(do("GET", "/sessions?active=true", "", "", "").Body.Bytes(), &sessions); err != nil || len(sessions) != 0 {
+		t.Errorf("Expected no active sessions, got %v (%v)", sessions, err)
+	}
+}
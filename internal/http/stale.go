@@ -0,0 +1,39 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/stale"
+)
+
+// handleStaleIssues handles GET /issues/stale
+func (s *Server) handleStaleIssues(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	policy, err := stale.LoadPolicy(ctx, s.storage)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if v := r.URL.Query().Get("days"); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil || days <= 0 {
+			s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("invalid days %q", v))
+			return
+		}
+		policy.Days = days
+	}
+
+	issues, err := stale.Find(ctx, s.storage, policy.After(), time.Now())
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if issues == nil {
+		issues = []*stale.Issue{}
+	}
+
+	s.writeSuccess(w, r, issues, opStale)
+}
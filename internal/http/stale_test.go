@@ -0,0 +1,72 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/stale"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestStaleIssues(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	get := func(path, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Accept", accept)
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	issue := &types.Issue{Title: "Old refactor", Status: types.StatusInProgress, Priority: 1, IssueType: types.TypeTask, Assignee: "alice"}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.UnderlyingDB().ExecContext(ctx, `UPDATE issues SET updated_at = ? WHERE id = ?`,
+		time.Now().Add(-5*24*time.Hour), issue.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// Not stale under the default 7 days
+	rec := get("/issues/stale", "application/json")
+	if rec.Code != http.StatusOK || strings.TrimSpace(rec.Body.String()) != "[]" {
+		t.Errorf("Expected no stale issues, got %d: %s", rec.Code, rec.Body)
+	}
+
+	rec = get("/issues/stale?days=3", "application/json")
+	var issues []*stale.Issue
+	if err := json.Unmarshal(rec.Body.Bytes(), &issues); err != nil {
+		t.Fatalf("failed to decode %s: %v", rec.Body, err)
+	}
+	if len(issues) != 1 || issues[0].ID != issue.ID || issues[0].IdleDays != 5 {
+		t.Errorf("Unexpected stale issues %s", rec.Body)
+	}
+
+	if text := get("/issues/stale?days=3", "text/plain").Body.String(); !strings.Contains(text, "alice, no updates in 5 days") {
+		t.Errorf("Unexpected stale text:\n%s", text)
+	}
+	if rec = get("/issues/stale?days=0", "application/json"); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for days=0, got %d", rec.Code)
+	}
+}
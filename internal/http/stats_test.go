@@ -0,0 +1,67 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestStatsGroupBy(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	do := func(path, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Accept", accept)
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for _, assignee := range []string{"alice", "alice", "bob"} {
+		if err := store.CreateIssue(ctx, &types.Issue{Title: "Work", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, Assignee: assignee}, "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rec := do("/issues/stats?group_by=assignee", "application/json")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var stats types.Statistics
+	if err := json.Unmarshal(rec.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("failed to decode %s: %v", rec.Body, err)
+	}
+	if stats.TotalIssues != 3 || stats.GroupBy != "assignee" || len(stats.Groups) != 2 ||
+		stats.Groups[0].Key != "alice" || stats.Groups[0].ByStatus[types.StatusOpen] != 2 {
+		t.Errorf("Unexpected grouped stats %s", rec.Body)
+	}
+
+	rec = do("/issues/stats?group_by=assignee", "text/plain")
+	if body := rec.Body.String(); !strings.Contains(body, "By assignee:") || !strings.Contains(body, "alice") {
+		t.Errorf("Expected a table by assignee, got %s", body)
+	}
+
+	if rec = do("/issues/stats?group_by=milestone", "application/json"); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown dimension, got %d", rec.Code)
+	}
+}
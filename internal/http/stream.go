@@ -0,0 +1,100 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// ndjsonType is the media type of newline-delimited JSON, one issue per
+// line, which listings stream instead of building a JSON array
+const ndjsonType = "application/x-ndjson"
+
+// streamBatch is how many issues a stream collects before writing them;
+// each batch gets its expansions together and is flushed to the client
+const streamBatch = 500
+
+// streamWriteTimeout bounds how long writing one batch of a stream may take
+const streamWriteTimeout = 30 * time.Second
+
+// wantsNDJSON reports whether a request asks for a streamed listing
+func wantsNDJSON(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), ndjsonType)
+}
+
+// streamIssues writes the issues each yields as NDJSON, slimmed down to
+// fields and with their expansions, as they come rather than all at once.
+// An error before the first batch gets the usual error response; after it,
+// the status is sent, so the stream just ends early and the error goes to
+// the request log.
+func (s *Server) streamIssues(w http.ResponseWriter, r *http.Request, fields, expand []string, each func(fn func(*types.Issue) error) error) {
+	ctx := r.Context()
+	encoder := json.NewEncoder(w)
+	controller := http.NewResponseController(w)
+	started := false
+	batch := make([]*types.Issue, 0, streamBatch)
+
+	flush := func() error {
+		var rows interface{} = batch
+		if len(expand) > 0 {
+			relations, err := s.expandRelations(ctx, batch, expand)
+			if err != nil {
+				return err
+			}
+			rows = expandIssues(batch, fields, relations)
+		} else if len(fields) > 0 {
+			rows = selectIssueFields(batch, fields)
+		}
+		// The server's write timeout is for a whole response; a stream only
+		// needs each batch to go out in that long
+		if err := controller.SetWriteDeadline(time.Now().Add(streamWriteTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+		if !started {
+			w.Header().Set("Content-Type", ndjsonType)
+			w.WriteHeader(http.StatusOK)
+			started = true
+		}
+		switch rows := rows.(type) {
+		case []*types.Issue:
+			for _, row := range rows {
+				if err := encoder.Encode(row); err != nil {
+					return err
+				}
+			}
+		case []map[string]json.RawMessage:
+			for _, row := range rows {
+				if err := encoder.Encode(row); err != nil {
+					return err
+				}
+			}
+		}
+		batch = batch[:0]
+		if err := controller.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+		return nil
+	}
+
+	err := each(func(issue *types.Issue) error {
+		batch = append(batch, issue)
+		if len(batch) == streamBatch {
+			return flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = flush()
+	}
+	switch {
+	case err == nil:
+	case !started:
+		s.writeStoreError(w, r, err)
+	case ctx.Err() == nil && s.requestLog != nil:
+		s.requestLog.ErrorContext(ctx, "stream ended early", "path", r.URL.Path, "error", err)
+	}
+}
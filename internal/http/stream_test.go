@@ -0,0 +1,141 @@
+package http
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+	"github.com/imalsogreg/beads/pkg/client"
+)
+
+func TestStreamIssues(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	// More than a batch, so the stream flushes midway
+	const n = streamBatch + 20
+	issues := make([]*types.Issue, n)
+	for i := range issues {
+		issues[i] = &types.Issue{Title: fmt.Sprintf("Issue %d", i), Status: types.StatusOpen, Priority: i % 5, IssueType: types.TypeTask}
+	}
+	if err := store.CreateIssues(ctx, issues, "test"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AddLabel(ctx, issues[n-1].ID, "last", "test"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.AddIssueComment(ctx, issues[0].ID, "test", "First!"); err != nil {
+		t.Fatal(err)
+	}
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.EnableCompression(CompressionConfig{MinSize: 1024})
+	ts := httptest.NewServer(srv.httpServer.Handler)
+	defer ts.Close()
+
+	stream := func(query string) (*http.Response, []map[string]json.RawMessage) {
+		t.Helper()
+		req, _ := http.NewRequest("GET", ts.URL+"/v1/issues"+query, nil)
+		req.Header.Set("Accept", ndjsonType)
+		req.Header.Set("Accept-Encoding", "gzip")
+		resp, err := http.DefaultTransport.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return resp, nil
+		}
+		body, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var rows []map[string]json.RawMessage
+		scanner := bufio.NewScanner(body)
+		scanner.Buffer(nil, 1<<20)
+		for scanner.Scan() {
+			var row map[string]json.RawMessage
+			if err := json.Unmarshal(scanner.Bytes(), &row); err != nil {
+				t.Fatalf("Line %d isn't JSON: %v", len(rows)+1, err)
+			}
+			rows = append(rows, row)
+		}
+		if err := scanner.Err(); err != nil {
+			t.Fatal(err)
+		}
+		return resp, rows
+	}
+
+	resp, rows := stream("?limit=0")
+	if got := resp.Header.Get("Content-Type"); got != ndjsonType {
+		t.Errorf("Expected Content-Type %s, got %q", ndjsonType, got)
+	}
+	if resp.Header.Get("ETag") != "" {
+		t.Error("Expected a streamed listing untagged")
+	}
+	if len(rows) != n {
+		t.Fatalf("Expected %d lines, got %d", n, len(rows))
+	}
+	labeled := 0
+	for _, row := range rows {
+		if string(row["labels"]) == `["last"]` && string(row["id"]) == `"`+issues[n-1].ID+`"` {
+			labeled++
+		}
+	}
+	if labeled != 1 {
+		t.Errorf("Expected %s streamed with its label", issues[n-1].ID)
+	}
+
+	_, rows = stream("?fields=id,title&expand=comments&priority=0")
+	if len(rows) != (n+4)/5 {
+		t.Errorf("Expected the priority filter applied, got %d lines", len(rows))
+	}
+	for _, row := range rows {
+		if len(row) != 3 || row["comments"] == nil {
+			t.Fatalf("Expected id, title, and comments only, got %v", row)
+		}
+	}
+
+	if resp, _ := stream("?expand=bogus"); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a bad expansion, got %d", resp.StatusCode)
+	}
+
+	// The client reads the stream one issue at a time
+	c, err := client.New(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	if err := c.ForEachIssue(ctx, client.IssueFilter{}, func(issue *client.Issue) error {
+		count++
+		return nil
+	}); err != nil || count != n {
+		t.Errorf("Expected %d issues from ForEachIssue, got %d (%v)", n, count, err)
+	}
+	stop := errors.New("stop")
+	if err := c.ForEachIssue(ctx, client.IssueFilter{}, func(issue *client.Issue) error {
+		return stop
+	}); !errors.Is(err, stop) {
+		t.Errorf("Expected ForEachIssue to return fn's error, got %v", err)
+	}
+}
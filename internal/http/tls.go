@@ -0,0 +1,86 @@
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// TLSConfig configures HTTPS for the server. Set either CertFile and
+// KeyFile, or ACMEDomains to get certificates from Let's Encrypt.
+type TLSConfig struct {
+	CertFile string
+	KeyFile  string
+
+	ACMEDomains  []string // Domains to request certificates for
+	ACMECacheDir string   // Where issued certificates are kept between restarts
+	ACMEEmail    string   // Optional contact address for the CA
+
+	// ClientCAFile, if set, is a PEM bundle of CAs that client certificates
+	// must chain to (mutual TLS). Connections without a valid certificate
+	// are refused.
+	ClientCAFile string
+}
+
+// EnableTLS makes Start serve HTTPS. Call before Start.
+func (s *Server) EnableTLS(config TLSConfig) error {
+	var tlsConfig *tls.Config
+	switch {
+	case len(config.ACMEDomains) > 0:
+		if config.CertFile != "" || config.KeyFile != "" {
+			return fmt.Errorf("use either a certificate and key or ACME, not both")
+		}
+		if config.ACMECacheDir == "" {
+			return fmt.Errorf("ACME needs a cache directory for issued certificates")
+		}
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(config.ACMEDomains...),
+			Cache:      autocert.DirCache(config.ACMECacheDir),
+			Email:      config.ACMEEmail,
+		}
+		// Includes the acme-tls/1 protocol, so the server answers
+		// TLS-ALPN-01 challenges itself when reachable on port 443
+		tlsConfig = manager.TLSConfig()
+	case config.CertFile != "" && config.KeyFile != "":
+		cert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load TLS certificate: %w", err)
+		}
+		tlsConfig = &tls.Config{Certificates: []tls.Certificate{cert}}
+	case config.CertFile != "" || config.KeyFile != "":
+		return fmt.Errorf("TLS needs both a certificate and a key")
+	default:
+		return fmt.Errorf("TLS needs a certificate and key or an ACME domain")
+	}
+	tlsConfig.MinVersion = tls.VersionTLS12
+
+	if config.ClientCAFile != "" {
+		pem, err := os.ReadFile(config.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read client CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in %s", config.ClientCAFile)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	s.httpServer.TLSConfig = tlsConfig
+	return nil
+}
+
+// clientCertName returns the common name of r's verified client
+// certificate, or "" without mutual TLS
+func clientCertName(r *http.Request) string {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+		return ""
+	}
+	return r.TLS.VerifiedChains[0][0].Subject.CommonName
+}
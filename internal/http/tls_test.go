@@ -0,0 +1,143 @@
+package http
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// testCert issues a certificate for name, signed by parent (self-signed if nil)
+func testCert(t *testing.T, name string, parent *tls.Certificate, isCA bool) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	signer, signerKey := template, interface{}(key)
+	if parent != nil {
+		signer, signerKey = parent.Leaf, parent.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+// writePEM writes a certificate and its key to dir
+func writePEM(t *testing.T, dir, name string, cert tls.Certificate) (string, string) {
+	t.Helper()
+	certPath := filepath.Join(dir, name+".crt")
+	if err := os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(cert.PrivateKey.(*ecdsa.PrivateKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPath := filepath.Join(dir, name+".key")
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certPath, keyPath
+}
+
+func TestMutualTLS(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := sqlite.New(filepath.Join(dir, "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	ca := testCert(t, "Test CA", nil, true)
+	server := testCert(t, "localhost", &ca, false)
+	client := testCert(t, "alice", &ca, false)
+	caPath, _ := writePEM(t, dir, "ca", ca)
+	certPath, keyPath := writePEM(t, dir, "server", server)
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.EnableTLS(TLSConfig{CertFile: certPath}); err == nil {
+		t.Error("Expected an error for a certificate without a key")
+	}
+	if err := srv.EnableTLS(TLSConfig{CertFile: certPath, KeyFile: keyPath, ClientCAFile: caPath}); err != nil {
+		t.Fatalf("EnableTLS failed: %v", err)
+	}
+
+	ts := httptest.NewUnstartedServer(srv.router)
+	ts.TLS = srv.httpServer.TLSConfig
+	ts.StartTLS()
+	defer ts.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Leaf)
+	post := func(certs []tls.Certificate) (*http.Response, error) {
+		c := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots, Certificates: certs}}}
+		req, _ := http.NewRequest("POST", ts.URL+"/issues", strings.NewReader(`{"title": "Over TLS", "issue_type": "task"}`))
+		req.Header.Set("Accept", "application/json")
+		return c.Do(req)
+	}
+
+	if resp, err := post(nil); err == nil {
+		resp.Body.Close()
+		t.Fatal("Expected a connection without a client certificate to be refused")
+	}
+
+	resp, err := post([]tls.Certificate{client})
+	if err != nil {
+		t.Fatalf("Request with client certificate failed: %v", err)
+	}
+	defer resp.Body.Close()
+	var issue types.Issue
+	if err := json.NewDecoder(resp.Body).Decode(&issue); err != nil {
+		t.Fatal(err)
+	}
+	events, err := store.GetEvents(ctx, issue.ID, 10)
+	if err != nil || len(events) == 0 {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	if events[0].Actor != "alice" {
+		t.Errorf("Expected the certificate's common name as actor, got %q", events[0].Actor)
+	}
+}
@@ -0,0 +1,288 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/imalsogreg/beads/internal/rpc"
+)
+
+// toolManifest is the body of GET /tools: every JSON endpoint described as
+// a function an LLM agent can call. Tool paths are relative to BasePath.
+type toolManifest struct {
+	Version  string    `json:"version"`
+	BasePath string    `json:"base_path"`
+	Tools    []apiTool `json:"tools"`
+}
+
+// apiTool is one endpoint as a function-calling tool. Parameters is a
+// self-contained JSON Schema object (no $refs), ready to hand to a model.
+type apiTool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Parameters  map[string]interface{} `json:"parameters"`
+	HTTP        toolHTTP               `json:"http"`
+	Examples    []toolExample          `json:"examples"`
+}
+
+// toolHTTP maps a tool call onto a request: arguments named in the path
+// template fill it, Query arguments go in the query string, and Body
+// arguments form the JSON body. With BodyArg, that one argument is the
+// whole body instead. Role is the API key role the call needs.
+type toolHTTP struct {
+	Method  string   `json:"method"`
+	Path    string   `json:"path"`
+	Query   []string `json:"query,omitempty"`
+	Body    []string `json:"body,omitempty"`
+	BodyArg string   `json:"body_arg,omitempty"`
+	Role    string   `json:"role"`
+}
+
+// toolExample is a sample call and the request it makes
+type toolExample struct {
+	Arguments map[string]interface{} `json:"arguments"`
+	Request   string                 `json:"request"`
+}
+
+// exampleValues fill in the arguments of generated examples by name.
+// Others get a <name> placeholder.
+var exampleValues = map[string]interface{}{
+	"depends_on": "bd-2",
+	"into":       "bd-2",
+	"title":      "Fix login bug",
+	"text":       "Looks good to me",
+	"label":      "backend",
+	"q":          "login",
+}
+
+// buildToolManifest describes routes as tools. Routes that neither take nor
+// return JSON (the docs, the web UI, streams, and feeds) are left out.
+func buildToolManifest(routes []apiRoute) toolManifest {
+	b := newSchemaBuilder()
+	manifest := toolManifest{Version: rpc.ServerVersion, BasePath: fmt.Sprintf("/v%d", APIVersion), Tools: []apiTool{}}
+	for _, route := range routes {
+		if route.Response == nil && route.Body == nil {
+			continue
+		}
+		manifest.Tools = append(manifest.Tools, buildTool(b, route))
+	}
+	return manifest
+}
+
+func buildTool(b *schemaBuilder, route apiRoute) apiTool {
+	path := specPath(route.Path)
+	tool := apiTool{
+		Name:        operationID(route),
+		Description: route.Summary,
+		HTTP:        toolHTTP{Method: route.Method, Path: path, Role: string(requiredRole(route.Method, route.Path))},
+	}
+	if route.Description != "" {
+		tool.Description += ". " + route.Description
+	}
+
+	properties := make(map[string]interface{})
+	var required []string
+	for _, name := range pathParams(route.Path) {
+		properties[name] = map[string]interface{}{"type": "string", "description": "Path parameter"}
+		required = append(required, name)
+	}
+	for _, p := range route.Params {
+		typ := p.Type
+		if typ == "" {
+			typ = "string"
+		}
+		prop := map[string]interface{}{"type": typ}
+		if p.Description != "" {
+			prop["description"] = p.Description
+		}
+		properties[p.Name] = prop
+		tool.HTTP.Query = append(tool.HTTP.Query, p.Name)
+		if p.Required {
+			required = append(required, p.Name)
+		}
+	}
+
+	if route.Body != nil {
+		body := inlineSchema(b.schema(reflect.TypeOf(route.Body)), b.components, nil)
+		if fields, ok := body["properties"].(map[string]interface{}); ok {
+			// A body field named like a path or query parameter is left
+			// to the parameter, which the handler reads first
+			inBody := make(map[string]bool)
+			for name, prop := range fields {
+				if _, taken := properties[name]; !taken {
+					inBody[name] = true
+					properties[name] = prop
+					tool.HTTP.Body = append(tool.HTTP.Body, name)
+				}
+			}
+			sort.Strings(tool.HTTP.Body)
+			bodyRequired, _ := body["required"].([]string)
+			for _, name := range bodyRequired {
+				if inBody[name] {
+					required = append(required, name)
+				}
+			}
+		} else {
+			properties["body"] = withExtra(body, "description", "The whole request body")
+			tool.HTTP.BodyArg = "body"
+			required = append(required, "body")
+		}
+	}
+
+	tool.Parameters = map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		tool.Parameters["required"] = required
+	}
+
+	args := route.Example
+	if args == nil {
+		args = exampleArguments(path, properties, required)
+	}
+	tool.Examples = []toolExample{{Arguments: args, Request: exampleRequest(tool.HTTP, args)}}
+	return tool
+}
+
+// inlineSchema replaces the $refs in a schema with the components they
+// name, so a tool's parameters stand alone. A component already being
+// expanded (a cycle) becomes a plain object. OpenAPI's nullable, which
+// JSON Schema lacks, is dropped.
+func inlineSchema(s map[string]interface{}, components map[string]interface{}, expanding map[string]bool) map[string]interface{} {
+	if ref, ok := s["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/components/schemas/")
+		if expanding[name] {
+			return map[string]interface{}{"type": "object"}
+		}
+		component, _ := components[name].(map[string]interface{})
+		nested := map[string]bool{name: true}
+		for n := range expanding {
+			nested[n] = true
+		}
+		return inlineSchema(component, components, nested)
+	}
+
+	out := make(map[string]interface{}, len(s))
+	for key, value := range s {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if key == "properties" {
+				props := make(map[string]interface{}, len(v))
+				for name, prop := range v {
+					props[name] = inlineSchema(prop.(map[string]interface{}), components, expanding)
+				}
+				out[key] = props
+			} else {
+				out[key] = inlineSchema(v, components, expanding)
+			}
+		case []interface{}:
+			items := make([]interface{}, len(v))
+			for i, item := range v {
+				if m, ok := item.(map[string]interface{}); ok {
+					items[i] = inlineSchema(m, components, expanding)
+				} else {
+					items[i] = item
+				}
+			}
+			out[key] = items
+		default:
+			if key != "nullable" {
+				out[key] = value
+			}
+		}
+	}
+	return out
+}
+
+// exampleArguments fills in the required arguments of the tool at path
+func exampleArguments(path string, properties map[string]interface{}, required []string) map[string]interface{} {
+	onIssue := strings.Contains(path, "/issues/{id}") || strings.HasPrefix(path, "/epics/{id}")
+	args := make(map[string]interface{})
+	for _, name := range required {
+		switch {
+		case onIssue && name == "id":
+			args[name] = "bd-1"
+			continue
+		case onIssue && name == "depId":
+			args[name] = "bd-2"
+			continue
+		}
+		if v, ok := exampleValues[name]; ok {
+			args[name] = v
+			continue
+		}
+		prop, _ := properties[name].(map[string]interface{})
+		if enum, ok := prop["enum"].([]string); ok && len(enum) > 0 {
+			args[name] = enum[0]
+			continue
+		}
+		switch prop["type"] {
+		case "integer":
+			args[name] = 1
+		case "boolean":
+			args[name] = true
+		case "array":
+			args[name] = []interface{}{}
+		case "object":
+			args[name] = map[string]interface{}{}
+		default:
+			args[name] = "<" + name + ">"
+		}
+	}
+	return args
+}
+
+// exampleRequest renders the request a call with args makes, e.g.
+// PATCH /issues/bd-1 {"priority":1}. Path arguments are shown as they are,
+// placeholders included, rather than escaped.
+func exampleRequest(h toolHTTP, args map[string]interface{}) string {
+	path := h.Path
+	for name, value := range args {
+		path = strings.ReplaceAll(path, "{"+name+"}", fmt.Sprint(value))
+	}
+
+	query := url.Values{}
+	for _, name := range h.Query {
+		if value, ok := args[name]; ok {
+			query.Set(name, fmt.Sprint(value))
+		}
+	}
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var body interface{}
+	if h.BodyArg != "" {
+		body = args[h.BodyArg]
+	} else if len(h.Body) > 0 {
+		fields := make(map[string]interface{})
+		for _, name := range h.Body {
+			if value, ok := args[name]; ok {
+				fields[name] = value
+			}
+		}
+		body = fields
+	}
+
+	var b strings.Builder
+	b.WriteString(h.Method + " " + path)
+	if body != nil {
+		b.WriteString(" ")
+		enc := json.NewEncoder(&b)
+		enc.SetEscapeHTML(false)
+		_ = enc.Encode(body)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// handleTools serves the tool manifest at /tools
+func (s *Server) handleTools(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(buildToolManifest(apiRoutes))
+}
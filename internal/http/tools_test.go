@@ -0,0 +1,88 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+)
+
+func TestToolManifest(t *testing.T) {
+	t.Setenv("BEADS_API_SECRET", "sekret")
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The manifest is readable without a token
+	rec := httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, httptest.NewRequest("GET", "/tools", nil))
+	if rec.Code != 200 {
+		t.Fatalf("GET /tools: status %d: %s", rec.Code, rec.Body)
+	}
+	if strings.Contains(rec.Body.String(), "$ref") {
+		t.Error("Expected the tool schemas to be self-contained")
+	}
+
+	var manifest toolManifest
+	if err := json.Unmarshal(rec.Body.Bytes(), &manifest); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	tools := make(map[string]apiTool)
+	for _, tool := range manifest.Tools {
+		if _, dup := tools[tool.Name]; dup {
+			t.Errorf("Duplicate tool name %s", tool.Name)
+		}
+		tools[tool.Name] = tool
+	}
+	for _, left := range []string{"getRoot", "getDocs", "getWs", "getTools"} {
+		if _, ok := tools[left]; ok {
+			t.Errorf("Expected %s left out of the manifest", left)
+		}
+	}
+
+	// Path, query, and body parameters merge into one schema
+	update, ok := tools["patchIssuesId"]
+	if !ok {
+		t.Fatal("missing patchIssuesId")
+	}
+	props, _ := update.Parameters["properties"].(map[string]interface{})
+	for _, name := range []string{"id", "status", "priority"} {
+		if _, ok := props[name]; !ok {
+			t.Errorf("patchIssuesId missing parameter %s", name)
+		}
+	}
+	if update.HTTP.Path != "/issues/{id}" || !contains(update.HTTP.Body, "status") || contains(update.HTTP.Body, "id") {
+		t.Errorf("Expected status in the body and id in the path, got %+v", update.HTTP)
+	}
+	if got := update.Examples[0].Request; !strings.HasPrefix(got, `PATCH /issues/bd-1 {`) || !strings.Contains(got, `"status":"in_progress"`) {
+		t.Errorf("Unexpected example request %s", got)
+	}
+
+	create := tools["postIssues"]
+	required, _ := create.Parameters["required"].([]interface{})
+	if len(required) == 0 || required[0] != "title" {
+		t.Errorf("Expected title required to create, got %v", required)
+	}
+	if create.HTTP.Role != "writer" || tools["postKeys"].HTTP.Role != "admin" {
+		t.Errorf("Expected roles writer and admin, got %s and %s", create.HTTP.Role, tools["postKeys"].HTTP.Role)
+	}
+
+	// Without a written example, the required arguments are filled in
+	search := tools["getIssuesSearch"]
+	if got := search.Examples[0].Request; got != "GET /issues/search?q=login" {
+		t.Errorf("Unexpected generated example %s", got)
+	}
+	if imp := tools["postImport"]; imp.HTTP.BodyArg != "body" {
+		t.Errorf("Expected the import body passed whole, got %+v", imp.HTTP)
+	}
+}
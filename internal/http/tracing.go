@@ -0,0 +1,158 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer creates a span per request. Until tracing is set up (see the
+// telemetry package) it drops them.
+var tracer = otel.Tracer("github.com/imalsogreg/beads/internal/http")
+
+// requestTrace carries what auth learns about a request back out to the
+// request log
+type requestTrace struct {
+	actor string
+}
+
+type requestTraceKey struct{}
+
+// EnableRequestLog logs every request to logger with its method, route,
+// status, latency, and actor. Call before Start.
+func (s *Server) EnableRequestLog(logger *slog.Logger) {
+	s.requestLog = logger
+}
+
+// traceMiddleware starts a span for each request, continuing any trace the
+// caller propagated in a traceparent header, and logs the request when
+// EnableRequestLog was called. It runs before auth so rejected requests are
+// traced and logged too.
+func (s *Server) traceMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		route := r.URL.Path
+		if current := mux.CurrentRoute(r); current != nil {
+			if template, err := current.GetPathTemplate(); err == nil {
+				route = template
+			}
+		}
+		path := r.URL.Path
+		if s.workspace != "" {
+			route = "/w/{workspace}" + route
+			path = "/w/" + s.workspace + path
+		}
+
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracer.Start(ctx, r.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", r.Method),
+				attribute.String("http.route", route),
+				attribute.String("url.path", path),
+			))
+		defer span.End()
+		if s.workspace != "" {
+			span.SetAttributes(attribute.String("beads.workspace", s.workspace))
+		}
+
+		info := &requestTrace{}
+		ctx = context.WithValue(ctx, requestTraceKey{}, info)
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+
+		latency := time.Since(start)
+		s.recordRequest(r.Method+" "+route, recorder.status, latency)
+
+		span.SetAttributes(attribute.Int("http.response.status_code", recorder.status))
+		if info.actor != "" {
+			span.SetAttributes(attribute.String("enduser.id", info.actor))
+		}
+		if recorder.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(recorder.status))
+		}
+
+		if s.requestLog == nil {
+			return
+		}
+		level := slog.LevelInfo
+		if recorder.status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		attrs := []slog.Attr{
+			slog.String("method", r.Method),
+			slog.String("route", route),
+			slog.String("path", path),
+			slog.Int("status", recorder.status),
+			slog.Float64("duration_ms", float64(latency.Microseconds())/1000),
+			slog.Int64("bytes", recorder.bytes),
+			slog.String("actor", info.actor),
+		}
+		if s.workspace != "" {
+			attrs = append(attrs, slog.String("workspace", s.workspace))
+		}
+		if sc := span.SpanContext(); sc.HasTraceID() {
+			attrs = append(attrs, slog.String("trace_id", sc.TraceID().String()))
+		}
+		s.requestLog.LogAttrs(ctx, level, "request", attrs...)
+	})
+}
+
+// actorMiddleware records who made the request, once auth has run, for
+// traceMiddleware to report
+func (s *Server) actorMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if info, ok := r.Context().Value(requestTraceKey{}).(*requestTrace); ok {
+			info.actor = s.getActor(r)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// statusRecorder notes a response's status and size while passing it through
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+func (rec *statusRecorder) WriteHeader(status int) {
+	if !rec.wroteHeader {
+		rec.status, rec.wroteHeader = status, true
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *statusRecorder) Write(p []byte) (int, error) {
+	rec.wroteHeader = true
+	n, err := rec.ResponseWriter.Write(p)
+	rec.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap lets streamed responses flush through
+func (rec *statusRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+// Hijack lets WebSocket upgrades through
+func (rec *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := rec.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not support hijacking")
+	}
+	rec.status, rec.wroteHeader = http.StatusSwitchingProtocols, true
+	return hijacker.Hijack()
+}
@@ -0,0 +1,109 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestRequestTracing(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var logs bytes.Buffer
+	srv.EnableRequestLog(slog.New(slog.NewJSONHandler(&logs, nil)))
+
+	issue := &types.Issue{Title: "Traced", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatal(err)
+	}
+	spans.Reset()
+
+	req := httptest.NewRequest("GET", "/issues/"+issue.ID, nil)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Actor", "alice")
+	rec := httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+
+	var server sdktrace.ReadOnlySpan
+	for _, span := range spans.Ended() {
+		if span.Name() == "GET /issues/{id}" {
+			server = span
+		}
+	}
+	if server == nil {
+		t.Fatalf("No server span among %d spans", len(spans.Ended()))
+	}
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range server.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	if attrs["http.route"].AsString() != "/issues/{id}" || attrs["http.response.status_code"].AsInt64() != 200 {
+		t.Errorf("Unexpected server span attributes: %v", server.Attributes())
+	}
+
+	// Storage calls are children of the request
+	var child bool
+	for _, span := range spans.Ended() {
+		if span.Name() == "sqlite.GetIssue" && span.Parent().SpanID() == server.SpanContext().SpanID() {
+			child = true
+		}
+	}
+	if !child {
+		t.Error("Expected a sqlite.GetIssue span under the request span")
+	}
+
+	var entry struct {
+		Msg     string `json:"msg"`
+		Method  string `json:"method"`
+		Route   string `json:"route"`
+		Status  int    `json:"status"`
+		Actor   string `json:"actor"`
+		TraceID string `json:"trace_id"`
+	}
+	if err := json.Unmarshal(logs.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected one JSON log line, got %q: %v", logs.String(), err)
+	}
+	if entry.Msg != "request" || entry.Method != "GET" || entry.Route != "/issues/{id}" || entry.Status != 200 {
+		t.Errorf("Unexpected log entry: %+v", entry)
+	}
+	if entry.Actor != "alice" {
+		t.Errorf("actor = %q, want alice", entry.Actor)
+	}
+	if entry.TraceID != server.SpanContext().TraceID().String() {
+		t.Errorf("trace_id = %q, want %s", entry.TraceID, server.SpanContext().TraceID())
+	}
+}
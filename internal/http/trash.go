@@ -0,0 +1,79 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// handleDeleteIssue handles DELETE /issues/{id}, which moves the issue to the trash
+func (s *Server) handleDeleteIssue(w http.ResponseWriter, r *http.Request) {
+	s.setDeleted(w, r, true)
+}
+
+// handleRestoreIssue handles POST /issues/{id}/restore
+func (s *Server) handleRestoreIssue(w http.ResponseWriter, r *http.Request) {
+	s.setDeleted(w, r, false)
+}
+
+func (s *Server) setDeleted(w http.ResponseWriter, r *http.Request, deleted bool) {
+	ctx := r.Context()
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("trash requires SQLite backend"))
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	issue, err := s.storage.GetIssue(ctx, id)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if issue == nil {
+		s.writeError(w, r, http.StatusNotFound, types.NotFound("issue", id))
+		return
+	}
+
+	message := "moved to trash"
+	if deleted {
+		err = sqliteStore.SoftDeleteIssue(ctx, id, s.getActor(r))
+	} else {
+		message = "restored"
+		err = sqliteStore.RestoreIssue(ctx, id, s.getActor(r))
+	}
+	if errors.Is(err, sqlite.ErrAlreadyInTrash) || errors.Is(err, sqlite.ErrNotInTrash) {
+		s.writeError(w, r, http.StatusConflict, err)
+		return
+	}
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.writeSuccess(w, r, map[string]string{"message": message}, message)
+}
+
+// handleListTrash handles GET /trash
+func (s *Server) handleListTrash(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("trash requires SQLite backend"))
+		return
+	}
+
+	issues, err := sqliteStore.ListTrash(r.Context())
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if issues == nil {
+		issues = []*types.Issue{}
+	}
+
+	s.writeSuccess(w, r, issues, opTrash)
+}
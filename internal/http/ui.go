@@ -0,0 +1,28 @@
+package http
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+	"strings"
+)
+
+// uiFiles is the web UI: a single page that talks to the JSON API
+//
+//go:embed ui
+var uiFiles embed.FS
+
+// uiHandler serves the web UI's files, with paths relative to /ui/
+func uiHandler() http.Handler {
+	sub, err := fs.Sub(uiFiles, "ui")
+	if err != nil {
+		panic(err) // The directory is embedded, so this can't happen
+	}
+	return http.FileServer(http.FS(sub))
+}
+
+// isUIPath reports whether r is for the web UI's files, which are public;
+// the page asks for a token when the API needs one
+func isUIPath(r *http.Request) bool {
+	return r.Method == "GET" && (r.URL.Path == "/ui" || strings.HasPrefix(r.URL.Path, "/ui/"))
+}
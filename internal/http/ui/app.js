@@ -0,0 +1,376 @@
+// Beads web UI: a small single-page app over the JSON API. Routes live in
+// the URL hash: #/ (list), #/board, #/issue/<id>, #/graph/<id>.
+"use strict";
+
+const STATUS_NAMES = { open: "Open", in_progress: "In progress", blocked: "Blocked", closed: "Closed" };
+const app = document.getElementById("app");
+
+// h builds an element; children may be strings, elements, or arrays of them.
+// Text always goes through text nodes, so issue content can't inject markup.
+function h(tag, attrs, ...children) {
+  const el = document.createElement(tag);
+  for (const [k, v] of Object.entries(attrs || {})) {
+    if (k.startsWith("on")) el.addEventListener(k.slice(2), v);
+    else if (v !== false && v != null) el.setAttribute(k, v === true ? "" : v);
+  }
+  for (const child of children.flat()) {
+    if (child != null) el.append(child instanceof Node ? child : String(child));
+  }
+  return el;
+}
+
+function svg(tag, attrs, ...children) {
+  const el = document.createElementNS("http://www.w3.org/2000/svg", tag);
+  for (const [k, v] of Object.entries(attrs || {})) {
+    if (k.startsWith("on")) el.addEventListener(k.slice(2), v);
+    else el.setAttribute(k, v);
+  }
+  for (const child of children.flat()) {
+    if (child != null) el.append(child instanceof Node ? child : String(child));
+  }
+  return el;
+}
+
+// api calls the JSON API, asking for a token when the server wants one
+async function api(method, path, body, headers) {
+  const init = { method, headers: { Accept: "application/json", ...(headers || {}) } };
+  const token = localStorage.getItem("beads.token");
+  if (token) init.headers.Authorization = "Bearer " + token;
+  if (body !== undefined) {
+    init.headers["Content-Type"] = "application/json";
+    init.body = JSON.stringify(body);
+  }
+  const resp = await fetch("/v1" + path, init);
+  if (resp.status === 401) {
+    await signIn();
+    return api(method, path, body, headers);
+  }
+  const data = resp.headers.get("Content-Type")?.includes("json") ? await resp.json() : null;
+  if (!resp.ok) {
+    const err = new Error(errorMessage(data) || resp.statusText);
+    err.status = resp.status;
+    err.code = data && data.code;
+    err.data = data;
+    throw err;
+  }
+  return { data, etag: resp.headers.get("ETag") };
+}
+
+// errorMessage describes an API error, one line per problem with an invalid issue
+function errorMessage(data) {
+  const violations = data && data.details && data.details.violations;
+  if (violations && violations.length > 1) return violations.map((v) => v.message).join("\n");
+  return data && data.error;
+}
+
+function signIn() {
+  const dialog = document.getElementById("login");
+  return new Promise((resolve) => {
+    dialog.addEventListener("close", () => {
+      localStorage.setItem("beads.token", dialog.querySelector("input").value.trim());
+      document.getElementById("sign-out").hidden = false;
+      resolve();
+    }, { once: true });
+    dialog.showModal();
+  });
+}
+
+// statusName names a status for display; custom ones read as their words
+function statusName(status) {
+  if (STATUS_NAMES[status]) return STATUS_NAMES[status];
+  const words = status.replace(/[_-]+/g, " ");
+  return words.charAt(0).toUpperCase() + words.slice(1);
+}
+
+// statuses lists the workflow's statuses, fetched once from GET /workflow
+let workflowStatuses;
+async function statuses() {
+  if (!workflowStatuses) workflowStatuses = (await api("GET", "/workflow")).data.statuses.map((s) => s.name);
+  return workflowStatuses;
+}
+
+function statusBadge(status) {
+  return h("span", { class: "status status-" + status }, statusName(status));
+}
+
+function priority(p) {
+  return h("span", { class: "priority p" + p }, "P" + p);
+}
+
+function issueLink(id) {
+  return h("a", { href: "#/issue/" + encodeURIComponent(id), class: "id" }, id);
+}
+
+function showError(err) {
+  app.replaceChildren(h("p", { class: "error" }, "Error: " + err.message));
+}
+
+// List view: filterable table of issues
+async function renderList(params) {
+  const query = new URLSearchParams();
+  if (params.get("status")) query.set("status", params.get("status"));
+  if (params.get("q")) query.set("q", params.get("q"));
+  const [{ data: issues }, all] = await Promise.all([api("GET", "/issues?" + query), statuses()]);
+
+  const status = h("select", {
+    onchange: (e) => {
+      params.set("status", e.target.value);
+      location.hash = "#/?" + params;
+    },
+  }, h("option", { value: "" }, "All statuses"),
+    all.map((s) => h("option", { value: s, selected: params.get("status") === s }, statusName(s))));
+
+  const rows = (issues || []).map((issue) =>
+    h("tr", { class: "issue", onclick: () => (location.hash = "#/issue/" + encodeURIComponent(issue.id)) },
+      h("td", { class: "id" }, issue.id),
+      h("td", {}, priority(issue.priority)),
+      h("td", {}, issue.issue_type),
+      h("td", {}, issue.title),
+      h("td", {}, statusBadge(issue.status)),
+      h("td", {}, issue.assignee || "")));
+
+  app.replaceChildren(
+    h("div", { class: "filters" }, status,
+      params.get("q") ? h("span", { class: "meta" }, `Matching "${params.get("q")}"`) : null,
+      h("span", { class: "meta" }, `${rows.length} issue(s)`)),
+    rows.length
+      ? h("table", {}, h("tr", {}, ["ID", "Pri", "Type", "Title", "Status", "Assignee"].map((c) => h("th", {}, c))), rows)
+      : h("p", { class: "empty" }, "No issues"));
+}
+
+// Board view: one column per status; dropping a card changes its status
+async function renderBoard() {
+  const [{ data: issues }, all] = await Promise.all([api("GET", "/issues"), statuses()]);
+  const columns = all.map((status) => {
+    const cards = (issues || []).filter((i) => i.status === status).map((issue) =>
+      h("div", {
+        class: "card", draggable: "true",
+        ondragstart: (e) => e.dataTransfer.setData("text/plain", issue.id),
+        ondblclick: () => (location.hash = "#/issue/" + encodeURIComponent(issue.id)),
+      }, h("div", {}, issueLink(issue.id), " ", priority(issue.priority)), h("div", { class: "title" }, issue.title)));
+    const column = h("section", { class: "column" }, h("h2", {}, `${statusName(status)} (${cards.length})`), cards);
+    column.addEventListener("dragover", (e) => {
+      e.preventDefault();
+      column.classList.add("over");
+    });
+    column.addEventListener("dragleave", () => column.classList.remove("over"));
+    column.addEventListener("drop", async (e) => {
+      e.preventDefault();
+      column.classList.remove("over");
+      const id = e.dataTransfer.getData("text/plain");
+      const issue = issues.find((i) => i.id === id);
+      if (!issue || issue.status === status) return;
+      try {
+        await setStatus(id, status);
+        renderBoard().catch(showError);
+      } catch (err) {
+        alert(err.message);
+      }
+    });
+    return column;
+  });
+  app.replaceChildren(h("p", { class: "meta" }, "Drag cards between columns to change status. Double-click a card to open it."),
+    h("div", { class: "board" }, columns));
+}
+
+// setStatus changes an issue's status, closing it through the close endpoint
+async function setStatus(id, status, etag, reason) {
+  const path = "/issues/" + encodeURIComponent(id);
+  if (status === "closed") return api("POST", path + "/close", reason ? { reason } : {});
+  return api("PATCH", path, { status }, etag ? { "If-Match": etag } : undefined);
+}
+
+// threadComments orders comments depth-first so each reply follows the one it answers
+function threadComments(comments) {
+  const ids = new Set(comments.map((c) => c.id));
+  const replies = {};
+  for (const c of comments) {
+    const parent = c.reply_to && ids.has(c.reply_to) && c.reply_to !== c.id ? c.reply_to : 0;
+    (replies[parent] = replies[parent] || []).push(c);
+  }
+  const out = [];
+  const seen = new Set();
+  const visit = (c, depth) => {
+    if (seen.has(c.id)) return;
+    seen.add(c.id);
+    out.push({ comment: c, depth });
+    for (const reply of replies[c.id] || []) visit(reply, depth + 1);
+  };
+  for (const c of replies[0] || []) visit(c, 0);
+  // Replies caught in a cycle have no root to hang from
+  for (const c of comments) visit(c, 0);
+  return out;
+}
+
+// Detail view: fields, text, comments, and actions
+async function renderIssue(id) {
+  const path = "/issues/" + encodeURIComponent(id);
+  const [{ data: issue, etag }, { data: list }] = await Promise.all([api("GET", path), api("GET", path + "/comments")]);
+  const comments = threadComments(list || []);
+  // Only the moves the workflow allows from here get a button
+  const { data: workflow } = await api("GET", "/workflow?from=" + encodeURIComponent(issue.status));
+
+  const section = (title, text) => (text ? [h("h3", {}, title), h("div", { class: "text" }, text)] : []);
+  const commentBox = h("textarea", { rows: 3, placeholder: "Add a comment" });
+  const actions = (workflow.next || []).map(({ to: s, require }) =>
+    h("button", {
+      type: "button",
+      title: require ? "Requires " + require.join(", ") : "",
+      onclick: async () => {
+        let reason;
+        if (require && require.includes("reason")) {
+          reason = prompt("Reason for closing");
+          if (!reason) return;
+        }
+        try {
+          await setStatus(id, s, etag, reason);
+        } catch (err) {
+          if (err.code === "conflict") alert("Someone else changed this issue; showing the latest version.");
+          else return alert(err.message);
+        }
+        renderIssue(id).catch(showError);
+      },
+    }, s === "closed" ? "Close" : s === "open" && issue.status === "closed" ? "Reopen" : "Mark " + statusName(s).toLowerCase()));
+
+  app.replaceChildren(
+    h("h2", {}, h("span", { class: "id" }, issue.id), " ", issue.title),
+    h("dl", { class: "fields" },
+      h("dt", {}, "Status"), h("dd", {}, statusBadge(issue.status)),
+      h("dt", {}, "Priority"), h("dd", {}, priority(issue.priority)),
+      h("dt", {}, "Type"), h("dd", {}, issue.issue_type),
+      issue.assignee ? [h("dt", {}, "Assignee"), h("dd", {}, issue.assignee)] : [],
+      issue.parent_id ? [h("dt", {}, "Parent"), h("dd", {}, issueLink(issue.parent_id))] : [],
+      issue.subtasks ? [h("dt", {}, "Subtasks"), h("dd", {}, `${issue.subtasks.closed}/${issue.subtasks.total} closed`)] : [],
+      issue.watchers ? [h("dt", {}, "Watchers"), h("dd", {}, issue.watchers.join(", "))] : [],
+      h("dt", {}, "Updated"), h("dd", {}, new Date(issue.updated_at).toLocaleString())),
+    h("div", { class: "detail-actions" }, actions,
+      h("a", { href: "#/graph/" + encodeURIComponent(id) }, "Dependency graph")),
+    section("Description", issue.description),
+    section("Design", issue.design),
+    section("Acceptance criteria", issue.acceptance_criteria),
+    section("Notes", issue.notes),
+    h("h3", {}, `Comments (${comments.length})`),
+    comments.map(({ comment: c, depth }) => h("div", { class: "comment", style: `margin-left: ${2 * depth}em` },
+      h("div", { class: "meta" }, `${c.author}, ${new Date(c.created_at).toLocaleString()}${c.updated_at ? " (edited)" : ""}`),
+      h("div", { class: "text" }, c.text),
+      c.reactions ? h("div", { class: "meta" }, Object.entries(c.reactions).map(([r, n]) => `${r} ${n}`).join("  ")) : [])),
+    commentBox,
+    h("div", { class: "actions" }, h("button", {
+      type: "button", class: "primary",
+      onclick: async () => {
+        const text = commentBox.value.trim();
+        if (!text) return;
+        try {
+          await api("POST", path + "/comments", { text });
+          renderIssue(id).catch(showError);
+        } catch (err) {
+          alert(err.message);
+        }
+      },
+    }, "Comment")));
+}
+
+// Graph view: the issue's dependencies, one rank per distance from the root
+async function renderGraph(id) {
+  const { data: graph } = await api("GET", "/issues/" + encodeURIComponent(id) + "/tree?format=graph");
+  const rank = { [graph.root]: 0 };
+  const queue = [graph.root];
+  while (queue.length) {
+    const current = queue.shift();
+    for (const e of graph.edges) {
+      const next = e.issue_id === current ? e.depends_on_id : e.depends_on_id === current ? e.issue_id : null;
+      if (next && rank[next] === undefined) {
+        rank[next] = rank[current] + 1;
+        queue.push(next);
+      }
+    }
+  }
+
+  const nodeW = 180, nodeH = 44, gapX = 40, gapY = 60;
+  const ranks = [];
+  for (const issue of graph.issues) (ranks[rank[issue.id] || 0] ||= []).push(issue);
+  const pos = {};
+  ranks.forEach((row, r) => row.forEach((issue, i) => (pos[issue.id] = { x: 20 + i * (nodeW + gapX), y: 20 + r * (nodeH + gapY) })));
+  const width = 40 + Math.max(...ranks.map((row) => row.length)) * (nodeW + gapX);
+  const height = 40 + ranks.length * (nodeH + gapY);
+
+  const edges = graph.edges.map((e) => {
+    const from = pos[e.issue_id], to = pos[e.depends_on_id];
+    return svg("line", {
+      class: "edge " + e.type, "marker-end": "url(#arrow)",
+      x1: from.x + nodeW / 2, y1: from.y + (to.y > from.y ? nodeH : 0),
+      x2: to.x + nodeW / 2, y2: to.y + (to.y > from.y ? 0 : nodeH),
+    });
+  });
+  const nodes = graph.issues.map((issue) => {
+    const p = pos[issue.id];
+    const title = issue.title.length > 26 ? issue.title.slice(0, 25) + "…" : issue.title;
+    return svg("g", {
+      class: "node status-" + issue.status + (issue.id === graph.root ? " root" : ""),
+      onclick: () => (location.hash = "#/issue/" + encodeURIComponent(issue.id)),
+    },
+      svg("title", {}, issue.title),
+      svg("rect", { x: p.x, y: p.y, width: nodeW, height: nodeH, rx: 6 }),
+      svg("text", { x: p.x + 8, y: p.y + 17, class: "node-id" }, issue.id),
+      svg("text", { x: p.x + 8, y: p.y + 34 }, title));
+  });
+
+  app.replaceChildren(
+    h("h2", {}, "Dependencies of ", issueLink(id)),
+    h("p", { class: "meta" }, "Arrows point from an issue to what it depends on. Click an issue to open it."),
+    h("div", { class: "graph" }, svg("svg", { width, height },
+      svg("defs", {}, svg("marker", { id: "arrow", viewBox: "0 0 10 10", refX: 10, refY: 5, markerWidth: 8, markerHeight: 8, orient: "auto" },
+        svg("path", { d: "M0,0 L10,5 L0,10 z" }))),
+      edges, nodes)));
+}
+
+async function route() {
+  const [path, query] = location.hash.slice(1).split("?");
+  const parts = (path || "/").split("/").filter(Boolean).map(decodeURIComponent);
+  const view = parts[0] === "board" ? "board" : parts[0] ? "" : "list";
+  for (const a of document.querySelectorAll("nav a")) a.classList.toggle("current", a.dataset.view === view);
+  try {
+    if (parts[0] === "board") await renderBoard();
+    else if (parts[0] === "issue" && parts[1]) await renderIssue(parts[1]);
+    else if (parts[0] === "graph" && parts[1]) await renderGraph(parts[1]);
+    else await renderList(new URLSearchParams(query));
+  } catch (err) {
+    showError(err);
+  }
+}
+
+document.getElementById("search").addEventListener("submit", (e) => {
+  e.preventDefault();
+  const q = e.target.q.value.trim();
+  location.hash = q ? "#/?" + new URLSearchParams({ q }) : "#/";
+});
+
+document.getElementById("new-issue").addEventListener("click", () => {
+  const dialog = document.getElementById("create");
+  dialog.querySelector("form").reset();
+  dialog.addEventListener("close", async () => {
+    if (dialog.returnValue !== "ok") return;
+    const form = dialog.querySelector("form");
+    try {
+      const { data: issue } = await api("POST", "/issues", {
+        title: form.elements.title.value,
+        description: form.elements.description.value,
+        issue_type: form.elements.issue_type.value,
+        priority: Number(form.elements.priority.value),
+      });
+      location.hash = "#/issue/" + encodeURIComponent(issue.id);
+    } catch (err) {
+      alert(err.message);
+    }
+  }, { once: true });
+  dialog.showModal();
+});
+
+document.getElementById("sign-out").hidden = !localStorage.getItem("beads.token");
+document.getElementById("sign-out").addEventListener("click", () => {
+  localStorage.removeItem("beads.token");
+  location.reload();
+});
+
+window.addEventListener("hashchange", route);
+route();
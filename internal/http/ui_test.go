@@ -0,0 +1,90 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/depgraph"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestWebUI(t *testing.T) {
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	t.Setenv("BEADS_API_SECRET", "sekret")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	get := func(path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// The page and its assets load without a token
+	rec := get("/ui/", "")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "app.js") {
+		t.Fatalf("Expected the UI page, got %d: %s", rec.Code, rec.Body.String())
+	}
+	for _, path := range []string{"/ui/app.js", "/ui/style.css"} {
+		if rec := get(path, ""); rec.Code != http.StatusOK {
+			t.Errorf("Expected 200 for %s, got %d", path, rec.Code)
+		}
+	}
+	if rec := get("/ui", ""); rec.Code != http.StatusMovedPermanently || rec.Header().Get("Location") != "/ui/" {
+		t.Errorf("Expected /ui to redirect to /ui/, got %d %q", rec.Code, rec.Header().Get("Location"))
+	}
+	if rec := get("/ui/missing.js", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a missing file, got %d", rec.Code)
+	}
+	// The API it calls still needs one
+	if rec := get("/issues", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for the API without a token, got %d", rec.Code)
+	}
+
+	// The graph view reads nodes and edges from the tree endpoint
+	ctx := t.Context()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	parent := &types.Issue{Title: "Parent", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	child := &types.Issue{Title: "Child", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	for _, issue := range []*types.Issue{parent, child} {
+		if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dep := &types.Dependency{IssueID: parent.ID, DependsOnID: child.ID, Type: types.DepBlocks}
+	if err := store.AddDependency(ctx, dep, "tester"); err != nil {
+		t.Fatal(err)
+	}
+
+	rec = get("/issues/"+parent.ID+"/tree?format=graph", "sekret")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200 for the graph, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var graph depgraph.Graph
+	if err := json.Unmarshal(rec.Body.Bytes(), &graph); err != nil {
+		t.Fatal(err)
+	}
+	if graph.Root != parent.ID || len(graph.Issues) != 2 || len(graph.Edges) != 1 {
+		t.Fatalf("Expected %s with 2 issues and 1 edge, got %+v", parent.ID, graph)
+	}
+	if graph.Edges[0].IssueID != parent.ID || graph.Edges[0].DependsOnID != child.ID {
+		t.Errorf("Expected an edge from %s to %s, got %+v", parent.ID, child.ID, graph.Edges[0])
+	}
+}
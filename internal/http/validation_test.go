@@ -0,0 +1,84 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestCreateIssueValidation(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	do := func(method, path, body, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", accept)
+		req.Header.Set("X-Actor", "alice")
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do("POST", "/issues", `{"title": "", "issue_type": "story", "priority": 2}`, "application/json")
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected 422, got %d: %s", rec.Code, rec.Body)
+	}
+	var body struct {
+		Code    types.ErrorCode       `json:"code"`
+		Details types.ValidationError `json:"details"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	violations := body.Details.Violations
+	if body.Code != types.CodeValidationFailed || len(violations) != 2 || violations[0].Field != "title" || violations[1].Field != "issue_type" {
+		t.Errorf("Expected title and issue_type violations, got %s", rec.Body)
+	}
+
+	rec = do("PUT", "/config/validation.required.bug", `{"value": "description,bogus"}`, "application/json")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown field, got %d: %s", rec.Code, rec.Body)
+	}
+	rec = do("PUT", "/config/validation.required.bug", `{"value": "description"}`, "application/json")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+
+	rec = do("POST", "/issues", `{"title": "Crash", "issue_type": "bug", "priority": 1}`, "text/plain")
+	if rec.Code != http.StatusUnprocessableEntity || !strings.Contains(rec.Body.String(), "description is required for bug issues") {
+		t.Errorf("Expected a 422 listing the description, got %d: %s", rec.Code, rec.Body)
+	}
+	rec = do("POST", "/issues", `{"title": "Crash", "description": "On save", "issue_type": "bug", "priority": 1}`, "application/json")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var issue types.Issue
+	if err := json.Unmarshal(rec.Body.Bytes(), &issue); err != nil {
+		t.Fatal(err)
+	}
+
+	rec = do("PATCH", "/issues/"+issue.ID, `{"description": ""}`, "application/json")
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 for clearing a required field, got %d: %s", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,96 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// APIVersion is the current version of the API, served under /v1/
+const APIVersion = 1
+
+// apiVersionHeader names the version a request wants, and the one its
+// response follows
+const apiVersionHeader = "API-Version"
+
+// unversionedDeprecated is when paths without a version were deprecated in
+// favor of /v1/, sent as the Deprecation header
+var unversionedDeprecated = time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)
+
+// versionedPath matches a path that starts with a version, e.g. /v2/issues
+var versionedPath = regexp.MustCompile(`^/v([0-9]+)(/|$)`)
+
+// unversionedPaths are the pages and probes that aren't part of the
+// versioned API and so aren't deprecated without a version
+var unversionedPaths = map[string]bool{
+	"/": true, "/openapi.json": true, "/docs": true, "/tools": true, "/ui": true,
+	"/health": true, "/healthz": true, "/readyz": true, "/ping": true, "/metrics": true,
+}
+
+// SetUnversionedSunset announces, with a Sunset header on every request to
+// a path without a version, when those paths will stop working
+func (s *Server) SetUnversionedSunset(sunset time.Time) {
+	s.unversionedSunset = sunset
+}
+
+// routeVersions serves /v1/... with the routes of the current version. The
+// paths without a version are a compatibility shim for clients that predate
+// /v1/: they answer as v1 does, but with Deprecation and Link headers
+// pointing at their successor, unless the request names its version with an
+// API-Version header.
+func (s *Server) routeVersions(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested, err := parseAPIVersion(r.Header.Get(apiVersionHeader))
+		if err != nil {
+			s.writeError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		path := r.URL.Path
+		if m := versionedPath.FindStringSubmatch(path); m != nil {
+			version, _ := strconv.Atoi(m[1])
+			if version != APIVersion {
+				s.writeError(w, r, http.StatusNotFound, fmt.Errorf("unsupported API version v%s (this server speaks v%d)", m[1], APIVersion))
+				return
+			}
+			if requested != 0 && requested != version {
+				s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("%s: %d conflicts with the path's v%d", apiVersionHeader, requested, version))
+				return
+			}
+			w.Header().Set(apiVersionHeader, strconv.Itoa(APIVersion))
+
+			r2 := r.Clone(r.Context())
+			r2.URL.Path = "/" + strings.TrimPrefix(strings.TrimPrefix(path, m[0]), "/")
+			r2.URL.RawPath = ""
+			next.ServeHTTP(w, r2)
+			return
+		}
+
+		w.Header().Set(apiVersionHeader, strconv.Itoa(APIVersion))
+		if requested == 0 && !unversionedPaths[path] && !strings.HasPrefix(path, "/ui/") {
+			w.Header().Set("Deprecation", "@"+strconv.FormatInt(unversionedDeprecated.Unix(), 10))
+			w.Header().Add("Link", fmt.Sprintf(`</v%d%s>; rel="successor-version"`, APIVersion, path))
+			if !s.unversionedSunset.IsZero() {
+				w.Header().Set("Sunset", s.unversionedSunset.UTC().Format(http.TimeFormat))
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// parseAPIVersion reads an API-Version header: a supported version, as 1 or
+// v1, or nothing (0)
+func parseAPIVersion(header string) (int, error) {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0, nil
+	}
+	version, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(header), "v"))
+	if err != nil || version != APIVersion {
+		return 0, fmt.Errorf("unsupported %s %q (this server speaks %d)", apiVersionHeader, header, APIVersion)
+	}
+	return version, nil
+}
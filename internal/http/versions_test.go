@@ -0,0 +1,95 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+)
+
+func TestAPIVersions(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	do := func(method, path, version, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Actor", "alice")
+		if version != "" {
+			req.Header.Set("API-Version", version)
+		}
+		rec := httptest.NewRecorder()
+		srv.httpServer.Handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do("POST", "/v1/issues", "", `{"title": "Versioned", "issue_type": "task", "priority": 2}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST /v1/issues: status %d: %s", rec.Code, rec.Body)
+	}
+	if rec.Header().Get("API-Version") != "1" || rec.Header().Get("Deprecation") != "" {
+		t.Errorf("Expected API-Version 1 and no Deprecation, got %v", rec.Header())
+	}
+	rec = do("GET", "/v1/issues/bd-1", "v1", "")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Versioned") {
+		t.Errorf("GET /v1/issues/bd-1: status %d: %s", rec.Code, rec.Body)
+	}
+
+	// The unversioned paths answer the same, but are deprecated
+	rec = do("GET", "/issues/bd-1", "", "")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Versioned") {
+		t.Errorf("GET /issues/bd-1: status %d: %s", rec.Code, rec.Body)
+	}
+	if got := rec.Header().Get("Deprecation"); got != "@1792281600" {
+		t.Errorf("Expected Deprecation @1792281600, got %q", got)
+	}
+	if got := rec.Header().Get("Link"); got != `</v1/issues/bd-1>; rel="successor-version"` {
+		t.Errorf("Expected a successor-version link, got %q", got)
+	}
+	if rec.Header().Get("Sunset") != "" {
+		t.Error("Expected no Sunset before one is announced")
+	}
+	if rec := do("GET", "/issues/bd-1", "1", ""); rec.Code != http.StatusOK || rec.Header().Get("Deprecation") != "" {
+		t.Errorf("Expected no Deprecation with API-Version, got %d %v", rec.Code, rec.Header())
+	}
+	if rec := do("GET", "/healthz", "", ""); rec.Header().Get("Deprecation") != "" {
+		t.Error("Expected probes not to be deprecated")
+	}
+
+	srv.SetUnversionedSunset(time.Date(2027, time.June, 30, 0, 0, 0, 0, time.UTC))
+	if got := do("GET", "/issues", "", "").Header().Get("Sunset"); got != "Wed, 30 Jun 2027 00:00:00 GMT" {
+		t.Errorf("Expected the announced Sunset, got %q", got)
+	}
+	if got := do("GET", "/v1/issues", "", "").Header().Get("Sunset"); got != "" {
+		t.Errorf("Expected no Sunset under /v1/, got %q", got)
+	}
+
+	// Versions the server doesn't speak
+	if rec := do("GET", "/v2/issues", "", ""); rec.Code != http.StatusNotFound || !strings.Contains(rec.Body.String(), `"not_found"`) {
+		t.Errorf("Expected 404 for /v2/, got %d: %s", rec.Code, rec.Body)
+	}
+	if rec := do("GET", "/issues", "2", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for API-Version 2, got %d", rec.Code)
+	}
+	if rec := do("GET", "/v1/issues", "latest", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for API-Version latest, got %d", rec.Code)
+	}
+}
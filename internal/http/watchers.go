@@ -0,0 +1,84 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// watchersResult is an issue's watchers after a watch, unwatch, or lookup
+type watchersResult struct {
+	IssueID  string   `json:"issue_id"`
+	Watchers []string `json:"watchers"`
+}
+
+// handleWatchIssue handles POST /issues/{id}/watch, subscribing the caller
+// to every change on the issue
+func (s *Server) handleWatchIssue(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("watching requires SQLite backend"))
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	issue, err := sqliteStore.GetIssue(r.Context(), id)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if issue == nil {
+		s.writeError(w, r, http.StatusNotFound, types.NotFound("issue", id))
+		return
+	}
+	if err := sqliteStore.WatchIssue(r.Context(), id, s.getActor(r)); err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.writeWatchers(w, r, sqliteStore, id)
+}
+
+// handleUnwatchIssue handles DELETE /issues/{id}/watch
+func (s *Server) handleUnwatchIssue(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("watching requires SQLite backend"))
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	if err := sqliteStore.UnwatchIssue(r.Context(), id, s.getActor(r)); err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.writeWatchers(w, r, sqliteStore, id)
+}
+
+// handleListWatchers handles GET /issues/{id}/watchers
+func (s *Server) handleListWatchers(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("watching requires SQLite backend"))
+		return
+	}
+
+	s.writeWatchers(w, r, sqliteStore, mux.Vars(r)["id"])
+}
+
+func (s *Server) writeWatchers(w http.ResponseWriter, r *http.Request, store *sqlite.SQLiteStorage, id string) {
+	watchers, err := store.GetWatchers(r.Context(), id)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if watchers == nil {
+		watchers = []string{}
+	}
+
+	s.writeSuccess(w, r, &watchersResult{IssueID: id, Watchers: watchers}, opWatchers)
+}
@@ -0,0 +1,83 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestWatchers(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	do := func(method, path, actor, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Accept", accept)
+		req.Header.Set("X-Actor", actor)
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+	watchers := func(rec *httptest.ResponseRecorder) []string {
+		t.Helper()
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+		}
+		var result watchersResult
+		if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+			t.Fatal(err)
+		}
+		return result.Watchers
+	}
+
+	issue := &types.Issue{Title: "Watch me", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatal(err)
+	}
+	path := "/issues/" + issue.ID
+
+	watchers(do("POST", path+"/watch", "alice", "application/json"))
+	if got := watchers(do("POST", path+"/watch", "bob", "application/json")); len(got) != 2 {
+		t.Errorf("Expected alice and bob watching, got %v", got)
+	}
+	if rec := do("POST", "/issues/bd-999/watch", "alice", "application/json"); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 watching a missing issue, got %d", rec.Code)
+	}
+	if got := watchers(do("DELETE", path+"/watch", "alice", "application/json")); len(got) != 1 || got[0] != "bob" {
+		t.Errorf("Expected only bob left, got %v", got)
+	}
+	if got := watchers(do("GET", path+"/watchers", "", "application/json")); len(got) != 1 || got[0] != "bob" {
+		t.Errorf("Expected bob listed, got %v", got)
+	}
+
+	// Issue detail lists watchers
+	var detail types.Issue
+	if err := json.Unmarshal(do("GET", path, "", "application/json").Body.Bytes(), &detail); err != nil {
+		t.Fatal(err)
+	}
+	if len(detail.Watchers) != 1 || detail.Watchers[0] != "bob" {
+		t.Errorf("Expected issue detail to list bob, got %v", detail.Watchers)
+	}
+	if text := do("GET", path, "", "text/plain").Body.String(); !strings.Contains(text, "Watchers: bob") {
+		t.Errorf("Expected a Watchers line in:\n%s", text)
+	}
+}
@@ -0,0 +1,131 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+	"github.com/imalsogreg/beads/internal/webhook"
+)
+
+// webhookRequest is the body of POST /webhooks
+type webhookRequest struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret,omitempty" doc:"Signs deliveries with HMAC-SHA256 in X-Beads-Signature"`
+	Events []string `json:"events,omitempty" doc:"issue.created, issue.updated, issue.closed, issue.commented, issue.mentioned; empty for all"`
+}
+
+// handleListWebhooks handles GET /webhooks
+func (s *Server) handleListWebhooks(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("webhooks require SQLite backend"))
+		return
+	}
+
+	hooks, err := sqliteStore.ListWebhooks(r.Context())
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if hooks == nil {
+		hooks = []*sqlite.Webhook{}
+	}
+
+	s.writeSuccess(w, r, hooks, opWebhooks)
+}
+
+// handleCreateWebhook handles POST /webhooks
+func (s *Server) handleCreateWebhook(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("webhooks require SQLite backend"))
+		return
+	}
+
+	var body webhookRequest
+	if err := s.parseBody(r, &body); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if err := webhook.ValidateURL(body.URL); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if err := webhook.ValidateEvents(body.Events); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	hook := &sqlite.Webhook{URL: body.URL, Secret: body.Secret, Events: body.Events, CreatedBy: s.getActor(r)}
+	if err := sqliteStore.CreateWebhook(r.Context(), hook); err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.writeSuccess(w, r, []*sqlite.Webhook{hook}, opWebhooks)
+}
+
+// handleDeleteWebhook handles DELETE /webhooks/{id}
+func (s *Server) handleDeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, hook, ok := s.lookupWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	if err := sqliteStore.DeleteWebhook(r.Context(), hook.ID); err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.writeSuccess(w, r, map[string]string{"message": "webhook deleted"}, "webhook_delete")
+}
+
+// handleTestWebhook handles POST /webhooks/{id}/test by sending a ping
+// delivery once, without retries
+func (s *Server) handleTestWebhook(w http.ResponseWriter, r *http.Request) {
+	_, hook, ok := s.lookupWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	sender := webhook.NewSender()
+	sender.Retries = 0
+	payload := &webhook.Payload{Event: webhook.EventPing, Actor: s.getActor(r), Timestamp: time.Now()}
+	if err := sender.Send(r.Context(), hook, payload); err != nil {
+		s.writeError(w, r, http.StatusBadGateway, err)
+		return
+	}
+
+	s.writeSuccess(w, r, map[string]string{"message": "delivered"}, "webhook_test")
+}
+
+// lookupWebhook resolves the {id} route variable, writing an error response
+// if the backend or webhook is missing
+func (s *Server) lookupWebhook(w http.ResponseWriter, r *http.Request) (*sqlite.SQLiteStorage, *sqlite.Webhook, bool) {
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("webhooks require SQLite backend"))
+		return nil, nil, false
+	}
+
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("invalid webhook ID %q", mux.Vars(r)["id"]))
+		return nil, nil, false
+	}
+	hook, err := sqliteStore.GetWebhook(r.Context(), id)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return nil, nil, false
+	}
+	if hook == nil {
+		s.writeError(w, r, http.StatusNotFound, types.NotFound("webhook", id))
+		return nil, nil, false
+	}
+	return sqliteStore, hook, true
+}
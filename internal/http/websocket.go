@@ -0,0 +1,474 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// WebSocket timing
+const (
+	wsPollInterval = time.Second      // how often the hub checks storage for changes
+	wsPingInterval = 30 * time.Second // server pings at this interval
+	wsPongWait     = 60 * time.Second // connections without a pong for this long are closed
+	wsWriteWait    = 10 * time.Second
+	wsSendBuffer   = 256 // queued messages before a slow client is dropped
+	wsMaxMessage   = 64 * 1024
+)
+
+var wsUpgrader = websocket.Upgrader{
+	ReadBufferSize:  4096,
+	WriteBufferSize: 4096,
+}
+
+// wsFilter selects the issues a client receives. Labels match if the issue has
+// any of them (infra/* matches labels under infra, by path); empty fields
+// match everything.
+type wsFilter struct {
+	Labels   []string `json:"labels,omitempty"`
+	Assignee string   `json:"assignee,omitempty"`
+	Status   []string `json:"status,omitempty"`
+}
+
+func (f *wsFilter) matches(issue *types.Issue) bool {
+	if f.Assignee != "" && issue.Assignee != f.Assignee {
+		return false
+	}
+	if len(f.Status) > 0 && !containsString(f.Status, string(issue.Status)) {
+		return false
+	}
+	if len(f.Labels) > 0 {
+		for _, l := range issue.Labels {
+			for _, want := range f.Labels {
+				if types.MatchesLabelFilter(l, want) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// wsClientMessage is sent by clients
+type wsClientMessage struct {
+	Type   string   `json:"type"` // subscribe or unsubscribe
+	Filter wsFilter `json:"filter"`
+}
+
+// wsServerMessage is sent to clients
+type wsServerMessage struct {
+	Type   string         `json:"type"` // snapshot, add, patch, remove, error
+	ID     string         `json:"id,omitempty"`
+	Issue  *types.Issue   `json:"issue,omitempty"`
+	Issues []*types.Issue `json:"issues,omitempty"`
+	Patch  []patchOp      `json:"patch,omitempty"`
+	Reason string         `json:"reason,omitempty"`
+	Error  string         `json:"error,omitempty"`
+}
+
+// wsHub polls storage for changed issues and fans them out to clients. It
+// watches the event log rather than hooking writes, so changes made by the
+// CLI or daemon are streamed too.
+type wsHub struct {
+	store *sqlite.SQLiteStorage
+
+	mu          sync.Mutex
+	clients     map[*wsClient]bool
+	lastEventID int64
+	issueIDs    map[string]bool
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func newWSHub(store *sqlite.SQLiteStorage) (*wsHub, error) {
+	ctx := context.Background()
+	last, err := store.GetLatestEventID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := store.GetAllIssueIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	h := &wsHub{
+		store:       store,
+		clients:     make(map[*wsClient]bool),
+		lastEventID: last,
+		issueIDs:    toSet(ids),
+		stop:        make(chan struct{}),
+	}
+	go h.run()
+	return h, nil
+}
+
+func toSet(ids []string) map[string]bool {
+	set := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
+
+func (h *wsHub) run() {
+	ticker := time.NewTicker(wsPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-h.stop:
+			return
+		case <-ticker.C:
+			if h.clientCount() > 0 {
+				h.poll(context.Background())
+			}
+		}
+	}
+}
+
+// close stops polling and disconnects every client
+func (h *wsHub) close() {
+	h.stopOnce.Do(func() { close(h.stop) })
+	h.closeClients()
+}
+
+// closeClients tells every client to go away, leaving the hub running
+func (h *wsHub) closeClients() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for c := range h.clients {
+		c.close()
+		delete(h.clients, c)
+	}
+}
+
+func (h *wsHub) register(c *wsClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.clients[c] = true
+}
+
+func (h *wsHub) unregister(c *wsClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.clients, c)
+}
+
+func (h *wsHub) clientCount() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.clients)
+}
+
+// poll collects issues changed or deleted since the last poll and delivers
+// them to clients. A nil issue in the change set means it was deleted.
+func (h *wsHub) poll(ctx context.Context) {
+	changedIDs, last, err := h.store.GetChangedIssuesSince(ctx, h.lastEventID)
+	if err != nil {
+		return
+	}
+	ids, err := h.store.GetAllIssueIDs(ctx)
+	if err != nil {
+		return
+	}
+	current := toSet(ids)
+
+	changes := make(map[string]*types.Issue)
+	for id := range h.issueIDs {
+		if !current[id] {
+			changes[id] = nil
+		}
+	}
+	for _, id := range changedIDs {
+		if !current[id] {
+			changes[id] = nil
+			continue
+		}
+		issue, err := loadStreamIssue(ctx, h.store, id)
+		if err != nil {
+			return // retry on the next tick
+		}
+		changes[id] = issue
+	}
+	h.lastEventID = last
+	h.issueIDs = current
+	if len(changes) == 0 {
+		return
+	}
+
+	h.mu.Lock()
+	clients := make([]*wsClient, 0, len(h.clients))
+	for c := range h.clients {
+		clients = append(clients, c)
+	}
+	h.mu.Unlock()
+	for _, c := range clients {
+		c.deliver(changes)
+	}
+}
+
+// loadStreamIssue loads an issue with its labels, the shape clients receive
+func loadStreamIssue(ctx context.Context, store *sqlite.SQLiteStorage, id string) (*types.Issue, error) {
+	issue, err := store.GetIssue(ctx, id)
+	if err != nil || issue == nil {
+		return issue, err
+	}
+	if issue.Labels, err = store.GetLabels(ctx, id); err != nil {
+		return nil, err
+	}
+	return issue, nil
+}
+
+// wsClient is one WebSocket connection and its subscription
+type wsClient struct {
+	hub  *wsHub
+	conn *websocket.Conn
+	send chan *wsServerMessage
+
+	mu         sync.Mutex
+	subscribed bool
+	filter     wsFilter
+	snapshot   map[string]map[string]interface{} // last JSON sent per issue
+	closed     bool
+	done       chan struct{}
+}
+
+func newWSClient(hub *wsHub, conn *websocket.Conn) *wsClient {
+	return &wsClient{
+		hub:      hub,
+		conn:     conn,
+		send:     make(chan *wsServerMessage, wsSendBuffer),
+		snapshot: make(map[string]map[string]interface{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// queue sends msg without blocking; clients that fall too far behind are dropped
+func (c *wsClient) queue(msg *wsServerMessage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return
+	}
+	select {
+	case c.send <- msg:
+	default:
+		c.closeLocked()
+	}
+}
+
+func (c *wsClient) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closeLocked()
+}
+
+func (c *wsClient) closeLocked() {
+	if !c.closed {
+		c.closed = true
+		close(c.done)
+	}
+}
+
+// subscribe replaces the client's filter and sends the matching issues
+func (c *wsClient) subscribe(ctx context.Context, filter wsFilter) error {
+	issues, err := c.hub.store.SearchIssues(ctx, "", types.IssueFilter{LabelsAny: filter.Labels})
+	if err != nil {
+		return err
+	}
+	matched := make([]*types.Issue, 0, len(issues))
+	snapshot := make(map[string]map[string]interface{})
+	for _, issue := range issues {
+		if issue.Labels, err = c.hub.store.GetLabels(ctx, issue.ID); err != nil {
+			return err
+		}
+		if !filter.matches(issue) {
+			continue
+		}
+		doc, err := toJSONDoc(issue)
+		if err != nil {
+			return err
+		}
+		matched = append(matched, issue)
+		snapshot[issue.ID] = doc
+	}
+
+	c.mu.Lock()
+	c.subscribed = true
+	c.filter = filter
+	c.snapshot = snapshot
+	c.mu.Unlock()
+
+	c.queue(&wsServerMessage{Type: "snapshot", Issues: matched})
+	return nil
+}
+
+func (c *wsClient) unsubscribe() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.subscribed = false
+	c.snapshot = make(map[string]map[string]interface{})
+}
+
+// deliver turns a change set into add, patch, and remove messages for this
+// client's filter
+func (c *wsClient) deliver(changes map[string]*types.Issue) {
+	c.mu.Lock()
+	if !c.subscribed {
+		c.mu.Unlock()
+		return
+	}
+	var msgs []*wsServerMessage
+	for id, issue := range changes {
+		prev, known := c.snapshot[id]
+		if issue == nil {
+			if known {
+				delete(c.snapshot, id)
+				msgs = append(msgs, &wsServerMessage{Type: "remove", ID: id, Reason: "deleted"})
+			}
+			continue
+		}
+		if !c.filter.matches(issue) {
+			if known {
+				delete(c.snapshot, id)
+				msgs = append(msgs, &wsServerMessage{Type: "remove", ID: id, Reason: "filtered"})
+			}
+			continue
+		}
+		doc, err := toJSONDoc(issue)
+		if err != nil {
+			continue
+		}
+		c.snapshot[id] = doc
+		if !known {
+			msgs = append(msgs, &wsServerMessage{Type: "add", ID: id, Issue: issue})
+			continue
+		}
+		if patch := diffJSON(prev, doc); len(patch) > 0 {
+			msgs = append(msgs, &wsServerMessage{Type: "patch", ID: id, Patch: patch})
+		}
+	}
+	c.mu.Unlock()
+
+	for _, msg := range msgs {
+		c.queue(msg)
+	}
+}
+
+// readLoop handles client messages until the connection fails
+func (c *wsClient) readLoop(ctx context.Context) {
+	c.conn.SetReadLimit(wsMaxMessage)
+	_ = c.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var msg wsClientMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			c.queue(&wsServerMessage{Type: "error", Error: "invalid message: " + err.Error()})
+			continue
+		}
+		switch msg.Type {
+		case "subscribe":
+			if err := c.subscribe(ctx, msg.Filter); err != nil {
+				c.queue(&wsServerMessage{Type: "error", Error: err.Error()})
+			}
+		case "unsubscribe":
+			c.unsubscribe()
+		default:
+			c.queue(&wsServerMessage{Type: "error", Error: fmt.Sprintf("unknown message type %q", msg.Type)})
+		}
+	}
+}
+
+// writeLoop sends queued messages and keepalive pings
+func (c *wsClient) writeLoop() {
+	ticker := time.NewTicker(wsPingInterval)
+	defer func() {
+		ticker.Stop()
+		_ = c.conn.Close()
+	}()
+	for {
+		select {
+		case msg := <-c.send:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := c.conn.WriteJSON(msg); err != nil {
+				return
+			}
+		case <-ticker.C:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-c.done:
+			_ = c.conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseGoingAway, ""), time.Now().Add(wsWriteWait))
+			return
+		}
+	}
+}
+
+// handleWebSocket handles GET /ws
+func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("websocket streaming requires SQLite backend"))
+		return
+	}
+	hub, err := s.getWSHub(sqliteStore)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return // Upgrade has already written an HTTP error
+	}
+	s.life.streams.Add(1)
+	defer s.life.streams.Done()
+	client := newWSClient(hub, conn)
+	hub.register(client)
+	go client.writeLoop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client.readLoop(ctx)
+	hub.unregister(client)
+	client.close()
+}
+
+// getWSHub starts the hub on first use
+func (s *Server) getWSHub(store *sqlite.SQLiteStorage) (*wsHub, error) {
+	s.wsMu.Lock()
+	defer s.wsMu.Unlock()
+	if s.wsHub == nil {
+		hub, err := newWSHub(store)
+		if err != nil {
+			return nil, err
+		}
+		s.wsHub = hub
+	}
+	return s.wsHub, nil
+}
@@ -0,0 +1,125 @@
+package http
+
+import (
+	"context"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestDiffJSON(t *testing.T) {
+	from := map[string]interface{}{"title": "a", "priority": 1.0, "labels": []interface{}{"x"}, "gone": true, "nested": map[string]interface{}{"k": "v"}}
+	to := map[string]interface{}{"title": "b", "priority": 1.0, "labels": []interface{}{"x", "y"}, "new/key": nil, "nested": map[string]interface{}{"k": "w"}}
+
+	ops := diffJSON(from, to)
+	got := make(map[string]string)
+	for _, op := range ops {
+		got[op.Path] = op.Op
+	}
+	want := map[string]string{"/gone": "remove", "/labels": "replace", "/new~1key": "add", "/nested/k": "replace", "/title": "replace"}
+	if len(got) != len(want) {
+		t.Fatalf("got ops %v, want %v", got, want)
+	}
+	for path, op := range want {
+		if got[path] != op {
+			t.Errorf("path %s: got %q, want %q", path, got[path], op)
+		}
+	}
+}
+
+func TestWebSocketSubscription(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "sekret")
+
+	watched := &types.Issue{Title: "Watched", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	other := &types.Issue{Title: "Other", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	for _, issue := range []*types.Issue{watched, other} {
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.AddLabel(ctx, watched.ID, "backend", "test"); err != nil {
+		t.Fatal(err)
+	}
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(srv.router)
+	defer ts.Close()
+	defer func() { _ = srv.Stop(ctx) }()
+	wsURL := "ws" + strings.TrimPrefix(ts.URL, "http") + "/ws"
+
+	if _, _, err := websocket.DefaultDialer.Dial(wsURL, nil); err == nil {
+		t.Fatal("expected unauthenticated connection to be rejected")
+	}
+
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL+"?token=sekret", nil)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer conn.Close()
+
+	read := func() wsServerMessage {
+		t.Helper()
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		var msg wsServerMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			t.Fatalf("read failed: %v", err)
+		}
+		return msg
+	}
+
+	if err := conn.WriteJSON(wsClientMessage{Type: "subscribe", Filter: wsFilter{Labels: []string{"backend"}}}); err != nil {
+		t.Fatal(err)
+	}
+	snap := read()
+	if snap.Type != "snapshot" || len(snap.Issues) != 1 || snap.Issues[0].ID != watched.ID {
+		t.Fatalf("unexpected snapshot %+v", snap)
+	}
+
+	// Changes to unwatched issues are not streamed; the watched change arrives as a patch
+	if err := store.UpdateIssue(ctx, other.ID, map[string]interface{}{"title": "Ignored"}, "test"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.UpdateIssue(ctx, watched.ID, map[string]interface{}{"priority": 0}, "test"); err != nil {
+		t.Fatal(err)
+	}
+	patch := read()
+	if patch.Type != "patch" || patch.ID != watched.ID {
+		t.Fatalf("unexpected message %+v", patch)
+	}
+	foundPriority := false
+	for _, op := range patch.Patch {
+		if op.Path == "/priority" && op.Op == "replace" {
+			foundPriority = true
+		}
+	}
+	if !foundPriority {
+		t.Errorf("expected priority replace in %+v", patch.Patch)
+	}
+
+	// Labeling the other issue brings it into the stream
+	if err := store.AddLabel(ctx, other.ID, "backend", "test"); err != nil {
+		t.Fatal(err)
+	}
+	add := read()
+	if add.Type != "add" || add.ID != other.ID {
+		t.Fatalf("unexpected message %+v", add)
+	}
+}
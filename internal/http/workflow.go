@@ -0,0 +1,69 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// workflowResponse is the body of GET /workflow. Next is set when the
+// request names a status with ?from=.
+type workflowResponse struct {
+	types.Workflow
+	From types.Status               `json:"from,omitempty"`
+	Next []types.WorkflowTransition `json:"next,omitempty"`
+}
+
+// workflow returns the store's workflow, or the default one for stores
+// without workflows
+func (s *Server) workflow(ctx context.Context) (*types.Workflow, error) {
+	if sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage); ok {
+		return sqliteStore.GetWorkflow(ctx)
+	}
+	return types.DefaultWorkflow(), nil
+}
+
+// handleGetWorkflow handles GET /workflow
+func (s *Server) handleGetWorkflow(w http.ResponseWriter, r *http.Request) {
+	workflow, err := s.workflow(r.Context())
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	resp := workflowResponse{Workflow: *workflow}
+	if from := types.Status(r.URL.Query().Get("from")); from != "" {
+		if !workflow.HasStatus(from) {
+			s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("from: unknown status %q", from))
+			return
+		}
+		resp.From = from
+		resp.Next = workflow.Next(from)
+	}
+
+	s.writeSuccess(w, r, resp, opWorkflow)
+}
+
+// handleSetWorkflow handles PUT /workflow
+func (s *Server) handleSetWorkflow(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("custom workflows require SQLite backend"))
+		return
+	}
+
+	var body types.Workflow
+	if err := s.parseBody(r, &body); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if err := sqliteStore.SetWorkflow(r.Context(), &body); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	s.writeSuccess(w, r, workflowResponse{Workflow: body}, opWorkflow)
+}
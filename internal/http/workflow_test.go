@@ -0,0 +1,89 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestWorkflowAPI(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("X-Actor", "alice")
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do("PUT", "/workflow", `{
+		"statuses": [{"name": "open"}, {"name": "in_progress"}, {"name": "in_review", "category": "in_progress"}, {"name": "blocked"}, {"name": "closed"}],
+		"transitions": [
+			{"from": "open", "to": "in_review"},
+			{"from": "in_review", "to": "closed", "require": ["reason"]}
+		]
+	}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	if rec := do("PUT", "/workflow", `{"statuses": [{"name": "open"}]}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a workflow without the built-in statuses, got %d", rec.Code)
+	}
+
+	rec = do("GET", "/workflow?from=in_review", "")
+	var resp workflowResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Statuses) != 5 || len(resp.Next) != 1 || resp.Next[0].To != types.StatusClosed {
+		t.Errorf("Expected five statuses and one move from in_review, got %s", rec.Body)
+	}
+	if rec := do("GET", "/workflow?from=done", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown status, got %d", rec.Code)
+	}
+
+	rec = do("POST", "/issues", `{"title": "Review me", "priority": 2, "issue_type": "task"}`)
+	var issue types.Issue
+	if err := json.Unmarshal(rec.Body.Bytes(), &issue); err != nil {
+		t.Fatal(err)
+	}
+	if rec := do("PATCH", "/issues/"+issue.ID, `{"status": "blocked"}`); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 for a move the workflow doesn't allow, got %d: %s", rec.Code, rec.Body)
+	}
+	if rec := do("PATCH", "/issues/"+issue.ID, `{"status": "in_review"}`); rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	if rec := do("POST", "/issues/"+issue.ID+"/close", `{}`); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 closing without a reason, got %d", rec.Code)
+	}
+
+	// Custom statuses can be listed by
+	rec = do("GET", "/issues?status=in_review", "")
+	var issues []*types.Issue
+	if err := json.Unmarshal(rec.Body.Bytes(), &issues); err != nil || len(issues) != 1 {
+		t.Errorf("Expected one issue in review, got %d: %s", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,73 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// workLogRequest is the body of POST /issues/{id}/worklogs
+type workLogRequest struct {
+	Minutes  int       `json:"minutes" doc:"Time spent, in minutes"`
+	Note     string    `json:"note,omitempty"`
+	LoggedAt time.Time `json:"logged_at,omitempty" doc:"When the work was done; RFC 3339, default now"`
+}
+
+// handleListWorkLogs handles GET /issues/{id}/worklogs
+func (s *Server) handleListWorkLogs(w http.ResponseWriter, r *http.Request) {
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("work logs require SQLite backend"))
+		return
+	}
+
+	logs, err := sqliteStore.GetWorkLogs(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if logs == nil {
+		logs = []*sqlite.WorkLog{}
+	}
+
+	s.writeSuccess(w, r, logs, opWorkLogs)
+}
+
+// handleAddWorkLog handles POST /issues/{id}/worklogs
+func (s *Server) handleAddWorkLog(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		s.writeError(w, r, http.StatusNotImplemented, fmt.Errorf("work logs require SQLite backend"))
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	issue, err := sqliteStore.GetIssue(ctx, id)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if issue == nil {
+		s.writeError(w, r, http.StatusNotFound, types.NotFound("issue", id))
+		return
+	}
+
+	var body workLogRequest
+	if err := s.parseBody(r, &body); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	log := &sqlite.WorkLog{IssueID: id, Actor: s.getActor(r), Minutes: body.Minutes, Note: body.Note, LoggedAt: body.LoggedAt}
+	if err := sqliteStore.AddWorkLog(ctx, log); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	s.writeSuccess(w, r, []*sqlite.WorkLog{log}, opWorkLogs)
+}
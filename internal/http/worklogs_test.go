@@ -0,0 +1,77 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestWorkLogs(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_API_SECRET", "")
+
+	srv, err := NewServer(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	do := func(method, path, body, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Accept", accept)
+		req.Header.Set("X-Actor", "alice")
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	estimate := 120
+	issue := &types.Issue{Title: "Fix login", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeBug, Assignee: "alice", EstimatedMinutes: &estimate}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := do("POST", "/issues/"+issue.ID+"/worklogs", `{"minutes": 45, "note": "debugging"}`, "application/json")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var logs []*sqlite.WorkLog
+	if err := json.Unmarshal(rec.Body.Bytes(), &logs); err != nil {
+		t.Fatalf("failed to decode %s: %v", rec.Body, err)
+	}
+	if len(logs) != 1 || logs[0].Actor != "alice" || logs[0].Minutes != 45 {
+		t.Errorf("Unexpected work log %s", rec.Body)
+	}
+
+	if rec = do("POST", "/issues/"+issue.ID+"/worklogs", `{"minutes": -5}`, "application/json"); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for negative minutes, got %d", rec.Code)
+	}
+	if rec = do("POST", "/issues/bd-999/worklogs", `{"minutes": 5}`, "application/json"); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a missing issue, got %d", rec.Code)
+	}
+
+	text := do("GET", "/issues/"+issue.ID+"/worklogs", "", "text/plain").Body.String()
+	if !strings.Contains(text, "Time logged on "+issue.ID+": 45m") || !strings.Contains(text, "alice: 45m (debugging)") {
+		t.Errorf("Unexpected work log text:\n%s", text)
+	}
+
+	text = do("GET", "/issues/stats", "", "text/plain").Body.String()
+	for _, want := range []string{"Time Logged: 45m", "alice", issue.ID + " Fix login: 45m logged of 2h 0m estimated"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in stats:\n%s", want, text)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/imalsogreg/beads/internal/apikey"
+	"github.com/imalsogreg/beads/internal/storage"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// defaultWorkspace names the database the server was created with, which
+// also answers at the top level without a /w/ prefix
+const defaultWorkspace = "default"
+
+// workspaceNamePattern matches the names workspaces can be mounted under.
+// Names are lowercase since config keys are.
+var workspaceNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// workspaceListRoutes check the caller's token against every workspace
+// themselves, so auth doesn't hold them to the default workspace's keys
+var workspaceListRoutes = map[string]bool{
+	"/workspaces":        true,
+	"/workspaces/issues": true,
+}
+
+// WorkspaceInfo is one entry of GET /workspaces
+type WorkspaceInfo struct {
+	Name       string            `json:"name"`
+	Path       string            `json:"path"` // URL prefix of the workspace's API
+	Statistics *types.Statistics `json:"statistics,omitempty"`
+}
+
+// workspaceIssue is an issue from GET /workspaces/issues, labeled with the
+// workspace it came from
+type workspaceIssue struct {
+	Workspace string `json:"workspace"`
+	*types.Issue
+}
+
+// AddWorkspace serves another project's database under /w/{name}/, with
+// the same API as the top level. Each workspace checks tokens against its
+// own API keys, and runs its own webhooks, digests, epic autoclose,
+// compaction, and backups.
+// BEADS_API_SECRET, OIDC, rate limits, the request log, read-only mode,
+// remotes, backup storage, and draining are shared, and each workspace gets
+// a query cache like the server's, so call AddWorkspace after the other
+// Enable methods and before Start.
+func (s *Server) AddWorkspace(name string, store storage.Storage) error {
+	if !workspaceNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid workspace name %q: use lowercase letters, digits, - and _", name)
+	}
+	if name == defaultWorkspace {
+		return fmt.Errorf("workspace name %q is reserved for the server's own database", name)
+	}
+	if _, exists := s.workspaces[name]; exists {
+		return fmt.Errorf("workspace %q already exists", name)
+	}
+
+	ws, err := NewServer(store, "")
+	if err != nil {
+		return err
+	}
+	ws.parent = s
+	ws.workspace = name
+	ws.compactInterval = s.compactInterval
+	ws.readOnly = s.readOnly
+	ws.remotes = s.remotes
+	ws.backupUploader = s.backupUploader
+	ws.backupInterval, ws.backupRetain = s.backupInterval, s.backupRetain
+	ws.limiter = s.limiter
+	if s.cache != nil {
+		ws.cache = newQueryCache(s.cache.ttl)
+	}
+	ws.oidc, ws.oidcRole = s.oidc, s.oidcRole
+	ws.requestLog = s.requestLog
+	ws.metrics, ws.startTime = s.metrics, s.startTime
+
+	if s.workspaces == nil {
+		s.workspaces = make(map[string]*Server)
+	}
+	s.workspaces[name] = ws
+	return nil
+}
+
+// workspaceNames returns the added workspaces' names in order
+func (s *Server) workspaceNames() []string {
+	names := make([]string, 0, len(s.workspaces))
+	for name := range s.workspaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// routeWorkspaces sends requests under /w/{name}/ to that workspace with
+// the prefix stripped, and everything else to next
+func (s *Server) routeWorkspaces(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rest, ok := strings.CutPrefix(r.URL.Path, "/w/")
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		name, path, _ := strings.Cut(rest, "/")
+		handler := next
+		if name != defaultWorkspace {
+			ws, exists := s.workspaces[name]
+			if !exists {
+				s.writeError(w, r, http.StatusNotFound, fmt.Errorf("no workspace named %q", name))
+				return
+			}
+			handler = ws.router
+		}
+
+		r2 := r.Clone(r.Context())
+		r2.URL.Path = "/" + path
+		r2.URL.RawPath = ""
+		handler.ServeHTTP(w, r2)
+	})
+}
+
+// visibleWorkspaces returns the workspaces, the default first, whose
+// credentials accept r's token for reading
+func (s *Server) visibleWorkspaces(r *http.Request) []*Server {
+	root := s
+	if s.parent != nil {
+		root = s.parent
+	}
+	var visible []*Server
+	for _, ws := range append([]*Server{root}, root.workspaceList()...) {
+		if p, ok := ws.authenticate(discardResponse{}, r); ok && p.Role.Allows(apikey.RoleReader) {
+			visible = append(visible, ws)
+		}
+	}
+	return visible
+}
+
+// workspaceList returns the added workspaces in name order
+func (s *Server) workspaceList() []*Server {
+	list := make([]*Server, 0, len(s.workspaces))
+	for _, name := range s.workspaceNames() {
+		list = append(list, s.workspaces[name])
+	}
+	return list
+}
+
+// workspaceName returns the name s is served under
+func (s *Server) workspaceName() string {
+	if s.workspace == "" {
+		return defaultWorkspace
+	}
+	return s.workspace
+}
+
+// handleListWorkspaces handles GET /workspaces
+func (s *Server) handleListWorkspaces(w http.ResponseWriter, r *http.Request) {
+	visible := s.visibleWorkspaces(r)
+	if len(visible) == 0 {
+		s.writeAuthError(w, r, "Token is not valid for any workspace")
+		return
+	}
+	infos := make([]WorkspaceInfo, 0, len(visible))
+	for _, ws := range visible {
+		info := WorkspaceInfo{Name: ws.workspaceName(), Path: "/w/" + ws.workspaceName()}
+		stats, err := ws.storage.GetStatistics(r.Context())
+		if err != nil {
+			s.writeError(w, r, http.StatusInternalServerError, fmt.Errorf("workspace %s: %w", info.Name, err))
+			return
+		}
+		info.Statistics = stats
+		infos = append(infos, info)
+	}
+	s.writeSuccess(w, r, infos, opWorkspaces)
+}
+
+// handleListWorkspaceIssues handles GET /workspaces/issues, which lists
+// issues from every workspace the caller can read
+func (s *Server) handleListWorkspaceIssues(w http.ResponseWriter, r *http.Request) {
+	filter, err := issueFilterFromQuery(r.URL.Query(), nil)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	visible := s.visibleWorkspaces(r)
+	if len(visible) == 0 {
+		s.writeAuthError(w, r, "Token is not valid for any workspace")
+		return
+	}
+
+	issues := []workspaceIssue{}
+	for _, ws := range visible {
+		found, err := ws.storage.SearchIssues(r.Context(), "", filter)
+		if err != nil {
+			s.writeError(w, r, http.StatusInternalServerError, fmt.Errorf("workspace %s: %w", ws.workspaceName(), err))
+			return
+		}
+		for _, issue := range found {
+			issues = append(issues, workspaceIssue{Workspace: ws.workspaceName(), Issue: issue})
+		}
+	}
+	// The limit applies to each workspace's search, and again to the whole
+	if filter.Limit > 0 && len(issues) > filter.Limit {
+		issues = issues[:filter.Limit]
+	}
+	s.writeSuccess(w, r, issues, opWorkspaceIssues)
+}
+
+// discardResponse swallows the error responses of auth checks made on a
+// caller's behalf
+type discardResponse struct{}
+
+func (discardResponse) Header() http.Header         { return http.Header{} }
+func (discardResponse) Write(p []byte) (int, error) { return len(p), nil }
+func (discardResponse) WriteHeader(int)             {}
@@ -0,0 +1,114 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/apikey"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestWorkspaces(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("BEADS_API_SECRET", "")
+
+	// Each workspace has one issue and its own writer key
+	newStore := func(name string) *sqlite.SQLiteStorage {
+		store, err := sqlite.New(filepath.Join(t.TempDir(), name+".db"))
+		if err != nil {
+			t.Fatalf("failed to create store: %v", err)
+		}
+		t.Cleanup(func() { store.Close() })
+		if err := store.SetConfig(ctx, "issue_prefix", name); err != nil {
+			t.Fatal(err)
+		}
+		issue := &types.Issue{Title: name + " issue", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.CreateAPIKey(ctx, &sqlite.APIKey{Name: name, Role: string(apikey.RoleWriter)}, apikey.Hash(name+"-token")); err != nil {
+			t.Fatal(err)
+		}
+		return store
+	}
+	primary, other := newStore("primary"), newStore("other")
+
+	srv, err := NewServer(primary, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.AddWorkspace("other", other); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"other", "default", "Bad Name"} {
+		if err := srv.AddWorkspace(name, other); err == nil {
+			t.Errorf("Expected AddWorkspace(%q) to fail", name)
+		}
+	}
+
+	do := func(method, path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		srv.httpServer.Handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Keys only open their own workspace
+	rec := do("GET", "/w/other/issues", "other-token", "")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "other issue") || strings.Contains(rec.Body.String(), "primary issue") {
+		t.Errorf("GET /w/other/issues: %d %s", rec.Code, rec.Body)
+	}
+	if rec := do("GET", "/w/other/issues", "primary-token", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected the primary key to be refused by the other workspace, got %d", rec.Code)
+	}
+	if rec := do("GET", "/issues", "other-token", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected the other key to be refused at the top level, got %d", rec.Code)
+	}
+	if rec := do("GET", "/w/default/issues", "primary-token", ""); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "primary issue") {
+		t.Errorf("GET /w/default/issues: %d %s", rec.Code, rec.Body)
+	}
+	if rec := do("GET", "/w/missing/issues", "primary-token", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown workspace, got %d", rec.Code)
+	}
+
+	// Writes land in the workspace's own database
+	if rec := do("POST", "/w/other/issues", "other-token", `{"title": "Created remotely", "priority": 1, "issue_type": "task"}`); rec.Code != http.StatusOK {
+		t.Fatalf("POST /w/other/issues: %d %s", rec.Code, rec.Body)
+	}
+	if issues, _ := other.SearchIssues(ctx, "Created remotely", types.IssueFilter{}); len(issues) != 1 {
+		t.Errorf("Expected the new issue in the other database, got %d", len(issues))
+	}
+
+	// Listings cover just the workspaces the token opens
+	var infos []WorkspaceInfo
+	rec = do("GET", "/workspaces", "other-token", "")
+	if err := json.Unmarshal(rec.Body.Bytes(), &infos); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("GET /workspaces: %d %s", rec.Code, rec.Body)
+	}
+	if len(infos) != 1 || infos[0].Name != "other" || infos[0].Path != "/w/other" || infos[0].Statistics.OpenIssues != 2 {
+		t.Errorf("Unexpected workspaces: %+v", infos)
+	}
+
+	var issues []workspaceIssue
+	rec = do("GET", "/workspaces/issues?status=open", "primary-token", "")
+	if err := json.Unmarshal(rec.Body.Bytes(), &issues); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("GET /workspaces/issues: %d %s", rec.Code, rec.Body)
+	}
+	if len(issues) != 1 || issues[0].Workspace != "default" || issues[0].Title != "primary issue" {
+		t.Errorf("Unexpected issues: %+v", issues)
+	}
+	if rec := do("GET", "/workspaces/issues", "", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without a token, got %d", rec.Code)
+	}
+}
@@ -56,6 +56,10 @@ type Result struct {
 // - issues: Parsed issues from JSONL
 // - opts: Import options
 func ImportIssues(ctx context.Context, dbPath string, store storage.Storage, issues []*types.Issue, opts Options) (*Result, error) {
+	// Imported issues were triaged, checked against the workflow, and
+	// given the fields their type requires where they were written
+	ctx = sqlite.ForImport(ctx)
+
 	result := &Result{
 		IDMapping:        make(map[string]string),
 		MismatchPrefixes: make(map[string]int),
@@ -263,28 +267,7 @@ func upsertIssues(ctx context.Context, sqliteStore *sqlite.SQLiteStorage, issues
 				continue
 			}
 
-			// Build updates map
-			updates := make(map[string]interface{})
-			updates["title"] = issue.Title
-			updates["description"] = issue.Description
-			updates["status"] = issue.Status
-			updates["priority"] = issue.Priority
-			updates["issue_type"] = issue.IssueType
-			updates["design"] = issue.Design
-			updates["acceptance_criteria"] = issue.AcceptanceCriteria
-			updates["notes"] = issue.Notes
-
-			if issue.Assignee != "" {
-				updates["assignee"] = issue.Assignee
-			} else {
-				updates["assignee"] = nil
-			}
-
-			if issue.ExternalRef != nil && *issue.ExternalRef != "" {
-				updates["external_ref"] = *issue.ExternalRef
-			} else {
-				updates["external_ref"] = nil
-			}
+			updates := issueUpdates(issue)
 
 			// Only update if data actually changed
 			if IssueDataChanged(existing, updates) {
@@ -325,6 +308,40 @@ func upsertIssues(ctx context.Context, sqliteStore *sqlite.SQLiteStorage, issues
 	return nil
 }
 
+// issueUpdates builds the UpdateIssue map that makes an existing issue match issue
+func issueUpdates(issue *types.Issue) map[string]interface{} {
+	updates := make(map[string]interface{})
+	updates["title"] = issue.Title
+	updates["description"] = issue.Description
+	updates["status"] = issue.Status
+	updates["priority"] = issue.Priority
+	updates["issue_type"] = issue.IssueType
+	updates["design"] = issue.Design
+	updates["acceptance_criteria"] = issue.AcceptanceCriteria
+	updates["notes"] = issue.Notes
+
+	if issue.Assignee != "" {
+		updates["assignee"] = issue.Assignee
+	} else {
+		updates["assignee"] = nil
+	}
+
+	if issue.ExternalRef != nil && *issue.ExternalRef != "" {
+		updates["external_ref"] = *issue.ExternalRef
+	} else {
+		updates["external_ref"] = nil
+	}
+	updates["due_date"] = issue.DueDate
+	updates["start_date"] = issue.StartDate
+
+	if issue.Milestone != "" {
+		updates["milestone"] = issue.Milestone
+	} else {
+		updates["milestone"] = nil
+	}
+	return updates
+}
+
 // importDependencies imports dependency relationships
 func importDependencies(ctx context.Context, sqliteStore *sqlite.SQLiteStorage, issues []*types.Issue, opts Options) error {
 	for _, issue := range issues {
@@ -413,29 +430,97 @@ func importComments(ctx context.Context, sqliteStore *sqlite.SQLiteStorage, issu
 		}
 
 		// Build a set of existing comments (by author+normalized text)
-		existingComments := make(map[string]bool)
+		existingComments := make(map[string]int64)
 		for _, c := range currentComments {
 			key := fmt.Sprintf("%s:%s", c.Author, strings.TrimSpace(c.Text))
-			existingComments[key] = true
+			existingComments[key] = c.ID
 		}
 
-		// Add missing comments
+		// Add missing comments, threading replies onto the local copies of
+		// the comments they answer, whose IDs differ from the exporter's
+		localIDs := make(map[int64]int64)
 		for _, comment := range issue.Comments {
 			key := fmt.Sprintf("%s:%s", comment.Author, strings.TrimSpace(comment.Text))
-			if !existingComments[key] {
-				if _, err := sqliteStore.AddIssueComment(ctx, issue.ID, comment.Author, comment.Text); err != nil {
-					if opts.Strict {
-						return fmt.Errorf("error adding comment to %s: %w", issue.ID, err)
-					}
-					continue
+			if id, ok := existingComments[key]; ok {
+				localIDs[comment.ID] = id
+				continue
+			}
+			var replyTo int64
+			threaded := false
+			if comment.ReplyTo != nil {
+				replyTo, threaded = localIDs[*comment.ReplyTo]
+			}
+			var added *types.Comment
+			var err error
+			if threaded {
+				added, err = sqliteStore.ReplyToComment(ctx, issue.ID, replyTo, comment.Author, comment.Text)
+			} else {
+				added, err = sqliteStore.AddIssueComment(ctx, issue.ID, comment.Author, comment.Text)
+			}
+			if err != nil {
+				if opts.Strict {
+					return fmt.Errorf("error adding comment to %s: %w", issue.ID, err)
 				}
+				continue
 			}
+			existingComments[key] = added.ID
+			localIDs[comment.ID] = added.ID
 		}
 	}
 
 	return nil
 }
 
+// ReplaceRelations makes the labels and dependencies of existing issues match
+// issues exactly, removing any that aren't listed, and adds missing comments
+// (comments are never removed). It's for when issues are the source of truth
+// rather than additions, as when applying a committed JSONL file.
+func ReplaceRelations(ctx context.Context, sqliteStore *sqlite.SQLiteStorage, issues []*types.Issue, opts Options) error {
+	for _, issue := range issues {
+		keepLabels := make(map[string]bool)
+		for _, label := range issue.Labels {
+			keepLabels[label] = true
+		}
+		currentLabels, err := sqliteStore.GetLabels(ctx, issue.ID)
+		if err != nil {
+			return fmt.Errorf("error getting labels for %s: %w", issue.ID, err)
+		}
+		for _, label := range currentLabels {
+			if keepLabels[label] {
+				continue
+			}
+			if err := sqliteStore.RemoveLabel(ctx, issue.ID, label, "import"); err != nil {
+				return fmt.Errorf("error removing label %s from %s: %w", label, issue.ID, err)
+			}
+		}
+
+		keepDeps := make(map[string]bool)
+		for _, dep := range issue.Dependencies {
+			keepDeps[fmt.Sprintf("%s|%s", dep.DependsOnID, dep.Type)] = true
+		}
+		currentDeps, err := sqliteStore.GetDependencyRecords(ctx, issue.ID)
+		if err != nil {
+			return fmt.Errorf("error checking dependencies for %s: %w", issue.ID, err)
+		}
+		for _, dep := range currentDeps {
+			if keepDeps[fmt.Sprintf("%s|%s", dep.DependsOnID, dep.Type)] {
+				continue
+			}
+			if err := sqliteStore.RemoveDependency(ctx, issue.ID, dep.DependsOnID, "import"); err != nil {
+				return fmt.Errorf("error removing dependency %s → %s: %w", issue.ID, dep.DependsOnID, err)
+			}
+		}
+	}
+
+	if err := importDependencies(ctx, sqliteStore, issues, opts); err != nil {
+		return err
+	}
+	if err := importLabels(ctx, sqliteStore, issues, opts); err != nil {
+		return err
+	}
+	return importComments(ctx, sqliteStore, issues, opts)
+}
+
 // Helper functions
 
 func GetPrefixList(prefixes map[string]int) []string {
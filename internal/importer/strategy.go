@@ -0,0 +1,170 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// Strategy decides what happens when an imported issue already exists
+type Strategy string
+
+// Merge strategies
+const (
+	StrategySkipExisting Strategy = "skip-existing" // keep the database version
+	StrategyOverwrite    Strategy = "overwrite"     // replace with the imported version
+	StrategyMergeNewer   Strategy = "merge-newer"   // replace only if the imported updated_at is later
+)
+
+// ParseStrategy validates a strategy name. An empty string means StrategySkipExisting.
+func ParseStrategy(s string) (Strategy, error) {
+	switch st := Strategy(s); st {
+	case "":
+		return StrategySkipExisting, nil
+	case StrategySkipExisting, StrategyOverwrite, StrategyMergeNewer:
+		return st, nil
+	default:
+		return "", fmt.Errorf("invalid strategy %q (must be skip-existing, overwrite, or merge-newer)", s)
+	}
+}
+
+// Change actions reported by MergeIssues
+const (
+	ActionCreate    = "create"
+	ActionUpdate    = "update"
+	ActionSkip      = "skip"
+	ActionUnchanged = "unchanged"
+)
+
+// Change describes what a merge does (or would do) to one imported issue
+type Change struct {
+	ID     string   `json:"id"`
+	Action string   `json:"action"`
+	Fields []string `json:"fields,omitempty"` // fields that differ, for updates and skips
+	Reason string   `json:"reason,omitempty"`
+}
+
+// MergeResult summarizes a strategy-based import
+type MergeResult struct {
+	Strategy  Strategy  `json:"strategy"`
+	DryRun    bool      `json:"dry_run"`
+	Created   int       `json:"created"`
+	Updated   int       `json:"updated"`
+	Unchanged int       `json:"unchanged"`
+	Skipped   int       `json:"skipped"`
+	Changes   []*Change `json:"changes"`
+}
+
+// MergeIssues upserts issues using strategy to resolve issues that already
+// exist. Unlike ImportIssues it never remaps IDs: an existing ID is always
+// treated as the same issue. Labels, dependencies, and comments are merged for
+// created and updated issues. With opts.DryRun nothing is written and the
+// result reports what would change.
+func MergeIssues(ctx context.Context, store *sqlite.SQLiteStorage, issues []*types.Issue, strategy Strategy, opts Options) (*MergeResult, error) {
+	// Imported issues were triaged, checked against the workflow, and
+	// given the fields their type requires where they were written
+	ctx = sqlite.ForImport(ctx)
+
+	result := &MergeResult{Strategy: strategy, DryRun: opts.DryRun, Changes: []*Change{}}
+
+	prefixResult := &Result{MismatchPrefixes: make(map[string]int)}
+	if err := handlePrefixMismatch(ctx, store, issues, opts, prefixResult); err != nil {
+		return nil, err
+	}
+
+	workflow, err := store.GetWorkflow(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var creates, updates []*types.Issue
+	updateMaps := make(map[string]map[string]interface{})
+	seen := make(map[string]bool)
+	for _, issue := range issues {
+		if seen[issue.ID] {
+			if opts.Strict {
+				return nil, fmt.Errorf("duplicate issue ID %s in import", issue.ID)
+			}
+			result.Changes = append(result.Changes, &Change{ID: issue.ID, Action: ActionSkip, Reason: "duplicate in payload"})
+			result.Skipped++
+			continue
+		}
+		seen[issue.ID] = true
+
+		if err := issue.ValidateFor(workflow); err != nil {
+			if opts.Strict {
+				return nil, fmt.Errorf("invalid issue %s: %w", issue.ID, err)
+			}
+			result.Changes = append(result.Changes, &Change{ID: issue.ID, Action: ActionSkip, Reason: err.Error()})
+			result.Skipped++
+			continue
+		}
+
+		existing, err := store.GetIssue(ctx, issue.ID)
+		if err != nil {
+			return nil, fmt.Errorf("error checking issue %s: %w", issue.ID, err)
+		}
+		if existing == nil {
+			result.Changes = append(result.Changes, &Change{ID: issue.ID, Action: ActionCreate})
+			creates = append(creates, issue)
+			continue
+		}
+
+		upd := issueUpdates(issue)
+		fields := ChangedFields(existing, upd)
+		change := &Change{ID: issue.ID, Fields: fields}
+		result.Changes = append(result.Changes, change)
+		switch {
+		case len(fields) == 0:
+			change.Action = ActionUnchanged
+			result.Unchanged++
+		case strategy == StrategySkipExisting:
+			change.Action = ActionSkip
+			change.Reason = "issue exists"
+			result.Skipped++
+		case strategy == StrategyMergeNewer && !issue.UpdatedAt.After(existing.UpdatedAt):
+			change.Action = ActionSkip
+			change.Reason = "database version is newer"
+			result.Skipped++
+		default:
+			change.Action = ActionUpdate
+			updates = append(updates, issue)
+			updateMaps[issue.ID] = upd
+		}
+	}
+	result.Created = len(creates)
+	result.Updated = len(updates)
+
+	if opts.DryRun {
+		return result, nil
+	}
+
+	if len(creates) > 0 {
+		if err := store.CreateIssues(ctx, creates, "import"); err != nil {
+			return nil, fmt.Errorf("error creating issues: %w", err)
+		}
+		if err := store.SyncAllCounters(ctx); err != nil {
+			return nil, fmt.Errorf("error syncing counters: %w", err)
+		}
+	}
+	for _, issue := range updates {
+		if err := store.UpdateIssue(ctx, issue.ID, updateMaps[issue.ID], "import"); err != nil {
+			return nil, fmt.Errorf("error updating issue %s: %w", issue.ID, err)
+		}
+	}
+
+	// Only issues that were written bring their relationships along
+	applied := append(append([]*types.Issue{}, creates...), updates...)
+	if err := importDependencies(ctx, store, applied, opts); err != nil {
+		return nil, err
+	}
+	if err := importLabels(ctx, store, applied, opts); err != nil {
+		return nil, err
+	}
+	if err := importComments(ctx, store, applied, opts); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,129 @@
+package importer
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func newStrategyTestStore(t *testing.T) *sqlite.SQLiteStorage {
+	t.Helper()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	if err := store.SetConfig(context.Background(), "issue_prefix", "bd"); err != nil {
+		t.Fatalf("failed to set prefix: %v", err)
+	}
+	return store
+}
+
+func TestParseStrategy(t *testing.T) {
+	if st, err := ParseStrategy(""); err != nil || st != StrategySkipExisting {
+		t.Errorf("empty strategy should default to skip-existing, got %q, %v", st, err)
+	}
+	for _, s := range []string{"skip-existing", "overwrite", "merge-newer"} {
+		if _, err := ParseStrategy(s); err != nil {
+			t.Errorf("ParseStrategy(%q) failed: %v", s, err)
+		}
+	}
+	if _, err := ParseStrategy("clobber"); err == nil {
+		t.Error("expected invalid strategy to fail")
+	}
+}
+
+func TestMergeIssuesStrategies(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		strategy  Strategy
+		incoming  time.Duration // incoming updated_at relative to the stored issue
+		wantTitle string
+		wantCount func(r *MergeResult) int
+	}{
+		{StrategySkipExisting, time.Hour, "Original", func(r *MergeResult) int { return r.Skipped }},
+		{StrategyOverwrite, -time.Hour, "Imported", func(r *MergeResult) int { return r.Updated }},
+		{StrategyMergeNewer, time.Hour, "Imported", func(r *MergeResult) int { return r.Updated }},
+		{StrategyMergeNewer, -time.Hour, "Original", func(r *MergeResult) int { return r.Skipped }},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.strategy)+"/"+tt.incoming.String(), func(t *testing.T) {
+			store := newStrategyTestStore(t)
+			existing := &types.Issue{Title: "Original", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+			if err := store.CreateIssue(ctx, existing, "test"); err != nil {
+				t.Fatalf("CreateIssue failed: %v", err)
+			}
+			stored, _ := store.GetIssue(ctx, existing.ID)
+
+			incoming := []*types.Issue{
+				{ID: existing.ID, Title: "Imported", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask,
+					UpdatedAt: stored.UpdatedAt.Add(tt.incoming), Labels: []string{"imported"}},
+				{ID: "bd-50", Title: "New", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+			}
+
+			// Dry run reports without writing
+			preview, err := MergeIssues(ctx, store, incoming, tt.strategy, Options{DryRun: true})
+			if err != nil {
+				t.Fatalf("dry run failed: %v", err)
+			}
+			if preview.Created != 1 || tt.wantCount(preview) != 1 {
+				t.Errorf("unexpected preview %+v", preview)
+			}
+			if got, _ := store.GetIssue(ctx, "bd-50"); got != nil {
+				t.Fatal("dry run created an issue")
+			}
+
+			result, err := MergeIssues(ctx, store, incoming, tt.strategy, Options{})
+			if err != nil {
+				t.Fatalf("MergeIssues failed: %v", err)
+			}
+			if result.Created != 1 || tt.wantCount(result) != 1 {
+				t.Errorf("unexpected result %+v", result)
+			}
+
+			got, _ := store.GetIssue(ctx, existing.ID)
+			if got.Title != tt.wantTitle {
+				t.Errorf("title = %q, want %q", got.Title, tt.wantTitle)
+			}
+			labels, _ := store.GetLabels(ctx, existing.ID)
+			if updated := tt.wantTitle == "Imported"; updated != (len(labels) == 1) {
+				t.Errorf("labels should be imported only with the issue, got %v", labels)
+			}
+			if created, _ := store.GetIssue(ctx, "bd-50"); created == nil {
+				t.Error("expected new issue to be created")
+			}
+		})
+	}
+}
+
+func TestMergeIssuesUnchangedAndDuplicates(t *testing.T) {
+	ctx := context.Background()
+	store := newStrategyTestStore(t)
+	existing := &types.Issue{Title: "Same", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, existing, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	incoming := []*types.Issue{
+		{ID: existing.ID, Title: "Same", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+		{ID: "bd-7", Title: "New", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+		{ID: "bd-7", Title: "New again", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+		{ID: "bd-8", Title: "", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+	}
+	result, err := MergeIssues(ctx, store, incoming, StrategyOverwrite, Options{})
+	if err != nil {
+		t.Fatalf("MergeIssues failed: %v", err)
+	}
+	if result.Unchanged != 1 || result.Created != 1 || result.Skipped != 2 {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	if _, err := MergeIssues(ctx, store, incoming, StrategyOverwrite, Options{Strict: true}); err == nil {
+		t.Error("strict mode should fail on duplicate IDs")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/imalsogreg/beads/internal/types"
 	"github.com/imalsogreg/beads/internal/utils"
@@ -20,6 +21,19 @@ func IssueDataChanged(existing *types.Issue, updates map[string]interface{}) boo
 	return false
 }
 
+// ChangedFields lists the update keys whose values differ from the database version
+func ChangedFields(existing *types.Issue, updates map[string]interface{}) []string {
+	fc := newFieldComparator()
+	var fields []string
+	for key, newVal := range updates {
+		if fc.checkFieldChanged(key, existing, newVal) {
+			fields = append(fields, key)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
 // fieldComparator handles comparison logic for different field types
 type fieldComparator struct {
 	strFrom func(v interface{}) (string, bool)
@@ -85,6 +99,14 @@ func (fc *fieldComparator) equalPtrStr(existing *string, newVal interface{}) boo
 	return *existing == s
 }
 
+func (fc *fieldComparator) equalTime(existing *time.Time, newVal interface{}) bool {
+	t, _ := newVal.(*time.Time)
+	if existing == nil || t == nil {
+		return existing == nil && t == nil
+	}
+	return existing.Equal(*t)
+}
+
 func (fc *fieldComparator) equalStatus(existing types.Status, newVal interface{}) bool {
 	switch t := newVal.(type) {
 	case types.Status:
@@ -134,6 +156,12 @@ func (fc *fieldComparator) checkFieldChanged(key string, existing *types.Issue,
 		return !fc.equalStr(existing.Assignee, newVal)
 	case "external_ref":
 		return !fc.equalPtrStr(existing.ExternalRef, newVal)
+	case "due_date":
+		return !fc.equalTime(existing.DueDate, newVal)
+	case "start_date":
+		return !fc.equalTime(existing.StartDate, newVal)
+	case "milestone":
+		return !fc.equalStr(existing.Milestone, newVal)
 	default:
 		return false
 	}
@@ -0,0 +1,181 @@
+// Package github syncs beads issues with GitHub Issues.
+//
+// Titles, descriptions, labels, the assignee, and open/closed state are
+// mirrored in both directions. Local issues are linked to GitHub issues
+// through the external_mappings table, and each sync only fetches GitHub
+// issues updated since the previous one.
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the public GitHub REST API
+const DefaultBaseURL = "https://api.github.com"
+
+// Issue is the subset of a GitHub issue that sync uses
+type Issue struct {
+	Number      int             `json:"number"`
+	Title       string          `json:"title"`
+	Body        string          `json:"body"`
+	State       string          `json:"state"` // "open" or "closed"
+	Labels      []Label         `json:"labels"`
+	Assignees   []User          `json:"assignees"`
+	HTMLURL     string          `json:"html_url"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	PullRequest json.RawMessage `json:"pull_request,omitempty"` // set when the issue is a PR
+}
+
+// Label is a GitHub issue label
+type Label struct {
+	Name string `json:"name"`
+}
+
+// User is a GitHub account
+type User struct {
+	Login string `json:"login"`
+}
+
+// IssueRequest is the body of an issue create or update call
+type IssueRequest struct {
+	Title     string   `json:"title"`
+	Body      string   `json:"body"`
+	State     string   `json:"state,omitempty"`
+	Labels    []string `json:"labels"`
+	Assignees []string `json:"assignees"`
+}
+
+// Client calls the GitHub REST API for one repository
+type Client struct {
+	BaseURL string // defaults to DefaultBaseURL
+	Repo    string // "owner/name"
+	Token   string
+	HTTP    *http.Client
+}
+
+// NewClient returns a client for repo, which must be "owner/name"
+func NewClient(repo, token string) (*Client, error) {
+	parts := strings.Split(repo, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid GitHub repository %q: expected owner/name", repo)
+	}
+	return &Client{
+		BaseURL: DefaultBaseURL,
+		Repo:    repo,
+		Token:   token,
+		HTTP:    &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// ListIssues returns every issue in the repository updated at or after since
+// (all issues if since is zero), oldest update first. Pull requests are
+// skipped.
+func (c *Client) ListIssues(ctx context.Context, since time.Time) ([]*Issue, error) {
+	query := url.Values{}
+	query.Set("state", "all")
+	query.Set("sort", "updated")
+	query.Set("direction", "asc")
+	query.Set("per_page", "100")
+	if !since.IsZero() {
+		query.Set("since", since.UTC().Format(time.RFC3339))
+	}
+
+	var issues []*Issue
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+		var batch []*Issue
+		if err := c.do(ctx, http.MethodGet, "/issues?"+query.Encode(), nil, &batch); err != nil {
+			return nil, err
+		}
+		for _, issue := range batch {
+			if issue.PullRequest == nil {
+				issues = append(issues, issue)
+			}
+		}
+		if len(batch) < 100 {
+			return issues, nil
+		}
+	}
+}
+
+// CreateIssue opens a new issue
+func (c *Client) CreateIssue(ctx context.Context, req *IssueRequest) (*Issue, error) {
+	var issue Issue
+	if err := c.do(ctx, http.MethodPost, "/issues", req, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// UpdateIssue replaces the synced fields of an existing issue
+func (c *Client) UpdateIssue(ctx context.Context, number int, req *IssueRequest) (*Issue, error) {
+	var issue Issue
+	if err := c.do(ctx, http.MethodPatch, "/issues/"+strconv.Itoa(number), req, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// do sends a request under /repos/{owner}/{name} and decodes the response
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	base := c.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(base, "/")+"/repos/"+c.Repo+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	req.Header.Set("User-Agent", "beads-sync")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	httpClient := c.HTTP
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("GitHub request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 64*1024)).Decode(&apiErr)
+		if apiErr.Message != "" {
+			return fmt.Errorf("GitHub %s %s: %s: %s", method, path, resp.Status, apiErr.Message)
+		}
+		return fmt.Errorf("GitHub %s %s: %s", method, path, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode GitHub response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,423 @@
+package github
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// Change actions reported by a sync
+const (
+	ActionImported = "imported" // GitHub issue created locally
+	ActionPulled   = "pulled"   // local issue updated from GitHub
+	ActionPushed   = "pushed"   // GitHub issue updated from local
+	ActionCreated  = "created"  // local issue opened on GitHub
+)
+
+// Options control a sync run
+type Options struct {
+	Actor        string
+	ImportOnly   bool // pull from GitHub without pushing local changes
+	CreateRemote bool // open GitHub issues for open local issues that aren't linked
+	DryRun       bool // report changes without making them
+}
+
+// Change is one issue touched by a sync. IssueID is empty for imports in a
+// dry run, and Number is zero for creations in a dry run.
+type Change struct {
+	Action   string `json:"action"`
+	IssueID  string `json:"issue_id,omitempty"`
+	Number   int    `json:"number,omitempty"`
+	Title    string `json:"title"`
+	Conflict bool   `json:"conflict,omitempty"` // changed on both sides; the newer side won
+}
+
+// Result summarizes a sync run
+type Result struct {
+	Repo    string    `json:"repo"`
+	Changes []*Change `json:"changes"`
+	Cursor  time.Time `json:"cursor"` // newest GitHub updated_at seen
+	DryRun  bool      `json:"dry_run,omitempty"`
+}
+
+// Syncer syncs one repository with a beads database
+type Syncer struct {
+	client *Client
+	store  *sqlite.SQLiteStorage
+}
+
+// NewSyncer returns a syncer for client's repository
+func NewSyncer(client *Client, store *sqlite.SQLiteStorage) *Syncer {
+	return &Syncer{client: client, store: store}
+}
+
+// System is the external_mappings system name for the repository
+func (s *Syncer) System() string {
+	return "github:" + s.client.Repo
+}
+
+func (s *Syncer) cursorKey() string {
+	return "github_sync_cursor:" + s.client.Repo
+}
+
+// Sync pulls GitHub issues updated since the last sync, then pushes local
+// changes to linked issues. A field set changed on both sides since the last
+// sync goes to whichever side was updated more recently.
+func (s *Syncer) Sync(ctx context.Context, opts Options) (*Result, error) {
+	result := &Result{Repo: s.client.Repo, Changes: []*Change{}, DryRun: opts.DryRun}
+
+	cursor, err := s.loadCursor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result.Cursor = cursor
+
+	pulled, conflicts, err := s.pull(ctx, opts, result)
+	if err != nil {
+		return nil, err
+	}
+	if !opts.ImportOnly {
+		if err := s.push(ctx, opts, pulled, conflicts, result); err != nil {
+			return nil, err
+		}
+	}
+
+	if !opts.DryRun && result.Cursor.After(cursor) {
+		if err := s.store.SetMetadata(ctx, s.cursorKey(), result.Cursor.UTC().Format(time.RFC3339)); err != nil {
+			return nil, fmt.Errorf("failed to save sync cursor: %w", err)
+		}
+	}
+	return result, nil
+}
+
+func (s *Syncer) loadCursor(ctx context.Context) (time.Time, error) {
+	value, err := s.store.GetMetadata(ctx, s.cursorKey())
+	if err != nil || value == "" {
+		return time.Time{}, err
+	}
+	cursor, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid sync cursor %q: %w", value, err)
+	}
+	return cursor, nil
+}
+
+// pull applies GitHub changes locally. It returns the local IDs it wrote and
+// those whose newer local changes should override GitHub.
+func (s *Syncer) pull(ctx context.Context, opts Options, result *Result) (pulled, conflicts map[string]bool, err error) {
+	remote, err := s.client.ListIssues(ctx, result.Cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pulled = make(map[string]bool)
+	conflicts = make(map[string]bool)
+	for _, gh := range remote {
+		if gh.UpdatedAt.After(result.Cursor) {
+			result.Cursor = gh.UpdatedAt
+		}
+		remoteState := remoteFields(gh)
+
+		mapping, err := s.store.GetExternalMapping(ctx, s.System(), strconv.Itoa(gh.Number))
+		if err != nil {
+			return nil, nil, err
+		}
+		if mapping == nil {
+			change := &Change{Action: ActionImported, Number: gh.Number, Title: gh.Title}
+			if !opts.DryRun {
+				if change.IssueID, err = s.importIssue(ctx, gh, remoteState, opts.Actor); err != nil {
+					return nil, nil, err
+				}
+				pulled[change.IssueID] = true
+			}
+			result.Changes = append(result.Changes, change)
+			continue
+		}
+
+		remoteHash := remoteState.hash()
+		if remoteHash == mapping.SyncedHash {
+			continue
+		}
+		issue, err := s.store.GetIssue(ctx, mapping.IssueID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if issue == nil {
+			continue
+		}
+		labels, err := s.store.GetLabels(ctx, issue.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		localState := localFields(issue, labels)
+
+		change := &Change{Action: ActionPulled, IssueID: issue.ID, Number: gh.Number, Title: gh.Title}
+		if localHash := localState.hash(); localHash != mapping.SyncedHash {
+			if localHash == remoteHash {
+				// Both sides made the same change
+				if !opts.DryRun {
+					if err := s.saveMapping(ctx, issue.ID, gh, remoteHash); err != nil {
+						return nil, nil, err
+					}
+				}
+				pulled[issue.ID] = true
+				continue
+			}
+			if issue.UpdatedAt.After(gh.UpdatedAt) {
+				// Local wins; push sends it
+				conflicts[issue.ID] = true
+				continue
+			}
+			change.Conflict = true
+		}
+
+		if !opts.DryRun {
+			if err := s.applyRemote(ctx, issue, localState, remoteState, opts.Actor); err != nil {
+				return nil, nil, err
+			}
+			if err := s.saveMapping(ctx, issue.ID, gh, remoteHash); err != nil {
+				return nil, nil, err
+			}
+		}
+		pulled[issue.ID] = true
+		result.Changes = append(result.Changes, change)
+	}
+	return pulled, conflicts, nil
+}
+
+// push sends local changes to GitHub, skipping issues pull just wrote
+func (s *Syncer) push(ctx context.Context, opts Options, pulled, conflicts map[string]bool, result *Result) error {
+	mappings, err := s.store.ListExternalMappings(ctx, s.System())
+	if err != nil {
+		return err
+	}
+	byIssue := make(map[string]*sqlite.ExternalMapping, len(mappings))
+	for _, m := range mappings {
+		byIssue[m.IssueID] = m
+	}
+
+	issues, err := s.store.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		return err
+	}
+	sort.Slice(issues, func(i, j int) bool { return issues[i].ID < issues[j].ID })
+
+	for _, issue := range issues {
+		if pulled[issue.ID] {
+			continue
+		}
+		mapping := byIssue[issue.ID]
+		if mapping == nil && (!opts.CreateRemote || issue.Status == types.StatusClosed) {
+			continue
+		}
+
+		labels, err := s.store.GetLabels(ctx, issue.ID)
+		if err != nil {
+			return err
+		}
+		state := localFields(issue, labels)
+		hash := state.hash()
+		if mapping != nil && hash == mapping.SyncedHash {
+			continue
+		}
+
+		change := &Change{Action: ActionPushed, IssueID: issue.ID, Title: issue.Title, Conflict: conflicts[issue.ID]}
+		if mapping == nil {
+			change.Action = ActionCreated
+		} else if change.Number, err = strconv.Atoi(mapping.ExternalID); err != nil {
+			return fmt.Errorf("invalid GitHub issue number %q for %s", mapping.ExternalID, issue.ID)
+		}
+		result.Changes = append(result.Changes, change)
+		if opts.DryRun {
+			continue
+		}
+
+		var gh *Issue
+		if mapping == nil {
+			req := state.request()
+			req.State = ""
+			if gh, err = s.client.CreateIssue(ctx, req); err != nil {
+				return err
+			}
+			change.Number = gh.Number
+			if issue.ExternalRef == nil || *issue.ExternalRef == "" {
+				if err := s.store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"external_ref": externalRef(gh.Number)}, opts.Actor); err != nil {
+					return err
+				}
+			}
+		} else if gh, err = s.client.UpdateIssue(ctx, change.Number, state.request()); err != nil {
+			return err
+		}
+
+		// Record what we sent rather than what came back, so that anything
+		// GitHub normalizes differently is pulled on the next sync instead of
+		// being pushed forever
+		if err := s.saveMapping(ctx, issue.ID, gh, hash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importIssue creates a local issue from a GitHub issue
+func (s *Syncer) importIssue(ctx context.Context, gh *Issue, state fields, actor string) (string, error) {
+	ref := externalRef(gh.Number)
+	issue := &types.Issue{
+		Title:       state.Title,
+		Description: state.Body,
+		Status:      types.StatusOpen,
+		Priority:    2,
+		IssueType:   types.TypeTask,
+		Assignee:    state.Assignee,
+		ExternalRef: &ref,
+	}
+	if state.State == "closed" {
+		now := time.Now()
+		issue.Status = types.StatusClosed
+		issue.ClosedAt = &now
+	}
+	if err := s.store.CreateIssue(ctx, issue, actor); err != nil {
+		return "", fmt.Errorf("failed to import GitHub issue #%d: %w", gh.Number, err)
+	}
+	for _, label := range state.Labels {
+		if err := s.store.AddLabel(ctx, issue.ID, label, actor); err != nil {
+			return "", err
+		}
+	}
+	return issue.ID, s.saveMapping(ctx, issue.ID, gh, state.hash())
+}
+
+// applyRemote updates a local issue to match the GitHub side
+func (s *Syncer) applyRemote(ctx context.Context, issue *types.Issue, local, remote fields, actor string) error {
+	updates := make(map[string]interface{})
+	if local.Title != remote.Title {
+		updates["title"] = remote.Title
+	}
+	if local.Body != remote.Body {
+		updates["description"] = remote.Body
+	}
+	if local.Assignee != remote.Assignee {
+		updates["assignee"] = remote.Assignee
+	}
+	if local.State != remote.State {
+		// Only open/closed is shared, so in_progress and blocked survive
+		// unless GitHub closed the issue
+		if remote.State == "closed" {
+			updates["status"] = string(types.StatusClosed)
+		} else {
+			updates["status"] = string(types.StatusOpen)
+		}
+	}
+	if len(updates) > 0 {
+		if err := s.store.UpdateIssue(ctx, issue.ID, updates, actor); err != nil {
+			return err
+		}
+	}
+
+	have := make(map[string]bool, len(local.Labels))
+	for _, label := range local.Labels {
+		have[label] = true
+	}
+	for _, label := range remote.Labels {
+		if have[label] {
+			delete(have, label)
+			continue
+		}
+		if err := s.store.AddLabel(ctx, issue.ID, label, actor); err != nil {
+			return err
+		}
+	}
+	for label := range have {
+		if err := s.store.RemoveLabel(ctx, issue.ID, label, actor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Syncer) saveMapping(ctx context.Context, issueID string, gh *Issue, hash string) error {
+	updatedAt := gh.UpdatedAt
+	return s.store.SetExternalMapping(ctx, &sqlite.ExternalMapping{
+		System:            s.System(),
+		ExternalID:        strconv.Itoa(gh.Number),
+		IssueID:           issueID,
+		SyncedHash:        hash,
+		ExternalUpdatedAt: &updatedAt,
+	})
+}
+
+func externalRef(number int) string {
+	return "gh-" + strconv.Itoa(number)
+}
+
+// fields is the state a beads issue and a GitHub issue share
+type fields struct {
+	Title    string   `json:"title"`
+	Body     string   `json:"body"`
+	State    string   `json:"state"`
+	Labels   []string `json:"labels"`
+	Assignee string   `json:"assignee"`
+}
+
+func localFields(issue *types.Issue, labels []string) fields {
+	f := fields{
+		Title:    issue.Title,
+		Body:     issue.Description,
+		State:    "open",
+		Labels:   sortedLabels(labels),
+		Assignee: issue.Assignee,
+	}
+	if issue.Status == types.StatusClosed {
+		f.State = "closed"
+	}
+	return f
+}
+
+// remoteFields maps a GitHub issue onto the shared fields. Beads has a single
+// assignee, so only the first GitHub assignee is kept.
+func remoteFields(gh *Issue) fields {
+	f := fields{Title: gh.Title, Body: gh.Body, State: gh.State}
+	labels := make([]string, 0, len(gh.Labels))
+	for _, label := range gh.Labels {
+		labels = append(labels, label.Name)
+	}
+	f.Labels = sortedLabels(labels)
+	if len(gh.Assignees) > 0 {
+		f.Assignee = gh.Assignees[0].Login
+	}
+	return f
+}
+
+func sortedLabels(labels []string) []string {
+	sorted := append([]string{}, labels...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+func (f fields) hash() string {
+	data, _ := json.Marshal(f)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func (f fields) request() *IssueRequest {
+	req := &IssueRequest{
+		Title:     f.Title,
+		Body:      f.Body,
+		State:     f.State,
+		Labels:    f.Labels,
+		Assignees: []string{},
+	}
+	if f.Assignee != "" {
+		req.Assignees = []string{f.Assignee}
+	}
+	return req
+}
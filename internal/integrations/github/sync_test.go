@@ -0,0 +1,338 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// fakeGitHub serves the issues endpoints for one repository
+type fakeGitHub struct {
+	mu     sync.Mutex
+	issues map[int]*Issue
+	clock  time.Time
+	since  []string // since parameter of each list call
+}
+
+func newFakeGitHub() *fakeGitHub {
+	// Remote edits look newer than local ones unless a test says otherwise
+	return &fakeGitHub{issues: make(map[int]*Issue), clock: time.Now().Add(time.Hour).Truncate(time.Second)}
+}
+
+func (f *fakeGitHub) tick() time.Time {
+	f.clock = f.clock.Add(time.Second)
+	return f.clock
+}
+
+func (f *fakeGitHub) add(title, state string, labels ...string) *Issue {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	issue := &Issue{Number: len(f.issues) + 1, Title: title, State: state, UpdatedAt: f.tick()}
+	for _, l := range labels {
+		issue.Labels = append(issue.Labels, Label{Name: l})
+	}
+	f.issues[issue.Number] = issue
+	return issue
+}
+
+func (f *fakeGitHub) edit(number int, fn func(*Issue)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	fn(f.issues[number])
+	f.issues[number].UpdatedAt = f.tick()
+}
+
+func (f *fakeGitHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/repos/acme/widgets/issues")
+	switch {
+	case r.Method == http.MethodGet && path == "":
+		since := r.URL.Query().Get("since")
+		f.since = append(f.since, since)
+		var list []*Issue
+		for _, issue := range f.issues {
+			if since != "" {
+				t, _ := time.Parse(time.RFC3339, since)
+				if issue.UpdatedAt.Before(t) {
+					continue
+				}
+			}
+			list = append(list, issue)
+		}
+		sort.Slice(list, func(i, j int) bool { return list[i].UpdatedAt.Before(list[j].UpdatedAt) })
+		if r.URL.Query().Get("page") != "1" {
+			list = nil
+		}
+		_ = json.NewEncoder(w).Encode(list)
+	case r.Method == http.MethodPost && path == "":
+		var req IssueRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		issue := &Issue{Number: len(f.issues) + 1, State: "open"}
+		f.issues[issue.Number] = issue
+		applyRequest(issue, &req)
+		issue.UpdatedAt = f.tick()
+		_ = json.NewEncoder(w).Encode(issue)
+	case r.Method == http.MethodPatch:
+		number, _ := strconv.Atoi(strings.TrimPrefix(path, "/"))
+		issue := f.issues[number]
+		if issue == nil {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+			return
+		}
+		var req IssueRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		applyRequest(issue, &req)
+		issue.UpdatedAt = f.tick()
+		_ = json.NewEncoder(w).Encode(issue)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func applyRequest(issue *Issue, req *IssueRequest) {
+	issue.Title, issue.Body = req.Title, req.Body
+	if req.State != "" {
+		issue.State = req.State
+	}
+	issue.Labels, issue.Assignees = nil, nil
+	for _, l := range req.Labels {
+		issue.Labels = append(issue.Labels, Label{Name: l})
+	}
+	for _, a := range req.Assignees {
+		issue.Assignees = append(issue.Assignees, User{Login: a})
+	}
+}
+
+func setupSync(t *testing.T) (*fakeGitHub, *Syncer, *sqlite.SQLiteStorage) {
+	t.Helper()
+	fake := newFakeGitHub()
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	if err := store.SetConfig(context.Background(), "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := NewClient("acme/widgets", "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.BaseURL = srv.URL
+	client.HTTP = srv.Client()
+	return fake, NewSyncer(client, store), store
+}
+
+func actions(result *Result) []string {
+	var out []string
+	for _, c := range result.Changes {
+		s := c.Action + " #" + strconv.Itoa(c.Number)
+		if c.Conflict {
+			s += " (conflict)"
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+func TestSyncImportsAndIsIncremental(t *testing.T) {
+	fake, syncer, store := setupSync(t)
+	ctx := context.Background()
+
+	fake.add("Fix login", "open", "bug")
+	fake.edit(1, func(i *Issue) { i.Assignees = []User{{Login: "alice"}, {Login: "bob"}} })
+	fake.add("Old report", "closed")
+
+	result, err := syncer.Sync(ctx, Options{Actor: "test"})
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if got := strings.Join(actions(result), ", "); got != "imported #1, imported #2" {
+		t.Fatalf("Unexpected changes: %s", got)
+	}
+
+	m, err := store.GetExternalMapping(ctx, "github:acme/widgets", "1")
+	if err != nil || m == nil {
+		t.Fatalf("Expected mapping for #1: %v", err)
+	}
+	issue, _ := store.GetIssue(ctx, m.IssueID)
+	labels, _ := store.GetLabels(ctx, issue.ID)
+	if issue.Title != "Fix login" || issue.Assignee != "alice" || len(labels) != 1 || labels[0] != "bug" {
+		t.Errorf("Unexpected import %+v labels=%v", issue, labels)
+	}
+	if issue.ExternalRef == nil || *issue.ExternalRef != "gh-1" {
+		t.Errorf("Expected external_ref gh-1, got %v", issue.ExternalRef)
+	}
+	m2, _ := store.GetExternalMapping(ctx, "github:acme/widgets", "2")
+	closed, _ := store.GetIssue(ctx, m2.IssueID)
+	if closed.Status != types.StatusClosed {
+		t.Errorf("Expected #2 imported closed, got %s", closed.Status)
+	}
+
+	// Nothing changed: second sync is a no-op and asks only for newer issues
+	result, err = syncer.Sync(ctx, Options{Actor: "test"})
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if len(result.Changes) != 0 {
+		t.Errorf("Expected no changes, got %v", actions(result))
+	}
+	if fake.since[1] == "" {
+		t.Error("Expected the second sync to pass a since cursor")
+	}
+}
+
+func TestSyncPullsAndPushes(t *testing.T) {
+	fake, syncer, store := setupSync(t)
+	ctx := context.Background()
+
+	fake.add("First", "open")
+	fake.add("Second", "open")
+	if _, err := syncer.Sync(ctx, Options{Actor: "test"}); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	m1, _ := store.GetExternalMapping(ctx, "github:acme/widgets", "1")
+	m2, _ := store.GetExternalMapping(ctx, "github:acme/widgets", "2")
+
+	// Remote closes #1; local relabels and starts #2
+	fake.edit(1, func(i *Issue) { i.State = "closed" })
+	if err := store.AddLabel(ctx, m2.IssueID, "backend", "test"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.UpdateIssue(ctx, m2.IssueID, map[string]interface{}{"status": "in_progress"}, "test"); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := syncer.Sync(ctx, Options{Actor: "test"})
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if got := strings.Join(actions(result), ", "); got != "pulled #1, pushed #2" {
+		t.Fatalf("Unexpected changes: %s", got)
+	}
+
+	first, _ := store.GetIssue(ctx, m1.IssueID)
+	if first.Status != types.StatusClosed {
+		t.Errorf("Expected #1 closed locally, got %s", first.Status)
+	}
+	second := fake.issues[2]
+	if second.State != "open" || len(second.Labels) != 1 || second.Labels[0].Name != "backend" {
+		t.Errorf("Unexpected pushed issue %+v", second)
+	}
+	// in_progress isn't shared, so the pushed state doesn't reopen a pull
+	local, _ := store.GetIssue(ctx, m2.IssueID)
+	if local.Status != types.StatusInProgress {
+		t.Errorf("Expected #2 to stay in_progress, got %s", local.Status)
+	}
+
+	result, err = syncer.Sync(ctx, Options{Actor: "test"})
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if len(result.Changes) != 0 {
+		t.Errorf("Expected no changes after round trip, got %v", actions(result))
+	}
+}
+
+func TestSyncConflictNewestWins(t *testing.T) {
+	fake, syncer, store := setupSync(t)
+	ctx := context.Background()
+
+	fake.add("Original", "open")
+	if _, err := syncer.Sync(ctx, Options{Actor: "test"}); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	m, _ := store.GetExternalMapping(ctx, "github:acme/widgets", "1")
+
+	// The fake clock runs ahead of local time, so GitHub's edit is newer
+	if err := store.UpdateIssue(ctx, m.IssueID, map[string]interface{}{"title": "Local title"}, "test"); err != nil {
+		t.Fatal(err)
+	}
+	fake.edit(1, func(i *Issue) { i.Title = "Remote title" })
+
+	result, err := syncer.Sync(ctx, Options{Actor: "test"})
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if got := strings.Join(actions(result), ", "); got != "pulled #1 (conflict)" {
+		t.Fatalf("Unexpected changes: %s", got)
+	}
+	issue, _ := store.GetIssue(ctx, m.IssueID)
+	if issue.Title != "Remote title" {
+		t.Errorf("Expected remote title to win, got %q", issue.Title)
+	}
+}
+
+func TestSyncCreateRemoteAndDryRun(t *testing.T) {
+	fake, syncer, store := setupSync(t)
+	ctx := context.Background()
+
+	issue := &types.Issue{Title: "Local only", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask, Assignee: "carol"}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Unlinked issues stay local without CreateRemote
+	result, err := syncer.Sync(ctx, Options{Actor: "test"})
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if len(result.Changes) != 0 {
+		t.Errorf("Expected no changes, got %v", actions(result))
+	}
+
+	result, err = syncer.Sync(ctx, Options{Actor: "test", CreateRemote: true, DryRun: true})
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if len(result.Changes) != 1 || result.Changes[0].Action != ActionCreated || len(fake.issues) != 0 {
+		t.Fatalf("Expected one dry-run creation, got %v with %d remote issues", actions(result), len(fake.issues))
+	}
+
+	if _, err := syncer.Sync(ctx, Options{Actor: "test", CreateRemote: true}); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	remote := fake.issues[1]
+	if remote == nil || remote.Title != "Local only" || len(remote.Assignees) != 1 || remote.Assignees[0].Login != "carol" {
+		t.Fatalf("Unexpected remote issue %+v", remote)
+	}
+	got, _ := store.GetIssue(ctx, issue.ID)
+	if got.ExternalRef == nil || *got.ExternalRef != "gh-1" {
+		t.Errorf("Expected external_ref gh-1, got %v", got.ExternalRef)
+	}
+
+	// The created issue comes back on the next pull without changes
+	result, err = syncer.Sync(ctx, Options{Actor: "test"})
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if len(result.Changes) != 0 {
+		t.Errorf("Expected no changes, got %v", actions(result))
+	}
+}
+
+func TestNewClientValidatesRepo(t *testing.T) {
+	for _, repo := range []string{"", "acme", "acme/", "/widgets", "a/b/c"} {
+		if _, err := NewClient(repo, ""); err == nil {
+			t.Errorf("Expected error for repo %q", repo)
+		}
+	}
+}
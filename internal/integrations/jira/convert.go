@@ -0,0 +1,193 @@
+package jira
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+	"github.com/imalsogreg/beads/internal/utils"
+)
+
+// ConvertOptions control how Jira issues become beads issues
+type ConvertOptions struct {
+	Prefix          string // issue prefix for newly imported issues
+	MinutesPerPoint int    // story point conversion when there's no time estimate
+}
+
+// Conversion is the result of Convert
+type Conversion struct {
+	Issues  []*types.Issue
+	New     int      // issues that don't exist locally yet
+	Skipped []string // parent or link references to issues in neither the export nor the database
+}
+
+// Convert maps Jira issues to beads issues. Issues imported before (matched
+// by external_ref against existing) keep their IDs; new ones get the next free
+// IDs under opts.Prefix.
+func Convert(jiraIssues []*Issue, existing []*types.Issue, opts ConvertOptions) (*Conversion, error) {
+	if opts.Prefix == "" {
+		return nil, fmt.Errorf("issue prefix is required")
+	}
+	if opts.MinutesPerPoint <= 0 {
+		opts.MinutesPerPoint = DefaultMinutesPerPoint
+	}
+
+	localByKey := make(map[string]string)
+	next := 1
+	for _, issue := range existing {
+		if key := JiraKey(issue); key != "" {
+			localByKey[key] = issue.ID
+		}
+		if utils.ExtractIssuePrefix(issue.ID) == opts.Prefix {
+			if n := utils.ExtractIssueNumber(issue.ID); n >= next {
+				next = n + 1
+			}
+		}
+	}
+
+	conv := &Conversion{}
+	keyByJiraID := make(map[string]string)
+	seen := make(map[string]bool)
+	var unique []*Issue
+	for _, ji := range jiraIssues {
+		if seen[ji.Key] {
+			continue
+		}
+		seen[ji.Key] = true
+		unique = append(unique, ji)
+		if ji.ID != "" {
+			keyByJiraID[ji.ID] = ji.Key
+		}
+		if _, ok := localByKey[ji.Key]; !ok {
+			localByKey[ji.Key] = fmt.Sprintf("%s-%d", opts.Prefix, next)
+			next++
+			conv.New++
+		}
+	}
+	resolve := func(ref string) string {
+		if id, ok := localByKey[ref]; ok {
+			return id
+		}
+		return localByKey[keyByJiraID[ref]]
+	}
+
+	blocks := make(map[[2]string]bool)
+	related := make(map[[2]string]bool)
+	for _, ji := range unique {
+		issue := convertIssue(ji, localByKey[ji.Key], opts.MinutesPerPoint)
+
+		if ji.Parent != "" {
+			if parent := resolve(ji.Parent); parent != "" && parent != issue.ID {
+				issue.Dependencies = append(issue.Dependencies, &types.Dependency{IssueID: issue.ID, DependsOnID: parent, Type: types.DepParentChild})
+			} else if parent == "" {
+				conv.Skipped = append(conv.Skipped, fmt.Sprintf("%s: parent %s", ji.Key, ji.Parent))
+			}
+		}
+
+		for _, link := range ji.Links {
+			other := resolve(link.Key)
+			if other == "" {
+				conv.Skipped = append(conv.Skipped, fmt.Sprintf("%s: %s link to %s", ji.Key, link.Type, link.Key))
+				continue
+			}
+			if other == issue.ID {
+				continue
+			}
+
+			if strings.EqualFold(link.Type, "Blocks") {
+				// The blocked issue depends on the blocker
+				blocker, blocked := issue.ID, other
+				if !link.Outward {
+					blocker, blocked = other, issue.ID
+				}
+				blocks[[2]string{blocked, blocker}] = true
+				continue
+			}
+			pair := [2]string{issue.ID, other}
+			if pair[0] > pair[1] {
+				pair[0], pair[1] = pair[1], pair[0]
+			}
+			related[pair] = true
+		}
+
+		conv.Issues = append(conv.Issues, issue)
+	}
+
+	// Each dependency belongs to its dependent issue, which has to be in the
+	// export for the importer to add it
+	byID := make(map[string]*types.Issue, len(conv.Issues))
+	for _, issue := range conv.Issues {
+		byID[issue.ID] = issue
+	}
+	addDeps := func(pairs map[[2]string]bool, depType types.DependencyType) {
+		keys := make([][2]string, 0, len(pairs))
+		for pair := range pairs {
+			keys = append(keys, pair)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i][0] < keys[j][0] || (keys[i][0] == keys[j][0] && keys[i][1] < keys[j][1])
+		})
+		for _, pair := range keys {
+			from, to := pair[0], pair[1]
+			if byID[from] == nil && depType == types.DepRelated {
+				from, to = to, from
+			}
+			if issue := byID[from]; issue != nil {
+				issue.Dependencies = append(issue.Dependencies, &types.Dependency{IssueID: from, DependsOnID: to, Type: depType})
+			}
+		}
+	}
+	addDeps(blocks, types.DepBlocks)
+	addDeps(related, types.DepRelated)
+
+	return conv, nil
+}
+
+func convertIssue(ji *Issue, id string, minutesPerPoint int) *types.Issue {
+	ref := RefPrefix + ji.Key
+	now := time.Now()
+	issue := &types.Issue{
+		ID:          id,
+		Title:       ji.Summary,
+		Description: ji.Description,
+		Status:      MapStatus(ji.Status, ji.StatusCategory),
+		Priority:    MapPriority(ji.Priority),
+		IssueType:   MapType(ji.Type),
+		Assignee:    ji.Assignee,
+		Labels:      ji.Labels,
+		ExternalRef: &ref,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if issue.Title == "" {
+		issue.Title = ji.Key
+	}
+	if ji.Created != nil {
+		issue.CreatedAt = *ji.Created
+		issue.UpdatedAt = *ji.Created
+	}
+	if ji.Updated != nil {
+		issue.UpdatedAt = *ji.Updated
+	}
+
+	switch {
+	case ji.OriginalEstimate != nil:
+		minutes := *ji.OriginalEstimate / 60
+		issue.EstimatedMinutes = &minutes
+	case ji.StoryPoints != nil:
+		minutes := int(math.Round(*ji.StoryPoints * float64(minutesPerPoint)))
+		issue.EstimatedMinutes = &minutes
+	}
+
+	if issue.Status == types.StatusClosed {
+		closedAt := issue.UpdatedAt
+		if ji.Resolved != nil {
+			closedAt = *ji.Resolved
+		}
+		issue.ClosedAt = &closedAt
+	}
+	return issue
+}
@@ -0,0 +1,102 @@
+package jira
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// csvTimeLayout matches the dates in Jira's own CSV export
+const csvTimeLayout = "02/Jan/06 3:04 PM"
+
+// WriteCSV writes issues in the CSV layout ParseCSV reads, which Jira's CSV
+// importer also accepts. Issue id is the beads ID and Parent id and links
+// refer to it, so issues without a Jira key can still be related. Issues need
+// Labels and Dependencies populated.
+func WriteCSV(w io.Writer, issues []*types.Issue) error {
+	parents := make(map[string]string)
+	blocks := make(map[string][]string) // blocker -> blocked
+	related := make(map[string][]string)
+	for _, issue := range issues {
+		for _, dep := range issue.Dependencies {
+			switch dep.Type {
+			case types.DepParentChild:
+				parents[issue.ID] = dep.DependsOnID
+			case types.DepBlocks:
+				blocks[dep.DependsOnID] = append(blocks[dep.DependsOnID], issue.ID)
+			case types.DepRelated:
+				related[issue.ID] = append(related[issue.ID], dep.DependsOnID)
+			}
+		}
+	}
+
+	maxLabels, maxBlocks, maxRelated := 1, 1, 1
+	for _, issue := range issues {
+		maxLabels = max(maxLabels, len(issue.Labels))
+		maxBlocks = max(maxBlocks, len(blocks[issue.ID]))
+		maxRelated = max(maxRelated, len(related[issue.ID]))
+	}
+
+	header := []string{
+		"Issue key", "Issue id", "Parent id", "Summary", "Issue Type", "Status", "Priority",
+		"Assignee", "Description", "Original Estimate", "Created", "Updated", "Resolved",
+	}
+	header = appendRepeated(header, "Labels", maxLabels)
+	header = appendRepeated(header, "Outward issue link (Blocks)", maxBlocks)
+	header = appendRepeated(header, "Outward issue link (Relates)", maxRelated)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	sorted := append([]*types.Issue{}, issues...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	for _, issue := range sorted {
+		estimate := ""
+		if issue.EstimatedMinutes != nil {
+			estimate = fmt.Sprint(*issue.EstimatedMinutes * 60)
+		}
+		resolved := ""
+		if issue.ClosedAt != nil {
+			resolved = issue.ClosedAt.Format(csvTimeLayout)
+		}
+		priority := priorityNames[2]
+		if issue.Priority >= 0 && issue.Priority < len(priorityNames) {
+			priority = priorityNames[issue.Priority]
+		}
+		labels := append([]string{}, issue.Labels...)
+		sort.Strings(labels)
+		record := []string{
+			JiraKey(issue), issue.ID, parents[issue.ID], issue.Title, typeNames[issue.IssueType], statusNames[issue.Status], priority,
+			issue.Assignee, issue.Description, estimate,
+			issue.CreatedAt.Format(csvTimeLayout), issue.UpdatedAt.Format(csvTimeLayout), resolved,
+		}
+		record = appendPadded(record, labels, maxLabels)
+		record = appendPadded(record, blocks[issue.ID], maxBlocks)
+		record = appendPadded(record, related[issue.ID], maxRelated)
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func appendRepeated(row []string, name string, n int) []string {
+	for i := 0; i < n; i++ {
+		row = append(row, name)
+	}
+	return row
+}
+
+func appendPadded(row, values []string, n int) []string {
+	row = append(row, values...)
+	for i := len(values); i < n; i++ {
+		row = append(row, "")
+	}
+	return row
+}
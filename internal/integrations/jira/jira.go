@@ -0,0 +1,136 @@
+// Package jira converts between Jira exports and beads issues.
+//
+// Parse reads either Jira's JSON (a REST search response or an array of
+// issues) or its CSV export. Convert turns the result into beads issues with
+// dependencies, ready for the regular importer: epics and parents become
+// parent-child dependencies, "Blocks" links become blocking dependencies, and
+// other links become related ones. Each imported issue keeps its Jira key in
+// external_ref (jira-<KEY>), so importing a newer export updates the same
+// issues. WriteCSV goes the other way for reporting back into Jira.
+package jira
+
+import (
+	"strings"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// RefPrefix starts the external_ref of issues that came from Jira
+const RefPrefix = "jira-"
+
+// DefaultMinutesPerPoint converts story points when an issue has no time estimate
+const DefaultMinutesPerPoint = 60
+
+// Issue is a Jira issue as read from an export
+type Issue struct {
+	Key              string
+	ID               string // Jira's numeric issue id, used by CSV parent references
+	Summary          string
+	Description      string
+	Type             string
+	Status           string
+	StatusCategory   string // "new", "indeterminate", or "done" when the export has it
+	Priority         string
+	Assignee         string
+	Labels           []string
+	StoryPoints      *float64
+	OriginalEstimate *int   // seconds
+	Parent           string // key (or numeric id in CSV) of the parent or epic
+	Links            []Link
+	Created          *time.Time
+	Updated          *time.Time
+	Resolved         *time.Time
+}
+
+// Link is an issue link from the point of view of the issue that has it
+type Link struct {
+	Type    string // link type name, e.g. "Blocks" or "Relates"
+	Outward bool   // true if this issue is the link's source ("blocks")
+	Key     string // the other issue
+}
+
+// MapStatus picks the beads status for a Jira status, preferring the status
+// category when the export includes it
+func MapStatus(name, category string) types.Status {
+	switch strings.ToLower(category) {
+	case "done":
+		return types.StatusClosed
+	case "indeterminate":
+		if strings.Contains(strings.ToLower(name), "block") {
+			return types.StatusBlocked
+		}
+		return types.StatusInProgress
+	case "new":
+		return types.StatusOpen
+	}
+
+	switch n := strings.ToLower(strings.TrimSpace(name)); {
+	case n == "done" || n == "closed" || n == "resolved" || n == "cancelled" || n == "canceled" || n == "won't do":
+		return types.StatusClosed
+	case strings.Contains(n, "block"):
+		return types.StatusBlocked
+	case strings.Contains(n, "progress") || strings.Contains(n, "review") || n == "testing" || n == "qa":
+		return types.StatusInProgress
+	default:
+		return types.StatusOpen
+	}
+}
+
+// MapPriority maps both the current (Highest..Lowest) and the legacy
+// (Blocker..Trivial) Jira priority schemes onto 0-4
+func MapPriority(name string) int {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "highest", "blocker":
+		return 0
+	case "high", "critical":
+		return 1
+	case "low", "minor":
+		return 3
+	case "lowest", "trivial":
+		return 4
+	default:
+		return 2
+	}
+}
+
+// MapType maps a Jira issue type name to a beads issue type
+func MapType(name string) types.IssueType {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "epic":
+		return types.TypeEpic
+	case "bug", "defect", "incident":
+		return types.TypeBug
+	case "story", "new feature", "feature", "improvement":
+		return types.TypeFeature
+	case "chore", "maintenance":
+		return types.TypeChore
+	default:
+		return types.TypeTask
+	}
+}
+
+var (
+	statusNames = map[types.Status]string{
+		types.StatusOpen:       "To Do",
+		types.StatusInProgress: "In Progress",
+		types.StatusBlocked:    "Blocked",
+		types.StatusClosed:     "Done",
+	}
+	priorityNames = []string{"Highest", "High", "Medium", "Low", "Lowest"}
+	typeNames     = map[types.IssueType]string{
+		types.TypeEpic:    "Epic",
+		types.TypeBug:     "Bug",
+		types.TypeFeature: "Story",
+		types.TypeTask:    "Task",
+		types.TypeChore:   "Task",
+	}
+)
+
+// JiraKey returns the Jira key recorded in an issue's external_ref, or ""
+func JiraKey(issue *types.Issue) string {
+	if issue.ExternalRef == nil || !strings.HasPrefix(*issue.ExternalRef, RefPrefix) {
+		return ""
+	}
+	return strings.TrimPrefix(*issue.ExternalRef, RefPrefix)
+}
@@ -0,0 +1,196 @@
+package jira
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+const searchJSON = `{
+  "names": {"customfield_10020": "Story Points"},
+  "issues": [
+    {"id": "10001", "key": "PROJ-1", "fields": {
+      "summary": "Checkout revamp",
+      "issuetype": {"name": "Epic"},
+      "status": {"name": "In Progress", "statusCategory": {"key": "indeterminate"}},
+      "priority": {"name": "High"},
+      "labels": ["payments"],
+      "created": "2024-05-01T09:00:00.000+0000",
+      "updated": "2024-05-02T09:00:00.000+0000"
+    }},
+    {"id": "10002", "key": "PROJ-2", "fields": {
+      "summary": "Card form",
+      "description": {"type": "doc", "content": [
+        {"type": "paragraph", "content": [{"type": "text", "text": "Use the new widget."}]}
+      ]},
+      "issuetype": {"name": "Story"},
+      "status": {"name": "Done", "statusCategory": {"key": "done"}},
+      "priority": {"name": "Medium"},
+      "assignee": {"displayName": "Alice", "emailAddress": "alice@example.com"},
+      "customfield_10020": 3,
+      "parent": {"key": "PROJ-1"},
+      "resolutiondate": "2024-05-03T12:00:00.000+0000",
+      "issuelinks": [
+        {"type": {"name": "Blocks"}, "outwardIssue": {"key": "PROJ-3"}},
+        {"type": {"name": "Relates"}, "outwardIssue": {"key": "OTHER-9"}}
+      ]
+    }},
+    {"id": "10003", "key": "PROJ-3", "fields": {
+      "summary": "Receipt emails",
+      "issuetype": {"name": "Task"},
+      "status": {"name": "To Do", "statusCategory": {"key": "new"}},
+      "priority": {"name": "Lowest"},
+      "timeoriginalestimate": 7200,
+      "customfield_10020": 5,
+      "issuelinks": [
+        {"type": {"name": "Blocks"}, "inwardIssue": {"key": "PROJ-2"}}
+      ]
+    }}
+  ]
+}`
+
+func TestParseJSON(t *testing.T) {
+	issues, err := Parse(strings.NewReader(searchJSON))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(issues) != 3 {
+		t.Fatalf("Expected 3 issues, got %d", len(issues))
+	}
+
+	story := issues[1]
+	if story.Description != "Use the new widget." {
+		t.Errorf("Expected ADF description flattened, got %q", story.Description)
+	}
+	if story.Assignee != "alice@example.com" || story.Parent != "PROJ-1" {
+		t.Errorf("Unexpected assignee/parent %q/%q", story.Assignee, story.Parent)
+	}
+	if story.StoryPoints == nil || *story.StoryPoints != 3 {
+		t.Errorf("Expected 3 story points from the named field, got %v", story.StoryPoints)
+	}
+	if len(story.Links) != 2 || !story.Links[0].Outward || story.Links[0].Key != "PROJ-3" {
+		t.Errorf("Unexpected links %+v", story.Links)
+	}
+	if story.Resolved == nil || story.Resolved.Day() != 3 {
+		t.Errorf("Expected resolution date, got %v", story.Resolved)
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	data := "Summary,Issue key,Issue id,Issue Type,Status,Priority,Assignee,Labels,Labels,Custom field (Story Points),Parent id,Outward issue link (Blocks)\n" +
+		"Epic,ABC-1,100,Epic,Open,Major,,,,,,\n" +
+		"\"Fix, quickly\",ABC-2,101,Bug,In Review,Blocker,bob,urgent,backend,2,100,ABC-3\n" +
+		"Follow-up,ABC-3,102,Sub-task,Blocked,Trivial,,,,,101,\n"
+
+	issues, err := Parse(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(issues) != 3 {
+		t.Fatalf("Expected 3 issues, got %d", len(issues))
+	}
+	bug := issues[1]
+	if bug.Summary != "Fix, quickly" || bug.Parent != "100" || len(bug.Labels) != 2 {
+		t.Errorf("Unexpected issue %+v", bug)
+	}
+	if len(bug.Links) != 1 || bug.Links[0].Type != "Blocks" || bug.Links[0].Key != "ABC-3" {
+		t.Errorf("Unexpected links %+v", bug.Links)
+	}
+
+	if _, err := Parse(strings.NewReader("id,title\n1,x\n")); err == nil {
+		t.Error("Expected error for a CSV that isn't a Jira export")
+	}
+}
+
+func TestConvert(t *testing.T) {
+	jiraIssues, err := Parse(strings.NewReader(searchJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// PROJ-3 was imported before as bd-7
+	ref := "jira-PROJ-3"
+	existing := []*types.Issue{
+		{ID: "bd-7", ExternalRef: &ref},
+		{ID: "bd-12"},
+	}
+	conv, err := Convert(jiraIssues, existing, ConvertOptions{Prefix: "bd", MinutesPerPoint: 120})
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	if conv.New != 2 {
+		t.Errorf("Expected 2 new issues, got %d", conv.New)
+	}
+
+	epic, story, task := conv.Issues[0], conv.Issues[1], conv.Issues[2]
+	if epic.ID != "bd-13" || story.ID != "bd-14" || task.ID != "bd-7" {
+		t.Fatalf("Unexpected IDs %s %s %s", epic.ID, story.ID, task.ID)
+	}
+	if epic.IssueType != types.TypeEpic || epic.Status != types.StatusInProgress || epic.Priority != 1 {
+		t.Errorf("Unexpected epic %+v", epic)
+	}
+	if story.Status != types.StatusClosed || story.ClosedAt == nil || story.IssueType != types.TypeFeature {
+		t.Errorf("Unexpected story %+v", story)
+	}
+	if story.EstimatedMinutes == nil || *story.EstimatedMinutes != 360 {
+		t.Errorf("Expected 3 points * 120 = 360 minutes, got %v", story.EstimatedMinutes)
+	}
+	if task.EstimatedMinutes == nil || *task.EstimatedMinutes != 120 {
+		t.Errorf("Expected the original estimate to win over points, got %v", task.EstimatedMinutes)
+	}
+	if *story.ExternalRef != "jira-PROJ-2" {
+		t.Errorf("Unexpected external_ref %q", *story.ExternalRef)
+	}
+
+	// Story is a child of the epic; the task is blocked by the story, once,
+	// even though both sides list the link
+	if len(story.Dependencies) != 1 || story.Dependencies[0].DependsOnID != "bd-13" || story.Dependencies[0].Type != types.DepParentChild {
+		t.Errorf("Unexpected story deps %+v", story.Dependencies)
+	}
+	if len(task.Dependencies) != 1 || task.Dependencies[0].DependsOnID != "bd-14" || task.Dependencies[0].Type != types.DepBlocks {
+		t.Errorf("Unexpected task deps %+v", task.Dependencies)
+	}
+	if len(conv.Skipped) != 1 || !strings.Contains(conv.Skipped[0], "OTHER-9") {
+		t.Errorf("Expected the link outside the export to be skipped, got %v", conv.Skipped)
+	}
+}
+
+func TestWriteCSVRoundTrip(t *testing.T) {
+	jiraIssues, err := Parse(strings.NewReader(searchJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	conv, err := Convert(jiraIssues, nil, ConvertOptions{Prefix: "bd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, conv.Issues); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "Issue key,Issue id,Parent id,Summary") {
+		t.Errorf("Unexpected header: %s", strings.SplitN(buf.String(), "\n", 2)[0])
+	}
+
+	again, err := Parse(&buf)
+	if err != nil {
+		t.Fatalf("Parse of exported CSV failed: %v", err)
+	}
+	conv2, err := Convert(again, conv.Issues, ConvertOptions{Prefix: "bd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conv2.New != 0 {
+		t.Errorf("Expected re-import to match existing issues, got %d new", conv2.New)
+	}
+	for i, issue := range conv2.Issues {
+		orig := conv.Issues[i]
+		if issue.ID != orig.ID || issue.Status != orig.Status || issue.Priority != orig.Priority ||
+			issue.IssueType != orig.IssueType || len(issue.Dependencies) != len(orig.Dependencies) {
+			t.Errorf("Round trip changed %s: %+v vs %+v", orig.ID, issue, orig)
+		}
+	}
+}
@@ -0,0 +1,392 @@
+package jira
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parse reads a Jira JSON or CSV export, telling them apart by the first
+// non-blank character
+func Parse(r io.Reader) ([]*Issue, error) {
+	br := bufio.NewReader(r)
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			if err == io.EOF {
+				return nil, fmt.Errorf("empty Jira export")
+			}
+			return nil, err
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			_, _ = br.ReadByte()
+			continue
+		case '{', '[':
+			return ParseJSON(br)
+		default:
+			return ParseCSV(br)
+		}
+	}
+}
+
+// jsonIssue is an issue from the Jira REST API (v2 or v3)
+type jsonIssue struct {
+	ID     string                     `json:"id"`
+	Key    string                     `json:"key"`
+	Fields map[string]json.RawMessage `json:"fields"`
+}
+
+type jsonExport struct {
+	Issues []jsonIssue       `json:"issues"`
+	Names  map[string]string `json:"names"` // field id -> display name, present with expand=names
+}
+
+// Commonly used custom field ids, tried when the export has no names map
+var (
+	defaultStoryPointFields = []string{"customfield_10016", "customfield_10002", "customfield_10026"}
+	defaultEpicLinkFields   = []string{"customfield_10014", "customfield_10008"}
+)
+
+// ParseJSON reads a REST search response ({"issues": [...]}) or a bare array
+// of issues
+func ParseJSON(r io.Reader) ([]*Issue, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var export jsonExport
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(trimmed, &export.Issues)
+	} else {
+		err = json.Unmarshal(trimmed, &export)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Jira JSON: %w", err)
+	}
+
+	storyPointFields := fieldsNamed(export.Names, defaultStoryPointFields, "story points", "story point estimate")
+	epicLinkFields := fieldsNamed(export.Names, defaultEpicLinkFields, "epic link")
+
+	issues := make([]*Issue, 0, len(export.Issues))
+	for _, raw := range export.Issues {
+		if raw.Key == "" {
+			return nil, fmt.Errorf("Jira issue without a key")
+		}
+		f := raw.Fields
+		issue := &Issue{
+			Key:         raw.Key,
+			ID:          raw.ID,
+			Summary:     stringField(f["summary"]),
+			Description: descriptionText(f["description"]),
+			Type:        nameField(f["issuetype"]),
+			Status:      nameField(f["status"]),
+			Priority:    nameField(f["priority"]),
+			Assignee:    userField(f["assignee"]),
+			Created:     timeField(stringField(f["created"])),
+			Updated:     timeField(stringField(f["updated"])),
+			Resolved:    timeField(stringField(f["resolutiondate"])),
+		}
+
+		var status struct {
+			StatusCategory struct {
+				Key string `json:"key"`
+			} `json:"statusCategory"`
+		}
+		_ = json.Unmarshal(f["status"], &status)
+		issue.StatusCategory = status.StatusCategory.Key
+
+		_ = json.Unmarshal(f["labels"], &issue.Labels)
+
+		for _, id := range storyPointFields {
+			var points float64
+			if json.Unmarshal(f[id], &points) == nil && points > 0 {
+				issue.StoryPoints = &points
+				break
+			}
+		}
+		var estimate int
+		if json.Unmarshal(f["timeoriginalestimate"], &estimate) == nil && estimate > 0 {
+			issue.OriginalEstimate = &estimate
+		}
+
+		var parent struct {
+			Key string `json:"key"`
+		}
+		if json.Unmarshal(f["parent"], &parent) == nil && parent.Key != "" {
+			issue.Parent = parent.Key
+		} else {
+			for _, id := range epicLinkFields {
+				if key := stringField(f[id]); key != "" {
+					issue.Parent = key
+					break
+				}
+			}
+		}
+
+		var links []struct {
+			Type struct {
+				Name string `json:"name"`
+			} `json:"type"`
+			OutwardIssue *struct {
+				Key string `json:"key"`
+			} `json:"outwardIssue"`
+			InwardIssue *struct {
+				Key string `json:"key"`
+			} `json:"inwardIssue"`
+		}
+		_ = json.Unmarshal(f["issuelinks"], &links)
+		for _, l := range links {
+			if l.OutwardIssue != nil {
+				issue.Links = append(issue.Links, Link{Type: l.Type.Name, Outward: true, Key: l.OutwardIssue.Key})
+			}
+			if l.InwardIssue != nil {
+				issue.Links = append(issue.Links, Link{Type: l.Type.Name, Key: l.InwardIssue.Key})
+			}
+		}
+
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+// fieldsNamed returns the ids of custom fields whose display name matches,
+// falling back to well-known ids
+func fieldsNamed(names map[string]string, defaults []string, want ...string) []string {
+	var ids []string
+	for id, name := range names {
+		for _, w := range want {
+			if strings.EqualFold(name, w) {
+				ids = append(ids, id)
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return defaults
+	}
+	return ids
+}
+
+func stringField(raw json.RawMessage) string {
+	var s string
+	_ = json.Unmarshal(raw, &s)
+	return s
+}
+
+func nameField(raw json.RawMessage) string {
+	var v struct {
+		Name string `json:"name"`
+	}
+	_ = json.Unmarshal(raw, &v)
+	return v.Name
+}
+
+func userField(raw json.RawMessage) string {
+	var v struct {
+		Name         string `json:"name"`
+		EmailAddress string `json:"emailAddress"`
+		DisplayName  string `json:"displayName"`
+	}
+	_ = json.Unmarshal(raw, &v)
+	switch {
+	case v.Name != "":
+		return v.Name
+	case v.EmailAddress != "":
+		return v.EmailAddress
+	default:
+		return v.DisplayName
+	}
+}
+
+// descriptionText accepts a plain string (API v2) or an Atlassian Document
+// Format tree (API v3), which is flattened to text
+func descriptionText(raw json.RawMessage) string {
+	if s := stringField(raw); s != "" {
+		return s
+	}
+	var doc adfNode
+	if json.Unmarshal(raw, &doc) != nil {
+		return ""
+	}
+	var b strings.Builder
+	doc.writeText(&b)
+	return strings.TrimSpace(b.String())
+}
+
+type adfNode struct {
+	Type    string    `json:"type"`
+	Text    string    `json:"text"`
+	Content []adfNode `json:"content"`
+}
+
+func (n adfNode) writeText(b *strings.Builder) {
+	switch n.Type {
+	case "text":
+		b.WriteString(n.Text)
+	case "hardBreak":
+		b.WriteString("\n")
+	case "listItem":
+		b.WriteString("- ")
+	}
+	for _, c := range n.Content {
+		c.writeText(b)
+	}
+	switch n.Type {
+	case "paragraph", "heading", "codeBlock", "blockquote", "rule":
+		b.WriteString("\n\n")
+	}
+}
+
+// Date layouts found in Jira exports: the REST API's, the CSV default, and
+// a couple of common custom formats
+var timeLayouts = []string{
+	"2006-01-02T15:04:05.000-0700",
+	time.RFC3339,
+	"02/Jan/06 3:04 PM",
+	"02/Jan/06 15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+func timeField(s string) *time.Time {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+var linkColumn = regexp.MustCompile(`^(Outward|Inward) issue link \((.+)\)$`)
+
+// ParseCSV reads Jira's CSV export. Repeated columns (Labels, issue links)
+// are all collected.
+func ParseCSV(r io.Reader) ([]*Issue, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Jira CSV header: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff"))
+	}
+	col := func(name string) int {
+		for i, h := range header {
+			if strings.EqualFold(h, name) {
+				return i
+			}
+		}
+		return -1
+	}
+	keyCol := col("Issue key")
+	if keyCol < 0 {
+		keyCol = col("Issue id")
+	}
+	if keyCol < 0 || col("Summary") < 0 {
+		return nil, fmt.Errorf("not a Jira CSV export: missing Summary or Issue key column")
+	}
+
+	var issues []*Issue
+	line := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Jira CSV line %d: %w", line, err)
+		}
+		// get returns the first non-empty value among the named columns
+		get := func(names ...string) string {
+			for _, name := range names {
+				if i := col(name); i >= 0 && i < len(record) {
+					if v := strings.TrimSpace(record[i]); v != "" {
+						return v
+					}
+				}
+			}
+			return ""
+		}
+
+		issue := &Issue{
+			Key:            get("Issue key"),
+			ID:             get("Issue id"),
+			Summary:        get("Summary"),
+			Description:    get("Description"),
+			Type:           get("Issue Type"),
+			Status:         get("Status"),
+			StatusCategory: statusCategoryKey(get("Status Category")),
+			Priority:       get("Priority"),
+			Assignee:       get("Assignee"),
+			Created:        timeField(get("Created")),
+			Updated:        timeField(get("Updated")),
+			Resolved:       timeField(get("Resolved")),
+			Parent:         get("Parent", "Parent key", "Custom field (Epic Link)", "Parent id"),
+		}
+		if issue.Key == "" {
+			issue.Key = issue.ID
+		}
+		if issue.Key == "" {
+			return nil, fmt.Errorf("Jira CSV line %d has no issue key", line)
+		}
+		if v := get("Custom field (Story Points)", "Custom field (Story point estimate)", "Story Points"); v != "" {
+			if points, err := strconv.ParseFloat(v, 64); err == nil && points > 0 {
+				issue.StoryPoints = &points
+			}
+		}
+		if v := get("Original Estimate", "Original estimate"); v != "" {
+			if seconds, err := strconv.Atoi(v); err == nil && seconds > 0 {
+				issue.OriginalEstimate = &seconds
+			}
+		}
+
+		for i, h := range header {
+			if i >= len(record) {
+				break
+			}
+			value := strings.TrimSpace(record[i])
+			if value == "" {
+				continue
+			}
+			if strings.EqualFold(h, "Labels") {
+				issue.Labels = append(issue.Labels, strings.Fields(value)...)
+			} else if m := linkColumn.FindStringSubmatch(h); m != nil {
+				issue.Links = append(issue.Links, Link{Type: m[2], Outward: m[1] == "Outward", Key: value})
+			}
+		}
+
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+// statusCategoryKey maps the category names used in CSV exports to the
+// keys used by the REST API
+func statusCategoryKey(name string) string {
+	switch strings.ToLower(name) {
+	case "to do", "new":
+		return "new"
+	case "in progress", "indeterminate":
+		return "indeterminate"
+	case "done", "complete":
+		return "done"
+	default:
+		return ""
+	}
+}
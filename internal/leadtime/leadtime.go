@@ -0,0 +1,333 @@
+// Package leadtime reports how long issues take to close.
+//
+// Lead time runs from an issue's creation to its close, and cycle time from
+// when work on it started (its first move to in_progress) to its close.
+// Issues that were never in progress have a lead time but no cycle time. An
+// issue reopened and closed again counts once, with its latest close.
+package leadtime
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// Buckets are the upper bounds of the histogram buckets, with the last
+// bucket taking everything longer
+var Buckets = []time.Duration{
+	time.Hour,
+	4 * time.Hour,
+	24 * time.Hour,
+	3 * 24 * time.Hour,
+	7 * 24 * time.Hour,
+	14 * 24 * time.Hour,
+	30 * 24 * time.Hour,
+	90 * 24 * time.Hour,
+}
+
+// BucketLabels name the histogram buckets, one more than Buckets
+var BucketLabels = []string{"<1h", "<4h", "<1d", "<3d", "<1w", "<2w", "<30d", "<90d", "90d+"}
+
+// GroupDimensions lists the valid values of a report's groupBy. Reports
+// without one have only the overall group.
+var GroupDimensions = []string{types.GroupByLabel, types.GroupByAssignee, types.GroupByType, types.GroupByPriority}
+
+// IsValidGroup reports whether a report can be grouped by groupBy
+func IsValidGroup(groupBy string) bool {
+	for _, d := range GroupDimensions {
+		if d == groupBy {
+			return true
+		}
+	}
+	return false
+}
+
+// Distribution summarizes a set of durations, in hours
+type Distribution struct {
+	Count     int     `json:"count"`
+	MinHours  float64 `json:"min_hours"`
+	P50Hours  float64 `json:"p50_hours"`
+	P85Hours  float64 `json:"p85_hours"`
+	P95Hours  float64 `json:"p95_hours"`
+	MaxHours  float64 `json:"max_hours"`
+	MeanHours float64 `json:"mean_hours"`
+	Histogram []int   `json:"histogram"` // issues per BucketLabels entry
+}
+
+// Group is the lead and cycle times of the issues sharing a key
+type Group struct {
+	Key       string       `json:"key"`
+	LeadTime  Distribution `json:"lead_time"`
+	CycleTime Distribution `json:"cycle_time"`
+}
+
+// Report is the lead and cycle times of closed issues, overall and by group
+type Report struct {
+	GroupBy string     `json:"group_by,omitempty"`
+	Since   *time.Time `json:"since,omitempty"`
+	Buckets []string   `json:"buckets"`
+	Overall *Group     `json:"overall"`
+	Groups  []*Group   `json:"groups,omitempty"`
+}
+
+// Compute reports on the issues closed since the given time (all of them for
+// the zero time), grouped by groupBy if it isn't empty
+func Compute(ctx context.Context, store *sqlite.SQLiteStorage, groupBy string, since time.Time) (*Report, error) {
+	if groupBy != "" && !IsValidGroup(groupBy) {
+		return nil, fmt.Errorf("can't group lead time by %q (valid: %s)", groupBy, strings.Join(GroupDimensions, ", "))
+	}
+	issues, err := store.GetClosedIssueTimes(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	report := Build(issues, groupBy)
+	if !since.IsZero() {
+		report.Since = &since
+	}
+	return report, nil
+}
+
+// Build reports on issues, grouped by groupBy if it isn't empty. Grouping by
+// label counts an issue under each of its labels, and issues without labels
+// under the empty key.
+func Build(issues []*sqlite.ClosedIssueTimes, groupBy string) *Report {
+	report := &Report{GroupBy: groupBy, Buckets: BucketLabels}
+
+	var lead, cycle []time.Duration
+	leadByKey := make(map[string][]time.Duration)
+	cycleByKey := make(map[string][]time.Duration)
+	var keys []string
+	for _, issue := range issues {
+		l := issue.ClosedAt.Sub(issue.CreatedAt)
+		if l < 0 {
+			continue
+		}
+		c := time.Duration(-1)
+		if issue.StartedAt != nil && !issue.StartedAt.After(issue.ClosedAt) {
+			c = issue.ClosedAt.Sub(*issue.StartedAt)
+		}
+		lead = append(lead, l)
+		if c >= 0 {
+			cycle = append(cycle, c)
+		}
+		if groupBy == "" {
+			continue
+		}
+		for _, key := range groupKeys(issue, groupBy) {
+			if _, ok := leadByKey[key]; !ok {
+				keys = append(keys, key)
+			}
+			leadByKey[key] = append(leadByKey[key], l)
+			if c >= 0 {
+				cycleByKey[key] = append(cycleByKey[key], c)
+			}
+		}
+	}
+
+	report.Overall = &Group{LeadTime: distribution(lead), CycleTime: distribution(cycle)}
+	for _, key := range keys {
+		report.Groups = append(report.Groups, &Group{
+			Key:       key,
+			LeadTime:  distribution(leadByKey[key]),
+			CycleTime: distribution(cycleByKey[key]),
+		})
+	}
+	sortGroups(groupBy, report.Groups)
+	return report
+}
+
+// groupKeys returns the keys an issue counts under
+func groupKeys(issue *sqlite.ClosedIssueTimes, groupBy string) []string {
+	switch groupBy {
+	case types.GroupByLabel:
+		if len(issue.Labels) == 0 {
+			return []string{""}
+		}
+		return issue.Labels
+	case types.GroupByAssignee:
+		return []string{issue.Assignee}
+	case types.GroupByType:
+		return []string{string(issue.IssueType)}
+	case types.GroupByPriority:
+		return []string{strconv.Itoa(issue.Priority)}
+	}
+	return nil
+}
+
+// sortGroups puts priorities in order and other groups with the most issues
+// first, with the empty key (unassigned, unlabeled) last
+func sortGroups(groupBy string, groups []*Group) {
+	sort.SliceStable(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if (a.Key == "") != (b.Key == "") {
+			return b.Key == ""
+		}
+		if groupBy == types.GroupByPriority {
+			pa, _ := strconv.Atoi(a.Key)
+			pb, _ := strconv.Atoi(b.Key)
+			return pa < pb
+		}
+		if a.LeadTime.Count != b.LeadTime.Count {
+			return a.LeadTime.Count > b.LeadTime.Count
+		}
+		return a.Key < b.Key
+	})
+}
+
+// distribution summarizes durations
+func distribution(durations []time.Duration) Distribution {
+	d := Distribution{Count: len(durations), Histogram: make([]int, len(BucketLabels))}
+	if len(durations) == 0 {
+		return d
+	}
+	hours := make([]float64, len(durations))
+	total := 0.0
+	for i, dur := range durations {
+		hours[i] = dur.Hours()
+		total += hours[i]
+		d.Histogram[bucket(dur)]++
+	}
+	sort.Float64s(hours)
+	d.MinHours = round(hours[0])
+	d.MaxHours = round(hours[len(hours)-1])
+	d.P50Hours = round(percentile(hours, 50))
+	d.P85Hours = round(percentile(hours, 85))
+	d.P95Hours = round(percentile(hours, 95))
+	d.MeanHours = round(total / float64(len(hours)))
+	return d
+}
+
+// bucket returns the histogram bucket of a duration
+func bucket(d time.Duration) int {
+	for i, bound := range Buckets {
+		if d < bound {
+			return i
+		}
+	}
+	return len(Buckets)
+}
+
+// percentile returns the nearest-rank percentile of sorted values
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// round rounds hours to two decimal places
+func round(hours float64) float64 {
+	return math.Round(hours*100) / 100
+}
+
+// sparkBars are the sparkline levels, lowest first
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline draws counts as a row of bars scaled to the largest, with a
+// space for zero
+func Sparkline(counts []int) string {
+	highest := 0
+	for _, n := range counts {
+		if n > highest {
+			highest = n
+		}
+	}
+	var b strings.Builder
+	for _, n := range counts {
+		if n == 0 {
+			b.WriteRune(' ')
+			continue
+		}
+		b.WriteRune(sparkBars[(n*len(sparkBars)-1)/highest])
+	}
+	return b.String()
+}
+
+// FormatHours formats a duration in hours compactly, as minutes, hours, or
+// days
+func FormatHours(hours float64) string {
+	switch {
+	case hours < 1:
+		return fmt.Sprintf("%.0fm", hours*60)
+	case hours < 48:
+		return fmt.Sprintf("%.1fh", hours)
+	}
+	return fmt.Sprintf("%.1fd", hours/24)
+}
+
+// Table renders the report as lead and cycle time tables, a row per group
+// and one for all issues, with a sparkline of each histogram
+func (r *Report) Table() string {
+	var b strings.Builder
+	if r.Overall == nil || r.Overall.LeadTime.Count == 0 {
+		b.WriteString("No closed issues")
+		if r.Since != nil {
+			fmt.Fprintf(&b, " since %s", r.Since.Local().Format("2006-01-02"))
+		}
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	groupBy := r.GroupBy
+	if groupBy == "" {
+		groupBy = "issues"
+	}
+	width := len(groupBy)
+	for _, g := range r.Groups {
+		if n := len(groupLabel(r.GroupBy, g.Key)); n > width {
+			width = n
+		}
+	}
+
+	fmt.Fprintf(&b, "%d issue(s) closed", r.Overall.LeadTime.Count)
+	if r.Since != nil {
+		fmt.Fprintf(&b, " since %s", r.Since.Local().Format("2006-01-02"))
+	}
+	fmt.Fprintf(&b, "; histograms run %s to %s\n", BucketLabels[0], BucketLabels[len(BucketLabels)-1])
+
+	tables := []struct {
+		title string
+		dist  func(*Group) Distribution
+	}{
+		{"Lead time (created to closed)", func(g *Group) Distribution { return g.LeadTime }},
+		{"Cycle time (started to closed)", func(g *Group) Distribution { return g.CycleTime }},
+	}
+	for _, table := range tables {
+		fmt.Fprintf(&b, "\n%s:\n", table.title)
+		fmt.Fprintf(&b, "  %-*s  %6s  %7s  %7s  %7s  %7s  %s\n", width, groupBy, "count", "p50", "p85", "p95", "mean", "histogram")
+		row := func(label string, d Distribution) {
+			if d.Count == 0 {
+				fmt.Fprintf(&b, "  %-*s  %6d  %7s  %7s  %7s  %7s\n", width, label, 0, "-", "-", "-", "-")
+				return
+			}
+			fmt.Fprintf(&b, "  %-*s  %6d  %7s  %7s  %7s  %7s  %s\n", width, label, d.Count,
+				FormatHours(d.P50Hours), FormatHours(d.P85Hours), FormatHours(d.P95Hours), FormatHours(d.MeanHours), strings.TrimRight(Sparkline(d.Histogram), " "))
+		}
+		for _, g := range r.Groups {
+			row(groupLabel(r.GroupBy, g.Key), table.dist(g))
+		}
+		row("(all)", table.dist(r.Overall))
+	}
+	return b.String()
+}
+
+// groupLabel names a group's key in tables
+func groupLabel(groupBy, key string) string {
+	switch {
+	case key == "" && groupBy == types.GroupByLabel:
+		return "(no labels)"
+	case key == "" && groupBy == types.GroupByAssignee:
+		return "(unassigned)"
+	case groupBy == types.GroupByPriority:
+		return "P" + key
+	}
+	return key
+}
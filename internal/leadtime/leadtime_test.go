@@ -0,0 +1,108 @@
+package leadtime
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestBuild(t *testing.T) {
+	base := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	started := base.Add(24 * time.Hour)
+	issues := []*sqlite.ClosedIssueTimes{
+		{ID: "a", Assignee: "alice", Labels: []string{"ui", "api"}, Priority: 1, CreatedAt: base, StartedAt: &started, ClosedAt: base.Add(48 * time.Hour)},
+		{ID: "b", Assignee: "alice", Labels: []string{"ui"}, Priority: 2, CreatedAt: base, ClosedAt: base.Add(2 * time.Hour)},
+		{ID: "c", Priority: 0, CreatedAt: base, ClosedAt: base.Add(30 * time.Minute)},
+	}
+
+	report := Build(issues, types.GroupByLabel)
+	if report.Overall.LeadTime.Count != 3 || report.Overall.CycleTime.Count != 1 {
+		t.Fatalf("Expected 3 lead times and 1 cycle time, got %+v", report.Overall)
+	}
+	lead := report.Overall.LeadTime
+	if lead.MinHours != 0.5 || lead.P50Hours != 2 || lead.MaxHours != 48 || lead.Histogram[0] != 1 || lead.Histogram[1] != 1 || lead.Histogram[3] != 1 {
+		t.Errorf("Unexpected lead time distribution %+v", lead)
+	}
+	if report.Overall.CycleTime.P50Hours != 24 {
+		t.Errorf("Expected a 24h cycle time, got %+v", report.Overall.CycleTime)
+	}
+	if len(report.Groups) != 3 || report.Groups[0].Key != "ui" || report.Groups[0].LeadTime.Count != 2 ||
+		report.Groups[1].Key != "api" || report.Groups[2].Key != "" {
+		t.Errorf("Unexpected label groups %+v %+v %+v", report.Groups[0], report.Groups[1], report.Groups[2])
+	}
+
+	report = Build(issues, types.GroupByPriority)
+	if len(report.Groups) != 3 || report.Groups[0].Key != "0" || report.Groups[2].Key != "2" {
+		t.Errorf("Expected groups in priority order, got %+v", report.Groups)
+	}
+	if table := report.Table(); !strings.Contains(table, "P1") || !strings.Contains(table, "Cycle time") {
+		t.Errorf("Unexpected table:\n%s", table)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	if got := Sparkline([]int{0, 1, 4, 8}); got != " ▁▄█" {
+		t.Errorf("Sparkline = %q", got)
+	}
+	if got := Sparkline([]int{0, 0}); got != "  " {
+		t.Errorf("Sparkline of nothing = %q", got)
+	}
+}
+
+func TestComputeFindsStartFromHistory(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "beads.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+
+	worked := &types.Issue{Title: "Worked", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	skipped := &types.Issue{Title: "Closed straight away", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	open := &types.Issue{Title: "Still open", Status: types.StatusInProgress, Priority: 2, IssueType: types.TypeTask}
+	for _, issue := range []*types.Issue{worked, skipped, open} {
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.UpdateIssue(ctx, worked.ID, map[string]interface{}{"status": string(types.StatusInProgress)}, "test"); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{worked.ID, skipped.ID} {
+		if err := store.CloseIssue(ctx, id, "done", "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	times, err := store.GetClosedIssueTimes(ctx, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(times) != 2 {
+		t.Fatalf("Expected the 2 closed issues, got %d", len(times))
+	}
+	for _, c := range times {
+		if (c.ID == worked.ID) != (c.StartedAt != nil) {
+			t.Errorf("%s: unexpected start %v", c.ID, c.StartedAt)
+		}
+	}
+
+	report, err := Compute(ctx, store, "", time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Overall.LeadTime.Count != 0 || report.Since == nil {
+		t.Errorf("Expected nothing closed since the future, got %+v", report.Overall)
+	}
+	if _, err := Compute(ctx, store, types.GroupByStatus, time.Time{}); err == nil {
+		t.Error("Expected grouping by status refused")
+	}
+}
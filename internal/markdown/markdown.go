@@ -0,0 +1,196 @@
+// Package markdown renders issues as GitHub-flavored markdown, for pasting
+// into pull requests and docs. It backs bd show/list --format markdown and
+// Accept: text/markdown on the HTTP API.
+package markdown
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// Detail is an issue with the related records shown with it. Everything but
+// Issue is optional.
+type Detail struct {
+	Issue        *types.Issue
+	Labels       []string
+	Dependencies []*types.Issue
+	Dependents   []*types.Issue
+	Comments     []*types.Comment
+}
+
+// Issue renders one issue: a heading, its fields, and a section for each
+// text field and relation that isn't empty
+func Issue(d Detail) string {
+	issue := d.Issue
+	labels := d.Labels
+	if labels == nil {
+		labels = issue.Labels
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s: %s\n\n", issue.ID, inline(issue.Title))
+
+	fields := []string{
+		"**Status:** " + string(issue.Status),
+		fmt.Sprintf("**Priority:** P%d", issue.Priority),
+		"**Type:** " + string(issue.IssueType),
+	}
+	if issue.Assignee != "" {
+		fields = append(fields, "**Assignee:** "+inline(issue.Assignee))
+	}
+	if issue.Milestone != "" {
+		fields = append(fields, "**Milestone:** "+inline(issue.Milestone))
+	}
+	if issue.StartDate != nil {
+		fields = append(fields, "**Start:** "+issue.StartDate.Local().Format("2006-01-02"))
+	}
+	if issue.DueDate != nil {
+		due := "**Due:** " + issue.DueDate.Local().Format("2006-01-02")
+		if issue.IsOverdue(time.Now()) {
+			due += " (overdue)"
+		}
+		fields = append(fields, due)
+	}
+	b.WriteString(strings.Join(fields, " · ") + "\n")
+	if len(labels) > 0 {
+		codes := make([]string, len(labels))
+		for i, label := range labels {
+			codes[i] = "`" + strings.ReplaceAll(label, "`", "'") + "`"
+		}
+		fmt.Fprintf(&b, "\n**Labels:** %s\n", strings.Join(codes, ", "))
+	}
+	if len(issue.Watchers) > 0 {
+		watchers := make([]string, len(issue.Watchers))
+		for i, user := range issue.Watchers {
+			watchers[i] = inline(user)
+		}
+		fmt.Fprintf(&b, "\n**Watchers:** %s\n", strings.Join(watchers, ", "))
+	}
+
+	section := func(title, text string) {
+		if text = strings.TrimSpace(text); text != "" {
+			fmt.Fprintf(&b, "\n## %s\n\n%s\n", title, text)
+		}
+	}
+	section("Description", issue.Description)
+	section("Design", issue.Design)
+	section("Acceptance Criteria", Checklist(issue.AcceptanceCriteria))
+	section("Notes", issue.Notes)
+
+	// Relations are checklists too, ticked once the other issue is closed.
+	// Linked issues get their own section instead.
+	linked := map[bool]map[string]bool{false: {}, true: {}}
+	for _, l := range issue.Links {
+		linked[l.Incoming][l.ID] = true
+	}
+	relation := func(title string, issues []*types.Issue, incoming bool) {
+		var shown []*types.Issue
+		for _, other := range issues {
+			if !linked[incoming][other.ID] {
+				shown = append(shown, other)
+			}
+		}
+		if len(shown) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n", title)
+		for _, other := range shown {
+			fmt.Fprintf(&b, "- %s %s: %s (%s)\n", checkbox(other.Status == types.StatusClosed), other.ID, inline(other.Title), other.Status)
+		}
+	}
+	relation("Depends On", d.Dependencies, false)
+	relation("Blocks", d.Dependents, true)
+
+	if len(issue.Links) > 0 {
+		b.WriteString("\n## Links\n\n")
+		for _, l := range issue.Links {
+			fmt.Fprintf(&b, "- %s %s: %s (%s)\n", l.Relation(), l.ID, inline(l.Title), l.Status)
+		}
+	}
+
+	if len(d.Comments) > 0 {
+		b.WriteString("\n## Comments\n")
+		for _, c := range d.Comments {
+			fmt.Fprintf(&b, "\n**%s** (%s):\n\n", inline(c.Author), c.CreatedAt.Format("2006-01-02 15:04"))
+			for _, line := range strings.Split(strings.TrimSpace(c.Text), "\n") {
+				b.WriteString(strings.TrimRight("> "+line, " ") + "\n")
+			}
+		}
+	}
+	return b.String()
+}
+
+// Issues renders several issues, separated by rules
+func Issues(details []Detail) string {
+	parts := make([]string, len(details))
+	for i, d := range details {
+		parts[i] = Issue(d)
+	}
+	return strings.Join(parts, "\n---\n\n")
+}
+
+// IssueList renders issues as a table
+func IssueList(issues []*types.Issue) string {
+	if len(issues) == 0 {
+		return "_No issues_\n"
+	}
+	var b strings.Builder
+	b.WriteString("| ID | Title | Status | Priority | Type | Assignee |\n")
+	b.WriteString("|----|-------|--------|----------|------|----------|\n")
+	for _, issue := range issues {
+		fmt.Fprintf(&b, "| %s | %s | %s | P%d | %s | %s |\n",
+			cell(issue.ID), cell(issue.Title), issue.Status, issue.Priority, issue.IssueType, cell(issue.Assignee))
+	}
+	return b.String()
+}
+
+// listItem matches a markdown list item, with an optional checkbox
+var listItem = regexp.MustCompile(`^(\s*)(?:[-*+]|\d+[.)])\s+(?:\[([ xX])\]\s+)?(.*)$`)
+
+// Checklist turns acceptance criteria into task list items. List items
+// become checkboxes (keeping any that are already ticked); if there are no
+// list items, each non-blank line does. Other lines are left alone.
+func Checklist(text string) string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return ""
+	}
+	lines := strings.Split(text, "\n")
+	hasItems := false
+	for _, line := range lines {
+		if listItem.MatchString(line) {
+			hasItems = true
+			break
+		}
+	}
+
+	for i, line := range lines {
+		if m := listItem.FindStringSubmatch(line); m != nil {
+			lines[i] = fmt.Sprintf("%s- %s %s", m[1], checkbox(m[2] == "x" || m[2] == "X"), m[3])
+		} else if !hasItems && strings.TrimSpace(line) != "" {
+			lines[i] = "- [ ] " + strings.TrimSpace(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func checkbox(checked bool) string {
+	if checked {
+		return "[x]"
+	}
+	return "[ ]"
+}
+
+// inline flattens text onto one line
+func inline(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// cell makes text safe inside a table cell
+func cell(s string) string {
+	return strings.ReplaceAll(inline(s), "|", `\|`)
+}
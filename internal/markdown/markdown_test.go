@@ -0,0 +1,86 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestChecklist(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain lines", "Works offline\n\nHandles errors", "- [ ] Works offline\n\n- [ ] Handles errors"},
+		{"list items", "Must:\n- one\n* [x] two\n1. three", "Must:\n- [ ] one\n- [x] two\n- [ ] three"},
+		{"nested", "- parent\n  - child", "- [ ] parent\n  - [ ] child"},
+		{"empty", "  \n", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Checklist(tt.in); got != tt.want {
+				t.Errorf("Checklist(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIssue(t *testing.T) {
+	created := time.Date(2025, 3, 4, 10, 30, 0, 0, time.UTC)
+	got := Issue(Detail{
+		Issue: &types.Issue{
+			ID: "bd-12", Title: "Fix login", Status: types.StatusInProgress, Priority: 1,
+			IssueType: types.TypeBug, Assignee: "alice",
+			Description:        "Tokens expire.",
+			AcceptanceCriteria: "Refreshes tokens",
+		},
+		Labels:       []string{"auth"},
+		Dependencies: []*types.Issue{{ID: "bd-3", Title: "Add refresh endpoint", Status: types.StatusClosed}},
+		Comments:     []*types.Comment{{Author: "bob", Text: "On it\n\nsoon", CreatedAt: created}},
+	})
+	want := "# bd-12: Fix login\n\n" +
+		"**Status:** in_progress · **Priority:** P1 · **Type:** bug · **Assignee:** alice\n\n" +
+		"**Labels:** `auth`\n\n" +
+		"## Description\n\nTokens expire.\n\n" +
+		"## Acceptance Criteria\n\n- [ ] Refreshes tokens\n\n" +
+		"## Depends On\n\n- [x] bd-3: Add refresh endpoint (closed)\n\n" +
+		"## Comments\n\n**bob** (2025-03-04 10:30):\n\n> On it\n>\n> soon\n"
+	if got != want {
+		t.Errorf("Issue() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestIssueLinks(t *testing.T) {
+	got := Issue(Detail{
+		Issue: &types.Issue{
+			ID: "bd-7", Title: "Login fails", Status: types.StatusClosed, Priority: 2, IssueType: types.TypeBug,
+			Links: []*types.IssueLink{{Type: types.DepDuplicates, ID: "bd-3", Title: "Fix login", Status: types.StatusOpen}},
+		},
+		Dependencies: []*types.Issue{
+			{ID: "bd-3", Title: "Fix login", Status: types.StatusOpen},
+			{ID: "bd-5", Title: "Session store", Status: types.StatusOpen},
+		},
+	})
+	want := "# bd-7: Login fails\n\n" +
+		"**Status:** closed · **Priority:** P2 · **Type:** bug\n\n" +
+		"## Depends On\n\n- [ ] bd-5: Session store (open)\n\n" +
+		"## Links\n\n- duplicate of bd-3: Fix login (open)\n"
+	if got != want {
+		t.Errorf("Issue() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestIssueList(t *testing.T) {
+	got := IssueList([]*types.Issue{
+		{ID: "bd-1", Title: "Pipes | in\ntitle", Status: types.StatusOpen, Priority: 0, IssueType: types.TypeTask},
+	})
+	if !strings.Contains(got, "| bd-1 | Pipes \\| in title | open | P0 | task |  |\n") {
+		t.Errorf("Expected an escaped table row, got:\n%s", got)
+	}
+	if IssueList(nil) != "_No issues_\n" {
+		t.Errorf("Expected a placeholder for no issues, got %q", IssueList(nil))
+	}
+}
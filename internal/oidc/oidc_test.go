@@ -0,0 +1,110 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// testProvider serves discovery and a JWKS with one RSA key, and signs tokens
+type testProvider struct {
+	t      *testing.T
+	key    *rsa.PrivateKey
+	server *httptest.Server
+}
+
+func newTestProvider(t *testing.T) *testProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &testProvider{t: t, key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"issuer": p.server.URL, "jwks_uri": p.server.URL + "/jwks"})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA", "kid": "k1", "use": "sig",
+			"n": base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+func (p *testProvider) sign(kid string, claims map[string]interface{}) string {
+	enc := func(v interface{}) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			p.t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signed := enc(map[string]string{"alg": "RS256", "kid": kid, "typ": "JWT"}) + "." + enc(claims)
+	digest := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, p.key, crypto.SHA256, digest[:])
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestVerify(t *testing.T) {
+	ctx := context.Background()
+	p := newTestProvider(t)
+	v, err := NewVerifier(Config{Issuer: p.server.URL, Audience: "beads", ActorClaim: "email"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := time.Now().Add(time.Hour).Unix()
+	valid := map[string]interface{}{"iss": p.server.URL, "sub": "u-123", "aud": []string{"other", "beads"}, "exp": exp, "email": "alice@example.com"}
+	claims, err := v.Verify(ctx, p.sign("k1", valid))
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if claims.Subject != "u-123" || claims.Actor != "alice@example.com" {
+		t.Errorf("Unexpected claims %+v", claims)
+	}
+
+	with := func(key string, value interface{}) map[string]interface{} {
+		c := map[string]interface{}{}
+		for k, v := range valid {
+			c[k] = v
+		}
+		c[key] = value
+		return c
+	}
+	tests := map[string]string{
+		"wrong issuer":   p.sign("k1", with("iss", "https://evil.example.com")),
+		"wrong audience": p.sign("k1", with("aud", "someone-else")),
+		"expired":        p.sign("k1", with("exp", time.Now().Add(-time.Hour).Unix())),
+		"unknown key":    p.sign("k2", valid),
+		"tampered":       p.sign("k1", valid)[:40] + "x" + p.sign("k1", valid)[41:],
+		"alg none":       base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`)) + ".e30.",
+	}
+	for name, token := range tests {
+		if _, err := v.Verify(ctx, token); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: expected ErrInvalidToken, got %v", name, err)
+		}
+	}
+}
+
+func TestLooksLikeJWT(t *testing.T) {
+	if !LooksLikeJWT("a.b.c") || LooksLikeJWT("bdk_0123abcd") {
+		t.Error("LooksLikeJWT misclassified a token")
+	}
+}
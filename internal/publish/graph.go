@@ -0,0 +1,144 @@
+package publish
+
+import (
+	"sort"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// Graph layout constants, in SVG user units
+const (
+	nodeWidth   = 190
+	nodeHeight  = 44
+	columnGap   = 70
+	rowGap      = 18
+	graphMargin = 20
+	titleChars  = 26
+)
+
+// graph is a layered drawing of the blocking dependencies: each issue sits one
+// column to the right of its deepest prerequisite
+type graph struct {
+	Width      int
+	Height     int
+	NodeWidth  int
+	NodeHeight int
+	Nodes      []*graphNode
+	Edges      []*graphEdge
+}
+
+type graphNode struct {
+	Issue *issueView
+	X, Y  int
+	Label string
+}
+
+type graphEdge struct {
+	X1, Y1, X2, Y2 int
+	Open           bool // the prerequisite is not yet closed
+}
+
+// layoutGraph places every issue that takes part in a blocking dependency.
+// Cycles are broken arbitrarily so that layout always terminates.
+func layoutGraph(issues []*issueView) *graph {
+	var nodes []*issueView
+	for _, iv := range issues {
+		if len(iv.DependsOn) > 0 || len(iv.Blocks) > 0 {
+			nodes = append(nodes, iv)
+		}
+	}
+	g := &graph{NodeWidth: nodeWidth, NodeHeight: nodeHeight}
+	if len(nodes) == 0 {
+		return g
+	}
+	byID := make(map[string]*issueView, len(nodes))
+	for _, iv := range nodes {
+		byID[iv.ID] = iv
+	}
+
+	rank := make(map[string]int, len(nodes))
+	visiting := make(map[string]bool)
+	var rankOf func(iv *issueView) int
+	rankOf = func(iv *issueView) int {
+		if r, ok := rank[iv.ID]; ok {
+			return r
+		}
+		if visiting[iv.ID] {
+			return 0
+		}
+		visiting[iv.ID] = true
+		r := 0
+		for _, dep := range iv.DependsOn {
+			if d, ok := byID[dep.ID]; ok {
+				if dr := rankOf(d) + 1; dr > r {
+					r = dr
+				}
+			}
+		}
+		visiting[iv.ID] = false
+		rank[iv.ID] = r
+		return r
+	}
+
+	var columns [][]*issueView
+	for _, iv := range nodes {
+		r := rankOf(iv)
+		for len(columns) <= r {
+			columns = append(columns, nil)
+		}
+		columns[r] = append(columns[r], iv)
+	}
+
+	placed := make(map[string]*graphNode, len(nodes))
+	tallest := 0
+	for c, col := range columns {
+		sort.SliceStable(col, func(i, j int) bool {
+			if col[i].Priority != col[j].Priority {
+				return col[i].Priority < col[j].Priority
+			}
+			return col[i].ID < col[j].ID
+		})
+		for row, iv := range col {
+			n := &graphNode{
+				Issue: iv,
+				X:     graphMargin + c*(nodeWidth+columnGap),
+				Y:     graphMargin + row*(nodeHeight+rowGap),
+				Label: truncate(iv.Title, titleChars),
+			}
+			g.Nodes = append(g.Nodes, n)
+			placed[iv.ID] = n
+		}
+		if len(col) > tallest {
+			tallest = len(col)
+		}
+	}
+
+	for _, n := range g.Nodes {
+		for _, dep := range n.Issue.DependsOn {
+			from, ok := placed[dep.ID]
+			if !ok {
+				continue
+			}
+			g.Edges = append(g.Edges, &graphEdge{
+				X1:   from.X + nodeWidth,
+				Y1:   from.Y + nodeHeight/2,
+				X2:   n.X,
+				Y2:   n.Y + nodeHeight/2,
+				Open: dep.Status != types.StatusClosed,
+			})
+		}
+	}
+
+	g.Width = 2*graphMargin + len(columns)*nodeWidth + (len(columns)-1)*columnGap
+	g.Height = 2*graphMargin + tallest*nodeHeight + (tallest-1)*rowGap
+	return g
+}
+
+// truncate shortens s to at most n runes, adding an ellipsis when cut
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
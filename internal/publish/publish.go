@@ -0,0 +1,352 @@
+// Package publish renders issues to a static HTML site.
+//
+// The generated site needs no server or JavaScript: an index of current work,
+// epic progress, a dependency graph drawn as inline SVG, and one page per
+// issue. It is meant to be pushed to GitHub Pages (or any static host) as a
+// read-only project status page.
+package publish
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+//go:embed templates/*.html templates/style.css
+var templateFS embed.FS
+
+// Site is a snapshot of the issue database to render
+type Site struct {
+	Title       string
+	GeneratedAt time.Time
+
+	// Issues carry their labels, dependency records, and comments
+	Issues []*types.Issue
+
+	// Ready holds the IDs of issues with no open blockers
+	Ready map[string]bool
+
+	// Redacted is set when sensitive text was withheld from the site
+	Redacted bool
+}
+
+// Load reads every issue along with its labels, dependencies, and comments
+func Load(ctx context.Context, store storage.Storage) (*Site, error) {
+	issues, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load issues: %w", err)
+	}
+	allDeps, err := store.GetAllDependencyRecords(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load dependencies: %w", err)
+	}
+	for _, issue := range issues {
+		issue.Dependencies = allDeps[issue.ID]
+		if issue.Labels, err = store.GetLabels(ctx, issue.ID); err != nil {
+			return nil, fmt.Errorf("failed to load labels for %s: %w", issue.ID, err)
+		}
+		if issue.Comments, err = store.GetIssueComments(ctx, issue.ID); err != nil {
+			return nil, fmt.Errorf("failed to load comments for %s: %w", issue.ID, err)
+		}
+	}
+
+	ready, err := store.GetReadyWork(ctx, types.WorkFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load ready work: %w", err)
+	}
+	site := &Site{
+		GeneratedAt: time.Now().UTC(),
+		Issues:      issues,
+		Ready:       make(map[string]bool, len(ready)),
+	}
+	for _, issue := range ready {
+		site.Ready[issue.ID] = true
+	}
+	return site, nil
+}
+
+// Redact removes descriptions, design notes, acceptance criteria, notes, and
+// comment text, leaving titles and structure
+func (s *Site) Redact() {
+	for _, issue := range s.Issues {
+		issue.Description = ""
+		issue.Design = ""
+		issue.AcceptanceCriteria = ""
+		issue.Notes = ""
+		for _, c := range issue.Comments {
+			c.Text = ""
+		}
+	}
+	s.Redacted = true
+}
+
+// Write renders the site into dir, creating it if needed, and returns the
+// number of files written. Existing files with other names are left alone;
+// pages for issues that no longer exist are removed.
+func (s *Site) Write(dir string) (int, error) {
+	tmpl, err := template.New("").Funcs(templateFuncs).ParseFS(templateFS, "templates/*.html")
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse templates: %w", err)
+	}
+	issuesDir := filepath.Join(dir, "issues")
+	if err := os.MkdirAll(issuesDir, 0750); err != nil {
+		return 0, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := removeStalePages(issuesDir, s.Issues); err != nil {
+		return 0, err
+	}
+
+	v := newView(s)
+	written := 0
+	render := func(name, page string, data interface{}) error {
+		var b strings.Builder
+		if err := tmpl.ExecuteTemplate(&b, page, data); err != nil {
+			return fmt.Errorf("failed to render %s: %w", name, err)
+		}
+		if err := writeFile(filepath.Join(dir, name), []byte(b.String())); err != nil {
+			return err
+		}
+		written++
+		return nil
+	}
+
+	css, err := templateFS.ReadFile("templates/style.css")
+	if err != nil {
+		return 0, err
+	}
+	if err := writeFile(filepath.Join(dir, "style.css"), css); err != nil {
+		return 0, err
+	}
+	written++
+
+	if err := render("index.html", "index.html", v.page("", "index")); err != nil {
+		return written, err
+	}
+	if err := render("epics.html", "epics.html", v.page("", "epics")); err != nil {
+		return written, err
+	}
+	if err := render("graph.html", "graph.html", v.page("", "graph")); err != nil {
+		return written, err
+	}
+	for _, iv := range v.Issues {
+		p := v.page("../", "")
+		p.Issue = iv
+		if err := render(filepath.Join("issues", iv.ID+".html"), "issue.html", p); err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+func writeFile(path string, data []byte) error {
+	// #nosec G306 - a published site is meant to be world-readable
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// removeStalePages deletes issue pages left over from deleted or renamed issues
+func removeStalePages(issuesDir string, issues []*types.Issue) error {
+	keep := make(map[string]bool, len(issues))
+	for _, issue := range issues {
+		keep[issue.ID+".html"] = true
+	}
+	entries, err := os.ReadDir(issuesDir)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", issuesDir, err)
+	}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".html") || keep[e.Name()] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(issuesDir, e.Name())); err != nil {
+			return fmt.Errorf("failed to remove stale page %s: %w", e.Name(), err)
+		}
+	}
+	return nil
+}
+
+// issueView is an issue with its relationships resolved for rendering
+type issueView struct {
+	*types.Issue
+	Ready     bool
+	DependsOn []*types.Issue // blocking prerequisites
+	Blocks    []*types.Issue // issues waiting on this one
+	Parent    *types.Issue
+	Children  []*types.Issue
+	Related   []*types.Issue // related and discovered-from, either direction
+	OpenDeps  int            // prerequisites that are not closed
+}
+
+// epicView summarizes an epic's progress
+type epicView struct {
+	*issueView
+	Closed  int
+	Total   int
+	Percent int
+}
+
+type view struct {
+	site   *Site
+	Issues []*issueView
+	byID   map[string]*issueView
+
+	InProgress []*issueView
+	Ready      []*issueView
+	Blocked    []*issueView
+	Backlog    []*issueView // open but neither ready nor blocked (e.g. children of open epics)
+	Closed     []*issueView
+	Epics      []*epicView
+	Graph      *graph
+	Counts     map[types.Status]int
+}
+
+// pageData is passed to every template
+type pageData struct {
+	*view
+	Title       string
+	GeneratedAt time.Time
+	Redacted    bool
+	Root        string // relative path to the site root
+	Nav         string
+	Issue       *issueView
+}
+
+func (v *view) page(root, nav string) *pageData {
+	return &pageData{
+		view:        v,
+		Title:       v.site.Title,
+		GeneratedAt: v.site.GeneratedAt,
+		Redacted:    v.site.Redacted,
+		Root:        root,
+		Nav:         nav,
+	}
+}
+
+func newView(s *Site) *view {
+	v := &view{site: s, byID: make(map[string]*issueView), Counts: make(map[types.Status]int)}
+	for _, issue := range s.Issues {
+		iv := &issueView{Issue: issue, Ready: s.Ready[issue.ID]}
+		v.Issues = append(v.Issues, iv)
+		v.byID[issue.ID] = iv
+		v.Counts[issue.Status]++
+	}
+	sort.Slice(v.Issues, func(i, j int) bool { return v.Issues[i].ID < v.Issues[j].ID })
+
+	for _, iv := range v.Issues {
+		for _, dep := range iv.Dependencies {
+			target, ok := v.byID[dep.DependsOnID]
+			if !ok {
+				continue
+			}
+			switch dep.Type {
+			case types.DepBlocks:
+				iv.DependsOn = append(iv.DependsOn, target.Issue)
+				target.Blocks = append(target.Blocks, iv.Issue)
+				if target.Status != types.StatusClosed {
+					iv.OpenDeps++
+				}
+			case types.DepParentChild:
+				iv.Parent = target.Issue
+				target.Children = append(target.Children, iv.Issue)
+			default:
+				iv.Related = append(iv.Related, target.Issue)
+				target.Related = append(target.Related, iv.Issue)
+			}
+		}
+	}
+
+	for _, iv := range v.Issues {
+		switch {
+		case iv.Status == types.StatusClosed:
+			v.Closed = append(v.Closed, iv)
+		case iv.Status == types.StatusInProgress:
+			v.InProgress = append(v.InProgress, iv)
+		case iv.Ready:
+			v.Ready = append(v.Ready, iv)
+		case iv.Status == types.StatusBlocked || iv.OpenDeps > 0:
+			v.Blocked = append(v.Blocked, iv)
+		default:
+			v.Backlog = append(v.Backlog, iv)
+		}
+		if iv.IssueType == types.TypeEpic {
+			ev := &epicView{issueView: iv, Total: len(iv.Children)}
+			for _, c := range iv.Children {
+				if c.Status == types.StatusClosed {
+					ev.Closed++
+				}
+			}
+			if ev.Total > 0 {
+				ev.Percent = ev.Closed * 100 / ev.Total
+			}
+			v.Epics = append(v.Epics, ev)
+		}
+	}
+
+	byPriority := func(list []*issueView) {
+		sort.SliceStable(list, func(i, j int) bool { return list[i].Priority < list[j].Priority })
+	}
+	byPriority(v.InProgress)
+	byPriority(v.Ready)
+	byPriority(v.Blocked)
+	byPriority(v.Backlog)
+	sort.SliceStable(v.Closed, func(i, j int) bool {
+		return closedTime(v.Closed[i].Issue).After(closedTime(v.Closed[j].Issue))
+	})
+	sort.SliceStable(v.Epics, func(i, j int) bool {
+		ci, cj := v.Epics[i].Status == types.StatusClosed, v.Epics[j].Status == types.StatusClosed
+		if ci != cj {
+			return !ci
+		}
+		return v.Epics[i].Priority < v.Epics[j].Priority
+	})
+
+	v.Graph = layoutGraph(v.Issues)
+	return v
+}
+
+func closedTime(issue *types.Issue) time.Time {
+	if issue.ClosedAt != nil {
+		return *issue.ClosedAt
+	}
+	return issue.UpdatedAt
+}
+
+var templateFuncs = template.FuncMap{
+	"statusClass": func(s types.Status) string {
+		return "status-" + strings.ReplaceAll(string(s), "_", "-")
+	},
+	"statusLabel": func(s types.Status) string {
+		return strings.ReplaceAll(string(s), "_", " ")
+	},
+	"date": func(t time.Time) string {
+		return t.UTC().Format("2006-01-02")
+	},
+	"datetime": func(v interface{}) string {
+		switch t := v.(type) {
+		case time.Time:
+			return t.UTC().Format("2006-01-02 15:04 UTC")
+		case *time.Time:
+			if t != nil {
+				return t.UTC().Format("2006-01-02 15:04 UTC")
+			}
+		}
+		return ""
+	},
+	"rows": func(root string, list []*issueView) map[string]interface{} {
+		return map[string]interface{}{"Root": root, "Rows": list}
+	},
+	"count": func(counts map[types.Status]int, s string) int {
+		return counts[types.Status(s)]
+	},
+}
@@ -0,0 +1,138 @@
+package publish
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func testSite() *Site {
+	now := time.Now()
+	closed := now.Add(-time.Hour)
+	return &Site{
+		Title:       "Test issues",
+		GeneratedAt: now,
+		Issues: []*types.Issue{
+			{ID: "bd-1", Title: "Epic", Status: types.StatusOpen, IssueType: types.TypeEpic, CreatedAt: now, UpdatedAt: now},
+			{ID: "bd-2", Title: "Foundation", Status: types.StatusClosed, IssueType: types.TypeTask, CreatedAt: now, UpdatedAt: now, ClosedAt: &closed,
+				Dependencies: []*types.Dependency{{IssueID: "bd-2", DependsOnID: "bd-1", Type: types.DepParentChild}}},
+			{ID: "bd-3", Title: "Build on <it>", Description: "secret plans", Status: types.StatusOpen, IssueType: types.TypeTask, CreatedAt: now, UpdatedAt: now,
+				Dependencies: []*types.Dependency{
+					{IssueID: "bd-3", DependsOnID: "bd-1", Type: types.DepParentChild},
+					{IssueID: "bd-3", DependsOnID: "bd-2", Type: types.DepBlocks},
+				},
+				Comments: []*types.Comment{{Author: "alice", Text: "comment text", CreatedAt: now}}},
+			{ID: "bd-4", Title: "Top", Status: types.StatusOpen, IssueType: types.TypeTask, CreatedAt: now, UpdatedAt: now,
+				Dependencies: []*types.Dependency{{IssueID: "bd-4", DependsOnID: "bd-3", Type: types.DepBlocks}}},
+		},
+		Ready: map[string]bool{"bd-1": true, "bd-3": true},
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func TestWriteSite(t *testing.T) {
+	dir := t.TempDir()
+	staleDir := filepath.Join(dir, "issues")
+	if err := os.MkdirAll(staleDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(staleDir, "bd-99.html"), []byte("old"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "CNAME"), []byte("example.com"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	written, err := testSite().Write(dir)
+	if err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if written != 8 {
+		t.Errorf("expected 8 files written, got %d", written)
+	}
+	if _, err := os.Stat(filepath.Join(staleDir, "bd-99.html")); !os.IsNotExist(err) {
+		t.Error("expected stale issue page to be removed")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "CNAME")); err != nil {
+		t.Error("expected unrelated files to be kept")
+	}
+
+	index := readFile(t, filepath.Join(dir, "index.html"))
+	if !strings.Contains(index, `href="issues/bd-3.html"`) || !strings.Contains(index, "Build on &lt;it&gt;") {
+		t.Error("index should link issues and escape titles")
+	}
+
+	epics := readFile(t, filepath.Join(dir, "epics.html"))
+	if !strings.Contains(epics, "1 of 2 children closed (50%)") {
+		t.Error("epics page should show child progress")
+	}
+
+	issue := readFile(t, filepath.Join(dir, "issues", "bd-3.html"))
+	for _, want := range []string{`href="../style.css"`, `href="../issues/bd-2.html"`, `href="../issues/bd-4.html"`, "secret plans", "comment text"} {
+		if !strings.Contains(issue, want) {
+			t.Errorf("issue page missing %q", want)
+		}
+	}
+
+	graph := readFile(t, filepath.Join(dir, "graph.html"))
+	if !strings.Contains(graph, "<svg") || strings.Count(graph, "<line") != 2 {
+		t.Error("graph page should draw both blocking edges")
+	}
+}
+
+func TestRedact(t *testing.T) {
+	dir := t.TempDir()
+	site := testSite()
+	site.Redact()
+	if _, err := site.Write(dir); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	issue := readFile(t, filepath.Join(dir, "issues", "bd-3.html"))
+	if strings.Contains(issue, "secret plans") || strings.Contains(issue, "comment text") {
+		t.Error("redacted site should not contain descriptions or comments")
+	}
+	if !strings.Contains(issue, "were not published") {
+		t.Error("redacted site should say content was withheld")
+	}
+}
+
+func TestLayoutGraphRanks(t *testing.T) {
+	v := newView(testSite())
+	g := v.Graph
+	x := make(map[string]int)
+	for _, n := range g.Nodes {
+		x[n.Issue.ID] = n.X
+	}
+	if _, ok := x["bd-1"]; ok {
+		t.Error("issues without blocking dependencies should not be drawn")
+	}
+	if !(x["bd-2"] < x["bd-3"] && x["bd-3"] < x["bd-4"]) {
+		t.Errorf("expected prerequisites left of dependents, got %v", x)
+	}
+	if v.byID["bd-4"].OpenDeps != 1 || len(v.Blocked) != 1 {
+		t.Error("bd-4 should be blocked by the open bd-3")
+	}
+}
+
+func TestLayoutGraphCycle(t *testing.T) {
+	a := &issueView{Issue: &types.Issue{ID: "a"}}
+	b := &issueView{Issue: &types.Issue{ID: "b"}}
+	a.DependsOn, a.Blocks = []*types.Issue{b.Issue}, []*types.Issue{b.Issue}
+	b.DependsOn, b.Blocks = []*types.Issue{a.Issue}, []*types.Issue{a.Issue}
+	g := layoutGraph([]*issueView{a, b})
+	if len(g.Nodes) != 2 || len(g.Edges) != 2 {
+		t.Errorf("expected 2 nodes and 2 edges, got %d and %d", len(g.Nodes), len(g.Edges))
+	}
+}
@@ -0,0 +1,239 @@
+// Package remote resolves references to issues in other beads workspaces.
+//
+// A reference names a remote and an issue ID in that remote, as in
+// "platform/bd-12". Remotes are registered by name with the base URL of the
+// workspace's bd serve API (for a workspace of a multi-workspace server,
+// including its /w/{name} prefix), and issues are fetched from its
+// GET /issues/{id}.
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// Ref is a reference to an issue in another workspace
+type Ref struct {
+	Remote string
+	ID     string
+}
+
+func (r Ref) String() string {
+	return r.Remote + "/" + r.ID
+}
+
+// Remote names follow workspace names; issue IDs are a prefix, a dash, and
+// a number or hash with optional child suffixes
+const (
+	namePattern = `[a-z0-9][a-z0-9_-]*`
+	idPattern   = `[A-Za-z0-9]+(?:-[A-Za-z0-9]+)*-[0-9A-Za-z]+(?:\.[0-9]+)*`
+)
+
+var (
+	nameRegexp = regexp.MustCompile(`^` + namePattern + `$`)
+	refPattern = regexp.MustCompile(`^(` + namePattern + `)/(` + idPattern + `)$`)
+
+	// textRefPattern finds references in prose, where they stand alone
+	// rather than at the end of a path
+	textRefPattern = regexp.MustCompile(`(?:^|[^\w/.-])(` + namePattern + `/` + idPattern + `)\b`)
+)
+
+// ParseRef parses "remote/issue-id"
+func ParseRef(s string) (Ref, bool) {
+	m := refPattern.FindStringSubmatch(s)
+	if m == nil {
+		return Ref{}, false
+	}
+	return Ref{Remote: m[1], ID: m[2]}, true
+}
+
+// IsRef reports whether s is a reference to another workspace's issue
+// rather than a local issue ID
+func IsRef(s string) bool {
+	_, ok := ParseRef(s)
+	return ok
+}
+
+// FindRefs returns the distinct references in text, in order of first
+// mention. Prose has plenty of things shaped like "a/b-1", so callers
+// should keep only refs to registered remotes.
+func FindRefs(text string) []Ref {
+	var refs []Ref
+	seen := make(map[Ref]bool)
+	for _, m := range textRefPattern.FindAllStringSubmatch(text, -1) {
+		ref, ok := ParseRef(m[1])
+		if !ok || seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// Remote is a workspace that references can point into
+type Remote struct {
+	Name  string `json:"name"`
+	URL   string `json:"url"`             // base URL of the workspace's bd serve API
+	Token string `json:"token,omitempty"` // API key, sent as a bearer token
+}
+
+// Registry resolves references against a set of remotes, caching what it
+// fetches so a tree that mentions an issue twice costs one request
+type Registry struct {
+	remotes map[string]Remote
+	client  *http.Client
+	ttl     time.Duration
+
+	mu    sync.Mutex
+	cache map[Ref]cached
+}
+
+type cached struct {
+	issue   *types.Issue
+	err     error
+	fetched time.Time
+}
+
+// NewRegistry returns a registry of remotes. Lookups are cached for ttl;
+// zero caches them for the registry's lifetime.
+func NewRegistry(remotes []Remote, ttl time.Duration) (*Registry, error) {
+	r := &Registry{
+		remotes: make(map[string]Remote),
+		client:  &http.Client{Timeout: 10 * time.Second},
+		ttl:     ttl,
+		cache:   make(map[Ref]cached),
+	}
+	for _, remote := range remotes {
+		if !nameRegexp.MatchString(remote.Name) {
+			return nil, fmt.Errorf("invalid remote name %q: use lowercase letters, digits, - and _", remote.Name)
+		}
+		u, err := url.Parse(remote.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("remote %s: invalid URL %q", remote.Name, remote.URL)
+		}
+		remote.URL = strings.TrimRight(remote.URL, "/")
+		r.remotes[remote.Name] = remote
+	}
+	return r, nil
+}
+
+// Has reports whether a remote is registered
+func (r *Registry) Has(name string) bool {
+	if r == nil {
+		return false
+	}
+	_, ok := r.remotes[name]
+	return ok
+}
+
+// Remotes returns the registered remotes in name order
+func (r *Registry) Remotes() []Remote {
+	if r == nil {
+		return nil
+	}
+	list := make([]Remote, 0, len(r.remotes))
+	for _, remote := range r.remotes {
+		list = append(list, remote)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// GetIssue fetches the issue a reference points to
+func (r *Registry) GetIssue(ctx context.Context, ref Ref) (*types.Issue, error) {
+	if !r.Has(ref.Remote) {
+		return nil, fmt.Errorf("no remote named %q", ref.Remote)
+	}
+	r.mu.Lock()
+	hit, ok := r.cache[ref]
+	r.mu.Unlock()
+	if ok && (r.ttl == 0 || time.Since(hit.fetched) < r.ttl) {
+		return hit.issue, hit.err
+	}
+
+	issue, err := r.fetch(ctx, ref)
+	r.mu.Lock()
+	r.cache[ref] = cached{issue: issue, err: err, fetched: time.Now()}
+	r.mu.Unlock()
+	return issue, err
+}
+
+// fetch asks the remote's API for an issue. Redirects from renamed IDs are
+// followed.
+func (r *Registry) fetch(ctx context.Context, ref Ref) (*types.Issue, error) {
+	remote := r.remotes[ref.Remote]
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, remote.URL+"/issues/"+url.PathEscape(ref.ID), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("API-Version", "1")
+	if remote.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+remote.Token)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ref, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		return nil, fmt.Errorf("%s: %w", ref, types.ParseAPIError(resp.StatusCode, body))
+	}
+
+	var issue *types.Issue
+	if err := json.NewDecoder(resp.Body).Decode(&issue); err != nil {
+		return nil, fmt.Errorf("%s: invalid response: %w", ref, err)
+	}
+	if issue == nil {
+		return nil, fmt.Errorf("%s: issue not found", ref)
+	}
+	return issue, nil
+}
+
+// ExpandTree adds the remote dependencies of each node in a dependency tree
+// below it, resolved through the registry. deps returns a local issue's
+// dependency records. Remote issues are leaves: their own dependencies
+// aren't followed. A remote that can't be reached still gets its node, with
+// Unreachable saying why.
+func (r *Registry) ExpandTree(ctx context.Context, tree []*types.TreeNode, deps func(issueID string) ([]*types.Dependency, error)) ([]*types.TreeNode, error) {
+	expanded := make([]*types.TreeNode, 0, len(tree))
+	for _, node := range tree {
+		expanded = append(expanded, node)
+		if node.Truncated || node.Remote != "" {
+			continue
+		}
+		records, err := deps(node.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, dep := range records {
+			ref, ok := ParseRef(dep.DependsOnID)
+			if !ok {
+				continue
+			}
+			child := &types.TreeNode{Depth: node.Depth + 1, DepType: dep.Type, Remote: ref.Remote}
+			if issue, err := r.GetIssue(ctx, ref); err != nil {
+				child.Unreachable = err.Error()
+			} else {
+				child.Issue = *issue
+			}
+			child.ID = ref.String()
+			expanded = append(expanded, child)
+		}
+	}
+	return expanded, nil
+}
@@ -0,0 +1,129 @@
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestParseRef(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Ref
+		ok   bool
+	}{
+		{"platform/bd-12", Ref{"platform", "bd-12"}, true},
+		{"docs/my-proj-a3f2.1", Ref{"docs", "my-proj-a3f2.1"}, true},
+		{"bd-12", Ref{}, false},
+		{"Platform/bd-12", Ref{}, false},
+		{"platform/bd", Ref{}, false},
+		{"a/b/bd-12", Ref{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseRef(tt.in)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseRef(%q) = %v, %v; want %v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestFindRefs(t *testing.T) {
+	text := "Needs platform/bd-12 (and platform/bd-12 again), docs/api-3.\n" +
+		"Not https://example.com/x/bd-1 or src/foo/bar-2, but feature/add-login is shaped like one."
+	want := []Ref{{"platform", "bd-12"}, {"docs", "api-3"}, {"feature", "add-login"}}
+	if got := FindRefs(text); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindRefs = %v, want %v", got, want)
+	}
+}
+
+func TestNewRegistryValidates(t *testing.T) {
+	for _, r := range []Remote{
+		{Name: "Bad Name", URL: "http://localhost"},
+		{Name: "ok", URL: "ftp://localhost"},
+		{Name: "ok", URL: "localhost:8080"},
+	} {
+		if _, err := NewRegistry([]Remote{r}, 0); err == nil {
+			t.Errorf("Expected %+v to be rejected", r)
+		}
+	}
+}
+
+func TestExpandTree(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/w/platform/issues/plat-1" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(&types.Issue{ID: "plat-1", Title: "Platform blocker", Status: types.StatusOpen, Priority: 1})
+	}))
+	defer srv.Close()
+
+	registry, err := NewRegistry([]Remote{{Name: "platform", URL: srv.URL + "/w/platform/", Token: "secret"}}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tree := []*types.TreeNode{
+		{Issue: types.Issue{ID: "bd-1"}},
+		{Issue: types.Issue{ID: "bd-2"}, Depth: 1},
+	}
+	deps := map[string][]*types.Dependency{
+		"bd-1": {
+			{IssueID: "bd-1", DependsOnID: "bd-2", Type: types.DepBlocks},
+			{IssueID: "bd-1", DependsOnID: "platform/plat-1", Type: types.DepBlocks},
+		},
+		"bd-2": {
+			{IssueID: "bd-2", DependsOnID: "platform/plat-1", Type: types.DepRelated},
+			{IssueID: "bd-2", DependsOnID: "platform/plat-9", Type: types.DepBlocks},
+			{IssueID: "bd-2", DependsOnID: "ghost/x-1", Type: types.DepBlocks},
+		},
+	}
+	expanded, err := registry.ExpandTree(context.Background(), tree, func(id string) ([]*types.Dependency, error) {
+		return deps[id], nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, node := range expanded {
+		line := node.ID
+		if node.Remote != "" {
+			line += " " + node.Title
+			if node.Unreachable != "" {
+				line += "!"
+			}
+		}
+		got = append(got, line)
+	}
+	want := []string{
+		"bd-1",
+		"platform/plat-1 Platform blocker",
+		"bd-2",
+		"platform/plat-1 Platform blocker",
+		"platform/plat-9 !",
+		"ghost/x-1 !",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExpandTree = %q, want %q", got, want)
+	}
+	if expanded[1].Depth != 1 || expanded[3].Depth != 2 {
+		t.Errorf("Expected remote nodes one level below their parent, got depths %d and %d", expanded[1].Depth, expanded[3].Depth)
+	}
+	// plat-1 is cached; plat-9 is fetched once; ghost is never requested
+	if n := requests.Load(); n != 2 {
+		t.Errorf("Expected 2 requests, got %d", n)
+	}
+}
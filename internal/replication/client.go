@@ -0,0 +1,92 @@
+package replication
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// Client talks to a peer's bd serve API: GET /replication for its state and
+// POST /replication/sync to exchange ops
+type Client struct {
+	url   string
+	token string
+	http  *http.Client
+}
+
+// NewClient returns a client for the bd serve API at baseURL (for a
+// workspace of a multi-workspace server, including its /w/{name} prefix).
+// A non-empty token is sent as a bearer token.
+func NewClient(baseURL, token string) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid URL %q", baseURL)
+	}
+	return &Client{
+		url:   strings.TrimRight(baseURL, "/"),
+		token: token,
+		http:  &http.Client{Timeout: 60 * time.Second},
+	}, nil
+}
+
+// State returns the peer's replica ID and vector clock
+func (c *Client) State(ctx context.Context) (*State, error) {
+	var state State
+	if err := c.do(ctx, http.MethodGet, "/replication", nil, &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// Exchange sends the peer the ops it lacks and returns its reply
+func (c *Client) Exchange(ctx context.Context, req *ExchangeRequest) (*ExchangeResponse, error) {
+	var resp ExchangeResponse
+	if err := c.do(ctx, http.MethodPost, "/replication/sync", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		return fmt.Errorf("%s %s: %w", method, path, types.ParseAPIError(resp.StatusCode, body))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%s %s: invalid response: %w", method, path, err)
+	}
+	return nil
+}
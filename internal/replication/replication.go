@@ -0,0 +1,212 @@
+// Package replication syncs workspaces that are edited offline.
+//
+// Each workspace is a replica with a random ID. Every change to an issue is
+// appended to the replica's oplog as an Op, numbered by a per-replica
+// sequence and stamped with a vector clock of the changes the replica had
+// seen. Two replicas sync by exchanging the ops the other hasn't seen, which
+// the vector clocks tell them, and applying them field by field: a field
+// takes the value of the last op to write it, where an op that saw another
+// comes after it, and of two concurrent ops the one whose replica had seen
+// more changes wins, then the later one, then the higher replica ID. Every
+// replica orders the same ops the same way, so replicas that have exchanged
+// all their ops agree.
+package replication
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// VectorClock maps replica IDs to the highest sequence number seen from each
+type VectorClock map[string]int64
+
+// Ordering is how two vector clocks relate
+type Ordering int
+
+const (
+	Equal      Ordering = iota
+	Before              // the first clock happened before the second
+	After               // the first clock happened after the second
+	Concurrent          // neither saw the other
+)
+
+// Compare orders clock c against other
+func (c VectorClock) Compare(other VectorClock) Ordering {
+	less, greater := false, false
+	for id, seq := range c {
+		if seq > other[id] {
+			greater = true
+		} else if seq < other[id] {
+			less = true
+		}
+	}
+	for id, seq := range other {
+		if _, ok := c[id]; !ok && seq > 0 {
+			less = true
+		}
+	}
+	switch {
+	case less && greater:
+		return Concurrent
+	case less:
+		return Before
+	case greater:
+		return After
+	}
+	return Equal
+}
+
+// Sum is the total number of changes the clock has seen. An op that saw
+// another always has a larger sum, which makes it a Lamport timestamp.
+func (c VectorClock) Sum() int64 {
+	var sum int64
+	for _, seq := range c {
+		sum += seq
+	}
+	return sum
+}
+
+// Copy returns an independent copy of the clock
+func (c VectorClock) Copy() VectorClock {
+	copied := make(VectorClock, len(c))
+	for id, seq := range c {
+		copied[id] = seq
+	}
+	return copied
+}
+
+// Op is one change to an issue, made on one replica
+type Op struct {
+	Replica string      `json:"replica"`
+	Seq     int64       `json:"seq"`
+	Clock   VectorClock `json:"clock"`
+	IssueID string      `json:"issue_id"`
+	Origin  string      `json:"origin,omitempty"` // the issue's created op, as Origin(replica, seq)
+	Kind    string      `json:"kind"`             // created, or the history revision kind of the change
+	Actor   string      `json:"actor"`
+
+	// Fields maps each field the op wrote to its new value, with label
+	// changes as LabelField(name) set to true or false. A created op holds
+	// the whole issue.
+	Fields    map[string]interface{} `json:"fields"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// Stamp identifies the op that last wrote a field, for deciding whether a
+// later op overwrites it
+type Stamp struct {
+	Replica   string      `json:"replica"`
+	Clock     VectorClock `json:"clock"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// Stamp returns the op's stamp
+func (op *Op) Stamp() Stamp {
+	return Stamp{Replica: op.Replica, Clock: op.Clock, CreatedAt: op.CreatedAt}
+}
+
+// Wins reports whether a write stamped s overwrites one stamped current.
+// Causally later writes always win; concurrent ones are ordered by clock
+// sum, wall time and replica ID, which extends causal order to a total
+// order every replica agrees on.
+func (s Stamp) Wins(current Stamp) bool {
+	switch s.Clock.Compare(current.Clock) {
+	case After:
+		return true
+	case Before:
+		return false
+	case Equal:
+		if s.Replica == current.Replica {
+			return false // the same op
+		}
+	}
+	if a, b := s.Clock.Sum(), current.Clock.Sum(); a != b {
+		return a > b
+	}
+	if !s.CreatedAt.Equal(current.CreatedAt) {
+		return s.CreatedAt.After(current.CreatedAt)
+	}
+	return s.Replica > current.Replica
+}
+
+// Origin identifies an issue by the op that created it. Replicas that
+// created issues offline may have used the same ID for different issues,
+// so a replica receiving an issue whose ID it already uses gives it a new
+// one, and ops find it by origin.
+func Origin(replica string, seq int64) string {
+	return fmt.Sprintf("%s:%d", replica, seq)
+}
+
+const labelPrefix = "label:"
+
+// LabelField is the op field for a label on the issue
+func LabelField(label string) string {
+	return labelPrefix + label
+}
+
+// ParseLabelField returns the label an op field is for, if it is one
+func ParseLabelField(field string) (string, bool) {
+	if !strings.HasPrefix(field, labelPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(field, labelPrefix), true
+}
+
+// SortCausal sorts ops so every op comes after the ops it saw. Ops from
+// one replica stay in sequence order.
+func SortCausal(ops []*Op) {
+	sort.SliceStable(ops, func(i, j int) bool {
+		a, b := ops[i], ops[j]
+		if sa, sb := a.Clock.Sum(), b.Clock.Sum(); sa != sb {
+			return sa < sb
+		}
+		if a.Replica != b.Replica {
+			return a.Replica < b.Replica
+		}
+		return a.Seq < b.Seq
+	})
+}
+
+// NewReplicaID returns a random replica ID
+func NewReplicaID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// State is a replica's ID and the ops it has seen
+type State struct {
+	Replica string      `json:"replica"`
+	Clock   VectorClock `json:"clock"`
+}
+
+// ExchangeRequest sends a peer the ops it lacks, along with the sender's
+// clock so the peer can reply with the ops the sender lacks
+type ExchangeRequest struct {
+	Replica string      `json:"replica"`
+	Clock   VectorClock `json:"clock"`
+	Ops     []*Op       `json:"ops"`
+}
+
+// ExchangeResponse reports what the peer did with the sent ops and returns
+// the ops the sender lacks
+type ExchangeResponse struct {
+	Replica string       `json:"replica"`
+	Result  *ApplyResult `json:"result"`
+	Ops     []*Op        `json:"ops"`
+}
+
+// ApplyResult counts what applying a batch of ops did
+type ApplyResult struct {
+	Applied   int `json:"applied"`   // ops that changed at least one field
+	Stale     int `json:"stale"`     // ops every field of which was already overwritten
+	Skipped   int `json:"skipped"`   // ops for issues this replica doesn't have
+	Conflicts int `json:"conflicts"` // fields written concurrently on both replicas
+	Renamed   int `json:"renamed"`   // new issues whose ID was taken here, created under a new one
+}
@@ -261,8 +261,8 @@ func (c *Client) Ready(args *ReadyArgs) (*Response, error) {
 }
 
 // Stats gets statistics via the daemon
-func (c *Client) Stats() (*Response, error) {
-	return c.Execute(OpStats, nil)
+func (c *Client) Stats(args *StatsArgs) (*Response, error) {
+	return c.Execute(OpStats, args)
 }
 
 // AddDependency adds a dependency via the daemon
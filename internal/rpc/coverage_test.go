@@ -72,7 +72,7 @@ func TestStats(t *testing.T) {
 	defer cleanup()
 	defer client.Close()
 
-	resp, err := client.Stats()
+	resp, err := client.Stats(nil)
 	if err != nil {
 		t.Fatalf("Stats failed: %v", err)
 	}
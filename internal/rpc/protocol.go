@@ -39,6 +39,7 @@ type Request struct {
 	Operation     string          `json:"operation"`
 	Args          json.RawMessage `json:"args"`
 	Actor         string          `json:"actor,omitempty"`
+	Session       string          `json:"session,omitempty"` // Agent session to tag changes with
 	RequestID     string          `json:"request_id,omitempty"`
 	Cwd           string          `json:"cwd,omitempty"`            // Working directory for database discovery
 	ClientVersion string          `json:"client_version,omitempty"` // Client version for compatibility checks
@@ -52,31 +53,39 @@ type Response struct {
 	Error   string          `json:"error,omitempty"`
 }
 
-// CreateArgs represents arguments for the create operation
+// CreateArgs represents arguments for the create operation.
+// The doc and enum tags describe the fields in the HTTP API's OpenAPI document.
 type CreateArgs struct {
-	ID                 string   `json:"id,omitempty"`
+	ID                 string   `json:"id,omitempty" doc:"Explicit ID; generated from the issue prefix when empty"`
 	Title              string   `json:"title"`
 	Description        string   `json:"description,omitempty"`
-	IssueType          string   `json:"issue_type"`
-	Priority           int      `json:"priority"`
+	IssueType          string   `json:"issue_type" enum:"bug,feature,task,epic,chore"`
+	Priority           int      `json:"priority" doc:"0 (highest) to 4"`
 	Design             string   `json:"design,omitempty"`
 	AcceptanceCriteria string   `json:"acceptance_criteria,omitempty"`
 	Assignee           string   `json:"assignee,omitempty"`
 	Labels             []string `json:"labels,omitempty"`
-	Dependencies       []string `json:"dependencies,omitempty"`
+	Dependencies       []string `json:"dependencies,omitempty" doc:"IDs this issue depends on, optionally as type:id"`
+	DueDate            string   `json:"due_date,omitempty" doc:"YYYY-MM-DD, RFC 3339, today, tomorrow, or +Nd/+Nw"`
+	StartDate          string   `json:"start_date,omitempty" doc:"Not ready work before this date; same formats as due_date"`
 }
 
-// UpdateArgs represents arguments for the update operation
+// UpdateArgs represents arguments for the update operation.
+// Over HTTP the ID comes from the URL path instead of the body.
 type UpdateArgs struct {
-	ID                 string  `json:"id"`
+	ID                 string  `json:"id" openapi:"path"`
 	Title              *string `json:"title,omitempty"`
 	Description        *string `json:"description,omitempty"`
-	Status             *string `json:"status,omitempty"`
-	Priority           *int    `json:"priority,omitempty"`
+	Status             *string `json:"status,omitempty" enum:"open,in_progress,blocked,closed"`
+	Priority           *int    `json:"priority,omitempty" doc:"0 (highest) to 4"`
 	Design             *string `json:"design,omitempty"`
 	AcceptanceCriteria *string `json:"acceptance_criteria,omitempty"`
 	Notes              *string `json:"notes,omitempty"`
 	Assignee           *string `json:"assignee,omitempty"`
+	DueDate            *string `json:"due_date,omitempty" doc:"YYYY-MM-DD, RFC 3339, today, tomorrow, or +Nd/+Nw; empty clears it"`
+	StartDate          *string `json:"start_date,omitempty" doc:"Same formats as due_date; empty clears it"`
+	Milestone          *string `json:"milestone,omitempty" doc:"Milestone (sprint) name; empty removes the issue from its milestone"`
+	ExpectedVersion    *int    `json:"expected_version,omitempty" doc:"Only update if the issue is still at this version; alternative to If-Match"`
 }
 
 // CloseArgs represents arguments for the close operation
@@ -96,7 +105,15 @@ type ListArgs struct {
 	Labels    []string `json:"labels,omitempty"`     // AND semantics
 	LabelsAny []string `json:"labels_any,omitempty"` // OR semantics
 	IDs       []string `json:"ids,omitempty"`        // Filter by specific issue IDs
+	DueBefore string   `json:"due_before,omitempty"` // Date, as for types.ParseDate
+	DueAfter  string   `json:"due_after,omitempty"`
+	Overdue   bool     `json:"overdue,omitempty"`
+	Milestone string   `json:"milestone,omitempty"`
+	Sort      string   `json:"sort,omitempty"` // As for types.ParseIssueSort, e.g. "priority,-updated_at"
+	Where     string   `json:"where,omitempty"` // Query language expression, as for types.ParseQuery
 	Limit     int      `json:"limit,omitempty"`
+
+	IncludeArchived bool `json:"include_archived,omitempty"`
 }
 
 // ShowArgs represents arguments for the show operation
@@ -106,10 +123,12 @@ type ShowArgs struct {
 
 // ReadyArgs represents arguments for the ready operation
 type ReadyArgs struct {
-	Assignee   string `json:"assignee,omitempty"`
-	Priority   *int   `json:"priority,omitempty"`
-	Limit      int    `json:"limit,omitempty"`
-	SortPolicy string `json:"sort_policy,omitempty"`
+	Assignee    string   `json:"assignee,omitempty"`
+	Priority    *int     `json:"priority,omitempty"`
+	MaxPriority *int     `json:"max_priority,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+	Limit       int      `json:"limit,omitempty"`
+	SortPolicy  string   `json:"sort_policy,omitempty"`
 }
 
 // DepAddArgs represents arguments for adding a dependency
@@ -151,9 +170,15 @@ type CommentListArgs struct {
 
 // CommentAddArgs represents arguments for adding a comment to an issue
 type CommentAddArgs struct {
-	ID     string `json:"id"`
-	Author string `json:"author"`
-	Text   string `json:"text"`
+	ID      string `json:"id"`
+	Author  string `json:"author"`
+	Text    string `json:"text"`
+	ReplyTo *int64 `json:"reply_to,omitempty"` // Comment on the same issue this one answers
+}
+
+// StatsArgs represents arguments for the stats operation
+type StatsArgs struct {
+	GroupBy string `json:"group_by,omitempty"` // Also count issues by this dimension and status (see types.StatGroupDimensions)
 }
 
 // EpicStatusArgs represents arguments for the epic status operation
@@ -245,6 +270,7 @@ type CompactResponse struct {
 	Reduction    string            `json:"reduction,omitempty"`
 	Duration     string            `json:"duration,omitempty"`
 	DryRun       bool              `json:"dry_run,omitempty"`
+	Purged       []string          `json:"purged,omitempty"`      // Expired trash deleted by a compact-all
 }
 
 // CompactResult represents the result of compacting a single issue
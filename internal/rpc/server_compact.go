@@ -90,13 +90,10 @@ func (s *Server) handleCompact(req *Request) Response {
 			}
 		}
 
-		if args.Tier == 1 {
-			err = compactor.CompactTier1(ctx, args.IssueID)
+		if args.Tier == 2 {
+			err = compactor.CompactTier2(ctx, args.IssueID)
 		} else {
-			return Response{
-				Success: false,
-				Error:   "Tier 2 compaction not yet implemented",
-			}
+			err = compactor.CompactTier1(ctx, args.IssueID)
 		}
 
 		if err != nil {
@@ -129,73 +126,55 @@ func (s *Server) handleCompact(req *Request) Response {
 	}
 
 	if args.All {
-		var candidates []*sqlite.CompactionCandidate
-
-		switch args.Tier {
-		case 1:
-			tier1, err := sqliteStore.GetTier1Candidates(ctx)
-			if err != nil {
-				return Response{
-					Success: false,
-					Error:   fmt.Sprintf("failed to get Tier 1 candidates: %v", err),
-				}
-			}
-			candidates = tier1
-		case 2:
-			tier2, err := sqliteStore.GetTier2Candidates(ctx)
-			if err != nil {
-				return Response{
-					Success: false,
-					Error:   fmt.Sprintf("failed to get Tier 2 candidates: %v", err),
-				}
-			}
-			candidates = tier2
-		default:
+		purged, err := compact.PurgeExpiredTrash(ctx, sqliteStore, args.DryRun)
+		if err != nil {
 			return Response{
 				Success: false,
-				Error:   fmt.Sprintf("invalid tier: %d (must be 1 or 2)", args.Tier),
+				Error:   fmt.Sprintf("failed to purge trash: %v", err),
 			}
 		}
 
-		if len(candidates) == 0 {
-			result := CompactResponse{
-				Success: true,
-				Results: []CompactResult{},
-			}
-			data, _ := json.Marshal(result)
+		if args.Tier != 1 && args.Tier != 2 {
 			return Response{
-				Success: true,
-				Data:    data,
+				Success: false,
+				Error:   fmt.Sprintf("invalid tier: %d (must be 1 or 2)", args.Tier),
 			}
 		}
 
-		issueIDs := make([]string, len(candidates))
-		for i, c := range candidates {
-			issueIDs[i] = c.IssueID
-		}
-
-		batchResults, err := compactor.CompactTier1Batch(ctx, issueIDs)
+		// A page of candidates at a time, so a large backlog isn't loaded whole
+		results := []CompactResult{}
+		err = sqliteStore.ForEachCandidatePage(ctx, args.Tier, func(page []*sqlite.CompactionCandidate) error {
+			var batchResults []*compact.Result
+			var err error
+			if args.Tier == 2 {
+				batchResults, err = compactor.CompactTier2Batch(ctx, compact.CandidateIDs(page))
+			} else {
+				batchResults, err = compactor.CompactTier1Batch(ctx, compact.CandidateIDs(page))
+			}
+			if err != nil {
+				return fmt.Errorf("batch compaction failed: %w", err)
+			}
+			for _, r := range batchResults {
+				result := CompactResult{
+					IssueID:       r.IssueID,
+					Success:       r.Err == nil,
+					OriginalSize:  r.OriginalSize,
+					CompactedSize: r.CompactedSize,
+				}
+				if r.Err != nil {
+					result.Error = r.Err.Error()
+				} else if r.OriginalSize > 0 && r.CompactedSize > 0 {
+					result.Reduction = fmt.Sprintf("%.1f%%", float64(r.OriginalSize-r.CompactedSize)/float64(r.OriginalSize)*100)
+				}
+				results = append(results, result)
+			}
+			return nil
+		})
 		if err != nil {
 			return Response{
 				Success: false,
-				Error:   fmt.Sprintf("batch compaction failed: %v", err),
-			}
-		}
-
-		results := make([]CompactResult, 0, len(batchResults))
-		for _, r := range batchResults {
-			result := CompactResult{
-				IssueID:       r.IssueID,
-				Success:       r.Err == nil,
-				OriginalSize:  r.OriginalSize,
-				CompactedSize: r.CompactedSize,
-			}
-			if r.Err != nil {
-				result.Error = r.Err.Error()
-			} else if r.OriginalSize > 0 && r.CompactedSize > 0 {
-				result.Reduction = fmt.Sprintf("%.1f%%", float64(r.OriginalSize-r.CompactedSize)/float64(r.OriginalSize)*100)
+				Error:   err.Error(),
 			}
-			results = append(results, result)
 		}
 
 		duration := time.Since(startTime)
@@ -204,6 +183,7 @@ func (s *Server) handleCompact(req *Request) Response {
 			Results:  results,
 			Duration: duration.String(),
 			DryRun:   args.DryRun,
+			Purged:   purged,
 		}
 		data, _ := json.Marshal(response)
 		return Response{
@@ -239,25 +219,23 @@ func (s *Server) handleCompactStats(req *Request) Response {
 
 	ctx := s.reqCtx(req)
 
-	tier1, err := sqliteStore.GetTier1Candidates(ctx)
-	if err != nil {
-		return Response{
-			Success: false,
-			Error:   fmt.Sprintf("failed to get Tier 1 candidates: %v", err),
-		}
-	}
-
-	tier2, err := sqliteStore.GetTier2Candidates(ctx)
-	if err != nil {
-		return Response{
-			Success: false,
-			Error:   fmt.Sprintf("failed to get Tier 2 candidates: %v", err),
+	var counts [3]int
+	for tier := 1; tier <= 2; tier++ {
+		err := sqliteStore.ForEachCandidatePage(ctx, tier, func(page []*sqlite.CompactionCandidate) error {
+			counts[tier] += len(page)
+			return nil
+		})
+		if err != nil {
+			return Response{
+				Success: false,
+				Error:   fmt.Sprintf("failed to get Tier %d candidates: %v", tier, err),
+			}
 		}
 	}
 
 	stats := CompactStatsData{
-		Tier1Candidates: len(tier1),
-		Tier2Candidates: len(tier2),
+		Tier1Candidates: counts[1],
+		Tier2Candidates: counts[2],
 		Tier1MinAge:     "30 days",
 		Tier2MinAge:     "90 days",
 		TotalClosed:     0, // Could query for this but not critical
@@ -98,8 +98,18 @@ func (s *Server) handleExport(req *Request) Response {
 	// Write JSONL
 	encoder := json.NewEncoder(tempFile)
 	exportedIDs := make([]string, 0, len(issues))
+	sqliteStore, _ := store.(*sqlite.SQLiteStorage)
 	for _, issue := range issues {
-		if err := encoder.Encode(issue); err != nil {
+		exported := issue
+		if sqliteStore != nil {
+			if exported, err = sqliteStore.EncryptForExport(issue); err != nil {
+				return Response{
+					Success: false,
+					Error:   fmt.Sprintf("failed to encrypt issue %s: %v", issue.ID, err),
+				}
+			}
+		}
+		if err := encoder.Encode(exported); err != nil {
 			return Response{
 				Success: false,
 				Error:   fmt.Sprintf("failed to encode issue %s: %v", issue.ID, err),
@@ -270,7 +280,11 @@ func (s *Server) triggerExport(ctx context.Context, store storage.Storage, dbPat
 
 	encoder := json.NewEncoder(file)
 	for _, issue := range allIssues {
-		if err := encoder.Encode(issue); err != nil {
+		exported, err := sqliteStore.EncryptForExport(issue)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt issue %s: %w", issue.ID, err)
+		}
+		if err := encoder.Encode(exported); err != nil {
 			return fmt.Errorf("failed to encode issue %s: %w", issue.ID, err)
 		}
 	}
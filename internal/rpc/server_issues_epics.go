@@ -4,7 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/imalsogreg/beads/internal/remote"
+	"github.com/imalsogreg/beads/internal/storage"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
 	"github.com/imalsogreg/beads/internal/types"
 )
 
@@ -59,6 +63,15 @@ func updatesFromArgs(a UpdateArgs) map[string]interface{} {
 	if a.Assignee != nil {
 		u["assignee"] = a.Assignee
 	}
+	if a.DueDate != nil {
+		u["due_date"] = *a.DueDate
+	}
+	if a.StartDate != nil {
+		u["start_date"] = *a.StartDate
+	}
+	if a.Milestone != nil {
+		u["milestone"] = *a.Milestone
+	}
 	return u
 }
 
@@ -96,6 +109,21 @@ func (s *Server) handleCreate(req *Request) Response {
 		Status:             types.StatusOpen,
 	}
 
+	now := time.Now()
+	var err error
+	if issue.DueDate, err = types.ParseOptionalDate(createArgs.DueDate, now); err != nil {
+		return Response{
+			Success: false,
+			Error:   fmt.Sprintf("invalid due date: %v", err),
+		}
+	}
+	if issue.StartDate, err = types.ParseOptionalDate(createArgs.StartDate, now); err != nil {
+		return Response{
+			Success: false,
+			Error:   fmt.Sprintf("invalid start date: %v", err),
+		}
+	}
+
 	ctx := s.reqCtx(req)
 	if err := store.CreateIssue(ctx, issue, s.reqActor(req)); err != nil {
 		return Response{
@@ -142,7 +170,7 @@ func (s *Server) handleCreate(req *Request) Response {
 		if !depType.IsValid() {
 			return Response{
 				Success: false,
-				Error:   fmt.Sprintf("invalid dependency type '%s' (valid: blocks, related, parent-child, discovered-from)", depType),
+				Error:   fmt.Sprintf("invalid dependency type '%s' (valid: blocks, related, relates-to, parent-child, discovered-from, duplicates, caused-by)", depType),
 			}
 		}
 
@@ -186,7 +214,20 @@ func (s *Server) handleUpdate(req *Request) Response {
 		return Response{Success: true}
 	}
 
-	if err := store.UpdateIssue(ctx, updateArgs.ID, updates, s.reqActor(req)); err != nil {
+	var err error
+	if updateArgs.ExpectedVersion != nil {
+		sqliteStore, ok := store.(*sqlite.SQLiteStorage)
+		if !ok {
+			return Response{
+				Success: false,
+				Error:   "conditional updates require SQLite backend",
+			}
+		}
+		err = sqliteStore.UpdateIssueIfVersion(ctx, updateArgs.ID, updates, s.reqActor(req), *updateArgs.ExpectedVersion)
+	} else {
+		err = store.UpdateIssue(ctx, updateArgs.ID, updates, s.reqActor(req))
+	}
+	if err != nil {
 		return Response{
 			Success: false,
 			Error:   fmt.Sprintf("failed to update issue: %v", err),
@@ -253,7 +294,8 @@ func (s *Server) handleList(req *Request) Response {
 	store := s.storage
 
 	filter := types.IssueFilter{
-		Limit: listArgs.Limit,
+		Limit:           listArgs.Limit,
+		ExcludeArchived: !listArgs.IncludeArchived,
 	}
 	if listArgs.Status != "" {
 		status := types.Status(listArgs.Status)
@@ -287,6 +329,36 @@ func (s *Server) handleList(req *Request) Response {
 			filter.IDs = ids
 		}
 	}
+	now := time.Now()
+	var err error
+	if filter.DueBefore, err = types.ParseOptionalDate(listArgs.DueBefore, now); err != nil {
+		return Response{
+			Success: false,
+			Error:   fmt.Sprintf("invalid due_before: %v", err),
+		}
+	}
+	if filter.DueAfter, err = types.ParseOptionalDate(listArgs.DueAfter, now); err != nil {
+		return Response{
+			Success: false,
+			Error:   fmt.Sprintf("invalid due_after: %v", err),
+		}
+	}
+	filter.Overdue = listArgs.Overdue
+	if listArgs.Milestone != "" {
+		filter.Milestone = &listArgs.Milestone
+	}
+	if filter.Sort, err = types.ParseIssueSort(listArgs.Sort); err != nil {
+		return Response{
+			Success: false,
+			Error:   fmt.Sprintf("invalid sort: %v", err),
+		}
+	}
+	if filter.Query, err = types.ParseQuery(listArgs.Where, time.Now()); err != nil {
+		return Response{
+			Success: false,
+			Error:   fmt.Sprintf("invalid query: %v", err),
+		}
+	}
 
 	// Guard against excessive ID lists to avoid SQLite parameter limits
 	const maxIDs = 1000
@@ -339,24 +411,41 @@ func (s *Server) handleShow(req *Request) Response {
 		}
 	}
 
-	// Populate labels, dependencies, and dependents
+	// Populate labels, dependencies, dependents, links, and watchers
+	if links, _ := storage.GetIssueLinks(ctx, store, issue.ID); len(links) > 0 {
+		issue.Links = links
+	}
+	if sqliteStore, ok := store.(*sqlite.SQLiteStorage); ok {
+		issue.Watchers, _ = sqliteStore.GetWatchers(ctx, issue.ID)
+	}
 	labels, _ := store.GetLabels(ctx, issue.ID)
 	deps, _ := store.GetDependencies(ctx, issue.ID)
 	dependents, _ := store.GetDependents(ctx, issue.ID)
 
+	// Dependencies on other workspaces' issues have no local issue to list
+	var remoteDeps []*types.Dependency
+	records, _ := store.GetDependencyRecords(ctx, issue.ID)
+	for _, dep := range records {
+		if remote.IsRef(dep.DependsOnID) {
+			remoteDeps = append(remoteDeps, dep)
+		}
+	}
+
 	// Create detailed response with related data
 	type IssueDetails struct {
 		*types.Issue
-		Labels       []string       `json:"labels,omitempty"`
-		Dependencies []*types.Issue `json:"dependencies,omitempty"`
-		Dependents   []*types.Issue `json:"dependents,omitempty"`
+		Labels             []string            `json:"labels,omitempty"`
+		Dependencies       []*types.Issue      `json:"dependencies,omitempty"`
+		Dependents         []*types.Issue      `json:"dependents,omitempty"`
+		RemoteDependencies []*types.Dependency `json:"remote_dependencies,omitempty"`
 	}
 
 	details := &IssueDetails{
-		Issue:        issue,
-		Labels:       labels,
-		Dependencies: deps,
-		Dependents:   dependents,
+		Issue:              issue,
+		Labels:             labels,
+		Dependencies:       deps,
+		Dependents:         dependents,
+		RemoteDependencies: remoteDeps,
 	}
 
 	data, _ := json.Marshal(details)
@@ -378,10 +467,12 @@ func (s *Server) handleReady(req *Request) Response {
 	store := s.storage
 
 	wf := types.WorkFilter{
-		Status:     types.StatusOpen,
-		Priority:   readyArgs.Priority,
-		Limit:      readyArgs.Limit,
-		SortPolicy: types.SortPolicy(readyArgs.SortPolicy),
+		Status:      types.StatusOpen,
+		Priority:    readyArgs.Priority,
+		MaxPriority: readyArgs.MaxPriority,
+		Labels:      readyArgs.Labels,
+		Limit:       readyArgs.Limit,
+		SortPolicy:  types.SortPolicy(readyArgs.SortPolicy),
 	}
 	if readyArgs.Assignee != "" {
 		wf.Assignee = &readyArgs.Assignee
@@ -404,6 +495,22 @@ func (s *Server) handleReady(req *Request) Response {
 }
 
 func (s *Server) handleStats(req *Request) Response {
+	var statsArgs StatsArgs
+	if len(req.Args) > 0 {
+		if err := json.Unmarshal(req.Args, &statsArgs); err != nil {
+			return Response{
+				Success: false,
+				Error:   fmt.Sprintf("invalid stats args: %v", err),
+			}
+		}
+	}
+	if statsArgs.GroupBy != "" && !types.IsValidStatGroup(statsArgs.GroupBy) {
+		return Response{
+			Success: false,
+			Error:   fmt.Sprintf("invalid group_by %q (valid: %s)", statsArgs.GroupBy, strings.Join(types.StatGroupDimensions, ", ")),
+		}
+	}
+
 	store := s.storage
 
 	ctx := s.reqCtx(req)
@@ -414,6 +521,15 @@ func (s *Server) handleStats(req *Request) Response {
 			Error:   fmt.Sprintf("failed to get statistics: %v", err),
 		}
 	}
+	if statsArgs.GroupBy != "" {
+		if stats.Groups, err = store.GetGroupedStatistics(ctx, statsArgs.GroupBy); err != nil {
+			return Response{
+				Success: false,
+				Error:   fmt.Sprintf("failed to get statistics: %v", err),
+			}
+		}
+		stats.GroupBy = statsArgs.GroupBy
+	}
 
 	data, _ := json.Marshal(stats)
 	return Response{
@@ -120,7 +120,13 @@ func (s *Server) handleCommentAdd(req *Request) Response {
 	store := s.storage
 
 	ctx := s.reqCtx(req)
-	comment, err := store.AddIssueComment(ctx, commentArgs.ID, commentArgs.Author, commentArgs.Text)
+	var comment *types.Comment
+	var err error
+	if commentArgs.ReplyTo != nil {
+		comment, err = store.ReplyToComment(ctx, commentArgs.ID, *commentArgs.ReplyTo, commentArgs.Author, commentArgs.Text)
+	} else {
+		comment, err = store.AddIssueComment(ctx, commentArgs.ID, commentArgs.Author, commentArgs.Text)
+	}
 	if err != nil {
 		return Response{
 			Success: false,
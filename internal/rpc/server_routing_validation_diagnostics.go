@@ -11,6 +11,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
 	"github.com/imalsogreg/beads/internal/types"
 	"golang.org/x/mod/semver"
 )
@@ -134,6 +135,18 @@ func (s *Server) handleRequest(req *Request) Response {
 		}
 	}
 
+	// A request tagged with an agent session must be the session actor's,
+	// while it's running
+	if req.Session != "" {
+		if err := s.checkSession(req); err != nil {
+			s.metrics.RecordError(req.Operation)
+			return Response{
+				Success: false,
+				Error:   err.Error(),
+			}
+		}
+	}
+
 	// Check for stale JSONL and auto-import if needed (bd-160)
 	// Skip for write operations that will trigger export anyway
 	// Skip for import operation itself to avoid recursion
@@ -216,15 +229,37 @@ func (s *Server) handleRequest(req *Request) Response {
 }
 
 // Adapter helpers
-func (s *Server) reqCtx(_ *Request) context.Context {
+
+// reqCtx returns the context to handle a request with, tagged with the
+// request's agent session if it names one
+func (s *Server) reqCtx(req *Request) context.Context {
+	if req != nil && req.Session != "" {
+		return sqlite.WithSession(context.Background(), req.Session)
+	}
 	return context.Background()
 }
 
+// checkSession validates the agent session a request names
+func (s *Server) checkSession(req *Request) error {
+	sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage)
+	if !ok {
+		return fmt.Errorf("agent sessions require SQLite backend")
+	}
+	return sqliteStore.CheckSession(context.Background(), req.Session, s.reqActor(req))
+}
+
+// reqActor returns the request's actor, resolved to a registered actor's
+// name when it is one's alias or email
 func (s *Server) reqActor(req *Request) string {
-	if req != nil && req.Actor != "" {
-		return req.Actor
+	if req == nil || req.Actor == "" {
+		return "daemon"
+	}
+	if sqliteStore, ok := s.storage.(*sqlite.SQLiteStorage); ok {
+		if resolved, err := sqliteStore.ResolveActor(context.Background(), req.Actor); err == nil && resolved != "" {
+			return resolved
+		}
 	}
-	return "daemon"
+	return req.Actor
 }
 
 // Handler implementations
@@ -0,0 +1,150 @@
+package schedule
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// Write renders s in the given format
+func Write(w io.Writer, s *Schedule, format string) error {
+	switch format {
+	case FormatMermaidGantt:
+		return WriteMermaidGantt(w, s)
+	case FormatCSV:
+		return WriteCSV(w, s)
+	default:
+		return fmt.Errorf("unknown schedule format %q (use %s)", format, strings.Join(Formats, " or "))
+	}
+}
+
+// WriteMermaidGantt renders s as a Mermaid Gantt chart with a section per
+// epic. Work in progress is marked active and late work critical.
+func WriteMermaidGantt(w io.Writer, s *Schedule) error {
+	var b strings.Builder
+	b.WriteString("gantt\n")
+	fmt.Fprintf(&b, "  title %s\n", ganttEscape(s.Title))
+	b.WriteString("  dateFormat YYYY-MM-DD\n")
+	b.WriteString("  axisFormat %b %d\n")
+
+	// Sections in the order their first item is scheduled
+	var sections []string
+	bySection := make(map[string][]*Item)
+	for _, item := range s.Items {
+		if _, ok := bySection[item.Section]; !ok {
+			sections = append(sections, item.Section)
+		}
+		bySection[item.Section] = append(bySection[item.Section], item)
+	}
+	for _, section := range sections {
+		fmt.Fprintf(&b, "\n  section %s\n", ganttEscape(section))
+		for _, item := range bySection[section] {
+			var tags []string
+			if item.Late {
+				tags = append(tags, "crit")
+			}
+			if item.Issue.Status == types.StatusInProgress {
+				tags = append(tags, "active")
+			}
+			tags = append(tags, ganttTaskID(item.Issue.ID), item.Start.Format("2006-01-02"), fmt.Sprintf("%dd", item.Days))
+			fmt.Fprintf(&b, "  %s %s :%s\n", item.Issue.ID, ganttEscape(item.Issue.Title), strings.Join(tags, ", "))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ganttTaskID turns an issue ID into a Mermaid task ID, which can't contain
+// hyphens
+func ganttTaskID(id string) string {
+	return "t_" + strings.NewReplacer("-", "_", ".", "_").Replace(id)
+}
+
+// ganttEscape keeps text from ending a Gantt task name or starting a comment
+func ganttEscape(s string) string {
+	return strings.NewReplacer(
+		":", " -",
+		";", ",",
+		"#", "",
+		"%%", "%",
+		"\n", " ",
+	).Replace(s)
+}
+
+// csvHeader names the columns of WriteCSV
+var csvHeader = []string{"id", "title", "section", "status", "priority", "assignee", "estimated_minutes", "start", "finish", "days", "due", "late", "blocked_by"}
+
+// WriteCSV renders s as CSV with a row per item. finish is the last day of
+// work, and estimated_minutes is empty for unestimated issues.
+func WriteCSV(w io.Writer, s *Schedule) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, item := range s.Items {
+		issue := item.Issue
+		estimate := ""
+		if issue.EstimatedMinutes != nil {
+			estimate = strconv.Itoa(*issue.EstimatedMinutes)
+		}
+		due := ""
+		if issue.DueDate != nil {
+			due = issue.DueDate.Local().Format("2006-01-02")
+		}
+		record := []string{
+			issue.ID, issue.Title, item.Section, string(issue.Status), strconv.Itoa(issue.Priority), issue.Assignee, estimate,
+			item.Start.Format("2006-01-02"), item.Finish.AddDate(0, 0, -1).Format("2006-01-02"), strconv.Itoa(item.Days),
+			due, strconv.FormatBool(item.Late), strings.Join(item.BlockedBy, " "),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Table renders s as a plain-text timeline, one line per item
+func (s *Schedule) Table() string {
+	var b strings.Builder
+	if len(s.Items) == 0 {
+		fmt.Fprintf(&b, "%s: no unfinished work\n", s.Title)
+		return b.String()
+	}
+	fmt.Fprintf(&b, "%s: %d issue(s), %s to %s\n\n", s.Title, len(s.Items),
+		s.Start.Format("2006-01-02"), s.Finish.AddDate(0, 0, -1).Format("2006-01-02"))
+
+	width := 0
+	for _, item := range s.Items {
+		if n := len(item.Issue.ID); n > width {
+			width = n
+		}
+	}
+	for _, item := range s.Items {
+		var notes []string
+		if item.Unestimated {
+			notes = append(notes, "unestimated")
+		}
+		if item.Late {
+			notes = append(notes, "late, due "+item.Issue.DueDate.Local().Format("2006-01-02"))
+		}
+		if len(item.BlockedBy) > 0 {
+			notes = append(notes, "after "+strings.Join(item.BlockedBy, ", "))
+		}
+		line := fmt.Sprintf("  %-*s  %s → %s  %3dd  %s", width, item.Issue.ID,
+			item.Start.Format("2006-01-02"), item.Finish.AddDate(0, 0, -1).Format("2006-01-02"), item.Days, item.Issue.Title)
+		if len(notes) > 0 {
+			line += " (" + strings.Join(notes, "; ") + ")"
+		}
+		b.WriteString(line + "\n")
+	}
+	if s.Late > 0 {
+		fmt.Fprintf(&b, "\n%d issue(s) projected to finish after their due date\n", s.Late)
+	}
+	return b.String()
+}
@@ -0,0 +1,266 @@
+// Package schedule projects when unfinished work will start and finish, for
+// sharing timelines as a Mermaid Gantt chart or CSV.
+//
+// Issues are ordered by their 'blocks' dependencies and each starts on the
+// latest of the schedule's first day, its own start date, and the day its
+// last blocker finishes; blockers outside the schedule aren't waited on. An
+// issue takes its estimate in working days of Options.HoursPerDay, rounded
+// up; unestimated issues take one day and are flagged. Work runs in parallel
+// wherever dependencies allow: nobody's capacity is modelled, and days are
+// calendar days.
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// Output formats
+const (
+	FormatMermaidGantt = "mermaid-gantt"
+	FormatCSV          = "csv"
+)
+
+// Formats lists the rendered output formats
+var Formats = []string{FormatMermaidGantt, FormatCSV}
+
+// ErrCycle is returned when the dependencies to schedule have a cycle
+var ErrCycle = errors.New("dependency cycle")
+
+// DefaultHoursPerDay is the working day estimates are divided into
+const DefaultHoursPerDay = 8
+
+// Options control a projection
+type Options struct {
+	From        time.Time // First day work can start; defaults to today
+	HoursPerDay int       // Working hours in a day; defaults to DefaultHoursPerDay
+}
+
+// Item is an issue's place in the schedule
+type Item struct {
+	Issue       *types.Issue `json:"issue"`
+	Section     string       `json:"section"` // Title of the nearest epic above it, for grouping
+	Start       time.Time    `json:"start"`
+	Finish      time.Time    `json:"finish"` // Exclusive: the day after its last day
+	Days        int          `json:"days"`
+	Unestimated bool         `json:"unestimated"`
+	BlockedBy   []string     `json:"blocked_by,omitempty"` // Blockers in the schedule
+	Late        bool         `json:"late"`                 // Finishes after its due date
+}
+
+// Schedule is the projected timeline of a set of issues
+type Schedule struct {
+	EpicID string    `json:"epic_id,omitempty"`
+	Title  string    `json:"title"`
+	Start  time.Time `json:"start"`
+	Finish time.Time `json:"finish"` // When the last item finishes
+	Items  []*Item   `json:"items"`  // In dependency order: every item comes after its blockers
+	Late   int       `json:"late"`
+}
+
+// ForEpic projects the unfinished work under an epic: its descendants via
+// parent-child dependencies, recursively, except nested epics, which become
+// sections
+func ForEpic(ctx context.Context, store storage.Storage, epicID string, opts Options) (*Schedule, error) {
+	epic, err := store.GetIssue(ctx, epicID)
+	if err != nil {
+		return nil, err
+	}
+	if epic == nil {
+		return nil, fmt.Errorf("issue %s not found", epicID)
+	}
+
+	var issues []*types.Issue
+	sections := make(map[string]string)
+	visited := map[string]bool{epic.ID: true}
+	var walk func(parent *types.Issue, section string) error
+	walk = func(parent *types.Issue, section string) error {
+		children, err := store.GetChildren(ctx, parent.ID)
+		if err != nil {
+			return err
+		}
+		for _, child := range children {
+			if visited[child.ID] {
+				continue
+			}
+			visited[child.ID] = true
+			childSection := section
+			if child.IssueType == types.TypeEpic {
+				childSection = child.Title
+			} else if child.Status != types.StatusClosed {
+				issues = append(issues, child)
+				sections[child.ID] = section
+			}
+			if err := walk(child, childSection); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(epic, epic.Title); err != nil {
+		return nil, err
+	}
+
+	s, err := Project(ctx, store, issues, sections, opts)
+	if err != nil {
+		return nil, err
+	}
+	s.EpicID, s.Title = epic.ID, fmt.Sprintf("%s (%s)", epic.Title, epic.ID)
+	return s, nil
+}
+
+// ForAll projects every unfinished issue that isn't an epic
+func ForAll(ctx context.Context, store storage.Storage, opts Options) (*Schedule, error) {
+	all, err := store.SearchIssues(ctx, "", types.IssueFilter{ExcludeArchived: true})
+	if err != nil {
+		return nil, err
+	}
+	var issues []*types.Issue
+	for _, issue := range all {
+		if issue.Status != types.StatusClosed && issue.IssueType != types.TypeEpic {
+			issues = append(issues, issue)
+		}
+	}
+	s, err := Project(ctx, store, issues, nil, opts)
+	if err != nil {
+		return nil, err
+	}
+	s.Title = "All unfinished work"
+	return s, nil
+}
+
+// Project schedules issues, ordered by the 'blocks' dependencies among them.
+// sections names each issue's section; issues without one go in "Work". It
+// fails if the dependencies have a cycle.
+func Project(ctx context.Context, store storage.Storage, issues []*types.Issue, sections map[string]string, opts Options) (*Schedule, error) {
+	if opts.From.IsZero() {
+		opts.From = time.Now()
+	}
+	if opts.HoursPerDay <= 0 {
+		opts.HoursPerDay = DefaultHoursPerDay
+	}
+	first := day(opts.From)
+
+	byID := make(map[string]*types.Issue, len(issues))
+	for _, issue := range issues {
+		byID[issue.ID] = issue
+	}
+	blockers := make(map[string][]string)
+	dependents := make(map[string][]string)
+	for _, issue := range issues {
+		deps, err := store.GetDependencyRecords(ctx, issue.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get dependencies of %s: %w", issue.ID, err)
+		}
+		for _, dep := range deps {
+			if dep.Type != types.DepBlocks || byID[dep.DependsOnID] == nil {
+				continue
+			}
+			blockers[issue.ID] = append(blockers[issue.ID], dep.DependsOnID)
+			dependents[dep.DependsOnID] = append(dependents[dep.DependsOnID], issue.ID)
+		}
+	}
+
+	// Kahn's algorithm, taking the most urgent ready issue first
+	waiting := make(map[string]int, len(issues))
+	var ready []*types.Issue
+	for _, issue := range issues {
+		waiting[issue.ID] = len(blockers[issue.ID])
+		if waiting[issue.ID] == 0 {
+			ready = append(ready, issue)
+		}
+	}
+	s := &Schedule{Start: first, Finish: first, Items: []*Item{}}
+	items := make(map[string]*Item, len(issues))
+	for len(ready) > 0 {
+		sort.Slice(ready, func(i, j int) bool { return urgent(ready[i], ready[j]) })
+		issue := ready[0]
+		ready = ready[1:]
+
+		item := &Item{Issue: issue, Section: "Work", Start: first, BlockedBy: blockers[issue.ID]}
+		if section := sections[issue.ID]; section != "" {
+			item.Section = section
+		}
+		if issue.StartDate != nil && issue.Status != types.StatusInProgress {
+			if start := day(*issue.StartDate); start.After(item.Start) {
+				item.Start = start
+			}
+		}
+		for _, id := range item.BlockedBy {
+			if finish := items[id].Finish; finish.After(item.Start) {
+				item.Start = finish
+			}
+		}
+		item.Days, item.Unestimated = days(issue, opts.HoursPerDay)
+		item.Finish = item.Start.AddDate(0, 0, item.Days)
+		if issue.DueDate != nil && item.Finish.After(day(*issue.DueDate).AddDate(0, 0, 1)) {
+			item.Late = true
+			s.Late++
+		}
+		if item.Finish.After(s.Finish) {
+			s.Finish = item.Finish
+		}
+		items[issue.ID] = item
+		s.Items = append(s.Items, item)
+
+		for _, id := range dependents[issue.ID] {
+			waiting[id]--
+			if waiting[id] == 0 {
+				ready = append(ready, byID[id])
+			}
+		}
+	}
+
+	if len(s.Items) < len(issues) {
+		var stuck []string
+		for _, issue := range issues {
+			if items[issue.ID] == nil {
+				stuck = append(stuck, issue.ID)
+			}
+		}
+		sort.Strings(stuck)
+		return nil, fmt.Errorf("%w among %s", ErrCycle, strings.Join(stuck, ", "))
+	}
+	return s, nil
+}
+
+// days returns how many working days an issue takes, and whether it has no
+// estimate to go on
+func days(issue *types.Issue, hoursPerDay int) (int, bool) {
+	if issue.EstimatedMinutes == nil || *issue.EstimatedMinutes <= 0 {
+		return 1, issue.EstimatedMinutes == nil
+	}
+	perDay := hoursPerDay * 60
+	return (*issue.EstimatedMinutes + perDay - 1) / perDay, false
+}
+
+// urgent orders ready issues: work in progress first, then by priority,
+// due date, and ID
+func urgent(a, b *types.Issue) bool {
+	if (a.Status == types.StatusInProgress) != (b.Status == types.StatusInProgress) {
+		return a.Status == types.StatusInProgress
+	}
+	if a.Priority != b.Priority {
+		return a.Priority < b.Priority
+	}
+	if (a.DueDate == nil) != (b.DueDate == nil) {
+		return a.DueDate != nil
+	}
+	if a.DueDate != nil && !a.DueDate.Equal(*b.DueDate) {
+		return a.DueDate.Before(*b.DueDate)
+	}
+	return a.ID < b.ID
+}
+
+// day returns the start of t's day in local time
+func day(t time.Time) time.Time {
+	y, m, d := t.Local().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
@@ -0,0 +1,176 @@
+package schedule
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage/memory"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestForEpic(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New("")
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+
+	from := time.Date(2025, 11, 3, 0, 0, 0, 0, time.Local)
+	date := func(d int) *time.Time { t := from.AddDate(0, 0, d); return &t }
+	mins := func(m int) *int { return &m }
+	create := func(issue *types.Issue) *types.Issue {
+		t.Helper()
+		issue.Status = types.StatusOpen
+		if issue.IssueType == "" {
+			issue.IssueType = types.TypeTask
+		}
+		if err := store.CreateIssue(ctx, issue, "test-user"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		return issue
+	}
+	blocks := func(blocker, blocked *types.Issue) {
+		t.Helper()
+		dep := &types.Dependency{IssueID: blocked.ID, DependsOnID: blocker.ID, Type: types.DepBlocks}
+		if err := store.AddDependency(ctx, dep, "test-user"); err != nil {
+			t.Fatalf("AddDependency failed: %v", err)
+		}
+	}
+
+	epic := create(&types.Issue{Title: "Launch", IssueType: types.TypeEpic})
+	backend := create(&types.Issue{Title: "Backend", IssueType: types.TypeEpic, ParentID: epic.ID})
+	schema := create(&types.Issue{Title: "Schema", Priority: 1, EstimatedMinutes: mins(16 * 60), ParentID: backend.ID})
+	api := create(&types.Issue{Title: "API: v2", Priority: 1, EstimatedMinutes: mins(4 * 60), ParentID: backend.ID})
+	docs := create(&types.Issue{Title: "Docs", Priority: 2, DueDate: date(2), ParentID: epic.ID})
+	design := create(&types.Issue{Title: "Design", Priority: 2, StartDate: date(5), ParentID: epic.ID})
+	done := create(&types.Issue{Title: "Done", Priority: 2, ParentID: epic.ID})
+	vendor := create(&types.Issue{Title: "Vendor", Priority: 2})
+
+	blocks(schema, api)
+	blocks(api, docs)
+	blocks(vendor, design) // outside the epic: ignored
+	if err := store.CloseIssue(ctx, done.ID, "done", "test-user"); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+
+	sched, err := ForEpic(ctx, store, epic.ID, Options{From: from})
+	if err != nil {
+		t.Fatalf("ForEpic failed: %v", err)
+	}
+	if sched.EpicID != epic.ID || sched.Title != "Launch ("+epic.ID+")" {
+		t.Errorf("unexpected header %q %q", sched.EpicID, sched.Title)
+	}
+
+	type want struct {
+		start, days int
+		section     string
+		late        bool
+		unestimated bool
+	}
+	wants := map[string]want{
+		schema.ID: {0, 2, "Backend", false, false},
+		api.ID:    {2, 1, "Backend", false, false},
+		docs.ID:   {3, 1, "Launch", true, true},
+		design.ID: {5, 1, "Launch", false, true},
+	}
+	if len(sched.Items) != len(wants) {
+		t.Fatalf("expected %d items, got %d", len(wants), len(sched.Items))
+	}
+	seen := map[string]bool{}
+	for _, item := range sched.Items {
+		w, ok := wants[item.Issue.ID]
+		if !ok {
+			t.Fatalf("unexpected item %s", item.Issue.ID)
+		}
+		for _, b := range item.BlockedBy {
+			if !seen[b] {
+				t.Errorf("%s scheduled before its blocker %s", item.Issue.ID, b)
+			}
+		}
+		seen[item.Issue.ID] = true
+		if !item.Start.Equal(*date(w.start)) || item.Days != w.days || !item.Finish.Equal(*date(w.start + w.days)) {
+			t.Errorf("%s: got start %s, %d days, finish %s; want start +%d, %d days",
+				item.Issue.Title, item.Start.Format("2006-01-02"), item.Days, item.Finish.Format("2006-01-02"), w.start, w.days)
+		}
+		if item.Section != w.section || item.Late != w.late || item.Unestimated != w.unestimated {
+			t.Errorf("%s: got section %q late %v unestimated %v", item.Issue.Title, item.Section, item.Late, item.Unestimated)
+		}
+	}
+	if sched.Late != 1 {
+		t.Errorf("expected 1 late item, got %d", sched.Late)
+	}
+	if !sched.Finish.Equal(*date(6)) {
+		t.Errorf("expected finish %s, got %s", date(6).Format("2006-01-02"), sched.Finish.Format("2006-01-02"))
+	}
+
+	var gantt bytes.Buffer
+	if err := Write(&gantt, sched, FormatMermaidGantt); err != nil {
+		t.Fatalf("Write mermaid failed: %v", err)
+	}
+	for _, s := range []string{"gantt\n", "dateFormat YYYY-MM-DD", "section Backend", "section Launch", "API - v2", ":crit, "} {
+		if !strings.Contains(gantt.String(), s) {
+			t.Errorf("mermaid output missing %q:\n%s", s, gantt.String())
+		}
+	}
+
+	var csv bytes.Buffer
+	if err := Write(&csv, sched, FormatCSV); err != nil {
+		t.Fatalf("Write csv failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(csv.String()), "\n")
+	if len(lines) != 5 || !strings.HasPrefix(lines[0], "id,title,section,") {
+		t.Fatalf("unexpected csv:\n%s", csv.String())
+	}
+	if !strings.Contains(csv.String(), api.ID+",API: v2,Backend,open,1,,240,2025-11-05,2025-11-05,1,,false,"+schema.ID) {
+		t.Errorf("csv missing API row:\n%s", csv.String())
+	}
+
+	if err := Write(&csv, sched, "xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+
+	all, err := ForAll(ctx, store, Options{From: from})
+	if err != nil {
+		t.Fatalf("ForAll failed: %v", err)
+	}
+	if len(all.Items) != 5 {
+		t.Errorf("expected 5 unfinished non-epic issues, got %d", len(all.Items))
+	}
+
+	if _, err := ForEpic(ctx, store, "bd-missing", Options{From: from}); err == nil {
+		t.Error("expected error for missing epic")
+	}
+}
+
+// cyclicStore reports dependencies the stores themselves refuse to create
+type cyclicStore struct {
+	*memory.MemoryStorage
+	deps map[string][]*types.Dependency
+}
+
+func (s *cyclicStore) GetDependencyRecords(ctx context.Context, issueID string) ([]*types.Dependency, error) {
+	return s.deps[issueID], nil
+}
+
+func TestProjectCycle(t *testing.T) {
+	ctx := context.Background()
+	a := &types.Issue{ID: "bd-1", Title: "A", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	b := &types.Issue{ID: "bd-2", Title: "B", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	store := &cyclicStore{
+		MemoryStorage: memory.New(""),
+		deps: map[string][]*types.Dependency{
+			a.ID: {{IssueID: a.ID, DependsOnID: b.ID, Type: types.DepBlocks}},
+			b.ID: {{IssueID: b.ID, DependsOnID: a.ID, Type: types.DepBlocks}},
+		},
+	}
+	defer store.Close()
+
+	if _, err := Project(ctx, store, []*types.Issue{a, b}, nil, Options{}); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+// Package secrets detects credentials pasted into free-text issue content.
+//
+// Agents frequently paste command output and logs into descriptions and
+// comments; those often contain API keys, tokens, or private keys that would
+// otherwise end up committed to git through the JSONL export.
+package secrets
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Mode controls what happens when a secret is detected on write
+type Mode string
+
+// Secret handling modes
+const (
+	ModeOff    Mode = "off"    // no scanning
+	ModeFlag   Mode = "flag"   // store the text unchanged but record the finding
+	ModeMask   Mode = "mask"   // replace the secret with a redaction marker
+	ModeReject Mode = "reject" // refuse the write
+)
+
+// ParseMode validates a mode string. An empty string means ModeOff.
+func ParseMode(s string) (Mode, error) {
+	switch m := Mode(strings.ToLower(strings.TrimSpace(s))); m {
+	case "", ModeOff:
+		return ModeOff, nil
+	case ModeFlag, ModeMask, ModeReject:
+		return m, nil
+	default:
+		return "", fmt.Errorf("invalid secrets mode %q (must be off, flag, mask, or reject)", s)
+	}
+}
+
+// Finding describes one detected secret. The secret itself is never retained;
+// Preview shows only its first and last characters.
+type Finding struct {
+	Kind    string `json:"kind"`
+	Start   int    `json:"start"`
+	End     int    `json:"end"`
+	Preview string `json:"preview"`
+}
+
+type pattern struct {
+	kind string
+	re   *regexp.Regexp
+}
+
+// patterns are checked in order; earlier patterns win when matches overlap
+var patterns = []pattern{
+	{"private_key", regexp.MustCompile(`-----BEGIN (?:[A-Z]+ )*PRIVATE KEY(?: BLOCK)?-----[\s\S]*?(?:-----END (?:[A-Z]+ )*PRIVATE KEY(?: BLOCK)?-----|$)`)},
+	{"aws_access_key", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"aws_secret_key", regexp.MustCompile(`(?i)aws_?secret_?(?:access_?)?key["']?\s*[:=]\s*["']?[A-Za-z0-9/+=]{40}`)},
+	{"github_token", regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{"gitlab_token", regexp.MustCompile(`\bglpat-[A-Za-z0-9_\-]{20,}\b`)},
+	{"slack_token", regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}\b`)},
+	{"stripe_key", regexp.MustCompile(`\b[rs]k_live_[A-Za-z0-9]{20,}\b`)},
+	{"google_api_key", regexp.MustCompile(`\bAIza[0-9A-Za-z_\-]{35}\b`)},
+	{"api_key", regexp.MustCompile(`\bsk-(?:ant-|proj-)?[A-Za-z0-9_\-]{32,}\b`)},
+	{"jwt", regexp.MustCompile(`\beyJ[A-Za-z0-9_\-]{10,}\.eyJ[A-Za-z0-9_\-]{10,}\.[A-Za-z0-9_\-]{10,}\b`)},
+	{"bearer_token", regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9\-._~+/]{20,}=*`)},
+	{"password_assignment", regexp.MustCompile(`(?i)\b(?:password|passwd|secret|api_?key|access_?token)["']?\s*[:=]\s*["']?[^\s"']{8,}`)},
+}
+
+// Scan returns all secrets found in text, ordered by position
+func Scan(text string) []Finding {
+	if text == "" {
+		return nil
+	}
+	var findings []Finding
+	for _, p := range patterns {
+		for _, loc := range p.re.FindAllStringIndex(text, -1) {
+			if overlaps(findings, loc[0], loc[1]) {
+				continue
+			}
+			findings = append(findings, Finding{
+				Kind:    p.kind,
+				Start:   loc[0],
+				End:     loc[1],
+				Preview: preview(text[loc[0]:loc[1]]),
+			})
+		}
+	}
+	sort.Slice(findings, func(i, j int) bool { return findings[i].Start < findings[j].Start })
+	return findings
+}
+
+// Mask replaces every detected secret with a [REDACTED:<kind>] marker
+func Mask(text string) (string, []Finding) {
+	findings := Scan(text)
+	if len(findings) == 0 {
+		return text, nil
+	}
+	var b strings.Builder
+	last := 0
+	for _, f := range findings {
+		b.WriteString(text[last:f.Start])
+		fmt.Fprintf(&b, "[REDACTED:%s]", f.Kind)
+		last = f.End
+	}
+	b.WriteString(text[last:])
+	return b.String(), findings
+}
+
+// Kinds returns the distinct kinds of the given findings
+func Kinds(findings []Finding) []string {
+	seen := make(map[string]bool)
+	var kinds []string
+	for _, f := range findings {
+		if !seen[f.Kind] {
+			seen[f.Kind] = true
+			kinds = append(kinds, f.Kind)
+		}
+	}
+	return kinds
+}
+
+// DetectedError is returned when a write is rejected because it contains secrets
+type DetectedError struct {
+	Field string
+	Kinds []string
+}
+
+func (e *DetectedError) Error() string {
+	return fmt.Sprintf("%s appears to contain secrets (%s); remove them or set secrets.mode to mask",
+		e.Field, strings.Join(e.Kinds, ", "))
+}
+
+func overlaps(findings []Finding, start, end int) bool {
+	for _, f := range findings {
+		if start < f.End && f.Start < end {
+			return true
+		}
+	}
+	return false
+}
+
+func preview(secret string) string {
+	secret = strings.TrimSpace(secret)
+	if len(secret) <= 8 {
+		return strings.Repeat("*", len(secret))
+	}
+	return secret[:4] + "…" + secret[len(secret)-4:]
+}
@@ -0,0 +1,14 @@
+package signing
+
+import "testing"
+
+func TestUnknownMethods(t *testing.T) {
+	for _, method := range []string{"hmac", "age", "rot13", ""} {
+		if _, _, err := Sign(method, "", "payload"); err == nil {
+			t.Errorf("Sign(%q): expected error for unknown method", method)
+		}
+		if err := Verify(method, "payload", "sig"); err == nil {
+			t.Errorf("Verify(%q): expected error for unknown method", method)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+// Package stale finds in-progress work that has gone quiet and nudges it.
+//
+// An issue is stale when it has been in_progress with no updates for
+// stale.days days. Nudging applies stale.action to each stale issue once per
+// quiet spell (it isn't nudged again until it is updated and goes quiet
+// again) and can email the assignee through their digest subscription.
+package stale
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/digest"
+	"github.com/imalsogreg/beads/internal/storage"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// Config keys for the staleness policy
+const (
+	DaysConfigKey   = "stale.days"   // in-progress issues untouched this long are stale
+	ActionConfigKey = "stale.action" // what a nudge does: comment, label, or reopen
+	LabelConfigKey  = "stale.label"  // label added by the label action
+	NudgeConfigKey  = "stale.nudge"  // "true" to nudge from the daemon
+)
+
+// Policy defaults
+const (
+	DefaultDays  = 7
+	DefaultLabel = "stale"
+)
+
+// Nudge actions
+const (
+	ActionComment = "comment" // comment asking whether the work is still going
+	ActionLabel   = "label"   // add the stale label
+	ActionReopen  = "reopen"  // move the issue back to open, with a comment
+)
+
+// Actions lists the valid values of stale.action
+var Actions = []string{ActionComment, ActionLabel, ActionReopen}
+
+// Policy says when an issue is stale and what nudging it does
+type Policy struct {
+	Days   int
+	Action string
+	Label  string
+}
+
+// After returns how long an issue must go without updates to be stale
+func (p *Policy) After() time.Duration {
+	return time.Duration(p.Days) * 24 * time.Hour
+}
+
+// LoadPolicy reads the stale.* config keys
+func LoadPolicy(ctx context.Context, store storage.Storage) (*Policy, error) {
+	p := &Policy{Days: DefaultDays, Action: ActionComment, Label: DefaultLabel}
+	if v, _ := store.GetConfig(ctx, DaysConfigKey); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid %s %q (must be a positive number of days)", DaysConfigKey, v)
+		}
+		p.Days = n
+	}
+	if v, _ := store.GetConfig(ctx, ActionConfigKey); v != "" {
+		if err := ValidateAction(v); err != nil {
+			return nil, err
+		}
+		p.Action = v
+	}
+	if v, _ := store.GetConfig(ctx, LabelConfigKey); v != "" {
+		p.Label = v
+	}
+	return p, nil
+}
+
+// ValidateAction checks that action is a known nudge action
+func ValidateAction(action string) error {
+	for _, a := range Actions {
+		if a == action {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid %s %q (valid: %s)", ActionConfigKey, action, strings.Join(Actions, ", "))
+}
+
+// Issue is a stale issue and how many whole days it has been quiet
+type Issue struct {
+	types.Issue
+	IdleDays int `json:"idle_days"`
+}
+
+// Find returns the in-progress issues not updated within after, quietest first
+func Find(ctx context.Context, store storage.Storage, after time.Duration, now time.Time) ([]*Issue, error) {
+	status := types.StatusInProgress
+	issues, err := store.SearchIssues(ctx, "", types.IssueFilter{Status: &status})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list in-progress issues: %w", err)
+	}
+
+	var stale []*Issue
+	for _, issue := range issues {
+		idle := now.Sub(issue.UpdatedAt)
+		if idle >= after {
+			stale = append(stale, &Issue{Issue: *issue, IdleDays: int(idle.Hours() / 24)})
+		}
+	}
+	sort.Slice(stale, func(i, j int) bool { return stale[i].UpdatedAt.Before(stale[j].UpdatedAt) })
+	return stale, nil
+}
+
+// Notifier tells an issue's assignee that it was nudged
+type Notifier func(ctx context.Context, issue *Issue) error
+
+// MailNotifier emails assignees who have a digest subscription. Assignees
+// without one aren't mailed.
+func MailNotifier(store *sqlite.SQLiteStorage, mailer digest.Mailer, prefix string) Notifier {
+	if prefix == "" {
+		prefix = "beads"
+	}
+	return func(ctx context.Context, issue *Issue) error {
+		if issue.Assignee == "" {
+			return nil
+		}
+		sub, err := store.GetDigestSubscription(ctx, issue.Assignee)
+		if err != nil || sub == nil {
+			return err
+		}
+		subject := fmt.Sprintf("[%s] Stale: %s %s", prefix, issue.ID, issue.Title)
+		body := fmt.Sprintf("Hi %s,\n\n%s [P%d] %s has been in progress with no updates for %d days.\n\n"+
+			"Update it if you're still working on it, or move it back to open so someone else can pick it up.\n",
+			issue.Assignee, issue.ID, issue.Priority, issue.Title, issue.IdleDays)
+		return mailer.Send(ctx, sub.Email, subject, body)
+	}
+}
+
+// Nudge applies the policy's action to every stale issue that hasn't been
+// nudged since its last update, then notifies its assignee if notify isn't
+// nil. It returns the issues nudged. A failed notification doesn't stop the
+// round; the first one is returned after every issue is handled.
+func Nudge(ctx context.Context, store *sqlite.SQLiteStorage, policy *Policy, actor string, now time.Time, notify Notifier) ([]*Issue, error) {
+	issues, err := Find(ctx, store, policy.After(), now)
+	if err != nil {
+		return nil, err
+	}
+
+	var nudged []*Issue
+	var notifyErr error
+	for _, issue := range issues {
+		last, err := store.GetStaleNudge(ctx, issue.ID)
+		if err != nil {
+			return nudged, err
+		}
+		if last != nil && !last.Before(issue.UpdatedAt) {
+			continue
+		}
+
+		if err := apply(ctx, store, policy, issue, actor); err != nil {
+			return nudged, fmt.Errorf("failed to nudge %s: %w", issue.ID, err)
+		}
+		if err := store.MarkStaleNudged(ctx, issue.ID, now); err != nil {
+			return nudged, err
+		}
+		nudged = append(nudged, issue)
+
+		if notify != nil {
+			if err := notify(ctx, issue); err != nil && notifyErr == nil {
+				notifyErr = fmt.Errorf("failed to notify %s about %s: %w", issue.Assignee, issue.ID, err)
+			}
+		}
+	}
+	return nudged, notifyErr
+}
+
+// apply performs the policy's action on one stale issue
+func apply(ctx context.Context, store storage.Storage, policy *Policy, issue *Issue, actor string) error {
+	switch policy.Action {
+	case ActionLabel:
+		return store.AddLabel(ctx, issue.ID, policy.Label, actor)
+	case ActionReopen:
+		if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"status": string(types.StatusOpen)}, actor); err != nil {
+			return err
+		}
+		_, err := store.AddIssueComment(ctx, issue.ID, actor,
+			fmt.Sprintf("Moved back to open after %d days in progress with no updates.", issue.IdleDays))
+		return err
+	default:
+		_, err := store.AddIssueComment(ctx, issue.ID, actor,
+			fmt.Sprintf("No updates in %d days while in progress. Still working on this? Update the issue, or move it back to open so someone else can pick it up.", issue.IdleDays))
+		return err
+	}
+}
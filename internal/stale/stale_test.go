@@ -0,0 +1,148 @@
+package stale
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+type fakeMailer struct {
+	to, subjects []string
+}
+
+func (m *fakeMailer) Send(_ context.Context, to, subject, _ string) error {
+	m.to = append(m.to, to)
+	m.subjects = append(m.subjects, subject)
+	return nil
+}
+
+func newTestStore(t *testing.T) *sqlite.SQLiteStorage {
+	t.Helper()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	if err := store.SetConfig(context.Background(), "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+// createIssue creates an issue last updated idleDays ago
+func createIssue(t *testing.T, store *sqlite.SQLiteStorage, title, assignee string, status types.Status, idleDays int) *types.Issue {
+	t.Helper()
+	ctx := context.Background()
+	issue := &types.Issue{Title: title, Status: status, Priority: 2, IssueType: types.TypeTask, Assignee: assignee}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	if _, err := store.UnderlyingDB().ExecContext(ctx, `UPDATE issues SET updated_at = ? WHERE id = ?`,
+		time.Now().Add(-time.Duration(idleDays)*24*time.Hour), issue.ID); err != nil {
+		t.Fatal(err)
+	}
+	return issue
+}
+
+func TestLoadPolicy(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+
+	p, err := LoadPolicy(ctx, store)
+	if err != nil {
+		t.Fatalf("LoadPolicy failed: %v", err)
+	}
+	if p.Days != DefaultDays || p.Action != ActionComment || p.Label != DefaultLabel {
+		t.Errorf("Unexpected default policy %+v", p)
+	}
+
+	for key, value := range map[string]string{DaysConfigKey: "0", ActionConfigKey: "delete"} {
+		store := newTestStore(t)
+		if err := store.SetConfig(ctx, key, value); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadPolicy(ctx, store); err == nil {
+			t.Errorf("Expected an error for %s=%s", key, value)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+
+	older := createIssue(t, store, "Old refactor", "alice", types.StatusInProgress, 20)
+	old := createIssue(t, store, "Flaky test", "bob", types.StatusInProgress, 10)
+	createIssue(t, store, "Fresh work", "alice", types.StatusInProgress, 1)
+	createIssue(t, store, "Never started", "alice", types.StatusOpen, 30)
+
+	issues, err := Find(ctx, store, 7*24*time.Hour, time.Now())
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if len(issues) != 2 || issues[0].ID != older.ID || issues[1].ID != old.ID {
+		t.Fatalf("Expected %s and %s, got %+v", older.ID, old.ID, issues)
+	}
+	if issues[0].IdleDays != 20 {
+		t.Errorf("Expected 20 idle days, got %d", issues[0].IdleDays)
+	}
+}
+
+func TestNudge(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+
+	mine := createIssue(t, store, "Old refactor", "alice", types.StatusInProgress, 10)
+	theirs := createIssue(t, store, "Flaky test", "bob", types.StatusInProgress, 10)
+	if err := store.SetDigestSubscription(ctx, &sqlite.DigestSubscription{Actor: "alice", Email: "alice@example.com", Frequency: "daily"}); err != nil {
+		t.Fatal(err)
+	}
+
+	mailer := &fakeMailer{}
+	notify := MailNotifier(store, mailer, "bd")
+	policy := &Policy{Days: 7, Action: ActionComment, Label: DefaultLabel}
+	nudged, err := Nudge(ctx, store, policy, "stale-bot", time.Now(), notify)
+	if err != nil {
+		t.Fatalf("Nudge failed: %v", err)
+	}
+	if len(nudged) != 2 {
+		t.Fatalf("Expected 2 nudged issues, got %d", len(nudged))
+	}
+	comments, err := store.GetIssueComments(ctx, mine.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comments) != 1 || comments[0].Author != "stale-bot" || !strings.Contains(comments[0].Text, "No updates in 10 days") {
+		t.Errorf("Unexpected comments %+v", comments)
+	}
+	// Only alice has a subscription to mail
+	if len(mailer.to) != 1 || mailer.to[0] != "alice@example.com" || !strings.Contains(mailer.subjects[0], mine.ID) {
+		t.Errorf("Unexpected mail to %v: %v", mailer.to, mailer.subjects)
+	}
+
+	// Nothing new until an issue is updated and goes quiet again
+	if nudged, err = Nudge(ctx, store, policy, "stale-bot", time.Now(), notify); err != nil || len(nudged) != 0 {
+		t.Errorf("Expected no repeat nudges, got %d (%v)", len(nudged), err)
+	}
+
+	policy.Action = ActionReopen
+	later := time.Now().Add(8 * 24 * time.Hour)
+	if err := store.UpdateIssue(ctx, theirs.ID, map[string]interface{}{"title": "Flaky test in CI"}, "bob"); err != nil {
+		t.Fatal(err)
+	}
+	if nudged, err = Nudge(ctx, store, policy, "stale-bot", later, nil); err != nil || len(nudged) != 1 || nudged[0].ID != theirs.ID {
+		t.Fatalf("Expected %s to be nudged again, got %+v (%v)", theirs.ID, nudged, err)
+	}
+	issue, err := store.GetIssue(ctx, theirs.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issue.Status != types.StatusOpen {
+		t.Errorf("Expected %s to be reopened, got %s", theirs.ID, issue.Status)
+	}
+}
@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"context"
+	"sort"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// GetBlockers finds the open issues holding up an issue.
+//
+// An issue is held up the way ready work sees it: by the open issues it
+// depends on with 'blocks' dependencies, and by those blocking any of its
+// ancestors via parent-child dependencies. With transitive, the blockers'
+// own blockers are followed too, so the result is everything upstream that
+// has to finish first. Each blocker is reported once, at its nearest
+// distance.
+func GetBlockers(ctx context.Context, s Storage, issueID string, transitive bool) (*types.BlockerTree, error) {
+	root, err := s.GetIssue(ctx, issueID)
+	if err != nil {
+		return nil, err
+	}
+	if root == nil {
+		return nil, types.NotFound("issue", issueID)
+	}
+
+	tree := &types.BlockerTree{Issue: root, Blockers: []*types.IssueBlocker{}, Transitive: transitive}
+	visited := map[string]bool{root.ID: true}
+	level := []*types.Issue{root}
+	for depth := 1; len(level) > 0 && (transitive || depth == 1); depth++ {
+		var next []*types.IssueBlocker
+		for _, issue := range level {
+			blockers, err := directBlockers(ctx, s, issue.ID)
+			if err != nil {
+				return nil, err
+			}
+			for _, blocker := range blockers {
+				if visited[blocker.Issue.ID] {
+					continue
+				}
+				visited[blocker.Issue.ID] = true
+				blocker.Depth = depth
+				next = append(next, blocker)
+			}
+		}
+		sort.Slice(next, func(i, j int) bool {
+			return preferIssue(next[i].Issue, next[j].Issue)
+		})
+
+		tree.Blockers = append(tree.Blockers, next...)
+		level = nil
+		for _, blocker := range next {
+			level = append(level, blocker.Issue)
+		}
+	}
+	tree.Total = len(tree.Blockers)
+
+	return tree, nil
+}
+
+// directBlockers returns the open issues blocking an issue or its ancestors
+func directBlockers(ctx context.Context, s Storage, issueID string) ([]*types.IssueBlocker, error) {
+	var blockers []*types.IssueBlocker
+	seen := make(map[string]bool)
+	for id := issueID; id != "" && !seen[id]; {
+		seen[id] = true
+		deps, err := s.GetDependencyRecords(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		parent := ""
+		for _, dep := range deps {
+			switch dep.Type {
+			case types.DepParentChild:
+				parent = dep.DependsOnID
+			case types.DepBlocks:
+				blocker, err := s.GetIssue(ctx, dep.DependsOnID)
+				if err != nil {
+					return nil, err
+				}
+				if blocker == nil || blocker.Status == types.StatusClosed {
+					continue
+				}
+				via := ""
+				if id != issueID {
+					via = id
+				}
+				blockers = append(blockers, &types.IssueBlocker{Issue: blocker, Blocks: issueID, Via: via})
+			}
+		}
+		id = parent
+	}
+	return blockers, nil
+}
@@ -0,0 +1,100 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage"
+	"github.com/imalsogreg/beads/internal/storage/memory"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestGetBlockers(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New("")
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+
+	create := func(title string, priority int, parentID string) *types.Issue {
+		t.Helper()
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: priority, IssueType: types.TypeTask, ParentID: parentID}
+		if err := store.CreateIssue(ctx, issue, "test-user"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		return issue
+	}
+	blocks := func(blocker, blocked *types.Issue) {
+		t.Helper()
+		dep := &types.Dependency{IssueID: blocked.ID, DependsOnID: blocker.ID, Type: types.DepBlocks}
+		if err := store.AddDependency(ctx, dep, "test-user"); err != nil {
+			t.Fatalf("AddDependency failed: %v", err)
+		}
+	}
+
+	feature := create("Feature", 1, "")
+	task := create("Task", 2, feature.ID)
+	api := create("API", 2, "")
+	schema := create("Schema", 1, "")
+	design := create("Design", 3, "")
+	vendor := create("Vendor", 0, "")
+	done := create("Done", 0, "")
+
+	blocks(api, task)
+	blocks(design, feature) // blocks the task through its parent
+	blocks(schema, api)
+	blocks(schema, design) // reachable twice: reported once, at its nearest
+	blocks(done, task)
+	blocks(vendor, done) // behind a closed blocker: not reached
+	if err := store.CloseIssue(ctx, done.ID, "done", "test-user"); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+
+	tree, err := storage.GetBlockers(ctx, store, task.ID, false)
+	if err != nil {
+		t.Fatalf("GetBlockers failed: %v", err)
+	}
+	if tree.Total != 2 || tree.Blockers[0].Issue.ID != api.ID || tree.Blockers[1].Issue.ID != design.ID {
+		t.Fatalf("expected direct blockers API, Design; got %d", tree.Total)
+	}
+	if tree.Blockers[1].Blocks != task.ID || tree.Blockers[1].Via != feature.ID || tree.Blockers[0].Via != "" {
+		t.Errorf("expected Design to block via %s, got %+v", feature.ID, tree.Blockers[1])
+	}
+
+	tree, err = storage.GetBlockers(ctx, store, task.ID, true)
+	if err != nil {
+		t.Fatalf("GetBlockers failed: %v", err)
+	}
+	want := []struct {
+		id    string
+		depth int
+		by    string
+	}{
+		{api.ID, 1, task.ID},
+		{design.ID, 1, task.ID},
+		{schema.ID, 2, api.ID},
+	}
+	if len(tree.Blockers) != len(want) {
+		t.Fatalf("expected %d transitive blockers, got %d", len(want), len(tree.Blockers))
+	}
+	for i, w := range want {
+		got := tree.Blockers[i]
+		if got.Issue.ID != w.id || got.Depth != w.depth || got.Blocks != w.by {
+			t.Errorf("blockers[%d]: got %s depth %d blocks %s, want %s depth %d blocks %s",
+				i, got.Issue.ID, got.Depth, got.Blocks, w.id, w.depth, w.by)
+		}
+	}
+
+	tree, err = storage.GetBlockers(ctx, store, schema.ID, true)
+	if err != nil {
+		t.Fatalf("GetBlockers failed: %v", err)
+	}
+	if tree.Total != 0 {
+		t.Errorf("expected no blockers for an unblocked issue, got %d", tree.Total)
+	}
+
+	if _, err := storage.GetBlockers(ctx, store, "bd-999", false); err == nil {
+		t.Error("expected error for missing issue")
+	}
+}
@@ -0,0 +1,155 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// pathLength is the cost of the longest chain ending at an issue
+type pathLength struct {
+	minutes int
+	issues  int
+}
+
+func (a pathLength) longerThan(b pathLength) bool {
+	if a.minutes != b.minutes {
+		return a.minutes > b.minutes
+	}
+	return a.issues > b.issues
+}
+
+// GetCriticalPath finds the longest chain of unfinished work under an epic.
+//
+// The epic's descendants (via parent-child dependencies, recursively) that are
+// not closed form the graph, and 'blocks' dependencies between them order it.
+// Each issue weighs its estimated_minutes, with unestimated issues counting as
+// zero; between chains of equal duration the one with more issues wins.
+// Blockers outside the epic are not considered.
+func GetCriticalPath(ctx context.Context, s Storage, epicID string) (*types.CriticalPath, error) {
+	epic, err := s.GetIssue(ctx, epicID)
+	if err != nil {
+		return nil, err
+	}
+	if epic == nil {
+		return nil, types.NotFound("issue", epicID)
+	}
+
+	// Collect unfinished descendants
+	nodes := make(map[string]*types.Issue)
+	visited := map[string]bool{epicID: true}
+	queue := []string{epicID}
+	for len(queue) > 0 {
+		parentID := queue[0]
+		queue = queue[1:]
+
+		children, err := s.GetChildren(ctx, parentID)
+		if err != nil {
+			return nil, err
+		}
+		for _, child := range children {
+			if visited[child.ID] {
+				continue
+			}
+			visited[child.ID] = true
+			queue = append(queue, child.ID)
+			if child.Status != types.StatusClosed {
+				nodes[child.ID] = child
+			}
+		}
+	}
+
+	// Edges from each issue to its unfinished blockers within the epic
+	blockers := make(map[string][]string)
+	for id := range nodes {
+		deps, err := s.GetDependencyRecords(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		for _, dep := range deps {
+			if dep.Type != types.DepBlocks {
+				continue
+			}
+			if _, ok := nodes[dep.DependsOnID]; ok {
+				blockers[id] = append(blockers[id], dep.DependsOnID)
+			}
+		}
+	}
+
+	// Longest path ending at each issue, memoized depth-first
+	lengths := make(map[string]pathLength)
+	prev := make(map[string]string)
+	inProgress := make(map[string]bool)
+	var longest func(id string) (pathLength, error)
+	longest = func(id string) (pathLength, error) {
+		if l, ok := lengths[id]; ok {
+			return l, nil
+		}
+		if inProgress[id] {
+			return pathLength{}, fmt.Errorf("dependency cycle at %s", id)
+		}
+		inProgress[id] = true
+
+		var best pathLength
+		bestPrev := ""
+		for _, blockerID := range blockers[id] {
+			l, err := longest(blockerID)
+			if err != nil {
+				return pathLength{}, err
+			}
+			if bestPrev == "" || l.longerThan(best) || (!best.longerThan(l) && preferIssue(nodes[blockerID], nodes[bestPrev])) {
+				best, bestPrev = l, blockerID
+			}
+		}
+
+		l := pathLength{minutes: best.minutes + estimate(nodes[id]), issues: best.issues + 1}
+		lengths[id] = l
+		if bestPrev != "" {
+			prev[id] = bestPrev
+		}
+		inProgress[id] = false
+		return l, nil
+	}
+
+	end := ""
+	var endLength pathLength
+	for id := range nodes {
+		l, err := longest(id)
+		if err != nil {
+			return nil, err
+		}
+		if end == "" || l.longerThan(endLength) || (!endLength.longerThan(l) && preferIssue(nodes[id], nodes[end])) {
+			end, endLength = id, l
+		}
+	}
+
+	path := &types.CriticalPath{EpicID: epicID, Issues: []*types.Issue{}}
+	for id := end; id != ""; id = prev[id] {
+		issue := nodes[id]
+		path.Issues = append([]*types.Issue{issue}, path.Issues...)
+		path.TotalMinutes += estimate(issue)
+		if issue.EstimatedMinutes == nil {
+			path.Unestimated++
+		}
+	}
+
+	return path, nil
+}
+
+// estimate returns an issue's estimated duration, or zero if unestimated
+func estimate(issue *types.Issue) int {
+	if issue.EstimatedMinutes == nil {
+		return 0
+	}
+	return *issue.EstimatedMinutes
+}
+
+// preferIssue breaks ties between equally long chains: higher priority
+// (lower number) first, then lower ID, so results are deterministic
+func preferIssue(a, b *types.Issue) bool {
+	if a.Priority != b.Priority {
+		return a.Priority < b.Priority
+	}
+	return a.ID < b.ID
+}
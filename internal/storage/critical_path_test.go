@@ -0,0 +1,120 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage"
+	"github.com/imalsogreg/beads/internal/storage/memory"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestGetCriticalPath(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New("")
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+
+	create := func(title string, minutes *int, issueType types.IssueType, parentID string) *types.Issue {
+		t.Helper()
+		issue := &types.Issue{
+			Title:            title,
+			Status:           types.StatusOpen,
+			Priority:         2,
+			IssueType:        issueType,
+			EstimatedMinutes: minutes,
+			ParentID:         parentID,
+		}
+		if err := store.CreateIssue(ctx, issue, "test-user"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		return issue
+	}
+	blocks := func(blocker, blocked *types.Issue) {
+		t.Helper()
+		dep := &types.Dependency{IssueID: blocked.ID, DependsOnID: blocker.ID, Type: types.DepBlocks}
+		if err := store.AddDependency(ctx, dep, "test-user"); err != nil {
+			t.Fatalf("AddDependency failed: %v", err)
+		}
+	}
+	mins := func(m int) *int { return &m }
+
+	epic := create("Launch", nil, types.TypeEpic, "")
+	a := create("Design", mins(60), types.TypeTask, epic.ID)
+	b := create("Build", mins(30), types.TypeTask, epic.ID)
+	c := create("Marketing", mins(120), types.TypeTask, epic.ID)
+	d := create("Announce", nil, types.TypeTask, epic.ID)
+	f := create("Prototype", mins(100), types.TypeTask, a.ID) // grandchild of the epic
+	done := create("Research", mins(500), types.TypeTask, epic.ID)
+
+	blocks(a, b)
+	blocks(f, b)
+	blocks(b, d)
+	blocks(done, a)
+	if err := store.CloseIssue(ctx, done.ID, "done", "test-user"); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+
+	path, err := storage.GetCriticalPath(ctx, store, epic.ID)
+	if err != nil {
+		t.Fatalf("GetCriticalPath failed: %v", err)
+	}
+
+	// Prototype (100) -> Build (30) -> Announce (0) beats Marketing (120)
+	// and Design (60) -> Build -> Announce; closed Research is ignored
+	want := []string{f.ID, b.ID, d.ID}
+	if len(path.Issues) != len(want) {
+		t.Fatalf("Expected path %v, got %d issues", want, len(path.Issues))
+	}
+	for i, issue := range path.Issues {
+		if issue.ID != want[i] {
+			t.Errorf("Path[%d]: expected %s, got %s", i, want[i], issue.ID)
+		}
+	}
+	if path.TotalMinutes != 130 {
+		t.Errorf("Expected 130 minutes, got %d", path.TotalMinutes)
+	}
+	if path.Unestimated != 1 {
+		t.Errorf("Expected 1 unestimated issue, got %d", path.Unestimated)
+	}
+
+	// Finishing work shortens the path
+	if err := store.CloseIssue(ctx, f.ID, "done", "test-user"); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+	path, err = storage.GetCriticalPath(ctx, store, epic.ID)
+	if err != nil {
+		t.Fatalf("GetCriticalPath failed: %v", err)
+	}
+	if len(path.Issues) != 1 || path.Issues[0].ID != c.ID || path.TotalMinutes != 120 {
+		t.Errorf("Expected Marketing alone at 120 minutes, got %+v", path)
+	}
+}
+
+func TestGetCriticalPathEmpty(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New("")
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+
+	epic := &types.Issue{Title: "Empty", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeEpic}
+	if err := store.CreateIssue(ctx, epic, "test-user"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	path, err := storage.GetCriticalPath(ctx, store, epic.ID)
+	if err != nil {
+		t.Fatalf("GetCriticalPath failed: %v", err)
+	}
+	if len(path.Issues) != 0 || path.TotalMinutes != 0 {
+		t.Errorf("Expected empty path, got %+v", path)
+	}
+
+	if _, err := storage.GetCriticalPath(ctx, store, "bd-999"); err == nil {
+		t.Error("Expected error for missing epic")
+	}
+}
@@ -0,0 +1,602 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// referencePattern matches issue IDs mentioned in free text
+var referencePattern = regexp.MustCompile(`\b[a-zA-Z][-a-zA-Z0-9]*-\d+\b`)
+
+// contentKey represents the fields we use to identify duplicate issues
+type contentKey struct {
+	title              string
+	description        string
+	design             string
+	acceptanceCriteria string
+	status             string // Only group issues with same status
+}
+
+// FindDuplicateGroups groups issues with identical title, description,
+// design, acceptance criteria, and status. Only groups of two or more are
+// returned.
+func FindDuplicateGroups(issues []*types.Issue) [][]*types.Issue {
+	groups := make(map[contentKey][]*types.Issue)
+
+	for _, issue := range issues {
+		key := contentKey{
+			title:              issue.Title,
+			description:        issue.Description,
+			design:             issue.Design,
+			acceptanceCriteria: issue.AcceptanceCriteria,
+			status:             string(issue.Status),
+		}
+
+		groups[key] = append(groups[key], issue)
+	}
+
+	// Filter to only groups with duplicates
+	var duplicates [][]*types.Issue
+	for _, group := range groups {
+		if len(group) > 1 {
+			duplicates = append(duplicates, group)
+		}
+	}
+
+	return duplicates
+}
+
+// CountReferences counts how many times each issue ID is mentioned in the
+// text fields of issues
+func CountReferences(issues []*types.Issue) map[string]int {
+	counts := make(map[string]int)
+	for _, issue := range issues {
+		countReferences(counts, issue)
+	}
+	return counts
+}
+
+// countReferences adds the issue IDs mentioned in issue's text fields to counts
+func countReferences(counts map[string]int, issue *types.Issue) {
+	textFields := []string{
+		issue.Description,
+		issue.Design,
+		issue.AcceptanceCriteria,
+		issue.Notes,
+	}
+
+	for _, text := range textFields {
+		for _, match := range referencePattern.FindAllString(text, -1) {
+			counts[match]++
+		}
+	}
+}
+
+// contentHash digests the fields FindDuplicateGroups compares, so grouping a
+// stream of issues needn't keep their text
+func contentHash(issue *types.Issue) [sha256.Size]byte {
+	h := sha256.New()
+	for _, field := range []string{issue.Title, issue.Description, issue.Design, issue.AcceptanceCriteria, string(issue.Status)} {
+		fmt.Fprintf(h, "%d:%s", len(field), field)
+	}
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+	return sum
+}
+
+// ChooseMergeTarget selects the best issue to merge into
+// Priority: highest reference count, then lexicographically smallest ID
+func ChooseMergeTarget(group []*types.Issue, refCounts map[string]int) *types.Issue {
+	if len(group) == 0 {
+		return nil
+	}
+
+	target := group[0]
+	targetRefs := refCounts[target.ID]
+
+	for _, issue := range group[1:] {
+		issueRefs := refCounts[issue.ID]
+		if issueRefs > targetRefs || (issueRefs == targetRefs && issue.ID < target.ID) {
+			target = issue
+			targetRefs = issueRefs
+		}
+	}
+
+	return target
+}
+
+// tokenPattern splits text into words for similarity scoring
+var tokenPattern = regexp.MustCompile(`[\pL\pN]+`)
+
+// tokenize returns the set of lowercased words in an issue's title and
+// description
+func tokenize(issue *types.Issue) map[string]bool {
+	tokens := make(map[string]bool)
+	for _, text := range []string{issue.Title, issue.Description} {
+		for _, word := range tokenPattern.FindAllString(strings.ToLower(text), -1) {
+			tokens[word] = true
+		}
+	}
+	return tokens
+}
+
+// jaccard returns |a ∩ b| / |a ∪ b|
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	shared := 0
+	for token := range a {
+		if b[token] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// Similarity scores how alike two issues are, from 0 to 1: the overlap of the
+// lowercased words in their titles and descriptions
+func Similarity(a, b *types.Issue) float64 {
+	return jaccard(tokenize(a), tokenize(b))
+}
+
+// FindSimilarGroups groups issues with matching status whose Similarity to
+// some other issue in the group is at least threshold. Only groups of two or
+// more are returned.
+func FindSimilarGroups(issues []*types.Issue, threshold float64) [][]*types.Issue {
+	tokens := make([]map[string]bool, len(issues))
+	for i, issue := range issues {
+		tokens[i] = tokenize(issue)
+	}
+	return groupSimilar(issues, tokens, threshold)
+}
+
+// groupSimilar is FindSimilarGroups given each issue's tokens
+func groupSimilar(issues []*types.Issue, tokens []map[string]bool, threshold float64) [][]*types.Issue {
+	// Union-find over every pair that clears the threshold
+	parent := make([]int, len(issues))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for i := range issues {
+		for j := i + 1; j < len(issues); j++ {
+			if issues[i].Status != issues[j].Status {
+				continue
+			}
+			// The overlap can't exceed the smaller set, so skip pairs too
+			// different in size to ever match
+			small, large := len(tokens[i]), len(tokens[j])
+			if small > large {
+				small, large = large, small
+			}
+			if large > 0 && float64(small)/float64(large) < threshold {
+				continue
+			}
+			if jaccard(tokens[i], tokens[j]) >= threshold {
+				parent[find(i)] = find(j)
+			}
+		}
+	}
+
+	groups := make(map[int][]*types.Issue)
+	for i, issue := range issues {
+		root := find(i)
+		groups[root] = append(groups[root], issue)
+	}
+	var similar [][]*types.Issue
+	for _, group := range groups {
+		if len(group) > 1 {
+			similar = append(similar, group)
+		}
+	}
+	return similar
+}
+
+// FindDuplicates finds every group of duplicate issues with a suggested merge
+// target, ordered by target ID. With a threshold of 0 only issues with
+// identical content are grouped; otherwise issues are grouped by Similarity
+// (see FindSimilarGroups) and each is scored against the target.
+//
+// Issues are read one at a time, keeping only what grouping needs (a digest
+// of their content, or their words), so large databases aren't loaded whole.
+func FindDuplicates(ctx context.Context, s Storage, threshold float64) ([]*types.DuplicateGroup, error) {
+	if threshold < 0 || threshold > 1 {
+		return nil, fmt.Errorf("similarity threshold must be between 0 and 1 (got %g)", threshold)
+	}
+
+	refCounts := make(map[string]int)
+	identical := make(map[[sha256.Size]byte][]*types.Issue)
+	var issues []*types.Issue
+	var tokens []map[string]bool
+	tokensByID := make(map[string]map[string]bool)
+	err := s.ForEachIssue(ctx, types.IssueFilter{}, func(issue *types.Issue) error {
+		countReferences(refCounts, issue)
+		slim := &types.Issue{ID: issue.ID, Title: issue.Title, Status: issue.Status, Priority: issue.Priority}
+		if threshold > 0 {
+			issueTokens := tokenize(issue)
+			issues = append(issues, slim)
+			tokens = append(tokens, issueTokens)
+			tokensByID[issue.ID] = issueTokens
+		} else {
+			key := contentHash(issue)
+			identical[key] = append(identical[key], slim)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch issues: %w", err)
+	}
+
+	var groups [][]*types.Issue
+	if threshold > 0 {
+		groups = groupSimilar(issues, tokens, threshold)
+	} else {
+		for _, group := range identical {
+			if len(group) > 1 {
+				groups = append(groups, group)
+			}
+		}
+	}
+
+	result := []*types.DuplicateGroup{}
+	for _, group := range groups {
+		sort.Slice(group, func(i, j int) bool { return group[i].ID < group[j].ID })
+		target := ChooseMergeTarget(group, refCounts)
+
+		dg := &types.DuplicateGroup{Title: target.Title, SuggestedTarget: target.ID, SuggestedSources: []string{}}
+		for _, issue := range group {
+			score := 1.0
+			if threshold > 0 {
+				score = jaccard(tokensByID[issue.ID], tokensByID[target.ID])
+			}
+			dg.Issues = append(dg.Issues, &types.DuplicateIssue{
+				ID:            issue.ID,
+				Title:         issue.Title,
+				Status:        issue.Status,
+				Priority:      issue.Priority,
+				References:    refCounts[issue.ID],
+				Score:         score,
+				IsMergeTarget: issue.ID == target.ID,
+			})
+			if issue.ID != target.ID {
+				dg.SuggestedSources = append(dg.SuggestedSources, issue.ID)
+			}
+		}
+		dg.SuggestedCommand = fmt.Sprintf("bd merge %s --into %s", strings.Join(dg.SuggestedSources, " "), target.ID)
+		result = append(result, dg)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].SuggestedTarget < result[j].SuggestedTarget })
+	return result, nil
+}
+
+// ValidateMerge checks that the target and every source exist and that no
+// source is the target
+func ValidateMerge(ctx context.Context, s Storage, targetID string, sourceIDs []string) error {
+	if len(sourceIDs) == 0 {
+		return fmt.Errorf("no source issues to merge")
+	}
+
+	target, err := s.GetIssue(ctx, targetID)
+	if err != nil || target == nil {
+		return fmt.Errorf("target issue not found: %s", targetID)
+	}
+
+	for _, sourceID := range sourceIDs {
+		if sourceID == targetID {
+			return fmt.Errorf("cannot merge issue into itself: %s", sourceID)
+		}
+
+		source, err := s.GetIssue(ctx, sourceID)
+		if err != nil || source == nil {
+			return fmt.Errorf("source issue not found: %s", sourceID)
+		}
+	}
+
+	return nil
+}
+
+// MergeIssues merges source issues into a target. It is idempotent and safe
+// to retry after a partial failure:
+//  1. Dependencies of and on each source move to the target (existing ones are skipped)
+//  2. Sources are linked to the target as duplicates (existing links are skipped)
+//  3. Comments and labels of each source are copied to the target (ones it already has are skipped)
+//  4. Mentions of the sources in other issues' text are rewritten to the target
+//  5. Sources are closed with reason 'Merged into <target>' (already closed ones are skipped)
+//
+// Call ValidateMerge first.
+// TODO(bd-202): Add transaction support for atomicity
+func MergeIssues(ctx context.Context, s Storage, targetID string, sourceIDs []string, actor string) (*types.MergeResult, error) {
+	result := &types.MergeResult{TargetID: targetID, SourceIDs: sourceIDs, Merged: len(sourceIDs)}
+
+	// Step 1: Migrate dependencies from source issues to target
+	for _, sourceID := range sourceIDs {
+		// Get all dependencies where source is the dependent (source depends on X)
+		deps, err := s.GetDependencyRecords(ctx, sourceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get dependencies for %s: %w", sourceID, err)
+		}
+
+		// Migrate each dependency to target
+		for _, dep := range deps {
+			// Skip if target already has this dependency
+			existingDeps, err := s.GetDependencyRecords(ctx, targetID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check target dependencies: %w", err)
+			}
+
+			alreadyExists := false
+			for _, existing := range existingDeps {
+				if existing.DependsOnID == dep.DependsOnID && existing.Type == dep.Type {
+					alreadyExists = true
+					break
+				}
+			}
+
+			if alreadyExists || dep.DependsOnID == targetID {
+				result.DependenciesSkipped++
+			} else {
+				// Add dependency to target
+				newDep := &types.Dependency{
+					IssueID:     targetID,
+					DependsOnID: dep.DependsOnID,
+					Type:        dep.Type,
+					CreatedAt:   time.Now(),
+					CreatedBy:   actor,
+				}
+				if err := s.AddDependency(ctx, newDep, actor); err != nil {
+					return nil, fmt.Errorf("failed to migrate dependency %s -> %s: %w", targetID, dep.DependsOnID, err)
+				}
+				result.DependenciesAdded++
+			}
+		}
+
+		// Get all dependencies where source is the dependency (X depends on source)
+		allDeps, err := s.GetAllDependencyRecords(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get all dependencies: %w", err)
+		}
+
+		for issueID, depList := range allDeps {
+			for _, dep := range depList {
+				if dep.DependsOnID == sourceID {
+					// Remove old dependency
+					if err := s.RemoveDependency(ctx, issueID, sourceID, actor); err != nil {
+						// Ignore "not found" errors as they may have been cleaned up
+						if !strings.Contains(err.Error(), "not found") {
+							return nil, fmt.Errorf("failed to remove dependency %s -> %s: %w", issueID, sourceID, err)
+						}
+					}
+
+					// Add new dependency to target (if not self-reference)
+					if issueID != targetID {
+						newDep := &types.Dependency{
+							IssueID:     issueID,
+							DependsOnID: targetID,
+							Type:        dep.Type,
+							CreatedAt:   time.Now(),
+							CreatedBy:   actor,
+						}
+						if err := s.AddDependency(ctx, newDep, actor); err != nil {
+							// Ignore if dependency already exists
+							if !strings.Contains(err.Error(), "UNIQUE constraint failed") {
+								return nil, fmt.Errorf("failed to add dependency %s -> %s: %w", issueID, targetID, err)
+							}
+							result.DependenciesSkipped++
+						} else {
+							result.DependenciesAdded++
+						}
+					}
+				}
+			}
+		}
+	}
+
+	// Step 2: Link sources as duplicates before copying comments, since copies
+	// name their source and would otherwise link the target to it as related
+	for _, sourceID := range sourceIDs {
+		if err := linkDuplicate(ctx, s, sourceID, targetID, actor); err != nil {
+			return nil, err
+		}
+	}
+
+	// Step 3: Copy comments and labels to target
+	if err := mergeCommentsAndLabels(ctx, s, targetID, sourceIDs, actor, result); err != nil {
+		return nil, err
+	}
+
+	// Step 4: Update text references in all issues
+	refCount, err := updateMergeTextReferences(ctx, s, sourceIDs, targetID, actor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update text references: %w", err)
+	}
+	result.TextReferences = refCount
+
+	// Step 5: Close source issues (idempotent - skip if already closed)
+	for _, sourceID := range sourceIDs {
+		issue, err := s.GetIssue(ctx, sourceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get source issue %s: %w", sourceID, err)
+		}
+		if issue == nil {
+			return nil, fmt.Errorf("source issue not found: %s", sourceID)
+		}
+
+		if issue.Status == types.StatusClosed {
+			// Already closed - skip
+			result.IssuesSkipped++
+		} else {
+			reason := fmt.Sprintf("Merged into %s", targetID)
+			if err := s.CloseIssue(ctx, sourceID, reason, actor); err != nil {
+				return nil, fmt.Errorf("failed to close source issue %s: %w", sourceID, err)
+			}
+			result.IssuesClosed++
+		}
+	}
+
+	return result, nil
+}
+
+// mergedCommentPrefix marks a comment copied from a merged issue
+const mergedCommentPrefix = "[from %s] "
+
+// mergeCommentsAndLabels copies comments and labels from each source to the
+// target, skipping any the target already has. Copied comments keep their
+// author and are prefixed with the source ID.
+func mergeCommentsAndLabels(ctx context.Context, s Storage, targetID string, sourceIDs []string, actor string, result *types.MergeResult) error {
+	targetComments, err := s.GetIssueComments(ctx, targetID)
+	if err != nil {
+		return fmt.Errorf("failed to get comments for %s: %w", targetID, err)
+	}
+	seen := make(map[[2]string]bool)
+	for _, c := range targetComments {
+		seen[[2]string{c.Author, c.Text}] = true
+	}
+
+	targetLabels, err := s.GetLabels(ctx, targetID)
+	if err != nil {
+		return fmt.Errorf("failed to get labels for %s: %w", targetID, err)
+	}
+	hasLabel := make(map[string]bool)
+	for _, label := range targetLabels {
+		hasLabel[label] = true
+	}
+
+	for _, sourceID := range sourceIDs {
+		comments, err := s.GetIssueComments(ctx, sourceID)
+		if err != nil {
+			return fmt.Errorf("failed to get comments for %s: %w", sourceID, err)
+		}
+		for _, c := range comments {
+			text := fmt.Sprintf(mergedCommentPrefix, sourceID) + c.Text
+			if seen[[2]string{c.Author, text}] {
+				continue
+			}
+			if _, err := s.AddIssueComment(ctx, targetID, c.Author, text); err != nil {
+				return fmt.Errorf("failed to copy comment %d from %s: %w", c.ID, sourceID, err)
+			}
+			seen[[2]string{c.Author, text}] = true
+			result.CommentsMoved++
+		}
+
+		labels, err := s.GetLabels(ctx, sourceID)
+		if err != nil {
+			return fmt.Errorf("failed to get labels for %s: %w", sourceID, err)
+		}
+		for _, label := range labels {
+			if hasLabel[label] {
+				continue
+			}
+			if err := s.AddLabel(ctx, targetID, label, actor); err != nil {
+				return fmt.Errorf("failed to copy label %s from %s: %w", label, sourceID, err)
+			}
+			hasLabel[label] = true
+			result.LabelsMoved++
+		}
+	}
+	return nil
+}
+
+// linkDuplicate records that sourceID is a duplicate of targetID, unless it
+// already is
+func linkDuplicate(ctx context.Context, s Storage, sourceID, targetID, actor string) error {
+	deps, err := s.GetDependencyRecords(ctx, sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to get dependencies for %s: %w", sourceID, err)
+	}
+	for _, dep := range deps {
+		if dep.DependsOnID == targetID && dep.Type == types.DepDuplicates {
+			return nil
+		}
+	}
+	dep := &types.Dependency{
+		IssueID:     sourceID,
+		DependsOnID: targetID,
+		Type:        types.DepDuplicates,
+		CreatedAt:   time.Now(),
+		CreatedBy:   actor,
+	}
+	if err := s.AddDependency(ctx, dep, actor); err != nil {
+		return fmt.Errorf("failed to link %s as a duplicate of %s: %w", sourceID, targetID, err)
+	}
+	return nil
+}
+
+// updateMergeTextReferences updates text references from source IDs to target ID
+// Returns the count of issues updated
+func updateMergeTextReferences(ctx context.Context, s Storage, sourceIDs []string, targetID, actor string) (int, error) {
+	// Build regex patterns to match issue IDs with word boundaries
+	patterns := make([]*regexp.Regexp, len(sourceIDs))
+	for i, sourceID := range sourceIDs {
+		patterns[i] = regexp.MustCompile(`(^|[^A-Za-z0-9_-])(` + regexp.QuoteMeta(sourceID) + `)($|[^A-Za-z0-9_-])`)
+	}
+	replacementText := `$1` + targetID + `$3`
+
+	// Scan every issue for references, applying the rewrites once the scan
+	// is done rather than writing mid-iteration
+	type pendingUpdate struct {
+		id      string
+		updates map[string]interface{}
+	}
+	var pending []pendingUpdate
+	err := s.ForEachIssue(ctx, types.IssueFilter{}, func(issue *types.Issue) error {
+		// Skip source issues (they're being closed anyway)
+		for _, srcID := range sourceIDs {
+			if issue.ID == srcID {
+				return nil
+			}
+		}
+
+		updates := make(map[string]interface{})
+		fields := []struct {
+			name string
+			text string
+		}{
+			{"description", issue.Description},
+			{"notes", issue.Notes},
+			{"design", issue.Design},
+			{"acceptance_criteria", issue.AcceptanceCriteria},
+		}
+		for _, field := range fields {
+			text := field.text
+			for _, re := range patterns {
+				text = re.ReplaceAllString(text, replacementText)
+			}
+			if text != field.text {
+				updates[field.name] = text
+			}
+		}
+
+		if len(updates) > 0 {
+			pending = append(pending, pendingUpdate{issue.ID, updates})
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get all issues: %w", err)
+	}
+
+	updatedCount := 0
+	for _, p := range pending {
+		if err := s.UpdateIssue(ctx, p.id, p.updates, actor); err != nil {
+			return updatedCount, fmt.Errorf("failed to update issue %s: %w", p.id, err)
+		}
+		updatedCount++
+	}
+
+	return updatedCount, nil
+}
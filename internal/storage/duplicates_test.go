@@ -0,0 +1,477 @@
+package storage_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage"
+	"github.com/imalsogreg/beads/internal/storage/memory"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestFindDuplicateGroups(t *testing.T) {
+	tests := []struct {
+		name           string
+		issues         []*types.Issue
+		expectedGroups int
+	}{
+		{
+			name: "no duplicates",
+			issues: []*types.Issue{
+				{ID: "bd-1", Title: "Task 1", Status: types.StatusOpen},
+				{ID: "bd-2", Title: "Task 2", Status: types.StatusOpen},
+			},
+			expectedGroups: 0,
+		},
+		{
+			name: "simple duplicate",
+			issues: []*types.Issue{
+				{ID: "bd-1", Title: "Task 1", Status: types.StatusOpen},
+				{ID: "bd-2", Title: "Task 1", Status: types.StatusOpen},
+			},
+			expectedGroups: 1,
+		},
+		{
+			name: "duplicate with different status ignored",
+			issues: []*types.Issue{
+				{ID: "bd-1", Title: "Task 1", Status: types.StatusOpen},
+				{ID: "bd-2", Title: "Task 1", Status: types.StatusClosed},
+			},
+			expectedGroups: 0,
+		},
+		{
+			name: "multiple duplicates",
+			issues: []*types.Issue{
+				{ID: "bd-1", Title: "Task 1", Status: types.StatusOpen},
+				{ID: "bd-2", Title: "Task 1", Status: types.StatusOpen},
+				{ID: "bd-3", Title: "Task 2", Status: types.StatusOpen},
+				{ID: "bd-4", Title: "Task 2", Status: types.StatusOpen},
+			},
+			expectedGroups: 2,
+		},
+		{
+			name: "different descriptions are duplicates if title matches",
+			issues: []*types.Issue{
+				{ID: "bd-1", Title: "Task 1", Description: "Desc 1", Status: types.StatusOpen},
+				{ID: "bd-2", Title: "Task 1", Description: "Desc 2", Status: types.StatusOpen},
+			},
+			expectedGroups: 0, // Different descriptions = not duplicates
+		},
+		{
+			name: "exact content match",
+			issues: []*types.Issue{
+				{ID: "bd-1", Title: "Task 1", Description: "Desc 1", Design: "Design 1", AcceptanceCriteria: "AC 1", Status: types.StatusOpen},
+				{ID: "bd-2", Title: "Task 1", Description: "Desc 1", Design: "Design 1", AcceptanceCriteria: "AC 1", Status: types.StatusOpen},
+			},
+			expectedGroups: 1,
+		},
+		{
+			name: "three-way duplicate",
+			issues: []*types.Issue{
+				{ID: "bd-1", Title: "Task 1", Status: types.StatusOpen},
+				{ID: "bd-2", Title: "Task 1", Status: types.StatusOpen},
+				{ID: "bd-3", Title: "Task 1", Status: types.StatusOpen},
+			},
+			expectedGroups: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groups := storage.FindDuplicateGroups(tt.issues)
+			if len(groups) != tt.expectedGroups {
+				t.Errorf("FindDuplicateGroups() returned %d groups, want %d", len(groups), tt.expectedGroups)
+			}
+		})
+	}
+}
+
+func TestChooseMergeTarget(t *testing.T) {
+	tests := []struct {
+		name      string
+		group     []*types.Issue
+		refCounts map[string]int
+		wantID    string
+	}{
+		{
+			name: "choose by reference count",
+			group: []*types.Issue{
+				{ID: "bd-2", Title: "Task"},
+				{ID: "bd-1", Title: "Task"},
+			},
+			refCounts: map[string]int{
+				"bd-1": 5,
+				"bd-2": 0,
+			},
+			wantID: "bd-1",
+		},
+		{
+			name: "choose by lexicographic order if same references",
+			group: []*types.Issue{
+				{ID: "bd-2", Title: "Task"},
+				{ID: "bd-1", Title: "Task"},
+			},
+			refCounts: map[string]int{
+				"bd-1": 0,
+				"bd-2": 0,
+			},
+			wantID: "bd-1",
+		},
+		{
+			name: "prefer higher references even with larger ID",
+			group: []*types.Issue{
+				{ID: "bd-1", Title: "Task"},
+				{ID: "bd-100", Title: "Task"},
+			},
+			refCounts: map[string]int{
+				"bd-1":   1,
+				"bd-100": 10,
+			},
+			wantID: "bd-100",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := storage.ChooseMergeTarget(tt.group, tt.refCounts)
+			if target.ID != tt.wantID {
+				t.Errorf("ChooseMergeTarget() = %v, want %v", target.ID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestCountReferences(t *testing.T) {
+	issues := []*types.Issue{
+		{
+			ID:          "bd-1",
+			Description: "See bd-2 for details",
+			Notes:       "Related to bd-3",
+		},
+		{
+			ID:          "bd-2",
+			Description: "Mentioned bd-1 twice: bd-1",
+		},
+		{
+			ID:    "bd-3",
+			Notes: "Nothing to see here",
+		},
+	}
+
+	counts := storage.CountReferences(issues)
+
+	expectedCounts := map[string]int{
+		"bd-1": 2, // Referenced twice in bd-2
+		"bd-2": 1, // Referenced once in bd-1
+		"bd-3": 1, // Referenced once in bd-1
+	}
+
+	for id, expectedCount := range expectedCounts {
+		if counts[id] != expectedCount {
+			t.Errorf("CountReferences()[%s] = %d, want %d", id, counts[id], expectedCount)
+		}
+	}
+}
+
+func TestDuplicateGroupsWithDifferentStatuses(t *testing.T) {
+	issues := []*types.Issue{
+		{ID: "bd-1", Title: "Task 1", Status: types.StatusOpen},
+		{ID: "bd-2", Title: "Task 1", Status: types.StatusClosed},
+		{ID: "bd-3", Title: "Task 1", Status: types.StatusOpen},
+	}
+
+	groups := storage.FindDuplicateGroups(issues)
+
+	// Should have 1 group with bd-1 and bd-3 (both open)
+	if len(groups) != 1 {
+		t.Fatalf("Expected 1 group, got %d", len(groups))
+	}
+
+	if len(groups[0]) != 2 {
+		t.Fatalf("Expected 2 issues in group, got %d", len(groups[0]))
+	}
+
+	// Verify bd-2 (closed) is not in the group
+	for _, issue := range groups[0] {
+		if issue.ID == "bd-2" {
+			t.Errorf("bd-2 (closed) should not be in group with open issues")
+		}
+	}
+}
+
+func TestDuplicatesIntegration(t *testing.T) {
+	ctx := context.Background()
+	testStore := memory.New("")
+	defer testStore.Close()
+	if err := testStore.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+
+	// Create duplicate issues
+	issues := []*types.Issue{
+		{
+			ID:          "bd-1",
+			Title:       "Fix authentication bug",
+			Description: "Users can't login",
+			Status:      types.StatusOpen,
+			Priority:    1,
+			IssueType:   types.TypeBug,
+		},
+		{
+			ID:          "bd-2",
+			Title:       "Fix authentication bug",
+			Description: "Users can't login",
+			Status:      types.StatusOpen,
+			Priority:    1,
+			IssueType:   types.TypeBug,
+		},
+		{
+			ID:          "bd-3",
+			Title:       "Different task",
+			Description: "Different description",
+			Status:      types.StatusOpen,
+			Priority:    2,
+			IssueType:   types.TypeTask,
+		},
+	}
+
+	for _, issue := range issues {
+		if err := testStore.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+
+	// Fetch all issues
+	allIssues, err := testStore.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+
+	// Find duplicates
+	groups := storage.FindDuplicateGroups(allIssues)
+
+	if len(groups) != 1 {
+		t.Fatalf("Expected 1 duplicate group, got %d", len(groups))
+	}
+
+	if len(groups[0]) != 2 {
+		t.Fatalf("Expected 2 issues in group, got %d", len(groups[0]))
+	}
+
+	// Verify the duplicate group contains bd-1 and bd-2
+	ids := make(map[string]bool)
+	for _, issue := range groups[0] {
+		ids[issue.ID] = true
+	}
+
+	if !ids["bd-1"] || !ids["bd-2"] {
+		t.Errorf("Expected duplicate group to contain bd-1 and bd-2")
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"Fix login bug", "fix login bug", 1},
+		{"Fix login bug", "Fix the login bug!", 0.75},
+		{"Fix login bug", "Add dark mode", 0},
+	}
+	for _, tt := range tests {
+		got := storage.Similarity(&types.Issue{Title: tt.a}, &types.Issue{Title: tt.b})
+		if got != tt.want {
+			t.Errorf("Similarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestFindSimilarGroups(t *testing.T) {
+	issues := []*types.Issue{
+		{ID: "bd-1", Title: "Login fails on Safari", Description: "Users can't log in", Status: types.StatusOpen},
+		{ID: "bd-2", Title: "Login fails on Safari browser", Description: "Users can't log in", Status: types.StatusOpen},
+		{ID: "bd-3", Title: "Login fails on Safari", Description: "Users can't log in", Status: types.StatusClosed},
+		{ID: "bd-4", Title: "Add dark mode", Status: types.StatusOpen},
+	}
+
+	if groups := storage.FindSimilarGroups(issues, 1); len(groups) != 0 {
+		t.Errorf("Expected no groups at threshold 1, got %d", len(groups))
+	}
+
+	groups := storage.FindSimilarGroups(issues, 0.85)
+	if len(groups) != 1 || len(groups[0]) != 2 {
+		t.Fatalf("Expected 1 group of 2, got %v", groups)
+	}
+	for _, issue := range groups[0] {
+		if issue.ID != "bd-1" && issue.ID != "bd-2" {
+			t.Errorf("Unexpected %s in group", issue.ID)
+		}
+	}
+}
+
+func TestFindDuplicatesFuzzy(t *testing.T) {
+	ctx := context.Background()
+	testStore := memory.New("")
+	defer testStore.Close()
+	if err := testStore.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+	for _, title := range []string{"Crash when saving large files", "Crash when saving very large files", "Crash when saving large files"} {
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeBug}
+		if err := testStore.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+
+	exact, err := storage.FindDuplicates(ctx, testStore, 0)
+	if err != nil {
+		t.Fatalf("FindDuplicates failed: %v", err)
+	}
+	if len(exact) != 1 || len(exact[0].Issues) != 2 {
+		t.Fatalf("Expected one exact group of 2, got %+v", exact)
+	}
+
+	fuzzy, err := storage.FindDuplicates(ctx, testStore, 0.8)
+	if err != nil {
+		t.Fatalf("FindDuplicates failed: %v", err)
+	}
+	if len(fuzzy) != 1 || len(fuzzy[0].Issues) != 3 || fuzzy[0].SuggestedTarget != "bd-1" {
+		t.Fatalf("Expected one fuzzy group of 3 targeting bd-1, got %+v", fuzzy)
+	}
+	scores := map[string]float64{}
+	for _, issue := range fuzzy[0].Issues {
+		scores[issue.ID] = issue.Score
+	}
+	if scores["bd-1"] != 1 || scores["bd-3"] != 1 || scores["bd-2"] != 5.0/6 {
+		t.Errorf("Unexpected scores %v", scores)
+	}
+
+	if _, err := storage.FindDuplicates(ctx, testStore, 1.5); err == nil {
+		t.Error("Expected an error for a threshold above 1")
+	}
+}
+
+// newPagedStore returns a SQLite store with a single read connection, so
+// reading issues a page at a time while the callback queries the store
+// deadlocks instead of passing by luck
+func newPagedStore(t *testing.T) (*sqlite.SQLiteStorage, context.Context) {
+	t.Helper()
+	store, err := sqlite.NewWithConfig(filepath.Join(t.TempDir(), "test.db"), sqlite.PoolConfig{ReadConnections: 1})
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	t.Cleanup(cancel)
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+	return store, ctx
+}
+
+func TestFindDuplicatesAcrossPages(t *testing.T) {
+	store, ctx := newPagedStore(t)
+
+	// Pairs of duplicates a page or two apart, among unique issues; the later
+	// of the first pair is referenced, so it's the suggested target
+	const n, pairs, gap = 1100, 10, 600
+	for i := 1; i <= n; i++ {
+		issue := &types.Issue{
+			ID:        fmt.Sprintf("bd-%d", i),
+			Title:     fmt.Sprintf("Unique task %d", i),
+			Status:    types.StatusOpen,
+			Priority:  2,
+			IssueType: types.TypeTask,
+		}
+		if k := (i - 1) % gap; k < pairs {
+			issue.Title = fmt.Sprintf("Duplicate %d", k)
+		}
+		if i == n {
+			issue.Description = fmt.Sprintf("See bd-%d", 1+gap)
+		}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+
+	for _, threshold := range []float64{0, 0.9} {
+		groups, err := storage.FindDuplicates(ctx, store, threshold)
+		if err != nil {
+			t.Fatalf("FindDuplicates(%g) failed: %v", threshold, err)
+		}
+		if len(groups) != pairs {
+			t.Fatalf("FindDuplicates(%g): expected %d groups, got %d", threshold, pairs, len(groups))
+		}
+		for _, group := range groups {
+			if len(group.Issues) != 2 {
+				t.Errorf("FindDuplicates(%g): expected pairs, got %+v", threshold, group)
+			}
+			if group.Title == "Duplicate 0" && group.SuggestedTarget != fmt.Sprintf("bd-%d", 1+gap) {
+				t.Errorf("FindDuplicates(%g): expected the referenced issue as target, got %s", threshold, group.SuggestedTarget)
+			}
+		}
+	}
+}
+
+func TestMergeIssuesMovesCommentsAndLabels(t *testing.T) {
+	ctx := context.Background()
+	testStore := memory.New("")
+	defer testStore.Close()
+	if err := testStore.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+	for _, title := range []string{"Login fails", "Login broken", "See bd-2 for details"} {
+		issue := &types.Issue{Title: title, Description: title, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeBug}
+		if err := testStore.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+	if _, err := testStore.AddIssueComment(ctx, "bd-2", "alice", "Repro: use Safari"); err != nil {
+		t.Fatal(err)
+	}
+	for _, label := range []string{"auth", "frontend"} {
+		if err := testStore.AddLabel(ctx, "bd-2", label, "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := testStore.AddLabel(ctx, "bd-1", "auth", "test"); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := storage.MergeIssues(ctx, testStore, "bd-1", []string{"bd-2"}, "bob")
+	if err != nil {
+		t.Fatalf("MergeIssues failed: %v", err)
+	}
+	if result.CommentsMoved != 1 || result.LabelsMoved != 1 || result.TextReferences != 1 || result.IssuesClosed != 1 {
+		t.Errorf("Unexpected result %+v", result)
+	}
+
+	comments, _ := testStore.GetIssueComments(ctx, "bd-1")
+	if len(comments) != 1 || comments[0].Author != "alice" || comments[0].Text != "[from bd-2] Repro: use Safari" {
+		t.Errorf("Unexpected comments on target: %+v", comments)
+	}
+	labels, _ := testStore.GetLabels(ctx, "bd-1")
+	if len(labels) != 2 {
+		t.Errorf("Expected auth and frontend on target, got %v", labels)
+	}
+	deps, _ := testStore.GetDependencyRecords(ctx, "bd-2")
+	if len(deps) != 1 || deps[0].DependsOnID != "bd-1" || deps[0].Type != types.DepDuplicates {
+		t.Errorf("Expected bd-2 to be a duplicate of bd-1, got %+v", deps)
+	}
+
+	// Retrying changes nothing
+	result, err = storage.MergeIssues(ctx, testStore, "bd-1", []string{"bd-2"}, "bob")
+	if err != nil {
+		t.Fatalf("MergeIssues retry failed: %v", err)
+	}
+	if result.CommentsMoved != 0 || result.LabelsMoved != 0 || result.IssuesSkipped != 1 {
+		t.Errorf("Expected retry to be a no-op, got %+v", result)
+	}
+	if deps, _ := testStore.GetDependencyRecords(ctx, "bd-2"); len(deps) != 1 {
+		t.Errorf("Expected one duplicate link after retry, got %+v", deps)
+	}
+}
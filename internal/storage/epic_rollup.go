@@ -0,0 +1,172 @@
+package storage
+
+import (
+	"context"
+	"sort"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// GetEpicRollup reports an epic's progress over all its descendants (via
+// parent-child dependencies, recursively). Nested epics aren't counted as
+// work themselves; their descendants count toward them and every epic above
+// them. Progress is given both by issue count and by estimated minutes,
+// where unestimated issues are left out and counted separately.
+func GetEpicRollup(ctx context.Context, s Storage, epicID string) (*types.EpicRollup, error) {
+	epic, err := s.GetIssue(ctx, epicID)
+	if err != nil {
+		return nil, err
+	}
+	if epic == nil {
+		return nil, types.NotFound("issue", epicID)
+	}
+
+	b := &rollupBuilder{
+		s:       s,
+		visited: map[string]bool{epicID: true},
+		issues:  make(map[string]*types.Issue),
+	}
+	roll, _, err := b.rollup(ctx, epic)
+	return roll, err
+}
+
+// rollupBuilder walks an epic's descendants once, sharing what it looks up
+// between the nested epics' rollups
+type rollupBuilder struct {
+	s       Storage
+	visited map[string]bool
+	issues  map[string]*types.Issue // Blockers looked up so far
+}
+
+// rollup builds the rollup of an epic, returning it and the epic's
+// descendants: its work issues and nested epics
+func (b *rollupBuilder) rollup(ctx context.Context, epic *types.Issue) (*types.EpicRollup, []*types.Issue, error) {
+	roll := &types.EpicRollup{Epic: epic}
+	descendants, err := b.walk(ctx, epic.ID, roll)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	under := make(map[string]bool)
+	for _, issue := range descendants {
+		under[issue.ID] = true
+	}
+	blockers := make(map[string]*types.EpicBlocker)
+	for _, issue := range descendants {
+		if issue.IssueType == types.TypeEpic {
+			continue
+		}
+		roll.Total++
+		switch issue.Status {
+		case types.StatusClosed:
+			roll.Closed++
+		case types.StatusInProgress:
+			roll.InProgress++
+		}
+		if issue.EstimatedMinutes == nil {
+			roll.Unestimated++
+		} else {
+			roll.EstimatedMinutes += *issue.EstimatedMinutes
+			if issue.Status == types.StatusClosed {
+				roll.ClosedMinutes += *issue.EstimatedMinutes
+			}
+		}
+		if issue.Status == types.StatusClosed {
+			continue
+		}
+
+		blocked := issue.Status == types.StatusBlocked
+		deps, err := b.s.GetDependencyRecords(ctx, issue.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, dep := range deps {
+			if dep.Type != types.DepBlocks {
+				continue
+			}
+			blocker, err := b.issue(ctx, dep.DependsOnID)
+			if err != nil {
+				return nil, nil, err
+			}
+			if blocker == nil || blocker.Status == types.StatusClosed {
+				continue
+			}
+			blocked = true
+			eb := blockers[blocker.ID]
+			if eb == nil {
+				eb = &types.EpicBlocker{Issue: blocker, External: !under[blocker.ID]}
+				blockers[blocker.ID] = eb
+			}
+			eb.Blocks = append(eb.Blocks, issue.ID)
+		}
+		if blocked {
+			roll.Blocked++
+		}
+	}
+
+	if roll.Total > 0 {
+		roll.Percent = roll.Closed * 100 / roll.Total
+	}
+	if roll.EstimatedMinutes > 0 {
+		roll.PercentByMinutes = roll.ClosedMinutes * 100 / roll.EstimatedMinutes
+	}
+	for _, eb := range blockers {
+		sort.Strings(eb.Blocks)
+		roll.Blockers = append(roll.Blockers, eb)
+	}
+	sort.Slice(roll.Blockers, func(i, j int) bool {
+		a, c := roll.Blockers[i], roll.Blockers[j]
+		if len(a.Blocks) != len(c.Blocks) {
+			return len(a.Blocks) > len(c.Blocks)
+		}
+		return preferIssue(a.Issue, c.Issue)
+	})
+	return roll, descendants, nil
+}
+
+// walk returns the descendants of parentID not seen yet, adding the rollup
+// of each nested epic among them to roll
+func (b *rollupBuilder) walk(ctx context.Context, parentID string, roll *types.EpicRollup) ([]*types.Issue, error) {
+	children, err := b.s.GetChildren(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+	var descendants []*types.Issue
+	for _, child := range children {
+		if b.visited[child.ID] {
+			continue
+		}
+		b.visited[child.ID] = true
+		b.issues[child.ID] = child
+		descendants = append(descendants, child)
+
+		var more []*types.Issue
+		if child.IssueType == types.TypeEpic {
+			var nested *types.EpicRollup
+			nested, more, err = b.rollup(ctx, child)
+			if err == nil {
+				roll.Epics = append(roll.Epics, nested)
+			}
+		} else {
+			more, err = b.walk(ctx, child.ID, roll)
+		}
+		if err != nil {
+			return nil, err
+		}
+		descendants = append(descendants, more...)
+	}
+	return descendants, nil
+}
+
+// issue looks up an issue, remembering it for the rest of the walk
+func (b *rollupBuilder) issue(ctx context.Context, id string) (*types.Issue, error) {
+	if issue, ok := b.issues[id]; ok {
+		return issue, nil
+	}
+	issue, err := b.s.GetIssue(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	b.issues[id] = issue
+	return issue, nil
+}
@@ -0,0 +1,88 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage"
+	"github.com/imalsogreg/beads/internal/storage/memory"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestGetEpicRollup(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New("")
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+
+	create := func(title string, minutes *int, issueType types.IssueType, parentID string) *types.Issue {
+		t.Helper()
+		issue := &types.Issue{
+			Title:            title,
+			Status:           types.StatusOpen,
+			Priority:         2,
+			IssueType:        issueType,
+			EstimatedMinutes: minutes,
+			ParentID:         parentID,
+		}
+		if err := store.CreateIssue(ctx, issue, "test-user"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		return issue
+	}
+	blocks := func(blocker, blocked *types.Issue) {
+		t.Helper()
+		dep := &types.Dependency{IssueID: blocked.ID, DependsOnID: blocker.ID, Type: types.DepBlocks}
+		if err := store.AddDependency(ctx, dep, "test-user"); err != nil {
+			t.Fatalf("AddDependency failed: %v", err)
+		}
+	}
+	mins := func(m int) *int { return &m }
+
+	epic := create("Launch", nil, types.TypeEpic, "")
+	backend := create("Backend", nil, types.TypeEpic, epic.ID)
+	api := create("API", mins(120), types.TypeTask, backend.ID)
+	schema := create("Schema", mins(60), types.TypeTask, backend.ID)
+	migration := create("Migration", nil, types.TypeTask, schema.ID) // subtask of a task in the nested epic
+	docs := create("Docs", mins(60), types.TypeTask, epic.ID)
+	vendor := create("Vendor contract", nil, types.TypeTask, "")
+
+	blocks(schema, api)
+	blocks(vendor, api)
+	blocks(vendor, docs)
+	if err := store.CloseIssue(ctx, schema.ID, "done", "test-user"); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+	if err := store.UpdateIssue(ctx, migration.ID, map[string]interface{}{"status": string(types.StatusInProgress)}, "test-user"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+
+	roll, err := storage.GetEpicRollup(ctx, store, epic.ID)
+	if err != nil {
+		t.Fatalf("GetEpicRollup failed: %v", err)
+	}
+	if roll.Total != 4 || roll.Closed != 1 || roll.InProgress != 1 || roll.Blocked != 2 || roll.Percent != 25 {
+		t.Errorf("Unexpected counts %+v", roll)
+	}
+	if roll.EstimatedMinutes != 240 || roll.ClosedMinutes != 60 || roll.PercentByMinutes != 25 || roll.Unestimated != 1 {
+		t.Errorf("Unexpected minutes %+v", roll)
+	}
+	if len(roll.Blockers) != 1 || roll.Blockers[0].Issue.ID != vendor.ID || !roll.Blockers[0].External ||
+		len(roll.Blockers[0].Blocks) != 2 {
+		t.Errorf("Expected the vendor contract blocking API and docs from outside, got %+v", roll.Blockers)
+	}
+
+	if len(roll.Epics) != 1 || roll.Epics[0].Epic.ID != backend.ID {
+		t.Fatalf("Expected the backend epic nested, got %+v", roll.Epics)
+	}
+	nested := roll.Epics[0]
+	if nested.Total != 3 || nested.Closed != 1 || nested.Blocked != 1 || nested.PercentByMinutes != 33 {
+		t.Errorf("Unexpected nested rollup %+v", nested)
+	}
+
+	if _, err := storage.GetEpicRollup(ctx, store, "bd-999"); err == nil {
+		t.Error("Expected an error for a missing epic")
+	}
+}
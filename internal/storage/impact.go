@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"context"
+	"sort"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// impactEdge is a way blockage spreads from one issue to another
+type impactEdge struct {
+	to        string
+	inherited bool
+}
+
+// GetBlockingImpact finds the unfinished issues an issue holds up.
+//
+// Blockage spreads the way ready work sees it: an open issue blocks the
+// issues that depend on it with 'blocks' dependencies, and a blocked issue
+// also holds up its children. Closed issues are neither blocked nor passed
+// through, and a closed issue blocks nothing. Each issue is reported once,
+// at its nearest distance.
+func GetBlockingImpact(ctx context.Context, s Storage, issueID string) (*types.BlockingImpact, error) {
+	root, err := s.GetIssue(ctx, issueID)
+	if err != nil {
+		return nil, err
+	}
+	if root == nil {
+		return nil, types.NotFound("issue", issueID)
+	}
+
+	impact := &types.BlockingImpact{Issue: root, Blocked: []*types.ImpactedIssue{}, ByPriority: map[int]int{}}
+	if root.Status == types.StatusClosed {
+		return impact, nil
+	}
+
+	all, err := s.GetAllDependencyRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+	edges := make(map[string][]impactEdge)
+	for _, deps := range all {
+		for _, dep := range deps {
+			switch dep.Type {
+			case types.DepBlocks:
+				edges[dep.DependsOnID] = append(edges[dep.DependsOnID], impactEdge{to: dep.IssueID})
+			case types.DepParentChild:
+				edges[dep.DependsOnID] = append(edges[dep.DependsOnID], impactEdge{to: dep.IssueID, inherited: true})
+			}
+		}
+	}
+
+	// Breadth-first, one level at a time so each level can be sorted
+	visited := map[string]bool{root.ID: true}
+	level := []*types.ImpactedIssue{{Issue: root}}
+	for depth := 1; len(level) > 0; depth++ {
+		var next []*types.ImpactedIssue
+		for _, from := range level {
+			for _, edge := range edges[from.Issue.ID] {
+				// The issue's own children aren't blocked by it, only its blockees' are
+				if visited[edge.to] || (edge.inherited && from.Issue.ID == root.ID) {
+					continue
+				}
+				issue, err := s.GetIssue(ctx, edge.to)
+				if err != nil {
+					return nil, err
+				}
+				if issue == nil || issue.Status == types.StatusClosed {
+					continue
+				}
+				visited[edge.to] = true
+				next = append(next, &types.ImpactedIssue{Issue: issue, Depth: depth, BlockedBy: from.Issue.ID, Inherited: edge.inherited})
+			}
+		}
+		sort.Slice(next, func(i, j int) bool {
+			return preferIssue(next[i].Issue, next[j].Issue)
+		})
+		impact.Blocked = append(impact.Blocked, next...)
+		level = next
+	}
+
+	for _, blocked := range impact.Blocked {
+		impact.Total++
+		if blocked.Depth == 1 {
+			impact.Direct++
+		}
+		impact.ByPriority[blocked.Issue.Priority]++
+		if impact.HighestPriority == nil || blocked.Issue.Priority < *impact.HighestPriority {
+			priority := blocked.Issue.Priority
+			impact.HighestPriority = &priority
+		}
+	}
+
+	return impact, nil
+}
@@ -0,0 +1,109 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage"
+	"github.com/imalsogreg/beads/internal/storage/memory"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestGetBlockingImpact(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New("")
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+
+	create := func(title string, priority int, parentID string) *types.Issue {
+		t.Helper()
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: priority, IssueType: types.TypeTask, ParentID: parentID}
+		if err := store.CreateIssue(ctx, issue, "test-user"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		return issue
+	}
+	blocks := func(blocker, blocked *types.Issue) {
+		t.Helper()
+		dep := &types.Dependency{IssueID: blocked.ID, DependsOnID: blocker.ID, Type: types.DepBlocks}
+		if err := store.AddDependency(ctx, dep, "test-user"); err != nil {
+			t.Fatalf("AddDependency failed: %v", err)
+		}
+	}
+
+	schema := create("Schema", 2, "")
+	create("Schema subtask", 2, schema.ID) // its own child: not blocked by it
+	api := create("API", 2, "")
+	client := create("Client", 1, api.ID)
+	docs := create("Docs", 3, "")
+	release := create("Release", 0, "")
+	shipped := create("Shipped", 0, "")
+	after := create("After shipped", 0, "")
+
+	blocks(schema, api)
+	blocks(schema, docs)
+	blocks(api, release)
+	blocks(docs, release) // reachable twice: reported once, at its nearest
+	blocks(schema, shipped)
+	blocks(shipped, after)
+	if err := store.CloseIssue(ctx, shipped.ID, "done", "test-user"); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+
+	impact, err := storage.GetBlockingImpact(ctx, store, schema.ID)
+	if err != nil {
+		t.Fatalf("GetBlockingImpact failed: %v", err)
+	}
+
+	want := []struct {
+		id        string
+		depth     int
+		by        string
+		inherited bool
+	}{
+		{api.ID, 1, schema.ID, false},
+		{docs.ID, 1, schema.ID, false},
+		{release.ID, 2, api.ID, false},
+		{client.ID, 2, api.ID, true},
+	}
+	if len(impact.Blocked) != len(want) {
+		for _, b := range impact.Blocked {
+			t.Logf("blocked: %s (depth %d)", b.Issue.Title, b.Depth)
+		}
+		t.Fatalf("expected %d blocked issues, got %d", len(want), len(impact.Blocked))
+	}
+	for i, w := range want {
+		got := impact.Blocked[i]
+		if got.Issue.ID != w.id || got.Depth != w.depth || got.BlockedBy != w.by || got.Inherited != w.inherited {
+			t.Errorf("blocked[%d]: got %s depth %d by %s inherited %v, want %s depth %d by %s inherited %v",
+				i, got.Issue.ID, got.Depth, got.BlockedBy, got.Inherited, w.id, w.depth, w.by, w.inherited)
+		}
+	}
+	if impact.Total != 4 || impact.Direct != 2 {
+		t.Errorf("expected 4 blocked, 2 directly; got %d, %d", impact.Total, impact.Direct)
+	}
+	if impact.HighestPriority == nil || *impact.HighestPriority != 0 {
+		t.Errorf("expected highest priority 0, got %v", impact.HighestPriority)
+	}
+	if impact.ByPriority[2] != 1 || impact.ByPriority[0] != 1 || impact.ByPriority[1] != 1 || impact.ByPriority[3] != 1 {
+		t.Errorf("unexpected priority counts %v", impact.ByPriority)
+	}
+
+	// A closed issue blocks nothing
+	if err := store.CloseIssue(ctx, schema.ID, "done", "test-user"); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+	impact, err = storage.GetBlockingImpact(ctx, store, schema.ID)
+	if err != nil {
+		t.Fatalf("GetBlockingImpact failed: %v", err)
+	}
+	if impact.Total != 0 || impact.HighestPriority != nil {
+		t.Errorf("expected no impact from a closed issue, got %d", impact.Total)
+	}
+
+	if _, err := storage.GetBlockingImpact(ctx, store, "bd-999"); err == nil {
+		t.Error("expected error for missing issue")
+	}
+}
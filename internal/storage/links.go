@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// GetIssueLinks returns the non-blocking links (related, duplicates,
+// caused-by) from and to an issue, outgoing links first
+func GetIssueLinks(ctx context.Context, s Storage, issueID string) ([]*types.IssueLink, error) {
+	records, err := s.GetDependencyRecords(ctx, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dependencies for %s: %w", issueID, err)
+	}
+	outgoing := make(map[string]types.DependencyType)
+	for _, dep := range records {
+		outgoing[dep.DependsOnID] = dep.Type
+	}
+
+	dependencies, err := s.GetDependencies(ctx, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dependencies for %s: %w", issueID, err)
+	}
+	links := []*types.IssueLink{}
+	for _, other := range dependencies {
+		if depType := outgoing[other.ID]; depType.IsLink() {
+			links = append(links, newIssueLink(other, depType, false))
+		}
+	}
+
+	dependents, err := s.GetDependents(ctx, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dependents of %s: %w", issueID, err)
+	}
+	for _, other := range dependents {
+		records, err := s.GetDependencyRecords(ctx, other.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get dependencies for %s: %w", other.ID, err)
+		}
+		for _, dep := range records {
+			if dep.DependsOnID == issueID && dep.Type.IsLink() {
+				links = append(links, newIssueLink(other, dep.Type, true))
+			}
+		}
+	}
+	return links, nil
+}
+
+func newIssueLink(other *types.Issue, depType types.DependencyType, incoming bool) *types.IssueLink {
+	return &types.IssueLink{
+		Type:     depType,
+		Incoming: incoming,
+		ID:       other.ID,
+		Title:    other.Title,
+		Status:   other.Status,
+		Priority: other.Priority,
+	}
+}
+
+// WithoutLinks drops the linked issues from a list of dependencies (or, with
+// incoming, dependents), leaving the blocking and hierarchy relations
+func WithoutLinks(issues []*types.Issue, links []*types.IssueLink, incoming bool) []*types.Issue {
+	linked := make(map[string]bool)
+	for _, l := range links {
+		if l.Incoming == incoming {
+			linked[l.ID] = true
+		}
+	}
+	var result []*types.Issue
+	for _, issue := range issues {
+		if !linked[issue.ID] {
+			result = append(result, issue)
+		}
+	}
+	return result
+}
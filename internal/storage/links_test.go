@@ -0,0 +1,57 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage"
+	"github.com/imalsogreg/beads/internal/storage/memory"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestGetIssueLinks(t *testing.T) {
+	ctx := context.Background()
+	testStore := memory.New("")
+	defer testStore.Close()
+	if err := testStore.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+	for _, title := range []string{"Regression", "Refactor", "Blocker", "Copy of regression"} {
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := testStore.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+	for _, dep := range []*types.Dependency{
+		{IssueID: "bd-1", DependsOnID: "bd-2", Type: types.DepCausedBy},
+		{IssueID: "bd-1", DependsOnID: "bd-3", Type: types.DepBlocks},
+		{IssueID: "bd-4", DependsOnID: "bd-1", Type: types.DepDuplicates},
+	} {
+		if err := testStore.AddDependency(ctx, dep, "test"); err != nil {
+			t.Fatalf("AddDependency failed: %v", err)
+		}
+	}
+
+	links, err := storage.GetIssueLinks(ctx, testStore, "bd-1")
+	if err != nil {
+		t.Fatalf("GetIssueLinks failed: %v", err)
+	}
+	if len(links) != 2 {
+		t.Fatalf("Expected 2 links, got %+v", links)
+	}
+	if links[0].ID != "bd-2" || links[0].Relation() != "caused by" {
+		t.Errorf("Expected bd-1 caused by bd-2, got %s %s", links[0].Relation(), links[0].ID)
+	}
+	if links[1].ID != "bd-4" || links[1].Relation() != "duplicated by" {
+		t.Errorf("Expected bd-1 duplicated by bd-4, got %s %s", links[1].Relation(), links[1].ID)
+	}
+
+	deps, err := testStore.GetDependencies(ctx, "bd-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocking := storage.WithoutLinks(deps, links, false)
+	if len(blocking) != 1 || blocking[0].ID != "bd-3" {
+		t.Errorf("Expected only bd-3 left, got %+v", blocking)
+	}
+}
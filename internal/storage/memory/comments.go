@@ -0,0 +1,303 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// Mentions aren't stored separately here; a comment @mentions whoever its
+// current text does.
+
+// AddIssueComment adds a comment to an issue
+func (m *MemoryStorage) AddIssueComment(ctx context.Context, issueID, author, text string) (*types.Comment, error) {
+	return m.addComment(ctx, issueID, nil, author, text)
+}
+
+// ReplyToComment adds a comment answering another comment on the same issue
+func (m *MemoryStorage) ReplyToComment(ctx context.Context, issueID string, replyTo int64, author, text string) (*types.Comment, error) {
+	return m.addComment(ctx, issueID, &replyTo, author, text)
+}
+
+func (m *MemoryStorage) addComment(ctx context.Context, issueID string, replyTo *int64, author, text string) (*types.Comment, error) {
+	comment, err := m.insertComment(issueID, replyTo, author, text)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.linkReferences(ctx, issueID, text, author); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (m *MemoryStorage) insertComment(issueID string, replyTo *int64, author, text string) (*types.Comment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.issues[issueID]; !exists {
+		return nil, types.NotFound("issue", issueID)
+	}
+	if replyTo != nil {
+		if _, err := m.findComment(issueID, *replyTo); err != nil {
+			return nil, err
+		}
+	}
+
+	m.nextCommentID++
+	comment := &types.Comment{
+		ID:        m.nextCommentID,
+		IssueID:   issueID,
+		Author:    author,
+		Text:      text,
+		CreatedAt: time.Now(),
+		ReplyTo:   replyTo,
+	}
+
+	m.comments[issueID] = append(m.comments[issueID], comment)
+	m.recordEvent(issueID, types.EventCommented, author, nil, stringPtr(fmt.Sprintf(`{"comment_id":%d}`, comment.ID)), stringPtr(text))
+	m.recordMentions(comment, author, "")
+	m.markDirty(issueID)
+
+	return copyComments([]*types.Comment{comment})[0], nil
+}
+
+// GetIssueComments retrieves all comments for an issue, oldest first
+func (m *MemoryStorage) GetIssueComments(ctx context.Context, issueID string) ([]*types.Comment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return copyComments(m.comments[issueID]), nil
+}
+
+// UpdateIssueComment replaces a comment's text, keeping the old text in its
+// edit history
+func (m *MemoryStorage) UpdateIssueComment(ctx context.Context, issueID string, commentID int64, editor, text string) (*types.Comment, error) {
+	comment, err := m.editComment(issueID, commentID, editor, text)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.linkReferences(ctx, issueID, text, editor); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (m *MemoryStorage) editComment(issueID string, commentID int64, editor, text string) (*types.Comment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	comment, err := m.findComment(issueID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.Text != text {
+		now := time.Now()
+		m.commentEdits[commentID] = append(m.commentEdits[commentID], &types.CommentEdit{
+			CommentID: commentID,
+			Text:      comment.Text,
+			EditedBy:  editor,
+			EditedAt:  now,
+		})
+		oldText := comment.Text
+		comment.Text = text
+		comment.UpdatedAt = &now
+		m.recordMentions(comment, editor, oldText)
+		m.markDirty(issueID)
+	}
+
+	return copyComments([]*types.Comment{comment})[0], nil
+}
+
+// DeleteIssueComment removes a comment with its edits and reactions. Its
+// replies move up to answer the comment it replied to, if any.
+func (m *MemoryStorage) DeleteIssueComment(ctx context.Context, issueID string, commentID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	comment, err := m.findComment(issueID, commentID)
+	if err != nil {
+		return err
+	}
+
+	var kept []*types.Comment
+	for _, c := range m.comments[issueID] {
+		if c.ID == commentID {
+			continue
+		}
+		if c.ReplyTo != nil && *c.ReplyTo == commentID {
+			c.ReplyTo = comment.ReplyTo
+		}
+		kept = append(kept, c)
+	}
+	m.comments[issueID] = kept
+	delete(m.commentEdits, commentID)
+	delete(m.reactions, commentID)
+	m.markDirty(issueID)
+
+	return nil
+}
+
+// GetCommentEdits returns a comment's earlier versions, oldest first
+func (m *MemoryStorage) GetCommentEdits(ctx context.Context, issueID string, commentID int64) ([]*types.CommentEdit, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, err := m.findComment(issueID, commentID); err != nil {
+		return nil, err
+	}
+	edits := make([]*types.CommentEdit, len(m.commentEdits[commentID]))
+	for i, edit := range m.commentEdits[commentID] {
+		e := *edit
+		edits[i] = &e
+	}
+	return edits, nil
+}
+
+// AddCommentReaction records an actor's reaction to a comment; reacting the
+// same way twice is a no-op
+func (m *MemoryStorage) AddCommentReaction(ctx context.Context, issueID string, commentID int64, actor, reaction string) (*types.Comment, error) {
+	if err := types.ValidateReaction(reaction); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	comment, err := m.findComment(issueID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	key := reaction + " " + actor
+	if !m.reactions[commentID][key] {
+		if m.reactions[commentID] == nil {
+			m.reactions[commentID] = make(map[string]bool)
+		}
+		m.reactions[commentID][key] = true
+		if comment.Reactions == nil {
+			comment.Reactions = make(map[string]int)
+		}
+		comment.Reactions[reaction]++
+		m.markDirty(issueID)
+	}
+
+	return copyComments([]*types.Comment{comment})[0], nil
+}
+
+// RemoveCommentReaction takes back an actor's reaction to a comment
+func (m *MemoryStorage) RemoveCommentReaction(ctx context.Context, issueID string, commentID int64, actor, reaction string) (*types.Comment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	comment, err := m.findComment(issueID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	key := reaction + " " + actor
+	if m.reactions[commentID][key] {
+		delete(m.reactions[commentID], key)
+		if comment.Reactions[reaction]--; comment.Reactions[reaction] <= 0 {
+			delete(comment.Reactions, reaction)
+		}
+		if len(comment.Reactions) == 0 {
+			comment.Reactions = nil
+		}
+		m.markDirty(issueID)
+	}
+
+	return copyComments([]*types.Comment{comment})[0], nil
+}
+
+// GetMentions returns the comments that @mention user, newest first
+func (m *MemoryStorage) GetMentions(ctx context.Context, user string) ([]*types.Mention, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	mentions := []*types.Mention{}
+	for issueID, comments := range m.comments {
+		issue, ok := m.issues[issueID]
+		if !ok || issue.DeletedAt != nil {
+			continue
+		}
+		for _, c := range comments {
+			for _, mentioned := range types.CommentMentions(c.Text) {
+				if mentioned == user {
+					mentions = append(mentions, &types.Mention{User: user, IssueTitle: issue.Title, Comment: copyComments([]*types.Comment{c})[0]})
+					break
+				}
+			}
+		}
+	}
+	sort.Slice(mentions, func(i, j int) bool {
+		if !mentions[i].CreatedAt.Equal(mentions[j].CreatedAt) {
+			return mentions[i].CreatedAt.After(mentions[j].CreatedAt)
+		}
+		return mentions[i].ID > mentions[j].ID
+	})
+	return mentions, nil
+}
+
+// recordMentions records a mentioned event for each user a comment
+// mentions that its previous text didn't, other than the writer. Callers
+// must hold the lock.
+func (m *MemoryStorage) recordMentions(comment *types.Comment, writer, oldText string) {
+	had := make(map[string]bool)
+	for _, user := range types.CommentMentions(oldText) {
+		had[user] = true
+	}
+	for _, user := range types.CommentMentions(comment.Text) {
+		if had[user] || user == writer {
+			continue
+		}
+		payload := fmt.Sprintf(`{"comment_id":%d,"mentioned":%q}`, comment.ID, user)
+		m.recordEvent(comment.IssueID, types.EventMentioned, writer, nil, &payload, stringPtr(comment.Text))
+	}
+}
+
+// linkReferences links an issue as related to each existing issue a comment
+// on it mentions by ID, unless the two are already linked either way
+func (m *MemoryStorage) linkReferences(ctx context.Context, issueID, text, actor string) error {
+	for _, id := range types.CommentReferences(text) {
+		if id == issueID || !m.linkable(issueID, id) {
+			continue
+		}
+		dep := &types.Dependency{IssueID: issueID, DependsOnID: id, Type: types.DepRelated}
+		if err := m.AddDependency(ctx, dep, actor); err != nil {
+			return fmt.Errorf("failed to link %s to %s: %w", issueID, id, err)
+		}
+	}
+	return nil
+}
+
+// linkable reports whether target exists and isn't linked to issueID yet
+func (m *MemoryStorage) linkable(issueID, target string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if issue, ok := m.issues[target]; !ok || issue.DeletedAt != nil {
+		return false
+	}
+	for _, dep := range m.dependencies[issueID] {
+		if dep.DependsOnID == target {
+			return false
+		}
+	}
+	for _, dep := range m.dependencies[target] {
+		if dep.DependsOnID == issueID {
+			return false
+		}
+	}
+	return true
+}
+
+// findComment returns the stored comment. Callers must hold the lock.
+func (m *MemoryStorage) findComment(issueID string, commentID int64) (*types.Comment, error) {
+	for _, c := range m.comments[issueID] {
+		if c.ID == commentID {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("comment %d not found on %s", commentID, issueID)
+}
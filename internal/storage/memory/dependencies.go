@@ -0,0 +1,417 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/remote"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+const (
+	// maxDependencyDepth is the maximum depth for recursive dependency traversal,
+	// the same bound the SQLite backend uses
+	maxDependencyDepth = 100
+)
+
+// AddDependency adds a dependency between issues with cycle prevention
+func (m *MemoryStorage) AddDependency(ctx context.Context, dep *types.Dependency, actor string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Validate dependency type
+	if !dep.Type.IsValid() {
+		return fmt.Errorf("invalid dependency type: %s (must be blocks, related/relates-to, parent-child, discovered-from, duplicates, or caused-by)", dep.Type)
+	}
+	dep.Type = dep.Type.Canonical()
+
+	// Check that both issues exist
+	issue, exists := m.issues[dep.IssueID]
+	if !exists {
+		return types.NotFound("issue", dep.IssueID)
+	}
+	// Issues in other workspaces can't be checked from here, and their
+	// edges can't close a cycle
+	isRemote := remote.IsRef(dep.DependsOnID)
+	dependsOn, exists := m.issues[dep.DependsOnID]
+	if !exists && !isRemote {
+		return types.NotFound("dependency target", dep.DependsOnID)
+	}
+
+	// Prevent self-dependency
+	if dep.IssueID == dep.DependsOnID {
+		return fmt.Errorf("issue cannot depend on itself")
+	}
+
+	// Child depends on parent, never the other way around
+	if dep.Type == types.DepParentChild && !isRemote && issue.IssueType == types.TypeEpic && dependsOn.IssueType != types.TypeEpic {
+		return fmt.Errorf("invalid parent-child dependency: parent (%s) cannot depend on child (%s). Use: bd dep add %s %s --type parent-child",
+			dep.IssueID, dep.DependsOnID, dep.DependsOnID, dep.IssueID)
+	}
+
+	// Check for duplicates
+	for _, existing := range m.dependencies[dep.IssueID] {
+		if existing.DependsOnID == dep.DependsOnID {
+			return fmt.Errorf("dependency already exists")
+		}
+	}
+
+	// Cycles are prevented across all dependency types, as in SQLite
+	if m.reachable(dep.DependsOnID, dep.IssueID) {
+		return &types.CycleError{IssueID: dep.IssueID, DependsOnID: dep.DependsOnID}
+	}
+
+	if dep.CreatedAt.IsZero() {
+		dep.CreatedAt = time.Now()
+	}
+	if dep.CreatedBy == "" {
+		dep.CreatedBy = actor
+	}
+
+	stored := *dep
+	m.dependencies[dep.IssueID] = append(m.dependencies[dep.IssueID], &stored)
+
+	m.recordEvent(dep.IssueID, types.EventDependencyAdded, actor, nil, nil,
+		stringPtr(fmt.Sprintf("Added dependency: %s %s %s", dep.IssueID, dep.Type, dep.DependsOnID)))
+
+	// Dependencies are exported with each issue, so both sides need updating
+	m.markDirty(dep.IssueID)
+	if !isRemote {
+		m.markDirty(dep.DependsOnID)
+	}
+
+	return nil
+}
+
+// reachable reports whether to can be reached from from by following
+// dependency edges. Callers must hold at least a read lock.
+func (m *MemoryStorage) reachable(from, to string) bool {
+	type frame struct {
+		id    string
+		depth int
+	}
+	visited := map[string]bool{from: true}
+	stack := []frame{{from, 0}}
+	for len(stack) > 0 {
+		f := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if f.depth >= maxDependencyDepth {
+			continue
+		}
+		for _, dep := range m.dependencies[f.id] {
+			if dep.DependsOnID == to {
+				return true
+			}
+			if !visited[dep.DependsOnID] {
+				visited[dep.DependsOnID] = true
+				stack = append(stack, frame{dep.DependsOnID, f.depth + 1})
+			}
+		}
+	}
+	return false
+}
+
+// RemoveDependency removes a dependency
+func (m *MemoryStorage) RemoveDependency(ctx context.Context, issueID, dependsOnID string, actor string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	deps := m.dependencies[issueID]
+	newDeps := make([]*types.Dependency, 0, len(deps))
+	for _, dep := range deps {
+		if dep.DependsOnID != dependsOnID {
+			newDeps = append(newDeps, dep)
+		}
+	}
+	if len(newDeps) == len(deps) {
+		return fmt.Errorf("dependency from %s to %s does not exist", issueID, dependsOnID)
+	}
+
+	if len(newDeps) == 0 {
+		delete(m.dependencies, issueID)
+	} else {
+		m.dependencies[issueID] = newDeps
+	}
+
+	m.recordEvent(issueID, types.EventDependencyRemoved, actor, nil, nil,
+		stringPtr(fmt.Sprintf("Removed dependency on %s", dependsOnID)))
+	m.markDirty(issueID, dependsOnID)
+
+	return nil
+}
+
+// sortIssuesByPriority orders issues by priority only, with ID as a stable
+// tie-breaker
+func sortIssuesByPriority(issues []*types.Issue) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Priority != issues[j].Priority {
+			return issues[i].Priority < issues[j].Priority
+		}
+		return issues[i].ID < issues[j].ID
+	})
+}
+
+// GetDependencies gets issues that this issue depends on
+func (m *MemoryStorage) GetDependencies(ctx context.Context, issueID string) ([]*types.Issue, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var results []*types.Issue
+	for _, dep := range m.dependencies[issueID] {
+		if issue, exists := m.issues[dep.DependsOnID]; exists {
+			results = append(results, m.copyIssue(issue))
+		}
+	}
+
+	sortIssuesByPriority(results)
+	return results, nil
+}
+
+// GetDependents gets issues that depend on this issue
+func (m *MemoryStorage) GetDependents(ctx context.Context, issueID string) ([]*types.Issue, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var results []*types.Issue
+	for _, id := range m.dependentIDs(issueID) {
+		if issue, exists := m.issues[id]; exists {
+			results = append(results, m.copyIssue(issue))
+		}
+	}
+
+	sortIssuesByPriority(results)
+	return results, nil
+}
+
+// GetChildren gets the direct subtasks of an issue via parent-child dependencies
+func (m *MemoryStorage) GetChildren(ctx context.Context, parentID string) ([]*types.Issue, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var results []*types.Issue
+	for id, deps := range m.dependencies {
+		for _, dep := range deps {
+			if dep.Type == types.DepParentChild && dep.DependsOnID == parentID {
+				if issue, exists := m.issues[id]; exists {
+					results = append(results, m.copyIssue(issue))
+				}
+				break
+			}
+		}
+	}
+
+	// ORDER BY priority, created_at
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Priority != results[j].Priority {
+			return results[i].Priority < results[j].Priority
+		}
+		if !results[i].CreatedAt.Equal(results[j].CreatedAt) {
+			return results[i].CreatedAt.Before(results[j].CreatedAt)
+		}
+		return results[i].ID < results[j].ID
+	})
+	return results, nil
+}
+
+// dependentIDs returns the IDs of issues with a dependency on issueID.
+// Callers must hold at least a read lock.
+func (m *MemoryStorage) dependentIDs(issueID string) []string {
+	var ids []string
+	for id, deps := range m.dependencies {
+		for _, dep := range deps {
+			if dep.DependsOnID == issueID {
+				ids = append(ids, id)
+				break
+			}
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// GetDependencyRecords gets dependency records for an issue
+func (m *MemoryStorage) GetDependencyRecords(ctx context.Context, issueID string) ([]*types.Dependency, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return copyDependencies(m.dependencies[issueID]), nil
+}
+
+// GetAllDependencyRecords gets all dependency records grouped by issue ID
+func (m *MemoryStorage) GetAllDependencyRecords(ctx context.Context) (map[string][]*types.Dependency, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make(map[string][]*types.Dependency)
+	for k, v := range m.dependencies {
+		if len(v) > 0 {
+			result[k] = copyDependencies(v)
+		}
+	}
+
+	return result, nil
+}
+
+// GetDependencyTree returns the dependency tree rooted at issueID, or the tree
+// of dependents when reverse is set. As in SQLite, nodes reachable through
+// several paths appear once at their shallowest depth unless showAllPaths is set.
+func (m *MemoryStorage) GetDependencyTree(ctx context.Context, issueID string, maxDepth int, showAllPaths bool, reverse bool) ([]*types.TreeNode, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if maxDepth <= 0 {
+		maxDepth = 50
+	}
+
+	root, exists := m.issues[issueID]
+	if !exists {
+		return nil, nil
+	}
+
+	type pathNode struct {
+		issue   *types.Issue
+		depth   int
+		path    []string
+		depType types.DependencyType
+	}
+
+	// Breadth-first expansion of every acyclic path, like the recursive CTE
+	var all []pathNode
+	level := []pathNode{{issue: root, depth: 0, path: []string{root.ID}}}
+	for len(level) > 0 {
+		all = append(all, level...)
+		var next []pathNode
+		for _, n := range level {
+			if n.depth >= maxDepth {
+				continue
+			}
+			var edges []*types.Dependency
+			if reverse {
+				for _, id := range m.dependentIDs(n.issue.ID) {
+					for _, dep := range m.dependencies[id] {
+						if dep.DependsOnID == n.issue.ID {
+							edges = append(edges, dep)
+						}
+					}
+				}
+			} else {
+				edges = m.dependencies[n.issue.ID]
+			}
+			for _, dep := range edges {
+				id := dep.DependsOnID
+				if reverse {
+					id = dep.IssueID
+				}
+				child, ok := m.issues[id]
+				if !ok || containsID(n.path, id) {
+					continue
+				}
+				path := append(append([]string(nil), n.path...), id)
+				next = append(next, pathNode{issue: child, depth: n.depth + 1, path: path, depType: dep.Type})
+			}
+		}
+		level = next
+	}
+
+	// ORDER BY depth, priority, id
+	sort.SliceStable(all, func(i, j int) bool {
+		if all[i].depth != all[j].depth {
+			return all[i].depth < all[j].depth
+		}
+		if all[i].issue.Priority != all[j].issue.Priority {
+			return all[i].issue.Priority < all[j].issue.Priority
+		}
+		return all[i].issue.ID < all[j].issue.ID
+	})
+
+	seen := make(map[string]bool)
+	var nodes []*types.TreeNode
+	for _, n := range all {
+		if !showAllPaths {
+			if seen[n.issue.ID] {
+				continue
+			}
+			seen[n.issue.ID] = true
+		}
+		issueCopy := *n.issue
+		nodes = append(nodes, &types.TreeNode{
+			Issue:     issueCopy,
+			Depth:     n.depth,
+			Truncated: n.depth == maxDepth,
+			DepType:   n.depType,
+		})
+	}
+
+	return nodes, nil
+}
+
+func containsID(ids []string, id string) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+// DetectCycles finds circular dependencies and returns the issues in each cycle.
+// Each cycle is reported once per entry point, like the SQLite backend.
+func (m *MemoryStorage) DetectCycles(ctx context.Context) ([][]*types.Issue, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	starts := make([]string, 0, len(m.dependencies))
+	for id := range m.dependencies {
+		starts = append(starts, id)
+	}
+	sort.Strings(starts)
+
+	seen := make(map[string]bool)
+	var paths []string
+
+	var walk func(start string, path []string)
+	walk = func(start string, path []string) {
+		if len(path) > maxDependencyDepth {
+			return
+		}
+		current := path[len(path)-1]
+		for _, dep := range m.dependencies[current] {
+			if dep.DependsOnID == start {
+				key := strings.Join(append(append([]string(nil), path...), start), "→")
+				if !seen[key] {
+					seen[key] = true
+					paths = append(paths, key)
+				}
+				continue
+			}
+			if containsID(path, dep.DependsOnID) {
+				continue
+			}
+			walk(start, append(path, dep.DependsOnID))
+		}
+	}
+	for _, start := range starts {
+		walk(start, []string{start})
+	}
+	sort.Strings(paths)
+
+	var cycles [][]*types.Issue
+	for _, p := range paths {
+		ids := strings.Split(p, "→")
+		ids = ids[:len(ids)-1] // drop the closing repeat of the start
+		var cycle []*types.Issue
+		for _, id := range ids {
+			if issue, ok := m.issues[id]; ok {
+				cycle = append(cycle, m.copyIssue(issue))
+			}
+		}
+		if len(cycle) > 0 {
+			cycles = append(cycles, cycle)
+		}
+	}
+
+	return cycles, nil
+}
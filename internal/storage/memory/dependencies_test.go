@@ -0,0 +1,224 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func createTestIssue(t *testing.T, store *MemoryStorage, title string, priority int, issueType types.IssueType) *types.Issue {
+	t.Helper()
+	issue := &types.Issue{
+		Title:     title,
+		Status:    types.StatusOpen,
+		Priority:  priority,
+		IssueType: issueType,
+	}
+	if err := store.CreateIssue(context.Background(), issue, "test-user"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	return issue
+}
+
+func addTestDep(t *testing.T, store *MemoryStorage, from, to string, depType types.DependencyType) {
+	t.Helper()
+	dep := &types.Dependency{IssueID: from, DependsOnID: to, Type: depType}
+	if err := store.AddDependency(context.Background(), dep, "test-user"); err != nil {
+		t.Fatalf("AddDependency %s -> %s failed: %v", from, to, err)
+	}
+}
+
+func TestAddDependencyValidation(t *testing.T) {
+	store := setupTestMemory(t)
+	defer store.Close()
+	ctx := context.Background()
+
+	a := createTestIssue(t, store, "A", 1, types.TypeTask)
+	b := createTestIssue(t, store, "B", 1, types.TypeTask)
+	epic := createTestIssue(t, store, "Epic", 1, types.TypeEpic)
+
+	tests := []struct {
+		name string
+		dep  *types.Dependency
+		want string
+	}{
+		{"self", &types.Dependency{IssueID: a.ID, DependsOnID: a.ID, Type: types.DepBlocks}, "itself"},
+		{"missing target", &types.Dependency{IssueID: a.ID, DependsOnID: "bd-999", Type: types.DepBlocks}, "not found"},
+		{"bad type", &types.Dependency{IssueID: a.ID, DependsOnID: b.ID, Type: "bogus"}, "invalid dependency type"},
+		{"backwards parent-child", &types.Dependency{IssueID: epic.ID, DependsOnID: a.ID, Type: types.DepParentChild}, "parent"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := store.AddDependency(ctx, tt.dep, "test-user")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestAddDependencyPreventsCycles(t *testing.T) {
+	store := setupTestMemory(t)
+	defer store.Close()
+	ctx := context.Background()
+
+	a := createTestIssue(t, store, "A", 1, types.TypeTask)
+	b := createTestIssue(t, store, "B", 1, types.TypeTask)
+	c := createTestIssue(t, store, "C", 1, types.TypeTask)
+
+	addTestDep(t, store, a.ID, b.ID, types.DepBlocks)
+	addTestDep(t, store, b.ID, c.ID, types.DepRelated)
+
+	err := store.AddDependency(ctx, &types.Dependency{IssueID: c.ID, DependsOnID: a.ID, Type: types.DepBlocks}, "test-user")
+	if err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Fatalf("expected cycle error, got %v", err)
+	}
+
+	cycles, err := store.DetectCycles(ctx)
+	if err != nil {
+		t.Fatalf("DetectCycles failed: %v", err)
+	}
+	if len(cycles) != 0 {
+		t.Errorf("expected no cycles, got %d", len(cycles))
+	}
+}
+
+func TestDetectCyclesFromLoadedData(t *testing.T) {
+	store := New("")
+	defer store.Close()
+
+	// Cycles can only arrive through imported data, since AddDependency rejects them
+	err := store.LoadFromIssues([]*types.Issue{
+		{ID: "bd-1", Title: "A", Status: types.StatusOpen, IssueType: types.TypeTask,
+			Dependencies: []*types.Dependency{{IssueID: "bd-1", DependsOnID: "bd-2", Type: types.DepBlocks}}},
+		{ID: "bd-2", Title: "B", Status: types.StatusOpen, IssueType: types.TypeTask,
+			Dependencies: []*types.Dependency{{IssueID: "bd-2", DependsOnID: "bd-1", Type: types.DepBlocks}}},
+	})
+	if err != nil {
+		t.Fatalf("LoadFromIssues failed: %v", err)
+	}
+
+	cycles, err := store.DetectCycles(context.Background())
+	if err != nil {
+		t.Fatalf("DetectCycles failed: %v", err)
+	}
+	// One entry per starting point, as with SQLite
+	if len(cycles) != 2 {
+		t.Fatalf("expected 2 cycle paths, got %d", len(cycles))
+	}
+	if len(cycles[0]) != 2 || cycles[0][0].ID != "bd-1" || cycles[0][1].ID != "bd-2" {
+		t.Errorf("unexpected first cycle: %v", cycles[0])
+	}
+}
+
+func TestRemoveDependencyMissing(t *testing.T) {
+	store := setupTestMemory(t)
+	defer store.Close()
+
+	a := createTestIssue(t, store, "A", 1, types.TypeTask)
+	b := createTestIssue(t, store, "B", 1, types.TypeTask)
+
+	if err := store.RemoveDependency(context.Background(), a.ID, b.ID, "test-user"); err == nil {
+		t.Error("expected error removing a dependency that does not exist")
+	}
+}
+
+func TestGetDependencyTree(t *testing.T) {
+	store := setupTestMemory(t)
+	defer store.Close()
+	ctx := context.Background()
+
+	// Diamond: a -> b -> d, a -> c -> d
+	a := createTestIssue(t, store, "A", 1, types.TypeTask)
+	b := createTestIssue(t, store, "B", 2, types.TypeTask)
+	c := createTestIssue(t, store, "C", 1, types.TypeTask)
+	d := createTestIssue(t, store, "D", 1, types.TypeTask)
+	addTestDep(t, store, a.ID, b.ID, types.DepBlocks)
+	addTestDep(t, store, a.ID, c.ID, types.DepBlocks)
+	addTestDep(t, store, b.ID, d.ID, types.DepBlocks)
+	addTestDep(t, store, c.ID, d.ID, types.DepBlocks)
+
+	tree, err := store.GetDependencyTree(ctx, a.ID, 10, false, false)
+	if err != nil {
+		t.Fatalf("GetDependencyTree failed: %v", err)
+	}
+	var got []string
+	for _, n := range tree {
+		got = append(got, n.ID)
+	}
+	// Root first, then depth 1 by priority (c before b), then d once
+	want := []string{a.ID, c.ID, b.ID, d.ID}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("tree = %v, want %v", got, want)
+	}
+	if tree[3].Depth != 2 {
+		t.Errorf("expected %s at depth 2, got %d", d.ID, tree[3].Depth)
+	}
+
+	all, err := store.GetDependencyTree(ctx, a.ID, 10, true, false)
+	if err != nil {
+		t.Fatalf("GetDependencyTree failed: %v", err)
+	}
+	if len(all) != 5 {
+		t.Errorf("expected 5 nodes with showAllPaths, got %d", len(all))
+	}
+
+	truncated, err := store.GetDependencyTree(ctx, a.ID, 1, false, false)
+	if err != nil {
+		t.Fatalf("GetDependencyTree failed: %v", err)
+	}
+	if len(truncated) != 3 || !truncated[1].Truncated {
+		t.Errorf("expected 3 nodes with depth-1 nodes truncated, got %d", len(truncated))
+	}
+
+	reverse, err := store.GetDependencyTree(ctx, d.ID, 10, false, true)
+	if err != nil {
+		t.Fatalf("GetDependencyTree reverse failed: %v", err)
+	}
+	if len(reverse) != 4 || reverse[len(reverse)-1].ID != a.ID {
+		t.Errorf("expected reverse tree ending at %s, got %d nodes", a.ID, len(reverse))
+	}
+}
+
+func TestCreateSubtask(t *testing.T) {
+	store := setupTestMemory(t)
+	defer store.Close()
+
+	ctx := context.Background()
+
+	parent := createTestIssue(t, store, "Ship v2", 1, types.TypeFeature)
+	low := &types.Issue{Title: "Write docs", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, ParentID: parent.ID}
+	high := &types.Issue{Title: "Cut release", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask, ParentID: parent.ID}
+	for _, issue := range []*types.Issue{low, high} {
+		if err := store.CreateIssue(ctx, issue, "test-user"); err != nil {
+			t.Fatalf("CreateIssue subtask failed: %v", err)
+		}
+	}
+
+	got, err := store.GetIssue(ctx, low.ID)
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if got.ParentID != parent.ID {
+		t.Errorf("Expected parent %s, got %q", parent.ID, got.ParentID)
+	}
+
+	children, err := store.GetChildren(ctx, parent.ID)
+	if err != nil {
+		t.Fatalf("GetChildren failed: %v", err)
+	}
+	if len(children) != 2 || children[0].ID != high.ID || children[1].ID != low.ID {
+		t.Fatalf("Expected children [%s %s], got %v", high.ID, low.ID, children)
+	}
+
+	orphan := &types.Issue{Title: "Orphan", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, ParentID: "bd-999"}
+	if err := store.CreateIssue(ctx, orphan, "test-user"); err == nil {
+		t.Error("Expected missing parent to be rejected")
+	}
+	epic := &types.Issue{Title: "Epic", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeEpic, ParentID: low.ID}
+	if err := store.CreateIssue(ctx, epic, "test-user"); err == nil {
+		t.Error("Expected epic under a task to be rejected")
+	}
+}
@@ -1,12 +1,16 @@
 // Package memory implements the storage interface using in-memory data structures.
-// This is designed for --no-db mode where the database is loaded from JSONL at startup
-// and written back to JSONL after each command.
+// It mirrors the semantics of the SQLite backend (events, dirty tracking, cycle
+// prevention, ready work calculation) so it can stand in for it in tests, in
+// --no-db mode where the database is loaded from JSONL at startup and written
+// back after each command, and in --ephemeral sessions that are never persisted.
 package memory
 
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -20,17 +24,23 @@ type MemoryStorage struct {
 	mu sync.RWMutex // Protects all maps
 
 	// Core data
-	issues       map[string]*types.Issue       // ID -> Issue
+	issues       map[string]*types.Issue        // ID -> Issue
 	dependencies map[string][]*types.Dependency // IssueID -> Dependencies
-	labels       map[string][]string           // IssueID -> Labels
-	events       map[string][]*types.Event     // IssueID -> Events
-	comments     map[string][]*types.Comment   // IssueID -> Comments
-	config       map[string]string             // Config key-value pairs
-	metadata     map[string]string             // Metadata key-value pairs
-	counters     map[string]int                // Prefix -> Last ID
+	labels       map[string][]string            // IssueID -> Labels
+	events       map[string][]*types.Event      // IssueID -> Events
+	comments     map[string][]*types.Comment    // IssueID -> Comments
+	commentEdits map[int64][]*types.CommentEdit // CommentID -> earlier versions
+	reactions    map[int64]map[string]bool      // CommentID -> "reaction actor" pairs given here
+	config       map[string]string              // Config key-value pairs
+	metadata     map[string]string              // Metadata key-value pairs
+	counters     map[string]int                 // Prefix -> Last ID
 
 	// For tracking
-	dirty map[string]bool // IssueIDs that have been modified
+	dirty        map[string]time.Time // IssueID -> when it was marked dirty
+	exportHashes map[string]string    // IssueID -> content hash at last export
+
+	nextEventID   int64
+	nextCommentID int64
 
 	jsonlPath string // Path to source JSONL file (for reference)
 	closed    bool
@@ -44,10 +54,13 @@ func New(jsonlPath string) *MemoryStorage {
 		labels:       make(map[string][]string),
 		events:       make(map[string][]*types.Event),
 		comments:     make(map[string][]*types.Comment),
+		commentEdits: make(map[int64][]*types.CommentEdit),
+		reactions:    make(map[int64]map[string]bool),
 		config:       make(map[string]string),
 		metadata:     make(map[string]string),
 		counters:     make(map[string]int),
-		dirty:        make(map[string]bool),
+		dirty:        make(map[string]time.Time),
+		exportHashes: make(map[string]string),
 		jsonlPath:    jsonlPath,
 	}
 }
@@ -63,22 +76,31 @@ func (m *MemoryStorage) LoadFromIssues(issues []*types.Issue) error {
 			continue
 		}
 
-		// Store the issue
-		m.issues[issue.ID] = issue
+		// Store the issue itself without its relations; those live in their own maps
+		stored := *issue
+		stored.Labels = nil
+		stored.Dependencies = nil
+		stored.Comments = nil
+		m.issues[issue.ID] = &stored
 
 		// Store dependencies
 		if len(issue.Dependencies) > 0 {
-			m.dependencies[issue.ID] = issue.Dependencies
+			m.dependencies[issue.ID] = copyDependencies(issue.Dependencies)
 		}
 
 		// Store labels
 		if len(issue.Labels) > 0 {
-			m.labels[issue.ID] = issue.Labels
+			m.labels[issue.ID] = append([]string(nil), issue.Labels...)
 		}
 
 		// Store comments
 		if len(issue.Comments) > 0 {
-			m.comments[issue.ID] = issue.Comments
+			m.comments[issue.ID] = copyComments(issue.Comments)
+			for _, c := range issue.Comments {
+				if c.ID > m.nextCommentID {
+					m.nextCommentID = c.ID
+				}
+			}
 		}
 
 		// Update counter based on issue ID
@@ -100,25 +122,9 @@ func (m *MemoryStorage) GetAllIssues() []*types.Issue {
 
 	issues := make([]*types.Issue, 0, len(m.issues))
 	for _, issue := range m.issues {
-		// Deep copy to avoid mutations
-		issueCopy := *issue
-
-		// Attach dependencies
-		if deps, ok := m.dependencies[issue.ID]; ok {
-			issueCopy.Dependencies = deps
-		}
-
-		// Attach labels
-		if labels, ok := m.labels[issue.ID]; ok {
-			issueCopy.Labels = labels
-		}
-
-		// Attach comments
-		if comments, ok := m.comments[issue.ID]; ok {
-			issueCopy.Comments = comments
-		}
-
-		issues = append(issues, &issueCopy)
+		issueCopy := m.copyIssue(issue)
+		issueCopy.Comments = copyComments(m.comments[issue.ID])
+		issues = append(issues, issueCopy)
 	}
 
 	// Sort by ID for consistent output
@@ -143,6 +149,116 @@ func extractPrefixAndNumber(id string) (string, int) {
 	return parts[0], num
 }
 
+// copyIssue returns a copy of a stored issue with its dependencies and labels
+// attached. Callers must hold at least a read lock.
+func (m *MemoryStorage) copyIssue(issue *types.Issue) *types.Issue {
+	issueCopy := *issue
+	issueCopy.Dependencies = copyDependencies(m.dependencies[issue.ID])
+	issueCopy.Labels = m.sortedLabels(issue.ID)
+	issueCopy.Comments = nil
+	issueCopy.ParentID = m.parentOf(issue.ID)
+	return &issueCopy
+}
+
+// parentOf returns the issue an issue is a subtask of, or "" if none.
+// Callers must hold at least a read lock.
+func (m *MemoryStorage) parentOf(issueID string) string {
+	for _, dep := range m.dependencies[issueID] {
+		if dep.Type == types.DepParentChild {
+			return dep.DependsOnID
+		}
+	}
+	return ""
+}
+
+// sortedLabels returns a sorted copy of an issue's labels, matching the
+// ORDER BY label of the SQLite backend
+func (m *MemoryStorage) sortedLabels(issueID string) []string {
+	labels := m.labels[issueID]
+	if len(labels) == 0 {
+		return nil
+	}
+	result := append([]string(nil), labels...)
+	sort.Strings(result)
+	return result
+}
+
+func copyDependencies(deps []*types.Dependency) []*types.Dependency {
+	if len(deps) == 0 {
+		return nil
+	}
+	result := make([]*types.Dependency, len(deps))
+	for i, dep := range deps {
+		d := *dep
+		result[i] = &d
+	}
+	return result
+}
+
+func copyComments(comments []*types.Comment) []*types.Comment {
+	if len(comments) == 0 {
+		return nil
+	}
+	result := make([]*types.Comment, len(comments))
+	for i, c := range comments {
+		cc := *c
+		if c.Reactions != nil {
+			cc.Reactions = make(map[string]int, len(c.Reactions))
+			for reaction, n := range c.Reactions {
+				cc.Reactions[reaction] = n
+			}
+		}
+		result[i] = &cc
+	}
+	return result
+}
+
+// recordEvent appends an audit event. Callers must hold the write lock.
+func (m *MemoryStorage) recordEvent(issueID string, eventType types.EventType, actor string, oldValue, newValue, comment *string) {
+	m.nextEventID++
+	m.events[issueID] = append(m.events[issueID], &types.Event{
+		ID:        m.nextEventID,
+		IssueID:   issueID,
+		EventType: eventType,
+		Actor:     actor,
+		OldValue:  oldValue,
+		NewValue:  newValue,
+		Comment:   comment,
+		CreatedAt: time.Now(),
+	})
+}
+
+// markDirty flags issues for incremental export. Callers must hold the write lock.
+func (m *MemoryStorage) markDirty(issueIDs ...string) {
+	now := time.Now()
+	for _, id := range issueIDs {
+		m.dirty[id] = now
+	}
+}
+
+// issuePrefix returns the configured issue prefix. Callers must hold a lock.
+func (m *MemoryStorage) issuePrefix() string {
+	prefix := m.config["issue_prefix"]
+	if prefix == "" {
+		prefix = "bd" // Default fallback
+	}
+	return prefix
+}
+
+// checkPrefix rejects explicitly provided IDs that don't match the configured
+// prefix (bd-177). Callers must hold a lock.
+func (m *MemoryStorage) checkPrefix(id string) error {
+	prefix := m.config["issue_prefix"]
+	if prefix != "" && !strings.HasPrefix(id, prefix+"-") {
+		return fmt.Errorf("issue ID '%s' does not match configured prefix '%s'", id, prefix)
+	}
+	return nil
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
 // CreateIssue creates a new issue
 func (m *MemoryStorage) CreateIssue(ctx context.Context, issue *types.Issue, actor string) error {
 	m.mu.Lock()
@@ -153,6 +269,20 @@ func (m *MemoryStorage) CreateIssue(ctx context.Context, issue *types.Issue, act
 		return fmt.Errorf("validation failed: %w", err)
 	}
 
+	// Check the parent before allocating an ID so a failed create leaves the counter alone
+	if issue.ParentID != "" {
+		if issue.ParentID == issue.ID {
+			return fmt.Errorf("issue cannot be its own parent")
+		}
+		parent, exists := m.issues[issue.ParentID]
+		if !exists {
+			return types.NotFound("parent issue", issue.ParentID)
+		}
+		if issue.IssueType == types.TypeEpic && parent.IssueType != types.TypeEpic {
+			return fmt.Errorf("invalid parent: an epic cannot be a subtask of %s %s", parent.IssueType, issue.ParentID)
+		}
+	}
+
 	// Set timestamps
 	now := time.Now()
 	issue.CreatedAt = now
@@ -160,14 +290,11 @@ func (m *MemoryStorage) CreateIssue(ctx context.Context, issue *types.Issue, act
 
 	// Generate ID if not set
 	if issue.ID == "" {
-		prefix := m.config["issue_prefix"]
-		if prefix == "" {
-			prefix = "bd" // Default fallback
-		}
-
-		// Get next ID
+		prefix := m.issuePrefix()
 		m.counters[prefix]++
 		issue.ID = fmt.Sprintf("%s-%d", prefix, m.counters[prefix])
+	} else if err := m.checkPrefix(issue.ID); err != nil {
+		return err
 	}
 
 	// Check for duplicate
@@ -175,20 +302,45 @@ func (m *MemoryStorage) CreateIssue(ctx context.Context, issue *types.Issue, act
 		return fmt.Errorf("issue %s already exists", issue.ID)
 	}
 
-	// Store issue
-	m.issues[issue.ID] = issue
-	m.dirty[issue.ID] = true
+	m.storeNewIssue(issue, actor)
+
+	if issue.ParentID != "" {
+		m.dependencies[issue.ID] = append(m.dependencies[issue.ID], &types.Dependency{
+			IssueID:     issue.ID,
+			DependsOnID: issue.ParentID,
+			Type:        types.DepParentChild,
+			CreatedAt:   now,
+			CreatedBy:   actor,
+		})
+		m.recordEvent(issue.ID, types.EventDependencyAdded, actor, nil, nil,
+			stringPtr(fmt.Sprintf("Added dependency: %s %s %s", issue.ID, types.DepParentChild, issue.ParentID)))
+		m.markDirty(issue.ParentID)
+	}
+	return nil
+}
 
-	// Record event
-	event := &types.Event{
-		IssueID:   issue.ID,
-		EventType: types.EventCreated,
-		Actor:     actor,
-		CreatedAt: now,
+// storeNewIssue inserts a validated issue and records its creation event.
+// Callers must hold the write lock.
+func (m *MemoryStorage) storeNewIssue(issue *types.Issue, actor string) {
+	stored := *issue
+	stored.Labels = nil
+	stored.Dependencies = nil
+	stored.Comments = nil
+	stored.ParentID = ""
+	stored.Subtasks = nil
+	m.issues[issue.ID] = &stored
+	m.markDirty(issue.ID)
+
+	// Keep the counter ahead of explicitly provided IDs
+	if prefix, num := extractPrefixAndNumber(issue.ID); prefix != "" && m.counters[prefix] < num {
+		m.counters[prefix] = num
 	}
-	m.events[issue.ID] = append(m.events[issue.ID], event)
 
-	return nil
+	var newValue *string
+	if data, err := json.Marshal(&stored); err == nil {
+		newValue = stringPtr(string(data))
+	}
+	m.recordEvent(issue.ID, types.EventCreated, actor, nil, newValue, nil)
 }
 
 // CreateIssues creates multiple issues atomically
@@ -204,49 +356,47 @@ func (m *MemoryStorage) CreateIssues(ctx context.Context, issues []*types.Issue,
 	}
 
 	now := time.Now()
-	prefix := m.config["issue_prefix"]
-	if prefix == "" {
-		prefix = "bd"
-	}
+	prefix := m.issuePrefix()
 
-	// Track IDs in this batch to detect duplicates within batch
+	// Check explicit IDs before allocating any new ones so a failed batch
+	// leaves the counters untouched
 	batchIDs := make(map[string]bool)
-
-	// Generate IDs for issues that need them
 	for _, issue := range issues {
-		issue.CreatedAt = now
-		issue.UpdatedAt = now
-
 		if issue.ID == "" {
-			m.counters[prefix]++
-			issue.ID = fmt.Sprintf("%s-%d", prefix, m.counters[prefix])
+			continue
+		}
+		if err := m.checkPrefix(issue.ID); err != nil {
+			return err
 		}
-
-		// Check for duplicates in existing issues
 		if _, exists := m.issues[issue.ID]; exists {
 			return fmt.Errorf("issue %s already exists", issue.ID)
 		}
-
-		// Check for duplicates within this batch
 		if batchIDs[issue.ID] {
 			return fmt.Errorf("duplicate ID within batch: %s", issue.ID)
 		}
 		batchIDs[issue.ID] = true
 	}
 
-	// Store all issues
+	// Generate IDs for issues that need them
 	for _, issue := range issues {
-		m.issues[issue.ID] = issue
-		m.dirty[issue.ID] = true
+		issue.CreatedAt = now
+		issue.UpdatedAt = now
 
-		// Record event
-		event := &types.Event{
-			IssueID:   issue.ID,
-			EventType: types.EventCreated,
-			Actor:     actor,
-			CreatedAt: now,
+		if issue.ID == "" {
+			for {
+				m.counters[prefix]++
+				issue.ID = fmt.Sprintf("%s-%d", prefix, m.counters[prefix])
+				if _, exists := m.issues[issue.ID]; !exists && !batchIDs[issue.ID] {
+					break
+				}
+			}
+			batchIDs[issue.ID] = true
 		}
-		m.events[issue.ID] = append(m.events[issue.ID], event)
+	}
+
+	// Store all issues
+	for _, issue := range issues {
+		m.storeNewIssue(issue, actor)
 	}
 
 	return nil
@@ -263,19 +413,138 @@ func (m *MemoryStorage) GetIssue(ctx context.Context, id string) (*types.Issue,
 	}
 
 	// Return a copy to avoid mutations
-	issueCopy := *issue
+	return m.copyIssue(issue), nil
+}
+
+// allowedUpdateFields mirrors the SQLite backend's whitelist
+var allowedUpdateFields = map[string]bool{
+	"status":              true,
+	"priority":            true,
+	"title":               true,
+	"assignee":            true,
+	"description":         true,
+	"design":              true,
+	"acceptance_criteria": true,
+	"notes":               true,
+	"issue_type":          true,
+	"estimated_minutes":   true,
+	"external_ref":        true,
+	"due_date":            true,
+	"start_date":          true,
+	"milestone":           true,
+}
 
-	// Attach dependencies
-	if deps, ok := m.dependencies[id]; ok {
-		issueCopy.Dependencies = deps
+// stringValue accepts plain strings as well as the typed string values
+// (types.Status, types.IssueType) callers pass through update maps
+func stringValue(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case *string:
+		if v == nil {
+			return "", false
+		}
+		return *v, true
+	case types.Status:
+		return string(v), true
+	case types.IssueType:
+		return string(v), true
+	}
+	return "", false
+}
+
+// intValue accepts the numeric types that arrive via Go callers and decoded JSON
+func intValue(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case float64:
+		return int(v), true
+	case *int:
+		if v == nil {
+			return 0, false
+		}
+		return *v, true
+	}
+	return 0, false
+}
+
+// dateValue accepts the date forms the SQLite backend does: a time.Time or
+// *time.Time, a string for types.ParseDate, or nil or "" to clear
+func dateValue(value interface{}) (*time.Time, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case time.Time:
+		return &v, nil
+	case *time.Time:
+		return v, nil
+	case string:
+		if v == "" {
+			return nil, nil
+		}
+		t, err := types.ParseDate(v, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		return &t, nil
 	}
+	return nil, fmt.Errorf("not a date")
+}
 
-	// Attach labels
-	if labels, ok := m.labels[id]; ok {
-		issueCopy.Labels = labels
+// validateUpdate applies the same field validation as the SQLite backend
+func validateUpdate(key string, value interface{}) error {
+	if !allowedUpdateFields[key] {
+		return fmt.Errorf("invalid field for update: %s", key)
+	}
+	switch key {
+	case "priority":
+		if p, ok := intValue(value); ok && (p < 0 || p > 4) {
+			return fmt.Errorf("priority must be between 0 and 4 (got %d)", p)
+		}
+	case "status":
+		if s, ok := stringValue(value); ok && !types.Status(s).IsValid() {
+			return fmt.Errorf("invalid status: %s", s)
+		}
+	case "issue_type":
+		if t, ok := stringValue(value); ok && !types.IssueType(t).IsValid() {
+			return fmt.Errorf("invalid issue type: %s", t)
+		}
+	case "title":
+		if t, ok := stringValue(value); ok && (len(t) == 0 || len(t) > 500) {
+			return fmt.Errorf("title must be 1-500 characters")
+		}
+	case "estimated_minutes":
+		if mins, ok := intValue(value); ok && mins < 0 {
+			return fmt.Errorf("estimated_minutes cannot be negative")
+		}
+	case "due_date", "start_date":
+		if _, err := dateValue(value); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
 	}
+	return nil
+}
 
-	return &issueCopy, nil
+// determineEventType picks the event type for an update based on old and new status
+func determineEventType(oldStatus types.Status, updates map[string]interface{}) types.EventType {
+	statusVal, hasStatus := updates["status"]
+	if !hasStatus {
+		return types.EventUpdated
+	}
+	newStatus, ok := stringValue(statusVal)
+	if !ok {
+		return types.EventUpdated
+	}
+	if newStatus == string(types.StatusClosed) {
+		return types.EventClosed
+	}
+	if oldStatus == types.StatusClosed {
+		return types.EventReopened
+	}
+	return types.EventStatusChanged
 }
 
 // UpdateIssue updates fields on an issue
@@ -285,9 +554,25 @@ func (m *MemoryStorage) UpdateIssue(ctx context.Context, id string, updates map[
 
 	issue, exists := m.issues[id]
 	if !exists {
-		return fmt.Errorf("issue %s not found", id)
+		return types.NotFound("issue", id)
 	}
 
+	// Validate everything before touching the issue so a bad update is atomic
+	for key, value := range updates {
+		if err := validateUpdate(key, value); err != nil {
+			return err
+		}
+	}
+
+	m.applyUpdates(issue, updates, actor)
+	return nil
+}
+
+// applyUpdates applies already-validated updates to issue, records the event,
+// and marks it dirty. Callers must hold the write lock.
+func (m *MemoryStorage) applyUpdates(issue *types.Issue, updates map[string]interface{}, actor string) {
+	id := issue.ID
+	oldIssue := *issue
 	now := time.Now()
 	issue.UpdatedAt = now
 
@@ -295,489 +580,462 @@ func (m *MemoryStorage) UpdateIssue(ctx context.Context, id string, updates map[
 	for key, value := range updates {
 		switch key {
 		case "title":
-			if v, ok := value.(string); ok {
+			if v, ok := stringValue(value); ok {
 				issue.Title = v
 			}
 		case "description":
-			if v, ok := value.(string); ok {
+			if v, ok := stringValue(value); ok {
 				issue.Description = v
 			}
 		case "design":
-			if v, ok := value.(string); ok {
+			if v, ok := stringValue(value); ok {
 				issue.Design = v
 			}
 		case "acceptance_criteria":
-			if v, ok := value.(string); ok {
+			if v, ok := stringValue(value); ok {
 				issue.AcceptanceCriteria = v
 			}
 		case "notes":
-			if v, ok := value.(string); ok {
+			if v, ok := stringValue(value); ok {
 				issue.Notes = v
 			}
 		case "status":
-			if v, ok := value.(string); ok {
-				oldStatus := issue.Status
+			if v, ok := stringValue(value); ok {
 				issue.Status = types.Status(v)
 
-				// Manage closed_at
-				if issue.Status == types.StatusClosed && oldStatus != types.StatusClosed {
-					issue.ClosedAt = &now
-				} else if issue.Status != types.StatusClosed && oldStatus == types.StatusClosed {
+				// Manage closed_at (enforce invariant)
+				if issue.Status == types.StatusClosed && oldIssue.Status != types.StatusClosed {
+					closedAt := now
+					issue.ClosedAt = &closedAt
+				} else if issue.Status != types.StatusClosed {
 					issue.ClosedAt = nil
 				}
 			}
 		case "priority":
-			if v, ok := value.(int); ok {
+			if v, ok := intValue(value); ok {
 				issue.Priority = v
 			}
 		case "issue_type":
-			if v, ok := value.(string); ok {
+			if v, ok := stringValue(value); ok {
 				issue.IssueType = types.IssueType(v)
 			}
 		case "assignee":
-			if v, ok := value.(string); ok {
+			if v, ok := stringValue(value); ok {
 				issue.Assignee = v
 			} else if value == nil {
 				issue.Assignee = ""
 			}
+		case "estimated_minutes":
+			if v, ok := intValue(value); ok {
+				issue.EstimatedMinutes = &v
+			} else if value == nil {
+				issue.EstimatedMinutes = nil
+			}
 		case "external_ref":
-			if v, ok := value.(string); ok {
+			if v, ok := stringValue(value); ok {
 				issue.ExternalRef = &v
 			} else if value == nil {
 				issue.ExternalRef = nil
 			}
+		case "due_date":
+			issue.DueDate, _ = dateValue(value)
+		case "start_date":
+			issue.StartDate, _ = dateValue(value)
+		case "milestone":
+			if v, ok := stringValue(value); ok {
+				issue.Milestone = v
+			} else if value == nil {
+				issue.Milestone = ""
+			}
 		}
 	}
 
-	m.dirty[id] = true
+	m.markDirty(id)
 
-	// Record event
-	eventType := types.EventUpdated
-	if status, hasStatus := updates["status"]; hasStatus {
-		if status == string(types.StatusClosed) {
-			eventType = types.EventClosed
-		}
+	// Record event with the same old/new payloads as the SQLite backend
+	var oldValue, newValue *string
+	if data, err := json.Marshal(&oldIssue); err == nil {
+		oldValue = stringPtr(string(data))
 	}
-
-	event := &types.Event{
-		IssueID:   id,
-		EventType: eventType,
-		Actor:     actor,
-		CreatedAt: now,
+	if data, err := json.Marshal(updates); err == nil {
+		newValue = stringPtr(string(data))
 	}
-	m.events[id] = append(m.events[id], event)
-
-	return nil
+	m.recordEvent(id, determineEventType(oldIssue.Status, updates), actor, oldValue, newValue, nil)
 }
 
-// CloseIssue closes an issue with a reason
-func (m *MemoryStorage) CloseIssue(ctx context.Context, id string, reason string, actor string) error {
-	return m.UpdateIssue(ctx, id, map[string]interface{}{
-		"status": string(types.StatusClosed),
-	}, actor)
-}
-
-// SearchIssues finds issues matching query and filters
-func (m *MemoryStorage) SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error) {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-
-	var results []*types.Issue
-
-	for _, issue := range m.issues {
-		// Apply filters
-		if filter.Status != nil && issue.Status != *filter.Status {
-			continue
-		}
-		if filter.Priority != nil && issue.Priority != *filter.Priority {
-			continue
-		}
-		if filter.IssueType != nil && issue.IssueType != *filter.IssueType {
-			continue
-		}
-		if filter.Assignee != nil && issue.Assignee != *filter.Assignee {
-			continue
-		}
-
-		// Query search (title, description, or ID)
-		if query != "" {
-			query = strings.ToLower(query)
-			if !strings.Contains(strings.ToLower(issue.Title), query) &&
-				!strings.Contains(strings.ToLower(issue.Description), query) &&
-				!strings.Contains(strings.ToLower(issue.ID), query) {
-				continue
-			}
-		}
-
-		// Label filtering: must have ALL specified labels
-		if len(filter.Labels) > 0 {
-			issueLabels := m.labels[issue.ID]
-			hasAllLabels := true
-			for _, reqLabel := range filter.Labels {
-				found := false
-				for _, label := range issueLabels {
-					if label == reqLabel {
-						found = true
-						break
-					}
-				}
-				if !found {
-					hasAllLabels = false
-					break
-				}
-			}
-			if !hasAllLabels {
-				continue
-			}
-		}
+// UpdateIssues applies the same updates to every issue matching filter and
+// returns the IDs of the updated issues. Updates are validated first, so
+// either all matching issues change or none do.
+func (m *MemoryStorage) UpdateIssues(ctx context.Context, filter types.IssueFilter, updates map[string]interface{}, actor string) ([]string, error) {
+	if filter.IsEmpty() {
+		return nil, fmt.Errorf("bulk update requires a filter")
+	}
+	if len(updates) == 0 {
+		return nil, fmt.Errorf("no updates specified")
+	}
 
-		// ID filtering
-		if len(filter.IDs) > 0 {
-			found := false
-			for _, filterID := range filter.IDs {
-				if issue.ID == filterID {
-					found = true
-					break
-				}
-			}
-			if !found {
-				continue
-			}
-		}
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-		// Copy issue and attach metadata
-		issueCopy := *issue
-		if deps, ok := m.dependencies[issue.ID]; ok {
-			issueCopy.Dependencies = deps
-		}
-		if labels, ok := m.labels[issue.ID]; ok {
-			issueCopy.Labels = labels
+	for key, value := range updates {
+		if err := validateUpdate(key, value); err != nil {
+			return nil, err
 		}
-
-		results = append(results, &issueCopy)
 	}
 
-	// Sort by priority, then by created_at
-	sort.Slice(results, func(i, j int) bool {
-		if results[i].Priority != results[j].Priority {
-			return results[i].Priority < results[j].Priority
+	var matches []*types.Issue
+	for _, issue := range m.issues {
+		if m.matchesFilter(issue, "", filter) {
+			matches = append(matches, issue)
 		}
-		return results[i].CreatedAt.After(results[j].CreatedAt)
-	})
+	}
+	sortIssues(matches, filter.Sort)
+	if filter.Limit > 0 && len(matches) > filter.Limit {
+		matches = matches[:filter.Limit]
+	}
 
-	// Apply limit
-	if filter.Limit > 0 && len(results) > filter.Limit {
-		results = results[:filter.Limit]
+	ids := make([]string, 0, len(matches))
+	for _, issue := range matches {
+		m.applyUpdates(issue, updates, actor)
+		ids = append(ids, issue.ID)
 	}
 
-	return results, nil
+	return ids, nil
 }
 
-// AddDependency adds a dependency between issues
-func (m *MemoryStorage) AddDependency(ctx context.Context, dep *types.Dependency, actor string) error {
+// CloseIssue closes an issue with a reason
+func (m *MemoryStorage) CloseIssue(ctx context.Context, id string, reason string, actor string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	// Check that both issues exist
-	if _, exists := m.issues[dep.IssueID]; !exists {
-		return fmt.Errorf("issue %s not found", dep.IssueID)
-	}
-	if _, exists := m.issues[dep.DependsOnID]; !exists {
-		return fmt.Errorf("issue %s not found", dep.DependsOnID)
+	issue, exists := m.issues[id]
+	if !exists {
+		return types.NotFound("issue", id)
 	}
 
-	// Check for duplicates
-	for _, existing := range m.dependencies[dep.IssueID] {
-		if existing.DependsOnID == dep.DependsOnID && existing.Type == dep.Type {
-			return fmt.Errorf("dependency already exists")
-		}
-	}
+	now := time.Now()
+	issue.Status = types.StatusClosed
+	issue.ClosedAt = &now
+	issue.UpdatedAt = now
 
-	m.dependencies[dep.IssueID] = append(m.dependencies[dep.IssueID], dep)
-	m.dirty[dep.IssueID] = true
+	m.recordEvent(id, types.EventClosed, actor, nil, nil, stringPtr(reason))
+	m.markDirty(id)
 
 	return nil
 }
 
-// RemoveDependency removes a dependency
-func (m *MemoryStorage) RemoveDependency(ctx context.Context, issueID, dependsOnID string, actor string) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	deps := m.dependencies[issueID]
-	newDeps := make([]*types.Dependency, 0)
+// matchesFilter reports whether an issue passes the query and filter.
+// Callers must hold at least a read lock.
+func (m *MemoryStorage) matchesFilter(issue *types.Issue, query string, filter types.IssueFilter) bool {
+	if filter.Status != nil && issue.Status != *filter.Status {
+		return false
+	}
+	if filter.Priority != nil && issue.Priority != *filter.Priority {
+		return false
+	}
+	if filter.IssueType != nil && issue.IssueType != *filter.IssueType {
+		return false
+	}
+	if filter.Assignee != nil && issue.Assignee != *filter.Assignee {
+		return false
+	}
+	if filter.DueBefore != nil && (issue.DueDate == nil || !issue.DueDate.Before(*filter.DueBefore)) {
+		return false
+	}
+	if filter.DueAfter != nil && (issue.DueDate == nil || issue.DueDate.Before(*filter.DueAfter)) {
+		return false
+	}
+	if filter.Overdue && !issue.IsOverdue(time.Now()) {
+		return false
+	}
+	if filter.Milestone != nil && issue.Milestone != *filter.Milestone {
+		return false
+	}
+	if len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, issue.Status) ||
+		slices.Contains(filter.ExcludeStatuses, issue.Status) {
+		return false
+	}
+	if len(filter.Priorities) > 0 && !slices.Contains(filter.Priorities, issue.Priority) ||
+		slices.Contains(filter.ExcludePriorities, issue.Priority) {
+		return false
+	}
+	if len(filter.IssueTypes) > 0 && !slices.Contains(filter.IssueTypes, issue.IssueType) ||
+		slices.Contains(filter.ExcludeIssueTypes, issue.IssueType) {
+		return false
+	}
+	if len(filter.Assignees) > 0 && !slices.Contains(filter.Assignees, issue.Assignee) ||
+		slices.Contains(filter.ExcludeAssignees, issue.Assignee) {
+		return false
+	}
 
-	for _, dep := range deps {
-		if dep.DependsOnID != dependsOnID {
-			newDeps = append(newDeps, dep)
+	// Query search (title, description, or ID)
+	if query != "" {
+		if !strings.Contains(strings.ToLower(issue.Title), query) &&
+			!strings.Contains(strings.ToLower(issue.Description), query) &&
+			!strings.Contains(strings.ToLower(issue.ID), query) {
+			return false
 		}
 	}
 
-	m.dependencies[issueID] = newDeps
-	m.dirty[issueID] = true
-
-	return nil
-}
+	if filter.TitleSearch != "" && !strings.Contains(strings.ToLower(issue.Title), strings.ToLower(filter.TitleSearch)) {
+		return false
+	}
 
-// GetDependencies gets issues that this issue depends on
-func (m *MemoryStorage) GetDependencies(ctx context.Context, issueID string) ([]*types.Issue, error) {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
+	// Label filtering: must have ALL specified labels
+	for _, reqLabel := range filter.Labels {
+		if !m.hasMatchingLabel(issue.ID, reqLabel) {
+			return false
+		}
+	}
 
-	var results []*types.Issue
-	for _, dep := range m.dependencies[issueID] {
-		if issue, exists := m.issues[dep.DependsOnID]; exists {
-			issueCopy := *issue
-			results = append(results, &issueCopy)
+	// Label filtering (OR): must have AT LEAST ONE of these labels
+	if len(filter.LabelsAny) > 0 {
+		found := false
+		for _, label := range filter.LabelsAny {
+			if m.hasMatchingLabel(issue.ID, label) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
 		}
 	}
 
-	return results, nil
-}
+	// Label exclusion: must have NONE of these labels
+	for _, label := range filter.ExcludeLabels {
+		if m.hasMatchingLabel(issue.ID, label) {
+			return false
+		}
+	}
 
-// GetDependents gets issues that depend on this issue
-func (m *MemoryStorage) GetDependents(ctx context.Context, issueID string) ([]*types.Issue, error) {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
+	if filter.Query != nil && !filter.Query.Matches(issue, func(label string) bool { return m.hasMatchingLabel(issue.ID, label) }) {
+		return false
+	}
 
-	var results []*types.Issue
-	for id, deps := range m.dependencies {
-		for _, dep := range deps {
-			if dep.DependsOnID == issueID {
-				if issue, exists := m.issues[id]; exists {
-					results = append(results, issue)
-				}
+	// ID filtering
+	if len(filter.IDs) > 0 {
+		found := false
+		for _, filterID := range filter.IDs {
+			if issue.ID == filterID {
+				found = true
 				break
 			}
 		}
+		if !found {
+			return false
+		}
 	}
 
-	return results, nil
+	return true
 }
 
-// GetDependencyRecords gets dependency records for an issue
-func (m *MemoryStorage) GetDependencyRecords(ctx context.Context, issueID string) ([]*types.Dependency, error) {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-
-	return m.dependencies[issueID], nil
+// hasLabel reports whether an issue carries a label. Callers must hold a lock.
+func (m *MemoryStorage) hasLabel(issueID, label string) bool {
+	for _, l := range m.labels[issueID] {
+		if l == label {
+			return true
+		}
+	}
+	return false
 }
 
-// GetAllDependencyRecords gets all dependency records
-func (m *MemoryStorage) GetAllDependencyRecords(ctx context.Context) (map[string][]*types.Dependency, error) {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-
-	// Return a copy
-	result := make(map[string][]*types.Dependency)
-	for k, v := range m.dependencies {
-		result[k] = v
+// hasMatchingLabel reports whether an issue carries a label matching a label
+// filter such as infra or infra/*. Callers must hold a lock.
+func (m *MemoryStorage) hasMatchingLabel(issueID, filter string) bool {
+	for _, l := range m.labels[issueID] {
+		if types.MatchesLabelFilter(l, filter) {
+			return true
+		}
 	}
-
-	return result, nil
+	return false
 }
 
-// GetDirtyIssueHash returns the hash for dirty issue tracking
-func (m *MemoryStorage) GetDirtyIssueHash(ctx context.Context, issueID string) (string, error) {
-	// Memory storage doesn't track dirty hashes, return empty string
-	return "", nil
+// sortByPriority orders issues by priority, newest first within a priority,
+// matching the SQLite backend's ORDER BY priority ASC, created_at DESC
+func sortByPriority(issues []*types.Issue) {
+	sortIssues(issues, nil)
 }
 
-// GetExportHash returns the hash for export tracking
-func (m *MemoryStorage) GetExportHash(ctx context.Context, issueID string) (string, error) {
-	// Memory storage doesn't track export hashes, return empty string
-	return "", nil
+// sortIssues orders issues by keys, then as sortByPriority does
+func sortIssues(issues []*types.Issue, keys []types.SortKey) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		if c := types.CompareIssues(issues[i], issues[j], keys); c != 0 {
+			return c < 0
+		}
+		if issues[i].Priority != issues[j].Priority {
+			return issues[i].Priority < issues[j].Priority
+		}
+		if !issues[i].CreatedAt.Equal(issues[j].CreatedAt) {
+			return issues[i].CreatedAt.After(issues[j].CreatedAt)
+		}
+		return issues[i].ID < issues[j].ID
+	})
 }
 
-// SetExportHash sets the hash for export tracking
-func (m *MemoryStorage) SetExportHash(ctx context.Context, issueID, hash string) error {
-	// Memory storage doesn't track export hashes, no-op
+// ForEachIssue calls fn with each issue SearchIssues would return for
+// filter; the issues are in memory anyway
+func (m *MemoryStorage) ForEachIssue(ctx context.Context, filter types.IssueFilter, fn func(*types.Issue) error) error {
+	issues, err := m.SearchIssues(ctx, "", filter)
+	if err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		if err := fn(issue); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-// GetDependencyTree gets the dependency tree for an issue
-func (m *MemoryStorage) GetDependencyTree(ctx context.Context, issueID string, maxDepth int, showAllPaths bool, reverse bool) ([]*types.TreeNode, error) {
-	// Simplified implementation - just return direct dependencies
-	deps, err := m.GetDependencies(ctx, issueID)
-	if err != nil {
-		return nil, err
-	}
+// SearchIssues finds issues matching query and filters
+func (m *MemoryStorage) SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	query = strings.ToLower(query)
 
-	var nodes []*types.TreeNode
-	for _, dep := range deps {
-		node := &types.TreeNode{
-			Depth: 1,
+	var results []*types.Issue
+	for _, issue := range m.issues {
+		if !m.matchesFilter(issue, query, filter) {
+			continue
 		}
-		// Copy issue fields
-		node.ID = dep.ID
-		node.Title = dep.Title
-		node.Description = dep.Description
-		node.Status = dep.Status
-		node.Priority = dep.Priority
-		node.IssueType = dep.IssueType
-		nodes = append(nodes, node)
+		results = append(results, m.copyIssue(issue))
 	}
 
-	return nodes, nil
-}
+	sortIssues(results, filter.Sort)
 
-// DetectCycles detects dependency cycles
-func (m *MemoryStorage) DetectCycles(ctx context.Context) ([][]*types.Issue, error) {
-	// Simplified - return empty (no cycles detected)
-	return nil, nil
+	// Apply limit
+	if filter.Limit > 0 && len(results) > filter.Limit {
+		results = results[:filter.Limit]
+	}
+
+	if filter.WithCounts {
+		dependents := make(map[string]int)
+		for _, deps := range m.dependencies {
+			for _, dep := range deps {
+				dependents[dep.DependsOnID]++
+			}
+		}
+		for _, issue := range results {
+			issue.DependencyCount = len(m.dependencies[issue.ID])
+			issue.DependentCount = dependents[issue.ID]
+			issue.CommentCount = len(m.comments[issue.ID])
+		}
+	}
+
+	return results, nil
 }
 
-// Add label methods
+// AddLabel adds a label to an issue
 func (m *MemoryStorage) AddLabel(ctx context.Context, issueID, label, actor string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	// Check if issue exists
 	if _, exists := m.issues[issueID]; !exists {
-		return fmt.Errorf("issue %s not found", issueID)
+		return types.NotFound("issue", issueID)
 	}
 
-	// Check for duplicate
-	for _, l := range m.labels[issueID] {
-		if l == label {
-			return nil // Already exists
-		}
+	// Adding an existing label is a no-op, but still audited like INSERT OR IGNORE
+	if !m.hasLabel(issueID, label) {
+		m.labels[issueID] = append(m.labels[issueID], label)
 	}
 
-	m.labels[issueID] = append(m.labels[issueID], label)
-	m.dirty[issueID] = true
+	m.recordEvent(issueID, types.EventLabelAdded, actor, nil, nil, stringPtr(fmt.Sprintf("Added label: %s", label)))
+	m.markDirty(issueID)
 
 	return nil
 }
 
+// RemoveLabel removes a label from an issue
 func (m *MemoryStorage) RemoveLabel(ctx context.Context, issueID, label, actor string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	labels := m.labels[issueID]
-	newLabels := make([]string, 0)
-
+	newLabels := make([]string, 0, len(labels))
 	for _, l := range labels {
 		if l != label {
 			newLabels = append(newLabels, l)
 		}
 	}
+	if len(newLabels) == 0 {
+		delete(m.labels, issueID)
+	} else {
+		m.labels[issueID] = newLabels
+	}
 
-	m.labels[issueID] = newLabels
-	m.dirty[issueID] = true
+	m.recordEvent(issueID, types.EventLabelRemoved, actor, nil, nil, stringPtr(fmt.Sprintf("Removed label: %s", label)))
+	m.markDirty(issueID)
 
 	return nil
 }
 
+// GetLabels returns all labels for an issue
 func (m *MemoryStorage) GetLabels(ctx context.Context, issueID string) ([]string, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	return m.labels[issueID], nil
+	return m.sortedLabels(issueID), nil
 }
 
+// GetIssuesByLabel returns issues with a specific label
 func (m *MemoryStorage) GetIssuesByLabel(ctx context.Context, label string) ([]*types.Issue, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
 	var results []*types.Issue
-	for issueID, labels := range m.labels {
-		for _, l := range labels {
-			if l == label {
-				if issue, exists := m.issues[issueID]; exists {
-					issueCopy := *issue
-					results = append(results, &issueCopy)
-				}
-				break
-			}
+	for issueID := range m.labels {
+		if !m.hasLabel(issueID, label) {
+			continue
+		}
+		if issue, exists := m.issues[issueID]; exists {
+			results = append(results, m.copyIssue(issue))
 		}
 	}
 
+	sortByPriority(results)
 	return results, nil
 }
 
-// Stub implementations for other required methods
-func (m *MemoryStorage) GetReadyWork(ctx context.Context, filter types.WorkFilter) ([]*types.Issue, error) {
-	// Simplified: return open issues with no blocking dependencies
-	return m.SearchIssues(ctx, "", types.IssueFilter{
-		Status: func() *types.Status { s := types.StatusOpen; return &s }(),
-	})
-}
-
-func (m *MemoryStorage) GetBlockedIssues(ctx context.Context) ([]*types.BlockedIssue, error) {
-	return nil, nil
-}
-
-func (m *MemoryStorage) GetEpicsEligibleForClosure(ctx context.Context) ([]*types.EpicStatus, error) {
-	return nil, nil
-}
-
+// AddComment records a comment in the issue's event history
 func (m *MemoryStorage) AddComment(ctx context.Context, issueID, actor, comment string) error {
-	return nil
-}
-
-func (m *MemoryStorage) GetEvents(ctx context.Context, issueID string, limit int) ([]*types.Event, error) {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-
-	events := m.events[issueID]
-	if limit > 0 && len(events) > limit {
-		events = events[len(events)-limit:]
-	}
-
-	return events, nil
-}
-
-func (m *MemoryStorage) AddIssueComment(ctx context.Context, issueID, author, text string) (*types.Comment, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	comment := &types.Comment{
-		ID:        int64(len(m.comments[issueID]) + 1),
-		IssueID:   issueID,
-		Author:    author,
-		Text:      text,
-		CreatedAt: time.Now(),
+	issue, exists := m.issues[issueID]
+	if !exists {
+		return types.NotFound("issue", issueID)
 	}
 
-	m.comments[issueID] = append(m.comments[issueID], comment)
-	m.dirty[issueID] = true
-
-	return comment, nil
-}
-
-func (m *MemoryStorage) GetIssueComments(ctx context.Context, issueID string) ([]*types.Comment, error) {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
+	issue.UpdatedAt = time.Now()
+	m.recordEvent(issueID, types.EventCommented, actor, nil, nil, stringPtr(comment))
+	m.markDirty(issueID)
 
-	return m.comments[issueID], nil
+	return nil
 }
 
-func (m *MemoryStorage) GetStatistics(ctx context.Context) (*types.Statistics, error) {
+// GetEvents returns the event history for an issue, newest first
+func (m *MemoryStorage) GetEvents(ctx context.Context, issueID string, limit int) ([]*types.Event, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	stats := &types.Statistics{
-		TotalIssues: len(m.issues),
-	}
-
-	for _, issue := range m.issues {
-		switch issue.Status {
-		case types.StatusOpen:
-			stats.OpenIssues++
-		case types.StatusInProgress:
-			stats.InProgressIssues++
-		case types.StatusBlocked:
-			stats.BlockedIssues++
-		case types.StatusClosed:
-			stats.ClosedIssues++
+	stored := m.events[issueID]
+	events := make([]*types.Event, 0, len(stored))
+	for i := len(stored) - 1; i >= 0; i-- {
+		e := *stored[i]
+		events = append(events, &e)
+		if limit > 0 && len(events) == limit {
+			break
 		}
 	}
 
-	return stats, nil
+	return events, nil
 }
 
-// Dirty tracking
+// GetDirtyIssues returns the IDs of issues changed since the last export,
+// oldest change first
 func (m *MemoryStorage) GetDirtyIssues(ctx context.Context) ([]string, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -786,18 +1044,33 @@ func (m *MemoryStorage) GetDirtyIssues(ctx context.Context) ([]string, error) {
 	for id := range m.dirty {
 		dirtyIDs = append(dirtyIDs, id)
 	}
+	sort.Slice(dirtyIDs, func(i, j int) bool {
+		ti, tj := m.dirty[dirtyIDs[i]], m.dirty[dirtyIDs[j]]
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+		return dirtyIDs[i] < dirtyIDs[j]
+	})
 
 	return dirtyIDs, nil
 }
 
+// GetDirtyIssueHash returns the hash for dirty issue tracking
+func (m *MemoryStorage) GetDirtyIssueHash(ctx context.Context, issueID string) (string, error) {
+	// Content hashes are never stored on dirty entries, matching a fresh SQLite row
+	return "", nil
+}
+
+// ClearDirtyIssues removes all dirty markers
 func (m *MemoryStorage) ClearDirtyIssues(ctx context.Context) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	m.dirty = make(map[string]bool)
+	m.dirty = make(map[string]time.Time)
 	return nil
 }
 
+// ClearDirtyIssuesByID removes dirty markers for the given issues only
 func (m *MemoryStorage) ClearDirtyIssuesByID(ctx context.Context, issueIDs []string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -809,7 +1082,33 @@ func (m *MemoryStorage) ClearDirtyIssuesByID(ctx context.Context, issueIDs []str
 	return nil
 }
 
-// Config
+// MarkIssueDirty marks an issue as dirty for export
+func (m *MemoryStorage) MarkIssueDirty(ctx context.Context, issueID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.markDirty(issueID)
+	return nil
+}
+
+// GetExportHash returns the content hash recorded at the last export
+func (m *MemoryStorage) GetExportHash(ctx context.Context, issueID string) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.exportHashes[issueID], nil
+}
+
+// SetExportHash records the content hash of an exported issue
+func (m *MemoryStorage) SetExportHash(ctx context.Context, issueID, hash string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.exportHashes[issueID] = hash
+	return nil
+}
+
+// SetConfig sets a configuration value
 func (m *MemoryStorage) SetConfig(ctx context.Context, key, value string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -818,6 +1117,7 @@ func (m *MemoryStorage) SetConfig(ctx context.Context, key, value string) error
 	return nil
 }
 
+// GetConfig returns a configuration value, or "" if unset
 func (m *MemoryStorage) GetConfig(ctx context.Context, key string) (string, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -825,6 +1125,7 @@ func (m *MemoryStorage) GetConfig(ctx context.Context, key string) (string, erro
 	return m.config[key], nil
 }
 
+// DeleteConfig removes a configuration value
 func (m *MemoryStorage) DeleteConfig(ctx context.Context, key string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -833,6 +1134,7 @@ func (m *MemoryStorage) DeleteConfig(ctx context.Context, key string) error {
 	return nil
 }
 
+// GetAllConfig returns a copy of all configuration values
 func (m *MemoryStorage) GetAllConfig(ctx context.Context) (map[string]string, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -846,7 +1148,7 @@ func (m *MemoryStorage) GetAllConfig(ctx context.Context) (map[string]string, er
 	return result, nil
 }
 
-// Metadata
+// SetMetadata sets an internal metadata value
 func (m *MemoryStorage) SetMetadata(ctx context.Context, key, value string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -855,6 +1157,7 @@ func (m *MemoryStorage) SetMetadata(ctx context.Context, key, value string) erro
 	return nil
 }
 
+// GetMetadata returns an internal metadata value, or "" if unset
 func (m *MemoryStorage) GetMetadata(ctx context.Context, key string) (string, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -862,42 +1165,94 @@ func (m *MemoryStorage) GetMetadata(ctx context.Context, key string) (string, er
 	return m.metadata[key], nil
 }
 
-// Prefix rename operations (no-ops for memory storage)
+// UpdateIssueID renames an issue and rewrites every reference to it
 func (m *MemoryStorage) UpdateIssueID(ctx context.Context, oldID, newID string, issue *types.Issue, actor string) error {
-	return fmt.Errorf("UpdateIssueID not supported in --no-db mode")
-}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, exists := m.issues[oldID]
+	if !exists {
+		return types.NotFound("issue", oldID)
+	}
+	if _, taken := m.issues[newID]; taken && newID != oldID {
+		return fmt.Errorf("issue %s already exists", newID)
+	}
+
+	stored.ID = newID
+	stored.Title = issue.Title
+	stored.Description = issue.Description
+	stored.Design = issue.Design
+	stored.AcceptanceCriteria = issue.AcceptanceCriteria
+	stored.Notes = issue.Notes
+	stored.UpdatedAt = time.Now()
+	delete(m.issues, oldID)
+	m.issues[newID] = stored
+
+	// Dependencies in both directions
+	if deps, ok := m.dependencies[oldID]; ok {
+		delete(m.dependencies, oldID)
+		m.dependencies[newID] = deps
+	}
+	for _, deps := range m.dependencies {
+		for _, dep := range deps {
+			if dep.IssueID == oldID {
+				dep.IssueID = newID
+			}
+			if dep.DependsOnID == oldID {
+				dep.DependsOnID = newID
+			}
+		}
+	}
+
+	if labels, ok := m.labels[oldID]; ok {
+		delete(m.labels, oldID)
+		m.labels[newID] = labels
+	}
+	if comments, ok := m.comments[oldID]; ok {
+		delete(m.comments, oldID)
+		for _, c := range comments {
+			c.IssueID = newID
+		}
+		m.comments[newID] = comments
+	}
+	if events, ok := m.events[oldID]; ok {
+		delete(m.events, oldID)
+		for _, e := range events {
+			e.IssueID = newID
+		}
+		m.events[newID] = events
+	}
+	if hash, ok := m.exportHashes[oldID]; ok {
+		delete(m.exportHashes, oldID)
+		m.exportHashes[newID] = hash
+	}
+	delete(m.dirty, oldID)
+	m.markDirty(newID)
+
+	m.recordEvent(newID, "renamed", actor, stringPtr(oldID), stringPtr(newID), nil)
 
-func (m *MemoryStorage) RenameDependencyPrefix(ctx context.Context, oldPrefix, newPrefix string) error {
 	return nil
 }
 
-func (m *MemoryStorage) RenameCounterPrefix(ctx context.Context, oldPrefix, newPrefix string) error {
+// RenameDependencyPrefix updates the prefix in all dependency records.
+// UpdateIssueID already rewrites references, so like SQLite this is a no-op.
+func (m *MemoryStorage) RenameDependencyPrefix(ctx context.Context, oldPrefix, newPrefix string) error {
 	return nil
 }
 
-// Lifecycle
-func (m *MemoryStorage) Close() error {
+// RenameCounterPrefix moves the ID counter from one prefix to another
+func (m *MemoryStorage) RenameCounterPrefix(ctx context.Context, oldPrefix, newPrefix string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	m.closed = true
-	return nil
-}
-
-func (m *MemoryStorage) Path() string {
-	return m.jsonlPath
-}
-
-// UnderlyingDB returns nil for memory storage (no SQL database)
-func (m *MemoryStorage) UnderlyingDB() *sql.DB {
+	lastID := m.counters[oldPrefix]
+	delete(m.counters, oldPrefix)
+	if m.counters[newPrefix] < lastID {
+		m.counters[newPrefix] = lastID
+	}
 	return nil
 }
 
-// UnderlyingConn returns error for memory storage (no SQL database)
-func (m *MemoryStorage) UnderlyingConn(ctx context.Context) (*sql.Conn, error) {
-	return nil, fmt.Errorf("UnderlyingConn not available in memory storage")
-}
-
 // SyncAllCounters synchronizes ID counters based on existing issues
 func (m *MemoryStorage) SyncAllCounters(ctx context.Context) error {
 	m.mu.Lock()
@@ -919,11 +1274,28 @@ func (m *MemoryStorage) SyncAllCounters(ctx context.Context) error {
 	return nil
 }
 
-// MarkIssueDirty marks an issue as dirty for export
-func (m *MemoryStorage) MarkIssueDirty(ctx context.Context, issueID string) error {
+// Close marks the storage closed. The data stays readable until the value is
+// garbage collected; nothing is persisted.
+func (m *MemoryStorage) Close() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	m.dirty[issueID] = true
+	m.closed = true
+	return nil
+}
+
+// Path returns the JSONL file this storage was loaded from, or "" for
+// ephemeral storage
+func (m *MemoryStorage) Path() string {
+	return m.jsonlPath
+}
+
+// UnderlyingDB returns nil for memory storage (no SQL database)
+func (m *MemoryStorage) UnderlyingDB() *sql.DB {
 	return nil
 }
+
+// UnderlyingConn returns error for memory storage (no SQL database)
+func (m *MemoryStorage) UnderlyingConn(ctx context.Context) (*sql.Conn, error) {
+	return nil, fmt.Errorf("UnderlyingConn not available in memory storage")
+}
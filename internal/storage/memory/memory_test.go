@@ -913,3 +913,312 @@ func TestClose(t *testing.T) {
 		t.Error("Store should be closed")
 	}
 }
+
+func TestEvents(t *testing.T) {
+	store := setupTestMemory(t)
+	defer store.Close()
+
+	ctx := context.Background()
+	issue := createTestIssue(t, store, "Events", 1, types.TypeTask)
+
+	steps := []func() error{
+		func() error {
+			return store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"status": types.StatusInProgress}, "alice")
+		},
+		func() error { return store.AddLabel(ctx, issue.ID, "backend", "alice") },
+		func() error { return store.AddComment(ctx, issue.ID, "bob", "looks good") },
+		func() error { return store.CloseIssue(ctx, issue.ID, "Done", "alice") },
+		func() error {
+			return store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"status": "open"}, "bob")
+		},
+	}
+	for i, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("step %d failed: %v", i, err)
+		}
+	}
+
+	events, err := store.GetEvents(ctx, issue.ID, 0)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	want := []types.EventType{
+		types.EventReopened, types.EventClosed, types.EventCommented,
+		types.EventLabelAdded, types.EventStatusChanged, types.EventCreated,
+	}
+	if len(events) != len(want) {
+		t.Fatalf("expected %d events, got %d", len(want), len(events))
+	}
+	for i, e := range events {
+		if e.EventType != want[i] {
+			t.Errorf("event %d: got %s, want %s", i, e.EventType, want[i])
+		}
+	}
+	if events[1].Comment == nil || *events[1].Comment != "Done" {
+		t.Errorf("close event should carry the reason, got %v", events[1].Comment)
+	}
+
+	limited, err := store.GetEvents(ctx, issue.ID, 2)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	if len(limited) != 2 || limited[0].EventType != types.EventReopened {
+		t.Errorf("limit should keep the newest events, got %d", len(limited))
+	}
+
+	reopened, err := store.GetIssue(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if reopened.ClosedAt != nil {
+		t.Error("reopening should clear closed_at")
+	}
+}
+
+func TestUpdateIssueValidation(t *testing.T) {
+	store := setupTestMemory(t)
+	defer store.Close()
+
+	ctx := context.Background()
+	issue := createTestIssue(t, store, "Validate", 1, types.TypeTask)
+
+	bad := []map[string]interface{}{
+		{"priority": 7},
+		{"status": "done"},
+		{"issue_type": "story"},
+		{"title": ""},
+		{"estimated_minutes": -5},
+		{"id": "bd-99"},
+	}
+	for _, updates := range bad {
+		if err := store.UpdateIssue(ctx, issue.ID, updates, "test-user"); err == nil {
+			t.Errorf("expected error for %v", updates)
+		}
+	}
+
+	// Rejected updates must not partially apply
+	err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"title": "Changed", "priority": 9}, "test-user")
+	if err == nil {
+		t.Fatal("expected error for invalid priority")
+	}
+	got, _ := store.GetIssue(ctx, issue.ID)
+	if got.Title != "Validate" {
+		t.Errorf("title changed despite failed update: %q", got.Title)
+	}
+
+	if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"estimated_minutes": 30, "issue_type": types.TypeBug}, "test-user"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	got, _ = store.GetIssue(ctx, issue.ID)
+	if got.EstimatedMinutes == nil || *got.EstimatedMinutes != 30 || got.IssueType != types.TypeBug {
+		t.Errorf("typed update values not applied: %+v", got)
+	}
+}
+
+func TestCreateIssueWrongPrefix(t *testing.T) {
+	store := setupTestMemory(t)
+	defer store.Close()
+
+	issue := &types.Issue{ID: "other-1", Title: "Wrong", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(context.Background(), issue, "test-user"); err == nil {
+		t.Error("expected error for ID with a different prefix")
+	}
+}
+
+func TestUpdateIssueID(t *testing.T) {
+	store := setupTestMemory(t)
+	defer store.Close()
+
+	ctx := context.Background()
+	a := createTestIssue(t, store, "A", 1, types.TypeTask)
+	b := createTestIssue(t, store, "B", 1, types.TypeTask)
+	addTestDep(t, store, b.ID, a.ID, types.DepBlocks)
+	if err := store.AddLabel(ctx, a.ID, "keep", "test-user"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+
+	renamed, _ := store.GetIssue(ctx, a.ID)
+	renamed.Title = "A renamed"
+	if err := store.UpdateIssueID(ctx, a.ID, "bd-100", renamed, "test-user"); err != nil {
+		t.Fatalf("UpdateIssueID failed: %v", err)
+	}
+
+	if old, _ := store.GetIssue(ctx, a.ID); old != nil {
+		t.Error("old ID should no longer exist")
+	}
+	got, _ := store.GetIssue(ctx, "bd-100")
+	if got == nil || got.Title != "A renamed" || len(got.Labels) != 1 {
+		t.Fatalf("renamed issue not found intact: %+v", got)
+	}
+
+	deps, _ := store.GetDependencyRecords(ctx, b.ID)
+	if len(deps) != 1 || deps[0].DependsOnID != "bd-100" {
+		t.Errorf("dependency not rewritten: %+v", deps)
+	}
+
+	events, _ := store.GetEvents(ctx, "bd-100", 1)
+	if len(events) != 1 || events[0].EventType != "renamed" {
+		t.Errorf("expected rename event, got %+v", events)
+	}
+}
+
+func TestReturnedSlicesAreCopies(t *testing.T) {
+	store := setupTestMemory(t)
+	defer store.Close()
+
+	ctx := context.Background()
+	issue := createTestIssue(t, store, "Copies", 1, types.TypeTask)
+	if err := store.AddLabel(ctx, issue.ID, "a", "test-user"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+
+	labels, _ := store.GetLabels(ctx, issue.ID)
+	labels[0] = "mutated"
+
+	again, _ := store.GetLabels(ctx, issue.ID)
+	if again[0] != "a" {
+		t.Errorf("caller mutation leaked into storage: %v", again)
+	}
+}
+
+func TestUpdateIssues(t *testing.T) {
+	store := setupTestMemory(t)
+	defer store.Close()
+
+	ctx := context.Background()
+
+	a := createTestIssue(t, store, "Rotate certs", 1, types.TypeTask)
+	b := createTestIssue(t, store, "Upgrade kernel", 2, types.TypeTask)
+	c := createTestIssue(t, store, "Fix typo", 3, types.TypeTask)
+	for _, id := range []string{a.ID, b.ID} {
+		if err := store.AddLabel(ctx, id, "infra", "test-user"); err != nil {
+			t.Fatalf("AddLabel failed: %v", err)
+		}
+	}
+
+	open := types.StatusOpen
+	filter := types.IssueFilter{Status: &open, Labels: []string{"infra"}}
+
+	// An invalid update changes nothing
+	if _, err := store.UpdateIssues(ctx, filter, map[string]interface{}{"assignee": "bob", "priority": 9}, "alice"); err == nil {
+		t.Fatal("Expected invalid priority to fail")
+	}
+	if got, _ := store.GetIssue(ctx, a.ID); got.Assignee != "" {
+		t.Errorf("Expected no partial update, got assignee %q", got.Assignee)
+	}
+
+	affected, err := store.UpdateIssues(ctx, filter, map[string]interface{}{"assignee": "bob"}, "alice")
+	if err != nil {
+		t.Fatalf("UpdateIssues failed: %v", err)
+	}
+	if len(affected) != 2 || affected[0] != a.ID || affected[1] != b.ID {
+		t.Fatalf("Expected affected [%s %s], got %v", a.ID, b.ID, affected)
+	}
+	for _, tc := range []struct {
+		id, want string
+	}{{a.ID, "bob"}, {b.ID, "bob"}, {c.ID, ""}} {
+		got, _ := store.GetIssue(ctx, tc.id)
+		if got.Assignee != tc.want {
+			t.Errorf("%s: expected assignee %q, got %q", tc.id, tc.want, got.Assignee)
+		}
+	}
+
+	if _, err := store.UpdateIssues(ctx, types.IssueFilter{}, map[string]interface{}{"assignee": "bob"}, "alice"); err == nil {
+		t.Error("Expected empty filter to be rejected")
+	}
+}
+
+func TestSearchIssuesLabelChildren(t *testing.T) {
+	store := setupTestMemory(t)
+	defer store.Close()
+
+	ctx := context.Background()
+
+	parent := createTestIssue(t, store, "Plan capacity", 1, types.TypeTask)
+	child := createTestIssue(t, store, "Tune queries", 1, types.TypeTask)
+	other := createTestIssue(t, store, "Write docs", 1, types.TypeTask)
+	for id, label := range map[string]string{parent.ID: "infra", child.ID: "infra/db", other.ID: "infrastructure"} {
+		if err := store.AddLabel(ctx, id, label, "test-user"); err != nil {
+			t.Fatalf("AddLabel failed: %v", err)
+		}
+	}
+
+	issues, err := store.SearchIssues(ctx, "", types.IssueFilter{Labels: []string{"infra/*"}})
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+	if len(issues) != 1 || issues[0].ID != child.ID {
+		t.Errorf("Expected only %s under infra/*, got %v", child.ID, issues)
+	}
+}
+
+func TestSearchIssuesMultiValueFilters(t *testing.T) {
+	store := setupTestMemory(t)
+	defer store.Close()
+
+	ctx := context.Background()
+
+	bug := createTestIssue(t, store, "Crash", 0, types.TypeBug)
+	task := createTestIssue(t, store, "Refactor", 2, types.TypeTask)
+	ignored := createTestIssue(t, store, "Cosmetic", 3, types.TypeBug)
+	if err := store.UpdateIssue(ctx, task.ID, map[string]interface{}{"assignee": "bob"}, "test-user"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	if err := store.AddLabel(ctx, ignored.ID, "wontfix", "test-user"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+
+	issues, err := store.SearchIssues(ctx, "", types.IssueFilter{
+		Priorities:       []int{0, 2, 3},
+		ExcludeAssignees: []string{"bob"},
+		ExcludeLabels:    []string{"wontfix"},
+	})
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+	if len(issues) != 1 || issues[0].ID != bug.ID {
+		t.Errorf("Expected only %s, got %v", bug.ID, issues)
+	}
+
+	issues, err = store.SearchIssues(ctx, "", types.IssueFilter{Assignees: []string{""}, IssueTypes: []types.IssueType{types.TypeBug}})
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+	if len(issues) != 2 {
+		t.Errorf("Expected 2 unassigned bugs, got %v", issues)
+	}
+}
+
+func TestSearchIssuesSort(t *testing.T) {
+	store := setupTestMemory(t)
+	defer store.Close()
+
+	ctx := context.Background()
+
+	a := createTestIssue(t, store, "A", 2, types.TypeTask)
+	createTestIssue(t, store, "B", 1, types.TypeTask)
+	c := createTestIssue(t, store, "C", 2, types.TypeTask)
+	for id, assignee := range map[string]string{a.ID: "bob", c.ID: "alice"} {
+		if err := store.UpdateIssue(ctx, id, map[string]interface{}{"assignee": assignee}, "test-user"); err != nil {
+			t.Fatalf("UpdateIssue failed: %v", err)
+		}
+	}
+
+	for spec, want := range map[string]string{"-priority,title": "ACB", "assignee": "CAB", "-assignee": "ACB"} {
+		keys, err := types.ParseIssueSort(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		issues, err := store.SearchIssues(ctx, "", types.IssueFilter{Sort: keys})
+		if err != nil {
+			t.Fatalf("SearchIssues failed: %v", err)
+		}
+		got := ""
+		for _, issue := range issues {
+			got += issue.Title
+		}
+		if got != want {
+			t.Errorf("sort=%s: got %s, want %s", spec, got, want)
+		}
+	}
+}
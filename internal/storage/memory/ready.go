@@ -0,0 +1,342 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// isActive reports whether an issue still blocks its dependents
+func isActive(status types.Status) bool {
+	return status == types.StatusOpen || status == types.StatusInProgress || status == types.StatusBlocked
+}
+
+// openBlockers returns the IDs of active issues that directly block issueID
+// via 'blocks' dependencies. Callers must hold at least a read lock.
+func (m *MemoryStorage) openBlockers(issueID string) []string {
+	var blockers []string
+	for _, dep := range m.dependencies[issueID] {
+		if dep.Type != types.DepBlocks {
+			continue
+		}
+		if blocker, ok := m.issues[dep.DependsOnID]; ok && isActive(blocker.Status) {
+			blockers = append(blockers, dep.DependsOnID)
+		}
+	}
+	return blockers
+}
+
+// blockedSet returns every issue that is blocked directly or, through
+// parent-child links, by a blocked ancestor. Callers must hold at least a read lock.
+func (m *MemoryStorage) blockedSet() map[string]bool {
+	blocked := make(map[string]bool)
+	var queue []string
+	for id := range m.issues {
+		if len(m.openBlockers(id)) > 0 {
+			blocked[id] = true
+			queue = append(queue, id)
+		}
+	}
+
+	// Children of blocked issues inherit the blockage
+	for depth := 0; len(queue) > 0 && depth < 50; depth++ {
+		var next []string
+		for _, parentID := range queue {
+			for childID, deps := range m.dependencies {
+				if blocked[childID] {
+					continue
+				}
+				for _, dep := range deps {
+					if dep.Type == types.DepParentChild && dep.DependsOnID == parentID {
+						blocked[childID] = true
+						next = append(next, childID)
+						break
+					}
+				}
+			}
+		}
+		queue = next
+	}
+
+	return blocked
+}
+
+// GetReadyWork returns issues with no open blockers
+// By default, shows both 'open' and 'in_progress' issues (bd-165)
+func (m *MemoryStorage) GetReadyWork(ctx context.Context, filter types.WorkFilter) ([]*types.Issue, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	blocked := m.blockedSet()
+	now := time.Now()
+
+	var results []*types.Issue
+issues:
+	for _, issue := range m.issues {
+		if filter.Status == "" {
+			if issue.Status != types.StatusOpen && issue.Status != types.StatusInProgress {
+				continue
+			}
+		} else if issue.Status != filter.Status {
+			continue
+		}
+		if filter.Priority != nil && issue.Priority != *filter.Priority {
+			continue
+		}
+		if filter.MaxPriority != nil && issue.Priority > *filter.MaxPriority {
+			continue
+		}
+		if filter.Assignee != nil && issue.Assignee != *filter.Assignee {
+			continue
+		}
+		for _, label := range filter.Labels {
+			if !m.hasMatchingLabel(issue.ID, label) {
+				continue issues
+			}
+		}
+		if blocked[issue.ID] {
+			continue
+		}
+		// Issues scheduled to start later aren't ready yet
+		if issue.StartDate != nil && issue.StartDate.After(now) {
+			continue
+		}
+		results = append(results, m.copyIssue(issue))
+	}
+
+	sortReadyWork(results, filter.SortPolicy, now)
+
+	if filter.Limit > 0 && len(results) > filter.Limit {
+		results = results[:filter.Limit]
+	}
+
+	return results, nil
+}
+
+// sortReadyWork orders ready work using the same policies as the SQLite backend
+func sortReadyWork(issues []*types.Issue, policy types.SortPolicy, now time.Time) {
+	byAge := func(i, j int) bool {
+		if !issues[i].CreatedAt.Equal(issues[j].CreatedAt) {
+			return issues[i].CreatedAt.Before(issues[j].CreatedAt)
+		}
+		return issues[i].ID < issues[j].ID
+	}
+
+	switch policy {
+	case types.SortPolicyPriority:
+		sort.SliceStable(issues, func(i, j int) bool {
+			if issues[i].Priority != issues[j].Priority {
+				return issues[i].Priority < issues[j].Priority
+			}
+			return byAge(i, j)
+		})
+
+	case types.SortPolicyOldest:
+		sort.SliceStable(issues, byAge)
+
+	default:
+		// Hybrid: issues from the last 48 hours by priority, then older ones by age
+		cutoff := now.Add(-48 * time.Hour)
+		sort.SliceStable(issues, func(i, j int) bool {
+			recentI := !issues[i].CreatedAt.Before(cutoff)
+			recentJ := !issues[j].CreatedAt.Before(cutoff)
+			if recentI != recentJ {
+				return recentI
+			}
+			if recentI && issues[i].Priority != issues[j].Priority {
+				return issues[i].Priority < issues[j].Priority
+			}
+			return byAge(i, j)
+		})
+	}
+
+	// Overdue issues come first under every policy
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].IsOverdue(now) && !issues[j].IsOverdue(now)
+	})
+}
+
+// GetBlockedIssues returns issues that are blocked by dependencies
+func (m *MemoryStorage) GetBlockedIssues(ctx context.Context) ([]*types.BlockedIssue, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var blocked []*types.BlockedIssue
+	for _, issue := range m.issues {
+		if !isActive(issue.Status) {
+			continue
+		}
+		blockers := m.openBlockers(issue.ID)
+		if len(blockers) == 0 {
+			continue
+		}
+		blocked = append(blocked, &types.BlockedIssue{
+			Issue:          *m.copyIssue(issue),
+			BlockedByCount: len(blockers),
+			BlockedBy:      blockers,
+		})
+	}
+
+	sort.SliceStable(blocked, func(i, j int) bool {
+		if blocked[i].Priority != blocked[j].Priority {
+			return blocked[i].Priority < blocked[j].Priority
+		}
+		return blocked[i].ID < blocked[j].ID
+	})
+
+	return blocked, nil
+}
+
+// epicProgress counts an epic's parent-child children and how many are closed.
+// Callers must hold at least a read lock.
+func (m *MemoryStorage) epicProgress(epicID string) (total, closed int) {
+	for childID, deps := range m.dependencies {
+		child, ok := m.issues[childID]
+		if !ok {
+			continue
+		}
+		for _, dep := range deps {
+			if dep.Type == types.DepParentChild && dep.DependsOnID == epicID {
+				total++
+				if child.Status == types.StatusClosed {
+					closed++
+				}
+			}
+		}
+	}
+	return total, closed
+}
+
+// GetEpicsEligibleForClosure returns all open epics with their completion status
+func (m *MemoryStorage) GetEpicsEligibleForClosure(ctx context.Context) ([]*types.EpicStatus, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var results []*types.EpicStatus
+	for _, issue := range m.issues {
+		if issue.IssueType != types.TypeEpic || issue.Status == types.StatusClosed {
+			continue
+		}
+		total, closed := m.epicProgress(issue.ID)
+		results = append(results, &types.EpicStatus{
+			Epic:             m.copyIssue(issue),
+			TotalChildren:    total,
+			ClosedChildren:   closed,
+			EligibleForClose: total > 0 && closed == total,
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i].Epic, results[j].Epic
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID < b.ID
+	})
+
+	return results, nil
+}
+
+// GetStatistics returns aggregate statistics
+func (m *MemoryStorage) GetStatistics(ctx context.Context) (*types.Statistics, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	stats := &types.Statistics{
+		TotalIssues: len(m.issues),
+	}
+
+	var leadTimeHours float64
+	var closedWithTime int
+
+	for _, issue := range m.issues {
+		switch issue.Status {
+		case types.StatusOpen:
+			stats.OpenIssues++
+		case types.StatusInProgress:
+			stats.InProgressIssues++
+		case types.StatusClosed:
+			stats.ClosedIssues++
+		}
+
+		blockers := m.openBlockers(issue.ID)
+		if isActive(issue.Status) && len(blockers) > 0 {
+			stats.BlockedIssues++
+		}
+		if issue.Status == types.StatusOpen && len(blockers) == 0 {
+			stats.ReadyIssues++
+		}
+
+		if issue.ClosedAt != nil {
+			leadTimeHours += issue.ClosedAt.Sub(issue.CreatedAt).Hours()
+			closedWithTime++
+		}
+
+		if issue.IssueType == types.TypeEpic && issue.Status != types.StatusClosed {
+			if total, closed := m.epicProgress(issue.ID); total > 0 && closed == total {
+				stats.EpicsEligibleForClosure++
+			}
+		}
+	}
+
+	if closedWithTime > 0 {
+		stats.AverageLeadTime = leadTimeHours / float64(closedWithTime)
+	}
+
+	return stats, nil
+}
+
+// GetGroupedStatistics counts issues by groupBy (one of the types.GroupBy*
+// dimensions) and status
+func (m *MemoryStorage) GetGroupedStatistics(ctx context.Context, groupBy string) ([]*types.StatGroup, error) {
+	if !types.IsValidStatGroup(groupBy) {
+		return nil, fmt.Errorf("can't group statistics by %q", groupBy)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	byKey := make(map[string]*types.StatGroup)
+	var groups []*types.StatGroup
+	count := func(key string, status types.Status) {
+		g := byKey[key]
+		if g == nil {
+			g = &types.StatGroup{Key: key, ByStatus: make(map[types.Status]int)}
+			byKey[key] = g
+			groups = append(groups, g)
+		}
+		g.ByStatus[status]++
+		g.Total++
+	}
+
+	for _, issue := range m.issues {
+		switch groupBy {
+		case types.GroupByStatus:
+			count(string(issue.Status), issue.Status)
+		case types.GroupByAssignee:
+			count(issue.Assignee, issue.Status)
+		case types.GroupByType:
+			count(string(issue.IssueType), issue.Status)
+		case types.GroupByPriority:
+			count(strconv.Itoa(issue.Priority), issue.Status)
+		case types.GroupByLabel:
+			labels := m.labels[issue.ID]
+			if len(labels) == 0 {
+				count("", issue.Status)
+			}
+			for _, label := range labels {
+				count(label, issue.Status)
+			}
+		}
+	}
+
+	types.SortStatGroups(groupBy, groups)
+	return groups, nil
+}
@@ -0,0 +1,217 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func readyIDs(t *testing.T, store *MemoryStorage, filter types.WorkFilter) map[string]bool {
+	t.Helper()
+	ready, err := store.GetReadyWork(context.Background(), filter)
+	if err != nil {
+		t.Fatalf("GetReadyWork failed: %v", err)
+	}
+	ids := make(map[string]bool)
+	for _, issue := range ready {
+		ids[issue.ID] = true
+	}
+	return ids
+}
+
+func TestGetReadyWorkExcludesBlocked(t *testing.T) {
+	store := setupTestMemory(t)
+	defer store.Close()
+	ctx := context.Background()
+
+	blocker := createTestIssue(t, store, "Blocker", 1, types.TypeTask)
+	blocked := createTestIssue(t, store, "Blocked", 1, types.TypeTask)
+	epic := createTestIssue(t, store, "Epic", 1, types.TypeEpic)
+	child := createTestIssue(t, store, "Child", 1, types.TypeTask)
+	related := createTestIssue(t, store, "Related", 1, types.TypeTask)
+
+	addTestDep(t, store, blocked.ID, blocker.ID, types.DepBlocks)
+	addTestDep(t, store, epic.ID, blocker.ID, types.DepBlocks)
+	addTestDep(t, store, child.ID, epic.ID, types.DepParentChild)
+	addTestDep(t, store, related.ID, blocker.ID, types.DepRelated)
+
+	ids := readyIDs(t, store, types.WorkFilter{})
+	if !ids[blocker.ID] || !ids[related.ID] {
+		t.Errorf("expected blocker and related issue to be ready, got %v", ids)
+	}
+	if ids[blocked.ID] || ids[epic.ID] || ids[child.ID] {
+		t.Errorf("blocked issues (including children of blocked epics) should not be ready, got %v", ids)
+	}
+
+	// Closing the blocker frees everything downstream
+	if err := store.CloseIssue(ctx, blocker.ID, "done", "test-user"); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+	ids = readyIDs(t, store, types.WorkFilter{})
+	if !ids[blocked.ID] || !ids[epic.ID] || !ids[child.ID] {
+		t.Errorf("expected issues to be ready once blocker closed, got %v", ids)
+	}
+	if ids[blocker.ID] {
+		t.Error("closed issues should not be ready")
+	}
+}
+
+func TestGetReadyWorkFiltersAndSort(t *testing.T) {
+	store := setupTestMemory(t)
+	defer store.Close()
+	ctx := context.Background()
+
+	low := createTestIssue(t, store, "Low", 3, types.TypeTask)
+	high := createTestIssue(t, store, "High", 0, types.TypeTask)
+	mine := createTestIssue(t, store, "Mine", 2, types.TypeTask)
+	if err := store.UpdateIssue(ctx, mine.ID, map[string]interface{}{"assignee": "alice", "status": "in_progress"}, "test-user"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+
+	// Age one issue past the hybrid window
+	store.mu.Lock()
+	store.issues[low.ID].CreatedAt = time.Now().Add(-72 * time.Hour)
+	store.mu.Unlock()
+
+	ready, err := store.GetReadyWork(ctx, types.WorkFilter{SortPolicy: types.SortPolicyPriority})
+	if err != nil {
+		t.Fatalf("GetReadyWork failed: %v", err)
+	}
+	if len(ready) != 3 || ready[0].ID != high.ID || ready[2].ID != low.ID {
+		t.Errorf("unexpected priority order: %v", ready)
+	}
+
+	ready, err = store.GetReadyWork(ctx, types.WorkFilter{SortPolicy: types.SortPolicyOldest})
+	if err != nil {
+		t.Fatalf("GetReadyWork failed: %v", err)
+	}
+	if ready[0].ID != low.ID {
+		t.Errorf("expected oldest issue first, got %s", ready[0].ID)
+	}
+
+	// Hybrid: recent issues by priority first, then older ones
+	ready, err = store.GetReadyWork(ctx, types.WorkFilter{})
+	if err != nil {
+		t.Fatalf("GetReadyWork failed: %v", err)
+	}
+	if ready[0].ID != high.ID || ready[len(ready)-1].ID != low.ID {
+		t.Errorf("unexpected hybrid order: %v", ready)
+	}
+
+	assignee := "alice"
+	ids := readyIDs(t, store, types.WorkFilter{Assignee: &assignee})
+	if len(ids) != 1 || !ids[mine.ID] {
+		t.Errorf("expected only %s for assignee filter, got %v", mine.ID, ids)
+	}
+
+	ids = readyIDs(t, store, types.WorkFilter{Status: types.StatusOpen, Limit: 1})
+	if len(ids) != 1 || ids[mine.ID] {
+		t.Errorf("expected one open issue, got %v", ids)
+	}
+
+	maxPriority := 2
+	ids = readyIDs(t, store, types.WorkFilter{MaxPriority: &maxPriority})
+	if len(ids) != 2 || ids[low.ID] {
+		t.Errorf("expected P2 and above only, got %v", ids)
+	}
+
+	if err := store.AddLabel(ctx, low.ID, "backend", "test-user"); err != nil {
+		t.Fatal(err)
+	}
+	ids = readyIDs(t, store, types.WorkFilter{Labels: []string{"backend"}})
+	if len(ids) != 1 || !ids[low.ID] {
+		t.Errorf("expected only %s for label filter, got %v", low.ID, ids)
+	}
+}
+
+func TestGetBlockedIssuesAndStatistics(t *testing.T) {
+	store := setupTestMemory(t)
+	defer store.Close()
+	ctx := context.Background()
+
+	a := createTestIssue(t, store, "A", 1, types.TypeTask)
+	b := createTestIssue(t, store, "B", 1, types.TypeTask)
+	c := createTestIssue(t, store, "C", 1, types.TypeTask)
+	addTestDep(t, store, a.ID, b.ID, types.DepBlocks)
+	addTestDep(t, store, a.ID, c.ID, types.DepBlocks)
+
+	blocked, err := store.GetBlockedIssues(ctx)
+	if err != nil {
+		t.Fatalf("GetBlockedIssues failed: %v", err)
+	}
+	if len(blocked) != 1 || blocked[0].ID != a.ID || blocked[0].BlockedByCount != 2 {
+		t.Fatalf("unexpected blocked issues: %+v", blocked)
+	}
+
+	stats, err := store.GetStatistics(ctx)
+	if err != nil {
+		t.Fatalf("GetStatistics failed: %v", err)
+	}
+	if stats.BlockedIssues != 1 || stats.ReadyIssues != 2 {
+		t.Errorf("expected 1 blocked and 2 ready, got %d and %d", stats.BlockedIssues, stats.ReadyIssues)
+	}
+}
+
+func TestGetEpicsEligibleForClosure(t *testing.T) {
+	store := setupTestMemory(t)
+	defer store.Close()
+	ctx := context.Background()
+
+	epic := createTestIssue(t, store, "Epic", 1, types.TypeEpic)
+	empty := createTestIssue(t, store, "Empty epic", 2, types.TypeEpic)
+	c1 := createTestIssue(t, store, "Child 1", 1, types.TypeTask)
+	c2 := createTestIssue(t, store, "Child 2", 1, types.TypeTask)
+	addTestDep(t, store, c1.ID, epic.ID, types.DepParentChild)
+	addTestDep(t, store, c2.ID, epic.ID, types.DepParentChild)
+
+	for _, id := range []string{c1.ID, c2.ID} {
+		if err := store.CloseIssue(ctx, id, "done", "test-user"); err != nil {
+			t.Fatalf("CloseIssue failed: %v", err)
+		}
+	}
+
+	epics, err := store.GetEpicsEligibleForClosure(ctx)
+	if err != nil {
+		t.Fatalf("GetEpicsEligibleForClosure failed: %v", err)
+	}
+	if len(epics) != 2 {
+		t.Fatalf("expected 2 epics, got %d", len(epics))
+	}
+	if epics[0].Epic.ID != epic.ID || !epics[0].EligibleForClose || epics[0].ClosedChildren != 2 {
+		t.Errorf("unexpected status for %s: %+v", epic.ID, epics[0])
+	}
+	if epics[1].Epic.ID != empty.ID || epics[1].EligibleForClose {
+		t.Errorf("epics without children should not be eligible: %+v", epics[1])
+	}
+
+	stats, err := store.GetStatistics(ctx)
+	if err != nil {
+		t.Fatalf("GetStatistics failed: %v", err)
+	}
+	if stats.EpicsEligibleForClosure != 1 {
+		t.Errorf("expected 1 eligible epic, got %d", stats.EpicsEligibleForClosure)
+	}
+}
+
+func TestGetGroupedStatistics(t *testing.T) {
+	store := setupTestMemory(t)
+	defer store.Close()
+	ctx := context.Background()
+
+	createTestIssue(t, store, "A", 1, types.TypeBug)
+	createTestIssue(t, store, "B", 2, types.TypeTask)
+	createTestIssue(t, store, "C", 2, types.TypeTask)
+
+	groups, err := store.GetGroupedStatistics(ctx, types.GroupByType)
+	if err != nil {
+		t.Fatalf("GetGroupedStatistics failed: %v", err)
+	}
+	if len(groups) != 2 || groups[0].Key != string(types.TypeTask) || groups[0].Total != 2 || groups[1].Key != string(types.TypeBug) {
+		t.Errorf("unexpected groups by type: %+v", groups)
+	}
+	if _, err := store.GetGroupedStatistics(ctx, "milestone"); err == nil {
+		t.Error("expected an unknown dimension refused")
+	}
+}
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Factory opens a storage backend from a DSN whose scheme names it, e.g.
+// "postgres://user@host/beads" for a backend registered as "postgres"
+type Factory func(dsn string) (Storage, error)
+
+var (
+	driversMu sync.RWMutex
+	drivers   = make(map[string]Factory)
+)
+
+// dsnScheme matches the scheme of a DSN. It takes at least two characters,
+// so a Windows drive letter reads as a path.
+var dsnScheme = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9+.-]+):`)
+
+// Register makes a storage backend available under name, for DSNs with that
+// scheme. Backends call it from an init function, so importing one is
+// enough to link it in. It panics if name is taken or factory is nil.
+func Register(name string, factory Factory) {
+	driversMu.Lock()
+	defer driversMu.Unlock()
+	if factory == nil {
+		panic("storage: Register factory is nil")
+	}
+	name = strings.ToLower(name)
+	if _, dup := drivers[name]; dup {
+		panic("storage: Register called twice for driver " + name)
+	}
+	drivers[name] = factory
+}
+
+// Drivers returns the names of the registered backends, sorted
+func Drivers() []string {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+	names := make([]string, 0, len(drivers))
+	for name := range drivers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DSNDriver returns the backend a DSN names: its scheme, lowercased, or
+// "sqlite" for a plain path
+func DSNDriver(dsn string) string {
+	if m := dsnScheme.FindStringSubmatch(dsn); m != nil {
+		return strings.ToLower(m[1])
+	}
+	return "sqlite"
+}
+
+// Open opens the storage a DSN names with the backend registered for its
+// scheme. A plain path is a SQLite database, as is a sqlite: URL.
+func Open(dsn string) (Storage, error) {
+	driver := DSNDriver(dsn)
+	driversMu.RLock()
+	factory := drivers[driver]
+	driversMu.RUnlock()
+	if factory == nil {
+		return nil, fmt.Errorf("unknown storage driver %q (available: %s)", driver, strings.Join(Drivers(), ", "))
+	}
+	if !dsnScheme.MatchString(dsn) {
+		dsn = driver + ":" + dsn
+	}
+	return factory(dsn)
+}
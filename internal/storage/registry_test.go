@@ -0,0 +1,89 @@
+package storage_test
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/storage"
+	"github.com/imalsogreg/beads/internal/storage/memory"
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+)
+
+func TestDSNDriver(t *testing.T) {
+	tests := []struct {
+		dsn, want string
+	}{
+		{".beads/beads.db", "sqlite"},
+		{"/abs/beads.db", "sqlite"},
+		{`C:\beads\beads.db`, "sqlite"},
+		{":memory:", "sqlite"},
+		{"sqlite:./beads.db", "sqlite"},
+		{"SQLite:///abs/beads.db", "sqlite"},
+		{"postgres://user@host/beads", "postgres"},
+	}
+	for _, tt := range tests {
+		if got := storage.DSNDriver(tt.dsn); got != tt.want {
+			t.Errorf("DSNDriver(%q) = %q, want %q", tt.dsn, got, tt.want)
+		}
+	}
+
+	for dsn, want := range map[string]string{
+		"./beads.db":             "./beads.db",
+		"sqlite:./beads.db":      "./beads.db",
+		"sqlite:///abs/beads.db": "/abs/beads.db",
+		"sqlite:beads.db":        "beads.db",
+	} {
+		if got := sqlite.DSNPath(dsn); got != want {
+			t.Errorf("DSNPath(%q) = %q, want %q", dsn, got, want)
+		}
+	}
+}
+
+func TestRegister(t *testing.T) {
+	var opened string
+	storage.Register("fake", func(dsn string) (storage.Storage, error) {
+		opened = dsn
+		return memory.New(""), nil
+	})
+	if !slices.Equal(storage.Drivers(), []string{"fake", "sqlite"}) {
+		t.Errorf("Expected the fake and SQLite drivers, got %v", storage.Drivers())
+	}
+
+	s, err := storage.Open("fake://somewhere/beads")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer s.Close()
+	if opened != "fake://somewhere/beads" {
+		t.Errorf("Expected the factory given the whole DSN, got %q", opened)
+	}
+
+	// Plain paths and sqlite: URLs open SQLite
+	dir := t.TempDir()
+	for _, dsn := range []string{filepath.Join(dir, "a.db"), "sqlite:" + filepath.Join(dir, "b.db")} {
+		s, err := storage.Open(dsn)
+		if err != nil {
+			t.Fatalf("Open(%q) failed: %v", dsn, err)
+		}
+		if _, ok := s.(*sqlite.SQLiteStorage); !ok {
+			t.Errorf("Open(%q) = %T, want SQLite", dsn, s)
+		}
+		if err := s.SetConfig(context.Background(), "issue_prefix", "bd"); err != nil {
+			t.Error(err)
+		}
+		s.Close()
+	}
+
+	if _, err := storage.Open("nope://x"); err == nil {
+		t.Error("Expected an error for an unregistered driver")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected registering a driver twice to panic")
+		}
+	}()
+	storage.Register("fake", func(string) (storage.Storage, error) { return nil, nil })
+}
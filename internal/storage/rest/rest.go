@@ -0,0 +1,371 @@
+// Package rest implements storage.Storage over the REST API of a bd serve
+// instance, so the CLI can work against a server instead of a local
+// database.
+//
+// The server keeps its own database and JSONL file, so the local
+// bookkeeping in the interface (dirty issues, export hashes, metadata) is a
+// no-op here. Operations the API has no endpoint for fail with an error
+// matching ErrUnsupported.
+package rest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage"
+	"github.com/imalsogreg/beads/internal/types"
+	"github.com/imalsogreg/beads/pkg/client"
+)
+
+// ErrUnsupported is returned for operations a remote server can't do
+var ErrUnsupported = errors.New("not supported against a remote server")
+
+func unsupported(what string) error {
+	return fmt.Errorf("%s is %w", what, ErrUnsupported)
+}
+
+// Storage is a storage.Storage backed by a bd serve API
+type Storage struct {
+	client *client.Client
+}
+
+var _ storage.Storage = (*Storage)(nil)
+
+// New returns storage that reads and writes through c. Writes are made in
+// the name of the actor each method is given.
+func New(c *client.Client) *Storage {
+	return &Storage{client: c}
+}
+
+// Client returns the API client
+func (s *Storage) Client() *client.Client {
+	return s.client
+}
+
+// CreateIssue creates the issue on the server and fills in what the server
+// set: its ID, timestamps, and any fields triage rules changed. Fields the
+// create endpoint doesn't take are set with a follow-up update.
+func (s *Storage) CreateIssue(ctx context.Context, issue *types.Issue, actor string) error {
+	c := s.client.As(actor)
+	args := &client.CreateArgs{
+		ID:                 issue.ID,
+		Title:              issue.Title,
+		Description:        issue.Description,
+		IssueType:          string(issue.IssueType),
+		Priority:           issue.Priority,
+		Design:             issue.Design,
+		AcceptanceCriteria: issue.AcceptanceCriteria,
+		Assignee:           issue.Assignee,
+	}
+	if issue.DueDate != nil {
+		args.DueDate = issue.DueDate.Format(time.RFC3339Nano)
+	}
+	if issue.StartDate != nil {
+		args.StartDate = issue.StartDate.Format(time.RFC3339Nano)
+	}
+
+	var created *types.Issue
+	var err error
+	if issue.ParentID != "" {
+		created, err = c.CreateSubtask(ctx, issue.ParentID, args)
+	} else {
+		created, err = c.CreateIssue(ctx, args)
+	}
+	if err != nil {
+		return err
+	}
+
+	updates := map[string]interface{}{}
+	if issue.Status != "" && issue.Status != types.StatusOpen {
+		updates["status"] = issue.Status
+	}
+	if issue.Notes != "" {
+		updates["notes"] = issue.Notes
+	}
+	if issue.EstimatedMinutes != nil {
+		updates["estimated_minutes"] = *issue.EstimatedMinutes
+	}
+	if issue.ExternalRef != nil {
+		updates["external_ref"] = *issue.ExternalRef
+	}
+	if issue.Milestone != "" {
+		updates["milestone"] = issue.Milestone
+	}
+	if len(updates) > 0 {
+		id := created.ID
+		if created, err = c.UpdateIssue(ctx, id, updates); err != nil {
+			return fmt.Errorf("created %s, but %w", id, err)
+		}
+	}
+
+	// Labels, dependencies, and comments are the caller's to add
+	labels, deps, comments := issue.Labels, issue.Dependencies, issue.Comments
+	*issue = *created
+	issue.Labels, issue.Dependencies, issue.Comments = labels, deps, comments
+	return nil
+}
+
+// CreateIssues creates the issues one at a time. Unlike with a local
+// database, a failure leaves the ones before it created.
+func (s *Storage) CreateIssues(ctx context.Context, issues []*types.Issue, actor string) error {
+	for _, issue := range issues {
+		if err := s.CreateIssue(ctx, issue, actor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetIssue returns nil for an issue that doesn't exist
+func (s *Storage) GetIssue(ctx context.Context, id string) (*types.Issue, error) {
+	issue, err := s.client.GetIssue(ctx, id)
+	if errors.Is(err, types.ErrNotFound) {
+		return nil, nil
+	}
+	return issue, err
+}
+
+func (s *Storage) UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error {
+	_, err := s.client.As(actor).UpdateIssue(ctx, id, updates)
+	return err
+}
+
+func (s *Storage) UpdateIssues(ctx context.Context, filter types.IssueFilter, updates map[string]interface{}, actor string) ([]string, error) {
+	return s.client.As(actor).UpdateIssues(ctx, filter, updates)
+}
+
+func (s *Storage) CloseIssue(ctx context.Context, id string, reason string, actor string) error {
+	return s.client.As(actor).CloseIssue(ctx, id, reason)
+}
+
+// SearchIssues sends query as a query expression term, which matches the
+// title, description, and ID as a local database does
+func (s *Storage) SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error) {
+	if query != "" {
+		term := &types.QueryExpr{Op: types.QueryTerm, Field: "text", Compare: "=", Values: []string{query}}
+		if filter.Query != nil {
+			term = &types.QueryExpr{Op: types.QueryAnd, Args: []*types.QueryExpr{filter.Query, term}}
+		}
+		filter.Query = term
+	}
+	return s.client.ListIssues(ctx, filter)
+}
+
+// ForEachIssue streams the server's listing rather than loading it whole
+func (s *Storage) ForEachIssue(ctx context.Context, filter types.IssueFilter, fn func(*types.Issue) error) error {
+	return s.client.ForEachIssue(ctx, filter, fn)
+}
+
+func (s *Storage) AddDependency(ctx context.Context, dep *types.Dependency, actor string) error {
+	return s.client.As(actor).AddDependency(ctx, dep)
+}
+
+func (s *Storage) RemoveDependency(ctx context.Context, issueID, dependsOnID string, actor string) error {
+	return s.client.As(actor).RemoveDependency(ctx, issueID, dependsOnID)
+}
+
+func (s *Storage) GetDependencies(ctx context.Context, issueID string) ([]*types.Issue, error) {
+	deps, err := s.client.GetDependencyRecords(ctx, issueID)
+	if err != nil || len(deps) == 0 {
+		return nil, err
+	}
+	filter := types.IssueFilter{}
+	for _, dep := range deps {
+		filter.IDs = append(filter.IDs, dep.DependsOnID)
+	}
+	return s.client.ListIssues(ctx, filter)
+}
+
+func (s *Storage) GetDependents(ctx context.Context, issueID string) ([]*types.Issue, error) {
+	return s.client.GetDependents(ctx, issueID)
+}
+
+func (s *Storage) GetChildren(ctx context.Context, parentID string) ([]*types.Issue, error) {
+	return s.client.GetChildren(ctx, parentID)
+}
+
+func (s *Storage) GetDependencyRecords(ctx context.Context, issueID string) ([]*types.Dependency, error) {
+	return s.client.GetDependencyRecords(ctx, issueID)
+}
+
+func (s *Storage) GetAllDependencyRecords(ctx context.Context) (map[string][]*types.Dependency, error) {
+	deps, err := s.client.ListDependencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byIssue := make(map[string][]*types.Dependency)
+	for _, dep := range deps {
+		byIssue[dep.IssueID] = append(byIssue[dep.IssueID], dep)
+	}
+	return byIssue, nil
+}
+
+// GetDependencyTree always shows each issue once: the API has no
+// showAllPaths
+func (s *Storage) GetDependencyTree(ctx context.Context, issueID string, maxDepth int, showAllPaths bool, reverse bool) ([]*types.TreeNode, error) {
+	return s.client.GetDependencyTree(ctx, issueID, maxDepth, reverse)
+}
+
+func (s *Storage) DetectCycles(ctx context.Context) ([][]*types.Issue, error) {
+	return s.client.DetectCycles(ctx)
+}
+
+func (s *Storage) AddLabel(ctx context.Context, issueID, label, actor string) error {
+	return s.client.As(actor).AddLabel(ctx, issueID, label)
+}
+
+func (s *Storage) RemoveLabel(ctx context.Context, issueID, label, actor string) error {
+	return s.client.As(actor).RemoveLabel(ctx, issueID, label)
+}
+
+func (s *Storage) GetLabels(ctx context.Context, issueID string) ([]string, error) {
+	return s.client.GetLabels(ctx, issueID)
+}
+
+func (s *Storage) GetIssuesByLabel(ctx context.Context, label string) ([]*types.Issue, error) {
+	return s.client.ListIssues(ctx, types.IssueFilter{Labels: []string{label}})
+}
+
+func (s *Storage) GetReadyWork(ctx context.Context, filter types.WorkFilter) ([]*types.Issue, error) {
+	return s.client.GetReadyWork(ctx, filter)
+}
+
+func (s *Storage) GetBlockedIssues(ctx context.Context) ([]*types.BlockedIssue, error) {
+	return s.client.GetBlockedIssues(ctx)
+}
+
+func (s *Storage) GetEpicsEligibleForClosure(ctx context.Context) ([]*types.EpicStatus, error) {
+	return s.client.ListEpics(ctx)
+}
+
+// AddComment posts comment as an issue comment: the API doesn't write
+// events directly
+func (s *Storage) AddComment(ctx context.Context, issueID, actor, comment string) error {
+	_, err := s.client.As(actor).AddComment(ctx, issueID, comment)
+	return err
+}
+
+func (s *Storage) GetEvents(ctx context.Context, issueID string, limit int) ([]*types.Event, error) {
+	return nil, unsupported("reading events")
+}
+
+func (s *Storage) AddIssueComment(ctx context.Context, issueID, author, text string) (*types.Comment, error) {
+	return s.client.As(author).AddComment(ctx, issueID, text)
+}
+
+func (s *Storage) GetIssueComments(ctx context.Context, issueID string) ([]*types.Comment, error) {
+	return s.client.ListComments(ctx, issueID)
+}
+
+func (s *Storage) ReplyToComment(ctx context.Context, issueID string, replyTo int64, author, text string) (*types.Comment, error) {
+	return s.client.As(author).ReplyToComment(ctx, issueID, replyTo, text)
+}
+
+func (s *Storage) UpdateIssueComment(ctx context.Context, issueID string, commentID int64, editor, text string) (*types.Comment, error) {
+	return s.client.As(editor).EditComment(ctx, issueID, commentID, text)
+}
+
+func (s *Storage) DeleteIssueComment(ctx context.Context, issueID string, commentID int64) error {
+	return s.client.DeleteComment(ctx, issueID, commentID)
+}
+
+func (s *Storage) GetCommentEdits(ctx context.Context, issueID string, commentID int64) ([]*types.CommentEdit, error) {
+	return s.client.GetCommentEdits(ctx, issueID, commentID)
+}
+
+func (s *Storage) AddCommentReaction(ctx context.Context, issueID string, commentID int64, actor, reaction string) (*types.Comment, error) {
+	return s.client.As(actor).AddReaction(ctx, issueID, commentID, reaction)
+}
+
+func (s *Storage) RemoveCommentReaction(ctx context.Context, issueID string, commentID int64, actor, reaction string) (*types.Comment, error) {
+	return s.client.As(actor).RemoveReaction(ctx, issueID, commentID, reaction)
+}
+
+func (s *Storage) GetMentions(ctx context.Context, user string) ([]*types.Mention, error) {
+	return s.client.GetMentions(ctx, user)
+}
+
+func (s *Storage) GetStatistics(ctx context.Context) (*types.Statistics, error) {
+	return s.client.GetStatistics(ctx)
+}
+
+func (s *Storage) GetGroupedStatistics(ctx context.Context, groupBy string) ([]*types.StatGroup, error) {
+	return s.client.GetGroupedStatistics(ctx, groupBy)
+}
+
+// The server exports its own JSONL, so there is no dirty tracking, export
+// hashing, or import metadata to keep locally
+
+func (s *Storage) GetDirtyIssues(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
+func (s *Storage) GetDirtyIssueHash(ctx context.Context, issueID string) (string, error) {
+	return "", nil
+}
+
+func (s *Storage) ClearDirtyIssues(ctx context.Context) error {
+	return nil
+}
+
+func (s *Storage) ClearDirtyIssuesByID(ctx context.Context, issueIDs []string) error {
+	return nil
+}
+
+func (s *Storage) GetExportHash(ctx context.Context, issueID string) (string, error) {
+	return "", nil
+}
+
+func (s *Storage) SetExportHash(ctx context.Context, issueID, contentHash string) error {
+	return nil
+}
+
+func (s *Storage) SetMetadata(ctx context.Context, key, value string) error {
+	return nil
+}
+
+func (s *Storage) GetMetadata(ctx context.Context, key string) (string, error) {
+	return "", nil
+}
+
+func (s *Storage) SetConfig(ctx context.Context, key, value string) error {
+	return s.client.SetConfig(ctx, key, value)
+}
+
+func (s *Storage) GetConfig(ctx context.Context, key string) (string, error) {
+	return s.client.GetConfig(ctx, key)
+}
+
+func (s *Storage) GetAllConfig(ctx context.Context) (map[string]string, error) {
+	return s.client.GetAllConfig(ctx)
+}
+
+func (s *Storage) DeleteConfig(ctx context.Context, key string) error {
+	return s.client.DeleteConfig(ctx, key)
+}
+
+func (s *Storage) UpdateIssueID(ctx context.Context, oldID, newID string, issue *types.Issue, actor string) error {
+	return unsupported("changing issue IDs")
+}
+
+func (s *Storage) RenameDependencyPrefix(ctx context.Context, oldPrefix, newPrefix string) error {
+	return unsupported("renaming the issue prefix")
+}
+
+func (s *Storage) RenameCounterPrefix(ctx context.Context, oldPrefix, newPrefix string) error {
+	return unsupported("renaming the issue prefix")
+}
+
+func (s *Storage) Close() error { return nil }
+
+// Path is empty: there is no local database file
+func (s *Storage) Path() string { return "" }
+
+func (s *Storage) UnderlyingDB() *sql.DB { return nil }
+
+func (s *Storage) UnderlyingConn(ctx context.Context) (*sql.Conn, error) {
+	return nil, unsupported("direct database access")
+}
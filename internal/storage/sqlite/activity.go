@@ -0,0 +1,116 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Activity kinds reported by GetActivity
+const (
+	ActivityCreated   = "created"
+	ActivityClosed    = "closed"
+	ActivityReopened  = "reopened"
+	ActivityCommented = "commented"
+)
+
+// ActivityEntry is one item of project activity: an issue being created,
+// closed, or reopened, or a comment being added
+type ActivityEntry struct {
+	ID         string    `json:"id"` // stable across calls, e.g. "event-12" or "comment-4"
+	Kind       string    `json:"kind"`
+	IssueID    string    `json:"issue_id"`
+	IssueTitle string    `json:"issue_title"`
+	Actor      string    `json:"actor"`
+	Text       string    `json:"text,omitempty"` // description, close reason, or comment text
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ActivityFilter narrows GetActivity results. Label matches issues carrying the
+// label; Epic matches the epic itself and all of its descendants.
+type ActivityFilter struct {
+	Label string
+	Epic  string
+	Limit int
+}
+
+// GetActivity returns recent creates, closes, reopens, and comments, newest first
+func (s *SQLiteStorage) GetActivity(ctx context.Context, filter ActivityFilter) ([]*ActivityEntry, error) {
+	var scope []string
+	var args []interface{}
+	if filter.Epic != "" {
+		args = append(args, filter.Epic)
+	}
+	if filter.Label != "" {
+		clause, labelArgs := labelFilterClause("a.issue_", filter.Label)
+		scope = append(scope, clause)
+		args = append(args, labelArgs...)
+	}
+	if filter.Epic != "" {
+		scope = append(scope, "a.issue_id IN (SELECT id FROM epic_tree)")
+	}
+	where := ""
+	if len(scope) > 0 {
+		where = "WHERE " + strings.Join(scope, " AND ")
+	}
+
+	limitSQL := ""
+	if filter.Limit > 0 {
+		limitSQL = limitClause
+		args = append(args, filter.Limit)
+	}
+
+	cte := ""
+	if filter.Epic != "" {
+		cte = `WITH RECURSIVE epic_tree(id) AS (
+			SELECT ?
+			UNION
+			SELECT d.issue_id FROM dependencies d JOIN epic_tree t ON d.depends_on_id = t.id
+			WHERE d.type = 'parent-child'
+		)`
+	}
+
+	// #nosec G201 - safe SQL with controlled formatting
+	query := fmt.Sprintf(`
+		%s
+		SELECT a.entry_id, a.kind, a.issue_id, i.title, i.description, a.actor, a.text, a.created_at
+		FROM (
+			SELECT 'event-' || id AS entry_id, event_type AS kind, issue_id, actor, comment AS text, created_at
+			FROM events WHERE event_type IN ('created', 'closed', 'reopened', 'commented')
+			-- Comments from the comments table come below; their events name them
+			AND NOT (event_type = 'commented' AND new_value IS NOT NULL)
+			UNION ALL
+			SELECT 'comment-' || id, 'commented', issue_id, author, text, created_at
+			FROM comments
+		) a
+		JOIN issues i ON i.id = a.issue_id
+		%s
+		ORDER BY datetime(a.created_at) DESC, a.entry_id DESC
+		%s
+	`, cte, where, limitSQL)
+
+	rows, err := s.reads.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query activity: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var entries []*ActivityEntry
+	for rows.Next() {
+		var e ActivityEntry
+		var description string
+		var text sql.NullString
+		if err := rows.Scan(&e.ID, &e.Kind, &e.IssueID, &e.IssueTitle, &description, &e.Actor, &text, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan activity: %w", err)
+		}
+		if e.Kind == ActivityCreated {
+			e.Text = s.decryptField(description)
+		} else if text.Valid {
+			e.Text = s.decryptField(text.String)
+		}
+		entries = append(entries, &e)
+	}
+	return entries, rows.Err()
+}
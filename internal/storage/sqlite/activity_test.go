@@ -0,0 +1,88 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestGetActivity(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	epic := &types.Issue{Title: "Epic", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeEpic}
+	child := &types.Issue{Title: "Child", Description: "child details", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	other := &types.Issue{Title: "Other", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeBug}
+	for _, issue := range []*types.Issue{epic, child, other} {
+		if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+	if err := store.AddDependency(ctx, &types.Dependency{IssueID: child.ID, DependsOnID: epic.ID, Type: types.DepParentChild}, "alice"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+	if _, err := store.AddIssueComment(ctx, child.ID, "bob", "looks good"); err != nil {
+		t.Fatalf("AddIssueComment failed: %v", err)
+	}
+	if err := store.CloseIssue(ctx, child.ID, "done", "bob"); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+	if err := store.AddLabel(ctx, other.ID, "frontend", "alice"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+
+	all, err := store.GetActivity(ctx, ActivityFilter{})
+	if err != nil {
+		t.Fatalf("GetActivity failed: %v", err)
+	}
+	if len(all) != 5 {
+		t.Fatalf("expected 5 entries (3 creates, 1 comment, 1 close), got %d", len(all))
+	}
+	kinds := make(map[string]int)
+	for _, e := range all {
+		kinds[e.Kind]++
+		if e.Kind == ActivityCreated && e.IssueID == child.ID && e.Text != "child details" {
+			t.Errorf("created entry should carry the description, got %q", e.Text)
+		}
+		if e.Kind == ActivityCommented && (e.Actor != "bob" || e.Text != "looks good") {
+			t.Errorf("unexpected comment entry %+v", e)
+		}
+		if e.Kind == ActivityClosed && e.Text != "done" {
+			t.Errorf("closed entry should carry the reason, got %q", e.Text)
+		}
+	}
+	if kinds[ActivityCreated] != 3 || kinds[ActivityCommented] != 1 || kinds[ActivityClosed] != 1 {
+		t.Errorf("unexpected kinds %v", kinds)
+	}
+
+	epicActivity, err := store.GetActivity(ctx, ActivityFilter{Epic: epic.ID})
+	if err != nil {
+		t.Fatalf("GetActivity by epic failed: %v", err)
+	}
+	for _, e := range epicActivity {
+		if e.IssueID != epic.ID && e.IssueID != child.ID {
+			t.Errorf("epic filter returned unrelated issue %s", e.IssueID)
+		}
+	}
+	if len(epicActivity) != 4 {
+		t.Errorf("expected 4 entries for epic, got %d", len(epicActivity))
+	}
+
+	labeled, err := store.GetActivity(ctx, ActivityFilter{Label: "frontend", Limit: 10})
+	if err != nil {
+		t.Fatalf("GetActivity by label failed: %v", err)
+	}
+	if len(labeled) != 1 || labeled[0].IssueID != other.ID {
+		t.Errorf("expected only the labeled issue's creation, got %+v", labeled)
+	}
+
+	limited, err := store.GetActivity(ctx, ActivityFilter{Limit: 2})
+	if err != nil {
+		t.Fatalf("GetActivity with limit failed: %v", err)
+	}
+	if len(limited) != 2 {
+		t.Errorf("expected 2 entries, got %d", len(limited))
+	}
+}
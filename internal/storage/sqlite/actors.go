@@ -0,0 +1,443 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Actor types
+const (
+	ActorHuman = "human"
+	ActorAgent = "agent"
+	ActorBot   = "bot"
+)
+
+// Actor is a registered identity. Actors are otherwise free-form strings;
+// registering one makes its aliases, email, and any casing of its name
+// resolve to Name wherever an actor or assignee is written.
+type Actor struct {
+	Name      string    `json:"name"`
+	Email     string    `json:"email,omitempty"`
+	Type      string    `json:"type"`
+	Aliases   []string  `json:"aliases,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ActorMerge reports what consolidating one actor into another rewrote
+type ActorMerge struct {
+	From              string   `json:"from"`
+	Into              string   `json:"into"`
+	DryRun            bool     `json:"dry_run,omitempty"`
+	Records           int      `json:"records"` // assignments, comments, events, and the like now naming Into
+	IssuesAffected    []string `json:"issues_affected"`
+	AuditChainRebuilt bool     `json:"audit_chain_rebuilt"`
+}
+
+var (
+	// ErrActorNotFound is returned for a name that resolves to no registered actor
+	ErrActorNotFound = errors.New("actor not registered")
+
+	// ErrActorTaken is returned when a name, alias, or email already resolves
+	// to another actor
+	ErrActorTaken = errors.New("already names another actor")
+)
+
+// ValidActorType reports whether t is human, agent, or bot
+func ValidActorType(t string) bool {
+	return t == ActorHuman || t == ActorAgent || t == ActorBot
+}
+
+// ResolveActor returns the registered name that name is, an alias of, or
+// the email of, ignoring case. Unregistered names come back trimmed but
+// otherwise as given.
+func (s *SQLiteStorage) ResolveActor(ctx context.Context, name string) (string, error) {
+	return resolveActor(ctx, s.reads, name)
+}
+
+func resolveActor(ctx context.Context, q dbQueryer, name string) (string, error) {
+	name = strings.TrimSpace(name)
+	resolved, found, err := lookupActor(ctx, q, name)
+	if err != nil || !found {
+		return name, err
+	}
+	return resolved, nil
+}
+
+// lookupActor returns the registered actor name resolves to, if any
+func lookupActor(ctx context.Context, q dbQueryer, name string) (string, bool, error) {
+	if name == "" {
+		return "", false, nil
+	}
+	var resolved string
+	err := q.QueryRowContext(ctx, `
+		SELECT name FROM actors WHERE name = ?1 OR (email != '' AND email = ?1 COLLATE NOCASE)
+		UNION ALL
+		SELECT actor FROM actor_aliases WHERE alias = ?1
+		LIMIT 1
+	`, name).Scan(&resolved)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to resolve actor %q: %w", name, err)
+	}
+	return resolved, true, nil
+}
+
+// normalizeAssignee resolves a string assignee in updates to its actor
+func (s *SQLiteStorage) normalizeAssignee(ctx context.Context, updates map[string]interface{}) error {
+	assignee, ok := updates["assignee"].(string)
+	if !ok || assignee == "" {
+		return nil
+	}
+	resolved, err := s.ResolveActor(ctx, assignee)
+	if err != nil {
+		return err
+	}
+	updates["assignee"] = resolved
+	return nil
+}
+
+// CreateActor registers an actor with its aliases. The name, each alias,
+// and the email must not already resolve to another actor.
+func (s *SQLiteStorage) CreateActor(ctx context.Context, actor *Actor) error {
+	actor.Name = strings.TrimSpace(actor.Name)
+	actor.Email = strings.TrimSpace(actor.Email)
+	if actor.Name == "" {
+		return fmt.Errorf("actor name is required")
+	}
+	if actor.Type == "" {
+		actor.Type = ActorHuman
+	}
+	if !ValidActorType(actor.Type) {
+		return fmt.Errorf("invalid actor type %q (must be %s, %s, or %s)", actor.Type, ActorHuman, ActorAgent, ActorBot)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, name := range []string{actor.Name, actor.Email} {
+		if err := checkActorNameFree(ctx, tx, name, ""); err != nil {
+			return err
+		}
+	}
+	actor.CreatedAt = time.Now()
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO actors (name, email, type, created_at) VALUES (?, ?, ?, ?)
+	`, actor.Name, actor.Email, actor.Type, actor.CreatedAt); err != nil {
+		return fmt.Errorf("failed to register actor %s: %w", actor.Name, err)
+	}
+	if err := addActorAliases(ctx, tx, actor.Name, actor.Aliases); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit actor: %w", err)
+	}
+	actor.Aliases, err = s.getActorAliases(ctx, actor.Name)
+	return err
+}
+
+// GetActor returns the actor name resolves to, or ErrActorNotFound
+func (s *SQLiteStorage) GetActor(ctx context.Context, name string) (*Actor, error) {
+	resolved, err := s.ResolveActor(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	actors, err := s.queryActors(ctx, `WHERE name = ?`, resolved)
+	if err != nil {
+		return nil, err
+	}
+	if len(actors) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrActorNotFound, name)
+	}
+	return actors[0], nil
+}
+
+// ListActors returns the registered actors by name
+func (s *SQLiteStorage) ListActors(ctx context.Context) ([]*Actor, error) {
+	return s.queryActors(ctx, ``)
+}
+
+// UpdateActor sets a registered actor's email and type, and adds aliases.
+// Empty fields are left alone.
+func (s *SQLiteStorage) UpdateActor(ctx context.Context, name string, changes *Actor) (*Actor, error) {
+	if changes.Type != "" && !ValidActorType(changes.Type) {
+		return nil, fmt.Errorf("invalid actor type %q (must be %s, %s, or %s)", changes.Type, ActorHuman, ActorAgent, ActorBot)
+	}
+	actor, err := s.GetActor(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if email := strings.TrimSpace(changes.Email); email != "" {
+		if err := checkActorNameFree(ctx, tx, email, actor.Name); err != nil {
+			return nil, err
+		}
+		actor.Email = email
+	}
+	if changes.Type != "" {
+		actor.Type = changes.Type
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE actors SET email = ?, type = ? WHERE name = ?`, actor.Email, actor.Type, actor.Name); err != nil {
+		return nil, fmt.Errorf("failed to update actor %s: %w", actor.Name, err)
+	}
+	if err := addActorAliases(ctx, tx, actor.Name, changes.Aliases); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit actor: %w", err)
+	}
+	return s.GetActor(ctx, actor.Name)
+}
+
+// RemoveActorAlias stops alias resolving to its actor
+func (s *SQLiteStorage) RemoveActorAlias(ctx context.Context, alias string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM actor_aliases WHERE alias = ?`, strings.TrimSpace(alias))
+	if err != nil {
+		return fmt.Errorf("failed to remove alias %s: %w", alias, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%w: no alias %s", ErrActorNotFound, alias)
+	}
+	return nil
+}
+
+// DeleteActor unregisters an actor and its aliases. Records naming the
+// actor are kept as they are.
+func (s *SQLiteStorage) DeleteActor(ctx context.Context, name string) error {
+	actor, err := s.GetActor(ctx, name)
+	if err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM actors WHERE name = ?`, actor.Name); err != nil {
+		return fmt.Errorf("failed to delete actor %s: %w", actor.Name, err)
+	}
+	return nil
+}
+
+// MergeActors consolidates from into the actor into resolves to: every
+// assignment, claim, comment, reaction, mention, watch, event, revision,
+// work log, commit link, trash and archive record, digest subscription,
+// snapshot, and label, milestone, webhook, API key, and triage rule naming
+// exactly from is rewritten to name into, and from becomes one of into's
+// aliases so later writes resolve too. If from is itself a registered
+// actor, its aliases move to into and its registration goes. into is
+// registered as a human if it isn't yet. As with a purge, the audit chain
+// is rebuilt over the rewritten history.
+func (s *SQLiteStorage) MergeActors(ctx context.Context, from, into string, dryRun bool) (*ActorMerge, error) {
+	from = strings.TrimSpace(from)
+	if from == "" {
+		return nil, fmt.Errorf("actor to merge is required")
+	}
+	into, err := s.ResolveActor(ctx, into)
+	if err != nil {
+		return nil, err
+	}
+	if into == "" {
+		return nil, fmt.Errorf("actor to merge into is required")
+	}
+	if into == from {
+		return nil, fmt.Errorf("cannot merge %s into itself", from)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	report := &PurgeReport{}
+	if err := rewriteActor(ctx, tx, from, PurgeActorOptions{Mode: PurgeModeAnonymize, Replacement: into}, report); err != nil {
+		return nil, err
+	}
+	res, err := tx.ExecContext(ctx, `UPDATE OR IGNORE issue_watchers SET user = ? WHERE user = ?`, into, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge watches: %w", err)
+	}
+	watches, _ := res.RowsAffected()
+	if _, err := tx.ExecContext(ctx, `DELETE FROM issue_watchers WHERE user = ?`, from); err != nil {
+		return nil, fmt.Errorf("failed to merge watches: %w", err)
+	}
+	// into's own digest subscription wins over from's
+	res, err = tx.ExecContext(ctx, `UPDATE OR IGNORE digest_subscriptions SET actor = ? WHERE actor = ?`, into, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge digest subscriptions: %w", err)
+	}
+	subscriptions, _ := res.RowsAffected()
+	if _, err := tx.ExecContext(ctx, `DELETE FROM digest_subscriptions WHERE actor = ?`, from); err != nil {
+		return nil, fmt.Errorf("failed to merge digest subscriptions: %w", err)
+	}
+
+	records := report.Assignments + report.Dependencies + report.Events + report.EventPayloads + report.Comments + report.Snapshots + report.Redactions +
+		report.History + report.Commits + report.Deletions + report.Archivals + report.WorkLogs + report.Leases + report.Records + report.Oplog
+	merge := &ActorMerge{
+		From:              from,
+		Into:              into,
+		DryRun:            dryRun,
+		Records:           records + int(watches) + int(subscriptions),
+		IssuesAffected:    report.IssuesAffected,
+		AuditChainRebuilt: report.AuditChainRebuilt,
+	}
+	if merge.IssuesAffected == nil {
+		merge.IssuesAffected = []string{}
+	}
+	if dryRun {
+		return merge, nil
+	}
+
+	if err := mergeActorRegistration(ctx, tx, from, into); err != nil {
+		return nil, err
+	}
+
+	// Mark affected issues dirty so the JSONL export picks up the new name
+	now := time.Now()
+	for _, id := range merge.IssuesAffected {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO dirty_issues (issue_id, marked_at)
+			SELECT id, ? FROM issues WHERE id = ?
+			ON CONFLICT (issue_id) DO UPDATE SET marked_at = excluded.marked_at
+		`, now, id); err != nil {
+			return nil, fmt.Errorf("failed to mark issue dirty: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit actor merge: %w", err)
+	}
+	return merge, nil
+}
+
+// mergeActorRegistration makes from an alias of into, registering into if
+// needed and folding from's own registration into it
+func mergeActorRegistration(ctx context.Context, tx *sql.Tx, from, into string) error {
+	if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO actors (name, type, created_at) VALUES (?, ?, ?)`, into, ActorHuman, time.Now()); err != nil {
+		return fmt.Errorf("failed to register actor %s: %w", into, err)
+	}
+
+	// Names ignore case, so merging "Alice" into "alice" leaves nothing to fold
+	if strings.EqualFold(from, into) {
+		return nil
+	}
+	var fromName, fromEmail string
+	err := tx.QueryRowContext(ctx, `SELECT name, email FROM actors WHERE name = ?`, from).Scan(&fromName, &fromEmail)
+	switch {
+	case err == sql.ErrNoRows:
+	case err != nil:
+		return fmt.Errorf("failed to look up actor %s: %w", from, err)
+	default:
+		if _, err := tx.ExecContext(ctx, `UPDATE actor_aliases SET actor = ? WHERE actor = ?`, into, fromName); err != nil {
+			return fmt.Errorf("failed to move aliases of %s: %w", fromName, err)
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE actors SET email = ? WHERE name = ? AND email = ''`, fromEmail, into); err != nil {
+			return fmt.Errorf("failed to move email of %s: %w", fromName, err)
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM actors WHERE name = ?`, fromName); err != nil {
+			return fmt.Errorf("failed to unregister %s: %w", fromName, err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO actor_aliases (alias, actor) VALUES (?, ?)
+		ON CONFLICT (alias) DO UPDATE SET actor = excluded.actor
+	`, from, into); err != nil {
+		return fmt.Errorf("failed to alias %s to %s: %w", from, into, err)
+	}
+	return nil
+}
+
+// addActorAliases adds aliases to the registered actor name, skipping ones
+// it already has
+func addActorAliases(ctx context.Context, tx *sql.Tx, name string, aliases []string) error {
+	for _, alias := range aliases {
+		alias = strings.TrimSpace(alias)
+		if alias == "" || strings.EqualFold(alias, name) {
+			continue
+		}
+		if err := checkActorNameFree(ctx, tx, alias, name); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO actor_aliases (alias, actor) VALUES (?, ?)`, alias, name); err != nil {
+			return fmt.Errorf("failed to add alias %s: %w", alias, err)
+		}
+	}
+	return nil
+}
+
+// checkActorNameFree fails if name resolves to a registered actor other
+// than owner
+func checkActorNameFree(ctx context.Context, tx *sql.Tx, name, owner string) error {
+	resolved, found, err := lookupActor(ctx, tx, name)
+	if err != nil {
+		return err
+	}
+	if found && !strings.EqualFold(resolved, owner) {
+		return fmt.Errorf("%s %w: %s", name, ErrActorTaken, resolved)
+	}
+	return nil
+}
+
+func (s *SQLiteStorage) queryActors(ctx context.Context, where string, args ...interface{}) ([]*Actor, error) {
+	// #nosec G202 - where is one of a fixed set of clauses
+	rows, err := s.reads.QueryContext(ctx, `
+		SELECT name, email, type, created_at FROM actors `+where+`
+		ORDER BY name
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query actors: %w", err)
+	}
+	var actors []*Actor
+	for rows.Next() {
+		var actor Actor
+		if err := rows.Scan(&actor.Name, &actor.Email, &actor.Type, &actor.CreatedAt); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("failed to scan actor: %w", err)
+		}
+		actors = append(actors, &actor)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, actor := range actors {
+		if actor.Aliases, err = s.getActorAliases(ctx, actor.Name); err != nil {
+			return nil, err
+		}
+	}
+	return actors, nil
+}
+
+func (s *SQLiteStorage) getActorAliases(ctx context.Context, name string) ([]string, error) {
+	rows, err := s.reads.QueryContext(ctx, `SELECT alias FROM actor_aliases WHERE actor = ?`, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get aliases of %s: %w", name, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var aliases []string
+	for rows.Next() {
+		var alias string
+		if err := rows.Scan(&alias); err != nil {
+			return nil, fmt.Errorf("failed to scan alias: %w", err)
+		}
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	return aliases, rows.Err()
+}
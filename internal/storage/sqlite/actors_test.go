@@ -0,0 +1,234 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestActorRegistry(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	alice := &Actor{Name: "alice", Email: "alice@corp.com", Aliases: []string{"al", "Alice"}}
+	if err := store.CreateActor(ctx, alice); err != nil {
+		t.Fatalf("CreateActor failed: %v", err)
+	}
+	if alice.Type != ActorHuman || len(alice.Aliases) != 1 || alice.Aliases[0] != "al" {
+		t.Errorf("Expected a human with only the alias al, got %+v", alice)
+	}
+
+	for _, name := range []string{"alice", "ALICE", " al ", "Alice@Corp.com"} {
+		if got, err := store.ResolveActor(ctx, name); err != nil || got != "alice" {
+			t.Errorf("ResolveActor(%q) = %q, %v; want alice", name, got, err)
+		}
+	}
+	if got, _ := store.ResolveActor(ctx, " carol "); got != "carol" {
+		t.Errorf("Expected an unregistered name back trimmed, got %q", got)
+	}
+
+	// Names, aliases, and emails can't be shared
+	for _, taken := range []*Actor{
+		{Name: "AL"},
+		{Name: "bob", Email: "alice@corp.com"},
+		{Name: "bob", Aliases: []string{"Alice"}},
+	} {
+		if err := store.CreateActor(ctx, taken); !errors.Is(err, ErrActorTaken) {
+			t.Errorf("Expected ErrActorTaken registering %+v, got %v", taken, err)
+		}
+	}
+	if err := store.CreateActor(ctx, &Actor{Name: "ci", Type: "robot"}); err == nil {
+		t.Error("Expected an invalid type to fail")
+	}
+
+	updated, err := store.UpdateActor(ctx, "al", &Actor{Type: ActorAgent, Aliases: []string{"a.smith"}})
+	if err != nil {
+		t.Fatalf("UpdateActor failed: %v", err)
+	}
+	if updated.Type != ActorAgent || updated.Email != "alice@corp.com" || len(updated.Aliases) != 2 {
+		t.Errorf("Expected an agent keeping its email with two aliases, got %+v", updated)
+	}
+	if err := store.RemoveActorAlias(ctx, "a.smith"); err != nil {
+		t.Fatalf("RemoveActorAlias failed: %v", err)
+	}
+	if err := store.RemoveActorAlias(ctx, "a.smith"); !errors.Is(err, ErrActorNotFound) {
+		t.Errorf("Expected ErrActorNotFound removing a missing alias, got %v", err)
+	}
+
+	// Assignees are normalized on write
+	issue := &types.Issue{Title: "Fix it", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, Assignee: "ALICE@corp.com"}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	if issue.Assignee != "alice" {
+		t.Errorf("Expected the assignee normalized on create, got %q", issue.Assignee)
+	}
+	if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"assignee": "al"}, "test"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	if got, _ := store.GetIssue(ctx, issue.ID); got.Assignee != "alice" {
+		t.Errorf("Expected the assignee normalized on update, got %q", got.Assignee)
+	}
+
+	if err := store.DeleteActor(ctx, "AL"); err != nil {
+		t.Fatalf("DeleteActor failed: %v", err)
+	}
+	if _, err := store.GetActor(ctx, "alice"); !errors.Is(err, ErrActorNotFound) {
+		t.Errorf("Expected ErrActorNotFound after deleting, got %v", err)
+	}
+	if got, _ := store.ResolveActor(ctx, "al"); got != "al" {
+		t.Errorf("Expected the alias to stop resolving, got %q", got)
+	}
+}
+
+func TestMergeActors(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	issue := &types.Issue{Title: "Fix it", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, Assignee: "a.smith"}
+	if err := store.CreateIssue(ctx, issue, "a.smith"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	if _, err := store.AddIssueComment(ctx, issue.ID, "a.smith", "On it"); err != nil {
+		t.Fatalf("AddIssueComment failed: %v", err)
+	}
+	for _, user := range []string{"a.smith", "alice"} {
+		if err := store.WatchIssue(ctx, issue.ID, user); err != nil {
+			t.Fatalf("WatchIssue failed: %v", err)
+		}
+	}
+	if err := store.CreateActor(ctx, &Actor{Name: "a.smith", Email: "alice@corp.com", Aliases: []string{"as"}}); err != nil {
+		t.Fatalf("CreateActor failed: %v", err)
+	}
+
+	if _, err := store.MergeActors(ctx, "alice", "alice", false); err == nil {
+		t.Error("Expected merging an actor into itself to fail")
+	}
+
+	preview, err := store.MergeActors(ctx, "a.smith", "alice", true)
+	if err != nil {
+		t.Fatalf("MergeActors dry run failed: %v", err)
+	}
+	if !preview.DryRun || preview.Records == 0 || len(preview.IssuesAffected) != 1 {
+		t.Errorf("Expected a dry run counting records on one issue, got %+v", preview)
+	}
+	if got, _ := store.GetIssue(ctx, issue.ID); got.Assignee != "a.smith" {
+		t.Errorf("Expected the dry run to change nothing, got assignee %q", got.Assignee)
+	}
+
+	merge, err := store.MergeActors(ctx, "a.smith", "alice", false)
+	if err != nil {
+		t.Fatalf("MergeActors failed: %v", err)
+	}
+	if merge.Records != preview.Records {
+		t.Errorf("Expected the dry run to count %d records, counted %d", merge.Records, preview.Records)
+	}
+
+	got, _ := store.GetIssue(ctx, issue.ID)
+	if got.Assignee != "alice" {
+		t.Errorf("Expected the assignee rewritten, got %q", got.Assignee)
+	}
+	comments, _ := store.GetIssueComments(ctx, issue.ID)
+	if len(comments) != 1 || comments[0].Author != "alice" {
+		t.Errorf("Expected the comment author rewritten, got %+v", comments)
+	}
+	if watchers, _ := store.GetWatchers(ctx, issue.ID); len(watchers) != 1 || watchers[0] != "alice" {
+		t.Errorf("Expected the two watches folded into one, got %v", watchers)
+	}
+	events, _ := store.GetEvents(ctx, issue.ID, 0)
+	for _, e := range events {
+		if e.Actor == "a.smith" {
+			t.Errorf("Expected no events left naming a.smith, got %+v", e)
+		}
+	}
+
+	// a.smith's registration folds into alice
+	actor, err := store.GetActor(ctx, "a.smith")
+	if err != nil {
+		t.Fatalf("GetActor failed: %v", err)
+	}
+	if actor.Name != "alice" || actor.Email != "alice@corp.com" || len(actor.Aliases) != 2 {
+		t.Errorf("Expected alice with the email and aliases a.smith and as, got %+v", actor)
+	}
+	if actors, _ := store.ListActors(ctx); len(actors) != 1 {
+		t.Errorf("Expected only alice registered, got %d actors", len(actors))
+	}
+}
+
+func TestMergeActorsRollsUpTimeAndWorkload(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// a.smith and alice are the same person, each with work under one name
+	for _, user := range []string{"a.smith", "alice"} {
+		issue := &types.Issue{Title: "Work for " + user, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, Assignee: user}
+		if err := store.CreateIssue(ctx, issue, user); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		if err := store.AddWorkLog(ctx, &WorkLog{IssueID: issue.ID, Actor: user, Minutes: 30}); err != nil {
+			t.Fatalf("AddWorkLog failed: %v", err)
+		}
+		if user == "a.smith" {
+			if _, err := store.ClaimIssue(ctx, issue.ID, user, time.Hour); err != nil {
+				t.Fatalf("ClaimIssue failed: %v", err)
+			}
+		}
+	}
+	if err := store.SetDigestSubscription(ctx, &DigestSubscription{Actor: "a.smith", Email: "alice@corp.com", Frequency: "weekly"}); err != nil {
+		t.Fatalf("SetDigestSubscription failed: %v", err)
+	}
+
+	if _, err := store.MergeActors(ctx, "a.smith", "alice", false); err != nil {
+		t.Fatalf("MergeActors failed: %v", err)
+	}
+
+	stats, err := store.GetStatistics(ctx)
+	if err != nil {
+		t.Fatalf("GetStatistics failed: %v", err)
+	}
+	if len(stats.TimeByAssignee) != 1 || *stats.TimeByAssignee[0] != (types.AssigneeTime{Assignee: "alice", Issues: 2, LoggedMinutes: 60}) {
+		t.Errorf("Expected all logged time rolled up under alice, got %+v", stats.TimeByAssignee)
+	}
+
+	workload, err := storage.GetWorkload(ctx, store)
+	if err != nil {
+		t.Fatalf("GetWorkload failed: %v", err)
+	}
+	if len(workload) != 1 || workload[0].Assignee != "alice" || workload[0].Open != 1 || workload[0].InProgress != 1 {
+		t.Errorf("Expected one workload for alice with both issues, got %+v", workload)
+	}
+
+	issues, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+	for _, issue := range issues {
+		logs, _ := store.GetWorkLogs(ctx, issue.ID)
+		if len(logs) != 1 || logs[0].Actor != "alice" {
+			t.Errorf("Expected %s's time logged by alice, got %+v", issue.ID, logs)
+		}
+		history, _ := store.GetIssueHistory(ctx, issue.ID)
+		for _, rev := range history {
+			if rev.Actor == "a.smith" {
+				t.Errorf("Expected no revisions left by a.smith, got %+v", rev)
+			}
+		}
+		if lease, _ := store.GetLease(ctx, issue.ID); lease != nil && lease.Holder != "alice" {
+			t.Errorf("Expected the claim carried over to alice, got %+v", lease)
+		}
+	}
+
+	if sub, _ := store.GetDigestSubscription(ctx, "alice"); sub == nil || sub.Email != "alice@corp.com" {
+		t.Errorf("Expected a.smith's digest subscription moved to alice, got %+v", sub)
+	}
+	if sub, _ := store.GetDigestSubscription(ctx, "a.smith"); sub != nil {
+		t.Errorf("Expected no subscription left for a.smith, got %+v", sub)
+	}
+}
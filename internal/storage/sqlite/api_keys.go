@@ -0,0 +1,124 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// APIKey is a credential for bd serve. Only a hash of the token is stored;
+// Hint keeps its first characters so keys can be told apart.
+type APIKey struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Role       string     `json:"role"`
+	Hint       string     `json:"hint"`
+	CreatedBy  string     `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// CreateAPIKey stores a key by its token hash and sets its ID and creation time
+func (s *SQLiteStorage) CreateAPIKey(ctx context.Context, key *APIKey, tokenHash string) error {
+	if key.Name == "" || tokenHash == "" {
+		return fmt.Errorf("API key requires a name and token")
+	}
+
+	key.CreatedAt = time.Now()
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO api_keys (name, role, token_hash, hint, created_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, key.Name, key.Role, tokenHash, key.Hint, key.CreatedBy, key.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create API key: %w", err)
+	}
+	if key.ID, err = result.LastInsertId(); err != nil {
+		return fmt.Errorf("failed to get API key ID: %w", err)
+	}
+	return nil
+}
+
+// ListAPIKeys returns every key, oldest first
+func (s *SQLiteStorage) ListAPIKeys(ctx context.Context) ([]*APIKey, error) {
+	rows, err := s.reads.QueryContext(ctx, `
+		SELECT id, name, role, hint, created_by, created_at, last_used_at
+		FROM api_keys
+		ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var keys []*APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// GetAPIKeyByHash returns the key with the given token hash, or nil
+func (s *SQLiteStorage) GetAPIKeyByHash(ctx context.Context, tokenHash string) (*APIKey, error) {
+	row := s.reads.QueryRowContext(ctx, `
+		SELECT id, name, role, hint, created_by, created_at, last_used_at
+		FROM api_keys
+		WHERE token_hash = ?
+	`, tokenHash)
+	key, err := scanAPIKey(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return key, err
+}
+
+// HasAPIKeys reports whether any key exists
+func (s *SQLiteStorage) HasAPIKeys(ctx context.Context) (bool, error) {
+	var exists bool
+	if err := s.reads.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM api_keys)`).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check API keys: %w", err)
+	}
+	return exists, nil
+}
+
+// TouchAPIKey records that a key was used
+func (s *SQLiteStorage) TouchAPIKey(ctx context.Context, id int64, usedAt time.Time) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE api_keys SET last_used_at = ? WHERE id = ?`, usedAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to update API key: %w", err)
+	}
+	return nil
+}
+
+// DeleteAPIKey revokes a key
+func (s *SQLiteStorage) DeleteAPIKey(ctx context.Context, id int64) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM api_keys WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete API key: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return types.NotFound("API key", id)
+	}
+	return nil
+}
+
+func scanAPIKey(row rowScanner) (*APIKey, error) {
+	var key APIKey
+	var lastUsed sql.NullTime
+	if err := row.Scan(&key.ID, &key.Name, &key.Role, &key.Hint, &key.CreatedBy, &key.CreatedAt, &lastUsed); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan API key: %w", err)
+	}
+	if lastUsed.Valid {
+		key.LastUsedAt = &lastUsed.Time
+	}
+	return &key, nil
+}
@@ -0,0 +1,62 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAPIKeys(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	if has, err := store.HasAPIKeys(ctx); err != nil || has {
+		t.Fatalf("Expected no keys: has=%v err=%v", has, err)
+	}
+
+	key := &APIKey{Name: "ci", Role: "writer", Hint: "bdk_1234", CreatedBy: "alice"}
+	if err := store.CreateAPIKey(ctx, key, "hash-1"); err != nil {
+		t.Fatalf("CreateAPIKey failed: %v", err)
+	}
+	if key.ID == 0 {
+		t.Fatal("Expected key ID to be set")
+	}
+	if err := store.CreateAPIKey(ctx, &APIKey{Name: "dup", Role: "reader"}, "hash-1"); err == nil {
+		t.Error("Expected error for duplicate token hash")
+	}
+	if has, _ := store.HasAPIKeys(ctx); !has {
+		t.Error("Expected HasAPIKeys after create")
+	}
+
+	got, err := store.GetAPIKeyByHash(ctx, "hash-1")
+	if err != nil {
+		t.Fatalf("GetAPIKeyByHash failed: %v", err)
+	}
+	if got == nil || got.Name != "ci" || got.Role != "writer" || got.LastUsedAt != nil {
+		t.Errorf("Unexpected key %+v", got)
+	}
+	if got, _ := store.GetAPIKeyByHash(ctx, "nope"); got != nil {
+		t.Errorf("Expected nil for unknown hash, got %+v", got)
+	}
+
+	used := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+	if err := store.TouchAPIKey(ctx, key.ID, used); err != nil {
+		t.Fatalf("TouchAPIKey failed: %v", err)
+	}
+	keys, err := store.ListAPIKeys(ctx)
+	if err != nil {
+		t.Fatalf("ListAPIKeys failed: %v", err)
+	}
+	if len(keys) != 1 || keys[0].LastUsedAt == nil || !keys[0].LastUsedAt.Equal(used) {
+		t.Errorf("Unexpected keys %+v", keys)
+	}
+
+	if err := store.DeleteAPIKey(ctx, key.ID); err != nil {
+		t.Fatalf("DeleteAPIKey failed: %v", err)
+	}
+	if err := store.DeleteAPIKey(ctx, key.ID); err == nil {
+		t.Error("Expected error revoking a missing key")
+	}
+}
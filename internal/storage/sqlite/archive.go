@@ -0,0 +1,150 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// DefaultArchiveDays is how long an issue must have been closed before
+// bd archive run moves it to the archive, unless archive_days says otherwise
+const DefaultArchiveDays = 90
+
+// ErrNotArchived is returned when restoring an issue that isn't archived
+var ErrNotArchived = errors.New("issue is not archived")
+
+// ArchivedIssue is an entry in the archive
+type ArchivedIssue struct {
+	IssueID    string    `json:"issue_id"`
+	Title      string    `json:"title"`
+	ClosedAt   time.Time `json:"closed_at"`
+	ArchivedAt time.Time `json:"archived_at"`
+	ArchivedBy string    `json:"archived_by"`
+}
+
+// ArchiveDays returns how many days an issue must have been closed before
+// it is archived (config archive_days)
+func (s *SQLiteStorage) ArchiveDays(ctx context.Context) (int, error) {
+	value, err := s.GetConfig(ctx, "archive_days")
+	if err != nil {
+		return 0, err
+	}
+	if value == "" {
+		return DefaultArchiveDays, nil
+	}
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 0 {
+		return 0, fmt.Errorf("invalid archive_days %q: must be a whole number of days", value)
+	}
+	return days, nil
+}
+
+// ArchiveClosedIssues moves issues closed at or before cutoff into the
+// archive and returns their IDs. Archived issues stay in the database and in
+// exports but are left out of listings and search that set
+// IssueFilter.ExcludeArchived. With dryRun nothing is archived.
+func (s *SQLiteStorage) ArchiveClosedIssues(ctx context.Context, cutoff time.Time, actor string, dryRun bool) ([]string, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id FROM issues
+		WHERE status = 'closed'
+		  AND closed_at IS NOT NULL
+		  AND closed_at <= ?
+		  AND deleted_at IS NULL
+		  AND id NOT IN (SELECT issue_id FROM archived_issues)
+		ORDER BY closed_at, id
+	`, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find issues to archive: %w", err)
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 || dryRun {
+		return ids, nil
+	}
+
+	now := time.Now()
+	for _, id := range ids {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO archived_issues (issue_id, archived_at, archived_by)
+			VALUES (?, ?, ?)
+		`, id, now, actor)
+		if err != nil {
+			return nil, fmt.Errorf("failed to archive %s: %w", id, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return ids, nil
+}
+
+// RestoreArchivedIssue takes an issue back out of the archive
+func (s *SQLiteStorage) RestoreArchivedIssue(ctx context.Context, id string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM archived_issues WHERE issue_id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to restore issue: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotArchived
+	}
+	return nil
+}
+
+// IsArchived reports whether an issue is in the archive
+func (s *SQLiteStorage) IsArchived(ctx context.Context, id string) (bool, error) {
+	var archived bool
+	err := s.reads.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM archived_issues WHERE issue_id = ?)`, id).Scan(&archived)
+	if err != nil {
+		return false, fmt.Errorf("failed to check archive: %w", err)
+	}
+	return archived, nil
+}
+
+// ListArchived returns the archive, most recently archived first
+func (s *SQLiteStorage) ListArchived(ctx context.Context) ([]*ArchivedIssue, error) {
+	rows, err := s.reads.QueryContext(ctx, `
+		SELECT a.issue_id, i.title, i.closed_at, a.archived_at, a.archived_by
+		FROM archived_issues a
+		JOIN issues i ON i.id = a.issue_id
+		ORDER BY a.archived_at DESC, a.issue_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list archive: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var archived []*ArchivedIssue
+	for rows.Next() {
+		var a ArchivedIssue
+		if err := rows.Scan(&a.IssueID, &a.Title, &a.ClosedAt, &a.ArchivedAt, &a.ArchivedBy); err != nil {
+			return nil, fmt.Errorf("failed to scan archived issue: %w", err)
+		}
+		archived = append(archived, &a)
+	}
+	return archived, rows.Err()
+}
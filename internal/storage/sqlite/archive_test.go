@@ -0,0 +1,87 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestArchiveClosedIssues(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	old := &types.Issue{Title: "Old", Status: types.StatusClosed, Priority: 2, IssueType: types.TypeTask,
+		ClosedAt: timePtr(time.Now().Add(-100 * 24 * time.Hour))}
+	recent := &types.Issue{Title: "Recent", Status: types.StatusClosed, Priority: 2, IssueType: types.TypeTask,
+		ClosedAt: timePtr(time.Now().Add(-time.Hour))}
+	open := &types.Issue{Title: "Open", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	for _, issue := range []*types.Issue{old, recent, open} {
+		if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -DefaultArchiveDays)
+	ids, err := store.ArchiveClosedIssues(ctx, cutoff, "bob", true)
+	if err != nil {
+		t.Fatalf("ArchiveClosedIssues dry run failed: %v", err)
+	}
+	if len(ids) != 1 || ids[0] != old.ID {
+		t.Fatalf("Expected dry run to find %s, got %v", old.ID, ids)
+	}
+	if archived, _ := store.IsArchived(ctx, old.ID); archived {
+		t.Fatal("Dry run should not archive")
+	}
+
+	if _, err := store.ArchiveClosedIssues(ctx, cutoff, "bob", false); err != nil {
+		t.Fatalf("ArchiveClosedIssues failed: %v", err)
+	}
+
+	// Hidden from listings that exclude the archive, but still in full scans
+	listed, _ := store.SearchIssues(ctx, "", types.IssueFilter{ExcludeArchived: true})
+	if len(listed) != 2 {
+		t.Errorf("Expected 2 unarchived issues, got %d", len(listed))
+	}
+	all, _ := store.SearchIssues(ctx, "", types.IssueFilter{})
+	if len(all) != 3 {
+		t.Errorf("Expected 3 issues including the archive, got %d", len(all))
+	}
+	hits, err := store.FullTextSearch(ctx, "Old", SearchOptions{Filter: types.IssueFilter{ExcludeArchived: true}})
+	if err != nil {
+		t.Fatalf("FullTextSearch failed: %v", err)
+	}
+	if len(hits) != 0 {
+		t.Errorf("Expected archived issue hidden from search, got %d hits", len(hits))
+	}
+
+	archive, err := store.ListArchived(ctx)
+	if err != nil {
+		t.Fatalf("ListArchived failed: %v", err)
+	}
+	if len(archive) != 1 || archive[0].IssueID != old.ID || archive[0].ArchivedBy != "bob" {
+		t.Fatalf("Unexpected archive: %+v", archive)
+	}
+
+	if err := store.RestoreArchivedIssue(ctx, old.ID); err != nil {
+		t.Fatalf("RestoreArchivedIssue failed: %v", err)
+	}
+	if err := store.RestoreArchivedIssue(ctx, old.ID); !errors.Is(err, ErrNotArchived) {
+		t.Errorf("Expected ErrNotArchived, got %v", err)
+	}
+
+	// Reopening an archived issue takes it out of the archive
+	if _, err := store.ArchiveClosedIssues(ctx, cutoff, "bob", false); err != nil {
+		t.Fatalf("ArchiveClosedIssues failed: %v", err)
+	}
+	if err := store.UpdateIssue(ctx, old.ID, map[string]interface{}{"status": "open"}, "bob"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	if archived, _ := store.IsArchived(ctx, old.ID); archived {
+		t.Error("Expected reopened issue to leave the archive")
+	}
+}
@@ -0,0 +1,448 @@
+package sqlite
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// auditGenesisHash is the prev_hash of the first entry in the audit chain
+const auditGenesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// AuditSealResult describes the outcome of sealing new events into the audit chain
+type AuditSealResult struct {
+	Sealed   int    `json:"sealed"`
+	HeadSeq  int64  `json:"head_seq"`
+	HeadHash string `json:"head_hash"`
+}
+
+// AuditProblem describes a single verification failure in the audit chain
+type AuditProblem struct {
+	Seq     int64  `json:"seq,omitempty"`
+	EventID int64  `json:"event_id"`
+	Kind    string `json:"kind"` // "modified", "deleted", "inserted", "broken_link"
+	Detail  string `json:"detail"`
+}
+
+// AuditVerifyResult is the outcome of verifying the audit chain
+type AuditVerifyResult struct {
+	Valid    bool            `json:"valid"`
+	Checked  int             `json:"checked"`
+	Unsealed int             `json:"unsealed"`
+	HeadSeq  int64           `json:"head_seq"`
+	HeadHash string          `json:"head_hash"`
+	Problems []*AuditProblem `json:"problems,omitempty"`
+}
+
+// AuditSignature is a signature over a chain head
+type AuditSignature struct {
+	ID        int64     `json:"id"`
+	HeadSeq   int64     `json:"head_seq"`
+	HeadHash  string    `json:"head_hash"`
+	Method    string    `json:"method"` // "gpg"
+	Signer    string    `json:"signer,omitempty"`
+	Signature string    `json:"signature"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// auditEventRecord is the canonical form of an event used for hashing.
+// Field order is fixed by the struct definition so the JSON encoding is stable.
+type auditEventRecord struct {
+	ID        int64   `json:"id"`
+	IssueID   string  `json:"issue_id"`
+	EventType string  `json:"event_type"`
+	Actor     string  `json:"actor"`
+	OldValue  *string `json:"old_value"`
+	NewValue  *string `json:"new_value"`
+	Comment   *string `json:"comment"`
+	CreatedAt string  `json:"created_at"`
+	SessionID string  `json:"session_id,omitempty"` // omitted when empty, so untagged events hash as before
+}
+
+// hashAuditEvent computes the content hash of a single event row
+func hashAuditEvent(rec *auditEventRecord) string {
+	data, _ := json.Marshal(rec)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// chainAuditHash links an event hash to the previous chain hash
+func chainAuditHash(prevHash, eventHash string) string {
+	sum := sha256.Sum256([]byte(prevHash + eventHash))
+	return hex.EncodeToString(sum[:])
+}
+
+// scanAuditEvent scans an events row into its canonical hashing form
+func scanAuditEvent(rows *sql.Rows) (*auditEventRecord, error) {
+	var rec auditEventRecord
+	var oldValue, newValue, comment, session sql.NullString
+	var createdAt time.Time
+	if err := rows.Scan(&rec.ID, &rec.IssueID, &rec.EventType, &rec.Actor,
+		&oldValue, &newValue, &comment, &createdAt, &session); err != nil {
+		return nil, fmt.Errorf("failed to scan event: %w", err)
+	}
+	if oldValue.Valid {
+		rec.OldValue = &oldValue.String
+	}
+	if newValue.Valid {
+		rec.NewValue = &newValue.String
+	}
+	if comment.Valid {
+		rec.Comment = &comment.String
+	}
+	rec.CreatedAt = createdAt.UTC().Format(time.RFC3339Nano)
+	rec.SessionID = session.String
+	return &rec, nil
+}
+
+// getAuditHead returns the last chain entry (seq, event_id, chain_hash)
+func getAuditHead(ctx context.Context, q interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}) (int64, int64, string, error) {
+	var seq, eventID int64
+	var hash string
+	err := q.QueryRowContext(ctx, `
+		SELECT seq, event_id, chain_hash FROM audit_chain ORDER BY seq DESC LIMIT 1
+	`).Scan(&seq, &eventID, &hash)
+	if err == sql.ErrNoRows {
+		return 0, 0, auditGenesisHash, nil
+	}
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("failed to get audit chain head: %w", err)
+	}
+	return seq, eventID, hash, nil
+}
+
+// GetAuditHead returns the sequence number and hash of the current chain head.
+// An empty chain returns seq 0 and the genesis hash.
+func (s *SQLiteStorage) GetAuditHead(ctx context.Context) (int64, string, error) {
+	seq, _, hash, err := getAuditHead(ctx, s.db)
+	return seq, hash, err
+}
+
+// SealAuditChain appends every event not yet in the audit chain, in event ID order.
+// Each entry commits to the event content and to the previous entry, so any later
+// modification, deletion, or back-dated insertion of sealed events is detectable.
+func (s *SQLiteStorage) SealAuditChain(ctx context.Context) (*AuditSealResult, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	seq, lastEventID, prevHash, err := getAuditHead(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, issue_id, event_type, actor, old_value, new_value, comment, created_at, session_id
+		FROM events
+		WHERE id > ?
+		ORDER BY id
+	`, lastEventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query unsealed events: %w", err)
+	}
+	var pending []*auditEventRecord
+	for rows.Next() {
+		rec, err := scanAuditEvent(rows)
+		if err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+		pending = append(pending, rec)
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, fmt.Errorf("error iterating events: %w", err)
+	}
+	_ = rows.Close()
+
+	result := &AuditSealResult{HeadSeq: seq, HeadHash: prevHash}
+	if len(pending) == 0 {
+		return result, nil
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO audit_chain (event_id, event_hash, prev_hash, chain_hash)
+		VALUES (?, ?, ?, ?)
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare audit chain insert: %w", err)
+	}
+	defer func() { _ = stmt.Close() }()
+
+	for _, rec := range pending {
+		eventHash := hashAuditEvent(rec)
+		chainHash := chainAuditHash(prevHash, eventHash)
+		res, err := stmt.ExecContext(ctx, rec.ID, eventHash, prevHash, chainHash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to seal event %d: %w", rec.ID, err)
+		}
+		if id, err := res.LastInsertId(); err == nil {
+			result.HeadSeq = id
+		}
+		prevHash = chainHash
+		result.Sealed++
+	}
+	result.HeadHash = prevHash
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit audit chain: %w", err)
+	}
+	return result, nil
+}
+
+// VerifyAuditChain recomputes the audit chain and compares it against the current
+// events table. It reports modified and deleted events, events inserted behind the
+// chain head, and chain entries whose links no longer match.
+func (s *SQLiteStorage) VerifyAuditChain(ctx context.Context) (*AuditVerifyResult, error) {
+	// Load current events keyed by ID
+	rows, err := s.reads.QueryContext(ctx, `
+		SELECT id, issue_id, event_type, actor, old_value, new_value, comment, created_at, session_id
+		FROM events
+		ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+	events := make(map[int64]*auditEventRecord)
+	var eventIDs []int64
+	for rows.Next() {
+		rec, err := scanAuditEvent(rows)
+		if err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+		events[rec.ID] = rec
+		eventIDs = append(eventIDs, rec.ID)
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, fmt.Errorf("error iterating events: %w", err)
+	}
+	_ = rows.Close()
+
+	chainRows, err := s.reads.QueryContext(ctx, `
+		SELECT seq, event_id, event_hash, prev_hash, chain_hash
+		FROM audit_chain
+		ORDER BY seq
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit chain: %w", err)
+	}
+	defer func() { _ = chainRows.Close() }()
+
+	result := &AuditVerifyResult{HeadHash: auditGenesisHash}
+	sealed := make(map[int64]bool)
+	prevHash := auditGenesisHash
+	var maxSealedID int64
+
+	for chainRows.Next() {
+		var seq, eventID int64
+		var eventHash, storedPrev, chainHash string
+		if err := chainRows.Scan(&seq, &eventID, &eventHash, &storedPrev, &chainHash); err != nil {
+			return nil, fmt.Errorf("failed to scan audit chain: %w", err)
+		}
+		result.Checked++
+		sealed[eventID] = true
+		if eventID > maxSealedID {
+			maxSealedID = eventID
+		}
+
+		if storedPrev != prevHash || chainAuditHash(storedPrev, eventHash) != chainHash {
+			result.Problems = append(result.Problems, &AuditProblem{
+				Seq: seq, EventID: eventID, Kind: "broken_link",
+				Detail: "chain entry does not link to its predecessor",
+			})
+		}
+
+		rec, ok := events[eventID]
+		switch {
+		case !ok:
+			result.Problems = append(result.Problems, &AuditProblem{
+				Seq: seq, EventID: eventID, Kind: "deleted",
+				Detail: "sealed event no longer exists",
+			})
+		case hashAuditEvent(rec) != eventHash:
+			result.Problems = append(result.Problems, &AuditProblem{
+				Seq: seq, EventID: eventID, Kind: "modified",
+				Detail: fmt.Sprintf("event on %s by %s changed after sealing", rec.IssueID, rec.Actor),
+			})
+		}
+
+		prevHash = chainHash
+		result.HeadSeq = seq
+		result.HeadHash = chainHash
+	}
+	if err := chainRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating audit chain: %w", err)
+	}
+
+	for _, id := range eventIDs {
+		if sealed[id] {
+			continue
+		}
+		if id < maxSealedID {
+			result.Problems = append(result.Problems, &AuditProblem{
+				EventID: id, Kind: "inserted",
+				Detail: fmt.Sprintf("event on %s was inserted behind the chain head", events[id].IssueID),
+			})
+			continue
+		}
+		result.Unsealed++
+	}
+
+	result.Valid = len(result.Problems) == 0
+	return result, nil
+}
+
+// AddAuditSignature records a signature over a chain head
+func (s *SQLiteStorage) AddAuditSignature(ctx context.Context, sig *AuditSignature) error {
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO audit_signatures (head_seq, head_hash, method, signer, signature)
+		VALUES (?, ?, ?, ?, ?)
+	`, sig.HeadSeq, sig.HeadHash, sig.Method, sig.Signer, sig.Signature)
+	if err != nil {
+		return fmt.Errorf("failed to record audit signature: %w", err)
+	}
+	if id, err := res.LastInsertId(); err == nil {
+		sig.ID = id
+	}
+	return nil
+}
+
+// GetAuditSignatures returns all recorded chain head signatures, oldest first
+func (s *SQLiteStorage) GetAuditSignatures(ctx context.Context) ([]*AuditSignature, error) {
+	rows, err := s.reads.QueryContext(ctx, `
+		SELECT id, head_seq, head_hash, method, signer, signature, created_at
+		FROM audit_signatures
+		ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit signatures: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var sigs []*AuditSignature
+	for rows.Next() {
+		var sig AuditSignature
+		if err := rows.Scan(&sig.ID, &sig.HeadSeq, &sig.HeadHash, &sig.Method,
+			&sig.Signer, &sig.Signature, &sig.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan audit signature: %w", err)
+		}
+		sigs = append(sigs, &sig)
+	}
+	return sigs, rows.Err()
+}
+
+// GetAuditChainHash returns the chain hash stored at a given sequence number
+func (s *SQLiteStorage) GetAuditChainHash(ctx context.Context, seq int64) (string, error) {
+	var hash string
+	err := s.reads.QueryRowContext(ctx, `SELECT chain_hash FROM audit_chain WHERE seq = ?`, seq).Scan(&hash)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get audit chain hash: %w", err)
+	}
+	return hash, nil
+}
+
+// rebuildAuditChain recomputes every chain entry from the current events table.
+// It is used only by sanctioned history rewrites (actor purges, retention) that
+// are themselves recorded in a signed report. Entries for events that no longer
+// exist are dropped, and signatures over heads that changed are removed.
+func rebuildAuditChain(ctx context.Context, tx *sql.Tx) (bool, int, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT c.seq, e.id, e.issue_id, e.event_type, e.actor, e.old_value, e.new_value, e.comment, e.created_at, e.session_id
+		FROM audit_chain c
+		LEFT JOIN events e ON e.id = c.event_id
+		ORDER BY c.seq
+	`)
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to query audit chain: %w", err)
+	}
+	type entry struct {
+		seq int64
+		rec *auditEventRecord
+	}
+	var entries []entry
+	for rows.Next() {
+		var seq int64
+		var id sql.NullInt64
+		var issueID, eventType, actor, oldValue, newValue, comment, session sql.NullString
+		var createdAt sql.NullTime
+		if err := rows.Scan(&seq, &id, &issueID, &eventType, &actor, &oldValue, &newValue, &comment, &createdAt, &session); err != nil {
+			_ = rows.Close()
+			return false, 0, fmt.Errorf("failed to scan audit chain: %w", err)
+		}
+		if !id.Valid {
+			entries = append(entries, entry{seq: seq})
+			continue
+		}
+		rec := &auditEventRecord{
+			ID:        id.Int64,
+			IssueID:   issueID.String,
+			EventType: eventType.String,
+			Actor:     actor.String,
+			CreatedAt: createdAt.Time.UTC().Format(time.RFC3339Nano),
+			SessionID: session.String,
+		}
+		if oldValue.Valid {
+			rec.OldValue = &oldValue.String
+		}
+		if newValue.Valid {
+			rec.NewValue = &newValue.String
+		}
+		if comment.Valid {
+			rec.Comment = &comment.String
+		}
+		entries = append(entries, entry{seq: seq, rec: rec})
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return false, 0, fmt.Errorf("error iterating audit chain: %w", err)
+	}
+	_ = rows.Close()
+
+	if len(entries) == 0 {
+		return false, 0, nil
+	}
+
+	prevHash := auditGenesisHash
+	for _, e := range entries {
+		if e.rec == nil {
+			if _, err := tx.ExecContext(ctx, `DELETE FROM audit_chain WHERE seq = ?`, e.seq); err != nil {
+				return false, 0, fmt.Errorf("failed to drop chain entry %d: %w", e.seq, err)
+			}
+			continue
+		}
+		eventHash := hashAuditEvent(e.rec)
+		chainHash := chainAuditHash(prevHash, eventHash)
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE audit_chain SET event_hash = ?, prev_hash = ?, chain_hash = ? WHERE seq = ?
+		`, eventHash, prevHash, chainHash, e.seq); err != nil {
+			return false, 0, fmt.Errorf("failed to rehash chain entry %d: %w", e.seq, err)
+		}
+		prevHash = chainHash
+	}
+
+	res, err := tx.ExecContext(ctx, `
+		DELETE FROM audit_signatures
+		WHERE NOT EXISTS (
+			SELECT 1 FROM audit_chain c
+			WHERE c.seq = audit_signatures.head_seq AND c.chain_hash = audit_signatures.head_hash
+		)
+	`)
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to remove stale signatures: %w", err)
+	}
+	removed, _ := res.RowsAffected()
+	return true, int(removed), nil
+}
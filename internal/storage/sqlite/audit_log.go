@@ -0,0 +1,264 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// FieldChange is one field's value before and after a change. Values are
+// as they appear in the issue's JSON; Old is nil when it wasn't recorded.
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// AuditEntry is one recorded change with the fields it changed
+type AuditEntry struct {
+	EventID   int64           `json:"event_id"`
+	IssueID   string          `json:"issue_id"`
+	EventType types.EventType `json:"event_type"`
+	Actor     string          `json:"actor"`
+	SessionID string          `json:"session_id,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	Changes   []FieldChange   `json:"changes,omitempty"`
+	Comment   string          `json:"comment,omitempty"`
+}
+
+// AuditFilter narrows QueryAuditLog. Field keeps only entries that changed
+// that field. Before is a pagination cursor: only events with a smaller ID
+// are returned.
+type AuditFilter struct {
+	Actor   string
+	Session string
+	IssueID string
+	Field   string
+	Since   *time.Time
+	Until   *time.Time
+	Before  int64
+	Limit   int
+}
+
+// AuditPage is a page of the audit log, newest first. NextCursor is passed
+// as Before to get the following page; it's 0 on the last page.
+type AuditPage struct {
+	Entries    []*AuditEntry `json:"entries"`
+	NextCursor int64         `json:"next_cursor,omitempty"`
+}
+
+// DefaultAuditLimit is the page size when the filter doesn't set one
+const DefaultAuditLimit = 50
+
+// QueryAuditLog returns matching events newest first, with field-level diffs
+func (s *SQLiteStorage) QueryAuditLog(ctx context.Context, filter AuditFilter) (*AuditPage, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DefaultAuditLimit
+	}
+
+	var where []string
+	var args []interface{}
+	if filter.Actor != "" {
+		where = append(where, "actor = ?")
+		args = append(args, filter.Actor)
+	}
+	if filter.Session != "" {
+		where = append(where, "session_id = ?")
+		args = append(args, filter.Session)
+	}
+	if filter.IssueID != "" {
+		where = append(where, "issue_id = ?")
+		args = append(args, filter.IssueID)
+	}
+	if filter.Since != nil {
+		where = append(where, "datetime(created_at) >= ?")
+		args = append(args, sqliteDateTime(*filter.Since))
+	}
+	if filter.Until != nil {
+		where = append(where, "datetime(created_at) < ?")
+		args = append(args, sqliteDateTime(*filter.Until))
+	}
+	if filter.Before > 0 {
+		where = append(where, "id < ?")
+		args = append(args, filter.Before)
+	}
+	whereSQL := ""
+	if len(where) > 0 {
+		whereSQL = "WHERE " + strings.Join(where, " AND ")
+	}
+
+	// Diffs are computed from the (possibly encrypted) payloads, so a field
+	// filter is applied while reading rather than in SQL
+	limitSQL := ""
+	if filter.Field == "" {
+		limitSQL = limitClause
+		args = append(args, limit+1)
+	}
+
+	// #nosec G201 - safe SQL with controlled formatting
+	query := fmt.Sprintf(`
+		SELECT id, issue_id, event_type, actor, old_value, new_value, comment, created_at, session_id
+		FROM events
+		%s
+		ORDER BY id DESC
+		%s
+	`, whereSQL, limitSQL)
+
+	rows, err := s.reads.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	events, err := s.scanEventsWhile(rows, func(e *types.Event) bool {
+		return filter.Field == "" || changesField(e, filter.Field)
+	}, limit+1)
+	_ = rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	page := &AuditPage{Entries: []*AuditEntry{}}
+	if len(events) > limit {
+		events = events[:limit]
+		page.NextCursor = events[limit-1].ID
+	}
+	for _, e := range events {
+		page.Entries = append(page.Entries, NewAuditEntry(e))
+	}
+	return page, nil
+}
+
+// scanEventsWhile reads events that pass keep until max are collected
+func (s *SQLiteStorage) scanEventsWhile(rows *sql.Rows, keep func(*types.Event) bool, max int) ([]*types.Event, error) {
+	var events []*types.Event
+	for len(events) < max && rows.Next() {
+		event, err := s.scanEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		if keep(event) {
+			events = append(events, event)
+		}
+	}
+	return events, rows.Err()
+}
+
+func changesField(e *types.Event, field string) bool {
+	for _, c := range EventChanges(e) {
+		if c.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// NewAuditEntry describes an event with its field changes
+func NewAuditEntry(e *types.Event) *AuditEntry {
+	entry := &AuditEntry{
+		EventID:   e.ID,
+		IssueID:   e.IssueID,
+		EventType: e.EventType,
+		Actor:     e.Actor,
+		SessionID: e.SessionID,
+		CreatedAt: e.CreatedAt,
+		Changes:   EventChanges(e),
+	}
+	if e.Comment != nil {
+		entry.Comment = *e.Comment
+	}
+	return entry
+}
+
+// EventChanges works out which fields an event changed. Updates record the
+// whole issue before and the updated fields after, so only fields whose
+// value actually differs are reported, sorted by name. Creates report no
+// changes; closes report the new status.
+func EventChanges(e *types.Event) []FieldChange {
+	switch e.EventType {
+	case "renamed":
+		if e.OldValue != nil && e.NewValue != nil {
+			return []FieldChange{{Field: "id", Old: *e.OldValue, New: *e.NewValue}}
+		}
+		return nil
+	case types.EventCreated:
+		return nil
+	}
+
+	var before, after map[string]interface{}
+	if e.NewValue == nil || json.Unmarshal([]byte(*e.NewValue), &after) != nil {
+		if e.EventType == types.EventClosed {
+			return []FieldChange{{Field: "status", New: string(types.StatusClosed)}}
+		}
+		return nil
+	}
+	if e.OldValue != nil {
+		_ = json.Unmarshal([]byte(*e.OldValue), &before)
+	}
+
+	return diffFields(before, after)
+}
+
+// diffFields lists the fields in after whose values differ from before,
+// sorted by name
+func diffFields(before, after map[string]interface{}) []FieldChange {
+	var changes []FieldChange
+	for field, newValue := range after {
+		oldValue := before[field]
+		if reflect.DeepEqual(oldValue, newValue) || (isEmptyValue(oldValue) && isEmptyValue(newValue)) {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: field, Old: oldValue, New: newValue})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+// isEmptyValue treats a missing field and its zero value alike, since issue
+// JSON omits empty fields
+func isEmptyValue(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case float64:
+		return v == 0
+	case bool:
+		return !v
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+// sqliteDateTime formats t like SQLite's datetime(), for comparing against
+// timestamps stored in different formats
+func sqliteDateTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05")
+}
+
+// ParseAuditTime parses a point in time given as RFC 3339, a date
+// (2006-01-02), or an age before now such as "36h", "7d", or "2w"
+func ParseAuditTime(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return now.Add(-d), nil
+	}
+	if days, err := ParseRetentionAge(s); err == nil {
+		return now.AddDate(0, 0, -days), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (use RFC 3339, YYYY-MM-DD, or an age like 7d)", s)
+}
@@ -0,0 +1,119 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestQueryAuditLog(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	issue := &types.Issue{Title: "Flaky test", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeBug}
+	if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	other := &types.Issue{Title: "Other", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, other, "alice"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	// bob raises the priority and sets the title to what it already was
+	if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"priority": 0, "title": "Flaky test"}, "bob"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"assignee": "carol"}, "alice"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	if err := store.CloseIssue(ctx, issue.ID, "Fixed the race", "carol"); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+
+	page, err := store.QueryAuditLog(ctx, AuditFilter{Actor: "bob"})
+	if err != nil {
+		t.Fatalf("QueryAuditLog failed: %v", err)
+	}
+	if len(page.Entries) != 1 {
+		t.Fatalf("Expected 1 change by bob, got %d", len(page.Entries))
+	}
+	changes := page.Entries[0].Changes
+	if len(changes) != 1 || changes[0].Field != "priority" || changes[0].Old != float64(2) || changes[0].New != float64(0) {
+		t.Errorf("Expected only the priority change, got %+v", changes)
+	}
+
+	// Who changed the priority?
+	page, err = store.QueryAuditLog(ctx, AuditFilter{IssueID: issue.ID, Field: "priority"})
+	if err != nil {
+		t.Fatalf("QueryAuditLog failed: %v", err)
+	}
+	if len(page.Entries) != 1 || page.Entries[0].Actor != "bob" {
+		t.Errorf("Expected bob's priority change, got %+v", page.Entries)
+	}
+
+	page, _ = store.QueryAuditLog(ctx, AuditFilter{Actor: "carol"})
+	if len(page.Entries) != 1 || page.Entries[0].Comment != "Fixed the race" ||
+		len(page.Entries[0].Changes) != 1 || page.Entries[0].Changes[0].New != "closed" {
+		t.Errorf("Unexpected close entry %+v", page.Entries)
+	}
+
+	// Pages go newest first and end with no cursor
+	var seen []int64
+	filter := AuditFilter{IssueID: issue.ID, Limit: 2}
+	for {
+		page, err := store.QueryAuditLog(ctx, filter)
+		if err != nil {
+			t.Fatalf("QueryAuditLog failed: %v", err)
+		}
+		for _, e := range page.Entries {
+			seen = append(seen, e.EventID)
+		}
+		if page.NextCursor == 0 {
+			break
+		}
+		filter.Before = page.NextCursor
+	}
+	if len(seen) != 4 {
+		t.Fatalf("Expected 4 events for %s across pages, got %v", issue.ID, seen)
+	}
+	for i := 1; i < len(seen); i++ {
+		if seen[i] >= seen[i-1] {
+			t.Errorf("Expected newest first, got %v", seen)
+		}
+	}
+
+	future := time.Now().Add(time.Hour)
+	if page, _ := store.QueryAuditLog(ctx, AuditFilter{Since: &future}); len(page.Entries) != 0 {
+		t.Errorf("Expected nothing since the future, got %d", len(page.Entries))
+	}
+	past := time.Now().Add(-time.Hour)
+	if page, _ := store.QueryAuditLog(ctx, AuditFilter{Since: &past}); len(page.Entries) != 5 {
+		t.Errorf("Expected all 5 events in the last hour, got %d", len(page.Entries))
+	}
+}
+
+func TestParseAuditTime(t *testing.T) {
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	tests := map[string]time.Time{
+		"2025-06-01T08:00:00Z": time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC),
+		"36h":                  now.Add(-36 * time.Hour),
+		"7d":                   now.AddDate(0, 0, -7),
+		"2w":                   now.AddDate(0, 0, -14),
+	}
+	for input, want := range tests {
+		got, err := ParseAuditTime(input, now)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("ParseAuditTime(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+	if got, err := ParseAuditTime("2025-06-01", now); err != nil || got.Day() != 1 {
+		t.Errorf("ParseAuditTime(date) = %v, %v", got, err)
+	}
+	if _, err := ParseAuditTime("last tuesday", now); err == nil {
+		t.Error("Expected error for unparseable time")
+	}
+}
@@ -0,0 +1,179 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func createAuditTestIssue(t *testing.T, store *SQLiteStorage, title string) *types.Issue {
+	t.Helper()
+	issue := &types.Issue{
+		Title:     title,
+		Status:    types.StatusOpen,
+		Priority:  2,
+		IssueType: types.TypeTask,
+	}
+	if err := store.CreateIssue(context.Background(), issue, "alice"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	return issue
+}
+
+func TestSealAndVerifyAuditChain(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	issue := createAuditTestIssue(t, store, "Audited issue")
+	if err := store.AddComment(ctx, issue.ID, "bob", "looks good"); err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+
+	result, err := store.SealAuditChain(ctx)
+	if err != nil {
+		t.Fatalf("SealAuditChain failed: %v", err)
+	}
+	if result.Sealed != 2 {
+		t.Errorf("expected 2 sealed events, got %d", result.Sealed)
+	}
+
+	// Sealing again is a no-op
+	again, err := store.SealAuditChain(ctx)
+	if err != nil {
+		t.Fatalf("second SealAuditChain failed: %v", err)
+	}
+	if again.Sealed != 0 || again.HeadHash != result.HeadHash {
+		t.Errorf("expected idempotent seal, got %+v", again)
+	}
+
+	// New events are reported as unsealed but do not invalidate the chain
+	if err := store.AddLabel(ctx, issue.ID, "security", "bob"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+	verify, err := store.VerifyAuditChain(ctx)
+	if err != nil {
+		t.Fatalf("VerifyAuditChain failed: %v", err)
+	}
+	if !verify.Valid {
+		t.Fatalf("expected valid chain, got problems: %+v", verify.Problems)
+	}
+	if verify.Checked != 2 || verify.Unsealed != 1 {
+		t.Errorf("expected 2 checked / 1 unsealed, got %d / %d", verify.Checked, verify.Unsealed)
+	}
+}
+
+func TestVerifyAuditChainDetectsTampering(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper string
+		kind   string
+	}{
+		{"modified actor", `UPDATE events SET actor = 'mallory' WHERE event_type = 'commented'`, "modified"},
+		{"modified comment", `UPDATE events SET comment = 'rewritten' WHERE event_type = 'commented'`, "modified"},
+		{"deleted event", `DELETE FROM events WHERE event_type = 'commented'`, "deleted"},
+		{"rewritten chain", `UPDATE audit_chain SET event_hash = 'abc' WHERE seq = 1`, "broken_link"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, cleanup := setupTestDB(t)
+			defer cleanup()
+			ctx := context.Background()
+
+			issue := createAuditTestIssue(t, store, "Tamper target")
+			if err := store.AddComment(ctx, issue.ID, "bob", "original"); err != nil {
+				t.Fatalf("AddComment failed: %v", err)
+			}
+			if _, err := store.SealAuditChain(ctx); err != nil {
+				t.Fatalf("SealAuditChain failed: %v", err)
+			}
+
+			if _, err := store.UnderlyingDB().ExecContext(ctx, tt.tamper); err != nil {
+				t.Fatalf("tamper failed: %v", err)
+			}
+
+			verify, err := store.VerifyAuditChain(ctx)
+			if err != nil {
+				t.Fatalf("VerifyAuditChain failed: %v", err)
+			}
+			if verify.Valid {
+				t.Fatal("expected tampering to be detected")
+			}
+			found := false
+			for _, p := range verify.Problems {
+				if p.Kind == tt.kind {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected a %q problem, got %+v", tt.kind, verify.Problems)
+			}
+		})
+	}
+}
+
+func TestVerifyAuditChainDetectsBackdatedInsert(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	issue := createAuditTestIssue(t, store, "Backdate target")
+	if err := store.AddComment(ctx, issue.ID, "bob", "first"); err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+	if err := store.AddComment(ctx, issue.ID, "bob", "second"); err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+	if _, err := store.SealAuditChain(ctx); err != nil {
+		t.Fatalf("SealAuditChain failed: %v", err)
+	}
+
+	// Remove a sealed event and re-insert a forged one under the same ID
+	db := store.UnderlyingDB()
+	if _, err := db.ExecContext(ctx, `DELETE FROM audit_chain WHERE event_id = 2`); err != nil {
+		t.Fatalf("failed to drop chain entry: %v", err)
+	}
+
+	verify, err := store.VerifyAuditChain(ctx)
+	if err != nil {
+		t.Fatalf("VerifyAuditChain failed: %v", err)
+	}
+	if verify.Valid {
+		t.Fatal("expected verification failure")
+	}
+}
+
+func TestAuditSignatures(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	createAuditTestIssue(t, store, "Signed")
+	result, err := store.SealAuditChain(ctx)
+	if err != nil {
+		t.Fatalf("SealAuditChain failed: %v", err)
+	}
+
+	sig := &AuditSignature{HeadSeq: result.HeadSeq, HeadHash: result.HeadHash, Method: "gpg", Signature: "deadbeef"}
+	if err := store.AddAuditSignature(ctx, sig); err != nil {
+		t.Fatalf("AddAuditSignature failed: %v", err)
+	}
+
+	sigs, err := store.GetAuditSignatures(ctx)
+	if err != nil {
+		t.Fatalf("GetAuditSignatures failed: %v", err)
+	}
+	if len(sigs) != 1 || sigs[0].HeadHash != result.HeadHash {
+		t.Fatalf("unexpected signatures: %+v", sigs)
+	}
+
+	hash, err := store.GetAuditChainHash(ctx, result.HeadSeq)
+	if err != nil {
+		t.Fatalf("GetAuditChainHash failed: %v", err)
+	}
+	if hash != result.HeadHash {
+		t.Errorf("expected head hash %s, got %s", result.HeadHash, hash)
+	}
+}
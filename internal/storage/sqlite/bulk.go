@@ -0,0 +1,68 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// UpdateIssues applies the same updates to every issue matching filter in a
+// single transaction and returns the IDs of the updated issues, in the order
+// SearchIssues returns them. If any issue fails validation, none are updated.
+//
+// An empty filter is rejected so a missing filter can't rewrite every issue.
+func (s *SQLiteStorage) UpdateIssues(ctx context.Context, filter types.IssueFilter, updates map[string]interface{}, actor string) ([]string, error) {
+	if filter.IsEmpty() {
+		return nil, fmt.Errorf("bulk update requires a filter")
+	}
+	if len(updates) == 0 {
+		return nil, fmt.Errorf("no updates specified")
+	}
+
+	// Check free-text fields for pasted credentials once; every issue gets the same text
+	scan, err := s.newSecretScan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := scan.applyToUpdates(updates); err != nil {
+		return nil, err
+	}
+	if err := s.normalizeAssignee(ctx, updates); err != nil {
+		return nil, err
+	}
+
+	matches, err := s.SearchIssues(ctx, "", filter)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	ids := make([]string, 0, len(matches))
+	for _, oldIssue := range matches {
+		// closed_at depends on each issue's old status, so each gets its own copy
+		issueUpdates := make(map[string]interface{}, len(updates))
+		for k, v := range updates {
+			issueUpdates[k] = v
+		}
+
+		if err := s.updateIssueInTx(ctx, tx, oldIssue, issueUpdates, actor, 0); err != nil {
+			return nil, fmt.Errorf("failed to update %s: %w", oldIssue.ID, err)
+		}
+		if err := scan.record(ctx, tx, oldIssue.ID, actor); err != nil {
+			return nil, err
+		}
+		ids = append(ids, oldIssue.ID)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit bulk update: %w", err)
+	}
+
+	return ids, nil
+}
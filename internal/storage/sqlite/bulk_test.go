@@ -0,0 +1,156 @@
+package sqlite
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestUpdateIssues(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	var ids []string
+	for i, title := range []string{"Rotate certs", "Upgrade kernel", "Fix typo"} {
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: i, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "test-user"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		ids = append(ids, issue.ID)
+	}
+	for _, id := range ids[:2] {
+		if err := store.AddLabel(ctx, id, "infra", "test-user"); err != nil {
+			t.Fatalf("AddLabel failed: %v", err)
+		}
+	}
+	if err := store.ClearDirtyIssues(ctx); err != nil {
+		t.Fatalf("ClearDirtyIssues failed: %v", err)
+	}
+
+	open := types.StatusOpen
+	filter := types.IssueFilter{Status: &open, Labels: []string{"infra"}}
+	affected, err := store.UpdateIssues(ctx, filter, map[string]interface{}{"assignee": "bob"}, "alice")
+	if err != nil {
+		t.Fatalf("UpdateIssues failed: %v", err)
+	}
+	if len(affected) != 2 || affected[0] != ids[0] || affected[1] != ids[1] {
+		t.Fatalf("Expected affected %v, got %v", ids[:2], affected)
+	}
+
+	for i, id := range ids {
+		issue, err := store.GetIssue(ctx, id)
+		if err != nil {
+			t.Fatalf("GetIssue failed: %v", err)
+		}
+		want := ""
+		if i < 2 {
+			want = "bob"
+		}
+		if issue.Assignee != want {
+			t.Errorf("%s: expected assignee %q, got %q", id, want, issue.Assignee)
+		}
+	}
+
+	// Each updated issue gets its own event and is marked dirty
+	events, err := store.GetEvents(ctx, ids[0], 0)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	found := false
+	for _, e := range events {
+		if e.EventType == types.EventUpdated && e.Actor == "alice" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected an updated event by alice on %s", ids[0])
+	}
+	dirty, err := store.GetDirtyIssues(ctx)
+	if err != nil {
+		t.Fatalf("GetDirtyIssues failed: %v", err)
+	}
+	sort.Strings(dirty)
+	if len(dirty) != 2 || dirty[0] != ids[0] || dirty[1] != ids[1] {
+		t.Errorf("Expected dirty %v, got %v", ids[:2], dirty)
+	}
+}
+
+func TestUpdateIssuesClosesWithClosedAt(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	issue := &types.Issue{Title: "Stale task", Status: types.StatusOpen, Priority: 3, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "test-user"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	priority := 3
+	updates := map[string]interface{}{"status": string(types.StatusClosed)}
+	if _, err := store.UpdateIssues(ctx, types.IssueFilter{Priority: &priority}, updates, "test-user"); err != nil {
+		t.Fatalf("UpdateIssues failed: %v", err)
+	}
+
+	got, err := store.GetIssue(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if got.Status != types.StatusClosed || got.ClosedAt == nil {
+		t.Errorf("Expected closed issue with closed_at, got status %s closed_at %v", got.Status, got.ClosedAt)
+	}
+	if _, ok := updates["closed_at"]; ok {
+		t.Errorf("Caller's updates map should not be modified")
+	}
+}
+
+func TestUpdateIssuesIsAtomic(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	for _, title := range []string{"One", "Two"} {
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "test-user"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+
+	open := types.StatusOpen
+	filter := types.IssueFilter{Status: &open}
+	_, err := store.UpdateIssues(ctx, filter, map[string]interface{}{"assignee": "bob", "priority": 9}, "test-user")
+	if err == nil {
+		t.Fatal("Expected invalid priority to fail")
+	}
+
+	issues, err := store.SearchIssues(ctx, "", filter)
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+	for _, issue := range issues {
+		if issue.Assignee != "" {
+			t.Errorf("%s was partially updated: assignee %q", issue.ID, issue.Assignee)
+		}
+	}
+}
+
+func TestUpdateIssuesRequiresFilter(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	if _, err := store.UpdateIssues(ctx, types.IssueFilter{Limit: 10}, map[string]interface{}{"assignee": "bob"}, "test-user"); err == nil {
+		t.Error("Expected empty filter to be rejected")
+	}
+
+	open := types.StatusOpen
+	if _, err := store.UpdateIssues(ctx, types.IssueFilter{Status: &open}, map[string]interface{}{}, "test-user"); err == nil {
+		t.Error("Expected empty updates to be rejected")
+	}
+}
@@ -0,0 +1,515 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// Comments are threaded by reply_to, keep their earlier versions in
+// comment_edits when edited, and collect reactions in comment_reactions.
+// Adding one also records a commented event, so webhooks and WebSocket
+// clients, which watch the event log, hear about it; the event's new_value
+// names the comment, which tells it apart from comments made with AddComment
+// that only live in the event log.
+//
+// Writing a comment also records who it @mentions in comment_mentions, with
+// a mentioned event for each newly mentioned user, and links the issue to the
+// issues it mentions by ID.
+
+// commentEvent is the new_value of the event recorded for a comment
+type commentEvent struct {
+	CommentID int64 `json:"comment_id"`
+}
+
+// mentionEvent is the new_value of the event recorded when a comment
+// @mentions a user
+type mentionEvent struct {
+	CommentID int64  `json:"comment_id"`
+	Mentioned string `json:"mentioned"`
+}
+
+// AddIssueComment adds a comment to an issue
+func (s *SQLiteStorage) AddIssueComment(ctx context.Context, issueID, author, text string) (*types.Comment, error) {
+	return s.addComment(ctx, issueID, nil, author, text)
+}
+
+// ReplyToComment adds a comment answering another comment on the same issue
+func (s *SQLiteStorage) ReplyToComment(ctx context.Context, issueID string, replyTo int64, author, text string) (*types.Comment, error) {
+	return s.addComment(ctx, issueID, &replyTo, author, text)
+}
+
+func (s *SQLiteStorage) addComment(ctx context.Context, issueID string, replyTo *int64, author, text string) (*types.Comment, error) {
+	// Verify issue exists
+	var exists bool
+	err := s.reads.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM issues WHERE id = ?)`, issueID).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check issue existence: %w", err)
+	}
+	if !exists {
+		return nil, types.NotFound("issue", issueID)
+	}
+
+	// Check comment text for pasted credentials
+	scan, err := s.newSecretScan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if text, err = scan.apply("comment", text); err != nil {
+		return nil, err
+	}
+
+	// Encrypt comment text if field encryption is enabled
+	storedText, err := s.encryptField(text)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if replyTo != nil {
+		if _, err := readComment(ctx, tx, issueID, *replyTo); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO comments (issue_id, author, text, reply_to, created_at)
+		VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)
+	`, issueID, author, storedText, replyTo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert comment: %w", err)
+	}
+	commentID, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment ID: %w", err)
+	}
+
+	marker, err := json.Marshal(commentEvent{CommentID: commentID})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO events (issue_id, event_type, actor, new_value, comment, session_id)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, issueID, types.EventCommented, author, string(marker), storedText, s.eventSession(ctx)); err != nil {
+		return nil, fmt.Errorf("failed to record comment event: %w", err)
+	}
+
+	if err := recordMentions(ctx, tx, issueID, commentID, author, s.sessionID(ctx), text, storedText); err != nil {
+		return nil, err
+	}
+
+	// Record any detected secrets
+	if err := scan.record(ctx, tx, issueID, author); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit comment: %w", err)
+	}
+
+	// Mark issue as dirty for JSONL export
+	if err := s.MarkIssueDirty(ctx, issueID); err != nil {
+		return nil, fmt.Errorf("failed to mark issue dirty: %w", err)
+	}
+	if err := s.linkReferences(ctx, issueID, text, author); err != nil {
+		return nil, err
+	}
+
+	// Fetch the complete comment from the writer, since a replica may not
+	// have it yet
+	return s.getComment(ctx, s.db, issueID, commentID)
+}
+
+// GetIssueComments retrieves all comments for an issue, oldest first
+func (s *SQLiteStorage) GetIssueComments(ctx context.Context, issueID string) ([]*types.Comment, error) {
+	rows, err := s.reads.QueryContext(ctx, `
+		SELECT id, issue_id, author, text, created_at, reply_to, updated_at
+		FROM comments
+		WHERE issue_id = ?
+		ORDER BY created_at ASC, id ASC
+	`, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query comments: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var comments []*types.Comment
+	byID := make(map[int64]*types.Comment)
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comment.Text = s.decryptField(comment.Text)
+		comments = append(comments, comment)
+		byID[comment.ID] = comment
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating comments: %w", err)
+	}
+	if len(comments) == 0 {
+		return comments, nil
+	}
+
+	reactions, err := s.reads.QueryContext(ctx, `
+		SELECT r.comment_id, r.reaction, COUNT(*)
+		FROM comment_reactions r JOIN comments c ON c.id = r.comment_id
+		WHERE c.issue_id = ?
+		GROUP BY r.comment_id, r.reaction
+	`, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reactions: %w", err)
+	}
+	defer func() { _ = reactions.Close() }()
+	for reactions.Next() {
+		var commentID int64
+		var reaction string
+		var count int
+		if err := reactions.Scan(&commentID, &reaction, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan reaction: %w", err)
+		}
+		if comment := byID[commentID]; comment != nil {
+			if comment.Reactions == nil {
+				comment.Reactions = make(map[string]int)
+			}
+			comment.Reactions[reaction] = count
+		}
+	}
+	return comments, reactions.Err()
+}
+
+// UpdateIssueComment replaces a comment's text, keeping the old text in its
+// edit history
+func (s *SQLiteStorage) UpdateIssueComment(ctx context.Context, issueID string, commentID int64, editor, text string) (*types.Comment, error) {
+	scan, err := s.newSecretScan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if text, err = scan.apply("comment", text); err != nil {
+		return nil, err
+	}
+	storedText, err := s.encryptField(text)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	old, err := readComment(ctx, tx, issueID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if s.decryptField(old.Text) == text {
+		return s.getComment(ctx, tx, issueID, commentID)
+	}
+
+	now := time.Now()
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO comment_edits (comment_id, text, edited_by, edited_at)
+		VALUES (?, ?, ?, ?)
+	`, commentID, old.Text, editor, now); err != nil {
+		return nil, fmt.Errorf("failed to record comment edit: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE comments SET text = ?, updated_at = ? WHERE id = ?`, storedText, now, commentID); err != nil {
+		return nil, fmt.Errorf("failed to update comment: %w", err)
+	}
+	if err := recordMentions(ctx, tx, issueID, commentID, editor, s.sessionID(ctx), text, storedText); err != nil {
+		return nil, err
+	}
+	if err := scan.record(ctx, tx, issueID, editor); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit comment edit: %w", err)
+	}
+
+	if err := s.MarkIssueDirty(ctx, issueID); err != nil {
+		return nil, fmt.Errorf("failed to mark issue dirty: %w", err)
+	}
+	if err := s.linkReferences(ctx, issueID, text, editor); err != nil {
+		return nil, err
+	}
+	return s.getComment(ctx, s.db, issueID, commentID)
+}
+
+// DeleteIssueComment removes a comment with its edits and reactions. Its
+// replies move up to answer the comment it replied to, if any.
+func (s *SQLiteStorage) DeleteIssueComment(ctx context.Context, issueID string, commentID int64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	comment, err := readComment(ctx, tx, issueID, commentID)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE comments SET reply_to = ? WHERE reply_to = ?`, comment.ReplyTo, commentID); err != nil {
+		return fmt.Errorf("failed to move replies: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM comments WHERE id = ?`, commentID); err != nil {
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit comment deletion: %w", err)
+	}
+
+	if err := s.MarkIssueDirty(ctx, issueID); err != nil {
+		return fmt.Errorf("failed to mark issue dirty: %w", err)
+	}
+	return nil
+}
+
+// GetCommentEdits returns a comment's earlier versions, oldest first
+func (s *SQLiteStorage) GetCommentEdits(ctx context.Context, issueID string, commentID int64) ([]*types.CommentEdit, error) {
+	if _, err := s.getComment(ctx, s.reads, issueID, commentID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.reads.QueryContext(ctx, `
+		SELECT comment_id, text, edited_by, edited_at
+		FROM comment_edits
+		WHERE comment_id = ?
+		ORDER BY id
+	`, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query comment edits: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	edits := []*types.CommentEdit{}
+	for rows.Next() {
+		edit := &types.CommentEdit{}
+		if err := rows.Scan(&edit.CommentID, &edit.Text, &edit.EditedBy, &edit.EditedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan comment edit: %w", err)
+		}
+		edit.Text = s.decryptField(edit.Text)
+		edits = append(edits, edit)
+	}
+	return edits, rows.Err()
+}
+
+// AddCommentReaction records an actor's reaction to a comment; reacting the
+// same way twice is a no-op
+func (s *SQLiteStorage) AddCommentReaction(ctx context.Context, issueID string, commentID int64, actor, reaction string) (*types.Comment, error) {
+	if err := types.ValidateReaction(reaction); err != nil {
+		return nil, err
+	}
+	if _, err := s.getComment(ctx, s.db, issueID, commentID); err != nil {
+		return nil, err
+	}
+	if _, err := s.db.ExecContext(ctx, `
+		INSERT INTO comment_reactions (comment_id, reaction, actor, created_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT DO NOTHING
+	`, commentID, reaction, actor, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to add reaction: %w", err)
+	}
+	if err := s.MarkIssueDirty(ctx, issueID); err != nil {
+		return nil, fmt.Errorf("failed to mark issue dirty: %w", err)
+	}
+	return s.getComment(ctx, s.db, issueID, commentID)
+}
+
+// RemoveCommentReaction takes back an actor's reaction to a comment
+func (s *SQLiteStorage) RemoveCommentReaction(ctx context.Context, issueID string, commentID int64, actor, reaction string) (*types.Comment, error) {
+	if _, err := s.getComment(ctx, s.db, issueID, commentID); err != nil {
+		return nil, err
+	}
+	if _, err := s.db.ExecContext(ctx, `
+		DELETE FROM comment_reactions WHERE comment_id = ? AND reaction = ? AND actor = ?
+	`, commentID, reaction, actor); err != nil {
+		return nil, fmt.Errorf("failed to remove reaction: %w", err)
+	}
+	if err := s.MarkIssueDirty(ctx, issueID); err != nil {
+		return nil, fmt.Errorf("failed to mark issue dirty: %w", err)
+	}
+	return s.getComment(ctx, s.db, issueID, commentID)
+}
+
+// GetMentions returns the comments that @mention user, newest first
+func (s *SQLiteStorage) GetMentions(ctx context.Context, user string) ([]*types.Mention, error) {
+	rows, err := s.reads.QueryContext(ctx, `
+		SELECT c.id, c.issue_id, c.author, c.text, c.created_at, c.reply_to, c.updated_at, i.title
+		FROM comment_mentions m
+		JOIN comments c ON c.id = m.comment_id
+		JOIN issues i ON i.id = c.issue_id
+		WHERE m.user = ? AND i.deleted_at IS NULL
+		ORDER BY c.created_at DESC, c.id DESC
+	`, user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query mentions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	mentions := []*types.Mention{}
+	for rows.Next() {
+		mention := &types.Mention{User: user}
+		if mention.Comment, err = scanComment(rows, &mention.IssueTitle); err != nil {
+			return nil, err
+		}
+		mention.Text = s.decryptField(mention.Text)
+		mentions = append(mentions, mention)
+	}
+	return mentions, rows.Err()
+}
+
+// recordMentions brings a comment's mention records in line with its text,
+// recording a mentioned event for each user it newly mentions other than the
+// writer. The event carries the comment's text as stored.
+func recordMentions(ctx context.Context, tx *sql.Tx, issueID string, commentID int64, writer, session, text, storedText string) error {
+	rows, err := tx.QueryContext(ctx, `SELECT user FROM comment_mentions WHERE comment_id = ?`, commentID)
+	if err != nil {
+		return fmt.Errorf("failed to query mentions: %w", err)
+	}
+	had := make(map[string]bool)
+	for rows.Next() {
+		var user string
+		if err := rows.Scan(&user); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("failed to scan mention: %w", err)
+		}
+		had[user] = true
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to query mentions: %w", err)
+	}
+
+	for _, user := range types.CommentMentions(text) {
+		if had[user] {
+			delete(had, user)
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO comment_mentions (comment_id, user) VALUES (?, ?)`, commentID, user); err != nil {
+			return fmt.Errorf("failed to record mention: %w", err)
+		}
+		if user == writer {
+			continue
+		}
+		payload, err := json.Marshal(mentionEvent{CommentID: commentID, Mentioned: user})
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO events (issue_id, event_type, actor, new_value, comment, session_id)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, issueID, types.EventMentioned, writer, string(payload), storedText, nullSession(session)); err != nil {
+			return fmt.Errorf("failed to record mention event: %w", err)
+		}
+	}
+	// Whoever is left is no longer mentioned
+	for user := range had {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM comment_mentions WHERE comment_id = ? AND user = ?`, commentID, user); err != nil {
+			return fmt.Errorf("failed to remove mention: %w", err)
+		}
+	}
+	return nil
+}
+
+// linkReferences links an issue as related to each existing issue a comment
+// on it mentions by ID, unless the two are already linked either way
+func (s *SQLiteStorage) linkReferences(ctx context.Context, issueID, text, actor string) error {
+	for _, id := range types.CommentReferences(text) {
+		if id == issueID {
+			continue
+		}
+		var linkable bool
+		if err := s.db.QueryRowContext(ctx, `
+			SELECT EXISTS(SELECT 1 FROM issues WHERE id = ? AND deleted_at IS NULL)
+			AND NOT EXISTS(
+				SELECT 1 FROM dependencies
+				WHERE (issue_id = ? AND depends_on_id = ?) OR (issue_id = ? AND depends_on_id = ?)
+			)
+		`, id, issueID, id, id, issueID).Scan(&linkable); err != nil {
+			return fmt.Errorf("failed to check link to %s: %w", id, err)
+		}
+		if !linkable {
+			continue
+		}
+		dep := &types.Dependency{IssueID: issueID, DependsOnID: id, Type: types.DepRelated}
+		if err := s.AddDependency(ctx, dep, actor); err != nil {
+			return fmt.Errorf("failed to link %s to %s: %w", issueID, id, err)
+		}
+	}
+	return nil
+}
+
+// getComment reads a comment with its text decrypted and its reactions
+// counted, or fails if the issue has no such comment
+func (s *SQLiteStorage) getComment(ctx context.Context, q dbQueryer, issueID string, commentID int64) (*types.Comment, error) {
+	comment, err := readComment(ctx, q, issueID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	comment.Text = s.decryptField(comment.Text)
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT reaction, COUNT(*) FROM comment_reactions WHERE comment_id = ? GROUP BY reaction
+	`, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reactions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var reaction string
+		var count int
+		if err := rows.Scan(&reaction, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan reaction: %w", err)
+		}
+		if comment.Reactions == nil {
+			comment.Reactions = make(map[string]int)
+		}
+		comment.Reactions[reaction] = count
+	}
+	return comment, rows.Err()
+}
+
+// readComment reads a comment with its text as stored and no reactions
+func readComment(ctx context.Context, q dbQueryer, issueID string, commentID int64) (*types.Comment, error) {
+	comment, err := scanComment(q.QueryRowContext(ctx, `
+		SELECT id, issue_id, author, text, created_at, reply_to, updated_at
+		FROM comments WHERE id = ? AND issue_id = ?
+	`, commentID, issueID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("comment %d not found on %s", commentID, issueID)
+	}
+	return comment, err
+}
+
+// scanComment scans a comment's columns, in the order readComment selects
+// them, followed by any extra columns into extra
+func scanComment(row rowScanner, extra ...interface{}) (*types.Comment, error) {
+	comment := &types.Comment{}
+	var replyTo sql.NullInt64
+	var updatedAt sql.NullTime
+	dest := append([]interface{}{&comment.ID, &comment.IssueID, &comment.Author, &comment.Text, &comment.CreatedAt, &replyTo, &updatedAt}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan comment: %w", err)
+	}
+	if replyTo.Valid {
+		comment.ReplyTo = &replyTo.Int64
+	}
+	if updatedAt.Valid {
+		comment.UpdatedAt = &updatedAt.Time
+	}
+	return comment, nil
+}
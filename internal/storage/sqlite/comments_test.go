@@ -0,0 +1,253 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestCommentThreadsEditsAndReactions(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	issue := &types.Issue{Title: "Discuss", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	other := &types.Issue{Title: "Elsewhere", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	for _, i := range []*types.Issue{issue, other} {
+		if err := store.CreateIssue(ctx, i, "alice"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+
+	first, err := store.AddIssueComment(ctx, issue.ID, "alice", "Ship it?")
+	if err != nil {
+		t.Fatalf("AddIssueComment failed: %v", err)
+	}
+	reply, err := store.ReplyToComment(ctx, issue.ID, first.ID, "bob", "Not yet")
+	if err != nil {
+		t.Fatalf("ReplyToComment failed: %v", err)
+	}
+	if reply.ReplyTo == nil || *reply.ReplyTo != first.ID {
+		t.Fatalf("Expected reply to #%d, got %v", first.ID, reply.ReplyTo)
+	}
+	nested, err := store.ReplyToComment(ctx, issue.ID, reply.ID, "alice", "Why not?")
+	if err != nil {
+		t.Fatalf("ReplyToComment failed: %v", err)
+	}
+	if _, err := store.ReplyToComment(ctx, other.ID, first.ID, "bob", "Wrong issue"); err == nil {
+		t.Error("Expected replying to a comment on another issue to fail")
+	}
+
+	t.Run("edit keeps history", func(t *testing.T) {
+		edited, err := store.UpdateIssueComment(ctx, issue.ID, first.ID, "carol", "Ship it today?")
+		if err != nil {
+			t.Fatalf("UpdateIssueComment failed: %v", err)
+		}
+		if edited.Text != "Ship it today?" || edited.UpdatedAt == nil {
+			t.Errorf("Expected edited text and updated_at, got %q %v", edited.Text, edited.UpdatedAt)
+		}
+		if edited.Author != "alice" {
+			t.Errorf("Editing should keep the author, got %s", edited.Author)
+		}
+		// Saving the same text again isn't an edit
+		if _, err := store.UpdateIssueComment(ctx, issue.ID, first.ID, "carol", "Ship it today?"); err != nil {
+			t.Fatalf("UpdateIssueComment failed: %v", err)
+		}
+
+		edits, err := store.GetCommentEdits(ctx, issue.ID, first.ID)
+		if err != nil {
+			t.Fatalf("GetCommentEdits failed: %v", err)
+		}
+		if len(edits) != 1 || edits[0].Text != "Ship it?" || edits[0].EditedBy != "carol" {
+			t.Fatalf("Expected one edit replacing the original text, got %+v", edits)
+		}
+		if _, err := store.UpdateIssueComment(ctx, other.ID, first.ID, "carol", "x"); err == nil {
+			t.Error("Expected editing through the wrong issue to fail")
+		}
+	})
+
+	t.Run("reactions count actors once", func(t *testing.T) {
+		for _, actor := range []string{"bob", "bob", "carol"} {
+			if _, err := store.AddCommentReaction(ctx, issue.ID, first.ID, actor, "👍"); err != nil {
+				t.Fatalf("AddCommentReaction failed: %v", err)
+			}
+		}
+		comment, err := store.AddCommentReaction(ctx, issue.ID, first.ID, "bob", "🎉")
+		if err != nil {
+			t.Fatalf("AddCommentReaction failed: %v", err)
+		}
+		if comment.Reactions["👍"] != 2 || comment.Reactions["🎉"] != 1 {
+			t.Errorf("Expected 👍 2 and 🎉 1, got %v", comment.Reactions)
+		}
+		comment, err = store.RemoveCommentReaction(ctx, issue.ID, first.ID, "bob", "🎉")
+		if err != nil {
+			t.Fatalf("RemoveCommentReaction failed: %v", err)
+		}
+		if _, ok := comment.Reactions["🎉"]; ok {
+			t.Errorf("Expected 🎉 to be gone, got %v", comment.Reactions)
+		}
+		if _, err := store.AddCommentReaction(ctx, issue.ID, first.ID, "bob", "thumbs up"); err == nil {
+			t.Error("Expected a reaction with whitespace to be rejected")
+		}
+	})
+
+	t.Run("delete moves replies up", func(t *testing.T) {
+		if err := store.DeleteIssueComment(ctx, issue.ID, reply.ID); err != nil {
+			t.Fatalf("DeleteIssueComment failed: %v", err)
+		}
+		comments, err := store.GetIssueComments(ctx, issue.ID)
+		if err != nil {
+			t.Fatalf("GetIssueComments failed: %v", err)
+		}
+		if len(comments) != 2 {
+			t.Fatalf("Expected 2 comments, got %d", len(comments))
+		}
+		if comments[1].ID != nested.ID || comments[1].ReplyTo == nil || *comments[1].ReplyTo != first.ID {
+			t.Errorf("Expected #%d to now answer #%d, got %+v", nested.ID, first.ID, comments[1])
+		}
+		if comments[0].Reactions["👍"] != 2 {
+			t.Errorf("Expected listed comments to carry reaction counts, got %v", comments[0].Reactions)
+		}
+		if err := store.DeleteIssueComment(ctx, issue.ID, reply.ID); err == nil {
+			t.Error("Expected deleting a deleted comment to fail")
+		}
+	})
+}
+
+func TestCommentEventsStayOutOfActivity(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	issue := &types.Issue{Title: "Discuss", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	comment, err := store.AddIssueComment(ctx, issue.ID, "bob", "looks good")
+	if err != nil {
+		t.Fatalf("AddIssueComment failed: %v", err)
+	}
+
+	// Webhooks and the WebSocket hub see the comment as an event naming it
+	events, err := store.GetEvents(ctx, issue.ID, 0)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	var found *types.Event
+	for _, e := range events {
+		if e.EventType == types.EventCommented {
+			found = e
+		}
+	}
+	if found == nil || found.NewValue == nil {
+		t.Fatalf("Expected a commented event naming the comment, got %+v", events)
+	}
+	var ref struct {
+		CommentID int64 `json:"comment_id"`
+	}
+	if err := json.Unmarshal([]byte(*found.NewValue), &ref); err != nil || ref.CommentID != comment.ID {
+		t.Errorf("Expected the event to name comment #%d, got %s", comment.ID, *found.NewValue)
+	}
+
+	// The activity feed lists comments from the comments table, so the event isn't repeated
+	activity, err := store.GetActivity(ctx, ActivityFilter{})
+	if err != nil {
+		t.Fatalf("GetActivity failed: %v", err)
+	}
+	commented := 0
+	for _, a := range activity {
+		if a.Kind == ActivityCommented {
+			commented++
+		}
+	}
+	if commented != 1 {
+		t.Errorf("Expected the comment once in the activity feed, got %d", commented)
+	}
+}
+
+func TestCommentMentionsAndReferences(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	issue := &types.Issue{Title: "Login broken", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeBug}
+	other := &types.Issue{Title: "Session store", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	for _, i := range []*types.Issue{issue, other} {
+		if err := store.CreateIssue(ctx, i, "alice"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+
+	text := "@bob @alice probably " + other.ID + ", not " + issue.ID + " or bd-999; `@carol` is code"
+	comment, err := store.AddIssueComment(ctx, issue.ID, "alice", text)
+	if err != nil {
+		t.Fatalf("AddIssueComment failed: %v", err)
+	}
+
+	mentioned := func() []string {
+		t.Helper()
+		events, err := store.GetEvents(ctx, issue.ID, 0)
+		if err != nil {
+			t.Fatalf("GetEvents failed: %v", err)
+		}
+		var users []string
+		for _, e := range events {
+			if e.EventType != types.EventMentioned {
+				continue
+			}
+			var m mentionEvent
+			if err := json.Unmarshal([]byte(*e.NewValue), &m); err != nil || m.CommentID != comment.ID {
+				t.Errorf("Unexpected mention event %s", *e.NewValue)
+			}
+			users = append(users, m.Mentioned)
+		}
+		return users
+	}
+	// alice mentioning herself doesn't notify her
+	if users := mentioned(); len(users) != 1 || users[0] != "bob" {
+		t.Errorf("Expected a mentioned event for bob only, got %v", users)
+	}
+	for user, want := range map[string]int{"bob": 1, "alice": 1, "carol": 0} {
+		mentions, err := store.GetMentions(ctx, user)
+		if err != nil {
+			t.Fatalf("GetMentions failed: %v", err)
+		}
+		if len(mentions) != want {
+			t.Errorf("Expected %d mentions of %s, got %d", want, user, len(mentions))
+		} else if want > 0 && (mentions[0].ID != comment.ID || mentions[0].IssueTitle != "Login broken" || mentions[0].Text != text) {
+			t.Errorf("Unexpected mention %+v", mentions[0])
+		}
+	}
+
+	deps, err := store.GetDependencyRecords(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetDependencyRecords failed: %v", err)
+	}
+	if len(deps) != 1 || deps[0].DependsOnID != other.ID || deps[0].Type != types.DepRelated {
+		t.Fatalf("Expected one related link to %s, got %+v", other.ID, deps)
+	}
+
+	// Editing notifies only the newly mentioned, and doesn't link twice
+	if _, err := store.UpdateIssueComment(ctx, issue.ID, comment.ID, "alice", "@dave @bob see "+other.ID); err != nil {
+		t.Fatalf("UpdateIssueComment failed: %v", err)
+	}
+	if users := mentioned(); len(users) != 2 || users[1] != "dave" {
+		t.Errorf("Expected dave notified after the edit, got %v", users)
+	}
+	if mentions, _ := store.GetMentions(ctx, "alice"); len(mentions) != 0 {
+		t.Errorf("Expected alice's mention gone with the text, got %d", len(mentions))
+	}
+	if deps, _ := store.GetDependencyRecords(ctx, issue.ID); len(deps) != 1 {
+		t.Errorf("Expected the link kept once, got %+v", deps)
+	}
+
+	// A reply on the other issue doesn't link back over the existing link
+	if _, err := store.AddIssueComment(ctx, other.ID, "bob", "Blocks "+issue.ID); err != nil {
+		t.Fatalf("AddIssueComment failed: %v", err)
+	}
+	if deps, _ := store.GetDependencyRecords(ctx, other.ID); len(deps) != 0 {
+		t.Errorf("Expected no link back, got %+v", deps)
+	}
+}
@@ -0,0 +1,67 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// CommitLink records a git commit that mentions an issue. Messages are kept
+// in plain text since they're already in the repository's history.
+type CommitLink struct {
+	IssueID     string     `json:"issue_id"`
+	SHA         string     `json:"sha"`
+	Message     string     `json:"message"`
+	Author      string     `json:"author,omitempty"`
+	URL         string     `json:"url,omitempty"`
+	CommittedAt *time.Time `json:"committed_at,omitempty"`
+	LinkedAt    time.Time  `json:"linked_at"`
+}
+
+// AddCommitLink links a commit to an issue and sets LinkedAt. It reports
+// false without changing anything if the commit was already linked.
+func (s *SQLiteStorage) AddCommitLink(ctx context.Context, link *CommitLink) (bool, error) {
+	if link.IssueID == "" || link.SHA == "" {
+		return false, fmt.Errorf("commit link requires an issue ID and commit SHA")
+	}
+
+	link.LinkedAt = time.Now()
+	result, err := s.db.ExecContext(ctx, `
+		INSERT OR IGNORE INTO issue_commits (issue_id, sha, message, author, url, committed_at, linked_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, link.IssueID, link.SHA, link.Message, link.Author, link.URL, link.CommittedAt, link.LinkedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to link commit: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return n > 0, nil
+}
+
+// GetCommitLinks returns the commits linked to an issue, oldest first
+func (s *SQLiteStorage) GetCommitLinks(ctx context.Context, issueID string) ([]*CommitLink, error) {
+	rows, err := s.reads.QueryContext(ctx, `
+		SELECT issue_id, sha, message, author, url, committed_at, linked_at
+		FROM issue_commits
+		WHERE issue_id = ?
+		ORDER BY COALESCE(committed_at, linked_at), sha
+	`, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit links: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var links []*CommitLink
+	for rows.Next() {
+		var link CommitLink
+		var committedAt sql.NullTime
+		if err := rows.Scan(&link.IssueID, &link.SHA, &link.Message, &link.Author, &link.URL, &committedAt, &link.LinkedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan commit link: %w", err)
+		}
+		if committedAt.Valid {
+			link.CommittedAt = &committedAt.Time
+		}
+		links = append(links, &link)
+	}
+	return links, rows.Err()
+}
@@ -0,0 +1,70 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestCommitLinks(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	issue := &types.Issue{Title: "Fix it", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeBug}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	later := time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC)
+	earlier := later.Add(-time.Hour)
+	added, err := store.AddCommitLink(ctx, &CommitLink{IssueID: issue.ID, SHA: "bbbb", Message: "second", CommittedAt: &later})
+	if err != nil || !added {
+		t.Fatalf("AddCommitLink failed: added=%v err=%v", added, err)
+	}
+	if _, err := store.AddCommitLink(ctx, &CommitLink{IssueID: issue.ID, SHA: "aaaa", Message: "first", CommittedAt: &earlier}); err != nil {
+		t.Fatalf("AddCommitLink failed: %v", err)
+	}
+
+	// Linking the same commit again is a no-op
+	added, err = store.AddCommitLink(ctx, &CommitLink{IssueID: issue.ID, SHA: "bbbb", Message: "changed"})
+	if err != nil || added {
+		t.Errorf("Expected duplicate link to be ignored: added=%v err=%v", added, err)
+	}
+
+	links, err := store.GetCommitLinks(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetCommitLinks failed: %v", err)
+	}
+	if len(links) != 2 || links[0].SHA != "aaaa" || links[1].SHA != "bbbb" {
+		t.Fatalf("Expected links oldest first, got %+v", links)
+	}
+	if links[1].Message != "second" || links[1].CommittedAt == nil || !links[1].CommittedAt.Equal(later) {
+		t.Errorf("Unexpected link %+v", links[1])
+	}
+
+	if _, err := store.AddCommitLink(ctx, &CommitLink{IssueID: issue.ID}); err == nil {
+		t.Error("Expected error for missing SHA")
+	}
+
+	// Renaming the issue carries its links
+	issue.ID = "bd-renamed"
+	oldID := links[0].IssueID
+	if err := store.UpdateIssueID(ctx, oldID, issue.ID, issue, "test"); err != nil {
+		t.Fatalf("UpdateIssueID failed: %v", err)
+	}
+	if links, _ := store.GetCommitLinks(ctx, issue.ID); len(links) != 2 {
+		t.Errorf("Expected 2 links after rename, got %d", len(links))
+	}
+
+	// Deleting the issue drops its links
+	if err := store.DeleteIssue(ctx, issue.ID); err != nil {
+		t.Fatalf("DeleteIssue failed: %v", err)
+	}
+	if links, _ := store.GetCommitLinks(ctx, issue.ID); len(links) != 0 {
+		t.Errorf("Expected no links after delete, got %d", len(links))
+	}
+}
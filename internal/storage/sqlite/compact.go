@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/imalsogreg/beads/internal/types"
@@ -25,6 +26,87 @@ type CompactionCandidate struct {
 // - No open dependents within compact_tier1_dep_levels depth
 // - Not already compacted (compaction_level = 0)
 func (s *SQLiteStorage) GetTier1Candidates(ctx context.Context) ([]*CompactionCandidate, error) {
+	return s.tier1Candidates(ctx, "", 0)
+}
+
+// ForEachCandidatePage calls fn with the candidates for compaction at tier
+// (1 or 2), as GetTier1Candidates and GetTier2Candidates list them, a page
+// of up to a few hundred at a time. Each page resumes after the last
+// candidate of the one before, and no connection is held while fn runs, so
+// fn may compact the page; its issues leave the tier as they're compacted.
+func (s *SQLiteStorage) ForEachCandidatePage(ctx context.Context, tier int, fn func([]*CompactionCandidate) error) error {
+	if tier != 1 && tier != 2 {
+		return fmt.Errorf("invalid tier: %d (must be 1 or 2)", tier)
+	}
+	after := ""
+	for {
+		page, err := s.candidatesAfter(ctx, tier, after)
+		if err != nil {
+			return err
+		}
+		if len(page) > 0 {
+			if err := fn(page); err != nil {
+				return err
+			}
+		}
+		if len(page) < idBatchSize {
+			return nil
+		}
+		after = page[len(page)-1].IssueID
+	}
+}
+
+// isCandidate reports whether issueID is a candidate for compaction at
+// tier, reading the candidates a page at a time until it turns up
+func (s *SQLiteStorage) isCandidate(ctx context.Context, tier int, issueID string) (bool, error) {
+	after := ""
+	for {
+		page, err := s.candidatesAfter(ctx, tier, after)
+		if err != nil {
+			return false, err
+		}
+		for _, c := range page {
+			if c.IssueID == issueID {
+				return true, nil
+			}
+		}
+		if len(page) < idBatchSize {
+			return false, nil
+		}
+		after = page[len(page)-1].IssueID
+	}
+}
+
+// candidatesAfter returns the page of candidates at tier that follows the
+// candidate with id after
+func (s *SQLiteStorage) candidatesAfter(ctx context.Context, tier int, after string) ([]*CompactionCandidate, error) {
+	if tier == 1 {
+		return s.tier1Candidates(ctx, after, idBatchSize)
+	}
+	return s.tier2Candidates(ctx, after, idBatchSize)
+}
+
+// candidatePage returns the SQL that limits a candidate query to the page
+// after the candidate with id after (none if empty), oldest closed first,
+// at most limit long (unlimited if 0): a WHERE condition and the ORDER BY
+// and LIMIT clauses, with the condition's arguments and the limit's.
+func candidatePage(after string, limit int) (string, string, []interface{}, []interface{}) {
+	where := ""
+	var whereArgs, limitArgs []interface{}
+	if after != "" {
+		where = "AND (i.closed_at, i.id) > (SELECT closed_at, id FROM issues WHERE id = ?)"
+		whereArgs = append(whereArgs, after)
+	}
+	order := "ORDER BY i.closed_at ASC, i.id ASC"
+	if limit > 0 {
+		order += " LIMIT ?"
+		limitArgs = append(limitArgs, limit)
+	}
+	return where, order, whereArgs, limitArgs
+}
+
+// tier1Candidates returns a page of GetTier1Candidates (see candidatePage)
+func (s *SQLiteStorage) tier1Candidates(ctx context.Context, after string, limit int) ([]*CompactionCandidate, error) {
 	// Get configuration
 	daysStr, err := s.GetConfig(ctx, "compact_tier1_days")
 	if err != nil {
@@ -42,7 +124,9 @@ func (s *SQLiteStorage) GetTier1Candidates(ctx context.Context) ([]*CompactionCa
 		depthStr = "2"
 	}
 
-	query := `
+	pageWhere, pageOrder, whereArgs, limitArgs := candidatePage(after, limit)
+	// #nosec G201 - only the fixed paging clauses are interpolated
+	query := fmt.Sprintf(`
 		WITH RECURSIVE
 		  -- Find all issues that depend on (are blocked by) other issues
 		  dependent_tree AS (
@@ -78,18 +162,21 @@ func (s *SQLiteStorage) GetTier1Candidates(ctx context.Context) ([]*CompactionCa
 		  COUNT(DISTINCT dt.dependent_id) as dependent_count
 		FROM issues i
 		LEFT JOIN dependent_tree dt ON i.id = dt.issue_id 
-		  AND dt.dependent_status IN ('open', 'in_progress', 'blocked')
+		  AND dt.dependent_status != 'closed'
 		  AND dt.depth <= ?
 		WHERE i.status = 'closed'
 		  AND i.closed_at IS NOT NULL
 		  AND i.closed_at <= datetime('now', '-' || CAST(? AS INTEGER) || ' days')
+		  AND i.deleted_at IS NULL
 		  AND COALESCE(i.compaction_level, 0) = 0
 		  AND dt.dependent_id IS NULL  -- No open dependents
+		  %s
 		GROUP BY i.id
-		ORDER BY i.closed_at ASC
-	`
+		%s
+	`, pageWhere, pageOrder)
 
-	rows, err := s.db.QueryContext(ctx, query, depthStr, depthStr, daysStr)
+	args := append([]interface{}{depthStr, depthStr, daysStr}, whereArgs...)
+	rows, err := s.reads.QueryContext(ctx, query, append(args, limitArgs...)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query tier1 candidates: %w", err)
 	}
@@ -119,6 +206,11 @@ func (s *SQLiteStorage) GetTier1Candidates(ctx context.Context) ([]*CompactionCa
 // - Already at compaction_level = 1
 // - Either has many commits (compact_tier2_commits) or many dependent issues
 func (s *SQLiteStorage) GetTier2Candidates(ctx context.Context) ([]*CompactionCandidate, error) {
+	return s.tier2Candidates(ctx, "", 0)
+}
+
+// tier2Candidates returns a page of GetTier2Candidates (see candidatePage)
+func (s *SQLiteStorage) tier2Candidates(ctx context.Context, after string, limit int) ([]*CompactionCandidate, error) {
 	// Get configuration
 	daysStr, err := s.GetConfig(ctx, "compact_tier2_days")
 	if err != nil {
@@ -136,7 +228,9 @@ func (s *SQLiteStorage) GetTier2Candidates(ctx context.Context) ([]*CompactionCa
 		commitsStr = "100"
 	}
 
-	query := `
+	pageWhere, pageOrder, whereArgs, limitArgs := candidatePage(after, limit)
+	// #nosec G201 - only the fixed paging clauses are interpolated
+	query := fmt.Sprintf(`
 		WITH event_counts AS (
 		  SELECT issue_id, COUNT(*) as event_count
 		  FROM events
@@ -153,6 +247,7 @@ func (s *SQLiteStorage) GetTier2Candidates(ctx context.Context) ([]*CompactionCa
 		WHERE i.status = 'closed'
 		  AND i.closed_at IS NOT NULL
 		  AND i.closed_at <= datetime('now', '-' || CAST(? AS INTEGER) || ' days')
+		  AND i.deleted_at IS NULL
 		  AND i.compaction_level = 1
 		  AND COALESCE(ec.event_count, 0) >= CAST(? AS INTEGER)
 		  AND NOT EXISTS (
@@ -161,12 +256,14 @@ func (s *SQLiteStorage) GetTier2Candidates(ctx context.Context) ([]*CompactionCa
 		    JOIN issues dep ON d.issue_id = dep.id
 		    WHERE d.depends_on_id = i.id
 		      AND d.type = 'blocks'
-		      AND dep.status IN ('open', 'in_progress', 'blocked')
+		      AND dep.status != 'closed'
 		  )
-		ORDER BY i.closed_at ASC
-	`
+		  %s
+		%s
+	`, pageWhere, pageOrder)
 
-	rows, err := s.db.QueryContext(ctx, query, daysStr, commitsStr)
+	args := append([]interface{}{daysStr, commitsStr}, whereArgs...)
+	rows, err := s.reads.QueryContext(ctx, query, append(args, limitArgs...)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query tier2 candidates: %w", err)
 	}
@@ -197,7 +294,7 @@ func (s *SQLiteStorage) CheckEligibility(ctx context.Context, issueID string, ti
 	var closedAt sql.NullTime
 	var compactionLevel int
 	
-	err := s.db.QueryRowContext(ctx, `
+	err := s.reads.QueryRowContext(ctx, `
 		SELECT status, closed_at, COALESCE(compaction_level, 0)
 		FROM issues
 		WHERE id = ?
@@ -226,15 +323,12 @@ func (s *SQLiteStorage) CheckEligibility(ctx context.Context, issueID string, ti
 		}
 		
 		// Check if it appears in tier1 candidates
-		candidates, err := s.GetTier1Candidates(ctx)
+		found, err := s.isCandidate(ctx, 1, issueID)
 		if err != nil {
 			return false, "", fmt.Errorf("failed to get tier1 candidates: %w", err)
 		}
-		
-		for _, c := range candidates {
-			if c.IssueID == issueID {
-				return true, "", nil
-			}
+		if found {
+			return true, "", nil
 		}
 		
 		return false, "issue has open dependents or not closed long enough", nil
@@ -245,15 +339,12 @@ func (s *SQLiteStorage) CheckEligibility(ctx context.Context, issueID string, ti
 		}
 		
 		// Check if it appears in tier2 candidates
-		candidates, err := s.GetTier2Candidates(ctx)
+		found, err := s.isCandidate(ctx, 2, issueID)
 		if err != nil {
 			return false, "", fmt.Errorf("failed to get tier2 candidates: %w", err)
 		}
-		
-		for _, c := range candidates {
-			if c.IssueID == issueID {
-				return true, "", nil
-			}
+		if found {
+			return true, "", nil
 		}
 		
 		return false, "issue has open dependents, not closed long enough, or insufficient events", nil
@@ -265,20 +356,32 @@ func (s *SQLiteStorage) CheckEligibility(ctx context.Context, issueID string, ti
 // ApplyCompaction updates the compaction metadata for an issue after successfully compacting it.
 // This sets compaction_level, compacted_at, compacted_at_commit, and original_size fields.
 func (s *SQLiteStorage) ApplyCompaction(ctx context.Context, issueID string, level int, originalSize int, compressedSize int, commitHash string) error {
-	now := time.Now().UTC()
-	
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer func() { _ = tx.Rollback() }()
-	
+
+	if err := applyCompactionInTx(ctx, tx, issueID, level, originalSize, compressedSize, commitHash); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func applyCompactionInTx(ctx context.Context, tx *sql.Tx, issueID string, level int, originalSize int, compressedSize int, commitHash string) error {
+	now := time.Now().UTC()
+
 	var commitHashPtr *string
 	if commitHash != "" {
 		commitHashPtr = &commitHash
 	}
-	
-	_, err = tx.ExecContext(ctx, `
+
+	_, err := tx.ExecContext(ctx, `
 		UPDATE issues
 		SET compaction_level = ?,
 		    compacted_at = ?,
@@ -287,31 +390,123 @@ func (s *SQLiteStorage) ApplyCompaction(ctx context.Context, issueID string, lev
 		    updated_at = ?
 		WHERE id = ?
 	`, level, now, commitHashPtr, originalSize, now, issueID)
-	
+
 	if err != nil {
 		return fmt.Errorf("failed to apply compaction metadata: %w", err)
 	}
-	
+
 	reductionPct := 0.0
 	if originalSize > 0 {
 		reductionPct = (1.0 - float64(compressedSize)/float64(originalSize)) * 100
 	}
-	
+
 	eventData := fmt.Sprintf(`{"tier":%d,"original_size":%d,"compressed_size":%d,"reduction_pct":%.1f}`,
 		level, originalSize, compressedSize, reductionPct)
-	
+
 	_, err = tx.ExecContext(ctx, `
 		INSERT INTO events (issue_id, event_type, actor, comment)
 		VALUES (?, ?, 'compactor', ?)
 	`, issueID, types.EventCompacted, eventData)
-	
+
 	if err != nil {
 		return fmt.Errorf("failed to record compaction event: %w", err)
 	}
-	
+
+	return nil
+}
+
+// tier2SummaryLimit caps the one-line summary Tier 2 keeps, in characters
+const tier2SummaryLimit = 200
+
+// verboseEventTypes are the events Tier 2 drops; the lifecycle (created,
+// status changes, closed, compacted, ...) is kept
+var verboseEventTypes = []types.EventType{
+	types.EventUpdated, types.EventCommented, types.EventMentioned, types.EventReverted,
+	types.EventLabelAdded, types.EventLabelRemoved,
+	types.EventDependencyAdded, types.EventDependencyRemoved,
+}
+
+// CompactTier2 performs Tier 2 compaction on an issue that is already at
+// level 1: the description is cut down to its first sentence, the remaining
+// text fields are cleared, and verbose events are dropped from the audit
+// trail. Unlike Tier 1 this needs no summarizer. It returns the compacted
+// size in bytes.
+func (s *SQLiteStorage) CompactTier2(ctx context.Context, issueID string, commitHash string) (int, error) {
+	eligible, reason, err := s.CheckEligibility(ctx, issueID, 2)
+	if err != nil {
+		return 0, fmt.Errorf("failed to verify eligibility: %w", err)
+	}
+	if !eligible {
+		return 0, fmt.Errorf("issue %s is not eligible for Tier 2 compaction: %s", issueID, reason)
+	}
+
+	issue, err := s.GetIssue(ctx, issueID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get issue: %w", err)
+	}
+	if issue == nil {
+		return 0, types.NotFound("issue", issueID)
+	}
+
+	originalSize := issue.OriginalSize
+	if originalSize == 0 {
+		originalSize = len(issue.Description) + len(issue.Design) + len(issue.Notes) + len(issue.AcceptanceCriteria)
+	}
+	summary := tier2Summary(issue.Description)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	updates := map[string]interface{}{
+		"description":         summary,
+		"design":              "",
+		"notes":               "",
+		"acceptance_criteria": "",
+	}
+	if err := s.updateIssueInTx(ctx, tx, issue, updates, "compactor", 0); err != nil {
+		return 0, err
+	}
+
+	// Drop verbose events before recording the compaction so the update
+	// above goes too
+	placeholders := make([]string, len(verboseEventTypes))
+	args := []interface{}{issueID}
+	for i, t := range verboseEventTypes {
+		placeholders[i] = "?"
+		args = append(args, t)
+	}
+	// #nosec G201 - only placeholders are interpolated
+	query := fmt.Sprintf(`DELETE FROM events WHERE issue_id = ? AND event_type IN (%s)`, strings.Join(placeholders, ","))
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return 0, fmt.Errorf("failed to drop events: %w", err)
+	}
+
+	if err := applyCompactionInTx(ctx, tx, issueID, 2, originalSize, len(summary), commitHash); err != nil {
+		return 0, err
+	}
+
 	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
 	}
-	
-	return nil
+
+	return len(summary), nil
+}
+
+// tier2Summary keeps the first sentence of the first line of description,
+// truncated to tier2SummaryLimit characters
+func tier2Summary(description string) string {
+	summary := strings.TrimSpace(description)
+	if i := strings.IndexByte(summary, '\n'); i >= 0 {
+		summary = strings.TrimSpace(summary[:i])
+	}
+	if i := strings.Index(summary, ". "); i >= 0 {
+		summary = summary[:i+1]
+	}
+	if runes := []rune(summary); len(runes) > tier2SummaryLimit {
+		summary = strings.TrimSpace(string(runes[:tier2SummaryLimit-1])) + "…"
+	}
+	return summary
 }
@@ -3,6 +3,9 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -150,6 +153,82 @@ func TestGetTier2Candidates(t *testing.T) {
 	}
 }
 
+func TestForEachCandidatePage(t *testing.T) {
+	store, err := NewWithConfig(filepath.Join(t.TempDir(), "test.db"), PoolConfig{ReadConnections: 1})
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+
+	// More than a page of candidates, many closed at the same moment
+	n := idBatchSize + 25
+	closed := time.Now().Add(-40 * 24 * time.Hour)
+	for i := 0; i < n; i++ {
+		issue := &types.Issue{
+			ID:          fmt.Sprintf("bd-%d", i+1),
+			Title:       "Old closed issue",
+			Description: "Compact me",
+			Status:      "closed",
+			Priority:    2,
+			IssueType:   "task",
+			ClosedAt:    timePtr(closed.Add(time.Duration(i%7) * time.Hour)),
+		}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("Failed to create issue: %v", err)
+		}
+	}
+
+	want, err := store.GetTier1Candidates(ctx)
+	if err != nil {
+		t.Fatalf("GetTier1Candidates failed: %v", err)
+	}
+
+	// Compacting each page as it comes leaves the rest to page through
+	var got []string
+	pages := 0
+	err = store.ForEachCandidatePage(ctx, 1, func(page []*CompactionCandidate) error {
+		pages++
+		for _, c := range page {
+			got = append(got, c.IssueID)
+			if err := store.ApplyCompaction(ctx, c.IssueID, 1, c.OriginalSize, 10, ""); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ForEachCandidatePage failed: %v", err)
+	}
+	if pages != 2 {
+		t.Errorf("Expected 2 pages, got %d", pages)
+	}
+	if len(got) != len(want) || len(got) != n {
+		t.Fatalf("Expected %d candidates, got %d", n, len(got))
+	}
+	for i := range want {
+		if got[i] != want[i].IssueID {
+			t.Fatalf("Candidate %d: expected %s, got %s", i, want[i].IssueID, got[i])
+		}
+	}
+
+	left, err := store.GetTier1Candidates(ctx)
+	if err != nil {
+		t.Fatalf("GetTier1Candidates failed: %v", err)
+	}
+	if len(left) != 0 {
+		t.Errorf("Expected every candidate compacted, %d left", len(left))
+	}
+
+	if err := store.ForEachCandidatePage(ctx, 3, func([]*CompactionCandidate) error { return nil }); err == nil {
+		t.Error("Expected an error for tier 3")
+	}
+}
+
 func TestCheckEligibilityTier1(t *testing.T) {
 	store, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -364,6 +443,97 @@ func TestApplyCompaction(t *testing.T) {
 	}
 }
 
+func TestCompactTier2(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	if err := store.SetConfig(ctx, "compact_tier2_commits", "3"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+
+	issue := &types.Issue{
+		ID:          "bd-1",
+		Title:       "Tier1 compacted",
+		Description: "Added retry to the sync worker. It backs off exponentially.\nMore detail here.",
+		Notes:       "leftover notes",
+		Status:      "closed",
+		Priority:    2,
+		IssueType:   "task",
+		ClosedAt:    timePtr(time.Now().Add(-100 * 24 * time.Hour)),
+	}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("Failed to create issue: %v", err)
+	}
+	if _, err := store.db.ExecContext(ctx, `UPDATE issues SET compaction_level = 1, original_size = 1000 WHERE id = ?`, "bd-1"); err != nil {
+		t.Fatalf("Failed to set compaction level: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := store.AddComment(ctx, "bd-1", "test", "comment"); err != nil {
+			t.Fatalf("Failed to add event: %v", err)
+		}
+	}
+
+	size, err := store.CompactTier2(ctx, "bd-1", "abc123")
+	if err != nil {
+		t.Fatalf("CompactTier2 failed: %v", err)
+	}
+
+	got, err := store.GetIssue(ctx, "bd-1")
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if got.Description != "Added retry to the sync worker." {
+		t.Errorf("Description = %q", got.Description)
+	}
+	if size != len(got.Description) {
+		t.Errorf("Expected compacted size %d, got %d", len(got.Description), size)
+	}
+	if got.Notes != "" {
+		t.Errorf("Expected notes cleared, got %q", got.Notes)
+	}
+	if got.CompactionLevel != 2 || got.OriginalSize != 1000 {
+		t.Errorf("Expected level 2 with original size 1000, got level %d size %d", got.CompactionLevel, got.OriginalSize)
+	}
+
+	events, err := store.GetEvents(ctx, "bd-1", 0)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	var kinds []types.EventType
+	for _, e := range events {
+		kinds = append(kinds, e.EventType)
+	}
+	for _, k := range kinds {
+		if k == types.EventCommented || k == types.EventUpdated {
+			t.Errorf("Expected verbose events dropped, got %v", kinds)
+			break
+		}
+	}
+
+	if _, err := store.CompactTier2(ctx, "bd-1", ""); err == nil {
+		t.Error("Expected second Tier 2 compaction to fail")
+	}
+}
+
+func TestTier2Summary(t *testing.T) {
+	long := strings.Repeat("word ", 60)
+	tests := []struct {
+		in, want string
+	}{
+		{"One sentence only", "One sentence only"},
+		{"First. Second.", "First."},
+		{"  Line one\nLine two. More", "Line one"},
+		{"", ""},
+		{long, strings.TrimSpace(long[:tier2SummaryLimit-1]) + "…"},
+	}
+	for _, tt := range tests {
+		if got := tier2Summary(tt.in); got != tt.want {
+			t.Errorf("tier2Summary(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
 func timePtr(t time.Time) *time.Time {
 	return &t
 }
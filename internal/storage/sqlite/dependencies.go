@@ -8,7 +8,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/imalsogreg/beads/internal/remote"
 	"github.com/imalsogreg/beads/internal/types"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const (
@@ -19,9 +21,17 @@ const (
 
 // AddDependency adds a dependency between issues with cycle prevention
 func (s *SQLiteStorage) AddDependency(ctx context.Context, dep *types.Dependency, actor string) error {
+	ctx, span := startSpan(ctx, "AddDependency", attribute.String("issue.id", dep.IssueID))
+	defer span.End()
+
 	// Validate dependency type
 	if !dep.Type.IsValid() {
-		return fmt.Errorf("invalid dependency type: %s (must be blocks, related, parent-child, or discovered-from)", dep.Type)
+		return fmt.Errorf("invalid dependency type: %s (must be blocks, related/relates-to, parent-child, discovered-from, duplicates, or caused-by)", dep.Type)
+	}
+	dep.Type = dep.Type.Canonical()
+
+	if remote.IsRef(dep.DependsOnID) {
+		return s.addRemoteDependency(ctx, dep, actor)
 	}
 
 	// Validate that both issues exist
@@ -30,7 +40,7 @@ func (s *SQLiteStorage) AddDependency(ctx context.Context, dep *types.Dependency
 		return fmt.Errorf("failed to check issue %s: %w", dep.IssueID, err)
 	}
 	if issueExists == nil {
-		return fmt.Errorf("issue %s not found", dep.IssueID)
+		return types.NotFound("issue", dep.IssueID)
 	}
 
 	dependsOnExists, err := s.GetIssue(ctx, dep.DependsOnID)
@@ -38,7 +48,7 @@ func (s *SQLiteStorage) AddDependency(ctx context.Context, dep *types.Dependency
 		return fmt.Errorf("failed to check dependency %s: %w", dep.DependsOnID, err)
 	}
 	if dependsOnExists == nil {
-		return fmt.Errorf("dependency target %s not found", dep.DependsOnID)
+		return types.NotFound("dependency target", dep.DependsOnID)
 	}
 
 	// Prevent self-dependency
@@ -76,7 +86,7 @@ func (s *SQLiteStorage) AddDependency(ctx context.Context, dep *types.Dependency
 
 	// Cycle Detection and Prevention
 	//
-	// We prevent cycles across ALL dependency types (blocks, related, parent-child, discovered-from)
+	// We prevent cycles across ALL dependency types (blocks, related, parent-child, discovered-from, duplicates, caused-by)
 	// to maintain a directed acyclic graph (DAG). This is critical for:
 	//
 	// 1. Ready Work Calculation: Cycles can hide issues from the ready list by making them
@@ -126,8 +136,7 @@ func (s *SQLiteStorage) AddDependency(ctx context.Context, dep *types.Dependency
 	}
 
 	if cycleExists {
-		return fmt.Errorf("cannot add dependency: would create a cycle (%s → %s → ... → %s)",
-			dep.IssueID, dep.DependsOnID, dep.IssueID)
+		return &types.CycleError{IssueID: dep.IssueID, DependsOnID: dep.DependsOnID}
 	}
 
 	// Insert dependency
@@ -141,10 +150,10 @@ func (s *SQLiteStorage) AddDependency(ctx context.Context, dep *types.Dependency
 
 	// Record event
 	_, err = tx.ExecContext(ctx, `
-		INSERT INTO events (issue_id, event_type, actor, comment)
-		VALUES (?, ?, ?, ?)
+		INSERT INTO events (issue_id, event_type, actor, comment, session_id)
+		VALUES (?, ?, ?, ?, ?)
 	`, dep.IssueID, types.EventDependencyAdded, actor,
-		fmt.Sprintf("Added dependency: %s %s %s", dep.IssueID, dep.Type, dep.DependsOnID))
+		fmt.Sprintf("Added dependency: %s %s %s", dep.IssueID, dep.Type, dep.DependsOnID), s.eventSession(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to record event: %w", err)
 	}
@@ -169,6 +178,9 @@ func (s *SQLiteStorage) addDependencyUnchecked(ctx context.Context, dep *types.D
 	if !dep.Type.IsValid() {
 		return fmt.Errorf("invalid dependency type: %s", dep.Type)
 	}
+	if remote.IsRef(dep.DependsOnID) {
+		return s.addRemoteDependency(ctx, dep, actor)
+	}
 
 	// Validate that both issues exist
 	issueExists, err := s.GetIssue(ctx, dep.IssueID)
@@ -176,7 +188,7 @@ func (s *SQLiteStorage) addDependencyUnchecked(ctx context.Context, dep *types.D
 		return fmt.Errorf("failed to check issue %s: %w", dep.IssueID, err)
 	}
 	if issueExists == nil {
-		return fmt.Errorf("issue %s not found", dep.IssueID)
+		return types.NotFound("issue", dep.IssueID)
 	}
 
 	dependsOnExists, err := s.GetIssue(ctx, dep.DependsOnID)
@@ -184,7 +196,7 @@ func (s *SQLiteStorage) addDependencyUnchecked(ctx context.Context, dep *types.D
 		return fmt.Errorf("failed to check dependency %s: %w", dep.DependsOnID, err)
 	}
 	if dependsOnExists == nil {
-		return fmt.Errorf("dependency target %s not found", dep.DependsOnID)
+		return types.NotFound("dependency target", dep.DependsOnID)
 	}
 
 	// Prevent self-dependency
@@ -240,8 +252,7 @@ func (s *SQLiteStorage) addDependencyUnchecked(ctx context.Context, dep *types.D
 	}
 
 	if cycleExists {
-		return fmt.Errorf("cannot add dependency: would create a cycle (%s → %s → ... → %s)",
-			dep.IssueID, dep.DependsOnID, dep.IssueID)
+		return &types.CycleError{IssueID: dep.IssueID, DependsOnID: dep.DependsOnID}
 	}
 
 	// Insert dependency
@@ -255,10 +266,10 @@ func (s *SQLiteStorage) addDependencyUnchecked(ctx context.Context, dep *types.D
 
 	// Record event
 	_, err = tx.ExecContext(ctx, `
-		INSERT INTO events (issue_id, event_type, actor, comment)
-		VALUES (?, ?, ?, ?)
+		INSERT INTO events (issue_id, event_type, actor, comment, session_id)
+		VALUES (?, ?, ?, ?, ?)
 	`, dep.IssueID, types.EventDependencyAdded, actor,
-		fmt.Sprintf("Added dependency: %s %s %s", dep.IssueID, dep.Type, dep.DependsOnID))
+		fmt.Sprintf("Added dependency: %s %s %s", dep.IssueID, dep.Type, dep.DependsOnID), s.eventSession(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to record event: %w", err)
 	}
@@ -279,9 +290,7 @@ func (s *SQLiteStorage) RemoveDependency(ctx context.Context, issueID, dependsOn
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	result, err := tx.ExecContext(ctx, `
-		DELETE FROM dependencies WHERE issue_id = ? AND depends_on_id = ?
-	`, issueID, dependsOnID)
+	result, err := tx.ExecContext(ctx, deleteDependencyQuery(dependsOnID), issueID, dependsOnID)
 	if err != nil {
 		return fmt.Errorf("failed to remove dependency: %w", err)
 	}
@@ -296,16 +305,16 @@ func (s *SQLiteStorage) RemoveDependency(ctx context.Context, issueID, dependsOn
 	}
 
 	_, err = tx.ExecContext(ctx, `
-		INSERT INTO events (issue_id, event_type, actor, comment)
-		VALUES (?, ?, ?, ?)
+		INSERT INTO events (issue_id, event_type, actor, comment, session_id)
+		VALUES (?, ?, ?, ?, ?)
 	`, issueID, types.EventDependencyRemoved, actor,
-		fmt.Sprintf("Removed dependency on %s", dependsOnID))
+		fmt.Sprintf("Removed dependency on %s", dependsOnID), s.eventSession(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to record event: %w", err)
 	}
 
 	// Mark both issues as dirty for incremental export
-	if err := markIssuesDirtyTx(ctx, tx, []string{issueID, dependsOnID}); err != nil {
+	if err := markIssuesDirtyTx(ctx, tx, localIssueIDs(issueID, dependsOnID)); err != nil {
 		return err
 	}
 
@@ -321,9 +330,7 @@ func (s *SQLiteStorage) removeDependencyIfExists(ctx context.Context, issueID, d
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	result, err := tx.ExecContext(ctx, `
-		DELETE FROM dependencies WHERE issue_id = ? AND depends_on_id = ?
-	`, issueID, dependsOnID)
+	result, err := tx.ExecContext(ctx, deleteDependencyQuery(dependsOnID), issueID, dependsOnID)
 	if err != nil {
 		return fmt.Errorf("failed to remove dependency: %w", err)
 	}
@@ -339,16 +346,16 @@ func (s *SQLiteStorage) removeDependencyIfExists(ctx context.Context, issueID, d
 	}
 
 	_, err = tx.ExecContext(ctx, `
-		INSERT INTO events (issue_id, event_type, actor, comment)
-		VALUES (?, ?, ?, ?)
+		INSERT INTO events (issue_id, event_type, actor, comment, session_id)
+		VALUES (?, ?, ?, ?, ?)
 	`, issueID, types.EventDependencyRemoved, actor,
-		fmt.Sprintf("Removed dependency on %s", dependsOnID))
+		fmt.Sprintf("Removed dependency on %s", dependsOnID), s.eventSession(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to record event: %w", err)
 	}
 
 	// Mark both issues as dirty for incremental export
-	if err := markIssuesDirtyTx(ctx, tx, []string{issueID, dependsOnID}); err != nil {
+	if err := markIssuesDirtyTx(ctx, tx, localIssueIDs(issueID, dependsOnID)); err != nil {
 		return err
 	}
 
@@ -357,10 +364,14 @@ func (s *SQLiteStorage) removeDependencyIfExists(ctx context.Context, issueID, d
 
 // GetDependencies returns issues that this issue depends on
 func (s *SQLiteStorage) GetDependencies(ctx context.Context, issueID string) ([]*types.Issue, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	ctx, span := startSpan(ctx, "GetDependencies", attribute.String("issue.id", issueID))
+	defer span.End()
+
+	rows, err := s.reads.QueryContext(ctx, `
 		SELECT i.id, i.title, i.description, i.design, i.acceptance_criteria, i.notes,
 		       i.status, i.priority, i.issue_type, i.assignee, i.estimated_minutes,
-		       i.created_at, i.updated_at, i.closed_at, i.external_ref
+		       i.created_at, i.updated_at, i.closed_at, i.external_ref,
+		       i.due_date, i.start_date, i.milestone
 		FROM issues i
 		JOIN dependencies d ON i.id = d.depends_on_id
 		WHERE d.issue_id = ?
@@ -376,10 +387,14 @@ func (s *SQLiteStorage) GetDependencies(ctx context.Context, issueID string) ([]
 
 // GetDependents returns issues that depend on this issue
 func (s *SQLiteStorage) GetDependents(ctx context.Context, issueID string) ([]*types.Issue, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	ctx, span := startSpan(ctx, "GetDependents", attribute.String("issue.id", issueID))
+	defer span.End()
+
+	rows, err := s.reads.QueryContext(ctx, `
 		SELECT i.id, i.title, i.description, i.design, i.acceptance_criteria, i.notes,
 		       i.status, i.priority, i.issue_type, i.assignee, i.estimated_minutes,
-		       i.created_at, i.updated_at, i.closed_at, i.external_ref
+		       i.created_at, i.updated_at, i.closed_at, i.external_ref,
+		       i.due_date, i.start_date, i.milestone
 		FROM issues i
 		JOIN dependencies d ON i.id = d.issue_id
 		WHERE d.depends_on_id = ?
@@ -393,14 +408,19 @@ func (s *SQLiteStorage) GetDependents(ctx context.Context, issueID string) ([]*t
 	return s.scanIssues(ctx, rows)
 }
 
-// GetDependencyRecords returns raw dependency records for an issue
+// GetDependencyRecords returns raw dependency records for an issue,
+// including dependencies on other workspaces' issues
 func (s *SQLiteStorage) GetDependencyRecords(ctx context.Context, issueID string) ([]*types.Dependency, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	rows, err := s.reads.QueryContext(ctx, `
 		SELECT issue_id, depends_on_id, type, created_at, created_by
 		FROM dependencies
 		WHERE issue_id = ?
+		UNION ALL
+		SELECT issue_id, depends_on_ref, type, created_at, created_by
+		FROM remote_dependencies
+		WHERE issue_id = ?
 		ORDER BY created_at ASC
-	`, issueID)
+	`, issueID, issueID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get dependency records: %w", err)
 	}
@@ -428,9 +448,12 @@ func (s *SQLiteStorage) GetDependencyRecords(ctx context.Context, issueID string
 // GetAllDependencyRecords returns all dependency records grouped by issue ID
 // This is optimized for bulk export operations to avoid N+1 queries
 func (s *SQLiteStorage) GetAllDependencyRecords(ctx context.Context) (map[string][]*types.Dependency, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	rows, err := s.reads.QueryContext(ctx, `
 		SELECT issue_id, depends_on_id, type, created_at, created_by
 		FROM dependencies
+		UNION ALL
+		SELECT issue_id, depends_on_ref, type, created_at, created_by
+		FROM remote_dependencies
 		ORDER BY issue_id, created_at ASC
 	`)
 	if err != nil {
@@ -483,7 +506,8 @@ func (s *SQLiteStorage) GetDependencyTree(ctx context.Context, issueID string, m
 				i.external_ref,
 				0 as depth,
 				i.id as path,
-				i.id as parent_id
+				i.id as parent_id,
+				'' as dep_type
 				FROM issues i
 				WHERE i.id = ?
 
@@ -496,7 +520,8 @@ func (s *SQLiteStorage) GetDependencyTree(ctx context.Context, issueID string, m
 				i.external_ref,
 				t.depth + 1,
 				t.path || '→' || i.id,
-				t.id
+				t.id,
+				d.type
 				FROM issues i
 				JOIN dependencies d ON i.id = d.issue_id
 				JOIN tree t ON d.depends_on_id = t.id
@@ -509,7 +534,7 @@ func (s *SQLiteStorage) GetDependencyTree(ctx context.Context, issueID string, m
 				SELECT id, title, status, priority, description, design,
 				acceptance_criteria, notes, issue_type, assignee,
 				estimated_minutes, created_at, updated_at, closed_at,
-				external_ref, depth, parent_id
+				external_ref, depth, parent_id, dep_type
 				FROM tree
 				ORDER BY depth, priority, id
 		`
@@ -524,7 +549,8 @@ func (s *SQLiteStorage) GetDependencyTree(ctx context.Context, issueID string, m
 				i.external_ref,
 				0 as depth,
 				i.id as path,
-				i.id as parent_id
+				i.id as parent_id,
+				'' as dep_type
 				FROM issues i
 				WHERE i.id = ?
 
@@ -537,7 +563,8 @@ func (s *SQLiteStorage) GetDependencyTree(ctx context.Context, issueID string, m
 				i.external_ref,
 				t.depth + 1,
 				t.path || '→' || i.id,
-				t.id
+				t.id,
+				d.type
 				FROM issues i
 				JOIN dependencies d ON i.id = d.depends_on_id
 				JOIN tree t ON d.issue_id = t.id
@@ -550,7 +577,7 @@ func (s *SQLiteStorage) GetDependencyTree(ctx context.Context, issueID string, m
 				SELECT id, title, status, priority, description, design,
 				acceptance_criteria, notes, issue_type, assignee,
 				estimated_minutes, created_at, updated_at, closed_at,
-				external_ref, depth, parent_id
+				external_ref, depth, parent_id, dep_type
 				FROM tree
 				ORDER BY depth, priority, id
 		`
@@ -558,7 +585,7 @@ func (s *SQLiteStorage) GetDependencyTree(ctx context.Context, issueID string, m
 
 	// First, build the complete tree with all paths using recursive CTE
 	// We need to track the full path to handle proper tree structure
-	rows, err := s.db.QueryContext(ctx, query, issueID, maxDepth)
+	rows, err := s.reads.QueryContext(ctx, query, issueID, maxDepth)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get dependency tree: %w", err)
 	}
@@ -582,12 +609,13 @@ func (s *SQLiteStorage) GetDependencyTree(ctx context.Context, issueID string, m
 			&node.Description, &node.Design, &node.AcceptanceCriteria,
 			&node.Notes, &node.IssueType, &assignee, &estimatedMinutes,
 			&node.CreatedAt, &node.UpdatedAt, &closedAt, &externalRef,
-			&node.Depth, &parentID,
+			&node.Depth, &parentID, &node.DepType,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan tree node: %w", err)
 		}
 		_ = parentID // Silence unused variable warning
+		s.decryptIssueFields(&node.Issue)
 
 		if closedAt.Valid {
 			node.ClosedAt = &closedAt.Time
@@ -629,7 +657,7 @@ func (s *SQLiteStorage) GetDependencyTree(ctx context.Context, issueID string, m
 func (s *SQLiteStorage) DetectCycles(ctx context.Context) ([][]*types.Issue, error) {
 	// Use recursive CTE to find cycles with full paths
 	// We track the path as a string to work around SQLite's lack of arrays
-	rows, err := s.db.QueryContext(ctx, `
+	rows, err := s.reads.QueryContext(ctx, `
 		WITH RECURSIVE paths AS (
 			SELECT
 				issue_id,
@@ -709,45 +737,66 @@ func (s *SQLiteStorage) DetectCycles(ctx context.Context) ([][]*types.Issue, err
 func (s *SQLiteStorage) scanIssues(ctx context.Context, rows *sql.Rows) ([]*types.Issue, error) {
 	var issues []*types.Issue
 	for rows.Next() {
-		var issue types.Issue
-		var closedAt sql.NullTime
-		var estimatedMinutes sql.NullInt64
-		var assignee sql.NullString
-		var externalRef sql.NullString
-
-		err := rows.Scan(
-			&issue.ID, &issue.Title, &issue.Description, &issue.Design,
-			&issue.AcceptanceCriteria, &issue.Notes, &issue.Status,
-			&issue.Priority, &issue.IssueType, &assignee, &estimatedMinutes,
-			&issue.CreatedAt, &issue.UpdatedAt, &closedAt, &externalRef,
-		)
+		issue, err := s.scanIssue(rows)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan issue: %w", err)
-		}
-
-		if closedAt.Valid {
-			issue.ClosedAt = &closedAt.Time
-		}
-		if estimatedMinutes.Valid {
-			mins := int(estimatedMinutes.Int64)
-			issue.EstimatedMinutes = &mins
-		}
-		if assignee.Valid {
-			issue.Assignee = assignee.String
-		}
-		if externalRef.Valid {
-			issue.ExternalRef = &externalRef.String
+			return nil, err
 		}
+		issues = append(issues, issue)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read issues: %w", err)
+	}
 
-		// Fetch labels for this issue
-		labels, err := s.GetLabels(ctx, issue.ID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get labels for issue %s: %w", issue.ID, err)
-		}
-		issue.Labels = labels
+	// Labels for all the issues at once, rather than a query per issue
+	if err := s.populateLabels(ctx, issues); err != nil {
+		return nil, err
+	}
+	return issues, nil
+}
 
-		issues = append(issues, &issue)
+// scanIssue reads the issue on rows' current row, without its labels
+func (s *SQLiteStorage) scanIssue(rows *sql.Rows) (*types.Issue, error) {
+	var issue types.Issue
+	var closedAt sql.NullTime
+	var estimatedMinutes sql.NullInt64
+	var assignee sql.NullString
+	var externalRef sql.NullString
+	var dueDate, startDate sql.NullTime
+	var milestone sql.NullString
+
+	err := rows.Scan(
+		&issue.ID, &issue.Title, &issue.Description, &issue.Design,
+		&issue.AcceptanceCriteria, &issue.Notes, &issue.Status,
+		&issue.Priority, &issue.IssueType, &assignee, &estimatedMinutes,
+		&issue.CreatedAt, &issue.UpdatedAt, &closedAt, &externalRef,
+		&dueDate, &startDate, &milestone,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan issue: %w", err)
 	}
 
-	return issues, nil
+	if closedAt.Valid {
+		issue.ClosedAt = &closedAt.Time
+	}
+	if estimatedMinutes.Valid {
+		mins := int(estimatedMinutes.Int64)
+		issue.EstimatedMinutes = &mins
+	}
+	if assignee.Valid {
+		issue.Assignee = assignee.String
+	}
+	if externalRef.Valid {
+		issue.ExternalRef = &externalRef.String
+	}
+	if dueDate.Valid {
+		issue.DueDate = &dueDate.Time
+	}
+	if startDate.Valid {
+		issue.StartDate = &startDate.Time
+	}
+	if milestone.Valid {
+		issue.Milestone = milestone.String
+	}
+	s.decryptIssueFields(&issue)
+	return &issue, nil
 }
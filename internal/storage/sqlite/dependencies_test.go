@@ -277,6 +277,55 @@ func TestGetDependencyTree(t *testing.T) {
 	}
 }
 
+func TestLinksDoNotBlockReadyWork(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	regression := &types.Issue{Title: "Regression", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeBug}
+	cause := &types.Issue{Title: "Refactor", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	other := &types.Issue{Title: "Nearby work", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	for _, issue := range []*types.Issue{regression, cause, other} {
+		if err := store.CreateIssue(ctx, issue, "test-user"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, dep := range []*types.Dependency{
+		{IssueID: regression.ID, DependsOnID: cause.ID, Type: types.DepCausedBy},
+		{IssueID: other.ID, DependsOnID: regression.ID, Type: types.DepRelatesTo},
+	} {
+		if err := store.AddDependency(ctx, dep, "test-user"); err != nil {
+			t.Fatalf("AddDependency(%s) failed: %v", dep.Type, err)
+		}
+	}
+
+	ready, err := store.GetReadyWork(ctx, types.WorkFilter{Status: types.StatusOpen})
+	if err != nil {
+		t.Fatalf("GetReadyWork failed: %v", err)
+	}
+	if len(ready) != 3 {
+		t.Errorf("Expected all 3 issues ready, got %d", len(ready))
+	}
+
+	// relates-to is stored as related
+	records, err := store.GetDependencyRecords(ctx, other.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Type != types.DepRelated {
+		t.Errorf("Expected a related record, got %+v", records)
+	}
+
+	tree, err := store.GetDependencyTree(ctx, other.ID, 10, false, false)
+	if err != nil {
+		t.Fatalf("GetDependencyTree failed: %v", err)
+	}
+	if len(tree) != 3 || tree[0].DepType != "" || tree[1].DepType != types.DepRelated || tree[2].DepType != types.DepCausedBy {
+		t.Errorf("Unexpected tree edge types: %+v", tree)
+	}
+}
+
 func TestGetDependencyTree_TruncationDepth(t *testing.T) {
 	store, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -903,3 +952,60 @@ func TestGetDependencyTree_SubstringBug(t *testing.T) {
 		t.Errorf("Expected bd-1 at depth 4, got %d", depthMap[issues[0].ID])
 	}
 }
+
+func TestRemoteDependencies(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	issue := &types.Issue{Title: "Client work", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "test-user"); err != nil {
+		t.Fatal(err)
+	}
+	dep := &types.Dependency{IssueID: issue.ID, DependsOnID: "platform/plat-12", Type: types.DepBlocks}
+	if err := store.AddDependency(ctx, dep, "test-user"); err != nil {
+		t.Fatalf("AddDependency on another workspace failed: %v", err)
+	}
+	if err := store.AddDependency(ctx, &types.Dependency{IssueID: "bd-999", DependsOnID: "platform/plat-12", Type: types.DepBlocks}, "test-user"); err == nil {
+		t.Error("Expected a dependency from a missing issue to fail")
+	}
+
+	records, err := store.GetDependencyRecords(ctx, issue.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].DependsOnID != "platform/plat-12" || records[0].CreatedBy != "test-user" {
+		t.Errorf("Expected the remote dependency record, got %+v", records)
+	}
+	all, err := store.GetAllDependencyRecords(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all[issue.ID]) != 1 {
+		t.Errorf("Expected the remote dependency in the export records, got %+v", all)
+	}
+
+	// Nothing local blocks the issue, and the tree has only local issues
+	ready, err := store.GetReadyWork(ctx, types.WorkFilter{Status: types.StatusOpen})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ready) != 1 {
+		t.Errorf("Expected the issue to be ready, got %d ready", len(ready))
+	}
+	tree, err := store.GetDependencyTree(ctx, issue.ID, 10, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tree) != 1 {
+		t.Errorf("Expected only the root in the local tree, got %d nodes", len(tree))
+	}
+
+	if err := store.RemoveDependency(ctx, issue.ID, "platform/plat-12", "test-user"); err != nil {
+		t.Fatalf("RemoveDependency failed: %v", err)
+	}
+	if records, _ := store.GetDependencyRecords(ctx, issue.ID); len(records) != 0 {
+		t.Errorf("Expected no records after removal, got %+v", records)
+	}
+}
@@ -0,0 +1,109 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// DigestSubscription asks for a periodic email summarizing an actor's work
+type DigestSubscription struct {
+	Actor      string     `json:"actor"`
+	Email      string     `json:"email"`
+	Frequency  string     `json:"frequency"` // "daily" or "weekly"
+	LastSentAt *time.Time `json:"last_sent_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// SetDigestSubscription creates or replaces an actor's subscription. The
+// last sent time is kept when an existing subscription is changed.
+func (s *SQLiteStorage) SetDigestSubscription(ctx context.Context, sub *DigestSubscription) error {
+	if sub.Actor == "" || sub.Email == "" {
+		return fmt.Errorf("digest subscription requires an actor and email")
+	}
+
+	sub.CreatedAt = time.Now()
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO digest_subscriptions (actor, email, frequency, created_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(actor) DO UPDATE SET email = excluded.email, frequency = excluded.frequency
+	`, sub.Actor, sub.Email, sub.Frequency, sub.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to set digest subscription: %w", err)
+	}
+	return nil
+}
+
+// GetDigestSubscription returns an actor's subscription, or nil if there isn't one
+func (s *SQLiteStorage) GetDigestSubscription(ctx context.Context, actor string) (*DigestSubscription, error) {
+	row := s.reads.QueryRowContext(ctx, `
+		SELECT actor, email, frequency, last_sent_at, created_at
+		FROM digest_subscriptions
+		WHERE actor = ?
+	`, actor)
+	sub, err := scanDigestSubscription(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return sub, err
+}
+
+// ListDigestSubscriptions returns every subscription ordered by actor
+func (s *SQLiteStorage) ListDigestSubscriptions(ctx context.Context) ([]*DigestSubscription, error) {
+	rows, err := s.reads.QueryContext(ctx, `
+		SELECT actor, email, frequency, last_sent_at, created_at
+		FROM digest_subscriptions
+		ORDER BY actor
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list digest subscriptions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var subs []*DigestSubscription
+	for rows.Next() {
+		sub, err := scanDigestSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+	return subs, rows.Err()
+}
+
+// DeleteDigestSubscription removes an actor's subscription
+func (s *SQLiteStorage) DeleteDigestSubscription(ctx context.Context, actor string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM digest_subscriptions WHERE actor = ?`, actor)
+	if err != nil {
+		return fmt.Errorf("failed to delete digest subscription: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("no digest subscription for %s", actor)
+	}
+	return nil
+}
+
+// MarkDigestSent records when an actor's digest was last sent
+func (s *SQLiteStorage) MarkDigestSent(ctx context.Context, actor string, sentAt time.Time) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE digest_subscriptions SET last_sent_at = ? WHERE actor = ?`, sentAt, actor)
+	if err != nil {
+		return fmt.Errorf("failed to mark digest sent: %w", err)
+	}
+	return nil
+}
+
+func scanDigestSubscription(row rowScanner) (*DigestSubscription, error) {
+	var sub DigestSubscription
+	var lastSent sql.NullTime
+	if err := row.Scan(&sub.Actor, &sub.Email, &sub.Frequency, &lastSent, &sub.CreatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan digest subscription: %w", err)
+	}
+	if lastSent.Valid {
+		sub.LastSentAt = &lastSent.Time
+	}
+	return &sub, nil
+}
@@ -0,0 +1,59 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDigestSubscriptions(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	if err := store.SetDigestSubscription(ctx, &DigestSubscription{Actor: "bob", Email: "bob@example.com", Frequency: "weekly"}); err != nil {
+		t.Fatalf("SetDigestSubscription failed: %v", err)
+	}
+	if err := store.SetDigestSubscription(ctx, &DigestSubscription{Actor: "alice", Email: "alice@example.com", Frequency: "daily"}); err != nil {
+		t.Fatalf("SetDigestSubscription failed: %v", err)
+	}
+
+	sent := time.Date(2025, 4, 1, 8, 0, 0, 0, time.UTC)
+	if err := store.MarkDigestSent(ctx, "alice", sent); err != nil {
+		t.Fatalf("MarkDigestSent failed: %v", err)
+	}
+
+	// Changing a subscription keeps its last sent time
+	if err := store.SetDigestSubscription(ctx, &DigestSubscription{Actor: "alice", Email: "a@example.com", Frequency: "weekly"}); err != nil {
+		t.Fatalf("SetDigestSubscription failed: %v", err)
+	}
+	got, err := store.GetDigestSubscription(ctx, "alice")
+	if err != nil {
+		t.Fatalf("GetDigestSubscription failed: %v", err)
+	}
+	if got.Email != "a@example.com" || got.Frequency != "weekly" || got.LastSentAt == nil || !got.LastSentAt.Equal(sent) {
+		t.Errorf("Unexpected subscription %+v", got)
+	}
+
+	subs, err := store.ListDigestSubscriptions(ctx)
+	if err != nil {
+		t.Fatalf("ListDigestSubscriptions failed: %v", err)
+	}
+	if len(subs) != 2 || subs[0].Actor != "alice" || subs[1].LastSentAt != nil {
+		t.Errorf("Unexpected subscriptions %+v", subs)
+	}
+
+	if err := store.DeleteDigestSubscription(ctx, "bob"); err != nil {
+		t.Fatalf("DeleteDigestSubscription failed: %v", err)
+	}
+	if got, _ := store.GetDigestSubscription(ctx, "bob"); got != nil {
+		t.Errorf("Expected bob's subscription to be gone, got %+v", got)
+	}
+	if err := store.DeleteDigestSubscription(ctx, "bob"); err == nil {
+		t.Error("Expected error deleting a missing subscription")
+	}
+	if err := store.SetDigestSubscription(ctx, &DigestSubscription{Actor: "carol"}); err == nil {
+		t.Error("Expected error for missing email")
+	}
+}
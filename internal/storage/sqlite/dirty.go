@@ -54,7 +54,7 @@ func (s *SQLiteStorage) MarkIssuesDirty(ctx context.Context, issueIDs []string)
 
 // GetDirtyIssues returns the list of issue IDs that need to be exported
 func (s *SQLiteStorage) GetDirtyIssues(ctx context.Context) ([]string, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	rows, err := s.reads.QueryContext(ctx, `
 		SELECT issue_id FROM dirty_issues
 		ORDER BY marked_at ASC
 	`)
@@ -78,7 +78,7 @@ func (s *SQLiteStorage) GetDirtyIssues(ctx context.Context) ([]string, error) {
 // GetDirtyIssueHash returns the stored content hash for a dirty issue, if it exists
 func (s *SQLiteStorage) GetDirtyIssueHash(ctx context.Context, issueID string) (string, error) {
 	var hash sql.NullString
-	err := s.db.QueryRowContext(ctx, `
+	err := s.reads.QueryRowContext(ctx, `
 		SELECT content_hash FROM dirty_issues WHERE issue_id = ?
 	`, issueID).Scan(&hash)
 	
@@ -140,7 +140,7 @@ func (s *SQLiteStorage) ClearDirtyIssuesByID(ctx context.Context, issueIDs []str
 // GetDirtyIssueCount returns the count of dirty issues (for monitoring/debugging)
 func (s *SQLiteStorage) GetDirtyIssueCount(ctx context.Context) (int, error) {
 	var count int
-	err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM dirty_issues`).Scan(&count)
+	err := s.reads.QueryRowContext(ctx, `SELECT COUNT(*) FROM dirty_issues`).Scan(&count)
 	if err != nil && err != sql.ErrNoRows {
 		return 0, fmt.Errorf("failed to count dirty issues: %w", err)
 	}
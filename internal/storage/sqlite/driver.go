@@ -0,0 +1,30 @@
+package sqlite
+
+import (
+	"strings"
+
+	"github.com/imalsogreg/beads/internal/storage"
+)
+
+// DriverName is the storage driver SQLite registers as, and the scheme of
+// its DSNs: sqlite:./beads.db, or sqlite:///abs/path/beads.db
+const DriverName = "sqlite"
+
+func init() {
+	storage.Register(DriverName, func(dsn string) (storage.Storage, error) {
+		return New(DSNPath(dsn))
+	})
+}
+
+// DSNPath returns the database file a SQLite DSN names. A plain path is
+// returned as is.
+func DSNPath(dsn string) string {
+	if len(dsn) <= len(DriverName) || !strings.EqualFold(dsn[:len(DriverName)+1], DriverName+":") {
+		return dsn
+	}
+	path := dsn[len(DriverName)+1:]
+	if strings.HasPrefix(path, "//") {
+		path = path[2:]
+	}
+	return path
+}
@@ -17,7 +17,7 @@ func (s *SQLiteStorage) GetEpicsEligibleForClosure(ctx context.Context) ([]*type
 				i.status AS child_status
 			FROM dependencies d
 			JOIN issues i ON i.id = d.issue_id
-			WHERE d.type = 'parent-child'
+			WHERE d.type = 'parent-child' AND i.deleted_at IS NULL
 		),
 		epic_stats AS (
 			SELECT 
@@ -37,10 +37,11 @@ func (s *SQLiteStorage) GetEpicsEligibleForClosure(ctx context.Context) ([]*type
 		LEFT JOIN epic_stats es ON es.epic_id = i.id
 		WHERE i.issue_type = 'epic'
 		  AND i.status != 'closed'
+		  AND i.deleted_at IS NULL
 		ORDER BY i.priority ASC, i.created_at ASC
 	`
 
-	rows, err := s.db.QueryContext(ctx, query)
+	rows, err := s.reads.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -68,6 +69,7 @@ func (s *SQLiteStorage) GetEpicsEligibleForClosure(ctx context.Context) ([]*type
 		if assignee.Valid {
 			epic.Assignee = assignee.String
 		}
+		s.decryptIssueFields(&epic)
 
 		eligibleForClose := false
 		if totalChildren > 0 && closedChildren == totalChildren {
@@ -0,0 +1,274 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// EscalationRule raises the priority of, or labels, issues that have been
+// open for at least MinAgeDays (measured from created_at). Empty filters
+// match every issue that isn't closed.
+type EscalationRule struct {
+	ID          int64     `json:"id"`
+	IssueType   string    `json:"issue_type,omitempty"`
+	Status      string    `json:"status,omitempty"`
+	Priority    *int      `json:"priority,omitempty"` // only issues at this priority
+	MinAgeDays  int       `json:"min_age_days"`
+	SetPriority *int      `json:"set_priority,omitempty"`
+	AddLabel    string    `json:"add_label,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Describe returns a one-line summary of the rule
+func (r *EscalationRule) Describe() string {
+	var parts []string
+	if r.Priority != nil {
+		parts = append(parts, fmt.Sprintf("P%d", *r.Priority))
+	}
+	if r.Status != "" {
+		parts = append(parts, r.Status)
+	}
+	if r.IssueType != "" {
+		parts = append(parts, r.IssueType)
+	}
+	parts = append(parts, "issues", fmt.Sprintf("open %d+ days", r.MinAgeDays))
+
+	var actions []string
+	if r.SetPriority != nil {
+		actions = append(actions, fmt.Sprintf("become P%d", *r.SetPriority))
+	}
+	if r.AddLabel != "" {
+		actions = append(actions, fmt.Sprintf("get label %s", r.AddLabel))
+	}
+	return strings.Join(parts, " ") + " " + strings.Join(actions, " and ")
+}
+
+// Escalation is the change made (or, in a dry run, planned) to one issue
+type Escalation struct {
+	IssueID      string   `json:"issue_id"`
+	Title        string   `json:"title"`
+	RuleIDs      []int64  `json:"rule_ids"`
+	FromPriority int      `json:"from_priority"`
+	ToPriority   int      `json:"to_priority"`
+	Labels       []string `json:"labels,omitempty"`
+}
+
+// EscalationReport summarizes an escalation run
+type EscalationReport struct {
+	DryRun      bool          `json:"dry_run"`
+	Rules       int           `json:"rules"`
+	Escalations []*Escalation `json:"escalations"`
+}
+
+// AddEscalationRule validates and stores an escalation rule
+func (s *SQLiteStorage) AddEscalationRule(ctx context.Context, rule *EscalationRule) error {
+	if rule.MinAgeDays <= 0 {
+		return fmt.Errorf("age must be positive")
+	}
+	if rule.SetPriority == nil && rule.AddLabel == "" {
+		return fmt.Errorf("escalation rule needs a priority to set or a label to add")
+	}
+	for _, p := range []*int{rule.Priority, rule.SetPriority} {
+		if p != nil && (*p < 0 || *p > 4) {
+			return fmt.Errorf("priority must be between 0 and 4 (got %d)", *p)
+		}
+	}
+	if rule.Status == string(types.StatusClosed) {
+		return fmt.Errorf("closed issues are never escalated")
+	}
+
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO escalation_rules (issue_type, status, priority, min_age_days, set_priority, add_label)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, rule.IssueType, rule.Status, rule.Priority, rule.MinAgeDays, rule.SetPriority, rule.AddLabel)
+	if err != nil {
+		return fmt.Errorf("failed to add escalation rule: %w", err)
+	}
+	rule.ID, err = res.LastInsertId()
+	return err
+}
+
+// GetEscalationRules returns all escalation rules in creation order
+func (s *SQLiteStorage) GetEscalationRules(ctx context.Context) ([]*EscalationRule, error) {
+	rows, err := s.reads.QueryContext(ctx, `
+		SELECT id, issue_type, status, priority, min_age_days, set_priority, add_label, created_at
+		FROM escalation_rules ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query escalation rules: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var rules []*EscalationRule
+	for rows.Next() {
+		var r EscalationRule
+		var priority, setPriority sql.NullInt64
+		if err := rows.Scan(&r.ID, &r.IssueType, &r.Status, &priority, &r.MinAgeDays, &setPriority, &r.AddLabel, &r.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan escalation rule: %w", err)
+		}
+		if priority.Valid {
+			p := int(priority.Int64)
+			r.Priority = &p
+		}
+		if setPriority.Valid {
+			p := int(setPriority.Int64)
+			r.SetPriority = &p
+		}
+		rules = append(rules, &r)
+	}
+	return rules, rows.Err()
+}
+
+// RemoveEscalationRule deletes an escalation rule by ID
+func (s *SQLiteStorage) RemoveEscalationRule(ctx context.Context, id int64) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM escalation_rules WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to remove escalation rule: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return types.NotFound("escalation rule", id)
+	}
+	return nil
+}
+
+// ApplyEscalation evaluates the escalation rules in order against every issue
+// that isn't closed. A rule only ever raises priority, and a later rule sees
+// the priority set by an earlier one, so P3 -> P2 -> P1 chains work in a
+// single run. Issues already at or above a rule's priority, or already
+// carrying its label, are left alone, so repeated runs are no-ops. With
+// dryRun, it only reports what would change.
+func (s *SQLiteStorage) ApplyEscalation(ctx context.Context, dryRun bool, actor string) (*EscalationReport, error) {
+	rules, err := s.GetEscalationRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report := &EscalationReport{DryRun: dryRun, Rules: len(rules), Escalations: []*Escalation{}}
+	if len(rules) == 0 {
+		return report, nil
+	}
+
+	candidates, err := s.escalationCandidates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, c := range candidates {
+		var esc *Escalation
+		priority := c.Priority
+		for _, rule := range rules {
+			if !rule.matches(c, priority, now) {
+				continue
+			}
+			var label string
+			if rule.AddLabel != "" && (esc == nil || !slices.Contains(esc.Labels, rule.AddLabel)) {
+				has, err := s.hasLabel(ctx, c.ID, rule.AddLabel)
+				if err != nil {
+					return nil, err
+				}
+				if !has {
+					label = rule.AddLabel
+				}
+			}
+			raise := rule.SetPriority != nil && *rule.SetPriority < priority
+			if !raise && label == "" {
+				continue
+			}
+
+			if esc == nil {
+				esc = &Escalation{IssueID: c.ID, Title: c.Title, FromPriority: c.Priority}
+			}
+			esc.RuleIDs = append(esc.RuleIDs, rule.ID)
+			if raise {
+				priority = *rule.SetPriority
+			}
+			if label != "" {
+				esc.Labels = append(esc.Labels, label)
+			}
+		}
+		if esc == nil {
+			continue
+		}
+		esc.ToPriority = priority
+		report.Escalations = append(report.Escalations, esc)
+
+		if dryRun {
+			continue
+		}
+		if esc.ToPriority != esc.FromPriority {
+			if err := s.UpdateIssue(ctx, c.ID, map[string]interface{}{"priority": esc.ToPriority}, actor); err != nil {
+				return nil, fmt.Errorf("failed to escalate %s: %w", c.ID, err)
+			}
+		}
+		for _, label := range esc.Labels {
+			if err := s.AddLabel(ctx, c.ID, label, actor); err != nil {
+				return nil, fmt.Errorf("failed to label %s: %w", c.ID, err)
+			}
+		}
+	}
+	return report, nil
+}
+
+// escalationCandidate is the part of an issue the rules look at
+type escalationCandidate struct {
+	ID        string
+	Title     string
+	Status    string
+	IssueType string
+	Priority  int
+	CreatedAt time.Time
+}
+
+// matches reports whether the rule applies to c at its current priority
+func (r *EscalationRule) matches(c *escalationCandidate, priority int, now time.Time) bool {
+	if r.IssueType != "" && r.IssueType != c.IssueType {
+		return false
+	}
+	if r.Status != "" && r.Status != c.Status {
+		return false
+	}
+	if r.Priority != nil && *r.Priority != priority {
+		return false
+	}
+	return now.Sub(c.CreatedAt) >= time.Duration(r.MinAgeDays)*24*time.Hour
+}
+
+// escalationCandidates returns every issue that isn't closed or in the trash
+func (s *SQLiteStorage) escalationCandidates(ctx context.Context) ([]*escalationCandidate, error) {
+	rows, err := s.reads.QueryContext(ctx, `
+		SELECT id, title, status, issue_type, priority, created_at
+		FROM issues
+		WHERE status != 'closed' AND deleted_at IS NULL
+		ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query issues for escalation: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var candidates []*escalationCandidate
+	for rows.Next() {
+		var c escalationCandidate
+		if err := rows.Scan(&c.ID, &c.Title, &c.Status, &c.IssueType, &c.Priority, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan issue for escalation: %w", err)
+		}
+		candidates = append(candidates, &c)
+	}
+	return candidates, rows.Err()
+}
+
+// hasLabel reports whether an issue carries a label
+func (s *SQLiteStorage) hasLabel(ctx context.Context, issueID, label string) (bool, error) {
+	var exists bool
+	err := s.reads.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM labels WHERE issue_id = ? AND label = ?)`, issueID, label).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check label: %w", err)
+	}
+	return exists, nil
+}
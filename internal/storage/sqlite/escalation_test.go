@@ -0,0 +1,118 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestAddEscalationRuleValidation(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	p1, p9 := 1, 9
+	bad := []*EscalationRule{
+		{MinAgeDays: 30},
+		{MinAgeDays: 0, SetPriority: &p1},
+		{MinAgeDays: 30, SetPriority: &p9},
+		{MinAgeDays: 30, Status: "closed", AddLabel: "old"},
+	}
+	for _, r := range bad {
+		if err := store.AddEscalationRule(ctx, r); err == nil {
+			t.Errorf("expected rule %+v to be rejected", r)
+		}
+	}
+
+	rule := &EscalationRule{MinAgeDays: 30, SetPriority: &p1}
+	if err := store.AddEscalationRule(ctx, rule); err != nil {
+		t.Fatalf("AddEscalationRule failed: %v", err)
+	}
+	if err := store.RemoveEscalationRule(ctx, rule.ID); err != nil {
+		t.Fatalf("RemoveEscalationRule failed: %v", err)
+	}
+	if err := store.RemoveEscalationRule(ctx, rule.ID); err == nil {
+		t.Error("expected removing a missing rule to fail")
+	}
+}
+
+func TestApplyEscalation(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	newIssue := func(title string, issueType types.IssueType, priority, ageDays int) *types.Issue {
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: priority, IssueType: issueType}
+		if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		if _, err := store.UnderlyingDB().Exec(`UPDATE issues SET created_at = datetime('now', '-' || ? || ' days') WHERE id = ?`, ageDays, issue.ID); err != nil {
+			t.Fatal(err)
+		}
+		return issue
+	}
+	ancientBug := newIssue("Ancient bug", types.TypeBug, 2, 90)
+	oldBug := newIssue("Old bug", types.TypeBug, 2, 40)
+	newBug := newIssue("New bug", types.TypeBug, 2, 5)
+	oldTask := newIssue("Old task", types.TypeTask, 2, 40)
+	closedBug := newIssue("Closed bug", types.TypeBug, 2, 90)
+	if err := store.CloseIssue(ctx, closedBug.ID, "done", "alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	p0, p1, p2 := 0, 1, 2
+	for _, rule := range []*EscalationRule{
+		{IssueType: "bug", Priority: &p2, MinAgeDays: 30, SetPriority: &p1, AddLabel: "aging"},
+		{IssueType: "bug", Priority: &p1, MinAgeDays: 60, SetPriority: &p0},
+	} {
+		if err := store.AddEscalationRule(ctx, rule); err != nil {
+			t.Fatalf("AddEscalationRule failed: %v", err)
+		}
+	}
+
+	report, err := store.ApplyEscalation(ctx, true, "escalation")
+	if err != nil {
+		t.Fatalf("ApplyEscalation dry run failed: %v", err)
+	}
+	if len(report.Escalations) != 2 {
+		t.Fatalf("expected 2 escalations, got %+v", report.Escalations)
+	}
+	ancient, old := report.Escalations[0], report.Escalations[1]
+	if ancient.IssueID != ancientBug.ID || ancient.ToPriority != 0 || len(ancient.RuleIDs) != 2 {
+		t.Errorf("expected %s to chain to P0, got %+v", ancientBug.ID, ancient)
+	}
+	if old.IssueID != oldBug.ID || old.ToPriority != 1 || len(old.Labels) != 1 || old.Labels[0] != "aging" {
+		t.Errorf("expected %s to become P1 with label aging, got %+v", oldBug.ID, old)
+	}
+	if issue, _ := store.GetIssue(ctx, oldBug.ID); issue.Priority != 2 {
+		t.Error("dry run changed priority")
+	}
+
+	if _, err := store.ApplyEscalation(ctx, false, "escalation"); err != nil {
+		t.Fatalf("ApplyEscalation failed: %v", err)
+	}
+	want := map[string]int{ancientBug.ID: 0, oldBug.ID: 1, newBug.ID: 2, oldTask.ID: 2, closedBug.ID: 2}
+	for id, priority := range want {
+		issue, err := store.GetIssue(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if issue.Priority != priority {
+			t.Errorf("%s: expected P%d, got P%d", id, priority, issue.Priority)
+		}
+	}
+	labels, _ := store.GetLabels(ctx, oldBug.ID)
+	if len(labels) != 1 || labels[0] != "aging" {
+		t.Errorf("expected label aging on %s, got %v", oldBug.ID, labels)
+	}
+
+	// Nothing left to do
+	report, err = store.ApplyEscalation(ctx, false, "escalation")
+	if err != nil {
+		t.Fatalf("ApplyEscalation failed: %v", err)
+	}
+	if len(report.Escalations) != 0 {
+		t.Errorf("expected a second run to change nothing, got %+v", report.Escalations)
+	}
+}
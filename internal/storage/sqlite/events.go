@@ -7,12 +7,30 @@ import (
 	"time"
 
 	"github.com/imalsogreg/beads/internal/types"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const limitClause = " LIMIT ?"
 
 // AddComment adds a comment to an issue
 func (s *SQLiteStorage) AddComment(ctx context.Context, issueID, actor, comment string) error {
+	ctx, span := startSpan(ctx, "AddComment", attribute.String("issue.id", issueID))
+	defer span.End()
+
+	// Check comment text for pasted credentials
+	scan, err := s.newSecretScan(ctx)
+	if err != nil {
+		return err
+	}
+	if comment, err = scan.apply("comment", comment); err != nil {
+		return err
+	}
+
+	// Encrypt comment text if field encryption is enabled
+	if comment, err = s.encryptField(comment); err != nil {
+		return err
+	}
+
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -20,9 +38,9 @@ func (s *SQLiteStorage) AddComment(ctx context.Context, issueID, actor, comment
 	defer func() { _ = tx.Rollback() }()
 
 	_, err = tx.ExecContext(ctx, `
-		INSERT INTO events (issue_id, event_type, actor, comment)
-		VALUES (?, ?, ?, ?)
-	`, issueID, types.EventCommented, actor, comment)
+		INSERT INTO events (issue_id, event_type, actor, comment, session_id)
+		VALUES (?, ?, ?, ?, ?)
+	`, issueID, types.EventCommented, actor, comment, s.eventSession(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to add comment: %w", err)
 	}
@@ -46,6 +64,11 @@ func (s *SQLiteStorage) AddComment(ctx context.Context, issueID, actor, comment
 		return fmt.Errorf("failed to mark issue dirty: %w", err)
 	}
 
+	// Record any detected secrets
+	if err := scan.record(ctx, tx, issueID, actor); err != nil {
+		return err
+	}
+
 	return tx.Commit()
 }
 
@@ -60,90 +83,189 @@ func (s *SQLiteStorage) GetEvents(ctx context.Context, issueID string, limit int
 
 	// #nosec G201 - safe SQL with controlled formatting
 	query := fmt.Sprintf(`
-		SELECT id, issue_id, event_type, actor, old_value, new_value, comment, created_at
+		SELECT id, issue_id, event_type, actor, old_value, new_value, comment, created_at, session_id
 		FROM events
 		WHERE issue_id = ?
 		ORDER BY created_at DESC
 		%s
 	`, limitSQL)
 
-	rows, err := s.db.QueryContext(ctx, query, args...)
+	rows, err := s.reads.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	return s.scanEvents(rows)
+}
+
+// GetEventsAfter returns up to limit events recorded after afterEventID,
+// oldest first
+func (s *SQLiteStorage) GetEventsAfter(ctx context.Context, afterEventID int64, limit int) ([]*types.Event, error) {
+	rows, err := s.reads.QueryContext(ctx, `
+		SELECT id, issue_id, event_type, actor, old_value, new_value, comment, created_at, session_id
+		FROM events
+		WHERE id > ?
+		ORDER BY id ASC
+		LIMIT ?
+	`, afterEventID, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get events: %w", err)
 	}
 	defer func() { _ = rows.Close() }()
 
+	return s.scanEvents(rows)
+}
+
+// scanEvents reads event rows, decrypting payloads and comments
+func (s *SQLiteStorage) scanEvents(rows *sql.Rows) ([]*types.Event, error) {
 	var events []*types.Event
 	for rows.Next() {
-		var event types.Event
-		var oldValue, newValue, comment sql.NullString
-
-		err := rows.Scan(
-			&event.ID, &event.IssueID, &event.EventType, &event.Actor,
-			&oldValue, &newValue, &comment, &event.CreatedAt,
-		)
+		event, err := s.scanEvent(rows)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan event: %w", err)
+			return nil, err
 		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
 
-		if oldValue.Valid {
-			event.OldValue = &oldValue.String
-		}
-		if newValue.Valid {
-			event.NewValue = &newValue.String
+// scanEvent reads one event row, decrypting its payloads and comment
+func (s *SQLiteStorage) scanEvent(row rowScanner) (*types.Event, error) {
+	var event types.Event
+	var oldValue, newValue, comment, session sql.NullString
+
+	err := row.Scan(
+		&event.ID, &event.IssueID, &event.EventType, &event.Actor,
+		&oldValue, &newValue, &comment, &event.CreatedAt, &session,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan event: %w", err)
+	}
+
+	if oldValue.Valid {
+		v := s.decryptEventPayload(oldValue.String)
+		event.OldValue = &v
+	}
+	if newValue.Valid {
+		v := s.decryptEventPayload(newValue.String)
+		event.NewValue = &v
+	}
+	if comment.Valid {
+		v := s.decryptField(comment.String)
+		event.Comment = &v
+	}
+	event.SessionID = session.String
+	return &event, nil
+}
+
+// GetChangedIssuesSince returns the distinct IDs of issues with events recorded
+// after afterEventID, along with the newest event ID seen. Pass 0 to start from
+// the beginning; callers that only want future changes should start from
+// GetLatestEventID.
+func (s *SQLiteStorage) GetChangedIssuesSince(ctx context.Context, afterEventID int64) ([]string, int64, error) {
+	rows, err := s.reads.QueryContext(ctx, `
+		SELECT issue_id, MAX(id) FROM events WHERE id > ? GROUP BY issue_id ORDER BY MAX(id)
+	`, afterEventID)
+	if err != nil {
+		return nil, afterEventID, fmt.Errorf("failed to query changed issues: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var ids []string
+	latest := afterEventID
+	for rows.Next() {
+		var id string
+		var eventID int64
+		if err := rows.Scan(&id, &eventID); err != nil {
+			return nil, afterEventID, fmt.Errorf("failed to scan changed issue: %w", err)
 		}
-		if comment.Valid {
-			event.Comment = &comment.String
+		ids = append(ids, id)
+		if eventID > latest {
+			latest = eventID
 		}
+	}
+	return ids, latest, rows.Err()
+}
+
+// GetLatestEventID returns the ID of the newest event, or 0 if there are none
+func (s *SQLiteStorage) GetLatestEventID(ctx context.Context) (int64, error) {
+	var id int64
+	if err := s.reads.QueryRowContext(ctx, `SELECT COALESCE(MAX(id), 0) FROM events`).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to get latest event: %w", err)
+	}
+	return id, nil
+}
 
-		events = append(events, &event)
+// GetAllIssueIDs returns the IDs of every issue
+func (s *SQLiteStorage) GetAllIssueIDs(ctx context.Context) ([]string, error) {
+	rows, err := s.reads.QueryContext(ctx, `SELECT id FROM issues ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query issue IDs: %w", err)
 	}
+	defer func() { _ = rows.Close() }()
 
-	return events, nil
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
 }
 
 // GetStatistics returns aggregate statistics
 func (s *SQLiteStorage) GetStatistics(ctx context.Context) (*types.Statistics, error) {
+	ctx, span := startSpan(ctx, "GetStatistics")
+	defer span.End()
+
 	var stats types.Statistics
 
 	// Get counts
-	err := s.db.QueryRowContext(ctx, `
+	err := s.reads.QueryRowContext(ctx, `
 		SELECT
 			COUNT(*) as total,
 			COALESCE(SUM(CASE WHEN status = 'open' THEN 1 ELSE 0 END), 0) as open,
 			COALESCE(SUM(CASE WHEN status = 'in_progress' THEN 1 ELSE 0 END), 0) as in_progress,
 			COALESCE(SUM(CASE WHEN status = 'closed' THEN 1 ELSE 0 END), 0) as closed
 		FROM issues
+		WHERE deleted_at IS NULL
 	`).Scan(&stats.TotalIssues, &stats.OpenIssues, &stats.InProgressIssues, &stats.ClosedIssues)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get issue counts: %w", err)
 	}
 
 	// Get blocked count
-	err = s.db.QueryRowContext(ctx, `
+	err = s.reads.QueryRowContext(ctx, `
 		SELECT COUNT(DISTINCT i.id)
 		FROM issues i
 		JOIN dependencies d ON i.id = d.issue_id
 		JOIN issues blocker ON d.depends_on_id = blocker.id
-		WHERE i.status IN ('open', 'in_progress', 'blocked')
+		WHERE i.status != 'closed'
+		  AND i.deleted_at IS NULL
 		  AND d.type = 'blocks'
-		  AND blocker.status IN ('open', 'in_progress', 'blocked')
+		  AND blocker.status != 'closed'
+		  AND blocker.deleted_at IS NULL
 	`).Scan(&stats.BlockedIssues)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get blocked count: %w", err)
 	}
 
 	// Get ready count
-	err = s.db.QueryRowContext(ctx, `
+	err = s.reads.QueryRowContext(ctx, `
 		SELECT COUNT(*)
 		FROM issues i
 		WHERE i.status = 'open'
+		  AND i.deleted_at IS NULL
 		  AND NOT EXISTS (
 		    SELECT 1 FROM dependencies d
 		    JOIN issues blocked ON d.depends_on_id = blocked.id
 		    WHERE d.issue_id = i.id
 		      AND d.type = 'blocks'
-		      AND blocked.status IN ('open', 'in_progress', 'blocked')
+		      AND blocked.status != 'closed'
+		      AND blocked.deleted_at IS NULL
 		  )
 	`).Scan(&stats.ReadyIssues)
 	if err != nil {
@@ -152,12 +274,12 @@ func (s *SQLiteStorage) GetStatistics(ctx context.Context) (*types.Statistics, e
 
 	// Get average lead time (hours from created to closed)
 	var avgLeadTime sql.NullFloat64
-	err = s.db.QueryRowContext(ctx, `
+	err = s.reads.QueryRowContext(ctx, `
 		SELECT AVG(
 			(julianday(closed_at) - julianday(created_at)) * 24
 		)
 		FROM issues
-		WHERE closed_at IS NOT NULL
+		WHERE closed_at IS NOT NULL AND deleted_at IS NULL
 	`).Scan(&avgLeadTime)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, fmt.Errorf("failed to get lead time: %w", err)
@@ -167,14 +289,14 @@ func (s *SQLiteStorage) GetStatistics(ctx context.Context) (*types.Statistics, e
 	}
 
 	// Get epics eligible for closure count
-	err = s.db.QueryRowContext(ctx, `
+	err = s.reads.QueryRowContext(ctx, `
 		WITH epic_children AS (
 			SELECT 
 				d.depends_on_id AS epic_id,
 				i.status AS child_status
 			FROM dependencies d
 			JOIN issues i ON i.id = d.issue_id
-			WHERE d.type = 'parent-child'
+			WHERE d.type = 'parent-child' AND i.deleted_at IS NULL
 		),
 		epic_stats AS (
 			SELECT 
@@ -189,6 +311,7 @@ func (s *SQLiteStorage) GetStatistics(ctx context.Context) (*types.Statistics, e
 		JOIN epic_stats es ON es.epic_id = i.id
 		WHERE i.issue_type = 'epic'
 		  AND i.status != 'closed'
+		  AND i.deleted_at IS NULL
 		  AND es.total_children > 0
 		  AND es.closed_children = es.total_children
 	`).Scan(&stats.EpicsEligibleForClosure)
@@ -196,5 +319,9 @@ func (s *SQLiteStorage) GetStatistics(ctx context.Context) (*types.Statistics, e
 		return nil, fmt.Errorf("failed to get eligible epics count: %w", err)
 	}
 
+	if err := s.addTimeStatistics(ctx, &stats); err != nil {
+		return nil, err
+	}
+
 	return &stats, nil
 }
@@ -0,0 +1,315 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// idBatchSize is how many IDs one IN (...) list binds, well under SQLite's
+// limit on variables
+const idBatchSize = 500
+
+// forIDBatches calls fn for each batch of up to idBatchSize ids, with the
+// placeholders of an IN list and the ids as its arguments
+func forIDBatches(ids []string, fn func(placeholders string, args []interface{}) error) error {
+	for start := 0; start < len(ids); start += idBatchSize {
+		batch := ids[start:min(start+idBatchSize, len(ids))]
+		args := make([]interface{}, len(batch))
+		for i, id := range batch {
+			args[i] = id
+		}
+		if err := fn(strings.TrimSuffix(strings.Repeat("?, ", len(batch)), ", "), args); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetCommentsForIssues returns the comments of each of issueIDs, oldest
+// first, as GetIssueComments does for one issue. It takes two queries per
+// few hundred issues rather than two per issue.
+func (s *SQLiteStorage) GetCommentsForIssues(ctx context.Context, issueIDs []string) (map[string][]*types.Comment, error) {
+	comments := make(map[string][]*types.Comment)
+	byID := make(map[int64]*types.Comment)
+	err := forIDBatches(issueIDs, func(placeholders string, args []interface{}) error {
+		// #nosec G201 - only placeholders are interpolated
+		rows, err := s.reads.QueryContext(ctx, fmt.Sprintf(`
+			SELECT id, issue_id, author, text, created_at, reply_to, updated_at
+			FROM comments
+			WHERE issue_id IN (%s)
+			ORDER BY created_at ASC, id ASC
+		`, placeholders), args...)
+		if err != nil {
+			return fmt.Errorf("failed to query comments: %w", err)
+		}
+		defer func() { _ = rows.Close() }()
+		for rows.Next() {
+			comment, err := scanComment(rows)
+			if err != nil {
+				return err
+			}
+			comment.Text = s.decryptField(comment.Text)
+			comments[comment.IssueID] = append(comments[comment.IssueID], comment)
+			byID[comment.ID] = comment
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("error iterating comments: %w", err)
+		}
+
+		// #nosec G201 - only placeholders are interpolated
+		reactions, err := s.reads.QueryContext(ctx, fmt.Sprintf(`
+			SELECT r.comment_id, r.reaction, COUNT(*)
+			FROM comment_reactions r JOIN comments c ON c.id = r.comment_id
+			WHERE c.issue_id IN (%s)
+			GROUP BY r.comment_id, r.reaction
+		`, placeholders), args...)
+		if err != nil {
+			return fmt.Errorf("failed to query reactions: %w", err)
+		}
+		defer func() { _ = reactions.Close() }()
+		for reactions.Next() {
+			var commentID int64
+			var reaction string
+			var count int
+			if err := reactions.Scan(&commentID, &reaction, &count); err != nil {
+				return fmt.Errorf("failed to scan reaction: %w", err)
+			}
+			if comment := byID[commentID]; comment != nil {
+				if comment.Reactions == nil {
+					comment.Reactions = make(map[string]int)
+				}
+				comment.Reactions[reaction] = count
+			}
+		}
+		return reactions.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// GetDependencyRecordsForIssues returns the dependency records of each of
+// issueIDs, as GetDependencyRecords does for one issue
+func (s *SQLiteStorage) GetDependencyRecordsForIssues(ctx context.Context, issueIDs []string) (map[string][]*types.Dependency, error) {
+	deps := make(map[string][]*types.Dependency)
+	err := forIDBatches(issueIDs, func(placeholders string, args []interface{}) error {
+		// #nosec G201 - only placeholders are interpolated
+		rows, err := s.reads.QueryContext(ctx, fmt.Sprintf(`
+			SELECT issue_id, depends_on_id, type, created_at, created_by
+			FROM dependencies
+			WHERE issue_id IN (%[1]s)
+			UNION ALL
+			SELECT issue_id, depends_on_ref, type, created_at, created_by
+			FROM remote_dependencies
+			WHERE issue_id IN (%[1]s)
+			ORDER BY created_at ASC
+		`, placeholders), append(args, args...)...)
+		if err != nil {
+			return fmt.Errorf("failed to get dependency records: %w", err)
+		}
+		defer func() { _ = rows.Close() }()
+		for rows.Next() {
+			var dep types.Dependency
+			if err := rows.Scan(&dep.IssueID, &dep.DependsOnID, &dep.Type, &dep.CreatedAt, &dep.CreatedBy); err != nil {
+				return fmt.Errorf("failed to scan dependency: %w", err)
+			}
+			deps[dep.IssueID] = append(deps[dep.IssueID], &dep)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deps, nil
+}
+
+// GetChildrenForIssues returns the direct subtasks of each of parentIDs, as
+// GetChildren does for one issue
+func (s *SQLiteStorage) GetChildrenForIssues(ctx context.Context, parentIDs []string) (map[string][]*types.Issue, error) {
+	type edge struct{ parent, child string }
+	var edges []edge
+	var childIDs []string
+	err := forIDBatches(parentIDs, func(placeholders string, args []interface{}) error {
+		// #nosec G201 - only placeholders are interpolated
+		rows, err := s.reads.QueryContext(ctx, fmt.Sprintf(`
+			SELECT d.depends_on_id, i.id
+			FROM dependencies d
+			CROSS JOIN issues i ON i.id = d.issue_id
+			WHERE d.depends_on_id IN (%s) AND d.type = 'parent-child' AND i.deleted_at IS NULL
+			ORDER BY i.priority ASC, i.created_at ASC
+		`, placeholders), args...)
+		if err != nil {
+			return fmt.Errorf("failed to get children: %w", err)
+		}
+		defer func() { _ = rows.Close() }()
+		for rows.Next() {
+			var e edge
+			if err := rows.Scan(&e.parent, &e.child); err != nil {
+				return fmt.Errorf("failed to scan child: %w", err)
+			}
+			edges = append(edges, e)
+			childIDs = append(childIDs, e.child)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	issues := make(map[string]*types.Issue, len(childIDs))
+	err = forIDBatches(childIDs, func(_ string, args []interface{}) error {
+		ids := make([]string, len(args))
+		for i, arg := range args {
+			ids[i] = arg.(string)
+		}
+		batch, err := s.SearchIssues(ctx, "", types.IssueFilter{IDs: ids})
+		for _, issue := range batch {
+			issues[issue.ID] = issue
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	children := make(map[string][]*types.Issue)
+	for _, e := range edges {
+		if issue := issues[e.child]; issue != nil {
+			child := *issue
+			child.ParentID = e.parent
+			children[e.parent] = append(children[e.parent], &child)
+		}
+	}
+	return children, nil
+}
+
+// GetHistoryForIssues returns the revisions of each of issueIDs, oldest
+// first, as GetIssueHistory does for one issue
+func (s *SQLiteStorage) GetHistoryForIssues(ctx context.Context, issueIDs []string) (map[string][]*IssueRevision, error) {
+	history := make(map[string][]*IssueRevision)
+	err := forIDBatches(issueIDs, func(placeholders string, args []interface{}) error {
+		// #nosec G201 - only placeholders are interpolated
+		rows, err := s.reads.QueryContext(ctx, fmt.Sprintf(`
+			SELECT `+revisionColumns+`
+			FROM issue_history
+			WHERE issue_id IN (%s)
+			ORDER BY issue_id, revision
+		`, placeholders), args...)
+		if err != nil {
+			return fmt.Errorf("failed to get issue history: %w", err)
+		}
+		defer func() { _ = rows.Close() }()
+		for rows.Next() {
+			rev, err := s.scanRevision(rows)
+			if err != nil {
+				return err
+			}
+			history[rev.IssueID] = append(history[rev.IssueID], rev)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// issueIDs returns the IDs of issues
+func issueIDs(issues []*types.Issue) []string {
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	return ids
+}
+
+// populateLabels fills in the labels of issues, sorted as GetLabels sorts
+// them
+func (s *SQLiteStorage) populateLabels(ctx context.Context, issues []*types.Issue) error {
+	labels := make(map[string][]string)
+	err := forIDBatches(issueIDs(issues), func(placeholders string, args []interface{}) error {
+		// #nosec G201 - only placeholders are interpolated
+		rows, err := s.reads.QueryContext(ctx, fmt.Sprintf(`
+			SELECT issue_id, label FROM labels WHERE issue_id IN (%s) ORDER BY issue_id, label
+		`, placeholders), args...)
+		if err != nil {
+			return fmt.Errorf("failed to get labels: %w", err)
+		}
+		defer func() { _ = rows.Close() }()
+		for rows.Next() {
+			var issueID, label string
+			if err := rows.Scan(&issueID, &label); err != nil {
+				return err
+			}
+			labels[issueID] = append(labels[issueID], label)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		issue.Labels = labels[issue.ID]
+	}
+	return nil
+}
+
+// populateCounts fills in the dependency, dependent, and comment counts of
+// issues. Dependencies on other workspaces' issues count; trashed
+// dependents don't.
+//
+// The CROSS JOINs here and in GetChildrenForIssues pin the join order:
+// left to itself, SQLite walks every live issue by idx_issues_deleted_at and
+// probes its dependencies, which is a hundred times slower than looking up
+// the batch's dependents first.
+func (s *SQLiteStorage) populateCounts(ctx context.Context, issues []*types.Issue) error {
+	byID := make(map[string]*types.Issue, len(issues))
+	for _, issue := range issues {
+		byID[issue.ID] = issue
+	}
+	return forIDBatches(issueIDs(issues), func(placeholders string, args []interface{}) error {
+		// #nosec G201 - only placeholders are interpolated
+		rows, err := s.reads.QueryContext(ctx, fmt.Sprintf(`
+			SELECT issue_id, 'dependency', COUNT(*) FROM (
+				SELECT issue_id FROM dependencies WHERE issue_id IN (%[1]s)
+				UNION ALL
+				SELECT issue_id FROM remote_dependencies WHERE issue_id IN (%[1]s)
+			) GROUP BY issue_id
+			UNION ALL
+			SELECT d.depends_on_id, 'dependent', COUNT(*)
+			FROM dependencies d CROSS JOIN issues i ON i.id = d.issue_id
+			WHERE d.depends_on_id IN (%[1]s) AND i.deleted_at IS NULL
+			GROUP BY d.depends_on_id
+			UNION ALL
+			SELECT issue_id, 'comment', COUNT(*) FROM comments WHERE issue_id IN (%[1]s) GROUP BY issue_id
+		`, placeholders), append(append(append(args, args...), args...), args...)...)
+		if err != nil {
+			return fmt.Errorf("failed to count dependencies and comments: %w", err)
+		}
+		defer func() { _ = rows.Close() }()
+		for rows.Next() {
+			var issueID, kind string
+			var count int
+			if err := rows.Scan(&issueID, &kind, &count); err != nil {
+				return fmt.Errorf("failed to scan count: %w", err)
+			}
+			issue := byID[issueID]
+			if issue == nil {
+				continue
+			}
+			switch kind {
+			case "dependency":
+				issue.DependencyCount = count
+			case "dependent":
+				issue.DependentCount = count
+			case "comment":
+				issue.CommentCount = count
+			}
+		}
+		return rows.Err()
+	})
+}
@@ -0,0 +1,162 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestBatchLookupsMatchSingleIssueOnes(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	create := func(title, parentID string) *types.Issue {
+		t.Helper()
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, ParentID: parentID}
+		if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		return issue
+	}
+	epic := create("Epic", "")
+	other := create("Other", "")
+	first := create("First", epic.ID)
+	second := create("Second", epic.ID)
+	if err := store.AddDependency(ctx, &types.Dependency{IssueID: second.ID, DependsOnID: other.ID, Type: types.DepBlocks}, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	comment, err := store.AddIssueComment(ctx, epic.ID, "alice", "Kickoff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.AddCommentReaction(ctx, epic.ID, comment.ID, "bob", "+1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.AddIssueComment(ctx, other.ID, "bob", "Later"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.UpdateIssue(ctx, first.ID, map[string]interface{}{"priority": 1}, "alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := []string{epic.ID, other.ID, first.ID, second.ID}
+	comments, err := store.GetCommentsForIssues(ctx, ids)
+	if err != nil {
+		t.Fatalf("GetCommentsForIssues failed: %v", err)
+	}
+	deps, err := store.GetDependencyRecordsForIssues(ctx, ids)
+	if err != nil {
+		t.Fatalf("GetDependencyRecordsForIssues failed: %v", err)
+	}
+	children, err := store.GetChildrenForIssues(ctx, ids)
+	if err != nil {
+		t.Fatalf("GetChildrenForIssues failed: %v", err)
+	}
+	history, err := store.GetHistoryForIssues(ctx, ids)
+	if err != nil {
+		t.Fatalf("GetHistoryForIssues failed: %v", err)
+	}
+
+	for _, id := range ids {
+		wantComments, _ := store.GetIssueComments(ctx, id)
+		if !reflect.DeepEqual(comments[id], wantComments) {
+			t.Errorf("%s: comments %+v, want %+v", id, comments[id], wantComments)
+		}
+		wantDeps, _ := store.GetDependencyRecords(ctx, id)
+		if !reflect.DeepEqual(deps[id], wantDeps) {
+			t.Errorf("%s: dependencies %+v, want %+v", id, deps[id], wantDeps)
+		}
+		wantHistory, _ := store.GetIssueHistory(ctx, id)
+		if len(history[id]) != len(wantHistory) {
+			t.Errorf("%s: %d revisions, want %d", id, len(history[id]), len(wantHistory))
+		}
+		wantChildren, _ := store.GetChildren(ctx, id)
+		if len(children[id]) != len(wantChildren) {
+			t.Fatalf("%s: %d children, want %d", id, len(children[id]), len(wantChildren))
+		}
+		for i, child := range children[id] {
+			if child.ID != wantChildren[i].ID || child.ParentID != id {
+				t.Errorf("%s: child %d is %s (parent %s), want %s", id, i, child.ID, child.ParentID, wantChildren[i].ID)
+			}
+		}
+	}
+	if comments[epic.ID][0].Reactions["+1"] != 1 {
+		t.Errorf("Expected the reaction counted, got %+v", comments[epic.ID][0])
+	}
+
+	// More IDs than fit in one query
+	many := make([]string, 2*idBatchSize+1)
+	for i := range many {
+		many[i] = fmt.Sprintf("missing-%d", i)
+	}
+	many[len(many)-1] = second.ID
+	deps, err = store.GetDependencyRecordsForIssues(ctx, many)
+	if err != nil {
+		t.Fatalf("GetDependencyRecordsForIssues failed: %v", err)
+	}
+	if len(deps) != 1 || len(deps[second.ID]) != 2 {
+		t.Errorf("Expected only %s's two dependencies, got %v", second.ID, deps)
+	}
+}
+
+func TestSearchIssuesWithCounts(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	var issues []*types.Issue
+	for _, title := range []string{"Base", "Blocked", "Trashed"} {
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		issues = append(issues, issue)
+	}
+	base, blocked, trashed := issues[0], issues[1], issues[2]
+	for _, dependent := range []*types.Issue{blocked, trashed} {
+		if err := store.AddDependency(ctx, &types.Dependency{IssueID: dependent.ID, DependsOnID: base.ID, Type: types.DepBlocks}, "alice"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, text := range []string{"One", "Two"} {
+		if _, err := store.AddIssueComment(ctx, base.ID, "alice", text); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, label := range []string{"ui", "backend"} {
+		if err := store.AddLabel(ctx, base.ID, label, "alice"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.SoftDeleteIssue(ctx, trashed.ID, "alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := store.SearchIssues(ctx, "", types.IssueFilter{WithCounts: true})
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+	byID := make(map[string]*types.Issue)
+	for _, issue := range found {
+		byID[issue.ID] = issue
+	}
+	if got := byID[base.ID]; got == nil || got.DependentCount != 1 || got.CommentCount != 2 || !reflect.DeepEqual(got.Labels, []string{"backend", "ui"}) {
+		t.Errorf("Expected %s with 1 live dependent, 2 comments, and sorted labels, got %+v", base.ID, got)
+	}
+	if got := byID[blocked.ID]; got == nil || got.DependencyCount != 1 || got.DependentCount != 0 {
+		t.Errorf("Expected %s with 1 dependency, got %+v", blocked.ID, got)
+	}
+
+	// Without WithCounts, as for exports, the counts stay zero
+	found, err = store.SearchIssues(ctx, "", types.IssueFilter{IDs: []string{base.ID}})
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+	if len(found) != 1 || found[0].CommentCount != 0 || len(found[0].Labels) != 2 {
+		t.Errorf("Expected labels but no counts without WithCounts, got %+v", found)
+	}
+}
@@ -0,0 +1,104 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ExternalMapping links a local issue to an issue in another tracker.
+// System names the tracker instance, e.g. "github:owner/repo".
+type ExternalMapping struct {
+	System            string     `json:"system"`
+	ExternalID        string     `json:"external_id"`
+	IssueID           string     `json:"issue_id"`
+	SyncedHash        string     `json:"synced_hash"`
+	ExternalUpdatedAt *time.Time `json:"external_updated_at,omitempty"`
+	SyncedAt          time.Time  `json:"synced_at"`
+}
+
+// SetExternalMapping creates or replaces the mapping for m.System and
+// m.ExternalID, and sets SyncedAt
+func (s *SQLiteStorage) SetExternalMapping(ctx context.Context, m *ExternalMapping) error {
+	if m.System == "" || m.ExternalID == "" || m.IssueID == "" {
+		return fmt.Errorf("external mapping requires system, external ID, and issue ID")
+	}
+
+	m.SyncedAt = time.Now()
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO external_mappings (system, external_id, issue_id, synced_hash, external_updated_at, synced_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (system, external_id) DO UPDATE SET
+			issue_id = excluded.issue_id,
+			synced_hash = excluded.synced_hash,
+			external_updated_at = excluded.external_updated_at,
+			synced_at = excluded.synced_at
+	`, m.System, m.ExternalID, m.IssueID, m.SyncedHash, m.ExternalUpdatedAt, m.SyncedAt)
+	if err != nil {
+		return fmt.Errorf("failed to set external mapping: %w", err)
+	}
+	return nil
+}
+
+// GetExternalMapping returns the mapping for an external issue, or nil if
+// it isn't linked
+func (s *SQLiteStorage) GetExternalMapping(ctx context.Context, system, externalID string) (*ExternalMapping, error) {
+	row := s.reads.QueryRowContext(ctx, `
+		SELECT system, external_id, issue_id, synced_hash, external_updated_at, synced_at
+		FROM external_mappings
+		WHERE system = ? AND external_id = ?
+	`, system, externalID)
+	return scanExternalMapping(row)
+}
+
+// GetExternalMappingForIssue returns the mapping for a local issue, or nil
+// if it isn't linked
+func (s *SQLiteStorage) GetExternalMappingForIssue(ctx context.Context, system, issueID string) (*ExternalMapping, error) {
+	row := s.reads.QueryRowContext(ctx, `
+		SELECT system, external_id, issue_id, synced_hash, external_updated_at, synced_at
+		FROM external_mappings
+		WHERE system = ? AND issue_id = ?
+	`, system, issueID)
+	return scanExternalMapping(row)
+}
+
+// ListExternalMappings returns every mapping for a system
+func (s *SQLiteStorage) ListExternalMappings(ctx context.Context, system string) ([]*ExternalMapping, error) {
+	rows, err := s.reads.QueryContext(ctx, `
+		SELECT system, external_id, issue_id, synced_hash, external_updated_at, synced_at
+		FROM external_mappings
+		WHERE system = ?
+		ORDER BY issue_id
+	`, system)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list external mappings: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var mappings []*ExternalMapping
+	for rows.Next() {
+		m, err := scanExternalMapping(rows)
+		if err != nil {
+			return nil, err
+		}
+		mappings = append(mappings, m)
+	}
+	return mappings, rows.Err()
+}
+
+func scanExternalMapping(row rowScanner) (*ExternalMapping, error) {
+	var m ExternalMapping
+	var externalUpdatedAt sql.NullTime
+	err := row.Scan(&m.System, &m.ExternalID, &m.IssueID, &m.SyncedHash, &externalUpdatedAt, &m.SyncedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan external mapping: %w", err)
+	}
+	if externalUpdatedAt.Valid {
+		m.ExternalUpdatedAt = &externalUpdatedAt.Time
+	}
+	return &m, nil
+}
@@ -0,0 +1,61 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestExternalMappings(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	issue := &types.Issue{Title: "Linked", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	updated := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	m := &ExternalMapping{System: "github:acme/widgets", ExternalID: "7", IssueID: issue.ID, SyncedHash: "abc", ExternalUpdatedAt: &updated}
+	if err := store.SetExternalMapping(ctx, m); err != nil {
+		t.Fatalf("SetExternalMapping failed: %v", err)
+	}
+
+	got, err := store.GetExternalMapping(ctx, "github:acme/widgets", "7")
+	if err != nil || got == nil {
+		t.Fatalf("GetExternalMapping failed: %v", err)
+	}
+	if got.IssueID != issue.ID || got.SyncedHash != "abc" || got.ExternalUpdatedAt == nil || !got.ExternalUpdatedAt.Equal(updated) {
+		t.Errorf("Unexpected mapping %+v", got)
+	}
+
+	// Upsert replaces the sync state
+	m.SyncedHash = "def"
+	if err := store.SetExternalMapping(ctx, m); err != nil {
+		t.Fatalf("SetExternalMapping failed: %v", err)
+	}
+	got, _ = store.GetExternalMappingForIssue(ctx, "github:acme/widgets", issue.ID)
+	if got == nil || got.SyncedHash != "def" {
+		t.Errorf("Expected updated hash, got %+v", got)
+	}
+
+	if got, _ := store.GetExternalMapping(ctx, "github:other/repo", "7"); got != nil {
+		t.Errorf("Expected no mapping for another system, got %+v", got)
+	}
+
+	// Deleting the issue drops its mapping
+	if err := store.DeleteIssue(ctx, issue.ID); err != nil {
+		t.Fatalf("DeleteIssue failed: %v", err)
+	}
+	mappings, err := store.ListExternalMappings(ctx, "github:acme/widgets")
+	if err != nil {
+		t.Fatalf("ListExternalMappings failed: %v", err)
+	}
+	if len(mappings) != 0 {
+		t.Errorf("Expected mapping to be deleted with issue, got %+v", mappings)
+	}
+}
@@ -0,0 +1,494 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/imalsogreg/beads/internal/fieldcrypt"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// Metadata keys for field encryption state
+const (
+	metaFieldEncryptionKey      = "field_encryption.wrapped_key"
+	metaFieldEncryptionProvider = "field_encryption.provider"
+)
+
+// encryptedEventFields lists the keys inside event old/new JSON payloads that hold sensitive text
+var encryptedEventFields = []string{"description", "notes"}
+
+// FieldEncryptionStatus reports whether field encryption is enabled and usable
+type FieldEncryptionStatus struct {
+	Enabled   bool   `json:"enabled"`
+	Provider  string `json:"provider,omitempty"`
+	KeyLoaded bool   `json:"key_loaded"`
+}
+
+// FieldEncryptionResult summarizes a bulk encrypt or decrypt pass
+type FieldEncryptionResult struct {
+	Issues         int  `json:"issues"`
+	Comments       int  `json:"comments"`
+	Events         int  `json:"events"`
+	HistorySkipped bool `json:"history_skipped,omitempty"`
+}
+
+// loadFieldEncryption checks whether the database has field encryption enabled and,
+// if a key provider is configured in the environment, unwraps the data key.
+// Without a provider, encrypted values are returned as ciphertext and writes of
+// sensitive fields are rejected.
+func (s *SQLiteStorage) loadFieldEncryption(ctx context.Context) error {
+	wrapped, err := s.GetMetadata(ctx, metaFieldEncryptionKey)
+	if err != nil {
+		return fmt.Errorf("failed to read field encryption state: %w", err)
+	}
+	if wrapped == "" {
+		return nil
+	}
+	s.encryptionRequired = true
+
+	provider, err := fieldcrypt.ProviderFromEnv()
+	if err != nil {
+		return err
+	}
+	if provider == nil {
+		return nil
+	}
+	return s.LoadFieldCipher(ctx, provider)
+}
+
+// LoadFieldCipher unwraps the workspace data key with the given provider so that
+// sensitive fields are transparently encrypted on write and decrypted on read.
+func (s *SQLiteStorage) LoadFieldCipher(ctx context.Context, provider fieldcrypt.KeyProvider) error {
+	wrapped, err := s.GetMetadata(ctx, metaFieldEncryptionKey)
+	if err != nil {
+		return fmt.Errorf("failed to read wrapped key: %w", err)
+	}
+	if wrapped == "" {
+		return fmt.Errorf("field encryption is not enabled")
+	}
+	raw, err := base64.StdEncoding.DecodeString(wrapped)
+	if err != nil {
+		return fmt.Errorf("malformed wrapped key: %w", err)
+	}
+	dek, err := provider.UnwrapKey(raw)
+	if err != nil {
+		return err
+	}
+	c, err := fieldcrypt.NewCipher(dek)
+	if err != nil {
+		return err
+	}
+	s.fieldCipher = c
+	s.encryptionRequired = true
+	return nil
+}
+
+// GetFieldEncryptionStatus reports the field encryption state of the database
+func (s *SQLiteStorage) GetFieldEncryptionStatus(ctx context.Context) (*FieldEncryptionStatus, error) {
+	wrapped, err := s.GetMetadata(ctx, metaFieldEncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	provider, err := s.GetMetadata(ctx, metaFieldEncryptionProvider)
+	if err != nil {
+		return nil, err
+	}
+	return &FieldEncryptionStatus{
+		Enabled:   wrapped != "",
+		Provider:  provider,
+		KeyLoaded: s.fieldCipher != nil,
+	}, nil
+}
+
+// EnableFieldEncryption generates a workspace data key, stores it wrapped by the
+// provider, and encrypts existing descriptions, notes, and comments in place.
+// Event history is only rewritten while the audit chain is empty, so enabling
+// encryption never invalidates sealed history.
+func (s *SQLiteStorage) EnableFieldEncryption(ctx context.Context, provider fieldcrypt.KeyProvider) (*FieldEncryptionResult, error) {
+	existing, err := s.GetMetadata(ctx, metaFieldEncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	if existing != "" {
+		return nil, fmt.Errorf("field encryption is already enabled")
+	}
+
+	dek, err := fieldcrypt.NewDataKey()
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := provider.WrapKey(dek)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap data key: %w", err)
+	}
+	c, err := fieldcrypt.NewCipher(dek)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.rewriteSensitiveFields(ctx, c.Encrypt, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `INSERT OR REPLACE INTO metadata (key, value) VALUES (?, ?)`,
+			metaFieldEncryptionKey, base64.StdEncoding.EncodeToString(wrapped)); err != nil {
+			return fmt.Errorf("failed to store wrapped key: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT OR REPLACE INTO metadata (key, value) VALUES (?, ?)`,
+			metaFieldEncryptionProvider, provider.Name()); err != nil {
+			return fmt.Errorf("failed to store key provider: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.fieldCipher = c
+	s.encryptionRequired = true
+	return result, nil
+}
+
+// DisableFieldEncryption decrypts all sensitive fields and removes the wrapped key.
+// The data key must already be loaded.
+func (s *SQLiteStorage) DisableFieldEncryption(ctx context.Context) (*FieldEncryptionResult, error) {
+	if s.fieldCipher == nil {
+		return nil, fmt.Errorf("field encryption key is not loaded (set %s or the KMS commands)", fieldcrypt.EnvKey)
+	}
+
+	result, err := s.rewriteSensitiveFields(ctx, s.fieldCipher.Decrypt, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `DELETE FROM metadata WHERE key IN (?, ?)`,
+			metaFieldEncryptionKey, metaFieldEncryptionProvider)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.fieldCipher = nil
+	s.encryptionRequired = false
+	return result, nil
+}
+
+// rewriteSensitiveFields applies transform to every sensitive stored value in one transaction
+func (s *SQLiteStorage) rewriteSensitiveFields(ctx context.Context, transform func(string) (string, error), finish func(*sql.Tx) error) (*FieldEncryptionResult, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	result := &FieldEncryptionResult{}
+
+	// Issues
+	type issueRow struct{ id, description, notes string }
+	var issueRows []issueRow
+	rows, err := tx.QueryContext(ctx, `SELECT id, description, notes FROM issues`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query issues: %w", err)
+	}
+	for rows.Next() {
+		var r issueRow
+		if err := rows.Scan(&r.id, &r.description, &r.notes); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("failed to scan issue: %w", err)
+		}
+		issueRows = append(issueRows, r)
+	}
+	_ = rows.Close()
+	for _, r := range issueRows {
+		desc, err := transform(r.description)
+		if err != nil {
+			return nil, fmt.Errorf("issue %s description: %w", r.id, err)
+		}
+		notes, err := transform(r.notes)
+		if err != nil {
+			return nil, fmt.Errorf("issue %s notes: %w", r.id, err)
+		}
+		if desc == r.description && notes == r.notes {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE issues SET description = ?, notes = ? WHERE id = ?`, desc, notes, r.id); err != nil {
+			return nil, fmt.Errorf("failed to update issue %s: %w", r.id, err)
+		}
+		result.Issues++
+	}
+
+	// Comments
+	type commentRow struct {
+		id   int64
+		text string
+	}
+	var commentRows []commentRow
+	rows, err = tx.QueryContext(ctx, `SELECT id, text FROM comments`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query comments: %w", err)
+	}
+	for rows.Next() {
+		var r commentRow
+		if err := rows.Scan(&r.id, &r.text); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("failed to scan comment: %w", err)
+		}
+		commentRows = append(commentRows, r)
+	}
+	_ = rows.Close()
+	for _, r := range commentRows {
+		text, err := transform(r.text)
+		if err != nil {
+			return nil, fmt.Errorf("comment %d: %w", r.id, err)
+		}
+		if text == r.text {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE comments SET text = ? WHERE id = ?`, text, r.id); err != nil {
+			return nil, fmt.Errorf("failed to update comment %d: %w", r.id, err)
+		}
+		result.Comments++
+	}
+
+	// Earlier versions of edited comments
+	var editRows []commentRow
+	rows, err = tx.QueryContext(ctx, `SELECT id, text FROM comment_edits`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query comment edits: %w", err)
+	}
+	for rows.Next() {
+		var r commentRow
+		if err := rows.Scan(&r.id, &r.text); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("failed to scan comment edit: %w", err)
+		}
+		editRows = append(editRows, r)
+	}
+	_ = rows.Close()
+	for _, r := range editRows {
+		text, err := transform(r.text)
+		if err != nil {
+			return nil, fmt.Errorf("comment edit %d: %w", r.id, err)
+		}
+		if text == r.text {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE comment_edits SET text = ? WHERE id = ?`, text, r.id); err != nil {
+			return nil, fmt.Errorf("failed to update comment edit %d: %w", r.id, err)
+		}
+	}
+
+	// Event history, unless it is already sealed into the audit chain
+	var sealed int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM audit_chain`).Scan(&sealed); err != nil {
+		return nil, fmt.Errorf("failed to check audit chain: %w", err)
+	}
+	if sealed > 0 {
+		result.HistorySkipped = true
+	} else {
+		n, err := rewriteEventHistory(ctx, tx, transform)
+		if err != nil {
+			return nil, err
+		}
+		result.Events = n
+	}
+
+	if err := finish(tx); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+	return result, nil
+}
+
+// rewriteEventHistory transforms comment text and sensitive keys inside event payloads
+func rewriteEventHistory(ctx context.Context, tx *sql.Tx, transform func(string) (string, error)) (int, error) {
+	type eventRow struct {
+		id                          int64
+		oldValue, newValue, comment sql.NullString
+	}
+	var eventRows []eventRow
+	rows, err := tx.QueryContext(ctx, `SELECT id, old_value, new_value, comment FROM events`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query events: %w", err)
+	}
+	for rows.Next() {
+		var r eventRow
+		if err := rows.Scan(&r.id, &r.oldValue, &r.newValue, &r.comment); err != nil {
+			_ = rows.Close()
+			return 0, fmt.Errorf("failed to scan event: %w", err)
+		}
+		eventRows = append(eventRows, r)
+	}
+	_ = rows.Close()
+
+	count := 0
+	for _, r := range eventRows {
+		oldValue := transformEventPayload(r.oldValue, transform)
+		newValue := transformEventPayload(r.newValue, transform)
+		comment := r.comment
+		if comment.Valid {
+			text, err := transform(comment.String)
+			if err != nil {
+				return 0, fmt.Errorf("event %d: %w", r.id, err)
+			}
+			comment.String = text
+		}
+		if oldValue == r.oldValue && newValue == r.newValue && comment == r.comment {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE events SET old_value = ?, new_value = ?, comment = ? WHERE id = ?`,
+			oldValue, newValue, comment, r.id); err != nil {
+			return 0, fmt.Errorf("failed to update event %d: %w", r.id, err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+// transformEventPayload applies transform to sensitive keys of a JSON object payload.
+// Payloads that are not JSON objects are returned unchanged.
+func transformEventPayload(payload sql.NullString, transform func(string) (string, error)) sql.NullString {
+	if !payload.Valid || payload.String == "" {
+		return payload
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal([]byte(payload.String), &obj); err != nil {
+		return payload
+	}
+	changed := false
+	for _, key := range encryptedEventFields {
+		v, ok := obj[key].(string)
+		if !ok {
+			continue
+		}
+		out, err := transform(v)
+		if err != nil || out == v {
+			continue
+		}
+		obj[key] = out
+		changed = true
+	}
+	if !changed {
+		return payload
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return payload
+	}
+	return sql.NullString{String: string(data), Valid: true}
+}
+
+// encryptField encrypts a sensitive value for storage. When encryption is enabled
+// but no key is loaded, plaintext writes are rejected rather than stored in the clear.
+func (s *SQLiteStorage) encryptField(value string) (string, error) {
+	if s.fieldCipher != nil {
+		return s.fieldCipher.Encrypt(value)
+	}
+	if s.encryptionRequired && value != "" && !fieldcrypt.IsEncrypted(value) {
+		return "", fmt.Errorf("field encryption is enabled but no key is loaded (set %s or the KMS commands)", fieldcrypt.EnvKey)
+	}
+	return value, nil
+}
+
+// decryptField returns the plaintext of a stored value when the key is loaded.
+// Without a key, or if decryption fails, the stored value is returned as-is.
+func (s *SQLiteStorage) decryptField(value string) string {
+	if s.fieldCipher == nil {
+		return value
+	}
+	plain, err := s.fieldCipher.Decrypt(value)
+	if err != nil {
+		return value
+	}
+	return plain
+}
+
+// decryptIssueFields decrypts the sensitive fields of a scanned issue in place
+func (s *SQLiteStorage) decryptIssueFields(issue *types.Issue) {
+	if s.fieldCipher == nil {
+		return
+	}
+	issue.Description = s.decryptField(issue.Description)
+	issue.Notes = s.decryptField(issue.Notes)
+}
+
+// encryptedIssueCopy returns a shallow copy of issue with sensitive fields encrypted.
+// The original is returned unchanged when encryption is not enabled.
+func (s *SQLiteStorage) encryptedIssueCopy(issue *types.Issue) (*types.Issue, error) {
+	if s.fieldCipher == nil && !s.encryptionRequired {
+		return issue, nil
+	}
+	desc, err := s.encryptField(issue.Description)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := s.encryptField(issue.Notes)
+	if err != nil {
+		return nil, err
+	}
+	stored := *issue
+	stored.Description = desc
+	stored.Notes = notes
+	return &stored, nil
+}
+
+// encryptEventPayload encrypts sensitive keys of an event JSON payload
+func (s *SQLiteStorage) encryptEventPayload(payload string) string {
+	if s.fieldCipher == nil {
+		return payload
+	}
+	return transformEventPayload(sql.NullString{String: payload, Valid: true}, s.fieldCipher.Encrypt).String
+}
+
+// decryptEventPayload decrypts sensitive keys of an event JSON payload
+func (s *SQLiteStorage) decryptEventPayload(payload string) string {
+	if s.fieldCipher == nil {
+		return payload
+	}
+	return transformEventPayload(sql.NullString{String: payload, Valid: true}, s.fieldCipher.Decrypt).String
+}
+
+// EncryptForExport returns a copy of issue (including comments) with sensitive fields
+// encrypted, so JSONL files committed to git never contain plaintext. Issues are
+// returned unchanged when field encryption is not enabled.
+func (s *SQLiteStorage) EncryptForExport(issue *types.Issue) (*types.Issue, error) {
+	if s.fieldCipher == nil {
+		return issue, nil
+	}
+	out, err := s.encryptedIssueCopy(issue)
+	if err != nil {
+		return nil, err
+	}
+	if len(issue.Comments) > 0 {
+		out.Comments = make([]*types.Comment, len(issue.Comments))
+		for i, c := range issue.Comments {
+			text, err := s.encryptField(c.Text)
+			if err != nil {
+				return nil, err
+			}
+			cc := *c
+			cc.Text = text
+			out.Comments[i] = &cc
+		}
+	}
+	return out, nil
+}
+
+// DecryptFromExport is the inverse of EncryptForExport: it returns a copy of
+// an exported issue with its sensitive fields decrypted. Values that can't be
+// decrypted, and every value when encryption is not enabled, are left as-is.
+func (s *SQLiteStorage) DecryptFromExport(issue *types.Issue) *types.Issue {
+	if s.fieldCipher == nil {
+		return issue
+	}
+	out := *issue
+	s.decryptIssueFields(&out)
+	if len(issue.Comments) > 0 {
+		out.Comments = make([]*types.Comment, len(issue.Comments))
+		for i, c := range issue.Comments {
+			cc := *c
+			cc.Text = s.decryptField(c.Text)
+			out.Comments[i] = &cc
+		}
+	}
+	return &out
+}
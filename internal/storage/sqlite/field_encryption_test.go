@@ -0,0 +1,211 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/fieldcrypt"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func newTestKeyProvider(t *testing.T) fieldcrypt.KeyProvider {
+	t.Helper()
+	kek, err := fieldcrypt.NewDataKey()
+	if err != nil {
+		t.Fatalf("NewDataKey failed: %v", err)
+	}
+	p, err := fieldcrypt.NewStaticKeyProvider(kek)
+	if err != nil {
+		t.Fatalf("NewStaticKeyProvider failed: %v", err)
+	}
+	return p
+}
+
+func TestFieldEncryptionRoundTrip(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	before := &types.Issue{
+		Title:       "Pre-existing",
+		Description: "old secret",
+		Status:      types.StatusOpen,
+		Priority:    2,
+		IssueType:   types.TypeTask,
+	}
+	if err := store.CreateIssue(ctx, before, "alice"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	if err := store.AddComment(ctx, before.ID, "alice", "secret comment"); err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+
+	result, err := store.EnableFieldEncryption(ctx, newTestKeyProvider(t))
+	if err != nil {
+		t.Fatalf("EnableFieldEncryption failed: %v", err)
+	}
+	if result.Issues != 1 || result.Events == 0 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	after := &types.Issue{
+		Title:     "Created encrypted",
+		Notes:     "new secret",
+		Status:    types.StatusOpen,
+		Priority:  2,
+		IssueType: types.TypeTask,
+	}
+	if err := store.CreateIssue(ctx, after, "alice"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	// Values are transparently decrypted on read
+	got, err := store.GetIssue(ctx, before.ID)
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if got.Description != "old secret" {
+		t.Errorf("expected decrypted description, got %q", got.Description)
+	}
+	got, err = store.GetIssue(ctx, after.ID)
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if got.Notes != "new secret" {
+		t.Errorf("expected decrypted notes, got %q", got.Notes)
+	}
+	events, err := store.GetEvents(ctx, before.ID, 0)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	for _, e := range events {
+		if e.Comment != nil && fieldcrypt.IsEncrypted(*e.Comment) {
+			t.Errorf("expected decrypted comment, got %q", *e.Comment)
+		}
+	}
+
+	// Nothing sensitive is stored in plaintext
+	db := store.UnderlyingDB()
+	var plaintext int
+	if err := db.QueryRowContext(ctx, `
+		SELECT
+			(SELECT COUNT(*) FROM issues WHERE description LIKE '%secret%' OR notes LIKE '%secret%') +
+			(SELECT COUNT(*) FROM events WHERE comment LIKE '%secret%' OR old_value LIKE '%secret%' OR new_value LIKE '%secret%')
+	`).Scan(&plaintext); err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if plaintext != 0 {
+		t.Errorf("found %d rows with plaintext secrets", plaintext)
+	}
+
+	// Exports are encrypted
+	exported, err := store.EncryptForExport(got)
+	if err != nil {
+		t.Fatalf("EncryptForExport failed: %v", err)
+	}
+	if !fieldcrypt.IsEncrypted(exported.Notes) || got.Notes != "new secret" {
+		t.Errorf("expected encrypted export copy without modifying the original")
+	}
+}
+
+func TestFieldEncryptionWithoutKey(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	ctx := context.Background()
+
+	store, err := New(dbPath)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+	issue := &types.Issue{
+		Title:       "Locked",
+		Description: "secret",
+		Status:      types.StatusOpen,
+		Priority:    2,
+		IssueType:   types.TypeTask,
+	}
+	if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	if _, err := store.EnableFieldEncryption(ctx, newTestKeyProvider(t)); err != nil {
+		t.Fatalf("EnableFieldEncryption failed: %v", err)
+	}
+	store.Close()
+
+	t.Setenv(fieldcrypt.EnvKey, "")
+	t.Setenv(fieldcrypt.EnvKMSWrap, "")
+	t.Setenv(fieldcrypt.EnvKMSUnwrap, "")
+	locked, err := New(dbPath)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer locked.Close()
+
+	got, err := locked.GetIssue(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if !fieldcrypt.IsEncrypted(got.Description) {
+		t.Errorf("expected ciphertext without key, got %q", got.Description)
+	}
+
+	// Writing sensitive fields is rejected, other updates still work
+	if err := locked.UpdateIssue(ctx, issue.ID, map[string]interface{}{"notes": "leak"}, "alice"); err == nil {
+		t.Error("expected notes update to fail without key")
+	}
+	if err := locked.AddComment(ctx, issue.ID, "alice", "leak"); err == nil {
+		t.Error("expected comment to fail without key")
+	}
+	if err := locked.UpdateIssue(ctx, issue.ID, map[string]interface{}{"priority": 1}, "alice"); err != nil {
+		t.Errorf("expected non-sensitive update to succeed: %v", err)
+	}
+
+	status, err := locked.GetFieldEncryptionStatus(ctx)
+	if err != nil {
+		t.Fatalf("GetFieldEncryptionStatus failed: %v", err)
+	}
+	if !status.Enabled || status.KeyLoaded {
+		t.Errorf("unexpected status: %+v", status)
+	}
+}
+
+func TestDisableFieldEncryption(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	issue := &types.Issue{
+		Title:       "Reversible",
+		Description: "secret",
+		Status:      types.StatusOpen,
+		Priority:    2,
+		IssueType:   types.TypeTask,
+	}
+	if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	if _, err := store.EnableFieldEncryption(ctx, newTestKeyProvider(t)); err != nil {
+		t.Fatalf("EnableFieldEncryption failed: %v", err)
+	}
+	if _, err := store.EnableFieldEncryption(ctx, newTestKeyProvider(t)); err == nil {
+		t.Error("expected enabling twice to fail")
+	}
+	if _, err := store.DisableFieldEncryption(ctx); err != nil {
+		t.Fatalf("DisableFieldEncryption failed: %v", err)
+	}
+
+	var desc string
+	if err := store.UnderlyingDB().QueryRowContext(ctx, `SELECT description FROM issues WHERE id = ?`, issue.ID).Scan(&desc); err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if desc != "secret" {
+		t.Errorf("expected plaintext after disable, got %q", desc)
+	}
+	if strings.HasPrefix(desc, fieldcrypt.Prefix) {
+		t.Error("description still encrypted")
+	}
+}
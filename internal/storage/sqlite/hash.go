@@ -10,7 +10,7 @@ import (
 // Returns empty string if no hash is stored (first export).
 func (s *SQLiteStorage) GetExportHash(ctx context.Context, issueID string) (string, error) {
 	var hash string
-	err := s.db.QueryRowContext(ctx, `
+	err := s.reads.QueryRowContext(ctx, `
 		SELECT content_hash FROM export_hashes WHERE issue_id = ?
 	`, issueID).Scan(&hash)
 	
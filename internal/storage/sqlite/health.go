@@ -0,0 +1,70 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// migratedColumns are the columns New adds to databases created before
+// them. A database missing any was last opened by an older bd, or swapped
+// out from under a running server.
+var migratedColumns = []struct{ table, column string }{
+	{"dirty_issues", "content_hash"},
+	{"issue_counters", "prefix"},
+	{"issue_snapshots", "issue_id"},
+	{"export_hashes", "issue_id"},
+	{"issues", "external_ref"},
+	{"issues", "compaction_level"},
+	{"issues", "compacted_at_commit"},
+	{"issues", "deleted_at"},
+	{"issues", "due_date"},
+	{"issues", "start_date"},
+	{"issues", "milestone"},
+	{"issue_redirects", "old_id"},
+	{"remote_dependencies", "depends_on_ref"},
+	{"oplog", "clock"},
+	{"replica_clock", "seq"},
+	{"replica_issues", "origin"},
+	{"replica_fields", "clock"},
+}
+
+// Ping checks that the database answers a query
+func (s *SQLiteStorage) Ping(ctx context.Context) error {
+	var one int
+	if err := s.reads.QueryRowContext(ctx, `SELECT 1`).Scan(&one); err != nil {
+		return fmt.Errorf("database did not answer: %w", err)
+	}
+	return nil
+}
+
+// CheckSchema reports an error naming any migrated tables or columns the
+// database lacks, and any migrations it hasn't had
+func (s *SQLiteStorage) CheckSchema(ctx context.Context) error {
+	var missing []string
+	for _, c := range migratedColumns {
+		var exists bool
+		err := s.reads.QueryRowContext(ctx, `
+			SELECT COUNT(*) > 0 FROM pragma_table_info(?) WHERE name = ?
+		`, c.table, c.column).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("failed to check %s.%s: %w", c.table, c.column, err)
+		}
+		if !exists {
+			missing = append(missing, c.table+"."+c.column)
+		}
+	}
+	applied, err := appliedMigrations(s.reads)
+	if err != nil {
+		return err
+	}
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; !ok {
+			missing = append(missing, "migration "+m.String())
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("schema is missing migrations for %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
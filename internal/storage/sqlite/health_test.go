@@ -0,0 +1,33 @@
+package sqlite
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestCheckSchema(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	if err := store.Ping(ctx); err != nil {
+		t.Fatalf("Ping: %v", err)
+	}
+	if err := store.CheckSchema(ctx); err != nil {
+		t.Fatalf("CheckSchema on a fresh database: %v", err)
+	}
+
+	if _, err := store.db.Exec(`DROP TABLE export_hashes`); err != nil {
+		t.Fatal(err)
+	}
+	err := store.CheckSchema(ctx)
+	if err == nil || !strings.Contains(err.Error(), "export_hashes.issue_id") {
+		t.Errorf("Expected the dropped table to be reported, got %v", err)
+	}
+
+	store.Close()
+	if err := store.Ping(ctx); err == nil {
+		t.Error("Expected Ping to fail on a closed database")
+	}
+}
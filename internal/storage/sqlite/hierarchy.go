@@ -0,0 +1,150 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// GetChildren returns the direct subtasks of an issue: the issues with a
+// parent-child dependency on it
+func (s *SQLiteStorage) GetChildren(ctx context.Context, parentID string) ([]*types.Issue, error) {
+	ctx, span := startSpan(ctx, "GetChildren", attribute.String("issue.id", parentID))
+	defer span.End()
+
+	rows, err := s.reads.QueryContext(ctx, `
+		SELECT i.id, i.title, i.description, i.design, i.acceptance_criteria, i.notes,
+		       i.status, i.priority, i.issue_type, i.assignee, i.estimated_minutes,
+		       i.created_at, i.updated_at, i.closed_at, i.external_ref,
+		       i.due_date, i.start_date, i.milestone
+		FROM issues i
+		JOIN dependencies d ON i.id = d.issue_id
+		WHERE d.depends_on_id = ? AND d.type = 'parent-child' AND i.deleted_at IS NULL
+		ORDER BY i.priority ASC, i.created_at ASC
+	`, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get children: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	children, err := s.scanIssues(ctx, rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, child := range children {
+		child.ParentID = parentID
+	}
+	return children, nil
+}
+
+// getParentID returns the parent of an issue, or "" if it has none
+func (s *SQLiteStorage) getParentID(ctx context.Context, issueID string) (string, error) {
+	var parentID string
+	err := s.reads.QueryRowContext(ctx, `
+		SELECT depends_on_id FROM dependencies
+		WHERE issue_id = ? AND type = 'parent-child'
+		ORDER BY created_at ASC
+		LIMIT 1
+	`, issueID).Scan(&parentID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get parent of %s: %w", issueID, err)
+	}
+	return parentID, nil
+}
+
+// populateParentIDs fills in ParentID for a batch of issues, with a query
+// per few hundred issues
+func (s *SQLiteStorage) populateParentIDs(ctx context.Context, issues []*types.Issue) error {
+	// Oldest link wins, matching getParentID
+	parents := make(map[string]string)
+	err := forIDBatches(issueIDs(issues), func(placeholders string, args []interface{}) error {
+		// #nosec G201 - only placeholders are interpolated
+		rows, err := s.reads.QueryContext(ctx, fmt.Sprintf(`
+			SELECT issue_id, depends_on_id FROM dependencies
+			WHERE type = 'parent-child' AND issue_id IN (%s)
+			ORDER BY created_at DESC
+		`, placeholders), args...)
+		if err != nil {
+			return fmt.Errorf("failed to get parents: %w", err)
+		}
+		defer func() { _ = rows.Close() }()
+		for rows.Next() {
+			var issueID, parentID string
+			if err := rows.Scan(&issueID, &parentID); err != nil {
+				return fmt.Errorf("failed to scan parent: %w", err)
+			}
+			parents[issueID] = parentID
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to read parents: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, issue := range issues {
+		issue.ParentID = parents[issue.ID]
+	}
+	return nil
+}
+
+// linkParentConn records issue as a subtask of issue.ParentID inside the
+// CreateIssue transaction on conn. The new issue is already marked dirty.
+func linkParentConn(ctx context.Context, conn *sql.Conn, issue *types.Issue, actor, session string) error {
+	if issue.ParentID == issue.ID {
+		return fmt.Errorf("issue cannot be its own parent")
+	}
+
+	var parentType types.IssueType
+	err := conn.QueryRowContext(ctx, `SELECT issue_type FROM issues WHERE id = ?`, issue.ParentID).Scan(&parentType)
+	if err == sql.ErrNoRows {
+		return types.NotFound("parent issue", issue.ParentID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check parent %s: %w", issue.ParentID, err)
+	}
+
+	// Same direction rule as AddDependency: an epic can't belong to a non-epic
+	if issue.IssueType == types.TypeEpic && parentType != types.TypeEpic {
+		return fmt.Errorf("invalid parent: an epic cannot be a subtask of %s %s", parentType, issue.ParentID)
+	}
+
+	now := time.Now()
+	_, err = conn.ExecContext(ctx, `
+		INSERT INTO dependencies (issue_id, depends_on_id, type, created_at, created_by)
+		VALUES (?, ?, ?, ?, ?)
+	`, issue.ID, issue.ParentID, types.DepParentChild, now, actor)
+	if err != nil {
+		return fmt.Errorf("failed to add parent: %w", err)
+	}
+
+	_, err = conn.ExecContext(ctx, `
+		INSERT INTO events (issue_id, event_type, actor, comment, session_id)
+		VALUES (?, ?, ?, ?, ?)
+	`, issue.ID, types.EventDependencyAdded, actor,
+		fmt.Sprintf("Added dependency: %s %s %s", issue.ID, types.DepParentChild, issue.ParentID), nullSession(session))
+	if err != nil {
+		return fmt.Errorf("failed to record event: %w", err)
+	}
+
+	// The parent's export line carries the new dependency too
+	_, err = conn.ExecContext(ctx, `
+		INSERT INTO dirty_issues (issue_id, marked_at)
+		VALUES (?, ?)
+		ON CONFLICT (issue_id) DO UPDATE SET marked_at = excluded.marked_at
+	`, issue.ParentID, now)
+	if err != nil {
+		return fmt.Errorf("failed to mark parent dirty: %w", err)
+	}
+
+	return nil
+}
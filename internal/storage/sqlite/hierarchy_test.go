@@ -0,0 +1,132 @@
+package sqlite
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestCreateSubtask(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	parent := &types.Issue{Title: "Ship v2", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeFeature}
+	if err := store.CreateIssue(ctx, parent, "test-user"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	if err := store.ClearDirtyIssues(ctx); err != nil {
+		t.Fatalf("ClearDirtyIssues failed: %v", err)
+	}
+
+	var children []*types.Issue
+	for i, title := range []string{"Write docs", "Cut release"} {
+		child := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 2 - i, IssueType: types.TypeTask, ParentID: parent.ID}
+		if err := store.CreateIssue(ctx, child, "test-user"); err != nil {
+			t.Fatalf("CreateIssue subtask failed: %v", err)
+		}
+		children = append(children, child)
+	}
+
+	got, err := store.GetIssue(ctx, children[0].ID)
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if got.ParentID != parent.ID {
+		t.Errorf("Expected parent %s, got %q", parent.ID, got.ParentID)
+	}
+
+	// The link is an ordinary parent-child dependency
+	deps, err := store.GetDependencyRecords(ctx, children[0].ID)
+	if err != nil {
+		t.Fatalf("GetDependencyRecords failed: %v", err)
+	}
+	if len(deps) != 1 || deps[0].DependsOnID != parent.ID || deps[0].Type != types.DepParentChild {
+		t.Errorf("Expected parent-child dependency on %s, got %+v", parent.ID, deps)
+	}
+
+	// The parent's export line changes too
+	dirty, err := store.GetDirtyIssues(ctx)
+	if err != nil {
+		t.Fatalf("GetDirtyIssues failed: %v", err)
+	}
+	found := false
+	for _, id := range dirty {
+		if id == parent.ID {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected parent %s to be dirty, got %v", parent.ID, dirty)
+	}
+
+	// Children come back by priority
+	kids, err := store.GetChildren(ctx, parent.ID)
+	if err != nil {
+		t.Fatalf("GetChildren failed: %v", err)
+	}
+	if len(kids) != 2 || kids[0].ID != children[1].ID || kids[1].ID != children[0].ID {
+		t.Fatalf("Expected children [%s %s], got %v", children[1].ID, children[0].ID, kids)
+	}
+	for _, kid := range kids {
+		if kid.ParentID != parent.ID {
+			t.Errorf("%s: expected parent %s, got %q", kid.ID, parent.ID, kid.ParentID)
+		}
+	}
+
+	// SearchIssues reports parents as well
+	issues, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+	for _, issue := range issues {
+		want := parent.ID
+		if issue.ID == parent.ID {
+			want = ""
+		}
+		if issue.ParentID != want {
+			t.Errorf("%s: expected parent %q, got %q", issue.ID, want, issue.ParentID)
+		}
+	}
+}
+
+func TestCreateSubtaskValidation(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	task := &types.Issue{Title: "Task", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, task, "test-user"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		issue *types.Issue
+		want  string
+	}{
+		{"missing parent", &types.Issue{Title: "Orphan", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, ParentID: "bd-999"}, "not found"},
+		{"epic under task", &types.Issue{Title: "Epic", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeEpic, ParentID: task.ID}, "cannot be a subtask"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := store.CreateIssue(ctx, tt.issue, "test-user")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	// Failed subtask creation leaves nothing behind
+	issues, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+	if len(issues) != 1 {
+		t.Errorf("Expected only the original task, got %d issues", len(issues))
+	}
+}
@@ -0,0 +1,177 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// IssueRevision is one entry in an issue's history. Revision 1 is the
+// creation; each later revision lists the fields it changed.
+type IssueRevision struct {
+	IssueID   string          `json:"issue_id"`
+	Revision  int             `json:"revision"`
+	Kind      types.EventType `json:"kind"` // created, updated, status_changed, closed, reopened, label_added, label_removed, renamed, or reverted
+	Actor     string          `json:"actor"`
+	Changes   []FieldChange   `json:"changes"`
+	Comment   string          `json:"comment,omitempty"` // close reason
+	Reverts   int             `json:"reverts,omitempty"` // revision undone by this one
+	CreatedAt time.Time       `json:"created_at"`
+
+	issue *types.Issue // the new issue, for a creation's replication op
+}
+
+// VersionConflictError is returned by UpdateIssueIfVersion when the issue
+// has changed since the caller read it
+type VersionConflictError struct {
+	IssueID  string `json:"issue_id"`
+	Expected int    `json:"expected_version"`
+	Current  int    `json:"current_version"`
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("%s has changed: expected version %d, now at %d", e.IssueID, e.Expected, e.Current)
+}
+
+// currentRevision returns the latest revision of an issue, 0 if it has no history
+func currentRevision(ctx context.Context, tx *sql.Tx, issueID string) (int, error) {
+	var revision int
+	err := tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(revision), 0) FROM issue_history WHERE issue_id = ?`, issueID).Scan(&revision)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get issue version: %w", err)
+	}
+	return revision, nil
+}
+
+// recordRevision appends rev as the next revision of its issue's history,
+// and logs it as a replication op. Changes are stored as JSON, with
+// sensitive values encrypted like event payloads.
+func (s *SQLiteStorage) recordRevision(ctx context.Context, exec dbQueryer, rev *IssueRevision) error {
+	stored := make([]FieldChange, 0, len(rev.Changes))
+	for _, c := range rev.Changes {
+		if isEncryptedEventField(c.Field) {
+			var err error
+			if c.Old, err = s.encryptChangeValue(c.Old); err != nil {
+				return err
+			}
+			if c.New, err = s.encryptChangeValue(c.New); err != nil {
+				return err
+			}
+		}
+		stored = append(stored, c)
+	}
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("failed to encode revision: %w", err)
+	}
+	comment, err := s.encryptField(rev.Comment)
+	if err != nil {
+		return err
+	}
+	var reverts interface{}
+	if rev.Reverts != 0 {
+		reverts = rev.Reverts
+	}
+
+	_, err = exec.ExecContext(ctx, `
+		INSERT INTO issue_history (issue_id, revision, kind, actor, changes, comment, reverts, created_at)
+		SELECT ?, COALESCE(MAX(revision), 0) + 1, ?, ?, ?, ?, ?, ?
+		FROM issue_history WHERE issue_id = ?
+	`, rev.IssueID, rev.Kind, rev.Actor, string(data), comment, reverts, time.Now(), rev.IssueID)
+	if err != nil {
+		return fmt.Errorf("failed to record revision: %w", err)
+	}
+	return s.recordOp(ctx, exec, rev)
+}
+
+const revisionColumns = `issue_id, revision, kind, actor, changes, comment, reverts, created_at`
+
+// scanRevision scans a row selected with revisionColumns
+func (s *SQLiteStorage) scanRevision(row rowScanner) (*IssueRevision, error) {
+	var rev IssueRevision
+	var changes, comment string
+	var reverts sql.NullInt64
+	if err := row.Scan(&rev.IssueID, &rev.Revision, &rev.Kind, &rev.Actor, &changes, &comment, &reverts, &rev.CreatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(changes), &rev.Changes); err != nil {
+		return nil, fmt.Errorf("failed to decode revision %d of %s: %w", rev.Revision, rev.IssueID, err)
+	}
+	for i, c := range rev.Changes {
+		if isEncryptedEventField(c.Field) {
+			rev.Changes[i].Old = s.decryptChangeValue(c.Old)
+			rev.Changes[i].New = s.decryptChangeValue(c.New)
+		}
+	}
+	rev.Comment = s.decryptField(comment)
+	rev.Reverts = int(reverts.Int64)
+	return &rev, nil
+}
+
+// GetIssueHistory returns an issue's revisions, oldest first. Issues created
+// before history was recorded start with their first later change.
+func (s *SQLiteStorage) GetIssueHistory(ctx context.Context, issueID string) ([]*IssueRevision, error) {
+	rows, err := s.reads.QueryContext(ctx, `
+		SELECT `+revisionColumns+`
+		FROM issue_history
+		WHERE issue_id = ?
+		ORDER BY revision
+	`, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue history: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var revisions []*IssueRevision
+	for rows.Next() {
+		rev, err := s.scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+	return revisions, rows.Err()
+}
+
+func isEncryptedEventField(field string) bool {
+	for _, key := range encryptedEventFields {
+		if key == field {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *SQLiteStorage) encryptChangeValue(v interface{}) (interface{}, error) {
+	if text, ok := v.(string); ok {
+		return s.encryptField(text)
+	}
+	return v, nil
+}
+
+func (s *SQLiteStorage) decryptChangeValue(v interface{}) interface{} {
+	if text, ok := v.(string); ok {
+		return s.decryptField(text)
+	}
+	return v
+}
+
+// updateChanges diffs an update against the issue it applies to
+func updateChanges(oldIssue *types.Issue, updates map[string]interface{}) []FieldChange {
+	var before, after map[string]interface{}
+	oldData, err := json.Marshal(oldIssue)
+	if err != nil {
+		return nil
+	}
+	newData, err := json.Marshal(updates)
+	if err != nil {
+		return nil
+	}
+	_ = json.Unmarshal(oldData, &before)
+	_ = json.Unmarshal(newData, &after)
+	return diffFields(before, after)
+}
@@ -0,0 +1,110 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestIssueHistory(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	issue := &types.Issue{Title: "Slow query", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeBug}
+	if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"priority": 1, "title": "Slow query on /issues"}, "bob"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	// No-op update does not add a revision
+	if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"priority": 1}, "bob"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	if err := store.CloseIssue(ctx, issue.ID, "Added an index", "carol"); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+
+	history, err := store.GetIssueHistory(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetIssueHistory failed: %v", err)
+	}
+	if len(history) != 3 {
+		t.Fatalf("Expected 3 revisions, got %d", len(history))
+	}
+
+	if history[0].Revision != 1 || history[0].Kind != types.EventCreated || history[0].Actor != "alice" || len(history[0].Changes) != 0 {
+		t.Errorf("Unexpected first revision %+v", history[0])
+	}
+
+	update := history[1]
+	if update.Revision != 2 || update.Actor != "bob" || len(update.Changes) != 2 {
+		t.Fatalf("Unexpected update revision %+v", update)
+	}
+	if c := update.Changes[0]; c.Field != "priority" || c.Old != float64(2) || c.New != float64(1) {
+		t.Errorf("Unexpected priority change %+v", c)
+	}
+	if c := update.Changes[1]; c.Field != "title" || c.Old != "Slow query" || c.New != "Slow query on /issues" {
+		t.Errorf("Unexpected title change %+v", c)
+	}
+
+	closed := history[2]
+	if closed.Kind != types.EventClosed || closed.Comment != "Added an index" ||
+		len(closed.Changes) != 1 || closed.Changes[0].Old != "open" || closed.Changes[0].New != "closed" {
+		t.Errorf("Unexpected close revision %+v", closed)
+	}
+
+	// History follows a rename and goes away with the issue
+	if err := store.UpdateIssueID(ctx, issue.ID, "bd-100", issue, "alice"); err != nil {
+		t.Fatalf("UpdateIssueID failed: %v", err)
+	}
+	if history, _ = store.GetIssueHistory(ctx, "bd-100"); len(history) != 4 || history[3].Changes[0].New != "bd-100" {
+		t.Errorf("Expected history to follow rename, got %d revisions", len(history))
+	}
+	if err := store.DeleteIssue(ctx, "bd-100"); err != nil {
+		t.Fatalf("DeleteIssue failed: %v", err)
+	}
+	if history, _ = store.GetIssueHistory(ctx, "bd-100"); len(history) != 0 {
+		t.Errorf("Expected history to be deleted, got %d revisions", len(history))
+	}
+}
+
+func TestUpdateIssueIfVersion(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	issue := &types.Issue{Title: "Flaky test", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeBug}
+	if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	read, err := store.GetIssue(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if read.Version != 1 {
+		t.Fatalf("Expected version 1 after create, got %d", read.Version)
+	}
+
+	if err := store.UpdateIssueIfVersion(ctx, issue.ID, map[string]interface{}{"priority": 1}, "alice", read.Version); err != nil {
+		t.Fatalf("UpdateIssueIfVersion failed: %v", err)
+	}
+
+	// A second writer still holding version 1 is turned away
+	err = store.UpdateIssueIfVersion(ctx, issue.ID, map[string]interface{}{"priority": 3}, "bob", read.Version)
+	var conflict *VersionConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("Expected VersionConflictError, got %v", err)
+	}
+	if conflict.Expected != 1 || conflict.Current != 2 {
+		t.Errorf("Unexpected conflict %+v", conflict)
+	}
+	if got, _ := store.GetIssue(ctx, issue.ID); got.Priority != 1 || got.Version != 2 {
+		t.Errorf("Expected stale update to change nothing, got priority %d at version %d", got.Priority, got.Version)
+	}
+}
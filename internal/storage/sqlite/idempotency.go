@@ -0,0 +1,70 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// IdempotencyKeyTTL is how long a stored response is replayed for its key
+const IdempotencyKeyTTL = 24 * time.Hour
+
+// IdempotentResponse is the stored result of the first request made with an
+// Idempotency-Key
+type IdempotentResponse struct {
+	Scope       string
+	Key         string
+	RequestHash string
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+}
+
+// GetIdempotentResponse returns the response stored for key in scope, or nil
+// if there is none or it is older than IdempotencyKeyTTL
+func (s *SQLiteStorage) GetIdempotentResponse(ctx context.Context, scope, key string) (*IdempotentResponse, error) {
+	resp := &IdempotentResponse{Scope: scope, Key: key}
+	err := s.reads.QueryRowContext(ctx, `
+		SELECT request_hash, status_code, content_type, body, created_at
+		FROM idempotency_keys
+		WHERE scope = ? AND key = ? AND created_at > ?
+	`, scope, key, time.Now().Add(-IdempotencyKeyTTL)).Scan(
+		&resp.RequestHash, &resp.StatusCode, &resp.ContentType, &resp.Body, &resp.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+	return resp, nil
+}
+
+// SaveIdempotentResponse stores the response for a key, replacing an expired
+// one, and purges other expired keys
+func (s *SQLiteStorage) SaveIdempotentResponse(ctx context.Context, resp *IdempotentResponse) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE created_at <= ?`, time.Now().Add(-IdempotencyKeyTTL)); err != nil {
+		return fmt.Errorf("failed to purge expired idempotency keys: %w", err)
+	}
+
+	resp.CreatedAt = time.Now()
+	if resp.Body == nil {
+		resp.Body = []byte{}
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO idempotency_keys (scope, key, request_hash, status_code, content_type, body, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, resp.Scope, resp.Key, resp.RequestHash, resp.StatusCode, resp.ContentType, resp.Body, resp.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save idempotency key: %w", err)
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,47 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestIdempotentResponses(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	if resp, err := store.GetIdempotentResponse(ctx, "", "k1"); err != nil || resp != nil {
+		t.Fatalf("Expected no stored response, got %v, %v", resp, err)
+	}
+
+	saved := &IdempotentResponse{Key: "k1", RequestHash: "abc", StatusCode: 200, ContentType: "application/json", Body: []byte(`{"id":"bd-1"}`)}
+	if err := store.SaveIdempotentResponse(ctx, saved); err != nil {
+		t.Fatalf("SaveIdempotentResponse failed: %v", err)
+	}
+	resp, err := store.GetIdempotentResponse(ctx, "", "k1")
+	if err != nil || resp == nil {
+		t.Fatalf("GetIdempotentResponse failed: %v, %v", resp, err)
+	}
+	if resp.RequestHash != "abc" || resp.StatusCode != 200 || string(resp.Body) != `{"id":"bd-1"}` {
+		t.Errorf("Unexpected stored response %+v", resp)
+	}
+
+	// Keys are scoped per caller
+	if resp, _ := store.GetIdempotentResponse(ctx, "7", "k1"); resp != nil {
+		t.Error("Expected key to be scoped")
+	}
+
+	// Expired keys are ignored and can be reused
+	old := time.Now().Add(-IdempotencyKeyTTL - time.Minute)
+	if _, err := store.db.ExecContext(ctx, `UPDATE idempotency_keys SET created_at = ?`, old); err != nil {
+		t.Fatal(err)
+	}
+	if resp, _ := store.GetIdempotentResponse(ctx, "", "k1"); resp != nil {
+		t.Error("Expected expired key to be ignored")
+	}
+	if err := store.SaveIdempotentResponse(ctx, &IdempotentResponse{Key: "k1", RequestHash: "def", StatusCode: 201}); err != nil {
+		t.Fatalf("Expected expired key to be reusable: %v", err)
+	}
+}
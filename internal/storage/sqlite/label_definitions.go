@@ -0,0 +1,320 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// Label is a label with its metadata. Issues carry labels by name, so a label
+// can be in use without being defined; such labels have Defined false and
+// take their parent from their path (infra for infra/db).
+type Label struct {
+	Name        string     `json:"name"`
+	Color       string     `json:"color,omitempty"` // #rrggbb
+	Description string     `json:"description,omitempty"`
+	Parent      string     `json:"parent,omitempty"`
+	Defined     bool       `json:"defined"`
+	CreatedBy   string     `json:"created_by,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+
+	Issues   int      `json:"issues"`             // issues carrying the label
+	Children []string `json:"children,omitempty"` // filled in by GetLabel
+}
+
+var labelColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// CreateLabel defines a label and sets its creation time. A path-style name
+// with no parent given gets its path prefix as parent.
+func (s *SQLiteStorage) CreateLabel(ctx context.Context, label *Label) error {
+	label.Name = strings.TrimSpace(label.Name)
+	if err := validateLabelName(label.Name); err != nil {
+		return err
+	}
+	if label.Parent == "" {
+		label.Parent = types.LabelPathParent(label.Name)
+	}
+
+	existing, err := s.getLabelDefinition(ctx, label.Name)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("label %s is already defined", label.Name)
+	}
+	if err := s.validateLabelDefinition(ctx, label); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	label.Defined = true
+	label.CreatedAt = &now
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO label_definitions (name, color, description, parent, created_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, label.Name, label.Color, label.Description, label.Parent, label.CreatedBy, now)
+	if err != nil {
+		return fmt.Errorf("failed to define label: %w", err)
+	}
+	return nil
+}
+
+// UpdateLabel replaces the color, description, and parent of a defined label
+func (s *SQLiteStorage) UpdateLabel(ctx context.Context, label *Label) error {
+	existing, err := s.getLabelDefinition(ctx, label.Name)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return fmt.Errorf("label %s is not defined", label.Name)
+	}
+	if err := s.validateLabelDefinition(ctx, label); err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		UPDATE label_definitions SET color = ?, description = ?, parent = ? WHERE name = ?
+	`, label.Color, label.Description, label.Parent, label.Name)
+	if err != nil {
+		return fmt.Errorf("failed to update label: %w", err)
+	}
+	return nil
+}
+
+// DeleteLabel removes a label's definition. Issues keep the label, and
+// labels defined under it fall back to their path parent.
+func (s *SQLiteStorage) DeleteLabel(ctx context.Context, name string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM label_definitions WHERE name = ?`, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete label: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("label %s is not defined", name)
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT name FROM label_definitions WHERE parent = ?`, name)
+	if err != nil {
+		return fmt.Errorf("failed to find child labels: %w", err)
+	}
+	var children []string
+	for rows.Next() {
+		var child string
+		if err := rows.Scan(&child); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("failed to scan child label: %w", err)
+		}
+		children = append(children, child)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, child := range children {
+		parent := types.LabelPathParent(child)
+		if parent == name {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE label_definitions SET parent = ? WHERE name = ?`, parent, child); err != nil {
+			return fmt.Errorf("failed to reparent label %s: %w", child, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ListLabels returns every defined or used label, sorted by name
+func (s *SQLiteStorage) ListLabels(ctx context.Context) ([]*Label, error) {
+	rows, err := s.reads.QueryContext(ctx, `
+		SELECT name, color, description, parent, created_by, created_at FROM label_definitions
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list labels: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	byName := make(map[string]*Label)
+	for rows.Next() {
+		label, err := scanLabel(rows)
+		if err != nil {
+			return nil, err
+		}
+		byName[label.Name] = label
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Trashed issues don't count
+	counts, err := s.reads.QueryContext(ctx, `
+		SELECT l.label, COUNT(*)
+		FROM labels l
+		JOIN issues i ON i.id = l.issue_id AND i.deleted_at IS NULL
+		GROUP BY l.label
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count labels: %w", err)
+	}
+	defer func() { _ = counts.Close() }()
+	for counts.Next() {
+		var name string
+		var n int
+		if err := counts.Scan(&name, &n); err != nil {
+			return nil, fmt.Errorf("failed to scan label count: %w", err)
+		}
+		label := byName[name]
+		if label == nil {
+			label = &Label{Name: name, Parent: types.LabelPathParent(name)}
+			byName[name] = label
+		}
+		label.Issues = n
+	}
+	if err := counts.Err(); err != nil {
+		return nil, err
+	}
+
+	labels := make([]*Label, 0, len(byName))
+	for _, label := range byName {
+		labels = append(labels, label)
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i].Name < labels[j].Name })
+	return labels, nil
+}
+
+// GetLabel returns a label with its direct children, or nil if it is not
+// defined, used, or the parent of a label that is
+func (s *SQLiteStorage) GetLabel(ctx context.Context, name string) (*Label, error) {
+	labels, err := s.ListLabels(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var found *Label
+	var children []string
+	for _, label := range labels {
+		if label.Name == name {
+			found = label
+		} else if label.Parent == name {
+			children = append(children, label.Name)
+		}
+	}
+	if found == nil {
+		if len(children) == 0 {
+			return nil, nil
+		}
+		found = &Label{Name: name, Parent: types.LabelPathParent(name)}
+	}
+	found.Children = children
+	return found, nil
+}
+
+func (s *SQLiteStorage) getLabelDefinition(ctx context.Context, name string) (*Label, error) {
+	row := s.reads.QueryRowContext(ctx, `
+		SELECT name, color, description, parent, created_by, created_at FROM label_definitions WHERE name = ?
+	`, name)
+	label, err := scanLabel(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return label, err
+}
+
+func validateLabelName(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("label name is required")
+	case strings.Contains(name, "*"):
+		return fmt.Errorf("label %q cannot contain *", name)
+	case strings.HasPrefix(name, "/") || strings.HasSuffix(name, "/") || strings.Contains(name, "//"):
+		return fmt.Errorf("label %q has an empty path segment", name)
+	}
+	return nil
+}
+
+// validateLabelDefinition checks the color and parent of label, refusing a
+// parent that is the label itself or sits under it
+func (s *SQLiteStorage) validateLabelDefinition(ctx context.Context, label *Label) error {
+	if label.Color != "" && !labelColorPattern.MatchString(label.Color) {
+		return fmt.Errorf("label color must be #rrggbb (got %q)", label.Color)
+	}
+	label.Color = strings.ToLower(label.Color)
+	if label.Parent == "" {
+		return nil
+	}
+	if err := validateLabelName(label.Parent); err != nil {
+		return fmt.Errorf("parent: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for ancestor := label.Parent; ancestor != ""; {
+		if ancestor == label.Name || types.MatchesLabelFilter(ancestor, label.Name+types.LabelChildrenSuffix) {
+			return fmt.Errorf("label %s cannot be its own ancestor (via %s)", label.Name, label.Parent)
+		}
+		if seen[ancestor] {
+			break
+		}
+		seen[ancestor] = true
+
+		def, err := s.getLabelDefinition(ctx, ancestor)
+		if err != nil {
+			return err
+		}
+		if def != nil {
+			ancestor = def.Parent
+		} else {
+			ancestor = types.LabelPathParent(ancestor)
+		}
+	}
+	return nil
+}
+
+func scanLabel(row rowScanner) (*Label, error) {
+	var label Label
+	var createdAt time.Time
+	err := row.Scan(&label.Name, &label.Color, &label.Description, &label.Parent, &label.CreatedBy, &createdAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan label: %w", err)
+	}
+	label.Defined = true
+	label.CreatedAt = &createdAt
+	return &label, nil
+}
+
+// labelFilterClause returns a WHERE clause matching rows whose col+"id" is
+// an issue carrying a label that matches filter. A filter such as infra/*
+// matches the labels under infra, by path or by a defined parent, at any
+// depth, but not infra itself.
+func labelFilterClause(col, filter string) (string, []interface{}) {
+	parent, ok := types.LabelFilterParent(filter)
+	if !ok {
+		return col + "id IN (SELECT issue_id FROM labels WHERE label = ?)", []interface{}{filter}
+	}
+	// Walk down from parent through defined and used labels alike, so a
+	// defined child of an undefined path label is still found
+	return col + `id IN (
+		WITH RECURSIVE tree(name) AS (
+			SELECT ?
+			UNION
+			SELECT k.name FROM (
+				SELECT name, parent FROM label_definitions
+				UNION SELECT label, '' FROM labels
+			) k JOIN tree t
+			  ON k.parent = t.name OR substr(k.name, 1, length(t.name) + 1) = t.name || '/'
+		)
+		SELECT issue_id FROM labels WHERE label IN (SELECT name FROM tree) AND label != ?
+	)`, []interface{}{parent, parent}
+}
@@ -0,0 +1,171 @@
+package sqlite
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestLabelDefinitions(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	infra := &Label{Name: "infra", Color: "#FF8800", Description: "Infrastructure", CreatedBy: "alice"}
+	if err := store.CreateLabel(ctx, infra); err != nil {
+		t.Fatalf("CreateLabel failed: %v", err)
+	}
+	if infra.Color != "#ff8800" {
+		t.Errorf("Expected the color to be lowercased, got %q", infra.Color)
+	}
+	if err := store.CreateLabel(ctx, &Label{Name: "infra"}); err == nil {
+		t.Error("Expected defining infra twice to fail")
+	}
+
+	// Path-style names default to their path parent
+	db := &Label{Name: "infra/db"}
+	if err := store.CreateLabel(ctx, db); err != nil {
+		t.Fatalf("CreateLabel failed: %v", err)
+	}
+	if db.Parent != "infra" {
+		t.Errorf("Expected parent infra, got %q", db.Parent)
+	}
+
+	for _, bad := range []*Label{
+		{Name: "infra/*"},
+		{Name: "/infra"},
+		{Name: "net", Color: "orange"},
+		{Name: "net", Parent: "net"},
+	} {
+		if err := store.CreateLabel(ctx, bad); err == nil {
+			t.Errorf("Expected %+v to be rejected", bad)
+		}
+	}
+
+	// A label can't move under its own descendant
+	infra.Parent = "infra/db"
+	if err := store.UpdateLabel(ctx, infra); err == nil {
+		t.Error("Expected a parent cycle to be rejected")
+	}
+	infra.Parent = ""
+	infra.Description = "Servers and such"
+	if err := store.UpdateLabel(ctx, infra); err != nil {
+		t.Fatalf("UpdateLabel failed: %v", err)
+	}
+	if err := store.UpdateLabel(ctx, &Label{Name: "undefined"}); err == nil {
+		t.Error("Expected updating an undefined label to fail")
+	}
+
+	issue := &types.Issue{Title: "Work", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	for _, label := range []string{"infra", "infra/net"} {
+		if err := store.AddLabel(ctx, issue.ID, label, "alice"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := store.GetLabel(ctx, "infra")
+	if err != nil {
+		t.Fatalf("GetLabel failed: %v", err)
+	}
+	if got.Description != "Servers and such" || got.Issues != 1 || !got.Defined {
+		t.Errorf("Unexpected label %+v", got)
+	}
+	if want := []string{"infra/db", "infra/net"}; !reflect.DeepEqual(got.Children, want) {
+		t.Errorf("Expected children %v, got %v", want, got.Children)
+	}
+
+	// Used but undefined labels are listed too
+	labels, err := store.ListLabels(ctx)
+	if err != nil {
+		t.Fatalf("ListLabels failed: %v", err)
+	}
+	var names []string
+	for _, label := range labels {
+		names = append(names, label.Name)
+	}
+	if want := []string{"infra", "infra/db", "infra/net"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Expected labels %v, got %v", want, names)
+	}
+	if labels[2].Defined || labels[2].Parent != "infra" {
+		t.Errorf("Expected infra/net to be undefined under infra, got %+v", labels[2])
+	}
+
+	// Deleting a definition leaves issues labeled
+	if err := store.DeleteLabel(ctx, "infra"); err != nil {
+		t.Fatalf("DeleteLabel failed: %v", err)
+	}
+	if err := store.DeleteLabel(ctx, "infra"); err == nil {
+		t.Error("Expected deleting an undefined label to fail")
+	}
+	if got, _ := store.GetLabel(ctx, "infra"); got == nil || got.Defined || got.Issues != 1 {
+		t.Errorf("Expected infra to remain in use, got %+v", got)
+	}
+	if got, _ := store.GetLabel(ctx, "nothing"); got != nil {
+		t.Errorf("Expected no label, got %+v", got)
+	}
+}
+
+func TestHierarchicalLabelFilter(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// postgres sits under infra/db by its defined parent, not its name
+	if err := store.CreateLabel(ctx, &Label{Name: "postgres", Parent: "infra/db"}); err != nil {
+		t.Fatal(err)
+	}
+
+	labeled := map[string]string{}
+	for _, label := range []string{"infra", "infra/db", "infra/db/backup", "postgres", "infrastructure"} {
+		issue := &types.Issue{Title: label, Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.AddLabel(ctx, issue.ID, label, "alice"); err != nil {
+			t.Fatal(err)
+		}
+		labeled[issue.ID] = label
+	}
+
+	titles := func(issues []*types.Issue) []string {
+		var got []string
+		for _, issue := range issues {
+			got = append(got, issue.Title)
+		}
+		sort.Strings(got)
+		return got
+	}
+
+	tests := []struct {
+		filter types.IssueFilter
+		want   []string
+	}{
+		{types.IssueFilter{Labels: []string{"infra"}}, []string{"infra"}},
+		{types.IssueFilter{Labels: []string{"infra/*"}}, []string{"infra/db", "infra/db/backup", "postgres"}},
+		{types.IssueFilter{Labels: []string{"infra/db/*"}}, []string{"infra/db/backup", "postgres"}},
+		{types.IssueFilter{LabelsAny: []string{"infra/db/*", "infrastructure"}}, []string{"infra/db/backup", "infrastructure", "postgres"}},
+	}
+	for _, tt := range tests {
+		issues, err := store.SearchIssues(ctx, "", tt.filter)
+		if err != nil {
+			t.Fatalf("SearchIssues(%+v) failed: %v", tt.filter, err)
+		}
+		if got := titles(issues); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SearchIssues(%+v) = %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+
+	ready, err := store.GetReadyWork(ctx, types.WorkFilter{Labels: []string{"infra/db/*"}})
+	if err != nil {
+		t.Fatalf("GetReadyWork failed: %v", err)
+	}
+	if got := titles(ready); !reflect.DeepEqual(got, []string{"infra/db/backup", "postgres"}) {
+		t.Errorf("GetReadyWork = %v", got)
+	}
+}
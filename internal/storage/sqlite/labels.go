@@ -2,41 +2,73 @@ package sqlite
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"time"
 
 	"github.com/imalsogreg/beads/internal/types"
+	"go.opentelemetry.io/otel/attribute"
 )
 
-// executeLabelOperation executes a label operation (add or remove) within a transaction
-func (s *SQLiteStorage) executeLabelOperation(
-	ctx context.Context,
-	issueID, actor string,
-	labelSQL string,
-	labelSQLArgs []interface{},
-	eventType types.EventType,
-	eventComment string,
-	operationError string,
-) error {
+// executeLabelOperation adds or removes a label within a transaction
+func (s *SQLiteStorage) executeLabelOperation(ctx context.Context, issueID, label, actor string, add bool) error {
+	ctx, span := startSpan(ctx, "LabelOperation", attribute.String("issue.id", issueID), attribute.String("label", label))
+	defer span.End()
+
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	_, err = tx.ExecContext(ctx, labelSQL, labelSQLArgs...)
+	if err := s.labelOperationInTx(ctx, tx, issueID, label, actor, add, 0); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// labelOperationInTx adds or removes a label within tx, recording the event
+// and, if the label set changed, a history revision. A nonzero reverts
+// records the change as the undo of that revision.
+func (s *SQLiteStorage) labelOperationInTx(ctx context.Context, tx *sql.Tx, issueID, label, actor string, add bool, reverts int) error {
+	labelSQL := `DELETE FROM labels WHERE issue_id = ? AND label = ?`
+	eventType := types.EventLabelRemoved
+	eventComment := fmt.Sprintf("Removed label: %s", label)
+	operationError := "failed to remove label"
+	change := FieldChange{Field: "label", Old: label}
+	if add {
+		labelSQL = `INSERT OR IGNORE INTO labels (issue_id, label) VALUES (?, ?)`
+		eventType = types.EventLabelAdded
+		eventComment = fmt.Sprintf("Added label: %s", label)
+		operationError = "failed to add label"
+		change = FieldChange{Field: "label", New: label}
+	}
+
+	result, err := tx.ExecContext(ctx, labelSQL, issueID, label)
 	if err != nil {
 		return fmt.Errorf("%s: %w", operationError, err)
 	}
 
 	_, err = tx.ExecContext(ctx, `
-		INSERT INTO events (issue_id, event_type, actor, comment)
-		VALUES (?, ?, ?, ?)
-	`, issueID, eventType, actor, eventComment)
+		INSERT INTO events (issue_id, event_type, actor, comment, session_id)
+		VALUES (?, ?, ?, ?, ?)
+	`, issueID, eventType, actor, eventComment, s.eventSession(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to record event: %w", err)
 	}
 
+	// Record a history revision unless the label was already there (or gone)
+	if n, err := result.RowsAffected(); err == nil && n > 0 {
+		rev := &IssueRevision{IssueID: issueID, Kind: eventType, Actor: actor, Changes: []FieldChange{change}, Reverts: reverts}
+		if reverts != 0 {
+			rev.Kind = types.EventReverted
+		}
+		if err := s.recordRevision(ctx, tx, rev); err != nil {
+			return err
+		}
+	}
+
 	// Mark issue as dirty for incremental export
 	_, err = tx.ExecContext(ctx, `
 		INSERT INTO dirty_issues (issue_id, marked_at)
@@ -47,36 +79,22 @@ func (s *SQLiteStorage) executeLabelOperation(
 		return fmt.Errorf("failed to mark issue dirty: %w", err)
 	}
 
-	return tx.Commit()
+	return nil
 }
 
 // AddLabel adds a label to an issue
 func (s *SQLiteStorage) AddLabel(ctx context.Context, issueID, label, actor string) error {
-	return s.executeLabelOperation(
-		ctx, issueID, actor,
-		`INSERT OR IGNORE INTO labels (issue_id, label) VALUES (?, ?)`,
-		[]interface{}{issueID, label},
-		types.EventLabelAdded,
-		fmt.Sprintf("Added label: %s", label),
-		"failed to add label",
-	)
+	return s.executeLabelOperation(ctx, issueID, label, actor, true)
 }
 
 // RemoveLabel removes a label from an issue
 func (s *SQLiteStorage) RemoveLabel(ctx context.Context, issueID, label, actor string) error {
-	return s.executeLabelOperation(
-		ctx, issueID, actor,
-		`DELETE FROM labels WHERE issue_id = ? AND label = ?`,
-		[]interface{}{issueID, label},
-		types.EventLabelRemoved,
-		fmt.Sprintf("Removed label: %s", label),
-		"failed to remove label",
-	)
+	return s.executeLabelOperation(ctx, issueID, label, actor, false)
 }
 
 // GetLabels returns all labels for an issue
 func (s *SQLiteStorage) GetLabels(ctx context.Context, issueID string) ([]string, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	rows, err := s.reads.QueryContext(ctx, `
 		SELECT label FROM labels WHERE issue_id = ? ORDER BY label
 	`, issueID)
 	if err != nil {
@@ -98,13 +116,14 @@ func (s *SQLiteStorage) GetLabels(ctx context.Context, issueID string) ([]string
 
 // GetIssuesByLabel returns issues with a specific label
 func (s *SQLiteStorage) GetIssuesByLabel(ctx context.Context, label string) ([]*types.Issue, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	rows, err := s.reads.QueryContext(ctx, `
 		SELECT i.id, i.title, i.description, i.design, i.acceptance_criteria, i.notes,
 		       i.status, i.priority, i.issue_type, i.assignee, i.estimated_minutes,
-		       i.created_at, i.updated_at, i.closed_at, i.external_ref
+		       i.created_at, i.updated_at, i.closed_at, i.external_ref,
+		       i.due_date, i.start_date, i.milestone
 		FROM issues i
 		JOIN labels l ON i.id = l.issue_id
-		WHERE l.label = ?
+		WHERE l.label = ? AND i.deleted_at IS NULL
 		ORDER BY i.priority ASC, i.created_at DESC
 	`, label)
 	if err != nil {
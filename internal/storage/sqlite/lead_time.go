@@ -0,0 +1,126 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// ClosedIssueTimes is when a closed issue was created, started, and closed,
+// for lead and cycle time reports
+type ClosedIssueTimes struct {
+	ID        string          `json:"id"`
+	Assignee  string          `json:"assignee,omitempty"`
+	IssueType types.IssueType `json:"issue_type"`
+	Priority  int             `json:"priority"`
+	Labels    []string        `json:"labels,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	StartedAt *time.Time      `json:"started_at,omitempty"` // first move to in_progress; nil if it never was
+	ClosedAt  time.Time       `json:"closed_at"`
+}
+
+// GetClosedIssueTimes returns the closed issues out of the trash that were
+// closed at or after since (all of them for the zero time), oldest close
+// first. Start times come from the event history: the first status change
+// to in_progress, or creation for issues created in progress.
+func (s *SQLiteStorage) GetClosedIssueTimes(ctx context.Context, since time.Time) ([]*ClosedIssueTimes, error) {
+	ctx, span := startSpan(ctx, "GetClosedIssueTimes")
+	defer span.End()
+
+	rows, err := s.reads.QueryContext(ctx, `
+		SELECT id, COALESCE(assignee, ''), issue_type, priority, created_at, closed_at
+		FROM issues
+		WHERE status = 'closed'
+		  AND closed_at IS NOT NULL
+		  AND deleted_at IS NULL
+		ORDER BY closed_at, id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query closed issues: %w", err)
+	}
+	var issues []*ClosedIssueTimes
+	byID := make(map[string]*ClosedIssueTimes)
+	for rows.Next() {
+		c := &ClosedIssueTimes{}
+		if err := rows.Scan(&c.ID, &c.Assignee, &c.IssueType, &c.Priority, &c.CreatedAt, &c.ClosedAt); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("failed to scan closed issue: %w", err)
+		}
+		if c.ClosedAt.Before(since) {
+			continue
+		}
+		issues = append(issues, c)
+		byID[c.ID] = c
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(issues) == 0 {
+		return issues, nil
+	}
+
+	rows, err = s.reads.QueryContext(ctx, `
+		SELECT l.issue_id, l.label FROM labels l
+		JOIN issues i ON i.id = l.issue_id
+		WHERE i.status = 'closed' AND i.deleted_at IS NULL
+		ORDER BY l.issue_id, l.label
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query labels: %w", err)
+	}
+	for rows.Next() {
+		var id, label string
+		if err := rows.Scan(&id, &label); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("failed to scan label: %w", err)
+		}
+		if c := byID[id]; c != nil {
+			c.Labels = append(c.Labels, label)
+		}
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = s.reads.QueryContext(ctx, `
+		SELECT e.issue_id, e.new_value, e.created_at FROM events e
+		JOIN issues i ON i.id = e.issue_id
+		WHERE i.status = 'closed' AND i.deleted_at IS NULL
+		  AND e.event_type IN (?, ?, ?)
+		  AND e.new_value IS NOT NULL
+		ORDER BY e.id
+	`, types.EventCreated, types.EventStatusChanged, types.EventReopened)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query status changes: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var id, payload string
+		var at time.Time
+		if err := rows.Scan(&id, &payload, &at); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		c := byID[id]
+		if c == nil || c.StartedAt != nil {
+			continue
+		}
+		var change struct {
+			Status types.Status `json:"status"`
+		}
+		if json.Unmarshal([]byte(s.decryptEventPayload(payload)), &change) != nil || change.Status != types.StatusInProgress {
+			continue
+		}
+		started := at
+		if started.Before(c.CreatedAt) {
+			// Imported issues were created before their creation event
+			started = c.CreatedAt
+		}
+		c.StartedAt = &started
+	}
+	return issues, rows.Err()
+}
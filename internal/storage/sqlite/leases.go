@@ -0,0 +1,298 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// Lease is a time-limited claim on an issue. While it is live only its
+// holder can claim the issue; once it expires the issue goes back to ready
+// work unless the holder claims it again first.
+type Lease struct {
+	IssueID   string    `json:"issue_id"`
+	Holder    string    `json:"holder"`
+	ClaimedAt time.Time `json:"claimed_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// LeaseConflictError is returned when an issue is claimed by someone else
+type LeaseConflictError struct {
+	IssueID   string
+	Holder    string
+	ExpiresAt time.Time
+}
+
+func (e *LeaseConflictError) Error() string {
+	return fmt.Sprintf("%s is claimed by %s until %s", e.IssueID, e.Holder, e.ExpiresAt.Format(time.RFC3339))
+}
+
+// DefaultLeaseTTL is how long a claim lasts when the claimant doesn't say
+const DefaultLeaseTTL = 30 * time.Minute
+
+var (
+	// ErrNotClaimable is returned when claiming an issue that is neither open
+	// nor already in progress for the claimant
+	ErrNotClaimable = errors.New("only open issues can be claimed")
+
+	// ErrLeaseNotHeld is returned when releasing a lease the caller doesn't hold
+	ErrLeaseNotHeld = errors.New("no live lease held")
+)
+
+// ClaimIssue claims an issue for holder for ttl, flipping it to in_progress
+// and assigning it to holder. Claiming an issue holder already has renews
+// the lease, as does claiming in-progress work already assigned to holder.
+// Expired leases are released first, so their issues can be claimed again.
+func (s *SQLiteStorage) ClaimIssue(ctx context.Context, issueID, holder string, ttl time.Duration) (*Lease, error) {
+	if err := validateClaim(holder, ttl); err != nil {
+		return nil, err
+	}
+	if _, err := s.ReleaseExpiredLeases(ctx); err != nil {
+		return nil, err
+	}
+	return s.claimIssue(ctx, issueID, holder, ttl)
+}
+
+// ClaimNext claims the first ready issue matching filter that is open and
+// either unassigned or assigned to holder. It returns nil, nil, nil when
+// there is no such work. Issues claimed by others in the meantime are skipped.
+func (s *SQLiteStorage) ClaimNext(ctx context.Context, holder string, ttl time.Duration, filter types.WorkFilter) (*types.Issue, *Lease, error) {
+	if err := validateClaim(holder, ttl); err != nil {
+		return nil, nil, err
+	}
+	if _, err := s.ReleaseExpiredLeases(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	// Only open work is unclaimed
+	filter.Status = types.StatusOpen
+	filter.Limit = 0
+	candidates, err := s.GetReadyWork(ctx, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, candidate := range candidates {
+		if candidate.Assignee != "" && candidate.Assignee != holder {
+			continue
+		}
+		lease, err := s.claimIssue(ctx, candidate.ID, holder, ttl)
+		var conflict *LeaseConflictError
+		if errors.As(err, &conflict) || errors.Is(err, ErrNotClaimable) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		issue, err := s.GetIssue(ctx, candidate.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		return issue, lease, nil
+	}
+	return nil, nil, nil
+}
+
+func validateClaim(holder string, ttl time.Duration) error {
+	if holder == "" {
+		return fmt.Errorf("claim requires a holder")
+	}
+	if ttl <= 0 {
+		return fmt.Errorf("lease TTL must be positive (got %v)", ttl)
+	}
+	return nil
+}
+
+func (s *SQLiteStorage) claimIssue(ctx context.Context, issueID, holder string, ttl time.Duration) (*Lease, error) {
+	issue, err := s.GetIssue(ctx, issueID)
+	if err != nil {
+		return nil, err
+	}
+	if issue == nil {
+		return nil, types.NotFound("issue", issueID)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// Write the lease first: it takes the write lock, so the issue can't
+	// change before we commit, and it only succeeds for a new lease or a
+	// renewal
+	now := time.Now()
+	lease := &Lease{IssueID: issueID, Holder: holder, ExpiresAt: now.Add(ttl)}
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO issue_leases (issue_id, holder, claimed_at, expires_at)
+		SELECT id, ?, ?, ? FROM issues WHERE id = ?
+		ON CONFLICT(issue_id) DO UPDATE SET expires_at = excluded.expires_at
+		WHERE issue_leases.holder = excluded.holder
+		RETURNING claimed_at
+	`, holder, now, lease.ExpiresAt, issueID).Scan(&lease.ClaimedAt)
+	if err == sql.ErrNoRows {
+		var conflict LeaseConflictError
+		err := tx.QueryRowContext(ctx, `SELECT holder, expires_at FROM issue_leases WHERE issue_id = ?`, issueID).
+			Scan(&conflict.Holder, &conflict.ExpiresAt)
+		if err == sql.ErrNoRows {
+			return nil, types.NotFound("issue", issueID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get lease: %w", err)
+		}
+		conflict.IssueID = issueID
+		return nil, &conflict
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim %s: %w", issueID, err)
+	}
+
+	// Re-check the status now that nothing else can write
+	if err := tx.QueryRowContext(ctx, `SELECT status, assignee FROM issues WHERE id = ?`, issueID).
+		Scan(&issue.Status, &issue.Assignee); err != nil {
+		return nil, fmt.Errorf("failed to get issue status: %w", err)
+	}
+	switch {
+	case issue.Status == types.StatusOpen:
+		updates := map[string]interface{}{"status": string(types.StatusInProgress), "assignee": holder}
+		if err := s.updateIssueInTx(ctx, tx, issue, updates, holder, 0); err != nil {
+			return nil, err
+		}
+	case issue.Status == types.StatusInProgress && issue.Assignee == holder:
+		// Renewal, or holder leasing work they already started
+	default:
+		return nil, fmt.Errorf("cannot claim %s (%s): %w", issueID, issue.Status, ErrNotClaimable)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit claim: %w", err)
+	}
+	return lease, nil
+}
+
+// ReleaseLease ends holder's live lease on an issue and, if the issue is
+// still in progress and assigned to holder, returns it to open and unassigned
+func (s *SQLiteStorage) ReleaseLease(ctx context.Context, issueID, holder string) error {
+	current, err := s.GetLease(ctx, issueID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if current == nil || !current.ExpiresAt.After(now) {
+		return fmt.Errorf("cannot release %s: %w by %s", issueID, ErrLeaseNotHeld, holder)
+	}
+	if current.Holder != holder {
+		return &LeaseConflictError{IssueID: issueID, Holder: current.Holder, ExpiresAt: current.ExpiresAt}
+	}
+	issue, err := s.GetIssue(ctx, issueID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := s.releaseLeaseInTx(ctx, tx, current, issue, holder); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ReleaseExpiredLeases deletes every expired lease and returns its issue to
+// the pool, as ReleaseLease does. It returns the leases released.
+func (s *SQLiteStorage) ReleaseExpiredLeases(ctx context.Context) ([]*Lease, error) {
+	expired, err := s.queryLeases(ctx, `WHERE expires_at <= ?`, time.Now())
+	if err != nil || len(expired) == 0 {
+		return nil, err
+	}
+	issues := make([]*types.Issue, len(expired))
+	for i, lease := range expired {
+		if issues[i], err = s.GetIssue(ctx, lease.IssueID); err != nil {
+			return nil, err
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for i, lease := range expired {
+		if err := s.releaseLeaseInTx(ctx, tx, lease, issues[i], "system"); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit lease expiry: %w", err)
+	}
+	return expired, nil
+}
+
+// releaseLeaseInTx deletes lease and reopens issue, the issue it was on,
+// if the lease holder was still working on it
+func (s *SQLiteStorage) releaseLeaseInTx(ctx context.Context, tx *sql.Tx, lease *Lease, issue *types.Issue, actor string) error {
+	// Match the expiry too, so a lease renewed since it was read is kept
+	res, err := tx.ExecContext(ctx, `DELETE FROM issue_leases WHERE issue_id = ? AND holder = ? AND expires_at = ?`,
+		lease.IssueID, lease.Holder, lease.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to release lease on %s: %w", lease.IssueID, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 || issue == nil {
+		return nil
+	}
+
+	if err := tx.QueryRowContext(ctx, `SELECT status, assignee FROM issues WHERE id = ?`, lease.IssueID).
+		Scan(&issue.Status, &issue.Assignee); err != nil {
+		return fmt.Errorf("failed to get issue status: %w", err)
+	}
+	if issue.Status != types.StatusInProgress || issue.Assignee != lease.Holder {
+		return nil
+	}
+	updates := map[string]interface{}{"status": string(types.StatusOpen), "assignee": ""}
+	return s.updateIssueInTx(ctx, tx, issue, updates, actor, 0)
+}
+
+// GetLease returns the lease on an issue, expired or not, or nil if there is none
+func (s *SQLiteStorage) GetLease(ctx context.Context, issueID string) (*Lease, error) {
+	leases, err := s.queryLeases(ctx, `WHERE issue_id = ?`, issueID)
+	if err != nil || len(leases) == 0 {
+		return nil, err
+	}
+	return leases[0], nil
+}
+
+// ListLeases returns the live leases, soonest to expire first
+func (s *SQLiteStorage) ListLeases(ctx context.Context) ([]*Lease, error) {
+	return s.queryLeases(ctx, `WHERE expires_at > ?`, time.Now())
+}
+
+func (s *SQLiteStorage) queryLeases(ctx context.Context, where string, args ...interface{}) ([]*Lease, error) {
+	// #nosec G202 - where is one of a fixed set of clauses
+	rows, err := s.reads.QueryContext(ctx, `
+		SELECT issue_id, holder, claimed_at, expires_at
+		FROM issue_leases `+where+`
+		ORDER BY expires_at, issue_id
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query leases: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var leases []*Lease
+	for rows.Next() {
+		var lease Lease
+		if err := rows.Scan(&lease.IssueID, &lease.Holder, &lease.ClaimedAt, &lease.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("failed to scan lease: %w", err)
+		}
+		leases = append(leases, &lease)
+	}
+	return leases, rows.Err()
+}
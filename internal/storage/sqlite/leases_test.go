@@ -0,0 +1,162 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestClaimIssue(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	issue := &types.Issue{Title: "Work", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	lease, err := store.ClaimIssue(ctx, issue.ID, "agent-1", time.Minute)
+	if err != nil {
+		t.Fatalf("ClaimIssue failed: %v", err)
+	}
+	got, _ := store.GetIssue(ctx, issue.ID)
+	if got.Status != types.StatusInProgress || got.Assignee != "agent-1" {
+		t.Errorf("Expected in_progress for agent-1, got %s for %q", got.Status, got.Assignee)
+	}
+
+	// Another agent is turned away
+	_, err = store.ClaimIssue(ctx, issue.ID, "agent-2", time.Minute)
+	var conflict *LeaseConflictError
+	if !errors.As(err, &conflict) || conflict.Holder != "agent-1" {
+		t.Fatalf("Expected a conflict with agent-1, got %v", err)
+	}
+
+	// Claiming again renews without resetting claimed_at
+	renewed, err := store.ClaimIssue(ctx, issue.ID, "agent-1", time.Hour)
+	if err != nil {
+		t.Fatalf("renewing failed: %v", err)
+	}
+	if !renewed.ClaimedAt.Equal(lease.ClaimedAt) || !renewed.ExpiresAt.After(lease.ExpiresAt) {
+		t.Errorf("Expected renewal to extend %+v, got %+v", lease, renewed)
+	}
+
+	if err := store.ReleaseLease(ctx, issue.ID, "agent-2"); !errors.As(err, &conflict) {
+		t.Errorf("Expected agent-2 release to conflict, got %v", err)
+	}
+	if err := store.ReleaseLease(ctx, issue.ID, "agent-1"); err != nil {
+		t.Fatalf("ReleaseLease failed: %v", err)
+	}
+	got, _ = store.GetIssue(ctx, issue.ID)
+	if got.Status != types.StatusOpen || got.Assignee != "" {
+		t.Errorf("Expected release to reopen and unassign, got %s for %q", got.Status, got.Assignee)
+	}
+	if err := store.ReleaseLease(ctx, issue.ID, "agent-1"); !errors.Is(err, ErrLeaseNotHeld) {
+		t.Errorf("Expected ErrLeaseNotHeld, got %v", err)
+	}
+
+	// Closed issues can't be claimed, and closing ends the lease
+	if _, err := store.ClaimIssue(ctx, issue.ID, "agent-2", time.Minute); err != nil {
+		t.Fatalf("ClaimIssue failed: %v", err)
+	}
+	if err := store.CloseIssue(ctx, issue.ID, "done", "agent-2"); err != nil {
+		t.Fatal(err)
+	}
+	if lease, _ := store.GetLease(ctx, issue.ID); lease != nil {
+		t.Errorf("Expected closing to end the lease, got %+v", lease)
+	}
+	if _, err := store.ClaimIssue(ctx, issue.ID, "agent-1", time.Minute); !errors.Is(err, ErrNotClaimable) {
+		t.Errorf("Expected ErrNotClaimable for a closed issue, got %v", err)
+	}
+
+	if _, err := store.ClaimIssue(ctx, "bd-999", "agent-1", time.Minute); err == nil {
+		t.Error("Expected claiming a missing issue to fail")
+	}
+	if _, err := store.ClaimIssue(ctx, issue.ID, "agent-1", 0); err == nil {
+		t.Error("Expected a zero TTL to be rejected")
+	}
+}
+
+func TestLeaseExpiry(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	issue := &types.Issue{Title: "Work", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	if _, err := store.ClaimIssue(ctx, issue.ID, "agent-1", time.Minute); err != nil {
+		t.Fatalf("ClaimIssue failed: %v", err)
+	}
+	if _, err := store.UnderlyingDB().Exec(`UPDATE issue_leases SET expires_at = ?`, time.Now().Add(-time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if leases, _ := store.ListLeases(ctx); len(leases) != 0 {
+		t.Errorf("Expected no live leases, got %v", leases)
+	}
+
+	// The expired lease no longer blocks others
+	if _, err := store.ClaimIssue(ctx, issue.ID, "agent-2", time.Minute); err != nil {
+		t.Fatalf("Expected agent-2 to claim expired work, got %v", err)
+	}
+	got, _ := store.GetIssue(ctx, issue.ID)
+	if got.Assignee != "agent-2" {
+		t.Errorf("Expected agent-2 to hold the issue, got %q", got.Assignee)
+	}
+
+	if _, err := store.UnderlyingDB().Exec(`UPDATE issue_leases SET expires_at = ?`, time.Now().Add(-time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	released, err := store.ReleaseExpiredLeases(ctx)
+	if err != nil {
+		t.Fatalf("ReleaseExpiredLeases failed: %v", err)
+	}
+	if len(released) != 1 || released[0].Holder != "agent-2" {
+		t.Errorf("Expected agent-2's lease to be released, got %v", released)
+	}
+	got, _ = store.GetIssue(ctx, issue.ID)
+	if got.Status != types.StatusOpen || got.Assignee != "" {
+		t.Errorf("Expected expiry to return work to the pool, got %s for %q", got.Status, got.Assignee)
+	}
+}
+
+func TestClaimNext(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	newIssue := func(title string, priority int, assignee string) *types.Issue {
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: priority, IssueType: types.TypeTask, Assignee: assignee}
+		if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		return issue
+	}
+	newIssue("Someone else's", 0, "bob")
+	first := newIssue("First", 1, "")
+	second := newIssue("Second", 2, "")
+
+	claimed := map[string]bool{}
+	for _, holder := range []string{"agent-1", "agent-2"} {
+		issue, lease, err := store.ClaimNext(ctx, holder, time.Minute, types.WorkFilter{SortPolicy: types.SortPolicyPriority})
+		if err != nil {
+			t.Fatalf("ClaimNext failed: %v", err)
+		}
+		if issue == nil || lease.Holder != holder || issue.Assignee != holder {
+			t.Fatalf("Expected %s to claim an issue, got %+v", holder, issue)
+		}
+		claimed[issue.ID] = true
+	}
+	if !claimed[first.ID] || !claimed[second.ID] {
+		t.Errorf("Expected %s and %s to be claimed, got %v", first.ID, second.ID, claimed)
+	}
+
+	issue, lease, err := store.ClaimNext(ctx, "agent-3", time.Minute, types.WorkFilter{})
+	if err != nil || issue != nil || lease != nil {
+		t.Errorf("Expected nothing left to claim, got %+v, %v", issue, err)
+	}
+}
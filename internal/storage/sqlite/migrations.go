@@ -0,0 +1,339 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Migrations are applied in version order when a database is opened
+// read-write, and recorded in schema_migrations so each runs once. To change
+// the schema, add a pair of files to migrations/ numbered one past the
+// newest:
+//
+//	0003_add_widgets.up.sql    applied going up
+//	0003_add_widgets.down.sql  undoes it (optional; without it the
+//	                           migration can't be rolled back)
+//
+// Each file runs in one transaction, which holds the database's write lock,
+// so processes opening the database at the same time apply it exactly once.
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationsTable records the migrations applied to a database
+const migrationsTable = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version INTEGER PRIMARY KEY,
+    name TEXT NOT NULL,
+    applied_at DATETIME NOT NULL
+)`
+
+// Migration is one step in the schema's history
+type Migration struct {
+	Version int
+	Name    string
+	Up      string // SQL applying the migration
+	Down    string // SQL undoing it; empty if it can't be undone
+
+	apply func(*sql.DB) error // Go in place of Up, for the baseline
+}
+
+// Reversible reports whether the migration can be rolled back
+func (m *Migration) Reversible() bool {
+	return m.Down != ""
+}
+
+func (m *Migration) String() string {
+	return fmt.Sprintf("%04d_%s", m.Version, m.Name)
+}
+
+// migrations is every migration this version of bd knows, oldest first.
+// Version 1 is the schema from before versioned migrations, applied with the
+// idempotent steps that used to run on every open, so databases made by any
+// older bd start from it.
+var migrations = loadMigrations()
+
+func loadMigrations() []*Migration {
+	all := []*Migration{{Version: 1, Name: "baseline", apply: migrateBaseline}}
+	byVersion := map[int]*Migration{1: all[0]}
+
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		panic(fmt.Sprintf("migrations: %v", err))
+	}
+	for _, entry := range entries {
+		file := entry.Name()
+		base, direction, ok := strings.Cut(strings.TrimSuffix(file, ".sql"), ".")
+		number, name, ok2 := strings.Cut(base, "_")
+		version, err := strconv.Atoi(number)
+		if !ok || !ok2 || err != nil || version < 2 || (direction != "up" && direction != "down") {
+			panic(fmt.Sprintf("migrations: bad file name %s, want NNNN_name.up.sql or NNNN_name.down.sql", file))
+		}
+		data, err := migrationFiles.ReadFile("migrations/" + file)
+		if err != nil {
+			panic(fmt.Sprintf("migrations: %v", err))
+		}
+
+		m := byVersion[version]
+		if m == nil {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+			all = append(all, m)
+		} else if m.Name != name {
+			panic(fmt.Sprintf("migrations: version %d is both %s and %s", version, m.Name, name))
+		}
+		if direction == "up" {
+			m.Up = string(data)
+		} else {
+			m.Down = string(data)
+		}
+	}
+
+	sort.Slice(all, func(i, j int) bool { return all[i].Version < all[j].Version })
+	for i, m := range all {
+		if m.Version != i+1 {
+			panic(fmt.Sprintf("migrations: %s should be version %d", m, i+1))
+		}
+		if m.apply == nil && m.Up == "" {
+			panic(fmt.Sprintf("migrations: %s has no .up.sql", m))
+		}
+	}
+	return all
+}
+
+// LatestMigration returns the newest schema version this bd knows
+func LatestMigration() int {
+	return migrations[len(migrations)-1].Version
+}
+
+// MigrationStatus is a migration and whether the database has it
+type MigrationStatus struct {
+	Version    int        `json:"version"`
+	Name       string     `json:"name"`
+	AppliedAt  *time.Time `json:"applied_at,omitempty"`
+	Reversible bool       `json:"reversible"`
+	Unknown    bool       `json:"unknown,omitempty"` // Applied by a newer bd
+}
+
+// migrate brings the database up to the newest schema
+func migrate(db *sql.DB) error {
+	_, err := migrateUp(db, 0)
+	return err
+}
+
+// migrateUp applies the migrations the database lacks, up to version to (0
+// for all of them), and returns the versions it applied. Migrations from a
+// newer bd are left alone.
+func migrateUp(db *sql.DB, to int) ([]int, error) {
+	if _, err := db.Exec(migrationsTable); err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var ran []int
+	for _, m := range migrations {
+		if to > 0 && m.Version > to {
+			break
+		}
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+		done, err := applyMigration(db, m)
+		if err != nil {
+			return ran, fmt.Errorf("migration %s failed: %w", m, err)
+		}
+		if done {
+			ran = append(ran, m.Version)
+		}
+	}
+	return ran, nil
+}
+
+// applyMigration applies m and records it, reporting false if another
+// process applied it first
+func applyMigration(db *sql.DB, m *Migration) (bool, error) {
+	if m.apply != nil {
+		// The baseline's steps are idempotent and manage their own
+		// transactions, so racing processes may both run them harmlessly
+		if err := m.apply(db); err != nil {
+			return false, err
+		}
+		res, err := db.Exec(`INSERT OR IGNORE INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`,
+			m.Version, m.Name, time.Now())
+		if err != nil {
+			return false, err
+		}
+		n, _ := res.RowsAffected()
+		return n > 0, nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// Check again under the write lock, which a process that got here first
+	// held until it committed
+	var exists bool
+	if err := tx.QueryRow(`SELECT COUNT(*) > 0 FROM schema_migrations WHERE version = ?`, m.Version).Scan(&exists); err != nil {
+		return false, err
+	}
+	if exists {
+		return false, nil
+	}
+	if _, err := tx.Exec(m.Up); err != nil {
+		return false, err
+	}
+	if _, err := tx.Exec(`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`,
+		m.Version, m.Name, time.Now()); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// migrateDown rolls back the applied migrations newer than version to,
+// newest first, and returns the versions it rolled back
+func migrateDown(db *sql.DB, to int) ([]int, error) {
+	if to < 1 {
+		return nil, fmt.Errorf("can't roll back the baseline (version 1)")
+	}
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var undo []*Migration
+	for version := range applied {
+		if version <= to {
+			continue
+		}
+		if version > LatestMigration() {
+			return nil, fmt.Errorf("version %d was applied by a newer bd; roll it back with that version", version)
+		}
+		m := migrations[version-1]
+		if !m.Reversible() {
+			return nil, fmt.Errorf("migration %s can't be rolled back", m)
+		}
+		undo = append(undo, m)
+	}
+	sort.Slice(undo, func(i, j int) bool { return undo[i].Version > undo[j].Version })
+
+	var ran []int
+	for _, m := range undo {
+		if err := revertMigration(db, m); err != nil {
+			return ran, fmt.Errorf("rolling back migration %s failed: %w", m, err)
+		}
+		ran = append(ran, m.Version)
+	}
+	return ran, nil
+}
+
+func revertMigration(db *sql.DB, m *Migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+	res, err := tx.Exec(`DELETE FROM schema_migrations WHERE version = ?`, m.Version)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil // Another process rolled it back first
+	}
+	if _, err := tx.Exec(m.Down); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// appliedMigrations returns when each applied migration was applied, by
+// version
+func appliedMigrations(db *sql.DB) (map[int]appliedMigration, error) {
+	var exists bool
+	err := db.QueryRow(`
+		SELECT COUNT(*) > 0 FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'
+	`).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for schema_migrations table: %w", err)
+	}
+	applied := map[int]appliedMigration{}
+	if !exists {
+		return applied, nil
+	}
+
+	rows, err := db.Query(`SELECT version, name, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var version int
+		var a appliedMigration
+		if err := rows.Scan(&version, &a.name, &a.at); err != nil {
+			return nil, err
+		}
+		applied[version] = a
+	}
+	return applied, rows.Err()
+}
+
+type appliedMigration struct {
+	name string
+	at   time.Time
+}
+
+// Migrations reports every migration this bd knows and any applied by a
+// newer one, oldest first, with when each was applied
+func (s *SQLiteStorage) Migrations(ctx context.Context) ([]*MigrationStatus, error) {
+	applied, err := appliedMigrations(s.reads)
+	if err != nil {
+		return nil, err
+	}
+	var statuses []*MigrationStatus
+	for _, m := range migrations {
+		status := &MigrationStatus{Version: m.Version, Name: m.Name, Reversible: m.Reversible()}
+		if a, ok := applied[m.Version]; ok {
+			at := a.at
+			status.AppliedAt = &at
+		}
+		statuses = append(statuses, status)
+	}
+	var newer []int
+	for version := range applied {
+		if version > LatestMigration() {
+			newer = append(newer, version)
+		}
+	}
+	sort.Ints(newer)
+	for _, version := range newer {
+		at := applied[version].at
+		statuses = append(statuses, &MigrationStatus{Version: version, Name: applied[version].name, AppliedAt: &at, Unknown: true})
+	}
+	return statuses, nil
+}
+
+// MigrateUp applies pending migrations up to version to, or all of them if
+// to is 0, and returns the versions applied. Opening a database read-write
+// already applies them all, so this matters only after MigrateDown.
+func (s *SQLiteStorage) MigrateUp(ctx context.Context, to int) ([]int, error) {
+	return migrateUp(s.db, to)
+}
+
+// MigrateDown rolls back applied migrations newer than version to and
+// returns the versions rolled back. The next read-write open applies them
+// again, so this is for handing the database to an older bd.
+func (s *SQLiteStorage) MigrateDown(ctx context.Context, to int) ([]int, error) {
+	return migrateDown(s.db, to)
+}
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS replica_fields;
+DROP TABLE IF EXISTS replica_clock;
+DROP TABLE IF EXISTS replica_issues;
+DROP TABLE IF EXISTS oplog;
+DELETE FROM metadata WHERE key = 'replica_id';
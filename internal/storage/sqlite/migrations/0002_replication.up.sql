@@ -0,0 +1,41 @@
+-- Oplog: every change to an issue as a replication op, from this replica
+-- and the ones it has synced with; clock is the op's vector clock as JSON
+CREATE TABLE IF NOT EXISTS oplog (
+    replica_id TEXT NOT NULL,
+    seq INTEGER NOT NULL,
+    issue_id TEXT NOT NULL,
+    origin TEXT NOT NULL DEFAULT '',
+    kind TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    fields TEXT NOT NULL,
+    clock TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (replica_id, seq)
+);
+
+-- Replica issues: the op that created each issue, as replica:seq, which
+-- identifies it across replicas that gave it different IDs
+CREATE TABLE IF NOT EXISTS replica_issues (
+    origin TEXT PRIMARY KEY,
+    issue_id TEXT NOT NULL UNIQUE,
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+);
+
+-- Replica clock: the highest oplog sequence number from each replica, which
+-- together make this replica's vector clock
+CREATE TABLE IF NOT EXISTS replica_clock (
+    replica_id TEXT PRIMARY KEY,
+    seq INTEGER NOT NULL
+);
+
+-- Replica fields: the op that last wrote each issue field, for resolving
+-- concurrent writes from other replicas
+CREATE TABLE IF NOT EXISTS replica_fields (
+    issue_id TEXT NOT NULL,
+    field TEXT NOT NULL,
+    replica_id TEXT NOT NULL,
+    clock TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (issue_id, field),
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+);
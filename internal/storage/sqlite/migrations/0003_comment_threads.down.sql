@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS comment_reactions;
+DROP TABLE IF EXISTS comment_edits;
+DROP INDEX IF EXISTS idx_comments_reply_to;
+ALTER TABLE comments DROP COLUMN updated_at;
+ALTER TABLE comments DROP COLUMN reply_to;
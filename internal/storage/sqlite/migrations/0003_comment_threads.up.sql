@@ -0,0 +1,27 @@
+-- Comment threads and edits: the comment each comment replies to, on the same
+-- issue, and when its text was last edited
+ALTER TABLE comments ADD COLUMN reply_to INTEGER;
+ALTER TABLE comments ADD COLUMN updated_at DATETIME;
+CREATE INDEX IF NOT EXISTS idx_comments_reply_to ON comments(reply_to);
+
+-- Comment edits: the earlier versions of edited comments, with text as
+-- stored, so anything under field encryption stays encrypted
+CREATE TABLE IF NOT EXISTS comment_edits (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    comment_id INTEGER NOT NULL,
+    text TEXT NOT NULL,
+    edited_by TEXT NOT NULL,
+    edited_at DATETIME NOT NULL,
+    FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_comment_edits_comment ON comment_edits(comment_id);
+
+-- Comment reactions: who reacted to a comment, and with what
+CREATE TABLE IF NOT EXISTS comment_reactions (
+    comment_id INTEGER NOT NULL,
+    reaction TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (comment_id, reaction, actor),
+    FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE
+);
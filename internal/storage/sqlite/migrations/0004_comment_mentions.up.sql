@@ -0,0 +1,9 @@
+-- Comment mentions: the users each comment @mentions, kept in line with its
+-- text as it's edited
+CREATE TABLE IF NOT EXISTS comment_mentions (
+    comment_id INTEGER NOT NULL,
+    user TEXT NOT NULL,
+    PRIMARY KEY (comment_id, user),
+    FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_comment_mentions_user ON comment_mentions(user);
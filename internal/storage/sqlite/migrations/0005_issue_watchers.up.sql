@@ -0,0 +1,9 @@
+-- Issue watchers: users subscribed to every change on an issue
+CREATE TABLE IF NOT EXISTS issue_watchers (
+    issue_id TEXT NOT NULL,
+    user TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (issue_id, user),
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_issue_watchers_user ON issue_watchers(user);
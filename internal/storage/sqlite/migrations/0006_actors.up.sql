@@ -0,0 +1,14 @@
+-- Actor registry: the people, agents, and bots that act on issues. Names,
+-- aliases, and emails all resolve to the actor's name, ignoring case.
+CREATE TABLE IF NOT EXISTS actors (
+    name TEXT PRIMARY KEY COLLATE NOCASE,
+    email TEXT NOT NULL DEFAULT '',
+    type TEXT NOT NULL DEFAULT 'human',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE TABLE IF NOT EXISTS actor_aliases (
+    alias TEXT PRIMARY KEY COLLATE NOCASE,
+    actor TEXT NOT NULL COLLATE NOCASE,
+    FOREIGN KEY (actor) REFERENCES actors(name) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_actor_aliases_actor ON actor_aliases(actor);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_events_session;
+ALTER TABLE events DROP COLUMN session_id;
+DROP TABLE IF EXISTS agent_sessions;
@@ -0,0 +1,15 @@
+-- Agent sessions: one run of an agent, with the model and tool behind it.
+-- Events record the session they were written in, if any.
+CREATE TABLE IF NOT EXISTS agent_sessions (
+    id TEXT PRIMARY KEY,
+    actor TEXT NOT NULL,
+    model TEXT NOT NULL DEFAULT '',
+    tool TEXT NOT NULL DEFAULT '',
+    run_id TEXT NOT NULL DEFAULT '',
+    started_at DATETIME NOT NULL,
+    ended_at DATETIME
+);
+CREATE INDEX IF NOT EXISTS idx_agent_sessions_actor ON agent_sessions(actor);
+
+ALTER TABLE events ADD COLUMN session_id TEXT;
+CREATE INDEX IF NOT EXISTS idx_events_session ON events(session_id);
@@ -0,0 +1,16 @@
+-- Triage rules: conditions on new or updated issues and the labels,
+-- priority, and assignee to give the issues that match them
+CREATE TABLE IF NOT EXISTS triage_rules (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    on_event TEXT NOT NULL DEFAULT 'create',
+    title_match TEXT NOT NULL DEFAULT '',
+    description_match TEXT NOT NULL DEFAULT '',
+    issue_type TEXT NOT NULL DEFAULT '',
+    priority INTEGER,
+    add_labels TEXT NOT NULL DEFAULT '',
+    set_priority INTEGER,
+    set_assignee TEXT NOT NULL DEFAULT '',
+    created_by TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,10 @@
+-- Retention reports: what each enforced retention run deleted and how it
+-- rewrote the audit chain, optionally signed
+CREATE TABLE IF NOT EXISTS retention_reports (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    report TEXT NOT NULL,
+    method TEXT NOT NULL DEFAULT '',
+    signer TEXT NOT NULL DEFAULT '',
+    signature TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
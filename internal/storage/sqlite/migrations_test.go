@@ -0,0 +1,162 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestMigrationsUpAndDown(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t, filepath.Join(t.TempDir(), "beads.db"))
+	defer store.Close()
+
+	statuses, err := store.Migrations(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != LatestMigration() {
+		t.Fatalf("Expected %d migrations, got %d", LatestMigration(), len(statuses))
+	}
+	for _, s := range statuses {
+		if s.AppliedAt == nil {
+			t.Errorf("Expected migration %d applied on open", s.Version)
+		}
+	}
+	if statuses[0].Name != "baseline" || statuses[0].Reversible {
+		t.Errorf("Expected an irreversible baseline first, got %+v", statuses[0])
+	}
+
+	undone, err := store.MigrateDown(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(undone) != LatestMigration()-1 || undone[0] != LatestMigration() {
+		t.Errorf("Expected every migration after the baseline rolled back newest first, got %v", undone)
+	}
+	var tables int
+	if err := store.db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name = 'oplog'`).Scan(&tables); err != nil {
+		t.Fatal(err)
+	}
+	if tables != 0 {
+		t.Error("Expected rolling back 0002_replication to drop the oplog")
+	}
+	if err := store.CheckSchema(ctx); err == nil || !strings.Contains(err.Error(), "migration 0002_replication") {
+		t.Errorf("Expected the pending migration reported, got %v", err)
+	}
+	if _, err := store.MigrateDown(ctx, 0); err == nil {
+		t.Error("Expected the baseline not to roll back")
+	}
+
+	applied, err := store.MigrateUp(ctx, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != LatestMigration()-1 {
+		t.Errorf("Expected the rolled back migrations applied again, got %v", applied)
+	}
+	if err := store.CheckSchema(ctx); err != nil {
+		t.Error(err)
+	}
+	if applied, err := store.MigrateUp(ctx, 0); err != nil || len(applied) != 0 {
+		t.Errorf("Expected nothing left to apply, got %v, %v", applied, err)
+	}
+}
+
+// TestMigrationsFromUnversionedDatabase opens a database last opened by a bd
+// from before versioned migrations
+func TestMigrationsFromUnversionedDatabase(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "beads.db")
+	store := newTestStore(t, dbPath)
+	issue := &types.Issue{Title: "Old", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	// Such a bd had only the baseline schema
+	if _, err := store.MigrateDown(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.db.Exec(`DROP TABLE schema_migrations`); err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+
+	store, err := New(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if err := store.CheckSchema(ctx); err != nil {
+		t.Error(err)
+	}
+	if got, err := store.GetIssue(ctx, issue.ID); err != nil || got == nil {
+		t.Errorf("Expected the issue kept, got %v, %v", got, err)
+	}
+}
+
+// TestMigrationsFromNewerBd opens a database a newer bd has migrated
+func TestMigrationsFromNewerBd(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "beads.db")
+	store := newTestStore(t, dbPath)
+	if _, err := store.db.Exec(`INSERT INTO schema_migrations (version, name, applied_at) VALUES (999, 'future', CURRENT_TIMESTAMP)`); err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+
+	store, err := New(dbPath)
+	if err != nil {
+		t.Fatalf("Expected a database from a newer bd to open: %v", err)
+	}
+	defer store.Close()
+	statuses, err := store.Migrations(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last := statuses[len(statuses)-1]; last.Version != 999 || !last.Unknown || last.AppliedAt == nil {
+		t.Errorf("Expected the newer migration listed as unknown, got %+v", last)
+	}
+	if _, err := store.MigrateDown(ctx, 1); err == nil || !strings.Contains(err.Error(), "newer bd") {
+		t.Errorf("Expected rolling back the newer migration refused, got %v", err)
+	}
+}
+
+func TestConcurrentOpensMigrateOnce(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "beads.db")
+	var wg sync.WaitGroup
+	errs := make(chan error, 4)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			store, err := New(dbPath)
+			if err != nil {
+				errs <- err
+				return
+			}
+			errs <- store.Close()
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store := newTestStore(t, dbPath)
+	defer store.Close()
+	var n int
+	if err := store.db.QueryRow(`SELECT COUNT(*) FROM schema_migrations`).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != LatestMigration() {
+		t.Errorf("Expected each migration recorded once, got %d rows", n)
+	}
+}
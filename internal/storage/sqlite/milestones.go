@@ -0,0 +1,196 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// Milestone is a time-boxed sprint that issues are assigned to by name. The
+// progress fields roll up the estimated minutes of its issues and are filled
+// in on read.
+type Milestone struct {
+	Name            string    `json:"name"`
+	StartDate       time.Time `json:"start_date"`
+	EndDate         time.Time `json:"end_date"` // last day of the milestone
+	CapacityMinutes int       `json:"capacity_minutes"`
+	CreatedBy       string    `json:"created_by"`
+	CreatedAt       time.Time `json:"created_at"`
+
+	Issues           int `json:"issues"`
+	ClosedIssues     int `json:"closed_issues"`
+	Unestimated      int `json:"unestimated"`       // issues without estimated_minutes
+	CommittedMinutes int `json:"committed_minutes"` // estimated minutes of every assigned issue
+	CompletedMinutes int `json:"completed_minutes"` // estimated minutes of the closed ones
+}
+
+// OverCapacity reports whether more work is committed than the milestone can hold
+func (m *Milestone) OverCapacity() bool {
+	return m.CapacityMinutes > 0 && m.CommittedMinutes > m.CapacityMinutes
+}
+
+// Active reports whether now falls within the milestone, counting the whole
+// of its end date
+func (m *Milestone) Active(now time.Time) bool {
+	return !now.Before(m.StartDate) && now.Before(m.EndDate.AddDate(0, 0, 1))
+}
+
+// CreateMilestone adds a milestone and sets its creation time
+func (s *SQLiteStorage) CreateMilestone(ctx context.Context, m *Milestone) error {
+	m.Name = strings.TrimSpace(m.Name)
+	if m.Name == "" {
+		return fmt.Errorf("milestone name is required")
+	}
+	if m.StartDate.IsZero() || m.EndDate.IsZero() {
+		return fmt.Errorf("milestone start and end dates are required")
+	}
+	if m.EndDate.Before(m.StartDate) {
+		return fmt.Errorf("milestone end date cannot be before its start date")
+	}
+	if m.CapacityMinutes < 0 {
+		return fmt.Errorf("milestone capacity cannot be negative")
+	}
+
+	existing, err := s.GetMilestone(ctx, m.Name)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("milestone %s already exists", m.Name)
+	}
+
+	m.CreatedAt = time.Now()
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO milestones (name, start_date, end_date, capacity_minutes, created_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, m.Name, m.StartDate, m.EndDate, m.CapacityMinutes, m.CreatedBy, m.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create milestone: %w", err)
+	}
+	return nil
+}
+
+// milestoneSelect rolls up each milestone's issues; trashed issues don't count
+const milestoneSelect = `
+	SELECT m.name, m.start_date, m.end_date, m.capacity_minutes, m.created_by, m.created_at,
+	       COUNT(i.id),
+	       COALESCE(SUM(i.status = 'closed'), 0),
+	       COALESCE(SUM(i.id IS NOT NULL AND i.estimated_minutes IS NULL), 0),
+	       COALESCE(SUM(i.estimated_minutes), 0),
+	       COALESCE(SUM(CASE WHEN i.status = 'closed' THEN i.estimated_minutes END), 0)
+	FROM milestones m
+	LEFT JOIN issues i ON i.milestone = m.name AND i.deleted_at IS NULL
+`
+
+// ListMilestones returns every milestone with its progress, earliest first
+func (s *SQLiteStorage) ListMilestones(ctx context.Context) ([]*Milestone, error) {
+	rows, err := s.reads.QueryContext(ctx, milestoneSelect+`
+		GROUP BY m.name
+		ORDER BY m.start_date, m.name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list milestones: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var milestones []*Milestone
+	for rows.Next() {
+		m, err := scanMilestone(rows)
+		if err != nil {
+			return nil, err
+		}
+		milestones = append(milestones, m)
+	}
+	return milestones, rows.Err()
+}
+
+// GetMilestone returns a milestone with its progress, or nil if it doesn't exist
+func (s *SQLiteStorage) GetMilestone(ctx context.Context, name string) (*Milestone, error) {
+	row := s.reads.QueryRowContext(ctx, milestoneSelect+`
+		WHERE m.name = ?
+		GROUP BY m.name
+	`, name)
+	m, err := scanMilestone(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return m, err
+}
+
+// AssignMilestone puts issues in a milestone, recording each change in the
+// issue history. An empty name takes them out of any milestone.
+func (s *SQLiteStorage) AssignMilestone(ctx context.Context, name string, ids []string, actor string) error {
+	if name != "" {
+		m, err := s.GetMilestone(ctx, name)
+		if err != nil {
+			return err
+		}
+		if m == nil {
+			return types.NotFound("milestone", name)
+		}
+	}
+
+	for _, id := range ids {
+		issue, err := s.GetIssue(ctx, id)
+		if err != nil {
+			return err
+		}
+		if issue == nil {
+			return types.NotFound("issue", id)
+		}
+		if issue.Milestone == name {
+			continue
+		}
+		if err := s.UpdateIssue(ctx, id, map[string]interface{}{"milestone": name}, actor); err != nil {
+			return fmt.Errorf("failed to assign %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// DeleteMilestone removes a milestone and takes its issues out of it
+func (s *SQLiteStorage) DeleteMilestone(ctx context.Context, name, actor string) error {
+	m, err := s.GetMilestone(ctx, name)
+	if err != nil {
+		return err
+	}
+	if m == nil {
+		return types.NotFound("milestone", name)
+	}
+
+	issues, err := s.SearchIssues(ctx, "", types.IssueFilter{Milestone: &name})
+	if err != nil {
+		return err
+	}
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	if err := s.AssignMilestone(ctx, "", ids, actor); err != nil {
+		return err
+	}
+
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM milestones WHERE name = ?`, name); err != nil {
+		return fmt.Errorf("failed to delete milestone: %w", err)
+	}
+	return nil
+}
+
+func scanMilestone(row rowScanner) (*Milestone, error) {
+	var m Milestone
+	err := row.Scan(
+		&m.Name, &m.StartDate, &m.EndDate, &m.CapacityMinutes, &m.CreatedBy, &m.CreatedAt,
+		&m.Issues, &m.ClosedIssues, &m.Unestimated, &m.CommittedMinutes, &m.CompletedMinutes,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan milestone: %w", err)
+	}
+	return &m, nil
+}
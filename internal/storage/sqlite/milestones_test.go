@@ -0,0 +1,104 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestMilestones(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	start := time.Date(2025, 3, 3, 0, 0, 0, 0, time.Local)
+
+	sprint := &Milestone{Name: "sprint-1", StartDate: start, EndDate: start.AddDate(0, 0, 13), CapacityMinutes: 300, CreatedBy: "alice"}
+	if err := store.CreateMilestone(ctx, sprint); err != nil {
+		t.Fatalf("CreateMilestone failed: %v", err)
+	}
+	if err := store.CreateMilestone(ctx, &Milestone{Name: "sprint-1", StartDate: start, EndDate: start}); err == nil {
+		t.Error("Expected an error for a duplicate milestone")
+	}
+	if err := store.CreateMilestone(ctx, &Milestone{Name: "backwards", StartDate: start, EndDate: start.AddDate(0, 0, -1)}); err == nil {
+		t.Error("Expected an error for an end date before the start date")
+	}
+
+	var ids []string
+	for i, mins := range []int{120, 90, 150} {
+		issue := &types.Issue{Title: "Work", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, EstimatedMinutes: &mins}
+		if i == 2 {
+			issue.EstimatedMinutes = nil
+		}
+		if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		ids = append(ids, issue.ID)
+	}
+	extra := 200
+	late := &types.Issue{Title: "Late addition", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, EstimatedMinutes: &extra}
+	if err := store.CreateIssue(ctx, late, "alice"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	if err := store.AssignMilestone(ctx, "sprint-1", ids, "alice"); err != nil {
+		t.Fatalf("AssignMilestone failed: %v", err)
+	}
+	if err := store.AssignMilestone(ctx, "sprint-9", ids, "alice"); err == nil {
+		t.Error("Expected an error assigning to an unknown milestone")
+	}
+	if err := store.CloseIssue(ctx, ids[0], "done", "alice"); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+
+	got, err := store.GetMilestone(ctx, "sprint-1")
+	if err != nil {
+		t.Fatalf("GetMilestone failed: %v", err)
+	}
+	if got.Issues != 3 || got.ClosedIssues != 1 || got.Unestimated != 1 || got.CommittedMinutes != 210 || got.CompletedMinutes != 120 {
+		t.Errorf("Unexpected progress %+v", got)
+	}
+	if got.OverCapacity() {
+		t.Error("Expected 210 of 300 minutes to fit")
+	}
+
+	// Assigning through an update works too
+	if err := store.UpdateIssue(ctx, late.ID, map[string]interface{}{"milestone": "sprint-1"}, "alice"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	got, _ = store.GetMilestone(ctx, "sprint-1")
+	if got.CommittedMinutes != 410 || !got.OverCapacity() {
+		t.Errorf("Expected 410 minutes over capacity, got %+v", got)
+	}
+
+	name := "sprint-1"
+	issues, err := store.SearchIssues(ctx, "", types.IssueFilter{Milestone: &name})
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+	if len(issues) != 4 || issues[0].Milestone != "sprint-1" {
+		t.Errorf("Expected 4 issues in sprint-1, got %d", len(issues))
+	}
+
+	if missing, err := store.GetMilestone(ctx, "sprint-9"); err != nil || missing != nil {
+		t.Errorf("Expected no sprint-9, got %v, %v", missing, err)
+	}
+
+	// Deleting the milestone unassigns its issues
+	if err := store.DeleteMilestone(ctx, "sprint-1", "alice"); err != nil {
+		t.Fatalf("DeleteMilestone failed: %v", err)
+	}
+	issue, _ := store.GetIssue(ctx, late.ID)
+	if issue.Milestone != "" {
+		t.Errorf("Expected %s to be unassigned, got %q", late.ID, issue.Milestone)
+	}
+	milestones, err := store.ListMilestones(ctx)
+	if err != nil {
+		t.Fatalf("ListMilestones failed: %v", err)
+	}
+	if len(milestones) != 0 {
+		t.Errorf("Expected no milestones, got %d", len(milestones))
+	}
+}
@@ -0,0 +1,143 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Pool defaults, used for zero PoolConfig fields
+const (
+	defaultReadConnections = 4
+	defaultBusyTimeout     = 30 * time.Second
+	defaultSynchronous     = "full"
+)
+
+// PoolConfig tunes how the storage connects to its database file.
+//
+// Every write goes through a single connection, so concurrent mutations
+// from one process queue for it in turn rather than racing for SQLite's
+// write lock, and transactions begin IMMEDIATE so they never fail halfway
+// through upgrading to it. Reads use a separate pool of read-only
+// connections, which WAL mode lets run alongside the writer. The busy
+// timeout only matters when other processes (the CLI, a daemon) write too.
+//
+// With ReplicaPath set, the read pool opens a copy of the database kept up
+// to date by some other tool (Litestream, rsync, a shared volume) instead,
+// taking read load off the primary. Reads then lag writes by however far
+// the replica is behind.
+type PoolConfig struct {
+	ReadConnections   int           // Size of the read pool (default 4)
+	BusyTimeout       time.Duration // How long to wait for another process's lock (default 30s)
+	Synchronous       string        // PRAGMA synchronous: full (default) or normal; normal is safe with WAL but may lose the last commits on power loss
+	WALAutocheckpoint int           // Pages written before the WAL is checkpointed into the database (default SQLite's 1000)
+	ReadOnly          bool          // Refuse every write, migrations included; the database must exist and be up to date
+	ReplicaPath       string        // Database file to serve reads from instead of the primary
+}
+
+// withDefaults fills in zero fields and checks the rest
+func (c PoolConfig) withDefaults() (PoolConfig, error) {
+	if c.ReadConnections < 0 || c.WALAutocheckpoint < 0 || c.BusyTimeout < 0 {
+		return c, fmt.Errorf("pool settings must not be negative")
+	}
+	if c.ReadConnections == 0 {
+		c.ReadConnections = defaultReadConnections
+	}
+	if c.BusyTimeout == 0 {
+		c.BusyTimeout = defaultBusyTimeout
+	}
+	c.Synchronous = strings.ToLower(c.Synchronous)
+	switch c.Synchronous {
+	case "":
+		c.Synchronous = defaultSynchronous
+	case "off", "normal", "full", "extra":
+	default:
+		return c, fmt.Errorf("invalid synchronous setting %q: expected off, normal, full, or extra", c.Synchronous)
+	}
+	return c, nil
+}
+
+// connString adds the pragmas every connection runs to dbPath.
+// _time_format=sqlite enables automatic parsing of DATETIME columns to
+// time.Time. Shared memory URLs already have a query string, so their
+// parameters are added with & rather than ?.
+func (c PoolConfig) connString(dbPath string) string {
+	params := []string{
+		"_pragma=journal_mode(WAL)",
+		"_pragma=foreign_keys(ON)",
+		fmt.Sprintf("_pragma=busy_timeout(%d)", c.BusyTimeout.Milliseconds()),
+		fmt.Sprintf("_pragma=synchronous(%s)", c.Synchronous),
+		"_time_format=sqlite",
+	}
+	if c.WALAutocheckpoint > 0 {
+		params = append(params, fmt.Sprintf("_pragma=wal_autocheckpoint(%d)", c.WALAutocheckpoint))
+	}
+	if c.ReadOnly {
+		params = append(params, "_pragma=query_only(1)")
+	}
+	sep := "?"
+	if strings.Contains(dbPath, "?") {
+		sep = "&"
+	}
+	return dbPath + sep + strings.Join(params, "&")
+}
+
+// openWritePool opens the single connection all writes share. In-memory
+// databases keep one unrestricted pool for reads and writes, since a
+// shared-cache database locks whole tables between connections.
+func openWritePool(dbPath string, config PoolConfig) (*sql.DB, error) {
+	connStr := config.connString(dbPath)
+	if isMemoryPath(dbPath) {
+		return sql.Open("sqlite", connStr)
+	}
+	db, err := sql.Open("sqlite", connStr+"&_txlock=immediate")
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+	return db, nil
+}
+
+// openReadPool opens the read-only connections, once the schema exists
+func openReadPool(dbPath string, config PoolConfig) (*sql.DB, error) {
+	config.ReadOnly = true
+	db, err := sql.Open("sqlite", config.connString(dbPath))
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(config.ReadConnections)
+	db.SetMaxIdleConns(config.ReadConnections)
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// Generation returns a number that changes whenever the database does:
+// after every write through this storage, and after commits by other
+// processes or connections. Caches compare it with the number they saw when
+// they loaded a result; the value itself means nothing.
+//
+// It asks the write connection, which sees its own changes in
+// total_changes() and everyone else's in PRAGMA data_version, so it waits
+// for a write in progress. In-memory databases, whose pool has no single
+// connection to ask, have no generation.
+func (s *SQLiteStorage) Generation(ctx context.Context) (uint64, error) {
+	if isMemoryPath(s.dbPath) {
+		return 0, fmt.Errorf("in-memory databases have no generation")
+	}
+	var dataVersion, changes uint64
+	err := s.db.QueryRowContext(ctx, `SELECT data_version, total_changes() FROM pragma_data_version`).Scan(&dataVersion, &changes)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read database generation: %w", err)
+	}
+	return dataVersion + changes, nil
+}
+
+func isMemoryPath(dbPath string) bool {
+	return strings.Contains(dbPath, ":memory:")
+}
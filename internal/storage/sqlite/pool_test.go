@@ -0,0 +1,210 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestPoolConfigDefaults(t *testing.T) {
+	config, err := PoolConfig{Synchronous: "NORMAL"}.withDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.ReadConnections != 4 || config.BusyTimeout != 30*time.Second || config.Synchronous != "normal" {
+		t.Errorf("Unexpected defaults: %+v", config)
+	}
+	if s := config.connString("test.db"); !strings.Contains(s, "busy_timeout(30000)") || strings.Contains(s, "wal_autocheckpoint") {
+		t.Errorf("Unexpected connection string %q", s)
+	}
+
+	for _, bad := range []PoolConfig{{ReadConnections: -1}, {BusyTimeout: -time.Second}, {Synchronous: "sometimes"}} {
+		if _, err := bad.withDefaults(); err == nil {
+			t.Errorf("Expected %+v to be rejected", bad)
+		}
+	}
+}
+
+func TestConcurrentWrites(t *testing.T) {
+	store, err := NewWithConfig(filepath.Join(t.TempDir(), "test.db"), PoolConfig{ReadConnections: 2, BusyTimeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Far more writers than SQLite could take at once without queuing;
+	// none should see SQLITE_BUSY even with a short busy timeout
+	const writers = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			issue := &types.Issue{Title: fmt.Sprintf("Issue %d", i), Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+			if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+				errs <- err
+				return
+			}
+			if _, err := store.GetIssue(ctx, issue.ID); err != nil {
+				errs <- err
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("Concurrent write failed: %v", err)
+	}
+
+	issues, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != writers {
+		t.Errorf("Expected %d issues, got %d", writers, len(issues))
+	}
+
+	// Reads can't write by accident
+	if _, err := store.reads.ExecContext(ctx, `DELETE FROM issues`); err == nil {
+		t.Error("Expected the read pool to refuse writes")
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	if _, err := NewWithConfig(dbPath, PoolConfig{ReadOnly: true}); err == nil {
+		t.Fatal("Expected a read-only open to need an existing database")
+	}
+
+	store, err := New(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	issue := &types.Issue{Title: "Existing", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+
+	readOnly, err := NewWithConfig(dbPath, PoolConfig{ReadOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer readOnly.Close()
+	if got, err := readOnly.GetIssue(ctx, issue.ID); err != nil || got == nil {
+		t.Fatalf("GetIssue: %v %v", got, err)
+	}
+	if err := readOnly.UpdateIssue(ctx, issue.ID, map[string]interface{}{"title": "Changed"}, "test"); err == nil {
+		t.Error("Expected a read-only storage to refuse updates")
+	}
+	if _, err := readOnly.UnderlyingDB().Exec(`DELETE FROM issues`); err == nil {
+		t.Error("Expected the extension pool to be read-only too")
+	}
+}
+
+func TestReplica(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	open := func(name string) *types.Issue {
+		store, err := New(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+			t.Fatal(err)
+		}
+		issue := &types.Issue{Title: "Only in " + name, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatal(err)
+		}
+		store.Close()
+		return issue
+	}
+	onPrimary := open("primary.db")
+	onReplica := open("replica.db")
+
+	store, err := NewWithConfig(filepath.Join(dir, "primary.db"), PoolConfig{ReplicaPath: filepath.Join(dir, "replica.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	// Reads see the replica; writes land on the primary
+	issues, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 || issues[0].Title != onReplica.Title {
+		t.Errorf("Expected reads from the replica, got %v", issues)
+	}
+	if err := store.UpdateIssue(ctx, onPrimary.ID, map[string]interface{}{"priority": 0}, "test"); err != nil {
+		t.Fatal(err)
+	}
+	var priority int
+	if err := store.UnderlyingDB().QueryRow(`SELECT priority FROM issues WHERE id = ?`, onPrimary.ID).Scan(&priority); err != nil || priority != 0 {
+		t.Errorf("Expected the update on the primary, got priority %d (%v)", priority, err)
+	}
+
+	if _, err := NewWithConfig(filepath.Join(dir, "primary.db"), PoolConfig{ReplicaPath: filepath.Join(dir, "missing.db")}); err == nil {
+		t.Error("Expected a missing replica to be an error")
+	}
+}
+
+func TestGeneration(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	generation := func() uint64 {
+		t.Helper()
+		gen, err := store.Generation(ctx)
+		if err != nil {
+			t.Fatalf("Generation failed: %v", err)
+		}
+		return gen
+	}
+	start := generation()
+	if _, err := store.SearchIssues(ctx, "", types.IssueFilter{}); err != nil {
+		t.Fatal(err)
+	}
+	if gen := generation(); gen != start {
+		t.Errorf("Expected reads to leave the generation at %d, got %d", start, gen)
+	}
+
+	issue := &types.Issue{Title: "New", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatal(err)
+	}
+	afterCreate := generation()
+	if afterCreate == start {
+		t.Error("Expected a write to change the generation")
+	}
+
+	// A commit by another process (here, another storage on the same file)
+	other, err := New(store.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Close()
+	if err := other.UpdateIssue(ctx, issue.ID, map[string]interface{}{"priority": 0}, "test"); err != nil {
+		t.Fatal(err)
+	}
+	if generation() == afterCreate {
+		t.Error("Expected another connection's write to change the generation")
+	}
+}
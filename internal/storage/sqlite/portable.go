@@ -0,0 +1,217 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// Comments and events for workspace bundles (bd export --full), which carry
+// them with their original IDs and times. Text is exported as stored, so
+// anything under field encryption stays encrypted, as in the JSONL file.
+
+// ExportComments returns every comment, oldest first, with text as stored
+func (s *SQLiteStorage) ExportComments(ctx context.Context) ([]*types.Comment, error) {
+	rows, err := s.reads.QueryContext(ctx, `
+		SELECT id, issue_id, author, text, created_at, reply_to, updated_at
+		FROM comments
+		ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query comments: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var comments []*types.Comment
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, c)
+	}
+	return comments, rows.Err()
+}
+
+// ExportEvents returns every event, oldest first, with payloads as stored
+func (s *SQLiteStorage) ExportEvents(ctx context.Context) ([]*types.Event, error) {
+	rows, err := s.reads.QueryContext(ctx, `
+		SELECT id, issue_id, event_type, actor, old_value, new_value, comment, created_at, session_id
+		FROM events
+		ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var events []*types.Event
+	for rows.Next() {
+		e := &types.Event{}
+		var oldValue, newValue, comment, session sql.NullString
+		if err := rows.Scan(&e.ID, &e.IssueID, &e.EventType, &e.Actor, &oldValue, &newValue, &comment, &e.CreatedAt, &session); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		e.SessionID = session.String
+		e.OldValue = nullStringPtr(oldValue)
+		e.NewValue = nullStringPtr(newValue)
+		e.Comment = nullStringPtr(comment)
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// ImportComments adds exported comments with their original authors and
+// times, skipping any an issue already has (same author and text) and any
+// for issues that don't exist. Replies are threaded onto the comments their
+// exported reply_to names, as added or matched. Their mentions are recorded
+// without notifying anyone again. It returns how many it added.
+func (s *SQLiteStorage) ImportComments(ctx context.Context, comments []*types.Comment) (int, error) {
+	byIssue := make(map[string][]*types.Comment)
+	var order []string
+	for _, c := range comments {
+		if _, ok := byIssue[c.IssueID]; !ok {
+			order = append(order, c.IssueID)
+		}
+		byIssue[c.IssueID] = append(byIssue[c.IssueID], c)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	added := 0
+	newIDs := make(map[int64]int64) // Exported comment ID -> ID here
+	for _, issueID := range order {
+		var exists bool
+		if err := tx.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM issues WHERE id = ?)`, issueID).Scan(&exists); err != nil {
+			return 0, fmt.Errorf("failed to check issue existence: %w", err)
+		}
+		if !exists {
+			continue
+		}
+
+		seen := make(map[string]int64)
+		rows, err := tx.QueryContext(ctx, `SELECT id, author, text FROM comments WHERE issue_id = ?`, issueID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to query comments: %w", err)
+		}
+		for rows.Next() {
+			var id int64
+			var author, text string
+			if err := rows.Scan(&id, &author, &text); err != nil {
+				_ = rows.Close()
+				return 0, fmt.Errorf("failed to scan comment: %w", err)
+			}
+			seen[author+":"+strings.TrimSpace(s.decryptField(text))] = id
+		}
+		_ = rows.Close()
+
+		for _, c := range byIssue[issueID] {
+			text := s.decryptField(c.Text)
+			key := c.Author + ":" + strings.TrimSpace(text)
+			if id, ok := seen[key]; ok {
+				newIDs[c.ID] = id
+				continue
+			}
+			stored, err := s.encryptField(text)
+			if err != nil {
+				return 0, err
+			}
+			var replyTo *int64
+			if c.ReplyTo != nil {
+				if id, ok := newIDs[*c.ReplyTo]; ok {
+					replyTo = &id
+				}
+			}
+			res, err := tx.ExecContext(ctx, `
+				INSERT INTO comments (issue_id, author, text, created_at, reply_to, updated_at)
+				VALUES (?, ?, ?, ?, ?, ?)
+			`, issueID, c.Author, stored, c.CreatedAt, replyTo, c.UpdatedAt)
+			if err != nil {
+				return 0, fmt.Errorf("failed to insert comment: %w", err)
+			}
+			id, err := res.LastInsertId()
+			if err != nil {
+				return 0, fmt.Errorf("failed to get comment ID: %w", err)
+			}
+			for _, user := range types.CommentMentions(text) {
+				if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO comment_mentions (comment_id, user) VALUES (?, ?)`, id, user); err != nil {
+					return 0, fmt.Errorf("failed to record mention: %w", err)
+				}
+			}
+			seen[key] = id
+			newIDs[c.ID] = id
+			added++
+		}
+	}
+	return added, tx.Commit()
+}
+
+// ReplaceEvents replaces the event history of each issue the events are for
+// with the exported events, keeping their original actors and times. It is
+// for issues an import just created, whose only event is their creation.
+// Events for issues that don't exist are skipped.
+func (s *SQLiteStorage) ReplaceEvents(ctx context.Context, events []*types.Event) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	cleared := make(map[string]bool)
+	added := 0
+	for _, e := range events {
+		exists, done := cleared[e.IssueID]
+		if !done {
+			if err := tx.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM issues WHERE id = ?)`, e.IssueID).Scan(&exists); err != nil {
+				return 0, fmt.Errorf("failed to check issue existence: %w", err)
+			}
+			if exists {
+				if _, err := tx.ExecContext(ctx, `DELETE FROM events WHERE issue_id = ?`, e.IssueID); err != nil {
+					return 0, fmt.Errorf("failed to clear events: %w", err)
+				}
+			}
+			cleared[e.IssueID] = exists
+		}
+		if !exists {
+			continue
+		}
+
+		var oldValue, newValue, comment interface{}
+		if e.OldValue != nil {
+			oldValue = s.encryptEventPayload(s.decryptEventPayload(*e.OldValue))
+		}
+		if e.NewValue != nil {
+			newValue = s.encryptEventPayload(s.decryptEventPayload(*e.NewValue))
+		}
+		if e.Comment != nil {
+			c, err := s.encryptField(s.decryptField(*e.Comment))
+			if err != nil {
+				return 0, err
+			}
+			comment = c
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO events (issue_id, event_type, actor, old_value, new_value, comment, created_at, session_id)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`, e.IssueID, e.EventType, e.Actor, oldValue, newValue, comment, e.CreatedAt, nullSession(e.SessionID)); err != nil {
+			return 0, fmt.Errorf("failed to insert event: %w", err)
+		}
+		added++
+	}
+	return added, tx.Commit()
+}
+
+func nullStringPtr(ns sql.NullString) *string {
+	if !ns.Valid {
+		return nil
+	}
+	v := ns.String
+	return &v
+}
@@ -0,0 +1,258 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// RenamePrefix renames every issue whose ID starts with oldPrefix to the same
+// ID under newPrefix, in one transaction. References to the renamed issues in
+// issue text and comments are rewritten, the ID counter and issue_prefix
+// move with them, and each old ID is kept as a redirect so links to it still
+// resolve (see GetIssueRedirect). It returns the old ID of each renamed issue
+// mapped to its new one.
+func (s *SQLiteStorage) RenamePrefix(ctx context.Context, oldPrefix, newPrefix, actor string) (map[string]string, error) {
+	if oldPrefix == newPrefix {
+		return nil, fmt.Errorf("new prefix is the same as the current prefix: %s", oldPrefix)
+	}
+
+	// Renamed rows point at each other mid-rename, so foreign keys are off
+	// for the transaction, as in UpdateIssueID
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+	if _, err := conn.ExecContext(ctx, `PRAGMA foreign_keys = OFF`); err != nil {
+		return nil, fmt.Errorf("failed to disable foreign keys: %w", err)
+	}
+	defer func() { _, _ = conn.ExecContext(context.Background(), `PRAGMA foreign_keys = ON`) }()
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	renames, err := plannedRenames(ctx, tx, oldPrefix, newPrefix)
+	if err != nil {
+		return nil, err
+	}
+	rewrite := referenceRewriter(oldPrefix, renames)
+
+	for oldID, newID := range renames {
+		if err := s.renameIssueRow(ctx, tx, oldID, newID, rewrite); err != nil {
+			return nil, err
+		}
+		if err := s.moveIssueID(ctx, tx, oldID, newID, actor); err != nil {
+			return nil, fmt.Errorf("failed to rename %s: %w", oldID, err)
+		}
+		if err := addRedirect(ctx, tx, oldID, newID); err != nil {
+			return nil, err
+		}
+	}
+	// An ID renamed back to a prefix it once had is live again
+	if _, err := tx.ExecContext(ctx, `DELETE FROM issue_redirects WHERE old_id IN (SELECT id FROM issues)`); err != nil {
+		return nil, fmt.Errorf("failed to clear stale redirects: %w", err)
+	}
+
+	if err := s.rewriteCommentReferences(ctx, tx, oldPrefix, rewrite); err != nil {
+		return nil, err
+	}
+	if err := renameCounter(ctx, tx, oldPrefix, newPrefix); err != nil {
+		return nil, err
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO config (key, value) VALUES ('issue_prefix', ?)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value
+	`, newPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update issue_prefix: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit prefix rename: %w", err)
+	}
+	return renames, nil
+}
+
+// GetIssueRedirect returns the ID an issue was renamed to, or "" if id was
+// never renamed
+func (s *SQLiteStorage) GetIssueRedirect(ctx context.Context, id string) (string, error) {
+	var newID string
+	err := s.reads.QueryRowContext(ctx, `SELECT new_id FROM issue_redirects WHERE old_id = ?`, id).Scan(&newID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to look up redirect for %s: %w", id, err)
+	}
+	return newID, nil
+}
+
+// plannedRenames maps each ID under oldPrefix to its ID under newPrefix,
+// refusing if any new ID is already taken
+func plannedRenames(ctx context.Context, tx *sql.Tx, oldPrefix, newPrefix string) (map[string]string, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT id FROM issues WHERE substr(id, 1, ?) = ?`, len(oldPrefix)+1, oldPrefix+"-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issues: %w", err)
+	}
+	defer rows.Close()
+
+	renames := make(map[string]string)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		renames[id] = newPrefix + "-" + strings.TrimPrefix(id, oldPrefix+"-")
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, newID := range renames {
+		var taken bool
+		if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) > 0 FROM issues WHERE id = ?`, newID).Scan(&taken); err != nil {
+			return nil, err
+		}
+		if taken {
+			return nil, fmt.Errorf("cannot rename to %s: an issue with that ID already exists", newID)
+		}
+	}
+	return renames, nil
+}
+
+// referenceRewriter returns a function that replaces mentions of renamed
+// IDs in text. Only IDs being renamed are touched, so words that merely
+// start with the old prefix (like "bd-style") are left alone, as are
+// references into other workspaces (like "platform/bd-12").
+func referenceRewriter(oldPrefix string, renames map[string]string) func(string) string {
+	pattern := regexp.MustCompile(`\b` + regexp.QuoteMeta(oldPrefix) + `-[0-9A-Za-z]+(\.[0-9]+)*\b`)
+	rename := func(match string) string {
+		// "bd-12.3" may be a child that doesn't exist; fall back to its parent
+		for id, rest := match, ""; ; {
+			if newID, ok := renames[id]; ok {
+				return newID + rest
+			}
+			dot := strings.LastIndex(id, ".")
+			if dot < 0 {
+				return match
+			}
+			id, rest = id[:dot], id[dot:]+rest
+		}
+	}
+	return func(text string) string {
+		var b strings.Builder
+		last := 0
+		for _, loc := range pattern.FindAllStringIndex(text, -1) {
+			if loc[0] > 0 && text[loc[0]-1] == '/' {
+				continue
+			}
+			b.WriteString(text[last:loc[0]])
+			b.WriteString(rename(text[loc[0]:loc[1]]))
+			last = loc[1]
+		}
+		b.WriteString(text[last:])
+		return b.String()
+	}
+}
+
+// renameIssueRow changes an issue's ID and rewrites references in its text
+func (s *SQLiteStorage) renameIssueRow(ctx context.Context, tx *sql.Tx, oldID, newID string, rewrite func(string) string) error {
+	var title, description, design, criteria, notes string
+	err := tx.QueryRowContext(ctx, `
+		SELECT title, description, design, acceptance_criteria, notes FROM issues WHERE id = ?
+	`, oldID).Scan(&title, &description, &design, &criteria, &notes)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", oldID, err)
+	}
+	if description, err = s.rewriteEncrypted(description, rewrite); err != nil {
+		return err
+	}
+	if notes, err = s.rewriteEncrypted(notes, rewrite); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE issues
+		SET id = ?, title = ?, description = ?, design = ?, acceptance_criteria = ?, notes = ?, updated_at = ?
+		WHERE id = ?
+	`, newID, rewrite(title), description, rewrite(design), rewrite(criteria), notes, time.Now(), oldID)
+	if err != nil {
+		return fmt.Errorf("failed to rename %s: %w", oldID, err)
+	}
+	return nil
+}
+
+// rewriteCommentReferences rewrites references in comments that mention
+// the old prefix
+func (s *SQLiteStorage) rewriteCommentReferences(ctx context.Context, tx *sql.Tx, oldPrefix string, rewrite func(string) string) error {
+	rows, err := tx.QueryContext(ctx, `SELECT id, text FROM comments`)
+	if err != nil {
+		return fmt.Errorf("failed to list comments: %w", err)
+	}
+	texts := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var text string
+		if err := rows.Scan(&id, &text); err != nil {
+			rows.Close()
+			return err
+		}
+		texts[id] = text
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for id, stored := range texts {
+		// Encrypted comments can't be filtered in SQL, so check here
+		if s.fieldCipher == nil && !strings.Contains(stored, oldPrefix+"-") {
+			continue
+		}
+		text, err := s.rewriteEncrypted(stored, rewrite)
+		if err != nil {
+			return err
+		}
+		if text == stored {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE comments SET text = ? WHERE id = ?`, text, id); err != nil {
+			return fmt.Errorf("failed to rewrite comment %d: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// rewriteEncrypted applies rewrite to the plaintext of a stored field,
+// returning the stored value unchanged when nothing was rewritten
+func (s *SQLiteStorage) rewriteEncrypted(stored string, rewrite func(string) string) (string, error) {
+	plain := s.decryptField(stored)
+	rewritten := rewrite(plain)
+	if rewritten == plain {
+		return stored, nil
+	}
+	return s.encryptField(rewritten)
+}
+
+// addRedirect records that oldID is now newID, and points redirects that
+// led to oldID at newID so lookups never take more than one hop
+func addRedirect(ctx context.Context, tx *sql.Tx, oldID, newID string) error {
+	if _, err := tx.ExecContext(ctx, `UPDATE issue_redirects SET new_id = ? WHERE new_id = ?`, newID, oldID); err != nil {
+		return fmt.Errorf("failed to update redirects to %s: %w", oldID, err)
+	}
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO issue_redirects (old_id, new_id, created_at) VALUES (?, ?, ?)
+		ON CONFLICT (old_id) DO UPDATE SET new_id = excluded.new_id, created_at = excluded.created_at
+	`, oldID, newID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to record redirect for %s: %w", oldID, err)
+	}
+	return nil
+}
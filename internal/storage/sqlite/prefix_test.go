@@ -0,0 +1,98 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestRenamePrefix(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	create := func(title, description string) *types.Issue {
+		issue := &types.Issue{Title: title, Description: description, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatal(err)
+		}
+		return issue
+	}
+	parent := create("Parent", "")
+	child := create("Child", "Blocks "+parent.ID+", unlike bd-style, bd-999, or platform/"+parent.ID+".")
+	if err := store.AddDependency(ctx, &types.Dependency{IssueID: child.ID, DependsOnID: parent.ID, Type: types.DepBlocks}, "test"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.AddIssueComment(ctx, parent.ID, "test", "See "+child.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	renames, err := store.RenamePrefix(ctx, "bd", "kw", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newParent, newChild := renames[parent.ID], renames[child.ID]
+	if len(renames) != 2 || newParent != "kw-"+parent.ID[3:] || newChild != "kw-"+child.ID[3:] {
+		t.Fatalf("Unexpected renames: %v", renames)
+	}
+
+	got, err := store.GetIssue(ctx, newChild)
+	if err != nil || got == nil {
+		t.Fatalf("GetIssue(%s): %v %v", newChild, got, err)
+	}
+	if want := "Blocks " + newParent + ", unlike bd-style, bd-999, or platform/" + parent.ID + "."; got.Description != want {
+		t.Errorf("Description = %q, want %q", got.Description, want)
+	}
+	if deps, _ := store.GetDependencies(ctx, newChild); len(deps) != 1 || deps[0].ID != newParent {
+		t.Errorf("Expected the dependency to follow the rename, got %v", deps)
+	}
+	if comments, _ := store.GetIssueComments(ctx, newParent); len(comments) != 1 || comments[0].Text != "See "+newChild {
+		t.Errorf("Expected the comment rewritten, got %v", comments)
+	}
+	if prefix, _ := store.GetConfig(ctx, "issue_prefix"); prefix != "kw" {
+		t.Errorf("issue_prefix = %q, want kw", prefix)
+	}
+	next := create("Next", "")
+	if next.ID != "kw-3" {
+		t.Errorf("Expected the counter to carry over, got %s", next.ID)
+	}
+
+	// Old IDs redirect, and a second rename doesn't chain them
+	if _, err := store.RenamePrefix(ctx, "kw", "mt", "test"); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{parent.ID, newParent} {
+		if to, err := store.GetIssueRedirect(ctx, id); err != nil || to != "mt-"+parent.ID[3:] {
+			t.Errorf("GetIssueRedirect(%s) = %q, %v", id, to, err)
+		}
+	}
+
+	// Renaming back makes the original IDs live again
+	if _, err := store.RenamePrefix(ctx, "mt", "bd", "test"); err != nil {
+		t.Fatal(err)
+	}
+	if to, _ := store.GetIssueRedirect(ctx, parent.ID); to != "" {
+		t.Errorf("Expected no redirect for a live ID, got %q", to)
+	}
+
+	// A rename that would collide changes nothing
+	create("Stray", "")
+	if err := store.UpdateIssueID(ctx, "bd-4", "kw-1", &types.Issue{Title: "Stray"}, "test"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.RenamePrefix(ctx, "bd", "kw", "test"); err == nil {
+		t.Error("Expected a colliding rename to fail")
+	}
+	if prefix, _ := store.GetConfig(ctx, "issue_prefix"); prefix != "bd" {
+		t.Errorf("Expected a failed rename to leave issue_prefix alone, got %q", prefix)
+	}
+	if got, _ := store.GetIssue(ctx, parent.ID); got == nil {
+		t.Error("Expected a failed rename to leave the issues alone")
+	}
+
+	// Foreign keys are back on after a rename
+	if _, err := store.db.ExecContext(ctx, `INSERT INTO labels (issue_id, label) VALUES ('missing', 'x')`); err == nil {
+		t.Error("Expected foreign keys to be enforced after the rename")
+	}
+}
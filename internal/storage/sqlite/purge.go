@@ -0,0 +1,566 @@
+package sqlite
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Actor purge modes
+const (
+	PurgeModeAnonymize = "anonymize" // replace the actor with a stable pseudonym everywhere
+	PurgeModeRemove    = "remove"    // also delete comments the actor authored
+)
+
+// actorJSONKeys are the keys inside stored JSON documents that hold actor names
+var actorJSONKeys = map[string]bool{
+	"assignee":   true,
+	"actor":      true,
+	"author":     true,
+	"created_by": true,
+	"mentioned":  true,
+}
+
+// PurgeActorOptions controls an actor data purge
+type PurgeActorOptions struct {
+	Mode        string // PurgeModeAnonymize (default) or PurgeModeRemove
+	Replacement string // pseudonym to substitute (default: deleted-user-<hash>)
+	PurgedBy    string // operator performing the purge
+	DryRun      bool   // count what would change without modifying anything
+}
+
+// PurgeReport records what an actor purge changed. The purged actor is only
+// identified by a hash of their name so the report itself holds no personal data.
+type PurgeReport struct {
+	ID                     int64     `json:"id,omitempty"`
+	SubjectHash            string    `json:"subject_hash"`
+	Replacement            string    `json:"replacement"`
+	Mode                   string    `json:"mode"`
+	PurgedBy               string    `json:"purged_by"`
+	PurgedAt               time.Time `json:"purged_at"`
+	DryRun                 bool      `json:"dry_run,omitempty"`
+	Assignments            int       `json:"assignments"`
+	Dependencies           int       `json:"dependencies"`
+	Events                 int       `json:"events"`
+	EventPayloads          int       `json:"event_payloads"`
+	Comments               int       `json:"comments"`
+	CommentsDeleted        int       `json:"comments_deleted"`
+	CommentEventsDeleted   int       `json:"comment_events_deleted"`
+	Snapshots              int       `json:"snapshots"`
+	Redactions             int       `json:"redactions"`
+	History                int       `json:"history"`
+	Commits                int       `json:"commits"`
+	Deletions              int       `json:"deletions"`
+	Archivals              int       `json:"archivals"`
+	WorkLogs               int       `json:"work_logs"`
+	Leases                 int       `json:"leases"`
+	Records                int       `json:"records"`
+	Oplog                  int       `json:"oplog"`
+	Registry               int       `json:"registry"`
+	IssuesAffected         []string  `json:"issues_affected"`
+	AuditChainRebuilt      bool      `json:"audit_chain_rebuilt"`
+	AuditSignaturesRemoved int       `json:"audit_signatures_removed"`
+	AuditHeadBefore        string    `json:"audit_head_before,omitempty"`
+	AuditHeadAfter         string    `json:"audit_head_after,omitempty"`
+	Method                 string    `json:"method,omitempty"`
+	Signer                 string    `json:"signer,omitempty"`
+	Signature              string    `json:"signature,omitempty"`
+}
+
+// SigningPayload returns the canonical JSON of the report without signature fields
+func (r *PurgeReport) SigningPayload() string {
+	c := *r
+	c.ID, c.Method, c.Signer, c.Signature = 0, "", "", ""
+	data, _ := json.Marshal(&c)
+	return string(data)
+}
+
+// ActorSubjectHash returns the identifier used for an actor in purge reports
+func ActorSubjectHash(actor string) string {
+	sum := sha256.Sum256([]byte(actor))
+	return hex.EncodeToString(sum[:])
+}
+
+// DefaultPurgeReplacement returns the stable pseudonym used for a purged actor
+func DefaultPurgeReplacement(actor string) string {
+	return "deleted-user-" + ActorSubjectHash(actor)[:8]
+}
+
+// PurgeActor removes an actor's personal data from the database: assignee fields,
+// dependency and comment authorship, event actors, and actor names embedded in
+// event payloads and compaction snapshots. Their watches, digest
+// subscriptions, and actor registration with its email and aliases are
+// deleted. Issue content is preserved. If the
+// audit chain is in use it is rebuilt, and the before and after heads are
+// recorded in the report.
+func (s *SQLiteStorage) PurgeActor(ctx context.Context, actor string, opts PurgeActorOptions) (*PurgeReport, error) {
+	actor = strings.TrimSpace(actor)
+	if actor == "" {
+		return nil, fmt.Errorf("actor name is required")
+	}
+	if opts.Mode == "" {
+		opts.Mode = PurgeModeAnonymize
+	}
+	if opts.Mode != PurgeModeAnonymize && opts.Mode != PurgeModeRemove {
+		return nil, fmt.Errorf("invalid purge mode %q (must be %s or %s)", opts.Mode, PurgeModeAnonymize, PurgeModeRemove)
+	}
+	if opts.Replacement == "" {
+		opts.Replacement = DefaultPurgeReplacement(actor)
+	}
+	if opts.Replacement == actor {
+		return nil, fmt.Errorf("replacement must differ from the purged actor")
+	}
+
+	report := &PurgeReport{
+		SubjectHash: ActorSubjectHash(actor),
+		Replacement: opts.Replacement,
+		Mode:        opts.Mode,
+		PurgedBy:    opts.PurgedBy,
+		PurgedAt:    time.Now().UTC(),
+		DryRun:      opts.DryRun,
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := rewriteActor(ctx, tx, actor, opts, report); err != nil {
+		return nil, err
+	}
+
+	// A pseudonym can't be notified, so watches go in either mode
+	if _, err := tx.ExecContext(ctx, `DELETE FROM issue_watchers WHERE user = ?`, actor); err != nil {
+		return nil, fmt.Errorf("failed to delete watches: %w", err)
+	}
+	// Digest subscriptions hold the actor's email address
+	if _, err := tx.ExecContext(ctx, `DELETE FROM digest_subscriptions WHERE actor = ?`, actor); err != nil {
+		return nil, fmt.Errorf("failed to delete digest subscriptions: %w", err)
+	}
+	// So does the registration, with its email and aliases
+	for _, query := range []string{
+		`DELETE FROM actor_aliases WHERE actor = ?1 OR alias = ?1`,
+		`DELETE FROM actors WHERE name = ?1`,
+	} {
+		res, err := tx.ExecContext(ctx, query, actor)
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete actor registration: %w", err)
+		}
+		n, _ := res.RowsAffected()
+		report.Registry += int(n)
+	}
+
+	if opts.DryRun {
+		return report, nil
+	}
+
+	// Mark affected issues dirty so the JSONL export drops the personal data too
+	now := time.Now()
+	for _, id := range report.IssuesAffected {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO dirty_issues (issue_id, marked_at)
+			SELECT id, ? FROM issues WHERE id = ?
+			ON CONFLICT (issue_id) DO UPDATE SET marked_at = excluded.marked_at
+		`, now, id); err != nil {
+			return nil, fmt.Errorf("failed to mark issue dirty: %w", err)
+		}
+	}
+
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO purge_reports (subject_hash, report) VALUES (?, ?)
+	`, report.SubjectHash, report.SigningPayload())
+	if err != nil {
+		return nil, fmt.Errorf("failed to store purge report: %w", err)
+	}
+	if report.ID, err = res.LastInsertId(); err != nil {
+		return nil, fmt.Errorf("failed to get purge report ID: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit purge: %w", err)
+	}
+	return report, nil
+}
+
+// rewriteActor replaces actor with opts.Replacement throughout the history
+// in tx, counting what changed in report, and rebuilds the audit chain over
+// the result. In remove mode the actor's comments are deleted and their
+// assignments cleared instead.
+func rewriteActor(ctx context.Context, tx *sql.Tx, actor string, opts PurgeActorOptions, report *PurgeReport) error {
+	affected := make(map[string]bool)
+	collect := func(query string, args ...interface{}) error {
+		rows, err := tx.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = rows.Close() }()
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				return err
+			}
+			affected[id] = true
+		}
+		return rows.Err()
+	}
+	if err := collect(`
+		SELECT id FROM issues WHERE assignee = ?
+		UNION SELECT issue_id FROM comments WHERE author = ?
+		UNION SELECT issue_id FROM dependencies WHERE created_by = ?
+	`, actor, actor, actor); err != nil {
+		return fmt.Errorf("failed to find affected issues: %w", err)
+	}
+
+	exec := func(counter *int, query string, args ...interface{}) error {
+		res, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		n, _ := res.RowsAffected()
+		*counter += int(n)
+		return nil
+	}
+
+	// Assignments are cleared in remove mode, pseudonymized otherwise
+	assignee := opts.Replacement
+	if opts.Mode == PurgeModeRemove {
+		assignee = ""
+	}
+	if err := exec(&report.Assignments, `UPDATE issues SET assignee = ? WHERE assignee = ?`, assignee, actor); err != nil {
+		return fmt.Errorf("failed to purge assignments: %w", err)
+	}
+	if err := exec(&report.Dependencies, `UPDATE dependencies SET created_by = ? WHERE created_by = ?`, opts.Replacement, actor); err != nil {
+		return fmt.Errorf("failed to purge dependency authorship: %w", err)
+	}
+	// Claims follow their assignments: kept under the pseudonym, or dropped
+	// in remove mode
+	if err := collect(`SELECT issue_id FROM issue_leases WHERE holder = ?`, actor); err != nil {
+		return fmt.Errorf("failed to find affected leases: %w", err)
+	}
+	leases := `UPDATE issue_leases SET holder = ?1 WHERE holder = ?2`
+	if opts.Mode == PurgeModeRemove {
+		leases = `DELETE FROM issue_leases WHERE holder = ?2`
+	}
+	if err := exec(&report.Leases, leases, opts.Replacement, actor); err != nil {
+		return fmt.Errorf("failed to purge leases: %w", err)
+	}
+	if err := collect(`SELECT id FROM issues WHERE deleted_by = ?`, actor); err != nil {
+		return fmt.Errorf("failed to find affected deletions: %w", err)
+	}
+	if err := exec(&report.Deletions, `UPDATE issues SET deleted_by = ? WHERE deleted_by = ?`, opts.Replacement, actor); err != nil {
+		return fmt.Errorf("failed to purge deleted-by actors: %w", err)
+	}
+	if err := collect(`SELECT issue_id FROM archived_issues WHERE archived_by = ?`, actor); err != nil {
+		return fmt.Errorf("failed to find affected archivals: %w", err)
+	}
+	if err := exec(&report.Archivals, `UPDATE archived_issues SET archived_by = ? WHERE archived_by = ?`, opts.Replacement, actor); err != nil {
+		return fmt.Errorf("failed to purge archive actors: %w", err)
+	}
+	if err := collect(`SELECT DISTINCT issue_id FROM issue_commits WHERE author = ?`, actor); err != nil {
+		return fmt.Errorf("failed to find affected commit links: %w", err)
+	}
+	if err := exec(&report.Commits, `UPDATE issue_commits SET author = ? WHERE author = ?`, opts.Replacement, actor); err != nil {
+		return fmt.Errorf("failed to purge commit authorship: %w", err)
+	}
+	if err := collect(`SELECT issue_id FROM remote_dependencies WHERE created_by = ?`, actor); err != nil {
+		return fmt.Errorf("failed to find affected remote dependencies: %w", err)
+	}
+	if err := exec(&report.Dependencies, `UPDATE remote_dependencies SET created_by = ? WHERE created_by = ?`, opts.Replacement, actor); err != nil {
+		return fmt.Errorf("failed to purge remote dependency authorship: %w", err)
+	}
+	// Labels, milestones, webhooks, API keys, and triage rules the actor set up
+	// keep working under the pseudonym
+	for _, table := range []string{"label_definitions", "milestones", "webhooks", "api_keys", "triage_rules"} {
+		// #nosec G201 - table names are fixed
+		if err := exec(&report.Records, fmt.Sprintf(`UPDATE %s SET created_by = ? WHERE created_by = ?`, table), opts.Replacement, actor); err != nil {
+			return fmt.Errorf("failed to purge %s authorship: %w", table, err)
+		}
+	}
+	if err := exec(&report.Records, `UPDATE triage_rules SET set_assignee = ? WHERE set_assignee = ?`, assignee, actor); err != nil {
+		return fmt.Errorf("failed to purge triage rule assignees: %w", err)
+	}
+
+	if opts.Mode == PurgeModeRemove {
+		if err := exec(&report.CommentsDeleted, `DELETE FROM comments WHERE author = ?`, actor); err != nil {
+			return fmt.Errorf("failed to delete comments: %w", err)
+		}
+		var mentioned, reactions, mentions int
+		if err := exec(&mentioned, `DELETE FROM events WHERE actor = ? AND event_type = 'mentioned'`, actor); err != nil {
+			return fmt.Errorf("failed to delete mention events: %w", err)
+		}
+		if err := exec(&report.CommentEventsDeleted, `DELETE FROM events WHERE actor = ? AND event_type = 'commented'`, actor); err != nil {
+			return fmt.Errorf("failed to delete comment events: %w", err)
+		}
+		if err := exec(&reactions, `DELETE FROM comment_reactions WHERE actor = ?`, actor); err != nil {
+			return fmt.Errorf("failed to delete comment reactions: %w", err)
+		}
+		if err := exec(&mentions, `DELETE FROM comment_mentions WHERE user = ?`, actor); err != nil {
+			return fmt.Errorf("failed to delete comment mentions: %w", err)
+		}
+	} else {
+		if err := exec(&report.Comments, `UPDATE comments SET author = ? WHERE author = ?`, opts.Replacement, actor); err != nil {
+			return fmt.Errorf("failed to purge comment authorship: %w", err)
+		}
+		// A reaction the replacement already gave is kept once
+		var reactions int
+		if err := exec(&reactions, `UPDATE OR IGNORE comment_reactions SET actor = ? WHERE actor = ?`, opts.Replacement, actor); err != nil {
+			return fmt.Errorf("failed to purge comment reactions: %w", err)
+		}
+		if err := exec(&reactions, `DELETE FROM comment_reactions WHERE actor = ?`, actor); err != nil {
+			return fmt.Errorf("failed to purge comment reactions: %w", err)
+		}
+		var mentions int
+		if err := exec(&mentions, `UPDATE OR IGNORE comment_mentions SET user = ? WHERE user = ?`, opts.Replacement, actor); err != nil {
+			return fmt.Errorf("failed to purge comment mentions: %w", err)
+		}
+		if err := exec(&mentions, `DELETE FROM comment_mentions WHERE user = ?`, actor); err != nil {
+			return fmt.Errorf("failed to purge comment mentions: %w", err)
+		}
+	}
+	// Logged time keeps counting toward its issue, under the pseudonym
+	if err := collect(`SELECT DISTINCT issue_id FROM work_logs WHERE actor = ?`, actor); err != nil {
+		return fmt.Errorf("failed to find affected work logs: %w", err)
+	}
+	if err := exec(&report.WorkLogs, `UPDATE work_logs SET actor = ? WHERE actor = ?`, opts.Replacement, actor); err != nil {
+		return fmt.Errorf("failed to purge work log actors: %w", err)
+	}
+	var edits int
+	if err := exec(&edits, `UPDATE comment_edits SET edited_by = ? WHERE edited_by = ?`, opts.Replacement, actor); err != nil {
+		return fmt.Errorf("failed to purge comment edits: %w", err)
+	}
+	var sessions int
+	if err := exec(&sessions, `UPDATE agent_sessions SET actor = ? WHERE actor = ?`, opts.Replacement, actor); err != nil {
+		return fmt.Errorf("failed to purge agent sessions: %w", err)
+	}
+
+	// Event actors: collect affected issues before rewriting
+	if err := collect(`SELECT DISTINCT issue_id FROM events WHERE actor = ?`, actor); err != nil {
+		return fmt.Errorf("failed to find affected events: %w", err)
+	}
+	if err := exec(&report.Events, `UPDATE events SET actor = ? WHERE actor = ?`, opts.Replacement, actor); err != nil {
+		return fmt.Errorf("failed to purge event actors: %w", err)
+	}
+
+	// Actor names embedded in JSON documents
+	n, err := rewriteActorJSONColumn(ctx, tx, "events", "id", []string{"old_value", "new_value"}, actor, assignee, opts.Replacement, affected)
+	if err != nil {
+		return err
+	}
+	report.EventPayloads = n
+	for _, table := range []struct {
+		name    string
+		columns []string
+	}{
+		{"issue_snapshots", []string{"original_content", "archived_events"}},
+		{"compaction_snapshots", []string{"snapshot_json"}},
+	} {
+		n, err := rewriteActorJSONColumn(ctx, tx, table.name, "id", table.columns, actor, assignee, opts.Replacement, affected)
+		if err != nil {
+			return err
+		}
+		report.Snapshots += n
+	}
+
+	if err := exec(&report.Redactions, `UPDATE secret_redactions SET actor = ? WHERE actor = ?`, opts.Replacement, actor); err != nil {
+		return fmt.Errorf("failed to purge redaction actors: %w", err)
+	}
+
+	// Revision history: who made each revision, and assignees in its changes
+	if err := collect(`SELECT DISTINCT issue_id FROM issue_history WHERE actor = ?`, actor); err != nil {
+		return fmt.Errorf("failed to find affected revisions: %w", err)
+	}
+	if err := exec(&report.History, `UPDATE issue_history SET actor = ? WHERE actor = ?`, opts.Replacement, actor); err != nil {
+		return fmt.Errorf("failed to purge revision actors: %w", err)
+	}
+	n, err = rewriteActorJSONColumn(ctx, tx, "issue_history", "id", []string{"changes"}, actor, assignee, opts.Replacement, affected)
+	if err != nil {
+		return err
+	}
+	report.History += n
+
+	// Replication ops: who made each, and names in the fields they carry, so
+	// bd sync remote doesn't hand the name on to peers
+	if err := collect(`SELECT DISTINCT issue_id FROM oplog WHERE actor = ?`, actor); err != nil {
+		return fmt.Errorf("failed to find affected ops: %w", err)
+	}
+	if err := exec(&report.Oplog, `UPDATE oplog SET actor = ? WHERE actor = ?`, opts.Replacement, actor); err != nil {
+		return fmt.Errorf("failed to purge op actors: %w", err)
+	}
+	n, err = rewriteActorJSONColumn(ctx, tx, "oplog", "rowid", []string{"fields"}, actor, assignee, opts.Replacement, affected)
+	if err != nil {
+		return err
+	}
+	report.Oplog += n
+
+	for id := range affected {
+		report.IssuesAffected = append(report.IssuesAffected, id)
+	}
+	sort.Strings(report.IssuesAffected)
+
+	// Rebuild the audit chain over the rewritten history
+	if report.Events > 0 || report.EventPayloads > 0 || report.CommentEventsDeleted > 0 {
+		_, _, before, err := getAuditHead(ctx, tx)
+		if err != nil {
+			return err
+		}
+		rebuilt, removed, err := rebuildAuditChain(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if rebuilt {
+			_, _, after, err := getAuditHead(ctx, tx)
+			if err != nil {
+				return err
+			}
+			report.AuditChainRebuilt = true
+			report.AuditSignaturesRemoved = removed
+			report.AuditHeadBefore = before
+			report.AuditHeadAfter = after
+		}
+	}
+
+	return nil
+}
+
+// SignPurgeReport attaches a signature over the report's signing payload
+func (s *SQLiteStorage) SignPurgeReport(ctx context.Context, report *PurgeReport) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE purge_reports SET method = ?, signer = ?, signature = ? WHERE id = ?
+	`, report.Method, report.Signer, report.Signature, report.ID)
+	if err != nil {
+		return fmt.Errorf("failed to sign purge report: %w", err)
+	}
+	return nil
+}
+
+// GetPurgeReports returns stored purge reports, newest first
+func (s *SQLiteStorage) GetPurgeReports(ctx context.Context) ([]*PurgeReport, error) {
+	rows, err := s.reads.QueryContext(ctx, `
+		SELECT id, report, method, signer, signature FROM purge_reports ORDER BY id DESC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query purge reports: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var reports []*PurgeReport
+	for rows.Next() {
+		var id int64
+		var payload, method, signer, signature string
+		if err := rows.Scan(&id, &payload, &method, &signer, &signature); err != nil {
+			return nil, fmt.Errorf("failed to scan purge report: %w", err)
+		}
+		var r PurgeReport
+		if err := json.Unmarshal([]byte(payload), &r); err != nil {
+			return nil, fmt.Errorf("malformed purge report %d: %w", id, err)
+		}
+		r.ID, r.Method, r.Signer, r.Signature = id, method, signer, signature
+		reports = append(reports, &r)
+	}
+	return reports, rows.Err()
+}
+
+// rewriteActorJSONColumn replaces the actor in JSON documents stored in the given
+// columns. Assignee values become assignee; all other actor keys become replacement.
+func rewriteActorJSONColumn(ctx context.Context, tx *sql.Tx, table, idColumn string, columns []string, actor, assignee, replacement string, affected map[string]bool) (int, error) {
+	changed := 0
+	for _, column := range columns {
+		// #nosec G201 - table and column names are fixed by the caller
+		rows, err := tx.QueryContext(ctx, fmt.Sprintf(`
+			SELECT %s, issue_id, CAST(%s AS TEXT) FROM %s WHERE instr(%s, ?) > 0
+		`, idColumn, column, table, column), actor)
+		if err != nil {
+			return changed, fmt.Errorf("failed to scan %s.%s: %w", table, column, err)
+		}
+		type update struct {
+			id    int64
+			value string
+		}
+		var updates []update
+		for rows.Next() {
+			var id int64
+			var issueID, value string
+			if err := rows.Scan(&id, &issueID, &value); err != nil {
+				_ = rows.Close()
+				return changed, fmt.Errorf("failed to read %s.%s: %w", table, column, err)
+			}
+			if rewritten, ok := rewriteActorJSON(value, actor, assignee, replacement); ok {
+				updates = append(updates, update{id, rewritten})
+				affected[issueID] = true
+			}
+		}
+		_ = rows.Close()
+
+		for _, u := range updates {
+			// #nosec G201 - table and column names are fixed by the caller
+			if _, err := tx.ExecContext(ctx, fmt.Sprintf(`UPDATE %s SET %s = ? WHERE %s = ?`, table, column, idColumn), u.value, u.id); err != nil {
+				return changed, fmt.Errorf("failed to update %s.%s: %w", table, column, err)
+			}
+			changed++
+		}
+	}
+	return changed, nil
+}
+
+// rewriteActorJSON replaces actor values in a JSON document, including the old
+// and new values of changes to actor fields. It returns false if the document
+// is not JSON or contains no actor values.
+func rewriteActorJSON(doc, actor, assignee, replacement string) (string, bool) {
+	var v interface{}
+	if err := json.Unmarshal([]byte(doc), &v); err != nil {
+		return doc, false
+	}
+	changed := false
+	var walk func(interface{}) interface{}
+	walk = func(node interface{}) interface{} {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			// A field change, {"field": "assignee", "old": ..., "new": ...}
+			if field, ok := n["field"].(string); ok && actorJSONKeys[field] {
+				for _, k := range []string{"old", "new"} {
+					if str, ok := n[k].(string); ok && str == actor {
+						if field == "assignee" {
+							n[k] = assignee
+						} else {
+							n[k] = replacement
+						}
+						changed = true
+					}
+				}
+			}
+			for k, child := range n {
+				if str, ok := child.(string); ok && actorJSONKeys[k] && str == actor {
+					if k == "assignee" {
+						n[k] = assignee
+					} else {
+						n[k] = replacement
+					}
+					changed = true
+					continue
+				}
+				n[k] = walk(child)
+			}
+		case []interface{}:
+			for i, child := range n {
+				n[i] = walk(child)
+			}
+		}
+		return node
+	}
+	v = walk(v)
+	if !changed {
+		return doc, false
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return doc, false
+	}
+	return string(data), true
+}
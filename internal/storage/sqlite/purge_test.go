@@ -0,0 +1,413 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// seedPurgeData creates issues touched by alice in every place actor data is kept
+func seedPurgeData(t *testing.T, store *SQLiteStorage) (*types.Issue, *types.Issue) {
+	t.Helper()
+	ctx := context.Background()
+
+	a := createAuditTestIssue(t, store, "Assigned to alice")
+	b := createAuditTestIssue(t, store, "Commented by alice")
+	if err := store.UpdateIssue(ctx, a.ID, map[string]interface{}{"assignee": "alice"}, "bob"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	if err := store.AddComment(ctx, b.ID, "alice", "I'll take a look"); err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+	if _, err := store.AddIssueComment(ctx, b.ID, "alice", "Fixed in main"); err != nil {
+		t.Fatalf("AddIssueComment failed: %v", err)
+	}
+	if err := store.AddDependency(ctx, &types.Dependency{IssueID: b.ID, DependsOnID: a.ID, Type: types.DepBlocks}, "alice"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+	if err := store.UpdateIssue(ctx, b.ID, map[string]interface{}{"priority": 1}, "alice"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	if _, err := store.AddCommitLink(ctx, &CommitLink{IssueID: b.ID, SHA: "abc123", Message: "Fix " + b.ID, Author: "alice"}); err != nil {
+		t.Fatalf("AddCommitLink failed: %v", err)
+	}
+	if _, err := store.ClaimIssue(ctx, a.ID, "alice", time.Hour); err != nil {
+		t.Fatalf("ClaimIssue failed: %v", err)
+	}
+	if err := store.AddWorkLog(ctx, &WorkLog{IssueID: a.ID, Actor: "alice", Minutes: 45, Note: "Triage"}); err != nil {
+		t.Fatalf("AddWorkLog failed: %v", err)
+	}
+	c := createAuditTestIssue(t, store, "Trashed by alice")
+	if err := store.SoftDeleteIssue(ctx, c.ID, "alice"); err != nil {
+		t.Fatalf("SoftDeleteIssue failed: %v", err)
+	}
+	d := createAuditTestIssue(t, store, "Old and closed")
+	if err := store.CloseIssue(ctx, d.ID, "Done", "bob"); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+	if _, err := store.ArchiveClosedIssues(ctx, time.Now().Add(time.Hour), "alice", false); err != nil {
+		t.Fatalf("ArchiveClosedIssues failed: %v", err)
+	}
+	if err := store.SetDigestSubscription(ctx, &DigestSubscription{Actor: "alice", Email: "alice@example.com", Frequency: "daily"}); err != nil {
+		t.Fatalf("SetDigestSubscription failed: %v", err)
+	}
+	if err := store.AddDependency(ctx, &types.Dependency{IssueID: a.ID, DependsOnID: "platform/pf-12", Type: types.DepBlocks}, "alice"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+	if err := store.CreateLabel(ctx, &Label{Name: "oncall", CreatedBy: "alice"}); err != nil {
+		t.Fatalf("CreateLabel failed: %v", err)
+	}
+	if err := store.CreateMilestone(ctx, &Milestone{Name: "sprint-1", StartDate: time.Now(), EndDate: time.Now(), CreatedBy: "alice"}); err != nil {
+		t.Fatalf("CreateMilestone failed: %v", err)
+	}
+	if err := store.CreateWebhook(ctx, &Webhook{URL: "https://example.com/hook", CreatedBy: "alice"}); err != nil {
+		t.Fatalf("CreateWebhook failed: %v", err)
+	}
+	if err := store.CreateAPIKey(ctx, &APIKey{Name: "ci", Role: "writer", CreatedBy: "alice"}, "hash"); err != nil {
+		t.Fatalf("CreateAPIKey failed: %v", err)
+	}
+	if err := store.AddTriageRule(ctx, &TriageRule{Name: "pager", TitleMatch: "outage", SetAssignee: "alice", CreatedBy: "alice"}); err != nil {
+		t.Fatalf("AddTriageRule failed: %v", err)
+	}
+	if err := store.CreateActor(ctx, &Actor{Name: "alice", Email: "alice@example.com", Aliases: []string{"asmith"}}); err != nil {
+		t.Fatalf("CreateActor failed: %v", err)
+	}
+	return a, b
+}
+
+// countActorRows counts rows anywhere in the database that still mention the actor
+func countActorRows(t *testing.T, store *SQLiteStorage, actor string) int {
+	t.Helper()
+	var n int
+	err := store.UnderlyingDB().QueryRow(`
+		SELECT
+			(SELECT COUNT(*) FROM issues WHERE assignee = ?1 OR deleted_by = ?1) +
+			(SELECT COUNT(*) FROM comments WHERE author = ?1) +
+			(SELECT COUNT(*) FROM dependencies WHERE created_by = ?1) +
+			(SELECT COUNT(*) FROM remote_dependencies WHERE created_by = ?1) +
+			(SELECT COUNT(*) FROM label_definitions WHERE created_by = ?1) +
+			(SELECT COUNT(*) FROM milestones WHERE created_by = ?1) +
+			(SELECT COUNT(*) FROM webhooks WHERE created_by = ?1) +
+			(SELECT COUNT(*) FROM api_keys WHERE created_by = ?1) +
+			(SELECT COUNT(*) FROM triage_rules WHERE created_by = ?1 OR set_assignee = ?1) +
+			(SELECT COUNT(*) FROM digest_subscriptions WHERE actor = ?1) +
+			(SELECT COUNT(*) FROM actors WHERE name = ?1 OR email LIKE ?1 || '@%') +
+			(SELECT COUNT(*) FROM actor_aliases WHERE actor = ?1) +
+			(SELECT COUNT(*) FROM issue_commits WHERE author = ?1) +
+			(SELECT COUNT(*) FROM archived_issues WHERE archived_by = ?1) +
+			(SELECT COUNT(*) FROM work_logs WHERE actor = ?1) +
+			(SELECT COUNT(*) FROM issue_leases WHERE holder = ?1) +
+			(SELECT COUNT(*) FROM oplog WHERE actor = ?1 OR instr(fields, '"' || ?1 || '"') > 0) +
+			(SELECT COUNT(*) FROM issue_history WHERE actor = ?1 OR instr(changes, '"' || ?1 || '"') > 0) +
+			(SELECT COUNT(*) FROM events WHERE actor = ?1 OR instr(old_value, '"' || ?1 || '"') > 0 OR instr(new_value, '"' || ?1 || '"') > 0)
+	`, actor).Scan(&n)
+	if err != nil {
+		t.Fatalf("count query failed: %v", err)
+	}
+	return n
+}
+
+func TestPurgeActorAnonymize(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	a, b := seedPurgeData(t, store)
+
+	// Dry run changes nothing
+	preview, err := store.PurgeActor(ctx, "alice", PurgeActorOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if preview.Assignments != 1 || preview.Comments != 1 {
+		t.Errorf("unexpected preview: %+v", preview)
+	}
+	if countActorRows(t, store, "alice") == 0 {
+		t.Fatal("dry run modified data")
+	}
+	if sub, err := store.GetDigestSubscription(ctx, "alice"); err != nil || sub == nil {
+		t.Fatalf("dry run deleted the digest subscription: %v", err)
+	}
+
+	report, err := store.PurgeActor(ctx, "alice", PurgeActorOptions{PurgedBy: "admin"})
+	if err != nil {
+		t.Fatalf("PurgeActor failed: %v", err)
+	}
+	if n := countActorRows(t, store, "alice"); n != 0 {
+		t.Errorf("expected no remaining references to alice, found %d", n)
+	}
+	var emails int
+	if err := store.UnderlyingDB().QueryRow(`SELECT COUNT(*) FROM digest_subscriptions WHERE email = 'alice@example.com'`).Scan(&emails); err != nil || emails != 0 {
+		t.Errorf("expected alice's digest subscription deleted, found %d (%v)", emails, err)
+	}
+
+	pseudonym := DefaultPurgeReplacement("alice")
+	if report.Replacement != pseudonym || report.ID == 0 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	if len(report.IssuesAffected) != 4 {
+		t.Errorf("expected 4 affected issues, got %v", report.IssuesAffected)
+	}
+
+	// So does alice's claim on it
+	lease, err := store.GetLease(ctx, a.ID)
+	if err != nil {
+		t.Fatalf("GetLease failed: %v", err)
+	}
+	if report.Leases != 1 || lease == nil || lease.Holder != pseudonym {
+		t.Errorf("expected the lease held by %s, got %+v (report %d)", pseudonym, lease, report.Leases)
+	}
+	// Ops sent to peers carry the pseudonym, as maker and as assignee
+	ops, err := store.OpsSince(ctx, nil)
+	if err != nil {
+		t.Fatalf("OpsSince failed: %v", err)
+	}
+	data, _ := json.Marshal(ops)
+	if report.Oplog == 0 || strings.Contains(string(data), `"alice"`) || !strings.Contains(string(data), `"assignee":"`+pseudonym+`"`) {
+		t.Errorf("expected ops rewritten to %s (report %d), got %s", pseudonym, report.Oplog, data)
+	}
+
+	// Records the actor set up stay, under the pseudonym: a label, milestone,
+	// webhook, API key, and triage rule, plus the rule's assignee
+	if report.Records != 6 {
+		t.Errorf("expected 6 records rewritten, got %d", report.Records)
+	}
+	rules, err := store.GetTriageRules(ctx)
+	if err != nil || len(rules) != 1 || rules[0].SetAssignee != pseudonym || rules[0].CreatedBy != pseudonym {
+		t.Errorf("expected the triage rule under %s, got %+v (%v)", pseudonym, rules, err)
+	}
+	// The registration goes with the name, email, and alias it holds
+	if report.Registry != 2 {
+		t.Errorf("expected the registration and its alias deleted, got %d", report.Registry)
+	}
+	if _, err := store.GetActor(ctx, "asmith"); !errors.Is(err, ErrActorNotFound) {
+		t.Errorf("expected the alias to resolve to no one, got %v", err)
+	}
+
+	// Logged time stays on the issue under the pseudonym
+	logs, err := store.GetWorkLogs(ctx, a.ID)
+	if err != nil {
+		t.Fatalf("GetWorkLogs failed: %v", err)
+	}
+	if report.WorkLogs != 1 || len(logs) != 1 || logs[0].Actor != pseudonym || logs[0].Minutes != 45 {
+		t.Errorf("unexpected work logs after purge: %+v (report %d)", logs, report.WorkLogs)
+	}
+
+	// So does the archive, including its listing
+	archived, err := store.ListArchived(ctx)
+	if err != nil {
+		t.Fatalf("ListArchived failed: %v", err)
+	}
+	listing, _ := json.Marshal(archived)
+	if report.Archivals != 1 || len(archived) != 1 || archived[0].ArchivedBy != pseudonym || strings.Contains(string(listing), "alice\"") {
+		t.Errorf("unexpected archive after purge: %s (report %d)", listing, report.Archivals)
+	}
+
+	// The trash keeps who deleted an issue, under the pseudonym
+	var deletedBy string
+	if err := store.UnderlyingDB().QueryRow(`SELECT deleted_by FROM issues WHERE deleted_at IS NOT NULL`).Scan(&deletedBy); err != nil {
+		t.Fatalf("failed to read deleted_by: %v", err)
+	}
+	if report.Deletions != 1 || deletedBy != pseudonym {
+		t.Errorf("expected deleted_by %s, got %q (report %d)", pseudonym, deletedBy, report.Deletions)
+	}
+
+	got, err := store.GetIssue(ctx, a.ID)
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if got.Assignee != pseudonym {
+		t.Errorf("expected assignee %s, got %s", pseudonym, got.Assignee)
+	}
+
+	// Linked commits keep their message under the pseudonym
+	links, err := store.GetCommitLinks(ctx, b.ID)
+	if err != nil {
+		t.Fatalf("GetCommitLinks failed: %v", err)
+	}
+	if report.Commits != 1 || len(links) != 1 || links[0].Author != pseudonym || links[0].Message != "Fix "+b.ID {
+		t.Errorf("unexpected commit links after purge: %+v (report %d)", links, report.Commits)
+	}
+
+	// Comment content is preserved
+	comments, err := store.GetIssueComments(ctx, b.ID)
+	if err != nil {
+		t.Fatalf("GetIssueComments failed: %v", err)
+	}
+	if len(comments) != 1 || comments[0].Text != "Fixed in main" || comments[0].Author != pseudonym {
+		t.Errorf("unexpected comments after purge: %+v", comments)
+	}
+
+	reports, err := store.GetPurgeReports(ctx)
+	if err != nil {
+		t.Fatalf("GetPurgeReports failed: %v", err)
+	}
+	if len(reports) != 1 || reports[0].SubjectHash != ActorSubjectHash("alice") {
+		t.Fatalf("unexpected stored reports: %+v", reports)
+	}
+	if reports[0].SigningPayload() != report.SigningPayload() {
+		t.Error("stored report payload does not match returned report")
+	}
+}
+
+func TestPurgeActorRemove(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	a, b := seedPurgeData(t, store)
+
+	report, err := store.PurgeActor(ctx, "alice", PurgeActorOptions{Mode: PurgeModeRemove})
+	if err != nil {
+		t.Fatalf("PurgeActor failed: %v", err)
+	}
+	if report.CommentsDeleted != 1 || report.CommentEventsDeleted != 2 {
+		t.Errorf("expected 1 deleted comment and 2 comment events, got %d and %d", report.CommentsDeleted, report.CommentEventsDeleted)
+	}
+
+	got, err := store.GetIssue(ctx, a.ID)
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if got.Assignee != "" {
+		t.Errorf("expected cleared assignee, got %q", got.Assignee)
+	}
+	if lease, err := store.GetLease(ctx, a.ID); err != nil || lease != nil || report.Leases != 1 {
+		t.Errorf("expected the lease dropped, got %+v (%v, report %d)", lease, err, report.Leases)
+	}
+	comments, err := store.GetIssueComments(ctx, b.ID)
+	if err != nil {
+		t.Fatalf("GetIssueComments failed: %v", err)
+	}
+	if len(comments) != 0 {
+		t.Errorf("expected comments to be deleted, got %d", len(comments))
+	}
+}
+
+func TestPurgeActorRewritesHistory(t *testing.T) {
+	for _, mode := range []string{PurgeModeAnonymize, PurgeModeRemove} {
+		t.Run(mode, func(t *testing.T) {
+			store, cleanup := setupTestDB(t)
+			defer cleanup()
+			ctx := context.Background()
+
+			a, b := seedPurgeData(t, store)
+			report, err := store.PurgeActor(ctx, "alice", PurgeActorOptions{Mode: mode})
+			if err != nil {
+				t.Fatalf("PurgeActor failed: %v", err)
+			}
+			if report.History == 0 {
+				t.Errorf("expected rewritten revisions in the report, got %+v", report)
+			}
+
+			// Assignees in changes follow the assignee rule; revision actors are pseudonymized
+			wantAssignee := report.Replacement
+			if mode == PurgeModeRemove {
+				wantAssignee = ""
+			}
+			history, err := store.GetIssueHistory(ctx, a.ID)
+			if err != nil {
+				t.Fatalf("GetIssueHistory failed: %v", err)
+			}
+			assigned := false
+			for _, rev := range history {
+				for _, c := range rev.Changes {
+					if c.Field == "assignee" {
+						assigned = true
+						if c.New != wantAssignee {
+							t.Errorf("expected assignee change to %q, got %v", wantAssignee, c.New)
+						}
+					}
+				}
+			}
+			if !assigned {
+				t.Fatalf("expected an assignee change in %s's history", a.ID)
+			}
+
+			history, err = store.GetIssueHistory(ctx, b.ID)
+			if err != nil {
+				t.Fatalf("GetIssueHistory failed: %v", err)
+			}
+			byReplacement := 0
+			for _, rev := range history {
+				if rev.Actor == "alice" {
+					t.Errorf("revision %d still made by alice", rev.Revision)
+				}
+				if rev.Actor == report.Replacement {
+					byReplacement++
+				}
+			}
+			if byReplacement == 0 {
+				t.Errorf("expected a revision by %s", report.Replacement)
+			}
+		})
+	}
+}
+
+func TestPurgeActorRebuildsAuditChain(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	_, b := seedPurgeData(t, store)
+
+	// Events made in a session hash their session ID, which the rebuild keeps
+	session := &AgentSession{Actor: "bob"}
+	if err := store.StartSession(ctx, session); err != nil {
+		t.Fatalf("StartSession failed: %v", err)
+	}
+	if err := store.AddLabel(WithSession(ctx, session.ID), b.ID, "triaged", "bob"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+	sealed, err := store.SealAuditChain(ctx)
+	if err != nil {
+		t.Fatalf("SealAuditChain failed: %v", err)
+	}
+	if err := store.AddAuditSignature(ctx, &AuditSignature{HeadSeq: sealed.HeadSeq, HeadHash: sealed.HeadHash, Method: "gpg", Signature: "x"}); err != nil {
+		t.Fatalf("AddAuditSignature failed: %v", err)
+	}
+
+	report, err := store.PurgeActor(ctx, "alice", PurgeActorOptions{Mode: PurgeModeRemove})
+	if err != nil {
+		t.Fatalf("PurgeActor failed: %v", err)
+	}
+	if !report.AuditChainRebuilt || report.AuditHeadBefore != sealed.HeadHash || report.AuditHeadAfter == sealed.HeadHash {
+		t.Errorf("unexpected audit fields: %+v", report)
+	}
+	if report.AuditSignaturesRemoved != 1 {
+		t.Errorf("expected stale signature to be removed, got %d", report.AuditSignaturesRemoved)
+	}
+
+	verify, err := store.VerifyAuditChain(ctx)
+	if err != nil {
+		t.Fatalf("VerifyAuditChain failed: %v", err)
+	}
+	if !verify.Valid {
+		t.Errorf("expected valid chain after purge, got %+v", verify.Problems)
+	}
+}
+
+func TestRewriteActorJSON(t *testing.T) {
+	doc := `{"assignee":"alice","title":"alice's bug","nested":[{"author":"alice"},{"author":"bob"}]}`
+	out, changed := rewriteActorJSON(doc, "alice", "", "anon")
+	if !changed {
+		t.Fatal("expected document to change")
+	}
+	if out != `{"assignee":"","nested":[{"author":"anon"},{"author":"bob"}],"title":"alice's bug"}` {
+		t.Errorf("unexpected rewrite: %s", out)
+	}
+
+	changes := `[{"field":"assignee","new":"alice","old":"bob"},{"field":"title","new":"alice","old":"x"}]`
+	out, changed = rewriteActorJSON(changes, "alice", "anon", "anon")
+	if !changed || out != `[{"field":"assignee","new":"anon","old":"bob"},{"field":"title","new":"alice","old":"x"}]` {
+		t.Errorf("unexpected rewrite of field changes: %s", out)
+	}
+	if _, changed := rewriteActorJSON("not json", "alice", "", "anon"); changed {
+		t.Error("expected non-JSON input to be left alone")
+	}
+}
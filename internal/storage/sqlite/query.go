@@ -0,0 +1,91 @@
+package sqlite
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// queryDateColumns maps the date fields of a query to their columns
+var queryDateColumns = map[string]string{
+	"created": "created_at",
+	"updated": "updated_at",
+	"closed":  "closed_at",
+	"due":     "due_date",
+	"start":   "start_date",
+}
+
+// queryComparators are the comparisons a query may make, as SQL
+var queryComparators = map[string]string{"=": "=", "<": "<", "<=": "<=", ">": ">", ">=": ">="}
+
+// queryClause compiles a parsed query into a WHERE clause, with col
+// qualifying the issues table columns as in issueFilterClauses. Issues
+// without a value for a term's field fail the term, so negating it matches
+// them, as in the in-memory backend.
+func queryClause(q *types.QueryExpr, col string) (string, []interface{}) {
+	var args []interface{}
+	switch q.Op {
+	case types.QueryAnd, types.QueryOr:
+		clauses := make([]string, len(q.Args))
+		for i, arg := range q.Args {
+			var argArgs []interface{}
+			clauses[i], argArgs = queryClause(arg, col)
+			args = append(args, argArgs...)
+		}
+		return "(" + strings.Join(clauses, " "+strings.ToUpper(string(q.Op))+" ") + ")", args
+	case types.QueryNot:
+		clause, notArgs := queryClause(q.Args[0], col)
+		return "NOT " + clause, notArgs
+	}
+
+	anyOf := func(expr string, values []interface{}) (string, []interface{}) {
+		return expr + " IN (" + strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ") + ")", values
+	}
+	compare, ok := queryComparators[q.Compare]
+	if !ok {
+		compare = "="
+	}
+
+	var clauses []string
+	switch q.Field {
+	case "status", "id":
+		return anyOf(col+q.Field, stringValues(q.Values))
+	case "type":
+		return anyOf(col+"issue_type", stringValues(q.Values))
+	case "assignee", "milestone":
+		return anyOf("COALESCE("+col+q.Field+", '')", stringValues(q.Values))
+	case "priority":
+		priorities := make([]int, len(q.Values))
+		for i, v := range q.Values {
+			priorities[i], _ = strconv.Atoi(v)
+		}
+		if compare == "=" {
+			return anyOf(col+"priority", intValues(priorities))
+		}
+		return col + "priority " + compare + " ?", []interface{}{priorities[0]}
+	case "label":
+		for _, label := range q.Values {
+			clause, labelArgs := labelFilterClause(col, label)
+			clauses = append(clauses, clause)
+			args = append(args, labelArgs...)
+		}
+		return "(" + strings.Join(clauses, " OR ") + ")", args
+	case "title":
+		return col + "title LIKE ?", []interface{}{"%" + q.Values[0] + "%"}
+	case "text":
+		pattern := "%" + q.Values[0] + "%"
+		return "(" + col + "title LIKE ? OR " + col + "description LIKE ? OR " + col + "id LIKE ?)",
+			[]interface{}{pattern, pattern, pattern}
+	}
+
+	// Dates are compared with datetime() since they may be stored with
+	// different time zone offsets
+	column, ok := queryDateColumns[q.Field]
+	if !ok {
+		return "0", nil
+	}
+	column = col + column
+	return "(" + column + " IS NOT NULL AND datetime(" + column + ") " + compare + " ?)",
+		[]interface{}{sqliteDateTime(q.Time)}
+}
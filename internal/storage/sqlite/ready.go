@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/imalsogreg/beads/internal/types"
 )
@@ -13,12 +14,25 @@ import (
 // By default, shows both 'open' and 'in_progress' issues so epics/tasks
 // ready to close are visible (bd-165)
 func (s *SQLiteStorage) GetReadyWork(ctx context.Context, filter types.WorkFilter) ([]*types.Issue, error) {
+	ctx, span := startSpan(ctx, "GetReadyWork")
+	defer span.End()
+
 	whereClauses := []string{}
 	args := []interface{}{}
 
-	// Default to open OR in_progress if not specified (bd-165)
+	// Default to open OR in_progress if not specified (bd-165), including
+	// the workflow's custom statuses that count as either
+	whereClauses = append(whereClauses, "i.deleted_at IS NULL")
 	if filter.Status == "" {
-		whereClauses = append(whereClauses, "i.status IN ('open', 'in_progress')")
+		workflow, err := s.GetWorkflow(ctx)
+		if err != nil {
+			return nil, err
+		}
+		statuses := workflow.StatusesIn(types.StatusOpen, types.StatusInProgress)
+		whereClauses = append(whereClauses, fmt.Sprintf("i.status IN (%s)", strings.TrimSuffix(strings.Repeat("?, ", len(statuses)), ", ")))
+		for _, status := range statuses {
+			args = append(args, string(status))
+		}
 	} else {
 		whereClauses = append(whereClauses, "i.status = ?")
 		args = append(args, filter.Status)
@@ -29,27 +43,44 @@ func (s *SQLiteStorage) GetReadyWork(ctx context.Context, filter types.WorkFilte
 		args = append(args, *filter.Priority)
 	}
 
+	if filter.MaxPriority != nil {
+		whereClauses = append(whereClauses, "i.priority <= ?")
+		args = append(args, *filter.MaxPriority)
+	}
+
 	if filter.Assignee != nil {
 		whereClauses = append(whereClauses, "i.assignee = ?")
 		args = append(args, *filter.Assignee)
 	}
 
+	for _, label := range filter.Labels {
+		clause, labelArgs := labelFilterClause("i.", label)
+		whereClauses = append(whereClauses, clause)
+		args = append(args, labelArgs...)
+	}
+
+	// Issues scheduled to start later aren't ready yet
+	now := time.Now()
+	whereClauses = append(whereClauses, "(i.start_date IS NULL OR datetime(i.start_date) <= ?)")
+	args = append(args, sqliteDateTime(now))
+
 	// Build WHERE clause properly
 	whereSQL := strings.Join(whereClauses, " AND ")
 
-	// Build LIMIT clause using parameter
-	limitSQL := ""
-	if filter.Limit > 0 {
-		limitSQL = " LIMIT ?"
-		args = append(args, filter.Limit)
-	}
-
 	// Default to hybrid sort for backwards compatibility
 	sortPolicy := filter.SortPolicy
 	if sortPolicy == "" {
 		sortPolicy = types.SortPolicyHybrid
 	}
 	orderBySQL := buildOrderByClause(sortPolicy)
+	args = append(args, sqliteDateTime(types.OverdueCutoff(now)))
+
+	// Build LIMIT clause using parameter
+	limitSQL := ""
+	if filter.Limit > 0 {
+		limitSQL = " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
 
 	// Query with recursive CTE to propagate blocking through parent-child hierarchy
 	// Algorithm:
@@ -65,7 +96,8 @@ func (s *SQLiteStorage) GetReadyWork(ctx context.Context, filter types.WorkFilte
 		    FROM dependencies d
 		    JOIN issues blocker ON d.depends_on_id = blocker.id
 		    WHERE d.type = 'blocks'
-		      AND blocker.status IN ('open', 'in_progress', 'blocked')
+		      AND blocker.status != 'closed'
+		      AND blocker.deleted_at IS NULL
 		  ),
 
 		  -- Step 2: Propagate blockage to all descendants via parent-child
@@ -87,7 +119,8 @@ func (s *SQLiteStorage) GetReadyWork(ctx context.Context, filter types.WorkFilte
 		-- Step 3: Select ready issues (excluding all blocked)
 		SELECT i.id, i.title, i.description, i.design, i.acceptance_criteria, i.notes,
 		i.status, i.priority, i.issue_type, i.assignee, i.estimated_minutes,
-		i.created_at, i.updated_at, i.closed_at, i.external_ref
+		i.created_at, i.updated_at, i.closed_at, i.external_ref,
+		i.due_date, i.start_date, i.milestone
 		FROM issues i
 		WHERE %s
 		AND NOT EXISTS (
@@ -97,7 +130,7 @@ func (s *SQLiteStorage) GetReadyWork(ctx context.Context, filter types.WorkFilte
 		%s
 	`, whereSQL, orderBySQL, limitSQL)
 
-	rows, err := s.db.QueryContext(ctx, query, args...)
+	rows, err := s.reads.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get ready work: %w", err)
 	}
@@ -109,7 +142,7 @@ func (s *SQLiteStorage) GetReadyWork(ctx context.Context, filter types.WorkFilte
 // GetBlockedIssues returns issues that are blocked by dependencies
 func (s *SQLiteStorage) GetBlockedIssues(ctx context.Context) ([]*types.BlockedIssue, error) {
 	// Use GROUP_CONCAT to get all blocker IDs in a single query (no N+1)
-	rows, err := s.db.QueryContext(ctx, `
+	rows, err := s.reads.QueryContext(ctx, `
 		SELECT
 		    i.id, i.title, i.description, i.design, i.acceptance_criteria, i.notes,
 		    i.status, i.priority, i.issue_type, i.assignee, i.estimated_minutes,
@@ -119,9 +152,11 @@ func (s *SQLiteStorage) GetBlockedIssues(ctx context.Context) ([]*types.BlockedI
 		FROM issues i
 		JOIN dependencies d ON i.id = d.issue_id
 		JOIN issues blocker ON d.depends_on_id = blocker.id
-		WHERE i.status IN ('open', 'in_progress', 'blocked')
+		WHERE i.status != 'closed'
+		  AND i.deleted_at IS NULL
 		  AND d.type = 'blocks'
-		  AND blocker.status IN ('open', 'in_progress', 'blocked')
+		  AND blocker.status != 'closed'
+		  AND blocker.deleted_at IS NULL
 		GROUP BY i.id
 		ORDER BY i.priority ASC
 	`)
@@ -163,6 +198,7 @@ func (s *SQLiteStorage) GetBlockedIssues(ctx context.Context) ([]*types.BlockedI
 		if externalRef.Valid {
 			issue.ExternalRef = &externalRef.String
 		}
+		s.decryptIssueFields(&issue.Issue)
 
 		// Parse comma-separated blocker IDs
 		if blockerIDsStr != "" {
@@ -175,19 +211,24 @@ func (s *SQLiteStorage) GetBlockedIssues(ctx context.Context) ([]*types.BlockedI
 	return blocked, nil
 }
 
-// buildOrderByClause generates the ORDER BY clause based on sort policy
+// overdueFirst puts overdue issues ahead of the rest under every sort
+// policy. Its parameter is the overdue cutoff (types.OverdueCutoff).
+const overdueFirst = `CASE WHEN datetime(i.due_date) < ? THEN 0 ELSE 1 END ASC`
+
+// buildOrderByClause generates the ORDER BY clause based on sort policy.
+// The clause takes one parameter, for overdueFirst.
 func buildOrderByClause(policy types.SortPolicy) string {
 	switch policy {
 	case types.SortPolicyPriority:
-		return `ORDER BY i.priority ASC, i.created_at ASC`
+		return `ORDER BY ` + overdueFirst + `, i.priority ASC, i.created_at ASC`
 
 	case types.SortPolicyOldest:
-		return `ORDER BY i.created_at ASC`
+		return `ORDER BY ` + overdueFirst + `, i.created_at ASC`
 
 	case types.SortPolicyHybrid:
 		fallthrough
 	default:
-		return `ORDER BY
+		return `ORDER BY ` + overdueFirst + `,
 			CASE
 				WHEN datetime(i.created_at) >= datetime('now', '-48 hours') THEN 0
 				ELSE 1
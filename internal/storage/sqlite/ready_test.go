@@ -4,6 +4,7 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/imalsogreg/beads/internal/types"
 )
@@ -168,6 +169,48 @@ func TestGetReadyWorkWithAssigneeFilter(t *testing.T) {
 	}
 }
 
+func TestGetReadyWorkWithMaxPriorityAndLabels(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	urgentBackend := &types.Issue{Title: "Urgent backend", Status: types.StatusOpen, Priority: 0, IssueType: types.TypeBug}
+	highBackend := &types.Issue{Title: "High backend", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	lowBackend := &types.Issue{Title: "Low backend", Status: types.StatusOpen, Priority: 3, IssueType: types.TypeTask}
+	urgentFrontend := &types.Issue{Title: "Urgent frontend", Status: types.StatusOpen, Priority: 0, IssueType: types.TypeBug}
+	for _, issue := range []*types.Issue{urgentBackend, highBackend, lowBackend, urgentFrontend} {
+		if err := store.CreateIssue(ctx, issue, "test-user"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, issue := range []*types.Issue{urgentBackend, highBackend, lowBackend} {
+		if err := store.AddLabel(ctx, issue.ID, "backend", "test-user"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.AddLabel(ctx, urgentBackend.ID, "oncall", "test-user"); err != nil {
+		t.Fatal(err)
+	}
+
+	maxPriority := 1
+	ready, err := store.GetReadyWork(ctx, types.WorkFilter{Status: types.StatusOpen, MaxPriority: &maxPriority, Labels: []string{"backend"}})
+	if err != nil {
+		t.Fatalf("GetReadyWork failed: %v", err)
+	}
+	if len(ready) != 2 || ready[0].ID != urgentBackend.ID || ready[1].ID != highBackend.ID {
+		t.Errorf("Expected %s and %s, got %v", urgentBackend.ID, highBackend.ID, ready)
+	}
+
+	ready, err = store.GetReadyWork(ctx, types.WorkFilter{Status: types.StatusOpen, Labels: []string{"backend", "oncall"}})
+	if err != nil {
+		t.Fatalf("GetReadyWork failed: %v", err)
+	}
+	if len(ready) != 1 || ready[0].ID != urgentBackend.ID {
+		t.Errorf("Expected only %s with both labels, got %v", urgentBackend.ID, ready)
+	}
+}
+
 func TestGetReadyWorkWithLimit(t *testing.T) {
 	store, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -1094,3 +1137,71 @@ func TestSortPolicyDefault(t *testing.T) {
 		t.Errorf("Expected P2 second, got P%d", ready[1].Priority)
 	}
 }
+
+func TestGetReadyWorkSchedule(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	now := time.Now()
+	lastWeek := now.AddDate(0, 0, -7)
+	nextWeek := now.AddDate(0, 0, 7)
+
+	onTime := &types.Issue{Title: "On time", Status: types.StatusOpen, Priority: 0, IssueType: types.TypeTask, DueDate: &nextWeek}
+	late := &types.Issue{Title: "Late", Status: types.StatusOpen, Priority: 3, IssueType: types.TypeTask, DueDate: &lastWeek}
+	later := &types.Issue{Title: "Not started", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask, StartDate: &nextWeek}
+	for _, issue := range []*types.Issue{onTime, late, later} {
+		if err := store.CreateIssue(ctx, issue, "test-user"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+
+	got, err := store.GetIssue(ctx, late.ID)
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if got.DueDate == nil || !got.DueDate.Equal(lastWeek) {
+		t.Errorf("DueDate = %v, want %v", got.DueDate, lastWeek)
+	}
+
+	// Overdue work comes first whatever its priority; work that hasn't
+	// started yet isn't ready
+	for _, policy := range []types.SortPolicy{types.SortPolicyHybrid, types.SortPolicyPriority, types.SortPolicyOldest} {
+		ready, err := store.GetReadyWork(ctx, types.WorkFilter{SortPolicy: policy})
+		if err != nil {
+			t.Fatalf("GetReadyWork failed: %v", err)
+		}
+		if len(ready) != 2 || ready[0].ID != late.ID || ready[1].ID != onTime.ID {
+			t.Errorf("%s: expected [%s %s], got %v", policy, late.ID, onTime.ID, issueIDs(ready))
+		}
+	}
+
+	overdue, err := store.SearchIssues(ctx, "", types.IssueFilter{Overdue: true})
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+	if len(overdue) != 1 || overdue[0].ID != late.ID {
+		t.Errorf("expected only %s overdue, got %v", late.ID, issueIDs(overdue))
+	}
+
+	dueBefore := now.AddDate(0, 0, 30)
+	due, err := store.SearchIssues(ctx, "", types.IssueFilter{DueBefore: &dueBefore, DueAfter: &now})
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+	if len(due) != 1 || due[0].ID != onTime.ID {
+		t.Errorf("expected only %s due in the next month, got %v", onTime.ID, issueIDs(due))
+	}
+
+	// Dates can be updated from strings, and cleared
+	if err := store.UpdateIssue(ctx, later.ID, map[string]interface{}{"start_date": "", "due_date": "2000-01-01"}, "test-user"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	got, _ = store.GetIssue(ctx, later.ID)
+	if got.StartDate != nil || got.DueDate == nil || got.DueDate.Year() != 2000 {
+		t.Errorf("after update: start %v, due %v", got.StartDate, got.DueDate)
+	}
+	if err := store.UpdateIssue(ctx, later.ID, map[string]interface{}{"due_date": "someday"}, "test-user"); err == nil {
+		t.Error("expected an error for an invalid due date")
+	}
+}
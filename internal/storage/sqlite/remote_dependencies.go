@@ -0,0 +1,77 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/remote"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// addRemoteDependency records that an issue depends on an issue in another
+// workspace. The target can't be checked from here, and remote edges can't
+// close a cycle, so only the local issue is validated.
+func (s *SQLiteStorage) addRemoteDependency(ctx context.Context, dep *types.Dependency, actor string) error {
+	issue, err := s.GetIssue(ctx, dep.IssueID)
+	if err != nil {
+		return fmt.Errorf("failed to check issue %s: %w", dep.IssueID, err)
+	}
+	if issue == nil {
+		return types.NotFound("issue", dep.IssueID)
+	}
+	if dep.CreatedAt.IsZero() {
+		dep.CreatedAt = time.Now()
+	}
+	if dep.CreatedBy == "" {
+		dep.CreatedBy = actor
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO remote_dependencies (issue_id, depends_on_ref, type, created_at, created_by)
+		VALUES (?, ?, ?, ?, ?)
+	`, dep.IssueID, dep.DependsOnID, dep.Type, dep.CreatedAt, dep.CreatedBy)
+	if err != nil {
+		return fmt.Errorf("failed to add dependency: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO events (issue_id, event_type, actor, comment, session_id)
+		VALUES (?, ?, ?, ?, ?)
+	`, dep.IssueID, types.EventDependencyAdded, actor,
+		fmt.Sprintf("Added dependency: %s %s %s", dep.IssueID, dep.Type, dep.DependsOnID), s.eventSession(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to record event: %w", err)
+	}
+
+	if err := markIssuesDirtyTx(ctx, tx, []string{dep.IssueID}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// deleteDependencyQuery returns the statement that deletes the dependency of
+// an issue on dependsOnID, from whichever table holds it
+func deleteDependencyQuery(dependsOnID string) string {
+	if remote.IsRef(dependsOnID) {
+		return `DELETE FROM remote_dependencies WHERE issue_id = ? AND depends_on_ref = ?`
+	}
+	return `DELETE FROM dependencies WHERE issue_id = ? AND depends_on_id = ?`
+}
+
+// localIssueIDs drops references to other workspaces from a list of IDs
+func localIssueIDs(ids ...string) []string {
+	local := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !remote.IsRef(id) {
+			local = append(local, id)
+		}
+	}
+	return local
+}
@@ -0,0 +1,490 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/replication"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// dbQueryer is a transaction or connection that can also be read from
+type dbQueryer interface {
+	dbExecer
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// replicaIDKey is the metadata key holding this database's replica ID
+const replicaIDKey = "replica_id"
+
+// replicatedCreateFields are the fields of a new issue its created op
+// carries; later ops carry whichever of allowedUpdateFields, labels, and
+// deleted_at they change
+var replicatedCreateFields = []string{"created_at", "updated_at", "closed_at"}
+
+type replicatingKey struct{}
+
+// withReplication marks ctx as applying another replica's ops, so the
+// changes they make aren't logged again as ops of this one
+func withReplication(ctx context.Context) context.Context {
+	return context.WithValue(ctx, replicatingKey{}, true)
+}
+
+func isReplicating(ctx context.Context) bool {
+	replicating, _ := ctx.Value(replicatingKey{}).(bool)
+	return replicating
+}
+
+// ReplicaID returns this database's replica ID, choosing one the first time
+func (s *SQLiteStorage) ReplicaID(ctx context.Context) (string, error) {
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+	return ensureReplicaID(ctx, conn)
+}
+
+// ResetReplicaID gives the database a new replica ID on its next change.
+// A restored backup needs one: its clock has gone back, so its new ops
+// would reuse sequence numbers peers have already seen.
+func (s *SQLiteStorage) ResetReplicaID(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM metadata WHERE key = ?`, replicaIDKey)
+	return err
+}
+
+func ensureReplicaID(ctx context.Context, q dbQueryer) (string, error) {
+	var id string
+	err := q.QueryRowContext(ctx, `SELECT value FROM metadata WHERE key = ?`, replicaIDKey).Scan(&id)
+	if err == nil && id != "" {
+		return id, nil
+	}
+	if err != nil && err != sql.ErrNoRows {
+		return "", fmt.Errorf("failed to get replica ID: %w", err)
+	}
+	if id, err = replication.NewReplicaID(); err != nil {
+		return "", fmt.Errorf("failed to generate replica ID: %w", err)
+	}
+	if _, err := q.ExecContext(ctx, `INSERT OR IGNORE INTO metadata (key, value) VALUES (?, ?)`, replicaIDKey, id); err != nil {
+		return "", fmt.Errorf("failed to set replica ID: %w", err)
+	}
+	err = q.QueryRowContext(ctx, `SELECT value FROM metadata WHERE key = ?`, replicaIDKey).Scan(&id)
+	if err != nil {
+		return "", fmt.Errorf("failed to get replica ID: %w", err)
+	}
+	return id, nil
+}
+
+// vectorClock returns the highest op sequence number logged from each replica
+func vectorClock(ctx context.Context, q dbQueryer) (replication.VectorClock, error) {
+	rows, err := q.QueryContext(ctx, `SELECT replica_id, seq FROM replica_clock`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vector clock: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	clock := make(replication.VectorClock)
+	for rows.Next() {
+		var id string
+		var seq int64
+		if err := rows.Scan(&id, &seq); err != nil {
+			return nil, err
+		}
+		clock[id] = seq
+	}
+	return clock, rows.Err()
+}
+
+// ReplicationState returns this replica's ID and vector clock
+func (s *SQLiteStorage) ReplicationState(ctx context.Context) (*replication.State, error) {
+	id, err := s.ReplicaID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	clock, err := vectorClock(ctx, s.db)
+	if err != nil {
+		return nil, err
+	}
+	return &replication.State{Replica: id, Clock: clock}, nil
+}
+
+// opFields returns the fields a revision writes, as op fields. Renames
+// aren't replicated, since each replica numbers its own issues.
+func opFields(rev *IssueRevision) map[string]interface{} {
+	fields := make(map[string]interface{})
+	if rev.issue != nil {
+		var all map[string]interface{}
+		data, err := json.Marshal(rev.issue)
+		if err != nil || json.Unmarshal(data, &all) != nil {
+			return nil
+		}
+		for field := range allowedUpdateFields {
+			if v, ok := all[field]; ok {
+				fields[field] = v
+			}
+		}
+		for _, field := range replicatedCreateFields {
+			if v, ok := all[field]; ok {
+				fields[field] = v
+			}
+		}
+		return fields
+	}
+	if rev.Kind == "renamed" {
+		return nil
+	}
+	for _, c := range rev.Changes {
+		switch {
+		case c.Field == "label" && c.New != nil:
+			fields[replication.LabelField(fmt.Sprint(c.New))] = true
+		case c.Field == "label":
+			fields[replication.LabelField(fmt.Sprint(c.Old))] = false
+		case allowedUpdateFields[c.Field] || c.Field == "deleted_at":
+			fields[c.Field] = c.New
+		}
+	}
+	return fields
+}
+
+// recordOp logs a local revision as the next op of this replica, unless
+// it comes from applying another replica's op
+func (s *SQLiteStorage) recordOp(ctx context.Context, q dbQueryer, rev *IssueRevision) error {
+	if isReplicating(ctx) {
+		return nil
+	}
+	fields := opFields(rev)
+	if len(fields) == 0 {
+		return nil
+	}
+	replica, err := ensureReplicaID(ctx, q)
+	if err != nil {
+		return err
+	}
+	clock, err := vectorClock(ctx, q)
+	if err != nil {
+		return err
+	}
+	clock[replica]++
+	op := &replication.Op{
+		Replica:   replica,
+		Seq:       clock[replica],
+		Clock:     clock,
+		IssueID:   rev.IssueID,
+		Kind:      string(rev.Kind),
+		Actor:     rev.Actor,
+		Fields:    fields,
+		CreatedAt: time.Now().UTC(),
+	}
+	if rev.issue != nil {
+		op.Origin = replication.Origin(replica, op.Seq)
+		if _, err := q.ExecContext(ctx, `INSERT INTO replica_issues (origin, issue_id) VALUES (?, ?)`, op.Origin, op.IssueID); err != nil {
+			return fmt.Errorf("failed to record issue origin: %w", err)
+		}
+	} else {
+		err := q.QueryRowContext(ctx, `SELECT origin FROM replica_issues WHERE issue_id = ?`, op.IssueID).Scan(&op.Origin)
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("failed to get issue origin: %w", err)
+		}
+	}
+	if err := s.insertOp(ctx, q, op); err != nil {
+		return err
+	}
+	return setFieldStamps(ctx, q, op.IssueID, op, fields)
+}
+
+// insertOp adds an op to the oplog and advances the vector clock past it.
+// Sensitive values are encrypted like history changes.
+func (s *SQLiteStorage) insertOp(ctx context.Context, exec dbExecer, op *replication.Op) error {
+	stored := make(map[string]interface{}, len(op.Fields))
+	for field, v := range op.Fields {
+		if isEncryptedEventField(field) {
+			var err error
+			if v, err = s.encryptChangeValue(v); err != nil {
+				return err
+			}
+		}
+		stored[field] = v
+	}
+	fields, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("failed to encode op: %w", err)
+	}
+	clock, err := json.Marshal(op.Clock)
+	if err != nil {
+		return fmt.Errorf("failed to encode op clock: %w", err)
+	}
+	_, err = exec.ExecContext(ctx, `
+		INSERT INTO oplog (replica_id, seq, issue_id, origin, kind, actor, fields, clock, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, op.Replica, op.Seq, op.IssueID, op.Origin, op.Kind, op.Actor, string(fields), string(clock), op.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to log op: %w", err)
+	}
+	_, err = exec.ExecContext(ctx, `
+		INSERT INTO replica_clock (replica_id, seq) VALUES (?, ?)
+		ON CONFLICT (replica_id) DO UPDATE SET seq = MAX(seq, excluded.seq)
+	`, op.Replica, op.Seq)
+	if err != nil {
+		return fmt.Errorf("failed to advance vector clock: %w", err)
+	}
+	return nil
+}
+
+// setFieldStamps records op as the last writer of fields of the local issue id
+func setFieldStamps(ctx context.Context, exec dbExecer, id string, op *replication.Op, fields map[string]interface{}) error {
+	clock, err := json.Marshal(op.Clock)
+	if err != nil {
+		return fmt.Errorf("failed to encode op clock: %w", err)
+	}
+	for field := range fields {
+		_, err := exec.ExecContext(ctx, `
+			INSERT INTO replica_fields (issue_id, field, replica_id, clock, created_at)
+			VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT (issue_id, field) DO UPDATE SET
+				replica_id = excluded.replica_id, clock = excluded.clock, created_at = excluded.created_at
+		`, id, field, op.Replica, string(clock), op.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to record field stamp: %w", err)
+		}
+	}
+	return nil
+}
+
+// fieldStamp returns the stamp of the op that last wrote an issue field,
+// or nil if no logged op has
+func (s *SQLiteStorage) fieldStamp(ctx context.Context, issueID, field string) (*replication.Stamp, error) {
+	var stamp replication.Stamp
+	var clock string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT replica_id, clock, created_at FROM replica_fields WHERE issue_id = ? AND field = ?
+	`, issueID, field).Scan(&stamp.Replica, &clock, &stamp.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get field stamp: %w", err)
+	}
+	if err := json.Unmarshal([]byte(clock), &stamp.Clock); err != nil {
+		return nil, fmt.Errorf("invalid clock for %s %s: %w", issueID, field, err)
+	}
+	return &stamp, nil
+}
+
+// OpsSince returns the logged ops a replica at clock hasn't seen, in
+// causal order
+func (s *SQLiteStorage) OpsSince(ctx context.Context, clock replication.VectorClock) ([]*replication.Op, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT replica_id, seq, issue_id, origin, kind, actor, fields, clock, created_at
+		FROM oplog
+		ORDER BY replica_id, seq
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read oplog: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var ops []*replication.Op
+	for rows.Next() {
+		var op replication.Op
+		var fields, opClock string
+		if err := rows.Scan(&op.Replica, &op.Seq, &op.IssueID, &op.Origin, &op.Kind, &op.Actor, &fields, &opClock, &op.CreatedAt); err != nil {
+			return nil, err
+		}
+		if op.Seq <= clock[op.Replica] {
+			continue
+		}
+		if err := json.Unmarshal([]byte(fields), &op.Fields); err != nil {
+			return nil, fmt.Errorf("invalid op %s/%d: %w", op.Replica, op.Seq, err)
+		}
+		if err := json.Unmarshal([]byte(opClock), &op.Clock); err != nil {
+			return nil, fmt.Errorf("invalid op %s/%d: %w", op.Replica, op.Seq, err)
+		}
+		for field, v := range op.Fields {
+			if isEncryptedEventField(field) {
+				op.Fields[field] = s.decryptChangeValue(v)
+			}
+		}
+		ops = append(ops, &op)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	replication.SortCausal(ops)
+	return ops, nil
+}
+
+// ApplyOps applies ops from other replicas, in causal order, and logs them.
+// Each field an op writes takes its value unless a later op already wrote
+// it (see replication.Stamp.Wins). Ops already logged are ignored, and ops
+// for issues this replica doesn't have, like ones created before either
+// replica logged ops, are logged without effect.
+func (s *SQLiteStorage) ApplyOps(ctx context.Context, ops []*replication.Op) (*replication.ApplyResult, error) {
+	ctx = withReplication(ctx)
+	local, err := s.ReplicaID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sorted := append([]*replication.Op(nil), ops...)
+	replication.SortCausal(sorted)
+
+	result := &replication.ApplyResult{}
+	for _, op := range sorted {
+		if op.Replica == local || op.Replica == "" || op.Seq <= 0 {
+			continue
+		}
+		var known bool
+		err := s.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM oplog WHERE replica_id = ? AND seq = ?)`, op.Replica, op.Seq).Scan(&known)
+		if err != nil {
+			return result, fmt.Errorf("failed to check oplog: %w", err)
+		}
+		if known {
+			continue
+		}
+		if err := s.applyOp(ctx, op, result); err != nil {
+			return result, fmt.Errorf("op %s/%d on %s: %w", op.Replica, op.Seq, op.IssueID, err)
+		}
+		if err := s.insertOp(ctx, s.db, op); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// localIssueID returns the ID this replica has for the issue an op is
+// about, found by origin when the op has one, or "" if it has none
+func (s *SQLiteStorage) localIssueID(ctx context.Context, op *replication.Op) (string, error) {
+	if op.Origin == "" {
+		issue, err := s.GetIssue(ctx, op.IssueID)
+		if err != nil || issue == nil {
+			return "", err
+		}
+		return issue.ID, nil
+	}
+	var id string
+	err := s.db.QueryRowContext(ctx, `SELECT issue_id FROM replica_issues WHERE origin = ?`, op.Origin).Scan(&id)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get issue origin: %w", err)
+	}
+	return id, nil
+}
+
+// applyOp applies the fields of op that win against the last local writes
+func (s *SQLiteStorage) applyOp(ctx context.Context, op *replication.Op, result *replication.ApplyResult) error {
+	id, err := s.localIssueID(ctx, op)
+	if err != nil {
+		return err
+	}
+	if id == "" {
+		if op.Kind != string(types.EventCreated) || op.Origin == "" {
+			result.Skipped++
+			return nil
+		}
+		if id, err = s.createFromOp(ctx, op, result); err != nil {
+			return err
+		}
+		result.Applied++
+		return setFieldStamps(ctx, s.db, id, op, op.Fields)
+	}
+
+	stamp := op.Stamp()
+	won := make(map[string]interface{})
+	for field, v := range op.Fields {
+		current, err := s.fieldStamp(ctx, id, field)
+		if err != nil {
+			return err
+		}
+		if current != nil && op.Clock.Compare(current.Clock) == replication.Concurrent {
+			result.Conflicts++
+		}
+		if current == nil || stamp.Wins(*current) {
+			won[field] = v
+		}
+	}
+	if len(won) == 0 {
+		result.Stale++
+		return nil
+	}
+
+	updates := make(map[string]interface{})
+	for field, v := range won {
+		if label, ok := replication.ParseLabelField(field); ok {
+			add, _ := v.(bool)
+			err = s.executeLabelOperation(ctx, id, label, op.Actor, add)
+		} else if field == "deleted_at" {
+			err = s.applyTrashOp(ctx, id, v != nil, op.Actor)
+		} else if allowedUpdateFields[field] {
+			updates[field] = opValue(v)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if len(updates) > 0 {
+		if err := s.UpdateIssue(ctx, id, updates, op.Actor); err != nil {
+			return err
+		}
+	}
+	result.Applied++
+	return setFieldStamps(ctx, s.db, id, op, won)
+}
+
+// createFromOp creates the issue a created op describes, keeping its
+// timestamps, and returns its ID here: the op's, unless another issue
+// already has it
+func (s *SQLiteStorage) createFromOp(ctx context.Context, op *replication.Op, result *replication.ApplyResult) (string, error) {
+	data, err := json.Marshal(op.Fields)
+	if err != nil {
+		return "", err
+	}
+	var issue types.Issue
+	if err := json.Unmarshal(data, &issue); err != nil {
+		return "", fmt.Errorf("invalid created op: %w", err)
+	}
+	taken, err := s.GetIssue(ctx, op.IssueID)
+	if err != nil {
+		return "", err
+	}
+	if taken == nil {
+		issue.ID = op.IssueID
+	} else {
+		result.Renamed++ // CreateIssues assigns the next free ID
+	}
+	if err := s.CreateIssues(ctx, []*types.Issue{&issue}, op.Actor); err != nil {
+		return "", err
+	}
+	if _, err := s.db.ExecContext(ctx, `INSERT INTO replica_issues (origin, issue_id) VALUES (?, ?)`, op.Origin, issue.ID); err != nil {
+		return "", fmt.Errorf("failed to record issue origin: %w", err)
+	}
+	return issue.ID, nil
+}
+
+// applyTrashOp moves an issue to or from the trash, if it isn't there already
+func (s *SQLiteStorage) applyTrashOp(ctx context.Context, id string, deleted bool, actor string) error {
+	var err error
+	if deleted {
+		err = s.SoftDeleteIssue(ctx, id, actor)
+	} else {
+		err = s.RestoreIssue(ctx, id, actor)
+	}
+	if errors.Is(err, ErrAlreadyInTrash) || errors.Is(err, ErrNotInTrash) {
+		return nil
+	}
+	return err
+}
+
+// opValue converts a JSON-decoded op value for UpdateIssue, which
+// validates integers as int
+func opValue(v interface{}) interface{} {
+	if f, ok := v.(float64); ok && f == float64(int(f)) {
+		return int(f)
+	}
+	return v
+}
@@ -0,0 +1,155 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/replication"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// syncStores exchanges ops between two replicas the way bd sync remote does
+func syncStores(t *testing.T, a, b *SQLiteStorage) {
+	t.Helper()
+	ctx := context.Background()
+	stateA, err := a.ReplicationState(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stateB, err := b.ReplicationState(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	toB, err := a.OpsSince(ctx, stateB.Clock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.ApplyOps(ctx, toB); err != nil {
+		t.Fatal(err)
+	}
+	toA, err := b.OpsSince(ctx, stateA.Clock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.ApplyOps(ctx, toA); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReplication(t *testing.T) {
+	ctx := context.Background()
+	a := newTestStore(t, filepath.Join(t.TempDir(), "a.db"))
+	defer a.Close()
+	b := newTestStore(t, filepath.Join(t.TempDir(), "b.db"))
+	defer b.Close()
+
+	shared := &types.Issue{Title: "Shared", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := a.CreateIssue(ctx, shared, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.AddLabel(ctx, shared.ID, "backend", "alice"); err != nil {
+		t.Fatal(err)
+	}
+	syncStores(t, a, b)
+
+	got, err := b.GetIssue(ctx, shared.ID)
+	if err != nil || got == nil {
+		t.Fatalf("Expected %s on the second replica, got %v, %v", shared.ID, got, err)
+	}
+	if got.Title != "Shared" || !got.CreatedAt.Equal(shared.CreatedAt) {
+		t.Errorf("Expected the issue as created, got %q created %v", got.Title, got.CreatedAt)
+	}
+	if labels, _ := b.GetLabels(ctx, shared.ID); len(labels) != 1 || labels[0] != "backend" {
+		t.Errorf("Expected the label to replicate, got %v", labels)
+	}
+
+	// Offline on both sides: the same field edited twice, different fields
+	// edited once each, and the same ID created for different issues
+	if err := a.UpdateIssue(ctx, shared.ID, map[string]interface{}{"title": "From A"}, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.RemoveLabel(ctx, shared.ID, "backend", "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.UpdateIssue(ctx, shared.ID, map[string]interface{}{"priority": 0}, "bob"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.UpdateIssue(ctx, shared.ID, map[string]interface{}{"title": "From B"}, "bob"); err != nil {
+		t.Fatal(err)
+	}
+	newA := &types.Issue{Title: "New on A", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := a.CreateIssue(ctx, newA, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	newB := &types.Issue{Title: "New on B", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := b.CreateIssue(ctx, newB, "bob"); err != nil {
+		t.Fatal(err)
+	}
+	if newA.ID != newB.ID {
+		t.Fatalf("Expected both replicas to pick the same ID, got %s and %s", newA.ID, newB.ID)
+	}
+	syncStores(t, a, b)
+
+	// B's title edit came after two changes of its own, A's after one, so
+	// B's wins on both replicas
+	for name, st := range map[string]*SQLiteStorage{"a": a, "b": b} {
+		issue, err := st.GetIssue(ctx, shared.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if issue.Title != "From B" || issue.Priority != 0 {
+			t.Errorf("%s: expected title From B at P0, got %q at P%d", name, issue.Title, issue.Priority)
+		}
+		if labels, _ := st.GetLabels(ctx, shared.ID); len(labels) != 0 {
+			t.Errorf("%s: expected the label removal to replicate, got %v", name, labels)
+		}
+		issues, err := st.SearchIssues(ctx, "New on", types.IssueFilter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(issues) != 2 {
+			t.Errorf("%s: expected both new issues under their own IDs, got %d", name, len(issues))
+		}
+	}
+
+	// The renumbered issue is still found by origin on later edits
+	if err := a.UpdateIssue(ctx, newA.ID, map[string]interface{}{"status": string(types.StatusInProgress)}, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	syncStores(t, a, b)
+	issues, err := b.SearchIssues(ctx, "New on A", types.IssueFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 || issues[0].ID == newA.ID || issues[0].Status != types.StatusInProgress {
+		t.Errorf("Expected A's issue under a new ID on B, in progress, got %+v", issues)
+	}
+
+	// Syncing again exchanges nothing new
+	state, _ := b.ReplicationState(ctx)
+	ops, err := a.OpsSince(ctx, state.Clock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ops) != 0 {
+		t.Errorf("Expected replicas in sync, A still has %d op(s) for B", len(ops))
+	}
+	result, err := b.ApplyOps(ctx, mustOps(t, a))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Applied != 0 {
+		t.Errorf("Expected replayed ops to be ignored, got %+v", result)
+	}
+}
+
+// mustOps returns every op st has logged
+func mustOps(t *testing.T, st *SQLiteStorage) []*replication.Op {
+	t.Helper()
+	ops, err := st.OpsSince(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ops
+}
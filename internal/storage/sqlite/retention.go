@@ -0,0 +1,374 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// Retention rule targets
+const (
+	RetentionTargetIssues = "issues"
+	RetentionTargetEvents = "events"
+)
+
+// RetentionRule hard-deletes issues or events older than MaxAgeDays.
+// Issue age is measured from closed_at (or updated_at for issues that are not closed);
+// event age from created_at. Issue rules without a status only match closed
+// issues, so open work is never deleted unless a rule names its status;
+// other empty filters match everything.
+type RetentionRule struct {
+	ID         int64     `json:"id"`
+	Target     string    `json:"target"`
+	IssueType  string    `json:"issue_type,omitempty"`
+	Status     string    `json:"status,omitempty"`
+	EventType  string    `json:"event_type,omitempty"`
+	MaxAgeDays int       `json:"max_age_days"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Describe returns a one-line summary of the rule
+func (r *RetentionRule) Describe() string {
+	var parts []string
+	if r.Target == RetentionTargetIssues {
+		parts = append(parts, "delete")
+		if r.Status != "" {
+			parts = append(parts, r.Status)
+		}
+		if r.IssueType != "" {
+			parts = append(parts, r.IssueType)
+		}
+		parts = append(parts, "issues")
+	} else {
+		parts = append(parts, "purge")
+		if r.EventType != "" {
+			parts = append(parts, r.EventType)
+		}
+		parts = append(parts, "events")
+	}
+	parts = append(parts, fmt.Sprintf("older than %d days", r.MaxAgeDays))
+	return strings.Join(parts, " ")
+}
+
+// RetentionRuleResult lists what a single rule matched
+type RetentionRuleResult struct {
+	Rule     *RetentionRule `json:"rule"`
+	IssueIDs []string       `json:"issue_ids,omitempty"`
+	Events   int            `json:"events"`
+}
+
+// RetentionReport summarizes a retention run. For dry runs the counts are what
+// would be deleted. Runs that delete anything are stored, with the audit chain
+// heads from before and after the rebuild, so they can be signed and reviewed.
+type RetentionReport struct {
+	ID                     int64                  `json:"id,omitempty"`
+	AppliedAt              time.Time              `json:"applied_at"`
+	DryRun                 bool                   `json:"dry_run"`
+	Rules                  []*RetentionRuleResult `json:"rules"`
+	IssuesDeleted          int                    `json:"issues_deleted"`
+	EventsDeleted          int                    `json:"events_deleted"`
+	AuditChainRebuilt      bool                   `json:"audit_chain_rebuilt,omitempty"`
+	AuditSignaturesRemoved int                    `json:"audit_signatures_removed,omitempty"`
+	AuditHeadBefore        string                 `json:"audit_head_before,omitempty"`
+	AuditHeadAfter         string                 `json:"audit_head_after,omitempty"`
+	Method                 string                 `json:"method,omitempty"`
+	Signer                 string                 `json:"signer,omitempty"`
+	Signature              string                 `json:"signature,omitempty"`
+}
+
+// SigningPayload returns the canonical JSON of the report without signature fields
+func (r *RetentionReport) SigningPayload() string {
+	c := *r
+	c.ID, c.Method, c.Signer, c.Signature = 0, "", "", ""
+	data, _ := json.Marshal(&c)
+	return string(data)
+}
+
+// ParseRetentionAge parses ages like "90d", "12w", "6mo", or "2y" into days.
+// Months count as 30 days and years as 365.
+func ParseRetentionAge(s string) (int, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	units := []struct {
+		suffix string
+		days   int
+	}{
+		{"mo", 30}, {"d", 1}, {"w", 7}, {"m", 30}, {"y", 365},
+	}
+	for _, u := range units {
+		if strings.HasSuffix(s, u.suffix) {
+			n, err := strconv.Atoi(strings.TrimSuffix(s, u.suffix))
+			if err != nil || n <= 0 {
+				break
+			}
+			return n * u.days, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid age %q (use e.g. 90d, 12w, 6mo, 2y)", s)
+}
+
+// AddRetentionRule validates and stores a retention rule
+func (s *SQLiteStorage) AddRetentionRule(ctx context.Context, rule *RetentionRule) error {
+	switch rule.Target {
+	case RetentionTargetIssues:
+		if rule.EventType != "" {
+			return fmt.Errorf("event_type only applies to event rules")
+		}
+		if rule.Status == "" {
+			rule.Status = string(types.StatusClosed)
+		}
+	case RetentionTargetEvents:
+		if rule.IssueType != "" || rule.Status != "" {
+			return fmt.Errorf("issue_type and status only apply to issue rules")
+		}
+	default:
+		return fmt.Errorf("invalid retention target %q (must be %s or %s)", rule.Target, RetentionTargetIssues, RetentionTargetEvents)
+	}
+	if rule.MaxAgeDays <= 0 {
+		return fmt.Errorf("max age must be positive")
+	}
+
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO retention_rules (target, issue_type, status, event_type, max_age_days)
+		VALUES (?, ?, ?, ?, ?)
+	`, rule.Target, rule.IssueType, rule.Status, rule.EventType, rule.MaxAgeDays)
+	if err != nil {
+		return fmt.Errorf("failed to add retention rule: %w", err)
+	}
+	rule.ID, err = res.LastInsertId()
+	return err
+}
+
+// GetRetentionRules returns all retention rules in creation order
+func (s *SQLiteStorage) GetRetentionRules(ctx context.Context) ([]*RetentionRule, error) {
+	rows, err := s.reads.QueryContext(ctx, `
+		SELECT id, target, issue_type, status, event_type, max_age_days, created_at
+		FROM retention_rules ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query retention rules: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var rules []*RetentionRule
+	for rows.Next() {
+		var r RetentionRule
+		if err := rows.Scan(&r.ID, &r.Target, &r.IssueType, &r.Status, &r.EventType, &r.MaxAgeDays, &r.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan retention rule: %w", err)
+		}
+		rules = append(rules, &r)
+	}
+	return rules, rows.Err()
+}
+
+// RemoveRetentionRule deletes a retention rule by ID
+func (s *SQLiteStorage) RemoveRetentionRule(ctx context.Context, id int64) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM retention_rules WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to remove retention rule: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return types.NotFound("retention rule", id)
+	}
+	return nil
+}
+
+// ApplyRetention evaluates every retention rule and hard-deletes the matching
+// issues and events in one transaction. With dryRun, it only reports what
+// would be removed. The audit chain, if in use, is rebuilt afterwards, and the
+// report of a run that deleted anything is stored with the chain heads.
+func (s *SQLiteStorage) ApplyRetention(ctx context.Context, dryRun bool) (*RetentionReport, error) {
+	rules, err := s.GetRetentionRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	report := &RetentionReport{AppliedAt: time.Now().UTC(), DryRun: dryRun}
+	deleteIDs := make(map[string]bool)
+	var orderedIDs []string
+
+	for _, rule := range rules {
+		result := &RetentionRuleResult{Rule: rule}
+		report.Rules = append(report.Rules, result)
+
+		switch rule.Target {
+		case RetentionTargetIssues:
+			ids, err := matchRetentionIssues(ctx, tx, rule)
+			if err != nil {
+				return nil, err
+			}
+			result.IssueIDs = ids
+			for _, id := range ids {
+				if !deleteIDs[id] {
+					deleteIDs[id] = true
+					orderedIDs = append(orderedIDs, id)
+				}
+			}
+
+		case RetentionTargetEvents:
+			where, args := retentionEventsWhere(rule)
+			// #nosec G201 - where clause built from fixed column names
+			query := "SELECT COUNT(*) FROM events WHERE " + where
+			if dryRun {
+				if err := tx.QueryRowContext(ctx, query, args...).Scan(&result.Events); err != nil {
+					return nil, fmt.Errorf("failed to count events for rule %d: %w", rule.ID, err)
+				}
+				report.EventsDeleted += result.Events
+				continue
+			}
+			// #nosec G201 - where clause built from fixed column names
+			res, err := tx.ExecContext(ctx, "DELETE FROM events WHERE "+where, args...)
+			if err != nil {
+				return nil, fmt.Errorf("failed to purge events for rule %d: %w", rule.ID, err)
+			}
+			n, _ := res.RowsAffected()
+			result.Events = int(n)
+			report.EventsDeleted += int(n)
+		}
+	}
+
+	if dryRun {
+		report.IssuesDeleted = len(orderedIDs)
+		return report, nil
+	}
+
+	if len(orderedIDs) > 0 {
+		inClause, args := buildSQLInClause(orderedIDs)
+		var deleted DeleteIssuesResult
+		if err := s.executeDelete(ctx, tx, inClause, args, &deleted); err != nil {
+			return nil, err
+		}
+		report.IssuesDeleted = deleted.DeletedCount
+	}
+
+	if report.IssuesDeleted > 0 || report.EventsDeleted > 0 {
+		_, _, before, err := getAuditHead(ctx, tx)
+		if err != nil {
+			return nil, err
+		}
+		rebuilt, removed, err := rebuildAuditChain(ctx, tx)
+		if err != nil {
+			return nil, err
+		}
+		if rebuilt {
+			_, _, after, err := getAuditHead(ctx, tx)
+			if err != nil {
+				return nil, err
+			}
+			report.AuditChainRebuilt = true
+			report.AuditSignaturesRemoved = removed
+			report.AuditHeadBefore = before
+			report.AuditHeadAfter = after
+		}
+
+		res, err := tx.ExecContext(ctx, `INSERT INTO retention_reports (report) VALUES (?)`, report.SigningPayload())
+		if err != nil {
+			return nil, fmt.Errorf("failed to store retention report: %w", err)
+		}
+		if report.ID, err = res.LastInsertId(); err != nil {
+			return nil, fmt.Errorf("failed to get retention report ID: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit retention: %w", err)
+	}
+
+	if report.IssuesDeleted > 0 {
+		if err := s.SyncAllCounters(ctx); err != nil {
+			return nil, fmt.Errorf("failed to sync counters after retention: %w", err)
+		}
+	}
+	return report, nil
+}
+
+// SignRetentionReport attaches a signature over the report's signing payload
+func (s *SQLiteStorage) SignRetentionReport(ctx context.Context, report *RetentionReport) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE retention_reports SET method = ?, signer = ?, signature = ? WHERE id = ?
+	`, report.Method, report.Signer, report.Signature, report.ID)
+	if err != nil {
+		return fmt.Errorf("failed to sign retention report: %w", err)
+	}
+	return nil
+}
+
+// GetRetentionReports returns stored retention reports, newest first
+func (s *SQLiteStorage) GetRetentionReports(ctx context.Context) ([]*RetentionReport, error) {
+	rows, err := s.reads.QueryContext(ctx, `
+		SELECT id, report, method, signer, signature FROM retention_reports ORDER BY id DESC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query retention reports: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var reports []*RetentionReport
+	for rows.Next() {
+		var id int64
+		var payload, method, signer, signature string
+		if err := rows.Scan(&id, &payload, &method, &signer, &signature); err != nil {
+			return nil, fmt.Errorf("failed to scan retention report: %w", err)
+		}
+		var r RetentionReport
+		if err := json.Unmarshal([]byte(payload), &r); err != nil {
+			return nil, fmt.Errorf("malformed retention report %d: %w", id, err)
+		}
+		r.ID, r.Method, r.Signer, r.Signature = id, method, signer, signature
+		reports = append(reports, &r)
+	}
+	return reports, rows.Err()
+}
+
+func matchRetentionIssues(ctx context.Context, tx *sql.Tx, rule *RetentionRule) ([]string, error) {
+	where := []string{`datetime(COALESCE(closed_at, updated_at)) <= datetime('now', '-' || CAST(? AS INTEGER) || ' days')`}
+	// Rules stored before status defaulted to closed get the same default
+	status := rule.Status
+	if status == "" {
+		status = string(types.StatusClosed)
+	}
+	where = append(where, "status = ?")
+	args := []interface{}{rule.MaxAgeDays, status}
+	if rule.IssueType != "" {
+		where = append(where, "issue_type = ?")
+		args = append(args, rule.IssueType)
+	}
+
+	// #nosec G201 - where clause built from fixed column names
+	rows, err := tx.QueryContext(ctx, "SELECT id FROM issues WHERE "+strings.Join(where, " AND ")+" ORDER BY id", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to match issues for rule %d: %w", rule.ID, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func retentionEventsWhere(rule *RetentionRule) (string, []interface{}) {
+	where := []string{`datetime(created_at) <= datetime('now', '-' || CAST(? AS INTEGER) || ' days')`}
+	args := []interface{}{rule.MaxAgeDays}
+	if rule.EventType != "" {
+		where = append(where, "event_type = ?")
+		args = append(args, rule.EventType)
+	}
+	return strings.Join(where, " AND "), args
+}
@@ -0,0 +1,228 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestParseRetentionAge(t *testing.T) {
+	tests := map[string]int{"90d": 90, "12w": 84, "6mo": 180, "6m": 180, "2y": 730, " 1Y ": 365}
+	for in, want := range tests {
+		got, err := ParseRetentionAge(in)
+		if err != nil || got != want {
+			t.Errorf("ParseRetentionAge(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "d", "0d", "-5d", "10", "3h"} {
+		if _, err := ParseRetentionAge(in); err == nil {
+			t.Errorf("ParseRetentionAge(%q) should fail", in)
+		}
+	}
+}
+
+func TestAddRetentionRuleValidation(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	bad := []*RetentionRule{
+		{Target: "labels", MaxAgeDays: 1},
+		{Target: RetentionTargetIssues, MaxAgeDays: 0},
+		{Target: RetentionTargetIssues, EventType: "commented", MaxAgeDays: 1},
+		{Target: RetentionTargetEvents, Status: "closed", MaxAgeDays: 1},
+	}
+	for _, r := range bad {
+		if err := store.AddRetentionRule(ctx, r); err == nil {
+			t.Errorf("expected rule %+v to be rejected", r)
+		}
+	}
+
+	rule := &RetentionRule{Target: RetentionTargetIssues, Status: "closed", MaxAgeDays: 30}
+	if err := store.AddRetentionRule(ctx, rule); err != nil {
+		t.Fatalf("AddRetentionRule failed: %v", err)
+	}
+	if err := store.RemoveRetentionRule(ctx, rule.ID); err != nil {
+		t.Fatalf("RemoveRetentionRule failed: %v", err)
+	}
+	if err := store.RemoveRetentionRule(ctx, rule.ID); err == nil {
+		t.Error("expected removing a missing rule to fail")
+	}
+}
+
+func TestApplyRetention(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+	db := store.UnderlyingDB()
+
+	newIssue := func(title string, issueType types.IssueType) *types.Issue {
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 2, IssueType: issueType}
+		if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		return issue
+	}
+	oldChore := newIssue("Old chore", types.TypeChore)
+	recentChore := newIssue("Recent chore", types.TypeChore)
+	oldBug := newIssue("Old bug", types.TypeBug)
+	openChore := newIssue("Open chore", types.TypeChore)
+	// The recent chore is closed in a session, whose ID the audit chain hashes
+	session := &AgentSession{Actor: "alice"}
+	if err := store.StartSession(ctx, session); err != nil {
+		t.Fatalf("StartSession failed: %v", err)
+	}
+	for _, issue := range []*types.Issue{oldChore, recentChore, oldBug} {
+		closeCtx := ctx
+		if issue == recentChore {
+			closeCtx = WithSession(ctx, session.ID)
+		}
+		if err := store.CloseIssue(closeCtx, issue.ID, "done", "alice"); err != nil {
+			t.Fatalf("CloseIssue failed: %v", err)
+		}
+	}
+
+	// Age the data
+	for _, id := range []string{oldChore.ID, oldBug.ID} {
+		if _, err := db.Exec(`UPDATE issues SET closed_at = datetime('now', '-800 days') WHERE id = ?`, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := db.Exec(`UPDATE issues SET updated_at = datetime('now', '-800 days') WHERE id = ?`, openChore.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`UPDATE events SET created_at = datetime('now', '-400 days') WHERE issue_id = ? AND event_type = 'created'`, oldBug.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.SealAuditChain(ctx); err != nil {
+		t.Fatalf("SealAuditChain failed: %v", err)
+	}
+
+	rules := []*RetentionRule{
+		{Target: RetentionTargetIssues, Status: "closed", IssueType: "chore", MaxAgeDays: 730},
+		{Target: RetentionTargetEvents, MaxAgeDays: 365},
+	}
+	for _, r := range rules {
+		if err := store.AddRetentionRule(ctx, r); err != nil {
+			t.Fatalf("AddRetentionRule failed: %v", err)
+		}
+	}
+
+	// Preview changes nothing
+	preview, err := store.ApplyRetention(ctx, true)
+	if err != nil {
+		t.Fatalf("preview failed: %v", err)
+	}
+	if preview.IssuesDeleted != 1 || preview.Rules[0].IssueIDs[0] != oldChore.ID || preview.EventsDeleted != 1 {
+		t.Fatalf("unexpected preview: %+v", preview)
+	}
+	if got, _ := store.GetIssue(ctx, oldChore.ID); got == nil {
+		t.Fatal("preview deleted an issue")
+	}
+	if reports, _ := store.GetRetentionReports(ctx); len(reports) != 0 {
+		t.Fatalf("preview stored a report: %+v", reports)
+	}
+
+	report, err := store.ApplyRetention(ctx, false)
+	if err != nil {
+		t.Fatalf("ApplyRetention failed: %v", err)
+	}
+	if report.IssuesDeleted != 1 || report.EventsDeleted != 1 || !report.AuditChainRebuilt {
+		t.Errorf("unexpected report: %+v", report)
+	}
+
+	if got, _ := store.GetIssue(ctx, oldChore.ID); got != nil {
+		t.Error("expected old closed chore to be deleted")
+	}
+	for _, id := range []string{recentChore.ID, oldBug.ID, openChore.ID} {
+		if got, _ := store.GetIssue(ctx, id); got == nil {
+			t.Errorf("expected %s to be retained", id)
+		}
+	}
+
+	verify, err := store.VerifyAuditChain(ctx)
+	if err != nil {
+		t.Fatalf("VerifyAuditChain failed: %v", err)
+	}
+	if !verify.Valid {
+		t.Errorf("expected valid audit chain after retention, got %+v", verify.Problems)
+	}
+
+	// The run is stored with the chain heads on either side of the rebuild
+	_, _, head, err := getAuditHead(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.AuditHeadBefore == "" || report.AuditHeadBefore == report.AuditHeadAfter || report.AuditHeadAfter != head {
+		t.Errorf("expected heads %q -> %q, current head %q", report.AuditHeadBefore, report.AuditHeadAfter, head)
+	}
+	report.Method, report.Signer, report.Signature = "gpg", "ops@example.com", "sig"
+	if err := store.SignRetentionReport(ctx, report); err != nil {
+		t.Fatalf("SignRetentionReport failed: %v", err)
+	}
+	reports, err := store.GetRetentionReports(ctx)
+	if err != nil {
+		t.Fatalf("GetRetentionReports failed: %v", err)
+	}
+	if len(reports) != 1 || reports[0].ID != report.ID || reports[0].Signature != "sig" {
+		t.Fatalf("unexpected stored reports: %+v", reports)
+	}
+	if reports[0].SigningPayload() != report.SigningPayload() {
+		t.Error("stored report payload does not match returned report")
+	}
+}
+
+func TestRetentionRuleWithoutStatus(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+	db := store.UnderlyingDB()
+
+	newIssue := func(title string) *types.Issue {
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeChore}
+		if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		return issue
+	}
+	oldClosed := newIssue("Old closed chore")
+	oldOpen := newIssue("Old open chore")
+	if err := store.CloseIssue(ctx, oldClosed.ID, "done", "alice"); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE issues SET closed_at = datetime('now', '-800 days') WHERE id = ?`, oldClosed.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`UPDATE issues SET updated_at = datetime('now', '-800 days')`); err != nil {
+		t.Fatal(err)
+	}
+
+	rule := &RetentionRule{Target: RetentionTargetIssues, IssueType: "chore", MaxAgeDays: 730}
+	if err := store.AddRetentionRule(ctx, rule); err != nil {
+		t.Fatalf("AddRetentionRule failed: %v", err)
+	}
+	if rule.Status != string(types.StatusClosed) {
+		t.Errorf("expected the rule to default to closed issues, got %q", rule.Status)
+	}
+
+	// A rule stored without a status, before the default, behaves the same
+	if _, err := db.Exec(`INSERT INTO retention_rules (target, issue_type, status, event_type, max_age_days) VALUES ('issues', '', '', '', 730)`); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := store.ApplyRetention(ctx, false)
+	if err != nil {
+		t.Fatalf("ApplyRetention failed: %v", err)
+	}
+	if report.IssuesDeleted != 1 {
+		t.Errorf("expected only the closed issue deleted, got %+v", report)
+	}
+	if got, _ := store.GetIssue(ctx, oldClosed.ID); got != nil {
+		t.Error("expected the old closed issue to be deleted")
+	}
+	if got, _ := store.GetIssue(ctx, oldOpen.ID); got == nil {
+		t.Error("expected the old open issue to survive")
+	}
+}
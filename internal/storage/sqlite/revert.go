@@ -0,0 +1,195 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// ErrNothingToRevert is returned by RevertLastChange when the actor has no
+// change left to undo
+var ErrNothingToRevert = errors.New("nothing to revert")
+
+// RevertConflictError is returned when a field was changed again after the
+// revision being reverted, so undoing it would overwrite someone's later edit
+type RevertConflictError struct {
+	IssueID  string
+	Revision int
+	Field    string
+}
+
+func (e *RevertConflictError) Error() string {
+	return fmt.Sprintf("cannot revert revision %d of %s: %s has changed since", e.Revision, e.IssueID, e.Field)
+}
+
+// revertibleKinds are the revisions RevertLastChange can undo
+var revertibleKinds = []types.EventType{
+	types.EventUpdated, types.EventStatusChanged, types.EventClosed, types.EventReopened,
+	types.EventLabelAdded, types.EventLabelRemoved,
+}
+
+// RevertLastChange undoes the most recent update, close, or label change by
+// actor that hasn't been reverted yet, restoring the prior values in one
+// transaction. With an empty issueID it looks across all issues. The undo is
+// itself recorded as a "reverted" revision; reverts are never undone, so
+// repeated calls walk further back.
+func (s *SQLiteStorage) RevertLastChange(ctx context.Context, issueID, actor string) (*IssueRevision, error) {
+	target, err := s.lastRevertible(ctx, issueID, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.GetIssue(ctx, target.IssueID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, types.NotFound("issue", target.IssueID)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	switch target.Kind {
+	case types.EventLabelAdded, types.EventLabelRemoved:
+		if err := s.revertLabelChange(ctx, tx, current, target, actor); err != nil {
+			return nil, err
+		}
+	default:
+		updates, err := revertUpdates(current, target)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.updateIssueInTx(ctx, tx, current, updates, actor, target.Revision); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	history, err := s.GetIssueHistory(ctx, target.IssueID)
+	if err != nil {
+		return nil, err
+	}
+	return history[len(history)-1], nil
+}
+
+// lastRevertible finds the newest revision by actor that RevertLastChange can undo
+func (s *SQLiteStorage) lastRevertible(ctx context.Context, issueID, actor string) (*IssueRevision, error) {
+	placeholders := make([]string, len(revertibleKinds))
+	args := []interface{}{actor}
+	for i, kind := range revertibleKinds {
+		placeholders[i] = "?"
+		args = append(args, kind)
+	}
+	issueClause := ""
+	if issueID != "" {
+		issueClause = " AND h.issue_id = ?"
+		args = append(args, issueID)
+	}
+
+	// #nosec G201 - only placeholders are interpolated
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM issue_history h
+		WHERE h.actor = ? AND h.kind IN (%s)%s
+		  AND NOT EXISTS (
+			SELECT 1 FROM issue_history r WHERE r.issue_id = h.issue_id AND r.reverts = h.revision
+		  )
+		ORDER BY h.id DESC
+		LIMIT 1
+	`, revisionColumns, strings.Join(placeholders, ","), issueClause)
+	rev, err := s.scanRevision(s.reads.QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, ErrNothingToRevert
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find change to revert: %w", err)
+	}
+	return rev, nil
+}
+
+// revertUpdates builds the updates that restore target's old values,
+// checking that each field still holds the value target gave it
+func revertUpdates(current *types.Issue, target *IssueRevision) (map[string]interface{}, error) {
+	var now map[string]interface{}
+	data, err := json.Marshal(current)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &now); err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]interface{})
+	for _, c := range target.Changes {
+		// closed_at follows status
+		if !allowedUpdateFields[c.Field] {
+			continue
+		}
+		if diffFields(map[string]interface{}{c.Field: now[c.Field]}, map[string]interface{}{c.Field: c.New}) != nil {
+			return nil, &RevertConflictError{IssueID: target.IssueID, Revision: target.Revision, Field: c.Field}
+		}
+		updates[c.Field] = restoredValue(c.Field, c.Old)
+	}
+	if len(updates) == 0 {
+		return nil, fmt.Errorf("revision %d of %s has no fields to restore", target.Revision, target.IssueID)
+	}
+	return updates, nil
+}
+
+// restoredValue converts a value decoded from a revision's JSON back to the
+// type UpdateIssue expects for field
+func restoredValue(field string, v interface{}) interface{} {
+	switch field {
+	case "priority":
+		if n, ok := v.(float64); ok {
+			return int(n)
+		}
+		return 0
+	case "estimated_minutes":
+		if n, ok := v.(float64); ok {
+			return int(n)
+		}
+		return nil
+	default:
+		if str, ok := v.(string); ok {
+			return str
+		}
+		return ""
+	}
+}
+
+// revertLabelChange re-adds a removed label or removes an added one
+func (s *SQLiteStorage) revertLabelChange(ctx context.Context, tx *sql.Tx, current *types.Issue, target *IssueRevision, actor string) error {
+	if len(target.Changes) != 1 {
+		return fmt.Errorf("revision %d of %s has no label change", target.Revision, target.IssueID)
+	}
+	change := target.Changes[0]
+	added := target.Kind == types.EventLabelAdded
+	label, _ := change.New.(string)
+	if !added {
+		label, _ = change.Old.(string)
+	}
+
+	var present int
+	err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM labels WHERE issue_id = ? AND label = ?`, current.ID, label).Scan(&present)
+	if err != nil {
+		return fmt.Errorf("failed to check label: %w", err)
+	}
+	if (present > 0) != added {
+		return &RevertConflictError{IssueID: target.IssueID, Revision: target.Revision, Field: "label " + label}
+	}
+
+	return s.labelOperationInTx(ctx, tx, current.ID, label, actor, !added, target.Revision)
+}
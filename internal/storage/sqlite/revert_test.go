@@ -0,0 +1,81 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestRevertLastChange(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	issue := &types.Issue{Title: "Login fails", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeBug}
+	if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	if _, err := store.RevertLastChange(ctx, issue.ID, "alice"); !errors.Is(err, ErrNothingToRevert) {
+		t.Fatalf("Expected ErrNothingToRevert for a new issue, got %v", err)
+	}
+
+	if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"priority": 0, "assignee": "bob"}, "alice"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	if err := store.AddLabel(ctx, issue.ID, "auth", "alice"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+	if err := store.CloseIssue(ctx, issue.ID, "Fixed", "alice"); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+
+	// Undo the close
+	rev, err := store.RevertLastChange(ctx, "", "alice")
+	if err != nil {
+		t.Fatalf("RevertLastChange failed: %v", err)
+	}
+	if rev.Kind != types.EventReverted || rev.Reverts != 4 {
+		t.Errorf("Expected revert of revision 4, got %+v", rev)
+	}
+	got, _ := store.GetIssue(ctx, issue.ID)
+	if got.Status != types.StatusOpen || got.ClosedAt != nil {
+		t.Errorf("Expected issue reopened, got status %s closed_at %v", got.Status, got.ClosedAt)
+	}
+
+	// Then the label
+	if _, err := store.RevertLastChange(ctx, issue.ID, "alice"); err != nil {
+		t.Fatalf("RevertLastChange failed: %v", err)
+	}
+	if labels, _ := store.GetLabels(ctx, issue.ID); len(labels) != 0 {
+		t.Errorf("Expected label removed, got %v", labels)
+	}
+
+	// Someone else changes the priority; undoing alice's update would clobber it
+	if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"priority": 1}, "carol"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	_, err = store.RevertLastChange(ctx, issue.ID, "alice")
+	var conflict *RevertConflictError
+	if !errors.As(err, &conflict) || conflict.Field != "priority" {
+		t.Fatalf("Expected priority conflict, got %v", err)
+	}
+
+	// With carol's change undone, alice's update reverts cleanly
+	if _, err := store.RevertLastChange(ctx, issue.ID, "carol"); err != nil {
+		t.Fatalf("RevertLastChange failed: %v", err)
+	}
+	if _, err := store.RevertLastChange(ctx, issue.ID, "alice"); err != nil {
+		t.Fatalf("RevertLastChange failed: %v", err)
+	}
+	got, _ = store.GetIssue(ctx, issue.ID)
+	if got.Priority != 2 || got.Assignee != "" {
+		t.Errorf("Expected priority 2 and no assignee, got %d %q", got.Priority, got.Assignee)
+	}
+
+	if _, err := store.RevertLastChange(ctx, issue.ID, "alice"); !errors.Is(err, ErrNothingToRevert) {
+		t.Errorf("Expected nothing left to revert, got %v", err)
+	}
+}
@@ -1,5 +1,8 @@
 package sqlite
 
+// schema is the baseline migration: the tables as they stood before
+// versioned migrations. Change the schema by adding a migration to
+// migrations/ rather than editing it here (see migrations.go).
 const schema = `
 -- Issues table
 CREATE TABLE IF NOT EXISTS issues (
@@ -22,6 +25,11 @@ CREATE TABLE IF NOT EXISTS issues (
     compacted_at DATETIME,
     compacted_at_commit TEXT,
     original_size INTEGER,
+    deleted_at DATETIME,
+    deleted_by TEXT,
+    due_date DATETIME,
+    start_date DATETIME,
+    milestone TEXT,
     CHECK ((status = 'closed') = (closed_at IS NOT NULL))
 );
 
@@ -162,6 +170,301 @@ CREATE TABLE IF NOT EXISTS compaction_snapshots (
 
 CREATE INDEX IF NOT EXISTS idx_comp_snap_issue_level_created ON compaction_snapshots(issue_id, compaction_level, created_at DESC);
 
+-- Audit chain table (tamper-evident hash chain over events)
+-- Deliberately has no foreign key to events so that deleted events are detectable
+CREATE TABLE IF NOT EXISTS audit_chain (
+    seq INTEGER PRIMARY KEY AUTOINCREMENT,
+    event_id INTEGER NOT NULL UNIQUE,
+    event_hash TEXT NOT NULL,
+    prev_hash TEXT NOT NULL,
+    chain_hash TEXT NOT NULL,
+    sealed_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Audit signatures table (signed chain heads)
+CREATE TABLE IF NOT EXISTS audit_signatures (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    head_seq INTEGER NOT NULL,
+    head_hash TEXT NOT NULL,
+    method TEXT NOT NULL,
+    signer TEXT NOT NULL DEFAULT '',
+    signature TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_signatures_head ON audit_signatures(head_seq);
+
+-- Secret redactions table (credentials detected in issue text on write)
+-- Only a short preview is kept; the secret itself is never stored here
+CREATE TABLE IF NOT EXISTS secret_redactions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    issue_id TEXT NOT NULL,
+    field TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    action TEXT NOT NULL,
+    preview TEXT NOT NULL DEFAULT '',
+    actor TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_secret_redactions_issue ON secret_redactions(issue_id);
+
+-- Retention rules table (age-based hard deletion of issues and events)
+CREATE TABLE IF NOT EXISTS retention_rules (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    target TEXT NOT NULL CHECK(target IN ('issues', 'events')),
+    issue_type TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT '',
+    event_type TEXT NOT NULL DEFAULT '',
+    max_age_days INTEGER NOT NULL CHECK(max_age_days > 0),
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Escalation rules table (priority aging of issues left open)
+CREATE TABLE IF NOT EXISTS escalation_rules (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    issue_type TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT '',
+    priority INTEGER,
+    min_age_days INTEGER NOT NULL CHECK(min_age_days > 0),
+    set_priority INTEGER,
+    add_label TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Purge reports table (signed records of actor data erasure)
+-- The purged actor is identified only by a hash of their name
+CREATE TABLE IF NOT EXISTS purge_reports (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    subject_hash TEXT NOT NULL,
+    report TEXT NOT NULL,
+    method TEXT NOT NULL DEFAULT '',
+    signer TEXT NOT NULL DEFAULT '',
+    signature TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Webhooks table (URLs that receive issue events)
+-- events is a comma-separated list of event names; empty means all
+CREATE TABLE IF NOT EXISTS webhooks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL DEFAULT '',
+    events TEXT NOT NULL DEFAULT '',
+    created_by TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- External mappings table (links issues to issues in other trackers such as GitHub)
+-- synced_hash fingerprints the fields both sides share, as of the last sync
+CREATE TABLE IF NOT EXISTS external_mappings (
+    system TEXT NOT NULL,
+    external_id TEXT NOT NULL,
+    issue_id TEXT NOT NULL,
+    synced_hash TEXT NOT NULL DEFAULT '',
+    external_updated_at DATETIME,
+    synced_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (system, external_id),
+    UNIQUE (system, issue_id),
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+);
+
+-- Issue commits table (git commits whose messages mention an issue)
+CREATE TABLE IF NOT EXISTS issue_commits (
+    issue_id TEXT NOT NULL,
+    sha TEXT NOT NULL,
+    message TEXT NOT NULL DEFAULT '',
+    author TEXT NOT NULL DEFAULT '',
+    url TEXT NOT NULL DEFAULT '',
+    committed_at DATETIME,
+    linked_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (issue_id, sha),
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+);
+
+-- Work logs table (time spent on an issue, one row per entry)
+CREATE TABLE IF NOT EXISTS work_logs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    issue_id TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    minutes INTEGER NOT NULL CHECK(minutes > 0),
+    note TEXT NOT NULL DEFAULT '',
+    logged_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_work_logs_issue ON work_logs(issue_id);
+
+-- Stale nudges table (when each quiet in-progress issue was last nudged)
+CREATE TABLE IF NOT EXISTS stale_nudges (
+    issue_id TEXT PRIMARY KEY,
+    nudged_at DATETIME NOT NULL,
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+);
+
+-- Digest subscriptions table (periodic email summaries, one per actor)
+CREATE TABLE IF NOT EXISTS digest_subscriptions (
+    actor TEXT PRIMARY KEY,
+    email TEXT NOT NULL,
+    frequency TEXT NOT NULL DEFAULT 'daily',
+    last_sent_at DATETIME,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- API keys table (bd serve credentials; only a SHA-256 hash of each token is kept)
+CREATE TABLE IF NOT EXISTS api_keys (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    role TEXT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    hint TEXT NOT NULL DEFAULT '',
+    created_by TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_at DATETIME
+);
+
+-- Issue history: one row per revision, written with each change
+CREATE TABLE IF NOT EXISTS issue_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    issue_id TEXT NOT NULL,
+    revision INTEGER NOT NULL,
+    kind TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    changes TEXT NOT NULL DEFAULT '[]',
+    comment TEXT NOT NULL DEFAULT '',
+    reverts INTEGER,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (issue_id, revision),
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+);
+
+-- Archive: closed issues hidden from default listings by bd archive run
+CREATE TABLE IF NOT EXISTS archived_issues (
+    issue_id TEXT PRIMARY KEY,
+    archived_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    archived_by TEXT NOT NULL DEFAULT '',
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+);
+
+-- Idempotency keys: the first response to each Idempotency-Key, replayed on retries
+-- scope separates callers (the API key ID, or empty); request_hash fingerprints the request
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    scope TEXT NOT NULL,
+    key TEXT NOT NULL,
+    request_hash TEXT NOT NULL,
+    status_code INTEGER NOT NULL,
+    content_type TEXT NOT NULL DEFAULT '',
+    body BLOB NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (scope, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created ON idempotency_keys(created_at);
+
+-- Milestones table (time-boxed sprints that issues are assigned to by name)
+-- capacity_minutes is the estimated work the milestone can hold
+CREATE TABLE IF NOT EXISTS milestones (
+    name TEXT PRIMARY KEY,
+    start_date DATETIME NOT NULL,
+    end_date DATETIME NOT NULL,
+    capacity_minutes INTEGER NOT NULL DEFAULT 0,
+    created_by TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Label definitions table (metadata for labels, which issues still carry by name)
+-- parent places a label in the hierarchy; path-style names like infra/db
+-- default to their path prefix
+CREATE TABLE IF NOT EXISTS label_definitions (
+    name TEXT PRIMARY KEY,
+    color TEXT NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    parent TEXT NOT NULL DEFAULT '',
+    created_by TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_label_definitions_parent ON label_definitions(parent);
+
+-- Issue leases table (time-limited claims on work, one per issue)
+CREATE TABLE IF NOT EXISTS issue_leases (
+    issue_id TEXT PRIMARY KEY,
+    holder TEXT NOT NULL,
+    claimed_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL,
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_issue_leases_expires ON issue_leases(expires_at);
+
+-- Issue redirects table (old IDs left behind by prefix renames)
+CREATE TABLE IF NOT EXISTS issue_redirects (
+    old_id TEXT PRIMARY KEY,
+    new_id TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Remote dependencies table (dependencies on issues in other workspaces,
+-- which the dependencies table's foreign key can't hold)
+CREATE TABLE IF NOT EXISTS remote_dependencies (
+    issue_id TEXT NOT NULL,
+    depends_on_ref TEXT NOT NULL,
+    type TEXT NOT NULL DEFAULT 'blocks',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by TEXT NOT NULL,
+    PRIMARY KEY (issue_id, depends_on_ref),
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+);
+
+-- Full-text index over issue text and comments (kept in sync by triggers)
+-- issue_id is stored but not tokenized; all other columns are searchable
+CREATE VIRTUAL TABLE IF NOT EXISTS issues_fts USING fts5(
+    issue_id UNINDEXED,
+    title,
+    description,
+    design,
+    acceptance_criteria,
+    notes,
+    comments,
+    tokenize = 'porter unicode61'
+);
+
+CREATE TRIGGER IF NOT EXISTS issues_fts_insert AFTER INSERT ON issues BEGIN
+    INSERT INTO issues_fts (issue_id, title, description, design, acceptance_criteria, notes, comments)
+    VALUES (new.id, new.title, new.description, new.design, new.acceptance_criteria, new.notes,
+            (SELECT COALESCE(GROUP_CONCAT(text, ' '), '') FROM comments WHERE issue_id = new.id));
+END;
+
+CREATE TRIGGER IF NOT EXISTS issues_fts_update
+AFTER UPDATE OF id, title, description, design, acceptance_criteria, notes ON issues BEGIN
+    DELETE FROM issues_fts WHERE issue_id = old.id;
+    INSERT INTO issues_fts (issue_id, title, description, design, acceptance_criteria, notes, comments)
+    VALUES (new.id, new.title, new.description, new.design, new.acceptance_criteria, new.notes,
+            (SELECT COALESCE(GROUP_CONCAT(text, ' '), '') FROM comments WHERE issue_id = new.id));
+END;
+
+CREATE TRIGGER IF NOT EXISTS issues_fts_delete AFTER DELETE ON issues BEGIN
+    DELETE FROM issues_fts WHERE issue_id = old.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS comments_fts_insert AFTER INSERT ON comments BEGIN
+    UPDATE issues_fts
+    SET comments = (SELECT COALESCE(GROUP_CONCAT(text, ' '), '') FROM comments WHERE issue_id = new.issue_id)
+    WHERE issue_id = new.issue_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS comments_fts_update AFTER UPDATE ON comments BEGIN
+    UPDATE issues_fts
+    SET comments = (SELECT COALESCE(GROUP_CONCAT(text, ' '), '') FROM comments WHERE issue_id = new.issue_id)
+    WHERE issue_id = new.issue_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS comments_fts_delete AFTER DELETE ON comments BEGIN
+    UPDATE issues_fts
+    SET comments = (SELECT COALESCE(GROUP_CONCAT(text, ' '), '') FROM comments WHERE issue_id = old.issue_id)
+    WHERE issue_id = old.issue_id;
+END;
+
 -- Ready work view (with hierarchical blocking)
 -- Uses recursive CTE to propagate blocking through parent-child hierarchy
 CREATE VIEW IF NOT EXISTS ready_issues AS
@@ -0,0 +1,221 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// Default highlight markers wrapped around matched terms in snippets
+const (
+	DefaultHighlightStart = "["
+	DefaultHighlightEnd   = "]"
+)
+
+// snippetTokens is the approximate number of tokens in each snippet
+const snippetTokens = 12
+
+// searchWeights are the bm25 column weights for issues_fts, in declaration order:
+// issue_id (not tokenized), title, description, design, acceptance_criteria,
+// notes, comments
+const searchWeights = "0.0, 10.0, 4.0, 2.0, 2.0, 2.0, 1.0"
+
+// plaintextSearchColumns are the columns that are never encrypted. When field
+// encryption is enabled the others hold ciphertext, so matches are limited to these.
+var plaintextSearchColumns = []string{"title", "design", "acceptance_criteria"}
+
+// SearchOptions controls a full-text search
+type SearchOptions struct {
+	Filter         types.IssueFilter // structured filters applied alongside the text match
+	HighlightStart string            // inserted before each matched term (default "[")
+	HighlightEnd   string            // inserted after each matched term (default "]")
+}
+
+// SearchHit is a single full-text search result
+type SearchHit struct {
+	Issue   *types.Issue `json:"issue"`
+	Score   float64      `json:"score"`   // relevance; higher is better
+	Snippet string       `json:"snippet"` // best-matching fragment with terms highlighted
+}
+
+// FullTextSearch finds issues whose title, description, design, acceptance
+// criteria, notes, or comments match query, ordered by relevance. Title
+// matches weigh most, comments least.
+//
+// Each whitespace-separated word must match (as a prefix, with stemming);
+// "double quoted" text matches as a phrase.
+func (s *SQLiteStorage) FullTextSearch(ctx context.Context, query string, opts SearchOptions) ([]*SearchHit, error) {
+	ctx, span := startSpan(ctx, "FullTextSearch")
+	defer span.End()
+
+	match, err := buildMatchQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	if s.encryptionRequired {
+		match = fmt.Sprintf("{%s} : (%s)", strings.Join(plaintextSearchColumns, " "), match)
+	}
+
+	start, end := opts.HighlightStart, opts.HighlightEnd
+	if start == "" && end == "" {
+		start, end = DefaultHighlightStart, DefaultHighlightEnd
+	}
+
+	args := []interface{}{start, end, match}
+	whereClauses := []string{"issues_fts MATCH ?"}
+
+	filterClauses, filterArgs := issueFilterClauses(opts.Filter, "i.")
+	whereClauses = append(whereClauses, filterClauses...)
+	args = append(args, filterArgs...)
+
+	limitSQL := ""
+	if opts.Filter.Limit > 0 {
+		limitSQL = limitClause
+		args = append(args, opts.Filter.Limit)
+	}
+
+	// Best matches first unless the filter asks for another order
+	order := "rank, " + issueOrderBy(nil, "i.")
+	if len(opts.Filter.Sort) > 0 {
+		order = issueOrderBy(opts.Filter.Sort, "i.")
+	}
+
+	// #nosec G201 - safe SQL with controlled formatting
+	querySQL := fmt.Sprintf(`
+		SELECT i.id, i.title, i.description, i.design, i.acceptance_criteria, i.notes,
+		       i.status, i.priority, i.issue_type, i.assignee, i.estimated_minutes,
+		       i.created_at, i.updated_at, i.closed_at, i.external_ref,
+		       i.due_date, i.start_date, i.milestone,
+		       bm25(issues_fts, %s) AS rank,
+		       snippet(issues_fts, -1, ?, ?, '…', %d)
+		FROM issues_fts
+		JOIN issues i ON i.id = issues_fts.issue_id
+		WHERE %s
+		ORDER BY %s
+		%s
+	`, searchWeights, snippetTokens, strings.Join(whereClauses, " AND "), order, limitSQL)
+
+	// The snippet markers are bound before the MATCH argument, matching their
+	// position in the query text
+	rows, err := s.reads.QueryContext(ctx, querySQL, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search issues: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var hits []*SearchHit
+	for rows.Next() {
+		var issue types.Issue
+		var closedAt sql.NullTime
+		var estimatedMinutes sql.NullInt64
+		var assignee sql.NullString
+		var externalRef sql.NullString
+		var dueDate, startDate sql.NullTime
+		var milestone sql.NullString
+		var rank float64
+		var snippet string
+
+		err := rows.Scan(
+			&issue.ID, &issue.Title, &issue.Description, &issue.Design,
+			&issue.AcceptanceCriteria, &issue.Notes, &issue.Status,
+			&issue.Priority, &issue.IssueType, &assignee, &estimatedMinutes,
+			&issue.CreatedAt, &issue.UpdatedAt, &closedAt, &externalRef,
+			&dueDate, &startDate, &milestone,
+			&rank, &snippet,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+
+		if closedAt.Valid {
+			issue.ClosedAt = &closedAt.Time
+		}
+		if estimatedMinutes.Valid {
+			mins := int(estimatedMinutes.Int64)
+			issue.EstimatedMinutes = &mins
+		}
+		if assignee.Valid {
+			issue.Assignee = assignee.String
+		}
+		if externalRef.Valid {
+			issue.ExternalRef = &externalRef.String
+		}
+		if dueDate.Valid {
+			issue.DueDate = &dueDate.Time
+		}
+		if startDate.Valid {
+			issue.StartDate = &startDate.Time
+		}
+		if milestone.Valid {
+			issue.Milestone = milestone.String
+		}
+		s.decryptIssueFields(&issue)
+
+		// bm25 is negative with more relevant matches lower; flip it so callers
+		// can treat higher scores as better. Snippets are shown on one line.
+		hits = append(hits, &SearchHit{Issue: &issue, Score: -rank, Snippet: strings.Join(strings.Fields(snippet), " ")})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read search results: %w", err)
+	}
+	_ = rows.Close()
+
+	issues := make([]*types.Issue, len(hits))
+	for i, hit := range hits {
+		issues[i] = hit.Issue
+	}
+	if err := s.populateLabels(ctx, issues); err != nil {
+		return nil, err
+	}
+	if opts.Filter.WithCounts {
+		if err := s.populateCounts(ctx, issues); err != nil {
+			return nil, err
+		}
+	}
+
+	return hits, nil
+}
+
+// buildMatchQuery turns free-form user input into an FTS5 MATCH expression.
+// Every term is quoted so punctuation in the input (hyphens, colons, stray
+// operators) can never produce a syntax error. Bare words match as prefixes;
+// quoted phrases match exactly.
+func buildMatchQuery(query string) (string, error) {
+	var terms []string
+	var current strings.Builder
+	inPhrase := false
+
+	flush := func(phrase bool) {
+		text := strings.TrimSpace(current.String())
+		current.Reset()
+		if text == "" {
+			return
+		}
+		quoted := `"` + strings.ReplaceAll(text, `"`, `""`) + `"`
+		if !phrase {
+			quoted += "*"
+		}
+		terms = append(terms, quoted)
+	}
+
+	for _, r := range query {
+		switch {
+		case r == '"':
+			flush(inPhrase)
+			inPhrase = !inPhrase
+		case !inPhrase && (r == ' ' || r == '\t' || r == '\n'):
+			flush(false)
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush(inPhrase)
+
+	if len(terms) == 0 {
+		return "", fmt.Errorf("search query is empty")
+	}
+	return strings.Join(terms, " "), nil
+}
@@ -0,0 +1,77 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// BenchmarkSearchIssues lists every issue of a project where each has two
+// labels, a dependency, and a comment. Labels, parents, and counts are
+// loaded with a query per 500 issues, so time per issue should stay flat as
+// the project grows: if 2000 issues take more than twice as long as 1000, a
+// per-issue query crept back in.
+func BenchmarkSearchIssues_1000(b *testing.B) {
+	benchmarkSearchIssues(b, 1000, types.IssueFilter{})
+}
+
+func BenchmarkSearchIssues_2000(b *testing.B) {
+	benchmarkSearchIssues(b, 2000, types.IssueFilter{})
+}
+
+// BenchmarkSearchIssues_WithCounts_1000 adds dependency and comment counts,
+// as GET /issues asks for
+func BenchmarkSearchIssues_WithCounts_1000(b *testing.B) {
+	benchmarkSearchIssues(b, 1000, types.IssueFilter{WithCounts: true})
+}
+
+func benchmarkSearchIssues(b *testing.B, n int, filter types.IssueFilter) {
+	store, cleanup := setupBenchDB(b)
+	defer cleanup()
+	ctx := context.Background()
+	if err := store.SetConfig(ctx, "issue_prefix", "bench"); err != nil {
+		b.Fatalf("Failed to set issue_prefix: %v", err)
+	}
+
+	issues := make([]*types.Issue, n)
+	for i := range issues {
+		issues[i] = &types.Issue{
+			Title:     fmt.Sprintf("Issue %d", i),
+			Status:    types.StatusOpen,
+			Priority:  i % 5,
+			IssueType: types.TypeTask,
+		}
+	}
+	if err := store.CreateIssues(ctx, issues, "benchmark"); err != nil {
+		b.Fatalf("Failed to create issues: %v", err)
+	}
+	for i, issue := range issues {
+		for _, label := range []string{"team-" + fmt.Sprint(i%4), "area-" + fmt.Sprint(i%7)} {
+			if err := store.AddLabel(ctx, issue.ID, label, "benchmark"); err != nil {
+				b.Fatalf("Failed to add label: %v", err)
+			}
+		}
+		if i > 0 {
+			dep := &types.Dependency{IssueID: issue.ID, DependsOnID: issues[i-1].ID, Type: types.DepRelated}
+			if err := store.AddDependency(ctx, dep, "benchmark"); err != nil {
+				b.Fatalf("Failed to add dependency: %v", err)
+			}
+		}
+		if _, err := store.AddIssueComment(ctx, issue.ID, "benchmark", "Noted"); err != nil {
+			b.Fatalf("Failed to add comment: %v", err)
+		}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		found, err := store.SearchIssues(ctx, "", filter)
+		if err != nil {
+			b.Fatalf("SearchIssues failed: %v", err)
+		}
+		if len(found) != n || len(found[0].Labels) != 2 {
+			b.Fatalf("Expected %d labeled issues, got %d", n, len(found))
+		}
+	}
+}
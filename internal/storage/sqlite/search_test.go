@@ -0,0 +1,217 @@
+package sqlite
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestBuildMatchQuery(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "login", want: `"login"*`},
+		{in: "login  timeout", want: `"login"* "timeout"*`},
+		{in: `"connection reset" db`, want: `"connection reset" "db"*`},
+		{in: `bd-12 foo:bar`, want: `"bd-12"* "foo:bar"*`},
+		{in: `say "hi`, want: `"say"* "hi"`},
+		{in: `a"b`, want: `"a"* "b"`},
+		{in: "   ", wantErr: true},
+		{in: `""`, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := buildMatchQuery(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("buildMatchQuery(%q) expected error, got %q", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("buildMatchQuery(%q) unexpected error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("buildMatchQuery(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFullTextSearch(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	inTitle := &types.Issue{Title: "Websocket reconnect storms", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeBug}
+	inNotes := &types.Issue{Title: "Client polish", Notes: "Reconnecting loops forever after websocket close", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	inDesign := &types.Issue{Title: "Transport layer", Design: "Use exponential backoff on reconnect", Status: types.StatusClosed, Priority: 1, IssueType: types.TypeFeature}
+	unrelated := &types.Issue{Title: "Docs typo", Description: "Fix spelling", Status: types.StatusOpen, Priority: 3, IssueType: types.TypeChore}
+	for _, issue := range []*types.Issue{inTitle, inNotes, inDesign, unrelated} {
+		if issue.Status == types.StatusClosed {
+			now := issue.CreatedAt
+			issue.ClosedAt = &now
+		}
+		if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+
+	hits, err := store.FullTextSearch(ctx, "reconnect", SearchOptions{})
+	if err != nil {
+		t.Fatalf("FullTextSearch failed: %v", err)
+	}
+	if len(hits) != 3 {
+		t.Fatalf("expected 3 hits, got %d", len(hits))
+	}
+	if hits[0].Issue.ID != inTitle.ID {
+		t.Errorf("title match should rank first, got %s", hits[0].Issue.ID)
+	}
+	for i := 1; i < len(hits); i++ {
+		if hits[i].Score > hits[i-1].Score {
+			t.Errorf("hits not ordered by score: %v > %v", hits[i].Score, hits[i-1].Score)
+		}
+	}
+	if !strings.Contains(hits[0].Snippet, "[Websocket]") && !strings.Contains(hits[0].Snippet, "[reconnect]") {
+		t.Errorf("expected highlighted snippet, got %q", hits[0].Snippet)
+	}
+
+	// Structured filters apply alongside the text match
+	status := types.StatusOpen
+	hits, err = store.FullTextSearch(ctx, "reconnect", SearchOptions{Filter: types.IssueFilter{Status: &status}})
+	if err != nil {
+		t.Fatalf("FullTextSearch failed: %v", err)
+	}
+	if len(hits) != 2 {
+		t.Errorf("expected 2 open hits, got %d", len(hits))
+	}
+
+	// Custom highlight markers
+	hits, err = store.FullTextSearch(ctx, "backoff", SearchOptions{HighlightStart: "<mark>", HighlightEnd: "</mark>"})
+	if err != nil {
+		t.Fatalf("FullTextSearch failed: %v", err)
+	}
+	if len(hits) != 1 || !strings.Contains(hits[0].Snippet, "<mark>backoff</mark>") {
+		t.Errorf("expected <mark> highlight, got %+v", hits)
+	}
+
+	// Punctuation in user input must never surface as an FTS syntax error
+	for _, q := range []string{"- reconnect", "c++", "title:reconnect", "reconnect OR", "(backoff"} {
+		if _, err := store.FullTextSearch(ctx, q, SearchOptions{}); err != nil {
+			t.Errorf("FullTextSearch(%q) failed: %v", q, err)
+		}
+	}
+
+	if _, err := store.FullTextSearch(ctx, "  ", SearchOptions{}); err == nil {
+		t.Error("expected error for empty query")
+	}
+}
+
+func TestFullTextSearchTracksChanges(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	issue := &types.Issue{Title: "Cache layer", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	search := func(q string) int {
+		t.Helper()
+		hits, err := store.FullTextSearch(ctx, q, SearchOptions{})
+		if err != nil {
+			t.Fatalf("FullTextSearch(%q) failed: %v", q, err)
+		}
+		return len(hits)
+	}
+
+	if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"description": "eviction is broken under memory pressure"}, "tester"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	if search("eviction") != 1 {
+		t.Error("updated description should be searchable")
+	}
+
+	if _, err := store.AddIssueComment(ctx, issue.ID, "tester", "reproduced with a thundering herd"); err != nil {
+		t.Fatalf("AddIssueComment failed: %v", err)
+	}
+	if search("thundering herd") != 1 || search(`"thundering herd"`) != 1 {
+		t.Error("comments should be searchable")
+	}
+
+	if err := store.DeleteIssue(ctx, issue.ID); err != nil {
+		t.Fatalf("DeleteIssue failed: %v", err)
+	}
+	if search("cache") != 0 {
+		t.Error("deleted issues should drop out of the index")
+	}
+}
+
+func TestMigrateFullTextIndex(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	issue := &types.Issue{Title: "Legacy import path", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	// Simulate a database that predates the index
+	if _, err := store.db.Exec(`DELETE FROM issues_fts`); err != nil {
+		t.Fatalf("failed to clear index: %v", err)
+	}
+	if err := migrateFullTextIndex(store.db); err != nil {
+		t.Fatalf("migrateFullTextIndex failed: %v", err)
+	}
+
+	hits, err := store.FullTextSearch(ctx, "legacy", SearchOptions{})
+	if err != nil {
+		t.Fatalf("FullTextSearch failed: %v", err)
+	}
+	if len(hits) != 1 {
+		t.Errorf("expected backfilled index to find the issue, got %d hits", len(hits))
+	}
+}
+
+func TestFullTextSearchWithFieldEncryption(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	issue := &types.Issue{
+		Title:       "Rotate signing keys",
+		Description: "secret rollout plan",
+		Design:      "staged rollout",
+		Status:      types.StatusOpen,
+		Priority:    1,
+		IssueType:   types.TypeTask,
+	}
+	if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	if _, err := store.EnableFieldEncryption(ctx, newTestKeyProvider(t)); err != nil {
+		t.Fatalf("EnableFieldEncryption failed: %v", err)
+	}
+
+	// Encrypted fields hold ciphertext, so only plaintext columns are matched
+	hits, err := store.FullTextSearch(ctx, "plan", SearchOptions{})
+	if err != nil {
+		t.Fatalf("FullTextSearch failed: %v", err)
+	}
+	if len(hits) != 0 {
+		t.Errorf("encrypted description should not be searchable, got %d hits", len(hits))
+	}
+
+	hits, err = store.FullTextSearch(ctx, "rollout", SearchOptions{})
+	if err != nil {
+		t.Fatalf("FullTextSearch failed: %v", err)
+	}
+	if len(hits) != 1 || strings.Contains(hits[0].Snippet, "secret") {
+		t.Errorf("expected one design match without description text, got %+v", hits)
+	}
+}
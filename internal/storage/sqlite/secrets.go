@@ -0,0 +1,160 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/secrets"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// configSecretsMode selects how detected secrets are handled (off, flag, mask, reject)
+const configSecretsMode = "secrets.mode"
+
+// secretScannedFields lists the free-text issue fields checked for secrets
+var secretScannedFields = []string{"description", "design", "acceptance_criteria", "notes"}
+
+// SecretRedaction records a secret detected in issue text on write
+type SecretRedaction struct {
+	ID        int64     `json:"id"`
+	IssueID   string    `json:"issue_id"`
+	Field     string    `json:"field"`
+	Kind      string    `json:"kind"`
+	Action    string    `json:"action"`
+	Preview   string    `json:"preview"`
+	Actor     string    `json:"actor"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// dbExecer is satisfied by *sql.DB, *sql.Tx, and *sql.Conn
+type dbExecer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+type fieldFinding struct {
+	field   string
+	finding secrets.Finding
+}
+
+// secretScan applies the configured secrets mode to the fields of a single write
+type secretScan struct {
+	mode     secrets.Mode
+	findings []fieldFinding
+}
+
+func (s *SQLiteStorage) newSecretScan(ctx context.Context) (*secretScan, error) {
+	value, err := s.GetConfig(ctx, configSecretsMode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", configSecretsMode, err)
+	}
+	mode, err := secrets.ParseMode(value)
+	if err != nil {
+		return nil, err
+	}
+	return &secretScan{mode: mode}, nil
+}
+
+// apply returns the value to store for a field, or an error if the write must be rejected
+func (sc *secretScan) apply(field, value string) (string, error) {
+	if sc.mode == secrets.ModeOff || value == "" {
+		return value, nil
+	}
+	masked, findings := secrets.Mask(value)
+	if len(findings) == 0 {
+		return value, nil
+	}
+	if sc.mode == secrets.ModeReject {
+		return "", &secrets.DetectedError{Field: field, Kinds: secrets.Kinds(findings)}
+	}
+	for _, f := range findings {
+		sc.findings = append(sc.findings, fieldFinding{field: field, finding: f})
+	}
+	if sc.mode == secrets.ModeMask {
+		return masked, nil
+	}
+	return value, nil
+}
+
+// applyToIssue scans the free-text fields of an issue, masking them in place if configured
+func (sc *secretScan) applyToIssue(issue *types.Issue) error {
+	fields := map[string]*string{
+		"description":         &issue.Description,
+		"design":              &issue.Design,
+		"acceptance_criteria": &issue.AcceptanceCriteria,
+		"notes":               &issue.Notes,
+	}
+	for _, name := range secretScannedFields {
+		v, err := sc.apply(name, *fields[name])
+		if err != nil {
+			return err
+		}
+		*fields[name] = v
+	}
+	return nil
+}
+
+// applyToUpdates scans string values of free-text fields in an update map
+func (sc *secretScan) applyToUpdates(updates map[string]interface{}) error {
+	for _, name := range secretScannedFields {
+		text, ok := updates[name].(string)
+		if !ok {
+			continue
+		}
+		v, err := sc.apply(name, text)
+		if err != nil {
+			return err
+		}
+		updates[name] = v
+	}
+	return nil
+}
+
+// record writes the accumulated findings to the redaction report
+func (sc *secretScan) record(ctx context.Context, exec dbExecer, issueID, actor string) error {
+	for _, ff := range sc.findings {
+		_, err := exec.ExecContext(ctx, `
+			INSERT INTO secret_redactions (issue_id, field, kind, action, preview, actor)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, issueID, ff.field, ff.finding.Kind, string(sc.mode), ff.finding.Preview, actor)
+		if err != nil {
+			return fmt.Errorf("failed to record secret redaction: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetSecretRedactions returns detected secrets, newest first. An empty issueID returns all.
+func (s *SQLiteStorage) GetSecretRedactions(ctx context.Context, issueID string, limit int) ([]*SecretRedaction, error) {
+	query := `
+		SELECT id, issue_id, field, kind, action, preview, actor, created_at
+		FROM secret_redactions
+	`
+	var args []interface{}
+	if issueID != "" {
+		query += ` WHERE issue_id = ?`
+		args = append(args, issueID)
+	}
+	query += ` ORDER BY id DESC`
+	if limit > 0 {
+		query += limitClause
+		args = append(args, limit)
+	}
+
+	rows, err := s.reads.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query secret redactions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var redactions []*SecretRedaction
+	for rows.Next() {
+		var r SecretRedaction
+		if err := rows.Scan(&r.ID, &r.IssueID, &r.Field, &r.Kind, &r.Action, &r.Preview, &r.Actor, &r.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan secret redaction: %w", err)
+		}
+		redactions = append(redactions, &r)
+	}
+	return redactions, rows.Err()
+}
@@ -0,0 +1,221 @@
+package sqlite
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AgentSession is one run of an agent working on the tracker. Every change
+// made in the session records its ID, so GetSessionActivity can show
+// everything the run did.
+type AgentSession struct {
+	ID        string     `json:"id"`
+	Actor     string     `json:"actor"`
+	Model     string     `json:"model,omitempty"`
+	Tool      string     `json:"tool,omitempty"`
+	RunID     string     `json:"run_id,omitempty" doc:"The agent's own ID for the run, e.g. a CI job or trace ID"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+	Changes   int        `json:"changes"` // events recorded in the session
+}
+
+// SessionFilter narrows ListSessions. Active keeps sessions that haven't ended.
+type SessionFilter struct {
+	Actor  string
+	Active bool
+	Limit  int
+}
+
+// SessionActivity is a session and the changes made in it, newest first
+type SessionActivity struct {
+	Session    *AgentSession `json:"session"`
+	Entries    []*AuditEntry `json:"entries"`
+	NextCursor int64         `json:"next_cursor,omitempty"`
+}
+
+var (
+	// ErrSessionNotFound is returned for an unknown session ID
+	ErrSessionNotFound = errors.New("session not found")
+
+	// ErrSessionEnded is returned when writing in a session that has ended
+	ErrSessionEnded = errors.New("session has ended")
+
+	// ErrSessionNotOwned is returned when one actor writes in another's session
+	ErrSessionNotOwned = errors.New("session belongs to another actor")
+)
+
+type sessionKey struct{}
+
+// WithSession tags the changes made with ctx as part of an agent session
+func WithSession(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, sessionKey{}, id)
+}
+
+// SetSession tags changes made with contexts that carry no session as part
+// of session id, for clients like the CLI that run one actor per process.
+// Call before using the store.
+func (s *SQLiteStorage) SetSession(id string) {
+	s.session = id
+}
+
+// sessionID returns the session a change made with ctx belongs to, or ""
+func (s *SQLiteStorage) sessionID(ctx context.Context) string {
+	if id, _ := ctx.Value(sessionKey{}).(string); id != "" {
+		return id
+	}
+	return s.session
+}
+
+// eventSession is sessionID as an events.session_id value
+func (s *SQLiteStorage) eventSession(ctx context.Context) interface{} {
+	return nullSession(s.sessionID(ctx))
+}
+
+// nullSession stores an empty session ID as NULL
+func nullSession(id string) interface{} {
+	if id == "" {
+		return nil
+	}
+	return id
+}
+
+// StartSession records a new session for session.Actor, filling in its ID
+// and start time
+func (s *SQLiteStorage) StartSession(ctx context.Context, session *AgentSession) error {
+	session.Actor = strings.TrimSpace(session.Actor)
+	if session.Actor == "" {
+		return fmt.Errorf("session actor is required")
+	}
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Errorf("failed to generate session ID: %w", err)
+	}
+	session.ID = "sess-" + hex.EncodeToString(b)
+	session.StartedAt = time.Now()
+	session.EndedAt = nil
+	session.Changes = 0
+
+	if _, err := s.db.ExecContext(ctx, `
+		INSERT INTO agent_sessions (id, actor, model, tool, run_id, started_at) VALUES (?, ?, ?, ?, ?, ?)
+	`, session.ID, session.Actor, session.Model, session.Tool, session.RunID, session.StartedAt); err != nil {
+		return fmt.Errorf("failed to start session: %w", err)
+	}
+	return nil
+}
+
+// GetSession returns a session with its change count, or ErrSessionNotFound
+func (s *SQLiteStorage) GetSession(ctx context.Context, id string) (*AgentSession, error) {
+	sessions, err := s.querySessions(ctx, `WHERE a.id = ?`, []interface{}{id}, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(sessions) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrSessionNotFound, id)
+	}
+	return sessions[0], nil
+}
+
+// ListSessions returns sessions newest first
+func (s *SQLiteStorage) ListSessions(ctx context.Context, filter SessionFilter) ([]*AgentSession, error) {
+	var where []string
+	var args []interface{}
+	if filter.Actor != "" {
+		where = append(where, "a.actor = ?")
+		args = append(args, filter.Actor)
+	}
+	if filter.Active {
+		where = append(where, "a.ended_at IS NULL")
+	}
+	whereSQL := ""
+	if len(where) > 0 {
+		whereSQL = "WHERE " + strings.Join(where, " AND ")
+	}
+	return s.querySessions(ctx, whereSQL, args, filter.Limit)
+}
+
+// CheckSession returns an error unless actor can write in session id: it
+// must exist, be the actor's, and not have ended
+func (s *SQLiteStorage) CheckSession(ctx context.Context, id, actor string) error {
+	session, err := s.GetSession(ctx, id)
+	if err != nil {
+		return err
+	}
+	if session.Actor != actor {
+		return fmt.Errorf("%w: %s is %s's", ErrSessionNotOwned, id, session.Actor)
+	}
+	if session.EndedAt != nil {
+		return fmt.Errorf("%w: %s", ErrSessionEnded, id)
+	}
+	return nil
+}
+
+// EndSession marks a session ended. Ending it again is a no-op.
+func (s *SQLiteStorage) EndSession(ctx context.Context, id string) (*AgentSession, error) {
+	if _, err := s.GetSession(ctx, id); err != nil {
+		return nil, err
+	}
+	if _, err := s.db.ExecContext(ctx, `
+		UPDATE agent_sessions SET ended_at = ? WHERE id = ? AND ended_at IS NULL
+	`, time.Now(), id); err != nil {
+		return nil, fmt.Errorf("failed to end session %s: %w", id, err)
+	}
+	return s.GetSession(ctx, id)
+}
+
+// GetSessionActivity returns a session and a page of the changes made in
+// it, newest first, with field-level diffs. filter is as for QueryAuditLog;
+// its Session is set to id.
+func (s *SQLiteStorage) GetSessionActivity(ctx context.Context, id string, filter AuditFilter) (*SessionActivity, error) {
+	session, err := s.GetSession(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	filter.Session = id
+	page, err := s.QueryAuditLog(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &SessionActivity{Session: session, Entries: page.Entries, NextCursor: page.NextCursor}, nil
+}
+
+func (s *SQLiteStorage) querySessions(ctx context.Context, where string, args []interface{}, limit int) ([]*AgentSession, error) {
+	limitSQL := ""
+	if limit > 0 {
+		limitSQL = limitClause
+		args = append(args, limit)
+	}
+
+	// #nosec G202 - where is built from fixed clauses
+	rows, err := s.reads.QueryContext(ctx, `
+		SELECT a.id, a.actor, a.model, a.tool, a.run_id, a.started_at, a.ended_at,
+		       (SELECT COUNT(*) FROM events e WHERE e.session_id = a.id)
+		FROM agent_sessions a
+		`+where+`
+		ORDER BY a.started_at DESC, a.id
+		`+limitSQL, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sessions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var sessions []*AgentSession
+	for rows.Next() {
+		var session AgentSession
+		var endedAt sql.NullTime
+		if err := rows.Scan(&session.ID, &session.Actor, &session.Model, &session.Tool, &session.RunID,
+			&session.StartedAt, &endedAt, &session.Changes); err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		if endedAt.Valid {
+			session.EndedAt = &endedAt.Time
+		}
+		sessions = append(sessions, &session)
+	}
+	return sessions, rows.Err()
+}
@@ -0,0 +1,100 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestAgentSessions(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	session := &AgentSession{Actor: "agent-1", Model: "m1", Tool: "ci", RunID: "job-42"}
+	if err := store.StartSession(ctx, session); err != nil {
+		t.Fatalf("StartSession failed: %v", err)
+	}
+	if err := store.CheckSession(ctx, session.ID, "agent-1"); err != nil {
+		t.Errorf("Expected the owner to write in the session, got %v", err)
+	}
+	if err := store.CheckSession(ctx, session.ID, "bob"); !errors.Is(err, ErrSessionNotOwned) {
+		t.Errorf("Expected ErrSessionNotOwned, got %v", err)
+	}
+	if err := store.CheckSession(ctx, "sess-missing", "agent-1"); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Expected ErrSessionNotFound, got %v", err)
+	}
+
+	// Changes made with the session in ctx are tagged; others aren't
+	sessCtx := WithSession(ctx, session.ID)
+	issue := &types.Issue{Title: "Fix it", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(sessCtx, issue, "agent-1"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	if err := store.UpdateIssue(sessCtx, issue.ID, map[string]interface{}{"priority": 1}, "agent-1"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	if err := store.AddLabel(ctx, issue.ID, "triaged", "bob"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+
+	activity, err := store.GetSessionActivity(ctx, session.ID, AuditFilter{})
+	if err != nil {
+		t.Fatalf("GetSessionActivity failed: %v", err)
+	}
+	if activity.Session.Changes != 2 || len(activity.Entries) != 2 {
+		t.Fatalf("Expected the create and update in the session, got %+v", activity)
+	}
+	if e := activity.Entries[0]; e.EventType != types.EventUpdated || e.SessionID != session.ID || len(e.Changes) != 1 {
+		t.Errorf("Expected the priority change first, got %+v", e)
+	}
+	events, _ := store.GetEvents(ctx, issue.ID, 0)
+	for _, e := range events {
+		if tagged := e.SessionID == session.ID; tagged == (e.Actor == "bob") {
+			t.Errorf("Expected only agent-1's events tagged, got %+v", e)
+		}
+	}
+
+	// A store-level session tags changes whose ctx carries none
+	store.SetSession(session.ID)
+	if err := store.AddComment(ctx, issue.ID, "agent-1", "Done"); err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+	store.SetSession("")
+	if got, _ := store.GetSession(ctx, session.ID); got.Changes != 3 {
+		t.Errorf("Expected 3 changes in the session, got %d", got.Changes)
+	}
+
+	// Session IDs are part of the audit chain
+	if _, err := store.SealAuditChain(ctx); err != nil {
+		t.Fatalf("SealAuditChain failed: %v", err)
+	}
+	if _, err := store.db.Exec(`UPDATE events SET session_id = NULL WHERE actor = 'agent-1'`); err != nil {
+		t.Fatal(err)
+	}
+	if verify, _ := store.VerifyAuditChain(ctx); verify.Valid {
+		t.Error("Expected untagging history to break the audit chain")
+	}
+
+	ended, err := store.EndSession(ctx, session.ID)
+	if err != nil {
+		t.Fatalf("EndSession failed: %v", err)
+	}
+	if ended.EndedAt == nil {
+		t.Error("Expected the session ended")
+	}
+	if err := store.CheckSession(ctx, session.ID, "agent-1"); !errors.Is(err, ErrSessionEnded) {
+		t.Errorf("Expected ErrSessionEnded, got %v", err)
+	}
+	if again, err := store.EndSession(ctx, session.ID); err != nil || !again.EndedAt.Equal(*ended.EndedAt) {
+		t.Errorf("Expected ending again to be a no-op, got %+v, %v", again, err)
+	}
+	if active, _ := store.ListSessions(ctx, SessionFilter{Active: true}); len(active) != 0 {
+		t.Errorf("Expected no active sessions, got %d", len(active))
+	}
+	if all, _ := store.ListSessions(ctx, SessionFilter{Actor: "agent-1"}); len(all) != 1 {
+		t.Errorf("Expected agent-1's one session, got %d", len(all))
+	}
+}
@@ -8,24 +8,46 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync/atomic"
 	"time"
 
 	// Import SQLite driver
+	"github.com/imalsogreg/beads/internal/fieldcrypt"
 	"github.com/imalsogreg/beads/internal/types"
+	"go.opentelemetry.io/otel/attribute"
 	_ "modernc.org/sqlite"
 )
 
 // SQLiteStorage implements the Storage interface using SQLite
 type SQLiteStorage struct {
-	db     *sql.DB
+	db     *sql.DB // Writes, on a single connection (see PoolConfig)
+	reads  *sql.DB // Read-only connections; the same as db for in-memory databases
+	ext    *sql.DB // Unrestricted pool for extensions (see UnderlyingDB); the same as db for in-memory databases
 	dbPath string
 	closed atomic.Bool // Tracks whether Close() has been called
+
+	// Field encryption (see field_encryption.go)
+	fieldCipher        *fieldcrypt.Cipher // nil unless the workspace data key is loaded
+	encryptionRequired bool               // true if the database has field encryption enabled
+
+	session string // Agent session for changes whose context names none (see SetSession)
 }
 
-// New creates a new SQLite storage backend
+// New creates a new SQLite storage backend with the default PoolConfig
 func New(path string) (*SQLiteStorage, error) {
+	return NewWithConfig(path, PoolConfig{})
+}
+
+// NewWithConfig creates a new SQLite storage backend with tuned connection
+// pools
+func NewWithConfig(path string, config PoolConfig) (*SQLiteStorage, error) {
+	config, err := config.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+
 	// Convert :memory: to shared memory URL for consistent behavior across connections
 	// SQLite creates separate in-memory databases for each connection to ":memory:",
 	// but "file::memory:?cache=shared" creates a shared in-memory database.
@@ -34,6 +56,22 @@ func New(path string) (*SQLiteStorage, error) {
 		dbPath = "file::memory:?cache=shared"
 	}
 
+	// A read-only storage can't create the database, and a replica is only
+	// ever read
+	if config.ReadOnly && !isMemoryPath(dbPath) {
+		if _, err := os.Stat(dbPath); err != nil {
+			return nil, fmt.Errorf("read-only database: %w", err)
+		}
+	}
+	if config.ReplicaPath != "" {
+		if isMemoryPath(dbPath) {
+			return nil, fmt.Errorf("in-memory databases can't have a replica")
+		}
+		if _, err := os.Stat(config.ReplicaPath); err != nil {
+			return nil, fmt.Errorf("replica database: %w", err)
+		}
+	}
+
 	// Ensure directory exists (skip for memory databases)
 	if !strings.Contains(dbPath, ":memory:") {
 		dir := filepath.Dir(dbPath)
@@ -43,20 +81,7 @@ func New(path string) (*SQLiteStorage, error) {
 	}
 
 	// Open database with WAL mode for better concurrency and busy timeout for parallel writes
-	// Use modernc.org/sqlite's _pragma syntax for all options to ensure consistent behavior
-	// _pragma=journal_mode(WAL) enables Write-Ahead Logging for better concurrency
-	// _pragma=foreign_keys(ON) enforces foreign key constraints
-	// _pragma=busy_timeout(30000) means wait up to 30 seconds for locks instead of failing immediately
-	// _time_format=sqlite enables automatic parsing of DATETIME columns to time.Time
-	// Note: For shared memory URLs, additional params need to be added with & not ?
-	connStr := dbPath
-	if strings.Contains(dbPath, "?") {
-		connStr += "&_pragma=journal_mode(WAL)&_pragma=foreign_keys(ON)&_pragma=busy_timeout(30000)&_time_format=sqlite"
-	} else {
-		connStr += "?_pragma=journal_mode(WAL)&_pragma=foreign_keys(ON)&_pragma=busy_timeout(30000)&_time_format=sqlite"
-	}
-
-	db, err := sql.Open("sqlite", connStr)
+	db, err := openWritePool(dbPath, config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -66,71 +91,137 @@ func New(path string) (*SQLiteStorage, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
+	if !config.ReadOnly {
+		if err := migrate(db); err != nil {
+			return nil, err
+		}
+	}
+
+	// Convert to absolute path for consistency
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	reads, ext := db, db
+	if !isMemoryPath(dbPath) {
+		readPath := dbPath
+		if config.ReplicaPath != "" {
+			readPath = config.ReplicaPath
+		}
+		if reads, err = openReadPool(readPath, config); err != nil {
+			return nil, fmt.Errorf("failed to open read connections: %w", err)
+		}
+		if ext, err = sql.Open("sqlite", config.connString(dbPath)); err != nil {
+			_ = reads.Close()
+			return nil, fmt.Errorf("failed to open database: %w", err)
+		}
+	}
+
+	s := &SQLiteStorage{
+		db:     db,
+		reads:  reads,
+		ext:    ext,
+		dbPath: absPath,
+	}
+
+	// Without migrations, a read-only storage needs a database that is
+	// already up to date
+	if config.ReadOnly {
+		if err := s.CheckSchema(context.Background()); err != nil {
+			_ = s.Close()
+			return nil, fmt.Errorf("%w; open it read-write once to migrate it", err)
+		}
+	}
+
+	// Load the field encryption key if this database has encryption enabled
+	if err := s.loadFieldEncryption(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to load field encryption key: %w", err)
+	}
+
+	return s, nil
+}
+
+// migrateBaseline creates the baseline schema and brings databases made by
+// versions from before versioned migrations up to it. Every step is
+// idempotent, so it is safe on a database at any of those versions.
+func migrateBaseline(db *sql.DB) error {
 	// Initialize schema
 	if _, err := db.Exec(schema); err != nil {
-		return nil, fmt.Errorf("failed to initialize schema: %w", err)
+		return fmt.Errorf("failed to initialize schema: %w", err)
 	}
 
 	// Migrate existing databases to add dirty_issues table if missing
 	if err := migrateDirtyIssuesTable(db); err != nil {
-		return nil, fmt.Errorf("failed to migrate dirty_issues table: %w", err)
+		return fmt.Errorf("failed to migrate dirty_issues table: %w", err)
 	}
 
 	// Migrate existing databases to add issue_counters table if missing
 	if err := migrateIssueCountersTable(db); err != nil {
-		return nil, fmt.Errorf("failed to migrate issue_counters table: %w", err)
+		return fmt.Errorf("failed to migrate issue_counters table: %w", err)
 	}
 
 	// Migrate existing databases to add external_ref column if missing
 	if err := migrateExternalRefColumn(db); err != nil {
-		return nil, fmt.Errorf("failed to migrate external_ref column: %w", err)
+		return fmt.Errorf("failed to migrate external_ref column: %w", err)
 	}
 
 	// Migrate existing databases to add composite index on dependencies
 	if err := migrateCompositeIndexes(db); err != nil {
-		return nil, fmt.Errorf("failed to migrate composite indexes: %w", err)
+		return fmt.Errorf("failed to migrate composite indexes: %w", err)
 	}
 
 	// Migrate existing databases to add status/closed_at CHECK constraint
 	if err := migrateClosedAtConstraint(db); err != nil {
-		return nil, fmt.Errorf("failed to migrate closed_at constraint: %w", err)
+		return fmt.Errorf("failed to migrate closed_at constraint: %w", err)
 	}
 
 	// Migrate existing databases to add compaction columns
 	if err := migrateCompactionColumns(db); err != nil {
-		return nil, fmt.Errorf("failed to migrate compaction columns: %w", err)
+		return fmt.Errorf("failed to migrate compaction columns: %w", err)
 	}
 
 	// Migrate existing databases to add issue_snapshots table
 	if err := migrateSnapshotsTable(db); err != nil {
-		return nil, fmt.Errorf("failed to migrate snapshots table: %w", err)
+		return fmt.Errorf("failed to migrate snapshots table: %w", err)
 	}
 
 	// Migrate existing databases to add compaction config defaults
 	if err := migrateCompactionConfig(db); err != nil {
-		return nil, fmt.Errorf("failed to migrate compaction config: %w", err)
+		return fmt.Errorf("failed to migrate compaction config: %w", err)
 	}
 
 	// Migrate existing databases to add compacted_at_commit column
 	if err := migrateCompactedAtCommitColumn(db); err != nil {
-		return nil, fmt.Errorf("failed to migrate compacted_at_commit column: %w", err)
+		return fmt.Errorf("failed to migrate compacted_at_commit column: %w", err)
 	}
 
 	// Migrate existing databases to add export_hashes table (bd-164)
 	if err := migrateExportHashesTable(db); err != nil {
-		return nil, fmt.Errorf("failed to migrate export_hashes table: %w", err)
+		return fmt.Errorf("failed to migrate export_hashes table: %w", err)
 	}
 
-	// Convert to absolute path for consistency
-	absPath, err := filepath.Abs(path)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	// Backfill the full-text index for databases created before it existed
+	if err := migrateFullTextIndex(db); err != nil {
+		return fmt.Errorf("failed to migrate full-text index: %w", err)
 	}
 
-	return &SQLiteStorage{
-		db:     db,
-		dbPath: absPath,
-	}, nil
+	// Migrate existing databases to add the trash columns
+	if err := migrateTrashColumns(db); err != nil {
+		return fmt.Errorf("failed to migrate trash columns: %w", err)
+	}
+
+	// Migrate existing databases to add the due and start date columns
+	if err := migrateScheduleColumns(db); err != nil {
+		return fmt.Errorf("failed to migrate schedule columns: %w", err)
+	}
+
+	// Migrate existing databases to add the milestone column
+	if err := migrateMilestoneColumn(db); err != nil {
+		return fmt.Errorf("failed to migrate milestone column: %w", err)
+	}
+
+	return nil
 }
 
 // migrateDirtyIssuesTable checks if the dirty_issues table exists and creates it if missing.
@@ -452,6 +543,7 @@ func migrateCompactionConfig(db *sql.DB) error {
 			('compact_model', 'claude-3-5-haiku-20241022'),
 			('compact_batch_size', '50'),
 			('compact_parallel_workers', '5'),
+			('compact_trash_days', '30'),
 			('auto_compact_enabled', 'false')
 	`)
 	if err != nil {
@@ -518,6 +610,130 @@ func migrateExportHashesTable(db *sql.DB) error {
 	return nil
 }
 
+// migrateFullTextIndex rebuilds issues_fts when it is out of step with the issues
+// table. The schema creates the index and its triggers, but databases that predate
+// them start with an empty index.
+func migrateFullTextIndex(db *sql.DB) error {
+	var issueCount, indexCount int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM issues`).Scan(&issueCount); err != nil {
+		return fmt.Errorf("failed to count issues: %w", err)
+	}
+	if err := db.QueryRow(`SELECT COUNT(*) FROM issues_fts`).Scan(&indexCount); err != nil {
+		return fmt.Errorf("failed to count indexed issues: %w", err)
+	}
+	if issueCount == indexCount {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(`DELETE FROM issues_fts`); err != nil {
+		return fmt.Errorf("failed to clear full-text index: %w", err)
+	}
+	_, err = tx.Exec(`
+		INSERT INTO issues_fts (issue_id, title, description, design, acceptance_criteria, notes, comments)
+		SELECT i.id, i.title, i.description, i.design, i.acceptance_criteria, i.notes,
+		       (SELECT COALESCE(GROUP_CONCAT(c.text, ' '), '') FROM comments c WHERE c.issue_id = i.id)
+		FROM issues i
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to populate full-text index: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// migrateTrashColumns adds the deleted_at and deleted_by columns used by the
+// trash (soft delete) to the issues table.
+// This migration is idempotent and safe to run multiple times.
+func migrateTrashColumns(db *sql.DB) error {
+	var columnExists bool
+	err := db.QueryRow(`
+		SELECT COUNT(*) > 0
+		FROM pragma_table_info('issues')
+		WHERE name = 'deleted_at'
+	`).Scan(&columnExists)
+	if err != nil {
+		return fmt.Errorf("failed to check deleted_at column: %w", err)
+	}
+
+	if !columnExists {
+		_, err = db.Exec(`
+			ALTER TABLE issues ADD COLUMN deleted_at DATETIME;
+			ALTER TABLE issues ADD COLUMN deleted_by TEXT;
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to add trash columns: %w", err)
+		}
+	}
+
+	_, err = db.Exec(`CREATE INDEX IF NOT EXISTS idx_issues_deleted_at ON issues(deleted_at)`)
+	if err != nil {
+		return fmt.Errorf("failed to create deleted_at index: %w", err)
+	}
+	return nil
+}
+
+// migrateScheduleColumns adds the due_date and start_date columns used for
+// time-based scheduling, if missing.
+func migrateScheduleColumns(db *sql.DB) error {
+	var columnExists bool
+	err := db.QueryRow(`
+		SELECT COUNT(*) > 0
+		FROM pragma_table_info('issues')
+		WHERE name = 'due_date'
+	`).Scan(&columnExists)
+	if err != nil {
+		return fmt.Errorf("failed to check due_date column: %w", err)
+	}
+
+	if !columnExists {
+		_, err = db.Exec(`
+			ALTER TABLE issues ADD COLUMN due_date DATETIME;
+			ALTER TABLE issues ADD COLUMN start_date DATETIME;
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to add schedule columns: %w", err)
+		}
+	}
+
+	_, err = db.Exec(`CREATE INDEX IF NOT EXISTS idx_issues_due_date ON issues(due_date)`)
+	if err != nil {
+		return fmt.Errorf("failed to create due_date index: %w", err)
+	}
+	return nil
+}
+
+// migrateMilestoneColumn adds the milestone column that assigns issues to
+// milestones, if missing.
+func migrateMilestoneColumn(db *sql.DB) error {
+	var columnExists bool
+	err := db.QueryRow(`
+		SELECT COUNT(*) > 0
+		FROM pragma_table_info('issues')
+		WHERE name = 'milestone'
+	`).Scan(&columnExists)
+	if err != nil {
+		return fmt.Errorf("failed to check milestone column: %w", err)
+	}
+
+	if !columnExists {
+		if _, err := db.Exec(`ALTER TABLE issues ADD COLUMN milestone TEXT`); err != nil {
+			return fmt.Errorf("failed to add milestone column: %w", err)
+		}
+	}
+
+	_, err = db.Exec(`CREATE INDEX IF NOT EXISTS idx_issues_milestone ON issues(milestone)`)
+	if err != nil {
+		return fmt.Errorf("failed to create milestone index: %w", err)
+	}
+	return nil
+}
+
 // getNextIDForPrefix atomically generates the next ID for a given prefix
 // Uses the issue_counters table for atomic, cross-process ID generation
 func (s *SQLiteStorage) getNextIDForPrefix(ctx context.Context, prefix string) (int, error) {
@@ -576,13 +792,48 @@ func (s *SQLiteStorage) SyncAllCounters(ctx context.Context) error {
 // The database should ALWAYS have issue_prefix config set explicitly (by 'bd init' or auto-import)
 // Never derive prefix from filename - it leads to silent data corruption
 
-// CreateIssue creates a new issue
+// CreateIssue creates a new issue and runs the triage rules on it
 func (s *SQLiteStorage) CreateIssue(ctx context.Context, issue *types.Issue, actor string) error {
+	ctx, span := startSpan(ctx, "CreateIssue")
+	defer span.End()
+
+	if err := s.createIssue(ctx, issue, actor); err != nil {
+		return err
+	}
+
+	// Run the triage rules on the new issue, reflecting what they set in it
+	result, err := s.triage(ctx, TriageOnCreate, nil, issue.ID)
+	if err != nil {
+		return fmt.Errorf("created %s, but %w", issue.ID, err)
+	}
+	result.applyTo(issue)
+	return nil
+}
+
+// createIssue inserts issue in its own transaction, releasing the write
+// connection before CreateIssue triages it
+func (s *SQLiteStorage) createIssue(ctx context.Context, issue *types.Issue, actor string) error {
 	// Validate issue before creating
-	if err := issue.Validate(); err != nil {
+	workflow, err := s.GetWorkflow(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.validateIssue(ctx, issue, workflow); err != nil {
 		return fmt.Errorf("validation failed: %w", err)
 	}
 
+	// Check free-text fields for pasted credentials
+	scan, err := s.newSecretScan(ctx)
+	if err != nil {
+		return err
+	}
+	if err := scan.applyToIssue(issue); err != nil {
+		return err
+	}
+	if issue.Assignee, err = s.ResolveActor(ctx, issue.Assignee); err != nil {
+		return err
+	}
+
 	// Set timestamps
 	now := time.Now()
 	issue.CreatedAt = now
@@ -674,38 +925,56 @@ func (s *SQLiteStorage) CreateIssue(ctx context.Context, issue *types.Issue, act
 		}
 	}
 
+	// Encrypt sensitive fields if field encryption is enabled
+	stored, err := s.encryptedIssueCopy(issue)
+	if err != nil {
+		return err
+	}
+
 	// Insert issue
 	_, err = conn.ExecContext(ctx, `
 		INSERT INTO issues (
 			id, title, description, design, acceptance_criteria, notes,
 			status, priority, issue_type, assignee, estimated_minutes,
-			created_at, updated_at, closed_at, external_ref
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			created_at, updated_at, closed_at, external_ref,
+			due_date, start_date, milestone
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''))
 	`,
-		issue.ID, issue.Title, issue.Description, issue.Design,
-		issue.AcceptanceCriteria, issue.Notes, issue.Status,
+		issue.ID, issue.Title, stored.Description, issue.Design,
+		issue.AcceptanceCriteria, stored.Notes, issue.Status,
 		issue.Priority, issue.IssueType, issue.Assignee,
 		issue.EstimatedMinutes, issue.CreatedAt, issue.UpdatedAt,
 		issue.ClosedAt, issue.ExternalRef,
+		issue.DueDate, issue.StartDate, issue.Milestone,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to insert issue: %w", err)
 	}
 
+	// Create as a subtask when a parent is given
+	if issue.ParentID != "" {
+		if err := linkParentConn(ctx, conn, issue, actor, s.sessionID(ctx)); err != nil {
+			return err
+		}
+	}
+
 	// Record creation event
-	eventData, err := json.Marshal(issue)
+	eventData, err := json.Marshal(stored)
 	if err != nil {
 		// Fall back to minimal description if marshaling fails
 		eventData = []byte(fmt.Sprintf(`{"id":"%s","title":"%s"}`, issue.ID, issue.Title))
 	}
 	eventDataStr := string(eventData)
 	_, err = conn.ExecContext(ctx, `
-		INSERT INTO events (issue_id, event_type, actor, new_value)
-		VALUES (?, ?, ?, ?)
-	`, issue.ID, types.EventCreated, actor, eventDataStr)
+		INSERT INTO events (issue_id, event_type, actor, new_value, session_id)
+		VALUES (?, ?, ?, ?, ?)
+	`, issue.ID, types.EventCreated, actor, eventDataStr, s.eventSession(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to record event: %w", err)
 	}
+	if err := s.recordRevision(ctx, conn, &IssueRevision{IssueID: issue.ID, Kind: types.EventCreated, Actor: actor, issue: issue}); err != nil {
+		return err
+	}
 
 	// Mark issue as dirty for incremental export
 	_, err = conn.ExecContext(ctx, `
@@ -717,6 +986,11 @@ func (s *SQLiteStorage) CreateIssue(ctx context.Context, issue *types.Issue, act
 		return fmt.Errorf("failed to mark issue dirty: %w", err)
 	}
 
+	// Record any detected secrets
+	if err := scan.record(ctx, conn, issue.ID, actor); err != nil {
+		return err
+	}
+
 	// Commit the transaction
 	if _, err := conn.ExecContext(ctx, "COMMIT"); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
@@ -726,7 +1000,7 @@ func (s *SQLiteStorage) CreateIssue(ctx context.Context, issue *types.Issue, act
 }
 
 // validateBatchIssues validates all issues in a batch and sets timestamps
-func validateBatchIssues(issues []*types.Issue) error {
+func (s *SQLiteStorage) validateBatchIssues(ctx context.Context, issues []*types.Issue, workflow *types.Workflow) error {
 	now := time.Now()
 	for i, issue := range issues {
 		if issue == nil {
@@ -741,7 +1015,7 @@ func validateBatchIssues(issues []*types.Issue) error {
 			issue.UpdatedAt = now
 		}
 
-		if err := issue.Validate(); err != nil {
+		if err := s.validateIssue(ctx, issue, workflow); err != nil {
 			return fmt.Errorf("validation failed for issue %d: %w", i, err)
 		}
 	}
@@ -817,8 +1091,9 @@ func bulkInsertIssues(ctx context.Context, conn *sql.Conn, issues []*types.Issue
 		INSERT INTO issues (
 			id, title, description, design, acceptance_criteria, notes,
 			status, priority, issue_type, assignee, estimated_minutes,
-			created_at, updated_at, closed_at, external_ref
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			created_at, updated_at, closed_at, external_ref,
+			due_date, start_date, milestone
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''))
 	`)
 	if err != nil {
 		return fmt.Errorf("failed to prepare statement: %w", err)
@@ -832,6 +1107,7 @@ func bulkInsertIssues(ctx context.Context, conn *sql.Conn, issues []*types.Issue
 			issue.Priority, issue.IssueType, issue.Assignee,
 			issue.EstimatedMinutes, issue.CreatedAt, issue.UpdatedAt,
 			issue.ClosedAt, issue.ExternalRef,
+			issue.DueDate, issue.StartDate, issue.Milestone,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to insert issue %s: %w", issue.ID, err)
@@ -841,10 +1117,10 @@ func bulkInsertIssues(ctx context.Context, conn *sql.Conn, issues []*types.Issue
 }
 
 // bulkRecordEvents records creation events for all issues
-func bulkRecordEvents(ctx context.Context, conn *sql.Conn, issues []*types.Issue, actor string) error {
+func bulkRecordEvents(ctx context.Context, conn *sql.Conn, issues []*types.Issue, actor, session string) error {
 	stmt, err := conn.PrepareContext(ctx, `
-		INSERT INTO events (issue_id, event_type, actor, new_value)
-		VALUES (?, ?, ?, ?)
+		INSERT INTO events (issue_id, event_type, actor, new_value, session_id)
+		VALUES (?, ?, ?, ?, ?)
 	`)
 	if err != nil {
 		return fmt.Errorf("failed to prepare event statement: %w", err)
@@ -858,7 +1134,7 @@ func bulkRecordEvents(ctx context.Context, conn *sql.Conn, issues []*types.Issue
 			eventData = []byte(fmt.Sprintf(`{"id":"%s","title":"%s"}`, issue.ID, issue.Title))
 		}
 
-		_, err = stmt.ExecContext(ctx, issue.ID, types.EventCreated, actor, string(eventData))
+		_, err = stmt.ExecContext(ctx, issue.ID, types.EventCreated, actor, string(eventData), nullSession(session))
 		if err != nil {
 			return fmt.Errorf("failed to record event for %s: %w", issue.ID, err)
 		}
@@ -944,9 +1220,34 @@ func (s *SQLiteStorage) CreateIssues(ctx context.Context, issues []*types.Issue,
 	}
 
 	// Phase 1: Validate all issues first (fail-fast)
-	if err := validateBatchIssues(issues); err != nil {
+	workflow, err := s.GetWorkflow(ctx)
+	if err != nil {
 		return err
 	}
+	if err := s.validateBatchIssues(ctx, issues, workflow); err != nil {
+		return err
+	}
+
+	// Check free-text fields for pasted credentials, keeping each issue's
+	// findings to record once it has an ID
+	config, err := s.newSecretScan(ctx)
+	if err != nil {
+		return err
+	}
+	scans := make([]*secretScan, len(issues))
+	for i, issue := range issues {
+		scans[i] = &secretScan{mode: config.mode}
+		if err := scans[i].applyToIssue(issue); err != nil {
+			return err
+		}
+	}
+	for _, issue := range issues {
+		resolved, err := s.ResolveActor(ctx, issue.Assignee)
+		if err != nil {
+			return err
+		}
+		issue.Assignee = resolved
+	}
 
 	// Phase 2: Acquire connection and start transaction
 	conn, err := s.db.Conn(ctx)
@@ -971,15 +1272,31 @@ func (s *SQLiteStorage) CreateIssues(ctx context.Context, issues []*types.Issue,
 		return err
 	}
 
-	// Phase 4: Bulk insert issues
-	if err := bulkInsertIssues(ctx, conn, issues); err != nil {
+	// Phase 4: Bulk insert issues (with sensitive fields encrypted if enabled)
+	stored := make([]*types.Issue, len(issues))
+	for i, issue := range issues {
+		enc, err := s.encryptedIssueCopy(issue)
+		if err != nil {
+			return err
+		}
+		stored[i] = enc
+	}
+	if err := bulkInsertIssues(ctx, conn, stored); err != nil {
 		return err
 	}
 
 	// Phase 5: Record creation events
-	if err := bulkRecordEvents(ctx, conn, issues, actor); err != nil {
+	if err := bulkRecordEvents(ctx, conn, stored, actor, s.sessionID(ctx)); err != nil {
 		return err
 	}
+	for i, issue := range issues {
+		if err := s.recordRevision(ctx, conn, &IssueRevision{IssueID: issue.ID, Kind: types.EventCreated, Actor: actor, issue: issue}); err != nil {
+			return err
+		}
+		if err := scans[i].record(ctx, conn, issue.ID, actor); err != nil {
+			return err
+		}
+	}
 
 	// Phase 6: Mark issues dirty for incremental export
 	if err := bulkMarkDirty(ctx, conn, issues); err != nil {
@@ -996,6 +1313,9 @@ func (s *SQLiteStorage) CreateIssues(ctx context.Context, issues []*types.Issue,
 
 // GetIssue retrieves an issue by ID
 func (s *SQLiteStorage) GetIssue(ctx context.Context, id string) (*types.Issue, error) {
+	ctx, span := startSpan(ctx, "GetIssue", attribute.String("issue.id", id))
+	defer span.End()
+
 	var issue types.Issue
 	var closedAt sql.NullTime
 	var estimatedMinutes sql.NullInt64
@@ -1005,11 +1325,17 @@ func (s *SQLiteStorage) GetIssue(ctx context.Context, id string) (*types.Issue,
 	var originalSize sql.NullInt64
 
 	var compactedAtCommit sql.NullString
-	err := s.db.QueryRowContext(ctx, `
+	var deletedAt sql.NullTime
+	var deletedBy sql.NullString
+	var dueDate, startDate sql.NullTime
+	var milestone sql.NullString
+	err := s.reads.QueryRowContext(ctx, `
 		SELECT id, title, description, design, acceptance_criteria, notes,
 		       status, priority, issue_type, assignee, estimated_minutes,
 		       created_at, updated_at, closed_at, external_ref,
-		       compaction_level, compacted_at, compacted_at_commit, original_size
+		       compaction_level, compacted_at, compacted_at_commit, original_size,
+		       deleted_at, deleted_by, due_date, start_date, milestone,
+		       (SELECT COALESCE(MAX(revision), 0) FROM issue_history WHERE issue_id = issues.id)
 		FROM issues
 		WHERE id = ?
 	`, id).Scan(
@@ -1018,6 +1344,8 @@ func (s *SQLiteStorage) GetIssue(ctx context.Context, id string) (*types.Issue,
 		&issue.Priority, &issue.IssueType, &assignee, &estimatedMinutes,
 		&issue.CreatedAt, &issue.UpdatedAt, &closedAt, &externalRef,
 		&issue.CompactionLevel, &compactedAt, &compactedAtCommit, &originalSize,
+		&deletedAt, &deletedBy, &dueDate, &startDate, &milestone,
+		&issue.Version,
 	)
 
 	if err == sql.ErrNoRows {
@@ -1049,6 +1377,20 @@ func (s *SQLiteStorage) GetIssue(ctx context.Context, id string) (*types.Issue,
 	if originalSize.Valid {
 		issue.OriginalSize = int(originalSize.Int64)
 	}
+	if deletedAt.Valid {
+		issue.DeletedAt = &deletedAt.Time
+		issue.DeletedBy = deletedBy.String
+	}
+	if dueDate.Valid {
+		issue.DueDate = &dueDate.Time
+	}
+	if startDate.Valid {
+		issue.StartDate = &startDate.Time
+	}
+	if milestone.Valid {
+		issue.Milestone = milestone.String
+	}
+	s.decryptIssueFields(&issue)
 
 	// Fetch labels for this issue
 	labels, err := s.GetLabels(ctx, issue.ID)
@@ -1057,6 +1399,12 @@ func (s *SQLiteStorage) GetIssue(ctx context.Context, id string) (*types.Issue,
 	}
 	issue.Labels = labels
 
+	parentID, err := s.getParentID(ctx, issue.ID)
+	if err != nil {
+		return nil, err
+	}
+	issue.ParentID = parentID
+
 	return &issue, nil
 }
 
@@ -1073,23 +1421,16 @@ var allowedUpdateFields = map[string]bool{
 	"issue_type":          true,
 	"estimated_minutes":   true,
 	"external_ref":        true,
+	"due_date":            true,
+	"start_date":          true,
+	"milestone":           true,
 }
 
 // validatePriority validates a priority value
 func validatePriority(value interface{}) error {
 	if priority, ok := value.(int); ok {
-		if priority < 0 || priority > 4 {
-			return fmt.Errorf("priority must be between 0 and 4 (got %d)", priority)
-		}
-	}
-	return nil
-}
-
-// validateStatus validates a status value
-func validateStatus(value interface{}) error {
-	if status, ok := value.(string); ok {
-		if !types.Status(status).IsValid() {
-			return fmt.Errorf("invalid status: %s", status)
+		if v := types.CheckPriority(priority); v != nil {
+			return types.NewValidationError(*v)
 		}
 	}
 	return nil
@@ -1098,8 +1439,8 @@ func validateStatus(value interface{}) error {
 // validateIssueType validates an issue type value
 func validateIssueType(value interface{}) error {
 	if issueType, ok := value.(string); ok {
-		if !types.IssueType(issueType).IsValid() {
-			return fmt.Errorf("invalid issue type: %s", issueType)
+		if v := types.CheckIssueType(types.IssueType(issueType)); v != nil {
+			return types.NewValidationError(*v)
 		}
 	}
 	return nil
@@ -1108,8 +1449,8 @@ func validateIssueType(value interface{}) error {
 // validateTitle validates a title value
 func validateTitle(value interface{}) error {
 	if title, ok := value.(string); ok {
-		if len(title) == 0 || len(title) > 500 {
-			return fmt.Errorf("title must be 1-500 characters")
+		if v := types.CheckTitle(title); v != nil {
+			return types.NewValidationError(*v)
 		}
 	}
 	return nil
@@ -1119,16 +1460,39 @@ func validateTitle(value interface{}) error {
 func validateEstimatedMinutes(value interface{}) error {
 	if mins, ok := value.(int); ok {
 		if mins < 0 {
-			return fmt.Errorf("estimated_minutes cannot be negative")
+			return types.NewValidationError(types.Violation{Field: "estimated_minutes", Code: types.ViolationOutOfRange, Message: "estimated_minutes cannot be negative"})
 		}
 	}
 	return nil
 }
 
+// dateFieldValue converts a due_date or start_date update to a *time.Time.
+// Values arriving as JSON are strings (see types.ParseDate); nil or "" clears
+// the date.
+func dateFieldValue(key string, value interface{}) (*time.Time, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case time.Time:
+		return &v, nil
+	case *time.Time:
+		return v, nil
+	case string:
+		if v == "" {
+			return nil, nil
+		}
+		t, err := types.ParseDate(v, time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		return &t, nil
+	}
+	return nil, fmt.Errorf("%s must be a date", key)
+}
+
 // fieldValidators maps field names to their validation functions
 var fieldValidators = map[string]func(interface{}) error{
 	"priority":           validatePriority,
-	"status":             validateStatus,
 	"issue_type":         validateIssueType,
 	"title":              validateTitle,
 	"estimated_minutes":  validateEstimatedMinutes,
@@ -1201,29 +1565,146 @@ func manageClosedAt(oldIssue *types.Issue, updates map[string]interface{}, setCl
 
 // UpdateIssue updates fields on an issue
 func (s *SQLiteStorage) UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error {
+	return s.updateIssue(ctx, id, updates, actor, nil)
+}
+
+// SetIssueTimes sets when an issue was last updated, and closed if it is
+// closed, for imports that carry the times a change was made elsewhere.
+// UpdateIssue stamps the current time instead.
+func (s *SQLiteStorage) SetIssueTimes(ctx context.Context, id string, updatedAt time.Time, closedAt *time.Time) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE issues
+		SET updated_at = ?,
+		    closed_at = CASE WHEN status = 'closed' AND ? IS NOT NULL THEN ? ELSE closed_at END
+		WHERE id = ?
+	`, updatedAt, closedAt, closedAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to set times for %s: %w", id, err)
+	}
+	return nil
+}
+
+// UpdateIssueIfVersion updates an issue only if it is still at version (its
+// latest revision, see types.Issue.Version). Otherwise it returns a
+// *VersionConflictError and changes nothing.
+func (s *SQLiteStorage) UpdateIssueIfVersion(ctx context.Context, id string, updates map[string]interface{}, actor string, version int) error {
+	return s.updateIssue(ctx, id, updates, actor, &version)
+}
+
+func (s *SQLiteStorage) updateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string, version *int) error {
+	ctx, span := startSpan(ctx, "UpdateIssue", attribute.String("issue.id", id))
+	defer span.End()
+
 	// Get old issue for event
 	oldIssue, err := s.GetIssue(ctx, id)
 	if err != nil {
 		return err
 	}
 	if oldIssue == nil {
-		return fmt.Errorf("issue %s not found", id)
+		return types.NotFound("issue", id)
 	}
 
-	// Build update query with validated field names
-	setClauses := []string{"updated_at = ?"}
-	args := []interface{}{time.Now()}
+	// Check free-text fields for pasted credentials
+	scan, err := s.newSecretScan(ctx)
+	if err != nil {
+		return err
+	}
+	if err := scan.applyToUpdates(updates); err != nil {
+		return err
+	}
+	if err := s.normalizeAssignee(ctx, updates); err != nil {
+		return err
+	}
 
-	for key, value := range updates {
-		// Prevent SQL injection by validating field names
-		if !allowedUpdateFields[key] {
-			return fmt.Errorf("invalid field for update: %s", key)
-		}
+	// Start transaction
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
 
-		// Validate field values
-		if err := validateFieldUpdate(key, value); err != nil {
+	// Check the version inside the transaction so no write can slip in
+	// between the check and the update
+	if version != nil {
+		current, err := currentRevision(ctx, tx, id)
+		if err != nil {
 			return err
 		}
+		if current != *version {
+			return &VersionConflictError{IssueID: id, Expected: *version, Current: current}
+		}
+	}
+
+	if err := s.updateIssueInTx(ctx, tx, oldIssue, updates, actor, 0); err != nil {
+		return err
+	}
+
+	// Record any detected secrets
+	if err := scan.record(ctx, tx, id, actor); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	if _, err := s.triage(ctx, TriageOnUpdate, oldIssue, id); err != nil {
+		return fmt.Errorf("updated %s, but %w", id, err)
+	}
+	return nil
+}
+
+// updateIssueInTx validates and applies updates to oldIssue within tx, recording
+// the event and marking the issue dirty. updates must already be secret-scanned;
+// closed_at is added to it when the status change requires one. A nonzero
+// reverts records the change as the undo of that revision.
+func (s *SQLiteStorage) updateIssueInTx(ctx context.Context, tx *sql.Tx, oldIssue *types.Issue, updates map[string]interface{}, actor string, reverts int) error {
+	id := oldIssue.ID
+
+	// Status changes follow the workflow, and required fields stay set
+	if err := checkStatusUpdate(ctx, tx, oldIssue, updates, reverts != 0); err != nil {
+		return err
+	}
+	if err := checkRequiredUpdate(ctx, tx, oldIssue, updates, reverts != 0); err != nil {
+		return err
+	}
+
+	// Build update query with validated field names
+	setClauses := []string{"updated_at = ?"}
+	args := []interface{}{time.Now()}
+
+	for key, value := range updates {
+		// Prevent SQL injection by validating field names
+		if !allowedUpdateFields[key] {
+			return fmt.Errorf("invalid field for update: %s", key)
+		}
+
+		// Validate field values
+		if err := validateFieldUpdate(key, value); err != nil {
+			return err
+		}
+
+		if key == "due_date" || key == "start_date" {
+			date, err := dateFieldValue(key, value)
+			if err != nil {
+				return err
+			}
+			value = date
+			updates[key] = date
+		}
+
+		// An empty milestone unassigns the issue
+		if key == "milestone" && value == "" {
+			value = nil
+		}
+
+		// Encrypt sensitive fields if field encryption is enabled
+		if text, ok := value.(string); ok && (key == "description" || key == "notes") {
+			enc, err := s.encryptField(text)
+			if err != nil {
+				return err
+			}
+			value = enc
+		}
 
 		setClauses = append(setClauses, fmt.Sprintf("%s = ?", key))
 		args = append(args, value)
@@ -1234,16 +1715,9 @@ func (s *SQLiteStorage) UpdateIssue(ctx context.Context, id string, updates map[
 
 	args = append(args, id)
 
-	// Start transaction
-	tx, err := s.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	defer func() { _ = tx.Rollback() }()
-
 	// Update issue
 	query := fmt.Sprintf("UPDATE issues SET %s WHERE id = ?", strings.Join(setClauses, ", ")) // #nosec G201 - safe SQL with controlled column names
-	_, err = tx.ExecContext(ctx, query, args...)
+	_, err := tx.ExecContext(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("failed to update issue: %w", err)
 	}
@@ -1259,19 +1733,37 @@ func (s *SQLiteStorage) UpdateIssue(ctx context.Context, id string, updates map[
 		// Fall back to minimal description if marshaling fails
 		newData = []byte(`{}`)
 	}
-	oldDataStr := string(oldData)
-	newDataStr := string(newData)
+	oldDataStr := s.encryptEventPayload(string(oldData))
+	newDataStr := s.encryptEventPayload(string(newData))
 
 	eventType := determineEventType(oldIssue, updates)
 
 	_, err = tx.ExecContext(ctx, `
-		INSERT INTO events (issue_id, event_type, actor, old_value, new_value)
-		VALUES (?, ?, ?, ?, ?)
-	`, id, eventType, actor, oldDataStr, newDataStr)
+		INSERT INTO events (issue_id, event_type, actor, old_value, new_value, session_id)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, id, eventType, actor, oldDataStr, newDataStr, s.eventSession(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to record event: %w", err)
 	}
 
+	// Reopening an archived issue brings it back out of the archive
+	if status, ok := updates["status"]; ok && status != string(types.StatusClosed) && status != types.StatusClosed {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM archived_issues WHERE issue_id = ?`, id); err != nil {
+			return fmt.Errorf("failed to unarchive issue: %w", err)
+		}
+	}
+
+	// Record a history revision when any field actually changed
+	if changes := updateChanges(oldIssue, updates); len(changes) > 0 {
+		rev := &IssueRevision{IssueID: id, Kind: eventType, Actor: actor, Changes: changes, Reverts: reverts}
+		if reverts != 0 {
+			rev.Kind = types.EventReverted
+		}
+		if err := s.recordRevision(ctx, tx, rev); err != nil {
+			return err
+		}
+	}
+
 	// Mark issue as dirty for incremental export
 	_, err = tx.ExecContext(ctx, `
 		INSERT INTO dirty_issues (issue_id, marked_at)
@@ -1282,7 +1774,7 @@ func (s *SQLiteStorage) UpdateIssue(ctx context.Context, id string, updates map[
 		return fmt.Errorf("failed to mark issue dirty: %w", err)
 	}
 
-	return tx.Commit()
+	return nil
 }
 
 // UpdateIssueID updates an issue ID and all its text fields in a single transaction
@@ -1294,11 +1786,13 @@ func (s *SQLiteStorage) UpdateIssueID(ctx context.Context, oldID, newID string,
 	}
 	defer func() { _ = conn.Close() }()
 
-	// Disable foreign keys on this specific connection
+	// Disable foreign keys on this specific connection. It's the pool's
+	// only writer, so they must be back on before it's returned.
 	_, err = conn.ExecContext(ctx, `PRAGMA foreign_keys = OFF`)
 	if err != nil {
 		return fmt.Errorf("failed to disable foreign keys: %w", err)
 	}
+	defer func() { _, _ = conn.ExecContext(context.Background(), `PRAGMA foreign_keys = ON`) }()
 
 	tx, err := conn.BeginTx(ctx, nil)
 	if err != nil {
@@ -1306,16 +1800,31 @@ func (s *SQLiteStorage) UpdateIssueID(ctx context.Context, oldID, newID string,
 	}
 	defer func() { _ = tx.Rollback() }()
 
+	stored, err := s.encryptedIssueCopy(issue)
+	if err != nil {
+		return err
+	}
+
 	_, err = tx.ExecContext(ctx, `
 		UPDATE issues
 		SET id = ?, title = ?, description = ?, design = ?, acceptance_criteria = ?, notes = ?, updated_at = ?
 		WHERE id = ?
-	`, newID, issue.Title, issue.Description, issue.Design, issue.AcceptanceCriteria, issue.Notes, time.Now(), oldID)
+	`, newID, issue.Title, stored.Description, issue.Design, issue.AcceptanceCriteria, stored.Notes, time.Now(), oldID)
 	if err != nil {
 		return fmt.Errorf("failed to update issue ID: %w", err)
 	}
 
-	_, err = tx.ExecContext(ctx, `UPDATE dependencies SET issue_id = ? WHERE issue_id = ?`, newID, oldID)
+	if err := s.moveIssueID(ctx, tx, oldID, newID, actor); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// moveIssueID points every row that refers to oldID at newID, after the
+// issue row itself has been renamed, and records the rename
+func (s *SQLiteStorage) moveIssueID(ctx context.Context, tx *sql.Tx, oldID, newID, actor string) error {
+	_, err := tx.ExecContext(ctx, `UPDATE dependencies SET issue_id = ? WHERE issue_id = ?`, newID, oldID)
 	if err != nil {
 		return fmt.Errorf("failed to update issue_id in dependencies: %w", err)
 	}
@@ -1325,6 +1834,21 @@ func (s *SQLiteStorage) UpdateIssueID(ctx context.Context, oldID, newID string,
 		return fmt.Errorf("failed to update depends_on_id in dependencies: %w", err)
 	}
 
+	_, err = tx.ExecContext(ctx, `UPDATE remote_dependencies SET issue_id = ? WHERE issue_id = ?`, newID, oldID)
+	if err != nil {
+		return fmt.Errorf("failed to update remote_dependencies: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE replica_fields SET issue_id = ? WHERE issue_id = ?`, newID, oldID)
+	if err != nil {
+		return fmt.Errorf("failed to update replica_fields: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE replica_issues SET issue_id = ? WHERE issue_id = ?`, newID, oldID)
+	if err != nil {
+		return fmt.Errorf("failed to update replica_issues: %w", err)
+	}
+
 	_, err = tx.ExecContext(ctx, `UPDATE events SET issue_id = ? WHERE issue_id = ?`, newID, oldID)
 	if err != nil {
 		return fmt.Errorf("failed to update events: %w", err)
@@ -1357,6 +1881,40 @@ func (s *SQLiteStorage) UpdateIssueID(ctx context.Context, oldID, newID string,
 		return fmt.Errorf("failed to update compaction_snapshots: %w", err)
 	}
 
+	_, err = tx.ExecContext(ctx, `UPDATE external_mappings SET issue_id = ? WHERE issue_id = ?`, newID, oldID)
+	if err != nil {
+		return fmt.Errorf("failed to update external_mappings: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE issue_commits SET issue_id = ? WHERE issue_id = ?`, newID, oldID)
+	if err != nil {
+		return fmt.Errorf("failed to update issue_commits: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE issue_history SET issue_id = ? WHERE issue_id = ?`, newID, oldID)
+	if err != nil {
+		return fmt.Errorf("failed to update issue_history: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE archived_issues SET issue_id = ? WHERE issue_id = ?`, newID, oldID)
+	if err != nil {
+		return fmt.Errorf("failed to update archived_issues: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE issue_leases SET issue_id = ? WHERE issue_id = ?`, newID, oldID)
+	if err != nil {
+		return fmt.Errorf("failed to update issue_leases: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE issue_watchers SET issue_id = ? WHERE issue_id = ?`, newID, oldID)
+	if err != nil {
+		return fmt.Errorf("failed to update issue_watchers: %w", err)
+	}
+	renamed := []FieldChange{{Field: "id", Old: oldID, New: newID}}
+	if err := s.recordRevision(ctx, tx, &IssueRevision{IssueID: newID, Kind: "renamed", Actor: actor, Changes: renamed}); err != nil {
+		return err
+	}
+
 	_, err = tx.ExecContext(ctx, `
 		INSERT INTO dirty_issues (issue_id, marked_at)
 		VALUES (?, ?)
@@ -1367,14 +1925,14 @@ func (s *SQLiteStorage) UpdateIssueID(ctx context.Context, oldID, newID string,
 	}
 
 	_, err = tx.ExecContext(ctx, `
-		INSERT INTO events (issue_id, event_type, actor, old_value, new_value)
-		VALUES (?, 'renamed', ?, ?, ?)
-	`, newID, actor, oldID, newID)
+		INSERT INTO events (issue_id, event_type, actor, old_value, new_value, session_id)
+		VALUES (?, 'renamed', ?, ?, ?, ?)
+	`, newID, actor, oldID, newID, s.eventSession(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to record rename event: %w", err)
 	}
 
-	return tx.Commit()
+	return nil
 }
 
 // RenameDependencyPrefix updates the prefix in all dependency records
@@ -1390,8 +1948,18 @@ func (s *SQLiteStorage) RenameCounterPrefix(ctx context.Context, oldPrefix, newP
 	}
 	defer func() { _ = tx.Rollback() }()
 
+	if err := renameCounter(ctx, tx, oldPrefix, newPrefix); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// renameCounter moves the issue counter for oldPrefix to newPrefix, keeping
+// the higher of the two if both exist
+func renameCounter(ctx context.Context, tx *sql.Tx, oldPrefix, newPrefix string) error {
 	var lastID int
-	err = tx.QueryRowContext(ctx, `SELECT last_id FROM issue_counters WHERE prefix = ?`, oldPrefix).Scan(&lastID)
+	err := tx.QueryRowContext(ctx, `SELECT last_id FROM issue_counters WHERE prefix = ?`, oldPrefix).Scan(&lastID)
 	if err != nil && err != sql.ErrNoRows {
 		return fmt.Errorf("failed to get old counter: %w", err)
 	}
@@ -1410,7 +1978,7 @@ func (s *SQLiteStorage) RenameCounterPrefix(ctx context.Context, oldPrefix, newP
 		return fmt.Errorf("failed to create new counter: %w", err)
 	}
 
-	return tx.Commit()
+	return nil
 }
 
 // ResetCounter deletes the counter for a prefix, forcing it to be recalculated from max ID
@@ -1425,6 +1993,24 @@ func (s *SQLiteStorage) ResetCounter(ctx context.Context, prefix string) error {
 
 // CloseIssue closes an issue with a reason
 func (s *SQLiteStorage) CloseIssue(ctx context.Context, id string, reason string, actor string) error {
+	ctx, span := startSpan(ctx, "CloseIssue", attribute.String("issue.id", id))
+	defer span.End()
+
+	// Closing follows the workflow, which may require a reason
+	issue, err := s.GetIssue(ctx, id)
+	if err != nil {
+		return err
+	}
+	if issue != nil {
+		workflow, err := s.GetWorkflow(ctx)
+		if err != nil {
+			return err
+		}
+		if err := workflow.CheckTransition(issue, types.StatusClosed, reason); err != nil {
+			return err
+		}
+	}
+
 	now := time.Now()
 
 	// Update with special event handling
@@ -1434,6 +2020,13 @@ func (s *SQLiteStorage) CloseIssue(ctx context.Context, id string, reason string
 	}
 	defer func() { _ = tx.Rollback() }()
 
+	var oldStatus string
+	err = tx.QueryRowContext(ctx, `SELECT status FROM issues WHERE id = ?`, id).Scan(&oldStatus)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to get issue status: %w", err)
+	}
+	found := err == nil
+
 	_, err = tx.ExecContext(ctx, `
 		UPDATE issues SET status = ?, closed_at = ?, updated_at = ?
 		WHERE id = ?
@@ -1443,13 +2036,28 @@ func (s *SQLiteStorage) CloseIssue(ctx context.Context, id string, reason string
 	}
 
 	_, err = tx.ExecContext(ctx, `
-		INSERT INTO events (issue_id, event_type, actor, comment)
-		VALUES (?, ?, ?, ?)
-	`, id, types.EventClosed, actor, reason)
+		INSERT INTO events (issue_id, event_type, actor, comment, session_id)
+		VALUES (?, ?, ?, ?, ?)
+	`, id, types.EventClosed, actor, reason, s.eventSession(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to record event: %w", err)
 	}
 
+	// Closing an issue ends any claim on it
+	if _, err := tx.ExecContext(ctx, `DELETE FROM issue_leases WHERE issue_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to release lease: %w", err)
+	}
+
+	if found {
+		var changes []FieldChange
+		if oldStatus != string(types.StatusClosed) {
+			changes = []FieldChange{{Field: "status", Old: oldStatus, New: string(types.StatusClosed)}}
+		}
+		if err := s.recordRevision(ctx, tx, &IssueRevision{IssueID: id, Kind: types.EventClosed, Actor: actor, Changes: changes, Comment: reason}); err != nil {
+			return err
+		}
+	}
+
 	// Mark issue as dirty for incremental export
 	_, err = tx.ExecContext(ctx, `
 		INSERT INTO dirty_issues (issue_id, marked_at)
@@ -1465,6 +2073,9 @@ func (s *SQLiteStorage) CloseIssue(ctx context.Context, id string, reason string
 
 // DeleteIssue permanently removes an issue from the database
 func (s *SQLiteStorage) DeleteIssue(ctx context.Context, id string) error {
+	ctx, span := startSpan(ctx, "DeleteIssue", attribute.String("issue.id", id))
+	defer span.End()
+
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -1489,6 +2100,24 @@ func (s *SQLiteStorage) DeleteIssue(ctx context.Context, id string) error {
 		return fmt.Errorf("failed to delete dirty marker: %w", err)
 	}
 
+	// Delete commit links
+	_, err = tx.ExecContext(ctx, `DELETE FROM issue_commits WHERE issue_id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete commit links: %w", err)
+	}
+
+	// Delete history
+	_, err = tx.ExecContext(ctx, `DELETE FROM issue_history WHERE issue_id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete history: %w", err)
+	}
+
+	// Delete archive entry
+	_, err = tx.ExecContext(ctx, `DELETE FROM archived_issues WHERE issue_id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete archive entry: %w", err)
+	}
+
 	// Delete the issue itself
 	result, err := tx.ExecContext(ctx, `DELETE FROM issues WHERE id = ?`, id)
 	if err != nil {
@@ -1723,6 +2352,9 @@ func (s *SQLiteStorage) executeDelete(ctx context.Context, tx *sql.Tx, inClause
 		{fmt.Sprintf(`DELETE FROM labels WHERE issue_id IN (%s)`, inClause), args},
 		{fmt.Sprintf(`DELETE FROM events WHERE issue_id IN (%s)`, inClause), args},
 		{fmt.Sprintf(`DELETE FROM dirty_issues WHERE issue_id IN (%s)`, inClause), args},
+		{fmt.Sprintf(`DELETE FROM issue_commits WHERE issue_id IN (%s)`, inClause), args},
+		{fmt.Sprintf(`DELETE FROM issue_history WHERE issue_id IN (%s)`, inClause), args},
+		{fmt.Sprintf(`DELETE FROM archived_issues WHERE issue_id IN (%s)`, inClause), args},
 		{fmt.Sprintf(`DELETE FROM issues WHERE id IN (%s)`, inClause), args},
 	}
 
@@ -1785,6 +2417,94 @@ func (s *SQLiteStorage) findAllDependentsRecursive(ctx context.Context, tx *sql.
 
 // SearchIssues finds issues matching query and filters
 func (s *SQLiteStorage) SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error) {
+	ctx, span := startSpan(ctx, "SearchIssues")
+	defer span.End()
+
+	querySQL, args := searchIssuesSQL(query, filter, "")
+	rows, err := s.reads.QueryContext(ctx, querySQL, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search issues: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	issues, err := s.scanIssues(ctx, rows)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.populateParentIDs(ctx, issues); err != nil {
+		return nil, err
+	}
+	if filter.WithCounts {
+		if err := s.populateCounts(ctx, issues); err != nil {
+			return nil, err
+		}
+	}
+	return issues, nil
+}
+
+// ForEachIssue calls fn with each issue SearchIssues would return for
+// filter, in the same order, stopping at the first error fn returns. It
+// reads a page of a few hundred issues at a time, resuming after the last
+// one in sort order, and loads their labels, parents, and counts together
+// as SearchIssues does, so memory stays flat however many issues match.
+//
+// No connection is held between pages or while fn runs, so fn may use the
+// store and a slow caller doesn't tie up the read pool. Each page sees the
+// database as it is when the page is read: an issue whose sort key changes
+// mid-iteration may be skipped or seen twice.
+func (s *SQLiteStorage) ForEachIssue(ctx context.Context, filter types.IssueFilter, fn func(*types.Issue) error) error {
+	ctx, span := startSpan(ctx, "ForEachIssue")
+	defer span.End()
+
+	remaining := filter.Limit
+	after := ""
+	for {
+		page := filter
+		page.Limit = idBatchSize
+		if remaining > 0 {
+			page.Limit = min(remaining, idBatchSize)
+		}
+		querySQL, args := searchIssuesSQL("", page, after)
+		rows, err := s.reads.QueryContext(ctx, querySQL, args...)
+		if err != nil {
+			return fmt.Errorf("failed to search issues: %w", err)
+		}
+		// scanIssues reads the page to the end, which frees its connection
+		// before the labels are loaded
+		batch, err := s.scanIssues(ctx, rows)
+		_ = rows.Close()
+		if err != nil {
+			return err
+		}
+		if err := s.populateParentIDs(ctx, batch); err != nil {
+			return err
+		}
+		if filter.WithCounts {
+			if err := s.populateCounts(ctx, batch); err != nil {
+				return err
+			}
+		}
+		for _, issue := range batch {
+			if err := fn(issue); err != nil {
+				return err
+			}
+		}
+
+		if len(batch) < page.Limit {
+			return nil
+		}
+		if remaining > 0 {
+			if remaining -= len(batch); remaining == 0 {
+				return nil
+			}
+		}
+		after = batch[len(batch)-1].ID
+	}
+}
+
+// searchIssuesSQL builds the query behind SearchIssues and ForEachIssue.
+// With after, it only matches the issues sorted after that one.
+func searchIssuesSQL(query string, filter types.IssueFilter, after string) (string, []interface{}) {
 	whereClauses := []string{}
 	args := []interface{}{}
 
@@ -1794,48 +2514,129 @@ func (s *SQLiteStorage) SearchIssues(ctx context.Context, query string, filter t
 		args = append(args, pattern, pattern, pattern)
 	}
 
+	filterClauses, filterArgs := issueFilterClauses(filter, "")
+	whereClauses = append(whereClauses, filterClauses...)
+	args = append(args, filterArgs...)
+
+	if after != "" {
+		clause, afterArgs := keysetAfter(issueOrderTerms(filter.Sort, ""), after)
+		whereClauses = append(whereClauses, clause)
+		args = append(args, afterArgs...)
+	}
+
+	whereSQL := ""
+	if len(whereClauses) > 0 {
+		whereSQL = "WHERE " + strings.Join(whereClauses, " AND ")
+	}
+
+	limitSQL := ""
+	if filter.Limit > 0 {
+		limitSQL = " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	// #nosec G201 - safe SQL with controlled formatting
+	querySQL := fmt.Sprintf(`
+		SELECT id, title, description, design, acceptance_criteria, notes,
+		       status, priority, issue_type, assignee, estimated_minutes,
+		       created_at, updated_at, closed_at, external_ref,
+		       due_date, start_date, milestone
+		FROM issues
+		%s
+		ORDER BY %s
+		%s
+	`, whereSQL, issueOrderBy(filter.Sort, ""), limitSQL)
+	return querySQL, args
+}
+
+// issueFilterClauses builds WHERE clauses for the structured fields of an
+// IssueFilter. col qualifies the issues table columns (e.g. "i.") so the
+// clauses can be reused in joined queries.
+func issueFilterClauses(filter types.IssueFilter, col string) ([]string, []interface{}) {
+	// Issues in the trash are hidden from every listing
+	whereClauses := []string{col + "deleted_at IS NULL"}
+	args := []interface{}{}
+
+	if filter.ExcludeArchived {
+		whereClauses = append(whereClauses, col+"id NOT IN (SELECT issue_id FROM archived_issues)")
+	}
+
 	if filter.TitleSearch != "" {
-		whereClauses = append(whereClauses, "title LIKE ?")
+		whereClauses = append(whereClauses, col+"title LIKE ?")
 		pattern := "%" + filter.TitleSearch + "%"
 		args = append(args, pattern)
 	}
 
 	if filter.Status != nil {
-		whereClauses = append(whereClauses, "status = ?")
+		whereClauses = append(whereClauses, col+"status = ?")
 		args = append(args, *filter.Status)
 	}
 
 	if filter.Priority != nil {
-		whereClauses = append(whereClauses, "priority = ?")
+		whereClauses = append(whereClauses, col+"priority = ?")
 		args = append(args, *filter.Priority)
 	}
 
 	if filter.IssueType != nil {
-		whereClauses = append(whereClauses, "issue_type = ?")
+		whereClauses = append(whereClauses, col+"issue_type = ?")
 		args = append(args, *filter.IssueType)
 	}
 
 	if filter.Assignee != nil {
-		whereClauses = append(whereClauses, "assignee = ?")
+		whereClauses = append(whereClauses, col+"assignee = ?")
 		args = append(args, *filter.Assignee)
 	}
 
 	// Label filtering: issue must have ALL specified labels
-	if len(filter.Labels) > 0 {
-		for _, label := range filter.Labels {
-			whereClauses = append(whereClauses, "id IN (SELECT issue_id FROM labels WHERE label = ?)")
-			args = append(args, label)
-		}
+	for _, label := range filter.Labels {
+		clause, labelArgs := labelFilterClause(col, label)
+		whereClauses = append(whereClauses, clause)
+		args = append(args, labelArgs...)
 	}
 
 	// Label filtering (OR): issue must have AT LEAST ONE of these labels
 	if len(filter.LabelsAny) > 0 {
-		placeholders := make([]string, len(filter.LabelsAny))
+		clauses := make([]string, len(filter.LabelsAny))
 		for i, label := range filter.LabelsAny {
-			placeholders[i] = "?"
-			args = append(args, label)
+			var labelArgs []interface{}
+			clauses[i], labelArgs = labelFilterClause(col, label)
+			args = append(args, labelArgs...)
+		}
+		whereClauses = append(whereClauses, "("+strings.Join(clauses, " OR ")+")")
+	}
+
+	// Label exclusion: issue must have NONE of these labels
+	for _, label := range filter.ExcludeLabels {
+		clause, labelArgs := labelFilterClause(col, label)
+		whereClauses = append(whereClauses, "NOT "+clause)
+		args = append(args, labelArgs...)
+	}
+
+	// Multi-value criteria; a missing assignee counts as unassigned
+	assignee := "COALESCE(" + col + "assignee, '')"
+	for _, set := range []struct {
+		expr    string
+		values  []interface{}
+		exclude bool
+	}{
+		{col + "status", statusValues(filter.Statuses), false},
+		{col + "status", statusValues(filter.ExcludeStatuses), true},
+		{col + "priority", intValues(filter.Priorities), false},
+		{col + "priority", intValues(filter.ExcludePriorities), true},
+		{col + "issue_type", issueTypeValues(filter.IssueTypes), false},
+		{col + "issue_type", issueTypeValues(filter.ExcludeIssueTypes), true},
+		{assignee, stringValues(filter.Assignees), false},
+		{assignee, stringValues(filter.ExcludeAssignees), true},
+	} {
+		if len(set.values) == 0 {
+			continue
+		}
+		op := " IN "
+		if set.exclude {
+			op = " NOT IN "
 		}
-		whereClauses = append(whereClauses, fmt.Sprintf("id IN (SELECT issue_id FROM labels WHERE label IN (%s))", strings.Join(placeholders, ", ")))
+		whereClauses = append(whereClauses, set.expr+op+"("+strings.TrimSuffix(strings.Repeat("?, ", len(set.values)), ", ")+")")
+		args = append(args, set.values...)
 	}
 
 	// ID filtering: match specific issue IDs
@@ -1845,38 +2646,144 @@ func (s *SQLiteStorage) SearchIssues(ctx context.Context, query string, filter t
 			placeholders[i] = "?"
 			args = append(args, id)
 		}
-		whereClauses = append(whereClauses, fmt.Sprintf("id IN (%s)", strings.Join(placeholders, ", ")))
+		whereClauses = append(whereClauses, fmt.Sprintf("%sid IN (%s)", col, strings.Join(placeholders, ", ")))
 	}
 
-	whereSQL := ""
-	if len(whereClauses) > 0 {
-		whereSQL = "WHERE " + strings.Join(whereClauses, " AND ")
+	// Due dates are compared with datetime() since they may be stored with
+	// different time zone offsets
+	if filter.DueBefore != nil {
+		whereClauses = append(whereClauses, "datetime("+col+"due_date) < ?")
+		args = append(args, sqliteDateTime(*filter.DueBefore))
+	}
+	if filter.DueAfter != nil {
+		whereClauses = append(whereClauses, "datetime("+col+"due_date) >= ?")
+		args = append(args, sqliteDateTime(*filter.DueAfter))
+	}
+	if filter.Overdue {
+		whereClauses = append(whereClauses, col+"status != 'closed'", "datetime("+col+"due_date) < ?")
+		args = append(args, sqliteDateTime(types.OverdueCutoff(time.Now())))
 	}
 
-	limitSQL := ""
-	if filter.Limit > 0 {
-		limitSQL = " LIMIT ?"
-		args = append(args, filter.Limit)
+	if filter.Milestone != nil {
+		whereClauses = append(whereClauses, col+"milestone = ?")
+		args = append(args, *filter.Milestone)
 	}
 
-	// #nosec G201 - safe SQL with controlled formatting
-	querySQL := fmt.Sprintf(`
-		SELECT id, title, description, design, acceptance_criteria, notes,
-		       status, priority, issue_type, assignee, estimated_minutes,
-		       created_at, updated_at, closed_at, external_ref
-		FROM issues
-		%s
-		ORDER BY priority ASC, created_at DESC
-		%s
-	`, whereSQL, limitSQL)
+	if filter.Query != nil {
+		clause, queryArgs := queryClause(filter.Query, col)
+		whereClauses = append(whereClauses, clause)
+		args = append(args, queryArgs...)
+	}
 
-	rows, err := s.db.QueryContext(ctx, querySQL, args...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to search issues: %w", err)
+	return whereClauses, args
+}
+
+// orderTerm is one term of an ORDER BY list
+type orderTerm struct {
+	expr string
+	desc bool
+}
+
+// issueOrderBy builds an ORDER BY list for keys, falling back to priority,
+// newest first, and then to ID so the order is total. col qualifies the
+// issues table columns as in issueFilterClauses. Missing values sort last
+// in either direction.
+func issueOrderBy(keys []types.SortKey, col string) string {
+	var terms []string
+	for _, term := range issueOrderTerms(keys, col) {
+		if term.desc {
+			terms = append(terms, term.expr+" DESC")
+		} else {
+			terms = append(terms, term.expr+" ASC")
+		}
 	}
-	defer func() { _ = rows.Close() }()
+	return strings.Join(terms, ", ")
+}
+
+// issueOrderTerms returns the terms of issueOrderBy
+func issueOrderTerms(keys []types.SortKey, col string) []orderTerm {
+	var terms []orderTerm
+	for _, key := range keys {
+		// Field names go into the query, so only known ones are used
+		if !slices.Contains(types.SortableIssueFields, key.Field) {
+			continue
+		}
+		expr := col + key.Field
+		switch key.Field {
+		case "assignee", "milestone":
+			terms = append(terms, orderTerm{expr: "COALESCE(" + expr + ", '') = ''"})
+		case "estimated_minutes", "closed_at":
+			terms = append(terms, orderTerm{expr: expr + " IS NULL"})
+		case "due_date", "start_date":
+			// Stored with differing time zone offsets, as in issueFilterClauses
+			expr = "datetime(" + expr + ")"
+			terms = append(terms, orderTerm{expr: expr + " IS NULL"})
+		}
+		terms = append(terms, orderTerm{expr: expr, desc: key.Desc})
+	}
+	return append(terms,
+		orderTerm{expr: col + "priority"},
+		orderTerm{expr: col + "created_at", desc: true},
+		orderTerm{expr: col + "id"})
+}
+
+// keysetAfter builds a WHERE clause matching the rows that sort after the
+// issue with id afterID under terms (unqualified), so a listing can resume
+// where a page left off. Its values are looked up by subquery rather than
+// passed in, which keeps their stored types. NULLs sort first, as in SQLite.
+func keysetAfter(terms []orderTerm, afterID string) (string, []interface{}) {
+	var alternatives []string
+	var args []interface{}
+	for k, term := range terms {
+		var parts []string
+		for _, prior := range terms[:k] {
+			parts = append(parts, fmt.Sprintf("(%[1]s) IS (SELECT %[1]s FROM issues WHERE id = ?)", prior.expr))
+			args = append(args, afterID)
+		}
+		last := fmt.Sprintf("(SELECT %s FROM issues WHERE id = ?)", term.expr)
+		if term.desc {
+			parts = append(parts, fmt.Sprintf("((%[1]s) < %[2]s OR ((%[1]s) IS NULL AND %[2]s IS NOT NULL))", term.expr, last))
+		} else {
+			parts = append(parts, fmt.Sprintf("((%[1]s) > %[2]s OR ((%[1]s) IS NOT NULL AND %[2]s IS NULL))", term.expr, last))
+		}
+		args = append(args, afterID, afterID)
+		alternatives = append(alternatives, "("+strings.Join(parts, " AND ")+")")
+	}
+	return "(" + strings.Join(alternatives, " OR ") + ")", args
+}
+
+// statusValues, issueTypeValues, intValues, and stringValues convert
+// multi-value filter criteria to query arguments
+func statusValues(statuses []types.Status) []interface{} {
+	values := make([]interface{}, len(statuses))
+	for i, status := range statuses {
+		values[i] = string(status)
+	}
+	return values
+}
+
+func issueTypeValues(issueTypes []types.IssueType) []interface{} {
+	values := make([]interface{}, len(issueTypes))
+	for i, issueType := range issueTypes {
+		values[i] = string(issueType)
+	}
+	return values
+}
+
+func intValues(ints []int) []interface{} {
+	values := make([]interface{}, len(ints))
+	for i, n := range ints {
+		values[i] = n
+	}
+	return values
+}
 
-	return s.scanIssues(ctx, rows)
+func stringValues(strs []string) []interface{} {
+	values := make([]interface{}, len(strs))
+	for i, s := range strs {
+		values[i] = s
+	}
+	return values
 }
 
 // SetConfig sets a configuration value
@@ -1891,7 +2798,7 @@ func (s *SQLiteStorage) SetConfig(ctx context.Context, key, value string) error
 // GetConfig gets a configuration value
 func (s *SQLiteStorage) GetConfig(ctx context.Context, key string) (string, error) {
 	var value string
-	err := s.db.QueryRowContext(ctx, `SELECT value FROM config WHERE key = ?`, key).Scan(&value)
+	err := s.reads.QueryRowContext(ctx, `SELECT value FROM config WHERE key = ?`, key).Scan(&value)
 	if err == sql.ErrNoRows {
 		return "", nil
 	}
@@ -1900,7 +2807,7 @@ func (s *SQLiteStorage) GetConfig(ctx context.Context, key string) (string, erro
 
 // GetAllConfig gets all configuration key-value pairs
 func (s *SQLiteStorage) GetAllConfig(ctx context.Context) (map[string]string, error) {
-	rows, err := s.db.QueryContext(ctx, `SELECT key, value FROM config ORDER BY key`)
+	rows, err := s.reads.QueryContext(ctx, `SELECT key, value FROM config ORDER BY key`)
 	if err != nil {
 		return nil, err
 	}
@@ -1935,91 +2842,22 @@ func (s *SQLiteStorage) SetMetadata(ctx context.Context, key, value string) erro
 // GetMetadata gets a metadata value (for internal state like import hashes)
 func (s *SQLiteStorage) GetMetadata(ctx context.Context, key string) (string, error) {
 	var value string
-	err := s.db.QueryRowContext(ctx, `SELECT value FROM metadata WHERE key = ?`, key).Scan(&value)
+	err := s.reads.QueryRowContext(ctx, `SELECT value FROM metadata WHERE key = ?`, key).Scan(&value)
 	if err == sql.ErrNoRows {
 		return "", nil
 	}
 	return value, err
 }
 
-// AddIssueComment adds a comment to an issue
-func (s *SQLiteStorage) AddIssueComment(ctx context.Context, issueID, author, text string) (*types.Comment, error) {
-	// Verify issue exists
-	var exists bool
-	err := s.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM issues WHERE id = ?)`, issueID).Scan(&exists)
-	if err != nil {
-		return nil, fmt.Errorf("failed to check issue existence: %w", err)
-	}
-	if !exists {
-		return nil, fmt.Errorf("issue %s not found", issueID)
-	}
-
-	// Insert comment
-	result, err := s.db.ExecContext(ctx, `
-		INSERT INTO comments (issue_id, author, text, created_at)
-		VALUES (?, ?, ?, CURRENT_TIMESTAMP)
-	`, issueID, author, text)
-	if err != nil {
-		return nil, fmt.Errorf("failed to insert comment: %w", err)
-	}
-
-	// Get the inserted comment ID
-	commentID, err := result.LastInsertId()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get comment ID: %w", err)
-	}
-
-	// Fetch the complete comment
-	comment := &types.Comment{}
-	err = s.db.QueryRowContext(ctx, `
-		SELECT id, issue_id, author, text, created_at
-		FROM comments WHERE id = ?
-	`, commentID).Scan(&comment.ID, &comment.IssueID, &comment.Author, &comment.Text, &comment.CreatedAt)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch comment: %w", err)
-	}
-
-	// Mark issue as dirty for JSONL export
-	if err := s.MarkIssueDirty(ctx, issueID); err != nil {
-		return nil, fmt.Errorf("failed to mark issue dirty: %w", err)
-	}
-
-	return comment, nil
-}
-
-// GetIssueComments retrieves all comments for an issue
-func (s *SQLiteStorage) GetIssueComments(ctx context.Context, issueID string) ([]*types.Comment, error) {
-	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, issue_id, author, text, created_at
-		FROM comments
-		WHERE issue_id = ?
-		ORDER BY created_at ASC
-	`, issueID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query comments: %w", err)
-	}
-	defer func() { _ = rows.Close() }()
-
-	var comments []*types.Comment
-	for rows.Next() {
-		comment := &types.Comment{}
-		err := rows.Scan(&comment.ID, &comment.IssueID, &comment.Author, &comment.Text, &comment.CreatedAt)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan comment: %w", err)
-		}
-		comments = append(comments, comment)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating comments: %w", err)
-	}
-
-	return comments, nil
-}
-
 // Close closes the database connection
 func (s *SQLiteStorage) Close() error {
 	s.closed.Store(true)
+	if s.reads != s.db {
+		_ = s.reads.Close()
+	}
+	if s.ext != s.db {
+		_ = s.ext.Close()
+	}
 	return s.db.Close()
 }
 
@@ -2036,9 +2874,10 @@ func (s *SQLiteStorage) IsClosed() bool {
 // UnderlyingDB returns the underlying *sql.DB connection for extensions.
 //
 // This allows extensions (like VC) to create their own tables in the same database
-// while leveraging the existing connection pool and schema. The returned *sql.DB is
-// safe for concurrent use and shares the same transaction isolation and locking
-// behavior as the core storage operations.
+// and schema. The returned *sql.DB is safe for concurrent use and has the same
+// PRAGMAs as the core storage operations, but is a pool of its own: the storage
+// writes through a single connection (see PoolConfig), which an extension holding
+// a transaction open would otherwise starve.
 //
 // IMPORTANT SAFETY RULES:
 //
@@ -2086,7 +2925,7 @@ func (s *SQLiteStorage) IsClosed() bool {
 //	`)
 //
 func (s *SQLiteStorage) UnderlyingDB() *sql.DB {
-	return s.db
+	return s.ext
 }
 
 // UnderlyingConn returns a single connection from the pool for scoped use.
@@ -2123,7 +2962,7 @@ func (s *SQLiteStorage) UnderlyingDB() *sql.DB {
 //	    )
 //	`)
 func (s *SQLiteStorage) UnderlyingConn(ctx context.Context) (*sql.Conn, error) {
-	return s.db.Conn(ctx)
+	return s.ext.Conn(ctx)
 }
 
 // CheckpointWAL checkpoints the WAL file to flush changes to the main database file.
@@ -2134,3 +2973,11 @@ func (s *SQLiteStorage) CheckpointWAL(ctx context.Context) error {
 	_, err := s.db.ExecContext(ctx, "PRAGMA wal_checkpoint(FULL)")
 	return err
 }
+
+// Snapshot writes a consistent copy of the database to path, which must not
+// exist. Writers are only held off while the copy is taken, and the copy is
+// compacted and needs no WAL.
+func (s *SQLiteStorage) Snapshot(ctx context.Context, path string) error {
+	_, err := s.db.ExecContext(ctx, "VACUUM INTO ?", path)
+	return err
+}
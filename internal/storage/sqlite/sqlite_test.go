@@ -2,8 +2,11 @@ package sqlite
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -969,6 +972,281 @@ func TestSearchIssues(t *testing.T) {
 	}
 }
 
+func TestSearchIssuesMultiValueFilters(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	issues := []*types.Issue{
+		{Title: "Open bug", Status: types.StatusOpen, Priority: 0, IssueType: types.TypeBug, Assignee: "alice"},
+		{Title: "Started feature", Status: types.StatusInProgress, Priority: 1, IssueType: types.TypeFeature},
+		{Title: "Blocked task", Status: types.StatusBlocked, Priority: 2, IssueType: types.TypeTask, Assignee: "bob"},
+		{Title: "Won't fix", Status: types.StatusOpen, Priority: 3, IssueType: types.TypeBug},
+	}
+	for _, issue := range issues {
+		if err := store.CreateIssue(ctx, issue, "test-user"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+	if err := store.AddLabel(ctx, issues[3].ID, "wontfix", "test-user"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		filter types.IssueFilter
+		want   []string
+	}{
+		{"any of several statuses", types.IssueFilter{Statuses: []types.Status{types.StatusOpen, types.StatusInProgress}},
+			[]string{"Open bug", "Started feature", "Won't fix"}},
+		{"excluded status", types.IssueFilter{ExcludeStatuses: []types.Status{types.StatusOpen}},
+			[]string{"Started feature", "Blocked task"}},
+		{"priorities", types.IssueFilter{Priorities: []int{0, 2}}, []string{"Open bug", "Blocked task"}},
+		{"excluded type", types.IssueFilter{ExcludeIssueTypes: []types.IssueType{types.TypeBug}},
+			[]string{"Started feature", "Blocked task"}},
+		{"unassigned", types.IssueFilter{Assignees: []string{""}}, []string{"Started feature", "Won't fix"}},
+		{"unassigned or bob", types.IssueFilter{Assignees: []string{"", "bob"}},
+			[]string{"Started feature", "Blocked task", "Won't fix"}},
+		{"assigned", types.IssueFilter{ExcludeAssignees: []string{""}}, []string{"Open bug", "Blocked task"}},
+		{"excluded label", types.IssueFilter{IssueTypes: []types.IssueType{types.TypeBug}, ExcludeLabels: []string{"wontfix"}},
+			[]string{"Open bug"}},
+	}
+	for _, tt := range tests {
+		results, err := store.SearchIssues(ctx, "", tt.filter)
+		if err != nil {
+			t.Fatalf("%s: SearchIssues failed: %v", tt.name, err)
+		}
+		got := make(map[string]bool)
+		for _, issue := range results {
+			got[issue.Title] = true
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: expected %v, got %d issues", tt.name, tt.want, len(results))
+			continue
+		}
+		for _, title := range tt.want {
+			if !got[title] {
+				t.Errorf("%s: expected %v, missing %q", tt.name, tt.want, title)
+			}
+		}
+	}
+}
+
+
+func TestSearchIssuesSort(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	due := time.Now().Add(48 * time.Hour)
+	for _, issue := range []*types.Issue{
+		{Title: "A", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, Assignee: "bob"},
+		{Title: "B", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask},
+		{Title: "C", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, Assignee: "alice", DueDate: &due},
+	} {
+		if err := store.CreateIssue(ctx, issue, "test-user"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{"", "BCA"}, // priority, then newest first
+		{"-priority,title", "ACB"},
+		{"assignee", "CAB"},
+		{"-assignee", "ACB"}, // unassigned still last
+		{"due_date,title", "CAB"},
+	}
+	for _, tt := range tests {
+		keys, err := types.ParseIssueSort(tt.spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		results, err := store.SearchIssues(ctx, "", types.IssueFilter{Sort: keys})
+		if err != nil {
+			t.Fatalf("SearchIssues(sort=%s) failed: %v", tt.spec, err)
+		}
+		got := ""
+		for _, issue := range results {
+			got += issue.Title
+		}
+		if got != tt.want {
+			t.Errorf("sort=%s: got %s, want %s", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestSearchIssuesQuery(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	due := time.Now().Add(72 * time.Hour)
+	issues := []*types.Issue{
+		{Title: "Backend bug", Status: types.StatusOpen, Priority: 0, IssueType: types.TypeBug},
+		{Title: "Alice's task", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, Assignee: "alice", DueDate: &due},
+		{Title: "Database tuning", Description: "Slow login queries", Status: types.StatusInProgress, Priority: 3, IssueType: types.TypeTask},
+	}
+	for _, issue := range issues {
+		if err := store.CreateIssue(ctx, issue, "test-user"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+	for id, label := range map[string]string{issues[0].ID: "backend", issues[2].ID: "infra/db"} {
+		if err := store.AddLabel(ctx, id, label, "test-user"); err != nil {
+			t.Fatalf("AddLabel failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"status:open AND (label:backend OR assignee:alice) AND updated:<7d", "Alice's task, Backend bug"},
+		{"label:infra/* OR priority:0", "Backend bug, Database tuning"},
+		{"priority:>=2 -assignee:unassigned", "Alice's task"},
+		{"NOT due:<+7d", "Backend bug, Database tuning"},
+		{"login type:task,bug", "Database tuning"},
+		{`title:"s task"`, "Alice's task"},
+		{"updated:>7d", ""},
+	}
+	for _, tt := range tests {
+		q, err := types.ParseQuery(tt.query, time.Now())
+		if err != nil {
+			t.Fatalf("ParseQuery(%q) failed: %v", tt.query, err)
+		}
+		results, err := store.SearchIssues(ctx, "", types.IssueFilter{Query: q})
+		if err != nil {
+			t.Fatalf("SearchIssues(%q) failed: %v", tt.query, err)
+		}
+		var titles []string
+		for _, issue := range results {
+			titles = append(titles, issue.Title)
+		}
+		sort.Strings(titles)
+		if got := strings.Join(titles, ", "); got != tt.want {
+			t.Errorf("%q: got %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+func TestForEachIssue(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// Enough issues for several batches
+	issues := make([]*types.Issue, 2*idBatchSize+7)
+	for i := range issues {
+		issues[i] = &types.Issue{Title: "Issue", Status: types.StatusOpen, Priority: i % 4, IssueType: types.TypeTask}
+	}
+	if err := store.CreateIssues(ctx, issues, "test-user"); err != nil {
+		t.Fatalf("CreateIssues failed: %v", err)
+	}
+	last := issues[len(issues)-1]
+	if err := store.AddLabel(ctx, last.ID, "tail", "test-user"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+
+	filter := types.IssueFilter{Sort: []types.SortKey{{Field: "priority"}, {Field: "id"}}}
+	want, err := store.SearchIssues(ctx, "", filter)
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+	var got []*types.Issue
+	if err := store.ForEachIssue(ctx, filter, func(issue *types.Issue) error {
+		got = append(got, issue)
+		return nil
+	}); err != nil {
+		t.Fatalf("ForEachIssue failed: %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d issues, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if got[i].ID != want[i].ID || strings.Join(got[i].Labels, ",") != strings.Join(want[i].Labels, ",") {
+			t.Fatalf("Issue %d: got %s %v, want %s %v", i, got[i].ID, got[i].Labels, want[i].ID, want[i].Labels)
+		}
+	}
+
+	// fn's error stops the iteration
+	stop := fmt.Errorf("stop")
+	seen := 0
+	err = store.ForEachIssue(ctx, types.IssueFilter{}, func(issue *types.Issue) error {
+		seen++
+		return stop
+	})
+	if err != stop || seen != 1 {
+		t.Errorf("Expected to stop after one issue with fn's error, got %d issues and %v", seen, err)
+	}
+}
+
+func TestForEachIssueSingleReadConnection(t *testing.T) {
+	store, err := NewWithConfig(filepath.Join(t.TempDir(), "test.db"), PoolConfig{ReadConnections: 1})
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	// A deadlock fails the test rather than hanging it
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+
+	// More than a page, with ties and missing values in the sort keys
+	issues := make([]*types.Issue, idBatchSize+40)
+	for i := range issues {
+		issues[i] = &types.Issue{Title: fmt.Sprintf("Issue %d", i), Status: types.StatusOpen, Priority: i % 3, IssueType: types.TypeTask}
+		if i%4 == 0 {
+			issues[i].Assignee = fmt.Sprintf("user%d", i%3)
+		}
+		if i%5 == 0 {
+			minutes := i % 7
+			issues[i].EstimatedMinutes = &minutes
+		}
+	}
+	if err := store.CreateIssues(ctx, issues, "test-user"); err != nil {
+		t.Fatalf("CreateIssues failed: %v", err)
+	}
+
+	for _, filter := range []types.IssueFilter{
+		{},
+		{WithCounts: true},
+		{Sort: []types.SortKey{{Field: "assignee"}}},
+		{Sort: []types.SortKey{{Field: "estimated_minutes", Desc: true}, {Field: "title"}}},
+		{Limit: idBatchSize + 3},
+	} {
+		want, err := store.SearchIssues(ctx, "", filter)
+		if err != nil {
+			t.Fatalf("SearchIssues failed: %v", err)
+		}
+		var got []string
+		// fn reads the store too, which needs the only read connection
+		err = store.ForEachIssue(ctx, filter, func(issue *types.Issue) error {
+			got = append(got, issue.ID)
+			_, err := store.GetLabels(ctx, issue.ID)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("ForEachIssue(%+v) failed: %v", filter, err)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("ForEachIssue(%+v): expected %d issues, got %d", filter, len(want), len(got))
+		}
+		for i := range want {
+			if got[i] != want[i].ID {
+				t.Fatalf("ForEachIssue(%+v): issue %d is %s, want %s", filter, i, got[i], want[i].ID)
+			}
+		}
+	}
+}
+
 func TestGetStatistics(t *testing.T) {
 	store, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -0,0 +1,34 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// MarkStaleNudged records that a stale issue was nudged at the given time
+func (s *SQLiteStorage) MarkStaleNudged(ctx context.Context, issueID string, at time.Time) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO stale_nudges (issue_id, nudged_at)
+		VALUES (?, ?)
+		ON CONFLICT(issue_id) DO UPDATE SET nudged_at = excluded.nudged_at
+	`, issueID, at)
+	if err != nil {
+		return fmt.Errorf("failed to record stale nudge: %w", err)
+	}
+	return nil
+}
+
+// GetStaleNudge returns when an issue was last nudged, or nil if it never was
+func (s *SQLiteStorage) GetStaleNudge(ctx context.Context, issueID string) (*time.Time, error) {
+	var at time.Time
+	err := s.reads.QueryRowContext(ctx, `SELECT nudged_at FROM stale_nudges WHERE issue_id = ?`, issueID).Scan(&at)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stale nudge: %w", err)
+	}
+	return &at, nil
+}
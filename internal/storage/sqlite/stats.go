@@ -0,0 +1,75 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// groupedStatsQueries count issues out of the trash by a dimension and
+// status. Grouping by label counts an issue under each of its labels, and
+// issues without labels under the empty key.
+var groupedStatsQueries = map[string]string{
+	types.GroupByStatus: `
+		SELECT status, status, COUNT(*) FROM issues
+		WHERE deleted_at IS NULL GROUP BY 1, 2`,
+	types.GroupByAssignee: `
+		SELECT COALESCE(assignee, ''), status, COUNT(*) FROM issues
+		WHERE deleted_at IS NULL GROUP BY 1, 2`,
+	types.GroupByType: `
+		SELECT issue_type, status, COUNT(*) FROM issues
+		WHERE deleted_at IS NULL GROUP BY 1, 2`,
+	types.GroupByPriority: `
+		SELECT CAST(priority AS TEXT), status, COUNT(*) FROM issues
+		WHERE deleted_at IS NULL GROUP BY 1, 2`,
+	types.GroupByLabel: `
+		SELECT l.label, i.status, COUNT(*) FROM labels l
+		JOIN issues i ON i.id = l.issue_id
+		WHERE i.deleted_at IS NULL GROUP BY 1, 2
+		UNION ALL
+		SELECT '', status, COUNT(*) FROM issues i
+		WHERE deleted_at IS NULL AND NOT EXISTS (SELECT 1 FROM labels l WHERE l.issue_id = i.id)
+		GROUP BY 2`,
+}
+
+// GetGroupedStatistics counts issues by groupBy (one of the types.GroupBy*
+// dimensions) and status
+func (s *SQLiteStorage) GetGroupedStatistics(ctx context.Context, groupBy string) ([]*types.StatGroup, error) {
+	ctx, span := startSpan(ctx, "GetGroupedStatistics")
+	defer span.End()
+
+	query, ok := groupedStatsQueries[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("can't group statistics by %q", groupBy)
+	}
+	rows, err := s.reads.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statistics by %s: %w", groupBy, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	byKey := make(map[string]*types.StatGroup)
+	var groups []*types.StatGroup
+	for rows.Next() {
+		var key string
+		var status types.Status
+		var n int
+		if err := rows.Scan(&key, &status, &n); err != nil {
+			return nil, fmt.Errorf("failed to scan statistics: %w", err)
+		}
+		g := byKey[key]
+		if g == nil {
+			g = &types.StatGroup{Key: key, ByStatus: make(map[types.Status]int)}
+			byKey[key] = g
+			groups = append(groups, g)
+		}
+		g.ByStatus[status] += n
+		g.Total += n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	types.SortStatGroups(groupBy, groups)
+	return groups, nil
+}
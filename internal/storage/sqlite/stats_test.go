@@ -0,0 +1,73 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestGetGroupedStatistics(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	create := func(title, assignee string, priority int, status types.Status, labels ...string) *types.Issue {
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: priority, IssueType: types.TypeTask, Assignee: assignee}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatal(err)
+		}
+		if status != types.StatusOpen {
+			if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"status": string(status)}, "test"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for _, label := range labels {
+			if err := store.AddLabel(ctx, issue.ID, label, "test"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return issue
+	}
+	create("A", "alice", 1, types.StatusOpen, "ui", "api")
+	create("B", "alice", 2, types.StatusInProgress, "ui")
+	create("C", "bob", 2, types.StatusOpen)
+	create("D", "", 0, types.StatusOpen)
+	trashed := create("E", "bob", 1, types.StatusOpen, "ui")
+	if err := store.SoftDeleteIssue(ctx, trashed.ID, "test"); err != nil {
+		t.Fatal(err)
+	}
+
+	groups, err := store.GetGroupedStatistics(ctx, types.GroupByAssignee)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 3 || groups[0].Key != "alice" || groups[0].Total != 2 || groups[0].ByStatus[types.StatusInProgress] != 1 ||
+		groups[1].Key != "bob" || groups[1].Total != 1 || groups[2].Key != "" {
+		t.Errorf("Unexpected groups by assignee %+v %+v %+v", groups[0], groups[1], groups[2])
+	}
+
+	groups, err = store.GetGroupedStatistics(ctx, types.GroupByLabel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byKey := make(map[string]int)
+	for _, g := range groups {
+		byKey[g.Key] = g.Total
+	}
+	if len(groups) != 3 || byKey["ui"] != 2 || byKey["api"] != 1 || byKey[""] != 2 || groups[len(groups)-1].Key != "" {
+		t.Errorf("Unexpected groups by label %v", byKey)
+	}
+
+	groups, err = store.GetGroupedStatistics(ctx, types.GroupByPriority)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 3 || groups[0].Key != "0" || groups[1].Key != "1" || groups[2].Key != "2" || groups[2].Total != 2 {
+		t.Errorf("Expected groups in priority order, got %+v %+v %+v", groups[0], groups[1], groups[2])
+	}
+
+	if _, err := store.GetGroupedStatistics(ctx, "milestone"); err == nil {
+		t.Error("Expected an unknown dimension refused")
+	}
+}
@@ -0,0 +1,20 @@
+package sqlite
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer creates spans for storage calls. Until tracing is set up (see the
+// telemetry package) it drops them.
+var tracer = otel.Tracer("github.com/imalsogreg/beads/internal/storage/sqlite")
+
+// startSpan starts a span for a storage call as a child of the caller's span
+// in ctx, so a traced HTTP request shows where its time went
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, attribute.String("db.system.name", "sqlite"))
+	return tracer.Start(ctx, "sqlite."+name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
@@ -0,0 +1,184 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// DefaultTrashDays is how long deleted issues stay in the trash before
+// compaction purges them, unless compact_trash_days says otherwise
+const DefaultTrashDays = 30
+
+var (
+	// ErrAlreadyInTrash is returned when deleting an issue that's already deleted
+	ErrAlreadyInTrash = errors.New("issue is already in the trash")
+	// ErrNotInTrash is returned when restoring an issue that isn't deleted
+	ErrNotInTrash = errors.New("issue is not in the trash")
+)
+
+// SoftDeleteIssue moves an issue to the trash. It keeps its dependencies,
+// labels, comments, and history, but is hidden from listings, search, ready
+// work, and statistics until restored or purged.
+func (s *SQLiteStorage) SoftDeleteIssue(ctx context.Context, id string, actor string) error {
+	return s.setDeleted(ctx, id, actor, true)
+}
+
+// RestoreIssue takes an issue back out of the trash
+func (s *SQLiteStorage) RestoreIssue(ctx context.Context, id string, actor string) error {
+	return s.setDeleted(ctx, id, actor, false)
+}
+
+func (s *SQLiteStorage) setDeleted(ctx context.Context, id string, actor string, deleted bool) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var deletedAt sql.NullTime
+	err = tx.QueryRowContext(ctx, `SELECT deleted_at FROM issues WHERE id = ?`, id).Scan(&deletedAt)
+	if err == sql.ErrNoRows {
+		return types.NotFound("issue", id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get issue: %w", err)
+	}
+
+	now := time.Now()
+	eventType := types.EventDeleted
+	change := FieldChange{Field: "deleted_at", New: now.UTC().Format(time.RFC3339)}
+	if deleted {
+		if deletedAt.Valid {
+			return ErrAlreadyInTrash
+		}
+		_, err = tx.ExecContext(ctx, `UPDATE issues SET deleted_at = ?, deleted_by = ?, updated_at = ? WHERE id = ?`, now, actor, now, id)
+	} else {
+		if !deletedAt.Valid {
+			return ErrNotInTrash
+		}
+		eventType = types.EventRestored
+		change = FieldChange{Field: "deleted_at", Old: deletedAt.Time.UTC().Format(time.RFC3339)}
+		_, err = tx.ExecContext(ctx, `UPDATE issues SET deleted_at = NULL, deleted_by = NULL, updated_at = ? WHERE id = ?`, now, id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update issue: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO events (issue_id, event_type, actor, session_id)
+		VALUES (?, ?, ?, ?)
+	`, id, eventType, actor, s.eventSession(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to record event: %w", err)
+	}
+	if err := s.recordRevision(ctx, tx, &IssueRevision{IssueID: id, Kind: eventType, Actor: actor, Changes: []FieldChange{change}}); err != nil {
+		return err
+	}
+
+	// Mark issue as dirty for incremental export
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO dirty_issues (issue_id, marked_at)
+		VALUES (?, ?)
+		ON CONFLICT (issue_id) DO UPDATE SET marked_at = excluded.marked_at
+	`, id, now)
+	if err != nil {
+		return fmt.Errorf("failed to mark issue dirty: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// ListTrash returns the issues in the trash, most recently deleted first
+func (s *SQLiteStorage) ListTrash(ctx context.Context) ([]*types.Issue, error) {
+	rows, err := s.reads.QueryContext(ctx, `
+		SELECT id FROM issues
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trash: %w", err)
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	issues := make([]*types.Issue, 0, len(ids))
+	for _, id := range ids {
+		issue, err := s.GetIssue(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if issue != nil {
+			issues = append(issues, issue)
+		}
+	}
+	return issues, nil
+}
+
+// TrashDays returns how many days issues stay in the trash (config
+// compact_trash_days); 0 means they are kept until purged by hand
+func (s *SQLiteStorage) TrashDays(ctx context.Context) (int, error) {
+	value, err := s.GetConfig(ctx, "compact_trash_days")
+	if err != nil {
+		return 0, err
+	}
+	if value == "" {
+		return DefaultTrashDays, nil
+	}
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 0 {
+		return 0, fmt.Errorf("invalid compact_trash_days %q: must be a whole number of days", value)
+	}
+	return days, nil
+}
+
+// PurgeTrash permanently deletes issues that were moved to the trash at or
+// before cutoff, returning their IDs. Dependencies on them are removed. With
+// dryRun nothing is deleted.
+func (s *SQLiteStorage) PurgeTrash(ctx context.Context, cutoff time.Time, dryRun bool) ([]string, error) {
+	rows, err := s.reads.QueryContext(ctx, `
+		SELECT id FROM issues
+		WHERE deleted_at IS NOT NULL AND deleted_at <= ?
+		ORDER BY id
+	`, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find expired trash: %w", err)
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 || dryRun {
+		return ids, nil
+	}
+	if _, err := s.DeleteIssues(ctx, ids, false, true, false); err != nil {
+		return nil, fmt.Errorf("failed to purge trash: %w", err)
+	}
+	return ids, nil
+}
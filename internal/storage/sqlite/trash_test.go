@@ -0,0 +1,112 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestSoftDeleteAndRestore(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	blocker := &types.Issue{Title: "Blocker", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	blocked := &types.Issue{Title: "Blocked", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	for _, issue := range []*types.Issue{blocker, blocked} {
+		if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+	dep := &types.Dependency{IssueID: blocked.ID, DependsOnID: blocker.ID, Type: types.DepBlocks}
+	if err := store.AddDependency(ctx, dep, "alice"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+
+	if err := store.SoftDeleteIssue(ctx, blocker.ID, "bob"); err != nil {
+		t.Fatalf("SoftDeleteIssue failed: %v", err)
+	}
+	if err := store.SoftDeleteIssue(ctx, blocker.ID, "bob"); !errors.Is(err, ErrAlreadyInTrash) {
+		t.Errorf("Expected ErrAlreadyInTrash, got %v", err)
+	}
+
+	// Hidden from listings, and no longer blocks
+	issues, _ := store.SearchIssues(ctx, "", types.IssueFilter{})
+	if len(issues) != 1 || issues[0].ID != blocked.ID {
+		t.Errorf("Expected only %s listed, got %d issues", blocked.ID, len(issues))
+	}
+	ready, _ := store.GetReadyWork(ctx, types.WorkFilter{})
+	if len(ready) != 1 || ready[0].ID != blocked.ID {
+		t.Errorf("Expected %s to be ready once its blocker is deleted", blocked.ID)
+	}
+
+	// Still readable by ID, and listed in the trash
+	got, _ := store.GetIssue(ctx, blocker.ID)
+	if got == nil || got.DeletedAt == nil || got.DeletedBy != "bob" {
+		t.Fatalf("Expected deleted issue with deleted_by bob, got %+v", got)
+	}
+	trash, err := store.ListTrash(ctx)
+	if err != nil {
+		t.Fatalf("ListTrash failed: %v", err)
+	}
+	if len(trash) != 1 || trash[0].ID != blocker.ID {
+		t.Errorf("Expected %s in the trash, got %d issues", blocker.ID, len(trash))
+	}
+
+	if err := store.RestoreIssue(ctx, blocker.ID, "bob"); err != nil {
+		t.Fatalf("RestoreIssue failed: %v", err)
+	}
+	if err := store.RestoreIssue(ctx, blocker.ID, "bob"); !errors.Is(err, ErrNotInTrash) {
+		t.Errorf("Expected ErrNotInTrash, got %v", err)
+	}
+	if ready, _ = store.GetReadyWork(ctx, types.WorkFilter{}); len(ready) != 1 || ready[0].ID != blocker.ID {
+		t.Errorf("Expected the restored blocker to block again")
+	}
+	if history, _ := store.GetIssueHistory(ctx, blocker.ID); len(history) != 3 || history[2].Kind != types.EventRestored {
+		t.Errorf("Expected delete and restore in history, got %d revisions", len(history))
+	}
+}
+
+func TestPurgeTrash(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	issue := &types.Issue{Title: "Old", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	if err := store.SoftDeleteIssue(ctx, issue.ID, "alice"); err != nil {
+		t.Fatalf("SoftDeleteIssue failed: %v", err)
+	}
+
+	// Deleted just now, so not expired yet
+	ids, err := store.PurgeTrash(ctx, time.Now().Add(-time.Hour), false)
+	if err != nil || len(ids) != 0 {
+		t.Fatalf("Expected nothing to purge, got %v, %v", ids, err)
+	}
+
+	ids, err = store.PurgeTrash(ctx, time.Now(), true)
+	if err != nil || len(ids) != 1 {
+		t.Fatalf("Expected dry run to find 1 issue, got %v, %v", ids, err)
+	}
+	if got, _ := store.GetIssue(ctx, issue.ID); got == nil {
+		t.Fatal("Dry run should not delete")
+	}
+
+	if _, err := store.PurgeTrash(ctx, time.Now(), false); err != nil {
+		t.Fatalf("PurgeTrash failed: %v", err)
+	}
+	if got, _ := store.GetIssue(ctx, issue.ID); got != nil {
+		t.Error("Expected issue to be purged")
+	}
+
+	if days, err := store.TrashDays(ctx); err != nil || days != DefaultTrashDays {
+		t.Errorf("Expected default of %d trash days, got %d, %v", DefaultTrashDays, days, err)
+	}
+}
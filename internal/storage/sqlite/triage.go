@@ -0,0 +1,388 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// TriageActor is who the changes triage rules make are recorded as
+const TriageActor = "triage"
+
+// When a triage rule runs
+const (
+	TriageOnCreate = "create"
+	TriageOnUpdate = "update"
+	TriageOnAny    = "any"
+)
+
+// TriageRule labels, prioritizes, or assigns issues as they are created or
+// updated. An issue matches when it meets every condition; empty conditions
+// match everything. Title and description matches are Go regular
+// expressions, so (?i)timeout ignores case.
+type TriageRule struct {
+	ID               int64     `json:"id"`
+	Name             string    `json:"name"`
+	On               string    `json:"on" enum:"create,update,any" doc:"When the rule runs; defaults to create"`
+	TitleMatch       string    `json:"title_match,omitempty" doc:"Regular expression the title must match, e.g. (?i)timeout"`
+	DescriptionMatch string    `json:"description_match,omitempty" doc:"Regular expression the description must match"`
+	IssueType        string    `json:"issue_type,omitempty" enum:"bug,feature,task,epic,chore"`
+	Priority         *int      `json:"priority,omitempty" doc:"Only issues at this priority"`
+	AddLabels        []string  `json:"add_labels,omitempty"`
+	SetPriority      *int      `json:"set_priority,omitempty"`
+	SetAssignee      string    `json:"set_assignee,omitempty"`
+	CreatedBy        string    `json:"created_by"`
+	CreatedAt        time.Time `json:"created_at"`
+
+	title, description *regexp.Regexp
+}
+
+// Describe returns a one-line summary of the rule
+func (r *TriageRule) Describe() string {
+	var conditions []string
+	if r.IssueType != "" {
+		conditions = append(conditions, "type "+r.IssueType)
+	}
+	if r.Priority != nil {
+		conditions = append(conditions, fmt.Sprintf("P%d", *r.Priority))
+	}
+	if r.TitleMatch != "" {
+		conditions = append(conditions, fmt.Sprintf("title /%s/", r.TitleMatch))
+	}
+	if r.DescriptionMatch != "" {
+		conditions = append(conditions, fmt.Sprintf("description /%s/", r.DescriptionMatch))
+	}
+	when := "on " + r.On
+	if r.On == TriageOnAny {
+		when = "on create or update"
+	}
+	if len(conditions) > 0 {
+		when += " with " + strings.Join(conditions, ", ")
+	}
+
+	var actions []string
+	if len(r.AddLabels) > 0 {
+		actions = append(actions, "add "+strings.Join(r.AddLabels, ", "))
+	}
+	if r.SetPriority != nil {
+		actions = append(actions, fmt.Sprintf("set P%d", *r.SetPriority))
+	}
+	if r.SetAssignee != "" {
+		actions = append(actions, "assign "+r.SetAssignee)
+	}
+	return when + ": " + strings.Join(actions, " and ")
+}
+
+// TriageResult is what the rules matching an issue did, or in a dry run
+// would do, to it. Only real changes are listed: labels it lacked and a
+// priority or assignee different from its own.
+type TriageResult struct {
+	IssueID   string   `json:"issue_id,omitempty"`
+	Matched   []string `json:"matched"` // rule names, in order
+	AddLabels []string `json:"add_labels,omitempty"`
+	Priority  *int     `json:"priority,omitempty"`
+	Assignee  string   `json:"assignee,omitempty"`
+}
+
+// Changed reports whether the result changes the issue
+func (r *TriageResult) Changed() bool {
+	return len(r.AddLabels) > 0 || r.Priority != nil || r.Assignee != ""
+}
+
+var (
+	// ErrTriageRuleNotFound is returned for an unknown rule name
+	ErrTriageRuleNotFound = errors.New("triage rule not found")
+
+	// ErrTriageRuleExists is returned when adding a rule under a name in use
+	ErrTriageRuleExists = errors.New("triage rule already exists")
+)
+
+type noTriageKey struct{}
+
+// WithoutTriage keeps the changes made with ctx from running triage rules,
+// for imports of issues already triaged where they came from
+func WithoutTriage(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noTriageKey{}, true)
+}
+
+func triageDisabled(ctx context.Context) bool {
+	disabled, _ := ctx.Value(noTriageKey{}).(bool)
+	return disabled || isReplicating(ctx)
+}
+
+// AddTriageRule validates and stores a triage rule, setting its ID
+func (s *SQLiteStorage) AddTriageRule(ctx context.Context, rule *TriageRule) error {
+	rule.Name = strings.TrimSpace(rule.Name)
+	if rule.Name == "" {
+		return fmt.Errorf("triage rule name is required")
+	}
+	if rule.On == "" {
+		rule.On = TriageOnCreate
+	}
+	if err := rule.compile(); err != nil {
+		return err
+	}
+	if rule.IssueType != "" && !types.IssueType(rule.IssueType).IsValid() {
+		return fmt.Errorf("invalid issue type %q", rule.IssueType)
+	}
+	for _, p := range []*int{rule.Priority, rule.SetPriority} {
+		if p != nil && (*p < 0 || *p > 4) {
+			return fmt.Errorf("priority must be between 0 and 4 (got %d)", *p)
+		}
+	}
+	var labels []string
+	for _, label := range rule.AddLabels {
+		if label = strings.TrimSpace(label); label != "" && !slices.Contains(labels, label) {
+			labels = append(labels, label)
+		}
+	}
+	rule.AddLabels = labels
+	if len(rule.AddLabels) == 0 && rule.SetPriority == nil && rule.SetAssignee == "" {
+		return fmt.Errorf("triage rule needs a label to add, a priority to set, or an assignee")
+	}
+	assignee, err := s.ResolveActor(ctx, rule.SetAssignee)
+	if err != nil {
+		return err
+	}
+	rule.SetAssignee = assignee
+
+	rule.CreatedAt = time.Now()
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO triage_rules (name, on_event, title_match, description_match, issue_type, priority,
+			add_labels, set_priority, set_assignee, created_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, rule.Name, rule.On, rule.TitleMatch, rule.DescriptionMatch, rule.IssueType, rule.Priority,
+		strings.Join(rule.AddLabels, ","), rule.SetPriority, rule.SetAssignee, rule.CreatedBy, rule.CreatedAt)
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			return fmt.Errorf("%w: %s", ErrTriageRuleExists, rule.Name)
+		}
+		return fmt.Errorf("failed to add triage rule: %w", err)
+	}
+	rule.ID, err = res.LastInsertId()
+	return err
+}
+
+// compile checks the rule's trigger and compiles its patterns
+func (r *TriageRule) compile() error {
+	switch r.On {
+	case TriageOnCreate, TriageOnUpdate, TriageOnAny:
+	default:
+		return fmt.Errorf("invalid trigger %q (want create, update, or any)", r.On)
+	}
+	var err error
+	if r.title, err = compileTriagePattern("title", r.TitleMatch); err != nil {
+		return err
+	}
+	r.description, err = compileTriagePattern("description", r.DescriptionMatch)
+	return err
+}
+
+func compileTriagePattern(field, pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s pattern: %w", field, err)
+	}
+	return re, nil
+}
+
+// GetTriageRules returns every triage rule in the order they run
+func (s *SQLiteStorage) GetTriageRules(ctx context.Context) ([]*TriageRule, error) {
+	rows, err := s.reads.QueryContext(ctx, `
+		SELECT id, name, on_event, title_match, description_match, issue_type, priority,
+		       add_labels, set_priority, set_assignee, created_by, created_at
+		FROM triage_rules ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query triage rules: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var rules []*TriageRule
+	for rows.Next() {
+		var r TriageRule
+		var priority, setPriority sql.NullInt64
+		var labels string
+		if err := rows.Scan(&r.ID, &r.Name, &r.On, &r.TitleMatch, &r.DescriptionMatch, &r.IssueType, &priority,
+			&labels, &setPriority, &r.SetAssignee, &r.CreatedBy, &r.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan triage rule: %w", err)
+		}
+		if priority.Valid {
+			p := int(priority.Int64)
+			r.Priority = &p
+		}
+		if setPriority.Valid {
+			p := int(setPriority.Int64)
+			r.SetPriority = &p
+		}
+		if labels != "" {
+			r.AddLabels = strings.Split(labels, ",")
+		}
+		if err := r.compile(); err != nil {
+			return nil, fmt.Errorf("triage rule %s: %w", r.Name, err)
+		}
+		rules = append(rules, &r)
+	}
+	return rules, rows.Err()
+}
+
+// RemoveTriageRule deletes a triage rule by name
+func (s *SQLiteStorage) RemoveTriageRule(ctx context.Context, name string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM triage_rules WHERE name = ?`, name)
+	if err != nil {
+		return fmt.Errorf("failed to remove triage rule: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%w: %s", ErrTriageRuleNotFound, name)
+	}
+	return nil
+}
+
+// runs reports whether the rule runs on trigger
+func (r *TriageRule) runs(trigger string) bool {
+	return r.On == TriageOnAny || r.On == trigger
+}
+
+// matches reports whether issue meets every condition of the rule
+func (r *TriageRule) matches(issue *types.Issue) bool {
+	if r.IssueType != "" && r.IssueType != string(issue.IssueType) {
+		return false
+	}
+	if r.Priority != nil && *r.Priority != issue.Priority {
+		return false
+	}
+	if r.title != nil && !r.title.MatchString(issue.Title) {
+		return false
+	}
+	return r.description == nil || r.description.MatchString(issue.Description)
+}
+
+// evaluateTriage runs rules against issue, which carries labels. On update,
+// before is the issue as it was, and only rules it didn't already match
+// fire, so a rule doesn't undo a later manual change. Conditions are checked
+// against the issue as given; where rules disagree, the last one's priority
+// or assignee wins.
+func evaluateTriage(rules []*TriageRule, trigger string, before, issue *types.Issue, labels []string) *TriageResult {
+	result := &TriageResult{IssueID: issue.ID, Matched: []string{}}
+	var priority *int
+	var assignee string
+	for _, rule := range rules {
+		if !rule.runs(trigger) || !rule.matches(issue) || (before != nil && rule.matches(before)) {
+			continue
+		}
+		result.Matched = append(result.Matched, rule.Name)
+		for _, label := range rule.AddLabels {
+			if !slices.Contains(labels, label) && !slices.Contains(result.AddLabels, label) {
+				result.AddLabels = append(result.AddLabels, label)
+			}
+		}
+		if rule.SetPriority != nil {
+			priority = rule.SetPriority
+		}
+		if rule.SetAssignee != "" {
+			assignee = rule.SetAssignee
+		}
+	}
+	if priority != nil && *priority != issue.Priority {
+		result.Priority = priority
+	}
+	if assignee != issue.Assignee {
+		result.Assignee = assignee
+	}
+	return result
+}
+
+// EvaluateTriageRules reports which rules running on trigger match issue,
+// and what they would change, without changing anything. The issue need not
+// exist; labels are the ones it has.
+func (s *SQLiteStorage) EvaluateTriageRules(ctx context.Context, issue *types.Issue, labels []string, trigger string) (*TriageResult, error) {
+	switch trigger {
+	case TriageOnCreate, TriageOnUpdate:
+	default:
+		return nil, fmt.Errorf("invalid trigger %q (want create or update)", trigger)
+	}
+	rules, err := s.GetTriageRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return evaluateTriage(rules, trigger, nil, issue, labels), nil
+}
+
+// triage runs the rules for trigger against issue id, just written, and
+// applies what they change as TriageActor in one transaction. before is the
+// issue ahead of an update, nil on create. Closed issues, imports, and
+// other replicas' ops aren't triaged.
+func (s *SQLiteStorage) triage(ctx context.Context, trigger string, before *types.Issue, id string) (*TriageResult, error) {
+	if triageDisabled(ctx) {
+		return nil, nil
+	}
+	rules, err := s.GetTriageRules(ctx)
+	if err != nil || len(rules) == 0 {
+		return nil, err
+	}
+	issue, err := s.GetIssue(ctx, id)
+	if err != nil || issue == nil || issue.Status == types.StatusClosed {
+		return nil, err
+	}
+	labels, err := s.GetLabels(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("triage rules failed: %w", err)
+	}
+
+	result := evaluateTriage(rules, trigger, before, issue, labels)
+	if !result.Changed() {
+		return result, nil
+	}
+
+	updates := make(map[string]interface{})
+	if result.Priority != nil {
+		updates["priority"] = *result.Priority
+	}
+	if result.Assignee != "" {
+		updates["assignee"] = result.Assignee
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("triage rules failed: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if len(updates) > 0 {
+		if err := s.updateIssueInTx(ctx, tx, issue, updates, TriageActor, 0); err != nil {
+			return nil, fmt.Errorf("triage rules failed: %w", err)
+		}
+	}
+	for _, label := range result.AddLabels {
+		if err := s.labelOperationInTx(ctx, tx, id, label, TriageActor, true, 0); err != nil {
+			return nil, fmt.Errorf("triage rules failed: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("triage rules failed: %w", err)
+	}
+	return result, nil
+}
+
+// applyTo copies the priority and assignee triage set onto issue
+func (r *TriageResult) applyTo(issue *types.Issue) {
+	if r == nil {
+		return
+	}
+	if r.Priority != nil {
+		issue.Priority = *r.Priority
+	}
+	if r.Assignee != "" {
+		issue.Assignee = r.Assignee
+	}
+}
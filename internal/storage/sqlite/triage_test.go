@@ -0,0 +1,104 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestTriageRules(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	one := 1
+	network := &TriageRule{Name: "network", TitleMatch: "(?i)timeout", AddLabels: []string{"network", " network "}, SetPriority: &one}
+	if err := store.AddTriageRule(ctx, network); err != nil {
+		t.Fatalf("AddTriageRule failed: %v", err)
+	}
+	if network.On != TriageOnCreate || len(network.AddLabels) != 1 {
+		t.Errorf("Expected a create rule adding one label, got %+v", network)
+	}
+	if err := store.AddTriageRule(ctx, &TriageRule{Name: "network", AddLabels: []string{"x"}}); !errors.Is(err, ErrTriageRuleExists) {
+		t.Errorf("Expected ErrTriageRuleExists, got %v", err)
+	}
+	for _, bad := range []*TriageRule{
+		{Name: "nothing", TitleMatch: "x"},
+		{Name: "pattern", TitleMatch: "(", AddLabels: []string{"x"}},
+		{Name: "trigger", On: "close", AddLabels: []string{"x"}},
+	} {
+		if err := store.AddTriageRule(ctx, bad); err == nil {
+			t.Errorf("Expected rule %s to be rejected", bad.Name)
+		}
+	}
+	bugs := &TriageRule{Name: "bugs", On: TriageOnUpdate, IssueType: "bug", SetAssignee: "oncall"}
+	if err := store.AddTriageRule(ctx, bugs); err != nil {
+		t.Fatalf("AddTriageRule failed: %v", err)
+	}
+
+	// A matching create is triaged, and the caller's issue reflects it
+	issue := &types.Issue{Title: "Login Timeout on mobile", Status: types.StatusOpen, Priority: 3, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	if issue.Priority != 1 {
+		t.Errorf("Expected the new issue raised to P1, got P%d", issue.Priority)
+	}
+	if labels, _ := store.GetLabels(ctx, issue.ID); len(labels) != 1 || labels[0] != "network" {
+		t.Errorf("Expected the network label, got %v", labels)
+	}
+	events, _ := store.GetEvents(ctx, issue.ID, 0)
+	triaged := 0
+	for _, e := range events {
+		if e.Actor == TriageActor {
+			triaged++
+		}
+	}
+	if triaged != 2 {
+		t.Errorf("Expected the priority and label changes recorded as triage, got %d of %+v", triaged, events)
+	}
+
+	// Update rules fire when an issue comes to match, not on every update
+	if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"issue_type": "bug"}, "alice"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	if got, _ := store.GetIssue(ctx, issue.ID); got.Assignee != "oncall" {
+		t.Errorf("Expected the bug assigned to oncall, got %q", got.Assignee)
+	}
+	if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"assignee": "bob"}, "alice"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	if got, _ := store.GetIssue(ctx, issue.ID); got.Assignee != "bob" {
+		t.Errorf("Expected the manual reassignment to stick, got %q", got.Assignee)
+	}
+
+	// Imports aren't triaged
+	imported := &types.Issue{Title: "Timeout elsewhere", Status: types.StatusOpen, Priority: 3, IssueType: types.TypeTask}
+	if err := store.CreateIssue(WithoutTriage(ctx), imported, "import"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	if imported.Priority != 3 {
+		t.Errorf("Expected an import left alone, got P%d", imported.Priority)
+	}
+
+	// A dry run reports without changing anything
+	result, err := store.EvaluateTriageRules(ctx, &types.Issue{Title: "DB timeout", Priority: 2, IssueType: types.TypeBug}, nil, TriageOnCreate)
+	if err != nil {
+		t.Fatalf("EvaluateTriageRules failed: %v", err)
+	}
+	if len(result.Matched) != 1 || result.Matched[0] != "network" || result.Priority == nil || *result.Priority != 1 {
+		t.Errorf("Expected the network rule to raise it to P1, got %+v", result)
+	}
+
+	if err := store.RemoveTriageRule(ctx, "network"); err != nil {
+		t.Fatalf("RemoveTriageRule failed: %v", err)
+	}
+	if err := store.RemoveTriageRule(ctx, "network"); !errors.Is(err, ErrTriageRuleNotFound) {
+		t.Errorf("Expected ErrTriageRuleNotFound, got %v", err)
+	}
+	if rules, _ := store.GetTriageRules(ctx); len(rules) != 1 || rules[0].Name != "bugs" {
+		t.Errorf("Expected only the bugs rule left, got %v", rules)
+	}
+}
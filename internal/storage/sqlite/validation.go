@@ -0,0 +1,140 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// RequiredFieldsConfigPrefix starts the config keys listing the fields
+// issues of a type must have, e.g. validation.required.bug =
+// "description,acceptance_criteria"
+const RequiredFieldsConfigPrefix = "validation.required."
+
+// GetRequiredFields returns the fields each issue type requires, for the
+// types that require any
+func (s *SQLiteStorage) GetRequiredFields(ctx context.Context) (map[types.IssueType][]string, error) {
+	rows, err := s.reads.QueryContext(ctx, `SELECT key, value FROM config WHERE key LIKE ? ORDER BY key`, RequiredFieldsConfigPrefix+"%")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read required fields: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	required := make(map[types.IssueType][]string)
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, err
+		}
+		fields, err := types.ParseRequiredFields(value)
+		if err != nil {
+			return nil, fmt.Errorf("config %s: %w", key, err)
+		}
+		if len(fields) > 0 {
+			required[types.IssueType(strings.TrimPrefix(key, RequiredFieldsConfigPrefix))] = fields
+		}
+	}
+	return required, rows.Err()
+}
+
+// SetRequiredFields sets the fields issues of type t must have when they're
+// created or updated. No fields removes the requirement.
+func (s *SQLiteStorage) SetRequiredFields(ctx context.Context, t types.IssueType, fields []string) error {
+	if v := types.CheckIssueType(t); v != nil {
+		return fmt.Errorf("%s", v.Message)
+	}
+	fields, err := types.ParseRequiredFields(strings.Join(fields, ","))
+	if err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return s.DeleteConfig(ctx, RequiredFieldsConfigPrefix+string(t))
+	}
+	return s.SetConfig(ctx, RequiredFieldsConfigPrefix+string(t), strings.Join(fields, ","))
+}
+
+// loadRequiredFields reads the fields issues of type t require
+func loadRequiredFields(ctx context.Context, q interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}, t types.IssueType) ([]string, error) {
+	key := RequiredFieldsConfigPrefix + string(t)
+	var value string
+	err := q.QueryRowContext(ctx, `SELECT value FROM config WHERE key = ?`, key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read required fields: %w", err)
+	}
+	fields, err := types.ParseRequiredFields(value)
+	if err != nil {
+		return nil, fmt.Errorf("config %s: %w", key, err)
+	}
+	return fields, nil
+}
+
+type noRequiredFieldsKey struct{}
+
+// WithoutRequiredFields lets the issues written with ctx lack the fields
+// their type requires, for imports of issues created elsewhere
+func WithoutRequiredFields(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noRequiredFieldsKey{}, true)
+}
+
+func requiredFieldsDisabled(ctx context.Context) bool {
+	disabled, _ := ctx.Value(noRequiredFieldsKey{}).(bool)
+	return disabled || isReplicating(ctx)
+}
+
+// ForImport marks ctx as writing issues imported from elsewhere, which were
+// triaged, checked against the workflow, and given their required fields
+// where they were written
+func ForImport(ctx context.Context) context.Context {
+	return WithoutRequiredFields(WithoutTransitionChecks(WithoutTriage(ctx)))
+}
+
+// validateIssue checks a new issue against workflow and, unless ctx is an
+// import, the fields its type requires. It returns a *types.ValidationError
+// listing every problem.
+func (s *SQLiteStorage) validateIssue(ctx context.Context, issue *types.Issue, workflow *types.Workflow) error {
+	violations := issue.Violations(workflow)
+	if !requiredFieldsDisabled(ctx) && issue.IssueType.IsValid() {
+		required, err := loadRequiredFields(ctx, s.reads, issue.IssueType)
+		if err != nil {
+			return err
+		}
+		violations = append(violations, issue.CheckRequired(required)...)
+	}
+	return types.NewValidationError(violations...)
+}
+
+// checkRequiredUpdate checks that updates don't leave the issue without a
+// field its type requires: one the update clears, or any, when the update
+// changes the type. Other fields missing from before are left alone.
+func checkRequiredUpdate(ctx context.Context, tx *sql.Tx, oldIssue *types.Issue, updates map[string]interface{}, revert bool) error {
+	if revert || requiredFieldsDisabled(ctx) {
+		return nil
+	}
+	after := *oldIssue
+	if value, ok := updates["issue_type"]; ok {
+		after.IssueType = types.IssueType(fmt.Sprint(value))
+	}
+	required, err := loadRequiredFields(ctx, tx, after.IssueType)
+	if err != nil || len(required) == 0 {
+		return err
+	}
+	if after.IssueType == oldIssue.IssueType {
+		required = slices.DeleteFunc(slices.Clone(required), func(field string) bool {
+			_, updated := updates[field]
+			return !updated
+		})
+	}
+	if err := applyTransitionFields(&after, updates); err != nil {
+		return err
+	}
+	return types.NewValidationError(after.CheckRequired(required)...)
+}
@@ -0,0 +1,83 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestRequiredFields(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	if err := store.SetRequiredFields(ctx, types.TypeBug, []string{"description", "acceptance_criteria"}); err != nil {
+		t.Fatalf("SetRequiredFields failed: %v", err)
+	}
+	if err := store.SetRequiredFields(ctx, types.TypeBug, []string{"reason"}); err == nil {
+		t.Error("Expected an unknown field to be rejected")
+	}
+	if err := store.SetRequiredFields(ctx, "story", []string{"description"}); err == nil {
+		t.Error("Expected an unknown type to be rejected")
+	}
+	required, err := store.GetRequiredFields(ctx)
+	if err != nil || len(required) != 1 || len(required[types.TypeBug]) != 2 {
+		t.Fatalf("Expected two fields for bugs, got %v (%v)", required, err)
+	}
+
+	// Every problem is reported, the required fields with the rest
+	bug := &types.Issue{Title: "", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeBug, Description: "Crashes on save"}
+	var invalid *types.ValidationError
+	err = store.CreateIssue(ctx, bug, "alice")
+	if !errors.As(err, &invalid) || len(invalid.Violations) != 2 ||
+		invalid.Violations[0].Field != "title" || invalid.Violations[1].Field != "acceptance_criteria" {
+		t.Fatalf("Expected title and acceptance_criteria violations, got %v", err)
+	}
+	bug.Title = "Save crashes"
+	bug.AcceptanceCriteria = "Saving works"
+	if err := store.CreateIssue(ctx, bug, "alice"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	// Batches are checked too, and imports aren't
+	batch := []*types.Issue{{Title: "Another crash", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeBug}}
+	if err := store.CreateIssues(ctx, batch, "alice"); !errors.As(err, &invalid) {
+		t.Errorf("Expected the batch to be rejected, got %v", err)
+	}
+	if err := store.CreateIssues(ForImport(ctx), batch, "alice"); err != nil {
+		t.Errorf("Expected an import to skip required fields, got %v", err)
+	}
+
+	// Updates can't clear a required field, or change to a type whose
+	// fields are missing
+	err = store.UpdateIssue(ctx, bug.ID, map[string]interface{}{"description": ""}, "alice")
+	if !errors.As(err, &invalid) || invalid.Violations[0].Field != "description" {
+		t.Errorf("Expected clearing the description to be rejected, got %v", err)
+	}
+	task := &types.Issue{Title: "Chore", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, task, "alice"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	err = store.UpdateIssue(ctx, task.ID, map[string]interface{}{"issue_type": "bug"}, "alice")
+	if !errors.As(err, &invalid) || len(invalid.Violations) != 2 {
+		t.Errorf("Expected the type change to need both fields, got %v", err)
+	}
+
+	// An issue already missing a field can still be edited otherwise
+	if err := store.UpdateIssue(ctx, batch[0].ID, map[string]interface{}{"priority": 0}, "alice"); err != nil {
+		t.Errorf("Expected an unrelated update to pass, got %v", err)
+	}
+	err = store.UpdateIssue(ctx, batch[0].ID, map[string]interface{}{"title": " "}, "alice")
+	if !errors.As(err, &invalid) || invalid.Violations[0].Code != types.ViolationRequired {
+		t.Errorf("Expected a blank title to be rejected, got %v", err)
+	}
+
+	if err := store.SetRequiredFields(ctx, types.TypeBug, nil); err != nil {
+		t.Fatal(err)
+	}
+	if required, _ := store.GetRequiredFields(ctx); len(required) != 0 {
+		t.Errorf("Expected no required fields, got %v", required)
+	}
+}
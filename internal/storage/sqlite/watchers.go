@@ -0,0 +1,91 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// Watchers subscribe to an issue. Webhook deliveries for an issue name its
+// watchers, so the receiving end can notify them of every change. Watching
+// is per database and isn't exported to JSONL.
+
+// WatchIssue subscribes user to an issue. Watching an issue again is a no-op.
+func (s *SQLiteStorage) WatchIssue(ctx context.Context, issueID, user string) error {
+	user = strings.TrimSpace(user)
+	if user == "" {
+		return fmt.Errorf("user is required to watch an issue")
+	}
+	issue, err := s.GetIssue(ctx, issueID)
+	if err != nil {
+		return err
+	}
+	if issue == nil {
+		return types.NotFound("issue", issueID)
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT OR IGNORE INTO issue_watchers (issue_id, user, created_at)
+		VALUES (?, ?, ?)
+	`, issueID, user, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to watch %s: %w", issueID, err)
+	}
+	return nil
+}
+
+// UnwatchIssue unsubscribes user from an issue. Unwatching an issue user
+// isn't watching is a no-op.
+func (s *SQLiteStorage) UnwatchIssue(ctx context.Context, issueID, user string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM issue_watchers WHERE issue_id = ? AND user = ?`, issueID, user)
+	if err != nil {
+		return fmt.Errorf("failed to unwatch %s: %w", issueID, err)
+	}
+	return nil
+}
+
+// GetWatchers returns the users watching an issue, longest-watching first
+func (s *SQLiteStorage) GetWatchers(ctx context.Context, issueID string) ([]string, error) {
+	rows, err := s.reads.QueryContext(ctx, `
+		SELECT user FROM issue_watchers
+		WHERE issue_id = ?
+		ORDER BY created_at, user
+	`, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watchers: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var users []string
+	for rows.Next() {
+		var user string
+		if err := rows.Scan(&user); err != nil {
+			return nil, fmt.Errorf("failed to scan watcher: %w", err)
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+// GetWatchedIssues returns the issues user watches, most recently updated first
+func (s *SQLiteStorage) GetWatchedIssues(ctx context.Context, user string) ([]*types.Issue, error) {
+	rows, err := s.reads.QueryContext(ctx, `
+		SELECT i.id, i.title, i.description, i.design, i.acceptance_criteria, i.notes,
+		       i.status, i.priority, i.issue_type, i.assignee, i.estimated_minutes,
+		       i.created_at, i.updated_at, i.closed_at, i.external_ref,
+		       i.due_date, i.start_date, i.milestone
+		FROM issues i
+		JOIN issue_watchers w ON i.id = w.issue_id
+		WHERE w.user = ? AND i.deleted_at IS NULL
+		ORDER BY i.updated_at DESC, i.id
+	`, user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watched issues: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	return s.scanIssues(ctx, rows)
+}
@@ -0,0 +1,67 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestWatchIssue(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	issue := &types.Issue{Title: "Watch me", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	other := &types.Issue{Title: "Not me", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	for _, i := range []*types.Issue{issue, other} {
+		if err := store.CreateIssue(ctx, i, "alice"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+
+	for _, user := range []string{"bob", "carol", "bob"} {
+		if err := store.WatchIssue(ctx, issue.ID, user); err != nil {
+			t.Fatalf("WatchIssue failed: %v", err)
+		}
+	}
+	watchers, err := store.GetWatchers(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetWatchers failed: %v", err)
+	}
+	if len(watchers) != 2 || watchers[0] != "bob" || watchers[1] != "carol" {
+		t.Errorf("Expected bob and carol watching once each, got %v", watchers)
+	}
+	if err := store.WatchIssue(ctx, "bd-999", "bob"); err == nil {
+		t.Error("Expected watching a missing issue to fail")
+	}
+	if err := store.WatchIssue(ctx, issue.ID, " "); err == nil {
+		t.Error("Expected watching without a user to fail")
+	}
+
+	watched, err := store.GetWatchedIssues(ctx, "bob")
+	if err != nil {
+		t.Fatalf("GetWatchedIssues failed: %v", err)
+	}
+	if len(watched) != 1 || watched[0].ID != issue.ID {
+		t.Errorf("Expected bob watching only %s, got %v", issue.ID, watched)
+	}
+
+	if err := store.UnwatchIssue(ctx, issue.ID, "bob"); err != nil {
+		t.Fatalf("UnwatchIssue failed: %v", err)
+	}
+	if err := store.UnwatchIssue(ctx, issue.ID, "bob"); err != nil {
+		t.Errorf("Expected unwatching twice to be a no-op, got %v", err)
+	}
+	if watchers, _ := store.GetWatchers(ctx, issue.ID); len(watchers) != 1 || watchers[0] != "carol" {
+		t.Errorf("Expected only carol left, got %v", watchers)
+	}
+
+	// Purging an actor drops their watches
+	if _, err := store.PurgeActor(ctx, "carol", PurgeActorOptions{}); err != nil {
+		t.Fatalf("PurgeActor failed: %v", err)
+	}
+	if watchers, _ := store.GetWatchers(ctx, issue.ID); len(watchers) != 0 {
+		t.Errorf("Expected no watchers after the purge, got %v", watchers)
+	}
+}
@@ -0,0 +1,119 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// Webhook is a URL that receives issue events. The secret signs deliveries
+// and is never serialized.
+type Webhook struct {
+	ID        int64     `json:"id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"-"`
+	HasSecret bool      `json:"has_secret"`
+	Events    []string  `json:"events,omitempty"` // empty means every event
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateWebhook registers a webhook and sets its ID and creation time
+func (s *SQLiteStorage) CreateWebhook(ctx context.Context, hook *Webhook) error {
+	if hook.URL == "" {
+		return fmt.Errorf("webhook URL is required")
+	}
+
+	secret, err := s.encryptField(hook.Secret)
+	if err != nil {
+		return err
+	}
+
+	hook.CreatedAt = time.Now()
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO webhooks (url, secret, events, created_by, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, hook.URL, secret, strings.Join(hook.Events, ","), hook.CreatedBy, hook.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+	if hook.ID, err = result.LastInsertId(); err != nil {
+		return fmt.Errorf("failed to get webhook ID: %w", err)
+	}
+	hook.HasSecret = hook.Secret != ""
+	return nil
+}
+
+// ListWebhooks returns every registered webhook, oldest first
+func (s *SQLiteStorage) ListWebhooks(ctx context.Context) ([]*Webhook, error) {
+	rows, err := s.reads.QueryContext(ctx, `
+		SELECT id, url, secret, events, created_by, created_at
+		FROM webhooks
+		ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var hooks []*Webhook
+	for rows.Next() {
+		hook, err := s.scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, hook)
+	}
+	return hooks, rows.Err()
+}
+
+// GetWebhook returns a webhook by ID, or nil if it doesn't exist
+func (s *SQLiteStorage) GetWebhook(ctx context.Context, id int64) (*Webhook, error) {
+	row := s.reads.QueryRowContext(ctx, `
+		SELECT id, url, secret, events, created_by, created_at
+		FROM webhooks
+		WHERE id = ?
+	`, id)
+	hook, err := s.scanWebhook(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return hook, err
+}
+
+// DeleteWebhook removes a webhook
+func (s *SQLiteStorage) DeleteWebhook(ctx context.Context, id int64) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM webhooks WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return types.NotFound("webhook", id)
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func (s *SQLiteStorage) scanWebhook(row rowScanner) (*Webhook, error) {
+	var hook Webhook
+	var secret, events string
+	if err := row.Scan(&hook.ID, &hook.URL, &secret, &events, &hook.CreatedBy, &hook.CreatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan webhook: %w", err)
+	}
+	hook.Secret = s.decryptField(secret)
+	hook.HasSecret = hook.Secret != ""
+	if events != "" {
+		hook.Events = strings.Split(events, ",")
+	}
+	return &hook, nil
+}
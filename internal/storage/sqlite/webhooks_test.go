@@ -0,0 +1,58 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestWebhooks(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	hook := &Webhook{URL: "https://example.com/hook", Secret: "s3cret", Events: []string{"issue.created", "issue.closed"}, CreatedBy: "alice"}
+	if err := store.CreateWebhook(ctx, hook); err != nil {
+		t.Fatalf("CreateWebhook failed: %v", err)
+	}
+	if hook.ID == 0 {
+		t.Fatal("Expected webhook ID to be set")
+	}
+
+	got, err := store.GetWebhook(ctx, hook.ID)
+	if err != nil {
+		t.Fatalf("GetWebhook failed: %v", err)
+	}
+	if got.URL != hook.URL || got.Secret != "s3cret" || !got.HasSecret || len(got.Events) != 2 {
+		t.Errorf("Unexpected webhook %+v", got)
+	}
+
+	// The secret never leaves in JSON
+	data, _ := json.Marshal(got)
+	if strings.Contains(string(data), "s3cret") {
+		t.Errorf("Secret leaked in JSON: %s", data)
+	}
+
+	if err := store.CreateWebhook(ctx, &Webhook{URL: "https://example.com/other"}); err != nil {
+		t.Fatalf("CreateWebhook failed: %v", err)
+	}
+	hooks, err := store.ListWebhooks(ctx)
+	if err != nil {
+		t.Fatalf("ListWebhooks failed: %v", err)
+	}
+	if len(hooks) != 2 || hooks[1].HasSecret || hooks[1].Events != nil {
+		t.Errorf("Unexpected webhooks %+v", hooks)
+	}
+
+	if err := store.DeleteWebhook(ctx, hook.ID); err != nil {
+		t.Fatalf("DeleteWebhook failed: %v", err)
+	}
+	if got, _ := store.GetWebhook(ctx, hook.ID); got != nil {
+		t.Error("Expected webhook to be deleted")
+	}
+	if err := store.DeleteWebhook(ctx, hook.ID); err == nil {
+		t.Error("Expected error deleting a missing webhook")
+	}
+}
@@ -0,0 +1,200 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// WorkflowConfigKey is the config key holding the workflow as JSON
+const WorkflowConfigKey = "workflow"
+
+// GetWorkflow returns the configured workflow, or types.DefaultWorkflow if
+// there is none
+func (s *SQLiteStorage) GetWorkflow(ctx context.Context) (*types.Workflow, error) {
+	return loadWorkflow(ctx, s.reads)
+}
+
+func loadWorkflow(ctx context.Context, q interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}) (*types.Workflow, error) {
+	var value string
+	err := q.QueryRowContext(ctx, `SELECT value FROM config WHERE key = ?`, WorkflowConfigKey).Scan(&value)
+	if err == sql.ErrNoRows || (err == nil && value == "") {
+		return types.DefaultWorkflow(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflow: %w", err)
+	}
+	w, err := types.ParseWorkflow([]byte(value))
+	if err != nil {
+		return nil, fmt.Errorf("config %s: %w", WorkflowConfigKey, err)
+	}
+	return w, nil
+}
+
+// SetWorkflow validates and stores w, or restores the default workflow if w
+// is nil. It fails if issues are still in a status w drops.
+func (s *SQLiteStorage) SetWorkflow(ctx context.Context, w *types.Workflow) error {
+	if w == nil {
+		w = types.DefaultWorkflow()
+	}
+	if err := w.Validate(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(w)
+	if err != nil {
+		return fmt.Errorf("failed to encode workflow: %w", err)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	names := make([]interface{}, len(w.Statuses))
+	for i, ws := range w.Statuses {
+		names[i] = string(ws.Name)
+	}
+	// #nosec G201 - only placeholders are formatted in
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(`
+		SELECT status, COUNT(*) FROM issues
+		WHERE status NOT IN (%s)
+		GROUP BY status ORDER BY status
+	`, strings.TrimSuffix(strings.Repeat("?, ", len(names)), ", ")), names...)
+	if err != nil {
+		return fmt.Errorf("failed to check statuses in use: %w", err)
+	}
+	var inUse []string
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		inUse = append(inUse, fmt.Sprintf("%s (%d issues)", status, count))
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(inUse) > 0 {
+		return fmt.Errorf("workflow drops statuses still in use: %s; move those issues first", strings.Join(inUse, ", "))
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO config (key, value) VALUES (?, ?)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value
+	`, WorkflowConfigKey, string(data)); err != nil {
+		return fmt.Errorf("failed to store workflow: %w", err)
+	}
+	return tx.Commit()
+}
+
+type noTransitionCheckKey struct{}
+
+// WithoutTransitionChecks lets the changes made with ctx move issues
+// between any of the workflow's statuses, for imports of changes already
+// checked where they were made
+func WithoutTransitionChecks(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noTransitionCheckKey{}, true)
+}
+
+func transitionChecksDisabled(ctx context.Context) bool {
+	disabled, _ := ctx.Value(noTransitionCheckKey{}).(bool)
+	return disabled || isReplicating(ctx)
+}
+
+// checkStatusUpdate checks a status change in updates against the workflow
+// read in tx. The new status must be one of the workflow's; unless the
+// update is a revert or an import, the move must be allowed, with the
+// fields it requires set once updates are applied.
+func checkStatusUpdate(ctx context.Context, tx *sql.Tx, oldIssue *types.Issue, updates map[string]interface{}, revert bool) error {
+	value, ok := updates["status"]
+	if !ok {
+		return nil
+	}
+	var to types.Status
+	switch v := value.(type) {
+	case string:
+		to = types.Status(v)
+	case types.Status:
+		to = v
+	default:
+		return fmt.Errorf("invalid status: %v", value)
+	}
+
+	w, err := loadWorkflow(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if !w.HasStatus(to) {
+		return fmt.Errorf("invalid status: %s", to)
+	}
+	if revert || transitionChecksDisabled(ctx) {
+		return nil
+	}
+	after := *oldIssue
+	if err := applyTransitionFields(&after, updates); err != nil {
+		return err
+	}
+	return w.CheckTransition(&after, to, "")
+}
+
+// applyTransitionFields sets the fields a transition can require from
+// updates, so the check sees the issue as the update leaves it
+func applyTransitionFields(issue *types.Issue, updates map[string]interface{}) error {
+	text := func(v interface{}) string {
+		switch v := v.(type) {
+		case string:
+			return v
+		case *string:
+			if v != nil {
+				return *v
+			}
+		}
+		return ""
+	}
+	for key, value := range updates {
+		switch key {
+		case "assignee":
+			issue.Assignee = text(value)
+		case "description":
+			issue.Description = text(value)
+		case "design":
+			issue.Design = text(value)
+		case "acceptance_criteria":
+			issue.AcceptanceCriteria = text(value)
+		case "notes":
+			issue.Notes = text(value)
+		case "milestone":
+			issue.Milestone = text(value)
+		case "external_ref":
+			ref := text(value)
+			issue.ExternalRef = &ref
+		case "estimated_minutes":
+			switch v := value.(type) {
+			case nil:
+				issue.EstimatedMinutes = nil
+			case *int:
+				issue.EstimatedMinutes = v
+			default:
+				minutes := 0
+				issue.EstimatedMinutes = &minutes
+			}
+		case "due_date":
+			date, err := dateFieldValue(key, value)
+			if err != nil {
+				return err
+			}
+			issue.DueDate = date
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestWorkflow(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	create := func(title string) *types.Issue {
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		return issue
+	}
+	a, b := create("A"), create("B")
+	if err := store.AddDependency(ctx, &types.Dependency{IssueID: b.ID, DependsOnID: a.ID, Type: types.DepBlocks}, "alice"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+
+	// Without a workflow, any built-in status goes
+	if err := store.UpdateIssue(ctx, a.ID, map[string]interface{}{"status": "in_review"}, "alice"); err == nil {
+		t.Error("Expected in_review to be rejected before it's defined")
+	}
+
+	workflow := types.DefaultWorkflow()
+	workflow.Statuses = append(workflow.Statuses, types.WorkflowStatus{Name: "in_review", Category: types.StatusInProgress})
+	workflow.Transitions = []types.WorkflowTransition{
+		{From: types.StatusOpen, To: types.StatusInProgress, Require: []string{"assignee"}},
+		{From: types.StatusInProgress, To: "in_review"},
+		{From: "in_review", To: types.StatusClosed, Require: []string{"reason"}},
+		{From: types.AnyStatus, To: types.StatusOpen},
+	}
+	if err := store.SetWorkflow(ctx, workflow); err != nil {
+		t.Fatalf("SetWorkflow failed: %v", err)
+	}
+	if got, err := store.GetWorkflow(ctx); err != nil || !got.HasStatus("in_review") || len(got.Transitions) != 4 {
+		t.Fatalf("Expected the stored workflow back, got %+v (%v)", got, err)
+	}
+
+	// Required fields may come with the update itself
+	var transition *types.TransitionError
+	err := store.UpdateIssue(ctx, a.ID, map[string]interface{}{"status": "in_progress"}, "alice")
+	if !errors.As(err, &transition) || len(transition.Missing) != 1 || transition.Missing[0] != "assignee" {
+		t.Errorf("Expected assignee to be required, got %v", err)
+	}
+	if err := store.UpdateIssue(ctx, a.ID, map[string]interface{}{"status": "in_progress", "assignee": "bob"}, "alice"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	if err := store.UpdateIssue(ctx, a.ID, map[string]interface{}{"status": "in_review"}, "alice"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+
+	// A custom status counts as its category: in_review still blocks B,
+	// and is ready work itself
+	ready, err := store.GetReadyWork(ctx, types.WorkFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ready) != 1 || ready[0].ID != a.ID {
+		t.Errorf("Expected only %s ready, got %d issues", a.ID, len(ready))
+	}
+
+	// Moves not listed are rejected, naming the ones allowed
+	err = store.UpdateIssue(ctx, b.ID, map[string]interface{}{"status": "in_review"}, "alice")
+	if !errors.As(err, &transition) || len(transition.Allowed) != 1 || transition.Allowed[0] != types.StatusInProgress {
+		t.Errorf("Expected open -> in_review rejected with in_progress allowed, got %v", err)
+	}
+
+	if err := store.CloseIssue(ctx, a.ID, "", "alice"); !errors.As(err, &transition) {
+		t.Errorf("Expected closing without a reason to be rejected, got %v", err)
+	}
+	if err := store.CloseIssue(ctx, a.ID, "Shipped", "alice"); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+
+	// Imports move issues freely between known statuses
+	if err := store.UpdateIssue(WithoutTransitionChecks(ctx), b.ID, map[string]interface{}{"status": "in_review"}, "import"); err != nil {
+		t.Fatalf("Expected an import to skip transition checks, got %v", err)
+	}
+	if err := store.SetWorkflow(ctx, nil); err == nil {
+		t.Error("Expected dropping a status in use to fail")
+	}
+	if err := store.UpdateIssue(ctx, b.ID, map[string]interface{}{"status": "open"}, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetWorkflow(ctx, nil); err != nil {
+		t.Fatalf("Expected the default workflow restored, got %v", err)
+	}
+}
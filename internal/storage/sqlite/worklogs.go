@@ -0,0 +1,146 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// timeByIssueLimit caps the per-issue rollup in statistics
+const timeByIssueLimit = 10
+
+// WorkLog records time spent on an issue
+type WorkLog struct {
+	ID       int64     `json:"id"`
+	IssueID  string    `json:"issue_id"`
+	Actor    string    `json:"actor"`
+	Minutes  int       `json:"minutes"`
+	Note     string    `json:"note,omitempty"`
+	LoggedAt time.Time `json:"logged_at"`
+}
+
+// AddWorkLog records time spent on an issue and sets the entry's ID. LoggedAt
+// defaults to now.
+func (s *SQLiteStorage) AddWorkLog(ctx context.Context, log *WorkLog) error {
+	if log.Minutes <= 0 {
+		return fmt.Errorf("logged time must be positive")
+	}
+	issue, err := s.GetIssue(ctx, log.IssueID)
+	if err != nil {
+		return err
+	}
+	if issue == nil {
+		return types.NotFound("issue", log.IssueID)
+	}
+
+	if log.LoggedAt.IsZero() {
+		log.LoggedAt = time.Now()
+	}
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO work_logs (issue_id, actor, minutes, note, logged_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, log.IssueID, log.Actor, log.Minutes, log.Note, log.LoggedAt)
+	if err != nil {
+		return fmt.Errorf("failed to log time: %w", err)
+	}
+	if log.ID, err = result.LastInsertId(); err != nil {
+		return fmt.Errorf("failed to get work log ID: %w", err)
+	}
+	return nil
+}
+
+// GetWorkLogs returns the time logged on an issue, oldest first
+func (s *SQLiteStorage) GetWorkLogs(ctx context.Context, issueID string) ([]*WorkLog, error) {
+	rows, err := s.reads.QueryContext(ctx, `
+		SELECT id, issue_id, actor, minutes, note, logged_at
+		FROM work_logs
+		WHERE issue_id = ?
+		ORDER BY logged_at, id
+	`, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get work logs: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var logs []*WorkLog
+	for rows.Next() {
+		var log WorkLog
+		if err := rows.Scan(&log.ID, &log.IssueID, &log.Actor, &log.Minutes, &log.Note, &log.LoggedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan work log: %w", err)
+		}
+		logs = append(logs, &log)
+	}
+	return logs, rows.Err()
+}
+
+// addTimeStatistics fills in the logged-time rollups. Time on issues in the
+// trash doesn't count.
+func (s *SQLiteStorage) addTimeStatistics(ctx context.Context, stats *types.Statistics) error {
+	err := s.reads.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(w.minutes), 0)
+		FROM work_logs w
+		JOIN issues i ON i.id = w.issue_id
+		WHERE i.deleted_at IS NULL
+	`).Scan(&stats.LoggedMinutes)
+	if err != nil {
+		return fmt.Errorf("failed to get logged time: %w", err)
+	}
+	if stats.LoggedMinutes == 0 {
+		return nil
+	}
+
+	rows, err := s.reads.QueryContext(ctx, `
+		SELECT i.id, i.title, COALESCE(i.assignee, ''), i.estimated_minutes, SUM(w.minutes) AS logged
+		FROM work_logs w
+		JOIN issues i ON i.id = w.issue_id
+		WHERE i.deleted_at IS NULL
+		GROUP BY i.id
+		ORDER BY logged DESC, i.id
+		LIMIT ?
+	`, timeByIssueLimit)
+	if err != nil {
+		return fmt.Errorf("failed to get logged time by issue: %w", err)
+	}
+	for rows.Next() {
+		var t types.IssueTime
+		var estimated sql.NullInt64
+		if err := rows.Scan(&t.IssueID, &t.Title, &t.Assignee, &estimated, &t.LoggedMinutes); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("failed to scan logged time: %w", err)
+		}
+		if estimated.Valid {
+			mins := int(estimated.Int64)
+			t.EstimatedMinutes = &mins
+		}
+		stats.TimeByIssue = append(stats.TimeByIssue, &t)
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return fmt.Errorf("failed to read logged time by issue: %w", err)
+	}
+	_ = rows.Close()
+
+	rows, err = s.reads.QueryContext(ctx, `
+		SELECT COALESCE(i.assignee, ''), COUNT(DISTINCT i.id), SUM(w.minutes) AS logged
+		FROM work_logs w
+		JOIN issues i ON i.id = w.issue_id
+		WHERE i.deleted_at IS NULL
+		GROUP BY COALESCE(i.assignee, '')
+		ORDER BY logged DESC, 1
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to get logged time by assignee: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var t types.AssigneeTime
+		if err := rows.Scan(&t.Assignee, &t.Issues, &t.LoggedMinutes); err != nil {
+			return fmt.Errorf("failed to scan logged time: %w", err)
+		}
+		stats.TimeByAssignee = append(stats.TimeByAssignee, &t)
+	}
+	return rows.Err()
+}
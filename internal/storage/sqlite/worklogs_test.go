@@ -0,0 +1,67 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestWorkLogs(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	estimate := 60
+	mine := &types.Issue{Title: "Fix login", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeBug, Assignee: "alice", EstimatedMinutes: &estimate}
+	other := &types.Issue{Title: "Write docs", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	for _, issue := range []*types.Issue{mine, other} {
+		if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+
+	for _, log := range []*WorkLog{
+		{IssueID: mine.ID, Actor: "alice", Minutes: 45, Note: "debugging"},
+		{IssueID: mine.ID, Actor: "bob", Minutes: 30},
+		{IssueID: other.ID, Actor: "alice", Minutes: 20},
+	} {
+		if err := store.AddWorkLog(ctx, log); err != nil {
+			t.Fatalf("AddWorkLog failed: %v", err)
+		}
+		if log.ID == 0 || log.LoggedAt.IsZero() {
+			t.Errorf("Expected ID and time to be set, got %+v", log)
+		}
+	}
+	if err := store.AddWorkLog(ctx, &WorkLog{IssueID: mine.ID, Actor: "alice", Minutes: 0}); err == nil {
+		t.Error("Expected an error for zero minutes")
+	}
+	if err := store.AddWorkLog(ctx, &WorkLog{IssueID: "bd-999", Actor: "alice", Minutes: 5}); err == nil {
+		t.Error("Expected an error for a missing issue")
+	}
+
+	logs, err := store.GetWorkLogs(ctx, mine.ID)
+	if err != nil {
+		t.Fatalf("GetWorkLogs failed: %v", err)
+	}
+	if len(logs) != 2 || logs[0].Note != "debugging" || logs[1].Actor != "bob" {
+		t.Errorf("Unexpected work logs %+v", logs)
+	}
+
+	stats, err := store.GetStatistics(ctx)
+	if err != nil {
+		t.Fatalf("GetStatistics failed: %v", err)
+	}
+	if stats.LoggedMinutes != 95 {
+		t.Errorf("Expected 95 logged minutes, got %d", stats.LoggedMinutes)
+	}
+	if len(stats.TimeByIssue) != 2 || stats.TimeByIssue[0].IssueID != mine.ID || stats.TimeByIssue[0].LoggedMinutes != 75 || *stats.TimeByIssue[0].EstimatedMinutes != 60 {
+		t.Errorf("Unexpected time by issue %+v", stats.TimeByIssue)
+	}
+	// Time rolls up to the issue's assignee, not whoever logged it
+	if len(stats.TimeByAssignee) != 2 || stats.TimeByAssignee[0].Assignee != "alice" || stats.TimeByAssignee[0].LoggedMinutes != 75 ||
+		stats.TimeByAssignee[1].Assignee != "" || stats.TimeByAssignee[1].LoggedMinutes != 20 {
+		t.Errorf("Unexpected time by assignee %+v", stats.TimeByAssignee)
+	}
+}
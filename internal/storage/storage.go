@@ -15,14 +15,17 @@ type Storage interface {
 	CreateIssues(ctx context.Context, issues []*types.Issue, actor string) error
 	GetIssue(ctx context.Context, id string) (*types.Issue, error)
 	UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error
+	UpdateIssues(ctx context.Context, filter types.IssueFilter, updates map[string]interface{}, actor string) ([]string, error) // Atomic bulk update; returns affected IDs
 	CloseIssue(ctx context.Context, id string, reason string, actor string) error
 	SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error)
+	ForEachIssue(ctx context.Context, filter types.IssueFilter, fn func(*types.Issue) error) error // SearchIssues without a query, one issue at a time; stops at fn's first error
 
 	// Dependencies
 	AddDependency(ctx context.Context, dep *types.Dependency, actor string) error
 	RemoveDependency(ctx context.Context, issueID, dependsOnID string, actor string) error
 	GetDependencies(ctx context.Context, issueID string) ([]*types.Issue, error)
 	GetDependents(ctx context.Context, issueID string) ([]*types.Issue, error)
+	GetChildren(ctx context.Context, parentID string) ([]*types.Issue, error) // Direct subtasks via parent-child dependencies
 	GetDependencyRecords(ctx context.Context, issueID string) ([]*types.Dependency, error)
 	GetAllDependencyRecords(ctx context.Context) (map[string][]*types.Dependency, error)
 	GetDependencyTree(ctx context.Context, issueID string, maxDepth int, showAllPaths bool, reverse bool) ([]*types.TreeNode, error)
@@ -46,9 +49,17 @@ type Storage interface {
 	// Comments
 	AddIssueComment(ctx context.Context, issueID, author, text string) (*types.Comment, error)
 	GetIssueComments(ctx context.Context, issueID string) ([]*types.Comment, error)
+	ReplyToComment(ctx context.Context, issueID string, replyTo int64, author, text string) (*types.Comment, error)
+	UpdateIssueComment(ctx context.Context, issueID string, commentID int64, editor, text string) (*types.Comment, error) // Keeps the old text in the edit history
+	DeleteIssueComment(ctx context.Context, issueID string, commentID int64) error                                        // Replies move up to the comment's parent
+	GetCommentEdits(ctx context.Context, issueID string, commentID int64) ([]*types.CommentEdit, error)
+	AddCommentReaction(ctx context.Context, issueID string, commentID int64, actor, reaction string) (*types.Comment, error)
+	RemoveCommentReaction(ctx context.Context, issueID string, commentID int64, actor, reaction string) (*types.Comment, error)
+	GetMentions(ctx context.Context, user string) ([]*types.Mention, error) // Comments that @mention user, newest first
 
 	// Statistics
 	GetStatistics(ctx context.Context) (*types.Statistics, error)
+	GetGroupedStatistics(ctx context.Context, groupBy string) ([]*types.StatGroup, error) // Counts by a types.GroupBy* dimension and status
 
 	// Dirty tracking (for incremental JSONL export)
 	GetDirtyIssues(ctx context.Context) ([]string, error)
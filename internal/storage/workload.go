@@ -0,0 +1,155 @@
+package storage
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// AssigneeHistoryDays is how far back closed work still makes someone a
+// candidate assignee
+const AssigneeHistoryDays = 90
+
+// GetWorkload summarizes the unfinished work of everyone with any assigned,
+// sorted by assignee
+func GetWorkload(ctx context.Context, s Storage) ([]*types.AssigneeWorkload, error) {
+	loads := make(map[string]*types.AssigneeWorkload)
+	err := s.ForEachIssue(ctx, types.IssueFilter{}, func(issue *types.Issue) error {
+		tallyWorkload(loads, issue)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	workload := make([]*types.AssigneeWorkload, 0, len(loads))
+	for _, load := range loads {
+		if load.Active() > 0 {
+			workload = append(workload, load)
+		}
+	}
+	sort.Slice(workload, func(i, j int) bool {
+		return workload[i].Assignee < workload[j].Assignee
+	})
+	return workload, nil
+}
+
+// tallyWorkload adds issue to its assignee's entry in loads if it's
+// unfinished. Everyone assigned an issue gets an entry, even if all of theirs
+// are closed.
+func tallyWorkload(loads map[string]*types.AssigneeWorkload, issue *types.Issue) {
+	if issue.Assignee == "" {
+		return
+	}
+	load := loads[issue.Assignee]
+	if load == nil {
+		load = &types.AssigneeWorkload{Assignee: issue.Assignee}
+		loads[issue.Assignee] = load
+	}
+	switch issue.Status {
+	case types.StatusOpen:
+		load.Open++
+	case types.StatusInProgress:
+		load.InProgress++
+	case types.StatusBlocked:
+		load.Blocked++
+	default:
+		return
+	}
+	if issue.EstimatedMinutes != nil {
+		load.EstimatedMinutes += *issue.EstimatedMinutes
+	} else {
+		load.Unestimated++
+	}
+}
+
+// SuggestAssignees ranks who could take an issue, least loaded first.
+//
+// Candidates are people assigned unfinished work, or work updated in the last
+// AssigneeHistoryDays. If the issue has labels, only candidates who have such
+// work sharing one of its labels are eligible. Candidates are ranked by their
+// number of unfinished issues, then estimated minutes, then most matching
+// issues. The issue's own assignment doesn't count toward anyone's load.
+func SuggestAssignees(ctx context.Context, s Storage, issueID string, now time.Time) ([]*types.AssigneeSuggestion, error) {
+	issue, err := s.GetIssue(ctx, issueID)
+	if err != nil {
+		return nil, err
+	}
+	if issue == nil {
+		return nil, types.NotFound("issue", issueID)
+	}
+	labels, err := s.GetLabels(ctx, issueID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Recent work, unfinished or touched within the history window, by
+	// assignee; the issue's own assignment is left out of everything
+	since := now.AddDate(0, 0, -AssigneeHistoryDays)
+	recent := make(map[string]string)
+	loads := make(map[string]*types.AssigneeWorkload)
+	err = s.ForEachIssue(ctx, types.IssueFilter{}, func(i *types.Issue) error {
+		if i.ID == issueID {
+			return nil
+		}
+		tallyWorkload(loads, i)
+		if i.Assignee != "" && (i.Status != types.StatusClosed || !i.UpdatedAt.Before(since)) {
+			recent[i.ID] = i.Assignee
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// With labels, count each candidate's recent issues sharing one
+	var matches map[string]int
+	if len(labels) > 0 {
+		matches = make(map[string]int)
+		matched := make(map[string]bool)
+		for _, label := range labels {
+			labeled, err := s.GetIssuesByLabel(ctx, label)
+			if err != nil {
+				return nil, err
+			}
+			for _, i := range labeled {
+				if assignee := recent[i.ID]; assignee != "" && !matched[i.ID] {
+					matched[i.ID] = true
+					matches[assignee]++
+				}
+			}
+		}
+	}
+
+	candidates := make(map[string]bool)
+	for _, assignee := range recent {
+		candidates[assignee] = true
+	}
+	var suggestions []*types.AssigneeSuggestion
+	for assignee := range candidates {
+		if matches != nil && matches[assignee] == 0 {
+			continue
+		}
+		suggestions = append(suggestions, &types.AssigneeSuggestion{
+			AssigneeWorkload: *loads[assignee],
+			MatchingIssues:   matches[assignee],
+		})
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		a, b := suggestions[i], suggestions[j]
+		if a.Active() != b.Active() {
+			return a.Active() < b.Active()
+		}
+		if a.EstimatedMinutes != b.EstimatedMinutes {
+			return a.EstimatedMinutes < b.EstimatedMinutes
+		}
+		if a.MatchingIssues != b.MatchingIssues {
+			return a.MatchingIssues > b.MatchingIssues
+		}
+		return a.Assignee < b.Assignee
+	})
+	return suggestions, nil
+}
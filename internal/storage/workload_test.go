@@ -0,0 +1,174 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage"
+	"github.com/imalsogreg/beads/internal/storage/memory"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestWorkloadAndSuggestAssignees(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New("")
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+
+	create := func(assignee string, status types.Status, minutes int, labels ...string) *types.Issue {
+		t.Helper()
+		issue := &types.Issue{Title: "Work", Status: status, Priority: 2, IssueType: types.TypeTask, Assignee: assignee}
+		if minutes > 0 {
+			issue.EstimatedMinutes = &minutes
+		}
+		if status == types.StatusClosed {
+			now := time.Now()
+			issue.ClosedAt = &now
+		}
+		if err := store.CreateIssue(ctx, issue, "test-user"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		for _, label := range labels {
+			if err := store.AddLabel(ctx, issue.ID, label, "test-user"); err != nil {
+				t.Fatalf("AddLabel failed: %v", err)
+			}
+		}
+		return issue
+	}
+	create("alice", types.StatusOpen, 60, "backend")
+	create("alice", types.StatusOpen, 30)
+	create("bob", types.StatusInProgress, 240, "backend")
+	create("carol", types.StatusClosed, 0, "backend")
+	create("dave", types.StatusOpen, 0, "frontend")
+	backend := create("", types.StatusOpen, 0, "backend")
+	unlabeled := create("", types.StatusOpen, 0)
+
+	workload, err := storage.GetWorkload(ctx, store)
+	if err != nil {
+		t.Fatalf("GetWorkload failed: %v", err)
+	}
+	if len(workload) != 3 {
+		t.Fatalf("Expected workload for alice, bob, and dave, got %d entries", len(workload))
+	}
+	alice, bob, dave := workload[0], workload[1], workload[2]
+	if alice.Assignee != "alice" || alice.Open != 2 || alice.EstimatedMinutes != 90 {
+		t.Errorf("Unexpected workload for alice: %+v", alice)
+	}
+	if bob.Assignee != "bob" || bob.InProgress != 1 || bob.EstimatedMinutes != 240 {
+		t.Errorf("Unexpected workload for bob: %+v", bob)
+	}
+	if dave.Assignee != "dave" || dave.Open != 1 || dave.Unestimated != 1 {
+		t.Errorf("Unexpected workload for dave: %+v", dave)
+	}
+
+	tests := []struct {
+		name  string
+		issue *types.Issue
+		now   time.Time
+		want  []string
+	}{
+		{"labels narrow candidates", backend, time.Now(), []string{"carol", "bob", "alice"}},
+		{"old closed work doesn't count", backend, time.Now().AddDate(0, 0, storage.AssigneeHistoryDays+1), []string{"bob", "alice"}},
+		{"no labels means everyone", unlabeled, time.Now(), []string{"carol", "dave", "bob", "alice"}},
+	}
+	for _, tt := range tests {
+		suggestions, err := storage.SuggestAssignees(ctx, store, tt.issue.ID, tt.now)
+		if err != nil {
+			t.Fatalf("%s: SuggestAssignees failed: %v", tt.name, err)
+		}
+		var got []string
+		for _, s := range suggestions {
+			got = append(got, s.Assignee)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+				break
+			}
+		}
+	}
+
+	if _, err := storage.SuggestAssignees(ctx, store, "bd-999", time.Now()); err == nil {
+		t.Error("Expected an error for a missing issue")
+	}
+}
+
+func TestWorkloadAcrossPages(t *testing.T) {
+	store, ctx := newPagedStore(t)
+
+	// Enough issues for several pages, spread over three assignees
+	assignees := []string{"alice", "bob", "carol"}
+	statuses := []types.Status{types.StatusOpen, types.StatusInProgress, types.StatusBlocked, types.StatusClosed}
+	want := make(map[string]*types.AssigneeWorkload)
+	for i := 0; i < 1100; i++ {
+		assignee := assignees[i%len(assignees)]
+		if i%11 == 0 {
+			assignee = "alice"
+		}
+		issue := &types.Issue{Title: "Work", Status: statuses[i%len(statuses)], Priority: 2, IssueType: types.TypeTask, Assignee: assignee}
+		if issue.Status == types.StatusClosed {
+			now := time.Now()
+			issue.ClosedAt = &now
+		}
+		minutes := 30
+		if i%2 == 0 {
+			issue.EstimatedMinutes = &minutes
+		}
+		if err := store.CreateIssue(ctx, issue, "test-user"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+
+		load := want[assignee]
+		if load == nil {
+			load = &types.AssigneeWorkload{Assignee: assignee}
+			want[assignee] = load
+		}
+		switch issue.Status {
+		case types.StatusOpen:
+			load.Open++
+		case types.StatusInProgress:
+			load.InProgress++
+		case types.StatusBlocked:
+			load.Blocked++
+		default:
+			continue
+		}
+		if issue.EstimatedMinutes != nil {
+			load.EstimatedMinutes += minutes
+		} else {
+			load.Unestimated++
+		}
+	}
+
+	workload, err := storage.GetWorkload(ctx, store)
+	if err != nil {
+		t.Fatalf("GetWorkload failed: %v", err)
+	}
+	if len(workload) != len(assignees) {
+		t.Fatalf("Expected %d assignees, got %d", len(assignees), len(workload))
+	}
+	for i, load := range workload {
+		if load.Assignee != assignees[i] || *load != *want[load.Assignee] {
+			t.Errorf("Expected %+v, got %+v", *want[assignees[i]], *load)
+		}
+	}
+
+	issue := &types.Issue{Title: "Needs someone", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "test-user"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	suggestions, err := storage.SuggestAssignees(ctx, store, issue.ID, time.Now())
+	if err != nil {
+		t.Fatalf("SuggestAssignees failed: %v", err)
+	}
+	if len(suggestions) != len(assignees) || suggestions[len(suggestions)-1].Assignee != "alice" {
+		t.Errorf("Expected everyone suggested, alice (the busiest) last, got %+v", suggestions)
+	}
+}
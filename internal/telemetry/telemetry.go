@@ -0,0 +1,94 @@
+// Package telemetry sets up OpenTelemetry tracing for bd serve, exporting
+// spans over OTLP/HTTP so operators can follow a slow request from the HTTP
+// handler down into storage.
+//
+// Code that creates spans uses otel.Tracer and works whether or not tracing
+// is set up; until Setup is called the global tracer provider drops spans at
+// next to no cost.
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Config describes where spans go
+type Config struct {
+	Endpoint       string            // Collector host:port, or a URL such as https://otel.example.com:4318
+	Insecure       bool              // Use plain HTTP for a host:port endpoint
+	Headers        map[string]string // Sent with each export, e.g. an API key
+	SampleRatio    float64           // Fraction of new traces to keep; traces started by callers follow their decision
+	ServiceName    string            // Default "bd"
+	ServiceVersion string
+}
+
+// Setup installs a global tracer provider exporting to config.Endpoint and
+// the W3C trace context propagator. The returned function flushes pending
+// spans and must be called before exiting.
+func Setup(ctx context.Context, config Config) (func(context.Context) error, error) {
+	if config.Endpoint == "" {
+		return nil, fmt.Errorf("an OTLP endpoint is required")
+	}
+	if config.SampleRatio < 0 || config.SampleRatio > 1 {
+		return nil, fmt.Errorf("sample ratio must be between 0 and 1 (got %v)", config.SampleRatio)
+	}
+	if config.ServiceName == "" {
+		config.ServiceName = "bd"
+	}
+
+	var opts []otlptracehttp.Option
+	if strings.Contains(config.Endpoint, "://") {
+		opts = append(opts, otlptracehttp.WithEndpointURL(config.Endpoint))
+	} else {
+		opts = append(opts, otlptracehttp.WithEndpoint(config.Endpoint))
+	}
+	if config.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	if len(config.Headers) > 0 {
+		opts = append(opts, otlptracehttp.WithHeaders(config.Headers))
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", config.ServiceName),
+		attribute.String("service.version", config.ServiceVersion),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe service: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider.Shutdown, nil
+}
+
+// ParseHeaders parses KEY=VALUE pairs for Config.Headers
+func ParseHeaders(pairs []string) (map[string]string, error) {
+	headers := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid header %q: expected KEY=VALUE", pair)
+		}
+		headers[key] = strings.TrimSpace(value)
+	}
+	return headers, nil
+}
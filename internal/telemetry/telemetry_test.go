@@ -0,0 +1,30 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseHeaders(t *testing.T) {
+	headers, err := ParseHeaders([]string{"x-api-key=secret", " team = core ", "empty="})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if headers["x-api-key"] != "secret" || headers["team"] != "core" || headers["empty"] != "" || len(headers) != 3 {
+		t.Errorf("Unexpected headers: %v", headers)
+	}
+	for _, bad := range []string{"novalue", "=value"} {
+		if _, err := ParseHeaders([]string{bad}); err == nil {
+			t.Errorf("Expected an error for %q", bad)
+		}
+	}
+}
+
+func TestSetupValidation(t *testing.T) {
+	if _, err := Setup(context.Background(), Config{}); err == nil {
+		t.Error("Expected an error without an endpoint")
+	}
+	if _, err := Setup(context.Background(), Config{Endpoint: "localhost:4318", SampleRatio: 2}); err == nil {
+		t.Error("Expected an error for a sample ratio above 1")
+	}
+}
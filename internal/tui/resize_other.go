@@ -0,0 +1,11 @@
+//go:build !unix
+
+package tui
+
+import "os"
+
+// notifyResize never fires where there's no SIGWINCH; the screen is redrawn
+// at the new size on the next key press
+func notifyResize() <-chan os.Signal {
+	return nil
+}
@@ -0,0 +1,128 @@
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// Run takes over the terminal on in and out until the user quits
+func (a *App) Run(in, out *os.File) error {
+	inFd, outFd := int(in.Fd()), int(out.Fd())
+	if !term.IsTerminal(inFd) || !term.IsTerminal(outFd) {
+		return fmt.Errorf("bd tui needs an interactive terminal")
+	}
+	state, err := term.MakeRaw(inFd)
+	if err != nil {
+		return fmt.Errorf("failed to set up terminal: %w", err)
+	}
+	defer func() { _ = term.Restore(inFd, state) }()
+
+	// Alternate screen, hidden cursor; both undone on exit
+	w := bufio.NewWriter(out)
+	fmt.Fprint(w, "\x1b[?1049h\x1b[?25l")
+	defer func() {
+		fmt.Fprint(w, "\x1b[?25h\x1b[?1049l")
+		_ = w.Flush()
+	}()
+
+	keys := make(chan Key)
+	go readKeys(in, keys)
+	resized := notifyResize()
+
+	for !a.quit {
+		width, height, err := term.GetSize(outFd)
+		if err != nil {
+			width, height = 80, 24
+		}
+		fmt.Fprint(w, "\x1b[H")
+		fmt.Fprint(w, strings.Join(a.View(width, height), "\x1b[K\r\n"))
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		select {
+		case k, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			a.HandleKey(k)
+		case <-resized:
+			fmt.Fprint(w, "\x1b[2J")
+		}
+	}
+	return nil
+}
+
+// readKeys decodes key presses from in until it fails
+func readKeys(in *os.File, keys chan<- Key) {
+	defer close(keys)
+	buf := make([]byte, 256)
+	for {
+		n, err := in.Read(buf)
+		if err != nil {
+			return
+		}
+		for _, k := range parseKeys(buf[:n]) {
+			keys <- k
+		}
+	}
+}
+
+// escapes are the terminal sequences for named keys
+var escapes = map[string]Key{
+	"\x1b[A": KeyUp, "\x1bOA": KeyUp,
+	"\x1b[B": KeyDown, "\x1bOB": KeyDown,
+	"\x1b[5~": KeyPageUp, "\x1b[6~": KeyPageDown,
+	"\x1b[H": KeyHome, "\x1bOH": KeyHome, "\x1b[1~": KeyHome,
+	"\x1b[F": KeyEnd, "\x1bOF": KeyEnd, "\x1b[4~": KeyEnd,
+}
+
+// parseKeys splits one read from the terminal into key presses. Unknown
+// escape sequences are dropped.
+func parseKeys(b []byte) []Key {
+	var keys []Key
+	for len(b) > 0 {
+		switch c := b[0]; {
+		case c == 0x1b:
+			if len(b) == 1 || (b[1] != '[' && b[1] != 'O') {
+				keys = append(keys, KeyEsc)
+				b = b[1:]
+				continue
+			}
+			// A sequence ends with its first byte in @..~ after the prefix
+			end := 2
+			for end < len(b) && (b[end] < 0x40 || b[end] > 0x7e) {
+				end++
+			}
+			end = min(end+1, len(b))
+			if k, ok := escapes[string(b[:end])]; ok {
+				keys = append(keys, k)
+			}
+			b = b[end:]
+		case c == '\r' || c == '\n':
+			keys = append(keys, KeyEnter)
+			b = b[1:]
+		case c == 0x7f || c == 0x08:
+			keys = append(keys, KeyBackspace)
+			b = b[1:]
+		case c == '\t':
+			keys = append(keys, KeyTab)
+			b = b[1:]
+		case c == 0x03:
+			keys = append(keys, KeyCtrlC)
+			b = b[1:]
+		case c < ' ':
+			b = b[1:]
+		default:
+			_, size := utf8.DecodeRune(b)
+			keys = append(keys, Key(b[:size]))
+			b = b[size:]
+		}
+	}
+	return keys
+}
@@ -0,0 +1,466 @@
+// Package tui is the full-screen terminal interface behind bd tui: a
+// searchable issue list, a detail pane, inline status and priority edits, a
+// comment composer, and navigation along dependencies.
+//
+// App holds the state and turns keys into storage calls; View renders it as
+// plain lines of text, so both can be tested without a terminal. Run (in
+// terminal.go) connects them to a real one.
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/imalsogreg/beads/internal/storage"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// Key is a key press: a single character, or one of the named keys below
+type Key string
+
+// Named keys
+const (
+	KeyUp        Key = "<up>"
+	KeyDown      Key = "<down>"
+	KeyPageUp    Key = "<pgup>"
+	KeyPageDown  Key = "<pgdn>"
+	KeyHome      Key = "<home>"
+	KeyEnd       Key = "<end>"
+	KeyEnter     Key = "<enter>"
+	KeyEsc       Key = "<esc>"
+	KeyBackspace Key = "<backspace>"
+	KeyTab       Key = "<tab>"
+	KeyCtrlC     Key = "<ctrl-c>"
+)
+
+type mode int
+
+const (
+	modeNormal  mode = iota
+	modeSearch       // typing a search query
+	modeComment      // typing a comment
+)
+
+// statusCycle is the order the s key steps through
+var statusCycle = []types.Status{types.StatusOpen, types.StatusInProgress, types.StatusBlocked, types.StatusClosed}
+
+// link is a dependency shown in the detail pane
+type link struct {
+	issue    *types.Issue
+	depType  types.DependencyType
+	outgoing bool // the current issue depends on it (rather than the reverse)
+}
+
+// detail is everything shown about the current issue
+type detail struct {
+	issue    *types.Issue
+	labels   []string
+	links    []link
+	comments []*types.Comment
+}
+
+// App is the TUI's state
+type App struct {
+	ctx   context.Context
+	store storage.Storage
+	actor string
+
+	// OnChange is called after each write, e.g. to schedule a JSONL export
+	OnChange func()
+
+	issues     []*types.Issue
+	cursor     int // selected row in issues
+	offset     int // first visible row
+	query      string
+	showClosed bool
+
+	current      string   // issue in the detail pane
+	detail       *detail  // nil if current couldn't be loaded
+	linkFocus    bool     // keys move through the detail pane's links
+	link         int      // selected link
+	detailOffset int      // first visible detail line
+	history      []string // issues left by following links, for going back
+
+	mode    mode
+	input   []rune
+	message string
+	quit    bool
+
+	// Page size, from the last View
+	listHeight int
+}
+
+// New loads the issue list for a new App
+func New(ctx context.Context, store storage.Storage, actor string) (*App, error) {
+	a := &App{ctx: ctx, store: store, actor: actor, listHeight: 20}
+	if err := a.reload(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Done reports whether the user has quit
+func (a *App) Done() bool {
+	return a.quit
+}
+
+// reload refreshes the list and the detail pane, keeping the selection on
+// the same issue when it's still listed
+func (a *App) reload() error {
+	issues, err := a.store.SearchIssues(a.ctx, a.query, types.IssueFilter{ExcludeArchived: true})
+	if err != nil {
+		return err
+	}
+	if !a.showClosed {
+		open := issues[:0]
+		for _, issue := range issues {
+			if issue.Status != types.StatusClosed {
+				open = append(open, issue)
+			}
+		}
+		issues = open
+	}
+	a.issues = issues
+
+	a.cursor = min(a.cursor, max(len(issues)-1, 0))
+	for i, issue := range issues {
+		if issue.ID == a.current {
+			a.cursor = i
+			break
+		}
+	}
+	if a.current == "" || a.indexOf(a.current) < 0 && len(a.history) == 0 {
+		a.current = ""
+		if len(issues) > 0 {
+			a.current = issues[a.cursor].ID
+		}
+	}
+	return a.loadDetail()
+}
+
+func (a *App) indexOf(id string) int {
+	for i, issue := range a.issues {
+		if issue.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// loadDetail reads the current issue with its labels, links, and comments
+func (a *App) loadDetail() error {
+	a.detail = nil
+	if a.current == "" {
+		return nil
+	}
+	issue, err := a.store.GetIssue(a.ctx, a.current)
+	if err != nil {
+		return err
+	}
+	if issue == nil {
+		return nil
+	}
+	d := &detail{issue: issue}
+	if d.labels, err = a.store.GetLabels(a.ctx, issue.ID); err != nil {
+		return err
+	}
+	if d.comments, err = a.store.GetIssueComments(a.ctx, issue.ID); err != nil {
+		return err
+	}
+
+	// Dependency records carry the type; the issue lists carry the titles
+	records, err := a.store.GetDependencyRecords(a.ctx, issue.ID)
+	if err != nil {
+		return err
+	}
+	depTypes := make(map[string]types.DependencyType, len(records))
+	for _, dep := range records {
+		depTypes[dep.DependsOnID] = dep.Type
+	}
+	deps, err := a.store.GetDependencies(a.ctx, issue.ID)
+	if err != nil {
+		return err
+	}
+	for _, dep := range deps {
+		d.links = append(d.links, link{issue: dep, depType: depTypes[dep.ID], outgoing: true})
+	}
+	dependents, err := a.store.GetDependents(a.ctx, issue.ID)
+	if err != nil {
+		return err
+	}
+	for _, dep := range dependents {
+		var depType types.DependencyType
+		if records, err := a.store.GetDependencyRecords(a.ctx, dep.ID); err == nil {
+			for _, r := range records {
+				if r.DependsOnID == issue.ID {
+					depType = r.Type
+				}
+			}
+		}
+		d.links = append(d.links, link{issue: dep, depType: depType})
+	}
+
+	a.detail = d
+	a.link = min(a.link, max(len(d.links)-1, 0))
+	if len(d.links) == 0 {
+		a.linkFocus = false
+	}
+	return nil
+}
+
+// selectRow moves the list cursor to row i and shows that issue
+func (a *App) selectRow(i int) {
+	if len(a.issues) == 0 {
+		return
+	}
+	i = max(0, min(i, len(a.issues)-1))
+	a.cursor = i
+	a.history = nil
+	if a.current != a.issues[i].ID {
+		a.current = a.issues[i].ID
+		a.link, a.detailOffset = 0, 0
+		a.report(a.loadDetail())
+	}
+}
+
+// report shows err in the status line, if there is one
+func (a *App) report(err error) {
+	if err != nil {
+		a.message = "Error: " + err.Error()
+	}
+}
+
+// HandleKey applies one key press
+func (a *App) HandleKey(k Key) {
+	if k == KeyCtrlC {
+		a.quit = true
+		return
+	}
+	switch a.mode {
+	case modeSearch:
+		a.handleSearchKey(k)
+	case modeComment:
+		a.handleCommentKey(k)
+	default:
+		a.message = ""
+		if a.linkFocus && a.detail != nil && len(a.detail.links) > 0 {
+			a.handleLinkKey(k)
+		} else {
+			a.handleListKey(k)
+		}
+	}
+}
+
+func (a *App) handleListKey(k Key) {
+	switch k {
+	case "j", KeyDown:
+		a.selectRow(a.cursor + 1)
+	case "k", KeyUp:
+		a.selectRow(a.cursor - 1)
+	case KeyPageDown, " ":
+		a.selectRow(a.cursor + a.listHeight)
+	case KeyPageUp:
+		a.selectRow(a.cursor - a.listHeight)
+	case "g", KeyHome:
+		a.selectRow(0)
+	case "G", KeyEnd:
+		a.selectRow(len(a.issues) - 1)
+	case KeyTab, KeyEnter:
+		if a.detail != nil && len(a.detail.links) > 0 {
+			a.linkFocus = true
+		}
+	default:
+		a.handleCommonKey(k)
+	}
+}
+
+func (a *App) handleLinkKey(k Key) {
+	switch k {
+	case "j", KeyDown:
+		a.link = min(a.link+1, len(a.detail.links)-1)
+	case "k", KeyUp:
+		a.link = max(a.link-1, 0)
+	case KeyEnter:
+		a.follow(a.detail.links[a.link].issue.ID)
+	case KeyTab, KeyEsc:
+		a.linkFocus = false
+	default:
+		a.handleCommonKey(k)
+	}
+}
+
+// handleCommonKey handles keys that work with either pane focused
+func (a *App) handleCommonKey(k Key) {
+	switch k {
+	case "q":
+		a.quit = true
+	case "/":
+		a.mode, a.input = modeSearch, []rune(a.query)
+	case "c":
+		if a.detail != nil {
+			a.mode, a.input = modeComment, nil
+		}
+	case "s":
+		a.cycleStatus(1)
+	case "S":
+		a.cycleStatus(-1)
+	case "0", "1", "2", "3", "4":
+		a.setPriority(int(k[0] - '0'))
+	case "b", KeyBackspace:
+		a.back()
+	case "a":
+		a.showClosed = !a.showClosed
+		a.report(a.reload())
+		if a.showClosed {
+			a.message = "Showing closed issues"
+		} else {
+			a.message = "Hiding closed issues"
+		}
+	case "r":
+		a.report(a.reload())
+	case "J":
+		a.detailOffset++
+	case "K":
+		a.detailOffset = max(a.detailOffset-1, 0)
+	case KeyEsc:
+		if a.query != "" {
+			a.query = ""
+			a.report(a.reload())
+		}
+	}
+}
+
+// follow shows the linked issue id, remembering where we came from
+func (a *App) follow(id string) {
+	a.history = append(a.history, a.current)
+	a.showIssue(id)
+}
+
+// back returns to the issue before the last followed link
+func (a *App) back() {
+	if len(a.history) == 0 {
+		return
+	}
+	id := a.history[len(a.history)-1]
+	a.history = a.history[:len(a.history)-1]
+	a.showIssue(id)
+}
+
+func (a *App) showIssue(id string) {
+	a.current = id
+	a.link, a.detailOffset = 0, 0
+	if i := a.indexOf(id); i >= 0 {
+		a.cursor = i
+	}
+	a.report(a.loadDetail())
+}
+
+func (a *App) handleSearchKey(k Key) {
+	switch k {
+	case KeyEnter:
+		a.mode = modeNormal
+		return
+	case KeyEsc:
+		a.mode, a.input = modeNormal, nil
+	case KeyBackspace:
+		if len(a.input) > 0 {
+			a.input = a.input[:len(a.input)-1]
+		}
+	default:
+		if !a.typed(k) {
+			return
+		}
+	}
+	// Search as you type
+	a.query = string(a.input)
+	a.history = nil
+	a.report(a.reload())
+}
+
+func (a *App) handleCommentKey(k Key) {
+	switch k {
+	case KeyEnter:
+		a.mode = modeNormal
+		text := strings.TrimSpace(string(a.input))
+		a.input = nil
+		if text == "" {
+			return
+		}
+		if _, err := a.store.AddIssueComment(a.ctx, a.current, a.actor, text); err != nil {
+			a.report(err)
+			return
+		}
+		a.changed(fmt.Sprintf("✓ Commented on %s", a.current))
+	case KeyEsc:
+		a.mode, a.input = modeNormal, nil
+	case KeyBackspace:
+		if len(a.input) > 0 {
+			a.input = a.input[:len(a.input)-1]
+		}
+	default:
+		a.typed(k)
+	}
+}
+
+// typed appends k to the input if it's a character
+func (a *App) typed(k Key) bool {
+	r, size := utf8.DecodeRuneInString(string(k))
+	if size != len(k) || r < ' ' {
+		return false
+	}
+	a.input = append(a.input, r)
+	return true
+}
+
+// cycleStatus moves the current issue dir steps through statusCycle
+func (a *App) cycleStatus(dir int) {
+	if a.detail == nil {
+		return
+	}
+	issue := a.detail.issue
+	i := 0
+	for j, s := range statusCycle {
+		if s == issue.Status {
+			i = j
+		}
+	}
+	next := statusCycle[(i+dir+len(statusCycle))%len(statusCycle)]
+
+	var err error
+	if next == types.StatusClosed {
+		err = a.store.CloseIssue(a.ctx, issue.ID, "Closed", a.actor)
+	} else {
+		err = a.store.UpdateIssue(a.ctx, issue.ID, map[string]interface{}{"status": string(next)}, a.actor)
+	}
+	if err != nil {
+		a.report(err)
+		return
+	}
+	a.changed(fmt.Sprintf("✓ %s is now %s", issue.ID, next))
+}
+
+func (a *App) setPriority(priority int) {
+	if a.detail == nil || a.detail.issue.Priority == priority {
+		return
+	}
+	id := a.detail.issue.ID
+	if err := a.store.UpdateIssue(a.ctx, id, map[string]interface{}{"priority": priority}, a.actor); err != nil {
+		a.report(err)
+		return
+	}
+	a.changed(fmt.Sprintf("✓ %s is now P%d", id, priority))
+}
+
+// changed reloads after a write and reports it
+func (a *App) changed(message string) {
+	if a.OnChange != nil {
+		a.OnChange()
+	}
+	if err := a.reload(); err != nil {
+		a.report(err)
+		return
+	}
+	a.message = message
+}
@@ -0,0 +1,147 @@
+package tui
+
+import (
+	"context"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+var ansi = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// screen renders app as plain text
+func screen(app *App) string {
+	return ansi.ReplaceAllString(strings.Join(app.View(100, 20), "\n"), "")
+}
+
+func TestApp(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+
+	blocker := &types.Issue{Title: "Fix the parser", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeBug}
+	feature := &types.Issue{Title: "Add query language", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeFeature}
+	closedAt := time.Now()
+	done := &types.Issue{Title: "Old work", Status: types.StatusClosed, Priority: 2, IssueType: types.TypeTask, ClosedAt: &closedAt}
+	for _, issue := range []*types.Issue{blocker, feature, done} {
+		if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dep := &types.Dependency{IssueID: feature.ID, DependsOnID: blocker.ID, Type: types.DepBlocks}
+	if err := store.AddDependency(ctx, dep, "tester"); err != nil {
+		t.Fatal(err)
+	}
+
+	app, err := New(ctx, store, "tester")
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes := 0
+	app.OnChange = func() { changes++ }
+	keys := func(keys ...Key) {
+		for _, k := range keys {
+			app.HandleKey(k)
+		}
+	}
+
+	// Closed issues are hidden until toggled
+	if len(app.issues) != 2 || strings.Contains(screen(app), "Old work") {
+		t.Fatalf("Expected 2 open issues, got %d:\n%s", len(app.issues), screen(app))
+	}
+	keys("a")
+	if len(app.issues) != 3 {
+		t.Fatalf("Expected 3 issues with closed shown, got %d", len(app.issues))
+	}
+	keys("a")
+
+	// Search as you type, then clear
+	keys("/", "q", "u", "e", "r", "y", KeyEnter)
+	if len(app.issues) != 1 || app.current != feature.ID {
+		t.Fatalf("Expected search to find %s, got %d issue(s), current %s", feature.ID, len(app.issues), app.current)
+	}
+	if s := screen(app); !strings.Contains(s, "Depends on") || !strings.Contains(s, blocker.ID) {
+		t.Errorf("Expected the detail pane to show the dependency:\n%s", s)
+	}
+
+	// Follow the dependency and come back
+	keys(KeyTab, KeyEnter)
+	if app.current != blocker.ID {
+		t.Fatalf("Expected to follow the link to %s, got %s", blocker.ID, app.current)
+	}
+	if s := screen(app); !strings.Contains(s, "Depended on by") || !strings.Contains(s, "Fix the parser") {
+		t.Errorf("Expected the blocker's detail:\n%s", s)
+	}
+	keys("b")
+	if app.current != feature.ID {
+		t.Fatalf("Expected to go back to %s, got %s", feature.ID, app.current)
+	}
+	keys(KeyEsc, KeyEsc)
+	if app.query != "" || len(app.issues) != 2 {
+		t.Fatalf("Expected Esc to clear the search, got %q with %d issue(s)", app.query, len(app.issues))
+	}
+
+	// Edit status and priority inline
+	keys("s", "0")
+	got, err := store.GetIssue(ctx, feature.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != types.StatusInProgress || got.Priority != 0 {
+		t.Errorf("Expected in_progress P0, got %s P%d", got.Status, got.Priority)
+	}
+
+	// Compose a comment
+	keys("c", "L", "G", "T", "M", KeyBackspace, KeyEnter)
+	comments, err := store.GetIssueComments(ctx, feature.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comments) != 1 || comments[0].Text != "LGT" || comments[0].Author != "tester" {
+		t.Fatalf("Expected one comment LGT by tester, got %+v", comments)
+	}
+	if !strings.Contains(screen(app), "Comments (1)") {
+		t.Errorf("Expected the comment on screen:\n%s", screen(app))
+	}
+	if changes != 3 {
+		t.Errorf("Expected OnChange after each of 3 writes, got %d", changes)
+	}
+
+	// Closing hides the issue and moves on
+	keys("s", "s")
+	if got, _ := store.GetIssue(ctx, feature.ID); got.Status != types.StatusClosed {
+		t.Errorf("Expected %s closed, got %s", feature.ID, got.Status)
+	}
+	if len(app.issues) != 1 || app.current != blocker.ID {
+		t.Errorf("Expected only %s left, got %d issue(s), current %s", blocker.ID, len(app.issues), app.current)
+	}
+
+	keys("q")
+	if !app.Done() {
+		t.Error("Expected q to quit")
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	got := parseKeys([]byte("j\x1b[A\x1b[6~\r\x7fé\x1b\x03"))
+	want := []Key{"j", KeyUp, KeyPageDown, KeyEnter, KeyBackspace, "é", KeyEsc, KeyCtrlC}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Key %d: expected %q, got %q", i, want[i], got[i])
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// ANSI styles
+const (
+	styleReset   = "\x1b[0m"
+	styleBold    = "\x1b[1m"
+	styleDim     = "\x1b[2m"
+	styleReverse = "\x1b[7m"
+	styleRed     = "\x1b[31m"
+	styleGreen   = "\x1b[32m"
+	styleYellow  = "\x1b[33m"
+	styleCyan    = "\x1b[36m"
+)
+
+var statusStyles = map[types.Status]string{
+	types.StatusOpen:       styleGreen,
+	types.StatusInProgress: styleYellow,
+	types.StatusBlocked:    styleRed,
+	types.StatusClosed:     styleDim,
+}
+
+// hints are shown in the status line when there's no message
+const (
+	listHints = "j/k move  / search  s status  0-4 priority  c comment  tab links  a closed  q quit"
+	linkHints = "j/k move  enter open  b back  tab list  q quit"
+)
+
+// View renders the screen as height lines that fit width columns. Lines
+// include ANSI styles.
+func (a *App) View(width, height int) []string {
+	width, height = max(width, 20), max(height, 4)
+	bodyHeight := height - 2
+	a.listHeight = bodyHeight
+
+	lines := make([]string, 0, height)
+	lines = append(lines, styleReverse+fit(a.header(), width)+styleReset)
+
+	listWidth := min(max(width*2/5, 24), 60)
+	if width < 60 {
+		listWidth = width // Too narrow for two panes: list only
+	}
+	list := a.viewList(listWidth, bodyHeight)
+	var detail []string
+	if listWidth < width {
+		detail = a.viewDetail(width-listWidth-1, bodyHeight)
+	}
+	for i := 0; i < bodyHeight; i++ {
+		line := list[i]
+		if detail != nil {
+			line += styleDim + "│" + styleReset + detail[i]
+		}
+		lines = append(lines, line)
+	}
+
+	lines = append(lines, a.statusLine(width))
+	return lines
+}
+
+func (a *App) header() string {
+	h := fmt.Sprintf(" beads  %d issue(s)", len(a.issues))
+	if a.query != "" {
+		h += fmt.Sprintf("  matching %q", a.query)
+	}
+	if a.showClosed {
+		h += "  (with closed)"
+	}
+	return h
+}
+
+func (a *App) viewList(width, height int) []string {
+	// Keep the cursor on screen
+	if a.cursor < a.offset {
+		a.offset = a.cursor
+	}
+	if a.cursor >= a.offset+height {
+		a.offset = a.cursor - height + 1
+	}
+
+	idWidth := 0
+	for _, issue := range a.issues {
+		idWidth = max(idWidth, utf8.RuneCountInString(issue.ID))
+	}
+
+	lines := make([]string, height)
+	for row := range lines {
+		i := a.offset + row
+		if i >= len(a.issues) {
+			lines[row] = strings.Repeat(" ", width)
+			if i == 0 && row == 0 {
+				lines[row] = styleDim + fit(" No issues", width) + styleReset
+			}
+			continue
+		}
+		issue := a.issues[i]
+		text := fit(fmt.Sprintf(" %-*s P%d %s %s", idWidth, issue.ID, issue.Priority, statusGlyph(issue.Status), issue.Title), width)
+		switch {
+		case i == a.cursor && !a.linkFocus:
+			lines[row] = styleReverse + text + styleReset
+		case i == a.cursor:
+			lines[row] = styleBold + text + styleReset
+		default:
+			lines[row] = statusStyles[issue.Status] + text + styleReset
+		}
+	}
+	return lines
+}
+
+// statusGlyph is a one-character status marker for the list
+func statusGlyph(s types.Status) string {
+	switch s {
+	case types.StatusInProgress:
+		return "◐"
+	case types.StatusBlocked:
+		return "✗"
+	case types.StatusClosed:
+		return "✓"
+	default:
+		return "○"
+	}
+}
+
+// viewDetail renders the current issue, scrolled to detailOffset (or to the
+// selected link)
+func (a *App) viewDetail(width, height int) []string {
+	var lines []string
+	add := func(style, text string) {
+		lines = append(lines, style+fit(" "+text, width)+styleReset)
+	}
+	wrapped := func(text string) {
+		for _, line := range wrap(text, width-2) {
+			add("", line)
+		}
+	}
+
+	d := a.detail
+	if d == nil {
+		add(styleDim, "No issue selected")
+	} else {
+		issue := d.issue
+		add(styleBold, issue.Title)
+		meta := fmt.Sprintf("%s · %s · P%d · %s", issue.ID, issue.Status, issue.Priority, issue.IssueType)
+		if issue.Assignee != "" {
+			meta += " · @" + issue.Assignee
+		}
+		add(statusStyles[issue.Status], meta)
+		if len(d.labels) > 0 {
+			add(styleCyan, "Labels: "+strings.Join(d.labels, ", "))
+		}
+
+		for _, section := range []struct{ title, text string }{
+			{"Description", issue.Description},
+			{"Design", issue.Design},
+			{"Acceptance criteria", issue.AcceptanceCriteria},
+			{"Notes", issue.Notes},
+		} {
+			if strings.TrimSpace(section.text) == "" {
+				continue
+			}
+			add("", "")
+			add(styleBold, section.title)
+			wrapped(section.text)
+		}
+
+		linkLine := -1
+		for i, l := range d.links {
+			// Outgoing links come first; each group gets a heading
+			if i == 0 || l.outgoing != d.links[i-1].outgoing {
+				add("", "")
+				if l.outgoing {
+					add(styleBold, "Depends on")
+				} else {
+					add(styleBold, "Depended on by")
+				}
+			}
+			text := fmt.Sprintf("%s %s %s (%s) %s", statusGlyph(l.issue.Status), l.issue.ID, l.issue.Status, l.depType, l.issue.Title)
+			style := statusStyles[l.issue.Status]
+			if a.linkFocus && i == a.link {
+				style, linkLine = styleReverse, len(lines)
+			}
+			add(style, text)
+		}
+
+		add("", "")
+		add(styleBold, fmt.Sprintf("Comments (%d)", len(d.comments)))
+		for _, c := range d.comments {
+			add(styleDim, fmt.Sprintf("%s, %s", c.Author, c.CreatedAt.Local().Format("2006-01-02 15:04")))
+			wrapped(c.Text)
+		}
+
+		if linkLine >= 0 {
+			// Scroll just enough to show the selected link
+			if linkLine < a.detailOffset {
+				a.detailOffset = linkLine
+			}
+			if linkLine >= a.detailOffset+height {
+				a.detailOffset = linkLine - height + 1
+			}
+		}
+	}
+
+	a.detailOffset = max(min(a.detailOffset, len(lines)-height), 0)
+	lines = lines[a.detailOffset:]
+	for len(lines) < height {
+		lines = append(lines, strings.Repeat(" ", width))
+	}
+	return lines[:height]
+}
+
+func (a *App) statusLine(width int) string {
+	switch a.mode {
+	case modeSearch:
+		return fit("/"+string(a.input)+"█", width)
+	case modeComment:
+		return fit("Comment on "+a.current+": "+string(a.input)+"█", width)
+	}
+	if a.message != "" {
+		style := styleGreen
+		if strings.HasPrefix(a.message, "Error") {
+			style = styleRed
+		}
+		return style + fit(a.message, width) + styleReset
+	}
+	hints := listHints
+	if a.linkFocus {
+		hints = linkHints
+	}
+	return styleDim + fit(hints, width) + styleReset
+}
+
+// fit pads or truncates s to exactly width columns
+func fit(s string, width int) string {
+	s = strings.Map(func(r rune) rune {
+		if r < ' ' {
+			return ' '
+		}
+		return r
+	}, s)
+	n := utf8.RuneCountInString(s)
+	if n <= width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	if width < 1 {
+		return ""
+	}
+	return string([]rune(s)[:width-1]) + "…"
+}
+
+// wrap breaks text into lines of at most width runes, at spaces where it can
+func wrap(text string, width int) []string {
+	width = max(width, 1)
+	var lines []string
+	for _, paragraph := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		line := []rune{}
+		for _, word := range strings.Fields(paragraph) {
+			w := []rune(word)
+			for len(w) > width { // Break words longer than a line
+				if len(line) > 0 {
+					lines = append(lines, string(line))
+					line = line[:0]
+				}
+				lines = append(lines, string(w[:width]))
+				w = w[width:]
+			}
+			if len(line) > 0 && len(line)+1+len(w) > width {
+				lines = append(lines, string(line))
+				line = line[:0]
+			}
+			if len(line) > 0 {
+				line = append(line, ' ')
+			}
+			line = append(line, w...)
+		}
+		lines = append(lines, string(line))
+	}
+	return lines
+}
@@ -0,0 +1,143 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrNotFound matches every *NotFoundError with errors.Is
+var ErrNotFound = errors.New("not found")
+
+// NotFoundError is returned for an issue or other record that doesn't exist
+type NotFoundError struct {
+	Kind string `json:"kind"` // what was looked up, e.g. "issue" or "milestone"
+	ID   string `json:"id"`
+}
+
+// NotFound returns a *NotFoundError for the kind of record with id
+func NotFound(kind string, id interface{}) error {
+	return &NotFoundError{Kind: kind, ID: fmt.Sprint(id)}
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s %s not found", e.Kind, e.ID)
+}
+
+// Is makes errors.Is(err, ErrNotFound) hold
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// CycleError is returned for a dependency that would close a cycle
+type CycleError struct {
+	IssueID     string `json:"issue_id"`
+	DependsOnID string `json:"depends_on_id"`
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("cannot add dependency: would create a cycle (%s → %s → ... → %s)", e.IssueID, e.DependsOnID, e.IssueID)
+}
+
+// ErrorCode classifies a failed API request. Codes are stable: clients can
+// switch on them rather than on messages.
+type ErrorCode string
+
+// Error codes of the HTTP API
+const (
+	CodeBadRequest        ErrorCode = "bad_request"        // malformed request or parameters
+	CodeUnauthorized      ErrorCode = "unauthorized"       // missing or invalid credentials
+	CodeForbidden         ErrorCode = "forbidden"          // credentials lack the role, or the server is read-only
+	CodeNotFound          ErrorCode = "not_found"          // the issue or other record doesn't exist
+	CodeConflict          ErrorCode = "conflict"           // the record changed, or already exists
+	CodeDependencyCycle   ErrorCode = "dependency_cycle"   // the dependency would close a cycle
+	CodeValidationFailed  ErrorCode = "validation_failed"  // the issue or other content is invalid
+	CodeInvalidTransition ErrorCode = "invalid_transition" // the workflow doesn't allow the status change
+	CodeRateLimited       ErrorCode = "rate_limited"       // too many requests; retry later
+	CodeNotImplemented    ErrorCode = "not_implemented"    // the storage backend lacks the feature
+	CodeUnavailable       ErrorCode = "unavailable"        // the server or an upstream is down
+	CodeInternal          ErrorCode = "internal"           // anything else
+)
+
+// ErrorCodes lists every ErrorCode
+var ErrorCodes = []ErrorCode{
+	CodeBadRequest, CodeUnauthorized, CodeForbidden, CodeNotFound, CodeConflict,
+	CodeDependencyCycle, CodeValidationFailed, CodeInvalidTransition,
+	CodeRateLimited, CodeNotImplemented, CodeUnavailable, CodeInternal,
+}
+
+// ErrorCodeForStatus returns the general code for an HTTP status, for
+// errors without a more specific one
+func ErrorCodeForStatus(status int) ErrorCode {
+	switch status {
+	case http.StatusBadRequest, http.StatusRequestEntityTooLarge, http.StatusUnsupportedMediaType:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound, http.StatusGone:
+		return CodeNotFound
+	case http.StatusConflict, http.StatusPreconditionFailed:
+		return CodeConflict
+	case http.StatusUnprocessableEntity:
+		return CodeValidationFailed
+	case http.StatusTooManyRequests:
+		return CodeRateLimited
+	case http.StatusNotImplemented:
+		return CodeNotImplemented
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return CodeUnavailable
+	}
+	if status >= 400 && status < 500 {
+		return CodeBadRequest
+	}
+	return CodeInternal
+}
+
+// APIError is the JSON body of a failed API request. Details, when
+// present, depend on the code: violations for validation_failed, the kind
+// and id for not_found, and so on.
+type APIError struct {
+	Status  int             `json:"-"`
+	Message string          `json:"error"`
+	Code    ErrorCode       `json:"code"`
+	Details json.RawMessage `json:"details,omitempty"`
+}
+
+// ParseAPIError reads the error from a failed response's status and body.
+// A body that isn't a JSON error (a text response, or one from a proxy)
+// becomes the message.
+func ParseAPIError(status int, body []byte) *APIError {
+	e := &APIError{}
+	if err := json.Unmarshal(body, e); err != nil || e.Message == "" {
+		e = &APIError{Message: strings.TrimPrefix(strings.TrimSpace(string(body)), "Error: ")}
+		if e.Message == "" {
+			e.Message = http.StatusText(status)
+		}
+	}
+	e.Status = status
+	if e.Code == "" {
+		e.Code = ErrorCodeForStatus(status)
+	}
+	return e
+}
+
+func (e *APIError) Error() string {
+	var invalid ValidationError
+	if e.Code == CodeValidationFailed && json.Unmarshal(e.Details, &invalid) == nil && len(invalid.Violations) > 1 {
+		lines := make([]string, len(invalid.Violations))
+		for i, v := range invalid.Violations {
+			lines[i] = "\n  " + v.Field + ": " + v.Message
+		}
+		return fmt.Sprintf("%d problems:%s", len(lines), strings.Join(lines, ""))
+	}
+	return e.Message
+}
+
+// Is makes errors.Is(err, ErrNotFound) hold for a not_found error
+func (e *APIError) Is(target error) bool {
+	return target == ErrNotFound && e.Code == CodeNotFound
+}
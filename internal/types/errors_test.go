@@ -0,0 +1,50 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestNotFoundError(t *testing.T) {
+	err := fmt.Errorf("failed to load: %w", NotFound("issue", "bd-7"))
+	if !errors.Is(err, ErrNotFound) {
+		t.Error("Expected a wrapped NotFoundError to match ErrNotFound")
+	}
+	if err.Error() != "failed to load: issue bd-7 not found" {
+		t.Errorf("Unexpected message %q", err.Error())
+	}
+	if NotFound("webhook", 3).Error() != "webhook 3 not found" {
+		t.Errorf("Unexpected message %q", NotFound("webhook", 3).Error())
+	}
+}
+
+func TestParseAPIError(t *testing.T) {
+	e := ParseAPIError(http.StatusUnprocessableEntity, []byte(`{
+		"error": "title is required; priority must be between 0 and 4 (got 7)",
+		"code": "validation_failed",
+		"details": {"violations": [
+			{"field": "title", "code": "required", "message": "title is required"},
+			{"field": "priority", "code": "out_of_range", "message": "priority must be between 0 and 4 (got 7)"}
+		]},
+		"success": false
+	}`))
+	if e.Status != http.StatusUnprocessableEntity || e.Code != CodeValidationFailed {
+		t.Errorf("Unexpected error %+v", e)
+	}
+	want := "2 problems:\n  title: title is required\n  priority: priority must be between 0 and 4 (got 7)"
+	if e.Error() != want {
+		t.Errorf("Expected violations listed, got %q", e.Error())
+	}
+
+	// Text bodies and bodies from proxies fall back to the status
+	e = ParseAPIError(http.StatusNotFound, []byte("Error: issue bd-9 not found\n"))
+	if e.Code != CodeNotFound || e.Error() != "issue bd-9 not found" || !errors.Is(e, ErrNotFound) {
+		t.Errorf("Unexpected error %+v", e)
+	}
+	e = ParseAPIError(http.StatusBadGateway, nil)
+	if e.Code != CodeUnavailable || e.Error() != "Bad Gateway" {
+		t.Errorf("Unexpected error %+v", e)
+	}
+}
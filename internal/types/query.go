@@ -0,0 +1,434 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// QueryOp is the kind of a QueryExpr node
+type QueryOp string
+
+const (
+	QueryAnd  QueryOp = "and"
+	QueryOr   QueryOp = "or"
+	QueryNot  QueryOp = "not"
+	QueryTerm QueryOp = "term"
+)
+
+// QueryExpr is a parsed issue query such as
+//
+//	status:open AND (label:backend OR assignee:alice) AND updated:<7d
+//
+// Terms are field:value pairs; bare words search the title, description,
+// and ID. Terms next to each other are ANDed, AND binds tighter than OR, and
+// NOT or a leading - negates a term or group. The operators are uppercase,
+// so a lowercase "or" is just a word. Storage backends evaluate the tree
+// themselves; see ParseQuery for the fields.
+type QueryExpr struct {
+	Op   QueryOp
+	Args []*QueryExpr // operands of and, or, and not
+
+	Field   string    // terms only: one of QueryFields, or "text" for bare words
+	Compare string    // "=", "<", "<=", ">", or ">="
+	Values  []string  // matches any of these; for priority, decimal numbers
+	Time    time.Time // for the date fields
+}
+
+// QueryFields are the fields query terms can name
+var QueryFields = []string{
+	"status", "type", "priority", "assignee", "milestone", "id", "label", "title",
+	"created", "updated", "closed", "due", "start",
+}
+
+// queryAgePattern matches ages such as 36h, 7d, and 2w
+var queryAgePattern = regexp.MustCompile(`^(\d+)([hdw])$`)
+
+// ParseQuery parses a query, resolving relative dates against now. An empty
+// query parses to nil.
+//
+// status and type take known values, priority a number or P-number, label a
+// label filter such as infra/*, and several comma-separated values match any
+// of them (status:open,in_progress). assignee:unassigned or assignee:"" match
+// issues with no assignee. title matches a substring.
+//
+// priority and the date fields (created, updated, closed, due, start) also
+// take <, <=, >, and >=. created, updated, and closed compare ages, so
+// updated:<7d means updated within the last week; due and start take dates as
+// ParseDate does, so due:<+7d means due within the next week. A date without
+// a comparison matches that whole day.
+func ParseQuery(s string, now time.Time) (*QueryExpr, error) {
+	tokens, err := lexQuery(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+	p := &queryParser{tokens: tokens, now: now}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok, ok := p.peek(); ok {
+		return nil, fmt.Errorf("unexpected %q in query", tok.text)
+	}
+	return expr, nil
+}
+
+// queryToken is a word or parenthesis of a query
+type queryToken struct {
+	text   string
+	quoted bool // wholly quoted, so never an operator or field term
+}
+
+// is reports whether the token is the unquoted keyword or parenthesis s
+func (t queryToken) is(s string) bool {
+	return !t.quoted && t.text == s
+}
+
+func lexQuery(s string) ([]queryToken, error) {
+	var tokens []queryToken
+	for i := 0; i < len(s); {
+		switch c := s[i]; c {
+		case ' ', '\t', '\n', '\r':
+			i++
+		case '(', ')':
+			tokens = append(tokens, queryToken{text: string(c)})
+			i++
+		default:
+			// A word runs to whitespace or a parenthesis; quoted parts such as
+			// title:"login page" may contain either
+			var word strings.Builder
+			quoted := c == '"'
+			for i < len(s) && !strings.ContainsRune(" \t\n\r()", rune(s[i])) {
+				if s[i] != '"' {
+					word.WriteByte(s[i])
+					i++
+					continue
+				}
+				end := strings.IndexByte(s[i+1:], '"')
+				if end < 0 {
+					return nil, fmt.Errorf("unterminated quote in query")
+				}
+				word.WriteString(s[i+1 : i+1+end])
+				i += end + 2
+			}
+			tokens = append(tokens, queryToken{text: word.String(), quoted: quoted})
+		}
+	}
+	return tokens, nil
+}
+
+type queryParser struct {
+	tokens []queryToken
+	pos    int
+	now    time.Time
+}
+
+func (p *queryParser) peek() (queryToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return queryToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *queryParser) parseOr() (*QueryExpr, error) {
+	args := []*QueryExpr{}
+	for {
+		expr, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, expr)
+		if tok, ok := p.peek(); !ok || !tok.is("OR") {
+			break
+		}
+		p.pos++
+	}
+	if len(args) == 1 {
+		return args[0], nil
+	}
+	return &QueryExpr{Op: QueryOr, Args: args}, nil
+}
+
+func (p *queryParser) parseAnd() (*QueryExpr, error) {
+	args := []*QueryExpr{}
+	for {
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, expr)
+
+		tok, ok := p.peek()
+		if !ok || tok.is(")") || tok.is("OR") {
+			break
+		}
+		if tok.is("AND") {
+			p.pos++
+		}
+	}
+	if len(args) == 1 {
+		return args[0], nil
+	}
+	return &QueryExpr{Op: QueryAnd, Args: args}, nil
+}
+
+func (p *queryParser) parseUnary() (*QueryExpr, error) {
+	tok, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("query ends unexpectedly")
+	}
+	p.pos++
+
+	switch {
+	case tok.is("NOT"), tok.is("-"):
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &QueryExpr{Op: QueryNot, Args: []*QueryExpr{expr}}, nil
+	case tok.is("("):
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if tok, ok := p.peek(); !ok || !tok.is(")") {
+			return nil, fmt.Errorf("missing ) in query")
+		}
+		p.pos++
+		return expr, nil
+	case tok.is(")"), tok.is("AND"), tok.is("OR"):
+		return nil, fmt.Errorf("unexpected %q in query", tok.text)
+	case !tok.quoted && len(tok.text) > 1 && tok.text[0] == '-':
+		tok.text = tok.text[1:]
+		expr, err := p.parseTerm(tok)
+		if err != nil {
+			return nil, err
+		}
+		return &QueryExpr{Op: QueryNot, Args: []*QueryExpr{expr}}, nil
+	}
+	return p.parseTerm(tok)
+}
+
+func (p *queryParser) parseTerm(tok queryToken) (*QueryExpr, error) {
+	field, value, ok := strings.Cut(tok.text, ":")
+	if tok.quoted || !ok {
+		return &QueryExpr{Op: QueryTerm, Field: "text", Compare: "=", Values: []string{tok.text}}, nil
+	}
+	field = strings.ToLower(field)
+	if !slices.Contains(QueryFields, field) {
+		return nil, fmt.Errorf("unknown query field %q (valid fields: %s)", field, strings.Join(QueryFields, ", "))
+	}
+
+	negate := strings.HasPrefix(value, "!")
+	value = strings.TrimPrefix(value, "!")
+	compare := "="
+	for _, op := range []string{"<=", ">=", "<", ">", "="} {
+		if strings.HasPrefix(value, op) {
+			compare, value = op, value[len(op):]
+			break
+		}
+	}
+
+	term := &QueryExpr{Op: QueryTerm, Field: field, Compare: compare}
+	var err error
+	switch field {
+	case "created", "updated", "closed", "due", "start":
+		term, err = p.dateTerm(term, value)
+	default:
+		err = term.setValues(value)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", field, err)
+	}
+
+	if negate {
+		return &QueryExpr{Op: QueryNot, Args: []*QueryExpr{term}}, nil
+	}
+	return term, nil
+}
+
+// setValues checks and sets the values of a term on a field other than a date
+func (q *QueryExpr) setValues(value string) error {
+	if q.Compare != "=" && q.Field != "priority" {
+		return fmt.Errorf("%s only matches values, not comparisons", q.Field)
+	}
+	if q.Field == "title" {
+		if value == "" {
+			return fmt.Errorf("missing value")
+		}
+		q.Values = []string{value}
+		return nil
+	}
+
+	for _, v := range strings.Split(value, ",") {
+		switch q.Field {
+		case "status":
+			if !Status(v).IsValid() {
+				return fmt.Errorf("unknown status %q", v)
+			}
+		case "type":
+			if !IssueType(v).IsValid() {
+				return fmt.Errorf("unknown issue type %q", v)
+			}
+		case "priority":
+			n, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(v), "P"))
+			if err != nil {
+				return fmt.Errorf("%q is not a priority", v)
+			}
+			v = strconv.Itoa(n)
+		case "assignee":
+			if v == "unassigned" {
+				v = ""
+			}
+		case "label", "id":
+			if v == "" {
+				return fmt.Errorf("missing value")
+			}
+		}
+		q.Values = append(q.Values, v)
+	}
+	if q.Compare != "=" && len(q.Values) != 1 {
+		return fmt.Errorf("%s compares with one value", q.Compare)
+	}
+	return nil
+}
+
+// dateTerm resolves the date of a term on a date field. Ages are turned into
+// the time that long ago, with the comparison flipped: created less than 7d
+// ago is created after that time.
+func (p *queryParser) dateTerm(q *QueryExpr, value string) (*QueryExpr, error) {
+	past := q.Field == "created" || q.Field == "updated" || q.Field == "closed"
+	if m := queryAgePattern.FindStringSubmatch(value); m != nil && past {
+		n, _ := strconv.Atoi(m[1])
+		unit := map[string]time.Duration{"h": time.Hour, "d": 24 * time.Hour, "w": 7 * 24 * time.Hour}[m[2]]
+		q.Time = p.now.Add(-time.Duration(n) * unit)
+		q.Compare = map[string]string{"=": ">=", "<": ">", "<=": ">=", ">": "<", ">=": "<="}[q.Compare]
+		return q, nil
+	}
+
+	t, err := ParseDate(value, p.now)
+	if err != nil {
+		return nil, err
+	}
+	q.Time = t
+	if q.Compare != "=" {
+		return q, nil
+	}
+	// A bare date is the whole day
+	end := *q
+	end.Compare, end.Time = "<", t.AddDate(0, 0, 1)
+	q.Compare = ">="
+	return &QueryExpr{Op: QueryAnd, Args: []*QueryExpr{q, &end}}, nil
+}
+
+// String renders the query so that ParseQuery parses it back to the same
+// tree, for sending a parsed query to another process. Relative dates come
+// out as the times they resolved to. Values can't contain double quotes.
+func (q *QueryExpr) String() string {
+	switch q.Op {
+	case QueryAnd, QueryOr:
+		args := make([]string, len(q.Args))
+		for i, arg := range q.Args {
+			args[i] = arg.String()
+		}
+		return "(" + strings.Join(args, " "+strings.ToUpper(string(q.Op))+" ") + ")"
+	case QueryNot:
+		return "NOT " + q.Args[0].String()
+	}
+
+	if q.Field == "text" {
+		return `"` + q.Values[0] + `"`
+	}
+	value := strings.Join(q.Values, ",")
+	if !q.Time.IsZero() {
+		value = q.Time.Format(time.RFC3339Nano)
+	}
+	if q.Compare != "=" {
+		value = q.Compare + value
+	}
+	return q.Field + `:"` + value + `"`
+}
+
+// Matches reports whether issue satisfies the query. hasLabel reports whether
+// the issue carries a label matching a label filter such as infra/*.
+func (q *QueryExpr) Matches(issue *Issue, hasLabel func(filter string) bool) bool {
+	switch q.Op {
+	case QueryAnd:
+		for _, arg := range q.Args {
+			if !arg.Matches(issue, hasLabel) {
+				return false
+			}
+		}
+		return true
+	case QueryOr:
+		for _, arg := range q.Args {
+			if arg.Matches(issue, hasLabel) {
+				return true
+			}
+		}
+		return false
+	case QueryNot:
+		return !q.Args[0].Matches(issue, hasLabel)
+	}
+
+	switch q.Field {
+	case "status":
+		return slices.Contains(q.Values, string(issue.Status))
+	case "type":
+		return slices.Contains(q.Values, string(issue.IssueType))
+	case "priority":
+		for _, v := range q.Values {
+			n, _ := strconv.Atoi(v)
+			if queryCompare(issue.Priority-n, q.Compare) {
+				return true
+			}
+		}
+		return false
+	case "assignee":
+		return slices.Contains(q.Values, issue.Assignee)
+	case "milestone":
+		return slices.Contains(q.Values, issue.Milestone)
+	case "id":
+		return slices.Contains(q.Values, issue.ID)
+	case "label":
+		return slices.ContainsFunc(q.Values, hasLabel)
+	case "title":
+		return strings.Contains(strings.ToLower(issue.Title), strings.ToLower(q.Values[0]))
+	case "text":
+		text := strings.ToLower(q.Values[0])
+		return strings.Contains(strings.ToLower(issue.Title), text) ||
+			strings.Contains(strings.ToLower(issue.Description), text) ||
+			strings.Contains(strings.ToLower(issue.ID), text)
+	}
+
+	t := map[string]*time.Time{
+		"created": &issue.CreatedAt,
+		"updated": &issue.UpdatedAt,
+		"closed":  issue.ClosedAt,
+		"due":     issue.DueDate,
+		"start":   issue.StartDate,
+	}[q.Field]
+	return t != nil && queryCompare(t.Compare(q.Time), q.Compare)
+}
+
+// queryCompare reports whether a comparison result c (negative, zero, or
+// positive) satisfies compare
+func queryCompare(c int, compare string) bool {
+	switch compare {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return c == 0
+}
@@ -0,0 +1,121 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseQueryErrors(t *testing.T) {
+	now := time.Now()
+	for _, query := range []string{
+		"status:done",
+		"priority:high",
+		"colour:red",
+		"status:<open",
+		"priority:<1,2",
+		"updated:<yesterdayish",
+		"(status:open",
+		"status:open)",
+		"status:open AND",
+		"OR status:open",
+		`title:"unterminated`,
+	} {
+		if _, err := ParseQuery(query, now); err == nil {
+			t.Errorf("ParseQuery(%q) succeeded, expected an error", query)
+		}
+	}
+
+	if q, err := ParseQuery("  ", now); err != nil || q != nil {
+		t.Errorf("Expected a blank query to parse to nil, got %+v, %v", q, err)
+	}
+}
+
+func TestQueryMatches(t *testing.T) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.Local)
+	due := time.Date(2025, 6, 18, 0, 0, 0, 0, time.Local)
+	issue := &Issue{
+		ID:          "bd-7",
+		Title:       "Fix login page",
+		Description: "Users see a blank screen",
+		Status:      StatusOpen,
+		Priority:    1,
+		IssueType:   TypeBug,
+		CreatedAt:   now.AddDate(0, 0, -30),
+		UpdatedAt:   now.AddDate(0, 0, -2),
+		DueDate:     &due,
+	}
+	labels := []string{"backend", "infra/db"}
+	hasLabel := func(filter string) bool {
+		for _, label := range labels {
+			if MatchesLabelFilter(label, filter) {
+				return true
+			}
+		}
+		return false
+	}
+
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"status:open AND (label:backend OR assignee:alice) AND updated:<7d", true},
+		{"status:open (label:frontend OR assignee:alice)", false},
+		{"status:closed OR priority:1", true},
+		{"status:open,in_progress type:bug", true},
+		{"priority:<=P1 priority:>0", true},
+		{"priority:>1", false},
+		{"-status:open", false},
+		{"NOT (label:frontend OR label:infra/*)", false},
+		{"status:!closed", true},
+		{"assignee:unassigned", true},
+		{`assignee:""`, true},
+		{"login", true},
+		{"blank screen", true},
+		{`"blank page"`, false},
+		{`title:"login page"`, true},
+		{"title:blank", false},
+		{"updated:>7d", false},
+		{"created:>7d created:<60d", true},
+		{"created:2025-05-16", true},
+		{"created:2025-05-17", false},
+		{"due:<+7d", true},
+		{"due:<tomorrow", false},
+		{"closed:<30d", false},
+		{"NOT closed:<30d", true},
+		{"id:bd-1,bd-7", true},
+		{"milestone:v1", false},
+		{"status:open or", false}, // lowercase or is a search word
+	}
+	for _, tt := range tests {
+		q, err := ParseQuery(tt.query, now)
+		if err != nil {
+			t.Errorf("ParseQuery(%q) failed: %v", tt.query, err)
+			continue
+		}
+		if got := q.Matches(issue, hasLabel); got != tt.want {
+			t.Errorf("%q matches = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestQueryStringRoundTrip(t *testing.T) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.Local)
+	for _, query := range []string{
+		`status:open,in_progress AND (label:backend OR assignee:unassigned) AND updated:<7d`,
+		`-type:chore priority:<=1 "login page"`,
+		`NOT (milestone:v2 OR id:bd-7) title:"blank screen" due:2025-06-18`,
+	} {
+		want, err := ParseQuery(query, now)
+		if err != nil {
+			t.Fatalf("ParseQuery(%q): %v", query, err)
+		}
+		// Parse the rendering at another time: relative dates are resolved
+		got, err := ParseQuery(want.String(), now.Add(48*time.Hour))
+		if err != nil {
+			t.Fatalf("ParseQuery(%q): %v", want.String(), err)
+		}
+		if got.String() != want.String() {
+			t.Errorf("%q rendered as %q, which parses as %q", query, want.String(), got.String())
+		}
+	}
+}
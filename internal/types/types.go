@@ -3,7 +3,12 @@ package types
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
 )
 
 // Issue represents a trackable work item
@@ -27,39 +32,92 @@ type Issue struct {
 	CompactedAt        *time.Time     `json:"compacted_at,omitempty"`
 	CompactedAtCommit  *string        `json:"compacted_at_commit,omitempty"` // Git commit hash when compacted
 	OriginalSize       int            `json:"original_size,omitempty"`
+	DeletedAt          *time.Time     `json:"deleted_at,omitempty"` // Set while the issue is in the trash
+	DeletedBy          string         `json:"deleted_by,omitempty"`
+	DueDate            *time.Time     `json:"due_date,omitempty"`   // When the work should be done by
+	StartDate          *time.Time     `json:"start_date,omitempty"` // Not ready work before this
+	Milestone          string         `json:"milestone,omitempty"`  // Name of the milestone (sprint) the issue is planned for
+	Version            int            `json:"version,omitempty"` // Latest revision in the local history, for optimistic concurrency; not exported
 	Labels             []string       `json:"labels,omitempty"` // Populated only for export/import
 	Dependencies       []*Dependency  `json:"dependencies,omitempty"` // Populated only for export/import
 	Comments           []*Comment     `json:"comments,omitempty"`     // Populated only for export/import
+	ParentID           string         `json:"parent_id,omitempty"`    // From the parent-child dependency; set on create to make a subtask
+	Subtasks           *SubtaskProgress `json:"subtasks,omitempty"`   // Roll-up of direct children, populated only for API issue detail
+	Links              []*IssueLink     `json:"links,omitempty"`      // Non-blocking links to and from the issue, populated only for issue detail
+	Watchers           []string         `json:"watchers,omitempty"`   // Users subscribed to the issue, populated only for issue detail
+	DependencyCount    int              `json:"dependency_count,omitempty"` // Issues this one depends on; this and the counts below are populated only for listings with IssueFilter.WithCounts
+	DependentCount     int              `json:"dependent_count,omitempty"`  // Issues depending on this one
+	CommentCount       int              `json:"comment_count,omitempty"`
 }
 
-// Validate checks if the issue has valid field values
+// Validate checks if the issue has valid field values, allowing only the
+// built-in statuses
 func (i *Issue) Validate() error {
-	if len(i.Title) == 0 {
-		return fmt.Errorf("title is required")
-	}
-	if len(i.Title) > 500 {
-		return fmt.Errorf("title must be 500 characters or less (got %d)", len(i.Title))
-	}
-	if i.Priority < 0 || i.Priority > 4 {
-		return fmt.Errorf("priority must be between 0 and 4 (got %d)", i.Priority)
+	return i.ValidateFor(nil)
+}
+
+// ValidateFor checks if the issue has valid field values, allowing the
+// statuses of workflow w (the built-in ones if w is nil). It returns a
+// *ValidationError listing every problem.
+func (i *Issue) ValidateFor(w *Workflow) error {
+	return NewValidationError(i.Violations(w)...)
+}
+
+// IsOverdue reports whether the issue is still open after its due date. A due
+// date holds through the end of its day, so an issue due today isn't overdue.
+func (i *Issue) IsOverdue(now time.Time) bool {
+	return i.DueDate != nil && i.Status != StatusClosed && i.DueDate.Before(OverdueCutoff(now))
+}
+
+// OverdueCutoff returns the start of now's day. Open issues due before it are
+// overdue.
+func OverdueCutoff(now time.Time) time.Time {
+	y, m, d := now.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, now.Location())
+}
+
+// ParseDate parses a due or start date given as YYYY-MM-DD, RFC 3339,
+// "today", "tomorrow", or a number of days or weeks from today such as "+3d"
+// or "2w". Dates without a time are midnight local time.
+func ParseDate(s string, now time.Time) (time.Time, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	today := OverdueCutoff(now.Local())
+	switch s {
+	case "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
 	}
-	if !i.Status.IsValid() {
-		return fmt.Errorf("invalid status: %s", i.Status)
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
 	}
-	if !i.IssueType.IsValid() {
-		return fmt.Errorf("invalid issue type: %s", i.IssueType)
+	if t, err := time.Parse(time.RFC3339, strings.ToUpper(s)); err == nil {
+		return t, nil
 	}
-	if i.EstimatedMinutes != nil && *i.EstimatedMinutes < 0 {
-		return fmt.Errorf("estimated_minutes cannot be negative")
+	if offset := strings.TrimPrefix(s, "+"); len(offset) > 1 {
+		n, err := strconv.Atoi(offset[:len(offset)-1])
+		if err == nil && n >= 0 {
+			switch offset[len(offset)-1] {
+			case 'd':
+				return today.AddDate(0, 0, n), nil
+			case 'w':
+				return today.AddDate(0, 0, 7*n), nil
+			}
+		}
 	}
-	// Enforce closed_at invariant: closed_at should be set if and only if status is closed
-	if i.Status == StatusClosed && i.ClosedAt == nil {
-		return fmt.Errorf("closed issues must have closed_at timestamp")
+	return time.Time{}, fmt.Errorf("invalid date %q (use YYYY-MM-DD, RFC 3339, today, tomorrow, or +Nd/+Nw)", s)
+}
+
+// ParseOptionalDate is ParseDate for optional fields: "" gives nil
+func ParseOptionalDate(s string, now time.Time) (*time.Time, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
 	}
-	if i.Status != StatusClosed && i.ClosedAt != nil {
-		return fmt.Errorf("non-closed issues cannot have closed_at timestamp")
+	t, err := ParseDate(s, now)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	return &t, nil
 }
 
 // Status represents the current state of an issue
@@ -103,6 +161,14 @@ func (t IssueType) IsValid() bool {
 	return false
 }
 
+// SubtaskProgress summarizes the status of an issue's direct children
+type SubtaskProgress struct {
+	Total      int `json:"total"`
+	Closed     int `json:"closed"`
+	InProgress int `json:"in_progress"`
+	Blocked    int `json:"blocked"`
+}
+
 // Dependency represents a relationship between issues
 type Dependency struct {
 	IssueID     string         `json:"issue_id"`
@@ -121,12 +187,63 @@ const (
 	DepRelated        DependencyType = "related"
 	DepParentChild    DependencyType = "parent-child"
 	DepDiscoveredFrom DependencyType = "discovered-from"
+	DepDuplicates     DependencyType = "duplicates" // issue is a duplicate of depends_on_id
+	DepCausedBy       DependencyType = "caused-by"  // issue was caused by depends_on_id (e.g. a regression)
+
+	// DepRelatesTo is accepted as another name for DepRelated and stored as it
+	DepRelatesTo DependencyType = "relates-to"
 )
 
+// IssueLink is a non-blocking link (see DependencyType.IsLink) as seen from
+// one of the issues. Incoming links were added on the other issue.
+type IssueLink struct {
+	Type     DependencyType `json:"type"`
+	Incoming bool           `json:"incoming,omitempty"`
+	ID       string         `json:"id"`
+	Title    string         `json:"title"`
+	Status   Status         `json:"status"`
+	Priority int            `json:"priority"`
+}
+
+// Relation describes the link from the viewing issue's side, e.g. "duplicate of"
+func (l *IssueLink) Relation() string {
+	switch l.Type {
+	case DepDuplicates:
+		if l.Incoming {
+			return "duplicated by"
+		}
+		return "duplicate of"
+	case DepCausedBy:
+		if l.Incoming {
+			return "caused"
+		}
+		return "caused by"
+	}
+	return "related to"
+}
+
 // IsValid checks if the dependency type value is valid
 func (d DependencyType) IsValid() bool {
 	switch d {
-	case DepBlocks, DepRelated, DepParentChild, DepDiscoveredFrom:
+	case DepBlocks, DepRelated, DepParentChild, DepDiscoveredFrom, DepDuplicates, DepCausedBy, DepRelatesTo:
+		return true
+	}
+	return false
+}
+
+// Canonical returns the stored form of the type, resolving aliases
+func (d DependencyType) Canonical() DependencyType {
+	if d == DepRelatesTo {
+		return DepRelated
+	}
+	return d
+}
+
+// IsLink reports whether the type is a non-blocking link between issues
+// (related, duplicates, caused-by). Links never affect ready work.
+func (d DependencyType) IsLink() bool {
+	switch d.Canonical() {
+	case DepRelated, DepDuplicates, DepCausedBy:
 		return true
 	}
 	return false
@@ -140,11 +257,153 @@ type Label struct {
 
 // Comment represents a comment on an issue
 type Comment struct {
-	ID        int64     `json:"id"`
-	IssueID   string    `json:"issue_id"`
-	Author    string    `json:"author"`
-	Text      string    `json:"text"`
-	CreatedAt time.Time `json:"created_at"`
+	ID        int64          `json:"id"`
+	IssueID   string         `json:"issue_id"`
+	Author    string         `json:"author"`
+	Text      string         `json:"text"`
+	CreatedAt time.Time      `json:"created_at"`
+	ReplyTo   *int64         `json:"reply_to,omitempty"`   // The comment on the same issue this one answers
+	UpdatedAt *time.Time     `json:"updated_at,omitempty"` // When the text was last edited
+	Reactions map[string]int `json:"reactions,omitempty"`  // How many actors reacted each way
+}
+
+// CommentEdit is an earlier version of an edited comment
+type CommentEdit struct {
+	CommentID int64     `json:"comment_id"`
+	Text      string    `json:"text"` // The text the edit replaced
+	EditedBy  string    `json:"edited_by"`
+	EditedAt  time.Time `json:"edited_at"`
+}
+
+// CommentThread is a comment's place in a threaded listing
+type CommentThread struct {
+	Comment *Comment
+	Depth   int // 0 unless it answers another listed comment
+}
+
+// CommentThreads orders comments depth-first, each reply after the comment it
+// answers, keeping the given order otherwise. Replies to comments that aren't
+// listed start threads of their own.
+func CommentThreads(comments []*Comment) []CommentThread {
+	listed := make(map[int64]bool, len(comments))
+	for _, c := range comments {
+		listed[c.ID] = true
+	}
+	replies := make(map[int64][]*Comment)
+	var roots []*Comment
+	for _, c := range comments {
+		if c.ReplyTo != nil && *c.ReplyTo != c.ID && listed[*c.ReplyTo] {
+			replies[*c.ReplyTo] = append(replies[*c.ReplyTo], c)
+		} else {
+			roots = append(roots, c)
+		}
+	}
+
+	threads := make([]CommentThread, 0, len(comments))
+	visited := make(map[int64]bool, len(comments))
+	var walk func(c *Comment, depth int)
+	walk = func(c *Comment, depth int) {
+		if visited[c.ID] {
+			return
+		}
+		visited[c.ID] = true
+		threads = append(threads, CommentThread{Comment: c, Depth: depth})
+		for _, reply := range replies[c.ID] {
+			walk(reply, depth+1)
+		}
+	}
+	for _, c := range roots {
+		walk(c, 0)
+	}
+	// Replies caught in a cycle have no root to hang from
+	for _, c := range comments {
+		walk(c, 0)
+	}
+	return threads
+}
+
+// FormatReactions renders reaction counts as "👍 2  🎉 1", most given first
+func FormatReactions(reactions map[string]int) string {
+	names := make([]string, 0, len(reactions))
+	for name, n := range reactions {
+		if n > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if reactions[names[i]] != reactions[names[j]] {
+			return reactions[names[i]] > reactions[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s %d", name, reactions[name])
+	}
+	return strings.Join(parts, "  ")
+}
+
+// MaxReactionLength bounds a reaction, which is an emoji or a short name
+// like "+1"
+const MaxReactionLength = 32
+
+// ValidateReaction checks that a reaction is a short word or emoji
+func ValidateReaction(reaction string) error {
+	if reaction == "" {
+		return fmt.Errorf("reaction is required")
+	}
+	if len(reaction) > MaxReactionLength {
+		return fmt.Errorf("reaction must be at most %d bytes", MaxReactionLength)
+	}
+	if strings.ContainsFunc(reaction, unicode.IsSpace) {
+		return fmt.Errorf("reaction must not contain spaces")
+	}
+	return nil
+}
+
+var (
+	// markdownCode matches fenced code blocks and inline code spans
+	markdownCode = regexp.MustCompile("(?s)```.*?(```|$)|`[^`\n]*`")
+	// mentionPattern matches @name, but not the @ of an email address or URL
+	mentionPattern = regexp.MustCompile(`(^|[^\w@./])@([a-zA-Z0-9][\w.-]*)`)
+	// issueRefPattern matches issue IDs like bd-123
+	issueRefPattern = regexp.MustCompile(`\b[a-zA-Z][-a-zA-Z0-9]*-\d+\b`)
+)
+
+// CommentMentions returns the users a comment @mentions, in order of first
+// mention. Mentions in markdown code don't count.
+func CommentMentions(text string) []string {
+	var users []string
+	seen := make(map[string]bool)
+	for _, m := range mentionPattern.FindAllStringSubmatch(markdownCode.ReplaceAllString(text, " "), -1) {
+		user := strings.TrimRight(m[2], ".-")
+		if user != "" && !seen[user] {
+			seen[user] = true
+			users = append(users, user)
+		}
+	}
+	return users
+}
+
+// CommentReferences returns the issue IDs a comment mentions, like bd-123,
+// in order of first mention. IDs in markdown code don't count.
+func CommentReferences(text string) []string {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, id := range issueRefPattern.FindAllString(markdownCode.ReplaceAllString(text, " "), -1) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// Mention is a comment that @mentions a user
+type Mention struct {
+	User       string `json:"user"`
+	IssueTitle string `json:"issue_title"`
+	*Comment
 }
 
 // Event represents an audit trail entry
@@ -157,6 +416,7 @@ type Event struct {
 	NewValue  *string    `json:"new_value,omitempty"`
 	Comment   *string    `json:"comment,omitempty"`
 	CreatedAt time.Time  `json:"created_at"`
+	SessionID string     `json:"session_id,omitempty"` // Agent session that made the change, if any
 }
 
 // EventType categorizes audit trail events
@@ -175,6 +435,10 @@ const (
 	EventLabelAdded        EventType = "label_added"
 	EventLabelRemoved      EventType = "label_removed"
 	EventCompacted         EventType = "compacted"
+	EventReverted          EventType = "reverted"
+	EventDeleted           EventType = "deleted"
+	EventRestored          EventType = "restored"
+	EventMentioned         EventType = "mentioned" // a comment @mentioned a user
 )
 
 // BlockedIssue extends Issue with blocking information
@@ -187,20 +451,137 @@ type BlockedIssue struct {
 // TreeNode represents a node in a dependency tree
 type TreeNode struct {
 	Issue
-	Depth     int  `json:"depth"`
-	Truncated bool `json:"truncated"`
+	Depth     int            `json:"depth"`
+	Truncated bool           `json:"truncated"`
+	DepType   DependencyType `json:"dep_type,omitempty"` // Type of the edge from the node above; empty for the root
+	// Remote names the workspace a cross-repository node lives in; empty for local issues
+	Remote string `json:"remote,omitempty"`
+	// Unreachable says why a remote node couldn't be fetched
+	Unreachable string `json:"unreachable,omitempty"`
 }
 
 // Statistics provides aggregate metrics
 type Statistics struct {
-	TotalIssues              int     `json:"total_issues"`
-	OpenIssues               int     `json:"open_issues"`
-	InProgressIssues         int     `json:"in_progress_issues"`
-	ClosedIssues             int     `json:"closed_issues"`
-	BlockedIssues            int     `json:"blocked_issues"`
-	ReadyIssues              int     `json:"ready_issues"`
-	EpicsEligibleForClosure  int     `json:"epics_eligible_for_closure"`
-	AverageLeadTime          float64 `json:"average_lead_time_hours"`
+	TotalIssues             int             `json:"total_issues"`
+	OpenIssues              int             `json:"open_issues"`
+	InProgressIssues        int             `json:"in_progress_issues"`
+	ClosedIssues            int             `json:"closed_issues"`
+	BlockedIssues           int             `json:"blocked_issues"`
+	ReadyIssues             int             `json:"ready_issues"`
+	EpicsEligibleForClosure int             `json:"epics_eligible_for_closure"`
+	AverageLeadTime         float64         `json:"average_lead_time_hours"`
+	LoggedMinutes           int             `json:"logged_minutes,omitempty"`   // Total time in work logs
+	TimeByIssue             []*IssueTime    `json:"time_by_issue,omitempty"`    // Issues with the most logged time
+	TimeByAssignee          []*AssigneeTime `json:"time_by_assignee,omitempty"` // Logged time by issue assignee
+	GroupBy                 string          `json:"group_by,omitempty"`         // Dimension of Groups, when asked for
+	Groups                  []*StatGroup    `json:"groups,omitempty"`           // Counts by GroupBy and status
+}
+
+// Dimensions statistics can be grouped by
+const (
+	GroupByStatus   = "status"
+	GroupByAssignee = "assignee"
+	GroupByLabel    = "label"
+	GroupByType     = "type"
+	GroupByPriority = "priority"
+)
+
+// StatGroupDimensions lists the dimensions statistics can be grouped by
+var StatGroupDimensions = []string{GroupByStatus, GroupByAssignee, GroupByLabel, GroupByType, GroupByPriority}
+
+// IsValidStatGroup reports whether statistics can be grouped by dimension
+func IsValidStatGroup(dimension string) bool {
+	for _, d := range StatGroupDimensions {
+		if d == dimension {
+			return true
+		}
+	}
+	return false
+}
+
+// StatGroup counts the issues sharing one value of a grouping dimension, by
+// status. Key is empty for unassigned issues and, grouping by label, for
+// issues without labels; an issue with several labels counts under each.
+type StatGroup struct {
+	Key      string         `json:"key"`
+	Total    int            `json:"total"`
+	ByStatus map[Status]int `json:"by_status"`
+}
+
+// StatGroupStatuses returns the statuses counted in any of groups, in
+// workflow order and then by name, for table columns
+func StatGroupStatuses(groups []*StatGroup) []Status {
+	seen := make(map[Status]bool)
+	for _, g := range groups {
+		for status := range g.ByStatus {
+			seen[status] = true
+		}
+	}
+	var statuses []Status
+	for _, status := range []Status{StatusOpen, StatusInProgress, StatusBlocked, StatusClosed} {
+		if seen[status] {
+			statuses = append(statuses, status)
+			delete(seen, status)
+		}
+	}
+	var others []Status
+	for status := range seen {
+		others = append(others, status)
+	}
+	sort.Slice(others, func(i, j int) bool { return others[i] < others[j] })
+	return append(statuses, others...)
+}
+
+// SortStatGroups orders groups for display: statuses in workflow order,
+// priorities highest first, and everything else largest first. The empty
+// key (unassigned, unlabeled) comes last.
+func SortStatGroups(groupBy string, groups []*StatGroup) {
+	statusRank := map[string]int{string(StatusOpen): 0, string(StatusInProgress): 1, string(StatusBlocked): 2, string(StatusClosed): 3}
+	sort.SliceStable(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if (a.Key == "") != (b.Key == "") {
+			return b.Key == ""
+		}
+		switch groupBy {
+		case GroupByStatus:
+			ra, okA := statusRank[a.Key]
+			rb, okB := statusRank[b.Key]
+			if okA != okB {
+				return okA
+			}
+			if okA && ra != rb {
+				return ra < rb
+			}
+		case GroupByPriority:
+			pa, errA := strconv.Atoi(a.Key)
+			pb, errB := strconv.Atoi(b.Key)
+			if errA == nil && errB == nil && pa != pb {
+				return pa < pb
+			}
+		default:
+			if a.Total != b.Total {
+				return a.Total > b.Total
+			}
+		}
+		return a.Key < b.Key
+	})
+}
+
+// IssueTime rolls up the time logged on one issue against its estimate
+type IssueTime struct {
+	IssueID          string `json:"issue_id"`
+	Title            string `json:"title"`
+	Assignee         string `json:"assignee,omitempty"`
+	EstimatedMinutes *int   `json:"estimated_minutes,omitempty"`
+	LoggedMinutes    int    `json:"logged_minutes"`
+}
+
+// AssigneeTime rolls up the time logged on issues assigned to one person.
+// Assignee is empty for unassigned issues.
+type AssigneeTime struct {
+	Assignee      string `json:"assignee"`
+	Issues        int    `json:"issues"`
+	LoggedMinutes int    `json:"logged_minutes"`
 }
 
 // IssueFilter is used to filter issue queries
@@ -213,7 +594,207 @@ type IssueFilter struct {
 	LabelsAny   []string  // OR semantics: issue must have AT LEAST ONE of these labels
 	TitleSearch string
 	IDs         []string  // Filter by specific issue IDs
+	DueBefore   *time.Time // Due strictly before this time
+	DueAfter    *time.Time // Due at or after this time
+	Overdue     bool       // Open and past the due date (see Issue.IsOverdue)
+	Milestone   *string    // Assigned to this milestone
 	Limit       int
+
+	// Multi-value criteria: an issue matches a list if it has any of its
+	// values, and an Exclude list if it has none of them. An empty assignee
+	// stands for unassigned.
+	Statuses          []Status
+	ExcludeStatuses   []Status
+	Priorities        []int
+	ExcludePriorities []int
+	IssueTypes        []IssueType
+	ExcludeIssueTypes []IssueType
+	Assignees         []string
+	ExcludeAssignees  []string
+	ExcludeLabels     []string // issue must have NONE of these labels
+
+	// Query must also match, as parsed by ParseQuery
+	Query *QueryExpr
+
+	// Sort orders the results; empty means by priority, newest first
+	Sort []SortKey
+
+	// ExcludeArchived hides issues moved to the archive by bd archive run.
+	// User-facing listings set it; exports and other full scans leave it off.
+	ExcludeArchived bool
+
+	// WithCounts fills in each issue's DependencyCount, DependentCount, and
+	// CommentCount. Exports leave it off so the counts stay out of JSONL.
+	WithCounts bool
+}
+
+// IsEmpty reports whether the filter has no criteria and so matches every issue.
+// Limit, ExcludeArchived, and WithCounts are not criteria.
+func (f IssueFilter) IsEmpty() bool {
+	return f.Status == nil && f.Priority == nil && f.IssueType == nil && f.Assignee == nil &&
+		len(f.Labels) == 0 && len(f.LabelsAny) == 0 && f.TitleSearch == "" && len(f.IDs) == 0 &&
+		f.DueBefore == nil && f.DueAfter == nil && !f.Overdue && f.Milestone == nil &&
+		len(f.Statuses) == 0 && len(f.ExcludeStatuses) == 0 && len(f.Priorities) == 0 && len(f.ExcludePriorities) == 0 &&
+		len(f.IssueTypes) == 0 && len(f.ExcludeIssueTypes) == 0 && len(f.Assignees) == 0 && len(f.ExcludeAssignees) == 0 &&
+		len(f.ExcludeLabels) == 0 && f.Query == nil
+}
+
+// SortKey orders issue listings by one field
+type SortKey struct {
+	Field string // one of SortableIssueFields
+	Desc  bool
+}
+
+// SortableIssueFields are the fields, by JSON name, that issue listings can
+// be sorted by
+var SortableIssueFields = []string{
+	"id", "title", "status", "priority", "issue_type", "assignee", "estimated_minutes",
+	"created_at", "updated_at", "closed_at", "due_date", "start_date", "milestone",
+}
+
+// ParseIssueSort parses a sort spec such as "priority,-updated_at": fields
+// separated by commas, each descending if prefixed with -
+func ParseIssueSort(spec string) ([]SortKey, error) {
+	var keys []SortKey
+	for _, field := range strings.Split(spec, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		key := SortKey{Field: strings.TrimPrefix(field, "-"), Desc: strings.HasPrefix(field, "-")}
+		if !isSortableIssueField(key.Field) {
+			return nil, fmt.Errorf("cannot sort by %q (valid fields: %s)", key.Field, strings.Join(SortableIssueFields, ", "))
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func isSortableIssueField(field string) bool {
+	for _, f := range SortableIssueFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// CompareIssues orders a and b by keys, returning a negative number if a
+// comes first and a positive one if b does. Missing values (no assignee, due
+// date, and so on) sort last in either direction, as in the SQLite backend.
+func CompareIssues(a, b *Issue, keys []SortKey) int {
+	for _, key := range keys {
+		av, bv := a.sortValue(key.Field), b.sortValue(key.Field)
+		if (av == nil) != (bv == nil) {
+			if av == nil {
+				return 1
+			}
+			return -1
+		}
+		if av == nil {
+			continue
+		}
+
+		var c int
+		switch av := av.(type) {
+		case int:
+			c = av - bv.(int)
+		case string:
+			c = strings.Compare(av, bv.(string))
+		case time.Time:
+			c = av.Compare(bv.(time.Time))
+		}
+		if c != 0 {
+			if key.Desc {
+				return -c
+			}
+			return c
+		}
+	}
+	return 0
+}
+
+// sortValue returns an issue's value for a sortable field: an int, string,
+// or time.Time, or nil if the issue has none
+func (i *Issue) sortValue(field string) interface{} {
+	optionalString := func(s string) interface{} {
+		if s == "" {
+			return nil
+		}
+		return s
+	}
+	optionalTime := func(t *time.Time) interface{} {
+		if t == nil {
+			return nil
+		}
+		return *t
+	}
+
+	switch field {
+	case "id":
+		return i.ID
+	case "title":
+		return i.Title
+	case "status":
+		return string(i.Status)
+	case "priority":
+		return i.Priority
+	case "issue_type":
+		return string(i.IssueType)
+	case "assignee":
+		return optionalString(i.Assignee)
+	case "estimated_minutes":
+		if i.EstimatedMinutes == nil {
+			return nil
+		}
+		return *i.EstimatedMinutes
+	case "created_at":
+		return i.CreatedAt
+	case "updated_at":
+		return i.UpdatedAt
+	case "closed_at":
+		return optionalTime(i.ClosedAt)
+	case "due_date":
+		return optionalTime(i.DueDate)
+	case "start_date":
+		return optionalTime(i.StartDate)
+	case "milestone":
+		return optionalString(i.Milestone)
+	}
+	return nil
+}
+
+// LabelChildrenSuffix ends a label filter that matches the labels under a
+// parent rather than one label: infra/* matches infra/db and infra/db/backup
+// but not infra itself
+const LabelChildrenSuffix = "/*"
+
+// LabelFilterParent returns the parent named by a filter such as infra/*, or
+// false if the filter names a single label
+func LabelFilterParent(filter string) (string, bool) {
+	if len(filter) <= len(LabelChildrenSuffix) || !strings.HasSuffix(filter, LabelChildrenSuffix) {
+		return "", false
+	}
+	return strings.TrimSuffix(filter, LabelChildrenSuffix), true
+}
+
+// LabelPathParent returns the label a path-style label sits under, such as
+// infra for infra/db, or "" for a top-level label
+func LabelPathParent(label string) string {
+	if i := strings.LastIndex(label, "/"); i > 0 {
+		return label[:i]
+	}
+	return ""
+}
+
+// MatchesLabelFilter reports whether label satisfies a label filter by name:
+// it is the label named, or its path is under the parent of an infra/* filter.
+// The SQLite backend also follows parents set on label definitions.
+func MatchesLabelFilter(label, filter string) bool {
+	if parent, ok := LabelFilterParent(filter); ok {
+		return strings.HasPrefix(label, parent+"/")
+	}
+	return label == filter
 }
 
 // SortPolicy determines how ready work is ordered
@@ -246,11 +827,42 @@ func (s SortPolicy) IsValid() bool {
 
 // WorkFilter is used to filter ready work queries
 type WorkFilter struct {
-	Status     Status
-	Priority   *int
-	Assignee   *string
-	Limit      int
-	SortPolicy SortPolicy
+	Status      Status
+	Priority    *int
+	MaxPriority *int // Only issues at this priority or more urgent (P0 is most urgent)
+	Assignee    *string
+	Labels      []string // AND semantics: issue must have ALL these labels
+	Limit       int
+	SortPolicy  SortPolicy
+}
+
+// ReadyQueue is the ready work for one assignee; Assignee is empty for
+// unassigned work
+type ReadyQueue struct {
+	Assignee string   `json:"assignee"`
+	Issues   []*Issue `json:"issues"`
+}
+
+// AssigneeWorkload summarizes the unfinished work assigned to one person
+type AssigneeWorkload struct {
+	Assignee         string `json:"assignee"`
+	Open             int    `json:"open"`
+	InProgress       int    `json:"in_progress"`
+	Blocked          int    `json:"blocked"`
+	EstimatedMinutes int    `json:"estimated_minutes"` // Sum of estimates on unfinished issues
+	Unestimated      int    `json:"unestimated"`       // Unfinished issues with no estimate
+}
+
+// Active returns the number of unfinished issues
+func (w *AssigneeWorkload) Active() int {
+	return w.Open + w.InProgress + w.Blocked
+}
+
+// AssigneeSuggestion is a candidate assignee for an issue, with their
+// current workload
+type AssigneeSuggestion struct {
+	AssigneeWorkload
+	MatchingIssues int `json:"matching_issues"` // Their recent issues sharing a label with the issue
 }
 
 // EpicStatus represents an epic with its completion status
@@ -260,3 +872,121 @@ type EpicStatus struct {
 	ClosedChildren  int    `json:"closed_children"`
 	EligibleForClose bool  `json:"eligible_for_close"`
 }
+
+// EpicRollup is an epic's progress over all its descendants. Nested epics
+// are rolled up into their parents: their descendants count toward every
+// epic above them, and they have rollups of their own in Epics.
+type EpicRollup struct {
+	Epic       *Issue `json:"epic"`
+	Total      int    `json:"total"`       // Descendant issues, not counting nested epics
+	Closed     int    `json:"closed"`
+	InProgress int    `json:"in_progress"`
+	Blocked    int    `json:"blocked"` // Unfinished descendants that are blocked or have an open blocker
+	Percent    int    `json:"percent"` // Of descendants closed
+
+	EstimatedMinutes int `json:"estimated_minutes"` // Sum over estimated descendants
+	ClosedMinutes    int `json:"closed_minutes"`    // Of which closed
+	PercentByMinutes int `json:"percent_by_minutes"`
+	Unestimated      int `json:"unestimated"` // Descendants with no estimate, left out of the minutes
+
+	Blockers []*EpicBlocker `json:"blockers,omitempty"` // Open issues holding up unfinished descendants, most blocking first
+	Epics    []*EpicRollup  `json:"epics,omitempty"`    // Nested epics that are direct children
+}
+
+// EpicBlocker is an open issue blocking work under an epic
+type EpicBlocker struct {
+	Issue    *Issue   `json:"issue"`
+	Blocks   []string `json:"blocks"`   // The epic's descendants it blocks
+	External bool     `json:"external"` // Not itself under the epic
+}
+
+// CriticalPath is the longest chain of unfinished work under an epic, ordered
+// by blocking dependencies and weighted by estimated duration
+type CriticalPath struct {
+	EpicID       string   `json:"epic_id"`
+	Issues       []*Issue `json:"issues"`        // First to last: each issue blocks the next
+	TotalMinutes int      `json:"total_minutes"` // Sum of estimated_minutes along the path
+	Unestimated  int      `json:"unestimated"`   // Issues on the path with no estimate
+}
+
+// BlockingImpact is the unfinished work an issue holds up, directly or
+// through the issues it blocks
+type BlockingImpact struct {
+	Issue           *Issue           `json:"issue"`
+	Blocked         []*ImpactedIssue `json:"blocked"` // Nearest first
+	Total           int              `json:"total"`
+	Direct          int              `json:"direct"`                     // Blocked by the issue itself
+	HighestPriority *int             `json:"highest_priority,omitempty"` // Most urgent priority among the blocked issues
+	ByPriority      map[int]int      `json:"by_priority"`
+}
+
+// ImpactedIssue is an issue held up by another, and how
+type ImpactedIssue struct {
+	Issue     *Issue `json:"issue"`
+	Depth     int    `json:"depth"`      // 1 if blocked by the issue itself
+	BlockedBy string `json:"blocked_by"` // The issue holding it up, one step nearer
+	Inherited bool   `json:"inherited"`  // Blocked because its parent is
+}
+
+// BlockerTree is the open work holding up an issue, for its assignee to chase
+type BlockerTree struct {
+	Issue      *Issue          `json:"issue"`
+	Blockers   []*IssueBlocker `json:"blockers"` // Nearest first; each blocks the issue or an earlier blocker
+	Total      int             `json:"total"`
+	Transitive bool            `json:"transitive"` // Includes blockers of blockers
+}
+
+// IssueBlocker is an open issue holding up another, and how
+type IssueBlocker struct {
+	Issue     *Issue `json:"issue"`
+	Depth  int    `json:"depth"`         // 1 if it holds up the issue itself
+	Blocks string `json:"blocks"`        // The issue it holds up, one step nearer
+	Via    string `json:"via,omitempty"` // The ancestor of Blocks it blocks, if it doesn't block Blocks directly
+}
+
+// Children returns the blockers holding up an issue in the tree, for
+// rendering it depth-first
+func (t *BlockerTree) Children(issueID string) []*IssueBlocker {
+	var children []*IssueBlocker
+	for _, blocker := range t.Blockers {
+		if blocker.Blocks == issueID {
+			children = append(children, blocker)
+		}
+	}
+	return children
+}
+
+// DuplicateGroup is a set of issues with identical content and status, with
+// the issue the rest should be merged into
+type DuplicateGroup struct {
+	Title            string            `json:"title"`
+	Issues           []*DuplicateIssue `json:"issues"`
+	SuggestedTarget  string            `json:"suggested_target"`
+	SuggestedSources []string          `json:"suggested_sources"`
+	SuggestedCommand string            `json:"suggested_merge_cmd"`
+}
+
+// DuplicateIssue is one member of a DuplicateGroup
+type DuplicateIssue struct {
+	ID            string  `json:"id"`
+	Title         string  `json:"title"`
+	Status        Status  `json:"status"`
+	Priority      int     `json:"priority"`
+	References    int     `json:"references"` // Mentions of the ID in other issues' text
+	Score         float64 `json:"score"`      // Similarity to the merge target, 1 for exact duplicates
+	IsMergeTarget bool    `json:"is_merge_target"`
+}
+
+// MergeResult reports what merging source issues into a target changed
+type MergeResult struct {
+	TargetID            string   `json:"target_id"`
+	SourceIDs           []string `json:"source_ids"`
+	Merged              int      `json:"merged"`
+	DependenciesAdded   int      `json:"dependencies_added"`
+	DependenciesSkipped int      `json:"dependencies_skipped"`
+	TextReferences      int      `json:"text_references"` // Issues whose text was rewritten
+	CommentsMoved       int      `json:"comments_moved"`
+	LabelsMoved         int      `json:"labels_moved"`
+	IssuesClosed        int      `json:"issues_closed"`
+	IssuesSkipped       int      `json:"issues_skipped"` // Sources that were already closed
+}
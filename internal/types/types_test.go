@@ -1,6 +1,8 @@
 package types
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 )
@@ -247,6 +249,9 @@ func TestDependencyTypeIsValid(t *testing.T) {
 		{DepRelated, true},
 		{DepParentChild, true},
 		{DepDiscoveredFrom, true},
+		{DepDuplicates, true},
+		{DepCausedBy, true},
+		{DepRelatesTo, true},
 		{DependencyType("invalid"), false},
 		{DependencyType(""), false},
 	}
@@ -260,6 +265,22 @@ func TestDependencyTypeIsValid(t *testing.T) {
 	}
 }
 
+func TestDependencyTypeIsLink(t *testing.T) {
+	for _, d := range []DependencyType{DepRelated, DepRelatesTo, DepDuplicates, DepCausedBy} {
+		if !d.IsLink() {
+			t.Errorf("%s should be a link", d)
+		}
+	}
+	for _, d := range []DependencyType{DepBlocks, DepParentChild, DepDiscoveredFrom} {
+		if d.IsLink() {
+			t.Errorf("%s should not be a link", d)
+		}
+	}
+	if DepRelatesTo.Canonical() != DepRelated || DepCausedBy.Canonical() != DepCausedBy {
+		t.Error("Canonical should only resolve relates-to")
+	}
+}
+
 func TestIssueStructFields(t *testing.T) {
 	// Test that all time fields work correctly
 	now := time.Now()
@@ -332,6 +353,73 @@ func TestTreeNodeEmbedding(t *testing.T) {
 	}
 }
 
+func TestParseDate(t *testing.T) {
+	now := time.Date(2025, 3, 10, 15, 30, 0, 0, time.Local)
+	day := func(m time.Month, d int) time.Time { return time.Date(2025, m, d, 0, 0, 0, 0, time.Local) }
+
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		{"2025-04-01", day(4, 1)},
+		{"2025-04-01T12:00:00Z", time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)},
+		{"today", day(3, 10)},
+		{"Tomorrow", day(3, 11)},
+		{"+3d", day(3, 13)},
+		{"2w", day(3, 24)},
+		{"0d", day(3, 10)},
+	}
+	for _, tt := range tests {
+		got, err := ParseDate(tt.input, now)
+		if err != nil {
+			t.Errorf("ParseDate(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseDate(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "soon", "+d", "-3d", "3m", "2025-13-01"} {
+		if _, err := ParseDate(bad, now); err == nil {
+			t.Errorf("ParseDate(%q) should fail", bad)
+		}
+	}
+
+	if got, err := ParseOptionalDate(" ", now); err != nil || got != nil {
+		t.Errorf("ParseOptionalDate(blank) = %v, %v; want nil, nil", got, err)
+	}
+}
+
+func TestIsOverdue(t *testing.T) {
+	now := time.Date(2025, 3, 10, 15, 30, 0, 0, time.Local)
+	tests := []struct {
+		name   string
+		due    *time.Time
+		status Status
+		want   bool
+	}{
+		{"no due date", nil, StatusOpen, false},
+		{"due yesterday", timePtr(now.AddDate(0, 0, -1)), StatusOpen, true},
+		{"due earlier today", timePtr(now.Add(-time.Hour)), StatusOpen, false},
+		{"due tomorrow", timePtr(now.AddDate(0, 0, 1)), StatusInProgress, false},
+		{"closed late", timePtr(now.AddDate(0, 0, -1)), StatusClosed, false},
+	}
+	for _, tt := range tests {
+		issue := &Issue{Status: tt.status, DueDate: tt.due}
+		if got := issue.IsOverdue(now); got != tt.want {
+			t.Errorf("%s: IsOverdue = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// A start date after the due date is invalid
+	issue := &Issue{Title: "x", Status: StatusOpen, Priority: 2, IssueType: TypeTask,
+		StartDate: timePtr(now.AddDate(0, 0, 2)), DueDate: timePtr(now)}
+	if err := issue.Validate(); err == nil {
+		t.Error("expected an error for a start date after the due date")
+	}
+}
+
 // Helper functions
 
 func intPtr(i int) *int {
@@ -354,3 +442,125 @@ func containsMiddle(s, substr string) bool {
 	}
 	return false
 }
+
+func TestMatchesLabelFilter(t *testing.T) {
+	tests := []struct {
+		label, filter string
+		want          bool
+	}{
+		{"infra", "infra", true},
+		{"infra/db", "infra", false},
+		{"infra/db", "infra/*", true},
+		{"infra/db/backup", "infra/*", true},
+		{"infra", "infra/*", false},
+		{"infrastructure", "infra/*", false},
+		{"infra/db/backup", "infra/db/*", true},
+		{"/*", "/*", true},
+	}
+	for _, tt := range tests {
+		if got := MatchesLabelFilter(tt.label, tt.filter); got != tt.want {
+			t.Errorf("MatchesLabelFilter(%q, %q) = %v, want %v", tt.label, tt.filter, got, tt.want)
+		}
+	}
+
+	if got := LabelPathParent("infra/db/backup"); got != "infra/db" {
+		t.Errorf("LabelPathParent = %q, want infra/db", got)
+	}
+	if got := LabelPathParent("infra"); got != "" {
+		t.Errorf("LabelPathParent = %q, want empty", got)
+	}
+}
+
+func TestParseIssueSort(t *testing.T) {
+	keys, err := ParseIssueSort("priority, -updated_at,")
+	if err != nil {
+		t.Fatalf("ParseIssueSort failed: %v", err)
+	}
+	if len(keys) != 2 || keys[0] != (SortKey{Field: "priority"}) || keys[1] != (SortKey{Field: "updated_at", Desc: true}) {
+		t.Errorf("Unexpected keys %+v", keys)
+	}
+	if keys, err := ParseIssueSort(""); err != nil || keys != nil {
+		t.Errorf("Expected no keys for an empty spec, got %+v, %v", keys, err)
+	}
+	if _, err := ParseIssueSort("labels"); err == nil {
+		t.Error("Expected an error sorting by labels")
+	}
+}
+
+func TestCompareIssues(t *testing.T) {
+	due := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := &Issue{ID: "bd-1", Priority: 1, Assignee: "bob", DueDate: &due}
+	b := &Issue{ID: "bd-2", Priority: 1}
+
+	tests := []struct {
+		spec string
+		want int // sign of CompareIssues(a, b)
+	}{
+		{"priority", 0},
+		{"priority,id", -1},
+		{"priority,-id", 1},
+		{"assignee", -1},
+		{"-assignee", -1}, // no assignee sorts last either way
+		{"-due_date", -1},
+	}
+	for _, tt := range tests {
+		keys, err := ParseIssueSort(tt.spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := CompareIssues(a, b, keys)
+		if (got < 0 && tt.want >= 0) || (got > 0 && tt.want <= 0) || (got == 0 && tt.want != 0) {
+			t.Errorf("CompareIssues by %s = %d, want sign %d", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestCommentThreads(t *testing.T) {
+	ref := func(id int64) *int64 { return &id }
+	comments := []*Comment{
+		{ID: 1},
+		{ID: 2},
+		{ID: 3, ReplyTo: ref(1)},
+		{ID: 4, ReplyTo: ref(3)},
+		{ID: 5, ReplyTo: ref(9)}, // answers a deleted comment
+		{ID: 6, ReplyTo: ref(1)},
+	}
+	var got []string
+	for _, thread := range CommentThreads(comments) {
+		got = append(got, fmt.Sprintf("%d@%d", thread.Comment.ID, thread.Depth))
+	}
+	want := "1@0 3@1 4@2 6@1 2@0 5@0"
+	if strings.Join(got, " ") != want {
+		t.Errorf("CommentThreads = %s, want %s", strings.Join(got, " "), want)
+	}
+
+	// Replies caught in a cycle are still listed once
+	cycle := []*Comment{{ID: 1, ReplyTo: ref(2)}, {ID: 2, ReplyTo: ref(1)}}
+	if threads := CommentThreads(cycle); len(threads) != 2 {
+		t.Errorf("Expected both comments of a cycle, got %d", len(threads))
+	}
+}
+
+func TestFormatReactions(t *testing.T) {
+	got := FormatReactions(map[string]int{"🎉": 1, "👍": 2, "+1": 1, "gone": 0})
+	if want := "👍 2  +1 1  🎉 1"; got != want {
+		t.Errorf("FormatReactions = %q, want %q", got, want)
+	}
+}
+
+func TestCommentMentions(t *testing.T) {
+	text := "@alice, can you and @bob.smith. look? cc @alice\n" +
+		"Not mail@example.com or https://x.com/@nobody, nor `@code` or\n```\n@fenced\n```"
+	got := strings.Join(CommentMentions(text), " ")
+	if want := "alice bob.smith"; got != want {
+		t.Errorf("CommentMentions = %q, want %q", got, want)
+	}
+}
+
+func TestCommentReferences(t *testing.T) {
+	text := "Same as bd-12, see also api-v2-7 and bd-12 again; not `bd-99`"
+	got := strings.Join(CommentReferences(text), " ")
+	if want := "bd-12 api-v2-7"; got != want {
+		t.Errorf("CommentReferences = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,151 @@
+package types
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// MaxTitleLength is the longest title an issue can have, in bytes
+const MaxTitleLength = 500
+
+// Violation codes say what is wrong with a field
+const (
+	ViolationRequired   = "required"     // the field is empty
+	ViolationTooLong    = "too_long"     // the field is longer than allowed
+	ViolationOutOfRange = "out_of_range" // a number outside its range
+	ViolationInvalid    = "invalid"      // a value that isn't one of the allowed ones
+)
+
+// Violation is one problem with one field of an issue
+type Violation struct {
+	Field   string `json:"field"`
+	Code    string `json:"code" enum:"required,too_long,out_of_range,invalid"`
+	Message string `json:"message"`
+}
+
+// ValidationError is returned for an issue with invalid fields, listing
+// every problem found rather than just the first
+type ValidationError struct {
+	Violations []Violation `json:"violations"`
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		messages[i] = v.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// NewValidationError returns a *ValidationError listing violations, or nil
+// if there are none
+func NewValidationError(violations ...Violation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: violations}
+}
+
+// RequirableFields are the issue fields that can be required, by an issue
+// type (see Issue.CheckRequired) or a workflow transition. They must be
+// non-empty.
+var RequirableFields = []string{
+	"assignee", "description", "design", "acceptance_criteria", "notes",
+	"estimated_minutes", "due_date", "milestone", "external_ref",
+}
+
+// ParseRequiredFields parses a comma-separated list of RequirableFields, as
+// stored in the validation.required.<type> config keys
+func ParseRequiredFields(value string) ([]string, error) {
+	var fields []string
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" || slices.Contains(fields, field) {
+			continue
+		}
+		if !slices.Contains(RequirableFields, field) {
+			return nil, fmt.Errorf("unknown field %q (use %s)", field, strings.Join(RequirableFields, ", "))
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// CheckTitle returns the problem with a title, if any
+func CheckTitle(title string) *Violation {
+	if strings.TrimSpace(title) == "" {
+		return &Violation{Field: "title", Code: ViolationRequired, Message: "title is required"}
+	}
+	if len(title) > MaxTitleLength {
+		return &Violation{Field: "title", Code: ViolationTooLong,
+			Message: fmt.Sprintf("title must be %d characters or less (got %d)", MaxTitleLength, len(title))}
+	}
+	return nil
+}
+
+// CheckPriority returns the problem with a priority, if any
+func CheckPriority(priority int) *Violation {
+	if priority < 0 || priority > 4 {
+		return &Violation{Field: "priority", Code: ViolationOutOfRange,
+			Message: fmt.Sprintf("priority must be between 0 and 4 (got %d)", priority)}
+	}
+	return nil
+}
+
+// CheckIssueType returns the problem with an issue type, if any
+func CheckIssueType(t IssueType) *Violation {
+	if !t.IsValid() {
+		return &Violation{Field: "issue_type", Code: ViolationInvalid,
+			Message: fmt.Sprintf("invalid issue type: %s (use bug, feature, task, epic, or chore)", t)}
+	}
+	return nil
+}
+
+// Violations returns every problem with the issue's fields, allowing the
+// statuses of workflow w (the built-in ones if w is nil)
+func (i *Issue) Violations(w *Workflow) []Violation {
+	var violations []Violation
+	add := func(v *Violation) {
+		if v != nil {
+			violations = append(violations, *v)
+		}
+	}
+	invalid := func(field, format string, args ...interface{}) {
+		violations = append(violations, Violation{Field: field, Code: ViolationInvalid, Message: fmt.Sprintf(format, args...)})
+	}
+
+	add(CheckTitle(i.Title))
+	add(CheckPriority(i.Priority))
+	if (w == nil && !i.Status.IsValid()) || (w != nil && !w.HasStatus(i.Status)) {
+		invalid("status", "invalid status: %s", i.Status)
+	}
+	add(CheckIssueType(i.IssueType))
+	if i.EstimatedMinutes != nil && *i.EstimatedMinutes < 0 {
+		violations = append(violations, Violation{Field: "estimated_minutes", Code: ViolationOutOfRange, Message: "estimated_minutes cannot be negative"})
+	}
+	// Enforce closed_at invariant: closed_at should be set if and only if status is closed
+	if i.Status == StatusClosed && i.ClosedAt == nil {
+		invalid("closed_at", "closed issues must have closed_at timestamp")
+	}
+	if i.Status != StatusClosed && i.ClosedAt != nil {
+		invalid("closed_at", "non-closed issues cannot have closed_at timestamp")
+	}
+	if i.DueDate != nil && i.StartDate != nil && i.StartDate.After(*i.DueDate) {
+		invalid("start_date", "start_date cannot be after due_date")
+	}
+	return violations
+}
+
+// CheckRequired returns a violation for each of fields, from
+// RequirableFields, that the issue leaves empty
+func (i *Issue) CheckRequired(fields []string) []Violation {
+	var violations []Violation
+	for _, field := range fields {
+		if !transitionFieldSet(i, field, "") {
+			violations = append(violations, Violation{Field: field, Code: ViolationRequired,
+				Message: fmt.Sprintf("%s is required for %s issues", field, i.IssueType)})
+		}
+	}
+	return violations
+}
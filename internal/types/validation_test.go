@@ -0,0 +1,65 @@
+package types
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateForListsEveryViolation(t *testing.T) {
+	issue := &Issue{Title: "   ", Priority: 9, Status: StatusOpen, IssueType: "story"}
+	err := issue.ValidateFor(nil)
+
+	var invalid *ValidationError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("Expected a *ValidationError, got %v", err)
+	}
+	want := []Violation{
+		{Field: "title", Code: ViolationRequired},
+		{Field: "priority", Code: ViolationOutOfRange},
+		{Field: "issue_type", Code: ViolationInvalid},
+	}
+	if len(invalid.Violations) != len(want) {
+		t.Fatalf("Expected %d violations, got %+v", len(want), invalid.Violations)
+	}
+	for i, v := range invalid.Violations {
+		if v.Field != want[i].Field || v.Code != want[i].Code {
+			t.Errorf("Violation %d: expected %s/%s, got %s/%s", i, want[i].Field, want[i].Code, v.Field, v.Code)
+		}
+	}
+	if !strings.Contains(err.Error(), "title is required; priority must be between 0 and 4") {
+		t.Errorf("Expected the messages joined, got %q", err.Error())
+	}
+
+	issue = &Issue{Title: strings.Repeat("x", MaxTitleLength+1), Priority: 2, Status: StatusOpen, IssueType: TypeTask}
+	if err := issue.ValidateFor(nil); !errors.As(err, &invalid) || invalid.Violations[0].Code != ViolationTooLong {
+		t.Errorf("Expected a too_long title, got %v", err)
+	}
+}
+
+func TestParseRequiredFields(t *testing.T) {
+	fields, err := ParseRequiredFields(" description, acceptance_criteria,,description ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(fields, ",") != "description,acceptance_criteria" {
+		t.Errorf("Expected description and acceptance_criteria, got %v", fields)
+	}
+	if fields, err := ParseRequiredFields(""); err != nil || len(fields) != 0 {
+		t.Errorf("Expected no fields, got %v (%v)", fields, err)
+	}
+	if _, err := ParseRequiredFields("description,reason"); err == nil {
+		t.Error("Expected reason to be rejected; only transitions can require it")
+	}
+}
+
+func TestCheckRequired(t *testing.T) {
+	issue := &Issue{Title: "Crash", IssueType: TypeBug, Description: "Steps to reproduce"}
+	violations := issue.CheckRequired([]string{"description", "acceptance_criteria", "assignee"})
+	if len(violations) != 2 || violations[0].Field != "acceptance_criteria" || violations[1].Field != "assignee" {
+		t.Fatalf("Expected acceptance_criteria and assignee, got %+v", violations)
+	}
+	if violations[0].Code != ViolationRequired || violations[0].Message != "acceptance_criteria is required for bug issues" {
+		t.Errorf("Unexpected violation %+v", violations[0])
+	}
+}
@@ -0,0 +1,276 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Workflow defines the statuses an issue can be in and the moves between
+// them. Custom statuses sit alongside the four built-in ones, each counting
+// as open, in_progress, or blocked wherever those matter (ready work,
+// blockers). closed is the only closed status. With no transitions, any
+// move is allowed.
+type Workflow struct {
+	Statuses    []WorkflowStatus     `json:"statuses"`
+	Transitions []WorkflowTransition `json:"transitions,omitempty"`
+}
+
+// WorkflowStatus is one status of a workflow
+type WorkflowStatus struct {
+	Name        Status `json:"name"`
+	Category    Status `json:"category" enum:"open,in_progress,blocked,closed" doc:"The built-in status this one counts as; a built-in status is its own"`
+	Description string `json:"description,omitempty"`
+}
+
+// WorkflowTransition allows moving an issue From one status To another.
+// From "*" means any status. Require names fields that must be set for the
+// move: reason (given when closing) or an issue field (see
+// TransitionFields).
+type WorkflowTransition struct {
+	From    Status   `json:"from"`
+	To      Status   `json:"to"`
+	Require []string `json:"require,omitempty"`
+}
+
+// AnyStatus as a transition's From matches every status
+const AnyStatus Status = "*"
+
+// TransitionFields are the fields a transition can require: reason, the
+// close reason, and RequirableFields
+var TransitionFields = append([]string{"reason"}, RequirableFields...)
+
+var statusNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
+// builtinStatuses are part of every workflow
+var builtinStatuses = []Status{StatusOpen, StatusInProgress, StatusBlocked, StatusClosed}
+
+// DefaultWorkflow is the workflow used until one is configured: the
+// built-in statuses, with any move allowed
+func DefaultWorkflow() *Workflow {
+	w := &Workflow{}
+	for _, s := range builtinStatuses {
+		w.Statuses = append(w.Statuses, WorkflowStatus{Name: s, Category: s})
+	}
+	return w
+}
+
+// ParseWorkflow parses and validates a workflow from JSON
+func ParseWorkflow(data []byte) (*Workflow, error) {
+	var w Workflow
+	if err := json.Unmarshal(data, &w); err != nil {
+		return nil, fmt.Errorf("invalid workflow: %w", err)
+	}
+	if err := w.Validate(); err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// Validate checks that the workflow keeps the built-in statuses, that each
+// custom status counts as an unclosed built-in one, and that transitions
+// name known statuses and fields
+func (w *Workflow) Validate() error {
+	seen := make(map[Status]bool)
+	for _, s := range w.Statuses {
+		if !statusNamePattern.MatchString(string(s.Name)) {
+			return fmt.Errorf("invalid status name %q (use lowercase letters, digits, _ and -)", s.Name)
+		}
+		if seen[s.Name] {
+			return fmt.Errorf("status %s is defined twice", s.Name)
+		}
+		seen[s.Name] = true
+		switch {
+		case s.Name.IsValid():
+			if s.Category != "" && s.Category != s.Name {
+				return fmt.Errorf("built-in status %s can't count as %s", s.Name, s.Category)
+			}
+		case s.Category == StatusOpen, s.Category == StatusInProgress, s.Category == StatusBlocked:
+		default:
+			return fmt.Errorf("status %s must count as open, in_progress, or blocked (got %q)", s.Name, s.Category)
+		}
+	}
+	for _, s := range builtinStatuses {
+		if !seen[s] {
+			return fmt.Errorf("workflow must include built-in status %s", s)
+		}
+	}
+
+	pairs := make(map[[2]Status]bool)
+	for _, t := range w.Transitions {
+		if t.From != AnyStatus && !seen[t.From] {
+			return fmt.Errorf("transition from unknown status %s", t.From)
+		}
+		if !seen[t.To] {
+			return fmt.Errorf("transition to unknown status %s", t.To)
+		}
+		if pairs[[2]Status{t.From, t.To}] {
+			return fmt.Errorf("transition %s -> %s is defined twice", t.From, t.To)
+		}
+		pairs[[2]Status{t.From, t.To}] = true
+		for _, field := range t.Require {
+			if !slices.Contains(TransitionFields, field) {
+				return fmt.Errorf("transition %s -> %s requires unknown field %q (use %s)", t.From, t.To, field, strings.Join(TransitionFields, ", "))
+			}
+			if field == "reason" && t.To != StatusClosed {
+				return fmt.Errorf("transition %s -> %s can't require a reason; only closing takes one", t.From, t.To)
+			}
+		}
+	}
+	return nil
+}
+
+// HasStatus reports whether s is one of the workflow's statuses
+func (w *Workflow) HasStatus(s Status) bool {
+	return slices.ContainsFunc(w.Statuses, func(ws WorkflowStatus) bool { return ws.Name == s })
+}
+
+// Category returns the built-in status s counts as, or s itself when it
+// isn't a custom status
+func (w *Workflow) Category(s Status) Status {
+	for _, ws := range w.Statuses {
+		if ws.Name == s && ws.Category != "" {
+			return ws.Category
+		}
+	}
+	return s
+}
+
+// StatusesIn returns the statuses counting as any of categories, in the
+// workflow's order
+func (w *Workflow) StatusesIn(categories ...Status) []Status {
+	var statuses []Status
+	for _, ws := range w.Statuses {
+		if slices.Contains(categories, w.Category(ws.Name)) {
+			statuses = append(statuses, ws.Name)
+		}
+	}
+	return statuses
+}
+
+// Transition returns the transition allowing a move from one status to
+// another, preferring one naming from over a "*" one. With no transitions
+// configured, every move between known statuses is allowed and needs
+// nothing.
+func (w *Workflow) Transition(from, to Status) (WorkflowTransition, bool) {
+	if !w.HasStatus(to) {
+		return WorkflowTransition{}, false
+	}
+	if len(w.Transitions) == 0 {
+		return WorkflowTransition{From: from, To: to}, true
+	}
+	var wildcard *WorkflowTransition
+	for i, t := range w.Transitions {
+		if t.To != to {
+			continue
+		}
+		if t.From == from {
+			return t, true
+		}
+		if t.From == AnyStatus {
+			wildcard = &w.Transitions[i]
+		}
+	}
+	if wildcard != nil {
+		return *wildcard, true
+	}
+	return WorkflowTransition{}, false
+}
+
+// Next returns the moves allowed from a status, in the workflow's status
+// order
+func (w *Workflow) Next(from Status) []WorkflowTransition {
+	next := []WorkflowTransition{}
+	for _, ws := range w.Statuses {
+		if ws.Name == from {
+			continue
+		}
+		if t, ok := w.Transition(from, ws.Name); ok {
+			next = append(next, t)
+		}
+	}
+	return next
+}
+
+// CheckTransition returns a *TransitionError if the workflow doesn't let
+// issue move to status to, or if the move requires fields issue lacks.
+// reason is the close reason, if any. Staying in the same status is always
+// allowed.
+func (w *Workflow) CheckTransition(issue *Issue, to Status, reason string) error {
+	if issue.Status == to {
+		return nil
+	}
+	t, ok := w.Transition(issue.Status, to)
+	if !ok {
+		e := &TransitionError{IssueID: issue.ID, From: issue.Status, To: to}
+		for _, next := range w.Next(issue.Status) {
+			e.Allowed = append(e.Allowed, next.To)
+		}
+		return e
+	}
+	var missing []string
+	for _, field := range t.Require {
+		if !transitionFieldSet(issue, field, reason) {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		return &TransitionError{IssueID: issue.ID, From: issue.Status, To: to, Missing: missing}
+	}
+	return nil
+}
+
+// transitionFieldSet reports whether issue has field, one of
+// TransitionFields, set
+func transitionFieldSet(issue *Issue, field, reason string) bool {
+	switch field {
+	case "reason":
+		return strings.TrimSpace(reason) != ""
+	case "assignee":
+		return issue.Assignee != ""
+	case "description":
+		return strings.TrimSpace(issue.Description) != ""
+	case "design":
+		return strings.TrimSpace(issue.Design) != ""
+	case "acceptance_criteria":
+		return strings.TrimSpace(issue.AcceptanceCriteria) != ""
+	case "notes":
+		return strings.TrimSpace(issue.Notes) != ""
+	case "estimated_minutes":
+		return issue.EstimatedMinutes != nil
+	case "due_date":
+		return issue.DueDate != nil
+	case "milestone":
+		return issue.Milestone != ""
+	case "external_ref":
+		return issue.ExternalRef != nil && *issue.ExternalRef != ""
+	}
+	return false
+}
+
+// TransitionError is returned for a status change the workflow doesn't
+// allow, or allows only once Missing fields are set
+type TransitionError struct {
+	IssueID string   `json:"issue_id"`
+	From    Status   `json:"from"`
+	To      Status   `json:"to"`
+	Missing []string `json:"missing,omitempty"`
+	Allowed []Status `json:"allowed,omitempty"` // where the issue can move instead
+}
+
+func (e *TransitionError) Error() string {
+	if len(e.Missing) > 0 {
+		return fmt.Sprintf("moving %s from %s to %s requires %s", e.IssueID, e.From, e.To, strings.Join(e.Missing, ", "))
+	}
+	msg := fmt.Sprintf("the workflow doesn't allow moving %s from %s to %s", e.IssueID, e.From, e.To)
+	if len(e.Allowed) == 0 {
+		return msg + " (it can't move from " + string(e.From) + ")"
+	}
+	allowed := make([]string, len(e.Allowed))
+	for i, s := range e.Allowed {
+		allowed[i] = string(s)
+	}
+	return msg + " (allowed: " + strings.Join(allowed, ", ") + ")"
+}
@@ -0,0 +1,81 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWorkflowValidate(t *testing.T) {
+	if err := DefaultWorkflow().Validate(); err != nil {
+		t.Fatalf("Default workflow is invalid: %v", err)
+	}
+
+	custom := func(status WorkflowStatus, transitions ...WorkflowTransition) *Workflow {
+		w := DefaultWorkflow()
+		w.Statuses = append(w.Statuses, status)
+		w.Transitions = transitions
+		return w
+	}
+	tests := []struct {
+		name     string
+		workflow *Workflow
+		wantErr  string
+	}{
+		{"custom status", custom(WorkflowStatus{Name: "in_review", Category: StatusInProgress}), ""},
+		{"missing built-in", &Workflow{Statuses: []WorkflowStatus{{Name: StatusOpen}}}, "built-in status in_progress"},
+		{"bad name", custom(WorkflowStatus{Name: "In Review", Category: StatusOpen}), "invalid status name"},
+		{"no category", custom(WorkflowStatus{Name: "triaged"}), "must count as"},
+		{"closed category", custom(WorkflowStatus{Name: "done", Category: StatusClosed}), "must count as"},
+		{"unknown target", custom(WorkflowStatus{Name: "triaged", Category: StatusOpen}, WorkflowTransition{From: StatusOpen, To: "done"}), "unknown status done"},
+		{"unknown field", custom(WorkflowStatus{Name: "triaged", Category: StatusOpen}, WorkflowTransition{From: AnyStatus, To: "triaged", Require: []string{"owner"}}), "unknown field"},
+		{"reason off close", custom(WorkflowStatus{Name: "triaged", Category: StatusOpen}, WorkflowTransition{From: AnyStatus, To: "triaged", Require: []string{"reason"}}), "only closing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.workflow.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected valid, got %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestWorkflowTransitions(t *testing.T) {
+	w := DefaultWorkflow()
+	w.Transitions = []WorkflowTransition{
+		{From: AnyStatus, To: StatusClosed},
+		{From: StatusInProgress, To: StatusClosed, Require: []string{"reason"}},
+		{From: StatusOpen, To: StatusInProgress},
+	}
+
+	// A transition naming the status wins over "*"
+	if tr, ok := w.Transition(StatusInProgress, StatusClosed); !ok || len(tr.Require) != 1 {
+		t.Errorf("Expected in_progress -> closed to require a reason, got %+v", tr)
+	}
+	if tr, ok := w.Transition(StatusOpen, StatusClosed); !ok || len(tr.Require) != 0 {
+		t.Errorf("Expected open -> closed through *, got %+v", tr)
+	}
+	if _, ok := w.Transition(StatusBlocked, StatusOpen); ok {
+		t.Error("Expected blocked -> open to be disallowed")
+	}
+
+	next := w.Next(StatusOpen)
+	if len(next) != 2 || next[0].To != StatusInProgress || next[1].To != StatusClosed {
+		t.Errorf("Expected open to move to in_progress or closed, got %+v", next)
+	}
+
+	issue := &Issue{ID: "bd-1", Status: StatusInProgress}
+	if err := w.CheckTransition(issue, StatusClosed, "Done"); err != nil {
+		t.Errorf("Expected close with a reason to pass, got %v", err)
+	}
+	if err := w.CheckTransition(issue, StatusClosed, " "); err == nil || !strings.Contains(err.Error(), "requires reason") {
+		t.Errorf("Expected a blank reason to be missing, got %v", err)
+	}
+	if err := w.CheckTransition(issue, StatusInProgress, ""); err != nil {
+		t.Errorf("Expected staying put to pass, got %v", err)
+	}
+}
@@ -0,0 +1,182 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+const (
+	pollInterval = 2 * time.Second
+	pollBatch    = 500
+)
+
+// Dispatcher watches the event log and delivers new events to webhooks.
+// Like the WebSocket hub it polls rather than hooking writes, so changes made
+// by the CLI or daemon are delivered too. Only events recorded after the
+// dispatcher starts are sent.
+type Dispatcher struct {
+	store  *sqlite.SQLiteStorage
+	sender *Sender
+
+	lastEventID int64
+
+	mu      sync.Mutex
+	lastRun time.Time
+	lastErr error
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+}
+
+// NewDispatcher creates a dispatcher positioned at the newest event
+func NewDispatcher(store *sqlite.SQLiteStorage, sender *Sender) (*Dispatcher, error) {
+	last, err := store.GetLatestEventID(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Dispatcher{
+		store:       store,
+		sender:      sender,
+		lastEventID: last,
+		ctx:         ctx,
+		cancel:      cancel,
+	}, nil
+}
+
+// Start begins polling in the background
+func (d *Dispatcher) Start() {
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-d.ctx.Done():
+				return
+			case <-ticker.C:
+				err := d.poll()
+				d.mu.Lock()
+				d.lastRun, d.lastErr = time.Now(), err
+				d.mu.Unlock()
+			}
+		}
+	}()
+}
+
+// Close stops polling, abandons pending retries, and waits for in-flight
+// deliveries to return
+func (d *Dispatcher) Close() {
+	d.stopOnce.Do(d.cancel)
+	d.wg.Wait()
+}
+
+// LastRun reports when the dispatcher last polled for events and the
+// storage error that poll hit, if any. Failed deliveries are the
+// endpoints' problem and aren't reported here.
+func (d *Dispatcher) LastRun() (time.Time, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.lastRun, d.lastErr
+}
+
+// poll delivers events recorded since the last poll. Each webhook gets its
+// batch in order on its own goroutine, so a slow endpoint doesn't hold up
+// the others or the next poll.
+func (d *Dispatcher) poll() error {
+	ctx := d.ctx
+	events, err := d.store.GetEventsAfter(ctx, d.lastEventID, pollBatch)
+	if err != nil || len(events) == 0 {
+		return err
+	}
+	d.lastEventID = events[len(events)-1].ID
+
+	hooks, err := d.store.ListWebhooks(ctx)
+	if err != nil || len(hooks) == 0 {
+		return err
+	}
+
+	payloads := make([]*Payload, 0, len(events))
+	issues := make(map[string]*types.Issue)
+	watchers := make(map[string][]string)
+	for _, event := range events {
+		name := EventName(event.EventType)
+		if name == "" {
+			continue
+		}
+		issue, ok := issues[event.IssueID]
+		if !ok {
+			issue, err = d.store.GetIssue(ctx, event.IssueID)
+			if err != nil {
+				continue
+			}
+			if issue != nil {
+				issue.Labels, _ = d.store.GetLabels(ctx, issue.ID)
+			}
+			issues[event.IssueID] = issue
+			watchers[event.IssueID], _ = d.store.GetWatchers(ctx, event.IssueID)
+		}
+		p := NewPayload(name, event, issue)
+		p.Watchers = watchers[event.IssueID]
+		payloads = append(payloads, p)
+	}
+
+	for _, hook := range hooks {
+		var queue []*Payload
+		for _, p := range payloads {
+			if Wants(hook, p.Event) {
+				queue = append(queue, p)
+			}
+		}
+		if len(queue) == 0 {
+			continue
+		}
+
+		d.wg.Add(1)
+		go func(hook *sqlite.Webhook, queue []*Payload) {
+			defer d.wg.Done()
+			for _, p := range queue {
+				if err := d.sender.Send(ctx, hook, p); err != nil {
+					if ctx.Err() != nil {
+						return
+					}
+					log.Printf("webhook delivery of %s for %s failed: %v", p.Event, p.IssueID, err)
+				}
+			}
+		}(hook, queue)
+	}
+	return nil
+}
+
+// NewPayload builds the delivery for an audit event
+func NewPayload(name string, event *types.Event, issue *types.Issue) *Payload {
+	p := &Payload{
+		Event:     name,
+		EventID:   event.ID,
+		IssueID:   event.IssueID,
+		Actor:     event.Actor,
+		Timestamp: event.CreatedAt,
+		Issue:     issue,
+	}
+	if event.Comment != nil && (name == EventCommented || name == EventClosed || name == EventMentioned) {
+		p.Comment = *event.Comment
+	}
+	if name == EventMentioned && event.NewValue != nil {
+		var mention struct {
+			Mentioned string `json:"mentioned"`
+		}
+		if json.Unmarshal([]byte(*event.NewValue), &mention) == nil {
+			p.Mentioned = mention.Mentioned
+		}
+	}
+	return p
+}
@@ -0,0 +1,194 @@
+// Package webhook delivers issue events to registered URLs.
+//
+// Deliveries are JSON POSTs signed with HMAC-SHA256 over the body when the
+// webhook has a secret. The signature is sent as
+//
+//	X-Beads-Signature: sha256=<hex>
+//
+// alongside X-Beads-Event (the event name) and X-Beads-Delivery (a unique ID).
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// Event names sent to webhooks
+const (
+	EventCreated   = "issue.created"
+	EventUpdated   = "issue.updated"
+	EventClosed    = "issue.closed"
+	EventCommented = "issue.commented"
+	EventMentioned = "issue.mentioned" // a comment @mentioned someone
+	EventPing      = "ping"            // sent by test deliveries only
+)
+
+// Events lists the event names a webhook can subscribe to
+var Events = []string{EventCreated, EventUpdated, EventClosed, EventCommented, EventMentioned}
+
+// EventName maps an audit event to the webhook event it triggers, or "" if
+// it isn't delivered
+func EventName(t types.EventType) string {
+	switch t {
+	case types.EventCreated:
+		return EventCreated
+	case types.EventUpdated, types.EventStatusChanged, types.EventReopened:
+		return EventUpdated
+	case types.EventClosed:
+		return EventClosed
+	case types.EventCommented:
+		return EventCommented
+	case types.EventMentioned:
+		return EventMentioned
+	default:
+		return ""
+	}
+}
+
+// ValidateEvents checks that every name is a known event
+func ValidateEvents(names []string) error {
+	for _, name := range names {
+		if !containsString(Events, name) {
+			return fmt.Errorf("unknown webhook event %q (valid: %s)", name, strings.Join(Events, ", "))
+		}
+	}
+	return nil
+}
+
+// ValidateURL checks that target is an absolute http or https URL
+func ValidateURL(target string) error {
+	u, err := url.Parse(target)
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid webhook URL %q: must be an absolute http or https URL", target)
+	}
+	return nil
+}
+
+// Wants reports whether hook subscribes to event
+func Wants(hook *sqlite.Webhook, event string) bool {
+	return len(hook.Events) == 0 || containsString(hook.Events, event)
+}
+
+// Payload is the JSON body of a delivery
+type Payload struct {
+	Event     string       `json:"event"`
+	EventID   int64        `json:"event_id,omitempty"`
+	IssueID   string       `json:"issue_id,omitempty"`
+	Actor     string       `json:"actor,omitempty"`
+	Timestamp time.Time    `json:"timestamp"`
+	Issue     *types.Issue `json:"issue,omitempty"`     // current state; nil if since deleted
+	Comment   string       `json:"comment,omitempty"`   // comment text or close reason
+	Mentioned string       `json:"mentioned,omitempty"` // the user a comment @mentioned
+	Watchers  []string     `json:"watchers,omitempty"`  // users watching the issue, to notify of any change
+}
+
+// Sign returns the X-Beads-Signature value for body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Sender posts payloads, retrying failed attempts with exponential backoff
+type Sender struct {
+	Client  *http.Client
+	Retries int           // attempts after the first
+	Backoff time.Duration // wait before the first retry; doubles each time
+}
+
+// NewSender returns a sender with the default timeout and retry policy
+func NewSender() *Sender {
+	return &Sender{
+		Client:  &http.Client{Timeout: 10 * time.Second},
+		Retries: 4,
+		Backoff: time.Second,
+	}
+}
+
+// Send delivers payload to hook. Network errors, 429, and 5xx responses are
+// retried; other non-2xx responses fail immediately.
+func (s *Sender) Send(ctx context.Context, hook *sqlite.Webhook, payload *Payload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+	delivery := newDeliveryID()
+
+	backoff := s.Backoff
+	for attempt := 0; ; attempt++ {
+		retry, err := s.post(ctx, hook, payload.Event, delivery, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= s.Retries {
+			return fmt.Errorf("webhook %d: %w", hook.ID, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("webhook %d: %w", hook.ID, ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// post makes one delivery attempt and reports whether a failure is worth retrying
+func (s *Sender) post(ctx context.Context, hook *sqlite.Webhook, event, delivery string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "beads-webhook")
+	req.Header.Set("X-Beads-Event", event)
+	req.Header.Set("X-Beads-Delivery", delivery)
+	if hook.Secret != "" {
+		req.Header.Set("X-Beads-Signature", Sign(hook.Secret, body))
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("%s responded %s", hook.URL, resp.Status)
+}
+
+func newDeliveryID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
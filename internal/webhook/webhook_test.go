@@ -0,0 +1,160 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/storage/sqlite"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestSenderSignsAndRetries(t *testing.T) {
+	var mu sync.Mutex
+	attempts := 0
+	var got *http.Request
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		got = r
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+
+	sender := &Sender{Client: srv.Client(), Retries: 4, Backoff: time.Millisecond}
+	hook := &sqlite.Webhook{ID: 1, URL: srv.URL, Secret: "s3cret"}
+	payload := &Payload{Event: EventCreated, IssueID: "bd-1", Timestamp: time.Now()}
+	if err := sender.Send(context.Background(), hook, payload); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+	if sig := got.Header.Get("X-Beads-Signature"); sig != Sign("s3cret", body) {
+		t.Errorf("Signature %q does not match body", sig)
+	}
+	if got.Header.Get("X-Beads-Event") != EventCreated {
+		t.Errorf("Expected event header %s, got %q", EventCreated, got.Header.Get("X-Beads-Event"))
+	}
+}
+
+func TestSenderDoesNotRetryClientErrors(t *testing.T) {
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	sender := &Sender{Client: srv.Client(), Retries: 4, Backoff: time.Millisecond}
+	err := sender.Send(context.Background(), &sqlite.Webhook{URL: srv.URL}, &Payload{Event: EventPing})
+	if err == nil {
+		t.Fatal("Expected error for 400 response")
+	}
+	if attempts != 1 {
+		t.Errorf("Expected 1 attempt, got %d", attempts)
+	}
+}
+
+func TestDispatcherDeliversEvents(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+
+	received := make(chan *Payload, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p Payload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			t.Errorf("bad payload: %v", err)
+		}
+		received <- &p
+	}))
+	defer srv.Close()
+
+	// One hook for everything, one for closes only
+	all := &sqlite.Webhook{URL: srv.URL}
+	closes := &sqlite.Webhook{URL: srv.URL, Events: []string{EventClosed}}
+	for _, hook := range []*sqlite.Webhook{all, closes} {
+		if err := store.CreateWebhook(ctx, hook); err != nil {
+			t.Fatalf("CreateWebhook failed: %v", err)
+		}
+	}
+
+	d, err := NewDispatcher(store, &Sender{Client: srv.Client(), Backoff: time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewDispatcher failed: %v", err)
+	}
+	defer d.Close()
+
+	issue := &types.Issue{Title: "Hook me", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.WatchIssue(ctx, issue.ID, "carol"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AddComment(ctx, issue.ID, "bob", "looks good"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.CloseIssue(ctx, issue.ID, "done", "alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	d.poll()
+	d.wg.Wait()
+	close(received)
+
+	counts := make(map[string]int)
+	for p := range received {
+		counts[p.Event]++
+		if p.IssueID != issue.ID || p.Issue == nil || p.Issue.Status != types.StatusClosed {
+			t.Errorf("%s: unexpected payload %+v", p.Event, p)
+		}
+		if len(p.Watchers) != 1 || p.Watchers[0] != "carol" {
+			t.Errorf("%s: expected carol as the watcher, got %v", p.Event, p.Watchers)
+		}
+		if p.Event == EventCommented && p.Comment != "looks good" {
+			t.Errorf("Expected comment text, got %q", p.Comment)
+		}
+	}
+	want := map[string]int{EventCreated: 1, EventCommented: 1, EventClosed: 2}
+	for event, n := range want {
+		if counts[event] != n {
+			t.Errorf("%s: expected %d deliveries, got %d", event, n, counts[event])
+		}
+	}
+}
+
+func TestMentionPayload(t *testing.T) {
+	text := "@carol can you review?"
+	mentioned := `{"comment_id":3,"mentioned":"carol"}`
+	event := &types.Event{ID: 7, IssueID: "bd-1", EventType: types.EventMentioned, Actor: "bob", NewValue: &mentioned, Comment: &text}
+
+	name := EventName(event.EventType)
+	if name != EventMentioned {
+		t.Fatalf("Expected %s, got %q", EventMentioned, name)
+	}
+	p := NewPayload(name, event, nil)
+	if p.Mentioned != "carol" || p.Actor != "bob" || p.Comment != text {
+		t.Errorf("Expected carol mentioned by bob with the comment text, got %+v", p)
+	}
+}
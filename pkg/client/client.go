@@ -0,0 +1,669 @@
+// Package client is a Go client for the REST API that bd serve exposes.
+//
+//	c, err := client.New("http://localhost:8080", client.WithToken(token))
+//	if err != nil {
+//		return err
+//	}
+//	issue, err := c.CreateIssue(ctx, &client.CreateArgs{Title: "Fix login", IssueType: "bug", Priority: 1})
+//
+// The base URL of a workspace on a multi-workspace server includes its
+// /w/{name} prefix. A request that fails gets an *APIError carrying the
+// API's stable error code; errors.Is(err, ErrNotFound) holds for a missing
+// issue or other record.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/imalsogreg/beads/internal/rpc"
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+// The API's request and response types, named here so programs outside this
+// module can use them
+type (
+	Issue          = types.Issue
+	IssueFilter    = types.IssueFilter
+	WorkFilter     = types.WorkFilter
+	CreateArgs     = rpc.CreateArgs
+	Dependency     = types.Dependency
+	DependencyType = types.DependencyType
+	TreeNode       = types.TreeNode
+	BlockedIssue   = types.BlockedIssue
+	EpicStatus     = types.EpicStatus
+	Comment        = types.Comment
+	CommentEdit    = types.CommentEdit
+	Mention        = types.Mention
+	Statistics     = types.Statistics
+	StatGroup      = types.StatGroup
+	Workflow       = types.Workflow
+	APIError       = types.APIError
+	ErrorCode      = types.ErrorCode
+)
+
+// apiVersion is the version of the API the client speaks, sent as the
+// API-Version header so that base URLs need no /v1 prefix
+const apiVersion = "1"
+
+// ErrNotFound matches, with errors.Is, the error for a missing issue or
+// other record
+var ErrNotFound = types.ErrNotFound
+
+// Client calls a bd serve API. It is safe for concurrent use.
+type Client struct {
+	url   string
+	token string
+	actor string
+	http  *http.Client
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithToken sends token, an API key or the server's BEADS_API_SECRET, as a
+// bearer token
+func WithToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// WithActor makes changes in the name of actor rather than the one the
+// server derives from the token
+func WithActor(actor string) Option {
+	return func(c *Client) { c.actor = actor }
+}
+
+// WithHTTPClient makes requests with h instead of a client with a 30s
+// timeout
+func WithHTTPClient(h *http.Client) Option {
+	return func(c *Client) { c.http = h }
+}
+
+// New returns a client for the bd serve API at baseURL
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid URL %q", baseURL)
+	}
+	c := &Client{
+		url:  strings.TrimRight(baseURL, "/"),
+		http: &http.Client{Timeout: 30 * time.Second},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// URL returns the base URL of the API
+func (c *Client) URL() string {
+	return c.url
+}
+
+// As returns a client that makes changes in the name of actor
+func (c *Client) As(actor string) *Client {
+	if actor == "" || actor == c.actor {
+		return c
+	}
+	clone := *c
+	clone.actor = actor
+	return &clone
+}
+
+// Issues
+
+// CreateIssue creates an issue and returns it with its ID
+func (c *Client) CreateIssue(ctx context.Context, args *CreateArgs) (*Issue, error) {
+	var issue Issue
+	if err := c.do(ctx, http.MethodPost, "/issues", nil, args, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// CreateSubtask creates an issue as a subtask of parentID
+func (c *Client) CreateSubtask(ctx context.Context, parentID string, args *CreateArgs) (*Issue, error) {
+	var issue Issue
+	if err := c.do(ctx, http.MethodPost, issuePath(parentID)+"/subtasks", nil, args, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// GetIssue returns an issue with its subtask roll-up, links, and watchers
+func (c *Client) GetIssue(ctx context.Context, id string) (*Issue, error) {
+	var issue Issue
+	if err := c.do(ctx, http.MethodGet, issuePath(id), nil, nil, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// ListIssues returns the issues matching filter
+func (c *Client) ListIssues(ctx context.Context, filter IssueFilter) ([]*Issue, error) {
+	var issues []*Issue
+	err := c.do(ctx, http.MethodGet, "/issues", issueFilterQuery(filter), nil, &issues)
+	return issues, err
+}
+
+// ForEachIssue calls fn with each issue matching filter as the server
+// streams them (as NDJSON), stopping at the first error fn returns, so a
+// listing of any size takes little memory. Large listings outlast the
+// default client's 30s timeout; use WithHTTPClient to raise it.
+func (c *Client) ForEachIssue(ctx context.Context, filter IssueFilter, fn func(*Issue) error) error {
+	resp, err := c.send(ctx, http.MethodGet, "/issues", issueFilterQuery(filter), nil, "application/x-ndjson")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var issue Issue
+		if err := decoder.Decode(&issue); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("GET /issues: invalid response: %w", err)
+		}
+		if err := fn(&issue); err != nil {
+			return err
+		}
+	}
+}
+
+// UpdateIssue sets the fields in updates, keyed by their JSON names, and
+// returns the updated issue
+func (c *Client) UpdateIssue(ctx context.Context, id string, updates map[string]interface{}) (*Issue, error) {
+	var issue Issue
+	if err := c.do(ctx, http.MethodPatch, issuePath(id), nil, updates, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// UpdateIssues applies updates to every issue matching filter in one
+// transaction and returns the IDs of the issues changed. The filter can
+// only use status, priority, assignee, type, labels, title, and IDs.
+func (c *Client) UpdateIssues(ctx context.Context, filter IssueFilter, updates map[string]interface{}) ([]string, error) {
+	rest := filter
+	rest.Status, rest.Priority, rest.Assignee, rest.IssueType = nil, nil, nil, nil
+	rest.Labels, rest.LabelsAny, rest.TitleSearch, rest.IDs = nil, nil, "", nil
+	if !rest.IsEmpty() {
+		return nil, fmt.Errorf("bulk updates can only filter by status, priority, assignee, type, labels, title, and IDs")
+	}
+
+	body := map[string]interface{}{
+		"filter": map[string]interface{}{
+			"status":     filter.Status,
+			"priority":   filter.Priority,
+			"assignee":   filter.Assignee,
+			"type":       filter.IssueType,
+			"labels":     filter.Labels,
+			"labels_any": filter.LabelsAny,
+			"title":      filter.TitleSearch,
+			"ids":        filter.IDs,
+			"limit":      filter.Limit,
+		},
+		"updates": updates,
+	}
+	var result struct {
+		Updated []string `json:"updated"`
+	}
+	err := c.do(ctx, http.MethodPatch, "/issues", nil, body, &result)
+	return result.Updated, err
+}
+
+// CloseIssue closes an issue, recording reason if it isn't empty
+func (c *Client) CloseIssue(ctx context.Context, id, reason string) error {
+	return c.do(ctx, http.MethodPost, issuePath(id)+"/close", nil, map[string]string{"reason": reason}, nil)
+}
+
+// DeleteIssue moves an issue to the trash
+func (c *Client) DeleteIssue(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, issuePath(id), nil, nil, nil)
+}
+
+// GetReadyWork returns the open issues with no open blockers
+func (c *Client) GetReadyWork(ctx context.Context, filter WorkFilter) ([]*Issue, error) {
+	query := url.Values{}
+	if filter.Status != "" {
+		query.Set("status", string(filter.Status))
+	}
+	query["label"] = filter.Labels
+	if filter.SortPolicy != "" {
+		query.Set("sort", string(filter.SortPolicy))
+	}
+	if filter.Assignee != nil {
+		query.Set("assignee", *filter.Assignee)
+	}
+	if filter.Priority != nil {
+		query.Set("priority", strconv.Itoa(*filter.Priority))
+	}
+	if filter.MaxPriority != nil {
+		query.Set("max_priority", strconv.Itoa(*filter.MaxPriority))
+	}
+	if filter.Limit > 0 {
+		query.Set("limit", strconv.Itoa(filter.Limit))
+	}
+
+	var issues []*Issue
+	err := c.do(ctx, http.MethodGet, "/issues/ready", query, nil, &issues)
+	return issues, err
+}
+
+// GetBlockedIssues returns the open issues waiting on open blockers
+func (c *Client) GetBlockedIssues(ctx context.Context) ([]*BlockedIssue, error) {
+	var blocked []*BlockedIssue
+	err := c.do(ctx, http.MethodGet, "/issues/blocked", nil, nil, &blocked)
+	return blocked, err
+}
+
+// GetStatistics returns issue counts
+func (c *Client) GetStatistics(ctx context.Context) (*Statistics, error) {
+	var stats Statistics
+	if err := c.do(ctx, http.MethodGet, "/issues/stats", nil, nil, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// GetGroupedStatistics returns issue counts by status for each value of
+// groupBy: status, assignee, label, type, or priority
+func (c *Client) GetGroupedStatistics(ctx context.Context, groupBy string) ([]*StatGroup, error) {
+	var stats Statistics
+	if err := c.do(ctx, http.MethodGet, "/issues/stats", url.Values{"group_by": {groupBy}}, nil, &stats); err != nil {
+		return nil, err
+	}
+	return stats.Groups, nil
+}
+
+// ListEpics returns every open epic with how many of its children are
+// closed
+func (c *Client) ListEpics(ctx context.Context) ([]*EpicStatus, error) {
+	var epics []*EpicStatus
+	err := c.do(ctx, http.MethodGet, "/epics", nil, nil, &epics)
+	return epics, err
+}
+
+// Labels
+
+// AddLabel adds a label to an issue
+func (c *Client) AddLabel(ctx context.Context, id, label string) error {
+	return c.do(ctx, http.MethodPost, issuePath(id)+"/labels", nil, map[string]string{"label": label}, nil)
+}
+
+// RemoveLabel removes a label from an issue
+func (c *Client) RemoveLabel(ctx context.Context, id, label string) error {
+	return c.do(ctx, http.MethodDelete, issuePath(id)+"/labels/"+url.PathEscape(label), nil, nil, nil)
+}
+
+// GetLabels returns an issue's labels
+func (c *Client) GetLabels(ctx context.Context, id string) ([]string, error) {
+	var labels []string
+	err := c.do(ctx, http.MethodGet, issuePath(id)+"/labels", nil, nil, &labels)
+	return labels, err
+}
+
+// Dependencies
+
+// AddDependency makes dep.IssueID depend on dep.DependsOnID, with a blocks
+// dependency if dep.Type is empty
+func (c *Client) AddDependency(ctx context.Context, dep *Dependency) error {
+	body := map[string]string{"depends_on": dep.DependsOnID, "type": string(dep.Type)}
+	return c.do(ctx, http.MethodPost, issuePath(dep.IssueID)+"/dependencies", nil, body, nil)
+}
+
+// RemoveDependency removes the dependency of issueID on dependsOnID
+func (c *Client) RemoveDependency(ctx context.Context, issueID, dependsOnID string) error {
+	return c.do(ctx, http.MethodDelete, issuePath(issueID)+"/dependencies/"+url.PathEscape(dependsOnID), nil, nil, nil)
+}
+
+// GetDependencyRecords returns the dependencies of an issue
+func (c *Client) GetDependencyRecords(ctx context.Context, id string) ([]*Dependency, error) {
+	var deps []*Dependency
+	err := c.do(ctx, http.MethodGet, issuePath(id)+"/dependencies", nil, nil, &deps)
+	return deps, err
+}
+
+// ListDependencies returns every dependency, ordered by issue
+func (c *Client) ListDependencies(ctx context.Context) ([]*Dependency, error) {
+	var deps []*Dependency
+	err := c.do(ctx, http.MethodGet, "/dependencies", nil, nil, &deps)
+	return deps, err
+}
+
+// GetDependents returns the issues that depend on an issue
+func (c *Client) GetDependents(ctx context.Context, id string) ([]*Issue, error) {
+	var issues []*Issue
+	err := c.do(ctx, http.MethodGet, issuePath(id)+"/dependents", nil, nil, &issues)
+	return issues, err
+}
+
+// GetChildren returns an issue's direct subtasks
+func (c *Client) GetChildren(ctx context.Context, id string) ([]*Issue, error) {
+	var issues []*Issue
+	err := c.do(ctx, http.MethodGet, issuePath(id)+"/children", nil, nil, &issues)
+	return issues, err
+}
+
+// GetDependencyTree returns an issue's dependencies to maxDepth, or its
+// dependents if reverse is set
+func (c *Client) GetDependencyTree(ctx context.Context, id string, maxDepth int, reverse bool) ([]*TreeNode, error) {
+	query := url.Values{"max_depth": {strconv.Itoa(maxDepth)}}
+	if reverse {
+		query.Set("reverse", "true")
+	}
+	var tree []*TreeNode
+	err := c.do(ctx, http.MethodGet, issuePath(id)+"/tree", query, nil, &tree)
+	return tree, err
+}
+
+// DetectCycles returns the dependency cycles, each as the issues along it
+func (c *Client) DetectCycles(ctx context.Context) ([][]*Issue, error) {
+	var cycles [][]*Issue
+	err := c.do(ctx, http.MethodGet, "/dependencies/cycles", nil, nil, &cycles)
+	return cycles, err
+}
+
+// Comments
+
+// AddComment adds a comment to an issue
+func (c *Client) AddComment(ctx context.Context, id, text string) (*Comment, error) {
+	return c.postComment(ctx, id, map[string]interface{}{"text": text})
+}
+
+// ReplyToComment adds a comment answering comment replyTo on the same issue
+func (c *Client) ReplyToComment(ctx context.Context, id string, replyTo int64, text string) (*Comment, error) {
+	return c.postComment(ctx, id, map[string]interface{}{"text": text, "reply_to": replyTo})
+}
+
+func (c *Client) postComment(ctx context.Context, id string, body map[string]interface{}) (*Comment, error) {
+	var comment Comment
+	if err := c.do(ctx, http.MethodPost, issuePath(id)+"/comments", nil, body, &comment); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// ListComments returns an issue's comments, oldest first
+func (c *Client) ListComments(ctx context.Context, id string) ([]*Comment, error) {
+	var comments []*Comment
+	err := c.do(ctx, http.MethodGet, issuePath(id)+"/comments", nil, nil, &comments)
+	return comments, err
+}
+
+// EditComment replaces a comment's text, keeping the old text in its edit
+// history
+func (c *Client) EditComment(ctx context.Context, id string, commentID int64, text string) (*Comment, error) {
+	var comment Comment
+	if err := c.do(ctx, http.MethodPatch, commentPath(id, commentID), nil, map[string]string{"text": text}, &comment); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// DeleteComment deletes a comment; its replies move up to its parent
+func (c *Client) DeleteComment(ctx context.Context, id string, commentID int64) error {
+	return c.do(ctx, http.MethodDelete, commentPath(id, commentID), nil, nil, nil)
+}
+
+// GetCommentEdits returns a comment's earlier versions, oldest first
+func (c *Client) GetCommentEdits(ctx context.Context, id string, commentID int64) ([]*CommentEdit, error) {
+	var edits []*CommentEdit
+	err := c.do(ctx, http.MethodGet, commentPath(id, commentID)+"/edits", nil, nil, &edits)
+	return edits, err
+}
+
+// AddReaction reacts to a comment and returns it with its reactions
+func (c *Client) AddReaction(ctx context.Context, id string, commentID int64, reaction string) (*Comment, error) {
+	var comment Comment
+	if err := c.do(ctx, http.MethodPost, commentPath(id, commentID)+"/reactions", nil, map[string]string{"reaction": reaction}, &comment); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// RemoveReaction takes back a reaction to a comment
+func (c *Client) RemoveReaction(ctx context.Context, id string, commentID int64, reaction string) (*Comment, error) {
+	var comment Comment
+	if err := c.do(ctx, http.MethodDelete, commentPath(id, commentID)+"/reactions/"+url.PathEscape(reaction), nil, nil, &comment); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// GetMentions returns the comments that @mention user, newest first; an
+// empty user means the caller
+func (c *Client) GetMentions(ctx context.Context, user string) ([]*Mention, error) {
+	query := url.Values{}
+	if user != "" {
+		query.Set("user", user)
+	}
+	var mentions []*Mention
+	err := c.do(ctx, http.MethodGet, "/mentions", query, nil, &mentions)
+	return mentions, err
+}
+
+// Configuration
+
+// GetConfig returns a config value, or "" if it isn't set
+func (c *Client) GetConfig(ctx context.Context, key string) (string, error) {
+	var result struct {
+		Value string `json:"value"`
+	}
+	err := c.do(ctx, http.MethodGet, "/config/"+url.PathEscape(key), nil, nil, &result)
+	return result.Value, err
+}
+
+// SetConfig sets a config value
+func (c *Client) SetConfig(ctx context.Context, key, value string) error {
+	return c.do(ctx, http.MethodPut, "/config/"+url.PathEscape(key), nil, map[string]string{"value": value}, nil)
+}
+
+// DeleteConfig deletes a config value
+func (c *Client) DeleteConfig(ctx context.Context, key string) error {
+	return c.do(ctx, http.MethodDelete, "/config/"+url.PathEscape(key), nil, nil, nil)
+}
+
+// GetAllConfig returns every config value, by key
+func (c *Client) GetAllConfig(ctx context.Context) (map[string]string, error) {
+	config := map[string]string{}
+	err := c.do(ctx, http.MethodGet, "/config", nil, nil, &config)
+	return config, err
+}
+
+// GetWorkflow returns the statuses issues can be in and the moves between
+// them
+func (c *Client) GetWorkflow(ctx context.Context) (*Workflow, error) {
+	var workflow Workflow
+	if err := c.do(ctx, http.MethodGet, "/workflow", nil, nil, &workflow); err != nil {
+		return nil, err
+	}
+	return &workflow, nil
+}
+
+// SetWorkflow replaces the workflow
+func (c *Client) SetWorkflow(ctx context.Context, workflow *Workflow) error {
+	return c.do(ctx, http.MethodPut, "/workflow", nil, workflow, nil)
+}
+
+// do sends a request with a JSON body, if any, and decodes a successful
+// response into out, if not nil
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	resp, err := c.send(ctx, method, path, query, body, "application/json")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%s %s: invalid response: %w", method, path, err)
+	}
+	return nil
+}
+
+// send makes a request accepting the given media type and returns the
+// response if it succeeded, for the caller to read and close
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, body interface{}, accept string) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	target := c.url + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+	req.Header.Set("API-Version", apiVersion)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.actor != "" {
+		req.Header.Set("X-Actor", c.actor)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		return nil, types.ParseAPIError(resp.StatusCode, data)
+	}
+	return resp, nil
+}
+
+func issuePath(id string) string {
+	return "/issues/" + url.PathEscape(id)
+}
+
+func commentPath(id string, commentID int64) string {
+	return issuePath(id) + "/comments/" + strconv.FormatInt(commentID, 10)
+}
+
+// issueFilterQuery encodes filter as the query parameters of GET /issues
+func issueFilterQuery(filter IssueFilter) url.Values {
+	query := url.Values{}
+	add := func(name string, value string, exclude bool) {
+		if exclude {
+			value = "!" + value
+		}
+		query.Add(name, value)
+	}
+	assignee := func(a string) string {
+		if a == "" {
+			return "unassigned"
+		}
+		return a
+	}
+
+	if filter.Status != nil {
+		add("status", string(*filter.Status), false)
+	}
+	for _, s := range filter.Statuses {
+		add("status", string(s), false)
+	}
+	for _, s := range filter.ExcludeStatuses {
+		add("status", string(s), true)
+	}
+	if filter.Priority != nil {
+		add("priority", strconv.Itoa(*filter.Priority), false)
+	}
+	for _, p := range filter.Priorities {
+		add("priority", strconv.Itoa(p), false)
+	}
+	for _, p := range filter.ExcludePriorities {
+		add("priority", strconv.Itoa(p), true)
+	}
+	if filter.IssueType != nil {
+		add("type", string(*filter.IssueType), false)
+	}
+	for _, t := range filter.IssueTypes {
+		add("type", string(t), false)
+	}
+	for _, t := range filter.ExcludeIssueTypes {
+		add("type", string(t), true)
+	}
+	if filter.Assignee != nil {
+		add("assignee", assignee(*filter.Assignee), false)
+	}
+	for _, a := range filter.Assignees {
+		add("assignee", assignee(a), false)
+	}
+	for _, a := range filter.ExcludeAssignees {
+		add("assignee", assignee(a), true)
+	}
+	for _, l := range filter.Labels {
+		add("label", l, false)
+	}
+	for _, l := range filter.ExcludeLabels {
+		add("label", l, true)
+	}
+	for _, l := range filter.LabelsAny {
+		query.Add("label_any", l)
+	}
+	for _, id := range filter.IDs {
+		query.Add("id", id)
+	}
+	if filter.TitleSearch != "" {
+		query.Set("title", filter.TitleSearch)
+	}
+	if filter.DueBefore != nil {
+		query.Set("due_before", filter.DueBefore.Format(time.RFC3339Nano))
+	}
+	if filter.DueAfter != nil {
+		query.Set("due_after", filter.DueAfter.Format(time.RFC3339Nano))
+	}
+	if filter.Overdue {
+		query.Set("overdue", "true")
+	}
+	if filter.Milestone != nil {
+		query.Set("milestone", *filter.Milestone)
+	}
+	if filter.Query != nil {
+		query.Set("query", filter.Query.String())
+	}
+	if len(filter.Sort) > 0 {
+		keys := make([]string, len(filter.Sort))
+		for i, key := range filter.Sort {
+			keys[i] = key.Field
+			if key.Desc {
+				keys[i] = "-" + key.Field
+			}
+		}
+		query.Set("sort", strings.Join(keys, ","))
+	}
+	if filter.Limit > 0 {
+		query.Set("limit", strconv.Itoa(filter.Limit))
+	}
+	if !filter.ExcludeArchived {
+		query.Set("include_archived", "true")
+	}
+	for name, values := range query {
+		if len(values) == 0 {
+			delete(query, name)
+		}
+	}
+	return query
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/imalsogreg/beads/internal/types"
+)
+
+func TestNewRejectsBadURLs(t *testing.T) {
+	for _, u := range []string{"", "localhost:8080", "ftp://example.com", "http://"} {
+		if _, err := New(u); err == nil {
+			t.Errorf("New(%q): expected an error", u)
+		}
+	}
+	c, err := New("http://localhost:8080/", WithActor("alice"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.URL() != "http://localhost:8080" {
+		t.Errorf("Expected the trailing slash trimmed, got %q", c.URL())
+	}
+	if c.As("alice") != c || c.As("bob").actor != "bob" || c.actor != "alice" {
+		t.Error("As should return a copy for another actor")
+	}
+}
+
+func TestIssueFilterQuery(t *testing.T) {
+	status := types.StatusOpen
+	priority := 1
+	unassigned := ""
+	query := issueFilterQuery(IssueFilter{
+		Status:          &status,
+		ExcludeStatuses: []types.Status{types.StatusBlocked},
+		Priority:        &priority,
+		Assignee:        &unassigned,
+		LabelsAny:       []string{"a", "b"},
+		ExcludeArchived: true,
+	})
+	want := "assignee=unassigned&label_any=a&label_any=b&priority=1&status=open&status=%21blocked"
+	if got := query.Encode(); got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+	if got := issueFilterQuery(IssueFilter{}).Encode(); got != "include_archived=true" {
+		t.Errorf("Expected only include_archived for an empty filter, got %s", got)
+	}
+}
+
+func TestErrorsFromResponses(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error": "issue bd-9 not found", "code": "not_found"}`))
+	}))
+	defer ts.Close()
+
+	c, err := New(ts.URL, WithToken("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.GetIssue(context.Background(), "bd-9")
+	if !errors.Is(err, ErrNotFound) || err.Error() != "issue bd-9 not found" {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+
+	c, _ = New(ts.URL)
+	_, err = c.GetIssue(context.Background(), "bd-9")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != types.CodeUnauthorized {
+		t.Errorf("Expected an unauthorized error, got %v", err)
+	}
+}